@@ -0,0 +1,30 @@
+// Command goflow is the GoFlow command-line interface.
+package main
+
+import (
+	"os"
+
+	"github.com/dshills/goflow/pkg/cli"
+)
+
+// Build information, set via -ldflags by the Makefile
+var (
+	Version   = ""
+	BuildTime = ""
+)
+
+func main() {
+	cmd := cli.NewRootCommand()
+	if Version != "" {
+		cmd.Version = Version
+		if BuildTime != "" {
+			cmd.Version += " (built " + BuildTime + ")"
+		}
+	}
+
+	// Cobra has already reported the error; the exit code tells CI
+	// pipelines whether a run failed or was interrupted
+	if err := cmd.Execute(); err != nil {
+		os.Exit(cli.ExitCode(err))
+	}
+}
@@ -19,7 +19,11 @@ func main() {
 	}
 
 	// Start server (blocks until stdin is closed or error occurs)
-	if err := server.Start(); err != nil {
+	err = server.Start()
+	if closeErr := server.Close(); closeErr != nil {
+		log.Printf("Failed to close server: %v", closeErr)
+	}
+	if err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
@@ -1,17 +1,38 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/dshills/goflow/pkg/workflow"
 	"gopkg.in/yaml.v3"
 )
 
+// Exit codes
+const (
+	exitValid   = 0 // Every template is valid
+	exitInvalid = 1 // At least one template is invalid
+	exitUsage   = 2 // Bad arguments or unreadable paths
+)
+
 type WorkflowTemplate struct {
-	Name         string      `yaml:"name"`
-	Description  string      `yaml:"description"`
-	Version      string      `yaml:"version"`
-	Parameters   []Parameter `yaml:"parameters"`
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description"`
+	Version     string      `yaml:"version"`
+	Parameters  []Parameter `yaml:"parameters"`
+	Extends     string      `yaml:"extends"`
+	Includes    []struct {
+		Template string `yaml:"template"`
+	} `yaml:"includes"`
+	Fragment     bool `yaml:"fragment"`
 	WorkflowSpec struct {
 		Version string        `yaml:"version"`
 		Name    string        `yaml:"name"`
@@ -30,48 +51,244 @@ type Parameter struct {
 	Validation  map[string]interface{} `yaml:"validation,omitempty"`
 }
 
+// FileResult is the validation result for one template file
+type FileResult struct {
+	File       string   `json:"file"`
+	Valid      bool     `json:"valid"`
+	Name       string   `json:"name,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	Parameters int      `json:"parameters"`
+	Servers    int      `json:"servers"`
+	Nodes      int      `json:"nodes"`
+	Edges      int      `json:"edges"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// Report summarizes the validation of every template
+type Report struct {
+	Total   int          `json:"total"`
+	Passed  int          `json:"passed"`
+	Failed  int          `json:"failed"`
+	Results []FileResult `json:"results"`
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <template-file>\n", os.Args[0])
-		os.Exit(1)
+	jsonOutput := flag.Bool("json", false, "Write a JSON report to stdout")
+	workers := flag.Int("workers", runtime.NumCPU(), "Number of templates to validate in parallel")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-json] [-workers n] <template-file|directory>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Directories are searched recursively for .yaml and .yml files.\n")
+		fmt.Fprintf(os.Stderr, "Exits 0 if every template is valid, 1 if any is invalid, 2 on usage errors.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(exitUsage)
 	}
 
-	data, err := os.ReadFile(os.Args[1])
+	files, err := collectTemplates(flag.Args())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no template files found\n")
+		os.Exit(exitUsage)
+	}
+
+	report := validateAll(files, *workers)
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(exitInvalid)
+	}
+	os.Exit(exitValid)
+}
+
+// collectTemplates expands directories into the YAML files beneath them
+func collectTemplates(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(p))
+			if !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				found = append(found, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// validateAll validates files in parallel, keeping results in input order
+func validateAll(files []string, workers int) Report {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]FileResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = validateFile(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	report := Report{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.Valid {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// validateFile checks one template, collecting every problem found
+func validateFile(path string) FileResult {
+	result := FileResult{File: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.Errors = []string{fmt.Sprintf("reading file: %v", err)}
+		return result
 	}
 
 	var template WorkflowTemplate
 	if err := yaml.Unmarshal(data, &template); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing YAML: %v\n", err)
-		os.Exit(1)
+		result.Errors = []string{fmt.Sprintf("parsing YAML: %v", err)}
+		return result
 	}
 
+	result.Name = template.Name
+	result.Version = template.Version
+	result.Parameters = len(template.Parameters)
+	result.Servers = len(template.WorkflowSpec.Servers)
+	result.Nodes = len(template.WorkflowSpec.Nodes)
+	result.Edges = len(template.WorkflowSpec.Edges)
+
 	// Basic validation
 	if template.Name == "" {
-		fmt.Fprintf(os.Stderr, "Error: template name is required\n")
-		os.Exit(1)
+		result.Errors = append(result.Errors, "template name is required")
 	}
-
 	if template.Version == "" {
-		fmt.Fprintf(os.Stderr, "Error: template version is required\n")
-		os.Exit(1)
+		result.Errors = append(result.Errors, "template version is required")
 	}
-
 	if template.WorkflowSpec.Version == "" {
-		fmt.Fprintf(os.Stderr, "Error: workflow_spec.version is required\n")
-		os.Exit(1)
+		result.Errors = append(result.Errors, "workflow_spec.version is required")
+	}
+	composed := template.Extends != "" || len(template.Includes) > 0
+	if len(template.WorkflowSpec.Nodes) == 0 && !composed {
+		result.Errors = append(result.Errors, "workflow_spec.nodes cannot be empty")
+	}
+
+	// Deep validation against the workflow engine's rules
+	if len(result.Errors) == 0 {
+		result.Errors = append(result.Errors, deepValidate(path, data)...)
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// deepValidate composes the template with any templates it extends or
+// includes from the same directory, instantiates the workflow_spec and runs
+// it through workflow.ValidateTemplate, returning one message per problem
+func deepValidate(path string, data []byte) []string {
+	var template workflow.WorkflowTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return []string{fmt.Sprintf("parsing template: %v", err)}
 	}
 
-	if len(template.WorkflowSpec.Nodes) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: workflow_spec.nodes cannot be empty\n")
-		os.Exit(1)
+	composed, err := workflow.ComposeTemplate(&template, workflow.DirectoryTemplateResolver(filepath.Dir(path)))
+	if err != nil {
+		return []string{fmt.Sprintf("composing template: %v", err)}
 	}
 
-	fmt.Printf("✓ Template '%s' v%s is valid\n", template.Name, template.Version)
-	fmt.Printf("  - Parameters: %d\n", len(template.Parameters))
-	fmt.Printf("  - Servers: %d\n", len(template.WorkflowSpec.Servers))
-	fmt.Printf("  - Nodes: %d\n", len(template.WorkflowSpec.Nodes))
-	fmt.Printf("  - Edges: %d\n", len(template.WorkflowSpec.Edges))
+	err = workflow.ValidateTemplate(composed)
+	if err == nil {
+		return nil
+	}
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		// Expression errors carry a multi-line source excerpt; keep the summary
+		msg, _, _ := strings.Cut(e.Error(), "\n")
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// printReport writes the human-readable report. Failures go to stderr.
+func printReport(report Report) {
+	for _, result := range report.Results {
+		if !result.Valid {
+			fmt.Fprintf(os.Stderr, "✗ %s\n", result.File)
+			for _, msg := range result.Errors {
+				fmt.Fprintf(os.Stderr, "  Error: %s\n", msg)
+			}
+			continue
+		}
+
+		fmt.Printf("✓ Template '%s' v%s is valid", result.Name, result.Version)
+		if report.Total > 1 {
+			fmt.Printf(" (%s)", result.File)
+		}
+		fmt.Println()
+		fmt.Printf("  - Parameters: %d\n", result.Parameters)
+		fmt.Printf("  - Servers: %d\n", result.Servers)
+		fmt.Printf("  - Nodes: %d\n", result.Nodes)
+		fmt.Printf("  - Edges: %d\n", result.Edges)
+	}
+
+	if report.Total > 1 {
+		fmt.Printf("\n%d template(s): %d passed, %d failed\n", report.Total, report.Passed, report.Failed)
+	}
 }
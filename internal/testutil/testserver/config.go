@@ -21,10 +21,17 @@ type ServerConfig struct {
 	// Logging
 	LogSecurityEvents bool   // Whether to log security violations
 	LogFilePath       string // Path to security audit log (empty = stderr)
+	LogMaxSize        int64  // Rotate the log file once it reaches this size in bytes
+	LogMaxBackups     int    // Number of rotated log files to keep (path.1 ... path.N)
 
 	// Performance
 	ReadTimeout  time.Duration // Timeout for file read operations
 	WriteTimeout time.Duration // Timeout for file write operations
+
+	// Testing
+	Faults       *FaultConfig // Fault injection for resilience tests (nil = disabled)
+	Fixtures     *Fixtures    // Resources and prompts to serve (nil = FixturesFile or defaults)
+	FixturesFile string       // JSON file with fixtures, used when Fixtures is nil
 }
 
 // DefaultConfig returns a secure default configuration.
@@ -34,6 +41,8 @@ type ServerConfig struct {
 //   - MaxFileSize: 10MB
 //   - LogSecurityEvents: true
 //   - LogFilePath: "" (stderr)
+//   - LogMaxSize: 10MB
+//   - LogMaxBackups: 3
 //   - ReadTimeout: 5 seconds
 //   - WriteTimeout: 5 seconds
 func DefaultConfig() *ServerConfig {
@@ -42,6 +51,8 @@ func DefaultConfig() *ServerConfig {
 		MaxFileSize:       10 * 1024 * 1024, // 10MB
 		LogSecurityEvents: true,
 		LogFilePath:       "",
+		LogMaxSize:        10 * 1024 * 1024, // 10MB
+		LogMaxBackups:     3,
 		ReadTimeout:       5 * time.Second,
 		WriteTimeout:      5 * time.Second,
 	}
@@ -58,6 +69,11 @@ func DefaultConfig() *ServerConfig {
 //   - GOFLOW_TESTSERVER_ALLOWED_DIR: Override allowed directory
 //   - GOFLOW_TESTSERVER_MAX_FILE_SIZE: Override max file size (bytes)
 //   - GOFLOW_TESTSERVER_LOG_SECURITY: Override security logging (true/false)
+//   - GOFLOW_TESTSERVER_LOG_FILE: Write security logs as JSON lines to this file
+//   - GOFLOW_TESTSERVER_LOG_MAX_SIZE: Log file rotation size (bytes)
+//   - GOFLOW_TESTSERVER_LOG_MAX_BACKUPS: Number of rotated log files to keep
+//   - GOFLOW_TESTSERVER_FAULT_*: Fault injection (see loadFaultConfig)
+//   - GOFLOW_TESTSERVER_FIXTURES: Path to a JSON resources/prompts fixtures file
 func LoadConfig() *ServerConfig {
 	config := DefaultConfig()
 
@@ -85,6 +101,27 @@ func LoadConfig() *ServerConfig {
 		// If parsing fails, keep the default
 	}
 
+	if logFile := os.Getenv("GOFLOW_TESTSERVER_LOG_FILE"); logFile != "" {
+		config.LogFilePath = logFile
+	}
+
+	if logMaxSizeStr := os.Getenv("GOFLOW_TESTSERVER_LOG_MAX_SIZE"); logMaxSizeStr != "" {
+		if logMaxSize, err := strconv.ParseInt(logMaxSizeStr, 10, 64); err == nil && logMaxSize > 0 {
+			config.LogMaxSize = logMaxSize
+		}
+		// If parsing fails or value is not positive, keep the default
+	}
+
+	if logMaxBackupsStr := os.Getenv("GOFLOW_TESTSERVER_LOG_MAX_BACKUPS"); logMaxBackupsStr != "" {
+		if logMaxBackups, err := strconv.Atoi(logMaxBackupsStr); err == nil && logMaxBackups >= 0 {
+			config.LogMaxBackups = logMaxBackups
+		}
+		// If parsing fails or value is negative, keep the default
+	}
+
+	config.Faults = loadFaultConfig()
+	config.FixturesFile = os.Getenv("GOFLOW_TESTSERVER_FIXTURES")
+
 	return config
 }
 
@@ -95,6 +132,8 @@ func LoadConfig() *ServerConfig {
 //   - AllowedDirectory does not exist
 //   - AllowedDirectory is not a directory
 //   - MaxFileSize is not positive
+//   - LogMaxSize is not positive or LogMaxBackups is negative while LogFilePath is set
+//   - Faults has out-of-range latencies or probabilities
 func (c *ServerConfig) Validate() error {
 	// Validate AllowedDirectory is not empty
 	if c.AllowedDirectory == "" {
@@ -125,5 +164,19 @@ func (c *ServerConfig) Validate() error {
 		return fmt.Errorf("max file size must be positive, got %d", c.MaxFileSize)
 	}
 
+	// Validate log rotation settings when logging to a file
+	if c.LogFilePath != "" {
+		if c.LogMaxSize <= 0 {
+			return fmt.Errorf("log max size must be positive, got %d", c.LogMaxSize)
+		}
+		if c.LogMaxBackups < 0 {
+			return fmt.Errorf("log max backups cannot be negative, got %d", c.LogMaxBackups)
+		}
+	}
+
+	if err := c.Faults.Validate(); err != nil {
+		return fmt.Errorf("invalid fault injection config: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,307 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FaultConfig configures fault injection for client resilience testing.
+//
+// Faults apply to every request except initialize, so clients can always
+// establish a session. With the same Seed and the same request sequence the
+// injected faults are identical between runs.
+type FaultConfig struct {
+	Seed int64 // Random seed (0 = seed from the current time, logged at startup)
+
+	LatencyMin time.Duration // Minimum added latency per request
+	LatencyMax time.Duration // Maximum added latency per request
+
+	ErrorRate      float64            // Probability [0,1] that a tool call fails
+	ToolErrorRates map[string]float64 // Per-tool failure probability, overrides ErrorRate
+
+	MalformedRate float64 // Probability [0,1] of a truncated, unparseable response
+	DropRate      float64 // Probability [0,1] that no response is written
+}
+
+// Enabled reports whether any fault is configured.
+func (f *FaultConfig) Enabled() bool {
+	if f == nil {
+		return false
+	}
+	return f.LatencyMax > 0 || f.ErrorRate > 0 || len(f.ToolErrorRates) > 0 ||
+		f.MalformedRate > 0 || f.DropRate > 0
+}
+
+// Validate checks that latencies and probabilities are in range.
+func (f *FaultConfig) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	if f.LatencyMin < 0 || f.LatencyMax < 0 {
+		return fmt.Errorf("fault latency cannot be negative")
+	}
+	if f.LatencyMax < f.LatencyMin {
+		return fmt.Errorf("fault latency max (%s) is less than min (%s)", f.LatencyMax, f.LatencyMin)
+	}
+
+	rates := map[string]float64{
+		"error rate":     f.ErrorRate,
+		"malformed rate": f.MalformedRate,
+		"drop rate":      f.DropRate,
+	}
+	for tool, rate := range f.ToolErrorRates {
+		rates["error rate for "+tool] = rate
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("fault %s must be between 0 and 1, got %v", name, rate)
+		}
+	}
+
+	return nil
+}
+
+// errorRate returns the failure probability for a tool
+func (f *FaultConfig) errorRate(tool string) float64 {
+	if rate, ok := f.ToolErrorRates[tool]; ok {
+		return rate
+	}
+	return f.ErrorRate
+}
+
+// String summarizes the configuration for the startup log.
+func (f *FaultConfig) String() string {
+	parts := []string{fmt.Sprintf("seed=%d", f.Seed)}
+	if f.LatencyMax > 0 {
+		parts = append(parts, fmt.Sprintf("latency=%s-%s", f.LatencyMin, f.LatencyMax))
+	}
+	if f.ErrorRate > 0 {
+		parts = append(parts, fmt.Sprintf("error_rate=%v", f.ErrorRate))
+	}
+	if len(f.ToolErrorRates) > 0 {
+		parts = append(parts, "tool_error_rates="+formatToolRates(f.ToolErrorRates))
+	}
+	if f.MalformedRate > 0 {
+		parts = append(parts, fmt.Sprintf("malformed_rate=%v", f.MalformedRate))
+	}
+	if f.DropRate > 0 {
+		parts = append(parts, fmt.Sprintf("drop_rate=%v", f.DropRate))
+	}
+	return strings.Join(parts, " ")
+}
+
+// faultKind is the response fault chosen for a request
+type faultKind int
+
+const (
+	faultNone faultKind = iota
+	faultToolError
+	faultMalformed
+	faultDrop
+)
+
+// faultInjector draws faults from a seeded source. Draws happen in a fixed
+// order per request so a seed reproduces the same fault sequence.
+type faultInjector struct {
+	config *FaultConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newFaultInjector creates an injector, choosing a seed if none is set
+func newFaultInjector(config *FaultConfig) *faultInjector {
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	return &faultInjector{
+		config: config,
+		rng:    rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+// next decides the latency and fault for one request. tool is the tool
+// name for tools/call requests and empty otherwise.
+func (fi *faultInjector) next(tool string) (time.Duration, faultKind) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	var latency time.Duration
+	if spread := fi.config.LatencyMax - fi.config.LatencyMin; spread > 0 {
+		latency = fi.config.LatencyMin + time.Duration(fi.rng.Int63n(int64(spread)+1))
+	} else {
+		latency = fi.config.LatencyMin
+	}
+
+	// Always draw all three so the sequence doesn't depend on which fault fired
+	dropDraw := fi.rng.Float64()
+	malformedDraw := fi.rng.Float64()
+	errorDraw := fi.rng.Float64()
+
+	switch {
+	case dropDraw < fi.config.DropRate:
+		return latency, faultDrop
+	case malformedDraw < fi.config.MalformedRate:
+		return latency, faultMalformed
+	case tool != "" && errorDraw < fi.config.errorRate(tool):
+		return latency, faultToolError
+	default:
+		return latency, faultNone
+	}
+}
+
+// injectFault applies latency and picks a fault for req. Returns true when
+// the request was answered with an injected error and must not be handled.
+func (s *Server) injectFault(req *JSONRPCRequest) bool {
+	var tool string
+	if req.Method == "tools/call" {
+		var params ToolCallParams
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			tool = params.Name
+		}
+	}
+
+	latency, kind := s.faults.next(tool)
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	switch kind {
+	case faultToolError:
+		s.logFault(req, "error")
+		s.writeError(req.ID, -32603, "Tool execution failed", fmt.Sprintf("injected fault in %s", tool))
+		return true
+	case faultMalformed:
+		s.logFault(req, "malformed")
+	case faultDrop:
+		s.logFault(req, "drop")
+	}
+	s.responseFault = kind
+	return false
+}
+
+// logFault records an injected fault on stderr
+func (s *Server) logFault(req *JSONRPCRequest, fault string) {
+	_, _ = fmt.Fprintf(s.stderr, "FAULT [testserver] Injected %s: method=%s id=%v\n", fault, req.Method, req.ID)
+}
+
+// loadFaultConfig reads fault injection settings from environment variables.
+// Returns nil when none are set. Invalid values are ignored.
+//
+// Environment variables:
+//   - GOFLOW_TESTSERVER_FAULT_SEED: Random seed (integer)
+//   - GOFLOW_TESTSERVER_FAULT_LATENCY: Latency range, e.g. "10ms-200ms" or "50ms"
+//   - GOFLOW_TESTSERVER_FAULT_ERROR_RATE: Tool call failure probability (0-1)
+//   - GOFLOW_TESTSERVER_FAULT_TOOL_ERROR_RATES: Per-tool rates, e.g. "read_file=0.5,echo=0.1"
+//   - GOFLOW_TESTSERVER_FAULT_MALFORMED_RATE: Malformed response probability (0-1)
+//   - GOFLOW_TESTSERVER_FAULT_DROP_RATE: Dropped response probability (0-1)
+func loadFaultConfig() *FaultConfig {
+	faults := &FaultConfig{}
+	set := false
+
+	if seedStr := os.Getenv("GOFLOW_TESTSERVER_FAULT_SEED"); seedStr != "" {
+		if seed, err := strconv.ParseInt(strings.TrimSpace(seedStr), 10, 64); err == nil {
+			faults.Seed = seed
+			set = true
+		}
+	}
+
+	if latencyStr := os.Getenv("GOFLOW_TESTSERVER_FAULT_LATENCY"); latencyStr != "" {
+		if minLatency, maxLatency, err := parseLatencyRange(latencyStr); err == nil {
+			faults.LatencyMin, faults.LatencyMax = minLatency, maxLatency
+			set = true
+		}
+	}
+
+	rateVars := map[string]*float64{
+		"GOFLOW_TESTSERVER_FAULT_ERROR_RATE":     &faults.ErrorRate,
+		"GOFLOW_TESTSERVER_FAULT_MALFORMED_RATE": &faults.MalformedRate,
+		"GOFLOW_TESTSERVER_FAULT_DROP_RATE":      &faults.DropRate,
+	}
+	for name, target := range rateVars {
+		if rate, ok := parseRate(os.Getenv(name)); ok {
+			*target = rate
+			set = true
+		}
+	}
+
+	if toolRates := os.Getenv("GOFLOW_TESTSERVER_FAULT_TOOL_ERROR_RATES"); toolRates != "" {
+		for _, pair := range strings.Split(toolRates, ",") {
+			tool, rateStr, found := strings.Cut(pair, "=")
+			tool = strings.TrimSpace(tool)
+			if !found || tool == "" {
+				continue
+			}
+			if rate, ok := parseRate(rateStr); ok {
+				if faults.ToolErrorRates == nil {
+					faults.ToolErrorRates = make(map[string]float64)
+				}
+				faults.ToolErrorRates[tool] = rate
+				set = true
+			}
+		}
+	}
+
+	if !set {
+		return nil
+	}
+	return faults
+}
+
+// parseLatencyRange parses "min-max" or a single duration
+func parseLatencyRange(value string) (time.Duration, time.Duration, error) {
+	minStr, maxStr, isRange := strings.Cut(strings.TrimSpace(value), "-")
+
+	minLatency, err := time.ParseDuration(strings.TrimSpace(minStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return minLatency, minLatency, nil
+	}
+
+	maxLatency, err := time.ParseDuration(strings.TrimSpace(maxStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	if minLatency < 0 || maxLatency < minLatency {
+		return 0, 0, fmt.Errorf("invalid latency range %q", value)
+	}
+	return minLatency, maxLatency, nil
+}
+
+// parseRate parses a probability in [0,1]
+func parseRate(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, false
+	}
+	return rate, true
+}
+
+// formatToolRates renders per-tool rates in a stable order
+func formatToolRates(rates map[string]float64) string {
+	tools := make([]string, 0, len(rates))
+	for tool := range rates {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	pairs := make([]string, len(tools))
+	for i, tool := range tools {
+		pairs[i] = fmt.Sprintf("%s=%v", tool, rates[tool])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,239 @@
+package testserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/internal/testutil/testserver"
+)
+
+// newFaultServer creates a server with the given fault configuration
+func newFaultServer(t *testing.T, faults *testserver.FaultConfig) (*testserver.Server, *bytes.Buffer) {
+	t.Helper()
+
+	config := testserver.DefaultConfig()
+	config.AllowedDirectory = t.TempDir()
+	config.Faults = faults
+	server, err := testserver.NewServer(config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	server.SetStderr(&stderr)
+	return server, &stderr
+}
+
+// rawCall sends a request and returns the raw response line
+func rawCall(server *testserver.Server, id int, method, tool string) string {
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+	}
+	if tool != "" {
+		req["params"] = map[string]interface{}{
+			"name":      tool,
+			"arguments": map[string]interface{}{"message": "hi"},
+		}
+	}
+
+	var stdout bytes.Buffer
+	server.SetStdout(&stdout)
+
+	reqJSON, _ := json.Marshal(req)
+	server.SetStdin(bytes.NewBuffer(append(reqJSON, '\n')))
+	_ = server.ProcessSingleRequest()
+
+	return stdout.String()
+}
+
+// outcome classifies a raw response line
+func outcome(line string) string {
+	if line == "" {
+		return "drop"
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		return "malformed"
+	}
+	if _, ok := resp["error"]; ok {
+		return "error"
+	}
+	return "ok"
+}
+
+// TestFaults_DeterministicWithSeed verifies a seed reproduces the same fault sequence.
+func TestFaults_DeterministicWithSeed(t *testing.T) {
+	run := func(seed int64) []string {
+		server, _ := newFaultServer(t, &testserver.FaultConfig{
+			Seed:          seed,
+			ErrorRate:     0.3,
+			MalformedRate: 0.2,
+			DropRate:      0.2,
+		})
+		outcomes := make([]string, 50)
+		for i := range outcomes {
+			outcomes[i] = outcome(rawCall(server, i, "tools/call", "echo"))
+		}
+		return outcomes
+	}
+
+	first := run(42)
+	second := run(42)
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("Same seed produced different faults:\n%v\n%v", first, second)
+	}
+
+	seen := make(map[string]bool)
+	for _, o := range first {
+		seen[o] = true
+	}
+	for _, want := range []string{"ok", "error", "malformed", "drop"} {
+		if !seen[want] {
+			t.Errorf("Outcome %q never occurred in 50 calls: %v", want, first)
+		}
+	}
+}
+
+// TestFaults_PerToolErrorRates verifies per-tool rates override the default.
+func TestFaults_PerToolErrorRates(t *testing.T) {
+	server, stderr := newFaultServer(t, &testserver.FaultConfig{
+		Seed:           1,
+		ToolErrorRates: map[string]float64{"echo": 1},
+	})
+
+	for i := 0; i < 5; i++ {
+		if got := outcome(rawCall(server, i, "tools/call", "echo")); got != "error" {
+			t.Errorf("echo call %d outcome = %s, want error", i, got)
+		}
+		if got := outcome(rawCall(server, i, "tools/call", "delay_task")); got != "ok" {
+			t.Errorf("delay_task call %d outcome = %s, want ok", i, got)
+		}
+	}
+
+	if !strings.Contains(stderr.String(), "FAULT [testserver] Injected error") {
+		t.Errorf("Injected faults were not logged: %s", stderr.String())
+	}
+}
+
+// TestFaults_ResponseFaults verifies dropped and malformed responses.
+func TestFaults_ResponseFaults(t *testing.T) {
+	server, _ := newFaultServer(t, &testserver.FaultConfig{Seed: 1, DropRate: 1})
+	if got := outcome(rawCall(server, 1, "ping", "")); got != "drop" {
+		t.Errorf("outcome with DropRate=1 = %s, want drop", got)
+	}
+
+	// initialize is never faulted so clients can connect
+	if got := outcome(rawCall(server, 2, "initialize", "")); got != "ok" {
+		t.Errorf("initialize outcome = %s, want ok", got)
+	}
+
+	server, _ = newFaultServer(t, &testserver.FaultConfig{Seed: 1, MalformedRate: 1})
+	line := rawCall(server, 1, "tools/list", "")
+	if got := outcome(line); got != "malformed" {
+		t.Errorf("outcome with MalformedRate=1 = %s, want malformed", got)
+	}
+	if !strings.HasSuffix(line, "\n") {
+		t.Error("Malformed response should still be newline-terminated")
+	}
+}
+
+// TestFaults_Latency verifies latency stays within the configured range.
+func TestFaults_Latency(t *testing.T) {
+	server, _ := newFaultServer(t, &testserver.FaultConfig{
+		Seed:       1,
+		LatencyMin: 20 * time.Millisecond,
+		LatencyMax: 30 * time.Millisecond,
+	})
+
+	start := time.Now()
+	if got := outcome(rawCall(server, 1, "ping", "")); got != "ok" {
+		t.Errorf("outcome = %s, want ok", got)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("request took %v, want at least 20ms", elapsed)
+	}
+}
+
+// TestFaultConfig_Validate verifies out-of-range settings are rejected.
+func TestFaultConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		faults  *testserver.FaultConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", &testserver.FaultConfig{ErrorRate: 0.5, LatencyMin: time.Millisecond, LatencyMax: time.Second}, false},
+		{"rate above one", &testserver.FaultConfig{DropRate: 1.5}, true},
+		{"negative tool rate", &testserver.FaultConfig{ToolErrorRates: map[string]float64{"echo": -0.1}}, true},
+		{"inverted latency", &testserver.FaultConfig{LatencyMin: time.Second, LatencyMax: time.Millisecond}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.faults.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestLoadConfig_FaultEnvironmentVariables verifies fault settings are read from env.
+func TestLoadConfig_FaultEnvironmentVariables(t *testing.T) {
+	envVars := map[string]string{
+		"GOFLOW_TESTSERVER_FAULT_SEED":             "7",
+		"GOFLOW_TESTSERVER_FAULT_LATENCY":          "10ms-50ms",
+		"GOFLOW_TESTSERVER_FAULT_ERROR_RATE":       "0.25",
+		"GOFLOW_TESTSERVER_FAULT_TOOL_ERROR_RATES": "read_file=0.5, echo=1,bogus=2",
+		"GOFLOW_TESTSERVER_FAULT_DROP_RATE":        "not-a-number",
+	}
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	faults := testserver.LoadConfig().Faults
+	if faults == nil {
+		t.Fatal("LoadConfig() Faults = nil, want fault config")
+	}
+	if faults.Seed != 7 {
+		t.Errorf("Seed = %d, want 7", faults.Seed)
+	}
+	if faults.LatencyMin != 10*time.Millisecond || faults.LatencyMax != 50*time.Millisecond {
+		t.Errorf("Latency = %s-%s, want 10ms-50ms", faults.LatencyMin, faults.LatencyMax)
+	}
+	if faults.ErrorRate != 0.25 {
+		t.Errorf("ErrorRate = %v, want 0.25", faults.ErrorRate)
+	}
+	if len(faults.ToolErrorRates) != 2 || faults.ToolErrorRates["read_file"] != 0.5 || faults.ToolErrorRates["echo"] != 1 {
+		t.Errorf("ToolErrorRates = %v, want read_file=0.5 echo=1", faults.ToolErrorRates)
+	}
+	if faults.DropRate != 0 {
+		t.Errorf("DropRate = %v, invalid value should be ignored", faults.DropRate)
+	}
+}
+
+// TestLoadConfig_NoFaults verifies fault injection is off by default.
+func TestLoadConfig_NoFaults(t *testing.T) {
+	for _, key := range []string{
+		"GOFLOW_TESTSERVER_FAULT_SEED",
+		"GOFLOW_TESTSERVER_FAULT_LATENCY",
+		"GOFLOW_TESTSERVER_FAULT_ERROR_RATE",
+		"GOFLOW_TESTSERVER_FAULT_TOOL_ERROR_RATES",
+		"GOFLOW_TESTSERVER_FAULT_MALFORMED_RATE",
+		"GOFLOW_TESTSERVER_FAULT_DROP_RATE",
+	} {
+		if _, ok := os.LookupEnv(key); ok {
+			t.Skipf("%s is set in the environment", key)
+		}
+	}
+
+	if faults := testserver.LoadConfig().Faults; faults != nil {
+		t.Errorf("LoadConfig() Faults = %+v, want nil", faults)
+	}
+}
@@ -0,0 +1,288 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileEntry describes a file or directory in list_directory and file_info results
+type fileEntry struct {
+	Name     string `json:"name"`
+	Path     string `json:"path,omitempty"`
+	IsDir    bool   `json:"is_dir"`
+	Size     int64  `json:"size"`
+	Mode     string `json:"mode"`
+	Modified string `json:"modified"`
+}
+
+// newFileEntry builds a fileEntry from file info
+func newFileEntry(info os.FileInfo) fileEntry {
+	return fileEntry{
+		Name:     info.Name(),
+		IsDir:    info.IsDir(),
+		Size:     info.Size(),
+		Mode:     info.Mode().Perm().String(),
+		Modified: info.ModTime().UTC().Format(time.RFC3339),
+	}
+}
+
+// fileToolDefinitions returns the tools/list entries for the filesystem tools
+func fileToolDefinitions() []map[string]interface{} {
+	pathProperty := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "string",
+			"description": description,
+		}
+	}
+
+	return []map[string]interface{}{
+		{
+			"name":        "list_directory",
+			"description": "Lists the entries of a directory",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": pathProperty("Path to the directory to list (default: allowed directory)"),
+				},
+			},
+		},
+		{
+			"name":        "delete_file",
+			"description": "Deletes a file",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": pathProperty("Path to the file to delete"),
+				},
+				"required": []string{"path"},
+			},
+		},
+		{
+			"name":        "move_file",
+			"description": "Moves or renames a file",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"source":      pathProperty("Path to the file to move"),
+					"destination": pathProperty("New path for the file"),
+					"overwrite": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Replace the destination if it exists (default: false)",
+					},
+				},
+				"required": []string{"source", "destination"},
+			},
+		},
+		{
+			"name":        "file_info",
+			"description": "Returns metadata for a file or directory",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": pathProperty("Path to the file or directory"),
+				},
+				"required": []string{"path"},
+			},
+		},
+	}
+}
+
+func (s *Server) handleListDirectory(id interface{}, args map[string]interface{}) {
+	path := "."
+	if value, exists := args["path"]; exists {
+		str, ok := value.(string)
+		if !ok {
+			s.writeError(id, -32602, "Invalid params", "path must be a string")
+			return
+		}
+		if str != "" {
+			path = str
+		}
+	}
+
+	// Validate path using PathValidator (SECURITY: prevent directory traversal)
+	validPath, err := s.validator.Validate(path)
+	if err != nil {
+		s.logSecurityViolation("list", path, err)
+		s.writeError(id, -32602, "Invalid file path", err.Error())
+		return
+	}
+
+	dirEntries, err := os.ReadDir(validPath)
+	if err != nil {
+		s.writeError(id, -32603, "Internal error", err.Error())
+		return
+	}
+
+	entries := make([]fileEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil {
+			// Entry removed while listing
+			continue
+		}
+		entry := newFileEntry(info)
+		entry.Path = filepath.ToSlash(filepath.Join(path, dirEntry.Name()))
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	s.writeJSONText(id, map[string]interface{}{
+		"path":    path,
+		"entries": entries,
+	})
+}
+
+func (s *Server) handleDeleteFile(id interface{}, args map[string]interface{}) {
+	path, ok := args["path"].(string)
+	if !ok {
+		s.writeError(id, -32602, "Invalid params", "path must be a string")
+		return
+	}
+
+	// Validate path using PathValidator (SECURITY: prevent directory traversal)
+	validPath, err := s.validator.Validate(path)
+	if err != nil {
+		s.logSecurityViolation("delete", path, err)
+		s.writeError(id, -32602, "Invalid file path", err.Error())
+		return
+	}
+
+	info, err := os.Stat(validPath)
+	if err != nil {
+		s.writeError(id, -32603, "Internal error", err.Error())
+		return
+	}
+	if info.IsDir() {
+		s.writeError(id, -32602, "Invalid params", fmt.Sprintf("%s is a directory", path))
+		return
+	}
+
+	if err := os.Remove(validPath); err != nil {
+		s.writeError(id, -32603, "Internal error", err.Error())
+		return
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Successfully deleted %s", path),
+			},
+		},
+	}
+	s.writeResponse(id, result)
+}
+
+func (s *Server) handleMoveFile(id interface{}, args map[string]interface{}) {
+	source, ok := args["source"].(string)
+	if !ok {
+		s.writeError(id, -32602, "Invalid params", "source must be a string")
+		return
+	}
+	destination, ok := args["destination"].(string)
+	if !ok {
+		s.writeError(id, -32602, "Invalid params", "destination must be a string")
+		return
+	}
+	overwrite, _ := args["overwrite"].(bool)
+
+	// Validate both paths using PathValidator (SECURITY: prevent directory traversal)
+	validSource, err := s.validator.Validate(source)
+	if err != nil {
+		s.logSecurityViolation("move", source, err)
+		s.writeError(id, -32602, "Invalid file path", err.Error())
+		return
+	}
+	validDestination, err := s.validator.Validate(destination)
+	if err != nil {
+		s.logSecurityViolation("move", destination, err)
+		s.writeError(id, -32602, "Invalid file path", err.Error())
+		return
+	}
+
+	info, err := os.Stat(validSource)
+	if err != nil {
+		s.writeError(id, -32603, "Internal error", err.Error())
+		return
+	}
+	if info.IsDir() {
+		s.writeError(id, -32602, "Invalid params", fmt.Sprintf("%s is a directory", source))
+		return
+	}
+
+	if destInfo, err := os.Stat(validDestination); err == nil {
+		if destInfo.IsDir() {
+			s.writeError(id, -32602, "Invalid params", fmt.Sprintf("%s is a directory", destination))
+			return
+		}
+		if !overwrite {
+			s.writeError(id, -32602, "Destination exists", destination)
+			return
+		}
+	}
+
+	if err := os.Rename(validSource, validDestination); err != nil {
+		s.writeError(id, -32603, "Internal error", err.Error())
+		return
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": fmt.Sprintf("Successfully moved %s to %s", source, destination),
+			},
+		},
+	}
+	s.writeResponse(id, result)
+}
+
+func (s *Server) handleFileInfo(id interface{}, args map[string]interface{}) {
+	path, ok := args["path"].(string)
+	if !ok {
+		s.writeError(id, -32602, "Invalid params", "path must be a string")
+		return
+	}
+
+	// Validate path using PathValidator (SECURITY: prevent directory traversal)
+	validPath, err := s.validator.Validate(path)
+	if err != nil {
+		s.logSecurityViolation("stat", path, err)
+		s.writeError(id, -32602, "Invalid file path", err.Error())
+		return
+	}
+
+	info, err := os.Stat(validPath)
+	if err != nil {
+		s.writeError(id, -32603, "Internal error", err.Error())
+		return
+	}
+
+	entry := newFileEntry(info)
+	entry.Path = path
+	s.writeJSONText(id, entry)
+}
+
+// writeJSONText responds with value encoded as JSON in a single text content item
+func (s *Server) writeJSONText(id interface{}, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.writeError(id, -32603, "Internal error", err.Error())
+		return
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": string(data),
+			},
+		},
+	}
+	s.writeResponse(id, result)
+}
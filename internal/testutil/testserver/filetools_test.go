@@ -0,0 +1,240 @@
+package testserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/internal/testutil/testserver"
+)
+
+// callTool sends a single tools/call request and returns the decoded response
+func callTool(t *testing.T, server *testserver.Server, name string, args map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]interface{}{
+			"name":      name,
+			"arguments": args,
+		},
+	}
+
+	var stdout bytes.Buffer
+	server.SetStdout(&stdout)
+
+	reqJSON, _ := json.Marshal(req)
+	stdin := bytes.NewBuffer(reqJSON)
+	stdin.WriteString("\n")
+	server.SetStdin(stdin)
+
+	server.ProcessSingleRequest()
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return resp
+}
+
+// resultText extracts the text of the first content item of a tool result
+func resultText(t *testing.T, resp map[string]interface{}) string {
+	t.Helper()
+
+	if errObj, ok := resp["error"]; ok {
+		t.Fatalf("Unexpected error response: %v", errObj)
+	}
+	result, _ := resp["result"].(map[string]interface{})
+	content, _ := result["content"].([]interface{})
+	if len(content) == 0 {
+		t.Fatalf("Response has no content: %v", resp)
+	}
+	item, _ := content[0].(map[string]interface{})
+	text, _ := item["text"].(string)
+	return text
+}
+
+// newFileToolServer creates a server rooted in a temp dir with a few files
+func newFileToolServer(t *testing.T) (*testserver.Server, string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "b.txt"), []byte("bravo"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("alpha!"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(tempDir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+
+	config := testserver.DefaultConfig()
+	config.AllowedDirectory = tempDir
+	config.LogSecurityEvents = false
+	server, err := testserver.NewServer(config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server, tempDir
+}
+
+// TestServer_ListDirectory verifies directory listings are sorted and complete.
+func TestServer_ListDirectory(t *testing.T) {
+	server, _ := newFileToolServer(t)
+
+	resp := callTool(t, server, "list_directory", map[string]interface{}{})
+
+	var listing struct {
+		Entries []struct {
+			Name  string `json:"name"`
+			IsDir bool   `json:"is_dir"`
+			Size  int64  `json:"size"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, resp)), &listing); err != nil {
+		t.Fatalf("Listing is not valid JSON: %v", err)
+	}
+
+	want := []string{"a.txt", "b.txt", "sub"}
+	if len(listing.Entries) != len(want) {
+		t.Fatalf("Got %d entries, want %d", len(listing.Entries), len(want))
+	}
+	for i, name := range want {
+		if listing.Entries[i].Name != name {
+			t.Errorf("Entry %d = %s, want %s", i, listing.Entries[i].Name, name)
+		}
+	}
+	if listing.Entries[0].Size != 6 {
+		t.Errorf("a.txt size = %d, want 6", listing.Entries[0].Size)
+	}
+	if !listing.Entries[2].IsDir {
+		t.Error("sub should be reported as a directory")
+	}
+}
+
+// TestServer_FileInfo verifies file metadata is returned.
+func TestServer_FileInfo(t *testing.T) {
+	server, _ := newFileToolServer(t)
+
+	resp := callTool(t, server, "file_info", map[string]interface{}{"path": "b.txt"})
+
+	var info struct {
+		Name     string `json:"name"`
+		Size     int64  `json:"size"`
+		IsDir    bool   `json:"is_dir"`
+		Modified string `json:"modified"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, resp)), &info); err != nil {
+		t.Fatalf("File info is not valid JSON: %v", err)
+	}
+	if info.Name != "b.txt" || info.Size != 5 || info.IsDir || info.Modified == "" {
+		t.Errorf("Unexpected file info: %+v", info)
+	}
+
+	resp = callTool(t, server, "file_info", map[string]interface{}{"path": "missing.txt"})
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// TestServer_DeleteFile verifies files are deleted and directories are refused.
+func TestServer_DeleteFile(t *testing.T) {
+	server, tempDir := newFileToolServer(t)
+
+	resp := callTool(t, server, "delete_file", map[string]interface{}{"path": "a.txt"})
+	resultText(t, resp)
+	if _, err := os.Stat(filepath.Join(tempDir, "a.txt")); !os.IsNotExist(err) {
+		t.Error("a.txt still exists after delete")
+	}
+
+	resp = callTool(t, server, "delete_file", map[string]interface{}{"path": "sub"})
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected error when deleting a directory")
+	}
+}
+
+// TestServer_MoveFile verifies moves and the overwrite guard.
+func TestServer_MoveFile(t *testing.T) {
+	server, tempDir := newFileToolServer(t)
+
+	resp := callTool(t, server, "move_file", map[string]interface{}{
+		"source":      "a.txt",
+		"destination": "sub/c.txt",
+	})
+	resultText(t, resp)
+	content, err := os.ReadFile(filepath.Join(tempDir, "sub", "c.txt"))
+	if err != nil || string(content) != "alpha!" {
+		t.Errorf("Moved file content = %q, err = %v", content, err)
+	}
+
+	// Existing destination requires overwrite
+	resp = callTool(t, server, "move_file", map[string]interface{}{
+		"source":      "b.txt",
+		"destination": "sub/c.txt",
+	})
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected error when destination exists")
+	}
+
+	resp = callTool(t, server, "move_file", map[string]interface{}{
+		"source":      "b.txt",
+		"destination": "sub/c.txt",
+		"overwrite":   true,
+	})
+	resultText(t, resp)
+	content, _ = os.ReadFile(filepath.Join(tempDir, "sub", "c.txt"))
+	if string(content) != "bravo" {
+		t.Errorf("Overwritten file content = %q, want bravo", content)
+	}
+}
+
+// TestServer_FileTools_MaliciousPaths verifies every file tool goes through PathValidator.
+func TestServer_FileTools_MaliciousPaths(t *testing.T) {
+	server, tempDir := newFileToolServer(t)
+
+	outside := filepath.Join(filepath.Dir(tempDir), "outside-"+filepath.Base(tempDir)+".txt")
+	if err := os.WriteFile(outside, []byte("keep me"), 0644); err != nil {
+		t.Fatalf("Failed to create outside file: %v", err)
+	}
+	defer os.Remove(outside)
+
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+	}{
+		{"list_directory", map[string]interface{}{"path": ".."}},
+		{"list_directory", map[string]interface{}{"path": "/etc"}},
+		{"file_info", map[string]interface{}{"path": outside}},
+		{"delete_file", map[string]interface{}{"path": outside}},
+		{"delete_file", map[string]interface{}{"path": "../" + filepath.Base(outside)}},
+		{"move_file", map[string]interface{}{"source": outside, "destination": "stolen.txt"}},
+		{"move_file", map[string]interface{}{"source": "b.txt", "destination": "../escaped.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			resp := callTool(t, server, tt.tool, tt.args)
+
+			errObj, ok := resp["error"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected error response, got %v", resp)
+			}
+			if msg, _ := errObj["message"].(string); !strings.Contains(msg, "Invalid file path") {
+				t.Errorf("Error message = %q, want Invalid file path", msg)
+			}
+		})
+	}
+
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("File outside allowed directory was modified: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "b.txt")); err != nil {
+		t.Errorf("b.txt should not have moved: %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Fixtures are the resources and prompts served by the resources/* and
+// prompts/* methods.
+type Fixtures struct {
+	Resources []ResourceFixture `json:"resources"`
+	Prompts   []PromptFixture   `json:"prompts"`
+}
+
+// ResourceFixture is a resource returned by resources/list and resources/read.
+// Exactly one of Text or Blob (base64) holds the content.
+type ResourceFixture struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Blob        string `json:"blob,omitempty"`
+}
+
+// PromptFixture is a prompt returned by prompts/list and prompts/get.
+// Message text may reference arguments as {{name}}.
+type PromptFixture struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+	Messages    []PromptMessage  `json:"messages"`
+}
+
+// PromptArgument describes an argument accepted by a prompt.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptMessage is one message of a prompt template.
+type PromptMessage struct {
+	Role string `json:"role"` // "user" or "assistant"
+	Text string `json:"text"`
+}
+
+// DefaultFixtures returns the built-in fixtures used when none are configured.
+func DefaultFixtures() *Fixtures {
+	return &Fixtures{
+		Resources: []ResourceFixture{
+			{
+				URI:         "test://greeting",
+				Name:        "greeting",
+				Description: "A plain text greeting",
+				MimeType:    "text/plain",
+				Text:        "Hello from the GoFlow test server",
+			},
+			{
+				URI:         "test://config.json",
+				Name:        "config",
+				Description: "A sample JSON document",
+				MimeType:    "application/json",
+				Text:        `{"name":"goflow","enabled":true}`,
+			},
+		},
+		Prompts: []PromptFixture{
+			{
+				Name:        "summarize",
+				Description: "Summarize a piece of text",
+				Arguments: []PromptArgument{
+					{Name: "text", Description: "Text to summarize", Required: true},
+					{Name: "style", Description: "Summary style (default: brief)"},
+				},
+				Messages: []PromptMessage{
+					{Role: "user", Text: "Summarize the following text in a {{style}} style:\n\n{{text}}"},
+				},
+			},
+		},
+	}
+}
+
+// LoadFixtures reads fixtures from a JSON file.
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures file: %w", err)
+	}
+
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures file %s: %w", path, err)
+	}
+	return &fixtures, nil
+}
+
+// Validate checks that resources and prompts are well formed and unique.
+func (f *Fixtures) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	uris := make(map[string]bool)
+	for i, resource := range f.Resources {
+		if resource.URI == "" {
+			return fmt.Errorf("resource %d: uri cannot be empty", i)
+		}
+		if uris[resource.URI] {
+			return fmt.Errorf("duplicate resource uri: %s", resource.URI)
+		}
+		uris[resource.URI] = true
+		if resource.Text != "" && resource.Blob != "" {
+			return fmt.Errorf("resource %s: text and blob are mutually exclusive", resource.URI)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, prompt := range f.Prompts {
+		if prompt.Name == "" {
+			return fmt.Errorf("prompt %d: name cannot be empty", i)
+		}
+		if names[prompt.Name] {
+			return fmt.Errorf("duplicate prompt name: %s", prompt.Name)
+		}
+		names[prompt.Name] = true
+		for _, message := range prompt.Messages {
+			if message.Role != "user" && message.Role != "assistant" {
+				return fmt.Errorf("prompt %s: invalid message role %q", prompt.Name, message.Role)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findResource returns the resource with the given URI
+func (f *Fixtures) findResource(uri string) (ResourceFixture, bool) {
+	for _, resource := range f.Resources {
+		if resource.URI == uri {
+			return resource, true
+		}
+	}
+	return ResourceFixture{}, false
+}
+
+// findPrompt returns the prompt with the given name
+func (f *Fixtures) findPrompt(name string) (PromptFixture, bool) {
+	for _, prompt := range f.Prompts {
+		if prompt.Name == name {
+			return prompt, true
+		}
+	}
+	return PromptFixture{}, false
+}
+
+type resourceReadParams struct {
+	URI string `json:"uri"`
+}
+
+type promptGetParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+func (s *Server) handleResourcesList(req *JSONRPCRequest) {
+	resources := make([]map[string]interface{}, 0, len(s.fixtures.Resources))
+	for _, resource := range s.fixtures.Resources {
+		entry := map[string]interface{}{
+			"uri":  resource.URI,
+			"name": resource.Name,
+		}
+		if resource.Description != "" {
+			entry["description"] = resource.Description
+		}
+		if resource.MimeType != "" {
+			entry["mimeType"] = resource.MimeType
+		}
+		resources = append(resources, entry)
+	}
+
+	s.writeResponse(req.ID, map[string]interface{}{
+		"resources": resources,
+	})
+}
+
+func (s *Server) handleResourcesRead(req *JSONRPCRequest) {
+	var params resourceReadParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		s.writeError(req.ID, -32602, "Invalid params", err.Error())
+		return
+	}
+	if params.URI == "" {
+		s.writeError(req.ID, -32602, "Invalid params", "uri is required")
+		return
+	}
+
+	resource, ok := s.fixtures.findResource(params.URI)
+	if !ok {
+		s.writeError(req.ID, -32002, "Resource not found", params.URI)
+		return
+	}
+
+	content := map[string]interface{}{
+		"uri": resource.URI,
+	}
+	if resource.MimeType != "" {
+		content["mimeType"] = resource.MimeType
+	}
+	if resource.Blob != "" {
+		content["blob"] = resource.Blob
+	} else {
+		content["text"] = resource.Text
+	}
+
+	s.writeResponse(req.ID, map[string]interface{}{
+		"contents": []map[string]interface{}{content},
+	})
+}
+
+func (s *Server) handlePromptsList(req *JSONRPCRequest) {
+	prompts := make([]map[string]interface{}, 0, len(s.fixtures.Prompts))
+	for _, prompt := range s.fixtures.Prompts {
+		entry := map[string]interface{}{
+			"name": prompt.Name,
+		}
+		if prompt.Description != "" {
+			entry["description"] = prompt.Description
+		}
+		if len(prompt.Arguments) > 0 {
+			entry["arguments"] = prompt.Arguments
+		}
+		prompts = append(prompts, entry)
+	}
+
+	s.writeResponse(req.ID, map[string]interface{}{
+		"prompts": prompts,
+	})
+}
+
+func (s *Server) handlePromptsGet(req *JSONRPCRequest) {
+	var params promptGetParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		s.writeError(req.ID, -32602, "Invalid params", err.Error())
+		return
+	}
+
+	prompt, ok := s.fixtures.findPrompt(params.Name)
+	if !ok {
+		s.writeError(req.ID, -32602, "Unknown prompt", params.Name)
+		return
+	}
+
+	// Fill in arguments; missing optional arguments become empty strings
+	replacements := make([]string, 0, 2*len(prompt.Arguments))
+	for _, arg := range prompt.Arguments {
+		value, provided := params.Arguments[arg.Name]
+		if arg.Required && !provided {
+			s.writeError(req.ID, -32602, "Missing required argument", arg.Name)
+			return
+		}
+		replacements = append(replacements, "{{"+arg.Name+"}}", value)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	messages := make([]map[string]interface{}, 0, len(prompt.Messages))
+	for _, message := range prompt.Messages {
+		messages = append(messages, map[string]interface{}{
+			"role": message.Role,
+			"content": map[string]interface{}{
+				"type": "text",
+				"text": replacer.Replace(message.Text),
+			},
+		})
+	}
+
+	result := map[string]interface{}{
+		"messages": messages,
+	}
+	if prompt.Description != "" {
+		result["description"] = prompt.Description
+	}
+	s.writeResponse(req.ID, result)
+}
@@ -0,0 +1,256 @@
+package testserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/internal/testutil/testserver"
+)
+
+// callMethod sends a single request and returns the decoded response
+func callMethod(t *testing.T, server *testserver.Server, method string, params interface{}) map[string]interface{} {
+	t.Helper()
+
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+	}
+	if params != nil {
+		req["params"] = params
+	}
+
+	var stdout bytes.Buffer
+	server.SetStdout(&stdout)
+
+	reqJSON, _ := json.Marshal(req)
+	stdin := bytes.NewBuffer(reqJSON)
+	stdin.WriteString("\n")
+	server.SetStdin(stdin)
+
+	server.ProcessSingleRequest()
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return resp
+}
+
+// newFixtureServer creates a server with the given fixtures (nil = defaults)
+func newFixtureServer(t *testing.T, fixtures *testserver.Fixtures) *testserver.Server {
+	t.Helper()
+
+	config := testserver.DefaultConfig()
+	config.AllowedDirectory = t.TempDir()
+	config.LogSecurityEvents = false
+	config.Fixtures = fixtures
+	server, err := testserver.NewServer(config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server
+}
+
+// errorCode returns the JSON-RPC error code of a response, or 0
+func errorCode(resp map[string]interface{}) int {
+	errObj, ok := resp["error"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	code, _ := errObj["code"].(float64)
+	return int(code)
+}
+
+func TestInitialize_AdvertisesResourcesAndPrompts(t *testing.T) {
+	server := newFixtureServer(t, nil)
+
+	resp := callMethod(t, server, "initialize", map[string]interface{}{})
+	result, _ := resp["result"].(map[string]interface{})
+	capabilities, _ := result["capabilities"].(map[string]interface{})
+	for _, capability := range []string{"tools", "resources", "prompts"} {
+		if _, ok := capabilities[capability]; !ok {
+			t.Errorf("Capability %q not advertised: %v", capability, capabilities)
+		}
+	}
+}
+
+func TestResources_ListAndRead(t *testing.T) {
+	server := newFixtureServer(t, &testserver.Fixtures{
+		Resources: []testserver.ResourceFixture{
+			{URI: "test://notes", Name: "notes", MimeType: "text/plain", Text: "remember the milk"},
+			{URI: "test://logo", Name: "logo", MimeType: "image/png", Blob: "iVBORw0KGgo="},
+		},
+	})
+
+	resp := callMethod(t, server, "resources/list", nil)
+	result, _ := resp["result"].(map[string]interface{})
+	resources, _ := result["resources"].([]interface{})
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %v", resp)
+	}
+	first, _ := resources[0].(map[string]interface{})
+	if first["uri"] != "test://notes" || first["mimeType"] != "text/plain" {
+		t.Errorf("Unexpected resource entry: %v", first)
+	}
+	if _, ok := first["text"]; ok {
+		t.Error("resources/list should not include content")
+	}
+
+	tests := []struct {
+		uri   string
+		field string
+		want  string
+	}{
+		{"test://notes", "text", "remember the milk"},
+		{"test://logo", "blob", "iVBORw0KGgo="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			resp := callMethod(t, server, "resources/read", map[string]interface{}{"uri": tt.uri})
+			result, _ := resp["result"].(map[string]interface{})
+			contents, _ := result["contents"].([]interface{})
+			if len(contents) != 1 {
+				t.Fatalf("Expected 1 content item, got %v", resp)
+			}
+			content, _ := contents[0].(map[string]interface{})
+			if content["uri"] != tt.uri || content[tt.field] != tt.want {
+				t.Errorf("Unexpected content: %v", content)
+			}
+		})
+	}
+}
+
+func TestResources_ReadErrors(t *testing.T) {
+	server := newFixtureServer(t, nil)
+
+	if code := errorCode(callMethod(t, server, "resources/read", map[string]interface{}{"uri": "test://missing"})); code != -32002 {
+		t.Errorf("Unknown resource: expected -32002, got %d", code)
+	}
+	if code := errorCode(callMethod(t, server, "resources/read", map[string]interface{}{})); code != -32602 {
+		t.Errorf("Missing uri: expected -32602, got %d", code)
+	}
+}
+
+func TestPrompts_ListAndGet(t *testing.T) {
+	server := newFixtureServer(t, nil)
+
+	resp := callMethod(t, server, "prompts/list", nil)
+	result, _ := resp["result"].(map[string]interface{})
+	prompts, _ := result["prompts"].([]interface{})
+	if len(prompts) != 1 {
+		t.Fatalf("Expected 1 default prompt, got %v", resp)
+	}
+	prompt, _ := prompts[0].(map[string]interface{})
+	args, _ := prompt["arguments"].([]interface{})
+	if prompt["name"] != "summarize" || len(args) != 2 {
+		t.Errorf("Unexpected prompt entry: %v", prompt)
+	}
+
+	resp = callMethod(t, server, "prompts/get", map[string]interface{}{
+		"name":      "summarize",
+		"arguments": map[string]string{"text": "Go is fun.", "style": "formal"},
+	})
+	result, _ = resp["result"].(map[string]interface{})
+	messages, _ := result["messages"].([]interface{})
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %v", resp)
+	}
+	message, _ := messages[0].(map[string]interface{})
+	content, _ := message["content"].(map[string]interface{})
+	text, _ := content["text"].(string)
+	if message["role"] != "user" || content["type"] != "text" {
+		t.Errorf("Unexpected message: %v", message)
+	}
+	if !strings.Contains(text, "formal style") || !strings.HasSuffix(text, "Go is fun.") {
+		t.Errorf("Arguments not substituted: %q", text)
+	}
+}
+
+func TestPrompts_GetErrors(t *testing.T) {
+	server := newFixtureServer(t, nil)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+	}{
+		{"unknown prompt", map[string]interface{}{"name": "missing"}},
+		{"missing required argument", map[string]interface{}{"name": "summarize", "arguments": map[string]string{"style": "brief"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := errorCode(callMethod(t, server, "prompts/get", tt.params)); code != -32602 {
+				t.Errorf("Expected -32602, got %d", code)
+			}
+		})
+	}
+}
+
+func TestFixtures_LoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	data := `{
+		"resources": [{"uri": "file://readme", "name": "readme", "text": "hi"}],
+		"prompts": [{"name": "greet", "messages": [{"role": "user", "text": "Hello {{who}}"}],
+		             "arguments": [{"name": "who", "required": true}]}]
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write fixtures: %v", err)
+	}
+
+	config := testserver.DefaultConfig()
+	config.AllowedDirectory = t.TempDir()
+	config.LogSecurityEvents = false
+	config.FixturesFile = path
+	server, err := testserver.NewServer(config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	resp := callMethod(t, server, "prompts/get", map[string]interface{}{
+		"name":      "greet",
+		"arguments": map[string]string{"who": "world"},
+	})
+	result, _ := resp["result"].(map[string]interface{})
+	messages, _ := result["messages"].([]interface{})
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %v", resp)
+	}
+	content, _ := messages[0].(map[string]interface{})["content"].(map[string]interface{})
+	if content["text"] != "Hello world" {
+		t.Errorf("Expected 'Hello world', got %v", content["text"])
+	}
+
+	config.FixturesFile = filepath.Join(t.TempDir(), "missing.json")
+	if _, err := testserver.NewServer(config); err == nil {
+		t.Error("Expected error for missing fixtures file")
+	}
+}
+
+func TestFixtures_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures *testserver.Fixtures
+		wantErr  bool
+	}{
+		{"nil", nil, false},
+		{"defaults", testserver.DefaultFixtures(), false},
+		{"empty uri", &testserver.Fixtures{Resources: []testserver.ResourceFixture{{Name: "x"}}}, true},
+		{"duplicate uri", &testserver.Fixtures{Resources: []testserver.ResourceFixture{{URI: "a://x"}, {URI: "a://x"}}}, true},
+		{"text and blob", &testserver.Fixtures{Resources: []testserver.ResourceFixture{{URI: "a://x", Text: "t", Blob: "b"}}}, true},
+		{"empty prompt name", &testserver.Fixtures{Prompts: []testserver.PromptFixture{{}}}, true},
+		{"duplicate prompt", &testserver.Fixtures{Prompts: []testserver.PromptFixture{{Name: "p"}, {Name: "p"}}}, true},
+		{"bad role", &testserver.Fixtures{Prompts: []testserver.PromptFixture{{Name: "p", Messages: []testserver.PromptMessage{{Role: "system"}}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fixtures.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
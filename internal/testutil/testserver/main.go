@@ -17,9 +17,17 @@ import (
 type Server struct {
 	config    *ServerConfig
 	validator *validation.PathValidator
+	fixtures  *Fixtures
 	stdin     io.Reader
 	stdout    io.Writer
 	stderr    io.Writer
+
+	// Security audit log file (nil when LogFilePath is empty)
+	securityLog *rotatingLog
+
+	// Fault injection (nil when disabled)
+	faults        *faultInjector
+	responseFault faultKind
 }
 
 type JSONRPCRequest struct {
@@ -52,6 +60,8 @@ type ToolCallParams struct {
 // Returns error if:
 //   - config is invalid (fails Validate())
 //   - Cannot create path validator
+//   - Fixtures cannot be loaded or are invalid
+//   - LogFilePath is set but cannot be opened
 //
 // Example:
 //
@@ -73,13 +83,40 @@ func NewServer(config *ServerConfig) (*Server, error) {
 		return nil, fmt.Errorf("failed to create path validator: %w", err)
 	}
 
-	return &Server{
+	// Resolve resource and prompt fixtures
+	fixtures := config.Fixtures
+	if fixtures == nil && config.FixturesFile != "" {
+		fixtures, err = LoadFixtures(config.FixturesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	if err := fixtures.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fixtures: %w", err)
+	}
+
+	server := &Server{
 		config:    config,
 		validator: validator,
+		fixtures:  fixtures,
 		stdin:     os.Stdin,
 		stdout:    os.Stdout,
 		stderr:    os.Stderr,
-	}, nil
+	}
+	if config.Faults.Enabled() {
+		server.faults = newFaultInjector(config.Faults)
+	}
+	if config.LogSecurityEvents && config.LogFilePath != "" {
+		server.securityLog, err = openRotatingLog(config.LogFilePath, config.LogMaxSize, config.LogMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return server, nil
 }
 
 // Start starts the test server and begins processing MCP requests.
@@ -89,10 +126,18 @@ func NewServer(config *ServerConfig) (*Server, error) {
 func (s *Server) Start() error {
 	// Log configuration at startup
 	if s.config.LogSecurityEvents {
-		log.Printf("Test server started: allowed_dir=%s max_size=%dMB security_log=%v",
+		logTarget := "stderr"
+		if s.securityLog != nil {
+			logTarget = s.config.LogFilePath
+		}
+		log.Printf("Test server started: allowed_dir=%s max_size=%dMB security_log=%v log_target=%s",
 			s.config.AllowedDirectory,
 			s.config.MaxFileSize/(1024*1024),
-			s.config.LogSecurityEvents)
+			s.config.LogSecurityEvents,
+			logTarget)
+	}
+	if s.faults != nil {
+		log.Printf("Fault injection enabled: %s", s.faults.config)
 	}
 
 	return s.run()
@@ -118,6 +163,13 @@ func (s *Server) run() error {
 }
 
 func (s *Server) handleRequest(req *JSONRPCRequest) {
+	if s.faults != nil && req.ID != nil && req.Method != "initialize" {
+		defer func() { s.responseFault = faultNone }()
+		if s.injectFault(req) {
+			return
+		}
+	}
+
 	switch req.Method {
 	case "initialize":
 		s.handleInitialize(req)
@@ -127,6 +179,14 @@ func (s *Server) handleRequest(req *JSONRPCRequest) {
 		s.handleToolsList(req)
 	case "tools/call":
 		s.handleToolsCall(req)
+	case "resources/list":
+		s.handleResourcesList(req)
+	case "resources/read":
+		s.handleResourcesRead(req)
+	case "prompts/list":
+		s.handlePromptsList(req)
+	case "prompts/get":
+		s.handlePromptsGet(req)
 	case "ping":
 		s.handlePing(req)
 	default:
@@ -138,7 +198,9 @@ func (s *Server) handleInitialize(req *JSONRPCRequest) {
 	result := map[string]interface{}{
 		"protocolVersion": "2024-11-05",
 		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
+			"tools":     map[string]interface{}{},
+			"resources": map[string]interface{}{},
+			"prompts":   map[string]interface{}{},
 		},
 		"serverInfo": map[string]interface{}{
 			"name":    "goflow-test-server",
@@ -218,6 +280,7 @@ func (s *Server) handleToolsList(req *JSONRPCRequest) {
 			},
 		},
 	}
+	tools = append(tools, fileToolDefinitions()...)
 
 	result := map[string]interface{}{
 		"tools": tools,
@@ -243,6 +306,14 @@ func (s *Server) handleToolsCall(req *JSONRPCRequest) {
 		s.handleFailingTool(req.ID, params.Arguments)
 	case "delay_task":
 		s.handleDelayTask(req.ID, params.Arguments)
+	case "list_directory":
+		s.handleListDirectory(req.ID, params.Arguments)
+	case "delete_file":
+		s.handleDeleteFile(req.ID, params.Arguments)
+	case "move_file":
+		s.handleMoveFile(req.ID, params.Arguments)
+	case "file_info":
+		s.handleFileInfo(req.ID, params.Arguments)
 	default:
 		s.writeError(req.ID, -32602, "Unknown tool", params.Name)
 	}
@@ -396,6 +467,14 @@ func (s *Server) write(resp JSONRPCResponse) {
 		_, _ = fmt.Fprintf(s.stderr, "Error marshaling response: %v\n", err)
 		return
 	}
+
+	switch s.responseFault {
+	case faultDrop:
+		return
+	case faultMalformed:
+		// Truncate mid-object so the line is not valid JSON
+		data = data[:len(data)/2]
+	}
 	_, _ = fmt.Fprintf(s.stdout, "%s\n", data)
 }
 
@@ -404,21 +483,29 @@ func (s *Server) write(resp JSONRPCResponse) {
 // Format: "SECURITY [testserver] Rejected {operation}: input={path} error={err}"
 //
 // This method implements the security logging requirement from the contract.
-// Logs are written to stderr (or LogFilePath if configured).
+// Logs are written to stderr, or as JSON lines to LogFilePath if configured
+// (rotated once the file reaches LogMaxSize).
 func (s *Server) logSecurityViolation(operation, path string, err error) {
 	if !s.config.LogSecurityEvents {
 		return
 	}
 
-	// Log to stderr by default (or configured log file)
-	logOutput := s.stderr
-	// TODO(Phase 4): Implement log file output when config.LogFilePath is set
-	// For now, always use stderr for simplicity
-	_ = s.config.LogFilePath // Explicitly acknowledge unused config field
+	if s.securityLog != nil {
+		s.writeSecurityRecord(operation, path, err)
+		return
+	}
 
 	logMsg := fmt.Sprintf("SECURITY [testserver] Rejected %s: input=%s error=%v\n",
 		operation, path, err)
-	_, _ = fmt.Fprint(logOutput, logMsg)
+	_, _ = fmt.Fprint(s.stderr, logMsg)
+}
+
+// Close releases resources held by the server, such as the security log file.
+func (s *Server) Close() error {
+	if s.securityLog == nil {
+		return nil
+	}
+	return s.securityLog.Close()
 }
 
 // Helper methods for testing
@@ -0,0 +1,144 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// securityRecord is one JSON line in the security audit log file.
+type securityRecord struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Component string `json:"component"`
+	Operation string `json:"operation"`
+	Input     string `json:"input"`
+	Error     string `json:"error"`
+}
+
+// rotatingLog appends lines to a file, rotating it once it would exceed
+// maxSize bytes. Rotated files are kept as path.1 (newest) to
+// path.{maxBackups} (oldest); older files are removed.
+type rotatingLog struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// openRotatingLog opens (or creates) the log file at path for appending
+func openRotatingLog(path string, maxSize int64, maxBackups int) (*rotatingLog, error) {
+	l := &rotatingLog{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *rotatingLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open security log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat security log: %w", err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// Write appends p as a single record, rotating first if needed.
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return 0, fmt.Errorf("security log is closed")
+	}
+
+	// A record larger than maxSize still gets written to a fresh file
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// rotate shifts existing backups up by one and starts a new file
+func (l *rotatingLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close security log: %w", err)
+	}
+	l.file = nil
+
+	if l.maxBackups > 0 {
+		_ = os.Remove(l.backupPath(l.maxBackups))
+		for i := l.maxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rotate security log: %w", err)
+			}
+		}
+		if err := os.Rename(l.path, l.backupPath(1)); err != nil {
+			return fmt.Errorf("failed to rotate security log: %w", err)
+		}
+	} else if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate security log: %w", err)
+	}
+
+	return l.open()
+}
+
+func (l *rotatingLog) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// Close closes the underlying file.
+func (l *rotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// writeSecurityRecord appends a JSON-line security record to the log file
+func (s *Server) writeSecurityRecord(operation, path string, err error) {
+	record := securityRecord{
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     "SECURITY",
+		Component: "testserver",
+		Operation: operation,
+		Input:     path,
+		Error:     fmt.Sprint(err),
+	}
+	data, marshalErr := json.Marshal(record)
+	if marshalErr != nil {
+		return
+	}
+
+	if _, writeErr := s.securityLog.Write(append(data, '\n')); writeErr != nil {
+		// Don't lose the record if the log file becomes unwritable
+		_, _ = fmt.Fprintf(s.stderr, "SECURITY [testserver] Failed to write security log: %v\n", writeErr)
+		_, _ = fmt.Fprintf(s.stderr, "SECURITY [testserver] Rejected %s: input=%s error=%v\n",
+			operation, path, err)
+	}
+}
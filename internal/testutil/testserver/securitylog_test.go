@@ -0,0 +1,157 @@
+package testserver_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dshills/goflow/internal/testutil/testserver"
+)
+
+// newLoggingServer creates a server that writes security logs to logPath
+func newLoggingServer(t *testing.T, logPath string, maxSize int64, maxBackups int) (*testserver.Server, *bytes.Buffer) {
+	t.Helper()
+
+	config := testserver.DefaultConfig()
+	config.AllowedDirectory = t.TempDir()
+	config.LogSecurityEvents = true
+	config.LogFilePath = logPath
+	config.LogMaxSize = maxSize
+	config.LogMaxBackups = maxBackups
+	server, err := testserver.NewServer(config)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	var stderr bytes.Buffer
+	server.SetStderr(&stderr)
+	return server, &stderr
+}
+
+// readLogRecords decodes every JSON line in a log file
+func readLogRecords(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	defer file.Close()
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Log line is not valid JSON: %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestSecurityLog_WritesJSONLines(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "security.log")
+	server, stderr := newLoggingServer(t, logPath, 1024*1024, 3)
+
+	callTool(t, server, "read_file", map[string]interface{}{"path": "../secret.txt"})
+	callTool(t, server, "write_file", map[string]interface{}{"path": "/etc/passwd", "content": "x"})
+
+	if stderr.Len() != 0 {
+		t.Errorf("Expected no stderr output when logging to a file, got %q", stderr.String())
+	}
+
+	records := readLogRecords(t, logPath)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d", len(records))
+	}
+
+	first := records[0]
+	if first["level"] != "SECURITY" || first["component"] != "testserver" {
+		t.Errorf("Unexpected record header: %v", first)
+	}
+	if first["operation"] != "read" || first["input"] != "../secret.txt" {
+		t.Errorf("Unexpected record: %v", first)
+	}
+	if first["error"] == "" || first["time"] == "" {
+		t.Errorf("Record missing error or time: %v", first)
+	}
+	if records[1]["operation"] != "write" || records[1]["input"] != "/etc/passwd" {
+		t.Errorf("Unexpected second record: %v", records[1])
+	}
+}
+
+func TestSecurityLog_Rotation(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "security.log")
+	// Small enough that every record rotates the file
+	server, _ := newLoggingServer(t, logPath, 64, 2)
+
+	for i := 0; i < 5; i++ {
+		callTool(t, server, "read_file", map[string]interface{}{"path": "../secret.txt"})
+	}
+
+	for _, path := range []string{logPath, logPath + ".1", logPath + ".2"} {
+		if records := readLogRecords(t, path); len(records) != 1 {
+			t.Errorf("%s: expected 1 record, got %d", filepath.Base(path), len(records))
+		}
+	}
+	if _, err := os.Stat(logPath + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Expected at most 2 backups, found %s.3 (err=%v)", filepath.Base(logPath), err)
+	}
+}
+
+func TestSecurityLog_AppendsToExistingFile(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "security.log")
+	if err := os.WriteFile(logPath, []byte(`{"operation":"earlier"}`+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to seed log file: %v", err)
+	}
+
+	server, _ := newLoggingServer(t, logPath, 1024*1024, 3)
+	callTool(t, server, "read_file", map[string]interface{}{"path": "../secret.txt"})
+
+	records := readLogRecords(t, logPath)
+	if len(records) != 2 || records[0]["operation"] != "earlier" {
+		t.Errorf("Expected existing record to be preserved, got %v", records)
+	}
+}
+
+func TestSecurityLog_InvalidPath(t *testing.T) {
+	config := testserver.DefaultConfig()
+	config.AllowedDirectory = t.TempDir()
+	config.LogFilePath = filepath.Join(t.TempDir(), "missing", "security.log")
+
+	if _, err := testserver.NewServer(config); err == nil {
+		t.Error("Expected error for unwritable log file path")
+	}
+}
+
+func TestLoadConfig_LogFileEnvironmentVariables(t *testing.T) {
+	t.Setenv("GOFLOW_TESTSERVER_LOG_FILE", "/tmp/security.log")
+	t.Setenv("GOFLOW_TESTSERVER_LOG_MAX_SIZE", "2048")
+	t.Setenv("GOFLOW_TESTSERVER_LOG_MAX_BACKUPS", "0")
+
+	config := testserver.LoadConfig()
+	if config.LogFilePath != "/tmp/security.log" {
+		t.Errorf("LogFilePath = %q, want /tmp/security.log", config.LogFilePath)
+	}
+	if config.LogMaxSize != 2048 {
+		t.Errorf("LogMaxSize = %d, want 2048", config.LogMaxSize)
+	}
+	if config.LogMaxBackups != 0 {
+		t.Errorf("LogMaxBackups = %d, want 0", config.LogMaxBackups)
+	}
+
+	t.Setenv("GOFLOW_TESTSERVER_LOG_MAX_SIZE", "-1")
+	t.Setenv("GOFLOW_TESTSERVER_LOG_MAX_BACKUPS", "many")
+	config = testserver.LoadConfig()
+	if config.LogMaxSize != testserver.DefaultConfig().LogMaxSize {
+		t.Errorf("Invalid LogMaxSize should keep default, got %d", config.LogMaxSize)
+	}
+	if config.LogMaxBackups != testserver.DefaultConfig().LogMaxBackups {
+		t.Errorf("Invalid LogMaxBackups should keep default, got %d", config.LogMaxBackups)
+	}
+}
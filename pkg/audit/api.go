@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewHandler returns the REST API for the audit log:
+//
+//	GET /audit  entries, newest first
+//
+// Query parameters filter the entries: actor, action (or a prefix such as
+// "server"), target, since (RFC 3339 or a duration such as 24h) and limit.
+func NewHandler(log *Log) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /audit", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter, err := ParseFilter(query.Get("actor"), query.Get("action"), query.Get("target"), query.Get("since"), query.Get("limit"))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		entries, err := log.Query(filter)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if entries == nil {
+			entries = []Entry{}
+		}
+		writeJSON(w, http.StatusOK, entries)
+	})
+	return mux
+}
+
+// ParseFilter builds a filter from text: since is an RFC 3339 time or a
+// duration before now, and limit a count; empty values don't filter
+func ParseFilter(actor, action, target, since, limit string) (Filter, error) {
+	filter := Filter{Actor: actor, Action: action, Target: target}
+	if since != "" {
+		if d, err := time.ParseDuration(since); err == nil {
+			filter.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, since); err == nil {
+			filter.Since = t
+		} else {
+			return Filter{}, fmt.Errorf("invalid since %q: use a time like 2024-05-01T00:00:00Z or a duration like 24h", since)
+		}
+	}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return Filter{}, fmt.Errorf("invalid limit %q", limit)
+		}
+		filter.Limit = n
+	}
+	return filter, nil
+}
+
+// writeJSON writes value as a JSON response with status
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value) // Error ignored: the client has gone away
+}
@@ -0,0 +1,176 @@
+// Package audit keeps the audit log: an append-only record of who changed
+// which workflow or server, who started or cancelled which execution, who
+// decided which approval and who managed which API token.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Action is what an audit entry records
+type Action string
+
+// Audited actions
+const (
+	WorkflowCreated    Action = "workflow.created"
+	WorkflowSaved      Action = "workflow.saved"
+	WorkflowImported   Action = "workflow.imported"
+	ServerAdded        Action = "server.added"
+	ServerChanged      Action = "server.changed"
+	ServerRemoved      Action = "server.removed"
+	ExecutionStarted   Action = "execution.started"
+	ExecutionCancelled Action = "execution.cancelled"
+	ApprovalDecided    Action = "approval.decided"
+	TokenCreated       Action = "token.created"
+	TokenRevoked       Action = "token.revoked"
+)
+
+// Entry is one audited operation
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`            // Local user, API token name or OIDC subject
+	Action Action    `json:"action"`           // What was done
+	Target string    `json:"target"`           // Workflow, server, execution ID or token
+	Detail string    `json:"detail,omitempty"` // E.g. the workflow an execution runs
+}
+
+// Filter selects audit entries. Zero fields match every entry.
+type Filter struct {
+	Actor  string
+	Action string // An action, or a prefix such as "server"
+	Target string
+	Since  time.Time
+	Limit  int // Newest entries kept; 0 keeps all
+}
+
+// matches reports whether the entry passes the filter
+func (f Filter) matches(entry Entry) bool {
+	if f.Actor != "" && entry.Actor != f.Actor {
+		return false
+	}
+	if f.Action != "" && string(entry.Action) != f.Action && !strings.HasPrefix(string(entry.Action), f.Action+".") {
+		return false
+	}
+	if f.Target != "" && entry.Target != f.Target {
+		return false
+	}
+	return f.Since.IsZero() || !entry.Time.Before(f.Since)
+}
+
+// DefaultPath returns the audit log path: $GOFLOW_CONFIG_DIR/audit.jsonl,
+// or ~/.goflow/audit.jsonl
+func DefaultPath() string {
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "audit.jsonl")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "audit.jsonl")
+	}
+	return filepath.Join(home, ".goflow", "audit.jsonl")
+}
+
+// LocalActor names the user running this process, for operations made
+// without an API
+func LocalActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// Log is an audit log file of JSON lines, one entry per line
+type Log struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+// NewLog returns the audit log at path; the file is created on the first
+// Record
+func NewLog(path string) *Log {
+	return &Log{path: path, now: time.Now}
+}
+
+// Default returns the audit log at DefaultPath
+func Default() *Log {
+	return NewLog(DefaultPath())
+}
+
+// Path returns the log file's path
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends an entry for actor doing action to target
+func (l *Log) Record(actor string, action Action, target, detail string) error {
+	entry := Entry{Time: l.now().UTC(), Actor: actor, Action: action, Target: target, Detail: detail}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// Query returns the entries passing filter, newest first. A missing log
+// has no entries; lines that can't be parsed are skipped.
+func (l *Log) Query(filter Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || !filter.matches(entry) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLog_RecordQuery(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "audit", "audit.jsonl"))
+	if entries, err := log.Query(Filter{}); err != nil || len(entries) != 0 {
+		t.Fatalf("Query() of a missing log = %v, %v", entries, err)
+	}
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	log.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+	records := []Entry{
+		{Actor: "ada", Action: ServerAdded, Target: "db"},
+		{Actor: "ada", Action: WorkflowSaved, Target: "deploy", Detail: "/w/deploy.yaml"},
+		{Actor: "ci", Action: ExecutionStarted, Target: "exec-1", Detail: "deploy"},
+		{Actor: "ci", Action: ServerRemoved, Target: "db"},
+	}
+	for _, r := range records {
+		if err := log.Record(r.Actor, r.Action, r.Target, r.Detail); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Lines that can't be parsed are skipped
+	file, err := os.OpenFile(log.Path(), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString("not json\n")
+	_ = file.Close()
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []Action
+	}{
+		{"all, newest first", Filter{}, []Action{ServerRemoved, ExecutionStarted, WorkflowSaved, ServerAdded}},
+		{"by actor", Filter{Actor: "ada"}, []Action{WorkflowSaved, ServerAdded}},
+		{"by action prefix", Filter{Action: "server"}, []Action{ServerRemoved, ServerAdded}},
+		{"by exact action", Filter{Action: "server.added"}, []Action{ServerAdded}},
+		{"prefix must end at a dot", Filter{Action: "serv"}, nil},
+		{"by target", Filter{Target: "db"}, []Action{ServerRemoved, ServerAdded}},
+		{"since", Filter{Since: start.Add(3 * time.Minute)}, []Action{ServerRemoved, ExecutionStarted}},
+		{"limit", Filter{Limit: 1}, []Action{ServerRemoved}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := log.Query(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []Action
+			for _, entry := range entries {
+				got = append(got, entry.Action)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Query() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if info, err := os.Stat(log.Path()); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("audit log mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	for _, target := range []string{"a", "b", "c"} {
+		if err := log.Record("ada", WorkflowSaved, target, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(NewHandler(log))
+	defer server.Close()
+
+	get := func(query string) (int, []Entry) {
+		resp, err := http.Get(server.URL + "/audit" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var entries []Entry
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return resp.StatusCode, entries
+	}
+
+	if status, entries := get("?action=workflow&since=1h&limit=2"); status != http.StatusOK || len(entries) != 2 || entries[0].Target != "c" {
+		t.Errorf("GET /audit = %d, %+v", status, entries)
+	}
+	if status, entries := get("?actor=nobody"); status != http.StatusOK || entries == nil || len(entries) != 0 {
+		t.Errorf("GET /audit with no matches = %d, %+v; want an empty list", status, entries)
+	}
+	if status, _ := get("?since=yesterday"); status != http.StatusBadRequest {
+		t.Errorf("GET /audit with a bad since = %d, want 400", status)
+	}
+}
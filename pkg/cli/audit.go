@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/tabwriter"
+
+	"github.com/dshills/goflow/pkg/audit"
+	"github.com/dshills/goflow/pkg/rbac"
+	"github.com/spf13/cobra"
+)
+
+// recordAudit adds an entry for the local user to the audit log. A failure
+// is only warned about, since the audited operation already succeeded.
+func recordAudit(cmd *cobra.Command, action audit.Action, target, detail string) {
+	if err := audit.NewLog(GetAuditLogPath()).Record(audit.LocalActor(), action, target, detail); err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err) // Error ignored: terminal output, failure is non-critical
+	}
+}
+
+// NewAuditCommand creates the audit command, which lists or serves the
+// audit log
+func NewAuditCommand() *cobra.Command {
+	var (
+		actor, action, target, since string
+		limit                        int
+		outputJSON                   bool
+		serveAddr                    string
+		authConfig                   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show who changed workflows and servers and who ran executions",
+		Long: `Show the audit log, newest first: who created, saved or imported which
+workflow, who added, changed or removed which server, who started or
+cancelled which execution, who decided which approval and who created or
+revoked which API token.
+
+Local operations are recorded as the user running goflow; operations made
+through an API as the token or OIDC subject that authenticated.
+
+--action matches an action such as server.removed, or every action of a
+prefix such as server. --since takes a time (2024-05-01T00:00:00Z) or a
+duration before now (24h).
+
+--serve serves the log at GET /audit, filtered by the same names as query
+parameters, until interrupted. With --auth, callers need a bearer token
+whose role grants view_audit (admin).
+
+Examples:
+  # What happened to the servers in the last day
+  goflow audit --action server --since 24h
+
+  # Everything ada did
+  goflow audit --actor ada
+
+  # Serve the audit API to admins
+  goflow audit --serve 127.0.0.1:8089 --auth ~/.goflow/auth.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := audit.NewLog(GetAuditLogPath())
+			if serveAddr != "" {
+				return serveAudit(cmd, log, serveAddr, authConfig)
+			}
+
+			filter, err := audit.ParseFilter(actor, action, target, since, "")
+			if err != nil {
+				return err
+			}
+			filter.Limit = limit
+			entries, err := log.Query(filter)
+			if err != nil {
+				return err
+			}
+			if outputJSON {
+				if entries == nil {
+					entries = []audit.Entry{}
+				}
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(entries)
+			}
+			if len(entries) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No audit entries.") // Error ignored: terminal output, failure is non-critical
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "TIME\tACTOR\tACTION\tTARGET\tDETAIL") // Error ignored: terminal output, failure is non-critical
+			for _, entry := range entries {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Actor, entry.Action, entry.Target, entry.Detail) // Error ignored: terminal output, failure is non-critical
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&actor, "actor", "", "Only entries by this user, token or OIDC subject")
+	cmd.Flags().StringVar(&action, "action", "", "Only this action, or actions with this prefix (e.g. server)")
+	cmd.Flags().StringVar(&target, "target", "", "Only entries about this workflow, server, execution or token")
+	cmd.Flags().StringVar(&since, "since", "", "Only entries since a time (RFC 3339) or a duration ago (e.g. 24h)")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 50, "Show at most this many entries (0 = all)")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output entries as JSON")
+	cmd.Flags().StringVar(&serveAddr, "serve", "", "Serve the audit API on this address, e.g. 127.0.0.1:8089")
+	cmd.Flags().StringVar(&authConfig, "auth", "", "Auth config file whose tokens or OIDC provider the audit API requires")
+
+	return cmd
+}
+
+// serveAudit serves the audit API on addr until interrupted, requiring the
+// view_audit permission when authConfig is set
+func serveAudit(cmd *cobra.Command, log *audit.Log, addr, authConfig string) error {
+	var handler http.Handler = audit.NewHandler(log)
+	if authConfig != "" {
+		authenticator, err := rbac.NewFileAuthenticator(authConfig, nil)
+		if err != nil {
+			return err
+		}
+		handler = rbac.Require(authenticator, rbac.Always(rbac.ViewAudit), handler)
+	}
+
+	ctx, sd := notifyShutdown(context.Background())
+	defer sd.Stop()
+	stopServer, err := serveHTTP(cmd, addr, handler, "Audit", "/audit")
+	if err != nil {
+		return err
+	}
+	defer stopServer()
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+// NewDiffCommand creates the diff command
+func NewDiffCommand() *cobra.Command {
+	var (
+		format   string
+		exitCode bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <workflow-a> <workflow-b>",
+		Short: "Show semantic differences between two workflows",
+		Long: `Compare two workflows by structure rather than by text.
+
+The diff lists nodes, edges, variables and servers that were added, removed
+or changed, with the individual fields (such as tool parameters) that
+changed. Element order, formatting and generated edge IDs are ignored, so
+reordering a YAML file produces no diff.
+
+Each argument is a workflow name or a path to a .yaml file.
+
+Examples:
+  goflow diff my-workflow ./my-workflow.yaml
+  goflow diff old.yaml new.yaml --format json
+  goflow diff old.yaml new.yaml --exit-code`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format: %s (expected text or json)", format)
+			}
+
+			workflows := make([]*workflow.Workflow, 2)
+			for i, arg := range args {
+				path, err := resolveWorkflowPath(arg)
+				if err != nil {
+					return err
+				}
+				wf, err := LoadWorkflowFromFile(path)
+				if err != nil {
+					return fmt.Errorf("failed to parse workflow %s: %w", arg, err)
+				}
+				workflows[i] = wf
+			}
+
+			diff, err := workflow.Diff(workflows[0], workflows[1])
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetEscapeHTML(false)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(diff); err != nil {
+					return fmt.Errorf("failed to encode diff: %w", err)
+				}
+			} else {
+				writeDiffText(cmd.OutOrStdout(), args[0], args[1], diff)
+			}
+
+			if exitCode && !diff.Empty() {
+				// Like diff(1), the status alone reports the difference
+				cmd.SilenceErrors = true
+				return fmt.Errorf("workflows differ")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text or json")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 1 if the workflows differ")
+
+	return cmd
+}
+
+// writeDiffText writes a diff in a human-readable form
+func writeDiffText(w io.Writer, a, b string, diff *workflow.WorkflowDiff) {
+	if diff.Empty() {
+		_, _ = fmt.Fprintf(w, "No differences between %s and %s\n", a, b)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "--- %s\n+++ %s\n", a, b)
+	if len(diff.Fields) > 0 {
+		_, _ = fmt.Fprintln(w, "\nWorkflow:")
+		for _, field := range diff.Fields {
+			writeFieldChange(w, "  ~ ", field)
+		}
+	}
+
+	sections := []struct {
+		title   string
+		entries []workflow.DiffEntry
+	}{
+		{"Nodes", diff.Nodes},
+		{"Edges", diff.Edges},
+		{"Variables", diff.Variables},
+		{"Servers", diff.Servers},
+	}
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n%s:\n", section.title)
+		for _, entry := range section.entries {
+			marker := "~"
+			switch entry.Change {
+			case workflow.ChangeAdded:
+				marker = "+"
+			case workflow.ChangeRemoved:
+				marker = "-"
+			}
+			if entry.NodeType != "" {
+				_, _ = fmt.Fprintf(w, "  %s %s (%s)\n", marker, entry.ID, entry.NodeType)
+			} else {
+				_, _ = fmt.Fprintf(w, "  %s %s\n", marker, entry.ID)
+			}
+			for _, field := range entry.Fields {
+				writeFieldChange(w, "      ", field)
+			}
+		}
+	}
+}
+
+// writeFieldChange writes one field change as "path: old → new"
+func writeFieldChange(w io.Writer, indent string, field workflow.FieldChange) {
+	_, _ = fmt.Fprintf(w, "%s%s: %s → %s\n", indent, field.Path, formatDiffValue(field.Old), formatDiffValue(field.New))
+}
+
+// formatDiffValue formats a field value compactly as JSON
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
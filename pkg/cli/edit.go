@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,8 +18,9 @@ func NewEditCommand() *cobra.Command {
 		Long: `Launch the TUI (Terminal User Interface) to edit a workflow visually.
 
 If a workflow name is provided, it will be loaded directly into the workflow builder.
-If no workflow name is provided, the TUI will open in workflow explorer mode, allowing
-you to browse and select a workflow to edit.
+If no workflow name is provided, the TUI restores the views and workflow that were
+open when it last closed, or opens in workflow explorer mode, allowing you to browse
+and select a workflow to edit.
 
 The TUI provides:
 - Visual workflow builder with node and edge management
@@ -27,7 +29,7 @@ The TUI provides:
 - Context-sensitive help (press ?)
 
 Examples:
-  goflow edit                     # Launch TUI in explorer mode
+  goflow edit                     # Resume the last session, or launch in explorer mode
   goflow edit my-workflow         # Edit specific workflow`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -81,12 +83,24 @@ Examples:
 					return fmt.Errorf("failed to switch to builder view: %w", err)
 				}
 			}
-			// else: Start in explorer view (already initialized by NewApp)
+			// else: Pick up where the last session left off, or start in
+			// the explorer view (already initialized by NewApp)
+			if workflowName == "" {
+				app.RestoreSession()
+			}
+
+			// Apply config.yaml changes while the TUI is running
+			watchCtx, stopWatching := context.WithCancel(context.Background())
+			defer stopWatching()
+			go WatchTunables(watchCtx, GetConfigFilePath())
 
 			// Run the TUI application
 			if err := app.Run(); err != nil {
 				return fmt.Errorf("TUI error: %w", err)
 			}
+			if sig := app.Signal(); sig != nil {
+				return &InterruptedError{Signal: sig}
+			}
 
 			// Success message after TUI exits
 			if workflowName != "" {
@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/spf13/cobra"
+)
+
+// NewEnvironmentCommand creates the environment command group
+func NewEnvironmentCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "environment",
+		Aliases: []string{"env"},
+		Short:   "Manage server environments",
+		Long: `Manage environments (such as dev, staging and prod) that map the server IDs
+workflows use to registered servers, so the same workflow runs against
+different backends.
+
+Environments are stored in ~/.goflow/environments.yaml. Choose one for a run
+with goflow run --environment, or make it the default with goflow environment
+use.`,
+	}
+
+	cmd.AddCommand(newEnvironmentListCommand())
+	cmd.AddCommand(newEnvironmentShowCommand())
+	cmd.AddCommand(newEnvironmentSetCommand())
+	cmd.AddCommand(newEnvironmentUseCommand())
+	cmd.AddCommand(newEnvironmentRemoveCommand())
+
+	return cmd
+}
+
+// newEnvironmentListCommand creates the environment list subcommand
+func newEnvironmentListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List environments",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			envs, err := mcpserver.LoadEnvironments(GetEnvironmentsPath())
+			if err != nil {
+				return err
+			}
+
+			if len(envs.Environments) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No environments defined.")                                                        // Error ignored: terminal output, failure is non-critical
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nDefine one with: goflow environment set <name> <server-id>=<registered-id>...") // Error ignored: terminal output, failure is non-critical
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tSERVERS\tDEFAULT") // Error ignored: terminal output, failure is non-critical
+			_, _ = fmt.Fprintln(w, "────\t───────\t───────") // Error ignored: terminal output, failure is non-critical
+			for _, name := range envs.Names() {
+				marker := ""
+				if name == envs.Default {
+					marker = "*"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", name, len(envs.Environments[name].Servers), marker) // Error ignored: terminal output, failure is non-critical
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// newEnvironmentShowCommand creates the environment show subcommand
+func newEnvironmentShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the servers an environment maps",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			envs, err := mcpserver.LoadEnvironments(GetEnvironmentsPath())
+			if err != nil {
+				return err
+			}
+			env, err := envs.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			ids := make([]string, 0, len(env.Servers))
+			for id := range env.Servers {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+
+			if env.RequireSigned {
+				_, _ = fmt.Fprint(cmd.OutOrStdout(), "Runs only workflows signed by a trusted key.\n\n") // Error ignored: terminal output, failure is non-critical
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "WORKFLOW SERVER\tREGISTERED SERVER") // Error ignored: terminal output, failure is non-critical
+			_, _ = fmt.Fprintln(w, "───────────────\t─────────────────") // Error ignored: terminal output, failure is non-critical
+			for _, id := range ids {
+				_, _ = fmt.Fprintf(w, "%s\t%s\n", id, env.Servers[id]) // Error ignored: terminal output, failure is non-critical
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// newEnvironmentSetCommand creates the environment set subcommand
+func newEnvironmentSetCommand() *cobra.Command {
+	var (
+		unset         []string
+		requireSigned bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set <name> [server-id=registered-id...]",
+		Short: "Create an environment or change its server mappings",
+		Long: `Map workflow server IDs to registered servers in an environment, creating
+the environment if it does not exist. The registered servers must exist.
+
+Examples:
+  # Run the db and search servers against staging backends
+  goflow environment set staging db=db-staging search=search-staging
+
+  # Stop mapping the search server in staging
+  goflow environment set staging --unset search
+
+  # Only run workflows signed by a trusted key in prod
+  goflow environment set prod --require-signed`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			mappings := make(map[string]string, len(args)-1)
+			for _, arg := range args[1:] {
+				id, target, ok := strings.Cut(arg, "=")
+				if !ok || id == "" || target == "" {
+					return fmt.Errorf("invalid mapping %q (expected server-id=registered-id)", arg)
+				}
+				mappings[id] = target
+			}
+
+			if len(mappings) > 0 {
+				repo, err := mcpserver.NewFileRepository(GetServersConfigPath())
+				if err != nil {
+					return fmt.Errorf("failed to load servers config: %w", err)
+				}
+				for _, target := range mappings {
+					if _, err := repo.Get(target); err != nil {
+						return fmt.Errorf("server not found: %s", target)
+					}
+				}
+			}
+
+			envs, err := mcpserver.LoadEnvironments(GetEnvironmentsPath())
+			if err != nil {
+				return err
+			}
+			env, exists := envs.Environments[name]
+			if !exists {
+				env = &mcpserver.Environment{Servers: make(map[string]string)}
+				envs.Environments[name] = env
+			}
+			for id, target := range mappings {
+				env.Servers[id] = target
+			}
+			for _, id := range unset {
+				delete(env.Servers, id)
+			}
+			if cmd.Flags().Changed("require-signed") {
+				env.RequireSigned = requireSigned
+			}
+
+			if err := envs.Save(GetEnvironmentsPath()); err != nil {
+				return fmt.Errorf("failed to save environments: %w", err)
+			}
+
+			verb := "updated"
+			if !exists {
+				verb = "created"
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Environment '%s' %s (%d server(s) mapped)\n", name, verb, len(env.Servers)) // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&unset, "unset", nil, "Workflow server IDs to stop mapping, can be used multiple times")
+	cmd.Flags().BoolVar(&requireSigned, "require-signed", false, "Only run workflows signed by a trusted key (see goflow key)")
+
+	return cmd
+}
+
+// newEnvironmentUseCommand creates the environment use subcommand
+func newEnvironmentUseCommand() *cobra.Command {
+	var clearDefault bool
+
+	cmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make an environment the default for runs",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if clearDefault {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			envs, err := mcpserver.LoadEnvironments(GetEnvironmentsPath())
+			if err != nil {
+				return err
+			}
+			name := ""
+			if !clearDefault {
+				name = args[0]
+			}
+			if err := envs.Use(name); err != nil {
+				return err
+			}
+			if err := envs.Save(GetEnvironmentsPath()); err != nil {
+				return fmt.Errorf("failed to save environments: %w", err)
+			}
+
+			if clearDefault {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "✓ Default environment cleared") // Error ignored: terminal output, failure is non-critical
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Runs now use environment '%s' by default\n", name) // Error ignored: terminal output, failure is non-critical
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clearDefault, "clear", false, "Clear the default environment")
+
+	return cmd
+}
+
+// newEnvironmentRemoveCommand creates the environment remove subcommand
+func newEnvironmentRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an environment",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			envs, err := mcpserver.LoadEnvironments(GetEnvironmentsPath())
+			if err != nil {
+				return err
+			}
+			if _, err := envs.Get(name); err != nil {
+				return err
+			}
+			delete(envs.Environments, name)
+			if envs.Default == name {
+				envs.Default = ""
+			}
+			if err := envs.Save(GetEnvironmentsPath()); err != nil {
+				return fmt.Errorf("failed to save environments: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Environment '%s' removed\n", name) // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+}
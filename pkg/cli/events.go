@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+// GetEventsSocketPath returns the default path of the workflow events socket
+func GetEventsSocketPath() string {
+	return filepath.Join(GetConfigDir(), "events.sock")
+}
+
+// NewEventsCommand creates the events command
+func NewEventsCommand() *cobra.Command {
+	var (
+		socketPath string
+		serve      bool
+		interval   time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Stream workflow change events",
+		Long: `Watch the workflows directory and emit an event whenever a workflow is
+created, updated, or deleted, so external tools (backup daemons, sync scripts)
+can react to changes without polling the directory themselves.
+
+Each event is one JSON line:
+
+  {"type":"workflow.updated","workflow":"etl","path":"...","hash":"<sha256>",
+   "previous_hash":"<sha256>","timestamp":"..."}
+
+Types are workflow.created, workflow.updated, and workflow.deleted. Hashes
+are SHA-256 of the file contents; saves that don't change the content emit
+nothing.
+
+By default events are written to stdout. With --serve they are also
+streamed to every client connected to a Unix socket (default
+~/.goflow/events.sock).
+
+Examples:
+  # Print events as they happen
+  goflow events
+
+  # Serve events on the default socket
+  goflow events --serve
+
+  # Consume events from another process
+  nc -U ~/.goflow/events.sock`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			watcher, err := storage.NewWorkflowChangeWatcher(GetWorkflowsDir(), interval)
+			if err != nil {
+				return err
+			}
+
+			// Streaming ends with an interrupt, which is not an error
+			ctx, sd := notifyShutdown(context.Background())
+			defer sd.Stop()
+
+			serveErr := make(chan error, 1)
+			if serve || socketPath != "" {
+				if socketPath == "" {
+					socketPath = GetEventsSocketPath()
+				}
+				listener, err := listenEventsSocket(socketPath)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = os.Remove(socketPath) }()
+
+				go func() {
+					serveErr <- storage.ServeWorkflowEvents(ctx, listener, watcher)
+				}()
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Serving workflow events on %s\n", socketPath)
+			}
+
+			events := watcher.Subscribe()
+			go watcher.Run(ctx)
+
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			for {
+				select {
+				case err := <-serveErr:
+					sd.Stop()
+					return err
+				case event, ok := <-events:
+					if !ok {
+						return nil
+					}
+					if err := encoder.Encode(event); err != nil {
+						return fmt.Errorf("failed to write event: %w", err)
+					}
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&serve, "serve", false, "Also stream events on a Unix socket")
+	cmd.Flags().StringVar(&socketPath, "socket", "", "Unix socket path for --serve (default: ~/.goflow/events.sock)")
+	cmd.Flags().DurationVar(&interval, "interval", storage.DefaultWorkflowWatchInterval, "How often to check for changes")
+
+	return cmd
+}
+
+// listenEventsSocket listens on a Unix socket, replacing a stale socket file
+// left behind by a previous run but refusing to take over a live one.
+func listenEventsSocket(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("workflow events are already being served on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	return listener, nil
+}
@@ -15,6 +15,7 @@ func NewExportCommand() *cobra.Command {
 	var (
 		outputFile string
 		verbose    bool
+		signWith   string
 	)
 
 	cmd := &cobra.Command{
@@ -35,13 +36,19 @@ Sensitive environment variables detected by patterns (KEY, SECRET, TOKEN,
 PASSWORD, etc.) are removed. Non-sensitive variables (HOST, PORT, etc.)
 are preserved.
 
+With --sign, the export is signed with an ed25519 key (see goflow key), so
+machines that trust the key can verify it was not changed since.
+
 Examples:
   # Export to stdout
   goflow export my-workflow
 
   # Export to a file
   goflow export my-workflow -o shared-workflow.yaml
-  goflow export my-workflow --output /path/to/workflow.yaml`,
+  goflow export my-workflow --output /path/to/workflow.yaml
+
+  # Sign with the key made by goflow key generate release
+  goflow export my-workflow --sign release -o release.yaml`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			workflowName := args[0]
@@ -75,6 +82,15 @@ Examples:
 				return fmt.Errorf("failed to export workflow: %w", err)
 			}
 
+			// Sign the exported file
+			if signWith != "" {
+				key, err := resolveSigningKey(signWith)
+				if err != nil {
+					return err
+				}
+				yamlBytes = workflow.SignWorkflowFile(yamlBytes, key)
+			}
+
 			// Write to output file or stdout
 			if outputFile != "" {
 				// Write to file
@@ -99,6 +115,9 @@ Examples:
 					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - Removed credentials from %d server configuration(s)\n", credentialCount)
 				}
 
+				if signWith != "" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - Signed with key: %s\n", signWith)
+				}
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - Output: %s\n", outputFile)
 
 				// Show warning if credentials were present
@@ -127,6 +146,7 @@ Examples:
 
 	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file path (default: stdout)")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed export information")
+	cmd.Flags().StringVar(&signWith, "sign", "", "Sign the export with this key name or private key file")
 
 	return cmd
 }
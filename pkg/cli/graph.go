@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+// NewGraphCommand creates the graph command
+func NewGraphCommand() *cobra.Command {
+	var (
+		format     string
+		outputFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "graph <workflow-name|file.yaml>",
+		Short: "Render a workflow's node graph",
+		Long: `Render the node/edge graph of a workflow as Graphviz DOT, Mermaid, or SVG.
+
+The output can be committed alongside workflows or embedded in wikis and
+documentation. Loop bodies and parallel branches are drawn as dashed edges,
+and edge conditions become edge labels.
+
+SVG is rendered directly and does not require Graphviz. When --format is not
+given it is taken from the output file extension (.dot, .gv, .mmd, .md, .svg),
+defaulting to DOT. Mermaid written to a .md file is wrapped in a mermaid code
+fence so it renders on GitHub and most wikis.
+
+Examples:
+  goflow graph my-workflow
+  goflow graph my-workflow | dot -Tpng > my-workflow.png
+  goflow graph my-workflow --format mermaid
+  goflow graph my-workflow -o docs/my-workflow.svg
+  goflow graph my-workflow -o docs/my-workflow.md`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			workflowPath, err := resolveWorkflowPath(args[0])
+			if err != nil {
+				return err
+			}
+
+			wf, err := LoadWorkflowFromFile(workflowPath)
+			if err != nil {
+				return fmt.Errorf("failed to parse workflow YAML: %w", err)
+			}
+
+			graphFormat := workflow.GraphFormat(format)
+			if format == "" {
+				graphFormat = graphFormatForFile(outputFile)
+			}
+			output, err := workflow.RenderGraph(wf, graphFormat)
+			if err != nil {
+				return err
+			}
+
+			if outputFile == "" {
+				_, _ = fmt.Fprint(cmd.OutOrStdout(), output)
+				return nil
+			}
+
+			if graphFormat == workflow.GraphMermaid && filepath.Ext(outputFile) == ".md" {
+				output = "```mermaid\n" + output + "```\n"
+			}
+			if dir := filepath.Dir(outputFile); dir != "." {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return fmt.Errorf("failed to create output directory: %w", err)
+				}
+			}
+			if err := os.WriteFile(outputFile, []byte(output), 0644); err != nil {
+				return fmt.Errorf("failed to write graph: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Graph written to %s\n", outputFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "Output format: dot, mermaid, or svg (default from output file, else dot)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file path (default: stdout)")
+
+	return cmd
+}
+
+// graphFormatForFile infers the graph format from an output file extension
+func graphFormatForFile(path string) workflow.GraphFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		return workflow.GraphSVG
+	case ".mmd", ".mermaid", ".md":
+		return workflow.GraphMermaid
+	default:
+		return workflow.GraphDOT
+	}
+}
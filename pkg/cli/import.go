@@ -8,16 +8,20 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/dshills/goflow/pkg/audit"
 	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/spf13/cobra"
 )
 
 // NewImportCommand creates the import command
 func NewImportCommand() *cobra.Command {
 	var (
-		verbose    bool
-		name       string
-		noInteract bool
+		verbose       bool
+		name          string
+		noInteract    bool
+		from          string
+		requireSigned bool
 	)
 
 	cmd := &cobra.Command{
@@ -34,13 +38,25 @@ This command:
 - Validates workflow structure
 - Saves to workflows directory
 
+With --from, a GitHub Actions workflow or n8n export is converted first:
+run steps and Execute Command nodes call the "shell" server, HTTP Request
+nodes call the "http" server, and constructs without a GoFlow equivalent
+become placeholder nodes that are listed for review.
+
+A signed workflow (see goflow export --sign) is verified against the trusted
+keys, and the import fails if it changed since it was signed. With
+--require-signed, unsigned workflows and those signed by untrusted keys are
+refused too.
+
 The imported workflow is saved in ~/.goflow/workflows/<workflow-name>.yaml
 
 Examples:
   goflow import /path/to/workflow.yaml
   goflow import ./my-workflow.yaml --verbose
   goflow import shared-workflow.yaml --name my-workflow
-  goflow import workflow.yaml --no-interact  # Skip interactive prompts`,
+  goflow import workflow.yaml --no-interact  # Skip interactive prompts
+  goflow import .github/workflows/ci.yml --from github-actions
+  goflow import n8n-export.json --from auto`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			workflowFile := args[0]
@@ -50,6 +66,19 @@ Examples:
 				return fmt.Errorf("workflow file not found: %s", workflowFile)
 			}
 
+			// Verify the signature before trusting anything in the file
+			if from == "" {
+				data, err := os.ReadFile(workflowFile)
+				if err != nil {
+					return fmt.Errorf("failed to read workflow file: %w", err)
+				}
+				if err := checkWorkflowSignature(cmd, data, requireSigned); err != nil {
+					return err
+				}
+			} else if requireSigned {
+				return fmt.Errorf("--require-signed cannot be used with --from: converted workflows are not signed")
+			}
+
 			// Load server config and populate registry
 			registry := mcpserver.NewRegistry()
 			serverConfig, err := loadServersConfig()
@@ -80,8 +109,18 @@ Examples:
 				}
 			}
 
-			// Import workflow using ImportWorkflow function
-			wf, err := ImportWorkflow(workflowFile, registry)
+			// Import workflow using ImportWorkflow, or convert it from
+			// another engine's format
+			var conversionWarnings []workflow.ImportWarning
+			importFile := func() (*workflow.Workflow, error) {
+				if from == "" {
+					return ImportWorkflow(workflowFile, registry)
+				}
+				wf, warnings, err := ImportForeignWorkflow(workflowFile, workflow.ForeignFormat(from), registry)
+				conversionWarnings = warnings
+				return wf, err
+			}
+			wf, err := importFile()
 
 			// Handle different error types
 			if err != nil {
@@ -113,7 +152,7 @@ Examples:
 							}
 						}
 						// Retry import after adding servers
-						wf, err = ImportWorkflow(workflowFile, registry)
+						wf, err = importFile()
 						if err != nil {
 							// Check again for credential warnings (expected)
 							var credentialWarning *CredentialPlaceholderWarning
@@ -197,15 +236,26 @@ Examples:
 				return fmt.Errorf("workflow already exists: %s\n\nLocation: %s\nUse --name flag with a different name or remove the existing workflow first", workflowName, workflowPath)
 			}
 
-			// Copy the file to workflows directory
-			sourceData, err := os.ReadFile(workflowFile)
-			if err != nil {
-				return fmt.Errorf("failed to read source workflow file: %w", err)
+			// Copy the file to workflows directory, or save the converted
+			// workflow
+			var sourceData []byte
+			if from == "" {
+				sourceData, err = os.ReadFile(workflowFile)
+				if err != nil {
+					return fmt.Errorf("failed to read source workflow file: %w", err)
+				}
+			} else {
+				wf.Name = workflowName
+				sourceData, err = workflow.ToYAML(wf)
+				if err != nil {
+					return fmt.Errorf("failed to encode converted workflow: %w", err)
+				}
 			}
 
 			if err := os.WriteFile(workflowPath, sourceData, 0644); err != nil {
 				return fmt.Errorf("failed to write workflow file: %w", err)
 			}
+			recordAudit(cmd, audit.WorkflowImported, workflowName, workflowFile)
 
 			// Count newly added servers
 			newServersCount := 0
@@ -224,6 +274,14 @@ Examples:
 			}
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - Workflow saved to: %s\n", workflowPath)
 
+			// List the constructs that did not convert faithfully
+			if len(conversionWarnings) > 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n⚠  %d construct(s) need review before running:\n", len(conversionWarnings))
+				for _, warning := range conversionWarnings {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", warning)
+				}
+			}
+
 			// Show next steps if there are credentials to configure
 			if len(serversWithCredentials) > 0 {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\n⚠  Required setup:")
@@ -250,6 +308,8 @@ Examples:
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed import information")
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Override workflow name")
 	cmd.Flags().BoolVar(&noInteract, "no-interact", false, "Skip interactive prompts for missing servers")
+	cmd.Flags().StringVar(&from, "from", "", "Convert from another engine's format: github-actions, n8n, or auto")
+	cmd.Flags().BoolVar(&requireSigned, "require-signed", false, "Refuse workflows not signed by a trusted key")
 
 	return cmd
 }
@@ -355,6 +415,9 @@ func handleMissingServers(cmd *cobra.Command, missingServers []string, serverCon
 	if err := saveServersConfig(serverConfig); err != nil {
 		return fmt.Errorf("failed to save server configuration: %w", err)
 	}
+	for _, serverID := range missingServers {
+		recordAudit(cmd, audit.ServerAdded, serverID, "configured on workflow import")
+	}
 
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n✓ Saved %d server configuration(s)\n", len(missingServers))
 	_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nNote: Credentials must be configured separately using:")
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/dshills/goflow/pkg/audit"
 	"github.com/dshills/goflow/pkg/tui"
 	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/spf13/cobra"
@@ -91,6 +92,7 @@ Examples:
 				return fmt.Errorf("failed to write workflow file: %w", err)
 			}
 
+			recordAudit(cmd, audit.WorkflowCreated, workflowName, "")
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Created workflow: %s\n", workflowName)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Location: %s\n", workflowPath)
 
@@ -387,9 +389,10 @@ func workflowToYAMLMap(wf *workflow.Workflow) map[string]interface{} {
 	}
 
 	return map[string]interface{}{
-		"version":     wf.Version,
-		"name":        wf.Name,
-		"description": wf.Description,
+		"schema_version": workflow.CurrentSchemaVersion,
+		"version":        wf.Version,
+		"name":           wf.Name,
+		"description":    wf.Description,
 		"metadata": map[string]interface{}{
 			"author":  wf.Metadata.Author,
 			"created": wf.Metadata.Created.Format(time.RFC3339),
@@ -423,6 +426,21 @@ func nodeToMap(node workflow.Node) map[string]interface{} {
 		if n.OutputVariable != "" {
 			m["output"] = n.OutputVariable
 		}
+	case *workflow.BatchToolNode:
+		m["server"] = n.ServerID
+		m["tool"] = n.ToolName
+		m["collection"] = n.Collection
+		m["item"] = n.ItemVariable
+		if len(n.Parameters) > 0 {
+			m["parameters"] = n.Parameters
+		}
+		if n.MaxConcurrency > 0 {
+			m["max_concurrency"] = n.MaxConcurrency
+		}
+		m["output"] = n.OutputVariable
+		if n.ErrorVariable != "" {
+			m["error_variable"] = n.ErrorVariable
+		}
 	case *workflow.TransformNode:
 		if n.InputVariable != "" {
 			m["input"] = n.InputVariable
@@ -437,6 +455,38 @@ func nodeToMap(node workflow.Node) map[string]interface{} {
 		if n.Condition != "" {
 			m["condition"] = n.Condition
 		}
+	case *workflow.SwitchNode:
+		if len(n.Cases) > 0 {
+			m["cases"] = n.Cases
+		}
+	case *workflow.TryNode:
+		if len(n.Body) > 0 {
+			m["body"] = n.Body
+		}
+	case *workflow.CatchNode:
+		if n.ErrorVariable != "" {
+			m["error_variable"] = n.ErrorVariable
+		}
+	case *workflow.DelayNode:
+		if n.Duration != "" {
+			m["duration"] = n.Duration
+		}
+		if n.Until != "" {
+			m["until"] = n.Until
+		}
+	case *workflow.ApprovalNode:
+		if n.Message != "" {
+			m["message"] = n.Message
+		}
+		if n.Timeout != "" {
+			m["timeout"] = n.Timeout
+		}
+		if n.DefaultAction != "" {
+			m["default_action"] = n.DefaultAction
+		}
+		if n.OutputVariable != "" {
+			m["output"] = n.OutputVariable
+		}
 	case *workflow.EndNode:
 		if n.ReturnValue != "" {
 			m["return"] = n.ReturnValue
@@ -478,6 +528,9 @@ func launchTUIForWorkflow(workflowName string) error {
 	if err := app.Run(); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
+	if sig := app.Signal(); sig != nil {
+		return &InterruptedError{Signal: sig}
+	}
 
 	return nil
 }
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+// NewKeyCommand creates the key command group
+func NewKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "key",
+		Short: "Manage workflow signing keys",
+		Long: `Generate the ed25519 keys that sign exported workflows, and choose the public
+keys trusted to verify them.
+
+Signing keys are stored in ~/.goflow/keys and trusted public keys in
+~/.goflow/trusted_keys. goflow export --sign signs a workflow; goflow import
+verifies signed workflows, and goflow run refuses unsigned or untrusted ones
+with --require-signed or in an environment set with --require-signed.`,
+	}
+
+	cmd.AddCommand(newKeyGenerateCommand())
+	cmd.AddCommand(newKeyListCommand())
+	cmd.AddCommand(newKeyTrustCommand())
+	cmd.AddCommand(newKeyUntrustCommand())
+	cmd.AddCommand(newKeyVerifyCommand())
+
+	return cmd
+}
+
+// newKeyGenerateCommand creates the key generate subcommand
+func newKeyGenerateCommand() *cobra.Command {
+	var trust bool
+
+	cmd := &cobra.Command{
+		Use:   "generate <name>",
+		Short: "Generate a signing key",
+		Long: `Generate an ed25519 key pair: <name>.key, readable only by you, signs
+workflows, and <name>.pub is the public key to share with the machines that
+verify them.
+
+Examples:
+  # A release key, also trusted on this machine
+  goflow key generate release --trust`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := validateKeyName(name); err != nil {
+				return err
+			}
+			key, err := workflow.GenerateSigningKey(GetKeysDir(), name)
+			if err != nil {
+				return err
+			}
+			publicPath := filepath.Join(GetKeysDir(), name+".pub")
+			if trust {
+				if err := workflow.WritePublicKey(filepath.Join(GetTrustedKeysDir(), name+".pub"), key); err != nil {
+					return err
+				}
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Signing key '%s' generated (ID %s)\n", name, workflow.KeyID(key)) // Error ignored: terminal output, failure is non-critical
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - Public key: %s\n", publicPath)                                  // Error ignored: terminal output, failure is non-critical
+			if !trust {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTrust it where workflows run with: goflow key trust %s\n", publicPath) // Error ignored: terminal output, failure is non-critical
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&trust, "trust", false, "Also trust the key on this machine")
+
+	return cmd
+}
+
+// newKeyListCommand creates the key list subcommand
+func newKeyListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List signing keys and trusted keys",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trusted, err := workflow.LoadTrustedKeys(GetTrustedKeysDir())
+			if err != nil {
+				return err
+			}
+			signing, err := filepath.Glob(filepath.Join(GetKeysDir(), "*.key"))
+			if err != nil {
+				return err
+			}
+			if len(trusted) == 0 && len(signing) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No keys.")                                        // Error ignored: terminal output, failure is non-critical
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nGenerate one with: goflow key generate <name>") // Error ignored: terminal output, failure is non-critical
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tID\tKIND") // Error ignored: terminal output, failure is non-critical
+			for _, path := range signing {
+				id := "?"
+				if key, err := workflow.LoadSigningKey(path); err == nil {
+					id = workflow.KeyID(key.Public().(ed25519.PublicKey))
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\tsigning\n", strings.TrimSuffix(filepath.Base(path), ".key"), id) // Error ignored: terminal output, failure is non-critical
+			}
+			for _, key := range trusted {
+				_, _ = fmt.Fprintf(w, "%s\t%s\ttrusted\n", key.Name, key.ID) // Error ignored: terminal output, failure is non-critical
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// newKeyTrustCommand creates the key trust subcommand
+func newKeyTrustCommand() *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "trust <public-key-file>",
+		Short: "Trust a public key to sign workflows",
+		Long: `Copy a public key into ~/.goflow/trusted_keys, so workflows it signed pass
+verification.
+
+Examples:
+  goflow key trust release.pub
+  goflow key trust ./keys/ci.pub --name ci-release`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := workflow.LoadPublicKey(args[0])
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+			}
+			if err := validateKeyName(name); err != nil {
+				return err
+			}
+			if err := workflow.WritePublicKey(filepath.Join(GetTrustedKeysDir(), name+".pub"), key); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Key '%s' trusted (ID %s)\n", name, workflow.KeyID(key)) // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name to trust the key as (default: the file name)")
+
+	return cmd
+}
+
+// newKeyUntrustCommand creates the key untrust subcommand
+func newKeyUntrustCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "untrust <name>",
+		Short: "Stop trusting a public key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateKeyName(args[0]); err != nil {
+				return err
+			}
+			err := os.Remove(filepath.Join(GetTrustedKeysDir(), args[0]+".pub"))
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("trusted key not found: %s", args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to remove trusted key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Key '%s' no longer trusted\n", args[0]) // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+}
+
+// newKeyVerifyCommand creates the key verify subcommand
+func newKeyVerifyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <workflow-file|workflow-name>",
+		Short: "Check a workflow's signature against the trusted keys",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if _, err := os.Stat(path); err != nil {
+				path = filepath.Join(GetWorkflowsDir(), args[0]+".yaml")
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read workflow file: %w", err)
+			}
+			keyID, err := verifyWorkflowSignature(data)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ %s is signed by trusted key %s\n", path, keyID) // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+}
+
+// validateKeyName rejects key names that would escape the keys directories
+func validateKeyName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid key name %q", name)
+	}
+	return nil
+}
+
+// resolveSigningKey loads the signing key a --sign flag names: a key made by
+// goflow key generate, or the path of a key file
+func resolveSigningKey(nameOrPath string) (ed25519.PrivateKey, error) {
+	path := nameOrPath
+	if validateKeyName(nameOrPath) == nil && filepath.Ext(nameOrPath) == "" {
+		path = filepath.Join(GetKeysDir(), nameOrPath+".key")
+	}
+	return workflow.LoadSigningKey(path)
+}
+
+// verifyWorkflowSignature checks workflow file data against the trusted
+// keys, returning the ID of the key that signed it
+func verifyWorkflowSignature(data []byte) (string, error) {
+	trusted, err := workflow.LoadTrustedKeys(GetTrustedKeysDir())
+	if err != nil {
+		return "", err
+	}
+	return workflow.VerifyWorkflowFile(data, workflow.TrustedKeyMap(trusted))
+}
+
+// checkWorkflowSignature verifies a signed workflow before it is imported or
+// run. A signature that does not match always fails; an unsigned workflow,
+// or one signed by an untrusted key, fails only when required, and otherwise
+// is reported as a warning for an untrusted key.
+func checkWorkflowSignature(cmd *cobra.Command, data []byte, required bool) error {
+	keyID, err := verifyWorkflowSignature(data)
+	switch {
+	case err == nil:
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "✓ Signed by trusted key %s\n", keyID) // Error ignored: terminal output, failure is non-critical
+		return nil
+	case errors.Is(err, workflow.ErrUnsigned) && !required:
+		return nil
+	case errors.Is(err, workflow.ErrUntrustedKey) && !required:
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "⚠ Warning: %v\n", err) // Error ignored: terminal output, failure is non-critical
+		return nil
+	case errors.Is(err, workflow.ErrUnsigned), errors.Is(err, workflow.ErrUntrustedKey):
+		return fmt.Errorf("%w: only workflows signed by a trusted key may run here (see goflow key)", err)
+	default:
+		return err
+	}
+}
+
+// environmentRequiresSigned reports whether the named environment, or the
+// default one when name is empty, only runs signed workflows
+func environmentRequiresSigned(name string) (bool, error) {
+	envs, err := mcpserver.LoadEnvironments(GetEnvironmentsPath())
+	if err != nil {
+		return false, err
+	}
+	if name == "" {
+		name = envs.Default
+	}
+	if name == "" {
+		return false, nil
+	}
+	env, err := envs.Get(name)
+	if err != nil {
+		return false, err
+	}
+	return env.RequireSigned, nil
+}
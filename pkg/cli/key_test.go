@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignedWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOFLOW_CONFIG_DIR", dir)
+	if err := os.MkdirAll(GetWorkflowsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	source := `version: "1.0"
+name: deploy
+nodes:
+  - id: start
+    type: start
+  - id: end
+    type: end
+edges:
+  - from: start
+    to: end
+`
+	if err := os.WriteFile(filepath.Join(GetWorkflowsDir(), "deploy.yaml"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := runCommand(t, "key", "generate", "release"); err != nil {
+		t.Fatalf("key generate: %v\n%s", err, out)
+	}
+	signedPath := filepath.Join(t.TempDir(), "deploy.yaml")
+	if out, err := runCommand(t, "export", "deploy", "--sign", "release", "-o", signedPath); err != nil {
+		t.Fatalf("export --sign: %v\n%s", err, out)
+	}
+
+	// Until the key is trusted, a signed workflow is refused where
+	// signatures are required and only warned about elsewhere
+	out, err := runCommand(t, "import", signedPath, "--name", "signed", "--require-signed", "--no-interact")
+	if err == nil || !strings.Contains(err.Error(), "untrusted key") {
+		t.Fatalf("import of an untrusted workflow = %v\n%s", err, out)
+	}
+	out, err = runCommand(t, "key", "trust", filepath.Join(GetKeysDir(), "release.pub"))
+	if err != nil {
+		t.Fatalf("key trust: %v\n%s", err, out)
+	}
+	out, err = runCommand(t, "key", "list")
+	if err != nil || strings.Count(out, "release") != 2 {
+		t.Errorf("key list = %v\n%s", err, out)
+	}
+	out, err = runCommand(t, "import", signedPath, "--name", "signed", "--require-signed", "--no-interact")
+	if err != nil || !strings.Contains(out, "Signed by trusted key") {
+		t.Fatalf("import of a signed workflow = %v\n%s", err, out)
+	}
+
+	// A tampered copy is refused even when signatures are optional
+	data, err := os.ReadFile(signedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamperedPath := filepath.Join(t.TempDir(), "tampered.yaml")
+	tampered := strings.Replace(string(data), "id: end", "id: finish", 1)
+	if err := os.WriteFile(tamperedPath, []byte(tampered), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCommand(t, "import", tamperedPath, "--name", "tampered", "--no-interact"); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("import of a tampered workflow = %v", err)
+	}
+
+	// A prod environment runs only the signed workflow
+	if _, err := runCommand(t, "environment", "set", "prod", "--require-signed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCommand(t, "run", "deploy", "--environment", "prod"); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("run of an unsigned workflow in prod = %v", err)
+	}
+	if out, err := runCommand(t, "run", "signed", "--environment", "prod"); err != nil {
+		t.Errorf("run of a signed workflow in prod = %v\n%s", err, out)
+	}
+	if out, err := runCommand(t, "run", "deploy"); err != nil {
+		t.Errorf("run of an unsigned workflow outside prod = %v\n%s", err, out)
+	}
+
+	if _, err := runCommand(t, "key", "untrust", "release"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCommand(t, "key", "verify", "signed"); err == nil {
+		t.Error("expected verification to fail once the key is no longer trusted")
+	}
+}
@@ -0,0 +1,547 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/goflow/pkg/mcp"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// lintDiscoveryTimeout bounds connecting to a server and listing its tools
+const lintDiscoveryTimeout = 10 * time.Second
+
+// LintConfig is the lint configuration file format:
+//
+//	rules:
+//	  unused-variable: error
+//	  missing-output: off
+type LintConfig struct {
+	Rules map[string]workflow.LintSeverity `yaml:"rules"`
+}
+
+// lintResult holds the findings for one workflow file
+type lintResult struct {
+	Workflow string                 `json:"workflow"`
+	Path     string                 `json:"path"`
+	Findings []workflow.LintFinding `json:"findings"`
+}
+
+// NewLintCommand creates the lint command
+func NewLintCommand() *cobra.Command {
+	var (
+		format     string
+		configPath string
+		disabled   []string
+		discover   bool
+		failOn     string
+		sandbox    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [workflow-name|file.yaml]...",
+		Short: "Check workflows for problems",
+		Long: `Run structural validation plus a configurable rule set over workflows.
+
+With no arguments, every workflow in ~/.goflow/workflows is linted.
+
+Rules:
+` + lintRulesHelp() + `
+Rule severities can be changed in a config file (--config):
+
+  rules:
+    unused-variable: error
+    missing-output: off
+
+Tool references are only checked with --discover, which starts each
+referenced stdio server and lists its tools. The tool schemas are compared
+with those recorded in ~/.goflow/tool_schemas.json when the server was last
+discovered (by testing it in the TUI server registry), and tools whose
+parameters changed are reported. Servers never discovered before are
+recorded as they are now.
+
+With --sandbox, nodes that the sandbox profile would stop are reported, so
+a run under goflow run --sandbox does not abort halfway.
+
+Findings are written as text, JSON, or SARIF 2.1.0 (--format). The command
+fails when any finding is at or above the --fail-on severity.
+
+Examples:
+  goflow lint
+  goflow lint my-workflow --discover
+  goflow lint my-workflow --sandbox read-only
+  goflow lint ./workflows/etl.yaml --format sarif > goflow.sarif
+  goflow lint --disable unused-variable --fail-on warning`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			switch format {
+			case "text", "json", "sarif":
+			default:
+				return fmt.Errorf("invalid format: %s (expected text, json, or sarif)", format)
+			}
+			threshold, err := parseFailOn(failOn)
+			if err != nil {
+				return err
+			}
+
+			opts := workflow.LintOptions{Severity: make(map[string]workflow.LintSeverity)}
+			if configPath != "" {
+				config, err := loadLintConfig(configPath)
+				if err != nil {
+					return err
+				}
+				for rule, severity := range config.Rules {
+					opts.Severity[rule] = severity
+				}
+			}
+			for _, rule := range disabled {
+				opts.Severity[rule] = workflow.LintOff
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			var servers mcpserver.ServerRepository
+			if sandbox != "" {
+				opts.Sandbox, err = workflow.LoadSandboxProfile(GetSandboxProfilesPath(), sandbox)
+				if err != nil {
+					return err
+				}
+				servers, err = mcpserver.NewFileRepository(GetServersConfigPath())
+				if err != nil {
+					return fmt.Errorf("failed to load servers config: %w", err)
+				}
+			}
+
+			if registry, err := loadServersConfig(); err == nil {
+				opts.RegisteredServers = make(map[string]bool, len(registry.Servers))
+				for id := range registry.Servers {
+					opts.RegisteredServers[id] = true
+				}
+			}
+
+			paths, err := lintTargets(args)
+			if err != nil {
+				return err
+			}
+
+			var results []lintResult
+			for _, path := range paths {
+				name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+				result := lintResult{Workflow: name, Path: path}
+
+				wf, err := LoadWorkflowFromFile(path)
+				if err != nil {
+					result.Findings = []workflow.LintFinding{{
+						Rule:     workflow.RuleStructure,
+						Severity: workflow.LintError,
+						Message:  err.Error(),
+					}}
+					results = append(results, result)
+					continue
+				}
+
+				// Sandbox profiles judge tools by the categories servers.yaml
+				// declares, never by the workflow's own say
+				if servers != nil {
+					applyRegisteredToolCategories(wf, servers)
+				}
+
+				wfOpts := opts
+				if discover {
+					discovered := discoverServerTools(cmd.ErrOrStderr(), wf)
+					wfOpts.ServerTools = toolNames(discovered)
+					wfOpts.ToolChanges = compareKnownTools(cmd.ErrOrStderr(), discovered)
+				}
+				findings, err := workflow.Lint(wf, wfOpts)
+				if err != nil {
+					return err
+				}
+				result.Findings = findings
+				results = append(results, result)
+			}
+
+			switch format {
+			case "json":
+				err = writeLintJSON(cmd.OutOrStdout(), results)
+			case "sarif":
+				err = writeLintSARIF(cmd.OutOrStdout(), results)
+			default:
+				writeLintText(cmd.OutOrStdout(), results)
+			}
+			if err != nil {
+				return err
+			}
+
+			if failing := countFailing(results, threshold); failing > 0 {
+				return fmt.Errorf("lint found %d problem(s) at or above %s severity", failing, failOn)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text, json, or sarif")
+	cmd.Flags().StringVar(&configPath, "config", "", "Lint configuration file (YAML)")
+	cmd.Flags().StringArrayVar(&disabled, "disable", []string{}, "Disable a rule, can be used multiple times")
+	cmd.Flags().BoolVar(&discover, "discover", false, "Start referenced servers to check tool names")
+	cmd.Flags().StringVar(&failOn, "fail-on", "error", "Fail on findings of this severity or worse: error, warning, or never")
+	cmd.Flags().StringVar(&sandbox, "sandbox", "", "Report nodes this sandbox profile would not let run")
+
+	return cmd
+}
+
+// lintRulesHelp lists the rules for the command help
+func lintRulesHelp() string {
+	var b strings.Builder
+	for _, rule := range workflow.LintRules {
+		fmt.Fprintf(&b, "  %-18s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+	}
+	return b.String()
+}
+
+// parseFailOn returns the minimum failing severity, or "" for never
+func parseFailOn(value string) (workflow.LintSeverity, error) {
+	switch value {
+	case "error":
+		return workflow.LintError, nil
+	case "warning":
+		return workflow.LintWarning, nil
+	case "never":
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid --fail-on value: %s (expected error, warning, or never)", value)
+	}
+}
+
+// countFailing counts findings at or above the threshold severity
+func countFailing(results []lintResult, threshold workflow.LintSeverity) int {
+	if threshold == "" {
+		return 0
+	}
+	count := 0
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			if finding.Severity == workflow.LintError || threshold == workflow.LintWarning {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// loadLintConfig reads a lint configuration file
+func loadLintConfig(path string) (*LintConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint config: %w", err)
+	}
+	var config LintConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse lint config: %w", err)
+	}
+	return &config, nil
+}
+
+// lintTargets resolves arguments to workflow files, defaulting to every
+// workflow in the workflows directory
+func lintTargets(args []string) ([]string, error) {
+	if len(args) == 0 {
+		paths, err := filepath.Glob(filepath.Join(GetWorkflowsDir(), "*.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflows: %w", err)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no workflows found in %s", GetWorkflowsDir())
+		}
+		sort.Strings(paths)
+		return paths, nil
+	}
+
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		path, err := resolveWorkflowPath(arg)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// resolveWorkflowPath returns the file for a workflow argument. Arguments
+// ending in .yaml or .yml are paths; others are names in the workflows
+// directory.
+func resolveWorkflowPath(arg string) (string, error) {
+	path := arg
+	if ext := filepath.Ext(arg); ext != ".yaml" && ext != ".yml" {
+		path = filepath.Join(GetWorkflowsDir(), arg+".yaml")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("workflow not found: %s\n\nLooked in: %s", arg, path)
+	}
+	return path, nil
+}
+
+// discoverServerTools lists the tools of every stdio server referenced by a
+// tool node. Servers that cannot be reached are reported on w and left out,
+// so their tool references are not checked.
+func discoverServerTools(w io.Writer, wf *workflow.Workflow) map[string][]mcpserver.Tool {
+	referenced := make(map[string]bool)
+	for _, node := range wf.Nodes {
+		if n, ok := node.(*workflow.MCPToolNode); ok && n.ServerID != "" {
+			referenced[n.ServerID] = true
+		}
+	}
+
+	tools := make(map[string][]mcpserver.Tool)
+	for _, server := range wf.ServerConfigs {
+		if server == nil || !referenced[server.ID] {
+			continue
+		}
+		if server.Transport != "" && server.Transport != "stdio" {
+			_, _ = fmt.Fprintf(w, "Warning: skipping tool discovery for %s server %s\n", server.Transport, server.ID)
+			continue
+		}
+
+		serverTools, err := listServerTools(mcp.ServerConfig{
+			ID:      server.ID,
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "Warning: could not discover tools of server %s: %v\n", server.ID, err)
+			continue
+		}
+		tools[server.ID] = serverTools
+	}
+	return tools
+}
+
+// toolNames returns the names of each server's tools
+func toolNames(tools map[string][]mcpserver.Tool) map[string][]string {
+	names := make(map[string][]string, len(tools))
+	for serverID, serverTools := range tools {
+		names[serverID] = make([]string, 0, len(serverTools))
+		for _, tool := range serverTools {
+			names[serverID] = append(names[serverID], tool.Name)
+		}
+	}
+	return names
+}
+
+// compareKnownTools describes how each discovered tool changed since its
+// server was last discovered. Servers that were never discovered are
+// recorded, so later runs have something to compare with; the schemas of
+// known servers are left for the TUI server registry to update once the
+// changes are reviewed.
+func compareKnownTools(w io.Writer, tools map[string][]mcpserver.Tool) map[string]map[string]string {
+	known, err := mcpserver.LoadKnownTools(GetToolSchemasPath())
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "Warning: not checking tool changes: %v\n", err)
+		return nil
+	}
+
+	changes := make(map[string]map[string]string)
+	recorded := false
+	for serverID, serverTools := range tools {
+		if _, exists := known.Servers[serverID]; !exists {
+			known.Update(serverID, serverTools)
+			recorded = true
+			continue
+		}
+		for _, change := range known.Diff(serverID, serverTools) {
+			if !change.Breaking() {
+				continue
+			}
+			if changes[serverID] == nil {
+				changes[serverID] = make(map[string]string)
+			}
+			changes[serverID][change.Tool] = change.Summary()
+		}
+	}
+	if recorded {
+		if err := known.Save(GetToolSchemasPath()); err != nil {
+			_, _ = fmt.Fprintf(w, "Warning: could not record tool schemas: %v\n", err)
+		}
+	}
+	return changes
+}
+
+// listServerTools connects to a stdio server and returns its tools
+func listServerTools(config mcp.ServerConfig) ([]mcpserver.Tool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lintDiscoveryTimeout)
+	defer cancel()
+
+	client, err := mcp.NewStdioClient(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Connect(ctx); err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	return client.ListTools(ctx)
+}
+
+// writeLintText writes findings in a human-readable form
+func writeLintText(w io.Writer, results []lintResult) {
+	total := 0
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			location := result.Workflow
+			if finding.NodeID != "" {
+				location += ":" + finding.NodeID
+			}
+			_, _ = fmt.Fprintf(w, "%s: %s: %s [%s]\n", location, finding.Severity, finding.Message, finding.Rule)
+			total++
+		}
+	}
+
+	if total == 0 {
+		_, _ = fmt.Fprintf(w, "✓ %d workflow(s) checked, no problems found\n", len(results))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n%d problem(s) in %d workflow(s)\n", total, len(results))
+}
+
+// writeLintJSON writes findings as a JSON array of per-workflow results
+func writeLintJSON(w io.Writer, results []lintResult) error {
+	for i := range results {
+		if results[i].Findings == nil {
+			results[i].Findings = []workflow.LintFinding{}
+		}
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode findings: %w", err)
+	}
+	_, _ = fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// SARIF 2.1.0 log structures, limited to the fields goflow emits
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// writeLintSARIF writes findings as a SARIF 2.1.0 log for code scanning tools
+func writeLintSARIF(w io.Writer, results []lintResult) error {
+	driver := sarifDriver{
+		Name:           "goflow",
+		Version:        Version,
+		InformationURI: "https://github.com/dshills/goflow",
+		Rules:          make([]sarifRule, 0, len(workflow.LintRules)),
+	}
+	for _, rule := range workflow.LintRules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: string(rule.Severity)},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(result.Path)},
+				},
+			}
+			if finding.NodeID != "" {
+				location.LogicalLocations = []sarifLogicalLocation{{
+					Name:               finding.NodeID,
+					FullyQualifiedName: result.Workflow + "." + finding.NodeID,
+					Kind:               "object",
+				}}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    finding.Rule,
+				Level:     string(finding.Severity),
+				Message:   sarifMessage{Text: finding.Message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode findings: %w", err)
+	}
+	_, _ = fmt.Fprintln(w, string(data))
+	return nil
+}
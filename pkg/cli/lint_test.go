@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+func TestCompareKnownTools(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir())
+
+	readFile := mcpserver.Tool{Name: "read_file", InputSchema: &mcpserver.ToolSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{"path": map[string]interface{}{"type": "string"}},
+		Required:   []string{"path"},
+	}}
+	var out bytes.Buffer
+
+	// The first discovery of a server is recorded
+	changes := compareKnownTools(&out, map[string][]mcpserver.Tool{"fs": {readFile, {Name: "stat"}}})
+	if len(changes) != 0 {
+		t.Fatalf("first discovery changes = %v, want none", changes)
+	}
+	known, err := mcpserver.LoadKnownTools(GetToolSchemasPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(known.Servers["fs"]) != 2 {
+		t.Fatalf("recorded tools = %v, want read_file and stat", known.Servers["fs"])
+	}
+
+	// Later discoveries are compared without being recorded; added tools
+	// break nothing
+	changed := readFile
+	changed.InputSchema = &mcpserver.ToolSchema{Type: "object"}
+	for i := 0; i < 2; i++ {
+		changes = compareKnownTools(&out, map[string][]mcpserver.Tool{"fs": {changed, {Name: "write_file"}}})
+		want := map[string]string{"read_file": "changed (param path removed)", "stat": "removed"}
+		if len(changes["fs"]) != len(want) {
+			t.Fatalf("changes = %v, want %v", changes["fs"], want)
+		}
+		for tool, summary := range want {
+			if changes["fs"][tool] != summary {
+				t.Errorf("change of %s = %q, want %q", tool, changes["fs"][tool], summary)
+			}
+		}
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected warnings: %s", out.String())
+	}
+}
@@ -4,10 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
@@ -175,9 +172,10 @@ func displayFollowLogs(cmd *cobra.Command, exec *execution.Execution, trail *pkg
 		displayEvent(cmd.OutOrStdout(), event, trail.StartedAt, noColor)
 	}
 
-	// Setup signal handling for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// Stop following on SIGINT, SIGTERM or SIGHUP. Following is ended by
+	// an interrupt, so a signal is not reported as an error.
+	ctx, sd := notifyShutdown(context.Background())
+	defer sd.Stop()
 
 	// Conditionally use color codes
 	yellow := ""
@@ -191,13 +189,12 @@ func displayFollowLogs(cmd *cobra.Command, exec *execution.Execution, trail *pkg
 		reset = colorReset
 	}
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	go func() {
-		<-sigCh
-		_, _ = fmt.Fprintf(cmd.OutOrStderr(), "\n%sReceived interrupt signal, stopping...%s\n",
-			yellow, reset)
-		cancel()
+		<-ctx.Done()
+		if sd.Signal() != nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStderr(), "\n%sReceived interrupt signal, stopping...%s\n",
+				yellow, reset)
+		}
 	}()
 
 	// Create monitor for real-time events
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+// NewMigrateCommand creates the migrate command
+func NewMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [workflow-name|file.yaml]...",
+		Short: "Upgrade workflow files to the current schema version",
+		Long: fmt.Sprintf(`Rewrite workflow files saved with an older schema version in the current
+format, version %d, keeping each original as <file>.bak.
+
+Every command reads older files by migrating them in memory, without
+writing them. migrate saves the upgrade; so does running a workflow from
+the repository or saving it in the editor. Without arguments, every
+workflow in the repository is migrated. Read-only workflows and files that
+can't be written are reported and left unchanged.
+
+Examples:
+  goflow migrate
+  goflow migrate my-workflow
+  goflow migrate ./shared/report.yaml`, workflow.CurrentSchemaVersion),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			var paths []string
+			if len(args) == 0 {
+				found, err := filepath.Glob(filepath.Join(GetWorkflowsDir(), "*.yaml"))
+				if err != nil {
+					return fmt.Errorf("failed to list workflows: %w", err)
+				}
+				paths = found
+			}
+			for _, arg := range args {
+				path, err := resolveWorkflowPath(arg)
+				if err != nil {
+					return err
+				}
+				paths = append(paths, path)
+			}
+
+			failed := 0
+			for _, path := range paths {
+				migrated, err := workflow.MigrateFile(path)
+				switch {
+				case err != nil:
+					failed++
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "✗ %s: %v\n", path, err)
+				case migrated:
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Upgraded %s to schema version %d (original saved as %s.bak)\n", path, workflow.CurrentSchemaVersion, path)
+				default:
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s is current\n", path)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d workflow files could not be migrated", failed, len(paths))
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/domain/types"
+	pkgexec "github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewProfileCommand creates the profile command
+func NewProfileCommand() *cobra.Command {
+	var (
+		format string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "profile <execution-id>",
+		Short: "Display an execution's performance report",
+		Long: `Display where an execution spent its time: the duration of each node, MCP
+tool call latency against transform time, retries, and the size of each
+node's inputs and outputs.
+
+JSON and CSV exports can be kept to track performance regressions between
+runs. In CSV, durations are in milliseconds; in JSON, nanoseconds.
+
+Examples:
+  # Show the report
+  goflow profile exec-12345
+
+  # Export one row per node for a spreadsheet
+  goflow profile exec-12345 --format csv --output etl.csv
+
+  # Export the full report
+  goflow profile exec-12345 --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := storage.NewSQLiteExecutionRepository()
+			if err != nil {
+				return fmt.Errorf("failed to create execution repository: %w", err)
+			}
+			defer func() { _ = repo.Close() }()
+
+			exec, err := repo.Load(types.ExecutionID(args[0]))
+			if err != nil {
+				return fmt.Errorf("failed to load execution: %w", err)
+			}
+			report, err := pkgexec.BuildPerformanceReport(exec)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer func() { _ = file.Close() }()
+				out = file
+			}
+			return writeProfileReport(out, report, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json, csv)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the report to a file instead of stdout")
+
+	return cmd
+}
+
+// writeProfileReport writes a performance report in the given format
+func writeProfileReport(w io.Writer, report *pkgexec.PerformanceReport, format string) error {
+	var data []byte
+	var err error
+	switch format {
+	case "json":
+		data, err = report.ExportJSON()
+		data = append(data, '\n')
+	case "csv":
+		data, err = report.ExportCSV()
+	case "text", "":
+		data = []byte(formatProfileText(report))
+	default:
+		return fmt.Errorf("invalid format: %s (valid: text, json, csv)", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export report: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// formatProfileText renders a performance report as a table
+func formatProfileText(report *pkgexec.PerformanceReport) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Execution: %s (%s)\n", report.ExecutionID, report.WorkflowID)
+	fmt.Fprintf(&sb, "Status:    %s\n", report.Status)
+	fmt.Fprintf(&sb, "Duration:  %s\n\n", formatDurationValue(report.Duration))
+
+	fmt.Fprintf(&sb, "MCP calls:  %d, %s\n", report.MCPCalls, formatDurationValue(report.MCPTime))
+	if report.CacheHits > 0 {
+		fmt.Fprintf(&sb, "Cache hits: %d\n", report.CacheHits)
+	}
+	fmt.Fprintf(&sb, "Transforms: %d, %s\n", report.Transforms, formatDurationValue(report.TransformTime))
+	fmt.Fprintf(&sb, "Other:      %s\n", formatDurationValue(report.OtherTime))
+	fmt.Fprintf(&sb, "Retries:    %d\n", report.Retries)
+	fmt.Fprintf(&sb, "Payloads:   %d bytes in, %d bytes out\n\n", report.InputBytes, report.OutputBytes)
+
+	fmt.Fprintf(&sb, "%-25s %-12s %5s %7s %9s %9s %9s %10s %10s\n",
+		"Node", "Type", "Runs", "Retries", "Total", "Avg", "Max", "In", "Out")
+	sb.WriteString(strings.Repeat("-", 103) + "\n")
+	for _, node := range report.Nodes {
+		fmt.Fprintf(&sb, "%-25s %-12s %5d %7d %9s %9s %9s %10d %10d\n",
+			truncateString(string(node.NodeID), 25), node.NodeType, node.Executions, node.Retries,
+			formatDurationValue(node.TotalDuration), formatDurationValue(node.AverageDuration()), formatDurationValue(node.MaxDuration),
+			node.InputBytes, node.OutputBytes)
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	pkgexec "github.com/dshills/goflow/pkg/execution"
+)
+
+func TestWriteProfileReport(t *testing.T) {
+	exec, err := execution.NewExecution("etl", "1.0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeExec := execution.NewNodeExecution(exec.ID, "fetch", "mcp_tool")
+	nodeExec.CompletedAt = nodeExec.StartedAt.Add(250 * time.Millisecond)
+	nodeExec.Status = execution.NodeStatusCompleted
+	exec.NodeExecutions = append(exec.NodeExecutions, nodeExec)
+
+	report, err := pkgexec.BuildPerformanceReport(exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text bytes.Buffer
+	if err := writeProfileReport(&text, report, "text"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "MCP calls:  1, 250ms") || !strings.Contains(text.String(), "fetch") {
+		t.Errorf("text report:\n%s", text.String())
+	}
+
+	var csv bytes.Buffer
+	if err := writeProfileReport(&csv, report, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[1], ",250.000,") {
+		t.Errorf("csv report:\n%s", csv.String())
+	}
+
+	if err := writeProfileReport(&text, report, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
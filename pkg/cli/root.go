@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/dshills/goflow/pkg/config"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -58,6 +59,7 @@ with conditional logic, data transformation, and parallel execution.`,
 
 	// Add subcommands
 	cmd.AddCommand(NewServerCommand())
+	cmd.AddCommand(NewEnvironmentCommand())
 	cmd.AddCommand(NewCredentialCommand())
 	cmd.AddCommand(NewValidateCommand())
 	cmd.AddCommand(NewRunCommand())
@@ -66,8 +68,17 @@ with conditional logic, data transformation, and parallel execution.`,
 	cmd.AddCommand(NewExecutionsCommand())
 	cmd.AddCommand(NewExecutionCommand())
 	cmd.AddCommand(NewLogsCommand())
+	cmd.AddCommand(NewProfileCommand())
 	cmd.AddCommand(NewExportCommand())
 	cmd.AddCommand(NewImportCommand())
+	cmd.AddCommand(NewEventsCommand())
+	cmd.AddCommand(NewLintCommand())
+	cmd.AddCommand(NewGraphCommand())
+	cmd.AddCommand(NewDiffCommand())
+	cmd.AddCommand(NewMigrateCommand())
+	cmd.AddCommand(NewTokenCommand())
+	cmd.AddCommand(NewAuditCommand())
+	cmd.AddCommand(NewKeyCommand())
 
 	return cmd
 }
@@ -105,8 +116,9 @@ func initConfig() error {
 	configFile := filepath.Join(GlobalConfig.ConfigDir, "config.yaml")
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
 		// Create default config
-		defaultConfig := map[string]interface{}{
-			"version": "1.0",
+		defaultConfig := globalConfigFile{
+			Version:  "1.0",
+			Tunables: config.DefaultTunables(),
 		}
 		data, err := yaml.Marshal(defaultConfig)
 		if err != nil {
@@ -117,6 +129,15 @@ func initConfig() error {
 		}
 	}
 
+	// Apply tunables (timings and queue sizes)
+	warnings, err := LoadTunables(configFile)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", configFile, warning)
+	}
+
 	return nil
 }
 
@@ -148,6 +169,44 @@ func GetServersConfigPath() string {
 	return filepath.Join(GetConfigDir(), "servers.yaml")
 }
 
+// GetEnvironmentsPath returns the path to the environments configuration file
+func GetEnvironmentsPath() string {
+	return filepath.Join(GetConfigDir(), "environments.yaml")
+}
+
+// GetAuditLogPath returns the path to the audit log
+func GetAuditLogPath() string {
+	return filepath.Join(GetConfigDir(), "audit.jsonl")
+}
+
+// GetAuthConfigPath returns the path to the auth config of API tokens
+func GetAuthConfigPath() string {
+	return filepath.Join(GetConfigDir(), "auth.yaml")
+}
+
+// GetKeysDir returns the directory of the signing keys made by goflow key
+// generate
+func GetKeysDir() string {
+	return filepath.Join(GetConfigDir(), "keys")
+}
+
+// GetTrustedKeysDir returns the directory of the public keys trusted to
+// sign workflows
+func GetTrustedKeysDir() string {
+	return filepath.Join(GetConfigDir(), "trusted_keys")
+}
+
+// GetSandboxProfilesPath returns the path of the custom sandbox profiles
+func GetSandboxProfilesPath() string {
+	return filepath.Join(GetConfigDir(), "sandbox.yaml")
+}
+
+// GetToolSchemasPath returns the path to the file recording the tool
+// schemas of each server when last discovered
+func GetToolSchemasPath() string {
+	return filepath.Join(GetConfigDir(), "tool_schemas.json")
+}
+
 // Execute runs the root command
 func Execute() error {
 	return NewRootCommand().Execute()
@@ -1,19 +1,27 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
-	"os/signal"
 	"path/filepath"
+	"strings"
 	"sync"
-	"syscall"
 	"time"
 
+	"github.com/dshills/goflow/pkg/audit"
 	domainexec "github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/events"
 	"github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/rbac"
+	"github.com/dshills/goflow/pkg/storage"
 	"github.com/dshills/goflow/pkg/tui"
 	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/dshills/goterm"
@@ -24,15 +32,27 @@ import (
 // NewRunCommand creates the run command
 func NewRunCommand() *cobra.Command {
 	var (
-		inputFile    string
-		watch        bool
-		tuiMode      bool
-		outputJSON   bool
-		varFlags     []string // Inline variables (--var key=value)
-		debugMode    bool
-		outputFormat string
-		timeout      int // Timeout in seconds
-		fromStdin    bool
+		inputFile     string
+		watch         bool
+		tuiMode       bool
+		outputJSON    bool
+		varFlags      []string // Inline variables (--var key=value)
+		paramFlags    []string // Typed parameters (--param key=value)
+		paramFiles    []string // Parameter files (--param-file params.yaml)
+		debugMode     bool
+		outputFormat  string
+		timeout       int // Timeout in seconds
+		fromStdin     bool
+		guardrails    execution.Guardrails
+		maxVarsMB     int
+		maxPayloadKB  int
+		approvalAddr  string
+		metricsAddr   string
+		authConfig    string   // Auth config guarding the approval and metrics APIs
+		serverTags    []string // Tags every server alias must carry (--server-tag prod)
+		environment   string   // Environment mapping workflow servers to registered ones
+		requireSigned bool     // Only run workflows signed by a trusted key
+		sandbox       string   // Sandbox profile restricting node types and tools
 	)
 
 	cmd := &cobra.Command{
@@ -42,6 +62,41 @@ func NewRunCommand() *cobra.Command {
 
 The workflow is loaded from ~/.goflow/workflows/<workflow-name>.yaml
 
+Parameters set workflow variables. --param values are converted to the
+variable's declared type (number, boolean, JSON object or array) and must
+name a declared variable. --param-file reads a YAML or JSON mapping; later
+files and --param flags override earlier values.
+
+Without --watch or --tui, node progress is streamed to stdout as plain lines,
+suitable for CI logs. The command exits with a nonzero status when the
+workflow fails.
+
+--environment runs the workflow's servers against the registered servers an
+environment maps them to (see goflow environment); without it, the default
+environment applies, if one is set.
+
+--require-signed, or an environment set with --require-signed, refuses to
+run workflows that are not signed by a trusted key (see goflow key).
+
+--sandbox runs the workflow under a profile restricting the node types and
+tool categories it may execute: read-only (no write or exec tools),
+no-network (no network tools), no-tools, or one defined in
+~/.goflow/sandbox.yaml. Reaching a node the profile denies aborts the run
+with a policy error; goflow lint --sandbox reports such nodes ahead of time.
+
+Approval nodes wait for a decision: press a or n in the --tui monitor, or
+serve the approval API with --approval-addr and POST to
+/approvals/<node>/approve or /approvals/<node>/reject.
+
+--metrics-addr serves the tool call counts and latency percentiles of each
+server while the workflow runs: Prometheus text at /metrics, and JSON with
+the most recent calls at /metrics/servers.
+
+--auth requires callers of these APIs to authenticate with a bearer token
+from the auth config file, or one from its OpenID Connect provider. Viewers
+may read approvals and metrics; deciding approvals needs the operator or
+admin role.
+
 Examples:
   # Run workflow with default variables
   goflow run my-workflow
@@ -49,6 +104,9 @@ Examples:
   # Run with input variables from JSON file
   goflow run my-workflow --input input.json
 
+  # Run headless in CI with typed parameters
+  goflow run my-workflow --param-file ci.yaml --param retries=3 --param dry_run=true
+
   # Run with inline progress monitoring
   goflow run my-workflow --watch
 
@@ -56,7 +114,25 @@ Examples:
   goflow run my-workflow --tui
 
   # Run with debug output
-  goflow run my-workflow --debug`,
+  goflow run my-workflow --debug
+
+  # Run against the servers tagged prod
+  goflow run my-workflow --server-tag prod
+
+  # Run against the servers the staging environment maps to
+  goflow run my-workflow --environment staging
+
+  # Decide approval nodes over HTTP
+  goflow run my-workflow --approval-addr 127.0.0.1:8088
+  curl -X POST -d '{"by": "ada"}' http://127.0.0.1:8088/approvals/review/approve
+
+  # Watch per-server call counts and latencies during the run
+  goflow run my-workflow --metrics-addr 127.0.0.1:9090
+  curl http://127.0.0.1:9090/metrics
+
+  # Only let authenticated operators decide approvals
+  goflow run my-workflow --approval-addr 0.0.0.0:8088 --auth ~/.goflow/auth.yaml
+  curl -X POST -H "Authorization: Bearer $TOKEN" http://host:8088/approvals/review/approve`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if fromStdin {
 				return nil // No args required when reading from stdin
@@ -64,6 +140,9 @@ Examples:
 			return cobra.ExactArgs(1)(cmd, args)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Arguments are valid; errors from here on are not usage errors
+			cmd.SilenceUsage = true
+
 			var workflowName string
 			var workflowPath string
 
@@ -78,9 +157,26 @@ Examples:
 			var wf *workflow.Workflow
 			var err error
 
+			// Refuse unsigned workflows where signatures are required
+			if !requireSigned {
+				requireSigned, err = environmentRequiresSigned(environment)
+				if err != nil {
+					return err
+				}
+			}
+
 			if fromStdin {
 				// Read workflow from stdin (use cmd.InOrStdin for testability)
-				wf, err = LoadWorkflowFromReader(cmd.InOrStdin())
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read workflow from stdin: %w", err)
+				}
+				if requireSigned {
+					if err := checkWorkflowSignature(cmd, data, true); err != nil {
+						return err
+					}
+				}
+				wf, err = LoadWorkflowFromReader(bytes.NewReader(data))
 				if err != nil {
 					return fmt.Errorf("failed to parse workflow from stdin: %w", err)
 				}
@@ -90,18 +186,58 @@ Examples:
 					return fmt.Errorf("workflow not found: %s\n\nLooked in: %s", workflowName, workflowPath)
 				}
 
-				// Load workflow file
-				wf, err = LoadWorkflowFromFile(workflowPath)
-				if err != nil {
-					return fmt.Errorf("failed to parse workflow YAML: %w", err)
+				if requireSigned {
+					// Parse the bytes whose signature was checked, not the
+					// file again, which could have changed in between. A
+					// signed file keeps the bytes its signature covers, so
+					// it is not upgraded.
+					data, err := os.ReadFile(workflowPath)
+					if err != nil {
+						return fmt.Errorf("failed to read workflow file: %w", err)
+					}
+					if err := checkWorkflowSignature(cmd, data, true); err != nil {
+						return err
+					}
+					wf, err = LoadWorkflowFromReader(bytes.NewReader(data))
+					if err != nil {
+						return fmt.Errorf("failed to parse workflow YAML: %w", err)
+					}
+				} else {
+					wf, err = LoadWorkflowFromFile(workflowPath)
+					if err != nil {
+						return fmt.Errorf("failed to parse workflow YAML: %w", err)
+					}
+					// Save the upgrade of an older file
+					upgradeWorkflowFile(cmd.ErrOrStderr(), workflowPath)
 				}
 			}
 
 			// Validate workflow
 			if err := wf.Validate(); err != nil {
+				events.Default().Publish(events.ValidationFailed(wf.Name, err.Error()))
 				return fmt.Errorf("workflow validation failed: %w", err)
 			}
 
+			// Sandbox profiles judge tools by the categories servers.yaml
+			// declares, never by the workflow's own say
+			if sandbox != "" {
+				repo, err := mcpserver.NewFileRepository(GetServersConfigPath())
+				if err != nil {
+					return fmt.Errorf("failed to load servers config: %w", err)
+				}
+				applyRegisteredToolCategories(wf, repo)
+			}
+
+			// Point servers the environment maps at their registered servers
+			if _, err := applyEnvironment(wf, environment); err != nil {
+				return err
+			}
+
+			// Point server aliases at the registered servers their tags select
+			if err := resolveServerAliases(wf, serverTags); err != nil {
+				return err
+			}
+
 			// Load input variables if provided
 			inputVars := make(map[string]interface{})
 
@@ -117,6 +253,26 @@ Examples:
 				}
 			}
 
+			// Load parameter files, later files overriding earlier ones
+			for _, paramFile := range paramFiles {
+				params, err := loadParamFile(paramFile)
+				if err != nil {
+					return err
+				}
+				for name, value := range params {
+					inputVars[name] = value
+				}
+			}
+
+			// Parse typed parameters (--param key=value)
+			params, err := parseParams(paramFlags, wf)
+			if err != nil {
+				return err
+			}
+			for name, value := range params {
+				inputVars[name] = value
+			}
+
 			// Parse inline variables (--var key=value)
 			for _, varFlag := range varFlags {
 				parts := splitKeyValue(varFlag)
@@ -154,38 +310,97 @@ Examples:
 				outputJSON = true
 			}
 
-			// Create execution engine
-			engine := execution.NewEngine()
+			// Create execution engine. Its event handler records the run and
+			// approval decisions in the audit log, and headless text runs
+			// stream node progress through it so no events are missed.
+			engineOpts := []execution.EngineOption{execution.WithToolCache(execution.SharedToolCache())}
+			guardrails.MaxVariablesBytes = int64(maxVarsMB) << 20
+			guardrails.MaxPayloadBytes = int64(maxPayloadKB) << 10
+			if !guardrails.IsZero() {
+				engineOpts = append(engineOpts, execution.WithGuardrails(guardrails))
+			}
+			if sandbox != "" {
+				profile, err := workflow.LoadSandboxProfile(GetSandboxProfilesPath(), sandbox)
+				if err != nil {
+					return err
+				}
+				engineOpts = append(engineOpts, execution.WithSandbox(profile))
+			}
+			auditLog, actor := audit.NewLog(GetAuditLogPath()), audit.LocalActor()
+			var state *watchState
+			if !tuiMode && !watch && !outputJSON {
+				state = &watchState{startTime: time.Now(), nodeCount: len(wf.Nodes)}
+			}
+			engineOpts = append(engineOpts, execution.WithEventHandler(func(event execution.ExecutionEvent) {
+				recordExecutionAudit(cmd, auditLog, actor, workflowName, event)
+				if state != nil {
+					handleInlineEvent(cmd, event, state, false)
+				}
+			}))
+			engine := execution.NewEngine(engineOpts...)
 			defer func() { _ = engine.Close() }()
 
-			// Create context with cancellation
-			ctx, cancel := context.WithCancel(context.Background())
+			// Serve the approval API for the duration of the run, guarded by
+			// the auth config if given
+			var authenticator rbac.Authenticator
+			if authConfig != "" {
+				fileAuthenticator, err := rbac.NewFileAuthenticator(authConfig, nil)
+				if err != nil {
+					return err
+				}
+				authenticator = fileAuthenticator
+			}
+			guard := func(handler http.Handler, permission func(*http.Request) rbac.Permission) http.Handler {
+				if authenticator == nil {
+					return handler
+				}
+				return rbac.Require(authenticator, permission, handler)
+			}
+			if approvalAddr != "" {
+				approvals := guard(execution.NewApprovalHandler(engine), rbac.ByMethod(rbac.View, rbac.OperateExecutions))
+				stopApprovals, err := serveHTTP(cmd, approvalAddr, approvals, "Approval", "/approvals")
+				if err != nil {
+					return err
+				}
+				defer stopApprovals()
+			}
+			if metricsAddr != "" {
+				stopMetrics, err := serveHTTP(cmd, metricsAddr, guard(mcpserver.NewMetricsHandler(engine.Servers(), engine.WriteToolCacheMetrics), rbac.Always(rbac.View)), "Metrics", "/metrics")
+				if err != nil {
+					return err
+				}
+				defer stopMetrics()
+			}
+
+			// Cancel the execution on SIGINT, SIGTERM or SIGHUP. Every mode
+			// waits for the engine to record the cancellation before the
+			// deferred engine.Close releases storage and server connections.
+			ctx, sd := notifyShutdown(context.Background())
+			defer sd.Stop()
+
+			// Cancellation from within the TUI (Ctrl+C is a key press in raw mode)
+			ctx, cancel := context.WithCancel(ctx)
 			defer cancel()
 
 			// Apply timeout if specified
 			if timeout > 0 {
-				ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
-				defer cancel()
+				var cancelTimeout context.CancelFunc
+				ctx, cancelTimeout = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+				defer cancelTimeout()
 			}
 
-			// Handle Ctrl+C for graceful cancellation
-			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-			go func() {
-				<-sigChan
-				cancel()
-			}()
-
 			// Decide execution mode: TUI, watch (inline), or silent
 			if tuiMode {
 				// Launch TUI monitoring mode
-				return runWithTUI(ctx, engine, wf, workflowName, inputVars)
+				err = runWithTUI(ctx, cancel, engine, wf, workflowName, inputVars)
 			} else if watch {
 				// Run with inline watch mode
-				return runWithInlineWatch(ctx, cmd, engine, wf, workflowName, inputVars, outputJSON, debugMode)
+				err = runWithInlineWatch(ctx, cmd, engine, wf, workflowName, inputVars, outputJSON, debugMode)
+			} else {
+				// Run headless, streaming node progress
+				err = runSilent(ctx, cmd, engine, wf, workflowName, inputVars, outputJSON, debugMode)
 			}
-			// Run silently, only show result at end
-			return runSilent(ctx, cmd, engine, wf, workflowName, inputVars, outputJSON, debugMode)
+			return sd.Err(err)
 		},
 	}
 
@@ -194,14 +409,77 @@ Examples:
 	cmd.Flags().BoolVar(&tuiMode, "tui", false, "Launch full TUI execution monitor")
 	cmd.Flags().BoolVar(&outputJSON, "output-json", false, "Output result as JSON")
 	cmd.Flags().StringArrayVar(&varFlags, "var", []string{}, "Set input variable (key=value), can be used multiple times")
+	cmd.Flags().StringArrayVar(&paramFlags, "param", []string{}, "Set a typed workflow parameter (key=value), can be used multiple times")
+	cmd.Flags().StringArrayVar(&paramFiles, "param-file", []string{}, "Load workflow parameters from a YAML or JSON file, can be used multiple times")
 	cmd.Flags().BoolVar(&debugMode, "debug", false, "Enable debug output")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format (json or text)")
 	cmd.Flags().IntVar(&timeout, "timeout", 0, "Execution timeout in seconds (0 = no timeout)")
 	cmd.Flags().BoolVar(&fromStdin, "stdin", false, "Read workflow definition from stdin")
+	cmd.Flags().IntVar(&maxVarsMB, "max-variables-mb", 0, "Abort if variables exceed this many MB (0 = max_variables_mb tunable)")
+	cmd.Flags().IntVar(&maxPayloadKB, "max-payload-kb", 0, "Abort if a node's inputs or outputs exceed this many KB (0 = max_payload_kb tunable)")
+	cmd.Flags().IntVar(&guardrails.MaxNodeExecutions, "max-node-executions", 0, "Abort after this many node executions (0 = max_node_executions tunable)")
+	cmd.Flags().DurationVar(&guardrails.MaxWallClock, "max-duration", 0, "Abort if the run takes longer, e.g. 10m (0 = max_execution_sec tunable)")
+	cmd.Flags().StringVarP(&environment, "environment", "e", "", "Environment mapping workflow servers to registered servers (default: goflow environment use)")
+	cmd.Flags().BoolVar(&requireSigned, "require-signed", false, "Refuse to run a workflow not signed by a trusted key")
+	cmd.Flags().StringVar(&sandbox, "sandbox", "", "Sandbox profile restricting node types and tool categories: read-only, no-network, no-tools, or one in sandbox.yaml")
+	cmd.Flags().StringSliceVar(&serverTags, "server-tag", nil, "Tags every server alias must also carry, e.g. prod, can be used multiple times")
+	cmd.Flags().StringVar(&approvalAddr, "approval-addr", "", "Serve the approval REST API on this address during the run, e.g. 127.0.0.1:8088")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve per-server call metrics on this address during the run, e.g. 127.0.0.1:9090")
+	cmd.Flags().StringVar(&authConfig, "auth", "", "Auth config file of tokens and OIDC settings the approval and metrics APIs require")
 
 	return cmd
 }
 
+// recordExecutionAudit records who started or cancelled an execution, or
+// decided one of its approvals, in the audit log. Approvals are recorded as
+// decided by whoever the decision names, which is the authenticated caller
+// for APIs behind --auth.
+func recordExecutionAudit(cmd *cobra.Command, log *audit.Log, actor, workflowName string, event execution.ExecutionEvent) {
+	var err error
+	switch event.Type {
+	case execution.EventExecutionStarted:
+		err = log.Record(actor, audit.ExecutionStarted, event.ExecutionID.String(), workflowName)
+	case execution.EventExecutionCancelled:
+		err = log.Record(actor, audit.ExecutionCancelled, event.ExecutionID.String(), workflowName)
+	case execution.EventApprovalDecided:
+		decision := "rejected"
+		if approved, _ := event.Metadata["approved"].(bool); approved {
+			decision = "approved"
+		}
+		by, _ := event.Metadata["by"].(string)
+		if by == "" {
+			by = actor
+		}
+		if timedOut, _ := event.Metadata["timed_out"].(bool); timedOut {
+			decision += " on timeout"
+		}
+		err = log.Record(by, audit.ApprovalDecided, string(event.NodeID), fmt.Sprintf("%s in execution %s", decision, event.ExecutionID))
+	default:
+		return
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err) // Error ignored: terminal output, failure is non-critical
+	}
+}
+
+// serveHTTP serves handler, the API named name, on addr until the returned
+// function is called, announcing its URL at path
+func serveHTTP(cmd *cobra.Command, addr string, handler http.Handler, name, path string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serve the %s API: %w", strings.ToLower(name), err)
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = server.Serve(listener) }() // Error ignored: Serve returns ErrServerClosed when stopped
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s API listening on http://%s%s\n", name, listener.Addr(), path) // Error ignored: terminal output, failure is non-critical
+	return func() { _ = server.Close() }, nil
+}
+
 // splitKeyValue splits a string like "key=value" into ["key", "value"]
 func splitKeyValue(s string) []string {
 	idx := -1
@@ -217,15 +495,17 @@ func splitKeyValue(s string) []string {
 	return []string{s[:idx], s[idx+1:]}
 }
 
-// runWithTUI launches the full TUI execution monitor.
-func runWithTUI(ctx context.Context, engine *execution.Engine, wf *workflow.Workflow, workflowName string, inputs map[string]interface{}) error {
+// runWithTUI launches the full TUI execution monitor. Ctrl+C or q cancels
+// the execution; the monitor then waits for it to stop before exiting.
+func runWithTUI(ctx context.Context, cancel context.CancelFunc, engine *execution.Engine, wf *workflow.Workflow, workflowName string, inputs map[string]interface{}) error {
 	// Create a goroutine to run the execution
 	var exec *domainexec.Execution
 	var execErr error
 	execDone := make(chan struct{})
 
 	go func() {
-		exec, execErr = engine.Execute(ctx, wf, inputs)
+		exec, execErr = execution.SharedDispatcher().Execute(ctx, engine, wf, inputs, execution.PriorityInteractive)
+		saveRunSample(wf, exec)
 		close(execDone)
 	}()
 
@@ -234,7 +514,7 @@ func runWithTUI(ctx context.Context, engine *execution.Engine, wf *workflow.Work
 	if err != nil {
 		return fmt.Errorf("failed to initialize TUI: %w", err)
 	}
-	defer func() { _ = screen.Close() }()
+	defer func() { _ = tui.CloseScreen(screen) }()
 
 	// Wait for execution to start and get monitor
 	time.Sleep(100 * time.Millisecond)
@@ -251,8 +531,16 @@ func runWithTUI(ctx context.Context, engine *execution.Engine, wf *workflow.Work
 	// Create execution monitor view
 	monitorView := tui.NewExecutionMonitor(exec, wf, screen)
 	monitorView.SetEventMonitor(monitor)
+	monitorView.SetNodeRetrier(engine)
+	monitorView.SetPauser(engine)
+	monitorView.SetCanceller(engine)
+	monitorView.SetApprover(engine)
 	defer monitorView.Close()
 
+	// Raw mode turns Ctrl+C into a key press instead of SIGINT
+	keys := make(chan rune, 16)
+	go readMonitorKeys(keys)
+
 	// TUI event loop with periodic refresh
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
@@ -273,8 +561,24 @@ func runWithTUI(ctx context.Context, engine *execution.Engine, wf *workflow.Work
 			return nil
 
 		case <-ctx.Done():
+			// Let the engine record the cancellation before exiting
+			_, _ = monitorView.Render()
+			if !waitForShutdown(execDone) {
+				return fmt.Errorf("execution did not stop within %s", shutdownGracePeriod)
+			}
+			if execErr != nil {
+				return execErr
+			}
 			return fmt.Errorf("execution cancelled")
 
+		case key := <-keys:
+			if key == ctrlC || key == 'q' {
+				cancel()
+				continue
+			}
+			monitorView.HandleKey(key)
+			_, _ = monitorView.Render()
+
 		case <-ticker.C:
 			// Periodic refresh
 			_, _ = monitorView.Render()
@@ -282,6 +586,29 @@ func runWithTUI(ctx context.Context, engine *execution.Engine, wf *workflow.Work
 	}
 }
 
+// ctrlC is the byte a terminal in raw mode sends for Ctrl+C
+const ctrlC = 3
+
+// readMonitorKeys forwards single-byte key presses from stdin until stdin
+// is closed. Multi-byte sequences (arrow keys) are ignored.
+func readMonitorKeys(keys chan<- rune) {
+	buf := make([]byte, 32)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		if n != 1 {
+			continue
+		}
+		select {
+		case keys <- rune(buf[0]):
+		default:
+			// Drop keys the monitor is not keeping up with
+		}
+	}
+}
+
 // runWithInlineWatch runs execution with inline progress updates.
 func runWithInlineWatch(ctx context.Context, cmd *cobra.Command, engine *execution.Engine, wf *workflow.Workflow, workflowName string, inputs map[string]interface{}, outputJSON, debugMode bool) error {
 	// Start execution in background
@@ -290,7 +617,8 @@ func runWithInlineWatch(ctx context.Context, cmd *cobra.Command, engine *executi
 	execDone := make(chan struct{})
 
 	go func() {
-		exec, execErr = engine.Execute(ctx, wf, inputs)
+		exec, execErr = execution.SharedDispatcher().Execute(ctx, engine, wf, inputs, execution.PriorityInteractive)
+		saveRunSample(wf, exec)
 		close(execDone)
 	}()
 
@@ -347,6 +675,18 @@ func runWithInlineWatch(ctx context.Context, cmd *cobra.Command, engine *executi
 			}
 
 		case <-ctx.Done():
+			// Let the engine record the cancellation before exiting
+			if !waitForShutdown(execDone) {
+				return fmt.Errorf("execution did not stop within %s", shutdownGracePeriod)
+			}
+			if !outputJSON {
+				displayFinalResult(cmd, exec, execErr, state, debugMode)
+			} else {
+				displayJSONResult(cmd, exec, execErr)
+			}
+			if execErr != nil {
+				return execErr
+			}
 			return fmt.Errorf("execution cancelled")
 		}
 	}
@@ -358,8 +698,9 @@ func runSilent(ctx context.Context, cmd *cobra.Command, engine *execution.Engine
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Executing workflow: %s\n", workflowName) // Error ignored: terminal output, failure is non-critical
 	}
 
-	// Execute workflow
-	exec, err := engine.Execute(ctx, wf, inputs)
+	// Execute workflow through the dispatcher shared with every other run
+	exec, err := execution.SharedDispatcher().Execute(ctx, engine, wf, inputs, execution.PriorityInteractive)
+	saveRunSample(wf, exec)
 
 	// Display result
 	if !outputJSON {
@@ -371,6 +712,34 @@ func runSilent(ctx context.Context, cmd *cobra.Command, engine *execution.Engine
 	return err
 }
 
+// saveRunSample records the variables a run ended with, so the workflow
+// builder can preview expressions against them, and the output of each
+// tool call, so it can suggest the fields of tool outputs. Recording is
+// best effort.
+func saveRunSample(wf *workflow.Workflow, exec *domainexec.Execution) {
+	if exec == nil || exec.Context == nil {
+		return
+	}
+	_ = storage.NewSampleStore(storage.DefaultSamplesDir()).Save(wf.Name, exec.Context.CreateSnapshot())
+
+	toolNodes := make(map[string]*workflow.MCPToolNode)
+	for _, node := range wf.Nodes {
+		if n, ok := node.(*workflow.MCPToolNode); ok && n.OutputVariable != "" {
+			toolNodes[n.ID] = n
+		}
+	}
+	tools := storage.NewToolSampleStore(storage.DefaultToolSamplesDir())
+	for _, nodeExec := range exec.NodeExecutions {
+		n, ok := toolNodes[string(nodeExec.NodeID)]
+		if !ok || nodeExec.Status != domainexec.NodeStatusCompleted {
+			continue
+		}
+		if output, ok := nodeExec.Outputs[n.OutputVariable]; ok {
+			_ = tools.Record(n.ServerID, n.ToolName, output)
+		}
+	}
+}
+
 // watchState tracks state for inline watch display.
 type watchState struct {
 	startTime      time.Time
@@ -406,6 +775,40 @@ func handleInlineEvent(cmd *cobra.Command, event execution.ExecutionEvent, state
 		timestamp := elapsed.Truncate(time.Millisecond)
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s ✗ %s failed: %v\n", timestamp, event.NodeID, event.Error) // Error ignored: terminal output, failure is non-critical
 
+	case execution.EventExecutionPaused:
+		timestamp := elapsed.Truncate(time.Millisecond)
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s ⏸ Execution paused\n", timestamp) // Error ignored: terminal output, failure is non-critical
+
+	case execution.EventExecutionResumed:
+		timestamp := elapsed.Truncate(time.Millisecond)
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s ▶ Execution resumed\n", timestamp) // Error ignored: terminal output, failure is non-critical
+
+	case execution.EventApprovalRequested:
+		timestamp := elapsed.Truncate(time.Millisecond)
+		message, _ := event.Metadata["message"].(string)
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s ⏸ %s waiting for approval: %s\n", timestamp, event.NodeID, message) // Error ignored: terminal output, failure is non-critical
+
+	case execution.EventApprovalDecided:
+		timestamp := elapsed.Truncate(time.Millisecond)
+		by, _ := event.Metadata["by"].(string)
+		if approved, _ := event.Metadata["approved"].(bool); approved {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s ✓ %s approved by %s\n", timestamp, event.NodeID, by) // Error ignored: terminal output, failure is non-critical
+		} else {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s ✗ %s rejected by %s\n", timestamp, event.NodeID, by) // Error ignored: terminal output, failure is non-critical
+		}
+
+	case execution.EventToolProgress:
+		timestamp := elapsed.Truncate(time.Millisecond)
+		progress, _ := event.Metadata["progress"].(float64)
+		line := fmt.Sprintf("%g", progress)
+		if total, _ := event.Metadata["total"].(float64); total > 0 {
+			line = fmt.Sprintf("%g/%g", progress, total)
+		}
+		if message, _ := event.Metadata["message"].(string); message != "" {
+			line += " " + message
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s ⋯ %s progress %s\n", timestamp, event.NodeID, line) // Error ignored: terminal output, failure is non-critical
+
 	case execution.EventVariableChanged:
 		state.variables = event.Variables
 	}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"gopkg.in/yaml.v3"
+)
+
+// loadParamFile reads workflow parameters from a YAML or JSON file
+// containing a single mapping of variable names to values.
+func loadParamFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parameter file: %w", err)
+	}
+
+	// YAML is a superset of JSON, so one decoder handles both formats
+	params := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse parameter file %s: %w", path, err)
+	}
+	return params, nil
+}
+
+// parseParams converts --param key=value flags to typed values using the
+// types of the workflow's declared variables. Unknown names are rejected so
+// that typos fail a CI run instead of being silently ignored.
+func parseParams(flags []string, wf *workflow.Workflow) (map[string]interface{}, error) {
+	declared := make(map[string]*workflow.Variable, len(wf.Variables))
+	for _, variable := range wf.Variables {
+		if variable != nil {
+			declared[variable.Name] = variable
+		}
+	}
+
+	params := make(map[string]interface{}, len(flags))
+	for _, flag := range flags {
+		parts := splitKeyValue(flag)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid parameter format: %s (expected key=value)", flag)
+		}
+		name, raw := parts[0], parts[1]
+
+		variable, ok := declared[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown parameter: %s (declared variables: %s)", name, declaredVariableNames(declared))
+		}
+
+		value, err := parseParamValue(raw, variable.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for parameter %s: %w", name, err)
+		}
+		params[name] = value
+	}
+	return params, nil
+}
+
+// parseParamValue converts a command-line string to the given variable type
+func parseParamValue(raw, varType string) (interface{}, error) {
+	switch varType {
+	case "string":
+		return raw, nil
+	case "number":
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", raw)
+		}
+		return number, nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", raw)
+		}
+		return b, nil
+	case "object":
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &object); err != nil {
+			return nil, fmt.Errorf("expected a JSON object: %w", err)
+		}
+		return object, nil
+	case "array":
+		var array []interface{}
+		if err := json.Unmarshal([]byte(raw), &array); err != nil {
+			return nil, fmt.Errorf("expected a JSON array: %w", err)
+		}
+		return array, nil
+	default:
+		// Untyped: accept JSON values, otherwise keep the raw string
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value, nil
+		}
+		return raw, nil
+	}
+}
+
+// declaredVariableNames returns a sorted, comma-separated list of names
+func declaredVariableNames(declared map[string]*workflow.Variable) string {
+	if len(declared) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -1,12 +1,15 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/dshills/goflow/pkg/audit"
+	"github.com/dshills/goflow/pkg/mcp"
 	"github.com/dshills/goflow/pkg/mcpserver"
 	"github.com/dshills/goflow/pkg/validation"
 	"github.com/spf13/cobra"
@@ -27,7 +30,13 @@ type ServerEntry struct {
 	Args          []string          `yaml:"args,omitempty"`
 	Transport     string            `yaml:"transport,omitempty"`
 	Env           map[string]string `yaml:"env,omitempty"`
+	Headers       map[string]string `yaml:"headers,omitempty"`
+	Tags          []string          `yaml:"tags,omitempty"`
 	CredentialRef string            `yaml:"credential_ref,omitempty"`
+
+	// ToolCategories declares the effects of the server's tools for
+	// sandbox profiles; only the operator's servers.yaml sets them
+	ToolCategories map[string][]string `yaml:"tool_categories,omitempty"`
 }
 
 // NewServerCommand creates the server management command
@@ -44,6 +53,8 @@ func NewServerCommand() *cobra.Command {
 	cmd.AddCommand(newServerRemoveCommand())
 	cmd.AddCommand(newServerUpdateCommand())
 	cmd.AddCommand(newServerShowCommand())
+	cmd.AddCommand(newServerProxyCommand())
+	cmd.AddCommand(newServerImportCommand())
 
 	return cmd
 }
@@ -53,6 +64,7 @@ func newServerAddCommand() *cobra.Command {
 	var (
 		transport     string
 		envVars       []string
+		tags          []string
 		credentialRef string
 		name          string
 		description   string
@@ -71,7 +83,10 @@ Examples:
   goflow server add myserver node server.js --transport sse
 
   # Add with environment variables
-  goflow server add api-server python api.py --env API_KEY=value --env DEBUG=true`,
+  goflow server add api-server python api.py --env API_KEY=value --env DEBUG=true
+
+  # Tag a server so workflows can select it by alias
+  goflow server add db-prod postgres-mcp --tag db --tag prod`,
 		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			serverID := args[0]
@@ -104,6 +119,11 @@ Examples:
 				return fmt.Errorf("invalid transport: %s (must be stdio, sse, or http)", transport)
 			}
 
+			tags, err := mcpserver.NormalizeTags(tags)
+			if err != nil {
+				return err
+			}
+
 			// Load existing servers config
 			config, err := loadServersConfig()
 			if err != nil {
@@ -129,6 +149,7 @@ Examples:
 				Args:          commandArgs,
 				Transport:     transport,
 				Env:           env,
+				Tags:          tags,
 				CredentialRef: credentialRef,
 			}
 
@@ -137,6 +158,7 @@ Examples:
 				return fmt.Errorf("failed to save servers config: %w", err)
 			}
 
+			recordAudit(cmd, audit.ServerAdded, serverID, command)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Server '%s' added successfully\n", serverID) // Error ignored: terminal output, failure is non-critical
 			return nil
 		},
@@ -144,6 +166,7 @@ Examples:
 
 	cmd.Flags().StringVar(&transport, "transport", "stdio", "Transport type (stdio|sse|http)")
 	cmd.Flags().StringSliceVar(&envVars, "env", []string{}, "Environment variables (KEY=VALUE)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Tags grouping the server (e.g. prod, db)")
 	cmd.Flags().StringVar(&credentialRef, "credential-ref", "", "Reference to keyring credential")
 	cmd.Flags().StringVar(&name, "name", "", "Friendly name for the server")
 	cmd.Flags().StringVar(&description, "description", "", "Description of the server")
@@ -195,6 +218,7 @@ func newServerListCommand() *cobra.Command {
 						"args":        server.Args,
 						"transport":   transport,
 						"env":         server.Env,
+						"tags":        server.Tags,
 						"status":      "Registered",
 					})
 				}
@@ -208,8 +232,8 @@ func newServerListCommand() *cobra.Command {
 
 			// Create table writer for human-readable output
 			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-			_, _ = fmt.Fprintln(w, "ID\tNAME\tCOMMAND\tTRANSPORT\tSTATUS") // Error ignored: terminal output, failure is non-critical
-			_, _ = fmt.Fprintln(w, "──\t────\t───────\t─────────\t──────") // Error ignored: terminal output, failure is non-critical
+			_, _ = fmt.Fprintln(w, "ID\tNAME\tCOMMAND\tTRANSPORT\tTAGS\tSTATUS") // Error ignored: terminal output, failure is non-critical
+			_, _ = fmt.Fprintln(w, "──\t────\t───────\t─────────\t────\t──────") // Error ignored: terminal output, failure is non-critical
 
 			for _, server := range config.Servers {
 				// TODO: Test connection to determine actual status
@@ -234,8 +258,13 @@ func newServerListCommand() *cobra.Command {
 					}
 				}
 
-				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", // Error ignored: terminal output, failure is non-critical
-					server.ID, name, cmdDisplay, transport, status)
+				tags := strings.Join(server.Tags, ",")
+				if tags == "" {
+					tags = "-"
+				}
+
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", // Error ignored: terminal output, failure is non-critical
+					server.ID, name, cmdDisplay, transport, tags, status)
 			}
 
 			_ = w.Flush() // Error ignored: terminal output, failure is non-critical
@@ -318,6 +347,75 @@ func newServerTestCommand() *cobra.Command {
 	return cmd
 }
 
+// newServerProxyCommand creates the server proxy subcommand
+func newServerProxyCommand() *cobra.Command {
+	var (
+		recordPath string
+		replayPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "proxy (--record <file> -- <command> [args...] | --replay <file>)",
+		Short: "Record or replay MCP server traffic",
+		Long: `Run as a stdio MCP server that sits between GoFlow and a real server.
+
+With --record, the real server is started and all JSON-RPC traffic in both
+directions is relayed unchanged and appended to the recording file.
+
+With --replay, no server is started: requests are answered from a recording,
+matching each request by method and parameters. Replays are deterministic,
+which makes recordings of third-party servers usable in regression tests.
+
+Use the proxy as the command of a registered server or workflow server.
+
+Examples:
+  # Record traffic to a filesystem server
+  goflow server add fs-recorded goflow server proxy --record fs.jsonl -- \
+    npx -y @modelcontextprotocol/server-filesystem /tmp
+
+  # Replay it later without the real server
+  goflow server add fs-replay goflow server proxy --replay fs.jsonl`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case recordPath != "" && replayPath != "":
+				return fmt.Errorf("--record and --replay are mutually exclusive")
+			case recordPath != "":
+				if len(args) == 0 {
+					return fmt.Errorf("--record requires the server command after --")
+				}
+				recording, err := os.OpenFile(recordPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+				if err != nil {
+					return fmt.Errorf("failed to open recording: %w", err)
+				}
+				defer func() { _ = recording.Close() }()
+
+				config := mcp.ServerConfig{Command: args[0], Args: args[1:]}
+				return mcp.RecordServer(context.Background(), config, cmd.InOrStdin(), cmd.OutOrStdout(), recording)
+			case replayPath != "":
+				if len(args) > 0 {
+					return fmt.Errorf("--replay does not start a server; unexpected arguments: %s", strings.Join(args, " "))
+				}
+				messages, err := mcp.LoadRecordingFile(replayPath)
+				if err != nil {
+					return err
+				}
+				replayer, err := mcp.NewReplayer(messages)
+				if err != nil {
+					return err
+				}
+				return replayer.Serve(cmd.InOrStdin(), cmd.OutOrStdout())
+			default:
+				return fmt.Errorf("one of --record or --replay is required")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&recordPath, "record", "", "Record traffic to this file (appends)")
+	cmd.Flags().StringVar(&replayPath, "replay", "", "Replay responses from this recording")
+
+	return cmd
+}
+
 // newServerRemoveCommand creates the server remove subcommand
 func newServerRemoveCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -347,6 +445,7 @@ func newServerRemoveCommand() *cobra.Command {
 				return fmt.Errorf("failed to save servers config: %w", err)
 			}
 
+			recordAudit(cmd, audit.ServerRemoved, serverID, "")
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Server '%s' removed successfully\n", serverID) // Error ignored: terminal output, failure is non-critical
 			return nil
 		},
@@ -355,11 +454,95 @@ func newServerRemoveCommand() *cobra.Command {
 	return cmd
 }
 
+// newServerImportCommand creates the server import subcommand
+func newServerImportCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import [config-file]",
+		Short: "Import MCP servers from a Claude Desktop config",
+		Long: `Register the servers of a standard mcpServers JSON config, the format used
+by Claude Desktop and other MCP clients. Without a file, Claude Desktop's
+config for this platform is read. Servers whose ID is already registered
+are left unchanged.
+
+Examples:
+  # Import from Claude Desktop
+  goflow server import
+
+  # Import from another client's config, listing what would be added
+  goflow server import ~/.cursor/mcp.json --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := mcpserver.ClaudeDesktopConfigPath()
+			if len(args) > 0 {
+				configPath = args[0]
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to read MCP config: %w", err)
+			}
+			servers, skipped, err := mcpserver.ImportMCPConfig(data)
+			if err != nil {
+				return err
+			}
+			for _, reason := range skipped {
+				_, _ = fmt.Fprintf(cmd.OutOrStderr(), "- Skipped %v\n", reason) // Error ignored: terminal output, failure is non-critical
+			}
+
+			var repo mcpserver.ServerRepository
+			if dryRun {
+				// List against a copy of the registered servers
+				config, err := loadServersConfig()
+				if err != nil {
+					return fmt.Errorf("failed to load servers config: %w", err)
+				}
+				registry := mcpserver.NewRegistry()
+				for id := range config.Servers {
+					_ = registry.Register(&mcpserver.MCPServer{ID: id})
+				}
+				repo = registry
+			} else {
+				repo, err = mcpserver.NewFileRepository(GetServersConfigPath())
+				if err != nil {
+					return fmt.Errorf("failed to load servers config: %w", err)
+				}
+			}
+
+			imported, existing, err := mcpserver.ImportServers(repo, servers)
+			for _, id := range existing {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "- Server '%s' already exists\n", id) // Error ignored: terminal output, failure is non-critical
+			}
+			verb := "Imported"
+			if dryRun {
+				verb = "Would import"
+			}
+			for _, id := range imported {
+				if !dryRun {
+					recordAudit(cmd, audit.ServerAdded, id, "imported from "+configPath)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ %s server '%s'\n", verb, id) // Error ignored: terminal output, failure is non-critical
+			}
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %d server(s) from %s\n", verb, len(imported), configPath) // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the servers that would be imported without saving them")
+
+	return cmd
+}
+
 // newServerUpdateCommand creates the server update subcommand
 func newServerUpdateCommand() *cobra.Command {
 	var (
 		description string
 		name        string
+		tags        []string
 	)
 
 	cmd := &cobra.Command{
@@ -389,12 +572,20 @@ func newServerUpdateCommand() *cobra.Command {
 			if cmd.Flags().Changed("name") {
 				server.Name = name
 			}
+			if cmd.Flags().Changed("tag") {
+				normalized, err := mcpserver.NormalizeTags(tags)
+				if err != nil {
+					return err
+				}
+				server.Tags = normalized
+			}
 
 			// Save config
 			if err := saveServersConfig(config); err != nil {
 				return fmt.Errorf("failed to save servers config: %w", err)
 			}
 
+			recordAudit(cmd, audit.ServerChanged, serverID, "")
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Server '%s' updated successfully\n", serverID) // Error ignored: terminal output, failure is non-critical
 			return nil
 		},
@@ -402,6 +593,7 @@ func newServerUpdateCommand() *cobra.Command {
 
 	cmd.Flags().StringVar(&description, "description", "", "Update server description")
 	cmd.Flags().StringVar(&name, "name", "", "Update server name")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Replace the server's tags (--tag \"\" clears them)")
 
 	return cmd
 }
@@ -451,6 +643,10 @@ func newServerShowCommand() *cobra.Command {
 			}
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Transport: %s\n", transport) // Error ignored: terminal output, failure is non-critical
 
+			if len(server.Tags) > 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Tags: %s\n", strings.Join(server.Tags, ", ")) // Error ignored: terminal output, failure is non-critical
+			}
+
 			if len(server.Env) > 0 {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Environment Variables:") // Error ignored: terminal output, failure is non-critical
 				for key, value := range server.Env {
@@ -513,7 +709,7 @@ func saveServersConfig(config *ServerConfig) error {
 
 	// Write atomically using temp file + rename
 	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
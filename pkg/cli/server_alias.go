@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// resolveServerAliases points each server alias of the workflow (a server
+// declared by tags only) at the registered server carrying those tags.
+// scope adds tags every alias must also carry, such as the environment to
+// run against ("prod"). Nodes keep referring to the alias ID.
+func resolveServerAliases(wf *workflow.Workflow, scope []string) error {
+	var repo mcpserver.ServerRepository
+	for _, config := range wf.ServerConfigs {
+		if config == nil || !config.IsAlias() {
+			continue
+		}
+		if repo == nil {
+			fileRepo, err := mcpserver.NewFileRepository(GetServersConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load servers config: %w", err)
+			}
+			repo = fileRepo
+		}
+
+		tags := append(append([]string(nil), config.Tags...), scope...)
+		server, err := mcpserver.ResolveAlias(repo, config.ID, tags)
+		if err != nil {
+			return err
+		}
+		applyResolvedServer(config, server)
+	}
+	return nil
+}
+
+// applyEnvironment points the workflow's servers that the named environment
+// maps at the registered servers it maps them to. An empty name selects the
+// default environment, if one is set. It returns the environment applied,
+// or "" for none. Nodes keep referring to the workflow's server IDs.
+func applyEnvironment(wf *workflow.Workflow, name string) (string, error) {
+	envs, err := mcpserver.LoadEnvironments(GetEnvironmentsPath())
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = envs.Default
+	}
+	if name == "" {
+		return "", nil
+	}
+	env, err := envs.Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	var repo mcpserver.ServerRepository
+	for _, config := range wf.ServerConfigs {
+		if config == nil {
+			continue
+		}
+		if _, mapped := env.Servers[config.ID]; !mapped {
+			continue
+		}
+		if repo == nil {
+			fileRepo, err := mcpserver.NewFileRepository(GetServersConfigPath())
+			if err != nil {
+				return "", fmt.Errorf("failed to load servers config: %w", err)
+			}
+			repo = fileRepo
+		}
+		server, err := env.Resolve(repo, config.ID)
+		if err != nil {
+			return "", fmt.Errorf("environment %s: %w", name, err)
+		}
+		applyResolvedServer(config, server)
+	}
+	return name, nil
+}
+
+// applyRegisteredToolCategories gives each workflow server the tool
+// categories the operator declared for it in servers.yaml: those of the
+// registered server with the same ID and command and arguments (or URL),
+// or, for an alias, of the one registered server its tags select. Other
+// servers get none, so sandbox profiles denying a category do not let
+// their tools run. Environments and aliases resolved later replace them
+// with those of the registered server they point at.
+func applyRegisteredToolCategories(wf *workflow.Workflow, repo mcpserver.ServerRepository) {
+	for _, config := range wf.ServerConfigs {
+		if config == nil {
+			continue
+		}
+		config.ToolCategories = nil
+		var server *mcpserver.MCPServer
+		if config.IsAlias() {
+			server, _ = mcpserver.ResolveAlias(repo, config.ID, config.Tags)
+		} else if registered, err := repo.Get(config.ID); err == nil && sameConnection(config, registered) {
+			server = registered
+		}
+		if server != nil {
+			config.ToolCategories = registeredToolCategories(server)
+		}
+	}
+}
+
+// sameConnection reports whether a workflow server config runs the same
+// command and arguments, or reaches the same URL, as a registered server
+func sameConnection(config *workflow.ServerConfig, server *mcpserver.MCPServer) bool {
+	switch transport := server.Transport.(type) {
+	case *mcpserver.StdioTransportConfig:
+		return config.GetTransport() == string(mcpserver.TransportStdio) &&
+			config.Command == server.Command && slices.Equal(config.Args, server.Args)
+	case *mcpserver.SSETransportConfig:
+		return config.GetTransport() == string(mcpserver.TransportSSE) && config.URL == transport.URL
+	case *mcpserver.HTTPTransportConfig:
+		return config.GetTransport() == string(mcpserver.TransportHTTP) && config.URL == transport.BaseURL
+	}
+	return false
+}
+
+// registeredToolCategories converts the tool categories of a registered
+// server for sandbox checks
+func registeredToolCategories(server *mcpserver.MCPServer) map[string][]workflow.ToolCategory {
+	if len(server.ToolCategories) == 0 {
+		return nil
+	}
+	declared := make(map[string][]workflow.ToolCategory, len(server.ToolCategories))
+	for tool, names := range server.ToolCategories {
+		categories := make([]workflow.ToolCategory, len(names))
+		for i, name := range names {
+			categories[i] = workflow.ToolCategory(name)
+		}
+		declared[tool] = categories
+	}
+	return declared
+}
+
+// applyResolvedServer replaces a server config's connection settings and
+// tool categories with those of the registered server it resolved to.
+// Environment variables set on the config take precedence.
+func applyResolvedServer(config *workflow.ServerConfig, server *mcpserver.MCPServer) {
+	if config.Name == "" {
+		config.Name = server.Name
+	}
+	config.ToolCategories = registeredToolCategories(server)
+	config.Command = ""
+	config.Args = nil
+	config.URL = ""
+	config.Headers = nil
+	switch transport := server.Transport.(type) {
+	case *mcpserver.StdioTransportConfig:
+		config.Transport = string(mcpserver.TransportStdio)
+		config.Command = server.Command
+		config.Args = append([]string(nil), server.Args...)
+		env := make(map[string]string, len(transport.Env)+len(config.Env))
+		for key, value := range transport.Env {
+			env[key] = value
+		}
+		for key, value := range config.Env {
+			env[key] = value
+		}
+		if len(env) > 0 {
+			config.Env = env
+		}
+	case *mcpserver.SSETransportConfig:
+		config.Transport = string(mcpserver.TransportSSE)
+		config.URL = transport.URL
+		config.Headers = transport.Headers
+	case *mcpserver.HTTPTransportConfig:
+		config.Transport = string(mcpserver.TransportHTTP)
+		config.URL = transport.BaseURL
+		config.Headers = transport.Headers
+	}
+}
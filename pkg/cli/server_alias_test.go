@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+func TestResolveServerAliases(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOFLOW_CONFIG_DIR", dir)
+	servers := `servers:
+  db-dev:
+    id: db-dev
+    command: postgres-mcp
+    args: [--dsn, dev]
+    tags: [db, dev]
+  db-prod:
+    id: db-prod
+    command: postgres-mcp
+    args: [--dsn, prod]
+    tags: [db, prod]
+  search:
+    id: search
+    command: https://search.example.com/mcp
+    transport: http
+    tags: [web]
+`
+	if err := os.WriteFile(filepath.Join(dir, "servers.yaml"), []byte(servers), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	newWorkflow := func() *workflow.Workflow {
+		wf, err := workflow.NewWorkflow("aliases", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		wf.ServerConfigs = []*workflow.ServerConfig{
+			{ID: "db", Tags: []string{"db"}},
+			{ID: "web", Tags: []string{"web"}},
+			{ID: "fs", Command: "fs-server"},
+		}
+		return wf
+	}
+
+	wf := newWorkflow()
+	if err := resolveServerAliases(wf, []string{"prod"}); err == nil || !strings.Contains(err.Error(), "web") {
+		t.Errorf("expected the prod scope to leave the web alias unresolved, got %v", err)
+	}
+
+	wf = newWorkflow()
+	wf.ServerConfigs = wf.ServerConfigs[:1]
+	if err := resolveServerAliases(wf, []string{"prod"}); err != nil {
+		t.Fatalf("resolveServerAliases failed: %v", err)
+	}
+	db := wf.ServerConfigs[0]
+	if db.ID != "db" || db.Command != "postgres-mcp" || strings.Join(db.Args, " ") != "--dsn prod" {
+		t.Errorf("db alias resolved to %+v", db)
+	}
+	if err := db.Validate(); err != nil {
+		t.Errorf("resolved alias is not a valid server: %v", err)
+	}
+
+	wf = newWorkflow()
+	if err := resolveServerAliases(wf, nil); err == nil || !strings.Contains(err.Error(), "db-dev, db-prod") {
+		t.Errorf("expected an ambiguous db alias, got %v", err)
+	}
+
+	wf = newWorkflow()
+	wf.ServerConfigs = wf.ServerConfigs[1:]
+	if err := resolveServerAliases(wf, nil); err != nil {
+		t.Fatalf("resolveServerAliases failed: %v", err)
+	}
+	if web := wf.ServerConfigs[0]; web.Transport != "http" || web.URL != "https://search.example.com/mcp" {
+		t.Errorf("web alias resolved to %+v", web)
+	}
+	if fs := wf.ServerConfigs[1]; fs.Command != "fs-server" {
+		t.Errorf("concrete servers are left alone, got %+v", fs)
+	}
+}
+
+func TestApplyEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOFLOW_CONFIG_DIR", dir)
+	servers := `servers:
+  db-staging:
+    id: db-staging
+    command: postgres-mcp
+    args: [--dsn, staging]
+  search-staging:
+    id: search-staging
+    command: https://staging.example.com/sse
+    transport: sse
+`
+	environments := `default: staging
+environments:
+  staging:
+    servers:
+      db: db-staging
+      search: search-staging
+  broken:
+    servers:
+      db: db-missing
+`
+	if err := os.WriteFile(filepath.Join(dir, "servers.yaml"), []byte(servers), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "environments.yaml"), []byte(environments), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	newWorkflow := func() *workflow.Workflow {
+		wf, err := workflow.NewWorkflow("environments", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		wf.ServerConfigs = []*workflow.ServerConfig{
+			{ID: "db", Command: "postgres-mcp", Args: []string{"--dsn", "local"}},
+			{ID: "search", Tags: []string{"search"}},
+			{ID: "fs", Command: "fs-server"},
+		}
+		return wf
+	}
+
+	wf := newWorkflow()
+	applied, err := applyEnvironment(wf, "")
+	if err != nil {
+		t.Fatalf("applyEnvironment failed: %v", err)
+	}
+	if applied != "staging" {
+		t.Errorf("expected the default environment, got %q", applied)
+	}
+	if db := wf.ServerConfigs[0]; strings.Join(db.Args, " ") != "--dsn staging" {
+		t.Errorf("db resolved to %+v", db)
+	}
+	search := wf.ServerConfigs[1]
+	if search.Transport != "sse" || search.URL != "https://staging.example.com/sse" || search.Command != "" || search.IsAlias() {
+		t.Errorf("search resolved to %+v", search)
+	}
+	if err := search.Validate(); err != nil {
+		t.Errorf("resolved server is not valid: %v", err)
+	}
+	if fs := wf.ServerConfigs[2]; fs.Command != "fs-server" {
+		t.Errorf("unmapped servers are left alone, got %+v", fs)
+	}
+
+	if _, err := applyEnvironment(newWorkflow(), "broken"); err == nil || !strings.Contains(err.Error(), "db-missing") {
+		t.Errorf("expected a missing server error, got %v", err)
+	}
+	if _, err := applyEnvironment(newWorkflow(), "prod"); err == nil {
+		t.Error("expected an unknown environment to fail")
+	}
+}
+
+func TestApplyRegisteredToolCategories(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOFLOW_CONFIG_DIR", dir)
+	servers := `servers:
+  fs:
+    id: fs
+    command: fs-server
+    args: [--root, /data]
+    tool_categories:
+      read_file: [read]
+      "*": [write]
+  db-prod:
+    id: db-prod
+    command: postgres-mcp
+    tags: [db, prod]
+    tool_categories:
+      "*": [read]
+  shell:
+    id: shell
+    command: shell-mcp
+    tags: [shell]
+    tool_categories:
+      "*": [exec]
+`
+	if err := os.WriteFile(filepath.Join(dir, "servers.yaml"), []byte(servers), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "environments.yaml"), []byte("environments:\n  ops:\n    servers:\n      db: shell\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := mcpserver.NewFileRepository(filepath.Join(dir, "servers.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readOnly := map[string][]workflow.ToolCategory{"*": {workflow.ToolCategoryRead}}
+	wf, err := workflow.NewWorkflow("categories", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wf.ServerConfigs = []*workflow.ServerConfig{
+		{ID: "fs", Command: "fs-server", Args: []string{"--root", "/data"}, ToolCategories: readOnly},
+		{ID: "db", Tags: []string{"db"}, ToolCategories: readOnly},
+		{ID: "shell", Command: "bash", Args: []string{"-c", "shell-mcp"}, ToolCategories: readOnly},
+		{ID: "local", Command: "local-mcp", ToolCategories: readOnly},
+	}
+	applyRegisteredToolCategories(wf, repo)
+
+	if got := wf.ServerConfigs[0].ToolCategories; len(got) != 2 || got["*"][0] != workflow.ToolCategoryWrite {
+		t.Errorf("fs categories = %v, want those in servers.yaml", got)
+	}
+	if got := wf.ServerConfigs[1].ToolCategories; len(got) != 1 || got["*"][0] != workflow.ToolCategoryRead {
+		t.Errorf("db alias categories = %v, want those of db-prod", got)
+	}
+	for _, config := range wf.ServerConfigs[2:] {
+		if config.ToolCategories != nil {
+			t.Errorf("%s keeps the workflow's own categories %v", config.ID, config.ToolCategories)
+		}
+	}
+
+	// An environment swapping in another registered server brings its
+	// categories along
+	if _, err := applyEnvironment(wf, "ops"); err != nil {
+		t.Fatalf("applyEnvironment failed: %v", err)
+	}
+	if got := wf.ServerConfigs[1].ToolCategories; len(got) != 1 || got["*"][0] != workflow.ToolCategoryExec {
+		t.Errorf("db categories after the environment = %v, want those of shell", got)
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Exit codes returned by the goflow binary. Signal exits follow the shell
+// convention of 128 + signal number.
+const (
+	ExitOK         = 0   // Command succeeded
+	ExitError      = 1   // Command or workflow failed
+	ExitHangup     = 129 // Interrupted by SIGHUP (terminal closed)
+	ExitInterrupt  = 130 // Interrupted by SIGINT (Ctrl+C)
+	ExitTerminated = 143 // Interrupted by SIGTERM
+)
+
+// shutdownGracePeriod bounds how long a command waits for a cancelled
+// execution to record its final state before giving up on it
+const shutdownGracePeriod = 5 * time.Second
+
+// shutdownSignals are the signals that cancel a running command
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// InterruptedError reports that a command stopped because of a signal
+type InterruptedError struct {
+	Signal os.Signal
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("interrupted by %s", signalName(e.Signal))
+}
+
+// ExitCode returns the process exit code for an error returned by a command
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var interrupted *InterruptedError
+	if errors.As(err, &interrupted) {
+		switch interrupted.Signal {
+		case syscall.SIGHUP:
+			return ExitHangup
+		case syscall.SIGTERM:
+			return ExitTerminated
+		default:
+			return ExitInterrupt
+		}
+	}
+	return ExitError
+}
+
+// shutdown cancels a context when the process receives SIGINT, SIGTERM or
+// SIGHUP and remembers which signal it was
+type shutdown struct {
+	mu     sync.Mutex
+	signal os.Signal
+	stop   func()
+}
+
+// notifyShutdown returns a context that is cancelled on the first shutdown
+// signal. Call stop to release the signal handler.
+func notifyShutdown(parent context.Context) (context.Context, *shutdown) {
+	ctx, cancel := context.WithCancelCause(parent)
+	s := &shutdown{}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, shutdownSignals...)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-sigCh:
+			s.mu.Lock()
+			s.signal = sig
+			s.mu.Unlock()
+			// The cause becomes the execution's cancellation reason
+			cancel(fmt.Errorf("received %s", sig))
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	s.stop = func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+			cancel(nil)
+		})
+	}
+	return ctx, s
+}
+
+// Stop releases the signal handler and cancels the context
+func (s *shutdown) Stop() {
+	s.stop()
+}
+
+// Signal returns the signal that cancelled the context, or nil
+func (s *shutdown) Signal() os.Signal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.signal
+}
+
+// Err returns an InterruptedError if a signal was received, otherwise err
+func (s *shutdown) Err(err error) error {
+	if sig := s.Signal(); sig != nil {
+		return &InterruptedError{Signal: sig}
+	}
+	return err
+}
+
+// waitForShutdown waits up to shutdownGracePeriod for done to close, so a
+// cancelled execution can record its final state before resources are
+// released. Returns false if the grace period expired.
+func waitForShutdown(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	case <-time.After(shutdownGracePeriod):
+		return false
+	}
+}
+
+// signalName returns a conventional name for a signal
+func signalName(sig os.Signal) string {
+	switch sig {
+	case syscall.SIGHUP:
+		return "SIGHUP"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	case os.Interrupt:
+		return "SIGINT"
+	case nil:
+		return "signal"
+	default:
+		return sig.String()
+	}
+}
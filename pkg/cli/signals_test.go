@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"failure", errors.New("workflow failed"), ExitError},
+		{"sigint", &InterruptedError{Signal: os.Interrupt}, ExitInterrupt},
+		{"sigterm", &InterruptedError{Signal: syscall.SIGTERM}, ExitTerminated},
+		{"sighup", &InterruptedError{Signal: syscall.SIGHUP}, ExitHangup},
+		{"wrapped", fmt.Errorf("run: %w", &InterruptedError{Signal: syscall.SIGTERM}), ExitTerminated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyShutdown(t *testing.T) {
+	ctx, sd := notifyShutdown(context.Background())
+	defer sd.Stop()
+
+	if sd.Signal() != nil || sd.Err(nil) != nil {
+		t.Fatal("no signal should be recorded before one arrives")
+	}
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("FindProcess() error = %v", err)
+	}
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Skipf("cannot signal own process: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by SIGHUP")
+	}
+
+	if sd.Signal() != syscall.SIGHUP {
+		t.Errorf("Signal() = %v, want SIGHUP", sd.Signal())
+	}
+	if got := ExitCode(sd.Err(errors.New("execution cancelled"))); got != ExitHangup {
+		t.Errorf("ExitCode(Err()) = %d, want %d", got, ExitHangup)
+	}
+}
+
+func TestNotifyShutdown_StopCancels(t *testing.T) {
+	ctx, sd := notifyShutdown(context.Background())
+	sd.Stop()
+	sd.Stop() // Safe to call twice
+
+	if ctx.Err() == nil {
+		t.Error("Stop() should cancel the context")
+	}
+	if sd.Signal() != nil {
+		t.Errorf("Signal() = %v, want nil", sd.Signal())
+	}
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dshills/goflow/pkg/audit"
+	"github.com/dshills/goflow/pkg/rbac"
+	"github.com/spf13/cobra"
+)
+
+// NewTokenCommand creates the token command group
+func NewTokenCommand() *cobra.Command {
+	var authConfig string
+
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage API tokens",
+		Long: `Create, list and revoke the bearer tokens that authenticate callers of the
+approval, metrics and audit APIs (see --auth on goflow run and goflow
+audit).
+
+Tokens are stored in ~/.goflow/auth.yaml by their SHA-256 only; a token is
+shown once, when it is created. Each token has a role (viewer, editor,
+operator or admin), and scopes can narrow it to some of the role's
+permissions: view, edit_workflows, manage_servers, operate_executions and
+view_audit.`,
+	}
+	cmd.PersistentFlags().StringVar(&authConfig, "auth", "", "Auth config file (default: ~/.goflow/auth.yaml)")
+	path := func() string {
+		if authConfig != "" {
+			return authConfig
+		}
+		return GetAuthConfigPath()
+	}
+
+	cmd.AddCommand(newTokenCreateCommand(path))
+	cmd.AddCommand(newTokenListCommand(path))
+	cmd.AddCommand(newTokenRevokeCommand(path))
+
+	return cmd
+}
+
+// newTokenCreateCommand creates the token create subcommand
+func newTokenCreateCommand(path func() string) *cobra.Command {
+	var (
+		role   string
+		scopes []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create an API token",
+		Long: `Create an API token and print it. Store it now: only its hash is kept.
+
+Examples:
+  # A token for a CI job that decides approvals
+  goflow token create release-bot --role operator
+
+  # A dashboard token that may only read
+  goflow token create dashboard --role admin --scope view --scope view_audit`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedRole, err := rbac.ParseRole(role)
+			if err != nil {
+				return err
+			}
+			var permissions []rbac.Permission
+			for _, scope := range scopes {
+				permission, err := rbac.ParsePermission(scope)
+				if err != nil {
+					return err
+				}
+				permissions = append(permissions, permission)
+			}
+
+			config, err := rbac.LoadOrCreateConfig(path())
+			if err != nil {
+				return err
+			}
+			token, err := config.CreateToken(args[0], parsedRole, permissions)
+			if err != nil {
+				return err
+			}
+			if err := config.Save(path()); err != nil {
+				return err
+			}
+			recordAudit(cmd, audit.TokenCreated, args[0], string(parsedRole))
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Token '%s' created with the %s role\n", args[0], parsedRole) // Error ignored: terminal output, failure is non-critical
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n  %s\n\nIt is not shown again.\n", token)                    // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&role, "role", string(rbac.RoleViewer), "Role: viewer, editor, operator or admin")
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, "Narrow the token to this permission of its role, can be used multiple times")
+
+	return cmd
+}
+
+// newTokenListCommand creates the token list subcommand
+func newTokenListCommand(path func() string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List API tokens",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := rbac.LoadOrCreateConfig(path())
+			if err != nil {
+				return err
+			}
+			if len(config.Tokens) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No API tokens.")                                              // Error ignored: terminal output, failure is non-critical
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nCreate one with: goflow token create <name> --role <role>") // Error ignored: terminal output, failure is non-critical
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tROLE\tSCOPES\tCREATED") // Error ignored: terminal output, failure is non-critical
+			for _, token := range config.Tokens {
+				scopes := "-"
+				if len(token.Scopes) > 0 {
+					names := make([]string, len(token.Scopes))
+					for i, scope := range token.Scopes {
+						names[i] = string(scope)
+					}
+					scopes = strings.Join(names, ",")
+				}
+				created := "-"
+				if !token.Created.IsZero() {
+					created = token.Created.Local().Format("2006-01-02 15:04")
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", token.Name, token.Role, scopes, created) // Error ignored: terminal output, failure is non-critical
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// newTokenRevokeCommand creates the token revoke subcommand
+func newTokenRevokeCommand(path func() string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <name>",
+		Short: "Revoke an API token",
+		Long: `Remove an API token, so it no longer authenticates. APIs already serving
+with this auth config refuse the token from their next request.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := rbac.LoadConfig(path())
+			if err != nil {
+				return err
+			}
+			if err := config.RevokeToken(args[0]); err != nil {
+				return err
+			}
+			if err := config.Save(path()); err != nil {
+				return err
+			}
+			recordAudit(cmd, audit.TokenRevoked, args[0], "")
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Token '%s' revoked\n", args[0]) // Error ignored: terminal output, failure is non-critical
+			return nil
+		},
+	}
+}
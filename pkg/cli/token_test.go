@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/audit"
+	"github.com/dshills/goflow/pkg/rbac"
+)
+
+// runCommand runs the root command with args, returning its output. The
+// global config it sets is restored afterwards.
+func runCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	saved := *GlobalConfig
+	t.Cleanup(func() { *GlobalConfig = saved })
+	cmd := NewRootCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestTokenAndAuditCommands(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir())
+
+	out, err := runCommand(t, "token", "create", "release-bot", "--role", "operator", "--scope", "operate_executions")
+	if err != nil {
+		t.Fatalf("token create: %v\n%s", err, out)
+	}
+	if _, err := runCommand(t, "token", "create", "greedy", "--role", "viewer", "--scope", "manage_servers"); err == nil {
+		t.Error("expected a scope beyond the role to be rejected")
+	}
+	config, err := rbac.LoadConfig(GetAuthConfigPath())
+	if err != nil || len(config.Tokens) != 1 {
+		t.Fatalf("auth config = %+v, %v", config, err)
+	}
+	token := ""
+	for _, field := range strings.Fields(out) {
+		if strings.HasPrefix(field, "gft_") {
+			token = field
+		}
+	}
+	if token == "" || rbac.HashToken(token) != config.Tokens[0].SHA256 {
+		t.Fatalf("printed token does not match the stored hash:\n%s", out)
+	}
+
+	out, err = runCommand(t, "token", "list")
+	if err != nil || !strings.Contains(out, "release-bot") || !strings.Contains(out, "operate_executions") || strings.Contains(out, token) {
+		t.Errorf("token list = %v\n%s", err, out)
+	}
+	if _, err := runCommand(t, "token", "revoke", "release-bot"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both changes are in the audit log, newest first
+	out, err = runCommand(t, "audit", "--action", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, created := strings.Index(out, string(audit.TokenRevoked)), strings.Index(out, string(audit.TokenCreated))
+	if revoked < 0 || created < revoked || !strings.Contains(out, audit.LocalActor()) {
+		t.Errorf("audit --action token =\n%s", out)
+	}
+	if out, err := runCommand(t, "audit", "--actor", "nobody"); err != nil || !strings.Contains(out, "No audit entries") {
+		t.Errorf("audit --actor nobody = %v\n%s", err, out)
+	}
+}
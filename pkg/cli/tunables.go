@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dshills/goflow/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// tunablesReloadInterval is how often config.yaml is checked for changes
+const tunablesReloadInterval = 2 * time.Second
+
+// globalConfigFile is the on-disk layout of config.yaml
+type globalConfigFile struct {
+	Version  string          `yaml:"version"`
+	Tunables config.Tunables `yaml:"tunables"`
+}
+
+// GetConfigFilePath returns the path to the global configuration file
+func GetConfigFilePath() string {
+	return filepath.Join(GetConfigDir(), "config.yaml")
+}
+
+// LoadTunables reads tunables from the config file and publishes them to
+// config.Global(). Out-of-range values are clamped and returned as warnings.
+// A missing file leaves the current tunables unchanged.
+func LoadTunables(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Keys missing from the file keep their defaults
+	cfg := globalConfigFile{Tunables: config.DefaultTunables()}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return config.Global().Update(cfg.Tunables), nil
+}
+
+// WatchTunables reloads tunables whenever the config file changes, until ctx
+// is cancelled. Invalid files are logged and the previous tunables are kept.
+func WatchTunables(ctx context.Context, path string) {
+	var lastMod time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(tunablesReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || !info.ModTime().After(lastMod) {
+				continue
+			}
+			lastMod = info.ModTime()
+
+			warnings, err := LoadTunables(path)
+			if err != nil {
+				log.Printf("Keeping previous tunables: %v", err)
+				continue
+			}
+			for _, warning := range warnings {
+				log.Printf("Config warning: %s", warning)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/dshills/goflow/pkg/events"
 	"github.com/spf13/cobra"
 )
 
@@ -54,6 +55,7 @@ Examples:
 
 			// Validate workflow structure
 			if err := wf.Validate(); err != nil {
+				events.Default().Publish(events.ValidationFailed(wf.Name, err.Error()))
 				_, _ = fmt.Fprintln(cmd.OutOrStderr(), "✗ Workflow validation failed")
 				if verbose {
 					_, _ = fmt.Fprintf(cmd.OutOrStderr(), "  Error: %v\n", err)
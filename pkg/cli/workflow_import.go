@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/dshills/goflow/pkg/mcpserver"
@@ -66,29 +67,70 @@ func ImportWorkflow(path string, registry mcpserver.ServerRepository) (*workflow
 		return nil, fmt.Errorf("empty workflow file")
 	}
 
+	return wf, checkImportedWorkflow(wf, registry)
+}
+
+// ImportForeignWorkflow converts a workflow exported from another engine
+// (GitHub Actions or n8n) and checks it like ImportWorkflow. An empty format
+// or "auto" detects the format from the file. Constructs that did not convert
+// faithfully are returned as warnings alongside the workflow.
+//
+// The converted workflow's import servers (shell, http) take their command
+// from the registry when a server with the same ID is registered.
+func ImportForeignWorkflow(path string, format workflow.ForeignFormat, registry mcpserver.ServerRepository) (*workflow.Workflow, []workflow.ImportWarning, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read workflow file: %w", err)
+	}
+
+	if format == "" || format == "auto" {
+		format, err = workflow.DetectForeignFormat(data)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	wf, warnings, err := workflow.ImportForeign(data, format)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert %s workflow: %w", format, err)
+	}
+
+	for _, sc := range wf.ServerConfigs {
+		if server, err := registry.Get(sc.ID); err == nil && server.Command != "" {
+			sc.Command = server.Command
+			sc.Args = server.Args
+		}
+	}
+
+	return wf, warnings, checkImportedWorkflow(wf, registry)
+}
+
+// checkImportedWorkflow runs the import checks on a loaded workflow:
+// version, server references, credential placeholders, then structure
+func checkImportedWorkflow(wf *workflow.Workflow, registry mcpserver.ServerRepository) error {
 	// Validate workflow version
 	if err := validateWorkflowVersion(wf.Version); err != nil {
-		return wf, err
+		return err
 	}
 
 	// Validate server references against registry
 	if err := validateServerReferences(wf, registry); err != nil {
-		return wf, err
+		return err
 	}
 
 	// Check for credential placeholders
 	if err := checkCredentialPlaceholders(wf); err != nil {
 		// This is a warning, not a hard error
 		// Return workflow with warning
-		return wf, err
+		return err
 	}
 
 	// Validate workflow structure
 	if err := wf.Validate(); err != nil {
-		return wf, fmt.Errorf("workflow validation failed: %w", err)
+		return fmt.Errorf("workflow validation failed: %w", err)
 	}
 
-	return wf, nil
+	return nil
 }
 
 // validateWorkflowVersion checks if the workflow version is supported
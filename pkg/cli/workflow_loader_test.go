@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// legacyWorkflow predates schema versioning and uses the end node's old
+// return_value field
+const legacyWorkflow = `version: "1.0.0"
+name: "legacy"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+    return_value: "done"
+edges:
+  - from: "start"
+    to: "end"
+`
+
+func TestLoadWorkflowFromFile_LeavesFileUntouched(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "legacy.yaml")
+	if err := os.WriteFile(path, []byte(legacyWorkflow), 0644); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory that can't be written still loads
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+
+	wf, err := LoadWorkflowFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadWorkflowFromFile() error = %v", err)
+	}
+	if end := wf.Nodes[1].(*workflow.EndNode); end.ReturnValue != "done" {
+		t.Errorf("end node return = %q, want the migrated return_value", end.ReturnValue)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != legacyWorkflow {
+		t.Errorf("file = %q, %v, want it unchanged", data, err)
+	}
+	after, err := os.Stat(path)
+	if err != nil || !os.SameFile(before, after) || !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("file was rewritten: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("directory has %d entries, %v, want only the workflow", len(entries), err)
+	}
+}
+
+func TestUpgradeWorkflowFile(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir())
+	if err := os.MkdirAll(GetWorkflowsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	inRepo := filepath.Join(GetWorkflowsDir(), "legacy.yaml")
+	outside := filepath.Join(t.TempDir(), "legacy.yaml")
+	for _, path := range []string{inRepo, outside} {
+		if err := os.WriteFile(path, []byte(legacyWorkflow), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Files outside the repository are never rewritten
+	var out bytes.Buffer
+	upgradeWorkflowFile(&out, outside)
+	if data, _ := os.ReadFile(outside); string(data) != legacyWorkflow || out.Len() != 0 {
+		t.Errorf("file outside the repository was upgraded: %q", out.String())
+	}
+
+	upgradeWorkflowFile(&out, inRepo)
+	if !strings.Contains(out.String(), "Upgraded") {
+		t.Errorf("output = %q, want the upgrade reported", out.String())
+	}
+	if backup, err := os.ReadFile(inRepo + ".bak"); err != nil || string(backup) != legacyWorkflow {
+		t.Errorf("backup = %q, %v, want the original", backup, err)
+	}
+	data, err := os.ReadFile(inRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := workflow.SchemaVersion(data); err != nil || version != workflow.CurrentSchemaVersion {
+		t.Errorf("SchemaVersion() = %d, %v, want the current version", version, err)
+	}
+
+	// A read-only workflow only gets a warning
+	shared := filepath.Join(GetWorkflowsDir(), "shared.yaml")
+	original := "metadata:\n  read_only: true\n" + legacyWorkflow
+	if err := os.WriteFile(shared, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	upgradeWorkflowFile(&out, shared)
+	if !strings.Contains(out.String(), "Warning") {
+		t.Errorf("output = %q, want a warning", out.String())
+	}
+	if data, _ := os.ReadFile(shared); string(data) != original {
+		t.Errorf("read-only workflow was rewritten")
+	}
+	if _, err := LoadWorkflowFromFile(shared); err != nil {
+		t.Errorf("LoadWorkflowFromFile() of read-only workflow = %v", err)
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir())
+	if err := os.MkdirAll(GetWorkflowsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(GetWorkflowsDir(), "legacy.yaml")
+	if err := os.WriteFile(path, []byte(legacyWorkflow), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewMigrateCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("migrate error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Upgraded") {
+		t.Errorf("output = %q, want the upgrade reported", out.String())
+	}
+	if _, err := os.Stat(path + ".bak"); err != nil {
+		t.Errorf("no backup: %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+// Package config holds runtime tunables shared across GoFlow subsystems.
+//
+// Tunables are loaded from the user's config.yaml, clamped to sane bounds,
+// and published through a Store so running components (TUI, health monitor,
+// execution monitor) can apply changes without a restart.
+package config
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Tunables are user-adjustable timings, buffer sizes and TUI options.
+// Zero values mean "use the default" except where noted.
+type Tunables struct {
+	// ValidationDebounceMs delays TUI workflow validation until edits pause.
+	// 0 validates immediately after every change.
+	ValidationDebounceMs int `yaml:"validation_debounce_ms" json:"validation_debounce_ms"`
+
+	// AutosaveIntervalSec saves modified workflows in the TUI periodically.
+	// 0 disables autosave.
+	AutosaveIntervalSec int `yaml:"autosave_interval_sec" json:"autosave_interval_sec"`
+
+	// HealthCheckIntervalSec is how often MCP server health is checked.
+	HealthCheckIntervalSec int `yaml:"health_check_interval_sec" json:"health_check_interval_sec"`
+
+	// EventQueueSize is the buffer size of execution event subscriptions.
+	EventQueueSize int `yaml:"event_queue_size" json:"event_queue_size"`
+
+	// InputQueueSize is the buffer size of the TUI keyboard input queue.
+	// Applied when the TUI starts.
+	InputQueueSize int `yaml:"input_queue_size" json:"input_queue_size"`
+
+	// SystemClipboard also copies nodes yanked in the TUI to the system
+	// clipboard, as YAML. Off by default.
+	SystemClipboard bool `yaml:"system_clipboard" json:"system_clipboard"`
+
+	// UndoDepth is how many undo steps the TUI workflow builder keeps.
+	UndoDepth int `yaml:"undo_depth" json:"undo_depth"`
+
+	// UndoMemoryMB caps the estimated memory used by the undo history;
+	// the oldest steps are dropped first.
+	UndoMemoryMB int `yaml:"undo_memory_mb" json:"undo_memory_mb"`
+
+	// PersistUndo saves the undo history of each workflow when it is saved
+	// and restores it when the unchanged file is opened again. Off by default.
+	PersistUndo bool `yaml:"persist_undo" json:"persist_undo"`
+
+	// GitWorkflows commits each workflow saved in the TUI builder to git,
+	// initializing a repository in the workflow's directory if it is not in
+	// one, and enables browsing its history. Off by default.
+	GitWorkflows bool `yaml:"git_workflows" json:"git_workflows"`
+
+	// Theme names the TUI color theme: a built-in theme (dark, light,
+	// high-contrast) or a theme file in ~/.goflow/themes. Empty uses dark.
+	Theme string `yaml:"theme" json:"theme"`
+
+	// Role is what the user may do in the TUI builder: editor, the default,
+	// or viewer, which opens every workflow read-only.
+	Role string `yaml:"role" json:"role"`
+
+	// Execution guardrails: a run that exceeds one is aborted with a
+	// guardrail error. 0 disables each of them.
+	//
+	// MaxVariablesMB caps the size of all variables together, as JSON.
+	MaxVariablesMB int `yaml:"max_variables_mb" json:"max_variables_mb"`
+	// MaxPayloadKB caps the size of a single node's inputs or outputs.
+	MaxPayloadKB int `yaml:"max_payload_kb" json:"max_payload_kb"`
+	// MaxNodeExecutions caps the node executions of a run, loop iterations
+	// included.
+	MaxNodeExecutions int `yaml:"max_node_executions" json:"max_node_executions"`
+	// MaxExecutionSec caps the wall-clock time of a run.
+	MaxExecutionSec int `yaml:"max_execution_sec" json:"max_execution_sec"`
+
+	// SpillVariableKB moves variables larger than this, as JSON, to temp
+	// files during execution. 0 keeps every variable in memory.
+	SpillVariableKB int `yaml:"spill_variable_kb" json:"spill_variable_kb"`
+}
+
+// Default values
+const (
+	DefaultValidationDebounceMs   = 250
+	DefaultAutosaveIntervalSec    = 0
+	DefaultHealthCheckIntervalSec = 30
+	DefaultEventQueueSize         = 200
+	DefaultInputQueueSize         = 100
+	DefaultUndoDepth              = 100
+	DefaultUndoMemoryMB           = 64
+)
+
+// Roles a user can have
+const (
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Bounds for each tunable
+const (
+	MaxValidationDebounceMs   = 5000
+	MinAutosaveIntervalSec    = 5
+	MaxAutosaveIntervalSec    = 3600
+	MinHealthCheckIntervalSec = 5
+	MaxHealthCheckIntervalSec = 3600
+	MinEventQueueSize         = 16
+	MaxEventQueueSize         = 100000
+	MinInputQueueSize         = 16
+	MaxInputQueueSize         = 10000
+	MinUndoDepth              = 1
+	MaxUndoDepth              = 10000
+	MinUndoMemoryMB           = 1
+	MaxUndoMemoryMB           = 4096
+)
+
+// DefaultTunables returns the built-in tunables
+func DefaultTunables() Tunables {
+	return Tunables{
+		ValidationDebounceMs:   DefaultValidationDebounceMs,
+		AutosaveIntervalSec:    DefaultAutosaveIntervalSec,
+		HealthCheckIntervalSec: DefaultHealthCheckIntervalSec,
+		EventQueueSize:         DefaultEventQueueSize,
+		InputQueueSize:         DefaultInputQueueSize,
+		UndoDepth:              DefaultUndoDepth,
+		UndoMemoryMB:           DefaultUndoMemoryMB,
+	}
+}
+
+// Normalize returns a copy with defaults filled in and out-of-range values
+// clamped to their bounds. Each adjustment is reported as a warning so the
+// user can fix their config file.
+func (t Tunables) Normalize() (Tunables, []string) {
+	var warnings []string
+
+	clamp := func(name string, value *int, min, max int) {
+		switch {
+		case *value < min:
+			warnings = append(warnings, fmt.Sprintf("%s %d is below minimum %d, using %d", name, *value, min, min))
+			*value = min
+		case *value > max:
+			warnings = append(warnings, fmt.Sprintf("%s %d exceeds maximum %d, using %d", name, *value, max, max))
+			*value = max
+		}
+	}
+
+	// Validation debounce: 0 is meaningful (immediate)
+	clamp("validation_debounce_ms", &t.ValidationDebounceMs, 0, MaxValidationDebounceMs)
+
+	// Autosave: 0 is meaningful (disabled), negative means disabled too
+	if t.AutosaveIntervalSec < 0 {
+		warnings = append(warnings, fmt.Sprintf("autosave_interval_sec %d is negative, disabling autosave", t.AutosaveIntervalSec))
+		t.AutosaveIntervalSec = 0
+	} else if t.AutosaveIntervalSec != 0 {
+		clamp("autosave_interval_sec", &t.AutosaveIntervalSec, MinAutosaveIntervalSec, MaxAutosaveIntervalSec)
+	}
+
+	if t.HealthCheckIntervalSec == 0 {
+		t.HealthCheckIntervalSec = DefaultHealthCheckIntervalSec
+	}
+	clamp("health_check_interval_sec", &t.HealthCheckIntervalSec, MinHealthCheckIntervalSec, MaxHealthCheckIntervalSec)
+
+	if t.EventQueueSize == 0 {
+		t.EventQueueSize = DefaultEventQueueSize
+	}
+	clamp("event_queue_size", &t.EventQueueSize, MinEventQueueSize, MaxEventQueueSize)
+
+	if t.InputQueueSize == 0 {
+		t.InputQueueSize = DefaultInputQueueSize
+	}
+	clamp("input_queue_size", &t.InputQueueSize, MinInputQueueSize, MaxInputQueueSize)
+
+	if t.UndoDepth == 0 {
+		t.UndoDepth = DefaultUndoDepth
+	}
+	clamp("undo_depth", &t.UndoDepth, MinUndoDepth, MaxUndoDepth)
+
+	if t.UndoMemoryMB == 0 {
+		t.UndoMemoryMB = DefaultUndoMemoryMB
+	}
+	clamp("undo_memory_mb", &t.UndoMemoryMB, MinUndoMemoryMB, MaxUndoMemoryMB)
+
+	// Guardrails: 0 is meaningful (no limit), negative means no limit too
+	for _, guardrail := range []struct {
+		name  string
+		value *int
+	}{
+		{"max_variables_mb", &t.MaxVariablesMB},
+		{"max_payload_kb", &t.MaxPayloadKB},
+		{"max_node_executions", &t.MaxNodeExecutions},
+		{"max_execution_sec", &t.MaxExecutionSec},
+	} {
+		if *guardrail.value < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s %d is negative, disabling the limit", guardrail.name, *guardrail.value))
+			*guardrail.value = 0
+		}
+	}
+
+	if t.SpillVariableKB < 0 {
+		warnings = append(warnings, fmt.Sprintf("spill_variable_kb %d is negative, disabling spilling", t.SpillVariableKB))
+		t.SpillVariableKB = 0
+	}
+
+	switch t.Role {
+	case "", RoleEditor, RoleViewer:
+	default:
+		warnings = append(warnings, fmt.Sprintf("role %q is unknown, using %s", t.Role, RoleViewer))
+		t.Role = RoleViewer
+	}
+
+	return t, warnings
+}
+
+// ValidationDebounce returns the validation debounce as a duration
+func (t Tunables) ValidationDebounce() time.Duration {
+	return time.Duration(t.ValidationDebounceMs) * time.Millisecond
+}
+
+// AutosaveInterval returns the autosave interval (0 when disabled)
+func (t Tunables) AutosaveInterval() time.Duration {
+	return time.Duration(t.AutosaveIntervalSec) * time.Second
+}
+
+// HealthCheckInterval returns the health check interval
+func (t Tunables) HealthCheckInterval() time.Duration {
+	return time.Duration(t.HealthCheckIntervalSec) * time.Second
+}
+
+// Store holds the current tunables and notifies subscribers of changes
+type Store struct {
+	mu          sync.RWMutex
+	current     Tunables
+	subscribers map[int]func(Tunables)
+	nextID      int
+}
+
+// NewStore creates a store initialized with the given tunables (normalized)
+func NewStore(initial Tunables) *Store {
+	normalized, _ := initial.Normalize()
+	return &Store{
+		current:     normalized,
+		subscribers: make(map[int]func(Tunables)),
+	}
+}
+
+// Get returns the current tunables
+func (s *Store) Get() Tunables {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Update normalizes and applies new tunables, notifying subscribers if
+// anything changed. Returns any warnings produced by normalization.
+func (s *Store) Update(t Tunables) []string {
+	normalized, warnings := t.Normalize()
+
+	s.mu.Lock()
+	if normalized == s.current {
+		s.mu.Unlock()
+		return warnings
+	}
+	s.current = normalized
+	subscribers := make([]func(Tunables), 0, len(s.subscribers))
+	for _, fn := range s.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	s.mu.Unlock()
+
+	// Notify outside the lock so callbacks may call Get
+	for _, fn := range subscribers {
+		fn(normalized)
+	}
+
+	return warnings
+}
+
+// Subscribe registers fn to be called with the new tunables after each change.
+// fn is also called immediately with the current value. The returned function
+// removes the subscription.
+func (s *Store) Subscribe(fn func(Tunables)) (unsubscribe func()) {
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = fn
+	current := s.current
+	s.mu.Unlock()
+
+	fn(current)
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+var (
+	globalStore     *Store
+	globalStoreOnce sync.Once
+)
+
+// Global returns the process-wide tunables store, initialized with defaults
+func Global() *Store {
+	globalStoreOnce.Do(func() {
+		globalStore = NewStore(DefaultTunables())
+	})
+	return globalStore
+}
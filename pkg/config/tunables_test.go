@@ -0,0 +1,160 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTunablesNormalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        Tunables
+		want         Tunables
+		wantWarnings int
+	}{
+		{
+			name:  "zero values use defaults",
+			input: Tunables{},
+			want: Tunables{
+				ValidationDebounceMs:   0,
+				AutosaveIntervalSec:    0,
+				HealthCheckIntervalSec: DefaultHealthCheckIntervalSec,
+				EventQueueSize:         DefaultEventQueueSize,
+				InputQueueSize:         DefaultInputQueueSize,
+				UndoDepth:              DefaultUndoDepth,
+				UndoMemoryMB:           DefaultUndoMemoryMB,
+			},
+		},
+		{
+			name:  "defaults unchanged",
+			input: DefaultTunables(),
+			want:  DefaultTunables(),
+		},
+		{
+			name: "values clamped to bounds",
+			input: Tunables{
+				ValidationDebounceMs:   60000,
+				AutosaveIntervalSec:    1,
+				HealthCheckIntervalSec: 1,
+				EventQueueSize:         1 << 30,
+				InputQueueSize:         2,
+				UndoDepth:              -5,
+				UndoMemoryMB:           1 << 20,
+			},
+			want: Tunables{
+				ValidationDebounceMs:   MaxValidationDebounceMs,
+				AutosaveIntervalSec:    MinAutosaveIntervalSec,
+				HealthCheckIntervalSec: MinHealthCheckIntervalSec,
+				EventQueueSize:         MaxEventQueueSize,
+				InputQueueSize:         MinInputQueueSize,
+				UndoDepth:              MinUndoDepth,
+				UndoMemoryMB:           MaxUndoMemoryMB,
+			},
+			wantWarnings: 7,
+		},
+		{
+			name: "negative values",
+			input: Tunables{
+				ValidationDebounceMs: -10,
+				AutosaveIntervalSec:  -1,
+				MaxPayloadKB:         -1,
+				MaxExecutionSec:      -5,
+			},
+			want: Tunables{
+				ValidationDebounceMs:   0,
+				AutosaveIntervalSec:    0,
+				HealthCheckIntervalSec: DefaultHealthCheckIntervalSec,
+				EventQueueSize:         DefaultEventQueueSize,
+				InputQueueSize:         DefaultInputQueueSize,
+				UndoDepth:              DefaultUndoDepth,
+				UndoMemoryMB:           DefaultUndoMemoryMB,
+			},
+			wantWarnings: 4,
+		},
+		{
+			name:  "unknown role is read-only",
+			input: Tunables{Role: "reviewer"},
+			want: Tunables{
+				HealthCheckIntervalSec: DefaultHealthCheckIntervalSec,
+				EventQueueSize:         DefaultEventQueueSize,
+				InputQueueSize:         DefaultInputQueueSize,
+				UndoDepth:              DefaultUndoDepth,
+				UndoMemoryMB:           DefaultUndoMemoryMB,
+				Role:                   RoleViewer,
+			},
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings := tt.input.Normalize()
+			if got != tt.want {
+				t.Errorf("Normalize() = %+v, want %+v", got, tt.want)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("Normalize() warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestTunablesDurations(t *testing.T) {
+	tun := Tunables{ValidationDebounceMs: 300, AutosaveIntervalSec: 60, HealthCheckIntervalSec: 10}
+	if got := tun.ValidationDebounce(); got != 300*time.Millisecond {
+		t.Errorf("ValidationDebounce() = %v", got)
+	}
+	if got := tun.AutosaveInterval(); got != time.Minute {
+		t.Errorf("AutosaveInterval() = %v", got)
+	}
+	if got := tun.HealthCheckInterval(); got != 10*time.Second {
+		t.Errorf("HealthCheckInterval() = %v", got)
+	}
+}
+
+func TestStoreSubscribe(t *testing.T) {
+	store := NewStore(DefaultTunables())
+
+	var received []Tunables
+	unsubscribe := store.Subscribe(func(t Tunables) {
+		received = append(received, t)
+	})
+
+	if len(received) != 1 || received[0] != DefaultTunables() {
+		t.Fatalf("Subscribe should deliver current value immediately, got %v", received)
+	}
+
+	updated := DefaultTunables()
+	updated.HealthCheckIntervalSec = 60
+	store.Update(updated)
+	if len(received) != 2 || received[1].HealthCheckIntervalSec != 60 {
+		t.Fatalf("expected update notification, got %v", received)
+	}
+
+	// Identical update does not notify
+	store.Update(updated)
+	if len(received) != 2 {
+		t.Errorf("unchanged update should not notify, got %d notifications", len(received))
+	}
+
+	unsubscribe()
+	updated.HealthCheckIntervalSec = 120
+	store.Update(updated)
+	if len(received) != 2 {
+		t.Errorf("unsubscribed callback was called")
+	}
+	if store.Get().HealthCheckIntervalSec != 120 {
+		t.Errorf("Get() = %+v, want health check 120", store.Get())
+	}
+}
+
+func TestStoreUpdateWarnings(t *testing.T) {
+	store := NewStore(DefaultTunables())
+	warnings := store.Update(Tunables{EventQueueSize: 1})
+	if len(warnings) != 1 {
+		t.Errorf("Update() warnings = %v, want 1", warnings)
+	}
+	if store.Get().EventQueueSize != MinEventQueueSize {
+		t.Errorf("EventQueueSize = %d, want %d", store.Get().EventQueueSize, MinEventQueueSize)
+	}
+}
@@ -0,0 +1,77 @@
+package execution
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Blob is binary data held in a variable, such as an image or audio clip a
+// tool returned. Small blobs keep their bytes in memory; large ones are
+// spilled to a file and only its path is kept. Blobs are immutable, so
+// copies of a variable share them.
+type Blob struct {
+	// MimeType describes the data (e.g. "image/png"); empty when unknown.
+	MimeType string
+	// Size is the length of the data in bytes.
+	Size int64
+
+	data []byte
+	path string
+}
+
+// NewBlob creates a blob holding data in memory.
+func NewBlob(data []byte, mimeType string) *Blob {
+	return &Blob{MimeType: mimeType, Size: int64(len(data)), data: data}
+}
+
+// NewFileBlob creates a blob whose size bytes of data are stored in the file
+// at path.
+func NewFileBlob(path, mimeType string, size int64) *Blob {
+	return &Blob{MimeType: mimeType, Size: size, path: path}
+}
+
+// Path returns the file holding the blob's data, or "" for blobs held in
+// memory.
+func (b *Blob) Path() string {
+	return b.path
+}
+
+// Bytes returns the blob's data, reading it from its file if it was spilled.
+func (b *Blob) Bytes() ([]byte, error) {
+	if b.path == "" {
+		return b.data, nil
+	}
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
+
+// String describes the blob without its data, e.g. "blob(image/png, 2048 bytes)".
+func (b *Blob) String() string {
+	mimeType := b.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return fmt.Sprintf("blob(%s, %d bytes)", mimeType, b.Size)
+}
+
+// MarshalJSON encodes the blob as an object of type "blob". Blobs held in
+// memory include their data in base64; spilled blobs include only their
+// path, keeping stored and measured variables small.
+func (b *Blob) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"type":      "blob",
+		"mime_type": b.MimeType,
+		"size":      b.Size,
+	}
+	if b.path != "" {
+		out["path"] = b.path
+	} else {
+		out["data"] = base64.StdEncoding.EncodeToString(b.data)
+	}
+	return json.Marshal(out)
+}
@@ -2,6 +2,8 @@ package execution
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
@@ -21,6 +23,8 @@ type ExecutionContext struct {
 	executionTrace []TraceEntry
 	// mu protects concurrent access to all fields.
 	mu sync.RWMutex
+	// spiller, when set, moves large values out of memory as they are set.
+	spiller Spiller
 
 	// Timeout support fields
 	ctx             context.Context    // Context with timeout/deadline
@@ -48,7 +52,21 @@ func NewExecutionContext(initialVars map[string]interface{}) (*ExecutionContext,
 
 // GetVariable retrieves a variable value by name.
 // Returns (value, true) if the variable exists, (nil, false) otherwise.
+// Spilled values are loaded from their files; if loading fails the
+// *SpilledValue reference is returned.
 func (ctx *ExecutionContext) GetVariable(name string) (interface{}, bool) {
+	val, exists := ctx.GetVariableRef(name)
+	if spilled, ok := val.(*SpilledValue); ok {
+		if loaded, err := spilled.Load(); err == nil {
+			return loaded, true
+		}
+	}
+	return val, exists
+}
+
+// GetVariableRef retrieves a variable value by name without loading
+// spilled values, for callers that can read a *SpilledValue lazily.
+func (ctx *ExecutionContext) GetVariableRef(name string) (interface{}, bool) {
 	ctx.mu.RLock()
 	defer ctx.mu.RUnlock()
 
@@ -56,6 +74,15 @@ func (ctx *ExecutionContext) GetVariable(name string) (interface{}, bool) {
 	return val, exists
 }
 
+// SetSpiller makes the context pass every value it sets to s, which may
+// move large values out of memory. Pass nil to keep all values in memory.
+func (ctx *ExecutionContext) SetSpiller(s Spiller) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	ctx.spiller = s
+}
+
 // SetVariable sets a variable value and records the change in the audit trail.
 // This is a convenience wrapper for SetVariableWithNode with no node execution ID.
 func (ctx *ExecutionContext) SetVariable(name string, value interface{}) error {
@@ -65,6 +92,20 @@ func (ctx *ExecutionContext) SetVariable(name string, value interface{}) error {
 // SetVariableWithNode sets a variable value and records which node made the change.
 // Creates a snapshot in the variable history for audit trail.
 func (ctx *ExecutionContext) SetVariableWithNode(name string, value interface{}, nodeExecID types.NodeExecutionID) error {
+	// Spill large values before taking the lock; writing them out is slow
+	ctx.mu.RLock()
+	spiller := ctx.spiller
+	ctx.mu.RUnlock()
+	if spiller != nil {
+		spilled, err := spiller.Spill(name, value)
+		if err != nil {
+			return fmt.Errorf("failed to spill variable '%s': %w", name, err)
+		}
+		if spilled != nil {
+			value = spilled
+		}
+	}
+
 	ctx.mu.Lock()
 	defer ctx.mu.Unlock()
 
@@ -214,6 +255,63 @@ func (ctx *ExecutionContext) CopyVariablesTo(target *ExecutionContext) {
 	}
 }
 
+// ChangedVariables returns deep copies of the variables whose values differ
+// from base, including variables base does not have. Used to merge only what
+// a parallel branch wrote back to its parent.
+func (ctx *ExecutionContext) ChangedVariables(base map[string]interface{}) map[string]interface{} {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+
+	changed := make(map[string]interface{})
+	for key, value := range ctx.Variables {
+		if old, exists := base[key]; exists && reflect.DeepEqual(old, value) {
+			continue
+		}
+		changed[key] = deepCopyValue(value)
+	}
+	return changed
+}
+
+// RestoreVariables rolls the variables back to base, as taken with
+// CreateSnapshot, ending a scope: variables set since are removed and
+// changed ones get their base values back. Variables named in keep are left
+// as they are. Restored values are recorded in the variable history.
+func (ctx *ExecutionContext) RestoreVariables(base map[string]interface{}, keep []string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[name] = true
+	}
+
+	restore := func(name string, oldValue, value interface{}) {
+		ctx.Variables[name] = value
+		ctx.variableHistory = append(ctx.variableHistory, VariableSnapshot{
+			Timestamp:    time.Now(),
+			VariableName: name,
+			OldValue:     oldValue,
+			NewValue:     value,
+		})
+	}
+	for name, value := range ctx.Variables {
+		if kept[name] {
+			continue
+		}
+		old, exists := base[name]
+		if !exists {
+			delete(ctx.Variables, name)
+		} else if !reflect.DeepEqual(old, value) {
+			restore(name, value, old)
+		}
+	}
+	for name, old := range base {
+		if _, exists := ctx.Variables[name]; !exists && !kept[name] {
+			restore(name, nil, old)
+		}
+	}
+}
+
 // Context returns the execution context for timeout and cancellation.
 // Returns context.Background() if no timeout is configured (backwards compatible).
 func (ctx *ExecutionContext) Context() context.Context {
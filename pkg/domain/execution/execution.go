@@ -112,6 +112,33 @@ func (e *Execution) Cancel() error {
 	return nil
 }
 
+// CancelWithReason marks the execution as cancelled and records why in
+// Error, with the node that was running when it stopped.
+// Returns an error if the execution is not in Running status.
+func (e *Execution) CancelWithReason(reason string, nodeID types.NodeID) error {
+	if err := e.Cancel(); err != nil {
+		return err
+	}
+
+	e.Error = &ExecutionError{
+		Type:        ErrorTypeCancelled,
+		Message:     reason,
+		NodeID:      nodeID,
+		Timestamp:   e.CompletedAt,
+		Recoverable: false,
+	}
+	return nil
+}
+
+// CancelReason returns why the execution was cancelled, or "" if it was not
+// cancelled or no reason was recorded.
+func (e *Execution) CancelReason() string {
+	if e.Status != StatusCancelled || e.Error == nil || e.Error.Type != ErrorTypeCancelled {
+		return ""
+	}
+	return e.Error.Message
+}
+
 // Timeout marks the execution as timed out with error details.
 // Returns an error if the execution is not in Running status.
 func (e *Execution) Timeout(timeoutNode string, err *ExecutionError) error {
@@ -152,6 +179,17 @@ func (e *Execution) AddNodeExecution(nodeExec *NodeExecution) error {
 	return nil
 }
 
+// LatestNodeExecution returns the most recent execution record of a node,
+// or nil if the node has not run.
+func (e *Execution) LatestNodeExecution(nodeID types.NodeID) *NodeExecution {
+	for i := len(e.NodeExecutions) - 1; i >= 0; i-- {
+		if e.NodeExecutions[i].NodeID == nodeID {
+			return e.NodeExecutions[i]
+		}
+	}
+	return nil
+}
+
 // Duration returns the total execution time.
 // Returns 0 if the execution hasn't completed yet.
 func (e *Execution) Duration() time.Duration {
@@ -31,6 +31,12 @@ type NodeExecution struct {
 	Error *NodeError
 	// RetryCount is the number of retries attempted for this node.
 	RetryCount int
+	// RetryOf references the failed node execution this one manually retries
+	// (empty for executions made during the normal workflow run).
+	RetryOf types.NodeExecutionID
+	// CacheHit reports that the node's result came from the tool result
+	// cache instead of a call to its MCP server.
+	CacheHit bool
 }
 
 // NewNodeExecution creates a new node execution record.
@@ -88,3 +94,9 @@ func (ne *NodeExecution) Duration() time.Duration {
 func (ne *NodeExecution) IncrementRetry() {
 	ne.RetryCount++
 }
+
+// IsManualRetry reports whether this node execution is a manual retry of an
+// earlier failed attempt.
+func (ne *NodeExecution) IsManualRetry() bool {
+	return ne.RetryOf != ""
+}
@@ -0,0 +1,66 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SpilledValue is a variable value moved out of memory into a file, as
+// JSON, because it was too large to keep. GetVariable loads it back
+// transparently; snapshots and the variable history keep the reference, and
+// transformations read the file only when they use the value.
+type SpilledValue struct {
+	// Path is the file holding the value's JSON encoding.
+	Path string
+	// Size is the length of the JSON encoding in bytes.
+	Size int64
+}
+
+// OpenJSON opens the value's JSON encoding for streaming.
+func (v *SpilledValue) OpenJSON() (io.ReadCloser, error) {
+	file, err := os.Open(v.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spilled value: %w", err)
+	}
+	return file, nil
+}
+
+// Load decodes the value from its file. Numbers come back as float64, as
+// from any JSON source.
+func (v *SpilledValue) Load() (interface{}, error) {
+	file, err := v.OpenJSON()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var value interface{}
+	if err := json.NewDecoder(file).Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode spilled value: %w", err)
+	}
+	return value, nil
+}
+
+// String describes the reference, e.g. "spilled(1048576 bytes)".
+func (v *SpilledValue) String() string {
+	return fmt.Sprintf("spilled(%d bytes)", v.Size)
+}
+
+// MarshalJSON encodes the reference, not the value, so persisting or
+// measuring variables does not load spilled values.
+func (v *SpilledValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type": "spilled",
+		"path": v.Path,
+		"size": v.Size,
+	})
+}
+
+// Spiller moves large variable values out of memory.
+type Spiller interface {
+	// Spill stores value outside memory and returns a reference to it, or
+	// nil when the value is small enough to keep.
+	Spill(name string, value interface{}) (*SpilledValue, error)
+}
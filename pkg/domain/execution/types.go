@@ -61,6 +61,14 @@ const (
 	ErrorTypeData ErrorType = "data"
 	// ErrorTypeTimeout indicates the execution exceeded its time limit.
 	ErrorTypeTimeout ErrorType = "timeout"
+	// ErrorTypeGuardrail indicates the execution exceeded a resource guardrail
+	// (variables memory, payload size, node executions, wall-clock time).
+	ErrorTypeGuardrail ErrorType = "guardrail"
+	// ErrorTypePolicy indicates the execution reached a node its sandbox
+	// profile does not allow.
+	ErrorTypePolicy ErrorType = "policy"
+	// ErrorTypeCancelled records why an execution was cancelled.
+	ErrorTypeCancelled ErrorType = "cancelled"
 )
 
 // ExecutionError represents detailed error information for failed executions.
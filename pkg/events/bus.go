@@ -0,0 +1,249 @@
+// Package events provides an in-process event bus that GoFlow subsystems
+// publish to and consumers (the TUI, metrics, external integrations)
+// subscribe to, instead of each consumer wiring its own callbacks.
+//
+// Events are delivered asynchronously: each subscriber has its own buffered
+// queue drained by its own goroutine, so a slow subscriber never blocks a
+// publisher or other subscribers. When a subscriber's queue is full, new
+// events for it are dropped and counted.
+package events
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dshills/goflow/pkg/config"
+)
+
+// Topic groups related event types so subscribers can choose what they
+// receive.
+type Topic string
+
+const (
+	// TopicExecution carries workflow execution lifecycle events
+	// (started, paused, resumed, completed, failed, cancelled) and the other
+	// execution-wide events: variables, loops, conditions, approvals and
+	// progress.
+	TopicExecution Topic = "execution"
+	// TopicNode carries node lifecycle events (started, completed, failed,
+	// skipped).
+	TopicNode Topic = "node"
+	// TopicValidation carries workflow validation failures.
+	TopicValidation Topic = "validation"
+	// TopicServer carries MCP server health changes.
+	TopicServer Topic = "server"
+)
+
+// Event types published outside the execution engine. Execution and node
+// events use the engine's own event types, e.g. "execution.started".
+const (
+	// TypeValidationFailed is published when a workflow fails validation.
+	// The payload is a ValidationFailure.
+	TypeValidationFailed = "validation.failed"
+	// TypeServerHealthChanged is published when an MCP server's health
+	// status changes. The payload is a ServerHealthChange.
+	TypeServerHealthChanged = "server.health_changed"
+)
+
+// Event is a single occurrence published on a Bus.
+type Event struct {
+	// Topic groups the event for subscriptions.
+	Topic Topic
+	// Type identifies what happened, e.g. "node.failed".
+	Type string
+	// Timestamp records when the event occurred.
+	Timestamp time.Time
+	// Source identifies what the event is about: an execution ID, a
+	// workflow name or a server ID.
+	Source string
+	// Payload carries the event details. Its type depends on Type:
+	// execution and node events carry an execution.ExecutionEvent.
+	Payload interface{}
+}
+
+// ValidationFailure is the payload of TypeValidationFailed events.
+type ValidationFailure struct {
+	// Workflow is the name of the workflow that failed validation.
+	Workflow string
+	// Errors lists the validation errors.
+	Errors []string
+}
+
+// ValidationFailed returns the event published when workflow fails
+// validation with errs.
+func ValidationFailed(workflow string, errs ...string) Event {
+	return Event{
+		Topic:   TopicValidation,
+		Type:    TypeValidationFailed,
+		Source:  workflow,
+		Payload: ValidationFailure{Workflow: workflow, Errors: errs},
+	}
+}
+
+// ServerHealthChange is the payload of TypeServerHealthChanged events.
+type ServerHealthChange struct {
+	// ServerID identifies the MCP server.
+	ServerID string
+	// Previous is the health status before the change.
+	Previous string
+	// Current is the new health status.
+	Current string
+	// Error describes the failure that made the server unhealthy, if any.
+	Error string
+}
+
+// TopicOf returns the topic of an event type from its prefix: "node.*"
+// types belong to TopicNode, "validation.*" to TopicValidation, "server.*"
+// to TopicServer and everything else to TopicExecution.
+func TopicOf(eventType string) Topic {
+	prefix, _, _ := strings.Cut(eventType, ".")
+	switch Topic(prefix) {
+	case TopicNode, TopicValidation, TopicServer:
+		return Topic(prefix)
+	}
+	return TopicExecution
+}
+
+// Subscriber consumes events from a Bus. HandleEvent is called from the
+// subscriber's own goroutine, one event at a time, in publish order.
+type Subscriber interface {
+	HandleEvent(Event)
+}
+
+// SubscriberFunc adapts a function to the Subscriber interface.
+type SubscriberFunc func(Event)
+
+// HandleEvent calls f(event).
+func (f SubscriberFunc) HandleEvent(event Event) {
+	f(event)
+}
+
+// subscription is a subscriber with its queue and topic filter
+type subscription struct {
+	subscriber Subscriber
+	topics     map[Topic]bool // nil means every topic
+	queue      chan Event
+	done       chan struct{}
+}
+
+// wants reports whether the subscription receives events of topic
+func (s *subscription) wants(topic Topic) bool {
+	return s.topics == nil || s.topics[topic]
+}
+
+// run delivers queued events until the queue is closed
+func (s *subscription) run() {
+	defer close(s.done)
+	for event := range s.queue {
+		s.subscriber.HandleEvent(event)
+	}
+}
+
+// Bus delivers published events to subscribers. The zero value is not
+// usable; create buses with NewBus.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[uint64]*subscription
+	nextID        uint64
+	queueSize     int
+	dropped       atomic.Uint64
+}
+
+// NewBus creates a bus whose subscribers queue up to queueSize events each.
+// 0 or a negative size follows the event_queue_size tunable.
+func NewBus(queueSize int) *Bus {
+	return &Bus{
+		subscriptions: make(map[uint64]*subscription),
+		queueSize:     queueSize,
+	}
+}
+
+// Subscribe registers sub for events of the given topics, or of every topic
+// when none are given. The returned function removes the subscription after
+// the events already queued for it are delivered.
+func (b *Bus) Subscribe(sub Subscriber, topics ...Topic) (unsubscribe func()) {
+	size := b.queueSize
+	if size <= 0 {
+		size = config.Global().Get().EventQueueSize
+	}
+
+	s := &subscription{
+		subscriber: sub,
+		queue:      make(chan Event, size),
+		done:       make(chan struct{}),
+	}
+	if len(topics) > 0 {
+		s.topics = make(map[Topic]bool, len(topics))
+		for _, topic := range topics {
+			s.topics[topic] = true
+		}
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscriptions[id] = s
+	b.mu.Unlock()
+
+	go s.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscriptions, id)
+			close(s.queue)
+			b.mu.Unlock()
+			<-s.done
+		})
+	}
+}
+
+// Publish queues event for every subscriber of its topic without blocking.
+// A zero Timestamp is set to the current time, and an empty Topic is derived
+// from the Type with TopicOf.
+func (b *Bus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.Topic == "" {
+		event.Topic = TopicOf(event.Type)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, s := range b.subscriptions {
+		if !s.wants(event.Topic) {
+			continue
+		}
+		select {
+		case s.queue <- event:
+		default:
+			// Queue full, drop rather than block the publisher
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped returns how many events were dropped because a subscriber's queue
+// was full.
+func (b *Bus) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+var (
+	defaultBus     *Bus
+	defaultBusOnce sync.Once
+)
+
+// Default returns the process-wide bus, which the execution engine, MCP
+// servers and workflow validation publish to unless configured otherwise.
+func Default() *Bus {
+	defaultBusOnce.Do(func() {
+		defaultBus = NewBus(0)
+	})
+	return defaultBus
+}
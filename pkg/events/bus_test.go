@@ -0,0 +1,137 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the events it receives
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) HandleEvent(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]string, 0, len(r.events))
+	for _, event := range r.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestTopicOf(t *testing.T) {
+	tests := map[string]Topic{
+		"execution.started":     TopicExecution,
+		"node.failed":           TopicNode,
+		"validation.failed":     TopicValidation,
+		"server.health_changed": TopicServer,
+		"variable.changed":      TopicExecution,
+		"progress":              TopicExecution,
+	}
+	for eventType, want := range tests {
+		if got := TopicOf(eventType); got != want {
+			t.Errorf("TopicOf(%q) = %q, want %q", eventType, got, want)
+		}
+	}
+}
+
+func TestBusDeliversByTopic(t *testing.T) {
+	bus := NewBus(16)
+	all, nodes := &recorder{}, &recorder{}
+	unsubscribeAll := bus.Subscribe(all)
+	unsubscribeNodes := bus.Subscribe(nodes, TopicNode, TopicServer)
+
+	bus.Publish(Event{Type: "execution.started"})
+	bus.Publish(Event{Type: "node.started"})
+	bus.Publish(Event{Type: TypeServerHealthChanged, Payload: ServerHealthChange{ServerID: "fs"}})
+	bus.Publish(Event{Type: "node.completed"})
+
+	// Unsubscribing delivers what is already queued
+	unsubscribeAll()
+	unsubscribeNodes()
+
+	wantAll := []string{"execution.started", "node.started", TypeServerHealthChanged, "node.completed"}
+	if got := all.types(); !equal(got, wantAll) {
+		t.Errorf("all topics got %v, want %v", got, wantAll)
+	}
+	wantNodes := []string{"node.started", TypeServerHealthChanged, "node.completed"}
+	if got := nodes.types(); !equal(got, wantNodes) {
+		t.Errorf("node and server topics got %v, want %v", got, wantNodes)
+	}
+	if nodes.events[1].Topic != TopicServer || nodes.events[1].Timestamp.IsZero() {
+		t.Errorf("expected topic and timestamp to be filled in, got %+v", nodes.events[1])
+	}
+
+	// Nothing is delivered after unsubscribing
+	bus.Publish(Event{Type: "node.started"})
+	if got := len(nodes.types()); got != 3 {
+		t.Errorf("expected no events after unsubscribe, got %d", got)
+	}
+}
+
+func TestBusDropsForSlowSubscribers(t *testing.T) {
+	bus := NewBus(2)
+	release := make(chan struct{})
+	received := make(chan string, 10)
+	unsubscribeSlow := bus.Subscribe(SubscriberFunc(func(event Event) {
+		<-release
+		received <- event.Type
+	}))
+	fast := &recorder{}
+	unsubscribeFast := bus.Subscribe(fast)
+
+	for i := 0; i < 10; i++ {
+		bus.Publish(Event{Type: "node.started"})
+	}
+
+	// The slow subscriber holds one event and queues two; the rest drop
+	// without blocking the publisher or the fast subscriber
+	close(release)
+	unsubscribeSlow()
+	unsubscribeFast()
+
+	if got := len(received); got > 3 {
+		t.Errorf("slow subscriber got %d events, want at most 3", got)
+	}
+	if bus.Dropped() < 7 {
+		t.Errorf("expected at least 7 dropped events, got %d", bus.Dropped())
+	}
+	if got := len(fast.types()); got == 0 {
+		t.Error("fast subscriber got no events")
+	}
+}
+
+func TestBusQueueSizeFromTunables(t *testing.T) {
+	bus := NewBus(0)
+	done := make(chan struct{})
+	unsubscribe := bus.Subscribe(SubscriberFunc(func(Event) { close(done) }))
+	defer unsubscribe()
+
+	bus.Publish(Event{Type: "execution.started"})
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,244 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// approvalTimeoutBy is recorded as the decider when an approval times out
+const approvalTimeoutBy = "timeout"
+
+// PendingApproval is an approval node waiting for a decision.
+type PendingApproval struct {
+	ExecutionID types.ExecutionID `json:"execution_id"`
+	NodeID      types.NodeID      `json:"node_id"`
+	Message     string            `json:"message,omitempty"`
+	RequestedAt time.Time         `json:"requested_at"`
+	// Deadline is when the node's default action is taken (nil = waits
+	// until decided).
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// DefaultAction is taken at the deadline: "approve" or "reject".
+	DefaultAction string `json:"default_action,omitempty"`
+}
+
+// ApprovalDecision approves or rejects a pending approval.
+type ApprovalDecision struct {
+	Approved bool   `json:"approved"`
+	By       string `json:"by,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// pendingApproval is a waiting approval node and where its decision goes
+type pendingApproval struct {
+	info     PendingApproval
+	decision chan ApprovalDecision // Buffered; receives exactly one decision
+}
+
+// PendingApprovals returns the approval nodes waiting for a decision, oldest
+// first.
+func (e *Engine) PendingApprovals() []PendingApproval {
+	e.approvalMu.Lock()
+	defer e.approvalMu.Unlock()
+
+	pending := make([]PendingApproval, 0, len(e.approvals))
+	for _, approval := range e.approvals {
+		pending = append(pending, approval.info)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+	return pending
+}
+
+// Approve lets the approval node nodeID continue, recording who approved it.
+func (e *Engine) Approve(nodeID, by string) error {
+	return e.Decide(nodeID, ApprovalDecision{Approved: true, By: by})
+}
+
+// Reject fails the approval node nodeID, recording who rejected it.
+func (e *Engine) Reject(nodeID, by string) error {
+	return e.Decide(nodeID, ApprovalDecision{Approved: false, By: by})
+}
+
+// Decide delivers decision to the approval node nodeID. It fails if the node
+// is not waiting for a decision.
+func (e *Engine) Decide(nodeID string, decision ApprovalDecision) error {
+	e.approvalMu.Lock()
+	approval, ok := e.approvals[types.NodeID(nodeID)]
+	if ok {
+		delete(e.approvals, types.NodeID(nodeID))
+	}
+	e.approvalMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no approval is pending for node %s", nodeID)
+	}
+	approval.decision <- decision
+	return nil
+}
+
+// executeApprovalNode waits for a decision on the node, or for its timeout.
+// A rejection fails the node.
+func (e *Engine) executeApprovalNode(ctx context.Context, node *workflow.ApprovalNode, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
+	message, err := e.substituteVariables(node.Message, exec.Context)
+	if err != nil {
+		return fmt.Errorf("failed to substitute variables in message: %w", err)
+	}
+
+	var timeout time.Duration
+	if node.Timeout != "" {
+		value, err := e.substituteVariables(node.Timeout, exec.Context)
+		if err != nil {
+			return fmt.Errorf("failed to substitute variables in timeout: %w", err)
+		}
+		if timeout, err = workflow.ParseDelayDuration(value); err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+	}
+
+	approval := &pendingApproval{
+		info: PendingApproval{
+			ExecutionID: exec.ID,
+			NodeID:      nodeExec.NodeID,
+			Message:     message,
+			RequestedAt: time.Now(),
+		},
+		decision: make(chan ApprovalDecision, 1),
+	}
+	if timeout > 0 {
+		deadline := approval.info.RequestedAt.Add(timeout)
+		approval.info.Deadline = &deadline
+		approval.info.DefaultAction = node.TimeoutAction()
+	}
+	nodeExec.Inputs = map[string]interface{}{
+		"message": message,
+		"timeout": node.Timeout,
+	}
+
+	e.approvalMu.Lock()
+	if _, exists := e.approvals[nodeExec.NodeID]; exists {
+		e.approvalMu.Unlock()
+		return fmt.Errorf("approval node '%s' is already waiting for a decision", node.ID)
+	}
+	if e.approvals == nil {
+		e.approvals = make(map[types.NodeID]*pendingApproval)
+	}
+	e.approvals[nodeExec.NodeID] = approval
+	e.approvalMu.Unlock()
+
+	// Withdraw the request if the node stops waiting without a decision
+	defer func() {
+		e.approvalMu.Lock()
+		if e.approvals[nodeExec.NodeID] == approval {
+			delete(e.approvals, nodeExec.NodeID)
+		}
+		e.approvalMu.Unlock()
+	}()
+
+	e.emitApprovalEvent(EventApprovalRequested, exec, approval.info, nil)
+
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	var decision ApprovalDecision
+	timedOut := false
+	select {
+	case decision = <-approval.decision:
+	case <-timer:
+		timedOut = true
+		decision = ApprovalDecision{
+			Approved: node.TimeoutAction() == workflow.ApprovalApprove,
+			By:       approvalTimeoutBy,
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	e.emitApprovalEvent(EventApprovalDecided, exec, approval.info, map[string]interface{}{
+		"approved":  decision.Approved,
+		"by":        decision.By,
+		"comment":   decision.Comment,
+		"timed_out": timedOut,
+	})
+
+	result := map[string]interface{}{
+		"approved":   decision.Approved,
+		"by":         decision.By,
+		"comment":    decision.Comment,
+		"timed_out":  timedOut,
+		"decided_at": time.Now().Format(time.RFC3339),
+	}
+	if node.OutputVariable != "" {
+		if err := exec.Context.SetVariableWithNode(node.OutputVariable, result, nodeExec.ID); err != nil {
+			return fmt.Errorf("failed to set output variable '%s': %w", node.OutputVariable, err)
+		}
+
+		// Log variable change
+		if e.logger != nil {
+			snapshots := exec.Context.GetVariableHistory()
+			if len(snapshots) > 0 {
+				e.logger.LogVariableChange(&snapshots[len(snapshots)-1])
+			}
+		}
+	}
+	nodeExec.Outputs = result
+
+	if !decision.Approved {
+		switch {
+		case timedOut:
+			return fmt.Errorf("approval timed out after %s", timeout)
+		case decision.Comment != "":
+			return fmt.Errorf("approval rejected by %s: %s", decisionBy(decision), decision.Comment)
+		default:
+			return fmt.Errorf("approval rejected by %s", decisionBy(decision))
+		}
+	}
+	return nil
+}
+
+// decisionBy names who made a decision, for messages
+func decisionBy(decision ApprovalDecision) string {
+	if decision.By == "" {
+		return "an unnamed user"
+	}
+	return decision.By
+}
+
+// emitApprovalEvent reports an approval request or decision, with the
+// request's message and deadline in the metadata.
+func (e *Engine) emitApprovalEvent(eventType ExecutionEventType, exec *execution.Execution, info PendingApproval, metadata map[string]interface{}) {
+	e.monitorMu.RLock()
+	monitor := e.monitor
+	e.monitorMu.RUnlock()
+
+	if monitor == nil {
+		return
+	}
+
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["message"] = info.Message
+	if info.Deadline != nil {
+		metadata["deadline"] = info.Deadline.Format(time.RFC3339)
+		metadata["default_action"] = info.DefaultAction
+	}
+	monitor.Emit(ExecutionEvent{
+		Type:        eventType,
+		Timestamp:   time.Now(),
+		ExecutionID: exec.ID,
+		NodeID:      info.NodeID,
+		Status:      execution.NodeStatusRunning,
+		Metadata:    metadata,
+	})
+}
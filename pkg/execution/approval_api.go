@@ -0,0 +1,69 @@
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/dshills/goflow/pkg/rbac"
+)
+
+// Approver lists and decides pending approvals. *Engine implements it.
+type Approver interface {
+	PendingApprovals() []PendingApproval
+	Decide(nodeID string, decision ApprovalDecision) error
+}
+
+// NewApprovalHandler returns the REST API for approval nodes:
+//
+//	GET  /approvals                 pending approvals, oldest first
+//	POST /approvals/{node}/approve  approve a pending approval
+//	POST /approvals/{node}/reject   reject a pending approval
+//
+// POST bodies are optional JSON objects with "by" and "comment"; behind
+// rbac.Require, "by" is the authenticated caller. Responses are JSON;
+// deciding an approval that is not pending answers 404.
+func NewApprovalHandler(approver Approver) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /approvals", func(w http.ResponseWriter, r *http.Request) {
+		writeApprovalJSON(w, http.StatusOK, approver.PendingApprovals())
+	})
+	mux.HandleFunc("POST /approvals/{node}/approve", func(w http.ResponseWriter, r *http.Request) {
+		decideApproval(w, r, approver, true)
+	})
+	mux.HandleFunc("POST /approvals/{node}/reject", func(w http.ResponseWriter, r *http.Request) {
+		decideApproval(w, r, approver, false)
+	})
+	return mux
+}
+
+// decideApproval handles an approve or reject request
+func decideApproval(w http.ResponseWriter, r *http.Request, approver Approver, approved bool) {
+	var decision ApprovalDecision
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&decision); err != nil && !errors.Is(err, io.EOF) {
+		writeApprovalJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
+		return
+	}
+	decision.Approved = approved
+	if principal, ok := rbac.PrincipalFrom(r.Context()); ok {
+		decision.By = principal.Name
+	}
+
+	nodeID := r.PathValue("node")
+	if err := approver.Decide(nodeID, decision); err != nil {
+		writeApprovalJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	writeApprovalJSON(w, http.StatusOK, map[string]interface{}{
+		"node_id":  nodeID,
+		"approved": approved,
+	})
+}
+
+// writeApprovalJSON writes value as a JSON response with status
+func writeApprovalJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value) // Error ignored: the client has gone away
+}
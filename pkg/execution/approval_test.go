@@ -0,0 +1,272 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/rbac"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const approvalWorkflowYAML = `
+version: "1.0"
+name: "approval-test"
+variables:
+  - name: "env"
+    type: "string"
+    default: "prod"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "review"
+    type: "approval"
+    message: "Deploy to ${env}?"
+    output: "decision"
+  - id: "end"
+    type: "end"
+    return: "${decision.by}"
+edges:
+  - from: "start"
+    to: "review"
+  - from: "review"
+    to: "end"
+`
+
+// waitForApproval waits until the engine has a pending approval
+func waitForApproval(t *testing.T, engine *Engine) PendingApproval {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if pending := engine.PendingApprovals(); len(pending) > 0 {
+			return pending[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no approval was requested")
+	return PendingApproval{}
+}
+
+func TestEngine_ApprovalDecisions(t *testing.T) {
+	wf, err := workflow.Parse([]byte(approvalWorkflowYAML))
+	require.NoError(t, err)
+
+	t.Run("approve", func(t *testing.T) {
+		engine := NewEngine()
+		defer engine.Close()
+
+		done := make(chan *execution.Execution, 1)
+		go func() {
+			exec, _ := engine.Execute(context.Background(), wf, nil)
+			done <- exec
+		}()
+
+		pending := waitForApproval(t, engine)
+		assert.Equal(t, types.NodeID("review"), pending.NodeID)
+		assert.Equal(t, "Deploy to prod?", pending.Message)
+		assert.Nil(t, pending.Deadline)
+
+		require.NoError(t, engine.Approve("review", "ada"))
+		exec := <-done
+		assert.Equal(t, execution.StatusCompleted, exec.Status)
+		assert.Equal(t, "ada", exec.ReturnValue)
+		assert.Empty(t, engine.PendingApprovals())
+		assert.Error(t, engine.Approve("review", "ada"), "the approval is no longer pending")
+	})
+
+	t.Run("reject fails the node", func(t *testing.T) {
+		engine := NewEngine()
+		defer engine.Close()
+
+		done := make(chan *execution.Execution, 1)
+		go func() {
+			exec, _ := engine.Execute(context.Background(), wf, nil)
+			done <- exec
+		}()
+
+		waitForApproval(t, engine)
+		require.NoError(t, engine.Decide("review", ApprovalDecision{By: "bob", Comment: "not today"}))
+		exec := <-done
+		assert.Equal(t, execution.StatusFailed, exec.Status)
+		require.NotNil(t, exec.Error)
+		assert.Contains(t, exec.Error.Message, "approval rejected by bob: not today")
+	})
+
+	t.Run("cancel while waiting", func(t *testing.T) {
+		engine := NewEngine()
+		defer engine.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan *execution.Execution, 1)
+		go func() {
+			exec, _ := engine.Execute(ctx, wf, nil)
+			done <- exec
+		}()
+
+		waitForApproval(t, engine)
+		cancel()
+		exec := <-done
+		assert.Equal(t, execution.StatusCancelled, exec.Status)
+		assert.Empty(t, engine.PendingApprovals())
+	})
+}
+
+func TestEngine_ApprovalTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultAction string
+		wantStatus    execution.Status
+	}{
+		{name: "rejects by default", wantStatus: execution.StatusFailed},
+		{name: "approve on timeout", defaultAction: "approve", wantStatus: execution.StatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := workflow.Parse([]byte(approvalWorkflowYAML))
+			require.NoError(t, err)
+			for _, node := range wf.Nodes {
+				if approval, ok := node.(*workflow.ApprovalNode); ok {
+					approval.Timeout = "50ms"
+					approval.DefaultAction = tt.defaultAction
+				}
+			}
+
+			engine := NewEngine()
+			defer engine.Close()
+
+			exec, _ := engine.Execute(context.Background(), wf, nil)
+			assert.Equal(t, tt.wantStatus, exec.Status)
+			decision, ok := exec.Context.GetVariable("decision")
+			require.True(t, ok)
+			assert.Equal(t, true, decision.(map[string]interface{})["timed_out"])
+		})
+	}
+}
+
+func TestEngine_DelayNode(t *testing.T) {
+	yaml := `
+version: "1.0"
+name: "delay-test"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "wait"
+    type: "delay"
+    duration: "50ms"
+  - id: "past"
+    type: "delay"
+    until: "2000-01-01T00:00:00Z"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "wait"
+  - from: "wait"
+    to: "past"
+  - from: "past"
+    to: "end"
+`
+	wf, err := workflow.Parse([]byte(yaml))
+	require.NoError(t, err)
+
+	engine := NewEngine()
+	defer engine.Close()
+
+	started := time.Now()
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+	assert.Equal(t, []types.NodeID{"start", "wait", "past", "end"}, executedNodes(exec))
+	assert.Equal(t, "0s", exec.NodeExecutions[2].Outputs["waited"], "a past timestamp does not wait")
+}
+
+func TestApprovalHandler(t *testing.T) {
+	wf, err := workflow.Parse([]byte(approvalWorkflowYAML))
+	require.NoError(t, err)
+
+	engine := NewEngine()
+	defer engine.Close()
+
+	done := make(chan *execution.Execution, 1)
+	go func() {
+		exec, _ := engine.Execute(context.Background(), wf, nil)
+		done <- exec
+	}()
+	waitForApproval(t, engine)
+
+	server := httptest.NewServer(NewApprovalHandler(engine))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/approvals")
+	require.NoError(t, err)
+	var pending []PendingApproval
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&pending))
+	_ = resp.Body.Close()
+	require.Len(t, pending, 1)
+	assert.Equal(t, types.NodeID("review"), pending[0].NodeID)
+
+	resp, err = http.Post(server.URL+"/approvals/missing/approve", "application/json", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/approvals/review/approve", "application/json", strings.NewReader(`{"by": "ci"}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	exec := <-done
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+	assert.Equal(t, "ci", exec.ReturnValue)
+}
+
+func TestApprovalHandler_RBAC(t *testing.T) {
+	wf, err := workflow.Parse([]byte(approvalWorkflowYAML))
+	require.NoError(t, err)
+
+	engine := NewEngine()
+	defer engine.Close()
+
+	done := make(chan *execution.Execution, 1)
+	go func() {
+		exec, _ := engine.Execute(context.Background(), wf, nil)
+		done <- exec
+	}()
+	waitForApproval(t, engine)
+
+	authenticator, err := rbac.NewTokenAuthenticator([]rbac.Token{
+		{Name: "dashboard", Role: rbac.RoleViewer, SHA256: rbac.HashToken("view-token")},
+		{Name: "release-bot", Role: rbac.RoleOperator, SHA256: rbac.HashToken("operate-token")},
+	})
+	require.NoError(t, err)
+	handler := rbac.Require(authenticator, rbac.ByMethod(rbac.View, rbac.OperateExecutions), NewApprovalHandler(engine))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	approve := func(token string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/approvals/review/approve", strings.NewReader(`{"by": "someone-else"}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusForbidden, approve("view-token"), "viewers cannot decide approvals")
+	assert.Len(t, engine.PendingApprovals(), 1)
+	assert.Equal(t, http.StatusOK, approve("operate-token"))
+
+	// The decision is recorded as the authenticated caller's, not the body's
+	exec := <-done
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+	assert.Equal(t, "release-bot", exec.ReturnValue)
+}
@@ -0,0 +1,169 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// executeBatchToolNode calls the node's tool once per collection element,
+// at most node.Concurrency() calls at a time. Results (and, with an error
+// variable, error messages) are stored in collection order. Without an
+// error variable the first failure cancels the calls still running and
+// fails the node.
+func (e *Engine) executeBatchToolNode(ctx context.Context, node *workflow.BatchToolNode, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
+	server, err := e.serverRegistry.Get(node.ServerID)
+	if err != nil {
+		return fmt.Errorf("server '%s' not found: %w", node.ServerID, err)
+	}
+
+	collection, exists := exec.Context.GetVariable(node.Collection)
+	if !exists {
+		return fmt.Errorf("collection variable '%s' not found", node.Collection)
+	}
+	items, err := convertToSlice(collection)
+	if err != nil {
+		return fmt.Errorf("collection variable '%s' is not iterable: %w", node.Collection, err)
+	}
+
+	// Resolve every call's parameters up front, binding the item in a copy
+	// of the context so it does not leak into the workflow's variables
+	itemCtx, err := execution.NewExecutionContext(exec.Context.GetVariableSnapshot())
+	if err != nil {
+		return fmt.Errorf("failed to create item context: %w", err)
+	}
+	calls := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		if err := itemCtx.SetVariable(node.ItemVariable, item); err != nil {
+			return fmt.Errorf("failed to set item variable: %w", err)
+		}
+		params := make(map[string]interface{}, len(node.Parameters))
+		for key, value := range node.Parameters {
+			substituted, err := e.substituteVariables(value, itemCtx)
+			if err != nil {
+				return fmt.Errorf("item %d: failed to substitute variables in parameter '%s': %w", i, key, err)
+			}
+			params[key] = substituted
+		}
+		calls[i] = params
+	}
+
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]interface{}, len(items))
+	callErrs := make([]error, len(items))
+	sem := make(chan struct{}, node.Concurrency())
+	var wg sync.WaitGroup
+	for i, params := range calls {
+		select {
+		case sem <- struct{}{}:
+		case <-callCtx.Done():
+		}
+		if callCtx.Err() != nil {
+			callErrs[i] = callCtx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, params map[string]interface{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result, err := server.InvokeToolContext(callCtx, node.ToolName, params)
+			if err == nil {
+				err = e.decodeToolBlobs(result)
+			}
+			if err != nil {
+				callErrs[i] = err
+				if node.ErrorVariable == "" {
+					cancel()
+				}
+				return
+			}
+			results[i] = result
+		}(i, params)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	failed := 0
+	errs := make([]interface{}, len(items))
+	for i, callErr := range callErrs {
+		if callErr == nil {
+			continue
+		}
+		failed++
+		errs[i] = callErr.Error()
+	}
+
+	nodeExec.Inputs = map[string]interface{}{
+		"collection": node.Collection,
+		"items":      len(items),
+		"calls":      calls,
+	}
+
+	if failed > 0 && node.ErrorVariable == "" {
+		index := firstToolFailure(callErrs)
+		err := callErrs[index]
+		return &MCPToolError{
+			ServerID:    node.ServerID,
+			ToolName:    node.ToolName,
+			Message:     fmt.Sprintf("tool invocation failed for item %d: %v", index, err),
+			Recoverable: strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "connection"),
+			Context: map[string]interface{}{
+				"parameters": calls[index],
+				"item":       items[index],
+			},
+		}
+	}
+
+	outputs := map[string]interface{}{node.OutputVariable: results}
+	if node.ErrorVariable != "" {
+		outputs[node.ErrorVariable] = errs
+	}
+	for _, variable := range []string{node.OutputVariable, node.ErrorVariable} {
+		if variable == "" {
+			continue
+		}
+		if err := exec.Context.SetVariableWithNode(variable, outputs[variable], nodeExec.ID); err != nil {
+			return fmt.Errorf("failed to set output variable '%s': %w", variable, err)
+		}
+		if e.logger != nil {
+			snapshots := exec.Context.GetVariableHistory()
+			if len(snapshots) > 0 {
+				e.logger.LogVariableChange(&snapshots[len(snapshots)-1])
+			}
+		}
+	}
+
+	outputs["succeeded"] = len(items) - failed
+	outputs["failed"] = failed
+	nodeExec.Outputs = outputs
+	return nil
+}
+
+// firstToolFailure returns the index of the call whose error caused the
+// batch to fail: the first one that was not cancelled because of another
+// call's failure
+func firstToolFailure(errs []error) int {
+	first := -1
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if !errors.Is(err, context.Canceled) {
+			return i
+		}
+	}
+	return first
+}
@@ -0,0 +1,150 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchClient echoes each call's id parameter, failing for "bad", and
+// records how many calls ran at once
+type batchClient struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (c *batchClient) Connect(ctx context.Context) error                       { return nil }
+func (c *batchClient) Close() error                                            { return nil }
+func (c *batchClient) IsConnected() bool                                       { return true }
+func (c *batchClient) ListTools(ctx context.Context) ([]mcpserver.Tool, error) { return nil, nil }
+func (c *batchClient) Ping(ctx context.Context) error                          { return nil }
+
+func (c *batchClient) CallTool(ctx context.Context, toolName string, params map[string]interface{}) (map[string]interface{}, error) {
+	c.mu.Lock()
+	c.running++
+	if c.running > c.peak {
+		c.peak = c.running
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.running--
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(10 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if params["id"] == "bad" {
+		return nil, errors.New("not found")
+	}
+	return map[string]interface{}{"id": params["id"]}, nil
+}
+
+func newBatchEngine(t *testing.T) (*Engine, *batchClient) {
+	t.Helper()
+	engine := NewEngine()
+	t.Cleanup(func() { _ = engine.Close() })
+
+	server, err := mcpserver.NewMCPServer("api", "mock", nil, mcpserver.TransportStdio)
+	require.NoError(t, err)
+	_ = server.Connect()
+	_ = server.CompleteConnection()
+	server.Tools = []mcpserver.Tool{{Name: "get_user"}}
+	client := &batchClient{}
+	server.SetClient(client)
+	require.NoError(t, engine.serverRegistry.Register(server))
+	return engine, client
+}
+
+func runBatch(t *testing.T, engine *Engine, node *workflow.BatchToolNode, ids []interface{}) (*execution.Execution, *execution.NodeExecution, error) {
+	t.Helper()
+	exec, err := execution.NewExecution("batch-workflow", "1.0", map[string]interface{}{"ids": ids})
+	require.NoError(t, err)
+	nodeExec := execution.NewNodeExecution(exec.ID, types.NodeID(node.ID), "batch_tool")
+	err = engine.executeBatchToolNode(context.Background(), node, exec, nodeExec)
+	return exec, nodeExec, err
+}
+
+func TestBatchToolNode_Results(t *testing.T) {
+	engine, client := newBatchEngine(t)
+	node := &workflow.BatchToolNode{
+		ID:             "fetch",
+		ServerID:       "api",
+		ToolName:       "get_user",
+		Collection:     "ids",
+		ItemVariable:   "id",
+		Parameters:     map[string]string{"id": "${id}"},
+		MaxConcurrency: 2,
+		OutputVariable: "users",
+	}
+	ids := []interface{}{"a", "b", "c", "d", "e"}
+
+	exec, nodeExec, err := runBatch(t, engine, node, ids)
+	require.NoError(t, err)
+
+	users, _ := exec.Context.GetVariable("users")
+	results := users.([]interface{})
+	require.Len(t, results, len(ids))
+	for i, id := range ids {
+		assert.Equal(t, id, results[i].(map[string]interface{})["id"], "results keep collection order")
+	}
+	assert.LessOrEqual(t, client.peak, 2, "no more than max_concurrency calls at once")
+	assert.Equal(t, 5, nodeExec.Outputs["succeeded"])
+
+	_, leaked := exec.Context.GetVariable("id")
+	assert.False(t, leaked, "the item variable stays local to the calls")
+}
+
+func TestBatchToolNode_Errors(t *testing.T) {
+	node := &workflow.BatchToolNode{
+		ID:             "fetch",
+		ServerID:       "api",
+		ToolName:       "get_user",
+		Collection:     "ids",
+		ItemVariable:   "id",
+		Parameters:     map[string]string{"id": "${id}"},
+		OutputVariable: "users",
+	}
+	ids := []interface{}{"a", "bad", "c"}
+
+	t.Run("fail fast", func(t *testing.T) {
+		engine, _ := newBatchEngine(t)
+		_, _, err := runBatch(t, engine, node, ids)
+		var toolErr *MCPToolError
+		require.ErrorAs(t, err, &toolErr)
+		assert.Contains(t, toolErr.Message, "item 1")
+		assert.Equal(t, "bad", toolErr.Context["item"])
+	})
+
+	t.Run("collected", func(t *testing.T) {
+		engine, _ := newBatchEngine(t)
+		collecting := *node
+		collecting.ErrorVariable = "failures"
+		exec, nodeExec, err := runBatch(t, engine, &collecting, ids)
+		require.NoError(t, err)
+
+		users, _ := exec.Context.GetVariable("users")
+		failures, _ := exec.Context.GetVariable("failures")
+		results := users.([]interface{})
+		errs := failures.([]interface{})
+		assert.NotNil(t, results[0])
+		assert.Nil(t, results[1])
+		assert.Nil(t, errs[0])
+		assert.Contains(t, errs[1], "not found")
+		assert.Equal(t, 2, nodeExec.Outputs["succeeded"])
+		assert.Equal(t, 1, nodeExec.Outputs["failed"])
+	})
+}
@@ -0,0 +1,224 @@
+package execution
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/validation"
+)
+
+// BlobLimits control how binary tool content is held in variables. Zero
+// fields use the defaults.
+type BlobLimits struct {
+	// MaxBytes rejects blobs larger than this; the tool call fails.
+	MaxBytes int64
+	// InlineBytes keeps blobs up to this size in memory; larger ones are
+	// spilled to a temp file.
+	InlineBytes int64
+	// Dir is where the engine creates its blob directory; empty uses the
+	// system temp directory.
+	Dir string
+}
+
+// Default blob limits
+const (
+	DefaultMaxBlobBytes    = 64 << 20
+	DefaultInlineBlobBytes = 256 << 10
+)
+
+// WithBlobLimits configures how binary tool content is stored.
+func WithBlobLimits(limits BlobLimits) EngineOption {
+	return func(e *Engine) {
+		e.blobLimits = limits
+	}
+}
+
+// blobStore creates the blobs of an engine's executions and holds the files
+// of spilled and handed-off blobs in one directory, removed when the engine
+// is closed.
+type blobStore struct {
+	limits BlobLimits
+
+	mu        sync.Mutex
+	dir       string
+	validator *validation.PathValidator
+	handoffs  map[*execution.Blob]string
+}
+
+func newBlobStore(limits BlobLimits) *blobStore {
+	if limits.MaxBytes <= 0 {
+		limits.MaxBytes = DefaultMaxBlobBytes
+	}
+	if limits.InlineBytes <= 0 {
+		limits.InlineBytes = DefaultInlineBlobBytes
+	}
+	return &blobStore{limits: limits, handoffs: make(map[*execution.Blob]string)}
+}
+
+// blobs returns the engine's blob store, creating it on first use
+func (e *Engine) blobs() *blobStore {
+	e.blobsOnce.Do(func() {
+		e.blobStore = newBlobStore(e.blobLimits)
+	})
+	return e.blobStore
+}
+
+// decode creates a blob from base64 data, spilling it to a file when it is
+// larger than the inline limit
+func (s *blobStore) decode(encoded, mimeType string) (*execution.Blob, error) {
+	if size := int64(base64.StdEncoding.DecodedLen(len(encoded))); size > s.limits.MaxBytes+2 {
+		return nil, fmt.Errorf("blob of about %d bytes exceeds the limit of %d bytes", size, s.limits.MaxBytes)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 blob data: %w", err)
+	}
+	if int64(len(data)) > s.limits.MaxBytes {
+		return nil, fmt.Errorf("blob of %d bytes exceeds the limit of %d bytes", len(data), s.limits.MaxBytes)
+	}
+	if int64(len(data)) <= s.limits.InlineBytes {
+		return execution.NewBlob(data, mimeType), nil
+	}
+
+	path, err := s.writeFile(data, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	return execution.NewFileBlob(path, mimeType, int64(len(data))), nil
+}
+
+// handoff returns a file holding the blob's data for downstream nodes.
+// Spilled blobs already have one; blobs held in memory are written to the
+// blob directory once.
+func (s *blobStore) handoff(blob *execution.Blob) (string, error) {
+	if path := blob.Path(); path != "" {
+		return s.contain(path)
+	}
+
+	s.mu.Lock()
+	path, exists := s.handoffs[blob]
+	s.mu.Unlock()
+	if exists {
+		return path, nil
+	}
+
+	data, err := blob.Bytes()
+	if err != nil {
+		return "", err
+	}
+	path, err = s.writeFile(data, blob.MimeType)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.handoffs[blob] = path
+	s.mu.Unlock()
+	return path, nil
+}
+
+// writeFile stores data in a new file in the blob directory, named with a
+// random ID and an extension for its MIME type
+func (s *blobStore) writeFile(data []byte, mimeType string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(s.limits.Dir, "goflow-blobs-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create blob directory: %w", err)
+		}
+		// Resolve symlinks (e.g. a symlinked temp directory) so the
+		// validator's paths stay relative to dir
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("invalid blob directory: %w", err)
+		}
+		validator, err := validation.NewPathValidator(resolved)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("invalid blob directory: %w", err)
+		}
+		s.dir = resolved
+		s.validator = validator
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to name blob file: %w", err)
+	}
+	name := hex.EncodeToString(id)
+	if extensions, _ := mime.ExtensionsByType(mimeType); len(extensions) > 0 {
+		name += extensions[0]
+	}
+	path, err := s.validator.Validate(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid blob file: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write blob: %w", err)
+	}
+	return path, nil
+}
+
+// contain checks that a spilled blob's file is still inside the blob
+// directory before its path is handed to another node
+func (s *blobStore) contain(path string) (string, error) {
+	s.mu.Lock()
+	dir, validator := s.dir, s.validator
+	s.mu.Unlock()
+	if validator == nil {
+		return "", fmt.Errorf("blob file %s is not in the blob directory", path)
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return "", fmt.Errorf("blob file %s is not in the blob directory", path)
+	}
+	valid, err := validator.Validate(rel)
+	if err != nil {
+		return "", fmt.Errorf("blob file %s: %w", path, err)
+	}
+	return valid, nil
+}
+
+// Close removes the blob directory and every file in it
+func (s *blobStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.dir)
+	s.dir = ""
+	s.validator = nil
+	s.handoffs = make(map[*execution.Blob]string)
+	return err
+}
+
+// blobField returns a field of a blob for variable references: path (the
+// blob's file, written on first use), mime_type, size or data (base64).
+func (e *Engine) blobField(blob *execution.Blob, field string) (interface{}, error) {
+	switch field {
+	case "path":
+		return e.blobs().handoff(blob)
+	case "mime_type":
+		return blob.MimeType, nil
+	case "size":
+		return blob.Size, nil
+	case "data":
+		data, err := blob.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	default:
+		return nil, fmt.Errorf("blobs have no field '%s' (path, mime_type, size, data)", field)
+	}
+}
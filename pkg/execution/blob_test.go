@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// imageClient returns its image as base64 image content
+type imageClient struct {
+	image []byte
+}
+
+func (c *imageClient) Connect(ctx context.Context) error                       { return nil }
+func (c *imageClient) Close() error                                            { return nil }
+func (c *imageClient) IsConnected() bool                                       { return true }
+func (c *imageClient) ListTools(ctx context.Context) ([]mcpserver.Tool, error) { return nil, nil }
+func (c *imageClient) Ping(ctx context.Context) error                          { return nil }
+
+func (c *imageClient) CallTool(ctx context.Context, toolName string, params map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"content": []interface{}{
+			map[string]interface{}{"type": "text", "text": "rendered"},
+			map[string]interface{}{"type": "image", "mimeType": "image/png", "data": base64.StdEncoding.EncodeToString(c.image)},
+		},
+	}, nil
+}
+
+func TestBlobStore_Limits(t *testing.T) {
+	store := newBlobStore(BlobLimits{MaxBytes: 64, InlineBytes: 16, Dir: t.TempDir()})
+	defer func() { _ = store.Close() }()
+	encode := func(n int) string { return base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", n))) }
+
+	small, err := store.decode(encode(10), "image/png")
+	require.NoError(t, err)
+	assert.Empty(t, small.Path(), "small blobs stay in memory")
+	assert.Equal(t, int64(10), small.Size)
+
+	large, err := store.decode(encode(40), "image/png")
+	require.NoError(t, err)
+	require.NotEmpty(t, large.Path(), "large blobs spill to a file")
+	assert.Equal(t, ".png", filepath.Ext(large.Path()))
+	data, err := large.Bytes()
+	require.NoError(t, err)
+	assert.Len(t, data, 40)
+
+	_, err = store.decode(encode(100), "image/png")
+	assert.ErrorContains(t, err, "exceeds the limit")
+	_, err = store.decode("not base64!", "image/png")
+	assert.ErrorContains(t, err, "invalid base64")
+
+	// Spilled blobs marshal without their data
+	encoded, err := json.Marshal(large)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"path"`)
+	assert.NotContains(t, string(encoded), `"data"`)
+
+	require.NoError(t, store.Close())
+	_, err = os.Stat(large.Path())
+	assert.True(t, os.IsNotExist(err), "closing removes blob files")
+}
+
+func TestBlobStore_Handoff(t *testing.T) {
+	dir := t.TempDir()
+	store := newBlobStore(BlobLimits{Dir: dir})
+	defer func() { _ = store.Close() }()
+
+	blob := execution.NewBlob([]byte("png bytes"), "image/png")
+	path, err := store.handoff(blob)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "png bytes", string(data))
+
+	again, err := store.handoff(blob)
+	require.NoError(t, err)
+	assert.Equal(t, path, again, "a blob is written once")
+
+	// Files outside the blob directory are not handed off
+	outside := filepath.Join(dir, "other.png")
+	require.NoError(t, os.WriteFile(outside, []byte("x"), 0o600))
+	_, err = store.handoff(execution.NewFileBlob(outside, "image/png", 1))
+	assert.Error(t, err)
+}
+
+func TestEngine_ToolBlobContent(t *testing.T) {
+	engine := NewEngine(WithBlobLimits(BlobLimits{Dir: t.TempDir()}))
+	defer engine.Close()
+
+	server, err := mcpserver.NewMCPServer("charts", "mock", nil, mcpserver.TransportStdio)
+	require.NoError(t, err)
+	_ = server.Connect()
+	_ = server.CompleteConnection()
+	server.Tools = []mcpserver.Tool{{Name: "render"}}
+	server.SetClient(&imageClient{image: []byte("\x89PNG chart")})
+	require.NoError(t, engine.serverRegistry.Register(server))
+
+	exec, err := execution.NewExecution("blob-workflow", "1.0", nil)
+	require.NoError(t, err)
+	node := &workflow.MCPToolNode{
+		ID:             "render",
+		ServerID:       "charts",
+		ToolName:       "render",
+		OutputVariable: "result",
+		ContentOutputs: map[string]string{"image": "charts"},
+	}
+	nodeExec := execution.NewNodeExecution(exec.ID, "render", "mcp_tool")
+	require.NoError(t, engine.executeMCPToolNode(context.Background(), node, nil, exec, nodeExec))
+
+	charts, _ := exec.Context.GetVariable("charts")
+	list := charts.([]interface{})
+	require.Len(t, list, 1)
+	blob, ok := list[0].(*execution.Blob)
+	require.True(t, ok, "image content is routed as a blob, got %T", list[0])
+	assert.Equal(t, "image/png", blob.MimeType)
+
+	// Downstream nodes reference the blob's fields
+	path, err := engine.substituteVariables("${charts.0.path}", exec.Context)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "\x89PNG chart", string(data))
+
+	described, err := engine.substituteVariables("${charts.0} (${charts.0.size})", exec.Context)
+	require.NoError(t, err)
+	assert.Equal(t, "blob(image/png, 10 bytes) (10)", described)
+
+	_, err = engine.substituteVariables("${charts.1.path}", exec.Context)
+	assert.ErrorContains(t, err, "out of range")
+}
@@ -4,11 +4,14 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
 )
 
 // CacheEntry represents a cached node execution result
@@ -20,6 +23,7 @@ type CacheEntry struct {
 	CachedAt    time.Time
 	AccessCount int64
 	LastAccess  time.Time
+	TTL         time.Duration // Lifetime of this entry (0 = the cache's TTL)
 }
 
 // CacheStats tracks cache performance metrics
@@ -97,8 +101,9 @@ func (c *ExecutionCache) Get(nodeID types.NodeID, nodeType string, inputs map[st
 		return nil, false
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	// Write lock: a lookup updates the statistics and the entry
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	// Compute input hash
 	inputsHash, err := c.hashInputs(inputs)
@@ -117,7 +122,7 @@ func (c *ExecutionCache) Get(nodeID types.NodeID, nodeType string, inputs map[st
 	}
 
 	// Check if entry has expired
-	if time.Since(entry.CachedAt) > c.ttl {
+	if c.expired(entry, time.Now()) {
 		c.incrementMisses()
 		return nil, false
 	}
@@ -136,6 +141,7 @@ func (c *ExecutionCache) Get(nodeID types.NodeID, nodeType string, inputs map[st
 		CachedAt:    entry.CachedAt,
 		AccessCount: entry.AccessCount,
 		LastAccess:  entry.LastAccess,
+		TTL:         entry.TTL,
 	}
 
 	return entryCopy, true
@@ -143,6 +149,12 @@ func (c *ExecutionCache) Get(nodeID types.NodeID, nodeType string, inputs map[st
 
 // Set stores a node execution result in the cache
 func (c *ExecutionCache) Set(nodeID types.NodeID, nodeType string, inputs map[string]interface{}, outputs map[string]interface{}) error {
+	return c.SetWithTTL(nodeID, nodeType, inputs, outputs, 0)
+}
+
+// SetWithTTL stores a node execution result that expires after ttl instead
+// of the cache's TTL (0 = the cache's TTL)
+func (c *ExecutionCache) SetWithTTL(nodeID types.NodeID, nodeType string, inputs map[string]interface{}, outputs map[string]interface{}, ttl time.Duration) error {
 	if !c.IsEnabled() {
 		return nil
 	}
@@ -173,6 +185,7 @@ func (c *ExecutionCache) Set(nodeID types.NodeID, nodeType string, inputs map[st
 		CachedAt:    time.Now(),
 		AccessCount: 0,
 		LastAccess:  time.Now(),
+		TTL:         ttl,
 	}
 
 	c.entries[key] = entry
@@ -268,7 +281,7 @@ func (c *ExecutionCache) CleanExpired() int {
 	removed := 0
 
 	for key, entry := range c.entries {
-		if now.Sub(entry.CachedAt) > c.ttl {
+		if c.expired(entry, now) {
 			delete(c.entries, key)
 			removed++
 		}
@@ -305,10 +318,10 @@ func (c *ExecutionCache) ShouldCache(nodeExec *execution.NodeExecution) bool {
 		return false
 	}
 
-	// Cache MCP tool calls, transforms, and conditions
+	// Cache MCP tool calls, transforms, conditions and switches
 	// These are deterministic and safe to cache
 	switch nodeExec.NodeType {
-	case "mcp_tool", "transform", "condition":
+	case "mcp_tool", "transform", "condition", "switch":
 		return true
 	default:
 		// Don't cache parallel or loop nodes (they're complex)
@@ -316,6 +329,15 @@ func (c *ExecutionCache) ShouldCache(nodeExec *execution.NodeExecution) bool {
 	}
 }
 
+// expired reports whether an entry has outlived its TTL at now
+func (c *ExecutionCache) expired(entry *CacheEntry, now time.Time) bool {
+	ttl := entry.TTL
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+	return now.Sub(entry.CachedAt) > ttl
+}
+
 // buildKey creates a cache key from node ID and input hash
 func (c *ExecutionCache) buildKey(nodeID types.NodeID, inputsHash string) string {
 	return fmt.Sprintf("%s:%s", nodeID, inputsHash)
@@ -386,3 +408,84 @@ func (c *ExecutionCache) incrementMisses() {
 	c.stats.Misses++
 	c.stats.LastUpdated = time.Now()
 }
+
+var (
+	sharedToolCache     *ExecutionCache
+	sharedToolCacheOnce sync.Once
+)
+
+// SharedToolCache returns the process-wide tool result cache. Engines given
+// it with WithToolCache, as those of goflow run, the TUI and the shared
+// dispatcher are, serve cached results to later executions of any of them.
+func SharedToolCache() *ExecutionCache {
+	sharedToolCacheOnce.Do(func() {
+		sharedToolCache = NewExecutionCache()
+	})
+	return sharedToolCache
+}
+
+// WithToolCache caches the results of mcp_tool nodes with a cache_ttl in
+// cache, which other engines may share. By default each engine has its own.
+func WithToolCache(cache *ExecutionCache) EngineOption {
+	return func(e *Engine) {
+		if cache != nil {
+			e.toolCache = cache
+		}
+	}
+}
+
+// toolCacheID identifies a server's tool in the tool result cache, where
+// entries are keyed by it and the hash of the call's arguments. Engines
+// running other workflows may share the cache, so the server is identified
+// by its command and arguments (or URL) as well as its ID.
+func toolCacheID(server *mcpserver.MCPServer, toolName string) types.NodeID {
+	return types.NodeID(fmt.Sprintf("%s/%s %q/%s", server.ID, server.Command, server.Args, toolName))
+}
+
+// cachedToolResult returns the cached result of calling the node's tool on
+// server with params, if the node caches results and one has not expired
+func (e *Engine) cachedToolResult(node *workflow.MCPToolNode, server *mcpserver.MCPServer, params map[string]interface{}) (interface{}, bool) {
+	if e.toolCache == nil || node.CacheDuration() == 0 {
+		return nil, false
+	}
+	entry, ok := e.toolCache.Get(toolCacheID(server, node.ToolName), node.Type(), params)
+	if !ok {
+		return nil, false
+	}
+	return entry.Outputs["result"], true
+}
+
+// cacheToolResult caches a tool result for the node's cache TTL
+func (e *Engine) cacheToolResult(node *workflow.MCPToolNode, server *mcpserver.MCPServer, params map[string]interface{}, result interface{}) {
+	ttl := node.CacheDuration()
+	if e.toolCache == nil || ttl == 0 {
+		return
+	}
+	// Results that cannot be hashed or copied are not cached
+	_ = e.toolCache.SetWithTTL(toolCacheID(server, node.ToolName), node.Type(), params, map[string]interface{}{"result": result}, ttl)
+}
+
+// ToolCacheStats returns the hits, misses and size of the engine's tool
+// result cache, which may be shared with other engines
+func (e *Engine) ToolCacheStats() CacheStats {
+	if e.toolCache == nil {
+		return CacheStats{}
+	}
+	return e.toolCache.GetStats()
+}
+
+// WriteToolCacheMetrics writes the tool result cache's statistics in the
+// Prometheus text exposition format, as a collector for the metrics
+// endpoint
+func (e *Engine) WriteToolCacheMetrics(w io.Writer) {
+	stats := e.ToolCacheStats()
+	_, _ = fmt.Fprintf(w, "# HELP goflow_tool_cache_hits_total Tool calls answered from the tool result cache.\n"+
+		"# TYPE goflow_tool_cache_hits_total counter\n"+
+		"goflow_tool_cache_hits_total %d\n", stats.Hits)
+	_, _ = fmt.Fprintf(w, "# HELP goflow_tool_cache_misses_total Cacheable tool calls the tool result cache could not answer.\n"+
+		"# TYPE goflow_tool_cache_misses_total counter\n"+
+		"goflow_tool_cache_misses_total %d\n", stats.Misses)
+	_, _ = fmt.Fprintf(w, "# HELP goflow_tool_cache_entries Results held in the tool result cache.\n"+
+		"# TYPE goflow_tool_cache_entries gauge\n"+
+		"goflow_tool_cache_entries %d\n", stats.TotalSize)
+}
@@ -1,11 +1,16 @@
 package execution
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -442,3 +447,123 @@ func TestCacheDeepCopy(t *testing.T) {
 		assert.Equal(t, "a", slice[0])
 	}
 }
+
+func TestCacheEntryTTL(t *testing.T) {
+	cache := NewExecutionCacheWithConfig(10, time.Hour)
+	inputs := map[string]interface{}{"path": "/tmp/a"}
+
+	require.NoError(t, cache.SetWithTTL("short", "mcp_tool", inputs, map[string]interface{}{"result": 1}, 10*time.Millisecond))
+	require.NoError(t, cache.Set("long", "mcp_tool", inputs, map[string]interface{}{"result": 2}))
+
+	time.Sleep(20 * time.Millisecond)
+	_, found := cache.Get("short", "mcp_tool", inputs)
+	assert.False(t, found, "entry should expire after its own TTL")
+	_, found = cache.Get("long", "mcp_tool", inputs)
+	assert.True(t, found, "entry without a TTL uses the cache's")
+	assert.Equal(t, 1, cache.CleanExpired())
+}
+
+func TestEngine_ToolResultCache(t *testing.T) {
+	engine := NewEngine()
+	defer engine.Close()
+
+	server, err := mcpserver.NewMCPServer("files", "mock", nil, mcpserver.TransportStdio)
+	require.NoError(t, err)
+	_ = server.Connect()
+	_ = server.CompleteConnection()
+	server.Tools = []mcpserver.Tool{{Name: "read_file"}}
+	require.NoError(t, engine.serverRegistry.Register(server))
+
+	exec, err := execution.NewExecution("cache-workflow", "1.0", map[string]interface{}{"path": "/tmp/a"})
+	require.NoError(t, err)
+	node := &workflow.MCPToolNode{
+		ID:             "read",
+		ServerID:       "files",
+		ToolName:       "read_file",
+		Parameters:     map[string]string{"path": "${path}"},
+		OutputVariable: "content",
+		CacheTTL:       "5m",
+	}
+	run := func() *execution.NodeExecution {
+		nodeExec := execution.NewNodeExecution(exec.ID, "read", "mcp_tool")
+		require.NoError(t, engine.executeMCPToolNode(context.Background(), node, nil, exec, nodeExec))
+		return nodeExec
+	}
+
+	first := run()
+	assert.False(t, first.CacheHit)
+	second := run()
+	assert.True(t, second.CacheHit, "same server, tool and arguments should hit the cache")
+	assert.Equal(t, first.Outputs["content"], second.Outputs["content"])
+	value, _ := exec.Context.GetVariable("content")
+	assert.Equal(t, "mock result", value.(map[string]interface{})["result"])
+
+	// Different arguments miss
+	require.NoError(t, exec.Context.SetVariable("path", "/tmp/b"))
+	assert.False(t, run().CacheHit)
+
+	// Nodes without a TTL always call the tool
+	node.CacheTTL = ""
+	assert.False(t, run().CacheHit)
+
+	stats := engine.ToolCacheStats()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.Equal(t, int64(2), stats.TotalSize)
+
+	// The metrics endpoint reports the same
+	rec := httptest.NewRecorder()
+	mcpserver.NewMetricsHandler(engine.Servers(), engine.WriteToolCacheMetrics).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `goflow_mcp_calls_total{server="files",result="success"}`)
+	assert.Contains(t, body, "# TYPE goflow_tool_cache_hits_total counter\ngoflow_tool_cache_hits_total 1\n")
+	assert.Contains(t, body, "# TYPE goflow_tool_cache_misses_total counter\ngoflow_tool_cache_misses_total 2\n")
+	assert.Contains(t, body, "# TYPE goflow_tool_cache_entries gauge\ngoflow_tool_cache_entries 2\n")
+}
+
+func TestEngine_SharedToolCache(t *testing.T) {
+	cache := NewExecutionCache()
+	newEngine := func(command string) *Engine {
+		engine := NewEngine(WithToolCache(cache))
+		server, err := mcpserver.NewMCPServer("files", command, nil, mcpserver.TransportStdio)
+		require.NoError(t, err)
+		_ = server.Connect()
+		_ = server.CompleteConnection()
+		server.Tools = []mcpserver.Tool{{Name: "read_file"}}
+		require.NoError(t, engine.serverRegistry.Register(server))
+		return engine
+	}
+	node := &workflow.MCPToolNode{
+		ID:             "read",
+		ServerID:       "files",
+		ToolName:       "read_file",
+		Parameters:     map[string]string{"path": "/tmp/a"},
+		OutputVariable: "content",
+		CacheTTL:       "5m",
+	}
+	run := func(engine *Engine) *execution.NodeExecution {
+		exec, err := execution.NewExecution("cache-workflow", "1.0", nil)
+		require.NoError(t, err)
+		nodeExec := execution.NewNodeExecution(exec.ID, "read", "mcp_tool")
+		require.NoError(t, engine.executeMCPToolNode(context.Background(), node, nil, exec, nodeExec))
+		return nodeExec
+	}
+
+	// Each run, like goflow run and the TUI, has an engine of its own
+	first := newEngine("mock")
+	defer first.Close()
+	assert.False(t, run(first).CacheHit)
+	second := newEngine("mock")
+	defer second.Close()
+	assert.True(t, run(second).CacheHit, "a later execution on another engine should hit the shared cache")
+
+	// A server of the same ID running another command is another server
+	other := newEngine("other-mock")
+	defer other.Close()
+	assert.False(t, run(other).CacheHit)
+
+	assert.Equal(t, int64(1), second.ToolCacheStats().Hits)
+	assert.Same(t, SharedToolCache(), SharedToolCache())
+}
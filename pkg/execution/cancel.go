@@ -0,0 +1,56 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// defaultCancelReason is recorded when an execution's context is cancelled
+// without a reason, e.g. by the caller of Execute.
+const defaultCancelReason = "context cancelled"
+
+// CancellationError is the cause of an execution's context when it is
+// cancelled through Engine.Cancel. MCP servers that support cancellation
+// receive it as the reason for abandoned tool calls.
+type CancellationError struct {
+	Reason string
+}
+
+func (e *CancellationError) Error() string {
+	return "execution cancelled: " + e.Reason
+}
+
+// Cancel stops the running execution: its context is cancelled, so running
+// nodes and in-flight MCP tool calls are abandoned, no further nodes start,
+// and the execution is recorded as cancelled with reason. A paused execution
+// is cancelled without resuming.
+func (e *Engine) Cancel(reason string) error {
+	if reason == "" {
+		reason = "cancelled by user"
+	}
+
+	e.monitorMu.RLock()
+	cancel := e.cancelRun
+	e.monitorMu.RUnlock()
+	if cancel == nil {
+		return fmt.Errorf("no execution is running")
+	}
+
+	cancel(&CancellationError{Reason: reason})
+	return nil
+}
+
+// cancelReason returns why ctx was cancelled: the reason given to Cancel,
+// another cause set by the caller, or defaultCancelReason.
+func cancelReason(ctx context.Context) string {
+	cause := context.Cause(ctx)
+	var cancelErr *CancellationError
+	switch {
+	case errors.As(cause, &cancelErr):
+		return cancelErr.Reason
+	case cause == nil || errors.Is(cause, context.Canceled):
+		return defaultCancelReason
+	}
+	return cause.Error()
+}
@@ -0,0 +1,86 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Cancel(t *testing.T) {
+	yaml := `
+version: "1.0"
+name: "test-workflow"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "node1"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "node1"
+  - from: "node1"
+    to: "end"
+`
+	wf, err := workflow.Parse([]byte(yaml))
+	require.NoError(t, err)
+
+	var cancelled *ExecutionEvent
+	engine := NewEngine(WithEventHandler(func(event ExecutionEvent) {
+		if event.Type == EventExecutionCancelled {
+			cancelled = &event
+		}
+	}))
+	defer engine.Close()
+
+	assert.Error(t, engine.Cancel("nothing to stop"), "no execution is running")
+
+	// Hold the execution at its first node so it is still running
+	engine.Pause()
+	type result struct {
+		exec *execution.Execution
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		exec, err := engine.Execute(context.Background(), wf, nil)
+		done <- result{exec, err}
+	}()
+
+	require.Eventually(t, func() bool {
+		return engine.Cancel("stopped by operator") == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var res result
+	select {
+	case res = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution did not stop")
+	}
+	require.Error(t, res.err)
+	require.NotNil(t, res.exec)
+	assert.Equal(t, execution.StatusCancelled, res.exec.Status)
+	assert.Equal(t, "stopped by operator", res.exec.CancelReason())
+	assert.Empty(t, res.exec.NodeExecutions, "no node should start after cancellation")
+
+	require.NotNil(t, cancelled)
+	assert.Equal(t, "stopped by operator", cancelled.Metadata["reason"])
+
+	assert.Error(t, engine.Cancel("again"), "the execution has already finished")
+}
+
+func TestCancelReason(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, defaultCancelReason, cancelReason(ctx))
+
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(&CancellationError{Reason: "deploy in progress"})
+	assert.Equal(t, "deploy in progress", cancelReason(ctx))
+}
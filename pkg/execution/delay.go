@@ -0,0 +1,60 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// executeDelayNode waits for the node's duration or until its timestamp.
+// A timestamp in the past completes immediately. Cancelling the execution
+// ends the wait.
+func (e *Engine) executeDelayNode(ctx context.Context, node *workflow.DelayNode, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
+	var wait time.Duration
+	inputs := map[string]interface{}{}
+
+	if node.Until != "" {
+		until, err := e.substituteVariables(node.Until, exec.Context)
+		if err != nil {
+			return fmt.Errorf("failed to substitute variables in until: %w", err)
+		}
+		deadline, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return fmt.Errorf("invalid until timestamp %q (expected RFC 3339)", until)
+		}
+		inputs["until"] = deadline.Format(time.RFC3339)
+		wait = time.Until(deadline)
+	} else {
+		duration, err := e.substituteVariables(node.Duration, exec.Context)
+		if err != nil {
+			return fmt.Errorf("failed to substitute variables in duration: %w", err)
+		}
+		wait, err = workflow.ParseDelayDuration(duration)
+		if err != nil {
+			return err
+		}
+		inputs["duration"] = wait.String()
+	}
+	nodeExec.Inputs = inputs
+
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	nodeExec.Outputs = map[string]interface{}{
+		"waited": wait.String(),
+	}
+	return nil
+}
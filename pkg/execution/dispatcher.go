@@ -0,0 +1,437 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// Priority orders the runs waiting in a Dispatcher: higher priorities start
+// first, and runs of equal priority start in submission order.
+type Priority int
+
+const (
+	// PriorityBackground is for runs nobody is waiting on, such as runs
+	// started by triggers and schedules.
+	PriorityBackground Priority = iota
+	// PriorityNormal is for runs that are neither, such as runs started by
+	// programs embedding the engine.
+	PriorityNormal
+	// PriorityInteractive is for runs a user is waiting on, started from
+	// the TUI or with goflow run.
+	PriorityInteractive
+)
+
+// String returns the priority's name
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityNormal:
+		return "normal"
+	case PriorityInteractive:
+		return "interactive"
+	}
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
+// DefaultDispatcherWorkers is the number of executions a Dispatcher runs at
+// once unless configured otherwise.
+const DefaultDispatcherWorkers = 4
+
+// Dispatcher errors
+var (
+	ErrQueueFull        = errors.New("execution queue is full")
+	ErrDispatcherClosed = errors.New("dispatcher is closed")
+)
+
+// DispatcherOptions configures a Dispatcher.
+type DispatcherOptions struct {
+	// Workers bounds the executions running at once. 0 uses
+	// DefaultDispatcherWorkers.
+	Workers int
+	// InteractiveReserve is how many workers only interactive runs may use,
+	// so a flood of background and normal runs cannot take every worker.
+	// It is capped at Workers-1.
+	InteractiveReserve int
+	// MaxPerWorkflow bounds the concurrent runs of any one workflow. 0 means
+	// no limit beyond Workers.
+	MaxPerWorkflow int
+	// WorkflowLimits overrides MaxPerWorkflow for individual workflows, by
+	// workflow name.
+	WorkflowLimits map[string]int
+	// MaxQueued bounds the runs waiting for a worker; Submit fails with
+	// ErrQueueFull beyond it. 0 means no limit.
+	MaxQueued int
+	// NewEngine creates the engine of each run. nil uses NewEngine().
+	NewEngine func() *Engine
+}
+
+// RunState is where a dispatched run is in its life cycle.
+type RunState string
+
+const (
+	// RunQueued means the run is waiting for a worker.
+	RunQueued RunState = "queued"
+	// RunRunning means the run's execution has started.
+	RunRunning RunState = "running"
+	// RunFinished means the execution ended, whatever its status.
+	RunFinished RunState = "finished"
+	// RunDropped means the run was cancelled or the dispatcher closed
+	// before it started.
+	RunDropped RunState = "dropped"
+)
+
+// Run is a workflow execution submitted to a Dispatcher.
+type Run struct {
+	seq       uint64
+	ctx       context.Context
+	provided  *Engine // Engine the submitter owns, or nil for one from NewEngine
+	workflow  *workflow.Workflow
+	inputs    map[string]interface{}
+	priority  Priority
+	submitted time.Time
+
+	mu     sync.Mutex
+	state  RunState
+	engine *Engine
+	exec   *execution.Execution
+	err    error
+	done   chan struct{}
+}
+
+// Workflow returns the workflow the run executes.
+func (r *Run) Workflow() *workflow.Workflow {
+	return r.workflow
+}
+
+// Priority returns the priority the run was submitted with.
+func (r *Run) Priority() Priority {
+	return r.priority
+}
+
+// SubmittedAt returns when the run was submitted.
+func (r *Run) SubmittedAt() time.Time {
+	return r.submitted
+}
+
+// State returns where the run is in its life cycle.
+func (r *Run) State() RunState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// Engine returns the engine executing the run, for monitoring, pausing and
+// cancelling it, or nil while the run is queued.
+func (r *Run) Engine() *Engine {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.engine
+}
+
+// Done returns a channel closed when the run has finished or was dropped.
+func (r *Run) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait blocks until the run has finished and returns the results of
+// Engine.Execute. A dropped run returns a *CancellationError.
+func (r *Run) Wait(ctx context.Context) (*execution.Execution, error) {
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.exec, r.err
+}
+
+// finish records the run's results and releases its waiters
+func (r *Run) finish(state RunState, exec *execution.Execution, err error) {
+	r.mu.Lock()
+	r.state = state
+	r.exec = exec
+	r.err = err
+	r.mu.Unlock()
+	close(r.done)
+}
+
+// Dispatcher runs workflow executions on a bounded pool of workers. Runs
+// wait in a priority queue, and per-workflow limits keep one busy workflow
+// from taking every worker.
+type Dispatcher struct {
+	opts DispatcherOptions
+
+	mu          sync.Mutex
+	queue       []*Run // Ordered by priority, then submission
+	seq         uint64
+	running     int
+	perWorkflow map[string]int
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+var (
+	sharedDispatcher     *Dispatcher
+	sharedDispatcherOnce sync.Once
+)
+
+// SharedDispatcher returns the process-wide dispatcher every run entry point
+// submits to, so runs started from the TUI, the command line and background
+// sources share one worker pool and queue. One worker is reserved for
+// interactive runs. The engines it creates share the process-wide tool
+// result cache.
+func SharedDispatcher() *Dispatcher {
+	sharedDispatcherOnce.Do(func() {
+		sharedDispatcher = NewDispatcher(DispatcherOptions{
+			InteractiveReserve: 1,
+			NewEngine:          func() *Engine { return NewEngine(WithToolCache(SharedToolCache())) },
+		})
+	})
+	return sharedDispatcher
+}
+
+// NewDispatcher creates a dispatcher with the given options.
+func NewDispatcher(opts DispatcherOptions) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultDispatcherWorkers
+	}
+	opts.InteractiveReserve = max(0, min(opts.InteractiveReserve, opts.Workers-1))
+	if opts.NewEngine == nil {
+		opts.NewEngine = func() *Engine { return NewEngine() }
+	}
+	return &Dispatcher{
+		opts:        opts,
+		perWorkflow: make(map[string]int),
+	}
+}
+
+// Submit queues an execution of wf. It starts as soon as a worker is free
+// and its workflow is under its limit, ahead of queued runs of lower
+// priority. ctx bounds the execution, not the time spent queued.
+func (d *Dispatcher) Submit(ctx context.Context, wf *workflow.Workflow, inputs map[string]interface{}, priority Priority) (*Run, error) {
+	return d.submit(ctx, nil, wf, inputs, priority)
+}
+
+// SubmitWithEngine queues an execution of wf like Submit, run on engine
+// instead of one from NewEngine, so the submitter can configure it and
+// follow its events. The submitter keeps ownership of engine and closes it
+// once the run is done.
+func (d *Dispatcher) SubmitWithEngine(ctx context.Context, engine *Engine, wf *workflow.Workflow, inputs map[string]interface{}, priority Priority) (*Run, error) {
+	if engine == nil {
+		return nil, fmt.Errorf("engine cannot be nil")
+	}
+	return d.submit(ctx, engine, wf, inputs, priority)
+}
+
+// Execute runs wf on engine through the dispatcher and returns the results
+// of Engine.Execute once it has finished. If ctx ends while the run is
+// still queued, the run is dropped and a *CancellationError returned.
+func (d *Dispatcher) Execute(ctx context.Context, engine *Engine, wf *workflow.Workflow, inputs map[string]interface{}, priority Priority) (*execution.Execution, error) {
+	run, err := d.SubmitWithEngine(ctx, engine, wf, inputs, priority)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-run.Done():
+	case <-ctx.Done():
+		// A running run ends through ctx on its own
+		d.drop(run, "cancelled while queued")
+		<-run.Done()
+	}
+	return run.Wait(context.Background())
+}
+
+// submit queues a run on engine, or on one from NewEngine if nil
+func (d *Dispatcher) submit(ctx context.Context, engine *Engine, wf *workflow.Workflow, inputs map[string]interface{}, priority Priority) (*Run, error) {
+	if wf == nil {
+		return nil, fmt.Errorf("workflow cannot be nil")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, ErrDispatcherClosed
+	}
+	if d.opts.MaxQueued > 0 && len(d.queue) >= d.opts.MaxQueued {
+		return nil, ErrQueueFull
+	}
+
+	d.seq++
+	run := &Run{
+		seq:       d.seq,
+		ctx:       ctx,
+		provided:  engine,
+		workflow:  wf,
+		inputs:    inputs,
+		priority:  priority,
+		submitted: time.Now(),
+		state:     RunQueued,
+		done:      make(chan struct{}),
+	}
+
+	// Insert after every run of the same or higher priority
+	i := len(d.queue)
+	for i > 0 && d.queue[i-1].priority < priority {
+		i--
+	}
+	d.queue = append(d.queue, nil)
+	copy(d.queue[i+1:], d.queue[i:])
+	d.queue[i] = run
+
+	d.dispatchLocked()
+	return run, nil
+}
+
+// Cancel drops a queued run, or cancels a running one through its engine.
+func (d *Dispatcher) Cancel(run *Run, reason string) error {
+	if reason == "" {
+		reason = "cancelled by user"
+	}
+
+	if d.drop(run, reason) {
+		return nil
+	}
+	if engine := run.Engine(); engine != nil && run.State() == RunRunning {
+		return engine.Cancel(reason)
+	}
+	return fmt.Errorf("run is not queued or running")
+}
+
+// drop removes a run from the queue, reporting whether it was queued
+func (d *Dispatcher) drop(run *Run, reason string) bool {
+	d.mu.Lock()
+	for i, queued := range d.queue {
+		if queued == run {
+			d.queue = append(d.queue[:i], d.queue[i+1:]...)
+			d.mu.Unlock()
+			run.finish(RunDropped, nil, &CancellationError{Reason: reason})
+			return true
+		}
+	}
+	d.mu.Unlock()
+	return false
+}
+
+// Close stops accepting runs, drops the queued ones and waits for the
+// running ones to finish.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	d.closed = true
+	dropped := d.queue
+	d.queue = nil
+	d.mu.Unlock()
+
+	for _, run := range dropped {
+		run.finish(RunDropped, nil, &CancellationError{Reason: "dispatcher closed"})
+	}
+	d.wg.Wait()
+}
+
+// DispatcherStats is a snapshot of a dispatcher's load.
+type DispatcherStats struct {
+	Queued            int
+	Running           int
+	RunningByWorkflow map[string]int
+}
+
+// Stats returns the dispatcher's current load.
+func (d *Dispatcher) Stats() DispatcherStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	byWorkflow := make(map[string]int, len(d.perWorkflow))
+	for name, count := range d.perWorkflow {
+		byWorkflow[name] = count
+	}
+	return DispatcherStats{
+		Queued:            len(d.queue),
+		Running:           d.running,
+		RunningByWorkflow: byWorkflow,
+	}
+}
+
+// workflowKey names a workflow for per-workflow limits
+func workflowKey(wf *workflow.Workflow) string {
+	if wf.Name != "" {
+		return wf.Name
+	}
+	return wf.ID
+}
+
+// workflowLimit returns how many runs of a workflow may run at once, or 0
+func (d *Dispatcher) workflowLimit(key string) int {
+	if limit, ok := d.opts.WorkflowLimits[key]; ok {
+		return limit
+	}
+	return d.opts.MaxPerWorkflow
+}
+
+// dispatchLocked starts queued runs while workers are free. Runs blocked by
+// their workflow's limit or the interactive reserve are skipped, not
+// waited on, so they don't hold up runs behind them.
+func (d *Dispatcher) dispatchLocked() {
+	for i := 0; i < len(d.queue) && d.running < d.opts.Workers; {
+		run := d.queue[i]
+		key := workflowKey(run.workflow)
+
+		shared := d.opts.Workers - d.opts.InteractiveReserve
+		if run.priority < PriorityInteractive && d.running >= shared {
+			i++
+			continue
+		}
+		if limit := d.workflowLimit(key); limit > 0 && d.perWorkflow[key] >= limit {
+			i++
+			continue
+		}
+
+		d.queue = append(d.queue[:i], d.queue[i+1:]...)
+		d.start(run, key)
+	}
+}
+
+// start runs a dequeued run on its submitter's engine, or a new one.
+// Called with d.mu held.
+func (d *Dispatcher) start(run *Run, key string) {
+	d.running++
+	d.perWorkflow[key]++
+
+	engine := run.provided
+	if engine == nil {
+		engine = d.opts.NewEngine()
+	}
+	run.mu.Lock()
+	run.state = RunRunning
+	run.engine = engine
+	run.mu.Unlock()
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		exec, err := engine.Execute(run.ctx, run.workflow, run.inputs)
+		if run.provided == nil {
+			_ = engine.Close()
+		}
+
+		d.mu.Lock()
+		d.running--
+		if d.perWorkflow[key]--; d.perWorkflow[key] <= 0 {
+			delete(d.perWorkflow, key)
+		}
+		if !d.closed {
+			d.dispatchLocked()
+		}
+		d.mu.Unlock()
+
+		run.finish(RunFinished, exec, err)
+	}()
+}
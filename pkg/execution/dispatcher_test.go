@@ -0,0 +1,236 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dispatcherWorkflow returns a minimal workflow with the given name
+func dispatcherWorkflow(t *testing.T, name string) *workflow.Workflow {
+	t.Helper()
+	yaml := fmt.Sprintf(`
+version: "1.0"
+name: %q
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "end"
+`, name)
+	wf, err := workflow.Parse([]byte(yaml))
+	require.NoError(t, err)
+	return wf
+}
+
+// pausedEngines creates engines that hold their run at the first node until
+// released, so tests control when workers free up.
+type pausedEngines struct {
+	mu      sync.Mutex
+	engines []*Engine
+}
+
+func (p *pausedEngines) newEngine() *Engine {
+	engine := NewEngine()
+	engine.Pause()
+	p.mu.Lock()
+	p.engines = append(p.engines, engine)
+	p.mu.Unlock()
+	return engine
+}
+
+func (p *pausedEngines) releaseAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, engine := range p.engines {
+		engine.Resume()
+	}
+}
+
+// waitState waits for a run to reach state
+func waitState(t *testing.T, run *Run, state RunState) {
+	t.Helper()
+	require.Eventually(t, func() bool { return run.State() == state },
+		5*time.Second, 5*time.Millisecond, "run did not become %s", state)
+}
+
+func TestDispatcher_PriorityOrder(t *testing.T) {
+	engines := &pausedEngines{}
+	d := NewDispatcher(DispatcherOptions{Workers: 1, NewEngine: engines.newEngine})
+	defer d.Close()
+	defer engines.releaseAll()
+
+	ctx := context.Background()
+	first, err := d.Submit(ctx, dispatcherWorkflow(t, "first"), nil, PriorityBackground)
+	require.NoError(t, err)
+	assert.Equal(t, RunRunning, first.State())
+
+	background, err := d.Submit(ctx, dispatcherWorkflow(t, "background"), nil, PriorityBackground)
+	require.NoError(t, err)
+	normal, err := d.Submit(ctx, dispatcherWorkflow(t, "normal"), nil, PriorityNormal)
+	require.NoError(t, err)
+	interactive, err := d.Submit(ctx, dispatcherWorkflow(t, "interactive"), nil, PriorityInteractive)
+	require.NoError(t, err)
+	assert.Equal(t, 3, d.Stats().Queued)
+
+	// Each freed worker goes to the highest priority waiting
+	for _, next := range []*Run{interactive, normal, background} {
+		engines.releaseAll()
+		waitState(t, next, RunRunning)
+		for _, other := range []*Run{interactive, normal, background} {
+			if other.seq > next.seq && other.priority < next.priority {
+				assert.Equal(t, RunQueued, other.State())
+			}
+		}
+	}
+
+	engines.releaseAll()
+	exec, err := background.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+}
+
+func TestDispatcher_InteractiveReserve(t *testing.T) {
+	engines := &pausedEngines{}
+	d := NewDispatcher(DispatcherOptions{Workers: 2, InteractiveReserve: 1, NewEngine: engines.newEngine})
+	defer d.Close()
+	defer engines.releaseAll()
+
+	ctx := context.Background()
+	var background []*Run
+	for i := 0; i < 3; i++ {
+		run, err := d.Submit(ctx, dispatcherWorkflow(t, fmt.Sprintf("triggered-%d", i)), nil, PriorityBackground)
+		require.NoError(t, err)
+		background = append(background, run)
+	}
+	assert.Equal(t, DispatcherStats{Queued: 2, Running: 1, RunningByWorkflow: map[string]int{"triggered-0": 1}}, d.Stats())
+
+	// The reserved worker is free for a run started from the TUI
+	interactive, err := d.Submit(ctx, dispatcherWorkflow(t, "interactive"), nil, PriorityInteractive)
+	require.NoError(t, err)
+	assert.Equal(t, RunRunning, interactive.State())
+	assert.Equal(t, RunQueued, background[1].State())
+}
+
+func TestDispatcher_WorkflowLimits(t *testing.T) {
+	engines := &pausedEngines{}
+	d := NewDispatcher(DispatcherOptions{
+		Workers:        4,
+		MaxPerWorkflow: 1,
+		WorkflowLimits: map[string]int{"wide": 2},
+		NewEngine:      engines.newEngine,
+	})
+	defer d.Close()
+	defer engines.releaseAll()
+
+	ctx := context.Background()
+	submit := func(name string) *Run {
+		run, err := d.Submit(ctx, dispatcherWorkflow(t, name), nil, PriorityNormal)
+		require.NoError(t, err)
+		return run
+	}
+
+	etl1, etl2 := submit("etl"), submit("etl")
+	wide1, wide2 := submit("wide"), submit("wide")
+	other := submit("other")
+
+	assert.Equal(t, RunRunning, etl1.State())
+	assert.Equal(t, RunQueued, etl2.State(), "etl is limited to one run")
+	assert.Equal(t, RunRunning, wide1.State())
+	assert.Equal(t, RunRunning, wide2.State())
+	assert.Equal(t, RunRunning, other.State(), "a blocked workflow must not hold up runs behind it")
+
+	engines.releaseAll()
+	waitState(t, etl1, RunFinished)
+	waitState(t, etl2, RunRunning)
+}
+
+func TestDispatcher_CancelAndClose(t *testing.T) {
+	engines := &pausedEngines{}
+	d := NewDispatcher(DispatcherOptions{Workers: 1, MaxQueued: 2, NewEngine: engines.newEngine})
+
+	ctx := context.Background()
+	running, err := d.Submit(ctx, dispatcherWorkflow(t, "running"), nil, PriorityNormal)
+	require.NoError(t, err)
+	queued, err := d.Submit(ctx, dispatcherWorkflow(t, "queued"), nil, PriorityNormal)
+	require.NoError(t, err)
+	leftover, err := d.Submit(ctx, dispatcherWorkflow(t, "leftover"), nil, PriorityNormal)
+	require.NoError(t, err)
+
+	_, err = d.Submit(ctx, dispatcherWorkflow(t, "overflow"), nil, PriorityNormal)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	// A queued run is dropped without starting
+	require.NoError(t, d.Cancel(queued, "no longer needed"))
+	_, err = queued.Wait(ctx)
+	var cancelErr *CancellationError
+	require.True(t, errors.As(err, &cancelErr))
+	assert.Equal(t, "no longer needed", cancelErr.Reason)
+	assert.Equal(t, RunDropped, queued.State())
+	assert.Nil(t, queued.Engine())
+
+	// A running run is cancelled through its engine, even while paused
+	require.Eventually(t, func() bool {
+		return d.Cancel(running, "operator stop") == nil
+	}, 5*time.Second, 5*time.Millisecond)
+	exec, _ := running.Wait(ctx)
+	require.NotNil(t, exec)
+	assert.Equal(t, execution.StatusCancelled, exec.Status)
+	assert.Equal(t, "operator stop", exec.CancelReason())
+
+	// Close drops what is still queued and refuses new runs
+	waitState(t, leftover, RunRunning)
+	engines.releaseAll()
+	d.Close()
+	assert.Equal(t, RunFinished, leftover.State())
+	_, err = d.Submit(ctx, dispatcherWorkflow(t, "late"), nil, PriorityNormal)
+	assert.ErrorIs(t, err, ErrDispatcherClosed)
+}
+
+func TestDispatcher_Execute(t *testing.T) {
+	engines := &pausedEngines{}
+	d := NewDispatcher(DispatcherOptions{Workers: 1, NewEngine: engines.newEngine})
+	defer d.Close()
+	defer engines.releaseAll()
+
+	busy, err := d.Submit(context.Background(), dispatcherWorkflow(t, "busy"), nil, PriorityBackground)
+	require.NoError(t, err)
+
+	// A run given up on while queued is dropped, leaving its engine unused
+	engine := NewEngine()
+	defer engine.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := d.Execute(ctx, engine, dispatcherWorkflow(t, "abandoned"), nil, PriorityInteractive)
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return d.Stats().Queued == 1 }, 5*time.Second, 5*time.Millisecond)
+	cancel()
+	var cancelErr *CancellationError
+	require.True(t, errors.As(<-done, &cancelErr))
+	assert.Equal(t, 0, d.Stats().Queued)
+
+	// Once a worker is free the run executes on the given engine, which
+	// stays open for its owner
+	engines.releaseAll()
+	waitState(t, busy, RunFinished)
+	exec, err := d.Execute(context.Background(), engine, dispatcherWorkflow(t, "owned"), nil, PriorityInteractive)
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+	assert.NotNil(t, engine.GetMonitor())
+
+	_, err = d.SubmitWithEngine(context.Background(), nil, dispatcherWorkflow(t, "none"), nil, PriorityNormal)
+	assert.Error(t, err)
+}
@@ -443,6 +443,14 @@ func classifyError(err *execution.ExecutionError) ErrorClassification {
 		classification.Severity = SeverityMedium
 		classification.RetryHint = "Increase timeout or optimize operation"
 
+	case execution.ErrorTypeGuardrail:
+		classification.Severity = SeverityHigh
+		classification.RetryHint = "Raise the guardrail limit or reduce the workflow's resource use"
+
+	case execution.ErrorTypePolicy:
+		classification.Severity = SeverityHigh
+		classification.RetryHint = "Run under a sandbox profile that allows the node, or remove it from the workflow"
+
 	case execution.ErrorTypeData:
 		classification.Severity = SeverityHigh
 		classification.RetryHint = "Verify data transformation expressions and input data"
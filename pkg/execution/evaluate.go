@@ -0,0 +1,109 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/transform"
+)
+
+// conditionVariablePattern matches JSONPath-style variable references ($.variable)
+var conditionVariablePattern = regexp.MustCompile(`\$\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// EvaluateTransform applies a Transform node's expression the way the engine
+// does when the node runs. JSONPath queries operate on the input value;
+// expressions and templates are evaluated against all variables, so they can
+// reference any of them. jq programs operate on the input value and read
+// variables as $name.
+func EvaluateTransform(ctx context.Context, expression string, input interface{}, variables map[string]interface{}) (interface{}, error) {
+	return EvaluateTransformAs(ctx, "", expression, input, variables)
+}
+
+// EvaluateTransformAs is EvaluateTransform for an expression in the named
+// language (jsonpath, expr, template or jq), detected from the expression
+// when empty or auto
+func EvaluateTransformAs(ctx context.Context, language, expression string, input interface{}, variables map[string]interface{}) (interface{}, error) {
+	transformType, err := transform.ParseTransformType(language)
+	if err != nil {
+		return nil, err
+	}
+	transformer := transform.NewTransformer()
+	if transformType == transform.TransformTypeUnknown && transform.IsJQExpression(expression) {
+		transformType = transform.TransformTypeJQ
+	}
+	if transformType != transform.TransformTypeUnknown {
+		return transformer.TransformAs(ctx, transformType, expression, input, variables)
+	}
+
+	transformData := interface{}(variables)
+	if isJSONPathExpression(expression) {
+		transformData = input
+	}
+	return transformer.Transform(ctx, expression, transformData)
+}
+
+// EvaluateCondition evaluates a Condition node's expression against the
+// variables the way the engine does when the node runs. The expression must
+// evaluate to a boolean.
+func EvaluateCondition(ctx context.Context, condition string, variables map[string]interface{}) (bool, error) {
+	processedExpr := processConditionExpression(condition, variables)
+
+	result, err := transform.NewExpressionEvaluator().Evaluate(ctx, processedExpr, variables)
+	if err != nil {
+		return false, fmt.Errorf("condition evaluation failed: %w", err)
+	}
+
+	boolResult, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition expression did not evaluate to boolean, got %T", result)
+	}
+	return boolResult, nil
+}
+
+// isJSONPathExpression determines if an expression is a JSONPath query
+// This duplicates the detection logic from transform.detectTransformType for JSONPath
+func isJSONPathExpression(expr string) bool {
+	trimmed := strings.TrimSpace(expr)
+
+	// Check for JSONPath patterns
+	if strings.HasPrefix(trimmed, "$.") || trimmed == "$" {
+		return true
+	}
+
+	// Check for recursive descent
+	if strings.Contains(trimmed, "..") {
+		return true
+	}
+
+	// Check for filter expressions
+	if strings.Contains(trimmed, "[?(") {
+		return true
+	}
+
+	// Check for array wildcard
+	if strings.Contains(trimmed, "[*]") {
+		return true
+	}
+
+	return false
+}
+
+// processConditionExpression converts JSONPath-style expressions ($.variable) into
+// direct variable references for evaluation. For example:
+// "$.fileSize > 1048576" becomes "fileSize > 1048576"
+func processConditionExpression(expression string, variables map[string]interface{}) string {
+	return conditionVariablePattern.ReplaceAllStringFunc(expression, func(match string) string {
+		// Extract variable name (remove "$." prefix)
+		varName := match[2:]
+
+		// Check if variable exists
+		if _, exists := variables[varName]; exists {
+			// Return the variable name without the "$." prefix
+			return varName
+		}
+		// If variable doesn't exist, return as-is and let evaluator handle the error
+		return match
+	})
+}
@@ -0,0 +1,64 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/transform"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expressionTimeoutWorkflowYAML branches on a condition that iterates
+// 640,000 times
+const expressionTimeoutWorkflowYAML = `
+version: "1.0"
+name: "expression-timeout-test"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "scan"
+    type: "condition"
+    condition: "any(1..800, {any(1..800, {# < 0})})"
+  - id: "found"
+    type: "end"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "scan"
+  - from: "scan"
+    to: "found"
+    condition: "true"
+  - from: "scan"
+    to: "end"
+    condition: "false"
+`
+
+func TestExpressionTimeout(t *testing.T) {
+	// Without a configured timeout the default lets the transform complete
+	wf, err := workflow.Parse([]byte(expressionTimeoutWorkflowYAML))
+	require.NoError(t, err)
+	engine := NewEngine()
+	defer engine.Close()
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+
+	// The workflow's timeout stops the evaluation
+	wf.Metadata.ExpressionTimeout = "1ms"
+	exec, err = engine.Execute(context.Background(), wf, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), transform.ErrEvaluationTimeout.Error())
+	assert.Equal(t, execution.StatusFailed, exec.Status)
+
+	// The node's timeout overrides the workflow's
+	scan := wf.Nodes[1].(*workflow.ConditionNode)
+	scan.ExpressionTimeout = "30s"
+	assert.Equal(t, "30s", wf.ExpressionTimeout(scan).String())
+	exec, err = engine.Execute(context.Background(), wf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+}
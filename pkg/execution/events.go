@@ -6,6 +6,7 @@ import (
 
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/events"
 )
 
 // ExecutionEventType categorizes different event types during workflow execution.
@@ -20,6 +21,11 @@ const (
 	EventExecutionFailed ExecutionEventType = "execution.failed"
 	// EventExecutionCancelled is emitted when a workflow execution is cancelled.
 	EventExecutionCancelled ExecutionEventType = "execution.cancelled"
+	// EventExecutionPaused is emitted when a running execution is paused; nodes
+	// already running finish, and no new node starts until it is resumed.
+	EventExecutionPaused ExecutionEventType = "execution.paused"
+	// EventExecutionResumed is emitted when a paused execution continues.
+	EventExecutionResumed ExecutionEventType = "execution.resumed"
 
 	// EventNodeStarted is emitted when a node begins execution.
 	EventNodeStarted ExecutionEventType = "node.started"
@@ -36,6 +42,20 @@ const (
 	// EventConditionEvaluated is emitted when a condition node evaluates its expression.
 	EventConditionEvaluated ExecutionEventType = "condition.evaluated"
 
+	// EventApprovalRequested is emitted when an approval node starts waiting
+	// for a decision; Metadata holds the message and, with a timeout, the
+	// deadline and default action.
+	EventApprovalRequested ExecutionEventType = "approval.requested"
+	// EventApprovalDecided is emitted when an approval node is approved or
+	// rejected, or times out; Metadata holds approved, by, comment and
+	// timed_out.
+	EventApprovalDecided ExecutionEventType = "approval.decided"
+
+	// EventToolProgress is emitted for each progress notification an MCP
+	// tool sends while it runs; Metadata holds progress, total (when known),
+	// message and, for streamed output, the text chunk.
+	EventToolProgress ExecutionEventType = "tool.progress"
+
 	// EventLoopStarted is emitted when a loop node begins iteration.
 	EventLoopStarted ExecutionEventType = "loop.started"
 	// EventLoopIteration is emitted for each loop iteration.
@@ -129,6 +149,29 @@ type ExecutionMonitor interface {
 	GetExecutionState() *execution.Execution
 }
 
+// WithEventHandler registers a function that observes every execution
+// event. Unlike monitor subscriptions it sees events from the very start of
+// each execution and never drops them. The handler runs synchronously on the
+// executing goroutine (possibly concurrently for parallel branches), so it
+// must be quick and safe for concurrent use.
+func WithEventHandler(handler func(ExecutionEvent)) EngineOption {
+	return func(e *Engine) {
+		e.eventHandler = handler
+	}
+}
+
+// WithEventBus publishes every execution event to bus, with the execution
+// ID as the source and the ExecutionEvent as the payload. Engines publish to
+// events.Default() unless configured otherwise; nil disables publishing.
+func WithEventBus(bus *events.Bus) EngineOption {
+	return func(e *Engine) {
+		e.eventBus = bus
+	}
+}
+
+// DefaultEventQueueSize is the default buffer size of subscription channels.
+const DefaultEventQueueSize = 200
+
 // subscription represents a single event subscriber.
 type subscription struct {
 	ch     chan ExecutionEvent
@@ -150,6 +193,15 @@ type monitor struct {
 
 	// closed indicates if the monitor has been closed
 	closed bool
+
+	// queueSize is the buffer size for new subscriptions (0 = default)
+	queueSize int
+
+	// handler observes every event before it is broadcast (optional)
+	handler func(ExecutionEvent)
+
+	// bus receives every event after the handler (optional)
+	bus *events.Bus
 }
 
 // NewMonitor creates a new execution monitor for the given execution.
@@ -163,6 +215,15 @@ func NewMonitor(exec *execution.Execution, totalNodes int) ExecutionMonitor {
 	}
 }
 
+// subscriptionBufferSize returns the channel buffer size for new subscriptions.
+// Caller must hold m.mu.
+func (m *monitor) subscriptionBufferSize() int {
+	if m.queueSize > 0 {
+		return m.queueSize
+	}
+	return DefaultEventQueueSize
+}
+
 // Subscribe returns a channel that receives all execution events.
 func (m *monitor) Subscribe() <-chan ExecutionEvent {
 	m.mu.Lock()
@@ -177,7 +238,7 @@ func (m *monitor) Subscribe() <-chan ExecutionEvent {
 
 	// Create buffered channel to prevent blocking event emission
 	// Buffer size of 200 should handle bursts of events in high-throughput scenarios
-	ch := make(chan ExecutionEvent, 200)
+	ch := make(chan ExecutionEvent, m.subscriptionBufferSize())
 	sub := &subscription{
 		ch:     ch,
 		filter: nil,
@@ -200,7 +261,7 @@ func (m *monitor) SubscribeFiltered(filter EventFilter) <-chan ExecutionEvent {
 	}
 
 	// Create buffered channel to prevent blocking event emission
-	ch := make(chan ExecutionEvent, 200)
+	ch := make(chan ExecutionEvent, m.subscriptionBufferSize())
 	sub := &subscription{
 		ch:     ch,
 		filter: &filter,
@@ -312,6 +373,19 @@ func (m *monitor) Emit(event ExecutionEvent) {
 		event.Timestamp = time.Now()
 	}
 
+	if m.handler != nil {
+		m.handler(event)
+	}
+	if m.bus != nil {
+		m.bus.Publish(events.Event{
+			Topic:     events.TopicOf(string(event.Type)),
+			Type:      string(event.Type),
+			Timestamp: event.Timestamp,
+			Source:    string(event.ExecutionID),
+			Payload:   event,
+		})
+	}
+
 	// Broadcast to all subscribers
 	for _, sub := range m.subscribers {
 		// Apply filter if present
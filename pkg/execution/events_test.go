@@ -2,11 +2,13 @@ package execution
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/events"
 	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -287,6 +289,98 @@ func TestExecutionMonitor_GetVariableSnapshot(t *testing.T) {
 	assert.Equal(t, true, snapshot["var3"])
 }
 
+func TestEngine_WithEventHandler(t *testing.T) {
+	yaml := `
+version: "1.0"
+name: "test-workflow"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "node1"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "node1"
+  - from: "node1"
+    to: "end"
+`
+
+	wf, err := workflow.Parse([]byte(yaml))
+	require.NoError(t, err)
+
+	// The handler sees every event, including those emitted before any
+	// subscriber could attach
+	var events []ExecutionEvent
+	engine := NewEngine(WithEventHandler(func(event ExecutionEvent) {
+		events = append(events, event)
+	}))
+	defer engine.Close()
+
+	_, err = engine.Execute(context.Background(), wf, nil)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, events)
+	assert.Equal(t, EventExecutionStarted, events[0].Type)
+	assert.Equal(t, EventExecutionCompleted, events[len(events)-1].Type)
+
+	var started []types.NodeID
+	for _, event := range events {
+		assert.False(t, event.Timestamp.IsZero())
+		if event.Type == EventNodeStarted {
+			started = append(started, event.NodeID)
+		}
+	}
+	assert.Equal(t, []types.NodeID{"start", "node1", "end"}, started)
+}
+
+func TestEngine_WithEventBus(t *testing.T) {
+	yaml := `
+version: "1.0"
+name: "test-workflow"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "end"
+`
+
+	wf, err := workflow.Parse([]byte(yaml))
+	require.NoError(t, err)
+
+	bus := events.NewBus(100)
+	var mu sync.Mutex
+	var received []events.Event
+	unsubscribe := bus.Subscribe(events.SubscriberFunc(func(event events.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event)
+	}), events.TopicExecution)
+
+	engine := NewEngine(WithEventBus(bus))
+	defer engine.Close()
+
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	require.NoError(t, err)
+	unsubscribe()
+
+	// Only execution-topic events arrive, with the engine event as payload
+	require.NotEmpty(t, received)
+	for _, event := range received {
+		assert.Equal(t, events.TopicExecution, event.Topic)
+		assert.Equal(t, string(exec.ID), event.Source)
+		payload, ok := event.Payload.(ExecutionEvent)
+		require.True(t, ok, "payload is %T", event.Payload)
+		assert.Equal(t, string(payload.Type), event.Type)
+	}
+	assert.Equal(t, string(EventExecutionStarted), received[0].Type)
+	assert.Equal(t, string(EventExecutionCompleted), received[len(received)-1].Type)
+}
+
 func TestEventFilter_Matches(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,202 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/dshills/goflow/pkg/config"
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// Guardrails limit the resources one execution may use. An execution that
+// exceeds one is aborted with an ErrorTypeGuardrail error. Zero disables a
+// limit.
+type Guardrails struct {
+	// MaxVariablesBytes caps the size of all variables together, as JSON,
+	// checked after each node.
+	MaxVariablesBytes int64
+	// MaxPayloadBytes caps the size of a single node's inputs or outputs,
+	// as JSON.
+	MaxPayloadBytes int64
+	// MaxNodeExecutions caps the node executions of a run, loop iterations
+	// and parallel branches included.
+	MaxNodeExecutions int
+	// MaxWallClock caps the time a run may take.
+	MaxWallClock time.Duration
+}
+
+// Guardrail names, as reported in the Context of guardrail errors
+const (
+	GuardrailVariablesMemory = "max_variables_bytes"
+	GuardrailPayloadSize     = "max_payload_bytes"
+	GuardrailNodeExecutions  = "max_node_executions"
+	GuardrailWallClock       = "max_wall_clock"
+)
+
+// IsZero reports whether no guardrail is set.
+func (g Guardrails) IsZero() bool {
+	return g == Guardrails{}
+}
+
+// GuardrailsFromTunables returns the guardrails set in the tunables.
+func GuardrailsFromTunables(t config.Tunables) Guardrails {
+	return Guardrails{
+		MaxVariablesBytes: int64(t.MaxVariablesMB) << 20,
+		MaxPayloadBytes:   int64(t.MaxPayloadKB) << 10,
+		MaxNodeExecutions: t.MaxNodeExecutions,
+		MaxWallClock:      time.Duration(t.MaxExecutionSec) * time.Second,
+	}
+}
+
+// WithGuardrails configures resource guardrails for executions. Limits left
+// at zero follow the tunables in config.yaml.
+func WithGuardrails(guardrails Guardrails) EngineOption {
+	return func(e *Engine) {
+		e.guardrails = guardrails
+	}
+}
+
+// resolveGuardrails returns the explicit guardrails, with the tunables
+// filling in the limits that were not set.
+func (e *Engine) resolveGuardrails() Guardrails {
+	g := e.guardrails
+	defaults := GuardrailsFromTunables(config.Global().Get())
+	if g.MaxVariablesBytes <= 0 {
+		g.MaxVariablesBytes = defaults.MaxVariablesBytes
+	}
+	if g.MaxPayloadBytes <= 0 {
+		g.MaxPayloadBytes = defaults.MaxPayloadBytes
+	}
+	if g.MaxNodeExecutions <= 0 {
+		g.MaxNodeExecutions = defaults.MaxNodeExecutions
+	}
+	if g.MaxWallClock <= 0 {
+		g.MaxWallClock = defaults.MaxWallClock
+	}
+	return g
+}
+
+// guardrailKey is the context key of an execution's guard
+type guardrailKey struct{}
+
+// guard enforces the guardrails of one execution. Exceeding a limit cancels
+// the execution's context with the guardrail error as the cause.
+type guard struct {
+	limits         Guardrails
+	sandbox        *workflow.SandboxProfile
+	cancel         context.CancelCauseFunc
+	nodeExecutions atomic.Int64
+}
+
+// withGuardrails returns a context carrying a guard for limits and the
+// sandbox profile, with the wall-clock limit as its deadline. Without
+// either ctx is returned as is.
+func withGuardrails(ctx context.Context, limits Guardrails, sandbox *workflow.SandboxProfile) (context.Context, context.CancelFunc) {
+	if limits.IsZero() && sandbox == nil {
+		return ctx, func() {}
+	}
+
+	ctx, cancelCause := context.WithCancelCause(ctx)
+	g := &guard{limits: limits, sandbox: sandbox, cancel: cancelCause}
+	ctx = context.WithValue(ctx, guardrailKey{}, g)
+
+	cancel := func() { cancelCause(nil) }
+	if limits.MaxWallClock > 0 {
+		cause := guardrailError(GuardrailWallClock, "", limits.MaxWallClock.String(), "",
+			fmt.Sprintf("execution exceeded the wall-clock limit of %v", limits.MaxWallClock))
+		var cancelDeadline context.CancelFunc
+		ctx, cancelDeadline = context.WithTimeoutCause(ctx, limits.MaxWallClock, cause)
+		cancel = func() {
+			cancelDeadline()
+			cancelCause(nil)
+		}
+	}
+	return ctx, cancel
+}
+
+// guardFromContext returns the execution's guard, or nil without guardrails
+func guardFromContext(ctx context.Context) *guard {
+	g, _ := ctx.Value(guardrailKey{}).(*guard)
+	return g
+}
+
+// guardrailViolation returns the guardrail or sandbox policy error that
+// stopped the execution running in ctx, if any.
+func guardrailViolation(ctx context.Context) *execution.ExecutionError {
+	var execErr *execution.ExecutionError
+	if errors.As(context.Cause(ctx), &execErr) && (execErr.Type == execution.ErrorTypeGuardrail || execErr.Type == execution.ErrorTypePolicy) {
+		return execErr
+	}
+	return nil
+}
+
+// guardrailError builds the error reported for an exceeded guardrail
+func guardrailError(guardrail string, nodeID types.NodeID, limit, actual interface{}, message string) *execution.ExecutionError {
+	ctx := map[string]interface{}{
+		"guardrail": guardrail,
+		"limit":     limit,
+	}
+	if actual != "" {
+		ctx["actual"] = actual
+	}
+	return &execution.ExecutionError{
+		Type:        execution.ErrorTypeGuardrail,
+		Message:     message,
+		NodeID:      nodeID,
+		Context:     ctx,
+		Recoverable: false,
+		Timestamp:   time.Now(),
+	}
+}
+
+// trip aborts the execution with err
+func (g *guard) trip(err *execution.ExecutionError) error {
+	g.cancel(err)
+	return err
+}
+
+// beforeNode counts a node execution against the limit.
+func (g *guard) beforeNode(nodeID types.NodeID) error {
+	if g.limits.MaxNodeExecutions <= 0 {
+		return nil
+	}
+	count := g.nodeExecutions.Add(1)
+	if count <= int64(g.limits.MaxNodeExecutions) {
+		return nil
+	}
+	return g.trip(guardrailError(GuardrailNodeExecutions, nodeID, g.limits.MaxNodeExecutions, count,
+		fmt.Sprintf("execution exceeded the limit of %d node executions", g.limits.MaxNodeExecutions)))
+}
+
+// afterNode checks the node's payloads and the variables it left behind.
+func (g *guard) afterNode(exec *execution.Execution, nodeExec *execution.NodeExecution) error {
+	if limit := g.limits.MaxPayloadBytes; limit > 0 {
+		for _, payload := range []struct {
+			kind string
+			data map[string]interface{}
+		}{
+			{"inputs", nodeExec.Inputs},
+			{"outputs", nodeExec.Outputs},
+		} {
+			if size := int64(payloadSize(payload.data)); size > limit {
+				return g.trip(guardrailError(GuardrailPayloadSize, nodeExec.NodeID, limit, size,
+					fmt.Sprintf("node %s %s are %d bytes, over the limit of %d", nodeExec.NodeID, payload.kind, size, limit)))
+			}
+		}
+	}
+
+	if limit := g.limits.MaxVariablesBytes; limit > 0 && exec.Context != nil {
+		data, err := json.Marshal(exec.Context.GetVariableSnapshot())
+		if err == nil && int64(len(data)) > limit {
+			return g.trip(guardrailError(GuardrailVariablesMemory, nodeExec.NodeID, limit, int64(len(data)),
+				fmt.Sprintf("variables take %d bytes, over the limit of %d", len(data), limit)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/config"
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const guardrailWorkflowYAML = `
+version: "1.0"
+name: "guardrail-test"
+variables:
+  - name: "text"
+    type: "string"
+    default: "abcdefghijklmnopqrstuvwxyz"
+  - name: "result"
+    type: "string"
+    default: ""
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "node1"
+    type: "passthrough"
+  - id: "node2"
+    type: "passthrough"
+  - id: "repeat"
+    type: "transform"
+    input: "text"
+    expression: "text + text + text + text"
+    output: "result"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "node1"
+  - from: "node1"
+    to: "node2"
+  - from: "node2"
+    to: "repeat"
+  - from: "repeat"
+    to: "end"
+`
+
+// requireGuardrailError asserts that err is a guardrail error for guardrail
+func requireGuardrailError(t *testing.T, exec *execution.Execution, err error, guardrail string) *execution.ExecutionError {
+	t.Helper()
+	require.Error(t, err)
+
+	var execErr *execution.ExecutionError
+	require.True(t, errors.As(err, &execErr), "expected an ExecutionError, got %T", err)
+	assert.Equal(t, execution.ErrorTypeGuardrail, execErr.Type)
+	assert.Equal(t, guardrail, execErr.Context["guardrail"])
+
+	require.NotNil(t, exec)
+	assert.Equal(t, execution.StatusFailed, exec.Status)
+	require.NotNil(t, exec.Error)
+	assert.Equal(t, execution.ErrorTypeGuardrail, exec.Error.Type)
+	return execErr
+}
+
+func TestGuardrails(t *testing.T) {
+	wf, err := workflow.Parse([]byte(guardrailWorkflowYAML))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		guardrails Guardrails
+		guardrail  string
+		nodeID     string
+	}{
+		{
+			name:       "node executions",
+			guardrails: Guardrails{MaxNodeExecutions: 2},
+			guardrail:  GuardrailNodeExecutions,
+			nodeID:     "node2",
+		},
+		{
+			name:       "payload size",
+			guardrails: Guardrails{MaxPayloadBytes: 64},
+			guardrail:  GuardrailPayloadSize,
+			nodeID:     "repeat",
+		},
+		{
+			name:       "variables memory",
+			guardrails: Guardrails{MaxVariablesBytes: 100},
+			guardrail:  GuardrailVariablesMemory,
+			nodeID:     "repeat",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(WithGuardrails(tt.guardrails))
+			defer engine.Close()
+
+			exec, err := engine.Execute(context.Background(), wf, nil)
+			execErr := requireGuardrailError(t, exec, err, tt.guardrail)
+			assert.Equal(t, tt.nodeID, string(execErr.NodeID))
+		})
+	}
+}
+
+func TestGuardrails_WithinLimits(t *testing.T) {
+	wf, err := workflow.Parse([]byte(guardrailWorkflowYAML))
+	require.NoError(t, err)
+
+	engine := NewEngine(WithGuardrails(Guardrails{
+		MaxVariablesBytes: 1 << 20,
+		MaxPayloadBytes:   1 << 10,
+		MaxNodeExecutions: 5,
+		MaxWallClock:      time.Minute,
+	}))
+	defer engine.Close()
+
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+}
+
+func TestGuardrails_WallClock(t *testing.T) {
+	wf, err := workflow.Parse([]byte(guardrailWorkflowYAML))
+	require.NoError(t, err)
+
+	// Hold the execution at its first node until the limit passes
+	engine := NewEngine(WithGuardrails(Guardrails{MaxWallClock: 50 * time.Millisecond}))
+	defer engine.Close()
+	engine.Pause()
+
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	requireGuardrailError(t, exec, err, GuardrailWallClock)
+}
+
+func TestGuardrails_FromTunables(t *testing.T) {
+	previous := config.Global().Get()
+	defer config.Global().Update(previous)
+
+	tunables := previous
+	tunables.MaxNodeExecutions = 1
+	tunables.MaxPayloadKB = 2
+	config.Global().Update(tunables)
+
+	engine := NewEngine(WithGuardrails(Guardrails{MaxPayloadBytes: 1 << 20}))
+	defer engine.Close()
+
+	// Explicit limits override the tunables; the rest follow them
+	resolved := engine.resolveGuardrails()
+	assert.Equal(t, int64(1<<20), resolved.MaxPayloadBytes)
+	assert.Equal(t, 1, resolved.MaxNodeExecutions)
+	assert.Zero(t, resolved.MaxWallClock)
+
+	wf, err := workflow.Parse([]byte(guardrailWorkflowYAML))
+	require.NoError(t, err)
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	requireGuardrailError(t, exec, err, GuardrailNodeExecutions)
+}
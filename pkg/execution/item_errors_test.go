@@ -0,0 +1,81 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// itemErrorsWorkflowYAML filters orders whose elements are not all well
+// formed, collecting the skipped ones
+const itemErrorsWorkflowYAML = `
+version: "1.0"
+name: "item-errors-test"
+variables:
+  - name: "orders"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "names"
+    type: "transform"
+    input: "orders"
+    expression: "$.items[?(@.price > 15)].name"
+    output: "names"
+    item_errors_output: "skipped"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "names"
+  - from: "names"
+    to: "end"
+`
+
+func TestTransformItemErrors(t *testing.T) {
+	wf, err := workflow.Parse([]byte(itemErrorsWorkflowYAML))
+	require.NoError(t, err)
+	engine := NewEngine()
+	defer engine.Close()
+
+	exec, err := engine.Execute(context.Background(), wf, map[string]interface{}{
+		"orders": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"name": "a", "price": 10},
+			map[string]interface{}{"price": 20},
+			map[string]interface{}{"name": "c", "price": "expensive"},
+			map[string]interface{}{"name": "d", "price": 50},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, execution.StatusCompleted, exec.Status)
+
+	names, _ := exec.Context.GetVariable("names")
+	assert.Equal(t, []interface{}{"d"}, names)
+
+	skipped, _ := exec.Context.GetVariable("skipped")
+	records, ok := skipped.([]interface{})
+	require.True(t, ok, "item errors are a list, got %T", skipped)
+	require.Len(t, records, 2)
+	missing := records[0].(map[string]interface{})
+	assert.EqualValues(t, 1, missing["index"])
+	assert.Equal(t, "$.items[1].name", missing["path"])
+	assert.Contains(t, missing["error"], "field not found")
+	mismatch := records[1].(map[string]interface{})
+	assert.EqualValues(t, 2, mismatch["index"])
+	assert.Equal(t, "$.items[2]", mismatch["path"])
+	assert.Contains(t, mismatch["error"], "type mismatch")
+
+	// A well formed input yields an empty error list
+	exec, err = engine.Execute(context.Background(), wf, map[string]interface{}{
+		"orders": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"name": "d", "price": 50},
+		}},
+	})
+	require.NoError(t, err)
+	skipped, _ = exec.Context.GetVariable("skipped")
+	assert.Equal(t, []interface{}{}, skipped)
+}
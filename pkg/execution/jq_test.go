@@ -0,0 +1,87 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jqWorkflowYAML totals the orders with a node in the jq language and names
+// them with a jq(...) expression
+const jqWorkflowYAML = `
+version: "1.0"
+name: "jq-test"
+variables:
+  - name: "orders"
+    type: "object"
+  - name: "min_qty"
+    type: "number"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "total"
+    type: "transform"
+    language: "jq"
+    input: "orders"
+    expression: ".items | map(select(.qty >= $min_qty) | .price * .qty) | add"
+    output: "total"
+  - id: "names"
+    type: "transform"
+    input: "orders"
+    expression: "jq(.items[].name)"
+    output: "names"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "total"
+  - from: "total"
+    to: "names"
+  - from: "names"
+    to: "end"
+`
+
+func TestTransformJQ(t *testing.T) {
+	wf, err := workflow.Parse([]byte(jqWorkflowYAML))
+	require.NoError(t, err)
+	engine := NewEngine()
+	defer engine.Close()
+
+	exec, err := engine.Execute(context.Background(), wf, map[string]interface{}{
+		"orders": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"name": "pen", "price": 2.5, "qty": 4},
+			map[string]interface{}{"name": "ink", "price": 7, "qty": 1},
+		}},
+		"min_qty": 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+
+	total, ok := exec.Context.GetVariable("total")
+	require.True(t, ok)
+	assert.EqualValues(t, 10, total)
+	names, ok := exec.Context.GetVariable("names")
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{"pen", "ink"}, names)
+}
+
+func TestEvaluateTransformAs(t *testing.T) {
+	input := map[string]interface{}{"items": []interface{}{1, 2, 3}}
+	variables := map[string]interface{}{"orders": input, "scale": 10}
+
+	result, err := EvaluateTransformAs(context.Background(), "jq", ".items | map(. * $scale)", input, variables)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{10, 20, 30}, result)
+
+	// Without a language, expressions are still detected
+	result, err = EvaluateTransformAs(context.Background(), "", "scale + 1", input, variables)
+	require.NoError(t, err)
+	assert.EqualValues(t, 11, result)
+
+	_, err = EvaluateTransformAs(context.Background(), "xpath", "$.items", input, variables)
+	assert.ErrorContains(t, err, `unknown transform language "xpath"`)
+}
@@ -67,6 +67,32 @@ func (l *Logger) LogNodeExecution(nodeExec *execution.NodeExecution) {
 	)
 }
 
+// LogManualRetry persists a manual retry together with the failed attempt it
+// re-runs. Node executions of a normal run are saved as part of the execution
+// entity, but a retry happens after the execution finished, so both attempts
+// are written to the history here.
+func (l *Logger) LogManualRetry(failed, retry *execution.NodeExecution) {
+	if l.repository == nil {
+		// No repository configured, skip logging
+		return
+	}
+
+	for _, nodeExec := range []*execution.NodeExecution{failed, retry} {
+		if err := l.repository.SaveNodeExecution(nodeExec); err != nil {
+			log.Printf("Warning: failed to log manual retry of node %s: %v", nodeExec.NodeID, err)
+			return
+		}
+	}
+
+	log.Printf("Node %s: %s -> %s (manual retry of %s, duration: %v)",
+		retry.NodeID,
+		retry.NodeType,
+		retry.Status,
+		retry.RetryOf,
+		retry.Duration(),
+	)
+}
+
 // LogVariableChange logs a variable value change.
 func (l *Logger) LogVariableChange(snapshot *execution.VariableSnapshot) {
 	if l.repository == nil {
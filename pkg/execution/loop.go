@@ -41,6 +41,14 @@ func (e *Engine) executeLoopIterations(
 	// Execute loop iterations
 	iterations := make([]LoopIteration, 0, len(items))
 
+	// Isolated iterations start from the variables as they were before the
+	// loop; only promoted variables carry over to later iterations and after
+	// the loop
+	var base map[string]interface{}
+	if node.Scope == workflow.ScopeIsolated {
+		base = exec.Context.CreateSnapshot()
+	}
+
 	for index, item := range items {
 		// Check for context cancellation
 		select {
@@ -61,6 +69,9 @@ func (e *Engine) executeLoopIterations(
 		)
 
 		iterations = append(iterations, iteration)
+		if base != nil {
+			exec.Context.RestoreVariables(base, node.Promote)
+		}
 
 		// Check if loop should break
 		if err != nil {
@@ -106,7 +117,7 @@ func (e *Engine) executeLoopIteration(
 
 	// Check break condition BEFORE executing body (if specified)
 	if node.BreakCondition != "" {
-		broken, err := e.evaluateBreakCondition(node.BreakCondition, exec)
+		broken, err := e.evaluateBreakCondition(ctx, node.BreakCondition, exec)
 		if err != nil {
 			iteration.Error = fmt.Errorf("break condition evaluation failed: %w", err)
 			return iteration, false, iteration.Error
@@ -150,6 +161,7 @@ func (e *Engine) executeLoopIteration(
 // evaluateBreakCondition evaluates the break condition expression
 // Returns true if the loop should break
 func (e *Engine) evaluateBreakCondition(
+	ctx context.Context,
 	condition string,
 	exec *execution.Execution,
 ) (bool, error) {
@@ -159,9 +171,9 @@ func (e *Engine) evaluateBreakCondition(
 	// Get all variables as context for expression evaluation
 	contextData := exec.Context.CreateSnapshot()
 
-	// Evaluate condition as boolean expression
-	// Note: Using context.Background() since we don't have a cancellation context here
-	result, err := transformer.Transform(context.Background(), condition, contextData)
+	// Evaluate condition as boolean expression, within the loop's
+	// expression timeout
+	result, err := transformer.Transform(ctx, condition, contextData)
 	if err != nil {
 		return false, fmt.Errorf("failed to evaluate break condition: %w", err)
 	}
@@ -2,11 +2,14 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/mcpserver"
 	"github.com/dshills/goflow/pkg/transform"
 	"github.com/dshills/goflow/pkg/workflow"
 )
@@ -77,23 +80,55 @@ func (e *Engine) executeMCPToolNode(ctx context.Context, node *workflow.MCPToolN
 		params[key] = substituted
 	}
 
+	return e.invokeMCPTool(ctx, node, server, params, exec, nodeExec)
+}
+
+// invokeMCPTool calls the node's tool with already-resolved parameters and
+// stores the result in the node's output variables.
+func (e *Engine) invokeMCPTool(ctx context.Context, node *workflow.MCPToolNode, server *mcpserver.MCPServer, params map[string]interface{}, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
 	// Record inputs
 	nodeExec.Inputs = params
 
-	// Invoke tool
-	result, err := server.InvokeTool(node.ToolName, params)
-	if err != nil {
-		// Check if it's a recoverable error
-		recoverable := strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "connection")
-
-		return &MCPToolError{
-			ServerID:    node.ServerID,
-			ToolName:    node.ToolName,
-			Message:     fmt.Sprintf("tool invocation failed: %v", err),
-			Recoverable: recoverable,
-			Context: map[string]interface{}{
-				"parameters": params,
-			},
+	// Reuse a result cached for the same server, tool and arguments, or
+	// invoke the tool
+	result, cached := e.cachedToolResult(node, server, params)
+	var streamed string
+	if cached {
+		nodeExec.CacheHit = true
+		if node.StreamOutput != "" {
+			streamed = toolResultText(result)
+		}
+	} else {
+		var err error
+		callCtx, stream := e.withToolProgress(ctx, node, exec, nodeExec)
+		result, err = server.InvokeToolContext(callCtx, node.ToolName, params)
+		if err != nil {
+			// Check if it's a recoverable error
+			recoverable := strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "connection")
+
+			return &MCPToolError{
+				ServerID:    node.ServerID,
+				ToolName:    node.ToolName,
+				Message:     fmt.Sprintf("tool invocation failed: %v", err),
+				Recoverable: recoverable,
+				Context: map[string]interface{}{
+					"parameters": params,
+				},
+			}
+		}
+		if err := e.decodeToolBlobs(result); err != nil {
+			return &MCPToolError{
+				ServerID: node.ServerID,
+				ToolName: node.ToolName,
+				Message:  fmt.Sprintf("invalid binary content: %v", err),
+				Context: map[string]interface{}{
+					"parameters": params,
+				},
+			}
+		}
+		e.cacheToolResult(node, server, params, result)
+		if stream != nil && node.StreamOutput != "" {
+			streamed = stream.finalText(result)
 		}
 	}
 
@@ -112,18 +147,44 @@ func (e *Engine) executeMCPToolNode(ctx context.Context, node *workflow.MCPToolN
 		}
 	}
 
+	// Route content items by type to their configured variables, and store
+	// the complete streamed text
+	routed := routeToolContent(result, node.ContentOutputs)
+	if node.StreamOutput != "" {
+		if routed == nil {
+			routed = make(map[string]interface{}, 1)
+		}
+		routed[node.StreamOutput] = streamed
+	}
+	for variable, value := range routed {
+		if err := exec.Context.SetVariableWithNode(variable, value, nodeExec.ID); err != nil {
+			return fmt.Errorf("failed to set content output variable '%s': %w", variable, err)
+		}
+
+		if e.logger != nil {
+			snapshots := exec.Context.GetVariableHistory()
+			if len(snapshots) > 0 {
+				e.logger.LogVariableChange(&snapshots[len(snapshots)-1])
+			}
+		}
+	}
+
 	// Record outputs
 	nodeExec.Outputs = map[string]interface{}{
 		node.OutputVariable: result,
 	}
+	for variable, value := range routed {
+		nodeExec.Outputs[variable] = value
+	}
 
 	return nil
 }
 
 // executeTransformNode executes a Transform node.
 func (e *Engine) executeTransformNode(ctx context.Context, node *workflow.TransformNode, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
-	// Get input variable value
-	inputValue, exists := exec.Context.GetVariable(node.InputVariable)
+	// Get input variable value; a spilled value is read by the
+	// transformation itself
+	inputValue, exists := exec.Context.GetVariableRef(node.InputVariable)
 	if !exists {
 		return fmt.Errorf("input variable '%s' not found", node.InputVariable)
 	}
@@ -133,24 +194,16 @@ func (e *Engine) executeTransformNode(ctx context.Context, node *workflow.Transf
 		node.InputVariable: inputValue,
 	}
 
-	// Create transformer
-	transformer := transform.NewTransformer()
-
-	// Determine what to pass to the transformer based on expression type
-	// JSONPath queries operate on the input value directly
-	// Expression/Template evaluations need the full variable context
-	var transformData interface{}
-	if e.isJSONPathExpression(node.Expression) {
-		// For JSONPath: pass the input value (data to query)
-		transformData = inputValue
+	// Apply transformation, keeping the values of the elements a [*] or
+	// [?()] query could evaluate when item errors are collected
+	var result interface{}
+	var itemErrors []interface{}
+	var err error
+	if node.ItemErrorsOutput != "" {
+		result, itemErrors, err = evaluatePartialTransform(ctx, node.Expression, inputValue)
 	} else {
-		// For Expression/Template: pass the full context snapshot
-		// This allows expressions to reference any variable
-		transformData = exec.Context.CreateSnapshot()
+		result, err = EvaluateTransformAs(ctx, node.Language, node.Expression, inputValue, exec.Context.CreateSnapshot())
 	}
-
-	// Apply transformation
-	result, err := transformer.Transform(ctx, node.Expression, transformData)
 	if err != nil {
 		return &TransformError{
 			InputVariable: node.InputVariable,
@@ -163,10 +216,34 @@ func (e *Engine) executeTransformNode(ctx context.Context, node *workflow.Transf
 		}
 	}
 
+	// Check the result against the declared schema before anything
+	// downstream can read it
+	if err := node.ValidateOutput(result); err != nil {
+		transformErr := &TransformError{
+			InputVariable: node.InputVariable,
+			Expression:    node.Expression,
+			Message:       err.Error(),
+			Context: map[string]interface{}{
+				"output_value": result,
+				"expression":   node.Expression,
+			},
+		}
+		var mismatchErr *workflow.SchemaMismatchError
+		if errors.As(err, &mismatchErr) {
+			transformErr.Context["mismatches"] = mismatchErr.Mismatches
+		}
+		return transformErr
+	}
+
 	// Store result in context
 	if err := exec.Context.SetVariableWithNode(node.OutputVariable, result, nodeExec.ID); err != nil {
 		return fmt.Errorf("failed to set output variable '%s': %w", node.OutputVariable, err)
 	}
+	if node.ItemErrorsOutput != "" {
+		if err := exec.Context.SetVariableWithNode(node.ItemErrorsOutput, itemErrors, nodeExec.ID); err != nil {
+			return fmt.Errorf("failed to set item errors variable '%s': %w", node.ItemErrorsOutput, err)
+		}
+	}
 
 	// Log variable change
 	if e.logger != nil {
@@ -180,45 +257,46 @@ func (e *Engine) executeTransformNode(ctx context.Context, node *workflow.Transf
 	nodeExec.Outputs = map[string]interface{}{
 		node.OutputVariable: result,
 	}
+	if node.ItemErrorsOutput != "" {
+		nodeExec.Outputs[node.ItemErrorsOutput] = itemErrors
+	}
 
 	return nil
 }
 
-// isJSONPathExpression determines if an expression is a JSONPath query
-// This duplicates the detection logic from transform.detectTransformType for JSONPath
-func (e *Engine) isJSONPathExpression(expr string) bool {
-	trimmed := strings.TrimSpace(expr)
-
-	// Check for JSONPath patterns
-	if strings.HasPrefix(trimmed, "$.") || trimmed == "$" {
-		return true
-	}
-
-	// Check for recursive descent
-	if strings.Contains(trimmed, "..") {
-		return true
+// evaluatePartialTransform runs a [*] or [?()] JSONPath query over input,
+// returning the values it extracted and a record with the index, path and
+// error of each element it skipped
+func evaluatePartialTransform(ctx context.Context, expression string, input interface{}) (interface{}, []interface{}, error) {
+	partial, err := transform.TransformJSONPathPartial(ctx, expression, input)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	// Check for filter expressions
-	if strings.Contains(trimmed, "[?(") {
-		return true
+	values := partial.Values
+	if values == nil {
+		values = []interface{}{}
 	}
-
-	// Check for array wildcard
-	if strings.Contains(trimmed, "[*]") {
-		return true
+	itemErrors := make([]interface{}, 0, len(partial.Errors))
+	for _, itemErr := range partial.Errors {
+		itemErrors = append(itemErrors, map[string]interface{}{
+			"index": itemErr.Index,
+			"path":  itemErr.Path,
+			"error": itemErr.Err.Error(),
+		})
 	}
-
-	return false
+	return values, itemErrors, nil
 }
 
 // substituteVariables replaces variable placeholders (${var_name}) with actual values from context.
 // resolveVariablePath resolves a variable path like "user.name" or "config.database.host"
-// Supports nested field access via dot notation for map[string]interface{} values only
-// Note: Does not currently support array/slice indexing with brackets (e.g., items[0])
+// Supports nested field access via dot notation on maps, numeric indexes on
+// lists (items.0 or items[0]) and blob fields (image.path)
 func (e *Engine) resolveVariablePath(ctx *execution.ExecutionContext, path string) (interface{}, error) {
-	// Split path by dots
-	parts := strings.Split(path, ".")
+	// Split path into fields and indexes
+	parts := transform.SplitTemplatePath(path)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty variable path")
+	}
 
 	// Get the root variable
 	value, exists := ctx.GetVariable(parts[0])
@@ -241,6 +319,26 @@ func (e *Engine) resolveVariablePath(ctx *execution.ExecutionContext, path strin
 			continue
 		}
 
+		// Handle list indexes (e.g. images.0)
+		if list, ok := current.([]interface{}); ok {
+			index, err := strconv.Atoi(field)
+			if err != nil || index < 0 || index >= len(list) {
+				return nil, fmt.Errorf("index '%s' out of range in variable '%s'", field, strings.Join(parts[:i+1], "."))
+			}
+			current = list[index]
+			continue
+		}
+
+		// Handle blob fields; path hands the data to the node as a file
+		if blob, ok := current.(*execution.Blob); ok {
+			val, err := e.blobField(blob, field)
+			if err != nil {
+				return nil, fmt.Errorf("variable '%s': %w", strings.Join(parts[:i+1], "."), err)
+			}
+			current = val
+			continue
+		}
+
 		// If not a map, can't access nested fields
 		return nil, fmt.Errorf("cannot access field '%s' on non-map value (type: %T)", field, current)
 	}
@@ -261,15 +359,28 @@ func (e *Engine) substituteVariables(input string, ctx *execution.ExecutionConte
 			continue
 		}
 
-		placeholder := match[0] // Full match: ${var_name}
-		varPath := match[1]     // Variable path: var_name or var_name.field.subfield
+		placeholder := match[0] // Full match: ${var_name | filter}
 
-		// Get variable value (supports nested field access via dot notation)
-		value, err := e.resolveVariablePath(ctx, varPath)
+		// Split the variable path (var_name or var_name.field.subfield) from
+		// the filters piped after it
+		varPath, filters, err := transform.ParseTemplatePipeline(match[1])
 		if err != nil {
 			return "", err
 		}
 
+		// Get variable value (supports nested field access via dot notation);
+		// a default filter stands in for a missing one
+		value, err := e.resolveVariablePath(ctx, varPath)
+		if err != nil && !transform.HasDefaultFilter(filters) {
+			return "", err
+		}
+		if len(filters) > 0 {
+			value, err = transform.ApplyTemplateFilters(value, filters, ctx.CreateSnapshot())
+			if err != nil {
+				return "", err
+			}
+		}
+
 		// Convert value to string
 		var strValue string
 		switch v := value.(type) {
@@ -307,45 +418,18 @@ func (e *Engine) executeConditionNode(ctx context.Context, node *workflow.Condit
 	// Prepare evaluation context with current variables
 	evalContext := exec.Context.CreateSnapshot()
 
-	// Process the condition expression to handle JSONPath-like syntax
-	processedExpr, err := e.processConditionExpression(ctx, node.Condition, evalContext)
-	if err != nil {
-		return &ConditionError{
-			Expression: node.Condition,
-			Message:    fmt.Sprintf("failed to process condition expression: %v", err),
-			Context: map[string]interface{}{
-				"variables": evalContext,
-			},
-		}
-	}
-
-	// Create expression evaluator
-	evaluator := transform.NewExpressionEvaluator()
-
 	// Evaluate the condition expression
-	result, err := evaluator.Evaluate(ctx, processedExpr, evalContext)
+	boolResult, err := EvaluateCondition(ctx, node.Condition, evalContext)
 	if err != nil {
 		return &ConditionError{
 			Expression: node.Condition,
-			Message:    fmt.Sprintf("condition evaluation failed: %v", err),
+			Message:    err.Error(),
 			Context: map[string]interface{}{
 				"variables": evalContext,
 			},
 		}
 	}
 
-	// Ensure result is boolean
-	boolResult, ok := result.(bool)
-	if !ok {
-		return &ConditionError{
-			Expression: node.Condition,
-			Message:    fmt.Sprintf("condition expression did not evaluate to boolean, got %T", result),
-			Context: map[string]interface{}{
-				"result": result,
-			},
-		}
-	}
-
 	// Record the condition result in outputs
 	nodeExec.Outputs = map[string]interface{}{
 		"result":    boolResult,
@@ -355,27 +439,35 @@ func (e *Engine) executeConditionNode(ctx context.Context, node *workflow.Condit
 	return nil
 }
 
-// processConditionExpression converts JSONPath-style expressions ($.variable) into
-// direct variable references for evaluation. For example:
-// "$.fileSize > 1048576" becomes "fileSize > 1048576"
-func (e *Engine) processConditionExpression(ctx context.Context, expression string, variables map[string]interface{}) (string, error) {
-	// Use regex to find all $.variable patterns
-	pattern := regexp.MustCompile(`\$\.([a-zA-Z_][a-zA-Z0-9_]*)`)
-
-	result := pattern.ReplaceAllStringFunc(expression, func(match string) string {
-		// Extract variable name (remove "$." prefix)
-		varName := match[2:]
-
-		// Check if variable exists
-		if _, exists := variables[varName]; exists {
-			// Return the variable name without the "$." prefix
-			return varName
+// executeSwitchNode executes a Switch node by evaluating its cases in order.
+// The first case that is true is recorded as the result; when none is, the
+// result is the default branch.
+func (e *Engine) executeSwitchNode(ctx context.Context, node *workflow.SwitchNode, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
+	evalContext := exec.Context.CreateSnapshot()
+
+	matched := workflow.SwitchDefaultCase
+	for _, c := range node.Cases {
+		boolResult, err := EvaluateCondition(ctx, c.Condition, evalContext)
+		if err != nil {
+			return &ConditionError{
+				Expression: c.Condition,
+				Message:    fmt.Sprintf("case %q: %v", c.Label, err),
+				Context: map[string]interface{}{
+					"variables": evalContext,
+				},
+			}
+		}
+		if boolResult {
+			matched = c.Label
+			break
 		}
-		// If variable doesn't exist, return as-is and let evaluator handle the error
-		return match
-	})
+	}
 
-	return result, nil
+	nodeExec.Outputs = map[string]interface{}{
+		"result": matched,
+	}
+
+	return nil
 }
 
 // executeParallelNode executes a Parallel node with concurrent branch execution.
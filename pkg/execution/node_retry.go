@@ -0,0 +1,144 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// RetryNode manually re-runs a failed MCP tool node of a finished execution.
+//
+// The node's most recent attempt must have failed. params are the resolved
+// tool arguments to send; pass nil to reuse the arguments of the failed
+// attempt. The retry is recorded as a new node execution whose RetryOf
+// references the failed attempt, so the history keeps both. On success the
+// node's output variables are updated in the execution context; the overall
+// execution status is left unchanged and downstream nodes are not re-run.
+//
+// If the node's server is not connected (the usual case once Execute has
+// returned), it is connected for the retry and disconnected afterwards.
+//
+// The retry is held to the engine's sandbox profile and guardrails like the
+// original run: a node the profile denies is not retried and the policy
+// error is returned, and outputs over the payload limit fail the retry.
+//
+// A tool failure is reported on the returned node execution, not as an error;
+// the error is reserved for retries that could not be attempted.
+func (e *Engine) RetryNode(ctx context.Context, wf *workflow.Workflow, exec *execution.Execution, nodeID types.NodeID, params map[string]interface{}) (*execution.NodeExecution, error) {
+	if wf == nil || exec == nil {
+		return nil, fmt.Errorf("workflow and execution are required")
+	}
+	if exec.Status == execution.StatusRunning {
+		return nil, fmt.Errorf("cannot retry node %s: execution is still running", nodeID)
+	}
+
+	var node workflow.Node
+	for _, n := range wf.Nodes {
+		if n.GetID() == string(nodeID) {
+			node = n
+			break
+		}
+	}
+	if node == nil {
+		return nil, fmt.Errorf("cannot retry node %s: node not found", nodeID)
+	}
+	toolNode, ok := node.(*workflow.MCPToolNode)
+	if !ok {
+		return nil, fmt.Errorf("cannot retry node %s: only mcp_tool nodes can be retried", nodeID)
+	}
+
+	failed := exec.LatestNodeExecution(nodeID)
+	if failed == nil || failed.Status != execution.NodeStatusFailed {
+		return nil, fmt.Errorf("cannot retry node %s: its last attempt did not fail", nodeID)
+	}
+
+	ctx, cancelGuardrails := withGuardrails(ctx, e.resolveGuardrails(), e.sandbox)
+	defer cancelGuardrails()
+	guard := guardFromContext(ctx)
+	if guard != nil {
+		if err := guard.checkSandbox(wf, node); err != nil {
+			return nil, err
+		}
+	}
+
+	if params == nil {
+		params = make(map[string]interface{}, len(failed.Inputs))
+		for key, value := range failed.Inputs {
+			params[key] = value
+		}
+	}
+
+	// Reuse a live connection, otherwise connect just this node's server
+	server, err := e.serverRegistry.Get(toolNode.ServerID)
+	if err != nil {
+		serverConfig := findServerConfig(wf, toolNode.ServerID)
+		if serverConfig == nil {
+			return nil, fmt.Errorf("cannot retry node %s: server '%s' not found", nodeID, toolNode.ServerID)
+		}
+		if err := e.connectServer(ctx, wf, serverConfig); err != nil {
+			return nil, err
+		}
+		defer e.disconnectServer(serverConfig.ID)
+
+		if server, err = e.serverRegistry.Get(toolNode.ServerID); err != nil {
+			return nil, fmt.Errorf("server '%s' not found: %w", toolNode.ServerID, err)
+		}
+	}
+
+	nodeExec := execution.NewNodeExecution(exec.ID, nodeID, toolNode.Type())
+	nodeExec.RetryOf = failed.ID
+	nodeExec.RetryCount = failed.RetryCount + 1
+	nodeExec.Start()
+
+	if err := e.invokeMCPTool(ctx, toolNode, server, params, exec, nodeExec); err != nil {
+		nodeExec.Fail(&execution.NodeError{
+			Type:       execution.ErrorTypeExecution,
+			Message:    err.Error(),
+			StackTrace: string(debug.Stack()),
+		})
+	} else if err := guardAfterRetry(guard, exec, nodeExec); err != nil {
+		nodeExec.Fail(&execution.NodeError{
+			Type:    err.Type,
+			Message: err.Message,
+			Context: err.Context,
+		})
+	} else {
+		nodeExec.Complete(nodeExec.Outputs)
+	}
+
+	_ = exec.AddNodeExecution(nodeExec)
+
+	if e.logger != nil {
+		e.logger.LogManualRetry(failed, nodeExec)
+	}
+
+	return nodeExec, nil
+}
+
+// findServerConfig returns the workflow's configuration for a server
+func findServerConfig(wf *workflow.Workflow, serverID string) *workflow.ServerConfig {
+	for _, serverConfig := range wf.ServerConfigs {
+		if serverConfig.ID == serverID {
+			return serverConfig
+		}
+	}
+	return nil
+}
+
+// guardAfterRetry checks a retried node's payloads and the variables it left
+// behind against the guardrails
+func guardAfterRetry(guard *guard, exec *execution.Execution, nodeExec *execution.NodeExecution) *execution.ExecutionError {
+	if guard == nil {
+		return nil
+	}
+	var execErr *execution.ExecutionError
+	if err := guard.afterNode(exec, nodeExec); errors.As(err, &execErr) {
+		return execErr
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// setupRetryTest builds a workflow whose "fetch" node failed, an engine with
+// a connected mock server, and the failed execution.
+func setupRetryTest(t *testing.T) (*Engine, *storage.SQLiteExecutionRepository, *workflow.Workflow, *execution.Execution) {
+	t.Helper()
+
+	repo, err := storage.NewSQLiteExecutionRepositoryWithPath(filepath.Join(t.TempDir(), "executions.db"))
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	engine := NewEngineWithRepository(repo)
+	t.Cleanup(func() { _ = engine.Close() })
+
+	wf, err := workflow.NewWorkflow("retry-workflow", "Manual retry test")
+	if err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+	_ = wf.AddNode(&workflow.StartNode{ID: "start"})
+	_ = wf.AddNode(&workflow.MCPToolNode{ID: "fetch", ServerID: "server1", ToolName: "fetch", OutputVariable: "page"})
+	_ = wf.AddNode(&workflow.EndNode{ID: "end"})
+
+	// A connected server without a client returns mock results
+	server, err := mcpserver.NewMCPServer("server1", "mock", nil, mcpserver.TransportStdio)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	_ = server.Connect()
+	_ = server.CompleteConnection()
+	server.Tools = []mcpserver.Tool{{Name: "fetch"}}
+	if err := engine.serverRegistry.Register(server); err != nil {
+		t.Fatalf("Failed to register server: %v", err)
+	}
+
+	exec, err := execution.NewExecution(types.WorkflowID(wf.ID), wf.Version, nil)
+	if err != nil {
+		t.Fatalf("Failed to create execution: %v", err)
+	}
+	_ = exec.Start()
+
+	startExec := execution.NewNodeExecution(exec.ID, "start", "start")
+	startExec.Start()
+	startExec.Complete(nil)
+	_ = exec.AddNodeExecution(startExec)
+
+	failed := execution.NewNodeExecution(exec.ID, "fetch", "mcp_tool")
+	failed.Start()
+	failed.Inputs = map[string]interface{}{"url": "http://bad.example"}
+	failed.Fail(&execution.NodeError{Type: execution.ErrorTypeExecution, Message: "connection refused"})
+	_ = exec.AddNodeExecution(failed)
+
+	_ = exec.Fail(&execution.ExecutionError{Type: execution.ErrorTypeExecution, Message: "node fetch failed", NodeID: "fetch"})
+	if err := repo.Save(exec); err != nil {
+		t.Fatalf("Failed to save execution: %v", err)
+	}
+
+	return engine, repo, wf, exec
+}
+
+func TestEngine_RetryNode(t *testing.T) {
+	engine, repo, wf, exec := setupRetryTest(t)
+	failed := exec.LatestNodeExecution("fetch")
+
+	args := map[string]interface{}{"url": "http://good.example"}
+	retry, err := engine.RetryNode(context.Background(), wf, exec, "fetch", args)
+	if err != nil {
+		t.Fatalf("RetryNode() error = %v", err)
+	}
+
+	if retry.Status != execution.NodeStatusCompleted {
+		t.Errorf("retry status = %s, want completed", retry.Status)
+	}
+	if retry.RetryOf != failed.ID || !retry.IsManualRetry() {
+		t.Errorf("retry.RetryOf = %q, want %q", retry.RetryOf, failed.ID)
+	}
+	if retry.RetryCount != 1 {
+		t.Errorf("retry.RetryCount = %d, want 1", retry.RetryCount)
+	}
+	if retry.Inputs["url"] != "http://good.example" {
+		t.Errorf("retry inputs = %v, want edited arguments", retry.Inputs)
+	}
+	if failed.Status != execution.NodeStatusFailed {
+		t.Errorf("failed attempt status changed to %s", failed.Status)
+	}
+	if exec.LatestNodeExecution("fetch") != retry {
+		t.Error("retry should be the node's latest execution")
+	}
+	if _, ok := exec.Context.GetVariable("page"); !ok {
+		t.Error("retry should set the output variable")
+	}
+	if exec.Status != execution.StatusFailed {
+		t.Errorf("execution status = %s, want unchanged failed", exec.Status)
+	}
+
+	// Both attempts are in the history as distinct records
+	loaded, err := repo.Load(exec.ID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var found bool
+	for _, ne := range loaded.NodeExecutions {
+		if ne.ID == retry.ID {
+			found = true
+			if ne.RetryOf != failed.ID {
+				t.Errorf("persisted RetryOf = %q, want %q", ne.RetryOf, failed.ID)
+			}
+		}
+	}
+	if !found || len(loaded.NodeExecutions) != 2 {
+		t.Errorf("history has %d node executions (retry found: %v), want failed attempt and retry", len(loaded.NodeExecutions), found)
+	}
+}
+
+func TestEngine_RetryNode_ReusesFailedArguments(t *testing.T) {
+	engine, _, wf, exec := setupRetryTest(t)
+
+	retry, err := engine.RetryNode(context.Background(), wf, exec, "fetch", nil)
+	if err != nil {
+		t.Fatalf("RetryNode() error = %v", err)
+	}
+	if retry.Inputs["url"] != "http://bad.example" {
+		t.Errorf("retry inputs = %v, want failed attempt's arguments", retry.Inputs)
+	}
+}
+
+func TestEngine_RetryNode_ToolFailure(t *testing.T) {
+	engine, _, wf, exec := setupRetryTest(t)
+	server, _ := engine.serverRegistry.Get("server1")
+	server.Tools = nil
+
+	retry, err := engine.RetryNode(context.Background(), wf, exec, "fetch", nil)
+	if err != nil {
+		t.Fatalf("RetryNode() error = %v", err)
+	}
+	if retry.Status != execution.NodeStatusFailed || retry.Error == nil {
+		t.Errorf("retry status = %s, want failed with error", retry.Status)
+	}
+
+	// A failed retry can itself be retried
+	server.Tools = []mcpserver.Tool{{Name: "fetch"}}
+	second, err := engine.RetryNode(context.Background(), wf, exec, "fetch", nil)
+	if err != nil {
+		t.Fatalf("second RetryNode() error = %v", err)
+	}
+	if second.RetryOf != retry.ID || second.RetryCount != 2 {
+		t.Errorf("second retry = (RetryOf %q, RetryCount %d), want (%q, 2)", second.RetryOf, second.RetryCount, retry.ID)
+	}
+}
+
+func TestEngine_RetryNode_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		nodeID types.NodeID
+		setup  func(exec *execution.Execution)
+	}{
+		{name: "node did not fail", nodeID: "start"},
+		{name: "unknown node", nodeID: "missing"},
+		{name: "not an mcp tool node", nodeID: "end"},
+		{
+			name:   "execution still running",
+			nodeID: "fetch",
+			setup:  func(exec *execution.Execution) { exec.SetStatusForTest(execution.StatusRunning) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, _, wf, exec := setupRetryTest(t)
+			if tt.setup != nil {
+				tt.setup(exec)
+			}
+			before := len(exec.NodeExecutions)
+
+			if _, err := engine.RetryNode(context.Background(), wf, exec, tt.nodeID, nil); err == nil {
+				t.Error("RetryNode() should fail")
+			}
+			if len(exec.NodeExecutions) != before {
+				t.Error("rejected retry should not record a node execution")
+			}
+		})
+	}
+}
+
+func TestEngine_RetryNode_Sandbox(t *testing.T) {
+	engine, _, wf, exec := setupRetryTest(t)
+	engine.sandbox = &workflow.SandboxProfile{Name: "no-tools", DenyNodeTypes: []string{"mcp_tool"}}
+	before := len(exec.NodeExecutions)
+
+	_, err := engine.RetryNode(context.Background(), wf, exec, "fetch", nil)
+	var execErr *execution.ExecutionError
+	if !errors.As(err, &execErr) || execErr.Type != execution.ErrorTypePolicy {
+		t.Fatalf("RetryNode() error = %v, want a policy error", err)
+	}
+	if execErr.NodeID != "fetch" || execErr.Context["sandbox_profile"] != "no-tools" {
+		t.Errorf("policy error = %+v", execErr)
+	}
+	if len(exec.NodeExecutions) != before {
+		t.Error("denied retry should not record a node execution")
+	}
+}
+
+func TestEngine_RetryNode_Guardrails(t *testing.T) {
+	engine, _, wf, exec := setupRetryTest(t)
+	engine.guardrails = Guardrails{MaxPayloadBytes: 1}
+
+	retry, err := engine.RetryNode(context.Background(), wf, exec, "fetch", nil)
+	if err != nil {
+		t.Fatalf("RetryNode() error = %v", err)
+	}
+	if retry.Status != execution.NodeStatusFailed || retry.Error == nil || retry.Error.Type != execution.ErrorTypeGuardrail {
+		t.Errorf("retry over the payload limit = %v, %+v, want a guardrail failure", retry.Status, retry.Error)
+	}
+}
@@ -0,0 +1,79 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outputSchemaWorkflowYAML shapes a user and hands it to a node that must
+// not run when the shape is wrong
+const outputSchemaWorkflowYAML = `
+version: "1.0"
+name: "output-schema-test"
+variables:
+  - name: "data"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "shape"
+    type: "transform"
+    input: "data"
+    expression: "$.user"
+    output: "user"
+    output_schema:
+      type: "object"
+      required: ["email"]
+      properties:
+        email:
+          type: "string"
+        age:
+          type: "integer"
+  - id: "use"
+    type: "transform"
+    input: "user"
+    expression: "$.email"
+    output: "email"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "shape"
+  - from: "shape"
+    to: "use"
+  - from: "use"
+    to: "end"
+`
+
+func TestTransformOutputSchema(t *testing.T) {
+	wf, err := workflow.Parse([]byte(outputSchemaWorkflowYAML))
+	require.NoError(t, err)
+	engine := NewEngine()
+	defer engine.Close()
+
+	// A matching result flows downstream
+	exec, err := engine.Execute(context.Background(), wf, map[string]interface{}{
+		"data": map[string]interface{}{"user": map[string]interface{}{"email": "a@example.com", "age": 30}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+
+	// A mismatch fails the transform before the next node reads it
+	exec, err = engine.Execute(context.Background(), wf, map[string]interface{}{
+		"data": map[string]interface{}{"user": map[string]interface{}{"age": "thirty"}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, execution.StatusFailed, exec.Status)
+	assert.Contains(t, err.Error(), "output does not match output_schema (2 mismatches)")
+	assert.Contains(t, err.Error(), "(root): email is required")
+	assert.Contains(t, err.Error(), "age: Invalid type")
+	assert.NotContains(t, executedNodes(exec), types.NodeID("use"))
+	_, stored := exec.Context.GetVariable("user")
+	assert.False(t, stored)
+}
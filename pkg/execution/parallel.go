@@ -3,6 +3,8 @@ package execution
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
@@ -156,6 +158,10 @@ func (e *Engine) executeWaitAll(
 		return results, fmt.Errorf("%s", errMsg)
 	}
 
+	if err := mergeBranchVariables(exec, node, branchExecs); err != nil {
+		return results, err
+	}
+
 	// Return error if any branch failed (after successful merging)
 	if firstErr != nil {
 		return results, firstErr
@@ -279,6 +285,10 @@ func (e *Engine) executeWaitAny(
 		_ = mergeErrors
 	}
 
+	if err := mergeBranchVariables(exec, node, branchExecs); err != nil {
+		return results, err
+	}
+
 	return results, nil
 }
 
@@ -365,8 +375,10 @@ func (e *Engine) executeWaitFirst(
 	// Even though we terminate early, we want variables from any branches that completed
 	// before cancellation to be available in the parent context
 	var mergeErrors []error
+	completed := make([]*execution.Execution, len(branchExecs))
 	for i, branchExec := range branchExecs {
 		if branchExec != nil && results[i].Error == nil {
+			completed[i] = branchExec
 			if err := e.mergeBranchContext(exec, branchExec); err != nil {
 				// CRITICAL: Collect merge errors for visibility and debugging
 				// For wait_first strategy, merge failures may affect workflow correctness
@@ -397,6 +409,10 @@ func (e *Engine) executeWaitFirst(
 		return results, fmt.Errorf("%s", errMsg)
 	}
 
+	if err := mergeBranchVariables(exec, node, completed); err != nil {
+		return results, err
+	}
+
 	// Return result from first branch
 	return results, results[firstBranchIndex].Error
 }
@@ -420,11 +436,9 @@ func (e *Engine) createBranchContext(parentExec *execution.Execution) (*executio
 	return branchExec, nil
 }
 
-// mergeBranchContext merges variable changes and node executions from branch back to parent context
+// mergeBranchContext merges node executions from branch back to parent context.
+// Variables are merged for all branches at once by mergeBranchVariables.
 func (e *Engine) mergeBranchContext(parentExec *execution.Execution, branchExec *execution.Execution) error {
-	// Copy all variables from branch context to parent context
-	branchExec.Context.CopyVariablesTo(parentExec.Context)
-
 	// Merge node executions from branch to parent
 	// This allows the parent execution to track all nodes executed in branches
 	parentExec.NodeExecutions = append(parentExec.NodeExecutions, branchExec.NodeExecutions...)
@@ -435,6 +449,71 @@ func (e *Engine) mergeBranchContext(parentExec *execution.Execution, branchExec
 	return nil
 }
 
+// mergeBranchVariables copies the variables the branches changed back to the
+// parent context. Only changed variables are merged, so a branch's stale copy
+// of a variable never overwrites another branch's write. Isolated branches
+// merge only their promoted variables, and branches writing different values
+// to one variable are resolved by the node's on_conflict policy. Nil entries
+// are skipped.
+func mergeBranchVariables(parentExec *execution.Execution, node *workflow.ParallelNode, branchExecs []*execution.Execution) error {
+	base := parentExec.Context.CreateSnapshot()
+	promoted := make(map[string]bool, len(node.Promote))
+	for _, name := range node.Promote {
+		promoted[name] = true
+	}
+
+	// Values written to each variable, in branch order
+	written := make(map[string][]interface{})
+	var names []string
+	for _, branchExec := range branchExecs {
+		if branchExec == nil {
+			continue
+		}
+		for name, value := range branchExec.Context.ChangedVariables(base) {
+			if node.Scope == workflow.ScopeIsolated && !promoted[name] {
+				continue
+			}
+			if _, seen := written[name]; !seen {
+				names = append(names, name)
+			}
+			written[name] = append(written[name], value)
+		}
+	}
+	sort.Strings(names)
+
+	merged := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		values := written[name]
+		value := values[len(values)-1]
+		if branchValuesConflict(values) {
+			switch node.OnConflict {
+			case workflow.ConflictError:
+				return fmt.Errorf("parallel branches wrote different values to variable '%s'", name)
+			case workflow.ConflictCollect:
+				value = values
+			}
+		}
+		merged[name] = value
+	}
+
+	for _, name := range names {
+		if err := parentExec.Context.SetVariable(name, merged[name]); err != nil {
+			return fmt.Errorf("failed to merge variable '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// branchValuesConflict reports whether branches wrote different values
+func branchValuesConflict(values []interface{}) bool {
+	for _, v := range values[1:] {
+		if !reflect.DeepEqual(v, values[0]) {
+			return true
+		}
+	}
+	return false
+}
+
 // executeBranchNodes executes a sequence of nodes in a parallel branch
 func (e *Engine) executeBranchNodes(
 	ctx context.Context,
@@ -0,0 +1,92 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+)
+
+// Pause stops executions on this engine before their next node starts, so
+// the execution context can be inspected and edited; nodes already running
+// finish first. Pausing an engine that is already paused has no effect.
+// A running execution emits EventExecutionPaused.
+func (e *Engine) Pause() {
+	e.pauseMu.Lock()
+	paused := e.pauseGate == nil
+	if paused {
+		e.pauseGate = make(chan struct{})
+	}
+	e.pauseMu.Unlock()
+
+	if paused {
+		e.emitPauseEvent(EventExecutionPaused)
+	}
+}
+
+// Resume lets paused executions continue with their next node. A running
+// execution emits EventExecutionResumed.
+func (e *Engine) Resume() {
+	e.pauseMu.Lock()
+	resumed := e.pauseGate != nil
+	if resumed {
+		close(e.pauseGate)
+		e.pauseGate = nil
+	}
+	e.pauseMu.Unlock()
+
+	if resumed {
+		e.emitPauseEvent(EventExecutionResumed)
+	}
+}
+
+// IsPaused reports whether the engine is paused.
+func (e *Engine) IsPaused() bool {
+	e.pauseMu.Lock()
+	defer e.pauseMu.Unlock()
+	return e.pauseGate != nil
+}
+
+// waitIfPaused blocks while the engine is paused. It returns the context's
+// error if the execution is cancelled while waiting.
+func (e *Engine) waitIfPaused(ctx context.Context) error {
+	e.pauseMu.Lock()
+	gate := e.pauseGate
+	e.pauseMu.Unlock()
+	if gate == nil {
+		return nil
+	}
+
+	select {
+	case <-gate:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// emitPauseEvent reports a pause or resume of the running execution, if
+// there is one, with the node it was running.
+func (e *Engine) emitPauseEvent(eventType ExecutionEventType) {
+	e.monitorMu.RLock()
+	monitor := e.monitor
+	running := e.cancelRun != nil
+	e.monitorMu.RUnlock()
+
+	if monitor == nil || !running || monitor.exec == nil {
+		return
+	}
+
+	exec := monitor.exec
+	metadata := map[string]interface{}{}
+	if currentNode := exec.Context.CurrentNode(); currentNode != nil {
+		metadata["current_node"] = string(*currentNode)
+	}
+	monitor.Emit(ExecutionEvent{
+		Type:        eventType,
+		Timestamp:   time.Now(),
+		ExecutionID: exec.ID,
+		Status:      execution.StatusRunning, // exec.Status belongs to the engine goroutine
+		Metadata:    metadata,
+	})
+}
@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_PauseAndResume(t *testing.T) {
+	yaml := `
+version: "1.0"
+name: "test-workflow"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "node1"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "node1"
+  - from: "node1"
+    to: "end"
+`
+	wf, err := workflow.Parse([]byte(yaml))
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var events []ExecutionEventType
+	engine := NewEngine(WithEventHandler(func(event ExecutionEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event.Type)
+	}))
+	defer engine.Close()
+	engine.Pause()
+	engine.Pause() // Pausing twice needs a single resume
+	assert.True(t, engine.IsPaused())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := engine.Execute(context.Background(), wf, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("execution finished while paused: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	engine.Resume()
+	assert.False(t, engine.IsPaused())
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution did not resume")
+	}
+
+	// The event stream shows the pause from the start and a single resume
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, len(events), 3)
+	assert.Equal(t, []ExecutionEventType{EventExecutionStarted, EventExecutionPaused}, events[:2])
+	var resumed int
+	for _, eventType := range events {
+		if eventType == EventExecutionResumed {
+			resumed++
+		}
+	}
+	assert.Equal(t, 1, resumed)
+	assert.Equal(t, EventExecutionCompleted, events[len(events)-1])
+}
+
+func TestEngine_CancelWhilePaused(t *testing.T) {
+	engine := NewEngine()
+	defer engine.Close()
+	engine.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, engine.waitIfPaused(ctx), context.Canceled)
+
+	engine.Resume()
+	assert.NoError(t, engine.waitIfPaused(ctx))
+}
@@ -0,0 +1,265 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+)
+
+// Node types the performance report breaks time down by
+const (
+	profileNodeTypeMCPTool   = "mcp_tool"
+	profileNodeTypeTransform = "transform"
+)
+
+// NodeProfile is the performance of one node across all of its executions
+// in a run: loop iterations, parallel branches and manual retries.
+type NodeProfile struct {
+	NodeID   types.NodeID         `json:"node_id"`
+	NodeType string               `json:"node_type"`
+	Status   execution.NodeStatus `json:"status"` // Status of the latest execution
+
+	// Executions is how many times the node ran; Retries counts the
+	// automatic and manual retries of failed attempts
+	Executions int `json:"executions"`
+	Retries    int `json:"retries"`
+	Failures   int `json:"failures"`
+	// CacheHits counts the executions served from the tool result cache
+	CacheHits int `json:"cache_hits"`
+
+	TotalDuration time.Duration `json:"total_duration"`
+	MaxDuration   time.Duration `json:"max_duration"`
+
+	// Payload sizes, as the JSON encoding of the node's inputs and outputs
+	InputBytes  int `json:"input_bytes"`
+	OutputBytes int `json:"output_bytes"`
+}
+
+// AverageDuration returns the mean duration of the node's executions
+func (p NodeProfile) AverageDuration() time.Duration {
+	if p.Executions == 0 {
+		return 0
+	}
+	return p.TotalDuration / time.Duration(p.Executions)
+}
+
+// PerformanceReport summarizes where an execution spent its time, for
+// finding slow nodes and tracking performance regressions between runs.
+type PerformanceReport struct {
+	ExecutionID types.ExecutionID `json:"execution_id"`
+	WorkflowID  types.WorkflowID  `json:"workflow_id"`
+	Status      execution.Status  `json:"status"`
+	StartedAt   time.Time         `json:"started_at"`
+	Duration    time.Duration     `json:"duration"`
+
+	// Nodes are ordered slowest first, by total duration
+	Nodes []NodeProfile `json:"nodes"`
+
+	// Time spent waiting on MCP tool calls, in transforms and in every
+	// other kind of node. MCPCalls counts calls made to a server;
+	// CacheHits the tool calls answered from the result cache instead.
+	MCPCalls      int           `json:"mcp_calls"`
+	CacheHits     int           `json:"cache_hits"`
+	MCPTime       time.Duration `json:"mcp_time"`
+	Transforms    int           `json:"transforms"`
+	TransformTime time.Duration `json:"transform_time"`
+	OtherTime     time.Duration `json:"other_time"`
+
+	Retries     int `json:"retries"`
+	InputBytes  int `json:"input_bytes"`
+	OutputBytes int `json:"output_bytes"`
+}
+
+// BuildPerformanceReport profiles an execution from its node executions.
+// It can be called while the execution is running; nodes still running
+// count no time yet.
+func BuildPerformanceReport(exec *execution.Execution) (*PerformanceReport, error) {
+	if exec == nil {
+		return nil, fmt.Errorf("execution cannot be nil")
+	}
+
+	report := &PerformanceReport{
+		ExecutionID: exec.ID,
+		WorkflowID:  exec.WorkflowID,
+		Status:      exec.Status,
+		StartedAt:   exec.StartedAt,
+		Duration:    exec.Duration(),
+	}
+
+	profiles := make(map[types.NodeID]*NodeProfile)
+	for _, nodeExec := range exec.NodeExecutions {
+		profile, exists := profiles[nodeExec.NodeID]
+		if !exists {
+			profile = &NodeProfile{NodeID: nodeExec.NodeID, NodeType: nodeExec.NodeType}
+			profiles[nodeExec.NodeID] = profile
+		}
+
+		duration := nodeExec.Duration()
+		profile.Status = nodeExec.Status
+		profile.Executions++
+		profile.TotalDuration += duration
+		profile.MaxDuration = max(profile.MaxDuration, duration)
+		if nodeExec.IsManualRetry() {
+			profile.Retries++ // RetryCount numbers manual retries; it is not a count
+		} else {
+			profile.Retries += nodeExec.RetryCount
+		}
+		if nodeExec.Status == execution.NodeStatusFailed {
+			profile.Failures++
+		}
+		if nodeExec.CacheHit {
+			profile.CacheHits++
+		}
+		profile.InputBytes += payloadSize(nodeExec.Inputs)
+		profile.OutputBytes += payloadSize(nodeExec.Outputs)
+
+		switch nodeExec.NodeType {
+		case profileNodeTypeMCPTool:
+			if nodeExec.CacheHit {
+				report.CacheHits++
+			} else {
+				report.MCPCalls++
+			}
+			report.MCPTime += duration
+		case profileNodeTypeTransform:
+			report.Transforms++
+			report.TransformTime += duration
+		default:
+			report.OtherTime += duration
+		}
+	}
+
+	report.Nodes = make([]NodeProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		report.Nodes = append(report.Nodes, *profile)
+		report.Retries += profile.Retries
+		report.InputBytes += profile.InputBytes
+		report.OutputBytes += profile.OutputBytes
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		a, b := report.Nodes[i], report.Nodes[j]
+		if a.TotalDuration != b.TotalDuration {
+			return a.TotalDuration > b.TotalDuration
+		}
+		return a.NodeID < b.NodeID
+	})
+
+	return report, nil
+}
+
+// payloadSize returns the size of a node's inputs or outputs encoded as JSON
+func payloadSize(payload map[string]interface{}) int {
+	if len(payload) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// ExportJSON returns the report as JSON. Durations are in nanoseconds.
+func (r *PerformanceReport) ExportJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// ExportCSV returns one row per node, durations in milliseconds, for
+// comparing runs in a spreadsheet or a regression script.
+func (r *PerformanceReport) ExportCSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{
+		"execution_id", "node_id", "node_type", "status", "executions", "retries", "failures",
+		"total_ms", "avg_ms", "max_ms", "input_bytes", "output_bytes", "cache_hits",
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, node := range r.Nodes {
+		record := []string{
+			r.ExecutionID.String(),
+			string(node.NodeID),
+			node.NodeType,
+			string(node.Status),
+			strconv.Itoa(node.Executions),
+			strconv.Itoa(node.Retries),
+			strconv.Itoa(node.Failures),
+			formatMillis(node.TotalDuration),
+			formatMillis(node.AverageDuration()),
+			formatMillis(node.MaxDuration),
+			strconv.Itoa(node.InputBytes),
+			strconv.Itoa(node.OutputBytes),
+			strconv.Itoa(node.CacheHits),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatMillis formats a duration as milliseconds with microsecond precision
+func formatMillis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+// DefaultReportsDir returns the directory performance reports are exported
+// to: $GOFLOW_REPORTS_DIR, or "reports" in the GoFlow config directory.
+func DefaultReportsDir() string {
+	if dir := os.Getenv("GOFLOW_REPORTS_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "reports")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "reports")
+	}
+	return filepath.Join(homeDir, ".goflow", "reports")
+}
+
+// WriteFiles exports the report as <execution-id>.json and
+// <execution-id>.csv in dir, returning the paths written.
+func (r *PerformanceReport) WriteFiles(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create reports directory: %w", err)
+	}
+
+	exports := []struct {
+		ext    string
+		export func() ([]byte, error)
+	}{
+		{".json", r.ExportJSON},
+		{".csv", r.ExportCSV},
+	}
+	paths := make([]string, 0, len(exports))
+	for _, e := range exports {
+		data, err := e.export()
+		if err != nil {
+			return paths, err
+		}
+		path := filepath.Join(dir, r.ExecutionID.String()+e.ext)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return paths, fmt.Errorf("failed to write report: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,103 @@
+package execution
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// profiledNode adds a finished node execution of the given duration
+func profiledNode(exec *execution.Execution, start time.Time, nodeID, nodeType string, duration time.Duration, failed bool) *execution.NodeExecution {
+	nodeExec := execution.NewNodeExecution(exec.ID, types.NodeID(nodeID), nodeType)
+	nodeExec.Status = execution.NodeStatusCompleted
+	nodeExec.StartedAt = start
+	nodeExec.CompletedAt = start.Add(duration)
+	nodeExec.Inputs = map[string]interface{}{"path": "/tmp/a"}
+	nodeExec.Outputs = map[string]interface{}{"content": "hello"}
+	if failed {
+		nodeExec.Status = execution.NodeStatusFailed
+		nodeExec.Outputs = nil
+	}
+	exec.NodeExecutions = append(exec.NodeExecutions, nodeExec)
+	return nodeExec
+}
+
+func newProfiledExecution(t *testing.T) *execution.Execution {
+	t.Helper()
+	exec, err := execution.NewExecution("etl", "1.0", nil)
+	require.NoError(t, err)
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	profiledNode(exec, start, "start", "start", time.Millisecond, false)
+	profiledNode(exec, start, "fetch", "mcp_tool", 300*time.Millisecond, true)
+	retry := profiledNode(exec, start, "fetch", "mcp_tool", 200*time.Millisecond, false)
+	retry.RetryOf = exec.NodeExecutions[1].ID
+	retry.RetryCount = 1
+	profiledNode(exec, start, "shape", "transform", 50*time.Millisecond, false)
+	cached := profiledNode(exec, start, "lookup", "mcp_tool", 0, false)
+	cached.CacheHit = true
+	return exec
+}
+
+func TestBuildPerformanceReport(t *testing.T) {
+	report, err := BuildPerformanceReport(newProfiledExecution(t))
+	require.NoError(t, err)
+
+	require.Len(t, report.Nodes, 4)
+	fetch := report.Nodes[0]
+	assert.Equal(t, types.NodeID("fetch"), fetch.NodeID, "slowest node first")
+	assert.Equal(t, 2, fetch.Executions)
+	assert.Equal(t, 1, fetch.Retries)
+	assert.Equal(t, 1, fetch.Failures)
+	assert.Equal(t, execution.NodeStatusCompleted, fetch.Status)
+	assert.Equal(t, 500*time.Millisecond, fetch.TotalDuration)
+	assert.Equal(t, 300*time.Millisecond, fetch.MaxDuration)
+	assert.Equal(t, 250*time.Millisecond, fetch.AverageDuration())
+	assert.Equal(t, len(`{"path":"/tmp/a"}`)*2, fetch.InputBytes)
+	assert.Equal(t, len(`{"content":"hello"}`), fetch.OutputBytes)
+
+	assert.Equal(t, 2, report.MCPCalls)
+	assert.Equal(t, 1, report.CacheHits, "cache hits are not server calls")
+	assert.Equal(t, 500*time.Millisecond, report.MCPTime)
+	assert.Equal(t, 1, report.Transforms)
+	assert.Equal(t, 50*time.Millisecond, report.TransformTime)
+	assert.Equal(t, time.Millisecond, report.OtherTime)
+	assert.Equal(t, 1, report.Retries)
+
+	_, err = BuildPerformanceReport(nil)
+	assert.Error(t, err)
+}
+
+func TestPerformanceReport_Export(t *testing.T) {
+	report, err := BuildPerformanceReport(newProfiledExecution(t))
+	require.NoError(t, err)
+
+	data, err := report.ExportCSV()
+	require.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	assert.Equal(t, "node_id", records[0][1])
+	assert.Equal(t, []string{"fetch", "mcp_tool", "completed", "2", "1", "1", "500.000", "250.000", "300.000"}, records[1][1:10])
+
+	dir := t.TempDir()
+	paths, err := report.WriteFiles(filepath.Join(dir, "reports"))
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+
+	data, err = os.ReadFile(paths[0])
+	require.NoError(t, err)
+	var decoded PerformanceReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report.MCPTime, decoded.MCPTime)
+	assert.Equal(t, report.ExecutionID.String()+".csv", filepath.Base(paths[1]))
+}
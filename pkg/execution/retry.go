@@ -248,6 +248,10 @@ func isErrorTypeMatch(pattern string, errType execution.ErrorType) bool {
 		return errType == execution.ErrorTypeData
 	case "execution", "execution_error":
 		return errType == execution.ErrorTypeExecution
+	case "guardrail", "guardrail_error":
+		return errType == execution.ErrorTypeGuardrail
+	case "policy", "policy_error":
+		return errType == execution.ErrorTypePolicy
 	case "rate_limit", "rate_limited", "throttle", "throttled":
 		// Rate limiting typically manifests as connection or execution errors
 		// Check error message for rate limit indicators
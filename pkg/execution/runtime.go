@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dshills/goflow/pkg/config"
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/events"
 	"github.com/dshills/goflow/pkg/mcp"
 	"github.com/dshills/goflow/pkg/mcpserver"
 	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goflow/pkg/transform"
 	"github.com/dshills/goflow/pkg/workflow"
 )
 
@@ -21,10 +25,29 @@ type Engine struct {
 	execRepository *storage.SQLiteExecutionRepository
 	logger         *Logger
 	monitorMu      sync.RWMutex
-	monitor        *monitor                    // Current execution monitor (set during Execute)
-	activeClients  map[string]*mcp.StdioClient // Track active clients for cleanup
+	monitor        *monitor              // Current execution monitor (set during Execute)
+	activeClients  map[string]mcp.Client // Track active clients for cleanup
+	secrets        mcp.SecretResolver    // Resolves the credential references of server auth
 	clientsMu      sync.RWMutex
-	timeout        time.Duration // Default timeout for workflow executions (0 = no timeout)
+	timeout        time.Duration        // Default timeout for workflow executions (0 = no timeout)
+	eventQueueSize atomic.Int64         // Buffer size for monitor subscriptions (0 = use config tunables)
+	snapshotSink   SnapshotSink         // Optional external snapshot persistence
+	snapshotOpts   SnapshotSinkOptions  // Backpressure settings for snapshotSink
+	snapshots      *snapshotDispatcher  // Current snapshot dispatcher (guarded by monitorMu)
+	eventHandler   func(ExecutionEvent) // Optional synchronous observer of every event
+	pauseMu        sync.Mutex
+	pauseGate      chan struct{}            // Non-nil while paused; closed on resume
+	guardrails     Guardrails               // Resource limits (zero fields = use config tunables)
+	sandbox        *workflow.SandboxProfile // Node types and tool categories runs may not execute
+	cancelRun      context.CancelCauseFunc  // Cancels the current execution (guarded by monitorMu)
+	eventBus       *events.Bus              // Bus every execution event is published to (nil = none)
+	approvalMu     sync.Mutex
+	approvals      map[types.NodeID]*pendingApproval // Approval nodes waiting for a decision
+	toolCache      *ExecutionCache                   // Results of mcp_tool nodes with a cache_ttl, shared by every execution (see WithToolCache)
+	blobLimits     BlobLimits                        // Size limits of binary tool content (zero fields = defaults)
+	blobsOnce      sync.Once
+	blobStore      *blobStore // Blob files of every execution (created on first use)
+	spillThreshold int64      // Variables larger than this, as JSON, are spilled to disk (0 = use config tunables)
 }
 
 // EngineOption is a functional option for engine configuration.
@@ -42,6 +65,49 @@ func WithTimeout(timeout time.Duration) EngineOption {
 	}
 }
 
+// WithSecretResolver configures where the credential references of server
+// auth (token_ref, key_ref, client_secret_ref) are looked up.
+// The default is the system keyring managed by `goflow credential`.
+func WithSecretResolver(secrets mcp.SecretResolver) EngineOption {
+	return func(e *Engine) {
+		e.secrets = secrets
+	}
+}
+
+// WithEventQueueSize configures the buffer size of execution event subscriptions.
+// Pass 0 or a negative size to follow the event_queue_size tunable.
+func WithEventQueueSize(size int) EngineOption {
+	return func(e *Engine) {
+		e.SetEventQueueSize(size)
+	}
+}
+
+// SetEventQueueSize changes the buffer size of execution event subscriptions.
+// The new size applies to subscriptions created after the call.
+func (e *Engine) SetEventQueueSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	e.eventQueueSize.Store(int64(size))
+
+	e.monitorMu.Lock()
+	defer e.monitorMu.Unlock()
+	if e.monitor != nil {
+		e.monitor.mu.Lock()
+		e.monitor.queueSize = e.resolveEventQueueSize()
+		e.monitor.mu.Unlock()
+	}
+}
+
+// resolveEventQueueSize returns the explicit queue size if one was set,
+// otherwise the current event_queue_size tunable.
+func (e *Engine) resolveEventQueueSize() int {
+	if size := e.eventQueueSize.Load(); size > 0 {
+		return int(size)
+	}
+	return config.Global().Get().EventQueueSize
+}
+
 // NewEngine creates a new execution engine with default configuration.
 func NewEngine(opts ...EngineOption) *Engine {
 	// Create execution repository
@@ -58,8 +124,11 @@ func NewEngine(opts ...EngineOption) *Engine {
 		serverRegistry: mcpserver.NewRegistry(),
 		execRepository: repo,
 		logger:         logger,
-		activeClients:  make(map[string]*mcp.StdioClient),
+		activeClients:  make(map[string]mcp.Client),
+		secrets:        storage.NewKeyringCredentialStore(),
 		timeout:        0, // No timeout by default
+		eventBus:       events.Default(),
+		toolCache:      NewExecutionCache(),
 	}
 
 	// Apply options
@@ -78,8 +147,11 @@ func NewEngineWithRepository(repo *storage.SQLiteExecutionRepository, opts ...En
 		serverRegistry: mcpserver.NewRegistry(),
 		execRepository: repo,
 		logger:         logger,
-		activeClients:  make(map[string]*mcp.StdioClient),
+		activeClients:  make(map[string]mcp.Client),
+		secrets:        storage.NewKeyringCredentialStore(),
 		timeout:        0, // No timeout by default
+		eventBus:       events.Default(),
+		toolCache:      NewExecutionCache(),
 	}
 
 	// Apply options
@@ -109,6 +181,11 @@ func (e *Engine) Execute(ctx context.Context, wf *workflow.Workflow, inputs map[
 		return nil, NewOperationalError("creating execution", wf.ID, "", err)
 	}
 
+	// Spill large variables to disk
+	if threshold := e.resolveSpillThreshold(); threshold > 0 {
+		exec.Context.SetSpiller(&variableSpiller{threshold: threshold, blobs: e.blobs()})
+	}
+
 	// Set up timeout context if configured
 	var cancel context.CancelFunc
 	execCtx := ctx
@@ -132,6 +209,14 @@ func (e *Engine) Execute(ctx context.Context, wf *workflow.Workflow, inputs map[
 		}
 	}()
 
+	// Let Cancel stop the execution with a reason
+	execCtx, cancelRun := context.WithCancelCause(execCtx)
+	defer cancelRun(nil)
+
+	// Enforce resource guardrails and the sandbox profile on the execution
+	execCtx, cancelGuardrails := withGuardrails(execCtx, e.resolveGuardrails(), e.sandbox)
+	defer cancelGuardrails()
+
 	// Create execution monitor
 	e.monitorMu.Lock()
 	e.monitor = &monitor{
@@ -139,16 +224,30 @@ func (e *Engine) Execute(ctx context.Context, wf *workflow.Workflow, inputs map[
 		totalNodes:  len(wf.Nodes),
 		subscribers: make([]*subscription, 0),
 		closed:      false,
+		queueSize:   e.resolveEventQueueSize(),
+		handler:     e.eventHandler,
+		bus:         e.eventBus,
+	}
+	if e.snapshotSink != nil {
+		e.snapshots = newSnapshotDispatcher(e.snapshotSink, e.snapshotOpts, exec.ID)
 	}
+	e.cancelRun = cancelRun
 	e.monitorMu.Unlock()
 	defer func() {
 		e.monitorMu.Lock()
+		e.cancelRun = nil
+		snapshots := e.snapshots
 		if e.monitor != nil {
 			e.monitor.Close()
 			// Keep monitor accessible after execution for tests/TUI
 			// e.monitor = nil
 		}
 		e.monitorMu.Unlock()
+
+		// Flush snapshots outside the lock, bounded by the backpressure budget
+		if snapshots != nil {
+			snapshots.Close()
+		}
 	}()
 
 	// Validate required input variables
@@ -174,6 +273,11 @@ func (e *Engine) Execute(ctx context.Context, wf *workflow.Workflow, inputs map[
 	// Emit execution started event
 	e.emitExecutionStarted(exec)
 
+	// An engine paused before the run holds it at the first node
+	if e.IsPaused() {
+		e.emitPauseEvent(EventExecutionPaused)
+	}
+
 	// Connect to MCP servers
 	if err := e.connectServers(execCtx, wf); err != nil {
 		opErr := NewOperationalError("connecting to MCP servers", string(exec.WorkflowID), "", err)
@@ -196,6 +300,21 @@ func (e *Engine) Execute(ctx context.Context, wf *workflow.Workflow, inputs map[
 
 	// Execute workflow
 	if err := e.executeWorkflow(execCtx, wf, exec); err != nil {
+		// A guardrail aborts the execution by cancelling its context
+		if violation := guardrailViolation(execCtx); violation != nil {
+			if violation.NodeID == "" {
+				if currentNode := exec.Context.CurrentNode(); currentNode != nil {
+					violation.NodeID = *currentNode
+				}
+			}
+			_ = exec.Fail(violation)
+			e.emitExecutionFailed(exec, violation)
+			if e.logger != nil {
+				e.logger.LogExecutionComplete(exec)
+			}
+			return exec, violation
+		}
+
 		// Check if context was cancelled or timed out
 		ctxErr := execCtx.Err()
 		switch ctxErr {
@@ -216,8 +335,12 @@ func (e *Engine) Execute(ctx context.Context, wf *workflow.Workflow, inputs map[
 			_ = exec.Timeout(timeoutNode, execErr)
 			e.emitExecutionFailed(exec, execErr)
 		case context.Canceled:
-			// Context was cancelled
-			_ = exec.Cancel()
+			// Context was cancelled, through Cancel or by the caller
+			var cancelNode types.NodeID
+			if currentNode := exec.Context.CurrentNode(); currentNode != nil {
+				cancelNode = *currentNode
+			}
+			_ = exec.CancelWithReason(cancelReason(execCtx), cancelNode)
 			e.emitExecutionCancelled(exec)
 		default:
 			// Execution failed for other reasons
@@ -309,7 +432,13 @@ func (e *Engine) executeNodePath(ctx context.Context, node workflow.Node, wf *wo
 	// Execute the current node
 	nodeExec, err := e.executeNodeAndGetExecution(ctx, node, wf, exec)
 	if err != nil {
-		return err
+		// A failure is handled by the node's error edges, unless the
+		// execution itself was cancelled, timed out or hit a guardrail
+		errorTargets := errorEdgeTargets(nodeID, wf)
+		if len(errorTargets) == 0 || ctx.Err() != nil {
+			return err
+		}
+		return e.executeNextNodes(ctx, errorTargets, wf, exec, nodeMap, visited)
 	}
 
 	// If this is an end node, stop here
@@ -329,7 +458,11 @@ func (e *Engine) executeNodePath(ctx context.Context, node workflow.Node, wf *wo
 		}
 	}
 
-	// Execute next nodes
+	return e.executeNextNodes(ctx, nextNodes, wf, exec, nodeMap, visited)
+}
+
+// executeNextNodes follows each of nextNodes in order.
+func (e *Engine) executeNextNodes(ctx context.Context, nextNodes []string, wf *workflow.Workflow, exec *execution.Execution, nodeMap map[string]workflow.Node, visited map[string]bool) error {
 	for _, nextNodeID := range nextNodes {
 		nextNode, exists := nodeMap[nextNodeID]
 		if !exists {
@@ -369,10 +502,11 @@ func (e *Engine) executeNodeAndGetExecution(ctx context.Context, node workflow.N
 
 // getNextNodes determines which nodes to execute next based on edges and condition results.
 func (e *Engine) getNextNodes(currentNodeID string, wf *workflow.Workflow, nodeExec *execution.NodeExecution) ([]string, error) {
-	// Get all edges from current node
+	// Get all edges from current node, except error edges, which are only
+	// followed when it fails
 	var edges []*workflow.Edge
 	for _, edge := range wf.Edges {
-		if edge.FromNodeID == currentNodeID {
+		if edge.FromNodeID == currentNodeID && !edge.OnError {
 			edges = append(edges, edge)
 		}
 	}
@@ -416,6 +550,24 @@ func (e *Engine) getNextNodes(currentNodeID string, wf *workflow.Workflow, nodeE
 		return []string{matchedEdge.ToNodeID}, nil
 	}
 
+	// If this is a switch node, follow the edge of the matched case
+	if nodeExec != nil && nodeExec.NodeType == "switch" {
+		label, ok := nodeExec.Outputs["result"].(string)
+		if !ok {
+			baseErr := fmt.Errorf("switch node %s did not produce a case", currentNodeID)
+			return nil, NewOperationalError("evaluating switch", wf.ID, currentNodeID, baseErr)
+		}
+
+		for _, edge := range edges {
+			if edge.Condition == label {
+				return []string{edge.ToNodeID}, nil
+			}
+		}
+
+		baseErr := fmt.Errorf("no edge found for case %q from switch node %s", label, currentNodeID)
+		return nil, NewOperationalError("selecting edge", wf.ID, currentNodeID, baseErr)
+	}
+
 	// For non-condition nodes, follow all outgoing edges
 	var nextNodes []string
 	for _, edge := range edges {
@@ -429,6 +581,23 @@ func (e *Engine) getNextNodes(currentNodeID string, wf *workflow.Workflow, nodeE
 func (e *Engine) executeNode(ctx context.Context, node workflow.Node, wf *workflow.Workflow, exec *execution.Execution) error {
 	nodeID := types.NodeID(node.GetID())
 
+	// Hold the node while the engine is paused
+	if err := e.waitIfPaused(ctx); err != nil {
+		return err
+	}
+
+	// Check the node against the sandbox profile and count it against the
+	// execution's guardrails
+	guard := guardFromContext(ctx)
+	if guard != nil {
+		if err := guard.checkSandbox(wf, node); err != nil {
+			return err
+		}
+		if err := guard.beforeNode(nodeID); err != nil {
+			return err
+		}
+	}
+
 	// Create node execution record
 	nodeExec := execution.NewNodeExecution(exec.ID, nodeID, node.Type())
 	nodeExec.Start()
@@ -440,6 +609,14 @@ func (e *Engine) executeNode(ctx context.Context, node workflow.Node, wf *workfl
 	exec.Context.SetCurrentNode(&nodeID)
 	defer exec.Context.SetCurrentNode(nil)
 
+	// Bound the node's expression evaluations by its timeout, or the
+	// workflow's, rather than one inherited from an enclosing node
+	timeout := wf.ExpressionTimeout(node)
+	if timeout <= 0 {
+		timeout = transform.DefaultEvaluationTimeout
+	}
+	ctx = transform.WithEvaluationTimeout(ctx, timeout)
+
 	// Execute based on node type
 	var err error
 	switch n := node.(type) {
@@ -453,10 +630,22 @@ func (e *Engine) executeNode(ctx context.Context, node workflow.Node, wf *workfl
 		err = e.executeTransformNode(ctx, n, exec, nodeExec)
 	case *workflow.ConditionNode:
 		err = e.executeConditionNode(ctx, n, exec, nodeExec)
+	case *workflow.SwitchNode:
+		err = e.executeSwitchNode(ctx, n, exec, nodeExec)
 	case *workflow.ParallelNode:
 		err = e.executeParallelNode(ctx, n, wf, exec, nodeExec)
 	case *workflow.LoopNode:
 		err = e.executeLoopNode(ctx, n, wf, exec, nodeExec)
+	case *workflow.TryNode:
+		err = e.executeTryNode(ctx, n, wf, exec, nodeExec)
+	case *workflow.CatchNode:
+		err = e.executeCatchNode(ctx, n, exec, nodeExec)
+	case *workflow.DelayNode:
+		err = e.executeDelayNode(ctx, n, exec, nodeExec)
+	case *workflow.ApprovalNode:
+		err = e.executeApprovalNode(ctx, n, exec, nodeExec)
+	case *workflow.BatchToolNode:
+		err = e.executeBatchToolNode(ctx, n, exec, nodeExec)
 	case *workflow.PassthroughNode:
 		// Passthrough nodes do nothing, just complete successfully
 		nodeExec.Complete(nil)
@@ -478,6 +667,7 @@ func (e *Engine) executeNode(ctx context.Context, node workflow.Node, wf *workfl
 
 		// Emit node failed event
 		e.emitNodeFailed(exec, nodeExec, nodeErr)
+		e.captureSnapshot(exec, nodeExec, SnapshotNodeFailed)
 
 		// Log node execution
 		if e.logger != nil {
@@ -505,12 +695,18 @@ func (e *Engine) executeNode(ctx context.Context, node workflow.Node, wf *workfl
 
 	// Emit node completed event
 	e.emitNodeCompleted(exec, nodeExec)
+	e.captureSnapshot(exec, nodeExec, SnapshotNodeCompleted)
 
 	// Log node execution
 	if e.logger != nil {
 		e.logger.LogNodeExecution(nodeExec)
 	}
 
+	// Check the node's payloads and the variables it set
+	if guard != nil {
+		return guard.afterNode(exec, nodeExec)
+	}
+
 	return nil
 }
 
@@ -549,166 +745,240 @@ func (e *Engine) initializeVariables(ctx *execution.ExecutionContext, wf *workfl
 	return nil
 }
 
+// requestLimits converts workflow server limits to client request limits.
+// Returns nil when no limits are configured.
+func requestLimits(limits *workflow.ServerLimits) *mcpserver.RequestLimits {
+	if limits == nil {
+		return nil
+	}
+	return &mcpserver.RequestLimits{
+		MaxConcurrent:     limits.MaxConcurrent,
+		RequestsPerSecond: limits.RequestsPerSecond,
+		Burst:             limits.Burst,
+		QueueTimeout:      limits.QueueDuration(),
+	}
+}
+
+// authConfig converts workflow server auth to transport auth settings.
+// Returns nil when no auth is configured.
+func authConfig(auth *workflow.ServerAuth) *mcpserver.AuthConfig {
+	if auth == nil {
+		return nil
+	}
+	return &mcpserver.AuthConfig{
+		Type:            mcpserver.AuthType(auth.Type),
+		TokenRef:        auth.TokenRef,
+		HeaderName:      auth.HeaderName,
+		KeyRef:          auth.KeyRef,
+		TokenURL:        auth.TokenURL,
+		ClientID:        auth.ClientID,
+		ClientSecretRef: auth.ClientSecretRef,
+		Scopes:          auth.Scopes,
+	}
+}
+
 // connectServers establishes connections to all MCP servers defined in the workflow.
 func (e *Engine) connectServers(ctx context.Context, wf *workflow.Workflow) error {
 	for _, serverConfig := range wf.ServerConfigs {
-		// Create MCP server
-		server, err := mcpserver.NewMCPServer(
-			serverConfig.ID,
-			serverConfig.Command,
-			serverConfig.Args,
-			mcpserver.TransportType(serverConfig.Transport),
-		)
-		if err != nil {
-			return NewOperationalErrorWithAttrs(
-				"creating MCP server",
-				wf.ID,
-				"",
-				err,
-				map[string]interface{}{
-					"serverID": serverConfig.ID,
-					"command":  serverConfig.Command,
-				},
-			)
+		if err := e.connectServer(ctx, wf, serverConfig); err != nil {
+			return err
 		}
+	}
 
-		// Register server
-		if err := e.serverRegistry.Register(server); err != nil {
-			return NewOperationalErrorWithAttrs(
-				"registering MCP server",
-				wf.ID,
-				"",
-				err,
-				map[string]interface{}{
-					"serverID": serverConfig.ID,
-				},
-			)
-		}
+	return nil
+}
 
-		// Create and connect MCP client for stdio transport
-		var client *mcp.StdioClient
-		if serverConfig.Transport == "stdio" {
-			// Create MCP client configuration
-			clientConfig := mcp.ServerConfig{
-				ID:      serverConfig.ID,
-				Command: serverConfig.Command,
-				Args:    serverConfig.Args,
-			}
+// connectServer creates, registers, and connects a single MCP server.
+func (e *Engine) connectServer(ctx context.Context, wf *workflow.Workflow, serverConfig *workflow.ServerConfig) error {
+	if serverConfig.IsAlias() {
+		return NewOperationalErrorWithAttrs(
+			"creating MCP server",
+			wf.ID,
+			"",
+			fmt.Errorf("server %s is an alias for the tags %v and was not resolved to a registered server", serverConfig.ID, serverConfig.Tags),
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+			},
+		)
+	}
 
-			// Create stdio client
-			var err error
-			client, err = mcp.NewStdioClient(clientConfig)
-			if err != nil {
-				return NewOperationalErrorWithAttrs(
-					"creating MCP client",
-					wf.ID,
-					"",
-					err,
-					map[string]interface{}{
-						"serverID": serverConfig.ID,
-						"command":  serverConfig.Command,
-					},
-				)
-			}
+	// Create MCP server; SSE and HTTP servers are addressed by their URL
+	transport := serverConfig.GetTransport()
+	address := serverConfig.Command
+	if transport != "stdio" {
+		address = serverConfig.URL
+	}
+	server, err := mcpserver.NewMCPServer(
+		serverConfig.ID,
+		address,
+		serverConfig.Args,
+		mcpserver.TransportType(transport),
+	)
+	if err != nil {
+		return NewOperationalErrorWithAttrs(
+			"creating MCP server",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+				"command":  address,
+			},
+		)
+	}
 
-			// Connect the client
-			if err := client.Connect(ctx); err != nil {
-				return NewOperationalErrorWithAttrs(
-					"connecting MCP client",
-					wf.ID,
-					"",
-					err,
-					map[string]interface{}{
-						"serverID": serverConfig.ID,
-					},
-				)
-			}
+	server.Limits = requestLimits(serverConfig.Limits)
+	auth := authConfig(serverConfig.Auth)
+	switch t := server.Transport.(type) {
+	case *mcpserver.SSETransportConfig:
+		t.Headers = serverConfig.Headers
+		t.Auth = auth
+	case *mcpserver.HTTPTransportConfig:
+		t.Headers = serverConfig.Headers
+		t.Auth = auth
+	}
+	if err := server.Transport.Validate(); err != nil {
+		return NewOperationalErrorWithAttrs(
+			"creating MCP server",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+			},
+		)
+	}
 
-			// Create adapter and set it on the server
-			adapter := mcpserver.NewClientAdapter(client)
-			server.SetClient(adapter)
-		}
+	// Register server
+	if err := e.serverRegistry.Register(server); err != nil {
+		return NewOperationalErrorWithAttrs(
+			"registering MCP server",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+			},
+		)
+	}
 
-		// Connect to server
-		if err := server.Connect(); err != nil {
-			// Cleanup client on error
-			if client != nil {
-				_ = client.Close()
-			}
-			return NewOperationalErrorWithAttrs(
-				"connecting to MCP server",
-				wf.ID,
-				"",
-				err,
-				map[string]interface{}{
-					"serverID": serverConfig.ID,
-				},
-			)
-		}
+	// Create the MCP client for the server's transport, authenticating SSE
+	// and HTTP requests and queuing requests beyond the server's
+	// concurrency and rate limits
+	client, err := mcp.NewClient(mcp.ServerConfig{
+		ID:        serverConfig.ID,
+		Command:   serverConfig.Command,
+		Args:      serverConfig.Args,
+		Transport: transport,
+		URL:       serverConfig.URL,
+		Headers:   serverConfig.Headers,
+		Auth:      auth,
+		Secrets:   e.secrets,
+		Limits:    server.Limits,
+	})
+	if err != nil {
+		return NewOperationalErrorWithAttrs(
+			"creating MCP client",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+				"command":  address,
+			},
+		)
+	}
 
-		// Complete connection
-		if err := server.CompleteConnection(); err != nil {
-			// Cleanup client on error
-			if client != nil {
-				_ = client.Close()
-			}
-			return NewOperationalErrorWithAttrs(
-				"completing MCP server connection",
-				wf.ID,
-				"",
-				err,
-				map[string]interface{}{
-					"serverID": serverConfig.ID,
-				},
-			)
-		}
+	// Connect the client
+	if err := client.Connect(ctx); err != nil {
+		return NewOperationalErrorWithAttrs(
+			"connecting MCP client",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+			},
+		)
+	}
+	server.SetClient(mcpserver.NewClientAdapter(client))
 
-		// Discover available tools
-		if err := server.DiscoverTools(); err != nil {
-			// Cleanup client on error
-			if client != nil {
-				_ = client.Close()
-			}
-			return NewOperationalErrorWithAttrs(
-				"discovering MCP tools",
-				wf.ID,
-				"",
-				err,
-				map[string]interface{}{
-					"serverID": serverConfig.ID,
-				},
-			)
-		}
+	// Connect to server
+	if err := server.Connect(); err != nil {
+		// Cleanup client on error
+		_ = client.Close()
+		return NewOperationalErrorWithAttrs(
+			"connecting to MCP server",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+			},
+		)
+	}
 
-		// Track the client for cleanup
-		if client != nil {
-			e.clientsMu.Lock()
-			e.activeClients[serverConfig.ID] = client
-			e.clientsMu.Unlock()
-		}
+	// Complete connection
+	if err := server.CompleteConnection(); err != nil {
+		// Cleanup client on error
+		_ = client.Close()
+		return NewOperationalErrorWithAttrs(
+			"completing MCP server connection",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+			},
+		)
 	}
 
+	// Discover available tools
+	if err := server.DiscoverTools(); err != nil {
+		// Cleanup client on error
+		_ = client.Close()
+		return NewOperationalErrorWithAttrs(
+			"discovering MCP tools",
+			wf.ID,
+			"",
+			err,
+			map[string]interface{}{
+				"serverID": serverConfig.ID,
+			},
+		)
+	}
+
+	// Track the client for cleanup
+	e.clientsMu.Lock()
+	e.activeClients[serverConfig.ID] = client
+	e.clientsMu.Unlock()
+
 	return nil
 }
 
 // disconnectServers closes all server connections.
 func (e *Engine) disconnectServers(wf *workflow.Workflow) {
 	for _, serverConfig := range wf.ServerConfigs {
-		if server, err := e.serverRegistry.Get(serverConfig.ID); err == nil {
-			_ = server.Disconnect()
-		}
+		e.disconnectServer(serverConfig.ID)
+	}
+}
 
-		// Unregister the server from the registry to allow re-registration
-		_ = e.serverRegistry.Unregister(serverConfig.ID)
+// disconnectServer closes and unregisters a single server connection.
+func (e *Engine) disconnectServer(serverID string) {
+	if server, err := e.serverRegistry.Get(serverID); err == nil {
+		_ = server.Disconnect()
+	}
 
-		// Close the MCP client if it exists
-		e.clientsMu.Lock()
-		if client, exists := e.activeClients[serverConfig.ID]; exists {
-			_ = client.Close()
-			delete(e.activeClients, serverConfig.ID)
-		}
-		e.clientsMu.Unlock()
+	// Unregister the server from the registry to allow re-registration
+	_ = e.serverRegistry.Unregister(serverID)
+
+	// Close the MCP client if it exists
+	e.clientsMu.Lock()
+	if client, exists := e.activeClients[serverID]; exists {
+		_ = client.Close()
+		delete(e.activeClients, serverID)
 	}
+	e.clientsMu.Unlock()
 }
 
 // Close cleans up engine resources.
@@ -721,6 +991,9 @@ func (e *Engine) Close() error {
 	}
 	e.clientsMu.Unlock()
 
+	// Remove spilled and handed-off blob files
+	_ = e.blobs().Close()
+
 	// Close the repository
 	if e.execRepository != nil {
 		return e.execRepository.Close()
@@ -728,6 +1001,12 @@ func (e *Engine) Close() error {
 	return nil
 }
 
+// Servers returns the MCP servers the running execution is connected to,
+// with their tool call statistics.
+func (e *Engine) Servers() mcpserver.ServerRepository {
+	return e.serverRegistry
+}
+
 // GetMonitor returns the execution monitor for the current execution.
 // Returns nil if no execution is currently running.
 func (e *Engine) GetMonitor() ExecutionMonitor {
@@ -817,14 +1096,20 @@ func (e *Engine) emitExecutionCancelled(exec *execution.Execution) {
 		return
 	}
 
-	monitor.Emit(ExecutionEvent{
+	event := ExecutionEvent{
 		Type:        EventExecutionCancelled,
 		Timestamp:   time.Now(),
 		ExecutionID: exec.ID,
 		Status:      exec.Status,
 		Variables:   monitor.GetVariableSnapshot(),
-		Metadata:    map[string]interface{}{},
-	})
+		Metadata: map[string]interface{}{
+			"reason": exec.CancelReason(),
+		},
+	}
+	if exec.Error != nil {
+		event.Error = exec.Error
+	}
+	monitor.Emit(event)
 }
 
 // emitNodeStarted emits a node started event.
@@ -2,12 +2,19 @@ package execution
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/mcp"
+	"github.com/dshills/goflow/pkg/mcpserver"
 	"github.com/dshills/goflow/pkg/workflow"
 )
 
@@ -137,6 +144,7 @@ func TestEngine_SubstituteVariables(t *testing.T) {
 		"name": "Alice",
 		"age":  30,
 		"city": "NYC",
+		"tags": []interface{}{"admin", "ops"},
 	})
 
 	engine := NewEngine()
@@ -151,6 +159,9 @@ func TestEngine_SubstituteVariables(t *testing.T) {
 		{"${name} lives in ${city}", "Alice lives in NYC"},
 		{"No variables here", "No variables here"},
 		{"${name} is ${age} years old", "Alice is 30 years old"},
+		{"${name | upper}", "ALICE"},
+		{"${tags[1]} ${tags.0}", "ops admin"},
+		{`${nickname | default:"friend"}, ${nickname | default:name}`, "friend, Alice"},
 	}
 
 	for _, tt := range tests {
@@ -814,3 +825,120 @@ func TestEngine_MultipleConcurrentTimeouts(t *testing.T) {
 		t.Errorf("Expected terminal status for exec2, got %s", exec2.Status)
 	}
 }
+
+// credentials is an in-memory secret resolver for server auth
+type credentials map[string]string
+
+func (c credentials) Get(key string) (string, error) {
+	value, ok := c[key]
+	if !ok {
+		return "", fmt.Errorf("credential not found: %s", key)
+	}
+	return value, nil
+}
+
+// TestEngine_HTTPServerAuth runs a tool on an HTTP MCP server that requires
+// a bearer token resolved from the credential store.
+func TestEngine_HTTPServerAuth(t *testing.T) {
+	var unauthorized atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			unauthorized.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req mcp.JSONRPCRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		result := map[string]interface{}{}
+		switch req.Method {
+		case "tools/list":
+			result["tools"] = []map[string]interface{}{{
+				"name":        "echo",
+				"inputSchema": map[string]interface{}{"type": "object"},
+			}}
+		case "tools/call":
+			result["content"] = []map[string]interface{}{{"type": "text", "text": "hello"}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  result,
+		})
+	}))
+	defer server.Close()
+
+	wf, err := workflow.Parse([]byte(`
+version: "1.0"
+name: http-auth
+servers:
+  - id: api
+    transport: http
+    url: ` + server.URL + `
+    auth:
+      type: bearer
+      token_ref: "api:token"
+nodes:
+  - id: start
+    type: start
+  - id: call
+    type: mcp_tool
+    server: api
+    tool: echo
+    output: reply
+  - id: end
+    type: end
+edges:
+  - from: start
+    to: call
+  - from: call
+    to: end
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	engine := NewEngine(WithSecretResolver(credentials{"api:token": "s3cret"}))
+	defer engine.Close()
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if exec.Status != execution.StatusCompleted {
+		t.Fatalf("status = %s, want completed", exec.Status)
+	}
+	if _, ok := exec.Context.GetVariable("reply"); !ok {
+		t.Error("reply variable not set")
+	}
+	if n := unauthorized.Load(); n != 0 {
+		t.Errorf("server rejected %d requests as unauthorized", n)
+	}
+
+	// A wrong token is rejected by the server and fails the run
+	engine = NewEngine(WithSecretResolver(credentials{"api:token": "wrong"}))
+	defer engine.Close()
+	if _, err := engine.Execute(context.Background(), wf, nil); err == nil {
+		t.Fatal("Execute() with a wrong token succeeded")
+	}
+	if unauthorized.Load() == 0 {
+		t.Error("expected the server to see the wrong token")
+	}
+}
+
+// TestRequestLimits tests converting workflow server limits, whose queue
+// timeout is a duration string, to client request limits
+func TestRequestLimits(t *testing.T) {
+	if limits := requestLimits(nil); limits != nil {
+		t.Errorf("requestLimits(nil) = %+v, want nil", limits)
+	}
+
+	limits := requestLimits(&workflow.ServerLimits{MaxConcurrent: 2, QueueTimeout: "250ms"})
+	if limits.MaxConcurrent != 2 || limits.QueueTimeout != 250*time.Millisecond {
+		t.Errorf("requestLimits() = %+v, want 2 concurrent and a 250ms queue timeout", limits)
+	}
+
+	limits = requestLimits(&workflow.ServerLimits{MaxConcurrent: 2})
+	if limits.EffectiveQueueTimeout() != mcpserver.DefaultQueueTimeout {
+		t.Errorf("EffectiveQueueTimeout() = %s, want the default", limits.EffectiveQueueTimeout())
+	}
+}
@@ -0,0 +1,38 @@
+package execution
+
+import (
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// WithSandbox runs executions under a sandbox profile. Reaching a node the
+// profile does not allow aborts the execution with an ErrorTypePolicy
+// error; nodes on branches that are not taken are never checked.
+func WithSandbox(profile *workflow.SandboxProfile) EngineOption {
+	return func(e *Engine) {
+		e.sandbox = profile
+	}
+}
+
+// checkSandbox aborts the execution if the sandbox profile does not let
+// node run
+func (g *guard) checkSandbox(wf *workflow.Workflow, node workflow.Node) error {
+	reason := g.sandbox.CheckNode(wf, node)
+	if reason == "" {
+		return nil
+	}
+	return g.trip(&execution.ExecutionError{
+		Type:    execution.ErrorTypePolicy,
+		Message: reason,
+		NodeID:  types.NodeID(node.GetID()),
+		Context: map[string]interface{}{
+			"sandbox_profile": g.sandbox.Name,
+			"node_type":       node.Type(),
+		},
+		Recoverable: false,
+		Timestamp:   time.Now(),
+	})
+}
@@ -0,0 +1,40 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandbox(t *testing.T) {
+	wf, err := workflow.Parse([]byte(guardrailWorkflowYAML))
+	require.NoError(t, err)
+
+	// Reaching a denied node aborts the run with a policy error
+	engine := NewEngine(WithSandbox(&workflow.SandboxProfile{Name: "no-transforms", DenyNodeTypes: []string{"transform"}}))
+	defer engine.Close()
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	require.Error(t, err)
+	var execErr *execution.ExecutionError
+	require.True(t, errors.As(err, &execErr), "expected an ExecutionError, got %T", err)
+	assert.Equal(t, execution.ErrorTypePolicy, execErr.Type)
+	assert.Equal(t, "repeat", string(execErr.NodeID))
+	assert.Equal(t, "no-transforms", execErr.Context["sandbox_profile"])
+	require.NotNil(t, exec)
+	assert.Equal(t, execution.StatusFailed, exec.Status)
+	require.NotNil(t, exec.Error)
+	assert.Equal(t, execution.ErrorTypePolicy, exec.Error.Type)
+	assert.Empty(t, exec.Context.GetVariableSnapshot()["result"], "the denied node must not run")
+
+	// A profile the workflow does not break lets it complete
+	engine = NewEngine(WithSandbox(&workflow.SandboxProfile{Name: "no-approvals", DenyNodeTypes: []string{"approval"}}))
+	defer engine.Close()
+	exec, err = engine.Execute(context.Background(), wf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+}
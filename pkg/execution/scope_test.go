@@ -0,0 +1,163 @@
+package execution
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scopeParallelYAML has two branches that both write x; the left branch also
+// writes y and the right branch w
+func scopeParallelYAML(options string) string {
+	return `
+version: "1.0"
+name: "scope-parallel"
+variables:
+  - name: "a"
+    type: "number"
+    default: 0
+  - name: "y"
+    type: "number"
+    default: 0
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "fan_out"
+    type: "parallel"
+    merge_strategy: "wait_all"
+` + options + `
+    branches:
+      - ["left_x", "left_y"]
+      - ["right_x", "right_w"]
+  - id: "left_x"
+    type: "transform"
+    input: "a"
+    expression: "a + 1"
+    output: "x"
+  - id: "left_y"
+    type: "transform"
+    input: "a"
+    expression: "a + 10"
+    output: "y"
+  - id: "right_x"
+    type: "transform"
+    input: "a"
+    expression: "a + 2"
+    output: "x"
+  - id: "right_w"
+    type: "transform"
+    input: "a"
+    expression: "a + 20"
+    output: "w"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "fan_out"
+  - from: "fan_out"
+    to: "end"
+`
+}
+
+func runScopeWorkflow(t *testing.T, yaml string) (*execution.Execution, error) {
+	t.Helper()
+	wf, err := workflow.Parse([]byte(yaml))
+	require.NoError(t, err)
+
+	engine := NewEngine()
+	defer engine.Close()
+	return engine.Execute(context.Background(), wf, nil)
+}
+
+func TestParallelScope_Shared(t *testing.T) {
+	exec, err := runScopeWorkflow(t, scopeParallelYAML(""))
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+
+	vars := exec.Context.GetVariableSnapshot()
+	// The right branch never wrote y, so its stale copy must not overwrite
+	// the left branch's value
+	assert.EqualValues(t, 10, vars["y"])
+	assert.EqualValues(t, 20, vars["w"])
+	// Conflicting writes keep the last branch's value
+	assert.EqualValues(t, 2, vars["x"])
+}
+
+func TestParallelScope_Isolated(t *testing.T) {
+	exec, err := runScopeWorkflow(t, scopeParallelYAML(`    scope: "isolated"
+    promote: ["y"]`))
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+
+	vars := exec.Context.GetVariableSnapshot()
+	assert.EqualValues(t, 10, vars["y"])
+	assert.NotContains(t, vars, "x")
+	assert.NotContains(t, vars, "w")
+}
+
+func TestParallelScope_OnConflict(t *testing.T) {
+	exec, err := runScopeWorkflow(t, scopeParallelYAML(`    on_conflict: "collect"`))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 2}, exec.Context.GetVariableSnapshot()["x"])
+
+	exec, err = runScopeWorkflow(t, scopeParallelYAML(`    on_conflict: "error"`))
+	require.Error(t, err)
+	assert.Equal(t, execution.StatusFailed, exec.Status)
+	assert.True(t, strings.Contains(err.Error(), "variable 'x'"), err.Error())
+}
+
+const scopeLoopYAML = `
+version: "1.0"
+name: "scope-loop"
+variables:
+  - name: "items"
+    type: "array"
+    default: [1, 2, 3]
+  - name: "total"
+    type: "number"
+    default: 0
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "sum"
+    type: "loop"
+    collection: "items"
+    item: "item"
+    scope: "isolated"
+    promote: ["total"]
+    body:
+      - "add"
+      - "scratch"
+  - id: "add"
+    type: "transform"
+    input: "item"
+    expression: "total + item"
+    output: "total"
+  - id: "scratch"
+    type: "transform"
+    input: "item"
+    expression: "item * 2"
+    output: "doubled"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "sum"
+  - from: "sum"
+    to: "end"
+`
+
+func TestLoopScope_Isolated(t *testing.T) {
+	exec, err := runScopeWorkflow(t, scopeLoopYAML)
+	require.NoError(t, err)
+	assert.Equal(t, execution.StatusCompleted, exec.Status)
+
+	vars := exec.Context.GetVariableSnapshot()
+	assert.EqualValues(t, 6, vars["total"])
+	assert.NotContains(t, vars, "doubled")
+}
@@ -0,0 +1,326 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+)
+
+// SnapshotPhase identifies the node boundary at which a snapshot was taken.
+type SnapshotPhase string
+
+const (
+	// SnapshotNodeCompleted is captured after a node completes successfully.
+	SnapshotNodeCompleted SnapshotPhase = "node.completed"
+	// SnapshotNodeFailed is captured after a node fails.
+	SnapshotNodeFailed SnapshotPhase = "node.failed"
+)
+
+// SnapshotRecord is a variable snapshot delivered to a SnapshotSink.
+type SnapshotRecord struct {
+	// ExecutionID identifies the workflow execution (the top-level execution,
+	// also for nodes running in parallel branches).
+	ExecutionID types.ExecutionID
+	// WorkflowID identifies the workflow being executed.
+	WorkflowID types.WorkflowID
+	// NodeID identifies the node at whose boundary the snapshot was taken.
+	NodeID types.NodeID
+	// NodeType is the type of that node.
+	NodeType string
+	// Phase is the node boundary (completed or failed).
+	Phase SnapshotPhase
+	// Timestamp records when the snapshot was captured.
+	Timestamp time.Time
+	// Variables is a deep copy of all variables; sinks may retain it.
+	Variables map[string]interface{}
+}
+
+// SnapshotSink persists variable snapshots outside the engine, e.g. to an
+// integrator's own database. Sinks are called from a single background
+// goroutine per execution, in capture order.
+type SnapshotSink interface {
+	// PersistSnapshot stores a snapshot. ctx is cancelled when the
+	// per-call timeout expires.
+	PersistSnapshot(ctx context.Context, record SnapshotRecord) error
+}
+
+// SnapshotSinkFunc adapts a function to the SnapshotSink interface.
+type SnapshotSinkFunc func(ctx context.Context, record SnapshotRecord) error
+
+// PersistSnapshot calls f(ctx, record).
+func (f SnapshotSinkFunc) PersistSnapshot(ctx context.Context, record SnapshotRecord) error {
+	return f(ctx, record)
+}
+
+// Default snapshot sink settings
+const (
+	DefaultSnapshotQueueSize = 64
+	DefaultSnapshotBudget    = 100 * time.Millisecond
+	DefaultSnapshotTimeout   = 5 * time.Second
+)
+
+// SnapshotSinkOptions controls backpressure between execution and a sink.
+type SnapshotSinkOptions struct {
+	// QueueSize is how many snapshots may wait for the sink (default 64).
+	QueueSize int
+	// Budget is the total time one execution may block waiting for queue
+	// space (default 100ms). Once spent, snapshots that don't fit in the
+	// queue are dropped instead of stalling execution. Negative never blocks.
+	Budget time.Duration
+	// Timeout bounds each PersistSnapshot call (default 5s).
+	Timeout time.Duration
+	// OnError is called when a snapshot fails to persist or is dropped.
+	// It may run on the executing goroutine and must not block. Optional.
+	OnError func(record SnapshotRecord, err error)
+}
+
+// SnapshotSinkStats reports snapshot delivery for the most recent execution.
+type SnapshotSinkStats struct {
+	// Delivered counts snapshots persisted successfully.
+	Delivered int
+	// Failed counts snapshots the sink returned an error for.
+	Failed int
+	// Dropped counts snapshots discarded because the queue was full and
+	// the blocking budget was spent.
+	Dropped int
+	// Blocked is the total time execution waited on the queue.
+	Blocked time.Duration
+}
+
+// ErrSnapshotDropped is passed to OnError for snapshots discarded under backpressure.
+var ErrSnapshotDropped = errors.New("snapshot dropped: sink too slow and backpressure budget exhausted")
+
+// WithSnapshotSink registers a sink that receives variable snapshots at node
+// boundaries. Slow sinks are isolated by a bounded queue; execution waits for
+// queue space only up to opts.Budget per execution.
+func WithSnapshotSink(sink SnapshotSink, opts SnapshotSinkOptions) EngineOption {
+	return func(e *Engine) {
+		e.snapshotSink = sink
+		e.snapshotOpts = opts.withDefaults()
+	}
+}
+
+// SnapshotSinkStats returns delivery statistics for the current or most
+// recent execution. Returns zero stats when no sink is configured.
+func (e *Engine) SnapshotSinkStats() SnapshotSinkStats {
+	e.monitorMu.RLock()
+	dispatcher := e.snapshots
+	e.monitorMu.RUnlock()
+
+	if dispatcher == nil {
+		return SnapshotSinkStats{}
+	}
+	return dispatcher.Stats()
+}
+
+// WaitForSnapshots blocks until every snapshot of the most recent execution
+// has been handed to the sink, or ctx is done. Execute returns once the
+// backpressure budget is spent, so integrators that need all snapshots
+// persisted (e.g. before shutdown) call this afterwards.
+func (e *Engine) WaitForSnapshots(ctx context.Context) error {
+	e.monitorMu.RLock()
+	dispatcher := e.snapshots
+	e.monitorMu.RUnlock()
+
+	if dispatcher == nil {
+		return nil
+	}
+	return dispatcher.Wait(ctx)
+}
+
+// captureSnapshot sends the current variables to the snapshot sink, if any
+func (e *Engine) captureSnapshot(exec *execution.Execution, nodeExec *execution.NodeExecution, phase SnapshotPhase) {
+	e.monitorMu.RLock()
+	dispatcher := e.snapshots
+	e.monitorMu.RUnlock()
+
+	if dispatcher == nil {
+		return
+	}
+
+	variables := exec.Context.GetVariableSnapshot()
+	if copied, err := deepCopyVariables(variables); err == nil {
+		variables = copied
+	}
+
+	dispatcher.Enqueue(SnapshotRecord{
+		WorkflowID: exec.WorkflowID,
+		NodeID:     nodeExec.NodeID,
+		NodeType:   nodeExec.NodeType,
+		Phase:      phase,
+		Timestamp:  time.Now(),
+		Variables:  variables,
+	})
+}
+
+// withDefaults fills in unset options
+func (o SnapshotSinkOptions) withDefaults() SnapshotSinkOptions {
+	if o.QueueSize <= 0 {
+		o.QueueSize = DefaultSnapshotQueueSize
+	}
+	if o.Budget == 0 {
+		o.Budget = DefaultSnapshotBudget
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultSnapshotTimeout
+	}
+	return o
+}
+
+// snapshotDispatcher delivers snapshots for one execution to the sink from a
+// background goroutine, enforcing the blocking budget.
+type snapshotDispatcher struct {
+	sink        SnapshotSink
+	opts        SnapshotSinkOptions
+	executionID types.ExecutionID
+	queue       chan SnapshotRecord
+	done        chan struct{}
+	closeOnce   sync.Once
+
+	// sendMu guards the queue against sends after Close
+	sendMu sync.RWMutex
+	closed bool
+
+	mu    sync.Mutex
+	stats SnapshotSinkStats
+}
+
+// newSnapshotDispatcher starts a dispatcher for one execution
+func newSnapshotDispatcher(sink SnapshotSink, opts SnapshotSinkOptions, executionID types.ExecutionID) *snapshotDispatcher {
+	d := &snapshotDispatcher{
+		sink:        sink,
+		opts:        opts,
+		executionID: executionID,
+		queue:       make(chan SnapshotRecord, opts.QueueSize),
+		done:        make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// run persists queued snapshots until the queue is closed
+func (d *snapshotDispatcher) run() {
+	defer close(d.done)
+
+	for record := range d.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+		err := d.sink.PersistSnapshot(ctx, record)
+		cancel()
+
+		d.mu.Lock()
+		if err != nil {
+			d.stats.Failed++
+		} else {
+			d.stats.Delivered++
+		}
+		d.mu.Unlock()
+
+		if err != nil && d.opts.OnError != nil {
+			d.opts.OnError(record, err)
+		}
+	}
+}
+
+// Enqueue hands a snapshot to the sink goroutine. If the queue is full it
+// waits for space using the remaining budget, then drops the snapshot.
+func (d *snapshotDispatcher) Enqueue(record SnapshotRecord) {
+	record.ExecutionID = d.executionID
+
+	d.sendMu.RLock()
+	defer d.sendMu.RUnlock()
+	if d.closed {
+		d.drop(record)
+		return
+	}
+
+	select {
+	case d.queue <- record:
+		return
+	default:
+	}
+
+	d.mu.Lock()
+	remaining := d.opts.Budget - d.stats.Blocked
+	d.mu.Unlock()
+
+	if remaining > 0 {
+		start := time.Now()
+		timer := time.NewTimer(remaining)
+		defer timer.Stop()
+
+		select {
+		case d.queue <- record:
+			d.addBlocked(time.Since(start))
+			return
+		case <-timer.C:
+			d.addBlocked(time.Since(start))
+		}
+	}
+
+	d.drop(record)
+}
+
+// Close stops accepting snapshots and waits for queued ones to be persisted
+// for at most the remaining budget. Snapshots still queued after that keep
+// being delivered in the background; use Wait to block until they are.
+func (d *snapshotDispatcher) Close() {
+	d.closeOnce.Do(func() {
+		d.sendMu.Lock()
+		d.closed = true
+		close(d.queue)
+		d.sendMu.Unlock()
+
+		d.mu.Lock()
+		remaining := d.opts.Budget - d.stats.Blocked
+		d.mu.Unlock()
+		if remaining <= 0 {
+			return
+		}
+
+		timer := time.NewTimer(remaining)
+		defer timer.Stop()
+
+		select {
+		case <-d.done:
+		case <-timer.C:
+		}
+	})
+}
+
+// Wait blocks until all queued snapshots have been handed to the sink or
+// ctx is done. Only meaningful after Close.
+func (d *snapshotDispatcher) Wait(ctx context.Context) error {
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns a copy of the delivery statistics
+func (d *snapshotDispatcher) Stats() SnapshotSinkStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+func (d *snapshotDispatcher) addBlocked(elapsed time.Duration) {
+	d.mu.Lock()
+	d.stats.Blocked += elapsed
+	d.mu.Unlock()
+}
+
+func (d *snapshotDispatcher) drop(record SnapshotRecord) {
+	d.mu.Lock()
+	d.stats.Dropped++
+	d.mu.Unlock()
+
+	if d.opts.OnError != nil {
+		d.opts.OnError(record, ErrSnapshotDropped)
+	}
+}
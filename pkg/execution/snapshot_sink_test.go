@@ -0,0 +1,152 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/types"
+)
+
+// recordingSink collects persisted snapshots, optionally blocking each call
+type recordingSink struct {
+	mu      sync.Mutex
+	records []SnapshotRecord
+	delay   time.Duration
+	err     error
+}
+
+func (s *recordingSink) PersistSnapshot(ctx context.Context, record SnapshotRecord) error {
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return s.err
+}
+
+func (s *recordingSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+func TestSnapshotDispatcher_DeliversInOrder(t *testing.T) {
+	sink := &recordingSink{}
+	d := newSnapshotDispatcher(sink, SnapshotSinkOptions{}.withDefaults(), "exec-1")
+
+	for _, id := range []types.NodeID{"a", "b", "c"} {
+		d.Enqueue(SnapshotRecord{NodeID: id, Phase: SnapshotNodeCompleted})
+	}
+	d.Close()
+	if err := d.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	if sink.count() != 3 {
+		t.Fatalf("delivered %d snapshots, want 3", sink.count())
+	}
+	for i, want := range []types.NodeID{"a", "b", "c"} {
+		got := sink.records[i]
+		if got.NodeID != want {
+			t.Errorf("record %d NodeID = %s, want %s", i, got.NodeID, want)
+		}
+		if got.ExecutionID != "exec-1" {
+			t.Errorf("record %d ExecutionID = %s, want exec-1", i, got.ExecutionID)
+		}
+	}
+	if stats := d.Stats(); stats.Delivered != 3 || stats.Dropped != 0 {
+		t.Errorf("Stats() = %+v, want 3 delivered", stats)
+	}
+}
+
+func TestSnapshotDispatcher_BudgetBoundsBlocking(t *testing.T) {
+	sink := &recordingSink{delay: 200 * time.Millisecond}
+
+	var mu sync.Mutex
+	var dropped int
+	opts := SnapshotSinkOptions{
+		QueueSize: 1,
+		Budget:    30 * time.Millisecond,
+		OnError: func(record SnapshotRecord, err error) {
+			if errors.Is(err, ErrSnapshotDropped) {
+				mu.Lock()
+				dropped++
+				mu.Unlock()
+			}
+		},
+	}.withDefaults()
+	d := newSnapshotDispatcher(sink, opts, "exec-1")
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		d.Enqueue(SnapshotRecord{NodeID: types.NodeID("n")})
+	}
+	d.Close()
+	elapsed := time.Since(start)
+
+	// A sink taking 200ms per call must not stall execution much beyond the
+	// 30ms budget, even across enqueues and the final flush
+	if elapsed > 150*time.Millisecond {
+		t.Errorf("execution blocked for %v, budget was 30ms", elapsed)
+	}
+
+	stats := d.Stats()
+	if stats.Dropped == 0 {
+		t.Error("expected snapshots to be dropped under backpressure")
+	}
+	mu.Lock()
+	if dropped != stats.Dropped {
+		t.Errorf("OnError saw %d drops, stats report %d", dropped, stats.Dropped)
+	}
+	mu.Unlock()
+
+	// Accepted snapshots are still delivered in the background
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := d.Wait(ctx); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if got := d.Stats().Delivered + d.Stats().Dropped; got != 10 {
+		t.Errorf("delivered + dropped = %d, want 10", got)
+	}
+}
+
+func TestSnapshotDispatcher_SinkErrors(t *testing.T) {
+	sink := &recordingSink{err: errors.New("database unavailable")}
+
+	var failures int
+	opts := SnapshotSinkOptions{
+		OnError: func(record SnapshotRecord, err error) { failures++ },
+	}.withDefaults()
+	d := newSnapshotDispatcher(sink, opts, "exec-1")
+
+	d.Enqueue(SnapshotRecord{NodeID: "a"})
+	d.Close()
+	_ = d.Wait(context.Background())
+
+	if stats := d.Stats(); stats.Failed != 1 || stats.Delivered != 0 {
+		t.Errorf("Stats() = %+v, want 1 failed", stats)
+	}
+	if failures != 1 {
+		t.Errorf("OnError called %d times, want 1", failures)
+	}
+}
+
+func TestSnapshotDispatcher_EnqueueAfterClose(t *testing.T) {
+	d := newSnapshotDispatcher(&recordingSink{}, SnapshotSinkOptions{}.withDefaults(), "exec-1")
+	d.Close()
+
+	// Must not panic on the closed queue
+	d.Enqueue(SnapshotRecord{NodeID: "late"})
+	if stats := d.Stats(); stats.Dropped != 1 {
+		t.Errorf("Stats() = %+v, want 1 dropped", stats)
+	}
+}
@@ -0,0 +1,63 @@
+package execution
+
+import (
+	"encoding/json"
+
+	"github.com/dshills/goflow/pkg/config"
+	"github.com/dshills/goflow/pkg/domain/execution"
+)
+
+// WithVariableSpill moves variable values whose JSON encoding is larger than
+// thresholdBytes to temp files, keeping memory bounded on workflows that
+// handle big data. Pass 0 to follow the spill_variable_kb tunable.
+func WithVariableSpill(thresholdBytes int64) EngineOption {
+	return func(e *Engine) {
+		e.spillThreshold = thresholdBytes
+	}
+}
+
+// resolveSpillThreshold returns the explicit spill threshold if one was
+// set, otherwise the spill_variable_kb tunable (0 disables spilling).
+func (e *Engine) resolveSpillThreshold() int64 {
+	if e.spillThreshold > 0 {
+		return e.spillThreshold
+	}
+	return int64(config.Global().Get().SpillVariableKB) << 10
+}
+
+// variableSpiller writes large strings, maps and lists to files in the
+// engine's blob directory, where path validation and cleanup on Close apply
+// as for blobs
+type variableSpiller struct {
+	threshold int64
+	blobs     *blobStore
+}
+
+// Spill implements execution.Spiller
+func (s *variableSpiller) Spill(name string, value interface{}) (*execution.SpilledValue, error) {
+	switch v := value.(type) {
+	case string:
+		if int64(len(v)) <= s.threshold {
+			return nil, nil
+		}
+	case map[string]interface{}, []interface{}:
+	default:
+		// Scalars stay small; blobs and references are already out of
+		// memory or have their own limits
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		// Values JSON cannot hold stay in memory
+		return nil, nil
+	}
+	if int64(len(data)) <= s.threshold {
+		return nil, nil
+	}
+	path, err := s.blobs.writeFile(data, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	return &execution.SpilledValue{Path: path, Size: int64(len(data))}, nil
+}
@@ -0,0 +1,68 @@
+package execution
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariableSpill(t *testing.T) {
+	engine := NewEngine(WithBlobLimits(BlobLimits{Dir: t.TempDir()}))
+	defer engine.Close()
+
+	exec, err := execution.NewExecution("spill-workflow", "1.0", nil)
+	require.NoError(t, err)
+	exec.Context.SetSpiller(&variableSpiller{threshold: 256, blobs: engine.blobs()})
+
+	rows := make([]interface{}, 50)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": float64(i), "name": strings.Repeat("x", 10)}
+	}
+	report := map[string]interface{}{"items": rows}
+	require.NoError(t, exec.Context.SetVariable("report", report))
+	require.NoError(t, exec.Context.SetVariable("small", "kept in memory"))
+
+	ref, _ := exec.Context.GetVariableRef("report")
+	spilled, ok := ref.(*execution.SpilledValue)
+	require.True(t, ok, "large values are stored as references, got %T", ref)
+	_, err = os.Stat(spilled.Path)
+	require.NoError(t, err)
+	small, _ := exec.Context.GetVariableRef("small")
+	assert.Equal(t, "kept in memory", small)
+
+	// Reads are transparent
+	loaded, _ := exec.Context.GetVariable("report")
+	assert.Equal(t, report, loaded)
+	history := exec.Context.GetVariableHistory()
+	assert.IsType(t, &execution.SpilledValue{}, history[0].NewValue, "the history keeps the reference")
+
+	// Transforms query the spilled value
+	node := &workflow.TransformNode{ID: "pick", InputVariable: "report", Expression: "$.items[3].id", OutputVariable: "picked"}
+	nodeExec := execution.NewNodeExecution(exec.ID, "pick", "transform")
+	require.NoError(t, engine.executeTransformNode(context.Background(), node, exec, nodeExec))
+	picked, _ := exec.Context.GetVariable("picked")
+	assert.EqualValues(t, 3, picked)
+
+	count := &workflow.TransformNode{ID: "count", InputVariable: "report", Expression: "len(report.items)", OutputVariable: "count"}
+	nodeExec = execution.NewNodeExecution(exec.ID, "count", "transform")
+	require.NoError(t, engine.executeTransformNode(context.Background(), count, exec, nodeExec))
+	total, _ := exec.Context.GetVariable("count")
+	assert.Equal(t, 50, total)
+
+	last := &workflow.TransformNode{ID: "last", InputVariable: "report", Expression: "$.items[?(@.id > 47)].id", OutputVariable: "last", ItemErrorsOutput: "skipped"}
+	nodeExec = execution.NewNodeExecution(exec.ID, "last", "transform")
+	require.NoError(t, engine.executeTransformNode(context.Background(), last, exec, nodeExec))
+	ids, _ := exec.Context.GetVariable("last")
+	assert.EqualValues(t, []interface{}{48, 49}, ids)
+
+	// Closing the engine removes spilled files
+	require.NoError(t, engine.Close())
+	_, err = os.Stat(spilled.Path)
+	assert.True(t, os.IsNotExist(err))
+}
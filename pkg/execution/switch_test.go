@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const switchWorkflowYAML = `
+version: "1.0"
+name: "switch-test"
+variables:
+  - name: "total"
+    type: "number"
+    default: 0
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "size"
+    type: "switch"
+    cases:
+      - label: "large"
+        condition: "total > 100"
+      - label: "medium"
+        condition: "total > 10"
+  - id: "large_path"
+    type: "passthrough"
+  - id: "medium_path"
+    type: "passthrough"
+  - id: "small_path"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "size"
+  - from: "size"
+    to: "large_path"
+    condition: "large"
+  - from: "size"
+    to: "medium_path"
+    condition: "medium"
+  - from: "size"
+    to: "small_path"
+    condition: "default"
+  - from: "large_path"
+    to: "end"
+  - from: "medium_path"
+    to: "end"
+  - from: "small_path"
+    to: "end"
+`
+
+func TestEngine_SwitchNode(t *testing.T) {
+	wf, err := workflow.Parse([]byte(switchWorkflowYAML))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		total float64
+		want  string
+		path  types.NodeID
+	}{
+		{name: "first matching case wins", total: 500, want: "large", path: "large_path"},
+		{name: "later case", total: 50, want: "medium", path: "medium_path"},
+		{name: "default", total: 5, want: workflow.SwitchDefaultCase, path: "small_path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+			defer engine.Close()
+
+			exec, err := engine.Execute(context.Background(), wf, map[string]interface{}{"total": tt.total})
+			require.NoError(t, err)
+			assert.Equal(t, execution.StatusCompleted, exec.Status)
+
+			var executed []types.NodeID
+			for _, nodeExec := range exec.NodeExecutions {
+				executed = append(executed, nodeExec.NodeID)
+				if nodeExec.NodeID == "size" {
+					assert.Equal(t, tt.want, nodeExec.Outputs["result"])
+				}
+			}
+			assert.Equal(t, []types.NodeID{"start", "size", tt.path, "end"}, executed)
+		})
+	}
+}
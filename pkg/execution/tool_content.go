@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// routeToolContent splits an MCP tool result's content items by type into
+// the variables named in routes (content type -> variable name).
+//
+// Text items are joined with newlines into a single string. Other types
+// produce a list of items; images and audio decoded by decodeToolBlobs are
+// unwrapped to their blobs, and embedded resources to the resource object
+// itself (uri, mimeType, text or blob). Variables for content types
+// absent from the result receive an empty string or empty list, so
+// downstream references always resolve.
+func routeToolContent(result interface{}, routes map[string]string) map[string]interface{} {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	var items []interface{}
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		items, _ = resultMap["content"].([]interface{})
+	}
+
+	var texts []string
+	byType := make(map[string][]interface{})
+	for _, raw := range items {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		contentType, _ := item["type"].(string)
+
+		switch contentType {
+		case workflow.ContentTypeText:
+			if text, ok := item["text"].(string); ok {
+				texts = append(texts, text)
+			}
+		case workflow.ContentTypeResource:
+			if resource, ok := item["resource"].(map[string]interface{}); ok {
+				byType[contentType] = append(byType[contentType], resource)
+			} else {
+				byType[contentType] = append(byType[contentType], item)
+			}
+		case workflow.ContentTypeImage, workflow.ContentTypeAudio:
+			if blob, ok := item["data"].(*execution.Blob); ok {
+				byType[contentType] = append(byType[contentType], blob)
+			} else {
+				byType[contentType] = append(byType[contentType], item)
+			}
+		default:
+			byType[contentType] = append(byType[contentType], item)
+		}
+	}
+
+	routed := make(map[string]interface{}, len(routes))
+	for contentType, variable := range routes {
+		if contentType == workflow.ContentTypeText {
+			routed[variable] = strings.Join(texts, "\n")
+			continue
+		}
+		values := byType[contentType]
+		if values == nil {
+			values = []interface{}{}
+		}
+		routed[variable] = values
+	}
+	return routed
+}
+
+// decodeToolBlobs replaces the base64 data of a tool result's binary content
+// (image and audio data, embedded resource blobs) with blobs, so the bytes
+// are decoded once, held within the engine's size limits and can be handed
+// to other nodes as files. Content items are copied, not modified.
+func (e *Engine) decodeToolBlobs(result interface{}) error {
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	items, ok := resultMap["content"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	decoded := make([]interface{}, len(items))
+	for i, raw := range items {
+		decoded[i] = raw
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		contentType, _ := item["type"].(string)
+
+		switch contentType {
+		case workflow.ContentTypeImage, workflow.ContentTypeAudio:
+			data, ok := item["data"].(string)
+			if !ok {
+				continue
+			}
+			mimeType, _ := item["mimeType"].(string)
+			blob, err := e.blobs().decode(data, mimeType)
+			if err != nil {
+				return fmt.Errorf("%s content %d: %w", contentType, i, err)
+			}
+			decoded[i] = copyWith(item, "data", blob)
+		case workflow.ContentTypeResource:
+			resource, ok := item["resource"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			data, ok := resource["blob"].(string)
+			if !ok {
+				continue
+			}
+			mimeType, _ := resource["mimeType"].(string)
+			blob, err := e.blobs().decode(data, mimeType)
+			if err != nil {
+				return fmt.Errorf("resource content %d: %w", i, err)
+			}
+			decoded[i] = copyWith(item, "resource", copyWith(resource, "blob", blob))
+		}
+	}
+	resultMap["content"] = decoded
+	return nil
+}
+
+// copyWith returns a shallow copy of m with key set to value
+func copyWith(m map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
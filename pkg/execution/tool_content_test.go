@@ -0,0 +1,62 @@
+package execution
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouteToolContent(t *testing.T) {
+	result := map[string]interface{}{
+		"content": []interface{}{
+			map[string]interface{}{"type": "text", "text": "first"},
+			map[string]interface{}{
+				"type": "resource",
+				"resource": map[string]interface{}{
+					"uri":      "file:///tmp/report.csv",
+					"mimeType": "text/csv",
+				},
+			},
+			map[string]interface{}{"type": "image", "data": "aGVsbG8=", "mimeType": "image/png"},
+			map[string]interface{}{"type": "text", "text": "second"},
+		},
+	}
+
+	routed := routeToolContent(result, map[string]string{
+		"text":     "summary",
+		"resource": "file_ref",
+		"audio":    "clip",
+	})
+
+	if got := routed["summary"]; got != "first\nsecond" {
+		t.Errorf("summary = %q, want joined text", got)
+	}
+
+	wantResources := []interface{}{
+		map[string]interface{}{"uri": "file:///tmp/report.csv", "mimeType": "text/csv"},
+	}
+	if got := routed["file_ref"]; !reflect.DeepEqual(got, wantResources) {
+		t.Errorf("file_ref = %v, want %v", got, wantResources)
+	}
+
+	// Missing content types resolve to an empty value
+	if got, ok := routed["clip"].([]interface{}); !ok || len(got) != 0 {
+		t.Errorf("clip = %#v, want empty list", routed["clip"])
+	}
+
+	// Unrouted types are not emitted
+	if len(routed) != 3 {
+		t.Errorf("routed %d variables, want 3", len(routed))
+	}
+}
+
+func TestRouteToolContent_NoRoutes(t *testing.T) {
+	if routed := routeToolContent(map[string]interface{}{"content": []interface{}{}}, nil); routed != nil {
+		t.Errorf("routeToolContent() without routes = %v, want nil", routed)
+	}
+
+	// Results without a content list still populate routed variables
+	routed := routeToolContent(map[string]interface{}{"success": true}, map[string]string{"text": "summary"})
+	if got := routed["summary"]; got != "" {
+		t.Errorf("summary = %q, want empty string", got)
+	}
+}
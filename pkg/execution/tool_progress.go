@@ -0,0 +1,107 @@
+package execution
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// toolStream collects the progress notifications of one MCP tool call. It
+// reports each to the execution monitor and, when the node has a stream
+// output variable, appends the streamed text to that variable as it
+// arrives.
+type toolStream struct {
+	engine   *Engine
+	node     *workflow.MCPToolNode
+	exec     *execution.Execution
+	nodeExec *execution.NodeExecution
+
+	mu       sync.Mutex
+	text     strings.Builder
+	streamed bool
+}
+
+// withToolProgress returns a context that asks the server for progress
+// notifications on the node's tool call, or ctx unchanged when nothing
+// would consume them
+func (e *Engine) withToolProgress(ctx context.Context, node *workflow.MCPToolNode, exec *execution.Execution, nodeExec *execution.NodeExecution) (context.Context, *toolStream) {
+	e.monitorMu.RLock()
+	monitored := e.monitor != nil
+	e.monitorMu.RUnlock()
+	if !monitored && node.StreamOutput == "" {
+		return ctx, nil
+	}
+
+	stream := &toolStream{engine: e, node: node, exec: exec, nodeExec: nodeExec}
+	return mcpserver.WithToolProgress(ctx, stream.update), stream
+}
+
+// update handles one progress notification. It runs on the client's reader
+// goroutine.
+func (s *toolStream) update(p mcpserver.ToolProgress) {
+	chunk := p.Text()
+
+	s.mu.Lock()
+	s.text.WriteString(chunk)
+	text := s.text.String()
+	if chunk != "" {
+		s.streamed = true
+	}
+	s.mu.Unlock()
+
+	if s.node.StreamOutput != "" && chunk != "" {
+		_ = s.exec.Context.SetVariableWithNode(s.node.StreamOutput, text, s.nodeExec.ID)
+	}
+
+	s.engine.monitorMu.RLock()
+	monitor := s.engine.monitor
+	s.engine.monitorMu.RUnlock()
+	if monitor == nil {
+		return
+	}
+
+	metadata := map[string]interface{}{
+		"progress": p.Progress,
+		"message":  p.Message,
+	}
+	if p.Total > 0 {
+		metadata["total"] = p.Total
+	}
+	if chunk != "" {
+		metadata["text"] = chunk
+	}
+	monitor.Emit(ExecutionEvent{
+		Type:        EventToolProgress,
+		Timestamp:   time.Now(),
+		ExecutionID: s.exec.ID,
+		NodeID:      types.NodeID(s.node.ID),
+		Status:      execution.NodeStatusRunning,
+		Metadata:    metadata,
+	})
+}
+
+// finalText returns the complete streamed text once the call has returned.
+// When the server streamed nothing it falls back to the text content of the
+// result, so the stream output variable always holds the tool's text.
+func (s *toolStream) finalText(result interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.streamed {
+		return s.text.String()
+	}
+	return toolResultText(result)
+}
+
+// toolResultText returns the text content of an MCP tool result
+func toolResultText(result interface{}) string {
+	const key = "text"
+	routed := routeToolContent(result, map[string]string{workflow.ContentTypeText: key})
+	text, _ := routed[key].(string)
+	return text
+}
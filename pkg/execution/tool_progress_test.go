@@ -0,0 +1,101 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingClient streams its chunks as progress notifications, unless
+// silent, before returning the whole text, recording the stream variable
+// after each chunk
+type streamingClient struct {
+	chunks []string
+	silent bool
+	exec   *execution.Execution
+	seen   []interface{}
+}
+
+func (c *streamingClient) Connect(ctx context.Context) error                       { return nil }
+func (c *streamingClient) Close() error                                            { return nil }
+func (c *streamingClient) IsConnected() bool                                       { return true }
+func (c *streamingClient) ListTools(ctx context.Context) ([]mcpserver.Tool, error) { return nil, nil }
+func (c *streamingClient) Ping(ctx context.Context) error                          { return nil }
+
+func (c *streamingClient) CallTool(ctx context.Context, toolName string, params map[string]interface{}) (map[string]interface{}, error) {
+	report := mcpserver.ToolProgressHandler(ctx)
+	full := ""
+	for i, chunk := range c.chunks {
+		full += chunk
+		if report == nil || c.silent {
+			continue
+		}
+		report(mcpserver.ToolProgress{
+			Progress: float64(i + 1),
+			Total:    float64(len(c.chunks)),
+			Content:  []interface{}{map[string]interface{}{"type": "text", "text": chunk}},
+		})
+		value, _ := c.exec.Context.GetVariable("partial")
+		c.seen = append(c.seen, value)
+	}
+	return map[string]interface{}{
+		"content": []interface{}{map[string]interface{}{"type": "text", "text": full}},
+	}, nil
+}
+
+func TestMCPToolNode_StreamOutput(t *testing.T) {
+	var events []ExecutionEvent
+	engine := NewEngine(WithEventHandler(func(event ExecutionEvent) {
+		events = append(events, event)
+	}))
+	defer engine.Close()
+
+	server, err := mcpserver.NewMCPServer("llm", "mock", nil, mcpserver.TransportStdio)
+	require.NoError(t, err)
+	_ = server.Connect()
+	_ = server.CompleteConnection()
+	server.Tools = []mcpserver.Tool{{Name: "generate"}}
+	require.NoError(t, engine.serverRegistry.Register(server))
+
+	exec, err := execution.NewExecution("stream-workflow", "1.0", nil)
+	require.NoError(t, err)
+	client := &streamingClient{chunks: []string{"Once ", "upon ", "a time"}, exec: exec}
+	server.SetClient(client)
+	engine.monitor = &monitor{exec: exec, handler: engine.eventHandler}
+
+	node := &workflow.MCPToolNode{
+		ID:             "write",
+		ServerID:       "llm",
+		ToolName:       "generate",
+		OutputVariable: "story",
+		StreamOutput:   "partial",
+	}
+	nodeExec := execution.NewNodeExecution(exec.ID, "write", "mcp_tool")
+	require.NoError(t, engine.executeMCPToolNode(context.Background(), node, nil, exec, nodeExec))
+
+	assert.Equal(t, []interface{}{"Once ", "Once upon ", "Once upon a time"}, client.seen, "the variable grows as chunks arrive")
+	partial, _ := exec.Context.GetVariable("partial")
+	assert.Equal(t, "Once upon a time", partial)
+
+	require.Len(t, events, 3)
+	last := events[2]
+	assert.Equal(t, EventToolProgress, last.Type)
+	assert.Equal(t, "write", string(last.NodeID))
+	assert.Equal(t, float64(3), last.Metadata["progress"])
+	assert.Equal(t, float64(3), last.Metadata["total"])
+	assert.Equal(t, "a time", last.Metadata["text"])
+
+	// Servers that do not stream still fill the variable from the result
+	engine.monitor = nil
+	server.SetClient(&streamingClient{chunks: []string{"The ", "end"}, silent: true, exec: exec})
+	node.StreamOutput = "tail"
+	nodeExec = execution.NewNodeExecution(exec.ID, "write", "mcp_tool")
+	require.NoError(t, engine.executeMCPToolNode(context.Background(), node, nil, exec, nodeExec))
+	tail, _ := exec.Context.GetVariable("tail")
+	assert.Equal(t, "The end", tail)
+}
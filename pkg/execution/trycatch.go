@@ -0,0 +1,99 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// errorEdgeTargets returns the nodes reached through the error edges of
+// nodeID, in edge order
+func errorEdgeTargets(nodeID string, wf *workflow.Workflow) []string {
+	var targets []string
+	for _, edge := range wf.Edges {
+		if edge.FromNodeID == nodeID && edge.OnError {
+			targets = append(targets, edge.ToNodeID)
+		}
+	}
+	return targets
+}
+
+// executeTryNode runs the try node's body nodes in order. The first body
+// node that fails fails the try node, so the failure follows the try node's
+// error edges.
+func (e *Engine) executeTryNode(ctx context.Context, node *workflow.TryNode, wf *workflow.Workflow, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
+	nodeMap := make(map[string]workflow.Node)
+	for _, n := range wf.Nodes {
+		nodeMap[n.GetID()] = n
+	}
+
+	nodeExec.Inputs = map[string]interface{}{
+		"body": node.Body,
+	}
+
+	for _, nodeID := range node.Body {
+		bodyNode, exists := nodeMap[nodeID]
+		if !exists {
+			return fmt.Errorf("try body node '%s' not found in workflow", nodeID)
+		}
+		if err := e.executeNode(ctx, bodyNode, wf, exec); err != nil {
+			return fmt.Errorf("try body node '%s' failed: %w", nodeID, err)
+		}
+	}
+
+	nodeExec.Outputs = map[string]interface{}{
+		"completed": len(node.Body),
+	}
+	return nil
+}
+
+// executeCatchNode stores the error object of the failure that led to the
+// catch node in its error variable
+func (e *Engine) executeCatchNode(ctx context.Context, node *workflow.CatchNode, exec *execution.Execution, nodeExec *execution.NodeExecution) error {
+	caught := caughtError(exec)
+	if caught == nil {
+		return fmt.Errorf("catch node '%s' reached without a failed node", node.ID)
+	}
+
+	if err := exec.Context.SetVariableWithNode(node.ErrorVariable, caught, nodeExec.ID); err != nil {
+		return fmt.Errorf("failed to set error variable '%s': %w", node.ErrorVariable, err)
+	}
+
+	// Log variable change
+	if e.logger != nil {
+		snapshots := exec.Context.GetVariableHistory()
+		if len(snapshots) > 0 {
+			e.logger.LogVariableChange(&snapshots[len(snapshots)-1])
+		}
+	}
+
+	nodeExec.Outputs = map[string]interface{}{
+		node.ErrorVariable: caught,
+	}
+	return nil
+}
+
+// caughtError returns the error object of the most recent failed node, or
+// nil if no node has failed. A failed try node stands in for the body node
+// that failed inside it, so the body node's failure is reported instead.
+func caughtError(exec *execution.Execution) map[string]interface{} {
+	for i := len(exec.NodeExecutions) - 1; i >= 0; i-- {
+		nodeExec := exec.NodeExecutions[i]
+		if nodeExec.Status != execution.NodeStatusFailed || nodeExec.NodeType == "try" {
+			continue
+		}
+
+		caught := map[string]interface{}{
+			"node_id":   string(nodeExec.NodeID),
+			"node_type": nodeExec.NodeType,
+		}
+		if nodeExec.Error != nil {
+			caught["type"] = string(nodeExec.Error.Type)
+			caught["message"] = nodeExec.Error.Message
+		}
+		return caught
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tryCatchWorkflowYAML = `
+version: "1.0"
+name: "try-catch-test"
+variables:
+  - name: "data"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "guard"
+    type: "try"
+    body: ["parse"]
+  - id: "parse"
+    type: "transform"
+    input: "data"
+    expression: "$.user.name"
+    output: "name"
+  - id: "handle"
+    type: "catch"
+    error_variable: "err"
+  - id: "ok"
+    type: "end"
+  - id: "recovered"
+    type: "end"
+    return: "${err.node_id}"
+edges:
+  - from: "start"
+    to: "guard"
+  - from: "guard"
+    to: "ok"
+  - from: "guard"
+    to: "handle"
+    on_error: true
+  - from: "handle"
+    to: "recovered"
+`
+
+func executedNodes(exec *execution.Execution) []types.NodeID {
+	var ids []types.NodeID
+	for _, nodeExec := range exec.NodeExecutions {
+		ids = append(ids, nodeExec.NodeID)
+	}
+	return ids
+}
+
+const errorEdgeWorkflowYAML = `
+version: "1.0"
+name: "error-edge-test"
+variables:
+  - name: "data"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "parse"
+    type: "transform"
+    input: "data"
+    expression: "$.user.name"
+    output: "name"
+  - id: "fallback"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "parse"
+  - from: "parse"
+    to: "end"
+  - from: "parse"
+    to: "fallback"
+    on_error: true
+  - from: "fallback"
+    to: "end"
+`
+
+func TestEngine_ErrorEdge(t *testing.T) {
+	wf, err := workflow.Parse([]byte(errorEdgeWorkflowYAML))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		inputs map[string]interface{}
+		want   []types.NodeID
+	}{
+		{
+			name:   "success skips the error edge",
+			inputs: map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"name": "ada"}}},
+			want:   []types.NodeID{"start", "parse", "end"},
+		},
+		{
+			name: "failure follows the error edge",
+			want: []types.NodeID{"start", "parse", "fallback", "end"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+			defer engine.Close()
+
+			exec, err := engine.Execute(context.Background(), wf, tt.inputs)
+			require.NoError(t, err)
+			assert.Equal(t, execution.StatusCompleted, exec.Status)
+			assert.Equal(t, tt.want, executedNodes(exec))
+		})
+	}
+}
+
+func TestEngine_FailureWithoutErrorEdge(t *testing.T) {
+	wf, err := workflow.Parse([]byte(errorEdgeWorkflowYAML))
+	require.NoError(t, err)
+	require.NoError(t, wf.RemoveNode("fallback"))
+
+	engine := NewEngine()
+	defer engine.Close()
+
+	exec, err := engine.Execute(context.Background(), wf, nil)
+	require.Error(t, err)
+	assert.Equal(t, execution.StatusFailed, exec.Status)
+}
+
+func TestEngine_TryCatch(t *testing.T) {
+	wf, err := workflow.Parse([]byte(tryCatchWorkflowYAML))
+	require.NoError(t, err)
+
+	t.Run("body succeeds", func(t *testing.T) {
+		engine := NewEngine()
+		defer engine.Close()
+
+		inputs := map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"name": "ada"}}}
+		exec, err := engine.Execute(context.Background(), wf, inputs)
+		require.NoError(t, err)
+		assert.Equal(t, execution.StatusCompleted, exec.Status)
+		assert.Equal(t, []types.NodeID{"start", "parse", "guard", "ok"}, executedNodes(exec))
+	})
+
+	t.Run("body fails", func(t *testing.T) {
+		engine := NewEngine()
+		defer engine.Close()
+
+		exec, err := engine.Execute(context.Background(), wf, nil)
+		require.NoError(t, err)
+		assert.Equal(t, execution.StatusCompleted, exec.Status)
+		assert.Equal(t, []types.NodeID{"start", "parse", "guard", "handle", "recovered"}, executedNodes(exec))
+
+		// The catch node captures the body node's failure, not the try node's
+		caught, ok := exec.Context.GetVariable("err")
+		require.True(t, ok)
+		errObj := caught.(map[string]interface{})
+		assert.Equal(t, "parse", errObj["node_id"])
+		assert.Equal(t, "transform", errObj["node_type"])
+		assert.Contains(t, errObj["message"], "input variable 'data' not found")
+		assert.Equal(t, "parse", exec.ReturnValue)
+	})
+}
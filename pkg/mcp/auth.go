@@ -0,0 +1,254 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// SecretResolver looks up secret values by reference.
+// storage.CredentialStore satisfies this interface.
+type SecretResolver interface {
+	Get(key string) (string, error)
+}
+
+// AuthProvider adds authentication to outgoing HTTP requests
+type AuthProvider interface {
+	// Apply sets authentication headers on the request
+	Apply(ctx context.Context, req *http.Request) error
+
+	// Invalidate discards any cached credentials so the next Apply
+	// fetches fresh ones (called after a 401 response)
+	Invalidate()
+}
+
+// tokenRefreshSkew is how long before expiry an OAuth2 token is refreshed
+const tokenRefreshSkew = 30 * time.Second
+
+// NewAuthProvider creates an AuthProvider for the given configuration,
+// resolving secret references through secrets.
+// Returns nil (and no error) when config is nil or has no auth type.
+func NewAuthProvider(config *mcpserver.AuthConfig, secrets SecretResolver) (AuthProvider, error) {
+	if config == nil || config.Type == mcpserver.AuthNone {
+		return nil, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if secrets == nil {
+		return nil, fmt.Errorf("%s auth requires a secret resolver", config.Type)
+	}
+
+	switch config.Type {
+	case mcpserver.AuthBearer:
+		return &staticHeaderAuth{
+			header:    "Authorization",
+			prefix:    "Bearer ",
+			secretRef: config.TokenRef,
+			secrets:   secrets,
+		}, nil
+
+	case mcpserver.AuthAPIKey:
+		return &staticHeaderAuth{
+			header:    config.APIKeyHeader(),
+			secretRef: config.KeyRef,
+			secrets:   secrets,
+		}, nil
+
+	case mcpserver.AuthOAuth2ClientCredentials:
+		return &oauth2ClientCredentials{
+			tokenURL:        config.TokenURL,
+			clientID:        config.ClientID,
+			clientSecretRef: config.ClientSecretRef,
+			scopes:          config.Scopes,
+			secrets:         secrets,
+			httpClient:      &http.Client{Timeout: 30 * time.Second},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported auth type: %s", config.Type)
+	}
+}
+
+// staticHeaderAuth sends a secret value in a fixed header (bearer token or API key)
+type staticHeaderAuth struct {
+	header    string
+	prefix    string
+	secretRef string
+	secrets   SecretResolver
+
+	mu    sync.Mutex
+	value string
+}
+
+// Apply sets the header, resolving the secret on first use
+func (a *staticHeaderAuth) Apply(ctx context.Context, req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.value == "" {
+		secret, err := a.secrets.Get(a.secretRef)
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret %q: %w", a.secretRef, err)
+		}
+		if secret == "" {
+			return fmt.Errorf("secret %q is empty", a.secretRef)
+		}
+		a.value = secret
+	}
+
+	req.Header.Set(a.header, a.prefix+a.value)
+	return nil
+}
+
+// Invalidate forces the secret to be re-read, picking up rotated credentials
+func (a *staticHeaderAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.value = ""
+}
+
+// oauth2ClientCredentials implements the OAuth2 client-credentials grant
+// with token caching and refresh before expiry
+type oauth2ClientCredentials struct {
+	tokenURL        string
+	clientID        string
+	clientSecretRef string
+	scopes          []string
+	secrets         SecretResolver
+	httpClient      *http.Client
+
+	mu        sync.Mutex
+	token     string
+	tokenType string
+	expiresAt time.Time
+}
+
+// oauth2TokenResponse is the token endpoint response (RFC 6749 section 5.1)
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Apply sets the Authorization header, fetching a new token if needed
+func (a *oauth2ClientCredentials) Apply(ctx context.Context, req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || (!a.expiresAt.IsZero() && time.Now().Add(tokenRefreshSkew).After(a.expiresAt)) {
+		if err := a.fetchToken(ctx); err != nil {
+			return err
+		}
+	}
+
+	tokenType := a.tokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	req.Header.Set("Authorization", tokenType+" "+a.token)
+	return nil
+}
+
+// Invalidate discards the cached token
+func (a *oauth2ClientCredentials) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = ""
+	a.expiresAt = time.Time{}
+}
+
+// fetchToken requests a new access token. Caller must hold a.mu.
+func (a *oauth2ClientCredentials) fetchToken(ctx context.Context) error {
+	secret, err := a.secrets.Get(a.clientSecretRef)
+	if err != nil {
+		return fmt.Errorf("failed to resolve secret %q: %w", a.clientSecretRef, err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(a.scopes) > 0 {
+		form.Set("scope", strings.Join(a.scopes, " "))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.SetBasicAuth(url.QueryEscape(a.clientID), url.QueryEscape(secret))
+
+	httpResp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to request OAuth2 token: %w", err)
+	}
+	defer func() {
+		if err := httpResp.Body.Close(); err != nil {
+			// Log error but don't fail - response was already received
+			_ = err
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OAuth2 token request failed with status %d: %s (body: %s)", httpResp.StatusCode, httpResp.Status, string(body))
+	}
+
+	var tokenResp oauth2TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return fmt.Errorf("OAuth2 token response missing access_token")
+	}
+
+	a.token = tokenResp.AccessToken
+	a.tokenType = tokenResp.TokenType
+	if tokenResp.ExpiresIn > 0 {
+		a.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	} else {
+		a.expiresAt = time.Time{}
+	}
+
+	return nil
+}
+
+// doWithAuth builds a request with newReq, applies auth and sends it.
+// If the server answers 401 Unauthorized, cached credentials are invalidated
+// and the request is rebuilt and retried once.
+func doWithAuth(ctx context.Context, client *http.Client, auth AuthProvider, newReq func() (*http.Request, error)) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		if auth != nil {
+			if err := auth.Apply(ctx, req); err != nil {
+				return nil, fmt.Errorf("failed to apply authentication: %w", err)
+			}
+		}
+		return client.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || auth == nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	_ = resp.Body.Close()
+	auth.Invalidate()
+	return send()
+}
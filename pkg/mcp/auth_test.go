@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// mapSecrets is an in-memory SecretResolver for tests
+type mapSecrets map[string]string
+
+func (m mapSecrets) Get(key string) (string, error) {
+	value, ok := m[key]
+	if !ok {
+		return "", fmt.Errorf("credential not found: %s", key)
+	}
+	return value, nil
+}
+
+func TestNewAuthProvider_None(t *testing.T) {
+	provider, err := NewAuthProvider(nil, nil)
+	if err != nil || provider != nil {
+		t.Fatalf("NewAuthProvider(nil) = %v, %v; want nil, nil", provider, err)
+	}
+
+	provider, err = NewAuthProvider(&mcpserver.AuthConfig{}, nil)
+	if err != nil || provider != nil {
+		t.Fatalf("NewAuthProvider(empty) = %v, %v; want nil, nil", provider, err)
+	}
+}
+
+func TestNewAuthProvider_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *mcpserver.AuthConfig
+		secrets SecretResolver
+	}{
+		{"unknown type", &mcpserver.AuthConfig{Type: "magic"}, mapSecrets{}},
+		{"bearer without ref", &mcpserver.AuthConfig{Type: mcpserver.AuthBearer}, mapSecrets{}},
+		{"api key without ref", &mcpserver.AuthConfig{Type: mcpserver.AuthAPIKey}, mapSecrets{}},
+		{"oauth2 without token url", &mcpserver.AuthConfig{Type: mcpserver.AuthOAuth2ClientCredentials, ClientID: "id", ClientSecretRef: "s"}, mapSecrets{}},
+		{"missing resolver", &mcpserver.AuthConfig{Type: mcpserver.AuthBearer, TokenRef: "t"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthProvider(tt.config, tt.secrets); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestStaticHeaderAuth(t *testing.T) {
+	secrets := mapSecrets{"token": "abc123", "key": "k-1"}
+
+	tests := []struct {
+		name       string
+		config     *mcpserver.AuthConfig
+		header     string
+		wantHeader string
+	}{
+		{
+			name:       "bearer",
+			config:     &mcpserver.AuthConfig{Type: mcpserver.AuthBearer, TokenRef: "token"},
+			header:     "Authorization",
+			wantHeader: "Bearer abc123",
+		},
+		{
+			name:       "api key default header",
+			config:     &mcpserver.AuthConfig{Type: mcpserver.AuthAPIKey, KeyRef: "key"},
+			header:     "X-API-Key",
+			wantHeader: "k-1",
+		},
+		{
+			name:       "api key custom header",
+			config:     &mcpserver.AuthConfig{Type: mcpserver.AuthAPIKey, KeyRef: "key", HeaderName: "X-Token"},
+			header:     "X-Token",
+			wantHeader: "k-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewAuthProvider(tt.config, secrets)
+			if err != nil {
+				t.Fatalf("NewAuthProvider() error = %v", err)
+			}
+
+			req, _ := http.NewRequest("POST", "http://example.com", nil)
+			if err := provider.Apply(context.Background(), req); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if got := req.Header.Get(tt.header); got != tt.wantHeader {
+				t.Errorf("header %s = %q, want %q", tt.header, got, tt.wantHeader)
+			}
+		})
+	}
+
+	t.Run("missing secret", func(t *testing.T) {
+		provider, _ := NewAuthProvider(&mcpserver.AuthConfig{Type: mcpserver.AuthBearer, TokenRef: "nope"}, secrets)
+		req, _ := http.NewRequest("POST", "http://example.com", nil)
+		if err := provider.Apply(context.Background(), req); err == nil {
+			t.Error("expected error for missing secret")
+		}
+	})
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&tokenRequests, 1)
+
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Form.Get("scope") != "tools:read tools:call" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	provider, err := NewAuthProvider(&mcpserver.AuthConfig{
+		Type:            mcpserver.AuthOAuth2ClientCredentials,
+		TokenURL:        tokenServer.URL,
+		ClientID:        "client",
+		ClientSecretRef: "oauth-secret",
+		Scopes:          []string{"tools:read", "tools:call"},
+	}, mapSecrets{"oauth-secret": "s3cret"})
+	if err != nil {
+		t.Fatalf("NewAuthProvider() error = %v", err)
+	}
+
+	apply := func() string {
+		t.Helper()
+		req, _ := http.NewRequest("POST", "http://example.com", nil)
+		if err := provider.Apply(context.Background(), req); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		return req.Header.Get("Authorization")
+	}
+
+	if got := apply(); got != "Bearer token-1" {
+		t.Errorf("first Authorization = %q, want %q", got, "Bearer token-1")
+	}
+	// Cached token is reused
+	if got := apply(); got != "Bearer token-1" {
+		t.Errorf("cached Authorization = %q, want %q", got, "Bearer token-1")
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Errorf("token endpoint called %d times, want 1", n)
+	}
+
+	// Invalidation forces a refresh
+	provider.Invalidate()
+	if got := apply(); got != "Bearer token-2" {
+		t.Errorf("refreshed Authorization = %q, want %q", got, "Bearer token-2")
+	}
+
+	// Expired tokens are refreshed
+	oauth := provider.(*oauth2ClientCredentials)
+	oauth.mu.Lock()
+	oauth.expiresAt = time.Now().Add(-time.Minute)
+	oauth.mu.Unlock()
+	if got := apply(); got != "Bearer token-3" {
+		t.Errorf("Authorization after expiry = %q, want %q", got, "Bearer token-3")
+	}
+}
+
+func TestHTTPClient_AuthRetryOnUnauthorized(t *testing.T) {
+	var validToken atomic.Value
+	validToken.Store("old")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+validToken.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req JSONRPCRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]interface{}{},
+		})
+	}))
+	defer server.Close()
+
+	secrets := mapSecrets{"token": "old"}
+	auth, err := NewAuthProvider(&mcpserver.AuthConfig{Type: mcpserver.AuthBearer, TokenRef: "token"}, secrets)
+	if err != nil {
+		t.Fatalf("NewAuthProvider() error = %v", err)
+	}
+
+	client, err := NewHTTPClient(HTTPConfig{BaseURL: server.URL, Auth: auth})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() with valid token error = %v", err)
+	}
+
+	// Rotate the credential: the cached token now gets a 401, which must
+	// trigger a re-read of the secret and a single retry
+	validToken.Store("new")
+	secrets["token"] = "new"
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() after rotation error = %v", err)
+	}
+
+	// A credential that stays invalid surfaces the 401
+	validToken.Store("other")
+	if err := client.Ping(context.Background()); err == nil {
+		t.Fatal("expected error when credentials remain invalid")
+	}
+}
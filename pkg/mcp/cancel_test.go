@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// nopWriteCloser collects what a client writes to the server
+type nopWriteCloser struct {
+	bytes.Buffer
+}
+
+func (w *nopWriteCloser) Close() error { return nil }
+
+func TestStdioClient_NotifiesCancelledRequests(t *testing.T) {
+	stdin := &nopWriteCloser{}
+	client := &StdioClient{
+		config:          ServerConfig{ID: "test"},
+		stdin:           stdin,
+		pendingRequests: make(map[interface{}]chan *JSONRPCResponse),
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("stopped by operator"))
+
+	if _, err := client.CallTool(ctx, "slow_tool", nil); err == nil {
+		t.Fatal("CallTool() succeeded with a cancelled context")
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdin.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d messages, want the request and a cancellation: %q", len(lines), stdin.String())
+	}
+
+	var request JSONRPCRequest
+	if err := json.Unmarshal([]byte(lines[0]), &request); err != nil {
+		t.Fatalf("invalid request: %v", err)
+	}
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			RequestID interface{} `json:"requestId"`
+			Reason    string      `json:"reason"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &notification); err != nil {
+		t.Fatalf("invalid notification: %v", err)
+	}
+
+	if notification.Method != "notifications/cancelled" {
+		t.Errorf("method = %q, want notifications/cancelled", notification.Method)
+	}
+	if notification.Params.RequestID != request.ID {
+		t.Errorf("requestId = %v, want %v", notification.Params.RequestID, request.ID)
+	}
+	if notification.Params.Reason != "stopped by operator" {
+		t.Errorf("reason = %q, want %q", notification.Params.Reason, "stopped by operator")
+	}
+}
+
+func TestStdioClient_DoesNotCancelInitialize(t *testing.T) {
+	stdin := &nopWriteCloser{}
+	client := &StdioClient{
+		config:          ServerConfig{ID: "test"},
+		stdin:           stdin,
+		pendingRequests: make(map[interface{}]chan *JSONRPCResponse),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := client.initialize(ctx); err == nil {
+		t.Fatal("initialize() succeeded with a cancelled context")
+	}
+	if strings.Contains(stdin.String(), "notifications/cancelled") {
+		t.Errorf("initialize was cancelled: %q", stdin.String())
+	}
+}
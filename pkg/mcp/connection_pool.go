@@ -9,8 +9,29 @@ import (
 	"time"
 )
 
-// createClient creates a new MCP client based on the transport type
+// NewClient creates an unconnected MCP client for the server's transport,
+// authenticating SSE and HTTP requests with config.Auth and applying any
+// configured request limits
+func NewClient(config ServerConfig) (Client, error) {
+	return createClient(config)
+}
+
+// createClient creates a new MCP client based on the transport type,
+// applying any configured request limits
 func createClient(config ServerConfig) (Client, error) {
+	if err := config.Limits.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := createTransportClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return WithRequestLimits(client, config.Limits), nil
+}
+
+// createTransportClient creates the transport-specific MCP client
+func createTransportClient(config ServerConfig) (Client, error) {
 	// Default to stdio if transport not specified
 	transport := config.Transport
 	if transport == "" {
@@ -22,15 +43,25 @@ func createClient(config ServerConfig) (Client, error) {
 		return NewStdioClient(config)
 
 	case "sse":
+		auth, err := NewAuthProvider(config.Auth, config.Secrets)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth configuration: %w", err)
+		}
 		return NewSSEClient(SSEConfig{
 			URL:     config.URL,
 			Headers: config.Headers,
+			Auth:    auth,
 		})
 
 	case "http":
+		auth, err := NewAuthProvider(config.Auth, config.Secrets)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth configuration: %w", err)
+		}
 		return NewHTTPClient(HTTPConfig{
 			BaseURL: config.URL,
 			Headers: config.Headers,
+			Auth:    auth,
 		})
 
 	default:
@@ -4,10 +4,12 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"github.com/dshills/goflow/pkg/config"
 )
 
 const (
-	// HealthCheckInterval is how often to check server health
+	// HealthCheckInterval is the default interval between server health checks
 	HealthCheckInterval = 30 * time.Second
 	// HealthCheckTimeout is the timeout for a single health check
 	HealthCheckTimeout = 5 * time.Second
@@ -22,6 +24,9 @@ type HealthMonitor struct {
 	mu           sync.RWMutex
 	stopChan     chan struct{}
 	stopped      bool
+	interval     time.Duration
+	intervalChan chan time.Duration
+	unsubscribe  func()
 }
 
 // ServerHealth tracks health status for a server
@@ -41,8 +46,15 @@ func NewHealthMonitor(pool *ConnectionPool) *HealthMonitor {
 		pool:         pool,
 		healthStatus: make(map[string]*ServerHealth),
 		stopChan:     make(chan struct{}),
+		interval:     HealthCheckInterval,
+		intervalChan: make(chan time.Duration, 1),
 	}
 
+	// Follow the health_check_interval tunable, including live changes
+	hm.unsubscribe = config.Global().Subscribe(func(t config.Tunables) {
+		hm.SetCheckInterval(t.HealthCheckInterval())
+	})
+
 	// Start background health checking
 	go hm.startHealthChecks()
 
@@ -109,6 +121,36 @@ func (hm *HealthMonitor) CheckNow(ctx context.Context, serverID string) error {
 	return hm.performHealthCheck(ctx, serverID)
 }
 
+// SetCheckInterval changes how often servers are checked. The new interval
+// takes effect immediately; non-positive values are ignored.
+func (hm *HealthMonitor) SetCheckInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	if hm.stopped || interval == hm.interval {
+		return
+	}
+	hm.interval = interval
+
+	// Replace any pending change so the latest interval wins
+	select {
+	case <-hm.intervalChan:
+	default:
+	}
+	hm.intervalChan <- interval
+}
+
+// CheckInterval returns the current health check interval
+func (hm *HealthMonitor) CheckInterval() time.Duration {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+	return hm.interval
+}
+
 // Stop stops the health monitor
 func (hm *HealthMonitor) Stop() {
 	hm.mu.Lock()
@@ -117,18 +159,23 @@ func (hm *HealthMonitor) Stop() {
 	if !hm.stopped {
 		close(hm.stopChan)
 		hm.stopped = true
+		if hm.unsubscribe != nil {
+			hm.unsubscribe()
+		}
 	}
 }
 
 // startHealthChecks runs periodic health checks in the background
 func (hm *HealthMonitor) startHealthChecks() {
-	ticker := time.NewTicker(HealthCheckInterval)
+	ticker := time.NewTicker(hm.CheckInterval())
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-hm.stopChan:
 			return
+		case interval := <-hm.intervalChan:
+			ticker.Reset(interval)
 		case <-ticker.C:
 			hm.checkAllServers()
 		}
@@ -18,6 +18,7 @@ import (
 type HTTPClient struct {
 	baseURL    string
 	headers    map[string]string
+	auth       AuthProvider
 	httpClient *http.Client
 	mu         sync.Mutex
 	closed     bool
@@ -29,6 +30,7 @@ type HTTPConfig struct {
 	BaseURL string
 	Headers map[string]string
 	Timeout time.Duration
+	Auth    AuthProvider // Optional authentication (see NewAuthProvider)
 }
 
 // NewHTTPClient creates a new HTTP-based MCP client
@@ -45,6 +47,7 @@ func NewHTTPClient(config HTTPConfig) (*HTTPClient, error) {
 	return &HTTPClient{
 		baseURL: config.BaseURL,
 		headers: config.Headers,
+		auth:    config.Auth,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
@@ -108,17 +111,7 @@ func (c *HTTPClient) initialize(ctx context.Context) error {
 	}
 
 	// Send notification as POST (we don't wait for response)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewReader(notifJSON))
-	if err != nil {
-		return fmt.Errorf("failed to create notification request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	for key, value := range c.headers {
-		httpReq.Header.Set(key, value)
-	}
-
-	httpResp, err := c.httpClient.Do(httpReq)
+	httpResp, err := c.post(ctx, notifJSON)
 	if err != nil {
 		return fmt.Errorf("failed to send initialized notification: %w", err)
 	}
@@ -158,19 +151,8 @@ func (c *HTTPClient) sendRequest(ctx context.Context, method string, params inte
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewReader(reqJSON))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	for key, value := range c.headers {
-		httpReq.Header.Set(key, value)
-	}
-
 	// Send HTTP request
-	httpResp, err := c.httpClient.Do(httpReq)
+	httpResp, err := c.post(ctx, reqJSON)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
@@ -201,6 +183,22 @@ func (c *HTTPClient) sendRequest(ctx context.Context, method string, params inte
 	return &resp, nil
 }
 
+// post sends a JSON payload to the endpoint with custom headers and authentication
+func (c *HTTPClient) post(ctx context.Context, payload []byte) (*http.Response, error) {
+	return doWithAuth(ctx, c.httpClient, c.auth, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		}
+
+		httpReq.Header.Set("Content-Type", "application/json")
+		for key, value := range c.headers {
+			httpReq.Header.Set(key, value)
+		}
+		return httpReq, nil
+	})
+}
+
 // Close terminates the connection to the MCP server
 func (c *HTTPClient) Close() error {
 	c.mu.Lock()
@@ -1,9 +1,13 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"sync/atomic"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
 )
 
 // JSONRPCRequest represents a JSON-RPC 2.0 request
@@ -45,6 +49,96 @@ func newRequestID() string {
 	return fmt.Sprintf("%d", atomic.AddUint64(&requestIDCounter, 1))
 }
 
+// cancelledNotification returns the notifications/cancelled message asking a
+// server to stop working on a request the client no longer waits for
+func cancelledNotification(requestID interface{}, reason string) ([]byte, error) {
+	params := map[string]interface{}{
+		"requestId": requestID,
+	}
+	if reason != "" {
+		params["reason"] = reason
+	}
+	return json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "notifications/cancelled",
+		"params":  params,
+	})
+}
+
+// cancellationReason describes why ctx was cancelled: its cause, such as the
+// reason an execution was stopped, or "context canceled"
+func cancellationReason(ctx context.Context) string {
+	if cause := context.Cause(ctx); cause != nil {
+		return cause.Error()
+	}
+	return ""
+}
+
+// progressRouter delivers notifications/progress messages to the handlers
+// of the tool calls that asked for them, by progress token
+type progressRouter struct {
+	mu       sync.Mutex
+	handlers map[string]mcpserver.ToolProgressFunc
+}
+
+// track asks the server for progress notifications on a tools/call request
+// when ctx carries a handler, returning a function that stops routing them
+func (r *progressRouter) track(ctx context.Context, callParams map[string]interface{}) func() {
+	handler := mcpserver.ToolProgressHandler(ctx)
+	if handler == nil {
+		return func() {}
+	}
+	token := "progress-" + newRequestID()
+	callParams["_meta"] = map[string]interface{}{"progressToken": token}
+
+	r.mu.Lock()
+	if r.handlers == nil {
+		r.handlers = make(map[string]mcpserver.ToolProgressFunc)
+	}
+	r.handlers[token] = handler
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		delete(r.handlers, token)
+		r.mu.Unlock()
+	}
+}
+
+// dispatch hands a progress notification to its call's handler. It reports
+// whether message was a progress notification, routed or not.
+func (r *progressRouter) dispatch(message []byte) bool {
+	var notification struct {
+		ID     interface{} `json:"id"`
+		Method string      `json:"method"`
+		Params struct {
+			ProgressToken interface{}   `json:"progressToken"`
+			Progress      float64       `json:"progress"`
+			Total         float64       `json:"total"`
+			Message       string        `json:"message"`
+			Content       []interface{} `json:"content"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &notification); err != nil ||
+		notification.ID != nil || notification.Method != "notifications/progress" {
+		return false
+	}
+
+	token := fmt.Sprint(notification.Params.ProgressToken)
+	r.mu.Lock()
+	handler := r.handlers[token]
+	r.mu.Unlock()
+	if handler != nil {
+		handler(mcpserver.ToolProgress{
+			Progress: notification.Params.Progress,
+			Total:    notification.Params.Total,
+			Message:  notification.Params.Message,
+			Content:  notification.Params.Content,
+		})
+	}
+	return true
+}
+
 // newRequest creates a new JSON-RPC request
 func newRequest(method string, params interface{}) (*JSONRPCRequest, error) {
 	var paramsJSON json.RawMessage
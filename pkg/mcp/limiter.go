@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// ErrRequestQueueTimeout is returned when a request waits longer than the
+// queue timeout for a concurrency slot or rate limit token
+var ErrRequestQueueTimeout = errors.New("timed out waiting for request slot")
+
+// RequestLimiter enforces per-server concurrency and rate limits.
+// Requests over the limits queue until a slot frees up or the queue
+// timeout expires. A nil *RequestLimiter imposes no limits.
+type RequestLimiter struct {
+	slots        chan struct{} // nil when concurrency is unlimited
+	queueTimeout time.Duration
+
+	mu     sync.Mutex
+	rate   float64 // tokens per second (0 = unlimited)
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRequestLimiter creates a limiter for the given limits.
+// Returns nil when limits is nil or sets no limits.
+func NewRequestLimiter(limits *mcpserver.RequestLimits) *RequestLimiter {
+	if limits.IsZero() {
+		return nil
+	}
+
+	l := &RequestLimiter{
+		queueTimeout: limits.EffectiveQueueTimeout(),
+		rate:         limits.RequestsPerSecond,
+		burst:        float64(limits.EffectiveBurst()),
+		last:         time.Now(),
+	}
+	l.tokens = l.burst
+	if limits.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return l
+}
+
+// Acquire waits for a concurrency slot and a rate token. The returned
+// release function must be called when the request completes.
+func (l *RequestLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	queueCtx, cancel := context.WithTimeout(ctx, l.queueTimeout)
+	defer cancel()
+
+	release = func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			var once sync.Once
+			release = func() {
+				once.Do(func() { <-l.slots })
+			}
+		case <-queueCtx.Done():
+			return nil, l.waitError(ctx)
+		}
+	}
+
+	if wait := l.reserve(time.Now()); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-queueCtx.Done():
+			l.cancelReservation()
+			release()
+			return nil, l.waitError(ctx)
+		}
+	}
+
+	return release, nil
+}
+
+// reserve takes a rate token and returns how long to wait until it is valid
+func (l *RequestLimiter) reserve(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancelReservation returns a token taken by reserve
+func (l *RequestLimiter) cancelReservation() {
+	if l.rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// waitError reports why waiting stopped: caller cancellation or queue timeout
+func (l *RequestLimiter) waitError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w after %s", ErrRequestQueueTimeout, l.queueTimeout)
+}
+
+// limitedClient wraps a Client and applies a RequestLimiter to tool
+// listing and tool calls. Ping bypasses the limiter so health checks
+// are not queued behind slow requests.
+type limitedClient struct {
+	Client
+	limiter *RequestLimiter
+}
+
+// WithRequestLimits wraps client so requests honor limits.
+// Returns client unchanged when no limits are configured.
+func WithRequestLimits(client Client, limits *mcpserver.RequestLimits) Client {
+	limiter := NewRequestLimiter(limits)
+	if limiter == nil {
+		return client
+	}
+	return &limitedClient{Client: client, limiter: limiter}
+}
+
+// ListTools retrieves tools once a request slot is available
+func (c *limitedClient) ListTools(ctx context.Context) ([]mcpserver.Tool, error) {
+	release, err := c.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tools: %w", err)
+	}
+	defer release()
+
+	return c.Client.ListTools(ctx)
+}
+
+// CallTool invokes a tool once a request slot is available
+func (c *limitedClient) CallTool(ctx context.Context, toolName string, params map[string]interface{}) (map[string]interface{}, error) {
+	release, err := c.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("call tool %s: %w", toolName, err)
+	}
+	defer release()
+
+	return c.Client.CallTool(ctx, toolName, params)
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// slowClient is a Client whose CallTool blocks for a fixed delay and
+// records the peak number of concurrent calls
+type slowClient struct {
+	delay    time.Duration
+	inFlight int32
+	peak     int32
+	calls    int32
+}
+
+func (c *slowClient) Connect(ctx context.Context) error { return nil }
+func (c *slowClient) Close() error                      { return nil }
+func (c *slowClient) IsConnected() bool                 { return true }
+func (c *slowClient) Ping(ctx context.Context) error    { return nil }
+func (c *slowClient) ListTools(ctx context.Context) ([]mcpserver.Tool, error) {
+	return nil, nil
+}
+
+func (c *slowClient) CallTool(ctx context.Context, toolName string, params map[string]interface{}) (map[string]interface{}, error) {
+	n := atomic.AddInt32(&c.inFlight, 1)
+	defer atomic.AddInt32(&c.inFlight, -1)
+	atomic.AddInt32(&c.calls, 1)
+	for {
+		peak := atomic.LoadInt32(&c.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&c.peak, peak, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(c.delay):
+		return map[string]interface{}{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestNewRequestLimiter_NoLimits(t *testing.T) {
+	if l := NewRequestLimiter(nil); l != nil {
+		t.Error("NewRequestLimiter(nil) should return nil")
+	}
+	if l := NewRequestLimiter(&mcpserver.RequestLimits{QueueTimeout: time.Second}); l != nil {
+		t.Error("NewRequestLimiter() without limits should return nil")
+	}
+
+	// A nil limiter never blocks
+	var l *RequestLimiter
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("nil limiter Acquire() error = %v", err)
+	}
+	release()
+
+	client := &slowClient{}
+	if got := WithRequestLimits(client, nil); got != Client(client) {
+		t.Error("WithRequestLimits() without limits should return the client unchanged")
+	}
+}
+
+func TestRequestLimiter_MaxConcurrent(t *testing.T) {
+	client := &slowClient{delay: 20 * time.Millisecond}
+	limited := WithRequestLimits(client, &mcpserver.RequestLimits{MaxConcurrent: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limited.CallTool(context.Background(), "tool", nil); err != nil {
+				t.Errorf("CallTool() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := atomic.LoadInt32(&client.peak); peak > 2 {
+		t.Errorf("peak concurrent calls = %d, want <= 2", peak)
+	}
+	if calls := atomic.LoadInt32(&client.calls); calls != 8 {
+		t.Errorf("calls = %d, want 8", calls)
+	}
+}
+
+func TestRequestLimiter_QueueTimeout(t *testing.T) {
+	client := &slowClient{delay: 200 * time.Millisecond}
+	limited := WithRequestLimits(client, &mcpserver.RequestLimits{
+		MaxConcurrent: 1,
+		QueueTimeout:  20 * time.Millisecond,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = limited.CallTool(context.Background(), "tool", nil)
+	}()
+
+	// Wait until the first call holds the only slot
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&client.inFlight) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := limited.CallTool(context.Background(), "tool", nil)
+	if !errors.Is(err, ErrRequestQueueTimeout) {
+		t.Errorf("CallTool() error = %v, want ErrRequestQueueTimeout", err)
+	}
+
+	// Caller cancellation is reported as such, not as a queue timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limited.CallTool(ctx, "tool", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("CallTool() with cancelled context error = %v, want context.Canceled", err)
+	}
+
+	<-done
+}
+
+func TestRequestLimiter_RequestsPerSecond(t *testing.T) {
+	limiter := NewRequestLimiter(&mcpserver.RequestLimits{RequestsPerSecond: 50, Burst: 2})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		release, err := limiter.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		release()
+	}
+	elapsed := time.Since(start)
+
+	// Burst of 2 is immediate, the remaining 4 need 20ms each
+	if elapsed < 70*time.Millisecond {
+		t.Errorf("6 requests at 50/s with burst 2 took %v, want >= 80ms", elapsed)
+	}
+}
+
+func TestRequestLimiter_RateQueueTimeout(t *testing.T) {
+	limiter := NewRequestLimiter(&mcpserver.RequestLimits{
+		RequestsPerSecond: 1,
+		QueueTimeout:      10 * time.Millisecond,
+	})
+
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire() error = %v", err)
+	}
+	release()
+
+	if _, err := limiter.Acquire(context.Background()); !errors.Is(err, ErrRequestQueueTimeout) {
+		t.Errorf("second Acquire() error = %v, want ErrRequestQueueTimeout", err)
+	}
+
+	// The abandoned reservation is returned: after one second a token is available again
+	limiter.mu.Lock()
+	limiter.last = limiter.last.Add(-time.Second)
+	limiter.mu.Unlock()
+	if _, err := limiter.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire() after refill error = %v", err)
+	}
+}
+
+func TestRequestLimits_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  *mcpserver.RequestLimits
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", &mcpserver.RequestLimits{MaxConcurrent: 4, RequestsPerSecond: 10, Burst: 5}, false},
+		{"negative concurrency", &mcpserver.RequestLimits{MaxConcurrent: -1}, true},
+		{"negative rate", &mcpserver.RequestLimits{RequestsPerSecond: -1}, true},
+		{"negative timeout", &mcpserver.RequestLimits{QueueTimeout: -time.Second}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limits.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// streamingServer answers each tools/call with two progress notifications,
+// when the call asked for them, and then the result
+func streamingServer(t *testing.T, in io.Reader, out io.Writer) {
+	t.Helper()
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		var req struct {
+			ID     interface{} `json:"id"`
+			Params struct {
+				Meta struct {
+					ProgressToken interface{} `json:"progressToken"`
+				} `json:"_meta"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == nil {
+			continue
+		}
+		if token := req.Params.Meta.ProgressToken; token != nil {
+			for i, chunk := range []string{"Hello, ", "world"} {
+				note, _ := json.Marshal(map[string]interface{}{
+					"jsonrpc": "2.0",
+					"method":  "notifications/progress",
+					"params": map[string]interface{}{
+						"progressToken": token,
+						"progress":      i + 1,
+						"total":         2,
+						"content":       []interface{}{map[string]interface{}{"type": "text", "text": chunk}},
+					},
+				})
+				_, _ = fmt.Fprintf(out, "%s\n", note)
+			}
+		}
+		resp, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": "Hello, world"}}},
+		})
+		_, _ = fmt.Fprintf(out, "%s\n", resp)
+	}
+}
+
+func TestStdioClient_StreamsToolProgress(t *testing.T) {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	defer func() { _ = clientOut.Close() }()
+	go streamingServer(t, serverIn, serverOut)
+
+	client := &StdioClient{
+		config:          ServerConfig{ID: "test"},
+		stdin:           clientOut,
+		scanner:         bufio.NewScanner(clientIn),
+		pendingRequests: make(map[interface{}]chan *JSONRPCResponse),
+		readerDone:      make(chan error, 1),
+	}
+	go client.readResponses()
+
+	var updates []mcpserver.ToolProgress
+	ctx := mcpserver.WithToolProgress(context.Background(), func(p mcpserver.ToolProgress) {
+		updates = append(updates, p)
+	})
+	result, err := client.CallTool(ctx, "greet", nil)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result["content"] == nil {
+		t.Errorf("result = %v", result)
+	}
+
+	// Notifications arrive before the response on the same stream
+	if len(updates) != 2 {
+		t.Fatalf("got %d progress updates, want 2", len(updates))
+	}
+	if updates[1].Progress != 2 || updates[1].Total != 2 {
+		t.Errorf("last update = %+v", updates[1])
+	}
+	if text := updates[0].Text() + updates[1].Text(); text != "Hello, world" {
+		t.Errorf("streamed text = %q", text)
+	}
+
+	// Without a handler no progress is requested
+	updates = nil
+	if _, err := client.CallTool(context.Background(), "greet", nil); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if len(updates) != 0 {
+		t.Errorf("got %d updates without a handler", len(updates))
+	}
+}
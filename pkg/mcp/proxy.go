@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Message directions in a traffic recording
+const (
+	FromClient = "client" // Sent by GoFlow to the server
+	FromServer = "server" // Sent by the server to GoFlow
+)
+
+// maxProxyLineSize bounds a single JSON-RPC message relayed by the proxy
+const maxProxyLineSize = 16 * 1024 * 1024
+
+// RecordedMessage is one line of a traffic recording. Recordings are
+// newline-delimited JSON, one RecordedMessage per relayed message.
+type RecordedMessage struct {
+	Seq     int             `json:"seq"`
+	Time    time.Time       `json:"time"`
+	From    string          `json:"from"`              // FromClient or FromServer
+	Message json.RawMessage `json:"message,omitempty"` // The JSON-RPC message
+	Raw     string          `json:"raw,omitempty"`     // Lines that were not valid JSON
+}
+
+// Recorder appends relayed messages to a recording. It is safe for
+// concurrent use by both relay directions.
+type Recorder struct {
+	mu  sync.Mutex
+	w   io.Writer
+	seq int
+}
+
+// NewRecorder creates a recorder writing to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Record appends one message line
+func (r *Recorder) Record(from string, line []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	msg := RecordedMessage{
+		Seq:  r.seq,
+		Time: time.Now().UTC(),
+		From: from,
+	}
+	if json.Valid(line) {
+		msg.Message = append(json.RawMessage(nil), line...)
+	} else {
+		msg.Raw = string(line)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode recorded message: %w", err)
+	}
+	if _, err := r.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// Relay copies newline-delimited messages from the client to the server and
+// back, recording each one. It returns once the server closes its output.
+// When the client closes its input, serverIn is closed so the server can
+// shut down.
+func (r *Recorder) Relay(clientIn io.Reader, clientOut io.Writer, serverIn io.WriteCloser, serverOut io.Reader) error {
+	go func() {
+		// Write failures here mean the server is gone, which the server
+		// direction below reports by reaching the end of its output
+		_ = r.copyLines(FromClient, clientIn, serverIn)
+		_ = serverIn.Close()
+	}()
+
+	return r.copyLines(FromServer, serverOut, clientOut)
+}
+
+// copyLines relays and records lines from src to dst until src ends
+func (r *Recorder) copyLines(from string, src io.Reader, dst io.Writer) error {
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 64*1024), maxProxyLineSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := r.Record(from, line); err != nil {
+			return err
+		}
+		// Copy: line aliases the scanner's buffer
+		out := make([]byte, 0, len(line)+1)
+		out = append(append(out, line...), '\n')
+		if _, err := dst.Write(out); err != nil {
+			return fmt.Errorf("failed to relay %s message: %w", from, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s messages: %w", from, err)
+	}
+	return nil
+}
+
+// RecordServer starts command as a stdio MCP server and relays the traffic
+// between it and the client on clientIn/clientOut, recording every message
+// to recording. The server's stderr is passed through to this process's
+// stderr. It blocks until the server exits.
+func RecordServer(ctx context.Context, config ServerConfig, clientIn io.Reader, clientOut io.Writer, recording io.Writer) error {
+	if config.Command == "" {
+		return fmt.Errorf("server command cannot be empty")
+	}
+
+	cmd := exec.CommandContext(ctx, config.Command, config.Args...)
+	cmd.Stderr = os.Stderr
+	if len(config.Env) > 0 {
+		cmd.Env = os.Environ()
+		for key, value := range config.Env {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+
+	serverIn, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	serverOut, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+
+	relayErr := NewRecorder(recording).Relay(clientIn, clientOut, serverIn, serverOut)
+	waitErr := cmd.Wait()
+	if relayErr != nil {
+		return relayErr
+	}
+	if waitErr != nil {
+		return fmt.Errorf("server exited: %w", waitErr)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeEchoServer answers every request line with a response carrying the
+// request's method and ID, until its input is closed.
+func fakeEchoServer(t *testing.T, in io.Reader, out io.WriteCloser) {
+	t.Helper()
+	go func() {
+		defer func() { _ = out.Close() }()
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			var req JSONRPCRequest
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == nil {
+				continue
+			}
+			result, _ := json.Marshal(map[string]interface{}{"method": req.Method, "params": req.Params})
+			resp, _ := json.Marshal(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+			_, _ = out.Write(append(resp, '\n'))
+		}
+	}()
+}
+
+func recordTraffic(t *testing.T, requests string) (string, string) {
+	t.Helper()
+
+	serverInR, serverInW := io.Pipe()
+	serverOutR, serverOutW := io.Pipe()
+	fakeEchoServer(t, serverInR, serverOutW)
+
+	var clientOut, recording bytes.Buffer
+	recorder := NewRecorder(&recording)
+	if err := recorder.Relay(strings.NewReader(requests), &clientOut, serverInW, serverOutR); err != nil {
+		t.Fatalf("Relay() error = %v", err)
+	}
+	return clientOut.String(), recording.String()
+}
+
+func TestRecorder_Relay(t *testing.T) {
+	requests := `{"jsonrpc":"2.0","id":"1","method":"initialize","params":{"clientInfo":{"name":"goflow"}}}
+{"jsonrpc":"2.0","method":"notifications/initialized"}
+{"jsonrpc":"2.0","id":"2","method":"tools/call","params":{"name":"echo","arguments":{"message":"hi"}}}
+`
+	clientOut, recording := recordTraffic(t, requests)
+
+	if got := strings.Count(clientOut, "\n"); got != 2 {
+		t.Errorf("client received %d responses, want 2:\n%s", got, clientOut)
+	}
+
+	messages, err := LoadRecording(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+	if len(messages) != 5 {
+		t.Fatalf("recorded %d messages, want 5 (3 client, 2 server)", len(messages))
+	}
+
+	var fromClient, fromServer int
+	for i, msg := range messages {
+		if msg.Seq != i+1 {
+			t.Errorf("message %d has seq %d", i, msg.Seq)
+		}
+		switch msg.From {
+		case FromClient:
+			fromClient++
+		case FromServer:
+			fromServer++
+		}
+		if msg.Time.IsZero() || len(msg.Message) == 0 {
+			t.Errorf("message %d is missing time or content: %+v", i, msg)
+		}
+	}
+	if fromClient != 3 || fromServer != 2 {
+		t.Errorf("recorded %d client and %d server messages, want 3 and 2", fromClient, fromServer)
+	}
+}
+
+func TestRecorder_RecordsInvalidLinesRaw(t *testing.T) {
+	var recording bytes.Buffer
+	recorder := NewRecorder(&recording)
+	if err := recorder.Record(FromServer, []byte("server starting...")); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	messages, err := LoadRecording(&recording)
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+	if len(messages) != 1 || messages[0].Raw != "server starting..." || len(messages[0].Message) != 0 {
+		t.Errorf("messages = %+v, want one raw line", messages)
+	}
+}
+
+func TestReplayer_Respond(t *testing.T) {
+	requests := `{"jsonrpc":"2.0","id":"1","method":"initialize","params":{"clientInfo":{"name":"goflow","version":"0.1.0"}}}
+{"jsonrpc":"2.0","id":"2","method":"tools/call","params":{"name":"echo","arguments":{"message":"first","count":1}}}
+{"jsonrpc":"2.0","id":"3","method":"tools/call","params":{"name":"echo","arguments":{"message":"second"}}}
+{"jsonrpc":"2.0","id":"4","method":"ping"}
+{"jsonrpc":"2.0","id":"5","method":"ping","params":{}}
+`
+	_, recording := recordTraffic(t, requests)
+	messages, err := LoadRecording(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+	replayer, err := NewReplayer(messages)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		request    string
+		wantID     interface{}
+		wantResult string // substring of the result; empty means an error is expected
+	}{
+		{
+			name:       "initialize matches on method only",
+			request:    `{"jsonrpc":"2.0","id":7,"method":"initialize","params":{"clientInfo":{"name":"other","version":"9.9"}}}`,
+			wantID:     float64(7),
+			wantResult: `"method":"initialize"`,
+		},
+		{
+			name:       "parameters match regardless of key order",
+			request:    `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"arguments":{"count":1,"message":"first"},"name":"echo"}}`,
+			wantID:     "a",
+			wantResult: `"message":"first"`,
+		},
+		{
+			name:       "different parameters get their own response",
+			request:    `{"jsonrpc":"2.0","id":"b","method":"tools/call","params":{"name":"echo","arguments":{"message":"second"}}}`,
+			wantID:     "b",
+			wantResult: `"message":"second"`,
+		},
+		{
+			name:       "repeated request reuses the last response",
+			request:    `{"jsonrpc":"2.0","id":"c","method":"tools/call","params":{"name":"echo","arguments":{"message":"second"}}}`,
+			wantID:     "c",
+			wantResult: `"message":"second"`,
+		},
+		{
+			name:    "unrecorded request is an error",
+			request: `{"jsonrpc":"2.0","id":"d","method":"tools/call","params":{"name":"echo","arguments":{"message":"third"}}}`,
+			wantID:  "d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := replayer.Respond([]byte(tt.request))
+			if err != nil {
+				t.Fatalf("Respond() error = %v", err)
+			}
+			var resp JSONRPCResponse
+			if err := json.Unmarshal(data, &resp); err != nil {
+				t.Fatalf("invalid response %s: %v", data, err)
+			}
+			if resp.ID != tt.wantID {
+				t.Errorf("response id = %v, want %v", resp.ID, tt.wantID)
+			}
+			if tt.wantResult == "" {
+				if resp.Error == nil || resp.Error.Code != replayNoMatchCode {
+					t.Errorf("response = %s, want no-match error", data)
+				}
+				return
+			}
+			if resp.Error != nil || !strings.Contains(string(resp.Result), tt.wantResult) {
+				t.Errorf("response = %s, want result containing %s", data, tt.wantResult)
+			}
+		})
+	}
+
+	// Notifications get no response
+	if data, err := replayer.Respond([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)); err != nil || data != nil {
+		t.Errorf("Respond(notification) = %s, %v; want no response", data, err)
+	}
+}
+
+func TestReplayer_SequentialResponses(t *testing.T) {
+	// The same request answered differently over time replays in order
+	recording := `{"seq":1,"time":"2026-01-01T00:00:00Z","from":"client","message":{"jsonrpc":"2.0","id":"1","method":"tools/call","params":{"name":"counter"}}}
+{"seq":2,"time":"2026-01-01T00:00:00Z","from":"server","message":{"jsonrpc":"2.0","id":"1","result":{"value":1}}}
+{"seq":3,"time":"2026-01-01T00:00:01Z","from":"client","message":{"jsonrpc":"2.0","id":"2","method":"tools/call","params":{"name":"counter"}}}
+{"seq":4,"time":"2026-01-01T00:00:01Z","from":"server","message":{"jsonrpc":"2.0","id":"2","result":{"value":2}}}
+`
+	messages, err := LoadRecording(strings.NewReader(recording))
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+	replayer, err := NewReplayer(messages)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+
+	in := strings.Repeat(`{"jsonrpc":"2.0","id":"x","method":"tools/call","params":{"name":"counter"}}`+"\n", 3)
+	var out bytes.Buffer
+	if err := replayer.Serve(strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	want := []string{`"value":1`, `"value":2`, `"value":2`}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d responses, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, want[i]) || !strings.Contains(line, `"id":"x"`) {
+			t.Errorf("response %d = %s, want %s with id x", i, line, want[i])
+		}
+	}
+}
+
+func TestLoadRecording_Invalid(t *testing.T) {
+	if _, err := LoadRecording(strings.NewReader("not json\n")); err == nil {
+		t.Error("LoadRecording() should reject invalid lines")
+	}
+}
+
+func TestRecordServer_ReplayMatchesLiveServer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs the test server")
+	}
+
+	serverPath, err := filepath.Abs("../../cmd/testserver/main.go")
+	if err != nil {
+		t.Fatalf("Failed to get test server path: %v", err)
+	}
+
+	requests := `{"jsonrpc":"2.0","id":"1","method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"0.1.0"}}}
+{"jsonrpc":"2.0","id":"2","method":"tools/list","params":{}}
+{"jsonrpc":"2.0","id":"3","method":"tools/call","params":{"name":"echo","arguments":{"message":"recorded"}}}
+`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var live, recording bytes.Buffer
+	config := ServerConfig{Command: "go", Args: []string{"run", serverPath}}
+	if err := RecordServer(ctx, config, strings.NewReader(requests), &live, &recording); err != nil {
+		t.Fatalf("RecordServer() error = %v", err)
+	}
+	if strings.Count(live.String(), "\n") != 3 {
+		t.Fatalf("live server sent unexpected output:\n%s", live.String())
+	}
+
+	messages, err := LoadRecording(&recording)
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+	replayer, err := NewReplayer(messages)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+
+	var replayed bytes.Buffer
+	if err := replayer.Serve(strings.NewReader(requests), &replayed); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	liveLines := strings.Split(strings.TrimSpace(live.String()), "\n")
+	replayLines := strings.Split(strings.TrimSpace(replayed.String()), "\n")
+	if len(replayLines) != len(liveLines) {
+		t.Fatalf("replayed %d responses, live server sent %d", len(replayLines), len(liveLines))
+	}
+	for i := range liveLines {
+		var want, got interface{}
+		_ = json.Unmarshal([]byte(liveLines[i]), &want)
+		_ = json.Unmarshal([]byte(replayLines[i]), &got)
+		wantJSON, _ := json.Marshal(want)
+		gotJSON, _ := json.Marshal(got)
+		if !bytes.Equal(wantJSON, gotJSON) {
+			t.Errorf("response %d differs:\nlive:   %s\nreplay: %s", i, wantJSON, gotJSON)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// replayNoMatchCode is the JSON-RPC error code returned for requests that
+// have no recorded response
+const replayNoMatchCode = -32001
+
+// LoadRecording reads a traffic recording written by a Recorder.
+func LoadRecording(r io.Reader) ([]RecordedMessage, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 2*maxProxyLineSize)
+
+	var messages []RecordedMessage
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var msg RecordedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return nil, fmt.Errorf("invalid recording line %d: %w", line, err)
+		}
+		messages = append(messages, msg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return messages, nil
+}
+
+// LoadRecordingFile reads a traffic recording from a file.
+func LoadRecordingFile(path string) ([]RecordedMessage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return LoadRecording(file)
+}
+
+// Replayer answers JSON-RPC requests from a recording instead of a live
+// server.
+//
+// Requests are matched on method and parameters (compared as JSON values, so
+// key order and whitespace do not matter); "initialize" is matched on method
+// alone because client info differs between runs. Identical requests get
+// their recorded responses in order, and the last one is repeated once they
+// are used up. Response IDs are rewritten to the incoming request's ID.
+// Requests with no recorded response get a JSON-RPC error.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]json.RawMessage
+	served    map[string]int
+}
+
+// NewReplayer indexes the request/response pairs of a recording.
+func NewReplayer(messages []RecordedMessage) (*Replayer, error) {
+	p := &Replayer{
+		responses: make(map[string][]json.RawMessage),
+		served:    make(map[string]int),
+	}
+
+	// Pair each response with the outstanding request that has its ID
+	pending := make(map[string]string) // request ID -> match key
+	for _, msg := range messages {
+		if len(msg.Message) == 0 {
+			continue
+		}
+		var envelope struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(msg.Message, &envelope); err != nil {
+			return nil, fmt.Errorf("invalid recorded message %d: %w", msg.Seq, err)
+		}
+		if len(envelope.ID) == 0 || string(envelope.ID) == "null" {
+			continue // Notification
+		}
+
+		switch {
+		case msg.From == FromClient && envelope.Method != "":
+			key, err := replayKey(envelope.Method, envelope.Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recorded message %d: %w", msg.Seq, err)
+			}
+			pending[string(envelope.ID)] = key
+		case msg.From == FromServer && envelope.Method == "":
+			key, ok := pending[string(envelope.ID)]
+			if !ok {
+				continue // Response to a request that was not recorded
+			}
+			delete(pending, string(envelope.ID))
+			p.responses[key] = append(p.responses[key], msg.Message)
+		}
+	}
+
+	return p, nil
+}
+
+// Respond returns the recorded response to a request line. Notifications
+// need no response and return nil.
+func (p *Replayer) Respond(request []byte) ([]byte, error) {
+	var req JSONRPCRequest
+	if err := json.Unmarshal(request, &req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.ID == nil {
+		return nil, nil
+	}
+
+	key, err := replayKey(req.Method, req.Params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	p.mu.Lock()
+	recorded := p.responses[key]
+	var response json.RawMessage
+	if len(recorded) > 0 {
+		index := min(p.served[key], len(recorded)-1)
+		p.served[key]++
+		response = recorded[index]
+	}
+	p.mu.Unlock()
+
+	if response == nil {
+		return json.Marshal(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &JSONRPCError{
+				Code:    replayNoMatchCode,
+				Message: "No recorded response",
+				Data:    req.Method,
+			},
+		})
+	}
+
+	// Answer with the caller's ID
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(response, &fields); err != nil {
+		return nil, fmt.Errorf("invalid recorded response: %w", err)
+	}
+	id, err := json.Marshal(req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request id: %w", err)
+	}
+	fields["id"] = id
+	return json.Marshal(fields)
+}
+
+// Serve answers newline-delimited requests from in on out until in ends.
+func (p *Replayer) Serve(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxProxyLineSize)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		response, err := p.Respond(scanner.Bytes())
+		if err != nil {
+			response, _ = json.Marshal(JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error:   &JSONRPCError{Code: -32700, Message: "Parse error", Data: err.Error()},
+			})
+		}
+		if response == nil {
+			continue
+		}
+		if _, err := out.Write(append(response, '\n')); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read requests: %w", err)
+	}
+	return nil
+}
+
+// replayKey identifies a request by method and normalized parameters
+func replayKey(method string, params json.RawMessage) (string, error) {
+	if method == "initialize" || len(params) == 0 || string(params) == "null" {
+		return method, nil
+	}
+
+	// Round-trip through a generic value: encoding/json sorts map keys
+	var value interface{}
+	if err := json.Unmarshal(params, &value); err != nil {
+		return "", err
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	if string(normalized) == "{}" {
+		return method, nil
+	}
+	return method + " " + string(normalized), nil
+}
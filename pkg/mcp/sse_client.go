@@ -14,12 +14,17 @@ import (
 	"github.com/dshills/goflow/pkg/mcpserver"
 )
 
+// cancelNotificationTimeout bounds the POST telling a server a request was
+// cancelled
+const cancelNotificationTimeout = 2 * time.Second
+
 // SSEClient implements the Client interface using Server-Sent Events transport
 // SSE is a unidirectional protocol where the server pushes events to the client.
 // For MCP, we use SSE for server->client messages and POST requests for client->server.
 type SSEClient struct {
 	url             string
 	headers         map[string]string
+	auth            AuthProvider
 	httpClient      *http.Client
 	sseConn         *http.Response
 	mu              sync.Mutex
@@ -27,6 +32,7 @@ type SSEClient struct {
 	connected       bool
 	pendingRequests map[interface{}]chan *JSONRPCResponse
 	readerDone      chan error
+	progress        progressRouter
 }
 
 // SSEConfig holds configuration for SSE transport
@@ -34,6 +40,7 @@ type SSEConfig struct {
 	URL     string
 	Headers map[string]string
 	Timeout time.Duration
+	Auth    AuthProvider // Optional authentication (see NewAuthProvider)
 }
 
 // NewSSEClient creates a new SSE-based MCP client
@@ -50,6 +57,7 @@ func NewSSEClient(config SSEConfig) (*SSEClient, error) {
 	return &SSEClient{
 		url:     config.URL,
 		headers: config.Headers,
+		auth:    config.Auth,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
@@ -67,25 +75,24 @@ func (c *SSEClient) Connect(ctx context.Context) error {
 		return fmt.Errorf("already connected")
 	}
 
-	// Create SSE connection request
-	req, err := http.NewRequestWithContext(ctx, "GET", c.url, nil)
-	if err != nil {
-		c.mu.Unlock()
-		return fmt.Errorf("failed to create SSE request: %w", err)
-	}
-
-	// Set SSE headers
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Connection", "keep-alive")
+	// Establish SSE connection
+	resp, err := doWithAuth(ctx, c.httpClient, c.auth, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSE request: %w", err)
+		}
 
-	// Add custom headers
-	for key, value := range c.headers {
-		req.Header.Set(key, value)
-	}
+		// Set SSE headers
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("Cache-Control", "no-cache")
+		req.Header.Set("Connection", "keep-alive")
 
-	// Establish SSE connection
-	resp, err := c.httpClient.Do(req)
+		// Add custom headers
+		for key, value := range c.headers {
+			req.Header.Set(key, value)
+		}
+		return req, nil
+	})
 	if err != nil {
 		c.mu.Unlock()
 		return fmt.Errorf("failed to connect to SSE endpoint: %w", err)
@@ -180,20 +187,10 @@ func (c *SSEClient) sendRequest(ctx context.Context, method string, params inter
 	}
 
 	// Send via POST request - note we don't wait for response here, it comes via SSE
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.url, strings.NewReader(string(reqJSON)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create POST request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	for key, value := range c.headers {
-		httpReq.Header.Set(key, value)
-	}
-
 	// Use a goroutine to send the POST so we don't block waiting for SSE response
 	sendDone := make(chan error, 1)
 	go func() {
-		httpResp, err := c.httpClient.Do(httpReq)
+		httpResp, err := c.post(ctx, reqJSON)
 		if err != nil {
 			sendDone <- fmt.Errorf("failed to send POST request: %w", err)
 			return
@@ -222,6 +219,7 @@ func (c *SSEClient) sendRequest(ctx context.Context, method string, params inter
 		// Send succeeded, now wait for SSE response
 		select {
 		case <-ctx.Done():
+			c.notifyCancelled(ctx, method, req.ID)
 			return nil, ctx.Err()
 		case resp, ok := <-respChan:
 			if !ok {
@@ -239,24 +237,36 @@ func (c *SSEClient) sendRequest(ctx context.Context, method string, params inter
 	}
 }
 
-// sendNotification sends a JSON-RPC notification (no response expected)
-func (c *SSEClient) sendNotification(ctx context.Context, notification interface{}) error {
-	notifJSON, err := json.Marshal(notification)
+// notifyCancelled tells the server to stop working on an abandoned request.
+// Servers that do not support cancellation ignore the notification, so
+// failures are not reported. Initialization cannot be cancelled.
+func (c *SSEClient) notifyCancelled(ctx context.Context, method string, requestID interface{}) {
+	if method == "initialize" {
+		return
+	}
+	notifJSON, err := cancelledNotification(requestID, cancellationReason(ctx))
 	if err != nil {
-		return fmt.Errorf("failed to marshal notification: %w", err)
+		return
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.url, strings.NewReader(string(notifJSON)))
+	// ctx is done; give the notification a moment of its own
+	notifyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelNotificationTimeout)
+	defer cancel()
+	httpResp, err := c.post(notifyCtx, notifJSON)
 	if err != nil {
-		return fmt.Errorf("failed to create POST request: %w", err)
+		return
 	}
+	_ = httpResp.Body.Close()
+}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	for key, value := range c.headers {
-		httpReq.Header.Set(key, value)
+// sendNotification sends a JSON-RPC notification (no response expected)
+func (c *SSEClient) sendNotification(ctx context.Context, notification interface{}) error {
+	notifJSON, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 
-	httpResp, err := c.httpClient.Do(httpReq)
+	httpResp, err := c.post(ctx, notifJSON)
 	if err != nil {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
@@ -274,6 +284,22 @@ func (c *SSEClient) sendNotification(ctx context.Context, notification interface
 	return nil
 }
 
+// post sends a JSON payload to the endpoint with custom headers and authentication
+func (c *SSEClient) post(ctx context.Context, payload []byte) (*http.Response, error) {
+	return doWithAuth(ctx, c.httpClient, c.auth, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.url, strings.NewReader(string(payload)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create POST request: %w", err)
+		}
+
+		httpReq.Header.Set("Content-Type", "application/json")
+		for key, value := range c.headers {
+			httpReq.Header.Set(key, value)
+		}
+		return httpReq, nil
+	})
+}
+
 // readSSEEvents reads Server-Sent Events from the connection
 func (c *SSEClient) readSSEEvents() {
 	defer func() {
@@ -324,8 +350,13 @@ func (c *SSEClient) readSSEEvents() {
 	}
 }
 
-// processSSEEvent processes a complete SSE event containing JSON-RPC response
+// processSSEEvent routes a complete SSE event: a progress notification to
+// its tool call, or a JSON-RPC response to its request
 func (c *SSEClient) processSSEEvent(data string) {
+	if c.progress.dispatch([]byte(data)) {
+		return
+	}
+
 	var resp JSONRPCResponse
 	if err := json.Unmarshal([]byte(data), &resp); err != nil {
 		// Invalid JSON, skip
@@ -429,6 +460,7 @@ func (c *SSEClient) CallTool(ctx context.Context, toolName string, params map[st
 		"name":      toolName,
 		"arguments": params,
 	}
+	defer c.progress.track(ctx, callParams)()
 
 	resp, err := c.sendRequest(ctx, "tools/call", callParams)
 	if err != nil {
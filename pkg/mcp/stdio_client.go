@@ -26,6 +26,7 @@ type StdioClient struct {
 	closed          bool
 	pendingRequests map[interface{}]chan *JSONRPCResponse
 	readerDone      chan error
+	progress        progressRouter
 }
 
 // NewStdioClient creates a new stdio-based MCP client
@@ -323,6 +324,7 @@ func (c *StdioClient) sendRequest(ctx context.Context, method string, params int
 	// Wait for response with timeout
 	select {
 	case <-ctx.Done():
+		c.notifyCancelled(ctx, method, req.ID)
 		return nil, errors.NewOperationalErrorWithAttrs(
 			"waiting for response",
 			"",
@@ -351,7 +353,22 @@ func (c *StdioClient) sendRequest(ctx context.Context, method string, params int
 	}
 }
 
-// readResponses reads JSON-RPC responses from stdout
+// notifyCancelled tells the server to stop working on an abandoned request.
+// Servers that do not support cancellation ignore the notification, so
+// failures are not reported. Initialization cannot be cancelled.
+func (c *StdioClient) notifyCancelled(ctx context.Context, method string, requestID interface{}) {
+	if method == "initialize" {
+		return
+	}
+	notifJSON, err := cancelledNotification(requestID, cancellationReason(ctx))
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(c.stdin, "%s\n", notifJSON)
+}
+
+// readResponses reads JSON-RPC responses and progress notifications from
+// stdout
 func (c *StdioClient) readResponses() {
 	defer func() {
 		c.mu.Lock()
@@ -368,6 +385,9 @@ func (c *StdioClient) readResponses() {
 		if len(line) == 0 {
 			continue
 		}
+		if c.progress.dispatch(line) {
+			continue
+		}
 
 		var resp JSONRPCResponse
 		if err := json.Unmarshal(line, &resp); err != nil {
@@ -536,6 +556,7 @@ func (c *StdioClient) CallTool(ctx context.Context, toolName string, params map[
 		"name":      toolName,
 		"arguments": params,
 	}
+	defer c.progress.track(ctx, callParams)()
 
 	resp, err := c.sendRequest(ctx, "tools/call", callParams)
 	if err != nil {
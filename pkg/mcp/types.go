@@ -36,4 +36,12 @@ type ServerConfig struct {
 	Transport string            // "stdio", "sse", or "http"
 	URL       string            // For SSE and HTTP transports
 	Headers   map[string]string // For SSE and HTTP transports
+
+	// Auth configures authentication for SSE and HTTP transports;
+	// secret references are resolved through Secrets
+	Auth    *mcpserver.AuthConfig
+	Secrets SecretResolver
+
+	// Limits bounds in-flight requests and request rate (nil = unlimited)
+	Limits *mcpserver.RequestLimits
 }
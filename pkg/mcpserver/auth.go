@@ -0,0 +1,112 @@
+package mcpserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AuthType identifies the authentication scheme used by HTTP-based transports
+type AuthType string
+
+const (
+	// AuthNone sends no authentication
+	AuthNone AuthType = ""
+	// AuthBearer sends a static token in the Authorization header
+	AuthBearer AuthType = "bearer"
+	// AuthAPIKey sends a static key in a configurable header
+	AuthAPIKey AuthType = "api_key"
+	// AuthOAuth2ClientCredentials obtains and refreshes tokens using the
+	// OAuth2 client-credentials grant
+	AuthOAuth2ClientCredentials AuthType = "oauth2_client_credentials"
+)
+
+// DefaultAPIKeyHeader is the header used for API key auth when none is configured
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// String returns the string representation of an AuthType
+func (at AuthType) String() string {
+	return string(at)
+}
+
+// IsValid checks if the AuthType is valid
+func (at AuthType) IsValid() bool {
+	switch at {
+	case AuthNone, AuthBearer, AuthAPIKey, AuthOAuth2ClientCredentials:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuthConfig configures authentication for SSE and HTTP transports.
+//
+// Secret values are never stored in the config itself. Fields ending in Ref
+// name an entry in the credential store (see storage.CredentialStore) that is
+// resolved when the client connects.
+type AuthConfig struct {
+	Type AuthType
+
+	// TokenRef references the bearer token (AuthBearer)
+	TokenRef string
+
+	// HeaderName is the header carrying the key (AuthAPIKey, default X-API-Key)
+	HeaderName string
+	// KeyRef references the API key (AuthAPIKey)
+	KeyRef string
+
+	// TokenURL is the OAuth2 token endpoint (AuthOAuth2ClientCredentials)
+	TokenURL string
+	// ClientID is the OAuth2 client identifier
+	ClientID string
+	// ClientSecretRef references the OAuth2 client secret
+	ClientSecretRef string
+	// Scopes are requested when obtaining a token
+	Scopes []string
+}
+
+// Validate checks if the auth configuration is complete for its type
+func (a *AuthConfig) Validate() error {
+	if a == nil {
+		return nil
+	}
+	if !a.Type.IsValid() {
+		return NewValidationError(fmt.Sprintf("invalid auth type: %s", a.Type))
+	}
+
+	switch a.Type {
+	case AuthBearer:
+		if a.TokenRef == "" {
+			return NewValidationError("bearer auth: token reference cannot be empty")
+		}
+	case AuthAPIKey:
+		if a.KeyRef == "" {
+			return NewValidationError("api_key auth: key reference cannot be empty")
+		}
+		if strings.ContainsAny(a.HeaderName, " :\r\n") {
+			return NewValidationError(fmt.Sprintf("api_key auth: invalid header name: %q", a.HeaderName))
+		}
+	case AuthOAuth2ClientCredentials:
+		if a.TokenURL == "" {
+			return NewValidationError("oauth2 auth: token URL cannot be empty")
+		}
+		if !strings.HasPrefix(a.TokenURL, "https://") && !strings.HasPrefix(a.TokenURL, "http://") {
+			return NewValidationError("oauth2 auth: token URL must start with http:// or https://")
+		}
+		if a.ClientID == "" {
+			return NewValidationError("oauth2 auth: client ID cannot be empty")
+		}
+		if a.ClientSecretRef == "" {
+			return NewValidationError("oauth2 auth: client secret reference cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// APIKeyHeader returns the configured API key header or the default
+func (a *AuthConfig) APIKeyHeader() string {
+	if a.HeaderName == "" {
+		return DefaultAPIKeyHeader
+	}
+	return a.HeaderName
+}
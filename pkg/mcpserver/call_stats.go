@@ -0,0 +1,133 @@
+package mcpserver
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultCallWindow is how many recent tool calls a server keeps for its
+// latency percentiles and recent call samples
+const DefaultCallWindow = 100
+
+// CallSample is one tool call made to a server
+type CallSample struct {
+	Tool     string
+	Time     time.Time // When the call started
+	Duration time.Duration
+	Error    string // Why the call failed; empty on success
+}
+
+// CallStats counts a server's tool calls and keeps the most recent ones, so
+// flaky or slow servers stand out. It is safe for concurrent use; a nil
+// *CallStats records nothing.
+type CallStats struct {
+	mu        sync.Mutex
+	successes uint64
+	errors    uint64
+	samples   []CallSample // Ring buffer of the most recent calls
+	next      int          // Where the next sample goes once samples is full
+	window    int
+}
+
+// NewCallStats creates call statistics that keep the last window calls;
+// window <= 0 uses DefaultCallWindow
+func NewCallStats(window int) *CallStats {
+	if window <= 0 {
+		window = DefaultCallWindow
+	}
+	return &CallStats{window: window}
+}
+
+// Record adds a call to tool that started at started and failed with err,
+// if not nil
+func (c *CallStats) Record(tool string, started time.Time, err error) {
+	if c == nil {
+		return
+	}
+	sample := CallSample{Tool: tool, Time: started, Duration: time.Since(started)}
+	if err != nil {
+		sample.Error = err.Error()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.errors++
+	} else {
+		c.successes++
+	}
+	if len(c.samples) < c.window {
+		c.samples = append(c.samples, sample)
+		return
+	}
+	c.samples[c.next] = sample
+	c.next = (c.next + 1) % c.window
+}
+
+// CallStatsSnapshot is a point-in-time copy of a server's call statistics.
+// Percentiles cover the recent calls only.
+type CallStatsSnapshot struct {
+	Successes uint64
+	Errors    uint64
+	P50       time.Duration
+	P90       time.Duration
+	P99       time.Duration
+	Recent    []CallSample // Newest first
+}
+
+// Calls returns the total number of calls
+func (s CallStatsSnapshot) Calls() uint64 {
+	return s.Successes + s.Errors
+}
+
+// ErrorRate returns the fraction of calls that failed, 0 with no calls
+func (s CallStatsSnapshot) ErrorRate() float64 {
+	if s.Calls() == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls())
+}
+
+// Snapshot returns the current statistics
+func (c *CallStats) Snapshot() CallStatsSnapshot {
+	if c == nil {
+		return CallStatsSnapshot{}
+	}
+
+	c.mu.Lock()
+	snapshot := CallStatsSnapshot{
+		Successes: c.successes,
+		Errors:    c.errors,
+		Recent:    make([]CallSample, 0, len(c.samples)),
+	}
+	// The oldest sample is at next once the ring has wrapped
+	for i := len(c.samples) - 1; i >= 0; i-- {
+		snapshot.Recent = append(snapshot.Recent, c.samples[(c.next+i)%len(c.samples)])
+	}
+	c.mu.Unlock()
+
+	durations := make([]time.Duration, len(snapshot.Recent))
+	for i, sample := range snapshot.Recent {
+		durations[i] = sample.Duration
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	snapshot.P50 = percentile(durations, 0.50)
+	snapshot.P90 = percentile(durations, 0.90)
+	snapshot.P99 = percentile(durations, 0.99)
+	return snapshot
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
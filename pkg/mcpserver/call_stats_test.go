@@ -0,0 +1,97 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallStats(t *testing.T) {
+	stats := NewCallStats(10)
+	now := time.Now()
+	for i := 1; i <= 20; i++ {
+		var err error
+		if i%5 == 0 {
+			err = errors.New("boom")
+		}
+		stats.Record("tool", now.Add(-time.Duration(i)*time.Millisecond), err)
+	}
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, uint64(16), snapshot.Successes)
+	assert.Equal(t, uint64(4), snapshot.Errors)
+	assert.Equal(t, uint64(20), snapshot.Calls())
+	assert.InDelta(t, 0.2, snapshot.ErrorRate(), 0.0001)
+
+	// Only the last 10 calls are kept, newest first; they took 11-20ms
+	require.Len(t, snapshot.Recent, 10)
+	assert.Equal(t, "boom", snapshot.Recent[0].Error)
+	assert.GreaterOrEqual(t, snapshot.Recent[0].Duration, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, snapshot.P50, 15*time.Millisecond)
+	assert.Less(t, snapshot.P50, snapshot.P99)
+	assert.LessOrEqual(t, snapshot.P90, snapshot.P99)
+
+	var none *CallStats
+	none.Record("tool", now, nil)
+	assert.Zero(t, none.Snapshot().Calls())
+	assert.Zero(t, CallStatsSnapshot{}.ErrorRate())
+}
+
+// callClient fails calls to the tool "flaky"
+type callClient struct{ pingClient }
+
+func (c *callClient) CallTool(ctx context.Context, toolName string, params map[string]interface{}) (map[string]interface{}, error) {
+	if toolName == "flaky" {
+		return nil, errors.New("upstream timeout")
+	}
+	return map[string]interface{}{"ok": true}, nil
+}
+
+func TestMCPServer_RecordsCallStats(t *testing.T) {
+	registry := NewRegistry()
+	server := registerConnected(t, registry, "stats", &callClient{})
+	server.Tools = []Tool{{Name: "steady"}, {Name: "flaky"}}
+
+	_, err := server.InvokeTool("steady", nil)
+	require.NoError(t, err)
+	_, err = server.InvokeTool("flaky", nil)
+	require.Error(t, err)
+	_, err = server.InvokeTool("missing", nil)
+	require.Error(t, err)
+
+	stats := server.Stats()
+	assert.Equal(t, uint64(1), stats.Successes)
+	assert.Equal(t, uint64(1), stats.Errors, "calls that never reach the server are not counted")
+	require.Len(t, stats.Recent, 2)
+	assert.Equal(t, "flaky", stats.Recent[0].Tool)
+	assert.Contains(t, stats.Recent[0].Error, "upstream timeout")
+
+	handler := NewMetricsHandler(registry)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `goflow_mcp_calls_total{server="stats",result="success"} 1`)
+	assert.Contains(t, body, `goflow_mcp_calls_total{server="stats",result="error"} 1`)
+	assert.Contains(t, body, `goflow_mcp_call_duration_seconds{server="stats",quantile="0.99"}`)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/servers", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var metrics []ServerMetrics
+	require.NoError(t, json.NewDecoder(strings.NewReader(rec.Body.String())).Decode(&metrics))
+	require.Len(t, metrics, 1)
+	assert.Equal(t, uint64(2), metrics[0].Calls)
+	assert.InDelta(t, 0.5, metrics[0].ErrorRate, 0.0001)
+	require.Len(t, metrics[0].Recent, 2)
+	assert.Equal(t, "flaky", metrics[0].Recent[0].Tool)
+}
@@ -89,7 +89,9 @@ func (s *MCPServer) CallTool(toolName string, arguments map[string]interface{})
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		started := time.Now()
 		result, err := s.client.CallTool(ctx, toolName, arguments)
+		s.stats.Record(toolName, started, err)
 		if err != nil {
 			return s.handleToolError(toolName, arguments, err)
 		}
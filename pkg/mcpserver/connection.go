@@ -119,6 +119,7 @@ func (c *StdioTransportConfig) Validate() error {
 type SSETransportConfig struct {
 	URL     string
 	Headers map[string]string
+	Auth    *AuthConfig // Optional authentication
 }
 
 // Type returns the transport type
@@ -131,6 +132,9 @@ func (c *SSETransportConfig) Validate() error {
 	if c.URL == "" {
 		return NewValidationError("sse transport: URL cannot be empty")
 	}
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -139,6 +143,7 @@ type HTTPTransportConfig struct {
 	BaseURL string
 	Headers map[string]string
 	Timeout time.Duration
+	Auth    *AuthConfig // Optional authentication
 }
 
 // Type returns the transport type
@@ -154,6 +159,9 @@ func (c *HTTPTransportConfig) Validate() error {
 	if c.Timeout <= 0 {
 		c.Timeout = 30 * time.Second // default timeout
 	}
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
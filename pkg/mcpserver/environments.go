@@ -0,0 +1,119 @@
+package mcpserver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment maps the logical server IDs workflows use to registered
+// servers, so the same workflow runs against different backends:
+//
+//	environments:
+//	  dev:
+//	    servers:
+//	      db: db-dev
+//	  prod:
+//	    require_signed: true
+//	    servers:
+//	      db: db-prod
+type Environment struct {
+	// RequireSigned runs only workflows signed by a trusted key
+	RequireSigned bool `yaml:"require_signed,omitempty"`
+	// Servers maps a workflow server ID to a registered server ID
+	Servers map[string]string `yaml:"servers"`
+}
+
+// Environments is the environments config file (by default
+// ~/.goflow/environments.yaml). Default names the environment used when a
+// run does not choose one.
+type Environments struct {
+	Default      string                  `yaml:"default,omitempty"`
+	Environments map[string]*Environment `yaml:"environments"`
+}
+
+// LoadEnvironments reads an environments config file. A missing file has no
+// environments.
+func LoadEnvironments(path string) (*Environments, error) {
+	envs := &Environments{Environments: make(map[string]*Environment)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return envs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environments config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, envs); err != nil {
+		return nil, fmt.Errorf("failed to parse environments config %s: %w", path, err)
+	}
+	if envs.Environments == nil {
+		envs.Environments = make(map[string]*Environment)
+	}
+	for name, env := range envs.Environments {
+		if env == nil {
+			envs.Environments[name] = &Environment{Servers: make(map[string]string)}
+		} else if env.Servers == nil {
+			env.Servers = make(map[string]string)
+		}
+	}
+	if envs.Default != "" && envs.Environments[envs.Default] == nil {
+		return nil, NewValidationError(fmt.Sprintf("default environment %s is not defined in %s", envs.Default, path))
+	}
+	return envs, nil
+}
+
+// Save writes the environments config file atomically
+func (e *Environments) Save(path string) error {
+	data, err := yaml.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal environments: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Names returns the names of the environments, sorted
+func (e *Environments) Names() []string {
+	names := make([]string, 0, len(e.Environments))
+	for name := range e.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the named environment
+func (e *Environments) Get(name string) (*Environment, error) {
+	env, exists := e.Environments[name]
+	if !exists {
+		return nil, NewValidationError(fmt.Sprintf("environment not found: %s", name))
+	}
+	return env, nil
+}
+
+// Use makes the named environment the default; an empty name clears it
+func (e *Environments) Use(name string) error {
+	if name != "" {
+		if _, err := e.Get(name); err != nil {
+			return err
+		}
+	}
+	e.Default = name
+	return nil
+}
+
+// Resolve returns the registered server the environment maps a workflow
+// server ID to, or nil when the environment does not map it
+func (env *Environment) Resolve(repo ServerRepository, serverID string) (*MCPServer, error) {
+	target, mapped := env.Servers[serverID]
+	if !mapped {
+		return nil, nil
+	}
+	server, err := repo.Get(target)
+	if err != nil {
+		return nil, fmt.Errorf("server %s maps to %s: %w", serverID, target, err)
+	}
+	return server, nil
+}
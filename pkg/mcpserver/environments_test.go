@@ -0,0 +1,62 @@
+package mcpserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironments_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "environments.yaml")
+
+	envs, err := LoadEnvironments(path)
+	require.NoError(t, err)
+	assert.Empty(t, envs.Names())
+
+	envs.Environments["prod"] = &Environment{Servers: map[string]string{"db": "db-prod"}}
+	envs.Environments["dev"] = &Environment{Servers: map[string]string{"db": "db-dev"}}
+	assert.Error(t, envs.Use("staging"))
+	require.NoError(t, envs.Use("prod"))
+	require.NoError(t, envs.Save(path))
+
+	loaded, err := LoadEnvironments(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, loaded.Names())
+	assert.Equal(t, "prod", loaded.Default)
+	env, err := loaded.Get("dev")
+	require.NoError(t, err)
+	assert.Equal(t, "db-dev", env.Servers["db"])
+	_, err = loaded.Get("staging")
+	assert.Error(t, err)
+}
+
+func TestLoadEnvironments_UndefinedDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "environments.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("default: prod\nenvironments:\n  dev: {}\n"), 0600))
+
+	_, err := LoadEnvironments(path)
+	assert.ErrorContains(t, err, "default environment prod")
+}
+
+func TestEnvironment_Resolve(t *testing.T) {
+	registry := NewRegistry()
+	server, err := NewMCPServer("db-prod", "postgres-mcp", nil, TransportStdio)
+	require.NoError(t, err)
+	require.NoError(t, registry.Register(server))
+
+	env := &Environment{Servers: map[string]string{"db": "db-prod", "cache": "redis-prod"}}
+
+	resolved, err := env.Resolve(registry, "db")
+	require.NoError(t, err)
+	assert.Equal(t, "db-prod", resolved.ID)
+
+	resolved, err = env.Resolve(registry, "search")
+	require.NoError(t, err)
+	assert.Nil(t, resolved)
+
+	_, err = env.Resolve(registry, "cache")
+	assert.ErrorContains(t, err, "cache maps to redis-prod")
+}
@@ -0,0 +1,371 @@
+package mcpserver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serverFile is the layout of servers.yaml, shared with `goflow server add`
+type serverFile struct {
+	Servers map[string]*serverFileEntry `yaml:"servers"`
+}
+
+// serverFileEntry is one server in servers.yaml. For SSE and HTTP servers
+// the command is the server URL.
+type serverFileEntry struct {
+	ID            string            `yaml:"id"`
+	Name          string            `yaml:"name,omitempty"`
+	Description   string            `yaml:"description,omitempty"`
+	Command       string            `yaml:"command"`
+	Args          []string          `yaml:"args,omitempty"`
+	Transport     string            `yaml:"transport,omitempty"`
+	Env           map[string]string `yaml:"env,omitempty"`
+	Headers       map[string]string `yaml:"headers,omitempty"`
+	Tags          []string          `yaml:"tags,omitempty"`
+	CredentialRef string            `yaml:"credential_ref,omitempty"`
+
+	ToolCategories map[string][]string `yaml:"tool_categories,omitempty"`
+}
+
+// toolCategories are the categories of effect tool_categories may list
+var toolCategories = map[string]bool{"read": true, "write": true, "network": true, "exec": true}
+
+// validateToolCategories checks that tool_categories only lists known
+// categories, and at least one for each tool
+func validateToolCategories(declared map[string][]string) error {
+	for tool, categories := range declared {
+		if len(categories) == 0 {
+			return NewValidationError(fmt.Sprintf("tool_categories of %s is empty (use [read] for a tool that only reads)", tool))
+		}
+		for _, category := range categories {
+			if !toolCategories[category] {
+				return NewValidationError(fmt.Sprintf("tool_categories of %s: unknown category %q (expected read, write, network, or exec)", tool, category))
+			}
+		}
+	}
+	return nil
+}
+
+// FileRepository is a ServerRepository that persists registered servers to
+// a YAML config file (by default ~/.goflow/servers.yaml, the file `goflow
+// server` manages), so they survive restarts. Every change rewrites the file
+// atomically with owner-only permissions.
+//
+// Server environments and request headers may hold secrets, so a file that
+// other users can read is refused when it has any; fix it with chmod 600.
+type FileRepository struct {
+	path string
+
+	mu       sync.Mutex
+	registry *Registry
+	entries  map[string]*serverFileEntry
+	loadErrs []error
+}
+
+// NewFileRepository loads the servers in the config file at path. A missing
+// file is an empty repository; it is created on the first Register.
+//
+// Entries that do not describe a valid server are not registered but are
+// kept in the file; LoadErrors reports them.
+func NewFileRepository(path string) (*FileRepository, error) {
+	repo := &FileRepository{
+		path:     path,
+		registry: NewRegistry(),
+		entries:  make(map[string]*serverFileEntry),
+	}
+
+	file, err := readServerFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for id, entry := range file.Servers {
+		if entry == nil {
+			continue
+		}
+		if entry.ID == "" {
+			entry.ID = id
+		}
+		repo.entries[id] = entry
+		server, err := entry.server()
+		if err == nil {
+			err = repo.registry.Register(server)
+		}
+		if err != nil {
+			repo.loadErrs = append(repo.loadErrs, fmt.Errorf("server %s in %s: %w", id, path, err))
+		}
+	}
+	sort.Slice(repo.loadErrs, func(i, j int) bool {
+		return repo.loadErrs[i].Error() < repo.loadErrs[j].Error()
+	})
+	return repo, nil
+}
+
+// LoadErrors returns why entries of the config file were not registered
+func (r *FileRepository) LoadErrors() []error {
+	return r.loadErrs
+}
+
+// Path returns the config file the repository persists to
+func (r *FileRepository) Path() string {
+	return r.path
+}
+
+// Register adds a server and saves the config file. The server is not
+// registered if the file cannot be written.
+func (r *FileRepository) Register(server *MCPServer) error {
+	if server == nil {
+		return NewValidationError("cannot register nil server")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Entries that failed to load still own their ID
+	if _, exists := r.entries[server.ID]; exists {
+		return NewValidationError(fmt.Sprintf("duplicate server ID: %s", server.ID))
+	}
+	if err := r.registry.Register(server); err != nil {
+		return err
+	}
+	r.entries[server.ID] = newServerFileEntry(server)
+	if err := r.save(); err != nil {
+		delete(r.entries, server.ID)
+		_ = r.registry.Unregister(server.ID)
+		return err
+	}
+	return nil
+}
+
+// Unregister removes a server and saves the config file. The server stays
+// registered if the file cannot be written.
+func (r *FileRepository) Unregister(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	server, err := r.registry.Get(id)
+	if err != nil {
+		return err
+	}
+	entry := r.entries[id]
+	if err := r.registry.Unregister(id); err != nil {
+		return err
+	}
+	delete(r.entries, id)
+	if err := r.save(); err != nil {
+		_ = r.registry.Register(server)
+		r.entries[id] = entry
+		return err
+	}
+	return nil
+}
+
+// Update replaces a registered server and saves the config file. Entry
+// fields the server does not model, such as the description and credential
+// reference, are kept. The previous server stays registered if the file
+// cannot be written.
+func (r *FileRepository) Update(server *MCPServer) error {
+	if server == nil {
+		return NewValidationError("cannot update nil server")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, err := r.registry.Get(server.ID)
+	if err != nil {
+		return err
+	}
+	previousEntry := r.entries[server.ID]
+	entry := newServerFileEntry(server)
+	if previousEntry != nil {
+		entry.Description = previousEntry.Description
+		entry.CredentialRef = previousEntry.CredentialRef
+	}
+
+	if err := r.registry.Update(server); err != nil {
+		return err
+	}
+	r.entries[server.ID] = entry
+	if err := r.save(); err != nil {
+		_ = r.registry.Update(previous)
+		r.entries[server.ID] = previousEntry
+		return err
+	}
+	return nil
+}
+
+// Get retrieves a server by ID
+func (r *FileRepository) Get(id string) (*MCPServer, error) {
+	return r.registry.Get(id)
+}
+
+// List returns all registered servers
+func (r *FileRepository) List() ([]*MCPServer, error) {
+	return r.registry.List()
+}
+
+// save writes the entries to the config file; the caller holds r.mu
+func (r *FileRepository) save() error {
+	file := serverFile{Servers: r.entries}
+	data, err := yaml.Marshal(&file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal servers: %w", err)
+	}
+	return writeFileAtomic(r.path, data)
+}
+
+// newServerFileEntry describes a server as a config file entry
+func newServerFileEntry(server *MCPServer) *serverFileEntry {
+	entry := &serverFileEntry{
+		ID:        server.ID,
+		Command:   server.Command,
+		Args:      server.Args,
+		Transport: string(TransportStdio),
+		Tags:      server.Tags,
+
+		ToolCategories: server.ToolCategories,
+	}
+	if server.Name != server.ID {
+		entry.Name = server.Name
+	}
+	if server.Transport != nil {
+		entry.Transport = string(server.Transport.Type())
+	}
+	if stdio, ok := server.Transport.(*StdioTransportConfig); ok && len(stdio.Env) > 0 {
+		entry.Env = stdio.Env
+	}
+	if headers := transportHeaders(server.Transport); len(headers) > 0 {
+		entry.Headers = headers
+	}
+	return entry
+}
+
+// server creates the server a config file entry describes
+func (e *serverFileEntry) server() (*MCPServer, error) {
+	transport := TransportType(e.Transport)
+	if transport == "" {
+		transport = TransportStdio
+	}
+	server, err := NewMCPServer(e.ID, e.Command, e.Args, transport)
+	if err != nil {
+		return nil, err
+	}
+	if e.Name != "" {
+		server.Name = e.Name
+	}
+	if server.Tags, err = NormalizeTags(e.Tags); err != nil {
+		return nil, err
+	}
+	if err := validateToolCategories(e.ToolCategories); err != nil {
+		return nil, err
+	}
+	server.ToolCategories = e.ToolCategories
+	if stdio, ok := server.Transport.(*StdioTransportConfig); ok && len(e.Env) > 0 {
+		stdio.Env = e.Env
+	}
+	if len(e.Headers) > 0 {
+		setTransportHeaders(server.Transport, e.Headers)
+	}
+	return server, nil
+}
+
+// readServerFile reads a servers config file, refusing one that other users
+// can read when it holds environment variables or headers
+func readServerFile(path string) (*serverFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &serverFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read servers config: %w", err)
+	}
+
+	var file serverFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse servers config %s: %w", path, err)
+	}
+
+	if err := checkServerFilePermissions(path, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// checkServerFilePermissions rejects a config file with environment
+// variables or headers, which may be secrets, that is readable by group or
+// others.
+// Windows has no permission bits to check.
+func checkServerFilePermissions(path string, file *serverFile) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to check servers config: %w", err)
+	}
+	mode := info.Mode().Perm()
+	if mode&0o077 == 0 {
+		return nil
+	}
+
+	var exposed []string
+	for id, entry := range file.Servers {
+		if entry != nil && (len(entry.Env) > 0 || len(entry.Headers) > 0) {
+			exposed = append(exposed, id)
+		}
+	}
+	if len(exposed) == 0 {
+		return nil
+	}
+	sort.Strings(exposed)
+	return fmt.Errorf("servers config %s is accessible by other users (mode %04o) and holds environment variables or headers for %v; restrict it with: chmod 600 %s",
+		path, mode, exposed, path)
+}
+
+// writeFileAtomic replaces the file at path with data, readable only by its
+// owner. The data is written to a temp file in the same directory, synced,
+// then renamed over path, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write servers config: %w", err)
+	}
+	tempPath := temp.Name()
+	fail := func(err error) error {
+		_ = temp.Close()
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to write servers config: %w", err)
+	}
+
+	// CreateTemp uses 0600, but set it explicitly in case of an odd umask
+	if err := temp.Chmod(0o600); err != nil {
+		return fail(err)
+	}
+	if _, err := temp.Write(data); err != nil {
+		return fail(err)
+	}
+	if err := temp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := temp.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to write servers config: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save servers config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package mcpserver
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRepository_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "servers.yaml")
+
+	repo, err := NewFileRepository(path)
+	require.NoError(t, err)
+	servers, err := repo.List()
+	require.NoError(t, err)
+	assert.Empty(t, servers, "a missing file is an empty repository")
+
+	fs, err := NewMCPServer("filesystem", "npx", []string{"-y", "server-filesystem"}, TransportStdio)
+	require.NoError(t, err)
+	fs.Name = "Files"
+	fs.Transport.(*StdioTransportConfig).Env = map[string]string{"API_KEY": "secret"}
+	require.NoError(t, repo.Register(fs))
+	api, err := NewMCPServer("api", "https://example.com/mcp", nil, TransportHTTP)
+	require.NoError(t, err)
+	require.NoError(t, repo.Register(api))
+	assert.Error(t, repo.Register(api), "duplicate IDs are rejected")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	// A new repository sees the saved servers
+	reloaded, err := NewFileRepository(path)
+	require.NoError(t, err)
+	got, err := reloaded.Get("filesystem")
+	require.NoError(t, err)
+	assert.Equal(t, "Files", got.Name)
+	assert.Equal(t, []string{"-y", "server-filesystem"}, got.Args)
+	assert.Equal(t, "secret", got.Transport.(*StdioTransportConfig).Env["API_KEY"])
+	got, err = reloaded.Get("api")
+	require.NoError(t, err)
+	assert.Equal(t, TransportHTTP, got.Transport.Type())
+
+	require.NoError(t, reloaded.Unregister("api"))
+	reloaded, err = NewFileRepository(path)
+	require.NoError(t, err)
+	_, err = reloaded.Get("api")
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temp files are left behind")
+}
+
+func TestFileRepository_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	config := "servers:\n  files:\n    id: files\n    description: Local files\n    command: npx\n"
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	repo, err := NewFileRepository(path)
+	require.NoError(t, err)
+
+	updated, err := NewMCPServer("files", "https://files.example.com/mcp", nil, TransportHTTP)
+	require.NoError(t, err)
+	require.NoError(t, repo.Update(updated))
+	missing, err := NewMCPServer("missing", "npx", nil, TransportStdio)
+	require.NoError(t, err)
+	assert.Error(t, repo.Update(missing), "only registered servers are updated")
+
+	reloaded, err := NewFileRepository(path)
+	require.NoError(t, err)
+	got, err := reloaded.Get("files")
+	require.NoError(t, err)
+	assert.Equal(t, TransportHTTP, got.Transport.Type())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "description: Local files", "unmodeled fields are kept")
+}
+
+func TestFileRepository_KeepsCLIEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	config := `servers:
+  files:
+    id: files
+    description: Local files
+    command: npx
+    credential_ref: files-token
+    tool_categories:
+      read_file: [read]
+      "*": [write]
+  broken:
+    id: broken
+    command: node
+    transport: carrier-pigeon
+  teleporter:
+    id: teleporter
+    command: beam
+    tool_categories:
+      "*": [teleport]
+`
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+	repo, err := NewFileRepository(path)
+	require.NoError(t, err)
+	require.Len(t, repo.LoadErrors(), 2)
+	assert.Contains(t, repo.LoadErrors()[0].Error(), "broken")
+	assert.Contains(t, repo.LoadErrors()[1].Error(), `unknown category "teleport"`)
+	servers, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, map[string][]string{"read_file": {"read"}, "*": {"write"}}, servers[0].ToolCategories)
+	assert.Error(t, repo.Register(&MCPServer{ID: "broken"}), "invalid entries keep their ID")
+
+	server, err := NewMCPServer("other", "python", nil, TransportStdio)
+	require.NoError(t, err)
+	require.NoError(t, repo.Register(server))
+
+	// Fields the registry doesn't model, and entries it couldn't load, survive a save
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "description: Local files")
+	assert.Contains(t, string(data), "credential_ref: files-token")
+	assert.Contains(t, string(data), "read_file:")
+	assert.Contains(t, string(data), "carrier-pigeon")
+}
+
+func TestFileRepository_Permissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no permission bits on windows")
+	}
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte("servers:\n  a:\n    command: npx\n"), 0o644))
+	_, err := NewFileRepository(path)
+	assert.NoError(t, err, "readable files without secrets load")
+
+	require.NoError(t, os.WriteFile(path, []byte("servers:\n  a:\n    command: npx\n    env:\n      TOKEN: x\n"), 0o644))
+	require.NoError(t, os.Chmod(path, 0o644))
+	_, err = NewFileRepository(path)
+	assert.ErrorContains(t, err, "chmod 600")
+
+	require.NoError(t, os.Chmod(path, 0o600))
+	_, err = NewFileRepository(path)
+	assert.NoError(t, err)
+}
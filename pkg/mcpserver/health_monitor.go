@@ -0,0 +1,245 @@
+package mcpserver
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/dshills/goflow/pkg/config"
+)
+
+const (
+	// DefaultHealthCheckTimeout bounds a single server's health check
+	DefaultHealthCheckTimeout = 5 * time.Second
+	// DefaultHealthCheckJitter spreads checks by up to 10% of the interval
+	DefaultHealthCheckJitter = 0.1
+)
+
+// HealthMonitorConfig configures a HealthMonitor
+type HealthMonitorConfig struct {
+	// Interval between rounds of checks. Zero follows the
+	// health_check_interval tunable, including live changes.
+	Interval time.Duration
+	// Jitter randomly shifts each round by up to this fraction of the
+	// interval either way, so monitors started together do not check in
+	// lockstep. Zero disables it.
+	Jitter float64
+	// Timeout bounds each server's check; zero uses
+	// DefaultHealthCheckTimeout. A check that times out marks the server
+	// unhealthy.
+	Timeout time.Duration
+}
+
+// HealthMonitor periodically health checks the connected servers of a
+// repository in the background. Servers publish each change of their
+// health status as a TypeServerHealthChanged event on the default event
+// bus, so views and other consumers subscribe there instead of polling.
+//
+// A monitor can be started and stopped any number of times; Stop waits for
+// a round of checks in progress to finish, which takes at most the timeout.
+type HealthMonitor struct {
+	repo   ServerRepository
+	config HealthMonitorConfig
+
+	mu           sync.Mutex
+	interval     time.Duration
+	running      bool
+	stopChan     chan struct{}
+	intervalChan chan time.Duration
+	unsubscribe  func()
+	lastCheck    time.Time
+	wg           sync.WaitGroup
+}
+
+// NewHealthMonitor creates a monitor for the servers in repo. It does not
+// check anything until started.
+func NewHealthMonitor(repo ServerRepository, cfg HealthMonitorConfig) *HealthMonitor {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultHealthCheckTimeout
+	}
+	if cfg.Jitter < 0 {
+		cfg.Jitter = 0
+	}
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = config.Global().Get().HealthCheckInterval()
+	}
+	return &HealthMonitor{
+		repo:         repo,
+		config:       cfg,
+		interval:     interval,
+		intervalChan: make(chan time.Duration, 1),
+	}
+}
+
+// Start begins checking servers in the background. Starting a running
+// monitor does nothing.
+func (m *HealthMonitor) Start() {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = true
+	m.stopChan = make(chan struct{})
+	stop := m.stopChan
+	m.wg.Add(1)
+	m.mu.Unlock()
+
+	// Follow the health_check_interval tunable unless an interval was set
+	if m.config.Interval <= 0 {
+		unsubscribe := config.Global().Subscribe(func(t config.Tunables) {
+			m.SetInterval(t.HealthCheckInterval())
+		})
+		m.mu.Lock()
+		m.unsubscribe = unsubscribe
+		m.mu.Unlock()
+	}
+
+	go m.run(stop)
+}
+
+// Stop stops the monitor and waits for checks in progress to finish.
+// Stopping a monitor that is not running does nothing.
+func (m *HealthMonitor) Stop() {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = false
+	close(m.stopChan)
+	unsubscribe := m.unsubscribe
+	m.unsubscribe = nil
+	m.mu.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+	m.wg.Wait()
+}
+
+// Running reports whether the monitor is started
+func (m *HealthMonitor) Running() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running
+}
+
+// SetInterval changes the time between rounds of checks. The new interval
+// takes effect immediately; non-positive values are ignored.
+func (m *HealthMonitor) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if interval == m.interval {
+		return
+	}
+	m.interval = interval
+
+	// Replace any pending change so the latest interval wins
+	select {
+	case <-m.intervalChan:
+	default:
+	}
+	m.intervalChan <- interval
+}
+
+// Interval returns the time between rounds of checks
+func (m *HealthMonitor) Interval() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.interval
+}
+
+// LastCheck returns when the last round of checks finished, or the zero
+// time if none has
+func (m *HealthMonitor) LastCheck() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastCheck
+}
+
+// CheckNow health checks every connected server and waits for the results.
+// It returns how many servers were healthy and how many failed.
+func (m *HealthMonitor) CheckNow() (healthy, failed int) {
+	servers, err := m.repo.List()
+	if err != nil {
+		return 0, 0
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, server := range servers {
+		if server.Connection.GetState() != StateConnected {
+			continue
+		}
+		wg.Add(1)
+		go func(s *MCPServer) {
+			defer wg.Done()
+			err := m.check(s)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+			} else {
+				healthy++
+			}
+		}(server)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	m.lastCheck = time.Now()
+	m.mu.Unlock()
+	return healthy, failed
+}
+
+// check health checks one server; a ping that does not answer within the
+// timeout marks it unhealthy
+func (m *HealthMonitor) check(server *MCPServer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.Timeout)
+	defer cancel()
+	return server.HealthCheckContext(ctx)
+}
+
+// run checks servers every interval, with jitter, until stop is closed
+func (m *HealthMonitor) run(stop <-chan struct{}) {
+	defer m.wg.Done()
+
+	timer := time.NewTimer(m.nextDelay())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-m.intervalChan:
+			timer.Reset(m.nextDelay())
+		case <-timer.C:
+			m.CheckNow()
+			timer.Reset(m.nextDelay())
+		}
+	}
+}
+
+// nextDelay returns the interval shifted by a random jitter
+func (m *HealthMonitor) nextDelay() time.Duration {
+	interval := m.Interval()
+	if m.config.Jitter == 0 {
+		return interval
+	}
+	spread := float64(interval) * m.config.Jitter
+	delay := time.Duration(float64(interval) + (rand.Float64()*2-1)*spread)
+	if delay <= 0 {
+		return interval
+	}
+	return delay
+}
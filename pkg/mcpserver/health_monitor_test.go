@@ -0,0 +1,136 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pingClient answers pings with err, or blocks until release is closed or
+// the ping times out
+type pingClient struct {
+	err     error
+	release chan struct{}
+}
+
+func (c *pingClient) Connect(ctx context.Context) error             { return nil }
+func (c *pingClient) Close() error                                  { return nil }
+func (c *pingClient) IsConnected() bool                             { return true }
+func (c *pingClient) ListTools(ctx context.Context) ([]Tool, error) { return nil, nil }
+func (c *pingClient) CallTool(ctx context.Context, toolName string, params map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (c *pingClient) Ping(ctx context.Context) error {
+	if c.release != nil {
+		select {
+		case <-c.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return c.err
+}
+
+// registerConnected registers a connected server using client, if any
+func registerConnected(t *testing.T, registry *Registry, id string, client MCPClient) *MCPServer {
+	t.Helper()
+	server, err := NewMCPServer(id, "mcp-"+id, nil, TransportStdio)
+	require.NoError(t, err)
+	require.NoError(t, server.Connect())
+	require.NoError(t, server.CompleteConnection())
+	if client != nil {
+		server.SetClient(client)
+	}
+	require.NoError(t, registry.Register(server))
+	return server
+}
+
+func TestHealthMonitor_CheckNow(t *testing.T) {
+	registry := NewRegistry()
+	healthy := registerConnected(t, registry, "hm-healthy", nil)
+	failing := registerConnected(t, registry, "hm-failing", &pingClient{err: errors.New("connection reset")})
+	idle, err := NewMCPServer("hm-idle", "mcp-idle", nil, TransportStdio)
+	require.NoError(t, err)
+	require.NoError(t, registry.Register(idle))
+
+	changes := make(chan events.ServerHealthChange, 10)
+	unsubscribe := events.Default().Subscribe(events.SubscriberFunc(func(event events.Event) {
+		if change, ok := event.Payload.(events.ServerHealthChange); ok && change.ServerID == failing.ID {
+			changes <- change
+		}
+	}), events.TopicServer)
+	defer unsubscribe()
+
+	monitor := NewHealthMonitor(registry, HealthMonitorConfig{Interval: time.Hour})
+	ok, failed := monitor.CheckNow()
+	assert.Equal(t, 1, ok)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, HealthHealthy, healthy.HealthStatus)
+	assert.Equal(t, HealthUnhealthy, failing.HealthStatus)
+	assert.Equal(t, HealthUnknown, idle.HealthStatus)
+	assert.False(t, monitor.LastCheck().IsZero())
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, string(HealthUnhealthy), change.Current)
+		assert.Contains(t, change.Error, "connection reset")
+	case <-time.After(time.Second):
+		t.Fatal("no health change event published")
+	}
+}
+
+func TestHealthMonitor_Timeout(t *testing.T) {
+	registry := NewRegistry()
+	client := &pingClient{release: make(chan struct{})}
+	server := registerConnected(t, registry, "hm-stuck", client)
+
+	monitor := NewHealthMonitor(registry, HealthMonitorConfig{Interval: time.Hour, Timeout: 20 * time.Millisecond})
+	_, failed := monitor.CheckNow()
+	close(client.release)
+
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, HealthUnhealthy, server.HealthStatus)
+	assert.Contains(t, server.Connection.GetLastError(), "deadline exceeded")
+}
+
+func TestHealthMonitor_StartStop(t *testing.T) {
+	registry := NewRegistry()
+	server := registerConnected(t, registry, "hm-periodic", nil)
+
+	monitor := NewHealthMonitor(registry, HealthMonitorConfig{Interval: 10 * time.Millisecond, Jitter: 0.5})
+	monitor.Start()
+	monitor.Start()
+	assert.True(t, monitor.Running())
+	require.Eventually(t, func() bool { return !monitor.LastCheck().IsZero() }, time.Second, 5*time.Millisecond)
+	monitor.Stop()
+	monitor.Stop()
+	assert.False(t, monitor.Running())
+	assert.Equal(t, HealthHealthy, server.HealthStatus)
+
+	// A stopped monitor checks nothing until started again
+	last := monitor.LastCheck()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, last, monitor.LastCheck())
+
+	monitor.Start()
+	defer monitor.Stop()
+	require.Eventually(t, func() bool { return monitor.LastCheck().After(last) }, time.Second, 5*time.Millisecond)
+}
+
+func TestHealthMonitor_Jitter(t *testing.T) {
+	monitor := NewHealthMonitor(NewRegistry(), HealthMonitorConfig{Interval: time.Second, Jitter: 0.2})
+	for i := 0; i < 100; i++ {
+		delay := monitor.nextDelay()
+		assert.GreaterOrEqual(t, delay, 800*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1200*time.Millisecond)
+	}
+
+	monitor.SetInterval(2 * time.Second)
+	assert.Equal(t, 2*time.Second, monitor.Interval())
+}
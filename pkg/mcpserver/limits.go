@@ -0,0 +1,61 @@
+package mcpserver
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultQueueTimeout is how long a request waits for a free slot when
+// RequestLimits.QueueTimeout is not set
+const DefaultQueueTimeout = 30 * time.Second
+
+// RequestLimits bounds the load a client puts on an MCP server.
+// Requests over the limits are queued until a slot frees up or the
+// queue timeout expires. Zero values mean unlimited.
+type RequestLimits struct {
+	MaxConcurrent     int           // Maximum in-flight requests (0 = unlimited)
+	RequestsPerSecond float64       // Sustained request rate (0 = unlimited)
+	Burst             int           // Requests allowed at once above the rate (default 1)
+	QueueTimeout      time.Duration // Maximum wait for a slot (0 = DefaultQueueTimeout)
+}
+
+// IsZero reports whether no limits are configured. Nil-safe.
+func (l *RequestLimits) IsZero() bool {
+	return l == nil || (l.MaxConcurrent == 0 && l.RequestsPerSecond == 0)
+}
+
+// Validate checks if the limits are valid. A nil config is valid.
+func (l *RequestLimits) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.MaxConcurrent < 0 {
+		return NewValidationError(fmt.Sprintf("request limits: max concurrent cannot be negative (got %d)", l.MaxConcurrent))
+	}
+	if l.RequestsPerSecond < 0 {
+		return NewValidationError(fmt.Sprintf("request limits: requests per second cannot be negative (got %g)", l.RequestsPerSecond))
+	}
+	if l.Burst < 0 {
+		return NewValidationError(fmt.Sprintf("request limits: burst cannot be negative (got %d)", l.Burst))
+	}
+	if l.QueueTimeout < 0 {
+		return NewValidationError(fmt.Sprintf("request limits: queue timeout cannot be negative (got %s)", l.QueueTimeout))
+	}
+	return nil
+}
+
+// EffectiveBurst returns the burst size, defaulting to 1
+func (l *RequestLimits) EffectiveBurst() int {
+	if l == nil || l.Burst <= 0 {
+		return 1
+	}
+	return l.Burst
+}
+
+// EffectiveQueueTimeout returns the queue timeout, defaulting to DefaultQueueTimeout
+func (l *RequestLimits) EffectiveQueueTimeout() time.Duration {
+	if l == nil || l.QueueTimeout <= 0 {
+		return DefaultQueueTimeout
+	}
+	return l.QueueTimeout
+}
@@ -0,0 +1,202 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// mcpConfigFile is the standard MCP client config used by Claude Desktop,
+// Cursor and other clients:
+//
+//	{
+//	  "mcpServers": {
+//	    "filesystem": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"]},
+//	    "remote": {"url": "https://example.com/mcp", "headers": {"Authorization": "Bearer ..."}}
+//	  }
+//	}
+type mcpConfigFile struct {
+	MCPServers map[string]*mcpConfigServer `json:"mcpServers"`
+}
+
+// mcpConfigServer is one entry of mcpServers. Local servers have a command;
+// remote ones a URL, with an optional type ("sse", "http" or
+// "streamable-http").
+type mcpConfigServer struct {
+	Command  string            `json:"command"`
+	Args     []string          `json:"args"`
+	Env      map[string]string `json:"env"`
+	URL      string            `json:"url"`
+	Type     string            `json:"type"`
+	Headers  map[string]string `json:"headers"`
+	Disabled bool              `json:"disabled"`
+}
+
+// ImportMCPConfig converts the servers of a standard mcpServers JSON config
+// into GoFlow servers, sorted by ID. Server names that are not valid IDs
+// are sanitized, keeping the original as the server's name. Entries that
+// are disabled or cannot be converted are returned as skipped, each with
+// the reason.
+func ImportMCPConfig(data []byte) (servers []*MCPServer, skipped []error, err error) {
+	var config mcpConfigFile
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse MCP config: %w", err)
+	}
+	if config.MCPServers == nil {
+		return nil, nil, NewValidationError("MCP config has no mcpServers section")
+	}
+
+	names := make([]string, 0, len(config.MCPServers))
+	for name := range config.MCPServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ids := make(map[string]bool, len(names))
+	for _, name := range names {
+		entry := config.MCPServers[name]
+		if entry == nil || entry.Disabled {
+			skipped = append(skipped, fmt.Errorf("%s: disabled", name))
+			continue
+		}
+		id := configServerID(name)
+		if id == "" || ids[id] {
+			skipped = append(skipped, fmt.Errorf("%s: no unique server ID", name))
+			continue
+		}
+		server, err := entry.server(id)
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		server.Name = name
+		ids[id] = true
+		servers = append(servers, server)
+	}
+
+	sort.Slice(servers, func(i, j int) bool { return servers[i].ID < servers[j].ID })
+	return servers, skipped, nil
+}
+
+// server creates the server an mcpServers entry describes
+func (c *mcpConfigServer) server(id string) (*MCPServer, error) {
+	if c.Command != "" {
+		server, err := NewMCPServer(id, c.Command, c.Args, TransportStdio)
+		if err != nil {
+			return nil, err
+		}
+		if len(c.Env) > 0 {
+			server.Transport.(*StdioTransportConfig).Env = c.Env
+		}
+		return server, nil
+	}
+	if c.URL == "" {
+		return nil, NewValidationError("entry has neither a command nor a url")
+	}
+
+	var transport TransportType
+	switch strings.ToLower(c.Type) {
+	case "sse":
+		transport = TransportSSE
+	case "http", "streamable-http", "streamablehttp":
+		transport = TransportHTTP
+	case "":
+		// Clients guess from the endpoint when no type is given
+		transport = TransportHTTP
+		if strings.HasSuffix(strings.TrimSuffix(c.URL, "/"), "/sse") {
+			transport = TransportSSE
+		}
+	default:
+		return nil, NewValidationError(fmt.Sprintf("unsupported server type: %s", c.Type))
+	}
+
+	server, err := NewMCPServer(id, c.URL, nil, transport)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Headers) > 0 {
+		setTransportHeaders(server.Transport, c.Headers)
+	}
+	return server, nil
+}
+
+// setTransportHeaders sets the request headers of an SSE or HTTP transport
+func setTransportHeaders(transport Transport, headers map[string]string) {
+	switch t := transport.(type) {
+	case *SSETransportConfig:
+		t.Headers = headers
+	case *HTTPTransportConfig:
+		t.Headers = headers
+	}
+}
+
+// transportHeaders returns the request headers of an SSE or HTTP transport
+func transportHeaders(transport Transport) map[string]string {
+	switch t := transport.(type) {
+	case *SSETransportConfig:
+		return t.Headers
+	case *HTTPTransportConfig:
+		return t.Headers
+	}
+	return nil
+}
+
+// configServerID turns an mcpServers name into a server ID of letters,
+// digits, dashes and underscores
+func configServerID(name string) string {
+	var b strings.Builder
+	for _, ch := range strings.TrimSpace(name) {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9', ch == '-', ch == '_':
+			b.WriteRune(ch)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// ClaudeDesktopConfigPath returns where Claude Desktop keeps its MCP config
+// on this platform
+func ClaudeDesktopConfigPath() string {
+	const name = "claude_desktop_config.json"
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(homeDir, "Library", "Application Support", "Claude", name)
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "Claude", name)
+		}
+		return filepath.Join(homeDir, "AppData", "Roaming", "Claude", name)
+	default:
+		if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+			return filepath.Join(configHome, "Claude", name)
+		}
+		return filepath.Join(homeDir, ".config", "Claude", name)
+	}
+}
+
+// ImportServers registers the servers in repo, skipping those whose ID is
+// already registered. It returns the IDs imported and the IDs that already
+// existed.
+func ImportServers(repo ServerRepository, servers []*MCPServer) (imported, existing []string, err error) {
+	for _, server := range servers {
+		if _, err := repo.Get(server.ID); err == nil {
+			existing = append(existing, server.ID)
+			continue
+		}
+		if err := repo.Register(server); err != nil {
+			return imported, existing, fmt.Errorf("failed to import server %s: %w", server.ID, err)
+		}
+		imported = append(imported, server.ID)
+	}
+	return imported, existing, nil
+}
@@ -0,0 +1,85 @@
+package mcpserver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const claudeDesktopConfig = `{
+  "mcpServers": {
+    "filesystem": {
+      "command": "npx",
+      "args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"],
+      "env": {"DEBUG": "1"}
+    },
+    "My Search": {"url": "https://search.example.com/sse"},
+    "remote": {
+      "type": "streamable-http",
+      "url": "https://api.example.com/mcp",
+      "headers": {"Authorization": "Bearer token"}
+    },
+    "off": {"command": "node", "disabled": true},
+    "empty": {}
+  }
+}`
+
+func TestImportMCPConfig(t *testing.T) {
+	servers, skipped, err := ImportMCPConfig([]byte(claudeDesktopConfig))
+	require.NoError(t, err)
+	require.Len(t, servers, 3)
+	assert.Len(t, skipped, 2, "disabled and incomplete entries are skipped")
+
+	byID := make(map[string]*MCPServer)
+	for _, server := range servers {
+		byID[server.ID] = server
+	}
+
+	fs := byID["filesystem"]
+	require.NotNil(t, fs)
+	assert.Equal(t, TransportStdio, fs.Transport.Type())
+	assert.Equal(t, []string{"-y", "@modelcontextprotocol/server-filesystem", "/tmp"}, fs.Args)
+	assert.Equal(t, "1", fs.Transport.(*StdioTransportConfig).Env["DEBUG"])
+
+	search := byID["My-Search"]
+	require.NotNil(t, search, "names are sanitized into IDs")
+	assert.Equal(t, "My Search", search.Name)
+	assert.Equal(t, TransportSSE, search.Transport.Type(), "/sse endpoints default to SSE")
+
+	remote := byID["remote"]
+	require.NotNil(t, remote)
+	assert.Equal(t, TransportHTTP, remote.Transport.Type())
+	assert.Equal(t, "Bearer token", remote.Transport.(*HTTPTransportConfig).Headers["Authorization"])
+
+	_, _, err = ImportMCPConfig([]byte(`{"servers": {}}`))
+	assert.Error(t, err, "configs without mcpServers are rejected")
+}
+
+func TestImportServers(t *testing.T) {
+	servers, _, err := ImportMCPConfig([]byte(claudeDesktopConfig))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	repo, err := NewFileRepository(path)
+	require.NoError(t, err)
+	existing, err := NewMCPServer("filesystem", "other", nil, TransportStdio)
+	require.NoError(t, err)
+	require.NoError(t, repo.Register(existing))
+
+	imported, skipped, err := ImportServers(repo, servers)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"My-Search", "remote"}, imported)
+	assert.Equal(t, []string{"filesystem"}, skipped)
+
+	// Headers are saved with the server
+	reloaded, err := NewFileRepository(path)
+	require.NoError(t, err)
+	remote, err := reloaded.Get("remote")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", remote.Transport.(*HTTPTransportConfig).Headers["Authorization"])
+	fs, err := reloaded.Get("filesystem")
+	require.NoError(t, err)
+	assert.Equal(t, "other", fs.Command, "registered servers are left unchanged")
+}
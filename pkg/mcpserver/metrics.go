@@ -0,0 +1,142 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ServerMetrics is the JSON form of a server's call statistics
+type ServerMetrics struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Health    HealthStatus    `json:"health"`
+	Calls     uint64          `json:"calls"`
+	Successes uint64          `json:"successes"`
+	Errors    uint64          `json:"errors"`
+	ErrorRate float64         `json:"error_rate"`
+	P50Ms     float64         `json:"p50_ms"`
+	P90Ms     float64         `json:"p90_ms"`
+	P99Ms     float64         `json:"p99_ms"`
+	Recent    []CallSampleRow `json:"recent"`
+}
+
+// CallSampleRow is the JSON form of a CallSample
+type CallSampleRow struct {
+	Tool       string  `json:"tool"`
+	Time       string  `json:"time"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// NewServerMetrics describes a server's call statistics
+func NewServerMetrics(server *MCPServer) ServerMetrics {
+	stats := server.Stats()
+	metrics := ServerMetrics{
+		ID:        server.ID,
+		Name:      server.Name,
+		Health:    server.HealthStatus,
+		Calls:     stats.Calls(),
+		Successes: stats.Successes,
+		Errors:    stats.Errors,
+		ErrorRate: stats.ErrorRate(),
+		P50Ms:     stats.P50.Seconds() * 1000,
+		P90Ms:     stats.P90.Seconds() * 1000,
+		P99Ms:     stats.P99.Seconds() * 1000,
+		Recent:    make([]CallSampleRow, len(stats.Recent)),
+	}
+	for i, sample := range stats.Recent {
+		metrics.Recent[i] = CallSampleRow{
+			Tool:       sample.Tool,
+			Time:       sample.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+			DurationMs: sample.Duration.Seconds() * 1000,
+			Error:      sample.Error,
+		}
+	}
+	return metrics
+}
+
+// MetricsCollector writes more metrics for GET /metrics, in the Prometheus
+// text exposition format
+type MetricsCollector func(w io.Writer)
+
+// NewMetricsHandler returns the metrics endpoint for the servers in repo:
+//
+//	GET /metrics          call counts and latency percentiles per server,
+//	                      in the Prometheus text format, followed by the
+//	                      metrics of collectors
+//	GET /metrics/servers  the same with the recent calls of each server, as
+//	                      JSON
+func NewMetricsHandler(repo ServerRepository, collectors ...MetricsCollector) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		servers, err := sortedServers(repo)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var b strings.Builder
+		b.WriteString(prometheusMetrics(servers))
+		for _, collect := range collectors {
+			collect(&b)
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_, _ = w.Write([]byte(b.String())) // Error ignored: the client has gone away
+	})
+	mux.HandleFunc("GET /metrics/servers", func(w http.ResponseWriter, r *http.Request) {
+		servers, err := sortedServers(repo)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		metrics := make([]ServerMetrics, len(servers))
+		for i, server := range servers {
+			metrics[i] = NewServerMetrics(server)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(metrics) // Error ignored: the client has gone away
+	})
+	return mux
+}
+
+// sortedServers lists the servers of repo by ID
+func sortedServers(repo ServerRepository) ([]*MCPServer, error) {
+	servers, err := repo.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].ID < servers[j].ID })
+	return servers, nil
+}
+
+// prometheusMetrics renders the servers' call statistics in the Prometheus
+// text exposition format
+func prometheusMetrics(servers []*MCPServer) string {
+	var b strings.Builder
+	b.WriteString("# HELP goflow_mcp_calls_total Tool calls made to an MCP server.\n")
+	b.WriteString("# TYPE goflow_mcp_calls_total counter\n")
+	stats := make([]CallStatsSnapshot, len(servers))
+	for i, server := range servers {
+		stats[i] = server.Stats()
+		fmt.Fprintf(&b, "goflow_mcp_calls_total{server=%q,result=\"success\"} %d\n", server.ID, stats[i].Successes)
+		fmt.Fprintf(&b, "goflow_mcp_calls_total{server=%q,result=\"error\"} %d\n", server.ID, stats[i].Errors)
+	}
+	b.WriteString("# HELP goflow_mcp_call_duration_seconds Latency of an MCP server's recent tool calls.\n")
+	b.WriteString("# TYPE goflow_mcp_call_duration_seconds summary\n")
+	for i, server := range servers {
+		for _, q := range []struct {
+			quantile string
+			value    float64
+		}{
+			{"0.5", stats[i].P50.Seconds()},
+			{"0.9", stats[i].P90.Seconds()},
+			{"0.99", stats[i].P99.Seconds()},
+		} {
+			fmt.Fprintf(&b, "goflow_mcp_call_duration_seconds{server=%q,quantile=%q} %g\n", server.ID, q.quantile, q.value)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package mcpserver
+
+import (
+	"regexp"
+	"sort"
+)
+
+const (
+	// maxSchemaDepth bounds how deeply nested fields are inferred
+	maxSchemaDepth = 8
+	// maxFieldPaths bounds the field paths listed for a schema
+	maxFieldPaths = 200
+)
+
+// identifierKey matches field names JSONPath can use after a dot
+var identifierKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// InferOutputSchema infers an approximate JSON schema for a tool's output
+// from sample outputs, as decoded from JSON. Each field has the types seen
+// in any sample; fields present in every sample that has the object are
+// required. It returns nil without samples.
+func InferOutputSchema(samples []interface{}) map[string]interface{} {
+	if len(samples) == 0 {
+		return nil
+	}
+	return inferSchema(samples, 0)
+}
+
+// inferSchema infers the schema of the values of one field
+func inferSchema(values []interface{}, depth int) map[string]interface{} {
+	types := make(map[string]bool)
+	var objects []map[string]interface{}
+	var items []interface{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			types["null"] = true
+		case bool:
+			types["boolean"] = true
+		case float64:
+			if v == float64(int64(v)) {
+				types["integer"] = true
+			} else {
+				types["number"] = true
+			}
+		case string:
+			types["string"] = true
+		case map[string]interface{}:
+			types["object"] = true
+			objects = append(objects, v)
+		case []interface{}:
+			types["array"] = true
+			items = append(items, v...)
+		}
+	}
+	if types["number"] {
+		delete(types, "integer")
+	}
+
+	schema := make(map[string]interface{})
+	switch names := sortedKeys(types); len(names) {
+	case 0:
+	case 1:
+		schema["type"] = names[0]
+	default:
+		list := make([]interface{}, len(names))
+		for i, name := range names {
+			list[i] = name
+		}
+		schema["type"] = list
+	}
+	if depth >= maxSchemaDepth {
+		return schema
+	}
+
+	if len(objects) > 0 {
+		fields := make(map[string][]interface{})
+		seen := make(map[string]int)
+		for _, object := range objects {
+			for key, value := range object {
+				fields[key] = append(fields[key], value)
+				seen[key]++
+			}
+		}
+		properties := make(map[string]interface{}, len(fields))
+		var required []string
+		for key, fieldValues := range fields {
+			properties[key] = inferSchema(fieldValues, depth+1)
+			if seen[key] == len(objects) {
+				required = append(required, key)
+			}
+		}
+		sort.Strings(required)
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+	if len(items) > 0 {
+		schema["items"] = inferSchema(items, depth+1)
+	}
+	return schema
+}
+
+// OutputFieldPaths lists the JSONPath of every field of an inferred output
+// schema, such as $.content[*].text, in depth-first order
+func OutputFieldPaths(schema map[string]interface{}) []string {
+	var paths []string
+	collectFieldPaths("$", schema, &paths)
+	return paths
+}
+
+// collectFieldPaths adds the paths of the fields below prefix
+func collectFieldPaths(prefix string, schema map[string]interface{}, paths *[]string) {
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, key := range sortedKeys(properties) {
+			if len(*paths) >= maxFieldPaths {
+				return
+			}
+			path := prefix + "['" + key + "']"
+			if identifierKey.MatchString(key) {
+				path = prefix + "." + key
+			}
+			*paths = append(*paths, path)
+			if field, ok := properties[key].(map[string]interface{}); ok {
+				collectFieldPaths(path, field, paths)
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		collectFieldPaths(prefix+"[*]", items, paths)
+	}
+}
+
+// sortedKeys returns the keys of a map, sorted
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,48 @@
+package mcpserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferOutputSchema(t *testing.T) {
+	samples := []interface{}{
+		map[string]interface{}{
+			"content": []interface{}{
+				map[string]interface{}{"type": "text", "text": "hello"},
+			},
+			"count": float64(1),
+		},
+		map[string]interface{}{
+			"content": []interface{}{},
+			"count":   1.5,
+			"isError": false,
+			"odd key": nil,
+		},
+	}
+
+	schema := InferOutputSchema(samples)
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []string{"content", "count"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, "number", properties["count"].(map[string]interface{})["type"], "integers and numbers merge to number")
+	content := properties["content"].(map[string]interface{})
+	assert.Equal(t, "array", content["type"])
+	assert.Equal(t, "object", content["items"].(map[string]interface{})["type"])
+
+	assert.Equal(t, []string{
+		"$.content",
+		"$.content[*].text",
+		"$.content[*].type",
+		"$.count",
+		"$.isError",
+		"$['odd key']",
+	}, OutputFieldPaths(schema))
+
+	assert.Nil(t, InferOutputSchema(nil))
+	assert.Equal(t, []interface{}{"integer", "string"}, InferOutputSchema([]interface{}{float64(3), "three"})["type"])
+}
@@ -0,0 +1,53 @@
+package mcpserver
+
+import (
+	"context"
+	"strings"
+)
+
+// ToolProgress is a progress notification a server sends while a tool call
+// runs. Tools that stream their output send it in Content, a piece at a
+// time.
+type ToolProgress struct {
+	// Progress increases with each notification; Total is 0 when unknown
+	Progress float64
+	Total    float64
+	Message  string
+	// Content holds content items streamed with the notification, in the
+	// same form as a tool result's content
+	Content []interface{}
+}
+
+// Text returns the text of the streamed content items
+func (p ToolProgress) Text() string {
+	var b strings.Builder
+	for _, item := range p.Content {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if text, ok := m["text"].(string); ok && (m["type"] == nil || m["type"] == "text") {
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+// ToolProgressFunc receives the progress notifications of a tool call. It is
+// called from the client's reader and must not block.
+type ToolProgressFunc func(ToolProgress)
+
+type toolProgressKey struct{}
+
+// WithToolProgress returns a context whose tool calls report progress to fn.
+// Clients that support progress ask the server for notifications only when
+// the context carries a handler.
+func WithToolProgress(ctx context.Context, fn ToolProgressFunc) context.Context {
+	return context.WithValue(ctx, toolProgressKey{}, fn)
+}
+
+// ToolProgressHandler returns the progress handler of ctx, or nil
+func ToolProgressHandler(ctx context.Context) ToolProgressFunc {
+	fn, _ := ctx.Value(toolProgressKey{}).(ToolProgressFunc)
+	return fn
+}
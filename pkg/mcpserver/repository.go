@@ -9,6 +9,7 @@ import (
 type ServerRepository interface {
 	Register(server *MCPServer) error
 	Unregister(id string) error
+	Update(server *MCPServer) error
 	Get(id string) (*MCPServer, error)
 	List() ([]*MCPServer, error)
 }
@@ -57,6 +58,23 @@ func (r *Registry) Unregister(id string) error {
 	return nil
 }
 
+// Update replaces a registered server with one of the same ID
+func (r *Registry) Update(server *MCPServer) error {
+	if server == nil {
+		return NewValidationError("cannot update nil server")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.servers[server.ID]; !exists {
+		return NewValidationError(fmt.Sprintf("server not found: %s", server.ID))
+	}
+
+	r.servers[server.ID] = server
+	return nil
+}
+
 // Get retrieves a server by ID
 func (r *Registry) Get(id string) (*MCPServer, error) {
 	r.mu.RLock()
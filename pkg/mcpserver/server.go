@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/dshills/goflow/pkg/events"
 )
 
 // MCPClient is an interface for MCP protocol communication
@@ -40,7 +42,11 @@ type MCPServer struct {
 	HealthStatus    HealthStatus
 	LastHealthCheck time.Time
 	Metadata        ServerMetadata
-	client          MCPClient // Optional MCP client for protocol communication
+	Limits          *RequestLimits      // Optional request concurrency and rate limits
+	Tags            []string            // Groups the server (e.g. "prod", "db"); see ResolveAlias
+	ToolCategories  map[string][]string // Effects of the server's tools by tool name or "*": read, write, network, exec
+	client          MCPClient           // Optional MCP client for protocol communication
+	stats           *CallStats          // Tool call counts and recent latencies
 }
 
 // ServerMetadata contains server capabilities and version information
@@ -62,6 +68,12 @@ func (s *MCPServer) GetClient() MCPClient {
 	return s.client
 }
 
+// Stats returns the server's tool call statistics: success and error
+// counts, latency percentiles and the most recent calls
+func (s *MCPServer) Stats() CallStatsSnapshot {
+	return s.stats.Snapshot()
+}
+
 // NewMCPServer creates a new MCP server registration
 func NewMCPServer(id, command string, args []string, transportType TransportType) (*MCPServer, error) {
 	// Validate inputs
@@ -113,6 +125,7 @@ func NewMCPServer(id, command string, args []string, transportType TransportType
 		Tools:        []Tool{},
 		HealthStatus: HealthUnknown,
 		Metadata:     ServerMetadata{},
+		stats:        NewCallStats(DefaultCallWindow),
 	}, nil
 }
 
@@ -185,7 +198,7 @@ func (s *MCPServer) FailConnection(errorMsg string) error {
 	s.Connection.mu.Unlock()
 
 	// Update health status
-	s.HealthStatus = HealthUnhealthy
+	s.setHealth(HealthUnhealthy, errorMsg)
 	s.LastHealthCheck = time.Now()
 
 	return nil
@@ -206,7 +219,7 @@ func (s *MCPServer) Disconnect() error {
 	s.Tools = []Tool{}
 
 	// Update health status
-	s.HealthStatus = HealthDisconnected
+	s.setHealth(HealthDisconnected, "")
 	s.LastHealthCheck = time.Now()
 
 	return nil
@@ -247,7 +260,7 @@ func (s *MCPServer) Reconnect() error {
 	s.Connection.LastActivity = time.Now()
 	s.Connection.mu.Unlock()
 
-	s.HealthStatus = HealthUnknown
+	s.setHealth(HealthUnknown, "")
 
 	return nil
 }
@@ -318,6 +331,12 @@ func (s *MCPServer) DiscoverTools() error {
 // - The tool is not found in the tools list
 // - The MCP client call fails
 func (s *MCPServer) InvokeTool(toolName string, params map[string]interface{}) (interface{}, error) {
+	return s.InvokeToolContext(context.Background(), toolName, params)
+}
+
+// InvokeToolContext is InvokeTool bound to ctx: cancelling ctx abandons the
+// call, and clients that support it tell the server to stop working on it.
+func (s *MCPServer) InvokeToolContext(ctx context.Context, toolName string, params map[string]interface{}) (interface{}, error) {
 	// THREAD-SAFETY: Use getter for state check
 	if s.Connection.GetState() != StateConnected {
 		return nil, NewConnectionError("cannot invoke tool: not connected")
@@ -338,10 +357,12 @@ func (s *MCPServer) InvokeTool(toolName string, params map[string]interface{}) (
 
 	// If a client is configured, use it to invoke the tool via MCP protocol
 	if s.client != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
 
+		started := time.Now()
 		result, err := s.client.CallTool(ctx, toolName, params)
+		s.stats.Record(toolName, started, err)
 		if err != nil {
 			errorMsg := fmt.Sprintf("tool invocation failed: %v", err)
 			s.RecordUnhealthy(errorMsg)
@@ -375,6 +396,12 @@ func (s *MCPServer) InvokeTool(toolName string, params map[string]interface{}) (
 //
 // Returns an error if the ping fails
 func (s *MCPServer) HealthCheck() error {
+	return s.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext is HealthCheck with the ping bounded by ctx as well as
+// the usual 5 second timeout
+func (s *MCPServer) HealthCheckContext(ctx context.Context) error {
 	s.LastHealthCheck = time.Now()
 
 	// THREAD-SAFETY: Use getter for state check
@@ -382,32 +409,32 @@ func (s *MCPServer) HealthCheck() error {
 
 	// If connection state is disconnected, report as disconnected
 	if currentState == StateDisconnected {
-		s.HealthStatus = HealthDisconnected
+		s.setHealth(HealthDisconnected, "")
 		return nil
 	}
 
 	// If a client is configured, use it to ping the server
 	if s.client != nil && currentState == StateConnected {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
 
 		err := s.client.Ping(ctx)
 		if err != nil {
-			s.HealthStatus = HealthUnhealthy
+			s.setHealth(HealthUnhealthy, fmt.Sprintf("ping failed: %v", err))
 			// THREAD-SAFETY: Use setters for error tracking
 			s.Connection.SetLastError(fmt.Sprintf("ping failed: %v", err))
 			s.Connection.IncrementErrorCount()
 			return fmt.Errorf("health check failed: %w", err)
 		}
 
-		s.HealthStatus = HealthHealthy
+		s.setHealth(HealthHealthy, "")
 		// THREAD-SAFETY: Use UpdateLastActivity method
 		s.Connection.UpdateLastActivity()
 		return nil
 	}
 
 	// For mock/testing scenarios without a client, mark as healthy if connected
-	s.HealthStatus = HealthHealthy
+	s.setHealth(HealthHealthy, "")
 	// THREAD-SAFETY: Use UpdateLastActivity method
 	s.Connection.UpdateLastActivity()
 
@@ -416,9 +443,30 @@ func (s *MCPServer) HealthCheck() error {
 
 // RecordUnhealthy marks the server as unhealthy
 func (s *MCPServer) RecordUnhealthy(errorMsg string) {
-	s.HealthStatus = HealthUnhealthy
+	s.setHealth(HealthUnhealthy, errorMsg)
 	s.LastHealthCheck = time.Now()
 	// THREAD-SAFETY: Use setters for error tracking
 	s.Connection.SetLastError(errorMsg)
 	s.Connection.IncrementErrorCount()
 }
+
+// setHealth updates the health status and publishes the change, if any, on
+// the default event bus.
+func (s *MCPServer) setHealth(status HealthStatus, errorMsg string) {
+	previous := s.HealthStatus
+	s.HealthStatus = status
+	if previous == status {
+		return
+	}
+	events.Default().Publish(events.Event{
+		Topic:  events.TopicServer,
+		Type:   events.TypeServerHealthChanged,
+		Source: s.ID,
+		Payload: events.ServerHealthChange{
+			ServerID: s.ID,
+			Previous: string(previous),
+			Current:  string(status),
+			Error:    errorMsg,
+		},
+	})
+}
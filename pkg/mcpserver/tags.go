@@ -0,0 +1,90 @@
+package mcpserver
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tagPattern matches a server tag: lowercase letters, digits, dashes and
+// underscores, e.g. "prod" or "db"
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// NormalizeTags lowercases, deduplicates and sorts server tags, rejecting
+// ones that are not valid tags.
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, NewValidationError(fmt.Sprintf("invalid server tag %q (use letters, digits, dashes and underscores)", tag))
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// HasTags reports whether the server carries every one of tags
+func (s *MCPServer) HasTags(tags ...string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, own := range s.Tags {
+			if own == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveAlias finds the one registered server carrying all of tags, for a
+// workflow that refers to a logical server alias (such as "db") instead of
+// a server ID. It is an error for no server, or more than one, to match.
+func ResolveAlias(repo ServerRepository, alias string, tags []string) (*MCPServer, error) {
+	tags, err := NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return nil, NewValidationError(fmt.Sprintf("server alias %s has no tags", alias))
+	}
+
+	servers, err := repo.List()
+	if err != nil {
+		return nil, err
+	}
+	var matches []*MCPServer
+	for _, server := range servers {
+		if server.HasTags(tags...) {
+			matches = append(matches, server)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, NewValidationError(fmt.Sprintf("server alias %s: no registered server is tagged %s", alias, strings.Join(tags, ", ")))
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, server := range matches {
+			ids[i] = server.ID
+		}
+		sort.Strings(ids)
+		return nil, NewValidationError(fmt.Sprintf("server alias %s: servers %s are all tagged %s; add a tag to choose one",
+			alias, strings.Join(ids, ", "), strings.Join(tags, ", ")))
+	}
+}
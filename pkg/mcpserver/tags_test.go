@@ -0,0 +1,60 @@
+package mcpserver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{"Prod", " db ", "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "prod"}, tags)
+
+	_, err = NormalizeTags([]string{"web server"})
+	assert.Error(t, err)
+}
+
+func TestResolveAlias(t *testing.T) {
+	registry := NewRegistry()
+	for id, tags := range map[string][]string{
+		"db-dev":  {"db", "dev"},
+		"db-prod": {"db", "prod"},
+		"web":     {"web", "prod"},
+	} {
+		server, err := NewMCPServer(id, "mcp-"+id, nil, TransportStdio)
+		require.NoError(t, err)
+		server.Tags = tags
+		require.NoError(t, registry.Register(server))
+	}
+
+	server, err := ResolveAlias(registry, "db", []string{"db", "PROD"})
+	require.NoError(t, err)
+	assert.Equal(t, "db-prod", server.ID)
+
+	_, err = ResolveAlias(registry, "db", []string{"db"})
+	assert.ErrorContains(t, err, "db-dev, db-prod", "ambiguous aliases name the candidates")
+	_, err = ResolveAlias(registry, "cache", []string{"cache"})
+	assert.ErrorContains(t, err, "no registered server")
+	_, err = ResolveAlias(registry, "any", nil)
+	assert.Error(t, err)
+}
+
+func TestFileRepository_Tags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	repo, err := NewFileRepository(path)
+	require.NoError(t, err)
+
+	server, err := NewMCPServer("db-prod", "postgres-mcp", nil, TransportStdio)
+	require.NoError(t, err)
+	server.Tags = []string{"db", "prod"}
+	require.NoError(t, repo.Register(server))
+
+	reloaded, err := NewFileRepository(path)
+	require.NoError(t, err)
+	got, err := reloaded.Get("db-prod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "prod"}, got.Tags)
+}
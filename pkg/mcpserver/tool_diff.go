@@ -0,0 +1,224 @@
+package mcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ToolChangeKind says how a tool changed between two discoveries
+type ToolChangeKind string
+
+// Tool change kinds
+const (
+	ToolAdded   ToolChangeKind = "added"
+	ToolRemoved ToolChangeKind = "removed"
+	ToolChanged ToolChangeKind = "changed"
+)
+
+// ToolChange is a difference between the tools a server offered when last
+// discovered and the tools it offers now
+type ToolChange struct {
+	Tool    string
+	Kind    ToolChangeKind
+	Details []string // Parameter and schema changes of a changed tool
+}
+
+// Breaking reports whether workflows calling the tool may stop working
+func (c ToolChange) Breaking() bool {
+	return c.Kind != ToolAdded
+}
+
+// Summary describes the change without the tool name, such as "changed
+// (param path removed)"
+func (c ToolChange) Summary() string {
+	if len(c.Details) == 0 {
+		return string(c.Kind)
+	}
+	return fmt.Sprintf("%s (%s)", c.Kind, strings.Join(c.Details, "; "))
+}
+
+// String describes the change, such as "read_file changed (param path
+// removed)"
+func (c ToolChange) String() string {
+	return c.Tool + " " + c.Summary()
+}
+
+// DiffTools compares the tools of two discoveries of a server. Changes are
+// ordered by tool name; descriptions are not compared.
+func DiffTools(previous, current []Tool) []ToolChange {
+	before := make(map[string]Tool, len(previous))
+	for _, tool := range previous {
+		before[tool.Name] = tool
+	}
+	after := make(map[string]Tool, len(current))
+	for _, tool := range current {
+		after[tool.Name] = tool
+	}
+
+	var changes []ToolChange
+	for name, tool := range after {
+		old, existed := before[name]
+		if !existed {
+			changes = append(changes, ToolChange{Tool: name, Kind: ToolAdded})
+			continue
+		}
+		if details := diffToolSchemas(old, tool); len(details) > 0 {
+			changes = append(changes, ToolChange{Tool: name, Kind: ToolChanged, Details: details})
+		}
+	}
+	for name := range before {
+		if _, exists := after[name]; !exists {
+			changes = append(changes, ToolChange{Tool: name, Kind: ToolRemoved})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Tool < changes[j].Tool })
+	return changes
+}
+
+// diffToolSchemas describes the parameter changes between two versions of a
+// tool, and whether its output schema changed
+func diffToolSchemas(old, tool Tool) []string {
+	var details []string
+	oldParams, oldRequired := schemaParams(old.InputSchema)
+	params, required := schemaParams(tool.InputSchema)
+
+	names := make([]string, 0, len(oldParams)+len(params))
+	for name := range oldParams {
+		names = append(names, name)
+	}
+	for name := range params {
+		if _, seen := oldParams[name]; !seen {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldParam, existed := oldParams[name]
+		param, exists := params[name]
+		switch {
+		case !exists:
+			details = append(details, fmt.Sprintf("param %s removed", name))
+		case !existed && required[name]:
+			details = append(details, fmt.Sprintf("param %s added (required)", name))
+		case !existed:
+			details = append(details, fmt.Sprintf("param %s added", name))
+		default:
+			if oldType, newType := paramType(oldParam), paramType(param); oldType != newType {
+				details = append(details, fmt.Sprintf("param %s type %s -> %s", name, oldType, newType))
+			}
+			if required[name] && !oldRequired[name] {
+				details = append(details, fmt.Sprintf("param %s now required", name))
+			} else if !required[name] && oldRequired[name] {
+				details = append(details, fmt.Sprintf("param %s now optional", name))
+			}
+		}
+	}
+
+	if !sameSchema(old.OutputSchema, tool.OutputSchema) {
+		details = append(details, "output schema changed")
+	}
+	return details
+}
+
+// sameSchema compares schemas by their JSON form, so a schema read back
+// from the tool schemas file equals the one it was saved from
+func sameSchema(a, b *ToolSchema) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// schemaParams returns the properties of an input schema and the set of
+// required ones
+func schemaParams(schema *ToolSchema) (map[string]interface{}, map[string]bool) {
+	if schema == nil {
+		return nil, nil
+	}
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	return schema.Properties, required
+}
+
+// paramType returns the JSON schema type of a property, "any" when it has
+// none
+func paramType(property interface{}) string {
+	prop, ok := property.(map[string]interface{})
+	if !ok {
+		return "any"
+	}
+	switch t := prop["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			types = append(types, fmt.Sprint(v))
+		}
+		slices.Sort(types)
+		return strings.Join(types, "|")
+	default:
+		return "any"
+	}
+}
+
+// KnownTools is the tool schemas file (by default ~/.goflow/tool_schemas.json)
+// that records the tools each server offered when last discovered, so a
+// rediscovery can tell what changed
+type KnownTools struct {
+	Servers map[string][]Tool `json:"servers"`
+}
+
+// LoadKnownTools reads a tool schemas file. A missing file knows no tools.
+func LoadKnownTools(path string) (*KnownTools, error) {
+	known := &KnownTools{Servers: make(map[string][]Tool)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return known, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool schemas: %w", err)
+	}
+	if err := json.Unmarshal(data, known); err != nil {
+		return nil, fmt.Errorf("failed to parse tool schemas %s: %w", path, err)
+	}
+	if known.Servers == nil {
+		known.Servers = make(map[string][]Tool)
+	}
+	return known, nil
+}
+
+// Save writes the tool schemas file atomically
+func (k *KnownTools) Save(path string) error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool schemas: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Diff compares a server's tools with those it offered when last
+// discovered. A server that was never discovered has no changes.
+func (k *KnownTools) Diff(serverID string, tools []Tool) []ToolChange {
+	previous, known := k.Servers[serverID]
+	if !known {
+		return nil
+	}
+	return DiffTools(previous, tools)
+}
+
+// Update records a server's tools and returns how they changed since it
+// was last discovered
+func (k *KnownTools) Update(serverID string, tools []Tool) []ToolChange {
+	changes := k.Diff(serverID, tools)
+	k.Servers[serverID] = slices.Clone(tools)
+	return changes
+}
@@ -0,0 +1,80 @@
+package mcpserver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaTool returns a tool whose input schema has the given property types
+func schemaTool(name string, params map[string]string, required ...string) Tool {
+	schema := &ToolSchema{Type: "object", Properties: make(map[string]interface{}), Required: required}
+	for param, typ := range params {
+		schema.Properties[param] = map[string]interface{}{"type": typ}
+	}
+	return Tool{Name: name, InputSchema: schema}
+}
+
+func TestDiffTools(t *testing.T) {
+	previous := []Tool{
+		schemaTool("read_file", map[string]string{"path": "string", "encoding": "string"}, "path"),
+		schemaTool("list_dir", map[string]string{"path": "string"}),
+		schemaTool("stat", map[string]string{"path": "string"}),
+	}
+	current := []Tool{
+		schemaTool("read_file", map[string]string{"path": "array", "limit": "integer"}, "path", "limit"),
+		schemaTool("list_dir", map[string]string{"path": "string"}, "path"),
+		schemaTool("write_file", map[string]string{"path": "string"}),
+	}
+	current[1].Description = "Descriptions are not compared"
+
+	changes := DiffTools(previous, current)
+	require.Len(t, changes, 4)
+
+	assert.Equal(t, ToolChange{Tool: "list_dir", Kind: ToolChanged, Details: []string{"param path now required"}}, changes[0])
+	assert.Equal(t, ToolChange{Tool: "read_file", Kind: ToolChanged, Details: []string{
+		"param encoding removed",
+		"param limit added (required)",
+		"param path type string -> array",
+	}}, changes[1])
+	assert.Equal(t, ToolChange{Tool: "stat", Kind: ToolRemoved}, changes[2])
+	assert.Equal(t, ToolChange{Tool: "write_file", Kind: ToolAdded}, changes[3])
+
+	assert.True(t, changes[2].Breaking())
+	assert.False(t, changes[3].Breaking())
+	assert.Equal(t, "stat removed", changes[2].String())
+	assert.Equal(t, "list_dir changed (param path now required)", changes[0].String())
+
+	assert.Empty(t, DiffTools(previous, previous))
+}
+
+func TestDiffTools_OutputSchema(t *testing.T) {
+	previous := []Tool{{Name: "query", OutputSchema: NewToolSchema("object")}}
+	current := []Tool{{Name: "query", OutputSchema: NewToolSchema("array")}}
+
+	changes := DiffTools(previous, current)
+	require.Len(t, changes, 1)
+	assert.Equal(t, []string{"output schema changed"}, changes[0].Details)
+}
+
+func TestKnownTools_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool_schemas.json")
+
+	known, err := LoadKnownTools(path)
+	require.NoError(t, err)
+	tools := []Tool{schemaTool("read_file", map[string]string{"path": "string"}, "path")}
+	assert.Empty(t, known.Update("fs", tools), "the first discovery has nothing to compare with")
+	require.NoError(t, known.Save(path))
+
+	loaded, err := LoadKnownTools(path)
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Diff("fs", tools))
+	assert.Nil(t, loaded.Diff("db", tools))
+
+	changes := loaded.Update("fs", nil)
+	require.Len(t, changes, 1)
+	assert.Equal(t, ToolRemoved, changes[0].Kind)
+	assert.Empty(t, loaded.Diff("fs", nil))
+}
@@ -0,0 +1,163 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the auth config file naming who may call the APIs, by token,
+// by OpenID Connect, or both:
+//
+//	tokens:
+//	  - name: ci
+//	    role: operator
+//	    sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	oidc:
+//	  issuer: https://login.example.com
+//	  audience: goflow
+type Config struct {
+	Tokens []Token     `yaml:"tokens,omitempty"`
+	OIDC   *OIDCConfig `yaml:"oidc,omitempty"`
+}
+
+// DefaultConfigPath returns the auth config path used by goflow token:
+// $GOFLOW_CONFIG_DIR/auth.yaml, or ~/.goflow/auth.yaml
+func DefaultConfigPath() string {
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "auth.yaml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "auth.yaml")
+	}
+	return filepath.Join(home, ".goflow", "auth.yaml")
+}
+
+// LoadConfig reads an auth config file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse auth config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// LoadOrCreateConfig reads an auth config file; a missing file is an
+// empty config, so the first token can be created
+func LoadOrCreateConfig(path string) (*Config, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	return LoadConfig(path)
+}
+
+// Save writes the auth config file atomically, readable only by its owner
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal auth config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create auth config directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write auth config: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write auth config: %w", err)
+	}
+	return nil
+}
+
+// Authenticator returns an authenticator accepting the config's tokens,
+// then its OIDC provider's tokens, fetched with client (nil for a default).
+// A config with neither is an error, since it would lock everyone out.
+func (c *Config) Authenticator(client *http.Client) (Authenticator, error) {
+	var authenticators Authenticators
+	if len(c.Tokens) > 0 {
+		tokens, err := NewTokenAuthenticator(c.Tokens)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth config: %w", err)
+		}
+		authenticators = append(authenticators, tokens)
+	}
+	if c.OIDC != nil {
+		oidc, err := NewOIDCAuthenticator(*c.OIDC, client)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth config: %w", err)
+		}
+		authenticators = append(authenticators, oidc)
+	}
+	if len(authenticators) == 0 {
+		return nil, errors.New("invalid auth config: it lists no tokens and no oidc provider")
+	}
+	return authenticators, nil
+}
+
+// FileAuthenticator authenticates by an auth config file, reloading it when
+// it changes, so tokens created or revoked apply to APIs already serving.
+// If a changed file is invalid, every request is refused until it is fixed.
+type FileAuthenticator struct {
+	path   string
+	client *http.Client
+
+	mu      sync.Mutex
+	modTime time.Time
+	current Authenticator
+	err     error
+}
+
+// NewFileAuthenticator creates an authenticator for the auth config at
+// path, fetching OIDC keys with client (nil for a default)
+func NewFileAuthenticator(path string, client *http.Client) (*FileAuthenticator, error) {
+	a := &FileAuthenticator{path: path, client: client}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+	if err := a.load(info.ModTime()); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// load reads the config, remembering the modification time it was read at
+func (a *FileAuthenticator) load(modTime time.Time) error {
+	a.modTime = modTime
+	config, err := LoadConfig(a.path)
+	if err == nil {
+		a.current, err = config.Authenticator(a.client)
+	}
+	a.err = err
+	return err
+}
+
+// Authenticate implements Authenticator
+func (a *FileAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	a.mu.Lock()
+	if info, err := os.Stat(a.path); err != nil {
+		a.modTime, a.err = time.Time{}, fmt.Errorf("failed to read auth config: %w", err)
+	} else if !info.ModTime().Equal(a.modTime) {
+		_ = a.load(info.ModTime()) // Error kept in a.err
+	}
+	current, err := a.current, a.err
+	a.mu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: the auth config cannot be used", ErrInvalidCredentials)
+	}
+	return current.Authenticate(r)
+}
@@ -0,0 +1,96 @@
+package rbac
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNoCredentials means the request carries no credentials the
+	// authenticator understands
+	ErrNoCredentials = errors.New("authentication required")
+	// ErrInvalidCredentials means the request's credentials were rejected
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator identifies the caller of a request. It returns an error
+// wrapping ErrNoCredentials when the request has no credentials it
+// understands, so another authenticator can try, and one wrapping
+// ErrInvalidCredentials when it rejects them.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Authenticators tries each authenticator in turn, accepting the first
+// that identifies the caller
+type Authenticators []Authenticator
+
+// Authenticate implements Authenticator. If none identifies the caller,
+// a rejection is reported in preference to missing credentials.
+func (a Authenticators) Authenticate(r *http.Request) (*Principal, error) {
+	err := ErrNoCredentials
+	for _, authenticator := range a {
+		principal, authErr := authenticator.Authenticate(r)
+		if authErr == nil {
+			return principal, nil
+		}
+		if !errors.Is(authErr, ErrNoCredentials) {
+			err = authErr
+		}
+	}
+	return nil, err
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header, or ""
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Always requires the same permission for every request
+func Always(permission Permission) func(*http.Request) Permission {
+	return func(*http.Request) Permission { return permission }
+}
+
+// ByMethod requires read for GET and HEAD requests and write for the rest
+func ByMethod(read, write Permission) func(*http.Request) Permission {
+	return func(r *http.Request) Permission {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return read
+		}
+		return write
+	}
+}
+
+// Require serves next only to callers authenticator identifies whose role
+// grants the permission the request needs. Others are answered 401, or 403
+// when authenticated without the permission, with a JSON error. Handlers
+// find the caller with PrincipalFrom.
+func Require(authenticator Authenticator, permission func(*http.Request) Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="goflow"`)
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		needed := permission(r)
+		if !principal.Can(needed) {
+			writeError(w, http.StatusForbidden, string(principal.Role)+" role lacks the "+string(needed)+" permission")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// writeError writes a JSON error response with status
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message}) // Error ignored: the client has gone away
+}
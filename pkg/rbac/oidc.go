@@ -0,0 +1,271 @@
+package rbac
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRoleClaim is the ID token claim holding the caller's role
+	defaultRoleClaim = "goflow_role"
+	// clockSkew is how far token times may be off from ours
+	clockSkew = time.Minute
+	// jwksRefreshInterval limits how often an unknown key ID refetches
+	// the provider's keys
+	jwksRefreshInterval = time.Minute
+)
+
+// OIDCConfig configures authentication by an OpenID Connect provider's
+// RS256-signed tokens:
+//
+//	oidc:
+//	  issuer: https://login.example.com
+//	  audience: goflow
+//	  role_claim: goflow_role
+type OIDCConfig struct {
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// RoleClaim names the claim holding the role, or a list whose first
+	// known role is used (default goflow_role)
+	RoleClaim string `yaml:"role_claim,omitempty"`
+	// DefaultRole is given to tokens without a role; empty rejects them
+	DefaultRole Role `yaml:"default_role,omitempty"`
+}
+
+// OIDCAuthenticator authenticates "Authorization: Bearer" tokens signed by
+// an OpenID Connect provider. The provider's keys are found by discovery on
+// first use and refetched when a token names an unknown key.
+type OIDCAuthenticator struct {
+	config OIDCConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// NewOIDCAuthenticator creates an authenticator for the provider in config,
+// fetching its discovery document and keys with client (nil uses a client
+// with a 10 second timeout)
+func NewOIDCAuthenticator(config OIDCConfig, client *http.Client) (*OIDCAuthenticator, error) {
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, errors.New("oidc needs an issuer and an audience")
+	}
+	if config.RoleClaim == "" {
+		config.RoleClaim = defaultRoleClaim
+	}
+	if config.DefaultRole != "" {
+		if _, err := ParseRole(string(config.DefaultRole)); err != nil {
+			return nil, fmt.Errorf("oidc default_role: %w", err)
+		}
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCAuthenticator{config: config, client: client, now: time.Now}, nil
+}
+
+// Authenticate implements Authenticator. Bearer tokens that aren't JWTs are
+// left for other authenticators.
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+	if strings.Count(token, ".") != 2 {
+		return nil, ErrNoCredentials
+	}
+	claims, err := a.verify(r.Context(), token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	return a.principal(claims)
+}
+
+// verify checks the token's signature, issuer, audience and validity
+// period, returning its claims
+func (a *OIDCAuthenticator) verify(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	key, err := a.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("bad token signature")
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); iss != a.config.Issuer {
+		return nil, fmt.Errorf("token issued by %q", iss)
+	}
+	if !hasAudience(claims["aud"], a.config.Audience) {
+		return nil, fmt.Errorf("token is not for audience %q", a.config.Audience)
+	}
+	now := a.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return nil, errors.New("token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("token is not valid yet")
+	}
+	return claims, nil
+}
+
+// principal returns the caller the verified claims identify
+func (a *OIDCAuthenticator) principal(claims map[string]any) (*Principal, error) {
+	name, _ := claims["email"].(string)
+	if name == "" {
+		name, _ = claims["sub"].(string)
+	}
+	var candidates []any
+	switch value := claims[a.config.RoleClaim].(type) {
+	case string:
+		candidates = []any{value}
+	case []any:
+		candidates = value
+	}
+	for _, candidate := range candidates {
+		if s, ok := candidate.(string); ok {
+			if role, err := ParseRole(s); err == nil {
+				return &Principal{Name: name, Role: role}, nil
+			}
+		}
+	}
+	if a.config.DefaultRole != "" {
+		return &Principal{Name: name, Role: a.config.DefaultRole}, nil
+	}
+	return nil, fmt.Errorf("%w: token has no known role in its %s claim", ErrInvalidCredentials, a.config.RoleClaim)
+}
+
+// key returns the provider's public key with ID kid, fetching the keys if
+// they haven't been or kid is unknown and they weren't fetched recently
+func (a *OIDCAuthenticator) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	if a.keys != nil && a.now().Sub(a.fetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	keys, err := a.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.keys, a.fetched = keys, a.now()
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// fetchKeys reads the provider's discovery document and its RSA keys
+func (a *OIDCAuthenticator) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := a.getJSON(ctx, strings.TrimSuffix(a.config.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if discovery.Issuer != a.config.Issuer {
+		return nil, fmt.Errorf("oidc discovery names issuer %q, want %q", discovery.Issuer, a.config.Issuer)
+	}
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := a.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+// getJSON fetches url and decodes its JSON body into v
+func (a *OIDCAuthenticator) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// decodeSegment decodes a base64url JWT segment as JSON into v
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// hasAudience reports whether the aud claim, a string or a list, includes
+// audience
+func hasAudience(aud any, audience string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == audience
+	case []any:
+		for _, a := range value {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package rbac
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testProvider is an OpenID Connect provider serving discovery and one
+// RSA key, and signing tokens with it
+type testProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProvider{key: key, kid: "key-1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": p.server.URL, "jwks_uri": p.server.URL + "/keys"})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": p.kid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// sign returns an RS256 JWT of claims signed with key
+func (p *testProvider) sign(t *testing.T, key *rsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": p.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	p := newTestProvider(t)
+	authenticator, err := NewOIDCAuthenticator(OIDCConfig{Issuer: p.server.URL, Audience: "goflow"}, p.server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	claims := func(changes map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":         p.server.URL,
+			"aud":         []string{"goflow", "other"},
+			"sub":         "u-123",
+			"email":       "ada@example.com",
+			"exp":         now.Add(time.Hour).Unix(),
+			"goflow_role": "operator",
+		}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	principal, err := authenticator.Authenticate(newRequest(http.MethodGet, p.sign(t, p.key, claims(nil))))
+	if err != nil || principal.Name != "ada@example.com" || principal.Role != RoleOperator {
+		t.Fatalf("Authenticate() = %+v, %v", principal, err)
+	}
+	principal, err = authenticator.Authenticate(newRequest(http.MethodGet, p.sign(t, p.key, claims(map[string]any{"email": nil, "goflow_role": []string{"staff", "editor"}}))))
+	if err != nil || principal.Name != "u-123" || principal.Role != RoleEditor {
+		t.Errorf("Authenticate() with a role list = %+v, %v", principal, err)
+	}
+
+	rejected := map[string]string{
+		"wrong key":      p.sign(t, otherKey, claims(nil)),
+		"wrong issuer":   p.sign(t, p.key, claims(map[string]any{"iss": "https://evil.example.com"})),
+		"wrong audience": p.sign(t, p.key, claims(map[string]any{"aud": "billing"})),
+		"expired":        p.sign(t, p.key, claims(map[string]any{"exp": now.Add(-time.Hour).Unix()})),
+		"not yet valid":  p.sign(t, p.key, claims(map[string]any{"nbf": now.Add(time.Hour).Unix()})),
+		"no role":        p.sign(t, p.key, claims(map[string]any{"goflow_role": nil})),
+	}
+	for name, token := range rejected {
+		if _, err := authenticator.Authenticate(newRequest(http.MethodGet, token)); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("%s: Authenticate() = %v, want invalid credentials", name, err)
+		}
+	}
+
+	// Bearer tokens that aren't JWTs are left to other authenticators
+	if _, err := authenticator.Authenticate(newRequest(http.MethodGet, "opaque-token")); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() of an opaque token = %v, want no credentials", err)
+	}
+}
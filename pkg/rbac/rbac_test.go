@@ -0,0 +1,226 @@
+package rbac
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoles(t *testing.T) {
+	tests := []struct {
+		role    Role
+		allowed []Permission
+	}{
+		{RoleViewer, []Permission{View}},
+		{RoleEditor, []Permission{View, EditWorkflows}},
+		{RoleOperator, []Permission{View, OperateExecutions}},
+		{RoleAdmin, []Permission{View, EditWorkflows, ManageServers, OperateExecutions, ViewAudit}},
+		{Role("guest"), nil},
+	}
+	for _, tt := range tests {
+		for _, permission := range []Permission{View, EditWorkflows, ManageServers, OperateExecutions, ViewAudit} {
+			want := false
+			for _, allowed := range tt.allowed {
+				want = want || allowed == permission
+			}
+			if got := tt.role.Can(permission); got != want {
+				t.Errorf("%s.Can(%s) = %v, want %v", tt.role, permission, got, want)
+			}
+		}
+	}
+	if _, err := ParseRole("guest"); err == nil {
+		t.Error("expected an unknown role to be rejected")
+	}
+}
+
+// newRequest returns a GET or POST request with the bearer token, if any
+func newRequest(method, token string) *http.Request {
+	r := httptest.NewRequest(method, "/approvals", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestRequire(t *testing.T) {
+	tokens, err := NewTokenAuthenticator([]Token{
+		{Name: "dashboard", Role: RoleViewer, SHA256: HashToken("view-token")},
+		{Name: "ci", Role: RoleOperator, SHA256: HashToken("operate-token")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var caller string
+	handler := Require(tokens, ByMethod(View, OperateExecutions), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ := PrincipalFrom(r.Context())
+		caller = principal.Name
+	}))
+
+	tests := []struct {
+		name, method, token string
+		want                int
+		caller              string
+	}{
+		{"no token", http.MethodGet, "", http.StatusUnauthorized, ""},
+		{"unknown token", http.MethodGet, "guess", http.StatusUnauthorized, ""},
+		{"viewer reads", http.MethodGet, "view-token", http.StatusOK, "dashboard"},
+		{"viewer decides", http.MethodPost, "view-token", http.StatusForbidden, ""},
+		{"operator decides", http.MethodPost, "operate-token", http.StatusOK, "ci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller = ""
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newRequest(tt.method, tt.token))
+			if w.Code != tt.want || caller != tt.caller {
+				t.Errorf("status %d, caller %q; want %d, %q", w.Code, caller, tt.want, tt.caller)
+			}
+			if tt.want == http.StatusUnauthorized && !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "Bearer") {
+				t.Error("expected a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestAuthenticators(t *testing.T) {
+	viewers, _ := NewTokenAuthenticator([]Token{{Name: "a", Role: RoleViewer, SHA256: HashToken("a")}})
+	admins, _ := NewTokenAuthenticator([]Token{{Name: "b", Role: RoleAdmin, SHA256: HashToken("b")}})
+	chain := Authenticators{viewers, admins}
+
+	if principal, err := chain.Authenticate(newRequest(http.MethodGet, "b")); err != nil || principal.Role != RoleAdmin {
+		t.Errorf("Authenticate() = %+v, %v; want the second authenticator's admin", principal, err)
+	}
+	if _, err := chain.Authenticate(newRequest(http.MethodGet, "c")); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate() of an unknown token = %v, want invalid credentials", err)
+	}
+	if _, err := chain.Authenticate(newRequest(http.MethodGet, "")); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() without a token = %v, want no credentials", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	config, err := LoadConfig(write("auth.yaml", "tokens:\n  - name: ci\n    role: operator\n    sha256: "+HashToken("secret")+"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := config.Authenticator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if principal, err := authenticator.Authenticate(newRequest(http.MethodPost, "secret")); err != nil || principal.Name != "ci" {
+		t.Errorf("Authenticate() = %+v, %v", principal, err)
+	}
+
+	invalid := map[string]string{
+		"empty.yaml":     "tokens: []\n",
+		"role.yaml":      "tokens:\n  - name: ci\n    role: root\n    sha256: " + HashToken("x") + "\n",
+		"hash.yaml":      "tokens:\n  - name: ci\n    role: viewer\n    sha256: plaintext\n",
+		"duplicate.yaml": "tokens:\n  - {name: ci, role: viewer, sha256: " + HashToken("x") + "}\n  - {name: ci, role: admin, sha256: " + HashToken("y") + "}\n",
+		"oidc.yaml":      "oidc:\n  issuer: https://login.example.com\n",
+	}
+	for name, content := range invalid {
+		config, err := LoadConfig(write(name, content))
+		if err == nil {
+			_, err = config.Authenticator(nil)
+		}
+		if err == nil {
+			t.Errorf("%s: expected an invalid config error", name)
+		}
+	}
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected a missing auth config to be an error")
+	}
+}
+
+func TestConfig_Tokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.yaml")
+	config, err := LoadOrCreateConfig(path)
+	if err != nil || len(config.Tokens) != 0 {
+		t.Fatalf("LoadOrCreateConfig() of a missing file = %+v, %v", config, err)
+	}
+
+	bot, err := config.CreateToken("bot", RoleOperator, []Permission{OperateExecutions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(bot, tokenPrefix) {
+		t.Errorf("token %q lacks the %s prefix", bot, tokenPrefix)
+	}
+	if _, err := config.CreateToken("bot", RoleViewer, nil); err == nil {
+		t.Error("expected a duplicate token name to be rejected")
+	}
+	if _, err := config.CreateToken("greedy", RoleViewer, []Permission{ManageServers}); err == nil {
+		t.Error("expected a scope beyond the role to be rejected")
+	}
+	admin, err := config.CreateToken("admin", RoleAdmin, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), bot) {
+		t.Error("expected only the token's hash to be saved")
+	}
+
+	// Scopes narrow the role: the bot may operate but not view
+	authenticator, err := NewFileAuthenticator(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, err := authenticator.Authenticate(newRequest(http.MethodPost, bot))
+	if err != nil || !principal.Can(OperateExecutions) || principal.Can(View) {
+		t.Errorf("bot = %+v, %v; want operate_executions only", principal, err)
+	}
+
+	// Revoking applies to the running authenticator
+	config, err = LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.RevokeToken("bot"); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.RevokeToken("bot"); err == nil {
+		t.Error("expected revoking a missing token to fail")
+	}
+	if err := config.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authenticator.Authenticate(newRequest(http.MethodPost, bot)); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("revoked token authenticated: %v", err)
+	}
+	if principal, err := authenticator.Authenticate(newRequest(http.MethodGet, admin)); err != nil || !principal.Can(ViewAudit) {
+		t.Errorf("admin = %+v, %v", principal, err)
+	}
+
+	// A broken config refuses everyone rather than the old tokens applying
+	if err := os.WriteFile(path, []byte("tokens: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	future = future.Add(time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authenticator.Authenticate(newRequest(http.MethodGet, admin)); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate() with a broken config = %v, want invalid credentials", err)
+	}
+}
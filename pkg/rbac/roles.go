@@ -0,0 +1,103 @@
+// Package rbac controls who may use GoFlow's HTTP APIs. Callers are
+// authenticated by a pluggable Authenticator (a token file, OIDC, or both)
+// and given a role; each role grants a set of permissions, which Require
+// checks before a request reaches the API.
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Role is what a caller may do, as a named set of permissions
+type Role string
+
+const (
+	// RoleViewer reads workflows, executions and metrics
+	RoleViewer Role = "viewer"
+	// RoleEditor also creates and changes workflows
+	RoleEditor Role = "editor"
+	// RoleOperator also starts and cancels executions and decides approvals
+	RoleOperator Role = "operator"
+	// RoleAdmin may do everything, including managing MCP servers and
+	// reading the audit log
+	RoleAdmin Role = "admin"
+)
+
+// Permission is an action a role may be allowed
+type Permission string
+
+const (
+	// View reads workflows, executions, approvals and server metrics
+	View Permission = "view"
+	// EditWorkflows creates, changes and deletes workflows
+	EditWorkflows Permission = "edit_workflows"
+	// ManageServers registers, changes and removes MCP servers
+	ManageServers Permission = "manage_servers"
+	// OperateExecutions starts and cancels executions and decides approvals
+	OperateExecutions Permission = "operate_executions"
+	// ViewAudit reads the audit log
+	ViewAudit Permission = "view_audit"
+)
+
+// rolePermissions lists the permissions each role grants
+var rolePermissions = map[Role][]Permission{
+	RoleViewer:   {View},
+	RoleEditor:   {View, EditWorkflows},
+	RoleOperator: {View, OperateExecutions},
+	RoleAdmin:    {View, EditWorkflows, ManageServers, OperateExecutions, ViewAudit},
+}
+
+// ParsePermission returns the permission named s
+func ParsePermission(s string) (Permission, error) {
+	permission := Permission(s)
+	if !RoleAdmin.Can(permission) {
+		return "", fmt.Errorf("unknown permission %q: use view, edit_workflows, manage_servers, operate_executions or view_audit", s)
+	}
+	return permission, nil
+}
+
+// ParseRole returns the role named s
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if _, ok := rolePermissions[role]; !ok {
+		return "", fmt.Errorf("unknown role %q: use viewer, editor, operator or admin", s)
+	}
+	return role, nil
+}
+
+// Can reports whether the role grants the permission
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	Name   string // Token name, or the OIDC subject's email or ID
+	Role   Role
+	Scopes []Permission // Narrows the role's permissions when not empty
+}
+
+// Can reports whether the caller's role grants the permission, and its
+// scopes, if any, include it
+func (p *Principal) Can(permission Permission) bool {
+	if p == nil || !p.Role.Can(permission) {
+		return false
+	}
+	return len(p.Scopes) == 0 || slices.Contains(p.Scopes, permission)
+}
+
+// principalKey is the context key of the authenticated caller
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the caller Require authenticated, if any
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,143 @@
+package rbac
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// tokenPrefix marks generated tokens, so leaked ones are easy to spot
+const tokenPrefix = "gft_"
+
+// Token is an API token in the auth config. Only the token's SHA-256 is
+// stored, so the file doesn't hold usable secrets. Scopes, if listed,
+// narrow the role to those permissions:
+//
+//	tokens:
+//	  - name: ci
+//	    role: operator
+//	    scopes: [operate_executions]
+//	    sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+type Token struct {
+	Name    string       `yaml:"name"`
+	Role    Role         `yaml:"role"`
+	Scopes  []Permission `yaml:"scopes,omitempty"`
+	SHA256  string       `yaml:"sha256"`
+	Created time.Time    `yaml:"created,omitempty"`
+}
+
+// GenerateToken returns a new random API token
+func GenerateToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashToken returns the SHA-256 to store in the auth config for token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenAuthenticator authenticates "Authorization: Bearer" tokens listed in
+// the auth config
+type TokenAuthenticator struct {
+	tokens []Token
+	hashes [][]byte
+}
+
+// NewTokenAuthenticator creates an authenticator for tokens, checking each
+// has a name, a known role and a well-formed hash
+func NewTokenAuthenticator(tokens []Token) (*TokenAuthenticator, error) {
+	a := &TokenAuthenticator{tokens: tokens}
+	names := make(map[string]bool, len(tokens))
+	for i, token := range tokens {
+		if token.Name == "" {
+			return nil, fmt.Errorf("token %d has no name", i+1)
+		}
+		if names[token.Name] {
+			return nil, fmt.Errorf("token %s is listed twice", token.Name)
+		}
+		names[token.Name] = true
+		if err := token.validate(); err != nil {
+			return nil, err
+		}
+		hash, err := hex.DecodeString(token.SHA256)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("token %s: sha256 must be 64 hex digits", token.Name)
+		}
+		a.hashes = append(a.hashes, hash)
+	}
+	return a, nil
+}
+
+// Authenticate implements Authenticator
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+	sum := sha256.Sum256([]byte(token))
+	for i, hash := range a.hashes {
+		if subtle.ConstantTimeCompare(sum[:], hash) == 1 {
+			token := a.tokens[i]
+			return &Principal{Name: token.Name, Role: token.Role, Scopes: token.Scopes}, nil
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// validate checks the token's role, and that its role grants its scopes
+func (t Token) validate() error {
+	if _, err := ParseRole(string(t.Role)); err != nil {
+		return fmt.Errorf("token %s: %w", t.Name, err)
+	}
+	for _, scope := range t.Scopes {
+		if _, err := ParsePermission(string(scope)); err != nil {
+			return fmt.Errorf("token %s: %w", t.Name, err)
+		}
+		if !t.Role.Can(scope) {
+			return fmt.Errorf("token %s: the %s role does not grant the %s scope", t.Name, t.Role, scope)
+		}
+	}
+	return nil
+}
+
+// CreateToken adds a token named name with role and scopes to the config,
+// returning the token; only its hash is kept
+func (c *Config) CreateToken(name string, role Role, scopes []Permission) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("token name is required")
+	}
+	if slices.ContainsFunc(c.Tokens, func(t Token) bool { return t.Name == name }) {
+		return "", fmt.Errorf("token %s already exists", name)
+	}
+	secret, err := GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	token := Token{Name: name, Role: role, Scopes: scopes, SHA256: HashToken(secret), Created: time.Now().UTC().Truncate(time.Second)}
+	if err := token.validate(); err != nil {
+		return "", err
+	}
+	c.Tokens = append(c.Tokens, token)
+	return secret, nil
+}
+
+// RevokeToken removes the token named name from the config
+func (c *Config) RevokeToken(name string) error {
+	i := slices.IndexFunc(c.Tokens, func(t Token) bool { return t.Name == name })
+	if i < 0 {
+		return fmt.Errorf("token %s not found", name)
+	}
+	c.Tokens = slices.Delete(c.Tokens, i, i+1)
+	return nil
+}
@@ -0,0 +1,413 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// Identity used for commits when git has no user configured
+const (
+	gitFallbackName  = "GoFlow"
+	gitFallbackEmail = "goflow@localhost"
+)
+
+// WorkflowRevision is a commit that changed a workflow file
+type WorkflowRevision struct {
+	Hash    string    // Full commit hash
+	Author  string    // Author name
+	Time    time.Time // Author time
+	Message string    // Subject line of the commit message
+}
+
+// ShortHash returns the abbreviated commit hash
+func (r WorkflowRevision) ShortHash() string {
+	if len(r.Hash) > 7 {
+		return r.Hash[:7]
+	}
+	return r.Hash
+}
+
+// GitWorkflowRepository implements WorkflowRepository on a git working
+// tree. Workflows are YAML files in a directory of the tree, and every save
+// and delete is committed, so each workflow's history is kept in git.
+// The git program must be on PATH.
+type GitWorkflowRepository struct {
+	dir      string     // Directory workflows are stored in
+	root     string     // Top level of the working tree
+	git      string     // Path to the git program
+	identity []string   // Options setting the commit identity, if git has none
+	mu       sync.Mutex // Serializes commits and guards files
+
+	// Workflow files don't store IDs, so a new one is generated each time a
+	// file is parsed. files remembers the file of every workflow ID the
+	// repository has returned or saved.
+	files map[string]string
+}
+
+// NewGitWorkflowRepository creates a repository storing workflows in dir.
+// If dir is not inside a git working tree, a new repository is
+// initialized there.
+func NewGitWorkflowRepository(dir string) (*GitWorkflowRepository, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return nil, fmt.Errorf("git not found: %w", err)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflows directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create workflows directory: %w", err)
+	}
+
+	r := &GitWorkflowRepository{dir: dir, git: gitPath, files: make(map[string]string)}
+	root, err := r.run("rev-parse", "--show-toplevel")
+	if err != nil {
+		if _, err := r.run("init", "--quiet"); err != nil {
+			return nil, err
+		}
+		if root, err = r.run("rev-parse", "--show-toplevel"); err != nil {
+			return nil, err
+		}
+	}
+	r.root = filepath.Clean(strings.TrimSpace(root))
+
+	// Commit as GoFlow rather than fail when no identity is configured
+	if email, err := r.run("config", "user.email"); err != nil || strings.TrimSpace(email) == "" {
+		r.identity = []string{"-c", "user.name=" + gitFallbackName, "-c", "user.email=" + gitFallbackEmail}
+	}
+	return r, nil
+}
+
+// Dir returns the directory workflows are stored in
+func (r *GitWorkflowRepository) Dir() string {
+	return r.dir
+}
+
+// Save writes a workflow to the repository and commits it with a default
+// message
+func (r *GitWorkflowRepository) Save(wf *workflow.Workflow) error {
+	return r.SaveWithMessage(wf, "")
+}
+
+// SaveWithMessage writes a workflow to the repository and commits it with
+// the given message; an empty message describes the change. A workflow is
+// stored in the file it was loaded from or last saved to, else in the file
+// of the workflow with the same name, else in <name>.yaml.
+func (r *GitWorkflowRepository) SaveWithMessage(wf *workflow.Workflow, message string) error {
+	if wf == nil {
+		return fmt.Errorf("cannot save nil workflow")
+	}
+	if err := workflow.ValidateWorkflowName(wf.Name); err != nil {
+		return err
+	}
+
+	path, err := r.fileOf(wf.ID)
+	if err != nil {
+		path, err = r.findFile(func(found *workflow.Workflow) bool { return found.Name == wf.Name })
+	}
+	if err != nil {
+		path = filepath.Join(r.dir, wf.Name+".yaml")
+	}
+
+	data, err := workflow.ToYAML(wf)
+	if err != nil {
+		return fmt.Errorf("failed to serialize workflow: %w", err)
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write workflow file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save workflow file: %w", err)
+	}
+	r.remember(wf.ID, path)
+	return r.CommitFile(path, message)
+}
+
+// CommitFile commits the current contents of a workflow file in the
+// working tree. Nothing is committed if the file is unchanged. An empty
+// message becomes "Add <file>" or "Update <file>".
+func (r *GitWorkflowRepository) CommitFile(path, message string) error {
+	rel, err := r.relPath(path)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status, err := r.run("status", "--porcelain", "--", rel)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) == "" {
+		return nil
+	}
+	if message == "" {
+		verb := "Update"
+		if strings.HasPrefix(status, "??") {
+			verb = "Add"
+		}
+		message = verb + " " + filepath.ToSlash(rel)
+	}
+
+	if _, err := r.run("add", "--", rel); err != nil {
+		return err
+	}
+	// Commit only this file, leaving anything else staged alone
+	_, err = r.run("commit", "--quiet", "-m", message, "--", rel)
+	return err
+}
+
+// History returns the commits that changed a workflow file, newest first
+func (r *GitWorkflowRepository) History(path string) ([]WorkflowRevision, error) {
+	rel, err := r.relPath(path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.run("log", "--format=%H%x1f%an%x1f%at%x1f%s", "--", rel)
+	if err != nil {
+		// A repository without commits has no history
+		if _, headErr := r.run("rev-parse", "--verify", "--quiet", "HEAD"); headErr != nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var revisions []WorkflowRevision
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, WorkflowRevision{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Time:    time.Unix(seconds, 0),
+			Message: fields[3],
+		})
+	}
+	return revisions, nil
+}
+
+// Revision returns a workflow file as it was in a commit
+func (r *GitWorkflowRepository) Revision(path, hash string) (*workflow.Workflow, error) {
+	rel, err := r.relPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" || strings.HasPrefix(hash, "-") {
+		return nil, fmt.Errorf("invalid revision: %q", hash)
+	}
+
+	// Paths in "rev:path" are relative to the top of the working tree
+	top, err := filepath.Rel(r.root, filepath.Join(r.dir, rel))
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflow path: %w", err)
+	}
+	data, err := r.run("show", hash+":"+filepath.ToSlash(top))
+	if err != nil {
+		return nil, err
+	}
+	wf, err := workflow.Parse([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s at %s: %w", filepath.Base(path), hash, err)
+	}
+	return wf, nil
+}
+
+// FindByID retrieves a workflow by the ID it was given when the repository
+// last returned or saved it
+func (r *GitWorkflowRepository) FindByID(id string) (*workflow.Workflow, error) {
+	path, err := r.fileOf(id)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := workflow.ParseFile(path)
+	if err != nil {
+		return nil, workflow.ErrWorkflowNotFound
+	}
+	wf.ID = id
+	return wf, nil
+}
+
+// FindByName retrieves a workflow by name
+func (r *GitWorkflowRepository) FindByName(name string) (*workflow.Workflow, error) {
+	return r.find(func(wf *workflow.Workflow) bool { return wf.Name == name })
+}
+
+// List returns all workflows in the repository. Files that cannot be
+// parsed are skipped.
+func (r *GitWorkflowRepository) List() ([]*workflow.Workflow, error) {
+	workflows := make([]*workflow.Workflow, 0)
+	err := r.walk(func(_ string, wf *workflow.Workflow) bool {
+		workflows = append(workflows, wf)
+		return false
+	})
+	return workflows, err
+}
+
+// Delete removes a workflow, by the ID it was given when the repository
+// last returned or saved it, and commits the removal
+func (r *GitWorkflowRepository) Delete(id string) error {
+	path, err := r.fileOf(id)
+	if err != nil {
+		return err
+	}
+	rel, err := r.relPath(path)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.files, id)
+
+	if _, err := r.run("ls-files", "--error-unmatch", "--", rel); err != nil {
+		// Never committed: just remove the file
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to delete workflow file: %w", err)
+		}
+		return nil
+	}
+	if _, err := r.run("rm", "--quiet", "--force", "--", rel); err != nil {
+		return err
+	}
+	_, err = r.run("commit", "--quiet", "-m", "Delete "+filepath.ToSlash(rel), "--", rel)
+	return err
+}
+
+// find returns the first workflow matching a predicate
+func (r *GitWorkflowRepository) find(match func(*workflow.Workflow) bool) (*workflow.Workflow, error) {
+	var found *workflow.Workflow
+	err := r.walk(func(_ string, wf *workflow.Workflow) bool {
+		if match(wf) {
+			found = wf
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, workflow.ErrWorkflowNotFound
+	}
+	return found, nil
+}
+
+// findFile returns the file of the first workflow matching a predicate
+func (r *GitWorkflowRepository) findFile(match func(*workflow.Workflow) bool) (string, error) {
+	found := ""
+	err := r.walk(func(path string, wf *workflow.Workflow) bool {
+		if match(wf) {
+			found = path
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", workflow.ErrWorkflowNotFound
+	}
+	return found, nil
+}
+
+// remember records the file a workflow ID belongs to
+func (r *GitWorkflowRepository) remember(id, path string) {
+	if id == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[id] = path
+}
+
+// fileOf returns the file a workflow ID belongs to, if it still exists
+func (r *GitWorkflowRepository) fileOf(id string) (string, error) {
+	r.mu.Lock()
+	path, exists := r.files[id]
+	r.mu.Unlock()
+	if !exists {
+		return "", workflow.ErrWorkflowNotFound
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", workflow.ErrWorkflowNotFound
+	}
+	return path, nil
+}
+
+// walk parses each workflow file in the directory until visit returns true.
+// The ID of every workflow visited is remembered.
+func (r *GitWorkflowRepository) walk(visit func(path string, wf *workflow.Workflow) bool) error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read workflows directory: %w", err)
+	}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(r.dir, entry.Name())
+		wf, err := workflow.ParseFile(path)
+		if err != nil {
+			continue
+		}
+		r.remember(wf.ID, path)
+		if visit(path, wf) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// relPath returns path relative to the workflows directory, refusing paths
+// outside it
+func (r *GitWorkflowRepository) relPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid workflow path: %w", err)
+	}
+	rel, err := filepath.Rel(r.dir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the repository directory %s", path, r.dir)
+	}
+	return rel, nil
+}
+
+// run runs a git command in the workflows directory, as the fallback
+// identity if one is needed, and returns its standard output
+func (r *GitWorkflowRepository) run(args ...string) (string, error) {
+	global := append([]string{"-C", r.dir}, r.identity...)
+	cmd := exec.Command(r.git, append(global, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
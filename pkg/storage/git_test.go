@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+func newTestGitRepository(t *testing.T, dir string) *GitWorkflowRepository {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo, err := NewGitWorkflowRepository(dir)
+	if err != nil {
+		t.Fatalf("NewGitWorkflowRepository() error = %v", err)
+	}
+	return repo
+}
+
+func TestGitWorkflowRepository(t *testing.T) {
+	var _ workflow.WorkflowRepository = (*GitWorkflowRepository)(nil)
+
+	repo := newTestGitRepository(t, t.TempDir())
+	wf := newSnapshotTestWorkflow(t, "my-flow")
+
+	if err := repo.Save(wf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := wf.AddNode(&workflow.EndNode{ID: "end"}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	if err := repo.SaveWithMessage(wf, "Add end node"); err != nil {
+		t.Fatalf("SaveWithMessage() error = %v", err)
+	}
+	// Saving without changes commits nothing
+	if err := repo.Save(wf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	path := filepath.Join(repo.Dir(), "my-flow.yaml")
+	history, err := repo.History(path)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 2 || history[0].Message != "Add end node" || history[1].Message != "Add my-flow.yaml" {
+		t.Fatalf("History() = %+v", history)
+	}
+	if len(history[1].ShortHash()) != 7 || history[1].Time.IsZero() {
+		t.Errorf("revision = %+v", history[1])
+	}
+
+	old, err := repo.Revision(path, history[1].Hash)
+	if err != nil {
+		t.Fatalf("Revision() error = %v", err)
+	}
+	if len(old.Nodes) != 1 {
+		t.Errorf("first revision has %d nodes, want 1", len(old.Nodes))
+	}
+	if _, err := repo.Revision(path, "--output=/tmp/x"); err == nil {
+		t.Error("expected error for an option as revision")
+	}
+	if _, err := repo.History(filepath.Join(repo.Dir(), "..", "outside.yaml")); err == nil {
+		t.Error("expected error for a file outside the repository")
+	}
+
+	// Files don't store IDs: the repository knows the saved workflow's ID
+	// and the one it gives the workflow when loading it
+	if found, err := repo.FindByID(wf.ID); err != nil || len(found.Nodes) != 2 {
+		t.Errorf("FindByID() = %v, %v", found, err)
+	}
+	found, err := repo.FindByName("my-flow")
+	if err != nil || found.Name != "my-flow" {
+		t.Fatalf("FindByName() = %v, %v", found, err)
+	}
+	if workflows, err := repo.List(); err != nil || len(workflows) != 1 {
+		t.Errorf("List() = %v, %v", workflows, err)
+	}
+	if err := repo.Delete(found.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.FindByID(found.ID); !errors.Is(err, workflow.ErrWorkflowNotFound) {
+		t.Errorf("FindByID() after delete error = %v, want ErrWorkflowNotFound", err)
+	}
+	if history, _ := repo.History(path); len(history) != 3 {
+		t.Errorf("expected the delete to be committed, history = %+v", history)
+	}
+}
+
+func TestGitWorkflowRepository_Subdirectory(t *testing.T) {
+	root := t.TempDir()
+	newTestGitRepository(t, root)
+
+	// Workflows in a directory of an existing working tree use that tree
+	repo := newTestGitRepository(t, filepath.Join(root, "flows"))
+	if repo.root != root {
+		if resolved, err := filepath.EvalSymlinks(root); err != nil || repo.root != resolved {
+			t.Fatalf("root = %s, want %s", repo.root, root)
+		}
+	}
+	if history, err := repo.History(filepath.Join(repo.Dir(), "none.yaml")); err != nil || len(history) != 0 {
+		t.Errorf("History() before any commit = %v, %v", history, err)
+	}
+
+	wf := newSnapshotTestWorkflow(t, "nested")
+	if err := repo.Save(wf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	path := filepath.Join(repo.Dir(), "nested.yaml")
+	history, err := repo.History(path)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("History() = %v, %v", history, err)
+	}
+	if _, err := repo.Revision(path, history[0].Hash); err != nil {
+		t.Errorf("Revision() error = %v", err)
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 // MigrationVersion tracks the current database schema version.
-const MigrationVersion = 1
+const MigrationVersion = 3
 
 // InitializeDatabase creates the SQLite database schema for execution history.
 // This includes migration version tracking to support future schema updates.
@@ -36,6 +36,16 @@ func InitializeDatabase(db *sql.DB) error {
 			return fmt.Errorf("failed to apply migration 1: %w", err)
 		}
 	}
+	if currentVersion < 2 {
+		if err := applyMigration2(db); err != nil {
+			return fmt.Errorf("failed to apply migration 2: %w", err)
+		}
+	}
+	if currentVersion < 3 {
+		if err := applyMigration3(db); err != nil {
+			return fmt.Errorf("failed to apply migration 3: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -162,3 +172,52 @@ func applyMigration1(db *sql.DB) error {
 
 	return nil
 }
+
+// applyMigration2 records which failed node execution a manual retry re-runs.
+func applyMigration2(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("ALTER TABLE node_executions ADD COLUMN retry_of TEXT;"); err != nil {
+		return fmt.Errorf("failed to add retry_of column: %w", err)
+	}
+
+	// Record migration
+	if _, err := tx.Exec("INSERT INTO migrations (version) VALUES (?)", 2); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+
+	return nil
+}
+
+// applyMigration3 records which node executions were served from the tool
+// result cache.
+func applyMigration3(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("ALTER TABLE node_executions ADD COLUMN cache_hit INTEGER NOT NULL DEFAULT 0;"); err != nil {
+		return fmt.Errorf("failed to add cache_hit column: %w", err)
+	}
+
+	// Record migration
+	if _, err := tx.Exec("INSERT INTO migrations (version) VALUES (?)", 3); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// SampleStore keeps the variables of each workflow's last run on disk, one
+// JSON file per workflow, as sample data for previewing expressions
+type SampleStore struct {
+	baseDir string
+}
+
+// NewSampleStore creates a sample store rooted at baseDir. The directory is
+// created on the first save.
+func NewSampleStore(baseDir string) *SampleStore {
+	return &SampleStore{baseDir: baseDir}
+}
+
+// DefaultSamplesDir returns GOFLOW_SAMPLES_DIR, or samples in
+// GOFLOW_CONFIG_DIR or ~/.goflow
+func DefaultSamplesDir() string {
+	if dir := os.Getenv("GOFLOW_SAMPLES_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "samples")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "samples")
+	}
+	return filepath.Join(homeDir, ".goflow", "samples")
+}
+
+// Save stores the variables of a workflow's run, replacing those of any
+// earlier run
+func (s *SampleStore) Save(workflowName string, variables map[string]interface{}) error {
+	filePath, err := s.samplePath(workflowName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(variables, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize variables: %w", err)
+	}
+	if err := os.MkdirAll(s.baseDir, 0755); err != nil {
+		return fmt.Errorf("failed to create sample directory: %w", err)
+	}
+
+	// Write to a temp file and rename, so a failed save never leaves a
+	// truncated sample behind
+	tempPath := filePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write sample: %w", err)
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save sample: %w", err)
+	}
+	return nil
+}
+
+// Load returns the variables of a workflow's last run, or nil if it has not
+// been run
+func (s *SampleStore) Load(workflowName string) (map[string]interface{}, error) {
+	filePath, err := s.samplePath(workflowName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sample: %w", err)
+	}
+
+	var variables map[string]interface{}
+	if err := json.Unmarshal(data, &variables); err != nil {
+		return nil, fmt.Errorf("failed to parse sample for %s: %w", workflowName, err)
+	}
+	return variables, nil
+}
+
+// samplePath returns the file a workflow's sample is stored in. The name is
+// escaped so any valid workflow name maps to a single file.
+func (s *SampleStore) samplePath(workflowName string) (string, error) {
+	if err := workflow.ValidateWorkflowName(workflowName); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.baseDir, url.PathEscape(workflowName)+".json"), nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSampleStore(t *testing.T) {
+	store := NewSampleStore(filepath.Join(t.TempDir(), "samples"))
+
+	if variables, err := store.Load("my flow"); err != nil || variables != nil {
+		t.Fatalf("Load() before saving = %v, %v; want nil", variables, err)
+	}
+
+	if err := store.Save("my flow", map[string]interface{}{"count": 3, "user": map[string]interface{}{"name": "ada"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save("my flow", map[string]interface{}{"count": 4}); err != nil {
+		t.Fatalf("Save() again error = %v", err)
+	}
+
+	variables, err := store.Load("my flow")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(variables) != 1 || variables["count"] != float64(4) {
+		t.Errorf("Load() = %v, want the last run's variables", variables)
+	}
+
+	if err := store.Save("", nil); err == nil {
+		t.Error("Save() accepted an empty workflow name")
+	}
+}
+
+func TestSampleStore_CorruptSample(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "flow.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSampleStore(dir).Load("flow"); err == nil {
+		t.Error("Load() accepted a corrupt sample")
+	}
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// maxSnapshotNameLength bounds snapshot names, which become file names
+const maxSnapshotNameLength = 64
+
+// validSnapshotName allows names that are safe as file names on every
+// platform
+var validSnapshotName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// WorkflowSnapshot describes a named version of a workflow
+type WorkflowSnapshot struct {
+	Name      string    // Snapshot name
+	Workflow  string    // Name of the workflow it is a version of
+	CreatedAt time.Time // When the snapshot was saved
+}
+
+// SnapshotStore keeps named versions of workflows on disk, independent of
+// any version control. Each workflow's snapshots are kept in their own
+// directory, one YAML file per snapshot.
+type SnapshotStore struct {
+	baseDir string
+}
+
+// NewSnapshotStore creates a snapshot store rooted at baseDir. The
+// directory is created on the first save.
+func NewSnapshotStore(baseDir string) *SnapshotStore {
+	return &SnapshotStore{baseDir: baseDir}
+}
+
+// DefaultSnapshotsDir returns GOFLOW_SNAPSHOTS_DIR or ~/.goflow/snapshots
+func DefaultSnapshotsDir() string {
+	if dir := os.Getenv("GOFLOW_SNAPSHOTS_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "snapshots")
+	}
+	return filepath.Join(homeDir, ".goflow", "snapshots")
+}
+
+// ValidateSnapshotName checks that a snapshot name can be stored
+func ValidateSnapshotName(name string) error {
+	if name == "" {
+		return errors.New("snapshot name cannot be empty")
+	}
+	if len(name) > maxSnapshotNameLength {
+		return fmt.Errorf("snapshot name exceeds maximum length of %d characters", maxSnapshotNameLength)
+	}
+	if !validSnapshotName.MatchString(name) {
+		return fmt.Errorf("snapshot name must start with a letter or digit and contain only alphanumeric characters, dots, underscores, or hyphens")
+	}
+	return nil
+}
+
+// Save stores the workflow as a snapshot with the given name, replacing
+// any earlier snapshot of the same name
+func (s *SnapshotStore) Save(wf *workflow.Workflow, name string) (WorkflowSnapshot, error) {
+	if wf == nil {
+		return WorkflowSnapshot{}, fmt.Errorf("cannot snapshot nil workflow")
+	}
+	if err := ValidateSnapshotName(name); err != nil {
+		return WorkflowSnapshot{}, err
+	}
+	dir, err := s.workflowDir(wf.Name)
+	if err != nil {
+		return WorkflowSnapshot{}, err
+	}
+
+	data, err := workflow.ToYAML(wf)
+	if err != nil {
+		return WorkflowSnapshot{}, fmt.Errorf("failed to serialize workflow: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return WorkflowSnapshot{}, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	// Write to a temp file and rename, so a failed save never leaves a
+	// truncated snapshot behind
+	filePath := filepath.Join(dir, name+".yaml")
+	tempPath := filePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return WorkflowSnapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		_ = os.Remove(tempPath)
+		return WorkflowSnapshot{}, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return s.snapshotInfo(wf.Name, filePath)
+}
+
+// List returns the snapshots of a workflow, newest first
+func (s *SnapshotStore) List(workflowName string) ([]WorkflowSnapshot, error) {
+	dir, err := s.workflowDir(workflowName)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	snapshots := make([]WorkflowSnapshot, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		snapshot, err := s.snapshotInfo(workflowName, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue // Removed while listing
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+		}
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
+// Load returns the workflow saved in a snapshot
+func (s *SnapshotStore) Load(workflowName, name string) (*workflow.Workflow, error) {
+	filePath, err := s.snapshotPath(workflowName, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("snapshot not found: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	wf, err := workflow.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", name, err)
+	}
+	return wf, nil
+}
+
+// Delete removes a snapshot
+func (s *SnapshotStore) Delete(workflowName, name string) error {
+	filePath, err := s.snapshotPath(workflowName, name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("snapshot not found: %s", name)
+		}
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	return nil
+}
+
+// workflowDir returns the directory holding a workflow's snapshots. The
+// name is escaped so any valid workflow name maps to a single directory.
+func (s *SnapshotStore) workflowDir(workflowName string) (string, error) {
+	if err := workflow.ValidateWorkflowName(workflowName); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.baseDir, url.PathEscape(workflowName)), nil
+}
+
+// snapshotPath returns the file a snapshot is stored in
+func (s *SnapshotStore) snapshotPath(workflowName, name string) (string, error) {
+	if err := ValidateSnapshotName(name); err != nil {
+		return "", err
+	}
+	dir, err := s.workflowDir(workflowName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".yaml"), nil
+}
+
+// snapshotInfo describes the snapshot stored at filePath
+func (s *SnapshotStore) snapshotInfo(workflowName, filePath string) (WorkflowSnapshot, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return WorkflowSnapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return WorkflowSnapshot{
+		Name:      strings.TrimSuffix(filepath.Base(filePath), ".yaml"),
+		Workflow:  workflowName,
+		CreatedAt: info.ModTime(),
+	}, nil
+}
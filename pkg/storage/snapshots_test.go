@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+func newSnapshotTestWorkflow(t *testing.T, name string) *workflow.Workflow {
+	t.Helper()
+	wf, err := workflow.NewWorkflow(name, "snapshot test")
+	if err != nil {
+		t.Fatalf("NewWorkflow() error = %v", err)
+	}
+	if err := wf.AddNode(&workflow.StartNode{ID: "start"}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	return wf
+}
+
+func TestSnapshotStore(t *testing.T) {
+	store := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots"))
+	wf := newSnapshotTestWorkflow(t, "my flow")
+
+	if snapshots, err := store.List(wf.Name); err != nil || len(snapshots) != 0 {
+		t.Fatalf("List() before saving = %v, %v; want none", snapshots, err)
+	}
+
+	first, err := store.Save(wf, "v1")
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if first.Name != "v1" || first.Workflow != "my flow" {
+		t.Errorf("Save() = %+v", first)
+	}
+
+	if err := wf.AddNode(&workflow.EndNode{ID: "end"}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	if _, err := store.Save(wf, "v2"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// Make the order independent of file system timestamp resolution
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(store.baseDir, "my%20flow", "v1.yaml"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := store.List(wf.Name)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "v2" || snapshots[1].Name != "v1" {
+		t.Fatalf("List() = %+v, want v2 then v1", snapshots)
+	}
+
+	loaded, err := store.Load(wf.Name, "v1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Nodes) != 1 || loaded.Nodes[0].GetID() != "start" {
+		t.Errorf("Load(v1) nodes = %v, want only start", loaded.Nodes)
+	}
+
+	if err := store.Delete(wf.Name, "v1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Load(wf.Name, "v1"); err == nil {
+		t.Error("expected error loading a deleted snapshot")
+	}
+	if err := store.Delete(wf.Name, "v1"); err == nil {
+		t.Error("expected error deleting a missing snapshot")
+	}
+}
+
+func TestSnapshotStore_InvalidNames(t *testing.T) {
+	store := NewSnapshotStore(t.TempDir())
+	wf := newSnapshotTestWorkflow(t, "flow")
+
+	for _, name := range []string{"", ".hidden", "../escape", "a/b", "with space"} {
+		if _, err := store.Save(wf, name); err == nil {
+			t.Errorf("Save(%q) succeeded, want error", name)
+		}
+	}
+	if _, err := store.Load("../other", "v1"); err == nil {
+		t.Error("expected error for a workflow name with path traversal")
+	}
+}
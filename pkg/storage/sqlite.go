@@ -257,7 +257,7 @@ func (r *SQLiteExecutionRepository) Load(id types.ExecutionID) (*execution.Execu
 func (r *SQLiteExecutionRepository) loadNodeExecutions(execID types.ExecutionID) ([]*execution.NodeExecution, error) {
 	query := `
 		SELECT id, execution_id, node_id, node_type, status, started_at, completed_at,
-		       inputs, outputs, error_type, error_message, error_context, retry_count
+		       inputs, outputs, error_type, error_message, error_context, retry_count, retry_of, cache_hit
 		FROM node_executions
 		WHERE execution_id = ?
 		ORDER BY started_at
@@ -276,7 +276,7 @@ func (r *SQLiteExecutionRepository) loadNodeExecutions(execID types.ExecutionID)
 	for rows.Next() {
 		var ne execution.NodeExecution
 		var completedAt sql.NullTime
-		var inputs, outputs, errorType, errorMessage, errorContext sql.NullString
+		var inputs, outputs, errorType, errorMessage, errorContext, retryOf sql.NullString
 
 		err := rows.Scan(
 			&ne.ID,
@@ -292,6 +292,8 @@ func (r *SQLiteExecutionRepository) loadNodeExecutions(execID types.ExecutionID)
 			&errorMessage,
 			&errorContext,
 			&ne.RetryCount,
+			&retryOf,
+			&ne.CacheHit,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan node execution: %w", err)
@@ -300,6 +302,9 @@ func (r *SQLiteExecutionRepository) loadNodeExecutions(execID types.ExecutionID)
 		if completedAt.Valid {
 			ne.CompletedAt = completedAt.Time
 		}
+		if retryOf.Valid {
+			ne.RetryOf = types.NodeExecutionID(retryOf.String)
+		}
 
 		// Deserialize JSON fields
 		if inputs.Valid {
@@ -625,11 +630,17 @@ func (r *SQLiteExecutionRepository) SaveNodeExecution(nodeExec *execution.NodeEx
 		completedAt.Time = nodeExec.CompletedAt
 	}
 
+	var retryOf sql.NullString
+	if nodeExec.RetryOf != "" {
+		retryOf.Valid = true
+		retryOf.String = string(nodeExec.RetryOf)
+	}
+
 	query := `
 		INSERT INTO node_executions (
 			id, execution_id, node_id, node_type, status, started_at, completed_at,
-			inputs, outputs, error_type, error_message, error_context, retry_count
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			inputs, outputs, error_type, error_message, error_context, retry_count, retry_of, cache_hit
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			status = excluded.status,
 			completed_at = excluded.completed_at,
@@ -637,7 +648,8 @@ func (r *SQLiteExecutionRepository) SaveNodeExecution(nodeExec *execution.NodeEx
 			error_type = excluded.error_type,
 			error_message = excluded.error_message,
 			error_context = excluded.error_context,
-			retry_count = excluded.retry_count
+			retry_count = excluded.retry_count,
+			cache_hit = excluded.cache_hit
 	`
 
 	_, err := r.db.Exec(query,
@@ -654,6 +666,8 @@ func (r *SQLiteExecutionRepository) SaveNodeExecution(nodeExec *execution.NodeEx
 		errorMessage,
 		errorContext,
 		nodeExec.RetryCount,
+		retryOf,
+		nodeExec.CacheHit,
 	)
 
 	if err != nil {
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// MaxToolSamples is how many recent outputs are kept per tool
+	MaxToolSamples = 5
+	// maxToolSampleSize bounds the size of a kept output, in bytes of JSON;
+	// larger outputs are not kept
+	maxToolSampleSize = 64 * 1024
+)
+
+// ToolSampleStore keeps the recent outputs of each server's tools on disk,
+// one JSON file per tool, as samples to infer the tools' output schemas
+// from
+type ToolSampleStore struct {
+	baseDir string
+}
+
+// NewToolSampleStore creates a tool sample store rooted at baseDir. The
+// directory is created on the first record.
+func NewToolSampleStore(baseDir string) *ToolSampleStore {
+	return &ToolSampleStore{baseDir: baseDir}
+}
+
+// DefaultToolSamplesDir returns the tools directory of the default sample
+// directory
+func DefaultToolSamplesDir() string {
+	return filepath.Join(DefaultSamplesDir(), "tools")
+}
+
+// Record adds an output of a server's tool, dropping the oldest once
+// MaxToolSamples are kept. Outputs over 64 KiB of JSON are not kept.
+func (s *ToolSampleStore) Record(serverID, toolName string, output interface{}) error {
+	filePath, err := s.samplePath(serverID, toolName)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return fmt.Errorf("failed to serialize output: %w", err)
+	}
+	if len(data) > maxToolSampleSize {
+		return nil
+	}
+
+	samples, err := s.load(filePath)
+	if err != nil {
+		return err
+	}
+	samples = append(samples, data)
+	if len(samples) > MaxToolSamples {
+		samples = samples[len(samples)-MaxToolSamples:]
+	}
+	data, err = json.MarshalIndent(samples, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize samples: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create sample directory: %w", err)
+	}
+
+	// Write to a temp file and rename, so a failed save never leaves a
+	// truncated sample behind
+	tempPath := filePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write samples: %w", err)
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save samples: %w", err)
+	}
+	return nil
+}
+
+// Load returns the recent outputs of a server's tool, oldest first, or nil
+// if none were recorded
+func (s *ToolSampleStore) Load(serverID, toolName string) ([]interface{}, error) {
+	filePath, err := s.samplePath(serverID, toolName)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := s.load(filePath)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	samples := make([]interface{}, 0, len(raw))
+	for _, data := range raw {
+		var sample interface{}
+		if err := json.Unmarshal(data, &sample); err != nil {
+			return nil, fmt.Errorf("failed to parse samples of %s/%s: %w", serverID, toolName, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// load reads the samples file at filePath, which may not exist
+func (s *ToolSampleStore) load(filePath string) ([]json.RawMessage, error) {
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read samples: %w", err)
+	}
+	var samples []json.RawMessage
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, fmt.Errorf("failed to parse samples %s: %w", filePath, err)
+	}
+	return samples, nil
+}
+
+// samplePath returns the file a tool's samples are stored in, in a
+// directory per server. Names are escaped so each maps to a single path
+// element.
+func (s *ToolSampleStore) samplePath(serverID, toolName string) (string, error) {
+	for _, name := range []string{serverID, toolName} {
+		if name == "" || name == "." || name == ".." {
+			return "", fmt.Errorf("invalid server or tool name: %q", name)
+		}
+	}
+	return filepath.Join(s.baseDir, url.PathEscape(serverID), url.PathEscape(toolName)+".json"), nil
+}
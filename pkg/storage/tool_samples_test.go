@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToolSampleStore(t *testing.T) {
+	store := NewToolSampleStore(filepath.Join(t.TempDir(), "tools"))
+
+	if samples, err := store.Load("fs", "read_file"); err != nil || samples != nil {
+		t.Fatalf("Load() before recording = %v, %v; want nil", samples, err)
+	}
+
+	for i := 0; i < MaxToolSamples+2; i++ {
+		if err := store.Record("fs", "read_file", map[string]interface{}{"n": i}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if err := store.Record("fs", "read_file", strings.Repeat("x", maxToolSampleSize)); err != nil {
+		t.Fatalf("Record() of a large output error = %v", err)
+	}
+
+	samples, err := store.Load("fs", "read_file")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(samples) != MaxToolSamples {
+		t.Fatalf("Load() kept %d samples, want %d", len(samples), MaxToolSamples)
+	}
+	first, _ := samples[0].(map[string]interface{})
+	last, _ := samples[len(samples)-1].(map[string]interface{})
+	if first["n"] != float64(2) || last["n"] != float64(MaxToolSamples+1) {
+		t.Errorf("Load() = %v, want the most recent outputs, oldest first", samples)
+	}
+
+	if samples, _ := store.Load("fs", "write_file"); samples != nil {
+		t.Errorf("Load() of another tool = %v, want nil", samples)
+	}
+	if err := store.Record("..", "read_file", nil); err == nil {
+		t.Error("Record() accepted .. as a server ID")
+	}
+	if err := store.Record("fs", "", nil); err == nil {
+		t.Error("Record() accepted an empty tool name")
+	}
+}
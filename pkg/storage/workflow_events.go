@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WorkflowChangeType identifies what happened to a workflow file
+type WorkflowChangeType string
+
+// Workflow change event types
+const (
+	WorkflowCreated WorkflowChangeType = "workflow.created"
+	WorkflowUpdated WorkflowChangeType = "workflow.updated"
+	WorkflowDeleted WorkflowChangeType = "workflow.deleted"
+)
+
+// DefaultWorkflowWatchInterval is how often the workflows directory is scanned
+const DefaultWorkflowWatchInterval = time.Second
+
+// workflowEventBuffer is the channel buffer of each subscriber
+const workflowEventBuffer = 64
+
+// WorkflowChangeEvent describes a change to a workflow in the repository
+// directory. Hashes are the hex SHA-256 of the file contents, so consumers
+// can tell versions apart and skip content they already have.
+type WorkflowChangeEvent struct {
+	Type         WorkflowChangeType `json:"type"`
+	Workflow     string             `json:"workflow"` // File name without the .yaml extension
+	Path         string             `json:"path"`
+	Hash         string             `json:"hash,omitempty"`          // Empty for deletions
+	PreviousHash string             `json:"previous_hash,omitempty"` // Empty for creations
+	Timestamp    time.Time          `json:"timestamp"`
+}
+
+// WorkflowChangeWatcher detects workflow changes by scanning the workflows
+// directory and comparing content hashes, so it sees changes from every
+// writer (the TUI, CLI commands, editors, sync tools). Rewrites that leave
+// the content unchanged produce no event.
+type WorkflowChangeWatcher struct {
+	dir      string
+	interval time.Duration
+
+	mu          sync.Mutex
+	hashes      map[string]string // workflow -> content hash
+	subscribers []chan WorkflowChangeEvent
+}
+
+// NewWorkflowChangeWatcher creates a watcher for dir, taking the current
+// contents as the baseline so only later changes are reported.
+func NewWorkflowChangeWatcher(dir string, interval time.Duration) (*WorkflowChangeWatcher, error) {
+	if interval <= 0 {
+		interval = DefaultWorkflowWatchInterval
+	}
+
+	hashes, err := hashWorkflowFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkflowChangeWatcher{
+		dir:      dir,
+		interval: interval,
+		hashes:   hashes,
+	}, nil
+}
+
+// Subscribe returns a channel receiving every subsequent change event.
+// Events are dropped for subscribers that fall more than a buffer behind.
+func (w *WorkflowChangeWatcher) Subscribe() <-chan WorkflowChangeEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan WorkflowChangeEvent, workflowEventBuffer)
+	w.subscribers = append(w.subscribers, ch)
+	return ch
+}
+
+// Unsubscribe stops delivery to a channel returned by Subscribe and closes it
+func (w *WorkflowChangeWatcher) Unsubscribe(ch <-chan WorkflowChangeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i, sub := range w.subscribers {
+		if sub == ch {
+			w.subscribers = append(w.subscribers[:i], w.subscribers[i+1:]...)
+			close(sub)
+			return
+		}
+	}
+}
+
+// Run scans for changes every interval until ctx is cancelled, then closes
+// all subscriber channels. Scan errors are logged and retried.
+func (w *WorkflowChangeWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.mu.Lock()
+			for _, sub := range w.subscribers {
+				close(sub)
+			}
+			w.subscribers = nil
+			w.mu.Unlock()
+			return
+		case <-ticker.C:
+			if _, err := w.Scan(); err != nil {
+				log.Printf("Workflow watch: %v", err)
+			}
+		}
+	}
+}
+
+// Scan compares the directory with the last scan, publishes an event per
+// change to all subscribers, and returns the events in workflow name order.
+func (w *WorkflowChangeWatcher) Scan() ([]WorkflowChangeEvent, error) {
+	hashes, err := hashWorkflowFiles(w.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now().UTC()
+	var events []WorkflowChangeEvent
+	for name, hash := range hashes {
+		previous, existed := w.hashes[name]
+		switch {
+		case !existed:
+			events = append(events, w.newEvent(WorkflowCreated, name, hash, "", now))
+		case previous != hash:
+			events = append(events, w.newEvent(WorkflowUpdated, name, hash, previous, now))
+		}
+	}
+	for name, previous := range w.hashes {
+		if _, exists := hashes[name]; !exists {
+			events = append(events, w.newEvent(WorkflowDeleted, name, "", previous, now))
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Workflow < events[j].Workflow })
+
+	w.hashes = hashes
+	for _, event := range events {
+		for _, sub := range w.subscribers {
+			select {
+			case sub <- event:
+			default:
+				// Subscriber is not keeping up
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func (w *WorkflowChangeWatcher) newEvent(changeType WorkflowChangeType, name, hash, previous string, now time.Time) WorkflowChangeEvent {
+	return WorkflowChangeEvent{
+		Type:         changeType,
+		Workflow:     name,
+		Path:         filepath.Join(w.dir, name+".yaml"),
+		Hash:         hash,
+		PreviousHash: previous,
+		Timestamp:    now,
+	}
+}
+
+// hashWorkflowFiles returns the content hash of every workflow file in dir.
+// Temporary files from atomic saves are ignored.
+func hashWorkflowFiles(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflows directory: %w", err)
+	}
+
+	hashes := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Removed since the directory was read
+			}
+			return nil, fmt.Errorf("failed to read workflow file: %w", err)
+		}
+		sum := sha256.Sum256(data)
+		hashes[strings.TrimSuffix(entry.Name(), ".yaml")] = hex.EncodeToString(sum[:])
+	}
+	return hashes, nil
+}
+
+// ServeWorkflowEvents writes change events as JSON lines to every client
+// connected to listener, until ctx is cancelled. Clients only receive
+// events that occur while they are connected.
+func ServeWorkflowEvents(ctx context.Context, listener net.Listener, watcher *WorkflowChangeWatcher) error {
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept event client: %w", err)
+		}
+
+		events := watcher.Subscribe()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer watcher.Unsubscribe(events)
+			defer func() { _ = conn.Close() }()
+			streamWorkflowEvents(ctx, conn, events)
+		}()
+	}
+}
+
+// streamWorkflowEvents writes events to conn until the client goes away,
+// the watcher stops, or ctx is cancelled
+func streamWorkflowEvents(ctx context.Context, conn net.Conn, events <-chan WorkflowChangeEvent) {
+	encoder := json.NewEncoder(conn)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		}
+	}
+}
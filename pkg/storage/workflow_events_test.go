@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeWorkflowFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestWorkflowChangeWatcher_Scan(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkflowFile(t, dir, "existing.yaml", "name: existing\n")
+	writeWorkflowFile(t, dir, "doomed.yaml", "name: doomed\n")
+
+	watcher, err := NewWorkflowChangeWatcher(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("NewWorkflowChangeWatcher() error = %v", err)
+	}
+	events := watcher.Subscribe()
+
+	// The baseline produces no events
+	if changes, err := watcher.Scan(); err != nil || len(changes) != 0 {
+		t.Fatalf("Scan() = %v, %v; want no changes", changes, err)
+	}
+
+	writeWorkflowFile(t, dir, "existing.yaml", "name: existing\nversion: 2\n")
+	writeWorkflowFile(t, dir, "new.yaml", "name: new\n")
+	writeWorkflowFile(t, dir, "notes.txt", "not a workflow")
+	if err := os.Remove(filepath.Join(dir, "doomed.yaml")); err != nil {
+		t.Fatalf("Failed to remove workflow: %v", err)
+	}
+
+	changes, err := watcher.Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	want := []struct {
+		workflow    string
+		changeType  WorkflowChangeType
+		hasHash     bool
+		hasPrevious bool
+	}{
+		{"doomed", WorkflowDeleted, false, true},
+		{"existing", WorkflowUpdated, true, true},
+		{"new", WorkflowCreated, true, false},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Scan() returned %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for i, w := range want {
+		got := changes[i]
+		if got.Workflow != w.workflow || got.Type != w.changeType {
+			t.Errorf("change %d = %s %s, want %s %s", i, got.Type, got.Workflow, w.changeType, w.workflow)
+		}
+		if (got.Hash != "") != w.hasHash || (got.PreviousHash != "") != w.hasPrevious {
+			t.Errorf("change %d hashes = %q/%q", i, got.Hash, got.PreviousHash)
+		}
+		if got.Path != filepath.Join(dir, w.workflow+".yaml") {
+			t.Errorf("change %d path = %s", i, got.Path)
+		}
+	}
+	if changes[1].Hash == changes[1].PreviousHash {
+		t.Error("updated workflow should have a new hash")
+	}
+
+	for i := range want {
+		select {
+		case event := <-events:
+			if event.Workflow != want[i].workflow {
+				t.Errorf("subscriber event %d = %s, want %s", i, event.Workflow, want[i].workflow)
+			}
+		default:
+			t.Fatalf("subscriber missed event %d", i)
+		}
+	}
+
+	// Rewriting identical content is not a change
+	writeWorkflowFile(t, dir, "new.yaml", "name: new\n")
+	if changes, err := watcher.Scan(); err != nil || len(changes) != 0 {
+		t.Errorf("Scan() after identical rewrite = %v, %v; want no changes", changes, err)
+	}
+}
+
+func TestWorkflowChangeWatcher_MissingDirectory(t *testing.T) {
+	if _, err := NewWorkflowChangeWatcher(filepath.Join(t.TempDir(), "missing"), 0); err == nil {
+		t.Error("NewWorkflowChangeWatcher() should fail for a missing directory")
+	}
+}
+
+func TestServeWorkflowEvents(t *testing.T) {
+	dir := t.TempDir()
+	watcher, err := NewWorkflowChangeWatcher(dir, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewWorkflowChangeWatcher() error = %v", err)
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "events.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- ServeWorkflowEvents(ctx, listener, watcher) }()
+	go watcher.Run(ctx)
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Wait for the server to subscribe the connection before changing files
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		watcher.mu.Lock()
+		subscribed := len(watcher.subscribers) > 0
+		watcher.mu.Unlock()
+		if subscribed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("connection was never subscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	writeWorkflowFile(t, dir, "synced.yaml", "name: synced\n")
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+
+	var event WorkflowChangeEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		t.Fatalf("Invalid event %s: %v", line, err)
+	}
+	if event.Type != WorkflowCreated || event.Workflow != "synced" || event.Hash == "" {
+		t.Errorf("event = %+v, want creation of synced", event)
+	}
+
+	cancel()
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			t.Errorf("ServeWorkflowEvents() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeWorkflowEvents() did not stop after cancel")
+	}
+}
@@ -8,11 +8,13 @@ var (
 	ErrInvalidJSONPath = errors.New("invalid JSONPath syntax")
 	ErrTypeMismatch    = errors.New("type mismatch in JSONPath query")
 	ErrNilData         = errors.New("cannot query nil data")
+	ErrMissingField    = errors.New("field not found in JSONPath query")
 
 	// Expression errors
 	ErrUnsafeOperation   = errors.New("unsafe operation attempted")
 	ErrEvaluationTimeout = errors.New("expression evaluation timed out")
 	ErrInvalidExpression = errors.New("invalid expression syntax")
+	ErrResourceLimit     = errors.New("expression exceeds its resource limits")
 
 	// Template and general errors
 	ErrInvalidTemplate = errors.New("invalid template syntax")
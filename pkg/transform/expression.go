@@ -2,9 +2,9 @@ package transform
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/expr-lang/expr"
 	"github.com/expr-lang/expr/vm"
@@ -53,47 +53,35 @@ func (e *exprEvaluator) Evaluate(ctx context.Context, expression string, context
 		return nil, err
 	}
 
-	// Get or compile program
-	program, err := e.getOrCompileProgram(expression, context)
+	// Load the lazy values the expression uses
+	context, err := resolveLazyVariables(expression, context)
 	if err != nil {
 		return nil, err
 	}
 
-	// Execute with timeout protection
-	resultChan := make(chan interface{}, 1)
-	errChan := make(chan error, 1)
-
-	go func() {
-		result, err := vm.Run(program, context)
-		if err != nil {
-			// Check if error is due to undefined variable
-			if strings.Contains(err.Error(), "undefined") || strings.Contains(err.Error(), "unknown name") {
-				errChan <- fmt.Errorf("%w: %v", ErrUndefinedVariable, err)
-				return
-			}
-			errChan <- fmt.Errorf("%w: %v", ErrInvalidExpression, err)
-			return
-		}
-		resultChan <- result
-	}()
-
-	// Wait for result or timeout
-	deadline, hasDeadline := ctx.Deadline()
-	timeout := 5 * time.Second // Default timeout
-	if hasDeadline {
-		timeout = time.Until(deadline)
+	// Bound the evaluation by its timeout
+	ctx, cancel := startEvaluation(ctx)
+	defer cancel()
+	env := evaluationEnv(ctx, context)
+
+	// Get or compile program
+	program, err := e.getOrCompileProgram(expression, env)
+	if err != nil {
+		return nil, err
 	}
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case result := <-resultChan:
-		return result, nil
-	case err := <-errChan:
+	result, err := runSandboxed(ctx, program, env)
+	if ctx.Err() != nil || errors.Is(err, ErrResourceLimit) {
 		return nil, err
-	case <-time.After(timeout):
-		return nil, ErrEvaluationTimeout
 	}
+	if err != nil {
+		// Check if error is due to undefined variable
+		if strings.Contains(err.Error(), "undefined") || strings.Contains(err.Error(), "unknown name") {
+			return nil, fmt.Errorf("%w: %v", ErrUndefinedVariable, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+	}
+	return result, nil
 }
 
 // EvaluateBool evaluates a boolean expression and returns its boolean result.
@@ -109,37 +97,14 @@ func (e *exprEvaluator) EvaluateBool(ctx context.Context, expression string, con
 	return extractBoolResult(result, "expression")
 }
 
-// validateExpression checks for unsafe operations
+// validateExpression checks the expression's syntax tree for unsafe
+// operations
 func (e *exprEvaluator) validateExpression(expression string) error {
-	// List of unsafe patterns to block
-	unsafePatterns := []string{
-		"os.",
-		"exec.",
-		"http.",
-		"net.",
-		"syscall.",
-		"unsafe.",
-		"__proto__",
-		"ReadFile",
-		"WriteFile",
-		"Command",
-		"Get(",
-		"Post(",
-	}
-
-	lowerExpr := strings.ToLower(expression)
-	for _, pattern := range unsafePatterns {
-		if strings.Contains(lowerExpr, strings.ToLower(pattern)) {
-			return ErrUnsafeOperation
-		}
-	}
-
 	// Note: We intentionally don't check for infinite loops here
 	// (like "while(true)" or "factorial(1000000)") because we want
 	// the timeout mechanism to catch these and return ErrEvaluationTimeout
 	// rather than ErrUnsafeOperation
-
-	return nil
+	return CheckExpressionSafety(expression)
 }
 
 // getOrCompileProgram retrieves cached program or compiles new one
@@ -185,7 +150,13 @@ func (e *exprEvaluator) getOrCompileProgram(expression string, context map[strin
 		}),
 	}
 
+	options = append(options, helperOptions(context)...)
+	options = append(options, sandboxOptions()...)
+
 	program, err := expr.Compile(expression, options...)
+	if isNodeLimitError(err) {
+		return nil, fmt.Errorf("%w: %v", ErrResourceLimit, err)
+	}
 	if err != nil {
 		// Check if this is an infinite loop or long-running expression pattern
 		// These patterns would timeout or cause issues if they could compile
@@ -0,0 +1,307 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/builtin"
+)
+
+// Helper is a function shared by templates and expressions. Templates call it
+// as ${name(args)} or apply it as a filter, ${value | name:args}, where the
+// piped value is its first argument. Expressions call it as name(args) unless
+// expr-lang has a builtin of the same name, which takes precedence.
+type Helper struct {
+	Name string
+	// Usage shows how the helper is called, as in default(value, fallback)
+	Usage       string
+	Description string
+	Func        func(args ...interface{}) (interface{}, error)
+}
+
+// helpers is the helper registry, sorted by name
+var helpers = []Helper{
+	{
+		Name:        "capitalize",
+		Usage:       "capitalize(s)",
+		Description: "s with its first letter in upper case",
+		Func:        helperCapitalize,
+	},
+	{
+		Name:        "default",
+		Usage:       "default(value, fallback)",
+		Description: "fallback if value is missing or empty, value otherwise",
+		Func:        helperDefault,
+	},
+	{
+		Name:        "formatDate",
+		Usage:       "formatDate(date, layout)",
+		Description: "an RFC 3339 date formatted with a Go time layout",
+		Func:        helperFormatDate,
+	},
+	{
+		Name:        "formatNumber",
+		Usage:       "formatNumber(n, precision)",
+		Description: "n with precision decimal places",
+		Func:        helperFormatNumber,
+	},
+	{
+		Name:        "if",
+		Usage:       "if(condition, then, else)",
+		Description: "then if the boolean condition holds, else otherwise",
+		Func:        helperIf,
+	},
+	{
+		Name:        "join",
+		Usage:       "join(array, separator)",
+		Description: "the elements of array joined with separator",
+		Func:        helperJoin,
+	},
+	{
+		Name:        "json",
+		Usage:       "json(value)",
+		Description: "the JSON encoding of value",
+		Func:        helperJSON,
+	},
+	{
+		Name:        "length",
+		Usage:       "length(value)",
+		Description: "the number of elements of an array or map, or characters of a string",
+		Func:        helperLength,
+	},
+	{
+		Name:        "lower",
+		Usage:       "lower(s)",
+		Description: "s in lower case",
+		Func:        helperLower,
+	},
+	{
+		Name:        "replace",
+		Usage:       "replace(s, old, new)",
+		Description: "s with every occurrence of old replaced by new",
+		Func:        helperReplace,
+	},
+	{
+		Name:        "trim",
+		Usage:       "trim(s)",
+		Description: "s without leading and trailing white space",
+		Func:        helperTrim,
+	},
+	{
+		Name:        "truncate",
+		Usage:       "truncate(s, n)",
+		Description: "the first n characters of s",
+		Func:        helperTruncate,
+	},
+	{
+		Name:        "upper",
+		Usage:       "upper(s)",
+		Description: "s in upper case",
+		Func:        helperUpper,
+	},
+}
+
+// helpersByName indexes the registry
+var helpersByName = func() map[string]Helper {
+	byName := make(map[string]Helper, len(helpers))
+	for _, helper := range helpers {
+		byName[helper.Name] = helper
+	}
+	return byName
+}()
+
+// Helpers returns the registered helpers sorted by name
+func Helpers() []Helper {
+	all := make([]Helper, len(helpers))
+	copy(all, helpers)
+	return all
+}
+
+// LookupHelper returns the helper registered under name
+func LookupHelper(name string) (Helper, bool) {
+	helper, ok := helpersByName[name]
+	return helper, ok
+}
+
+// expressionReserved are helper names expressions cannot call because the
+// expression language uses them as keywords
+var expressionReserved = map[string]bool{"if": true}
+
+// helperOptions are the compile options adding the helpers to expressions,
+// except those named like an expr-lang builtin, a keyword or a variable of env
+func helperOptions(env map[string]interface{}) []expr.Option {
+	builtins := make(map[string]bool, len(builtin.Names))
+	for _, name := range builtin.Names {
+		builtins[name] = true
+	}
+	var options []expr.Option
+	for _, helper := range helpers {
+		if builtins[helper.Name] || expressionReserved[helper.Name] {
+			continue
+		}
+		if _, ok := env[helper.Name]; ok {
+			continue
+		}
+		options = append(options, expr.Function(helper.Name, helper.Func))
+	}
+	return options
+}
+
+func helperUpper(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("upper requires 1 argument")
+	}
+	return strings.ToUpper(fmt.Sprint(args[0])), nil
+}
+
+func helperLower(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("lower requires 1 argument")
+	}
+	return strings.ToLower(fmt.Sprint(args[0])), nil
+}
+
+func helperCapitalize(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("capitalize requires 1 argument")
+	}
+	s := fmt.Sprint(args[0])
+	if len(s) == 0 {
+		return "", nil
+	}
+	return strings.ToUpper(s[:1]) + s[1:], nil
+}
+
+func helperTrim(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("trim requires 1 argument")
+	}
+	return strings.TrimSpace(fmt.Sprint(args[0])), nil
+}
+
+func helperLength(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("length requires 1 argument")
+	}
+	val := reflect.ValueOf(args[0])
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String, reflect.Map:
+		return val.Len(), nil
+	default:
+		return nil, fmt.Errorf("%w: length() requires array, string, or map", ErrTypeMismatch)
+	}
+}
+
+func helperDefault(args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("default requires 2 arguments")
+	}
+	// Return the default value (second arg) if first arg is nil or empty
+	if args[0] == nil || args[0] == "" {
+		return args[1], nil
+	}
+	return args[0], nil
+}
+
+func helperJoin(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("join requires 2 arguments")
+	}
+	val := reflect.ValueOf(args[0])
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: join() requires array", ErrTypeMismatch)
+	}
+
+	sep := fmt.Sprint(args[1])
+	parts := make([]string, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		parts[i] = fmt.Sprint(val.Index(i).Interface())
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func helperFormatNumber(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("formatNumber requires 2 arguments")
+	}
+	num, ok := args[0].(float64)
+	if !ok {
+		if intNum, ok := args[0].(int); ok {
+			num = float64(intNum)
+		} else {
+			return nil, fmt.Errorf("%w: formatNumber() requires numeric value", ErrTypeMismatch)
+		}
+	}
+	precision, ok := args[1].(int)
+	if !ok {
+		return nil, fmt.Errorf("%w: formatNumber() precision must be integer", ErrTypeMismatch)
+	}
+	return fmt.Sprintf("%."+strconv.Itoa(precision)+"f", num), nil
+}
+
+func helperFormatDate(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("formatDate requires 2 arguments")
+	}
+	dateStr := fmt.Sprint(args[0])
+	layout := fmt.Sprint(args[1])
+
+	// Try to parse the date
+	t, err := time.Parse(time.RFC3339, dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format: %v", err)
+	}
+	return t.Format(layout), nil
+}
+
+func helperIf(args ...interface{}) (interface{}, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("if requires 3 arguments")
+	}
+	condition, ok := args[0].(bool)
+	if !ok {
+		return nil, fmt.Errorf("%w: if() condition must be boolean", ErrTypeMismatch)
+	}
+	if condition {
+		return args[1], nil
+	}
+	return args[2], nil
+}
+
+func helperReplace(args ...interface{}) (interface{}, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("replace requires 3 arguments")
+	}
+	return strings.ReplaceAll(fmt.Sprint(args[0]), fmt.Sprint(args[1]), fmt.Sprint(args[2])), nil
+}
+
+func helperTruncate(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("truncate requires 2 arguments")
+	}
+	n, ok := args[1].(int)
+	if !ok || n < 0 {
+		return nil, fmt.Errorf("%w: truncate() length must be a non-negative integer", ErrTypeMismatch)
+	}
+	runes := []rune(fmt.Sprint(args[0]))
+	if len(runes) <= n {
+		return string(runes), nil
+	}
+	return string(runes[:n]), nil
+}
+
+func helperJSON(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("json requires 1 argument")
+	}
+	data, err := json.Marshal(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: json() cannot encode value: %v", ErrTypeMismatch, err)
+	}
+	return string(data), nil
+}
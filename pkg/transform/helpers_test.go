@@ -0,0 +1,73 @@
+package transform
+
+import (
+	"context"
+	"testing"
+)
+
+// TestHelperRegistry tests that helpers are listed, looked up and callable
+// from expressions
+func TestHelperRegistry(t *testing.T) {
+	all := Helpers()
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Name >= all[i].Name {
+			t.Errorf("Helpers() not sorted: %s before %s", all[i-1].Name, all[i].Name)
+		}
+	}
+	for _, helper := range all {
+		if helper.Usage == "" || helper.Description == "" || helper.Func == nil {
+			t.Errorf("helper %s is not documented or has no function", helper.Name)
+		}
+	}
+
+	if _, ok := LookupHelper("truncate"); !ok {
+		t.Error("LookupHelper(truncate) not found")
+	}
+	if _, ok := LookupHelper("shout"); ok {
+		t.Error("LookupHelper(shout) found")
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		context    map[string]interface{}
+		want       interface{}
+	}{
+		{
+			name:       "helper without builtin",
+			expression: `truncate(capitalize(name), 3)`,
+			context:    map[string]interface{}{"name": "alice"},
+			want:       "Ali",
+		},
+		{
+			name:       "default helper",
+			expression: `default(nickname, "guest")`,
+			context:    map[string]interface{}{"nickname": ""},
+			want:       "guest",
+		},
+		{
+			name:       "builtin takes precedence",
+			expression: `upper(name)`,
+			context:    map[string]interface{}{"name": "bob"},
+			want:       "BOB",
+		},
+		{
+			name:       "variable shadows helper",
+			expression: `json + 1`,
+			context:    map[string]interface{}{"json": 1},
+			want:       2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExpressionEvaluator().Evaluate(context.Background(), tt.expression, tt.context)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
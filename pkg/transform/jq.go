@@ -0,0 +1,173 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// MaxJQResults caps the values a jq program may produce
+const MaxJQResults = ExpressionMemoryBudget
+
+// jqVariablePattern matches the $name references of a jq program
+var jqVariablePattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// jqBindingPattern matches where a jq program binds variables itself: the
+// patterns of ... as $name, as [$a, $b] or as {key: $v}, and the parameter
+// lists of def name($a; $b)
+var jqBindingPattern = regexp.MustCompile(`\bas\s*(\$[A-Za-z_][A-Za-z0-9_]*|\[[^\]]*\]|\{[^}]*\})|\bdef\s+[A-Za-z_][A-Za-z0-9_]*\s*\(([^)]*)\)`)
+
+// IsJQExpression reports whether expr is written as jq(program)
+func IsJQExpression(expr string) bool {
+	trimmed := strings.TrimSpace(expr)
+	return strings.HasPrefix(trimmed, "jq(") && strings.HasSuffix(trimmed, ")")
+}
+
+// jqProgram returns the program of a jq(program) expression, or expr itself
+func jqProgram(expr string) string {
+	trimmed := strings.TrimSpace(expr)
+	if IsJQExpression(trimmed) {
+		return trimmed[len("jq(") : len(trimmed)-1]
+	}
+	return trimmed
+}
+
+// CheckJQ reports whether a jq program, bare or as jq(program), parses
+func CheckJQ(program string) error {
+	if _, err := gojq.Parse(jqProgram(program)); err != nil {
+		return fmt.Errorf("%w: jq: %v", ErrInvalidExpression, err)
+	}
+	return nil
+}
+
+// JQVariableReferences returns the names of the variables a jq program
+// reads as $name, in order of first use. Variables the program binds
+// itself and $ENV are not included.
+func JQVariableReferences(program string) []string {
+	program = jqProgram(program)
+	bound := map[string]bool{"ENV": true, "__loc__": true}
+	for _, binding := range jqBindingPattern.FindAllString(program, -1) {
+		for _, match := range jqVariablePattern.FindAllStringSubmatch(binding, -1) {
+			bound[match[1]] = true
+		}
+	}
+	var names []string
+	for _, match := range jqVariablePattern.FindAllStringSubmatch(program, -1) {
+		if name := match[1]; !bound[name] {
+			bound[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// QueryJQ runs a jq program on input, as in jq(.items | map(.price) | add)
+// or the bare program. The variables the program refers to as $name are
+// bound from variables. A program producing one value returns it, one
+// producing several returns them as an array, and one producing none
+// returns nil. The environment, $ENV, is empty.
+func QueryJQ(ctx context.Context, program string, input interface{}, variables map[string]interface{}) (interface{}, error) {
+	program = jqProgram(program)
+	query, err := gojq.Parse(program)
+	if err != nil {
+		return nil, fmt.Errorf("%w: jq: %v", ErrInvalidExpression, err)
+	}
+
+	names, values, err := jqVariables(program, variables)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gojq.Compile(query, gojq.WithVariables(names))
+	if err != nil {
+		return nil, fmt.Errorf("%w: jq: %v", ErrInvalidExpression, err)
+	}
+
+	data, err := jqInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := startEvaluation(ctx)
+	defer cancel()
+
+	var results []interface{}
+	iter := code.RunWithContext(ctx, data, values...)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, isErr := value.(error); isErr {
+			if ctx.Err() != nil {
+				return nil, evaluationError(ctx)
+			}
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				break
+			}
+			return nil, fmt.Errorf("jq: %w", err)
+		}
+		if len(results) == MaxJQResults {
+			return nil, fmt.Errorf("%w: jq program produced more than %d values", ErrResourceLimit, MaxJQResults)
+		}
+		results = append(results, normalizeNumbers(value))
+	}
+	if ctx.Err() != nil {
+		return nil, evaluationError(ctx)
+	}
+
+	switch len(results) {
+	case 0:
+		return nil, nil
+	case 1:
+		return results[0], nil
+	default:
+		return results, nil
+	}
+}
+
+// jqVariables returns the names, with their $, and values of the variables
+// a program reads. References to unknown variables are left for the
+// compiler to report.
+func jqVariables(program string, variables map[string]interface{}) ([]string, []interface{}, error) {
+	var referenced []string
+	for _, name := range JQVariableReferences(program) {
+		if _, ok := variables[name]; ok {
+			referenced = append(referenced, name)
+		}
+	}
+
+	names := make([]string, len(referenced))
+	values := make([]interface{}, len(referenced))
+	for i, name := range referenced {
+		value, err := jqInput(variables[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("variable '%s': %w", name, err)
+		}
+		names[i] = "$" + name
+		values[i] = value
+	}
+	return names, values, nil
+}
+
+// jqInput converts a value to the JSON types gojq works on. Lazy values are
+// decoded from their JSON encoding.
+func jqInput(value interface{}) (interface{}, error) {
+	if lazy, ok := value.(LazyValue); ok {
+		data, err := readLazyJSON(lazy)
+		if err != nil {
+			return nil, err
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode lazy value: %w", err)
+		}
+		return normalizeNumbers(decoded), nil
+	}
+	return normalizeJSONData(value)
+}
@@ -0,0 +1,126 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestQueryJQ tests running jq programs on input values
+func TestQueryJQ(t *testing.T) {
+	input := map[string]interface{}{
+		"data": []interface{}{
+			map[string]interface{}{"name": "pen", "price": 1.5, "qty": 4},
+			map[string]interface{}{"name": "ink", "price": 2.5, "qty": 2},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		program   string
+		variables map[string]interface{}
+		want      interface{}
+		wantErr   error
+	}{
+		{name: "wrapped program", program: "jq(.data | map(.price) | add)", want: 4},
+		{name: "bare program", program: ".data[0].name", want: "pen"},
+		{name: "object construction", program: "jq(.data[1] | {item: .name, total: (.price * .qty)})", want: map[string]interface{}{"item": "ink", "total": 5}},
+		{name: "several results become an array", program: "jq(.data[].name)", want: []interface{}{"pen", "ink"}},
+		{name: "no results", program: "jq(.data[] | select(.qty > 10))", want: nil},
+		{
+			name:      "variables",
+			program:   "jq(.data | map(select(.qty >= $min)) | length)",
+			variables: map[string]interface{}{"min": 3, "unused": "x"},
+			want:      1,
+		},
+		{name: "environment is empty", program: "jq($ENV | length)", want: 0},
+		{name: "syntax error", program: "jq(.data | map()", wantErr: ErrInvalidExpression},
+		{name: "undefined variable", program: "jq($missing)", wantErr: ErrInvalidExpression},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryJQ(context.Background(), tt.program, input, tt.variables)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("QueryJQ() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("QueryJQ() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryJQ() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestQueryJQLimits tests that jq programs stop at the evaluation timeout,
+// on runtime errors, and on lazy input
+func TestQueryJQLimits(t *testing.T) {
+	ctx := WithEvaluationTimeout(context.Background(), 10*time.Millisecond)
+	if _, err := QueryJQ(ctx, "jq([range(1; infinite)] | length)", nil, nil); !errors.Is(err, ErrEvaluationTimeout) {
+		t.Errorf("QueryJQ() error = %v, want %v", err, ErrEvaluationTimeout)
+	}
+
+	if _, err := QueryJQ(context.Background(), `jq(error("bad data"))`, nil, nil); err == nil {
+		t.Error("QueryJQ() of error() succeeded")
+	}
+
+	lazy := &jsonSource{json: `{"items": [1, 2, 3]}`}
+	got, err := QueryJQ(context.Background(), "jq(.items | add)", lazy, nil)
+	if err != nil || got != 6 {
+		t.Errorf("QueryJQ() on lazy input = %v, %v, want 6", got, err)
+	}
+}
+
+// TestJQVariableReferences tests finding the variables a program reads
+func TestJQVariableReferences(t *testing.T) {
+	program := `jq(.items | map(select(.qty >= $min)) | reduce .[] as $item (0; . + $item.price) | [., $ENV, $limit, $min])`
+	if got, want := JQVariableReferences(program), []string{"min", "limit"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("JQVariableReferences() = %v, want %v", got, want)
+	}
+	if got := JQVariableReferences("def scale($f): . * $f; .x | scale(2) as [$a, $b] | {$a, b: $b}"); len(got) != 0 {
+		t.Errorf("JQVariableReferences() with local bindings = %v, want none", got)
+	}
+	if got, want := JQVariableReferences("reduce .[] as $x ($start; . + $x)"), []string{"start"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("JQVariableReferences() = %v, want %v", got, want)
+	}
+	if err := CheckJQ("jq(.a | )"); !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("CheckJQ() error = %v, want %v", err, ErrInvalidExpression)
+	}
+}
+
+// TestTransformJQDetection tests that jq(...) expressions are detected and
+// that languages are selected by name
+func TestTransformJQDetection(t *testing.T) {
+	if got := detectTransformType("jq(.items | map(.price) | add)"); got != TransformTypeJQ {
+		t.Errorf("detectTransformType() = %v, want jq", got)
+	}
+
+	for _, name := range TransformTypeNames() {
+		transformType, err := ParseTransformType(name)
+		if err != nil || transformType.String() != name {
+			t.Errorf("ParseTransformType(%q) = %v, %v", name, transformType, err)
+		}
+	}
+	if _, err := ParseTransformType("xslt"); err == nil {
+		t.Error("ParseTransformType(xslt) succeeded")
+	}
+
+	// A program selected as jq need not be wrapped, and a JSONPath-looking
+	// program runs as jq
+	input := map[string]interface{}{"total": 3}
+	got, err := NewTransformer().TransformAs(context.Background(), TransformTypeJQ, ".total * 2", input, nil)
+	if err != nil || got != 6 {
+		t.Errorf("TransformAs(jq) = %v, %v, want 6", got, err)
+	}
+	got, err = NewTransformer().TransformAs(context.Background(), TransformTypeUnknown, "$.total", input, nil)
+	if err != nil || got != 3 {
+		t.Errorf("TransformAs(auto) = %v, %v, want 3", got, err)
+	}
+}
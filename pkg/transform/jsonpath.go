@@ -3,13 +3,15 @@ package transform
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
 	"github.com/expr-lang/expr/vm"
 	"github.com/tidwall/gjson"
 )
@@ -27,8 +29,21 @@ func NewJSONPathQuerier() JSONPathQuerier {
 	return &gjsonQuerier{}
 }
 
-// Query executes a JSONPath query against the provided data
+// Query executes a JSONPath query against the provided data. Its filters
+// are evaluated within the evaluation timeout.
 func (q *gjsonQuerier) Query(ctx context.Context, path string, data interface{}) (interface{}, error) {
+	ctx, cancel := startEvaluation(ctx)
+	defer cancel()
+
+	result, err := q.query(ctx, path, data)
+	if ctx.Err() != nil {
+		return nil, evaluationError(ctx)
+	}
+	return result, err
+}
+
+// query executes a JSONPath query with the evaluation's context
+func (q *gjsonQuerier) query(ctx context.Context, path string, data interface{}) (interface{}, error) {
 	// Check for nil data
 	if data == nil {
 		return nil, ErrNilData
@@ -54,10 +69,31 @@ func (q *gjsonQuerier) Query(ctx context.Context, path string, data interface{})
 		return nil, ErrInvalidJSONPath
 	}
 
+	// Unions and quoted field names are beyond the gjson conversion
+	steps, ok, err := parseNativePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if ok && hasSelection(steps) {
+		return queryUnion(path, steps, data)
+	}
+
+	// Walk maps and slices directly when the path allows, rather than
+	// serializing them for gjson
+	if _, lazy := data.(LazyValue); !lazy {
+		if result, ok := queryNative(path, data); ok {
+			return result, nil
+		}
+	}
+	return queryJSON(ctx, path, data)
+}
+
+// queryJSON executes a validated JSONPath query on the JSON encoding of data
+func queryJSON(ctx context.Context, path string, data interface{}) (interface{}, error) {
 	// Convert data to JSON string for gjson
-	jsonBytes, err := json.Marshal(data)
+	jsonBytes, data, err := jsonPathInput(path, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal data: %w", err)
+		return nil, err
 	}
 
 	jsonStr := string(jsonBytes)
@@ -75,7 +111,7 @@ func (q *gjsonQuerier) Query(ctx context.Context, path string, data interface{})
 		parts := strings.Split(path, "..")
 		if len(parts) > 1 {
 			pattern := strings.TrimPrefix(parts[1], ".")
-			return handleRecursiveDescentPattern(jsonStr, pattern, data)
+			return handleRecursiveDescentPattern(ctx, jsonStr, pattern, data)
 		}
 	}
 
@@ -83,7 +119,7 @@ func (q *gjsonQuerier) Query(ctx context.Context, path string, data interface{})
 	// Must be checked BEFORE wildcard handling because patterns like @.roles[*] contains "admin"
 	// contain [*] but need special filter handling
 	if hasContainsFilter(path) {
-		return handleContainsFilter(jsonStr, path)
+		return handleContainsFilter(ctx, jsonStr, path)
 	}
 
 	// Check if we have a filter followed by wildcard operations
@@ -252,6 +288,9 @@ func convertJSONPathToGJSON(path string) (string, error) {
 	// But keep [-1] as .-1 (gjson supports negative indices)
 	result = replaceArrayIndexes(result)
 
+	// An index into a root array, $[0], is the gjson path 0, not .0
+	result = strings.TrimPrefix(result, ".")
+
 	return result, nil
 }
 
@@ -652,7 +691,7 @@ func handleNestedWildcards(jsonStr, queryPath string) (interface{}, error) {
 
 // handleRecursiveDescentPattern handles recursive descent queries with complex patterns
 // Examples: $..name, $..name[?(@.active == true)], $..items[*].id, $..[?(@.active == true)].name
-func handleRecursiveDescentPattern(jsonStr, pattern string, originalData interface{}) (interface{}, error) {
+func handleRecursiveDescentPattern(ctx context.Context, jsonStr, pattern string, originalData interface{}) (interface{}, error) {
 	// Check if pattern has filter or array operations
 	hasFilter := strings.Contains(pattern, "[?(")
 	hasWildcard := strings.Contains(pattern, "[*]")
@@ -687,7 +726,7 @@ func handleRecursiveDescentPattern(jsonStr, pattern string, originalData interfa
 
 		// Find all objects recursively that match the filter
 		var results []interface{}
-		findAllObjectsWithFilter(data, filterExpr, afterFilter, &results)
+		findAllObjectsWithFilter(ctx, data, filterExpr, afterFilter, &results)
 
 		if len(results) == 0 {
 			return nil, nil
@@ -725,7 +764,7 @@ func handleRecursiveDescentPattern(jsonStr, pattern string, originalData interfa
 
 		// Find all objects that have the base field
 		var results []interface{}
-		findRecursiveWithFilter(data, baseField, filterExpr, &results)
+		findRecursiveWithFilter(ctx, data, baseField, filterExpr, &results)
 
 		if len(results) == 0 {
 			return nil, nil
@@ -753,7 +792,7 @@ func handleRecursiveDescentPattern(jsonStr, pattern string, originalData interfa
 			queryPath := "$" + afterBracket
 
 			// Query this result
-			result, err := querier.Query(context.Background(), queryPath, baseResult)
+			result, err := querier.Query(ctx, queryPath, baseResult)
 			if err == nil && result != nil {
 				// Flatten results if it's an array
 				if arr, ok := result.([]interface{}); ok {
@@ -777,11 +816,11 @@ func handleRecursiveDescentPattern(jsonStr, pattern string, originalData interfa
 // findAllObjectsWithFilter finds all objects recursively that match a filter, then extracts a field
 // For example, $..[?(@.active == true)].name finds all objects where active==true, then gets their name field
 // Note: Security validation must be done by caller before calling this function
-func findAllObjectsWithFilter(data interface{}, filterExpr string, fieldToExtract string, results *[]interface{}) {
+func findAllObjectsWithFilter(ctx context.Context, data interface{}, filterExpr string, fieldToExtract string, results *[]interface{}) {
 	switch v := data.(type) {
 	case map[string]interface{}:
 		// Check if this object matches the filter
-		if evaluateFilter(v, filterExpr) {
+		if evaluateFilter(ctx, v, filterExpr) {
 			// Extract the specified field if present
 			if fieldToExtract != "" {
 				if val, ok := v[fieldToExtract]; ok {
@@ -794,12 +833,12 @@ func findAllObjectsWithFilter(data interface{}, filterExpr string, fieldToExtrac
 		}
 		// Recurse into all values
 		for _, mapVal := range v {
-			findAllObjectsWithFilter(mapVal, filterExpr, fieldToExtract, results)
+			findAllObjectsWithFilter(ctx, mapVal, filterExpr, fieldToExtract, results)
 		}
 	case []interface{}:
 		// Recurse into array elements
 		for _, item := range v {
-			findAllObjectsWithFilter(item, filterExpr, fieldToExtract, results)
+			findAllObjectsWithFilter(ctx, item, filterExpr, fieldToExtract, results)
 		}
 	}
 }
@@ -807,24 +846,24 @@ func findAllObjectsWithFilter(data interface{}, filterExpr string, fieldToExtrac
 // findRecursiveWithFilter finds objects with a specific field that match a filter condition
 // For example, $..name[?(@.active == true)] finds all objects that have a "name" field
 // where the parent object's "active" field is true, then returns the "name" values
-func findRecursiveWithFilter(data interface{}, fieldName string, filterExpr string, results *[]interface{}) {
+func findRecursiveWithFilter(ctx context.Context, data interface{}, fieldName string, filterExpr string, results *[]interface{}) {
 	switch v := data.(type) {
 	case map[string]interface{}:
 		// Check if this map has the field
 		if val, ok := v[fieldName]; ok {
 			// Evaluate the filter on this object
-			if evaluateFilter(v, filterExpr) {
+			if evaluateFilter(ctx, v, filterExpr) {
 				*results = append(*results, val)
 			}
 		}
 		// Recurse into all values
 		for _, mapVal := range v {
-			findRecursiveWithFilter(mapVal, fieldName, filterExpr, results)
+			findRecursiveWithFilter(ctx, mapVal, fieldName, filterExpr, results)
 		}
 	case []interface{}:
 		// Recurse into array elements
 		for _, item := range v {
-			findRecursiveWithFilter(item, fieldName, filterExpr, results)
+			findRecursiveWithFilter(ctx, item, fieldName, filterExpr, results)
 		}
 	}
 }
@@ -832,11 +871,23 @@ func findRecursiveWithFilter(data interface{}, fieldName string, filterExpr stri
 // evaluateFilter evaluates a filter expression on an object using sandboxed expr-lang evaluation
 // Examples: "@.active == true", "@.price > 100", "@.status == 'pending'", "@.roles[*] contains 'admin'"
 // Security: Uses same sandbox configuration as expression.go to prevent code injection
-func evaluateFilter(obj map[string]interface{}, filterExpr string) bool {
+func evaluateFilter(ctx context.Context, obj map[string]interface{}, filterExpr string) bool {
+	matched, err := evaluateFilterStrict(ctx, obj, filterExpr)
+	if err != nil {
+		return false
+	}
+	return matched
+}
+
+// evaluateFilterStrict is like evaluateFilter but reports why an object could
+// not be evaluated instead of treating every failure as a non-match.
+// Missing fields surface as ErrMissingField, non-boolean results as ErrTypeMismatch.
+// It runs until ctx ends, reporting ErrEvaluationTimeout if its timeout expired.
+func evaluateFilterStrict(ctx context.Context, obj map[string]interface{}, filterExpr string) (bool, error) {
 	// First, validate expression for unsafe operations (same as expression.go)
 	if err := validateFilterExpression(filterExpr); err != nil {
 		// Reject unsafe expressions
-		return false
+		return false, err
 	}
 
 	// Remove @. prefix - it's JSONPath syntax, not needed for expr-lang
@@ -849,71 +900,120 @@ func evaluateFilter(obj map[string]interface{}, filterExpr string) bool {
 		filterExpr = convertContainsToExprLang(filterExpr)
 	}
 
+	// Check the fields the filter reads against the object before compiling,
+	// as the compiler rejects both missing fields and mismatched operands
+	if tree, err := parser.Parse(filterExpr); err == nil {
+		if err := checkFilterOperands(tree.Node, obj); err != nil {
+			return false, err
+		}
+	}
+
 	// Compile expression with sandboxed options (same as expression.go)
-	program, err := compileFilterExpression(filterExpr, obj)
+	env := evaluationEnv(ctx, obj)
+	program, err := compileFilterExpression(filterExpr, env)
 	if err != nil {
-		// Invalid expression - reject
-		return false
+		return false, err
 	}
 
-	// Execute with timeout protection (1 second default for filter expressions)
-	resultChan := make(chan interface{}, 1)
-	errChan := make(chan error, 1)
+	result, err := runSandboxed(ctx, program, env)
+	if ctx.Err() != nil || errors.Is(err, ErrResourceLimit) {
+		return false, err
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrTypeMismatch, err)
+	}
+	// Type assert to boolean
+	if boolResult, ok := result.(bool); ok {
+		return boolResult, nil
+	}
+	return false, fmt.Errorf("%w: filter returned %T, expected bool", ErrTypeMismatch, result)
+}
 
-	go func() {
-		result, err := vm.Run(program, obj)
-		if err != nil {
-			errChan <- err
-			return
+// checkFilterOperands reports the first field a filter expression reads that
+// obj lacks, as ErrMissingField, and the first operator applied to fields or
+// literals of types it does not accept, as ErrTypeMismatch. Functions are
+// called by name, so only their arguments are checked.
+func checkFilterOperands(node ast.Node, obj map[string]interface{}) error {
+	switch n := node.(type) {
+	case *ast.IdentifierNode:
+		if _, ok := obj[n.Value]; !ok {
+			return fmt.Errorf("%w: %s", ErrMissingField, n.Value)
+		}
+	case *ast.MemberNode:
+		return checkFilterOperands(n.Node, obj)
+	case *ast.ChainNode:
+		return checkFilterOperands(n.Node, obj)
+	case *ast.UnaryNode:
+		return checkFilterOperands(n.Node, obj)
+	case *ast.CallNode:
+		return checkFilterOperandList(n.Arguments, obj)
+	case *ast.BuiltinNode:
+		return checkFilterOperandList(n.Arguments, obj)
+	case *ast.ArrayNode:
+		return checkFilterOperandList(n.Nodes, obj)
+	case *ast.ConditionalNode:
+		return checkFilterOperandList([]ast.Node{n.Cond, n.Exp1, n.Exp2}, obj)
+	case *ast.BinaryNode:
+		if err := checkFilterOperandList([]ast.Node{n.Left, n.Right}, obj); err != nil {
+			return err
+		}
+		left, right := filterOperandType(n.Left, obj), filterOperandType(n.Right, obj)
+		if left == "" || right == "" {
+			return nil
+		}
+		var accepted bool
+		switch n.Operator {
+		case "<", ">", "<=", ">=", "+":
+			accepted = left == right && (left == "number" || left == "string")
+		case "-", "*", "/", "%", "**", "^":
+			accepted = left == "number" && right == "number"
+		default:
+			accepted = true
+		}
+		if !accepted {
+			return fmt.Errorf("%w: cannot apply %s to %s and %s", ErrTypeMismatch, n.Operator, left, right)
 		}
-		resultChan <- result
-	}()
-
-	// Wait for result or timeout
-	timeout := 1 * time.Second
+	}
+	return nil
+}
 
-	select {
-	case result := <-resultChan:
-		// Type assert to boolean
-		if boolResult, ok := result.(bool); ok {
-			return boolResult
+// checkFilterOperandList applies checkFilterOperands to each node in turn
+func checkFilterOperandList(nodes []ast.Node, obj map[string]interface{}) error {
+	for _, node := range nodes {
+		if err := checkFilterOperands(node, obj); err != nil {
+			return err
 		}
-		return false
-	case <-errChan:
-		return false
-	case <-time.After(timeout):
-		// Timeout - reject expression
-		return false
 	}
+	return nil
 }
 
-// validateFilterExpression checks for unsafe operations in filter expressions
-// Same security model as expression.go
-func validateFilterExpression(expression string) error {
-	// List of unsafe patterns to block (same as expression.go)
-	unsafePatterns := []string{
-		"os.",
-		"exec.",
-		"http.",
-		"net.",
-		"syscall.",
-		"unsafe.",
-		"__proto__",
-		"ReadFile",
-		"WriteFile",
-		"Command",
-		"Get(",
-		"Post(",
-	}
+// filterOperandType returns the JSON type of a literal or of a field of obj,
+// or "" when it is only known once the filter runs
+func filterOperandType(node ast.Node, obj map[string]interface{}) string {
+	switch n := node.(type) {
+	case *ast.IntegerNode, *ast.FloatNode:
+		return "number"
+	case *ast.StringNode:
+		return "string"
+	case *ast.BoolNode:
+		return "boolean"
+	case *ast.IdentifierNode:
+		if value, ok := obj[n.Value]; ok && value != nil {
+			return jsonTypeName(value)
+		}
+	}
+	return ""
+}
 
-	lowerExpr := strings.ToLower(expression)
-	for _, pattern := range unsafePatterns {
-		if strings.Contains(lowerExpr, strings.ToLower(pattern)) {
-			return ErrUnsafeOperation
-		}
+// validateFilterExpression checks the syntax tree of a filter expression
+// for unsafe operations, as it is evaluated: without @. prefixes and with
+// contains rewritten. Same security model as expression.go.
+func validateFilterExpression(expression string) error {
+	expression = strings.ReplaceAll(expression, "@.", "")
+	if strings.Contains(expression, " contains ") {
+		expression = convertContainsToExprLang(expression)
 	}
-
-	return nil
+	return CheckExpressionSafety(expression)
 }
 
 // compileFilterExpression compiles a filter expression with sandboxed options
@@ -953,7 +1053,12 @@ func compileFilterExpression(expression string, context map[string]interface{})
 		}),
 	}
 
+	options = append(options, sandboxOptions()...)
+
 	program, err := expr.Compile(expression, options...)
+	if isNodeLimitError(err) {
+		return nil, fmt.Errorf("%w: %v", ErrResourceLimit, err)
+	}
 	if err != nil {
 		// Check if this is an infinite loop or long-running expression pattern
 		if strings.Contains(expression, "while(true)") ||
@@ -1498,7 +1603,7 @@ func handleFilteredWildcardPath(jsonStr, path string) (interface{}, error) {
 
 // handleContainsFilter handles queries with contains operator
 // $.users[?(@.roles[*] contains "admin")].email
-func handleContainsFilter(jsonStr, path string) (interface{}, error) {
+func handleContainsFilter(ctx context.Context, jsonStr, path string) (interface{}, error) {
 	// Find the filter
 	filterStart := strings.Index(path, "[?(")
 	if filterStart == -1 {
@@ -1584,7 +1689,7 @@ func handleContainsFilter(jsonStr, path string) (interface{}, error) {
 	var filteredResults []interface{}
 	for _, item := range baseArray {
 		if itemMap, ok := item.(map[string]interface{}); ok {
-			if evaluateFilter(itemMap, filterExpr) {
+			if evaluateFilter(ctx, itemMap, filterExpr) {
 				// If there's an afterFilter path, extract that field
 				if afterFilter != "" {
 					afterFilter = strings.TrimPrefix(afterFilter, ".")
@@ -0,0 +1,142 @@
+package transform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+// jsonPathCase is a representative JSONPath query with the data it runs on
+// and the P95 latency it must stay within
+type jsonPathCase struct {
+	name   string
+	path   string
+	data   interface{}
+	budget time.Duration
+}
+
+// benchmarkStore builds an order store with n orders of three items each
+func benchmarkStore(n int) map[string]interface{} {
+	orders := make([]interface{}, n)
+	for i := range orders {
+		items := make([]interface{}, 3)
+		for j := range items {
+			items[j] = map[string]interface{}{
+				"sku":   fmt.Sprintf("sku-%d-%d", i, j),
+				"price": float64((i*7+j*13)%200) + 0.99,
+				"qty":   (i + j) % 5,
+			}
+		}
+		orders[i] = map[string]interface{}{
+			"id":     i,
+			"status": []string{"pending", "shipped", "delivered"}[i%3],
+			"customer": map[string]interface{}{
+				"name":  fmt.Sprintf("customer %d", i),
+				"email": fmt.Sprintf("c%d@example.com", i),
+				"tier":  []string{"free", "pro"}[i%2],
+			},
+			"items": items,
+		}
+	}
+	return map[string]interface{}{"store": map[string]interface{}{"name": "bench", "orders": orders}}
+}
+
+// jsonPathCases are the queries benchmarked and held to a budget: field
+// access, indexes and slices, wildcards, filters and recursive descent on
+// 100 orders, and the same shapes on 5,000. Budgets are five to ten times
+// the current P95 or more. Fields, indexes, wildcards and recursive descent
+// to a field walk the data directly, and their budgets fail if they go back
+// to serializing it; slices and filters still serialize it.
+func jsonPathCases() []jsonPathCase {
+	small := benchmarkStore(100)
+	large := benchmarkStore(5000)
+	return []jsonPathCase{
+		{name: "field", path: "$.store.name", data: small, budget: 250 * time.Microsecond},
+		{name: "index", path: "$.store.orders[42].customer.email", data: small, budget: 250 * time.Microsecond},
+		{name: "negative_index", path: "$.store.orders[-1].id", data: small, budget: 250 * time.Microsecond},
+		{name: "slice", path: "$.store.orders[10:20]", data: small, budget: 10 * time.Millisecond},
+		{name: "wildcard", path: "$.store.orders[*].customer.email", data: small, budget: 500 * time.Microsecond},
+		{name: "nested_wildcard", path: "$.store.orders[*].items[*].sku", data: small, budget: time.Millisecond},
+		{name: "filter", path: "$.store.orders[?(@.status == 'pending')]", data: small, budget: 20 * time.Millisecond},
+		{name: "filter_and", path: "$.store.orders[?(@.id > 50 && @.status == 'shipped')]", data: small, budget: 20 * time.Millisecond},
+		{name: "filter_then_wildcard", path: "$.store.orders[?(@.status == 'pending')].items[*].sku", data: small, budget: 10 * time.Millisecond},
+		{name: "recursive", path: "$..email", data: small, budget: 2 * time.Millisecond},
+		{name: "recursive_filter", path: "$..[?(@.tier == 'pro')].name", data: small, budget: 200 * time.Millisecond},
+		{name: "large_index", path: "$.store.orders[4999].customer.name", data: large, budget: 250 * time.Microsecond},
+		{name: "large_wildcard", path: "$.store.orders[*].id", data: large, budget: 10 * time.Millisecond},
+		{name: "large_filter", path: "$.store.orders[?(@.status == 'delivered')].id", data: large, budget: 300 * time.Millisecond},
+		{name: "large_recursive", path: "$..sku", data: large, budget: 100 * time.Millisecond},
+	}
+}
+
+// Each budgeted query runs for at least budgetSamples runs and at most
+// budgetSampleTime, unless it has maxBudgetSamples runs by then
+const (
+	budgetSamples    = 10
+	maxBudgetSamples = 200
+	budgetSampleTime = 300 * time.Millisecond
+)
+
+// BenchmarkJSONPathQuery measures each representative query
+func BenchmarkJSONPathQuery(b *testing.B) {
+	querier := NewJSONPathQuerier()
+	ctx := context.Background()
+	for _, tc := range jsonPathCases() {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := querier.Query(ctx, tc.path, tc.data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestJSONPathPerformanceBudget fails when the 95th percentile latency of a
+// representative query exceeds its budget. The budgets leave headroom for
+// slow machines, so a failure means a regression of several times, not noise.
+// They do not hold under the race detector, so it skips there as in short mode.
+func TestJSONPathPerformanceBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping performance budget in short mode")
+	}
+	if raceEnabled {
+		t.Skip("skipping performance budget under the race detector")
+	}
+
+	querier := NewJSONPathQuerier()
+	ctx := context.Background()
+	for _, tc := range jsonPathCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := querier.Query(ctx, tc.path, tc.data); err != nil {
+				t.Fatalf("Query(%s) error = %v", tc.path, err)
+			}
+
+			var latencies []time.Duration
+			began := time.Now()
+			for len(latencies) < budgetSamples || (len(latencies) < maxBudgetSamples && time.Since(began) < budgetSampleTime) {
+				start := time.Now()
+				if _, err := querier.Query(ctx, tc.path, tc.data); err != nil {
+					t.Fatalf("Query(%s) error = %v", tc.path, err)
+				}
+				latencies = append(latencies, time.Since(start))
+			}
+			if p95 := percentile(latencies, 95); p95 > tc.budget {
+				t.Errorf("Query(%s) P95 = %v, budget %v", tc.path, p95, tc.budget)
+			}
+		})
+	}
+}
+
+// percentile returns the pth percentile of latencies, sorting them
+func percentile(latencies []time.Duration, p int) time.Duration {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	index := (len(latencies)*p+99)/100 - 1
+	if index < 0 {
+		index = 0
+	}
+	return latencies[index]
+}
@@ -0,0 +1,494 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// nativeStepKind is the kind of a step of a path queryNative evaluates
+type nativeStepKind int
+
+const (
+	// stepField selects a field of an object, as in .name
+	stepField nativeStepKind = iota
+	// stepIndex selects an array element, from the end if negative, as in [2]
+	stepIndex
+	// stepWildcard selects every array element, as in [*]
+	stepWildcard
+	// stepIndexUnion selects several array elements, as in [0,2,-1]
+	stepIndexUnion
+	// stepFieldUnion selects several fields of an object into an object, as
+	// in ['name','email']
+	stepFieldUnion
+)
+
+// nativeStep is one step of a path queryNative or queryUnion evaluates
+type nativeStep struct {
+	kind    nativeStepKind
+	name    string
+	index   int
+	indexes []int
+	names   []string
+	// bracketed marks a field written as ['name'], which gjson cannot read
+	bracketed bool
+}
+
+// queryNative evaluates the common JSONPath forms by walking maps and
+// slices, without serializing data for gjson: field names, array indexes,
+// [*] wildcards and recursive descent to a field, as in $..email. It
+// reports false when the path or the data it reaches needs the gjson path,
+// so results, including their number types, are the same either way. Only
+// the result is copied, so callers cannot change data through it; parts of
+// data the query does not reach are not checked for being encodable as JSON.
+func queryNative(path string, data interface{}) (interface{}, bool) {
+	if field, ok := strings.CutPrefix(path, "$.."); ok {
+		if !isNativeField(field) {
+			return nil, false
+		}
+		return recursiveNative(data, field)
+	}
+
+	steps, ok, err := parseNativePath(path)
+	if !ok || err != nil || hasSelection(steps) {
+		return nil, false
+	}
+	if len(steps) == 0 {
+		return data, true
+	}
+
+	negative, wildcard := -1, -1
+	for i, step := range steps {
+		switch {
+		case step.kind == stepIndex && step.index < 0 && negative < 0:
+			negative = i
+		case step.kind == stepIndex && step.index < 0:
+			return nil, false
+		case step.kind == stepWildcard && wildcard < 0:
+			wildcard = i
+		}
+	}
+	switch {
+	case negative >= 0:
+		return negativeIndexNative(data, steps, negative)
+	case wildcard >= 0:
+		result, matched, ok := wildcardNative(data, steps)
+		if !ok || !matched {
+			return nil, false // A missing or non-array base is a type mismatch
+		}
+		return result, true
+	}
+
+	value, found, ok := walkNative(data, steps)
+	if !ok {
+		return nil, false
+	}
+	if !found {
+		if strings.Contains(path, "[") {
+			return nil, false // gjson tells an index into a string apart
+		}
+		return nil, true
+	}
+	return jsonResult(value, true)
+}
+
+// parseNativePath splits a path made only of .name, [index], [*], union and
+// ['name'] steps. It reports false for paths with other steps, such as
+// slices and filters, and an error for a malformed union.
+func parseNativePath(path string) ([]nativeStep, bool, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, false, nil
+	}
+	var steps []nativeStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := 1
+			for end < len(rest) && rest[end] != '.' && rest[end] != '[' {
+				end++
+			}
+			if !isNativeField(rest[1:end]) {
+				return nil, false, nil
+			}
+			steps = append(steps, nativeStep{kind: stepField, name: rest[1:end]})
+			rest = rest[end:]
+		case '[':
+			closing := closingBracket(rest)
+			if closing < 0 {
+				return nil, false, nil
+			}
+			step, ok, err := parseBracket(rest[1:closing])
+			if !ok || err != nil {
+				return nil, false, err
+			}
+			steps = append(steps, step)
+			rest = rest[closing+1:]
+		default:
+			return nil, false, nil
+		}
+	}
+	return steps, true, nil
+}
+
+// closingBracket returns the position of the bracket closing the one s
+// starts with, skipping quoted names and brackets nested in filters, or -1
+func closingBracket(s string) int {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '\'' || s[i] == '"':
+			quote = s[i]
+		case s[i] == '[':
+			depth++
+		case s[i] == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseBracket parses what is inside a bracket step: *, an index, a union
+// of indexes, or one or more quoted field names
+func parseBracket(inner string) (nativeStep, bool, error) {
+	if inner == "*" {
+		return nativeStep{kind: stepWildcard}, true, nil
+	}
+	if index, ok := parseIndex(inner); ok {
+		return nativeStep{kind: stepIndex, index: index}, true, nil
+	}
+	if strings.HasPrefix(inner, "?") || strings.Contains(inner, ":") {
+		return nativeStep{}, false, nil // Filters and slices
+	}
+
+	parts := splitOutsideQuotes(inner, ',')
+	trimmed := strings.TrimSpace(parts[0])
+	if len(parts) == 1 && !isQuoted(trimmed) {
+		return nativeStep{}, false, nil
+	}
+
+	if isQuoted(trimmed) {
+		names := make([]string, len(parts))
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if !isQuoted(part) {
+				return nativeStep{}, false, fmt.Errorf("%w: union [%s] mixes field names with other selectors", ErrInvalidJSONPath, inner)
+			}
+			names[i] = part[1 : len(part)-1]
+		}
+		if len(names) == 1 {
+			return nativeStep{kind: stepField, name: names[0], bracketed: true}, true, nil
+		}
+		return nativeStep{kind: stepFieldUnion, names: names}, true, nil
+	}
+
+	indexes := make([]int, len(parts))
+	for i, part := range parts {
+		index, ok := parseIndex(strings.TrimSpace(part))
+		if !ok {
+			return nativeStep{}, false, fmt.Errorf("%w: union [%s] must list indexes or quoted field names", ErrInvalidJSONPath, inner)
+		}
+		indexes[i] = index
+	}
+	return nativeStep{kind: stepIndexUnion, indexes: indexes}, true, nil
+}
+
+// parseIndex parses an array index, negative from the end, written without
+// a plus sign or leading zeros
+func parseIndex(s string) (int, bool) {
+	index, err := strconv.Atoi(s)
+	return index, err == nil && strconv.Itoa(index) == s
+}
+
+// hasSelection reports whether steps include unions or bracketed fields,
+// which only queryUnion evaluates
+func hasSelection(steps []nativeStep) bool {
+	for _, step := range steps {
+		if step.kind == stepIndexUnion || step.kind == stepFieldUnion || step.bracketed {
+			return true
+		}
+	}
+	return false
+}
+
+// isNativeField reports whether name is a plain field name, which gjson
+// reads the same way as a map lookup
+func isNativeField(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !letter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// walkNative follows field and non-negative index steps from value. found
+// is false when a step has nothing to select; ok is false when a step
+// reaches a value other than the maps, slices and scalars JSON decodes to.
+func walkNative(value interface{}, steps []nativeStep) (result interface{}, found, ok bool) {
+	for _, step := range steps {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if step.kind != stepField {
+				return nil, false, false // gjson would look up the index as a key
+			}
+			field, exists := v[step.name]
+			if !exists {
+				return nil, false, true
+			}
+			value = field
+		case []interface{}:
+			if step.kind != stepIndex || step.index < 0 || step.index >= len(v) {
+				return nil, false, true
+			}
+			value = v[step.index]
+		default:
+			if !isJSONScalar(value) {
+				return nil, false, false
+			}
+			return nil, false, true
+		}
+	}
+	return value, true, true
+}
+
+// negativeIndexNative evaluates a path whose step at is a negative index,
+// counting from the end of the array the fields before it select
+func negativeIndexNative(data interface{}, steps []nativeStep, at int) (interface{}, bool) {
+	for i, step := range steps {
+		if i != at && step.kind != stepField {
+			return nil, false
+		}
+	}
+	base, found, ok := walkNative(data, steps[:at])
+	items, isArray := base.([]interface{})
+	if !ok || !found || !isArray {
+		return nil, false // A missing or non-array base is a type mismatch
+	}
+	index := len(items) + steps[at].index
+	if index < 0 {
+		return nil, true
+	}
+	value, found, ok := walkNative(items[index], steps[at+1:])
+	if !ok {
+		return nil, false
+	}
+	if !found {
+		return nil, true
+	}
+	return jsonResult(value, true)
+}
+
+// wildcardNative evaluates a path of fields and wildcards, flattening the
+// values every wildcard selects. Elements the steps after a wildcard select
+// nothing in are left out. matched is false when the fields before the first
+// wildcard do not select an array.
+func wildcardNative(value interface{}, steps []nativeStep) (result []interface{}, matched, ok bool) {
+	at := -1
+	for i, step := range steps {
+		switch {
+		case step.kind == stepIndex:
+			return nil, false, false
+		case step.kind == stepWildcard && i > 0 && steps[i-1].kind == stepWildcard:
+			return nil, false, false
+		case step.kind == stepWildcard && at < 0:
+			at = i
+		}
+	}
+
+	base, found, ok := walkNative(value, steps[:at])
+	if !ok {
+		return nil, false, false
+	}
+	items, isArray := base.([]interface{})
+	if !found || !isArray {
+		return nil, false, isJSONValue(base)
+	}
+
+	rest := steps[at+1:]
+	nested := false
+	for _, step := range rest {
+		nested = nested || step.kind == stepWildcard
+	}
+	for _, item := range items {
+		if nested {
+			values, matched, ok := wildcardNative(item, rest)
+			if !ok {
+				return nil, false, false
+			}
+			if matched {
+				result = append(result, values...)
+			}
+			continue
+		}
+
+		selected, found, ok := walkNative(item, rest)
+		if !ok {
+			return nil, false, false
+		}
+		if !found {
+			continue
+		}
+		converted, ok := jsonResult(selected, true)
+		if !ok {
+			return nil, false, false
+		}
+		result = append(result, converted)
+	}
+	return result, true, true
+}
+
+// recursiveNative finds every value of field at any depth of data
+func recursiveNative(data interface{}, field string) (interface{}, bool) {
+	var found []interface{}
+	if !findNative(data, field, &found) {
+		return nil, false
+	}
+	if len(found) == 0 {
+		return nil, true
+	}
+	results := make([]interface{}, len(found))
+	for i, value := range found {
+		converted, ok := jsonResult(value, false)
+		if !ok {
+			return nil, false
+		}
+		results[i] = converted
+	}
+	return results, true
+}
+
+// findNative collects the values of field in data and everything it
+// contains, in the order findRecursive does. It reports false on values
+// other than those JSON decodes to.
+func findNative(data interface{}, field string, results *[]interface{}) bool {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if value, ok := v[field]; ok {
+			*results = append(*results, value)
+		}
+		for _, value := range v {
+			if !findNative(value, field, results) {
+				return false
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if !findNative(item, field, results) {
+				return false
+			}
+		}
+	default:
+		return isJSONScalar(data)
+	}
+	return true
+}
+
+// jsonResult copies value the way encoding it as JSON and decoding it again
+// would: numbers become float64, or int when whole and normalize is set.
+// It reports false for values JSON would encode differently or not at all.
+func jsonResult(value interface{}, normalize bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil, bool:
+		return v, true
+	case string:
+		return v, utf8.ValidString(v)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			converted, ok := jsonResult(field, normalize)
+			if !ok || !utf8.ValidString(key) {
+				return nil, false
+			}
+			result[key] = converted
+		}
+		return result, true
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			converted, ok := jsonResult(item, normalize)
+			if !ok {
+				return nil, false
+			}
+			result[i] = converted
+		}
+		return result, true
+	}
+
+	number, ok := jsonNumber(value)
+	if !ok {
+		return nil, false
+	}
+	if normalize && number == float64(int64(number)) {
+		return int(number), true
+	}
+	return number, true
+}
+
+// jsonNumber returns the float64 a number decodes to from its JSON encoding
+func jsonNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// isJSONScalar reports whether value is a scalar jsonResult accepts
+func isJSONScalar(value interface{}) bool {
+	switch v := value.(type) {
+	case nil, bool:
+		return true
+	case string:
+		return utf8.ValidString(v)
+	default:
+		_, ok := jsonNumber(value)
+		return ok
+	}
+}
+
+// isJSONValue reports whether value is a scalar jsonResult accepts or one of
+// the containers JSON decodes to, without checking their contents
+func isJSONValue(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return isJSONScalar(value)
+	}
+}
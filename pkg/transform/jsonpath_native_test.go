@@ -0,0 +1,177 @@
+package transform
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// nativeTestData mixes the number types, nulls and shapes both query paths
+// must agree on
+func nativeTestData() map[string]interface{} {
+	return map[string]interface{}{
+		"name":  "store",
+		"count": 3,
+		"ratio": 0.25,
+		"big":   int64(1) << 40,
+		"whole": float64(7),
+		"none":  nil,
+		"tags":  []interface{}{"a", "b"},
+		"empty": []interface{}{},
+		"orders": []interface{}{
+			map[string]interface{}{
+				"id":       1,
+				"total":    12.5,
+				"customer": map[string]interface{}{"email": "a@example.com"},
+				"items": []interface{}{
+					map[string]interface{}{"sku": "x1", "qty": 2},
+					map[string]interface{}{"sku": "x2", "qty": uint8(1)},
+				},
+			},
+			map[string]interface{}{
+				"id":       2,
+				"total":    float64(30),
+				"customer": map[string]interface{}{"email": nil},
+				"items":    []interface{}{map[string]interface{}{"qty": 4}},
+			},
+			map[string]interface{}{"id": 3, "items": "none"},
+			"loose",
+			[]interface{}{1, 2},
+		},
+		"matrix": []interface{}{[]interface{}{1, 2}, []interface{}{3.5}},
+	}
+}
+
+func TestQueryNativeMatchesJSON(t *testing.T) {
+	ctx := context.Background()
+	data := nativeTestData()
+
+	native := []string{
+		"$",
+		"$.name",
+		"$.count",
+		"$.ratio",
+		"$.big",
+		"$.whole",
+		"$.none",
+		"$.missing",
+		"$.name.first",
+		"$.tags.first",
+		"$.tags",
+		"$.tags[1]",
+		"$.orders[0].customer.email",
+		"$.orders[0].items[1].qty",
+		"$.orders[0]",
+		"$.orders[-1]",
+		"$.orders[-3].id",
+		"$.orders[-2].id",
+		"$.orders[-9]",
+		"$.orders[*]",
+		"$.orders[*].id",
+		"$.orders[*].customer.email",
+		"$.orders[*].items[*].sku",
+		"$.orders[*].items[*].qty",
+		"$.empty[*]",
+		"$.matrix[*]",
+		"$..email",
+		"$..qty",
+		"$..missing",
+	}
+	for _, path := range native {
+		t.Run(path, func(t *testing.T) {
+			want, err := queryJSON(ctx, path, data)
+			if err != nil {
+				t.Fatalf("queryJSON() error = %v", err)
+			}
+			got, ok := queryNative(path, data)
+			if !ok {
+				t.Fatalf("queryNative() fell back to gjson")
+			}
+			if strings.HasPrefix(path, "$..") {
+				got, want = sortedValues(got), sortedValues(want)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("queryNative() = %#v, want %#v", got, want)
+			}
+		})
+	}
+
+	// Indexes into a root array
+	list := []interface{}{map[string]interface{}{"a": 1}, map[string]interface{}{"a": 2.5}, []interface{}{1}}
+	for _, path := range []string{"$[0]", "$[1].a", "$[*].a", "$[-1]"} {
+		want, err := queryJSON(ctx, path, list)
+		if err != nil {
+			t.Fatalf("queryJSON(%s) error = %v", path, err)
+		}
+		if got, ok := queryNative(path, list); !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("queryNative(%s) = %#v, %v, want %#v", path, got, ok, want)
+		}
+	}
+
+	fallback := []string{
+		"$.tags[5]",           // Missing after an index: gjson checks for a string
+		"$.name[0]",           // Index into a string is a type mismatch
+		"$.missing[*]",        // Wildcard over nothing is a type mismatch
+		"$.orders[*][0]",      // Index after a wildcard
+		"$.matrix[*][*]",      // Consecutive wildcards
+		"$.orders[-1][-1]",    // Two negative indexes
+		"$.orders[0:2]",       // Slice
+		"$['name']",           // Quoted field
+		"$.orders.length()",   // Function
+		"$..items[*].sku",     // Recursive descent beyond a field
+		"$.orders[?(@.id>1)]", // Filter
+	}
+	for _, path := range fallback {
+		if _, ok := queryNative(path, data); ok {
+			t.Errorf("queryNative(%s) did not fall back to gjson", path)
+		}
+	}
+}
+
+func TestQueryNativeFallsBackOnOtherTypes(t *testing.T) {
+	data := map[string]interface{}{
+		"list":  []string{"a", "b"},
+		"float": float32(0.1),
+		"ok":    map[string]interface{}{"id": 1},
+	}
+	for _, path := range []string{"$.list", "$.list[0]", "$.float", "$..id"} {
+		if _, ok := queryNative(path, data); ok {
+			t.Errorf("queryNative(%s) did not fall back to gjson", path)
+		}
+	}
+
+	// Parts of the data the query does not reach may be of any type
+	got, ok := queryNative("$.ok.id", data)
+	if !ok || got != 1 {
+		t.Errorf("queryNative($.ok.id) = %v, %v, want 1", got, ok)
+	}
+}
+
+func TestQueryNativeCopiesResult(t *testing.T) {
+	data := nativeTestData()
+	result, err := NewJSONPathQuerier().Query(context.Background(), "$.orders[0].customer", data)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	result.(map[string]interface{})["email"] = "changed"
+
+	customer := data["orders"].([]interface{})[0].(map[string]interface{})["customer"]
+	if email := customer.(map[string]interface{})["email"]; email != "a@example.com" {
+		t.Errorf("changing the result changed the data: email = %v", email)
+	}
+}
+
+// sortedValues orders the values of a recursive descent, which follow map
+// iteration order
+func sortedValues(value interface{}) interface{} {
+	values, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	sorted := append([]interface{}(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return fmt.Sprint(sorted[i]) < fmt.Sprint(sorted[j]) })
+	return sorted
+}
@@ -0,0 +1,322 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ItemError records why a single array element was excluded from the results
+// of a wildcard or filter query.
+type ItemError struct {
+	// Index is the position of the element within the queried array
+	Index int
+	// Path is the JSONPath of the element, e.g. $.items[3]
+	Path string
+	// Err is the underlying cause (ErrMissingField, ErrTypeMismatch, ...)
+	Err error
+}
+
+// Error implements the error interface
+func (e ItemError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying cause so errors.Is works on item errors
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+// PartialResult holds the values a wildcard or filter query could extract
+// together with a structured error for every element it had to skip.
+type PartialResult struct {
+	Values []interface{}
+	Errors []ItemError
+}
+
+// HasErrors reports whether any array element failed to evaluate
+func (r *PartialResult) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// PartialQuerier is implemented by queriers that can collect partial results
+// for wildcard ([*]) and filter ([?(...)]) queries over heterogeneous arrays.
+type PartialQuerier interface {
+	QueryPartial(ctx context.Context, path string, data interface{}) (*PartialResult, error)
+}
+
+// QueryPartial evaluates a wildcard or filter query element by element.
+// Instead of silently dropping elements that lack the selected field or have
+// the wrong type, each such element is reported in PartialResult.Errors.
+// Errors affecting the whole query (invalid syntax, base path not an array)
+// are returned directly, as is ErrEvaluationTimeout once the evaluation
+// timeout expires.
+func (q *gjsonQuerier) QueryPartial(ctx context.Context, path string, data interface{}) (*PartialResult, error) {
+	if data == nil {
+		return nil, ErrNilData
+	}
+	if path == "" {
+		return nil, ErrInvalidJSONPath
+	}
+	if err := validateBrackets(path); err != nil {
+		return nil, err
+	}
+
+	base, filterExpr, rest, ok := splitIterationPath(path)
+	if !ok {
+		return nil, fmt.Errorf("%w: partial queries require a [*] or [?()] selector", ErrInvalidJSONPath)
+	}
+	if filterExpr != "" {
+		if err := validateFilterExpression(filterExpr); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := startEvaluation(ctx)
+	defer cancel()
+
+	if lazy, ok := data.(LazyValue); ok {
+		decoded, err := loadLazy(lazy)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+
+	// Normalize to generic JSON structures so structs and typed slices behave
+	// the same as decoded JSON.
+	normalized, err := normalizeJSONData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	baseValue := normalized
+	if base != "$" {
+		baseValue, err = q.Query(ctx, base, normalized)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items, ok := baseValue.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is %s, not an array", ErrTypeMismatch, base, jsonTypeName(baseValue))
+	}
+
+	result := &PartialResult{}
+	for i, item := range items {
+		if ctx.Err() != nil {
+			return nil, evaluationError(ctx)
+		}
+
+		itemPath := fmt.Sprintf("%s[%d]", base, i)
+
+		if filterExpr != "" {
+			obj, isObj := item.(map[string]interface{})
+			if !isObj {
+				result.Errors = append(result.Errors, ItemError{
+					Index: i,
+					Path:  itemPath,
+					Err:   fmt.Errorf("%w: expected object, got %s", ErrTypeMismatch, jsonTypeName(item)),
+				})
+				continue
+			}
+			matched, err := evaluateFilterStrict(ctx, obj, filterExpr)
+			if err != nil {
+				result.Errors = append(result.Errors, ItemError{Index: i, Path: itemPath, Err: err})
+				continue
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		value, err := q.selectFromItem(ctx, item, rest)
+		if err != nil {
+			result.Errors = append(result.Errors, ItemError{Index: i, Path: itemPath + rest, Err: err})
+			continue
+		}
+
+		// Nested wildcards flatten into the outer result, matching Query
+		if strings.Contains(rest, "[*]") {
+			if nested, isSlice := value.([]interface{}); isSlice {
+				result.Values = append(result.Values, nested...)
+				continue
+			}
+		}
+		result.Values = append(result.Values, value)
+	}
+
+	return result, nil
+}
+
+// selectFromItem applies the remainder of a path (after the iteration
+// selector) to a single array element.
+func (q *gjsonQuerier) selectFromItem(ctx context.Context, item interface{}, rest string) (interface{}, error) {
+	if rest == "" {
+		return item, nil
+	}
+
+	segments, simple := parsePathSegments(rest)
+	if !simple {
+		// Complex remainders (nested wildcards, filters, slices) go through
+		// the regular query engine; a nil result means nothing matched.
+		value, err := q.Query(ctx, "$"+rest, item)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, fmt.Errorf("%w: %s", ErrMissingField, strings.TrimPrefix(rest, "."))
+		}
+		return value, nil
+	}
+
+	current := item
+	for _, seg := range segments {
+		if seg.field != "" {
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: cannot access field %q on %s", ErrTypeMismatch, seg.field, jsonTypeName(current))
+			}
+			value, exists := obj[seg.field]
+			if !exists {
+				return nil, fmt.Errorf("%w: %s", ErrMissingField, seg.field)
+			}
+			current = value
+			continue
+		}
+
+		arr, ok := current.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot index %s", ErrTypeMismatch, jsonTypeName(current))
+		}
+		if seg.index < 0 || seg.index >= len(arr) {
+			return nil, fmt.Errorf("%w: index %d out of range (length %d)", ErrMissingField, seg.index, len(arr))
+		}
+		current = arr[seg.index]
+	}
+
+	return current, nil
+}
+
+// pathSegment is either a field access (field != "") or an array index
+type pathSegment struct {
+	field string
+	index int
+}
+
+// parsePathSegments parses a remainder like ".user.tags[0].name" into
+// segments. It returns false when the remainder uses syntax other than plain
+// field names and non-negative numeric indexes.
+func parsePathSegments(rest string) ([]pathSegment, bool) {
+	var segments []pathSegment
+	i := 0
+	for i < len(rest) {
+		switch rest[i] {
+		case '.':
+			if i+1 < len(rest) && rest[i+1] == '.' {
+				return nil, false
+			}
+			j := i + 1
+			for j < len(rest) && rest[j] != '.' && rest[j] != '[' {
+				j++
+			}
+			if j == i+1 {
+				return nil, false
+			}
+			segments = append(segments, pathSegment{field: rest[i+1 : j]})
+			i = j
+		case '[':
+			end := strings.IndexByte(rest[i:], ']')
+			if end == -1 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(rest[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, false
+			}
+			segments = append(segments, pathSegment{index: index})
+			i += end + 1
+		default:
+			return nil, false
+		}
+	}
+	return segments, true
+}
+
+// splitIterationPath splits a path at its first [*] or [?(...)] selector,
+// returning the base path, the filter expression (empty for wildcards) and
+// the remaining path.
+func splitIterationPath(path string) (base, filterExpr, rest string, ok bool) {
+	wildcardIdx := strings.Index(path, "[*]")
+	filterIdx := strings.Index(path, "[?(")
+
+	if filterIdx != -1 && (wildcardIdx == -1 || filterIdx < wildcardIdx) {
+		depth := 1
+		j := filterIdx + 3
+		for j < len(path) && depth > 0 {
+			switch path[j] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			j++
+		}
+		if depth != 0 || j >= len(path) || path[j] != ']' {
+			return "", "", "", false
+		}
+		base = strings.TrimSuffix(path[:filterIdx], ".")
+		filterExpr = path[filterIdx+3 : j-1]
+		rest = path[j+1:]
+	} else if wildcardIdx != -1 {
+		base = strings.TrimSuffix(path[:wildcardIdx], ".")
+		rest = path[wildcardIdx+3:]
+	} else {
+		return "", "", "", false
+	}
+
+	if base == "" {
+		base = "$"
+	}
+	if !strings.HasPrefix(base, "$") || strings.Contains(base, "..") {
+		return "", "", "", false
+	}
+	return base, filterExpr, rest, true
+}
+
+// normalizeJSONData round-trips data through JSON so that arbitrary Go values
+// are represented as map[string]interface{} / []interface{}
+func normalizeJSONData(data interface{}) (interface{}, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(jsonBytes, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+	return normalizeNumbers(value), nil
+}
+
+// jsonTypeName returns the JSON type name of a decoded value for error messages
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,169 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestJSONPathQueryPartial tests partial result collection for wildcard and filter queries
+func TestJSONPathQueryPartial(t *testing.T) {
+	messy := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "price": 10},
+			map[string]interface{}{"price": 20},
+			"not an object",
+			map[string]interface{}{"name": "d", "price": "expensive"},
+			map[string]interface{}{"name": "e", "price": 50, "tags": []interface{}{"x", "y"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		jsonPath   string
+		data       interface{}
+		wantValues []interface{}
+		wantErrors map[int]error
+		wantErr    error
+	}{
+		{
+			name:       "wildcard field extraction reports missing and wrong type",
+			jsonPath:   "$.items[*].name",
+			data:       messy,
+			wantValues: []interface{}{"a", "d", "e"},
+			wantErrors: map[int]error{1: ErrMissingField, 2: ErrTypeMismatch},
+		},
+		{
+			name:       "wildcard with nested index",
+			jsonPath:   "$.items[*].tags[1]",
+			data:       messy,
+			wantValues: []interface{}{"y"},
+			wantErrors: map[int]error{0: ErrMissingField, 1: ErrMissingField, 2: ErrTypeMismatch, 3: ErrMissingField},
+		},
+		{
+			name:       "wildcard without remainder returns all items",
+			jsonPath:   "$.items[*]",
+			data:       map[string]interface{}{"items": []interface{}{1, "two"}},
+			wantValues: []interface{}{1, "two"},
+		},
+		{
+			name:       "filter reports items it cannot evaluate",
+			jsonPath:   "$.items[?(@.price > 15)].name",
+			data:       messy,
+			wantValues: []interface{}{"e"},
+			wantErrors: map[int]error{1: ErrMissingField, 2: ErrTypeMismatch, 3: ErrTypeMismatch},
+		},
+		{
+			name:     "filter missing field in filter expression",
+			jsonPath: "$.items[?(@.active == true)]",
+			data: map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"id": 1, "active": true},
+					map[string]interface{}{"id": 2},
+				},
+			},
+			wantValues: []interface{}{map[string]interface{}{"id": 1, "active": true}},
+			wantErrors: map[int]error{1: ErrMissingField},
+		},
+		{
+			name:       "filter function argument missing",
+			jsonPath:   "$.items[?(@.tags[*] contains 'x')].name",
+			data:       messy,
+			wantValues: []interface{}{"e"},
+			wantErrors: map[int]error{0: ErrMissingField, 1: ErrMissingField, 2: ErrTypeMismatch, 3: ErrMissingField},
+		},
+		{
+			name:     "root array",
+			jsonPath: "$[*].id",
+			data: []interface{}{
+				map[string]interface{}{"id": 1},
+				map[string]interface{}{"other": 2},
+			},
+			wantValues: []interface{}{1},
+			wantErrors: map[int]error{1: ErrMissingField},
+		},
+		{
+			name:     "base path is not an array",
+			jsonPath: "$.items[*].name",
+			data:     map[string]interface{}{"items": "nope"},
+			wantErr:  ErrTypeMismatch,
+		},
+		{
+			name:     "path without iteration selector",
+			jsonPath: "$.items[0].name",
+			data:     messy,
+			wantErr:  ErrInvalidJSONPath,
+		},
+		{
+			name:     "unsafe filter rejected",
+			jsonPath: "$.items[?(@.x == os.Exit(1))]",
+			data:     messy,
+			wantErr:  ErrUnsafeOperation,
+		},
+		{
+			name:     "nil data",
+			jsonPath: "$.items[*]",
+			data:     nil,
+			wantErr:  ErrNilData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TransformJSONPathPartial(context.Background(), tt.jsonPath, tt.data)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("QueryPartial() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("QueryPartial() unexpected error = %v", err)
+			}
+
+			if !reflect.DeepEqual(result.Values, tt.wantValues) {
+				t.Errorf("QueryPartial() values = %#v, want %#v", result.Values, tt.wantValues)
+			}
+
+			if len(result.Errors) != len(tt.wantErrors) {
+				t.Fatalf("QueryPartial() got %d item errors, want %d: %v", len(result.Errors), len(tt.wantErrors), result.Errors)
+			}
+			for _, itemErr := range result.Errors {
+				want, ok := tt.wantErrors[itemErr.Index]
+				if !ok {
+					t.Errorf("unexpected item error at index %d: %v", itemErr.Index, itemErr)
+					continue
+				}
+				if !errors.Is(itemErr, want) {
+					t.Errorf("item %d error = %v, want %v", itemErr.Index, itemErr, want)
+				}
+			}
+			if result.HasErrors() != (len(tt.wantErrors) > 0) {
+				t.Errorf("HasErrors() = %v", result.HasErrors())
+			}
+		})
+	}
+}
+
+// TestItemErrorPath tests that item errors carry the element path
+func TestItemErrorPath(t *testing.T) {
+	data := map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"email": "a@example.com"},
+			map[string]interface{}{},
+		},
+	}
+
+	result, err := TransformJSONPathPartial(context.Background(), "$.users[*].email", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected 1 item error, got %d", len(result.Errors))
+	}
+	if got := result.Errors[0].Path; got != "$.users[1].email" {
+		t.Errorf("item error path = %q, want %q", got, "$.users[1].email")
+	}
+}
@@ -0,0 +1,350 @@
+package transform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPathMatch is a value a JSONPath query selected, with the normalized
+// path that leads to it in the queried data, as in $.items[3].name
+type JSONPathMatch struct {
+	Path  string
+	Value interface{}
+}
+
+// PathQuerier is implemented by queriers that can report where in the
+// queried data each value they select comes from
+type PathQuerier interface {
+	QueryWithPaths(ctx context.Context, path string, data interface{}) ([]JSONPathMatch, error)
+}
+
+// pathSelector is one step of a path QueryWithPaths evaluates
+type pathSelector struct {
+	// step is the field, index, wildcard or union the selector selects,
+	// unless it is a slice or a filter
+	step nativeStep
+	// descendant applies the selector at every depth, as after ..
+	descendant bool
+	// filter is the expression of a [?(...)] filter
+	filter string
+	// slice holds the bounds of a [start:end] slice
+	slice *arraySlice
+}
+
+// arraySlice is the bounds of a slice, either of which may be left out
+type arraySlice struct {
+	start, end       int
+	hasStart, hasEnd bool
+}
+
+// QueryWithPaths executes a JSONPath query and returns every value it
+// selects with its normalized path: fields as .name, or ['name'] when not
+// a plain identifier, and array indexes counted from the start. A field
+// union selects each field as its own match. Recursive descent visits
+// object fields in sorted order. Selectors that do not apply to a value,
+// such as an index into an object, select nothing from it. Values are
+// copies, so changing them does not change data.
+func (q *gjsonQuerier) QueryWithPaths(ctx context.Context, path string, data interface{}) ([]JSONPathMatch, error) {
+	ctx, cancel := startEvaluation(ctx)
+	defer cancel()
+
+	matches, err := queryWithPaths(ctx, path, data)
+	if ctx.Err() != nil {
+		return nil, evaluationError(ctx)
+	}
+	return matches, err
+}
+
+// queryWithPaths evaluates a path on a copy of data, tracking where each
+// value comes from
+func queryWithPaths(ctx context.Context, path string, data interface{}) ([]JSONPathMatch, error) {
+	if data == nil {
+		return nil, ErrNilData
+	}
+	selectors, err := parsePathSelectors(path)
+	if err != nil {
+		return nil, err
+	}
+	root, err := pathInput(data)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []JSONPathMatch{{Path: "$", Value: root}}
+	for _, selector := range selectors {
+		var next []JSONPathMatch
+		for _, match := range matches {
+			nodes := []JSONPathMatch{match}
+			if selector.descendant {
+				nodes = descendants(match, nodes)
+			}
+			for _, node := range nodes {
+				next = append(next, applySelector(ctx, selector, node)...)
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+		matches = next
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return matches, nil
+}
+
+// pathInput copies data as the values JSON decodes to, with whole numbers
+// as int the way Query returns them
+func pathInput(data interface{}) (interface{}, error) {
+	if lazy, ok := data.(LazyValue); ok {
+		decoded, err := loadLazy(lazy)
+		if err != nil {
+			return nil, err
+		}
+		return normalizeNumbers(decoded), nil
+	}
+	if copied, ok := jsonResult(data, true); ok {
+		return copied, nil
+	}
+	return normalizeJSONData(data)
+}
+
+// parsePathSelectors splits a path into the selectors QueryWithPaths applies
+func parsePathSelectors(path string) ([]pathSelector, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, fmt.Errorf("%w: path must start with $", ErrInvalidJSONPath)
+	}
+
+	var selectors []pathSelector
+	for rest != "" {
+		descendant := strings.HasPrefix(rest, "..")
+		if descendant {
+			rest = rest[1:]
+			if len(rest) > 1 && rest[1] == '[' {
+				rest = rest[1:]
+			}
+		}
+
+		switch rest[0] {
+		case '.':
+			end := 1
+			for end < len(rest) && rest[end] != '.' && rest[end] != '[' {
+				end++
+			}
+			name := rest[1:end]
+			switch {
+			case name == "":
+				return nil, fmt.Errorf("%w: empty field name in %s", ErrInvalidJSONPath, path)
+			case strings.ContainsAny(name, "()"):
+				return nil, fmt.Errorf("%w: function %s selects no path", ErrInvalidJSONPath, name)
+			case name == "*":
+				selectors = append(selectors, pathSelector{step: nativeStep{kind: stepWildcard}, descendant: descendant})
+			default:
+				selectors = append(selectors, pathSelector{step: nativeStep{kind: stepField, name: name}, descendant: descendant})
+			}
+			rest = rest[end:]
+		case '[':
+			closing := closingBracket(rest)
+			if closing < 0 {
+				return nil, fmt.Errorf("%w: unclosed bracket in %s", ErrInvalidJSONPath, path)
+			}
+			selector, err := parseBracketSelector(rest[1:closing])
+			if err != nil {
+				return nil, err
+			}
+			selector.descendant = descendant
+			selectors = append(selectors, selector)
+			rest = rest[closing+1:]
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q in %s", ErrInvalidJSONPath, rest[0], path)
+		}
+	}
+	return selectors, nil
+}
+
+// parseBracketSelector parses what is inside a bracket: a filter, a slice,
+// or what parseBracket accepts
+func parseBracketSelector(inner string) (pathSelector, error) {
+	trimmed := strings.TrimSpace(inner)
+	if filter, ok := strings.CutPrefix(trimmed, "?"); ok {
+		filter = strings.TrimSpace(filter)
+		if strings.HasPrefix(filter, "(") && strings.HasSuffix(filter, ")") {
+			filter = filter[1 : len(filter)-1]
+		}
+		if err := validateFilterExpression(filter); err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{filter: filter}, nil
+	}
+
+	if strings.Contains(trimmed, ":") {
+		bounds := strings.Split(trimmed, ":")
+		if len(bounds) != 2 {
+			return pathSelector{}, fmt.Errorf("%w: slice [%s] must be start:end", ErrInvalidJSONPath, inner)
+		}
+		var slice arraySlice
+		var err error
+		if bound := strings.TrimSpace(bounds[0]); bound != "" {
+			slice.hasStart = true
+			if slice.start, err = strconv.Atoi(bound); err != nil {
+				return pathSelector{}, fmt.Errorf("%w: slice start %q", ErrInvalidJSONPath, bound)
+			}
+		}
+		if bound := strings.TrimSpace(bounds[1]); bound != "" {
+			slice.hasEnd = true
+			if slice.end, err = strconv.Atoi(bound); err != nil {
+				return pathSelector{}, fmt.Errorf("%w: slice end %q", ErrInvalidJSONPath, bound)
+			}
+		}
+		return pathSelector{slice: &slice}, nil
+	}
+
+	step, ok, err := parseBracket(trimmed)
+	if err != nil {
+		return pathSelector{}, err
+	}
+	if !ok {
+		return pathSelector{}, fmt.Errorf("%w: unsupported selector [%s]", ErrInvalidJSONPath, inner)
+	}
+	return pathSelector{step: step}, nil
+}
+
+// descendants appends every value match contains to nodes, each before the
+// values it contains in turn
+func descendants(match JSONPathMatch, nodes []JSONPathMatch) []JSONPathMatch {
+	for _, child := range children(match) {
+		nodes = append(nodes, child)
+		nodes = descendants(child, nodes)
+	}
+	return nodes
+}
+
+// children returns the fields of an object, in sorted order, or the
+// elements of an array
+func children(match JSONPathMatch) []JSONPathMatch {
+	switch v := match.Value.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result := make([]JSONPathMatch, len(names))
+		for i, name := range names {
+			result[i] = JSONPathMatch{Path: fieldPath(match.Path, name), Value: v[name]}
+		}
+		return result
+	case []interface{}:
+		result := make([]JSONPathMatch, len(v))
+		for i, item := range v {
+			result[i] = JSONPathMatch{Path: indexPath(match.Path, i), Value: item}
+		}
+		return result
+	}
+	return nil
+}
+
+// applySelector returns the values selector selects from node
+func applySelector(ctx context.Context, selector pathSelector, node JSONPathMatch) []JSONPathMatch {
+	switch {
+	case selector.filter != "":
+		var selected []JSONPathMatch
+		for _, child := range children(node) {
+			if object, ok := child.Value.(map[string]interface{}); ok && evaluateFilter(ctx, object, selector.filter) {
+				selected = append(selected, child)
+			}
+		}
+		return selected
+	case selector.slice != nil:
+		items, ok := node.Value.([]interface{})
+		if !ok {
+			return nil
+		}
+		start, end := selector.slice.bounds(len(items))
+		var selected []JSONPathMatch
+		for i := start; i < end; i++ {
+			selected = append(selected, JSONPathMatch{Path: indexPath(node.Path, i), Value: items[i]})
+		}
+		return selected
+	}
+
+	step := selector.step
+	switch step.kind {
+	case stepWildcard:
+		return children(node)
+	case stepField, stepFieldUnion:
+		object, ok := node.Value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		names := step.names
+		if step.kind == stepField {
+			names = []string{step.name}
+		}
+		var selected []JSONPathMatch
+		for _, name := range names {
+			if value, exists := object[name]; exists {
+				selected = append(selected, JSONPathMatch{Path: fieldPath(node.Path, name), Value: value})
+			}
+		}
+		return selected
+	default:
+		items, ok := node.Value.([]interface{})
+		if !ok {
+			return nil
+		}
+		indexes := step.indexes
+		if step.kind == stepIndex {
+			indexes = []int{step.index}
+		}
+		var selected []JSONPathMatch
+		for _, index := range indexes {
+			if index < 0 {
+				index += len(items)
+			}
+			if index >= 0 && index < len(items) {
+				selected = append(selected, JSONPathMatch{Path: indexPath(node.Path, index), Value: items[index]})
+			}
+		}
+		return selected
+	}
+}
+
+// bounds returns the range of a slice of an array of length n, counting
+// negative bounds from the end
+func (s *arraySlice) bounds(n int) (int, int) {
+	start, end := 0, n
+	if s.hasStart {
+		start = s.start
+	}
+	if s.hasEnd {
+		end = s.end
+	}
+	if start < 0 {
+		start += n
+	}
+	if end < 0 {
+		end += n
+	}
+	start = max(0, min(start, n))
+	end = max(start, min(end, n))
+	return start, end
+}
+
+// fieldPath appends a field to a normalized path
+func fieldPath(path, name string) string {
+	if isNativeField(name) {
+		return path + "." + name
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name)
+	return path + "['" + escaped + "']"
+}
+
+// indexPath appends an array index to a normalized path
+func indexPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,164 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// pathTestData is an order store with fields that need quoting
+func pathTestData() map[string]interface{} {
+	return map[string]interface{}{
+		"name": "store",
+		"items": []interface{}{
+			map[string]interface{}{"name": "pen", "price": 1.5, "tags": []interface{}{"office"}},
+			map[string]interface{}{"name": "ink", "price": float64(4)},
+			map[string]interface{}{"name": "pad", "price": 3, "tags": []interface{}{}},
+		},
+		"user": map[string]interface{}{"name": "Ada", "email": "ada@example.com", "first name": "Ada", "it's": true},
+	}
+}
+
+func TestQueryWithPaths(t *testing.T) {
+	tests := []struct {
+		path string
+		want []JSONPathMatch
+	}{
+		{"$.items[1]", []JSONPathMatch{{Path: "$.items[1]", Value: map[string]interface{}{"name": "ink", "price": 4}}}},
+		{"$.name", []JSONPathMatch{{Path: "$.name", Value: "store"}}},
+		{"$.items[1].name", []JSONPathMatch{{Path: "$.items[1].name", Value: "ink"}}},
+		{"$.items[-1].price", []JSONPathMatch{{Path: "$.items[2].price", Value: 3}}},
+		{"$.items[*].price", []JSONPathMatch{
+			{Path: "$.items[0].price", Value: 1.5},
+			{Path: "$.items[1].price", Value: 4},
+			{Path: "$.items[2].price", Value: 3},
+		}},
+		{"$.items[0,2].name", []JSONPathMatch{
+			{Path: "$.items[0].name", Value: "pen"},
+			{Path: "$.items[2].name", Value: "pad"},
+		}},
+		{"$.items[1:].name", []JSONPathMatch{
+			{Path: "$.items[1].name", Value: "ink"},
+			{Path: "$.items[2].name", Value: "pad"},
+		}},
+		{"$.items[?(@.price > 2)].name", []JSONPathMatch{
+			{Path: "$.items[1].name", Value: "ink"},
+			{Path: "$.items[2].name", Value: "pad"},
+		}},
+		{"$.items[?(@.tags[0] == 'office')].name", []JSONPathMatch{{Path: "$.items[0].name", Value: "pen"}}},
+		{"$.user['email','name']", []JSONPathMatch{
+			{Path: "$.user.email", Value: "ada@example.com"},
+			{Path: "$.user.name", Value: "Ada"},
+		}},
+		{"$.user['first name']", []JSONPathMatch{{Path: "$.user['first name']", Value: "Ada"}}},
+		{`$.user["it's"]`, []JSONPathMatch{{Path: `$.user['it\'s']`, Value: true}}},
+		{"$..name", []JSONPathMatch{
+			{Path: "$.name", Value: "store"},
+			{Path: "$.items[0].name", Value: "pen"},
+			{Path: "$.items[1].name", Value: "ink"},
+			{Path: "$.items[2].name", Value: "pad"},
+			{Path: "$.user.name", Value: "Ada"},
+		}},
+		{"$..tags[*]", []JSONPathMatch{{Path: "$.items[0].tags[0]", Value: "office"}}},
+		{"$.missing", nil},
+		{"$.name[0]", nil},
+		{"$.items.name", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := TransformJSONPathWithPaths(context.Background(), tt.path, pathTestData())
+			if err != nil {
+				t.Fatalf("QueryWithPaths() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryWithPaths() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryWithPathsMatchesQuery(t *testing.T) {
+	querier := NewJSONPathQuerier()
+	paths := querier.(PathQuerier)
+	ctx := context.Background()
+	data := pathTestData()
+
+	for _, path := range []string{
+		"$.name",
+		"$.items[0]",
+		"$.items[*].name",
+		"$.items[0,2].price",
+		"$.items[0:2]",
+		"$.items[?(@.price < 4)]",
+	} {
+		want, err := querier.Query(ctx, path, data)
+		if err != nil {
+			t.Fatalf("Query(%s) error = %v", path, err)
+		}
+		matches, err := paths.QueryWithPaths(ctx, path, data)
+		if err != nil {
+			t.Fatalf("QueryWithPaths(%s) error = %v", path, err)
+		}
+
+		// Each match's path selects its value on its own
+		values := make([]interface{}, len(matches))
+		for i, match := range matches {
+			values[i] = match.Value
+			single, err := querier.Query(ctx, match.Path, data)
+			if err != nil || !reflect.DeepEqual(single, match.Value) {
+				t.Errorf("Query(%s) = %#v, %v, want %#v", match.Path, single, err, match.Value)
+			}
+		}
+
+		var got interface{} = values
+		if _, many := want.([]interface{}); !many && len(values) == 1 {
+			got = values[0]
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("QueryWithPaths(%s) values = %#v, Query() = %#v", path, got, want)
+		}
+	}
+}
+
+func TestQueryWithPathsInput(t *testing.T) {
+	querier := &gjsonQuerier{}
+	ctx := context.Background()
+
+	lazy := &jsonSource{json: `{"items": [{"id": 1}, {"id": 2.5}]}`}
+	matches, err := querier.QueryWithPaths(ctx, "$.items[*].id", lazy)
+	want := []JSONPathMatch{{Path: "$.items[0].id", Value: 1}, {Path: "$.items[1].id", Value: 2.5}}
+	if err != nil || !reflect.DeepEqual(matches, want) {
+		t.Errorf("QueryWithPaths() on lazy value = %#v, %v, want %#v", matches, err, want)
+	}
+
+	// Other types are read as their JSON encoding, and values are copies
+	typed := map[string]interface{}{"scores": []float32{1, 2.5}, "user": map[string]interface{}{"name": "Ada"}}
+	matches, err = querier.QueryWithPaths(ctx, "$.scores[1]", typed)
+	if want := []JSONPathMatch{{Path: "$.scores[1]", Value: 2.5}}; err != nil || !reflect.DeepEqual(matches, want) {
+		t.Errorf("QueryWithPaths() on typed slice = %#v, %v, want %#v", matches, err, want)
+	}
+	matches, err = querier.QueryWithPaths(ctx, "$.user", typed)
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("QueryWithPaths($.user) = %#v, %v", matches, err)
+	}
+	matches[0].Value.(map[string]interface{})["name"] = "changed"
+	if name := typed["user"].(map[string]interface{})["name"]; name != "Ada" {
+		t.Errorf("changing a match changed the data: name = %v", name)
+	}
+}
+
+func TestQueryWithPathsErrors(t *testing.T) {
+	querier := &gjsonQuerier{}
+	ctx := context.Background()
+
+	if _, err := querier.QueryWithPaths(ctx, "$.name", nil); !errors.Is(err, ErrNilData) {
+		t.Errorf("QueryWithPaths() on nil = %v, want ErrNilData", err)
+	}
+	for _, path := range []string{"", "name", "$.", "$..", "$.items[0", "$.items[0,,1]", "$.items[name]", "$.items[1:2:3]", "$.items.length()"} {
+		if _, err := querier.QueryWithPaths(ctx, path, pathTestData()); !errors.Is(err, ErrInvalidJSONPath) {
+			t.Errorf("QueryWithPaths(%q) error = %v, want ErrInvalidJSONPath", path, err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestJSONPathBasicQueries tests simple JSONPath query operations
@@ -782,26 +783,28 @@ func TestJSONPathExpressionSecurity(t *testing.T) {
 	}
 }
 
-// TestJSONPathExpressionTimeout tests that expression evaluation has timeout protection
+// TestJSONPathExpressionTimeout tests that filter evaluation stops once its
+// timeout expires
 // This is T042: Expression evaluation timeout protection
 func TestJSONPathExpressionTimeout(t *testing.T) {
-	t.Skip("Timeout protection is implemented with 1 second default timeout in evaluateFilter. " +
-		"Actual long-running expressions would require complex mathematical operations " +
-		"that are difficult to construct in the limited expr-lang sandbox. " +
-		"The timeout mechanism is verified through the implementation: " +
-		"evaluateFilter uses goroutines with time.After(1*time.Second) channel select. " +
-		"Manual verification: The timeout code is covered in evaluateFilter lines 832-860.")
-
-	// Note: In production, the 1-second timeout in evaluateFilter will catch:
-	// 1. Expensive recursive operations
-	// 2. Large data processing
-	// 3. Complex nested loops
-	//
-	// The expr-lang sandbox already prevents:
-	// - Infinite loops (syntax not supported)
-	// - Recursive function calls (not available in sandbox)
-	// - Expensive built-in functions (only safe functions exposed)
-	//
-	// Therefore, the timeout is a defense-in-depth measure rather than
-	// a primary security control for this sandboxed environment.
+	ctx, cancel := startEvaluation(WithEvaluationTimeout(context.Background(), time.Millisecond))
+	defer cancel()
+
+	item := map[string]interface{}{"n": 1}
+	start := time.Now()
+	_, err := evaluateFilterStrict(ctx, item, "@.n > 0 && any(1..800, {any(1..800, {# < 0})})")
+	if !errors.Is(err, ErrEvaluationTimeout) {
+		t.Fatalf("evaluateFilterStrict() error = %v, want %v", err, ErrEvaluationTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("evaluateFilterStrict() returned after %v, want it stopped at the timeout", elapsed)
+	}
+
+	// Queries report the timeout instead of treating the filter as a non-match
+	data := map[string]interface{}{"items": []interface{}{item}}
+	querier := NewJSONPathQuerier()
+	_, err = querier.Query(WithEvaluationTimeout(context.Background(), time.Nanosecond), `$.items[?(@.n > 0)]`, data)
+	if !errors.Is(err, ErrEvaluationTimeout) {
+		t.Errorf("Query() error = %v, want %v", err, ErrEvaluationTimeout)
+	}
 }
@@ -0,0 +1,150 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// errNotNative reports that queryUnion reached a value other than the maps,
+// slices and scalars JSON decodes to
+var errNotNative = errors.New("value is not decoded JSON")
+
+// queryUnion evaluates a path with index unions, as in $.items[0,2,4], or
+// quoted field names, as in $.user['name','email'] or $['first name'].
+// An index union selects the elements that exist, counting negative indexes
+// from the end, and the path returns an array of what it selects. A field
+// union, which must be the last step, selects an object of the fields that
+// exist, or an array of such objects after a wildcard or index union.
+func queryUnion(path string, steps []nativeStep, data interface{}) (interface{}, error) {
+	for i, step := range steps {
+		if step.kind == stepFieldUnion && i != len(steps)-1 {
+			return nil, fmt.Errorf("%w: field union must be the last step of %s", ErrInvalidJSONPath, path)
+		}
+	}
+
+	if lazy, ok := data.(LazyValue); ok {
+		decoded, err := loadLazy(lazy)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+	result, err := selectUnion(steps, data)
+	if !errors.Is(err, errNotNative) {
+		return result, err
+	}
+
+	// Other types are read as their JSON encoding
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode data: %w", err)
+	}
+	return selectUnion(steps, decoded)
+}
+
+// selectUnion applies steps to data, keeping every value they select. Once a
+// wildcard or index union has selected several values, values a step cannot
+// apply to are left out; before that, they are a type mismatch.
+func selectUnion(steps []nativeStep, data interface{}) (interface{}, error) {
+	nodes := []interface{}{data}
+	multi := false
+	for _, step := range steps {
+		var next []interface{}
+		for _, node := range nodes {
+			if !isJSONValue(node) {
+				return nil, errNotNative
+			}
+			switch step.kind {
+			case stepField:
+				if object, ok := node.(map[string]interface{}); ok {
+					if value, exists := object[step.name]; exists {
+						next = append(next, value)
+					}
+				}
+			case stepFieldUnion:
+				object, ok := node.(map[string]interface{})
+				if !ok {
+					if !multi {
+						return nil, fmt.Errorf("%w: field union needs an object, got %T", ErrTypeMismatch, node)
+					}
+					continue
+				}
+				selected := make(map[string]interface{}, len(step.names))
+				for _, name := range step.names {
+					if value, exists := object[name]; exists {
+						selected[name] = value
+					}
+				}
+				next = append(next, selected)
+			default:
+				items, ok := node.([]interface{})
+				if !ok {
+					if !multi {
+						return nil, fmt.Errorf("%w: array step needs an array, got %T", ErrTypeMismatch, node)
+					}
+					continue
+				}
+				next = append(next, selectElements(step, items)...)
+			}
+		}
+		nodes = next
+		multi = multi || step.kind == stepWildcard || step.kind == stepIndexUnion
+	}
+
+	if !multi {
+		if len(nodes) == 0 {
+			return nil, nil
+		}
+		return unionResult(nodes[0])
+	}
+	results := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		converted, err := unionResult(node)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, converted)
+	}
+	return results, nil
+}
+
+// selectElements returns the elements of items an index, wildcard or index
+// union step selects, skipping indexes out of range
+func selectElements(step nativeStep, items []interface{}) []interface{} {
+	switch step.kind {
+	case stepWildcard:
+		return items
+	case stepIndex:
+		return elementsAt(items, []int{step.index})
+	default:
+		return elementsAt(items, step.indexes)
+	}
+}
+
+// elementsAt returns the elements of items at indexes, in their order
+func elementsAt(items []interface{}, indexes []int) []interface{} {
+	var selected []interface{}
+	for _, index := range indexes {
+		if index < 0 {
+			index += len(items)
+		}
+		if index >= 0 && index < len(items) {
+			selected = append(selected, items[index])
+		}
+	}
+	return selected
+}
+
+// unionResult copies a selected value the way jsonResult does
+func unionResult(value interface{}) (interface{}, error) {
+	converted, ok := jsonResult(value, true)
+	if !ok {
+		return nil, errNotNative
+	}
+	return converted, nil
+}
@@ -0,0 +1,103 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJSONPathUnions(t *testing.T) {
+	data := map[string]interface{}{
+		"items": []interface{}{"a", "b", "c", "d", "e"},
+		"user":  map[string]interface{}{"name": "Ada", "email": "ada@example.com", "age": 36, "first name": "Ada"},
+		"users": []interface{}{
+			map[string]interface{}{"name": "Ada", "email": "ada@example.com", "age": 36},
+			map[string]interface{}{"name": "Bob", "age": 41.5},
+			"loose",
+		},
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"$.items[0,2,4]", []interface{}{"a", "c", "e"}},
+		{"$.items[4, 0]", []interface{}{"e", "a"}},
+		{"$.items[0,-1,9]", []interface{}{"a", "e"}},
+		{"$.items[7,8]", []interface{}{}},
+		{"$.users[0,1].name", []interface{}{"Ada", "Bob"}},
+		{"$.users[0,1].email", []interface{}{"ada@example.com"}},
+		{"$.user['name','email']", map[string]interface{}{"name": "Ada", "email": "ada@example.com"}},
+		{`$.user["name", 'zip']`, map[string]interface{}{"name": "Ada"}},
+		{"$.user['name']", "Ada"},
+		{"$['user']['first name']", "Ada"},
+		{"$.missing['name','email']", nil},
+		{"$.users[*]['name','age']", []interface{}{
+			map[string]interface{}{"name": "Ada", "age": 36},
+			map[string]interface{}{"name": "Bob", "age": 41.5},
+		}},
+		{"$.users[0,2]['email']", []interface{}{"ada@example.com"}},
+	}
+
+	querier := NewJSONPathQuerier()
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := querier.Query(context.Background(), tt.path, data)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathUnionsOnOtherInput(t *testing.T) {
+	querier := NewJSONPathQuerier()
+	ctx := context.Background()
+
+	lazy := &jsonSource{json: `{"user": {"name": "Ada", "email": "ada@example.com", "age": 36}}`}
+	got, err := querier.Query(ctx, "$.user['name','age']", lazy)
+	if want := map[string]interface{}{"name": "Ada", "age": 36}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() on lazy value = %#v, %v, want %#v", got, err, want)
+	}
+
+	// Types other than those JSON decodes to are read as their encoding
+	typed := map[string]interface{}{"scores": []float32{1, 2.5, 4}}
+	got, err = querier.Query(ctx, "$.scores[0,2]", typed)
+	if want := []interface{}{1, 4}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() on typed slice = %#v, %v, want %#v", got, err, want)
+	}
+}
+
+func TestJSONPathUnionErrors(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "store",
+		"items": []interface{}{1, 2, 3},
+		"user":  map[string]interface{}{"name": "Ada"},
+	}
+
+	tests := []struct {
+		path    string
+		wantErr error
+	}{
+		{"$.items[0,,2]", ErrInvalidJSONPath},
+		{"$.items[0,'name']", ErrInvalidJSONPath},
+		{"$.items[0,x]", ErrInvalidJSONPath},
+		{"$.user['name','email'].first", ErrInvalidJSONPath},
+		{"$.name[0,1]", ErrTypeMismatch},
+		{"$.items['name','email']", ErrTypeMismatch},
+	}
+
+	querier := NewJSONPathQuerier()
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := querier.Query(context.Background(), tt.path, data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Query() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// LazyValue is a value kept outside memory, such as a workflow variable
+// spilled to disk, whose JSON encoding is read only when a transformation
+// uses it.
+type LazyValue interface {
+	// OpenJSON opens the value's JSON encoding for streaming
+	OpenJSON() (io.ReadCloser, error)
+}
+
+// identifierPattern matches the names an expression or template may refer to
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// readLazyJSON returns the JSON encoding of a lazy value
+func readLazyJSON(value LazyValue) ([]byte, error) {
+	r, err := value.OpenJSON()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
+
+// loadLazy decodes a lazy value, streaming it from its source
+func loadLazy(value LazyValue) (interface{}, error) {
+	r, err := value.OpenJSON()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	var decoded interface{}
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode lazy value: %w", err)
+	}
+	return decoded, nil
+}
+
+// resolveLazyVariables returns the variables with the lazy values that expr
+// refers to loaded. Lazy values it does not mention stay unloaded, and vars
+// itself is not modified.
+func resolveLazyVariables(expr string, vars map[string]interface{}) (map[string]interface{}, error) {
+	var resolved map[string]interface{}
+	for _, name := range identifierPattern.FindAllString(expr, -1) {
+		lazy, ok := vars[name].(LazyValue)
+		if !ok {
+			continue
+		}
+		value, err := loadLazy(lazy)
+		if err != nil {
+			return nil, fmt.Errorf("variable '%s': %w", name, err)
+		}
+		if resolved == nil {
+			resolved = make(map[string]interface{}, len(vars))
+			for k, v := range vars {
+				resolved[k] = v
+			}
+		}
+		resolved[name] = value
+	}
+	if resolved == nil {
+		return vars, nil
+	}
+	return resolved, nil
+}
+
+// jsonPathInput returns the JSON encoding of a query's data, and the data
+// itself. Lazy values are read as stored instead of being decoded and
+// encoded again; they are decoded only for queries that use the values
+// themselves (the root, wildcards and recursive descent).
+func jsonPathInput(path string, data interface{}) ([]byte, interface{}, error) {
+	lazy, ok := data.(LazyValue)
+	if !ok {
+		jsonBytes, err := json.Marshal(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+		return jsonBytes, data, nil
+	}
+
+	jsonBytes, err := readLazyJSON(lazy)
+	if err != nil {
+		return nil, nil, err
+	}
+	trimmed := strings.TrimSpace(path)
+	if trimmed == "$" || strings.Contains(trimmed, "[*]") || strings.Contains(trimmed, "..") {
+		var decoded interface{}
+		if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode lazy value: %w", err)
+		}
+		data = decoded
+	}
+	return jsonBytes, data, nil
+}
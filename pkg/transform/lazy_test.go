@@ -0,0 +1,59 @@
+package transform
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// jsonSource is a lazy value that counts how often it is read
+type jsonSource struct {
+	json  string
+	reads int
+}
+
+func (s *jsonSource) OpenJSON() (io.ReadCloser, error) {
+	s.reads++
+	return io.NopCloser(strings.NewReader(s.json)), nil
+}
+
+func TestLazyValues(t *testing.T) {
+	ctx := context.Background()
+	orders := &jsonSource{json: `{"items":[{"sku":"a","qty":2},{"sku":"b","qty":5}]}`}
+
+	t.Run("jsonpath", func(t *testing.T) {
+		got, err := NewJSONPathQuerier().Query(ctx, "$.items[1].sku", orders)
+		if err != nil || got != "b" {
+			t.Errorf("Query() = %v, %v; want b", got, err)
+		}
+		got, err = NewJSONPathQuerier().Query(ctx, "$.items[*].qty", orders)
+		if err != nil || !reflect.DeepEqual(got, []interface{}{2, 5}) {
+			t.Errorf("wildcard Query() = %v, %v", got, err)
+		}
+	})
+
+	t.Run("expression", func(t *testing.T) {
+		unused := &jsonSource{json: `"never read"`}
+		vars := map[string]interface{}{"orders": orders, "other": unused, "limit": 1}
+		got, err := NewExpressionEvaluator().Evaluate(ctx, "len(orders.items) > limit", vars)
+		if err != nil || got != true {
+			t.Errorf("Evaluate() = %v, %v; want true", got, err)
+		}
+		if unused.reads != 0 {
+			t.Error("lazy values the expression does not use should not be read")
+		}
+		if _, lazy := vars["orders"].(LazyValue); !lazy {
+			t.Error("the caller's variables should not be modified")
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		customer := &jsonSource{json: `{"name":"Ada"}`}
+		got, err := NewTemplateRenderer().Render(ctx, "Hello ${customer.name}", map[string]interface{}{"customer": customer})
+		if err != nil || got != "Hello Ada" {
+			t.Errorf("Render() = %q, %v", got, err)
+		}
+	})
+}
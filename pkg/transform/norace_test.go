@@ -0,0 +1,7 @@
+//go:build !race
+
+package transform
+
+// raceEnabled reports whether tests run with the race detector, which slows
+// queries too much for their latency budgets
+const raceEnabled = false
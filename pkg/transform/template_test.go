@@ -664,3 +664,169 @@ func TestTemplatePerformance(t *testing.T) {
 		}
 	})
 }
+
+// TestTemplateFilters tests piping values through helpers with ${value | filter}
+func TestTemplateFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		context  map[string]interface{}
+		strict   bool
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "single filter",
+			template: "${name | upper}",
+			context:  map[string]interface{}{"name": "john"},
+			want:     "JOHN",
+		},
+		{
+			name:     "chained filters",
+			template: "${name | trim | upper}",
+			context:  map[string]interface{}{"name": "  john  "},
+			want:     "JOHN",
+		},
+		{
+			name:     "filter with arguments",
+			template: `${title | replace:"-"," " | truncate:9}`,
+			context:  map[string]interface{}{"title": "hello-world-again"},
+			want:     "hello wor",
+		},
+		{
+			name:     "default for missing variable",
+			template: `${missing | default:"x"}`,
+			context:  map[string]interface{}{},
+			want:     "x",
+		},
+		{
+			name:     "default in strict mode",
+			template: `${user.nickname | default:"guest" | upper}`,
+			context:  map[string]interface{}{"user": map[string]interface{}{}},
+			strict:   true,
+			want:     "GUEST",
+		},
+		{
+			name:     "default keeps present value",
+			template: `${name | default:"x"}`,
+			context:  map[string]interface{}{"name": "alice"},
+			want:     "alice",
+		},
+		{
+			name:     "default from variable",
+			template: `${missing | default:fallback}`,
+			context:  map[string]interface{}{"fallback": "y"},
+			want:     "y",
+		},
+		{
+			name:     "quoted argument with braces and pipes",
+			template: `${missing | default:"{a|b}"}`,
+			context:  map[string]interface{}{},
+			want:     "{a|b}",
+		},
+		{
+			name:     "filter after helper call",
+			template: "${join(tags, ',') | upper}",
+			context:  map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			want:     "A,B",
+		},
+		{
+			name:     "literal head",
+			template: `${"done" | capitalize}`,
+			context:  map[string]interface{}{},
+			want:     "Done",
+		},
+		{
+			name:     "json filter",
+			template: "${items | json}",
+			context:  map[string]interface{}{"items": []interface{}{1, "a"}},
+			want:     `[1,"a"]`,
+		},
+		{
+			name:     "missing variable without default in strict mode",
+			template: "${missing | upper}",
+			context:  map[string]interface{}{},
+			strict:   true,
+			wantErr:  true,
+		},
+		{
+			name:     "unknown filter",
+			template: "${name | shout}",
+			context:  map[string]interface{}{"name": "john"},
+			wantErr:  true,
+		},
+		{
+			name:     "missing value before filter",
+			template: "${ | upper}",
+			context:  map[string]interface{}{},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid filter name",
+			template: "${name | up-per}",
+			context:  map[string]interface{}{"name": "john"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := NewTemplateRenderer()
+			renderer.SetStrictMode(tt.strict)
+			got, err := renderer.Render(context.Background(), tt.template, tt.context)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTemplateNestedPaths tests path access through maps, lists and structs
+func TestTemplateNestedPaths(t *testing.T) {
+	type owner struct {
+		Name string
+	}
+	templateContext := map[string]interface{}{
+		"order": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"sku": "A-1"},
+				map[string]interface{}{"sku": "B-2"},
+			},
+			"labels": map[string]string{"first class": "yes"},
+		},
+		"owner":  &owner{Name: "Ada"},
+		"scores": []int{7, 9},
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "${order.items[1].sku}", want: "B-2"},
+		{template: "${order.items.0.sku}", want: "A-1"},
+		{template: `${order.labels["first class"]}`, want: "yes"},
+		{template: "${owner.Name}", want: "Ada"},
+		{template: "${scores[1]}", want: "9"},
+		{template: `${order.items[5].sku | default:"none"}`, want: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			renderer := NewTemplateRenderer()
+			renderer.SetStrictMode(true)
+			got, err := renderer.Render(context.Background(), tt.template, templateContext)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultEvaluationTimeout bounds an expression evaluation or JSONPath query
+// when the context sets no evaluation timeout
+const DefaultEvaluationTimeout = 5 * time.Second
+
+// evaluationTimeoutKey is the context key of the evaluation timeout
+type evaluationTimeoutKey struct{}
+
+// WithEvaluationTimeout returns a context under which each expression
+// evaluation and JSONPath query may take at most timeout. A timeout of zero
+// or less keeps DefaultEvaluationTimeout.
+func WithEvaluationTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if timeout <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, evaluationTimeoutKey{}, timeout)
+}
+
+// EvaluationTimeout returns the evaluation timeout set on ctx, or
+// DefaultEvaluationTimeout
+func EvaluationTimeout(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(evaluationTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return DefaultEvaluationTimeout
+}
+
+// startEvaluation returns a context that expires after the evaluation
+// timeout with ErrEvaluationTimeout as its cause
+func startEvaluation(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(ctx, EvaluationTimeout(ctx), ErrEvaluationTimeout)
+}
+
+// evaluationError returns why an evaluation's context ended:
+// ErrEvaluationTimeout if its timeout expired, the parent's error otherwise
+func evaluationError(ctx context.Context) error {
+	if errors.Is(context.Cause(ctx), ErrEvaluationTimeout) {
+		return ErrEvaluationTimeout
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,53 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// heavyExpression iterates 640,000 times within the memory budget
+const heavyExpression = `any(1..800, {any(1..800, {# < 0})})`
+
+// TestEvaluationTimeout tests that the evaluation timeout comes from the
+// context and stops evaluations that exceed it
+func TestEvaluationTimeout(t *testing.T) {
+	ctx := context.Background()
+	if got := EvaluationTimeout(ctx); got != DefaultEvaluationTimeout {
+		t.Errorf("EvaluationTimeout() = %v, want %v", got, DefaultEvaluationTimeout)
+	}
+	if got := EvaluationTimeout(WithEvaluationTimeout(ctx, 0)); got != DefaultEvaluationTimeout {
+		t.Errorf("EvaluationTimeout() with zero = %v, want %v", got, DefaultEvaluationTimeout)
+	}
+	if got := EvaluationTimeout(WithEvaluationTimeout(ctx, 2*time.Second)); got != 2*time.Second {
+		t.Errorf("EvaluationTimeout() = %v, want 2s", got)
+	}
+
+	evaluator := NewExpressionEvaluator()
+
+	// The heavy expression completes within the default timeout
+	result, err := evaluator.Evaluate(ctx, heavyExpression, nil)
+	if err != nil || result != false {
+		t.Fatalf("Evaluate() = %v, %v, want false", result, err)
+	}
+
+	// A short timeout stops it at the next checkpoint
+	start := time.Now()
+	_, err = evaluator.Evaluate(WithEvaluationTimeout(ctx, time.Millisecond), heavyExpression, nil)
+	if !errors.Is(err, ErrEvaluationTimeout) {
+		t.Errorf("Evaluate() error = %v, want %v", err, ErrEvaluationTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Evaluate() returned after %v, want it stopped at the timeout", elapsed)
+	}
+
+	// Cancelling the caller's context reports its error, not a timeout
+	cancelled, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(time.Millisecond, cancel)
+	defer timer.Stop()
+	_, err = evaluator.Evaluate(cancelled, heavyExpression, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Evaluate() after cancellation error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -14,8 +14,46 @@ const (
 	TransformTypeJSONPath
 	TransformTypeExpression
 	TransformTypeTemplate
+	TransformTypeJQ
 )
 
+// transformTypeNames are the names a Transform node selects its language by
+var transformTypeNames = map[TransformType]string{
+	TransformTypeJSONPath:   "jsonpath",
+	TransformTypeExpression: "expr",
+	TransformTypeTemplate:   "template",
+	TransformTypeJQ:         "jq",
+}
+
+// String returns the name of the transformation type, or auto for
+// TransformTypeUnknown, which is detected from the expression
+func (t TransformType) String() string {
+	if name, ok := transformTypeNames[t]; ok {
+		return name
+	}
+	return "auto"
+}
+
+// TransformTypeNames lists the names ParseTransformType accepts
+func TransformTypeNames() []string {
+	return []string{"auto", "jsonpath", "expr", "template", "jq"}
+}
+
+// ParseTransformType returns the transformation type named name. An empty
+// name or auto is TransformTypeUnknown, which is detected from the
+// expression.
+func ParseTransformType(name string) (TransformType, error) {
+	if name == "" || name == "auto" {
+		return TransformTypeUnknown, nil
+	}
+	for t, typeName := range transformTypeNames {
+		if typeName == name {
+			return t, nil
+		}
+	}
+	return TransformTypeUnknown, fmt.Errorf("unknown transform language %q (want one of %s)", name, strings.Join(TransformTypeNames(), ", "))
+}
+
 // Transformer provides a unified interface for all transformation types
 type Transformer struct {
 	jsonPath   JSONPathQuerier
@@ -51,6 +89,31 @@ func (t *Transformer) Transform(ctx context.Context, expr string, data interface
 			return nil, fmt.Errorf("template rendering requires map[string]interface{} context")
 		}
 		return t.template.Render(ctx, expr, dataMap)
+	case TransformTypeJQ:
+		return QueryJQ(ctx, expr, data, nil)
+	default:
+		return nil, fmt.Errorf("unable to determine transformation type for expression: %s", expr)
+	}
+}
+
+// TransformAs applies expr as a transformation of the given type, detecting
+// the type from expr for TransformTypeUnknown. JSONPath queries and jq
+// programs operate on input, and jq programs can also read variables as
+// $name; expressions and templates are evaluated against variables.
+func (t *Transformer) TransformAs(ctx context.Context, transformType TransformType, expr string, input interface{}, variables map[string]interface{}) (interface{}, error) {
+	if transformType == TransformTypeUnknown {
+		transformType = detectTransformType(expr)
+	}
+
+	switch transformType {
+	case TransformTypeJSONPath:
+		return t.jsonPath.Query(ctx, expr, input)
+	case TransformTypeExpression:
+		return t.expression.Evaluate(ctx, expr, variables)
+	case TransformTypeTemplate:
+		return t.template.Render(ctx, expr, variables)
+	case TransformTypeJQ:
+		return QueryJQ(ctx, expr, input, variables)
 	default:
 		return nil, fmt.Errorf("unable to determine transformation type for expression: %s", expr)
 	}
@@ -60,6 +123,11 @@ func (t *Transformer) Transform(ctx context.Context, expr string, data interface
 func detectTransformType(expr string) TransformType {
 	trimmed := strings.TrimSpace(expr)
 
+	// Check for jq: jq(program)
+	if IsJQExpression(trimmed) {
+		return TransformTypeJQ
+	}
+
 	// Check for JSONPath: starts with $ or $.
 	if strings.HasPrefix(trimmed, "$.") || trimmed == "$" {
 		return TransformTypeJSONPath
@@ -114,8 +182,27 @@ func TransformExpression(ctx context.Context, expression string, context map[str
 	return evaluator.Evaluate(ctx, expression, context)
 }
 
+// TransformJQ explicitly runs a jq program on data
+func TransformJQ(ctx context.Context, program string, data interface{}, variables map[string]interface{}) (interface{}, error) {
+	return QueryJQ(ctx, program, data, variables)
+}
+
 // TransformTemplate explicitly renders a template
 func TransformTemplate(ctx context.Context, template string, context map[string]interface{}) (string, error) {
 	renderer := NewTemplateRenderer()
 	return renderer.Render(ctx, template, context)
 }
+
+// TransformJSONPathPartial applies a wildcard or filter JSONPath query and
+// returns the extracted values together with per-element errors
+func TransformJSONPathPartial(ctx context.Context, path string, data interface{}) (*PartialResult, error) {
+	querier := &gjsonQuerier{}
+	return querier.QueryPartial(ctx, path, data)
+}
+
+// TransformJSONPathWithPaths applies a JSONPath query and returns each value
+// it selects with the normalized path to it
+func TransformJSONPathWithPaths(ctx context.Context, path string, data interface{}) ([]JSONPathMatch, error) {
+	querier := &gjsonQuerier{}
+	return querier.QueryWithPaths(ctx, path, data)
+}
@@ -2,14 +2,17 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/dshills/goflow/pkg/config"
 	"github.com/dshills/goterm"
 )
 
@@ -18,12 +21,24 @@ type App struct {
 	screen        *goterm.Screen
 	viewManager   *ViewManager
 	keyboard      *KeyboardHandler
+	keymap        Keymap // Effective keymap (defaults plus ~/.goflow/keymap.yaml)
+	commands      *CommandRegistry
+	commandLine   *CommandLine
+	finder        *FuzzyFinder
+	messages      *MessageLog
+	layout        *Layout
 	running       bool
 	mu            sync.RWMutex
 	ctx           context.Context
 	cancel        context.CancelFunc
 	inputChan     chan KeyEvent
 	lastFrameTime time.Time
+	tunablesChan  chan config.Tunables
+	configTheme   string // The theme last named by the config file
+	unsubscribe   func()
+	signal        os.Signal // Signal that stopped Run, if any
+	session       *Session  // Restored on startup and saved by Close
+	sessionPath   string
 }
 
 // NewApp creates a new TUI application instance
@@ -42,21 +57,50 @@ func NewApp() (*App, error) {
 	// Create keyboard handler
 	keyboard := NewKeyboardHandler()
 
+	// Load the user's keymap; conflicts are reported before the UI starts
+	keymap, err := LoadKeymap(DefaultKeymapPath())
+	if err != nil {
+		cancel()
+		if closeErr := CloseScreen(screen); closeErr != nil {
+			return nil, fmt.Errorf("failed to load keymap: %w (and failed to close screen: %v)", err, closeErr)
+		}
+		return nil, fmt.Errorf("failed to load keymap: %w", err)
+	}
+
+	tunables := config.Global().Get()
+	commands := NewCommandRegistry()
+
+	// A session that can't be read is replaced rather than stopping the UI
+	sessionPath := DefaultSessionPath()
+	session, sessionErr := LoadSession(sessionPath)
+	if sessionErr != nil {
+		session = NewSession()
+	}
+
 	app := &App{
 		screen:        screen,
 		viewManager:   viewManager,
 		keyboard:      keyboard,
+		keymap:        keymap,
+		commands:      commands,
+		commandLine:   NewCommandLine(commands),
 		running:       false,
 		ctx:           ctx,
 		cancel:        cancel,
-		inputChan:     make(chan KeyEvent, 100),
+		inputChan:     make(chan KeyEvent, tunables.InputQueueSize),
 		lastFrameTime: time.Now(),
+		tunablesChan:  make(chan config.Tunables, 1),
+		session:       session,
+		sessionPath:   sessionPath,
+	}
+	if sessionErr != nil {
+		app.commandLine.ShowError(sessionErr)
 	}
 
 	// Register default views
 	if err := app.registerViews(); err != nil {
 		// Error path: Log Close() errors to stderr instead of silently ignoring
-		if closeErr := screen.Close(); closeErr != nil {
+		if closeErr := CloseScreen(screen); closeErr != nil {
 			// Use %w for primary error (register views) and %v for secondary error (screen close)
 			// because we want error chain unwrapping to focus on the root cause (register failure)
 			// while still reporting the cleanup failure for debugging
@@ -65,10 +109,23 @@ func NewApp() (*App, error) {
 		return nil, fmt.Errorf("failed to register views: %w", err)
 	}
 
+	app.finder = NewFuzzyFinder(app.selectFinderItem)
+	app.messages = NewMessageLog()
+	app.layout = NewLayout()
+	viewManager.AddSwitchHook(app.followSwitch)
+
+	// Register command-mode commands from the app and its views
+	if err := app.registerCommands(); err != nil {
+		if closeErr := CloseScreen(screen); closeErr != nil {
+			return nil, fmt.Errorf("failed to register commands: %w (and failed to close screen: %v)", err, closeErr)
+		}
+		return nil, fmt.Errorf("failed to register commands: %w", err)
+	}
+
 	// Register default keybindings
 	if err := app.registerGlobalKeybindings(); err != nil {
 		// Error path: Log Close() errors to stderr instead of silently ignoring
-		if closeErr := screen.Close(); closeErr != nil {
+		if closeErr := CloseScreen(screen); closeErr != nil {
 			// Use %w for primary error (register keybindings) and %v for secondary error (screen close)
 			return nil, fmt.Errorf("failed to register keybindings: %w (and failed to close screen: %v)", err, closeErr)
 		}
@@ -78,16 +135,64 @@ func NewApp() (*App, error) {
 	// Initialize view manager with workflow explorer
 	if err := viewManager.Initialize("explorer"); err != nil {
 		// Error path: Log Close() errors to stderr instead of silently ignoring
-		if closeErr := screen.Close(); closeErr != nil {
+		if closeErr := CloseScreen(screen); closeErr != nil {
 			// Use %w for primary error (initialize view manager) and %v for secondary error (screen close)
 			return nil, fmt.Errorf("failed to initialize view manager: %w (and failed to close screen: %v)", err, closeErr)
 		}
 		return nil, fmt.Errorf("failed to initialize view manager: %w", err)
 	}
 
+	// Apply tunables now and whenever the configuration changes.
+	// Changes are handed to the main loop so views are only touched there.
+	app.applyTunables(tunables)
+	app.unsubscribe = config.Global().Subscribe(func(t config.Tunables) {
+		// Keep only the latest value
+		select {
+		case <-app.tunablesChan:
+		default:
+		}
+		select {
+		case app.tunablesChan <- t:
+		default:
+		}
+	})
+
 	return app, nil
 }
 
+// applyTunables pushes configuration tunables to the views that use them
+func (a *App) applyTunables(t config.Tunables) {
+	view, err := a.viewManager.GetView("builder")
+	if err != nil {
+		return
+	}
+	if builderView, ok := view.(*WorkflowBuilderView); ok {
+		builderView.ApplyTunables(t)
+	}
+
+	// The configured theme is applied when it changes, so one picked with
+	// :theme lasts until the config file names another
+	if t.Theme != a.configTheme {
+		a.configTheme = t.Theme
+		if err := applyTheme(t.Theme); err != nil {
+			a.commandLine.ShowError(err)
+		}
+	}
+}
+
+// applyTheme draws the TUI with the named theme; empty names the default
+func applyTheme(name string) error {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	theme, err := LoadTheme(name, DefaultThemesDir())
+	if err != nil {
+		return err
+	}
+	SetTheme(theme)
+	return nil
+}
+
 // registerViews registers all available views
 func (a *App) registerViews() error {
 	// Register workflow explorer view
@@ -98,6 +203,7 @@ func (a *App) registerViews() error {
 
 	// Register workflow builder view
 	builderView := NewWorkflowBuilderView()
+	builderView.SetOnLoad(a.recordRecent)
 	if err := a.viewManager.RegisterView(builderView); err != nil {
 		return fmt.Errorf("failed to register builder view: %w", err)
 	}
@@ -110,13 +216,120 @@ func (a *App) registerViews() error {
 
 	// Register server registry view
 	registryView := NewServerRegistryView()
+	registryView.UsePersistentRegistry(DefaultServersPath())
 	if err := a.viewManager.RegisterView(registryView); err != nil {
 		return fmt.Errorf("failed to register registry view: %w", err)
 	}
+	builderView.SetServerRegistry(registryView.registry)
+	registryView.SetOpenWorkflow(builderView.Workflow)
+
+	// Register the workflow browser, which opens workflows in the builder
+	browserView := NewWorkflowBrowserView()
+	browserView.SetOpener(builderView.OpenFile)
+	if err := a.viewManager.RegisterView(browserView); err != nil {
+		return fmt.Errorf("failed to register browser view: %w", err)
+	}
+
+	// Register the audit log
+	if err := a.viewManager.RegisterView(NewAuditView()); err != nil {
+		return fmt.Errorf("failed to register audit view: %w", err)
+	}
+
+	// Register the run form, which launches executions into the monitor
+	runView := NewRunWorkflowView()
+	runView.SetOpenWorkflow(builderView.Workflow)
+	runView.SetLauncher(monitorView.Launch)
+	if err := a.viewManager.RegisterView(runView); err != nil {
+		return fmt.Errorf("failed to register run view: %w", err)
+	}
 
 	return nil
 }
 
+// registerCommands registers the built-in commands and those of every view
+// implementing CommandRegistrar
+func (a *App) registerCommands() error {
+	config := DefaultBindingsConfig{
+		OnQuit: func() error {
+			a.cancel()
+			return nil
+		},
+		OnExecuteCommand: func(command string) error {
+			switch command {
+			case "save":
+				view, err := a.viewManager.GetView("builder")
+				if err != nil {
+					return err
+				}
+				builderView, ok := view.(*WorkflowBuilderView)
+				if !ok {
+					return fmt.Errorf("builder view cannot save")
+				}
+				return builderView.Save()
+			case "force_quit":
+				a.cancel()
+				return nil
+			}
+			return fmt.Errorf("unknown command: %s", command)
+		},
+	}
+	if err := RegisterBuiltinCommands(a.commands, config); err != nil {
+		return err
+	}
+	if err := a.registerLayoutCommands(); err != nil {
+		return err
+	}
+	if err := a.registerEnvironmentCommand(); err != nil {
+		return err
+	}
+	if err := a.registerRecentCommand(); err != nil {
+		return err
+	}
+	err := a.commands.Register(Command{
+		Name:        "messages",
+		Description: "Show the message history",
+		Run: func(args []string) error {
+			a.messages.Open()
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = a.commands.Register(Command{
+		Name:        "theme",
+		Usage:       "<name>",
+		Description: "Switch the color theme",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run: func(args []string) error {
+			return applyTheme(args[0])
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return ThemeNames(DefaultThemesDir())
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range a.viewManager.ListViews() {
+		view, err := a.viewManager.GetView(name)
+		if err != nil {
+			return err
+		}
+		if registrar, ok := view.(CommandRegistrar); ok {
+			if err := registrar.RegisterCommands(a.commands); err != nil {
+				return fmt.Errorf("failed to register %s view commands: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // registerGlobalKeybindings registers application-wide keybindings
 func (a *App) registerGlobalKeybindings() error {
 	// Ctrl+C: Quit application
@@ -131,16 +344,11 @@ func (a *App) registerGlobalKeybindings() error {
 		return err
 	}
 
-	// q: Quit application (in normal mode only)
-	if err := a.keyboard.RegisterBinding(
-		ModeNormal,
-		KeyEvent{Key: 'q'},
-		func(event KeyEvent) error {
-			a.cancel()
-			return nil
-		},
-		"Quit application",
-	); err != nil {
+	// Quit application (in normal mode only), q unless remapped
+	if err := a.bindKeymapAction(ModeNormal, "quit", func(event KeyEvent) error {
+		a.cancel()
+		return nil
+	}, "Quit application"); err != nil {
 		return err
 	}
 
@@ -155,22 +363,139 @@ func (a *App) registerGlobalKeybindings() error {
 		return err
 	}
 
-	// ?: Show help (will be implemented by views)
-	if err := a.keyboard.RegisterBinding(
-		ModeNormal,
-		KeyEvent{Key: '?'},
-		func(event KeyEvent) error {
-			// TODO: Show help overlay
-			return nil
-		},
-		"Show help",
-	); err != nil {
+	// Show help (will be implemented by views), ? unless remapped
+	if err := a.bindKeymapAction(ModeNormal, "toggle_help", func(event KeyEvent) error {
+		// TODO: Show help overlay
+		return nil
+	}, "Show help"); err != nil {
+		return err
+	}
+
+	// Open the command line, : unless remapped
+	if err := a.bindKeymapAction(ModeNormal, "command_mode", func(event KeyEvent) error {
+		a.commandLine.Open()
+		return nil
+	}, "Enter command mode"); err != nil {
+		return err
+	}
+
+	// Fuzzy finder, Ctrl-p unless remapped
+	if err := a.bindKeymapAction(ModeNormal, "find", func(event KeyEvent) error {
+		a.finder.Open(a.finderItems())
+		return nil
+	}, "Fuzzy find"); err != nil {
+		return err
+	}
+
+	// Pane commands, Ctrl-w unless remapped
+	if err := a.bindKeymapAction(ModeNormal, "pane", func(event KeyEvent) error {
+		a.layout.pending = true
+		return nil
+	}, "Pane command"); err != nil {
+		return err
+	}
+
+	// Message history, Ctrl-g unless remapped
+	if err := a.bindKeymapAction(ModeNormal, "messages", func(event KeyEvent) error {
+		a.messages.Open()
+		return nil
+	}, "Show the message history"); err != nil {
+		return err
+	}
+
+	// Macros record raw keys and replay them through handleKeyEvent, so the
+	// active view sees the replayed keys
+	a.keyboard.SetMacroPlayer(a.handleKeyEvent)
+	if err := a.bindKeymapAction(ModeNormal, "record_macro", recordMacro(a.keyboard, DefaultBindingsConfig{}), "Record macro"); err != nil {
+		return err
+	}
+	if err := a.bindKeymapAction(ModeNormal, "play_macro", playMacro(a.keyboard, DefaultBindingsConfig{}), "Play macro"); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// finderItems collects what the fuzzy finder searches: canvas nodes, saved
+// workflows and registered commands
+func (a *App) finderItems() []FinderItem {
+	var items []FinderItem
+	if view, err := a.viewManager.GetView("builder"); err == nil {
+		if builderView, ok := view.(*WorkflowBuilderView); ok {
+			items = append(items, builderView.FinderItems()...)
+		}
+	}
+	for _, cmd := range a.commands.Commands() {
+		items = append(items, FinderItem{
+			Kind:   FinderCommand,
+			Label:  cmd.Name,
+			Detail: strings.TrimSpace(cmd.Usage + "  " + cmd.Description),
+			Value:  cmd.Name,
+		})
+	}
+	return items
+}
+
+// selectFinderItem acts on the item chosen in the fuzzy finder. Commands
+// that take arguments open the command line ready for them.
+func (a *App) selectFinderItem(item FinderItem) error {
+	switch item.Kind {
+	case FinderCommand:
+		for _, cmd := range a.commands.Commands() {
+			if cmd.Name == item.Value && cmd.MinArgs > 0 {
+				a.commandLine.OpenWith(cmd.Name + " ")
+				return nil
+			}
+		}
+		return a.commands.Execute(item.Value)
+	case FinderEnvironment:
+		return a.selectEnvironment(item.Value)
+	case FinderRecent:
+		builderView, err := a.builderView()
+		if err != nil {
+			return err
+		}
+		return builderView.OpenFile(item.Value)
+	case FinderNode, FinderWorkflow:
+		view, err := a.viewManager.GetView("builder")
+		if err != nil {
+			return err
+		}
+		builderView, ok := view.(*WorkflowBuilderView)
+		if !ok {
+			return fmt.Errorf("builder view unavailable")
+		}
+		if item.Kind == FinderNode {
+			return builderView.JumpToNode(item.Value)
+		}
+		if err := builderView.open(item.Value); err != nil {
+			return err
+		}
+		if item.Node != "" {
+			return builderView.JumpToNode(item.Node)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown finder item: %s", item.Kind)
+}
+
+// bindKeymapAction registers handler on the key the keymap assigns to an
+// action; unbound actions are skipped
+func (a *App) bindKeymapAction(mode Mode, action string, handler KeyHandler, label string) error {
+	spec := a.keymap.Key(mode, action)
+	if spec == "" {
+		return nil
+	}
+	key, sequence, err := parseKeySpec(spec)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	if sequence != "" {
+		return a.keyboard.registerSequence(mode, sequence, handler, label)
+	}
+	return a.keyboard.RegisterBinding(mode, key, handler, label)
+}
+
 // Run starts the TUI application main loop
 func (a *App) Run() error {
 	a.mu.Lock()
@@ -183,9 +508,11 @@ func (a *App) Run() error {
 		a.mu.Unlock()
 	}()
 
-	// Set up signal handling for graceful shutdown
+	// Set up signal handling for graceful shutdown. SIGHUP arrives when
+	// the terminal is closed.
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
 
 	// Start keyboard input goroutine
 	go a.readKeyboardInput()
@@ -204,7 +531,10 @@ func (a *App) Run() error {
 		case <-a.ctx.Done():
 			return nil
 
-		case <-sigChan:
+		case sig := <-sigChan:
+			a.mu.Lock()
+			a.signal = sig
+			a.mu.Unlock()
 			a.cancel()
 			return nil
 
@@ -217,7 +547,18 @@ func (a *App) Run() error {
 				return err
 			}
 
-		case <-ticker.C:
+		case t := <-a.tunablesChan:
+			a.applyTunables(t)
+
+		case now := <-ticker.C:
+			// Let the active view run periodic work before drawing
+			if view, ok := a.viewManager.GetCurrentView().(Ticker); ok {
+				view.Tick(now)
+			}
+			if view, ok := a.otherPaneView().(Ticker); ok {
+				view.Tick(now)
+			}
+
 			// Regular frame update
 			if err := a.render(); err != nil {
 				return err
@@ -228,10 +569,61 @@ func (a *App) Run() error {
 
 // handleKeyEvent processes keyboard input through the keyboard handler
 func (a *App) handleKeyEvent(event KeyEvent) error {
+	a.commandLine.ClearMessage()
+
+	// The finder takes every key while open
+	if a.finder.IsVisible() {
+		a.keyboard.captureKey(event)
+		if err := a.finder.HandleKey(event); err != nil {
+			a.commandLine.ShowError(err)
+		}
+		return nil
+	}
+
+	// So does the message history
+	if a.messages.IsVisible() {
+		a.keyboard.captureKey(event)
+		a.messages.HandleKey(event)
+		return nil
+	}
+
+	// The key after Ctrl-w is a pane command
+	if a.layout.pending {
+		a.keyboard.captureKey(event)
+		if err := a.handlePaneKey(event); err != nil {
+			a.commandLine.ShowError(err)
+		}
+		return nil
+	}
+
+	// The command line takes every key while open, including Tab
+	if a.commandLine.Active() {
+		a.keyboard.captureKey(event)
+		if err := a.commandLine.HandleKey(event); err != nil {
+			// Shown on the command line; not fatal to the app
+			return nil
+		}
+		return nil
+	}
+
+	// So does a view while it takes text input
+	if capturer, ok := a.viewManager.GetCurrentView().(TextCapturer); ok && capturer.CapturingText() {
+		a.keyboard.captureKey(event)
+		if err := a.viewManager.GetCurrentView().HandleKey(event); err != nil {
+			return fmt.Errorf("view key handler error: %w", err)
+		}
+		return nil
+	}
+
 	// First, let the keyboard handler process global bindings
-	if err := a.keyboard.HandleKey(event); err != nil {
+	consumed, err := a.keyboard.Dispatch(event)
+	if err != nil {
 		return fmt.Errorf("keyboard handler error: %w", err)
 	}
+	if consumed {
+		// Macro register names are not input for the view
+		return nil
+	}
 
 	// Then pass to the current view
 	currentView := a.viewManager.GetCurrentView()
@@ -254,13 +646,23 @@ func (a *App) render() error {
 	// Clear screen buffer
 	a.screen.Clear()
 
-	// Render current view
+	// Render current view, beside the other pane's if the screen is split
 	if currentView != nil {
-		if err := currentView.Render(a.screen); err != nil {
+		var err error
+		if other := a.otherPaneView(); other != nil {
+			err = a.layout.Render(a.screen, currentView, other)
+		} else {
+			err = currentView.Render(a.screen)
+		}
+		if err != nil {
 			return fmt.Errorf("view render failed: %w", err)
 		}
 	}
 
+	a.finder.Render(a.screen)
+	a.messages.Render(a.screen)
+	a.renderCommandLine()
+
 	// Show the screen
 	if err := a.screen.Show(); err != nil {
 		return fmt.Errorf("screen show failed: %w", err)
@@ -280,6 +682,61 @@ func (a *App) render() error {
 	return nil
 }
 
+// renderCommandLine draws the command line, or the result of the last
+// command, on the bottom row over the current view
+func (a *App) renderCommandLine() {
+	width, height := a.screen.Size()
+	if height == 0 {
+		return
+	}
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+	clear := strings.Repeat(" ", width)
+
+	if !a.commandLine.Active() {
+		message, isError := a.commandLine.Message()
+		if message == "" {
+			return
+		}
+		if isError {
+			fg = theme.Error
+		}
+		a.screen.DrawText(0, height-1, clear, fg, bg, goterm.StyleNone)
+		a.screen.DrawText(0, height-1, fitToWidth(message, width), fg, bg, goterm.StyleNone)
+		return
+	}
+
+	// Completion candidates on the row above the line
+	if completions := a.commandLine.Completions(); len(completions) > 1 && height > 1 {
+		a.screen.DrawText(0, height-2, clear, fg, bg, goterm.StyleNone)
+		a.screen.DrawText(0, height-2, fitToWidth(strings.Join(completions, "  "), width), fg, bg, goterm.StyleDim)
+	}
+
+	text := ":" + a.commandLine.Text()
+	a.screen.DrawText(0, height-1, clear, fg, bg, goterm.StyleNone)
+	a.screen.DrawText(0, height-1, fitToWidth(text, width), fg, bg, goterm.StyleNone)
+
+	// Cursor
+	cursor := 1 + a.commandLine.Cursor()
+	if cursor < width {
+		ch := ' '
+		if runes := []rune(text); cursor < len(runes) {
+			ch = runes[cursor]
+		}
+		a.screen.DrawText(cursor, height-1, string(ch), fg, bg, goterm.StyleReverse)
+	}
+}
+
+// fitToWidth cuts s to at most width runes
+func fitToWidth(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:max(width, 0)])
+}
+
 // readKeyboardInput reads keyboard input in a background goroutine
 func (a *App) readKeyboardInput() {
 	// Read from stdin in raw mode (blocking)
@@ -370,10 +827,31 @@ func (a *App) parseKeyInput(buf []byte) KeyEvent {
 	}
 }
 
-// Close performs cleanup and restores terminal state
+// Signal returns the signal that stopped Run, or nil if the user quit
+func (a *App) Signal() os.Signal {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.signal
+}
+
+// Close performs cleanup and restores terminal state. Pending autosaves
+// are flushed first so edits are not lost when the TUI is interrupted,
+// and the session is saved for the next start.
 func (a *App) Close() error {
 	a.cancel()
 
+	if a.unsubscribe != nil {
+		a.unsubscribe()
+	}
+
+	var flushErr error
+	if view, err := a.viewManager.GetView("builder"); err == nil {
+		if builderView, ok := view.(*WorkflowBuilderView); ok {
+			flushErr = builderView.FlushAutosave()
+		}
+	}
+	sessionErr := a.saveSession()
+
 	// Shutdown view manager (cleans up all views)
 	if err := a.viewManager.Shutdown(); err != nil {
 		// Log error but continue cleanup
@@ -381,11 +859,22 @@ func (a *App) Close() error {
 	}
 
 	// Close screen (restores terminal)
-	if err := a.screen.Close(); err != nil {
+	if err := CloseScreen(a.screen); err != nil {
 		return fmt.Errorf("failed to close screen: %w", err)
 	}
 
-	return nil
+	return errors.Join(flushErr, sessionErr)
+}
+
+// CloseScreen clears the screen, shows the cursor and resets attributes
+// before restoring the terminal mode, so an interrupted TUI never leaves
+// the terminal raw or with a hidden cursor.
+func CloseScreen(screen *goterm.Screen) error {
+	if screen == nil {
+		return nil
+	}
+	_, _ = fmt.Fprint(os.Stdout, "\x1b[0m\x1b[2J\x1b[H\x1b[?25h")
+	return screen.Close()
 }
 
 // GetViewManager returns the view manager instance
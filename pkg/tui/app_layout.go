@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+)
+
+// splitScreen shows view in a new pane beside (vertical) or below the
+// current view, replacing the view in the other pane if already split
+func (a *App) splitScreen(direction SplitDirection, name string) error {
+	current := a.viewManager.GetCurrentView()
+	if current == nil {
+		return fmt.Errorf("no view to split")
+	}
+	if name == current.Name() {
+		return fmt.Errorf("%s is already in the focused pane", name)
+	}
+	view, err := a.viewManager.GetView(name)
+	if err != nil {
+		return err
+	}
+
+	if a.layout.IsSplit() && a.layout.Other() != name {
+		a.closeOtherPane()
+	}
+	if a.layout.Other() != name {
+		if err := view.Init(); err != nil {
+			return fmt.Errorf("failed to initialize view %q: %w", name, err)
+		}
+		view.SetActive(true)
+	}
+	a.layout.Split(direction, name)
+	return nil
+}
+
+// focusOtherPane moves the focus, and the keys, to the other pane
+func (a *App) focusOtherPane() error {
+	if !a.layout.IsSplit() {
+		return fmt.Errorf("the screen is not split")
+	}
+	current := a.viewManager.GetCurrentView()
+	if err := a.viewManager.Focus(a.layout.Other()); err != nil {
+		return err
+	}
+	a.layout.SwapFocus(current.Name())
+	return nil
+}
+
+// closeOtherPane gives the whole screen to the focused view
+func (a *App) closeOtherPane() {
+	if !a.layout.IsSplit() {
+		return
+	}
+	if view, err := a.viewManager.GetView(a.layout.Unsplit()); err == nil {
+		_ = view.Cleanup() // Views keep their state; nothing to report
+		view.SetActive(false)
+	}
+}
+
+// otherPaneView returns the view in the pane without the focus, or nil if
+// the screen is not split
+func (a *App) otherPaneView() View {
+	if !a.layout.IsSplit() {
+		return nil
+	}
+	view, err := a.viewManager.GetView(a.layout.Other())
+	if err != nil {
+		return nil
+	}
+	return view
+}
+
+// nextSplitView returns the view a new split shows: the first one, in
+// Tab order, after the current view
+func (a *App) nextSplitView() string {
+	names := a.viewManager.ListViews()
+	sort.Strings(names)
+	current := a.viewManager.GetCurrentView().Name()
+	for i, name := range names {
+		if name == current {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[0]
+}
+
+// handlePaneKey runs the pane command typed after Ctrl-w
+func (a *App) handlePaneKey(event KeyEvent) error {
+	a.layout.pending = false
+	if event.IsSpecial && event.Special == "Escape" {
+		return nil
+	}
+	if event.Ctrl && event.Key == 'w' {
+		event = KeyEvent{Key: 'w'} // Ctrl-w Ctrl-w is Ctrl-w w, as in vim
+	}
+
+	switch event.Key {
+	case 'v':
+		return a.splitScreen(SplitVertical, a.nextSplitView())
+	case 's':
+		return a.splitScreen(SplitHorizontal, a.nextSplitView())
+	}
+	if !a.layout.IsSplit() {
+		return fmt.Errorf("the screen is not split: Ctrl-w v or :vsplit <view> splits it")
+	}
+
+	switch event.Key {
+	case 'w':
+		return a.focusOtherPane()
+	case 'h', 'k':
+		if !a.layout.focusFirst {
+			return a.focusOtherPane()
+		}
+	case 'l', 'j':
+		if a.layout.focusFirst {
+			return a.focusOtherPane()
+		}
+	case '>', '+':
+		a.layout.Resize(splitResizeStep)
+	case '<', '-':
+		a.layout.Resize(-splitResizeStep)
+	case '=':
+		a.layout.Equalize()
+	case 'x':
+		a.layout.SwapPanes()
+	case 'o':
+		a.closeOtherPane()
+	case 'c', 'q':
+		// Close the focused pane: the other view takes the screen
+		if err := a.focusOtherPane(); err != nil {
+			return err
+		}
+		a.closeOtherPane()
+	default:
+		return fmt.Errorf("unknown pane command: Ctrl-w %s", keyEventToString(event))
+	}
+	return nil
+}
+
+// followSwitch keeps a view out of both panes: switching the focused pane
+// to the view in the other pane puts the view left behind there instead.
+// It runs as a view switch hook, with the view manager locked.
+func (a *App) followSwitch(from, to View) error {
+	if a.layout.IsSplit() && to != nil && to.Name() == a.layout.Other() {
+		if from == nil {
+			a.layout.Unsplit() // The view manager is locked: no cleanup
+			return nil
+		}
+		a.layout.other = from.Name()
+	}
+	return nil
+}
+
+// registerLayoutCommands registers :split, :vsplit and :only
+func (a *App) registerLayoutCommands() error {
+	viewNames := func(args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		names := a.viewManager.ListViews()
+		sort.Strings(names)
+		return names
+	}
+	commands := []Command{
+		{
+			Name:        "vsplit",
+			Aliases:     []string{"vs"},
+			Usage:       "[view]",
+			Description: "Show a view beside the current one",
+			MaxArgs:     1,
+			Run: func(args []string) error {
+				return a.splitScreen(SplitVertical, a.splitArg(args))
+			},
+			Complete: viewNames,
+		},
+		{
+			Name:        "split",
+			Aliases:     []string{"sp"},
+			Usage:       "[view]",
+			Description: "Show a view below the current one",
+			MaxArgs:     1,
+			Run: func(args []string) error {
+				return a.splitScreen(SplitHorizontal, a.splitArg(args))
+			},
+			Complete: viewNames,
+		},
+		{
+			Name:        "only",
+			Description: "Close the other pane",
+			Run: func(args []string) error {
+				a.closeOtherPane()
+				return nil
+			},
+		},
+	}
+	for _, cmd := range commands {
+		if err := a.commands.Register(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitArg returns the view named by a split command, or the next view
+func (a *App) splitArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return a.nextSplitView()
+}
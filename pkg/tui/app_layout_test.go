@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dshills/goterm"
+)
+
+// newLayoutTestApp creates an app with the explorer and monitor views,
+// showing the explorer
+func newLayoutTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	vm := NewViewManager()
+	for _, view := range []View{NewWorkflowExplorerView(), NewExecutionMonitorView()} {
+		if err := vm.RegisterView(view); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commands := NewCommandRegistry()
+	app := &App{
+		viewManager: vm,
+		commands:    commands,
+		commandLine: NewCommandLine(commands),
+		layout:      NewLayout(),
+	}
+	vm.AddSwitchHook(app.followSwitch)
+	if err := app.registerLayoutCommands(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Initialize("explorer"); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestApp_SplitScreen(t *testing.T) {
+	app := newLayoutTestApp(t)
+
+	if err := app.commands.Execute("vsplit monitor"); err != nil {
+		t.Fatalf(":vsplit failed: %v", err)
+	}
+	if !app.layout.IsSplit() || app.layout.Other() != "monitor" {
+		t.Fatalf("layout = %+v, want monitor in the other pane", app.layout)
+	}
+
+	screen := goterm.NewScreen(81, 10)
+	if err := app.layout.Render(screen, app.viewManager.GetCurrentView(), app.otherPaneView()); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	top := screenRow(screen, 0)
+	if !strings.HasPrefix(top, "Workflow Explorer") || !strings.Contains(top, "│Execution Monitor") {
+		t.Errorf("top row = %q, want both views split by a divider", top)
+	}
+	if cell := screen.GetCell(41, 0); cell.Style&goterm.StyleDim == 0 {
+		t.Error("expected the pane without focus dimmed")
+	}
+
+	// Keys go to the focused pane's view
+	if err := app.handlePaneKey(KeyEvent{Key: 'l'}); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.viewManager.GetCurrentView().Name(); got != "monitor" {
+		t.Errorf("focused %q, want monitor", got)
+	}
+	if app.layout.Other() != "explorer" || app.layout.focusFirst {
+		t.Errorf("layout = %+v after moving the focus", app.layout)
+	}
+
+	// Resizing grows the focused pane, within limits
+	for i := 0; i < 10; i++ {
+		if err := app.handlePaneKey(KeyEvent{Key: '>'}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if app.layout.ratio != minSplitRatio {
+		t.Errorf("ratio = %d, want the first pane at its minimum", app.layout.ratio)
+	}
+	first, second := app.layout.Panes(81, 10)
+	if first.Width != 16 || second.X != 17 || second.Width != 64 {
+		t.Errorf("panes = %+v %+v", first, second)
+	}
+
+	if err := app.handlePaneKey(KeyEvent{Key: 'o'}); err != nil {
+		t.Fatal(err)
+	}
+	if app.layout.IsSplit() || app.viewManager.GetCurrentView().Name() != "monitor" {
+		t.Error("expected Ctrl-w o to leave the focused view alone on screen")
+	}
+}
+
+func TestApp_SplitScreenErrors(t *testing.T) {
+	app := newLayoutTestApp(t)
+
+	if err := app.handlePaneKey(KeyEvent{Key: 'w'}); err == nil || !strings.Contains(err.Error(), "not split") {
+		t.Errorf("Ctrl-w w error = %v", err)
+	}
+	if err := app.commands.Execute("split explorer"); err == nil || !strings.Contains(err.Error(), "already in the focused pane") {
+		t.Errorf(":split explorer error = %v", err)
+	}
+	if err := app.commands.Execute("vsplit nowhere"); err == nil {
+		t.Error("expected an error splitting with an unknown view")
+	}
+	if err := app.handlePaneKey(KeyEvent{Key: 's'}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.handlePaneKey(KeyEvent{Key: '!'}); err == nil || !strings.Contains(err.Error(), "unknown pane command") {
+		t.Errorf("Ctrl-w ! error = %v", err)
+	}
+}
+
+func TestApp_SwitchToOtherPaneView(t *testing.T) {
+	app := newLayoutTestApp(t)
+	if err := app.commands.Execute("split monitor"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Switching the focused pane to the other pane's view swaps them
+	if err := app.viewManager.SwitchTo("monitor"); err != nil {
+		t.Fatal(err)
+	}
+	if app.layout.Other() != "explorer" {
+		t.Errorf("other pane shows %q, want explorer", app.layout.Other())
+	}
+}
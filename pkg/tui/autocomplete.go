@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxSuggestions caps the completions offered for a word
+const maxSuggestions = 8
+
+// Autocomplete suggests completions for the word being typed into a field.
+// Candidates are ranked with FuzzyMatch; Tab cycles through the matches,
+// replacing the word with each in turn.
+type Autocomplete struct {
+	matches  []string // Candidates matching the word, best first
+	selected int      // Match last inserted, -1 before the first Tab
+}
+
+// Update finds the candidates matching word. An empty word matches every
+// candidate, in order; a candidate equal to word is not offered.
+func (a *Autocomplete) Update(word string, candidates []string) {
+	a.Reset()
+
+	type scored struct {
+		text  string
+		score int
+	}
+	var found []scored
+	for _, candidate := range candidates {
+		if candidate == word {
+			continue
+		}
+		score, _, ok := FuzzyMatch(word, candidate)
+		if !ok {
+			continue
+		}
+		found = append(found, scored{candidate, score})
+	}
+	// Stable, so candidates that score the same keep their order
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+
+	for i := 0; i < len(found) && i < maxSuggestions; i++ {
+		a.matches = append(a.matches, found[i].text)
+	}
+}
+
+// Matches returns the completions for the word, best first
+func (a *Autocomplete) Matches() []string {
+	return a.matches
+}
+
+// Selected returns the index of the match last inserted, or -1
+func (a *Autocomplete) Selected() int {
+	return a.selected
+}
+
+// Next selects the next (or previous) match and returns it. It returns
+// false if there is nothing to complete.
+func (a *Autocomplete) Next(backward bool) (string, bool) {
+	n := len(a.matches)
+	if n == 0 {
+		return "", false
+	}
+	switch {
+	case a.selected < 0 && backward:
+		a.selected = n - 1
+	case a.selected < 0:
+		a.selected = 0
+	case backward:
+		a.selected = (a.selected - 1 + n) % n
+	default:
+		a.selected = (a.selected + 1) % n
+	}
+	return a.matches[a.selected], true
+}
+
+// Reset forgets the matches
+func (a *Autocomplete) Reset() {
+	a.matches = nil
+	a.selected = -1
+}
+
+// jsonPathStart returns where the JSONPath ending value starts, such as
+// $.content[*].te, or -1 if value does not end in one
+func jsonPathStart(value string) int {
+	start := strings.LastIndexAny(value, " \t(),") + 1
+	word := value[start:]
+	if !strings.HasPrefix(word, "$") || strings.HasPrefix(word, "${") {
+		return -1
+	}
+	return start
+}
+
+// identifierStart returns where the identifier ending value starts, or -1
+// if value ends in a field access such as user.na, whose fields are not
+// known before the workflow runs
+func identifierStart(value string) int {
+	runes := []rune(value)
+	start := len(runes)
+	for start > 0 && (unicode.IsLetter(runes[start-1]) || unicode.IsDigit(runes[start-1]) || runes[start-1] == '_') {
+		start--
+	}
+	if start > 0 && runes[start-1] == '.' {
+		return -1
+	}
+	return len(string(runes[:start]))
+}
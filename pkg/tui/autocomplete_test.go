@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAutocomplete(t *testing.T) {
+	var a Autocomplete
+	a.Update("in", []string{"input", "count", "items_in", "in"})
+	if got := a.Matches(); !reflect.DeepEqual(got, []string{"input", "items_in"}) {
+		t.Fatalf("Matches() = %q", got)
+	}
+	if a.Selected() != -1 {
+		t.Errorf("Selected() = %d before Tab, want -1", a.Selected())
+	}
+
+	for _, want := range []string{"input", "items_in", "input"} {
+		if got, ok := a.Next(false); !ok || got != want {
+			t.Errorf("Next(false) = %q, %v, want %q", got, ok, want)
+		}
+	}
+	if got, _ := a.Next(true); got != "items_in" {
+		t.Errorf("Next(true) = %q, want items_in", got)
+	}
+
+	a.Update("", []string{"b", "a"})
+	if got := a.Matches(); !reflect.DeepEqual(got, []string{"b", "a"}) {
+		t.Errorf("empty word matches %q, want every candidate in order", got)
+	}
+	a.Update("zz", []string{"b", "a"})
+	if _, ok := a.Next(false); ok {
+		t.Error("Next() completed without matches")
+	}
+}
+
+func TestIdentifierStart(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", 0},
+		{"tot", 0},
+		{"total + cou", 8},
+		{"len(items)", 10},
+		{"${user_na", 2},
+		{"user.na", -1},
+	}
+	for _, tt := range tests {
+		if got := identifierStart(tt.value); got != tt.want {
+			t.Errorf("identifierStart(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
 )
 
 // BenchmarkCanvasNodeOperations measures node add/remove performance
@@ -71,6 +72,20 @@ func BenchmarkCanvasRenderingWithZoom(b *testing.B) {
 	}
 }
 
+// BenchmarkCanvasRenderLargeGraph measures rendering a 500-node graph
+// Target: well under the 16ms frame budget, since only visible cells are drawn
+func BenchmarkCanvasRenderLargeGraph(b *testing.B) {
+	canvas := newGridCanvas(b, 25, 20)
+	canvas.ViewportX, canvas.ViewportY = 200, 100
+	screen := goterm.NewScreen(200, 60)
+	canvas.Width, canvas.Height = 200, 60
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = canvas.RenderToScreen(screen)
+	}
+}
+
 // BenchmarkAutoLayout measures auto-layout algorithm performance with 50 nodes
 // Target: < 200ms for 50 nodes
 func BenchmarkAutoLayout(b *testing.B) {
@@ -26,6 +26,17 @@ type Canvas struct {
 	edges []*canvasEdge
 	// selectedID is the currently selected node ID
 	selectedID string
+	// minimapHidden turns off the minimap, which is otherwise drawn whenever
+	// the graph doesn't fit in the view
+	minimapHidden bool
+	// groups are the node groups drawn as frames, or as single boxes while
+	// collapsed
+	groups []*canvasGroup
+	// notes are drawn beside their nodes as sticky notes, keyed by node ID
+	notes map[string]string
+	// searchMatches are the IDs of the nodes matching the builder's search,
+	// highlighted when drawn
+	searchMatches map[string]bool
 }
 
 // canvasNode wraps a domain Node with rendering state
@@ -106,13 +117,22 @@ func (c *Canvas) RemoveNode(nodeID string) error {
 
 	// Remove all edges connected to this node
 	newEdges := make([]*canvasEdge, 0, len(c.edges))
+	neighbours := make([]string, 0)
 	for _, edge := range c.edges {
-		if edge.edge.FromNodeID != nodeID && edge.edge.ToNodeID != nodeID {
+		switch nodeID {
+		case edge.edge.FromNodeID:
+			neighbours = append(neighbours, edge.edge.ToNodeID)
+		case edge.edge.ToNodeID:
+			neighbours = append(neighbours, edge.edge.FromNodeID)
+		default:
 			newEdges = append(newEdges, edge)
 		}
 	}
 	c.edges = newEdges
 
+	// Neighbours' ports spread over fewer edges now
+	c.rerouteNodeEdges(neighbours...)
+
 	// Clear selection if removed node was selected
 	if c.selectedID == nodeID {
 		c.selectedID = ""
@@ -139,11 +159,7 @@ func (c *Canvas) MoveNode(nodeID string, newPos Position) error {
 	cNode.position = newPos
 
 	// Re-route all edges connected to this node
-	for _, edge := range c.edges {
-		if edge.edge.FromNodeID == nodeID || edge.edge.ToNodeID == nodeID {
-			c.routeEdge(edge)
-		}
-	}
+	c.rerouteNodeEdges(nodeID)
 
 	return nil
 }
@@ -179,10 +195,11 @@ func (c *Canvas) AddEdge(edge *workflow.Edge) error {
 		selected:      false,
 	}
 
-	// Calculate routing
-	c.routeEdge(cEdge)
-
 	c.edges = append(c.edges, cEdge)
+
+	// Calculate routing, moving the other edges at both ends off the ports
+	// the new edge takes
+	c.rerouteNodeEdges(edge.FromNodeID, edge.ToNodeID)
 	return nil
 }
 
@@ -204,6 +221,7 @@ func (c *Canvas) RemoveEdge(fromID, toID string) error {
 	}
 
 	c.edges = newEdges
+	c.rerouteNodeEdges(fromID, toID)
 	return nil
 }
 
@@ -280,14 +298,14 @@ func (c *Canvas) calculateNodeSize(node workflow.Node) (width int, height int) {
 	case "end":
 		width = 16
 		height = 3
-	case "mcp_tool":
+	case "mcp_tool", "batch_tool":
 		// Size based on tool name length
 		width = 20
 		height = 5
 	case "transform":
 		width = 20
 		height = 5
-	case "condition":
+	case "condition", "switch":
 		width = 18
 		height = 4
 	case "loop":
@@ -296,50 +314,76 @@ func (c *Canvas) calculateNodeSize(node workflow.Node) (width int, height int) {
 	case "parallel":
 		width = 20
 		height = 4
+	case "try", "catch", "delay", "approval":
+		width = 18
+		height = 4
 	}
 
 	return width, height
 }
 
+// cellScreen is the part of goterm.Screen the canvas and the builder's
+// panels draw on
+type cellScreen interface {
+	SetCell(x, y int, cell goterm.Cell)
+	Size() (int, int)
+}
+
 // RenderToScreen renders the canvas to the terminal screen
 // This is the main rendering orchestrator that:
 // 1. Applies viewport transformation
-// 2. Renders all edges
-// 3. Renders all nodes
-// 4. Applies zoom scaling
+// 2. Renders the edges and nodes inside the view
+// 3. Renders the minimap when the graph doesn't fit
+//
+// Only what is visible is computed, so the cost of a frame depends on the
+// size of the view rather than the size of the graph.
 func (c *Canvas) RenderToScreen(screen interface{}) error {
-	// Import goterm dynamically to avoid circular dependency issues
-	// For now, we'll use a simpler approach with direct screen access
-
-	// Type assert to goterm.Screen
-	type Screen interface {
-		SetCell(x, y int, cell interface{})
-		Size() (int, int)
-	}
-
-	scr, ok := screen.(Screen)
+	scr, ok := screen.(cellScreen)
 	if !ok {
 		return fmt.Errorf("invalid screen type")
 	}
 
-	// Get screen dimensions
+	// The canvas area: the canvas size, clipped to the screen
 	screenWidth, screenHeight := scr.Size()
+	if c.Width > 0 {
+		screenWidth = min(screenWidth, c.Width)
+	}
+	if c.Height > 0 {
+		screenHeight = min(screenHeight, c.Height)
+	}
+	view := NewBoundingBox(c.ViewportX, c.ViewportY, screenWidth, screenHeight)
+
+	// Group frames go behind everything else
+	c.renderGroupFrames(scr, screenWidth, screenHeight)
 
 	// Render edges first (so they appear behind nodes)
-	for _, edge := range c.edges {
-		c.renderEdge(scr, edge, screenWidth, screenHeight)
-	}
+	c.renderEdges(scr, screenWidth, screenHeight)
+
+	// Notes go over the edges, under the nodes
+	c.renderNotes(scr, screenWidth, screenHeight)
 
 	// Render nodes
 	for _, node := range c.nodes {
-		c.renderNode(scr, node, screenWidth, screenHeight)
+		if nodeBounds(node).Intersects(view) {
+			c.renderNode(scr, node, screenWidth, screenHeight)
+		}
 	}
 
+	// Arrowheads and labels go on top, so they show where an edge meets a node
+	c.renderEdgeDecorations(scr, screenWidth, screenHeight)
+
+	c.renderMinimap(scr, screenWidth, screenHeight)
+
 	return nil
 }
 
+// nodeBounds returns the box a node covers
+func nodeBounds(node *canvasNode) BoundingBox {
+	return NewBoundingBox(node.position.X, node.position.Y, node.width, node.height)
+}
+
 // renderNode draws a single node on the screen
-func (c *Canvas) renderNode(screen interface{}, node *canvasNode, screenWidth, screenHeight int) {
+func (c *Canvas) renderNode(scr cellScreen, node *canvasNode, screenWidth, screenHeight int) {
 	// Convert logical coordinates to screen coordinates
 	screenX := node.position.X - c.ViewportX
 	screenY := node.position.Y - c.ViewportY
@@ -359,12 +403,6 @@ func (c *Canvas) renderNode(screen interface{}, node *canvasNode, screenWidth, s
 	// Get colors based on node state
 	fg, bg, style := c.getNodeColors(node)
 
-	// Type assert screen to access SetCell
-	type Screen interface {
-		SetCell(x, y int, cell interface{})
-	}
-	scr := screen.(Screen)
-
 	// Draw node box using Unicode box-drawing characters
 	// Top border
 	if screenY >= 0 && screenY < screenHeight {
@@ -405,6 +443,9 @@ func (c *Canvas) renderNode(screen interface{}, node *canvasNode, screenWidth, s
 			case 2:
 				// Second content line: node ID (truncated if needed)
 				content = nodeID
+				if group, ok := node.node.(*groupNode); ok {
+					content = group.label()
+				}
 				if len(content) > node.width-4 {
 					content = content[:node.width-7] + "..."
 				}
@@ -460,159 +501,198 @@ func (c *Canvas) renderNode(screen interface{}, node *canvasNode, screenWidth, s
 	}
 }
 
-// renderEdge draws a single edge on the screen
-func (c *Canvas) renderEdge(screen interface{}, edge *canvasEdge, screenWidth, screenHeight int) {
-	if len(edge.routingPoints) < 2 {
-		return
-	}
-
-	// Type assert screen
-	type Screen interface {
-		SetCell(x, y int, cell interface{})
-	}
-	scr := screen.(Screen)
+// Line directions leaving a cell, combined so that corners, tees and
+// crossings between edges get the right box-drawing character
+const (
+	lineUp uint8 = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
 
-	// Get edge colors
-	fg := goterm.ColorRGB(170, 170, 170) // Gray color for edges
-	bg := goterm.ColorRGB(0, 0, 0)       // Black background
-	style := goterm.StyleNone            // No special style
+// lineRunes maps the directions leaving a cell to its character
+var lineRunes = map[uint8]rune{
+	lineUp:                                   '│',
+	lineDown:                                 '│',
+	lineUp | lineDown:                        '│',
+	lineLeft:                                 '─',
+	lineRight:                                '─',
+	lineLeft | lineRight:                     '─',
+	lineDown | lineRight:                     '┌',
+	lineDown | lineLeft:                      '┐',
+	lineUp | lineRight:                       '└',
+	lineUp | lineLeft:                        '┘',
+	lineUp | lineDown | lineRight:            '├',
+	lineUp | lineDown | lineLeft:             '┤',
+	lineLeft | lineRight | lineDown:          '┬',
+	lineLeft | lineRight | lineUp:            '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+}
 
-	if edge.selected {
-		fg = goterm.ColorRGB(0, 255, 255) // Cyan for selected edges
+// lineDirection returns the direction bit for a one-cell step
+func lineDirection(dx, dy int) uint8 {
+	switch {
+	case dy < 0:
+		return lineUp
+	case dy > 0:
+		return lineDown
+	case dx < 0:
+		return lineLeft
+	default:
+		return lineRight
 	}
+}
 
-	// Draw lines between consecutive routing points
-	for i := 0; i < len(edge.routingPoints)-1; i++ {
-		p1 := edge.routingPoints[i]
-		p2 := edge.routingPoints[i+1]
-
-		// Convert to screen coordinates
-		screenX1 := p1.X - c.ViewportX
-		screenY1 := p1.Y - c.ViewportY
-		screenX2 := p2.X - c.ViewportX
-		screenY2 := p2.Y - c.ViewportY
+// renderEdges draws the lines of the edges inside the view. All edges are
+// drawn into one grid of the view's cells first so that where they meet or
+// cross the joint is drawn properly; segments are clipped to the view, so
+// long edges cost no more than short ones.
+func (c *Canvas) renderEdges(scr cellScreen, screenWidth, screenHeight int) {
+	if screenWidth <= 0 || screenHeight <= 0 {
+		return
+	}
+	lines := make([]uint8, screenWidth*screenHeight)
+	selected := make([]bool, screenWidth*screenHeight)
+	onError := make([]bool, screenWidth*screenHeight)
+
+	// Cells one past the view still count, so a line leaving the view is
+	// drawn running off the edge rather than ending short of it
+	minX, minY := c.ViewportX-1, c.ViewportY-1
+	maxX, maxY := c.ViewportX+screenWidth, c.ViewportY+screenHeight
+	mark := func(p Position, dir uint8, edge *canvasEdge) {
+		x, y := p.X-c.ViewportX, p.Y-c.ViewportY
+		if x < 0 || x >= screenWidth || y < 0 || y >= screenHeight {
+			return
+		}
+		lines[y*screenWidth+x] |= dir
+		selected[y*screenWidth+x] = selected[y*screenWidth+x] || edge.selected
+		onError[y*screenWidth+x] = onError[y*screenWidth+x] || edge.edge.OnError
+	}
 
-		// Draw line segment
-		if screenX1 == screenX2 {
-			// Vertical line
-			minY := screenY1
-			maxY := screenY2
-			if minY > maxY {
-				minY, maxY = maxY, minY
-			}
-			for y := minY; y <= maxY; y++ {
-				if screenX1 >= 0 && screenX1 < screenWidth && y >= 0 && y < screenHeight {
-					char := '│'
-					if i == len(edge.routingPoints)-2 && y == maxY {
-						char = '▼' // Arrow head at end
-					}
-					cell := goterm.NewCell(char, fg, bg, style)
-					scr.SetCell(screenX1, y, cell)
-				}
-			}
-		} else if screenY1 == screenY2 {
-			// Horizontal line
-			minX := screenX1
-			maxX := screenX2
-			if minX > maxX {
-				minX, maxX = maxX, minX
+	for _, edge := range c.edges {
+		points := edge.routingPoints
+		for i := 0; i+1 < len(points); i++ {
+			a, b := points[i], points[i+1]
+			if max(a.X, b.X) < minX || min(a.X, b.X) > maxX || max(a.Y, b.Y) < minY || min(a.Y, b.Y) > maxY {
+				continue
 			}
-			for x := minX; x <= maxX; x++ {
-				if x >= 0 && x < screenWidth && screenY1 >= 0 && screenY1 < screenHeight {
-					char := '─'
-					cell := goterm.NewCell(char, fg, bg, style)
-					scr.SetCell(x, screenY1, cell)
-				}
+			dx, dy := intSign(b.X-a.X), intSign(b.Y-a.Y)
+
+			// Clip the segment to the view before walking it
+			start := Position{X: clamp(a.X, minX, maxX), Y: clamp(a.Y, minY, maxY)}
+			end := Position{X: clamp(b.X, minX, maxX), Y: clamp(b.Y, minY, maxY)}
+			for p := start; p != end; p = (Position{X: p.X + dx, Y: p.Y + dy}) {
+				next := Position{X: p.X + dx, Y: p.Y + dy}
+				mark(p, lineDirection(dx, dy), edge)
+				mark(next, lineDirection(-dx, -dy), edge)
 			}
 		}
+	}
 
-		// Draw corner characters at routing points
-		if i > 0 && i < len(edge.routingPoints)-1 {
-			p0 := edge.routingPoints[i-1]
+	bg := CurrentTheme().CanvasBg
+	for i, dirs := range lines {
+		if dirs == 0 {
+			continue
+		}
+		cell := goterm.NewCell(lineRunes[dirs], c.edgeColor(selected[i], onError[i]), bg, goterm.StyleNone)
+		scr.SetCell(i%screenWidth, i/screenWidth, cell)
+	}
+}
 
-			screenX0 := p0.X - c.ViewportX
-			screenY0 := p0.Y - c.ViewportY
+// clamp limits v to the range lo to hi
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
 
-			if screenX1 >= 0 && screenX1 < screenWidth && screenY1 >= 0 && screenY1 < screenHeight {
-				// Determine corner character based on direction
-				corner := '┼' // Default intersection
+// renderEdgeDecorations draws each edge's label and the arrowhead where it
+// enters its target
+func (c *Canvas) renderEdgeDecorations(scr cellScreen, screenWidth, screenHeight int) {
+	theme := CurrentTheme()
+	bg := theme.CanvasBg
+	labelFg := theme.EdgeLabel // Labels and conditions
 
-				// Horizontal to vertical or vice versa
-				if screenX0 == screenX1 && screenY1 == screenY2 {
-					// Coming from top/bottom, going right/left
-					if screenX2 > screenX1 {
-						corner = '└' // Coming from above, going right
-					} else {
-						corner = '┘' // Coming from above, going left
-					}
-					if screenY0 > screenY1 {
-						if screenX2 > screenX1 {
-							corner = '┌' // Coming from below, going right
-						} else {
-							corner = '┐' // Coming from below, going left
-						}
-					}
-				} else if screenY0 == screenY1 && screenX1 == screenX2 {
-					// Coming from left/right, going up/down
-					if screenY2 > screenY1 {
-						if screenX0 < screenX1 {
-							corner = '┐' // Coming from left, going down
-						} else {
-							corner = '┌' // Coming from right, going down
-						}
-					} else {
-						if screenX0 < screenX1 {
-							corner = '┘' // Coming from left, going up
-						} else {
-							corner = '└' // Coming from right, going up
-						}
-					}
-				}
+	visible := func(p Position) bool {
+		x, y := p.X-c.ViewportX, p.Y-c.ViewportY
+		return x >= 0 && x < screenWidth && y >= 0 && y < screenHeight
+	}
+	set := func(p Position, ch rune, fg goterm.Color) {
+		scr.SetCell(p.X-c.ViewportX, p.Y-c.ViewportY, goterm.NewCell(ch, fg, bg, goterm.StyleNone))
+	}
+
+	for _, edge := range c.edges {
+		points := edge.routingPoints
+		if len(points) < 2 {
+			continue
+		}
 
-				cell := goterm.NewCell(corner, fg, bg, style)
-				scr.SetCell(screenX1, screenY1, cell)
+		fg := labelFg
+		if edge.edge.OnError {
+			fg = theme.EdgeError
+		}
+		for i, ch := range []rune(edge.label) {
+			p := Position{X: edge.labelPos.X + i, Y: edge.labelPos.Y}
+			if visible(p) && !c.coveredByNode(p) {
+				set(p, ch, fg)
 			}
 		}
+
+		// The arrowhead sits on the target's border, pointing into it
+		from, to := points[len(points)-2], points[len(points)-1]
+		if from == to || !visible(to) {
+			continue
+		}
+		set(to, []rune(getEdgeDirection(from, to))[0], c.edgeColor(edge.selected, edge.edge.OnError))
+	}
+}
+
+// coveredByNode reports whether a logical position is inside any node's box
+func (c *Canvas) coveredByNode(p Position) bool {
+	for _, n := range c.nodes {
+		if segmentHitsNode(p, p, n) {
+			return true
+		}
 	}
+	return false
+}
+
+// edgeColor returns the color of an edge's line. Selection wins over the
+// error edge color.
+func (c *Canvas) edgeColor(selected, onError bool) goterm.Color {
+	if selected {
+		return CurrentTheme().EdgeSelected
+	}
+	if onError {
+		return CurrentTheme().EdgeError
+	}
+	return CurrentTheme().Edge
 }
 
 // getNodeColors returns the foreground, background, and style for a node
 func (c *Canvas) getNodeColors(node *canvasNode) (fg goterm.Color, bg goterm.Color, style goterm.Style) {
-	// Default colors
-	fg = goterm.ColorRGB(220, 220, 220) // Light gray text
-	bg = goterm.ColorRGB(0, 0, 0)       // Black background
-	style = goterm.StyleNone            // No special style
+	theme := CurrentTheme()
 
 	// Color by node type
-	switch node.node.Type() {
-	case "start":
-		fg = goterm.ColorRGB(0, 255, 0) // Green
-	case "end":
-		fg = goterm.ColorRGB(255, 0, 0) // Red
-	case "mcp_tool":
-		fg = goterm.ColorRGB(0, 170, 255) // Light blue
-	case "transform":
-		fg = goterm.ColorRGB(255, 170, 0) // Orange
-	case "condition":
-		fg = goterm.ColorRGB(255, 255, 0) // Yellow
-	case "loop":
-		fg = goterm.ColorRGB(255, 0, 255) // Magenta
-	case "parallel":
-		fg = goterm.ColorRGB(0, 255, 255) // Cyan
+	fg = theme.NodeColor(node.node.Type())
+	bg = theme.CanvasBg
+	style = goterm.StyleNone // No special style
+
+	// Search matches stand out until selected
+	if c.isSearchMatch(node) {
+		bg = theme.SearchMatchBg
 	}
 
 	// Override for selection
 	if node.selected {
-		bg = goterm.ColorRGB(0, 100, 200) // Blue background for selected
-		style = goterm.StyleBold          // Bold
+		bg = theme.NodeSelectedBg
+		style = goterm.StyleBold // Bold
 	}
 
 	// Override for validation status
 	switch node.validationStatus {
 	case "error":
-		fg = goterm.ColorRGB(255, 0, 0) // Red for errors
+		fg = theme.NodeError
 	case "warning":
-		fg = goterm.ColorRGB(255, 170, 0) // Orange for warnings
+		fg = theme.NodeWarning
 	}
 
 	return fg, bg, style
@@ -627,15 +707,56 @@ func (c *Canvas) getNodeTypeIcon(nodeType string) string {
 		return "■ END"
 	case "mcp_tool":
 		return "⚙ MCP Tool"
+	case "batch_tool":
+		return "⚙ Batch Tool"
 	case "transform":
 		return "⟳ Transform"
 	case "condition":
 		return "◆ Condition"
+	case "switch":
+		return "◇ Switch"
 	case "loop":
 		return "↻ Loop"
 	case "parallel":
 		return "⫴ Parallel"
+	case "try":
+		return "⛨ Try"
+	case "catch":
+		return "✚ Catch"
+	case "delay":
+		return "⧗ Delay"
+	case "approval":
+		return "✔ Approval"
+	case "group":
+		return "▸ Group"
 	default:
 		return "? " + nodeType
 	}
 }
+
+// SetSearchMatches sets the nodes highlighted as search matches, by ID
+func (c *Canvas) SetSearchMatches(matches map[string]bool) {
+	c.searchMatches = matches
+}
+
+// isSearchMatch reports whether a node matches the search. A collapsed
+// group matches if a node hidden in it does.
+func (c *Canvas) isSearchMatch(node *canvasNode) bool {
+	if len(c.searchMatches) == 0 {
+		return false
+	}
+	if c.searchMatches[node.node.GetID()] {
+		return true
+	}
+	for _, g := range c.groups {
+		if g.proxy != node {
+			continue
+		}
+		for id := range g.members {
+			if c.searchMatches[id] {
+				return true
+			}
+		}
+	}
+	return false
+}
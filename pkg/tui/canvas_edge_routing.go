@@ -1,23 +1,55 @@
 package tui
 
-import "github.com/dshills/goflow/pkg/workflow"
+import (
+	"sort"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// Routing costs. A path through a node is taken only when nothing else
+// exists; crossings and runs shared with other edges are avoided whenever a
+// short detour will do.
+const (
+	costNodeHit  = 10000
+	costOverlap  = 300
+	costCrossing = 100
+	costBend     = 2
+)
+
+// maxChannelRows limits how many rows are tried for the horizontal run of a
+// downward edge
+const maxChannelRows = 12
+
+// sideClearance is the gap kept between a detour and the nodes it goes around
+const sideClearance = 2
+
+// maxEdgeLabel is the longest edge label drawn before it is truncated
+const maxEdgeLabel = 16
 
 // canvasEdge wraps a domain Edge with routing information
 type canvasEdge struct {
 	// edge is the domain edge
 	edge *workflow.Edge
-	// routingPoints are intermediate waypoints for the edge path
+	// routingPoints are the corners of the orthogonal path, from the source
+	// port to the target port
 	routingPoints []Position
 	// selected indicates visual selection state
 	selected bool
+	// label is the text drawn along the path: the edge label or condition,
+	// or "on error" for an unlabeled error edge
+	label string
+	// labelPos is where the label starts, in logical coordinates
+	labelPos Position
 }
 
 // routeEdge calculates the routing points for an edge using orthogonal routing
 // Algorithm:
-// 1. Start at source node center-bottom
-// 2. End at target node center-top
-// 3. If aligned vertically: straight line
-// 4. Otherwise: horizontal → vertical → horizontal (up to 3 segments)
+// 1. Start at a port on the source node's bottom border
+// 2. End at a port on the target node's top border
+// 3. Try a straight line, Z-shaped paths through each free row between the
+// nodes, and detours down either side of the nodes in the way
+// 4. Keep the cheapest: node hits, then overlaps and crossings with the other
+// edges, then bends and length
 func (c *Canvas) routeEdge(edge *canvasEdge) {
 	fromNode, fromExists := c.nodes[edge.edge.FromNodeID]
 	toNode, toExists := c.nodes[edge.edge.ToNodeID]
@@ -25,66 +57,358 @@ func (c *Canvas) routeEdge(edge *canvasEdge) {
 	if !fromExists || !toExists {
 		// Nodes don't exist yet, skip routing
 		edge.routingPoints = make([]Position, 0)
+		edge.label = ""
 		return
 	}
 
-	// Calculate source and target points
-	// Source: center-bottom of from node
-	sourceX := fromNode.position.X + fromNode.width/2
-	sourceY := fromNode.position.Y + fromNode.height
+	source := Position{X: c.portX(fromNode, edge, true), Y: fromNode.position.Y + fromNode.height}
+	target := Position{X: c.portX(toNode, edge, false), Y: toNode.position.Y}
+
+	var best []Position
+	bestCost := 0
+	for _, candidate := range c.candidateRoutes(source, target, fromNode, toNode) {
+		path := simplifyPath(candidate)
+		cost := c.routeCost(edge, path)
+		if best == nil || cost < bestCost {
+			best, bestCost = path, cost
+		}
+	}
+	edge.routingPoints = best
+	placeEdgeLabel(edge)
+}
+
+// routeEdges reroutes every edge from scratch. Edges are routed in order, so
+// each one steers clear of those already placed.
+func (c *Canvas) routeEdges() {
+	for _, edge := range c.edges {
+		edge.routingPoints = nil
+	}
+	for _, edge := range c.edges {
+		c.routeEdge(edge)
+	}
+}
+
+// rerouteNodeEdges reroutes the edges attached to the given nodes. Ports are
+// shared out among a node's edges, so adding, removing or moving one edge
+// shifts its neighbours too.
+func (c *Canvas) rerouteNodeEdges(nodeIDs ...string) {
+	for _, edge := range c.edges {
+		for _, id := range nodeIDs {
+			if edge.edge.FromNodeID == id || edge.edge.ToNodeID == id {
+				c.routeEdge(edge)
+				break
+			}
+		}
+	}
+}
+
+// portX picks the column where an edge leaves a node's bottom border
+// (outgoing) or enters its top border. A node's edges are spread evenly
+// across the border in the order of the nodes at their other ends, so edges
+// fanning out from one node don't cross each other. A lone edge uses the
+// center.
+func (c *Canvas) portX(node *canvasNode, edge *canvasEdge, outgoing bool) int {
+	nodeID := node.node.GetID()
+	end := func(e *canvasEdge) string {
+		if outgoing {
+			return e.edge.FromNodeID
+		}
+		return e.edge.ToNodeID
+	}
+	otherX := func(e *canvasEdge) int {
+		otherID := e.edge.ToNodeID
+		if !outgoing {
+			otherID = e.edge.FromNodeID
+		}
+		if other, exists := c.nodes[otherID]; exists {
+			return other.position.X + other.width/2
+		}
+		return 0
+	}
+
+	siblings := make([]*canvasEdge, 0, 4)
+	included := false
+	for _, e := range c.edges {
+		if end(e) == nodeID {
+			siblings = append(siblings, e)
+			included = included || e == edge
+		}
+	}
+	if !included {
+		// The edge is being added and isn't on the canvas yet
+		siblings = append(siblings, edge)
+	}
+	if len(siblings) == 1 {
+		return node.position.X + node.width/2
+	}
+
+	sort.SliceStable(siblings, func(i, j int) bool {
+		return otherX(siblings[i]) < otherX(siblings[j])
+	})
+	idx := 0
+	for i, e := range siblings {
+		if e == edge {
+			idx = i
+			break
+		}
+	}
+	return node.position.X + (idx+1)*node.width/(len(siblings)+1)
+}
+
+// candidateRoutes lists the paths worth trying from source to target
+func (c *Canvas) candidateRoutes(source, target Position, fromNode, toNode *canvasNode) [][]Position {
+	routes := make([][]Position, 0, maxChannelRows+3)
+
+	// Rows for the horizontal runs: one below the source, so the edge is
+	// seen leaving downwards, and just above the target
+	topRow, bottomRow := source.Y+1, target.Y-1
+	if target.Y > source.Y {
+		if source.X == target.X {
+			routes = append(routes, []Position{source, target})
+		}
+		if topRow > bottomRow {
+			// No room for a stub between the nodes
+			topRow = source.Y
+		}
+		// Z-shaped: down, across on a free row, down again
+		for _, y := range channelRows(topRow, bottomRow) {
+			routes = append(routes, []Position{
+				source, {X: source.X, Y: y}, {X: target.X, Y: y}, target,
+			})
+		}
+	}
+
+	// Detours down a column clear of everything between the two rows
+	left, right := c.obstacleSpan(min(topRow, bottomRow), max(topRow, bottomRow), fromNode, toNode)
+	for _, x := range []int{right + sideClearance, left - sideClearance} {
+		if x < 0 {
+			continue
+		}
+		routes = append(routes, []Position{
+			source, {X: source.X, Y: topRow}, {X: x, Y: topRow},
+			{X: x, Y: bottomRow}, {X: target.X, Y: bottomRow}, target,
+		})
+	}
+	return routes
+}
+
+// channelRows lists the rows from lo to hi, nearest the middle first, up to
+// maxChannelRows of them
+func channelRows(lo, hi int) []int {
+	if hi < lo {
+		return nil
+	}
+	mid := (lo + hi) / 2
+	rows := make([]int, 0, maxChannelRows)
+	for d := 0; len(rows) < maxChannelRows && (mid-d >= lo || mid+d <= hi); d++ {
+		if mid+d <= hi {
+			rows = append(rows, mid+d)
+		}
+		if d > 0 && mid-d >= lo && len(rows) < maxChannelRows {
+			rows = append(rows, mid-d)
+		}
+	}
+	return rows
+}
+
+// obstacleSpan returns the leftmost and rightmost columns taken by the two
+// end nodes and any node reaching into the rows from top to bottom
+func (c *Canvas) obstacleSpan(top, bottom int, fromNode, toNode *canvasNode) (left, right int) {
+	left = min(fromNode.position.X, toNode.position.X)
+	right = max(fromNode.position.X+fromNode.width-1, toNode.position.X+toNode.width-1)
+	for _, n := range c.nodes {
+		if n.position.Y > bottom || n.position.Y+n.height-1 < top {
+			continue
+		}
+		left = min(left, n.position.X)
+		right = max(right, n.position.X+n.width-1)
+	}
+	return left, right
+}
 
-	// Target: center-top of to node
-	targetX := toNode.position.X + toNode.width/2
-	targetY := toNode.position.Y
+// routeCost scores a path; lower is better
+func (c *Canvas) routeCost(edge *canvasEdge, path []Position) int {
+	cost := (len(path) - 2) * costBend
+	for i := 0; i+1 < len(path); i++ {
+		a, b := path[i], path[i+1]
+		cost += intAbs(b.X-a.X) + intAbs(b.Y-a.Y)
+		if i+2 == len(path) {
+			// The last point sits on the target's border
+			b = stepToward(b, a)
+		}
+		for _, n := range c.nodes {
+			if segmentHitsNode(a, b, n) {
+				cost += costNodeHit
+			}
+		}
+	}
 
-	// Build routing points based on relative positions
-	routingPoints := make([]Position, 0, 4)
+	for _, other := range c.edges {
+		if other == edge || len(other.routingPoints) < 2 {
+			continue
+		}
+		crossings, overlaps := pathIntersections(path, other.routingPoints)
+		cost += crossings*costCrossing + overlaps*costOverlap
+	}
+	return cost
+}
 
-	// Start point
-	routingPoints = append(routingPoints, Position{X: sourceX, Y: sourceY})
+// simplifyPath drops repeated points and points in the middle of a straight
+// run
+func simplifyPath(path []Position) []Position {
+	out := make([]Position, 0, len(path))
+	for _, p := range path {
+		if len(out) > 0 && out[len(out)-1] == p {
+			continue
+		}
+		if len(out) >= 2 {
+			a, b := out[len(out)-2], out[len(out)-1]
+			if (a.X == b.X && b.X == p.X) || (a.Y == b.Y && b.Y == p.Y) {
+				out[len(out)-1] = p
+				continue
+			}
+		}
+		out = append(out, p)
+	}
+	if len(out) == 1 {
+		// Keep both ends of a zero-length edge
+		out = append(out, out[0])
+	}
+	return out
+}
 
-	// Case 1: Target is directly below source (vertical alignment)
-	// Just draw a straight line
-	if sourceX == targetX {
-		// Straight vertical line - no intermediate points needed
-	} else if targetY > sourceY {
-		// Case 2: Target is below and to the side
-		// Route: horizontal → vertical → horizontal
-		midY := (sourceY + targetY) / 2
+// stepToward moves p one cell toward q along their shared row or column
+func stepToward(p, q Position) Position {
+	return Position{X: p.X + intSign(q.X-p.X), Y: p.Y + intSign(q.Y-p.Y)}
+}
+
+// segmentHitsNode reports whether the segment from a to b passes through
+// any cell of a node's box
+func segmentHitsNode(a, b Position, n *canvasNode) bool {
+	return min(a.X, b.X) <= n.position.X+n.width-1 && max(a.X, b.X) >= n.position.X &&
+		min(a.Y, b.Y) <= n.position.Y+n.height-1 && max(a.Y, b.Y) >= n.position.Y
+}
+
+// pathIntersections counts where two paths cross and how many of their
+// segments run along each other. Meeting at a shared end point, as edges
+// into the same node may, doesn't count.
+func pathIntersections(p, q []Position) (crossings, overlaps int) {
+	shared := func(pt Position) bool {
+		return (pt == p[0] || pt == p[len(p)-1]) && (pt == q[0] || pt == q[len(q)-1])
+	}
+	for i := 0; i+1 < len(p); i++ {
+		a1, a2 := p[i], p[i+1]
+		for j := 0; j+1 < len(q); j++ {
+			b1, b2 := q[j], q[j+1]
+			aVertical, bVertical := a1.X == a2.X, b1.X == b2.X
+			switch {
+			case aVertical && !bVertical:
+				pt := Position{X: a1.X, Y: b1.Y}
+				if between(pt.Y, a1.Y, a2.Y) && between(pt.X, b1.X, b2.X) && !shared(pt) {
+					crossings++
+				}
+			case !aVertical && bVertical:
+				pt := Position{X: b1.X, Y: a1.Y}
+				if between(pt.X, a1.X, a2.X) && between(pt.Y, b1.Y, b2.Y) && !shared(pt) {
+					crossings++
+				}
+			case aVertical && bVertical && a1.X == b1.X:
+				if overlapLength(a1.Y, a2.Y, b1.Y, b2.Y) > 0 {
+					overlaps++
+				}
+			case !aVertical && !bVertical && a1.Y == b1.Y:
+				if overlapLength(a1.X, a2.X, b1.X, b2.X) > 0 {
+					overlaps++
+				}
+			}
+		}
+	}
+	return crossings, overlaps
+}
 
-		// Horizontal segment from source
-		routingPoints = append(routingPoints, Position{X: sourceX, Y: midY})
-		// Vertical segment to target level
-		routingPoints = append(routingPoints, Position{X: targetX, Y: midY})
-	} else {
-		// Case 3: Target is above source (backward edge)
-		// Route around: down → horizontal → down → horizontal
-		gapY := 2 // Gap below source node
-		routingPoints = append(routingPoints, Position{X: sourceX, Y: sourceY + gapY})
+// placeEdgeLabel chooses where an edge's label goes: centered on the longest
+// horizontal run it fits on, otherwise beside the middle of the longest
+// vertical run
+func placeEdgeLabel(edge *canvasEdge) {
+	text := edge.edge.Label
+	if text == "" {
+		text = edge.edge.Condition
+	}
+	if text == "" && edge.edge.OnError {
+		text = "on error"
+	}
+	edge.label = truncateEdgeLabel(text)
+	if edge.label == "" || len(edge.routingPoints) < 2 {
+		return
+	}
+	width := len([]rune(edge.label))
 
-		// Horizontal segment
-		if targetX > sourceX {
-			// Target is to the right
-			routingPoints = append(routingPoints, Position{X: targetX, Y: sourceY + gapY})
-		} else {
-			// Target is to the left
-			routingPoints = append(routingPoints, Position{X: targetX, Y: sourceY + gapY})
+	bestH, bestV := -1, -1
+	for i := 0; i+1 < len(edge.routingPoints); i++ {
+		a, b := edge.routingPoints[i], edge.routingPoints[i+1]
+		if a.Y == b.Y && a.X != b.X {
+			if l := intAbs(b.X - a.X); l >= width+2 && (bestH < 0 || l > segmentLength(edge.routingPoints, bestH)) {
+				bestH = i
+			}
+		} else if bestV < 0 || intAbs(b.Y-a.Y) > segmentLength(edge.routingPoints, bestV) {
+			bestV = i
 		}
+	}
 
-		// Vertical segment down to target
-		routingPoints = append(routingPoints, Position{X: targetX, Y: targetY})
+	if bestH >= 0 {
+		a, b := edge.routingPoints[bestH], edge.routingPoints[bestH+1]
+		left := min(a.X, b.X)
+		edge.labelPos = Position{X: left + (intAbs(b.X-a.X)-width)/2 + 1, Y: a.Y}
+		return
 	}
+	a, b := edge.routingPoints[bestV], edge.routingPoints[bestV+1]
+	edge.labelPos = Position{X: a.X + 2, Y: (a.Y + b.Y) / 2}
+}
 
-	// End point
-	routingPoints = append(routingPoints, Position{X: targetX, Y: targetY})
+// truncateEdgeLabel shortens a label to maxEdgeLabel runes
+func truncateEdgeLabel(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxEdgeLabel {
+		return text
+	}
+	return string(runes[:maxEdgeLabel-1]) + "…"
+}
 
-	edge.routingPoints = routingPoints
+// segmentLength returns the length of segment i of a path
+func segmentLength(path []Position, i int) int {
+	a, b := path[i], path[i+1]
+	return intAbs(b.X-a.X) + intAbs(b.Y-a.Y)
+}
+
+// overlapLength returns how far the ranges [a1, a2] and [b1, b2] overlap
+func overlapLength(a1, a2, b1, b2 int) int {
+	return min(max(a1, a2), max(b1, b2)) - max(min(a1, a2), min(b1, b2))
+}
+
+// between reports whether v lies between a and b inclusive
+func between(v, a, b int) bool {
+	return v >= min(a, b) && v <= max(a, b)
+}
+
+func intAbs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func intSign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
 }
 
 // getEdgeDirection returns the arrow direction character for an edge segment
 // based on the direction from 'from' to 'to'
-//
-//nolint:unused // Used in tests
 func getEdgeDirection(from, to Position) string {
 	if to.Y > from.Y {
 		return "▼" // Down
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
 )
 
 // TestEdgeRoutingStraightVertical tests straight vertical edge routing
@@ -348,3 +349,142 @@ func TestGetEdgeLineChar(t *testing.T) {
 		})
 	}
 }
+
+// findCanvasEdge returns the canvas edge from one node to another
+func findCanvasEdge(t *testing.T, canvas *Canvas, fromID, toID string) *canvasEdge {
+	t.Helper()
+	for _, e := range canvas.edges {
+		if e.edge.FromNodeID == fromID && e.edge.ToNodeID == toID {
+			return e
+		}
+	}
+	t.Fatalf("edge %s -> %s not found in canvas", fromID, toID)
+	return nil
+}
+
+// TestEdgeRoutingAvoidsNodes tests that an edge goes around a node in its way
+func TestEdgeRoutingAvoidsNodes(t *testing.T) {
+	canvas := NewCanvas(80, 40)
+
+	canvas.AddNode(&workflow.StartNode{ID: "top"}, Position{X: 10, Y: 0})
+	canvas.AddNode(&workflow.PassthroughNode{ID: "middle"}, Position{X: 10, Y: 10})
+	canvas.AddNode(&workflow.EndNode{ID: "bottom"}, Position{X: 10, Y: 20})
+
+	if err := canvas.AddEdge(&workflow.Edge{ID: "skip", FromNodeID: "top", ToNodeID: "bottom"}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	cEdge := findCanvasEdge(t, canvas, "top", "bottom")
+	blocker := canvas.nodes["middle"]
+	points := cEdge.routingPoints
+	for i := 0; i+1 < len(points); i++ {
+		if segmentHitsNode(points[i], points[i+1], blocker) {
+			t.Errorf("segment %v -> %v passes through the middle node", points[i], points[i+1])
+		}
+		if points[i].X != points[i+1].X && points[i].Y != points[i+1].Y {
+			t.Errorf("segment %v -> %v is not orthogonal", points[i], points[i+1])
+		}
+	}
+}
+
+// TestEdgeRoutingSpreadsPorts tests that edges leaving one node use separate
+// ports ordered by their targets, so they don't cross
+func TestEdgeRoutingSpreadsPorts(t *testing.T) {
+	canvas := NewCanvas(80, 40)
+
+	canvas.AddNode(&workflow.StartNode{ID: "source"}, Position{X: 20, Y: 0})
+	canvas.AddNode(&workflow.EndNode{ID: "right"}, Position{X: 45, Y: 12})
+	canvas.AddNode(&workflow.EndNode{ID: "left"}, Position{X: 0, Y: 12})
+
+	// Add the right edge first so the ports have to be reassigned
+	canvas.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "source", ToNodeID: "right"})
+	canvas.AddEdge(&workflow.Edge{ID: "e2", FromNodeID: "source", ToNodeID: "left"})
+
+	left := findCanvasEdge(t, canvas, "source", "left")
+	right := findCanvasEdge(t, canvas, "source", "right")
+
+	if left.routingPoints[0].X >= right.routingPoints[0].X {
+		t.Errorf("left edge port %d should be left of right edge port %d",
+			left.routingPoints[0].X, right.routingPoints[0].X)
+	}
+	if crossings, overlaps := pathIntersections(left.routingPoints, right.routingPoints); crossings != 0 || overlaps != 0 {
+		t.Errorf("edges cross %d times and overlap %d times, want neither", crossings, overlaps)
+	}
+}
+
+// TestEdgeRoutingLabel tests that a condition label is placed on the path
+func TestEdgeRoutingLabel(t *testing.T) {
+	canvas := NewCanvas(80, 40)
+
+	canvas.AddNode(&workflow.StartNode{ID: "node-1"}, Position{X: 0, Y: 0})
+	canvas.AddNode(&workflow.EndNode{ID: "node-2"}, Position{X: 40, Y: 10})
+	canvas.AddEdge(&workflow.Edge{ID: "e", FromNodeID: "node-1", ToNodeID: "node-2", Condition: "count > 5"})
+
+	cEdge := findCanvasEdge(t, canvas, "node-1", "node-2")
+	if cEdge.label != "count > 5" {
+		t.Fatalf("label = %q, want the condition", cEdge.label)
+	}
+
+	// The label should sit inside a horizontal run of the path
+	onPath := false
+	points := cEdge.routingPoints
+	for i := 0; i+1 < len(points); i++ {
+		a, b := points[i], points[i+1]
+		if a.Y == b.Y && a.Y == cEdge.labelPos.Y &&
+			cEdge.labelPos.X > min(a.X, b.X) && cEdge.labelPos.X+len(cEdge.label) < max(a.X, b.X) {
+			onPath = true
+		}
+	}
+	if !onPath {
+		t.Errorf("label at %v is not on a horizontal run of %v", cEdge.labelPos, points)
+	}
+}
+
+// TestTruncateEdgeLabel tests that long labels are shortened
+func TestTruncateEdgeLabel(t *testing.T) {
+	if got := truncateEdgeLabel("short"); got != "short" {
+		t.Errorf("truncateEdgeLabel(short) = %q", got)
+	}
+	got := truncateEdgeLabel("a very long condition expression")
+	if len([]rune(got)) != maxEdgeLabel {
+		t.Errorf("truncated label %q has %d runes, want %d", got, len([]rune(got)), maxEdgeLabel)
+	}
+}
+
+// TestRenderEdges tests that edges are drawn on a real screen with an
+// arrowhead into the target and a corner where they turn
+func TestRenderEdges(t *testing.T) {
+	canvas := NewCanvas(80, 40)
+
+	canvas.AddNode(&workflow.StartNode{ID: "node-1"}, Position{X: 0, Y: 0})
+	canvas.AddNode(&workflow.EndNode{ID: "node-2"}, Position{X: 30, Y: 12})
+	canvas.AddEdge(&workflow.Edge{ID: "e", FromNodeID: "node-1", ToNodeID: "node-2"})
+
+	screen := goterm.NewScreen(80, 40)
+	if err := canvas.RenderToScreen(screen); err != nil {
+		t.Fatalf("RenderToScreen() error = %v", err)
+	}
+
+	points := findCanvasEdge(t, canvas, "node-1", "node-2").routingPoints
+	end := points[len(points)-1]
+	if got := screen.GetCell(end.X, end.Y).Ch; got != '▼' {
+		t.Errorf("arrowhead = %q, want '▼'", got)
+	}
+
+	start := points[0]
+	if got := screen.GetCell(start.X, start.Y).Ch; got != '│' {
+		t.Errorf("cell below source = %q, want '│'", got)
+	}
+
+	// A downward Z turns right, then down
+	if len(points) == 4 {
+		if got := screen.GetCell(points[1].X, points[1].Y).Ch; got != '└' {
+			t.Errorf("first corner = %q, want '└'", got)
+		}
+		if got := screen.GetCell(points[2].X, points[2].Y).Ch; got != '┐' {
+			t.Errorf("second corner = %q, want '┐'", got)
+		}
+	} else {
+		t.Errorf("expected a Z-shaped route, got %v", points)
+	}
+}
@@ -0,0 +1,305 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// groupNodePrefix starts the canvas IDs of collapsed groups. Node IDs
+// cannot contain ':', so these never clash with a node's.
+const groupNodePrefix = "group:"
+
+// groupNodeMinWidth is the narrowest box drawn for a collapsed group
+const groupNodeMinWidth = 20
+
+// groupNode stands in on the canvas for the nodes of a collapsed group
+type groupNode struct {
+	name  string
+	count int // Nodes hidden in the group
+}
+
+func (n *groupNode) GetID() string   { return groupNodeID(n.name) }
+func (n *groupNode) Type() string    { return "group" }
+func (n *groupNode) Validate() error { return nil }
+
+func (n *groupNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.GetConfiguration())
+}
+
+func (n *groupNode) GetConfiguration() map[string]interface{} {
+	return map[string]interface{}{"name": n.name, "nodes": n.count}
+}
+
+func (n *groupNode) GetRetryPolicy() *workflow.RetryPolicy { return nil }
+
+// label is drawn in the group's box: its name and how many nodes it holds
+func (n *groupNode) label() string {
+	return fmt.Sprintf("%s (%d)", n.name, n.count)
+}
+
+// groupNodeID returns the canvas ID of a collapsed group
+func groupNodeID(name string) string {
+	return groupNodePrefix + name
+}
+
+// groupName returns the group a canvas ID stands for, if it is a collapsed
+// group's
+func groupName(id string) (string, bool) {
+	return strings.CutPrefix(id, groupNodePrefix)
+}
+
+// canvasGroup is a node group drawn on the canvas: a frame around its
+// nodes or, while collapsed, a single box in their place
+type canvasGroup struct {
+	group *workflow.NodeGroup
+
+	// Set while the group is collapsed
+	proxy       *canvasNode            // Box drawn in place of the nodes
+	members     map[string]*canvasNode // Nodes hidden in the box
+	hiddenEdges []*canvasEdge          // Edges to, from and between the hidden nodes
+	proxyEdges  []*canvasEdge          // Those edges drawn to and from the box instead
+}
+
+// SetGroups sets the node groups drawn on the canvas, collapsing those
+// marked collapsed
+func (c *Canvas) SetGroups(groups []*workflow.NodeGroup) {
+	c.expandGroups()
+	c.groups = nil
+	for _, group := range groups {
+		if group != nil {
+			c.groups = append(c.groups, &canvasGroup{group: group})
+		}
+	}
+	c.collapseGroups()
+}
+
+// forgetGroups drops the canvas's groups without putting the nodes of
+// collapsed ones back, for when the nodes are about to be rebuilt
+func (c *Canvas) forgetGroups() {
+	c.groups = nil
+}
+
+// hiddenNodes returns the nodes hidden in collapsed groups
+func (c *Canvas) hiddenNodes() []*canvasNode {
+	var nodes []*canvasNode
+	for _, g := range c.groups {
+		for _, n := range g.members {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// expandGroups puts the nodes of collapsed groups back on the canvas. Groups
+// are expanded in the reverse order they were collapsed, so edges between
+// two collapsed groups are restored.
+func (c *Canvas) expandGroups() {
+	for i := len(c.groups) - 1; i >= 0; i-- {
+		c.expand(c.groups[i])
+	}
+}
+
+// collapseGroups replaces the nodes of each group marked collapsed by a box
+func (c *Canvas) collapseGroups() {
+	for _, g := range c.groups {
+		if g.group.Collapsed {
+			c.collapse(g)
+		}
+	}
+	c.routeEdges()
+}
+
+// collapse hides a group's nodes behind a box at their top-left corner.
+// Edges between the group and other nodes are drawn to and from the box,
+// once for each node at the other end.
+func (c *Canvas) collapse(g *canvasGroup) {
+	members := make(map[string]*canvasNode)
+	var minX, minY int
+	for _, id := range g.group.Nodes {
+		n, exists := c.nodes[id]
+		if !exists {
+			continue
+		}
+		if len(members) == 0 {
+			minX, minY = n.position.X, n.position.Y
+		}
+		minX, minY = min(minX, n.position.X), min(minY, n.position.Y)
+		members[id] = n
+	}
+	if len(members) == 0 {
+		return
+	}
+
+	node := &groupNode{name: g.group.Name, count: len(members)}
+	proxyID := node.GetID()
+	g.proxy = &canvasNode{
+		node:             node,
+		position:         Position{X: minX, Y: minY},
+		width:            max(groupNodeMinWidth, utf8.RuneCountInString(node.label())+4),
+		height:           4,
+		validationStatus: "valid",
+	}
+	g.members = members
+	for id := range members {
+		delete(c.nodes, id)
+		if c.selectedID == id {
+			c.selectedID = proxyID
+		}
+	}
+	g.proxy.selected = c.selectedID == proxyID
+	c.nodes[proxyID] = g.proxy
+
+	edges := make([]*canvasEdge, 0, len(c.edges))
+	drawn := make(map[string]bool)
+	for _, e := range c.edges {
+		from, to := e.edge.FromNodeID, e.edge.ToNodeID
+		_, fromHidden := members[from]
+		_, toHidden := members[to]
+		if !fromHidden && !toHidden {
+			edges = append(edges, e)
+			continue
+		}
+		g.hiddenEdges = append(g.hiddenEdges, e)
+		if fromHidden && toHidden {
+			continue
+		}
+		if fromHidden {
+			from = proxyID
+		} else {
+			to = proxyID
+		}
+		if drawn[from+"\x00"+to] {
+			continue
+		}
+		drawn[from+"\x00"+to] = true
+		proxyEdge := &canvasEdge{edge: &workflow.Edge{
+			ID:         e.edge.ID,
+			FromNodeID: from,
+			ToNodeID:   to,
+			Condition:  e.edge.Condition,
+			Label:      e.edge.Label,
+			OnError:    e.edge.OnError,
+		}}
+		g.proxyEdges = append(g.proxyEdges, proxyEdge)
+		edges = append(edges, proxyEdge)
+	}
+	c.edges = edges
+}
+
+// expand puts a collapsed group's nodes and edges back in place of its box.
+// Edges to nodes removed in the meantime are dropped.
+func (c *Canvas) expand(g *canvasGroup) {
+	if g.proxy == nil {
+		return
+	}
+	proxyID := g.proxy.node.GetID()
+	delete(c.nodes, proxyID)
+	for id, n := range g.members {
+		c.nodes[id] = n
+	}
+
+	proxyEdges := make(map[*canvasEdge]bool, len(g.proxyEdges))
+	for _, e := range g.proxyEdges {
+		proxyEdges[e] = true
+	}
+	edges := make([]*canvasEdge, 0, len(c.edges)+len(g.hiddenEdges))
+	for _, e := range c.edges {
+		if !proxyEdges[e] {
+			edges = append(edges, e)
+		}
+	}
+	for _, e := range g.hiddenEdges {
+		_, fromExists := c.nodes[e.edge.FromNodeID]
+		_, toExists := c.nodes[e.edge.ToNodeID]
+		if fromExists && toExists {
+			edges = append(edges, e)
+		}
+	}
+	c.edges = edges
+
+	if c.selectedID == proxyID {
+		for _, id := range g.group.Nodes {
+			if _, exists := g.members[id]; exists {
+				c.selectedID = id
+				break
+			}
+		}
+	}
+	g.proxy, g.members, g.hiddenEdges, g.proxyEdges = nil, nil, nil, nil
+}
+
+// renderGroupFrames draws a frame around the nodes of each expanded group,
+// with the group's name in its top border. Frames go under the edges and
+// nodes.
+func (c *Canvas) renderGroupFrames(scr cellScreen, screenWidth, screenHeight int) {
+	fg := CurrentTheme().GroupFrame
+	bg := CurrentTheme().CanvasBg
+	set := func(x, y int, ch rune) {
+		x, y = x-c.ViewportX, y-c.ViewportY
+		if x >= 0 && x < screenWidth && y >= 0 && y < screenHeight {
+			scr.SetCell(x, y, goterm.NewCell(ch, fg, bg, goterm.StyleDim))
+		}
+	}
+
+	for _, g := range c.groups {
+		if g.proxy != nil {
+			continue // Collapsed: drawn as a node
+		}
+		frame, ok := c.groupFrame(g)
+		if !ok {
+			continue
+		}
+		left, top := frame.TopLeft.X, frame.TopLeft.Y
+		bottomRight := frame.BottomRight()
+		right, bottom := bottomRight.X, bottomRight.Y
+		for x := left + 1; x < right; x++ {
+			set(x, top, '─')
+			set(x, bottom, '─')
+		}
+		for y := top + 1; y < bottom; y++ {
+			set(left, y, '│')
+			set(right, y, '│')
+		}
+		set(left, top, '╭')
+		set(right, top, '╮')
+		set(left, bottom, '╰')
+		set(right, bottom, '╯')
+
+		title := []rune(" " + g.group.Name + " ")
+		for i, ch := range title {
+			if x := left + 2 + i; x < right-1 {
+				set(x, top, ch)
+			}
+		}
+	}
+}
+
+// groupFrame returns the box drawn around an expanded group's nodes, a
+// cell clear of them on every side
+func (c *Canvas) groupFrame(g *canvasGroup) (BoundingBox, bool) {
+	first := true
+	var minX, minY, maxX, maxY int
+	for _, id := range g.group.Nodes {
+		n, exists := c.nodes[id]
+		if !exists {
+			continue
+		}
+		if first {
+			minX, minY = n.position.X, n.position.Y
+			maxX, maxY = n.position.X+n.width, n.position.Y+n.height
+			first = false
+			continue
+		}
+		minX, minY = min(minX, n.position.X), min(minY, n.position.Y)
+		maxX, maxY = max(maxX, n.position.X+n.width), max(maxY, n.position.Y+n.height)
+	}
+	if first {
+		return BoundingBox{}, false
+	}
+	return NewBoundingBox(minX-2, minY-1, maxX-minX+4, maxY-minY+2), true
+}
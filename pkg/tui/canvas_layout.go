@@ -26,6 +26,10 @@ func (c *Canvas) AutoLayout(wf *workflow.Workflow) {
 		return
 	}
 
+	// Lay out every node, then collapse groups again where they now are
+	c.expandGroups()
+	defer c.collapseGroups()
+
 	// Build adjacency list for graph traversal
 	adjacency := make(map[string][]string)
 	inDegree := make(map[string]int)
@@ -53,9 +57,7 @@ func (c *Canvas) AutoLayout(wf *workflow.Workflow) {
 	c.assignPositions(layers)
 
 	// Step 4: Re-route all edges
-	for _, edge := range c.edges {
-		c.routeEdge(edge)
-	}
+	c.routeEdges()
 }
 
 // assignLayers performs topological sort and assigns each node to a layer
@@ -223,6 +225,21 @@ func (c *Canvas) ResetView() {
 	}
 }
 
+// CenterOn centers the viewport on a node, keeping the zoom level.
+// Returns an error if the node is not on the canvas.
+func (c *Canvas) CenterOn(nodeID string) error {
+	cNode, exists := c.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+
+	centerX := cNode.position.X + cNode.width/2
+	centerY := cNode.position.Y + cNode.height/2
+	c.ViewportX = max(centerX-c.Width/2, 0)
+	c.ViewportY = max(centerY-c.Height/2, 0)
+	return nil
+}
+
 // FitAll adjusts zoom and viewport to show all nodes in the viewport.
 // Calculates bounding box of all nodes, then computes zoom level to fit.
 // Centers viewport on the bounding box center.
@@ -0,0 +1,185 @@
+package tui
+
+import "github.com/dshills/goterm"
+
+// Minimap size limits in cells, border included
+const (
+	minimapMaxWidth  = 32
+	minimapMaxHeight = 12
+	minimapMinWidth  = 12
+	minimapMinHeight = 6
+)
+
+// minimap is the placement of the minimap overlay and the logical area it
+// shows
+type minimap struct {
+	// x, y, width and height are the overlay's screen rectangle, border
+	// included
+	x, y, width, height int
+	// world is the logical area drawn: the whole graph and the view
+	world BoundingBox
+}
+
+// ShowMinimap turns the minimap overlay on or off. When on, it is drawn in
+// the bottom-right corner of the view whenever the graph doesn't fit.
+func (c *Canvas) ShowMinimap(show bool) {
+	c.minimapHidden = !show
+}
+
+// ToggleMinimap switches the minimap overlay on or off
+func (c *Canvas) ToggleMinimap() {
+	c.minimapHidden = !c.minimapHidden
+}
+
+// MinimapEnabled returns whether the minimap overlay is switched on
+func (c *Canvas) MinimapEnabled() bool {
+	return !c.minimapHidden
+}
+
+// graphBounds returns the box around every node, or false for an empty
+// canvas
+func (c *Canvas) graphBounds() (BoundingBox, bool) {
+	if len(c.nodes) == 0 {
+		return BoundingBox{}, false
+	}
+	first := true
+	var minX, minY, maxX, maxY int
+	for _, n := range c.nodes {
+		if first {
+			minX, minY = n.position.X, n.position.Y
+			maxX, maxY = n.position.X+n.width, n.position.Y+n.height
+			first = false
+			continue
+		}
+		minX, minY = min(minX, n.position.X), min(minY, n.position.Y)
+		maxX, maxY = max(maxX, n.position.X+n.width), max(maxY, n.position.Y+n.height)
+	}
+	return NewBoundingBox(minX, minY, maxX-minX, maxY-minY), true
+}
+
+// layoutMinimap places the minimap for a view of the given size. It returns
+// false when the minimap is off, the graph fits in the view, or the view is
+// too small to spare the room.
+func (c *Canvas) layoutMinimap(screenWidth, screenHeight int) (minimap, bool) {
+	if c.minimapHidden {
+		return minimap{}, false
+	}
+	graph, ok := c.graphBounds()
+	if !ok {
+		return minimap{}, false
+	}
+	view := NewBoundingBox(c.ViewportX, c.ViewportY, screenWidth, screenHeight)
+	if view.Contains(graph.TopLeft) && view.Contains(graph.BottomRight()) {
+		return minimap{}, false
+	}
+
+	width := clamp(screenWidth/4, minimapMinWidth, minimapMaxWidth)
+	height := clamp(screenHeight/4, minimapMinHeight, minimapMaxHeight)
+	if width > screenWidth/2 || height > screenHeight/2 {
+		return minimap{}, false
+	}
+
+	// Show the view as well, so its outline is on the map even when it has
+	// been panned away from the graph
+	minX, minY := min(graph.TopLeft.X, view.TopLeft.X), min(graph.TopLeft.Y, view.TopLeft.Y)
+	maxX := max(graph.TopLeft.X+graph.Size.Width, view.TopLeft.X+view.Size.Width)
+	maxY := max(graph.TopLeft.Y+graph.Size.Height, view.TopLeft.Y+view.Size.Height)
+
+	return minimap{
+		x:      screenWidth - width,
+		y:      screenHeight - height,
+		width:  width,
+		height: height,
+		world:  NewBoundingBox(minX, minY, maxX-minX, maxY-minY),
+	}, true
+}
+
+// toCell maps a logical position to a screen cell inside the minimap's
+// border
+func (m minimap) toCell(p Position) (int, int) {
+	innerWidth, innerHeight := m.width-2, m.height-2
+	x := (p.X - m.world.TopLeft.X) * innerWidth / m.world.Size.Width
+	y := (p.Y - m.world.TopLeft.Y) * innerHeight / m.world.Size.Height
+	return m.x + 1 + clamp(x, 0, innerWidth-1), m.y + 1 + clamp(y, 0, innerHeight-1)
+}
+
+// renderMinimap draws the whole graph scaled down into the bottom-right
+// corner of the view, with the visible area highlighted. Each node is one
+// dot, so the cost grows with the number of nodes, not their size.
+func (c *Canvas) renderMinimap(scr cellScreen, screenWidth, screenHeight int) {
+	m, ok := c.layoutMinimap(screenWidth, screenHeight)
+	if !ok {
+		return
+	}
+
+	theme := CurrentTheme()
+	borderFg := theme.PanelBorder
+	bg := theme.MinimapBg
+	viewBg := theme.MinimapView // The visible area
+	nodeFg := theme.MinimapNode // Nodes
+	selectedFg := theme.Accent  // The selected node
+	errorFg := theme.Error      // Nodes with errors
+	style := goterm.StyleNone
+
+	// The visible area, in minimap cells
+	viewLeft, viewTop := m.toCell(Position{X: c.ViewportX, Y: c.ViewportY})
+	viewRight, viewBottom := m.toCell(Position{X: c.ViewportX + screenWidth - 1, Y: c.ViewportY + screenHeight - 1})
+	background := func(x, y int) goterm.Color {
+		if x >= viewLeft && x <= viewRight && y >= viewTop && y <= viewBottom {
+			return viewBg
+		}
+		return bg
+	}
+
+	// Border and background
+	for y := m.y; y < m.y+m.height; y++ {
+		for x := m.x; x < m.x+m.width; x++ {
+			top, bottom := y == m.y, y == m.y+m.height-1
+			left, right := x == m.x, x == m.x+m.width-1
+			ch, cellBg := ' ', bg
+			switch {
+			case top && left:
+				ch = '┌'
+			case top && right:
+				ch = '┐'
+			case bottom && left:
+				ch = '└'
+			case bottom && right:
+				ch = '┘'
+			case top || bottom:
+				ch = '─'
+			case left || right:
+				ch = '│'
+			default:
+				cellBg = background(x, y)
+			}
+			scr.SetCell(x, y, goterm.NewCell(ch, borderFg, cellBg, style))
+		}
+	}
+	for i, ch := range " Map " {
+		scr.SetCell(m.x+2+i, m.y, goterm.NewCell(ch, borderFg, bg, style))
+	}
+
+	// One dot per node at its center; the current node is drawn last so a
+	// neighbour sharing its cell never hides it
+	dot := func(n *canvasNode, fg goterm.Color) {
+		x, y := m.toCell(nodeBounds(n).Center())
+		scr.SetCell(x, y, goterm.NewCell('■', fg, background(x, y), style))
+	}
+	for id, n := range c.nodes {
+		if id == c.selectedID {
+			continue
+		}
+		switch {
+		case n.selected:
+			dot(n, selectedFg)
+		case n.validationStatus == "error":
+			dot(n, errorFg)
+		default:
+			dot(n, nodeFg)
+		}
+	}
+	if current, exists := c.nodes[c.selectedID]; exists {
+		dot(current, selectedFg)
+	}
+}
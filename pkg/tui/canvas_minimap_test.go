@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// countingScreen records how many cells are written
+type countingScreen struct {
+	width, height int
+	writes        int
+}
+
+func (s *countingScreen) SetCell(_, _ int, _ goterm.Cell) {
+	s.writes++
+}
+
+func (s *countingScreen) Size() (int, int) {
+	return s.width, s.height
+}
+
+// newGridCanvas builds a canvas with rows*cols nodes laid out in a grid,
+// each joined to its right and lower neighbours
+func newGridCanvas(t testing.TB, rows, cols int) *Canvas {
+	t.Helper()
+	canvas := NewCanvas(80, 24)
+	id := func(r, c int) string { return fmt.Sprintf("n-%d-%d", r, c) }
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			node := &workflow.PassthroughNode{ID: id(r, c)}
+			if err := canvas.AddNode(node, Position{X: c * 30, Y: r * 10}); err != nil {
+				t.Fatalf("AddNode() error = %v", err)
+			}
+		}
+	}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if c+1 < cols {
+				canvas.edges = append(canvas.edges, &canvasEdge{edge: &workflow.Edge{FromNodeID: id(r, c), ToNodeID: id(r, c+1)}})
+			}
+			if r+1 < rows {
+				canvas.edges = append(canvas.edges, &canvasEdge{edge: &workflow.Edge{FromNodeID: id(r, c), ToNodeID: id(r+1, c)}})
+			}
+		}
+	}
+	canvas.routeEdges()
+	return canvas
+}
+
+// TestMinimap_HiddenWhenGraphFits tests that no minimap is drawn for a graph
+// that fits in the view
+func TestMinimap_HiddenWhenGraphFits(t *testing.T) {
+	canvas := NewCanvas(80, 24)
+	canvas.AddNode(&workflow.StartNode{ID: "start"}, Position{X: 2, Y: 2})
+
+	if _, ok := canvas.layoutMinimap(80, 24); ok {
+		t.Error("minimap should be hidden when the graph fits")
+	}
+}
+
+// TestMinimap_ShowsViewportAndNodes tests that a large graph gets a minimap
+// with the visible area highlighted and a dot for the current node
+func TestMinimap_ShowsViewportAndNodes(t *testing.T) {
+	canvas := newGridCanvas(t, 10, 10)
+	canvas.selectedID = "n-0-0"
+
+	screen := goterm.NewScreen(80, 24)
+	if err := canvas.RenderToScreen(screen); err != nil {
+		t.Fatalf("RenderToScreen() error = %v", err)
+	}
+
+	m, ok := canvas.layoutMinimap(80, 24)
+	if !ok {
+		t.Fatal("minimap should be shown when the graph doesn't fit")
+	}
+	if m.x+m.width != 80 || m.y+m.height != 24 {
+		t.Errorf("minimap at (%d, %d) size %dx%d, want the bottom-right corner", m.x, m.y, m.width, m.height)
+	}
+	if got := screen.GetCell(m.x, m.y).Ch; got != '┌' {
+		t.Errorf("minimap corner = %q, want '┌'", got)
+	}
+
+	// The view is at the top-left of the graph, so the top-left cell inside
+	// the border is highlighted and holds the current node
+	cell := screen.GetCell(m.x+1, m.y+1)
+	if cell.Ch != '■' {
+		t.Errorf("current node cell = %q, want '■'", cell.Ch)
+	}
+	if cell.Bg != goterm.ColorRGB(60, 60, 90) {
+		t.Error("visible area should be highlighted")
+	}
+	if corner := screen.GetCell(m.x+m.width-2, m.y+m.height-2); corner.Bg == goterm.ColorRGB(60, 60, 90) {
+		t.Error("area outside the view should not be highlighted")
+	}
+}
+
+// TestMinimap_Toggle tests switching the minimap off and on
+func TestMinimap_Toggle(t *testing.T) {
+	canvas := newGridCanvas(t, 10, 10)
+
+	canvas.ToggleMinimap()
+	if canvas.MinimapEnabled() {
+		t.Fatal("minimap should be off after toggling")
+	}
+	if _, ok := canvas.layoutMinimap(80, 24); ok {
+		t.Error("minimap should not be laid out when off")
+	}
+
+	canvas.ShowMinimap(true)
+	if _, ok := canvas.layoutMinimap(80, 24); !ok {
+		t.Error("minimap should be laid out when on")
+	}
+}
+
+// TestRenderToScreen_OnlyVisibleCells tests that rendering a large graph
+// writes a number of cells bounded by the view, not the graph
+func TestRenderToScreen_OnlyVisibleCells(t *testing.T) {
+	canvas := newGridCanvas(t, 25, 20)
+
+	for _, viewport := range []Position{{X: 0, Y: 0}, {X: 250, Y: 120}} {
+		canvas.ViewportX, canvas.ViewportY = viewport.X, viewport.Y
+		screen := &countingScreen{width: 80, height: 24}
+		if err := canvas.RenderToScreen(screen); err != nil {
+			t.Fatalf("RenderToScreen() error = %v", err)
+		}
+		if limit := 3 * 80 * 24; screen.writes == 0 || screen.writes > limit {
+			t.Errorf("viewport %v: %d cell writes, want between 1 and %d", viewport, screen.writes, limit)
+		}
+	}
+}
+
+// TestRenderToScreen_ClipsToCanvasWidth tests that the canvas leaves the
+// screen beyond its width, where the builder's panels go, untouched
+func TestRenderToScreen_ClipsToCanvasWidth(t *testing.T) {
+	canvas := NewCanvas(30, 24)
+	canvas.AddNode(&workflow.StartNode{ID: "wide"}, Position{X: 20, Y: 2})
+
+	screen := goterm.NewScreen(80, 24)
+	if err := canvas.RenderToScreen(screen); err != nil {
+		t.Fatalf("RenderToScreen() error = %v", err)
+	}
+	if got := screen.GetCell(29, 2).Ch; got == ' ' {
+		t.Error("node should be drawn up to the canvas width")
+	}
+	if got := screen.GetCell(30, 2).Ch; got != ' ' {
+		t.Errorf("cell past the canvas width = %q, want blank", got)
+	}
+}
+
+// TestWorkflowBuilderView_RendersCanvas tests that the builder draws its
+// canvas on a real screen
+func TestWorkflowBuilderView_RendersCanvas(t *testing.T) {
+	view := NewWorkflowBuilderView()
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	text := renderViewText(t, view, 100, 30)
+	if strings.Contains(text, "Render error") {
+		t.Errorf("builder failed to render:\n%s", text)
+	}
+	if !strings.Contains(text, "┌") {
+		t.Errorf("no nodes drawn:\n%s", text)
+	}
+}
+
+// renderViewText renders a view to a screen of the given size and returns
+// its characters, one line per row
+func renderViewText(t *testing.T, view View, width, height int) string {
+	t.Helper()
+	screen := goterm.NewScreen(width, height)
+	if err := view.Render(screen); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var text strings.Builder
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			text.WriteRune(screen.GetCell(x, y).Ch)
+		}
+		text.WriteRune('\n')
+	}
+	return text.String()
+}
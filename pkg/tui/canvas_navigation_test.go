@@ -408,3 +408,29 @@ func TestFitAll_ClampsZoom(t *testing.T) {
 		t.Errorf("Expected ZoomLevel <= 2.0 (clamped), got %.2f", canvas.ZoomLevel)
 	}
 }
+
+// TestCenterOn_CentersViewportOnNode verifies that CenterOn() centers the viewport on a node
+func TestCenterOn_CentersViewportOnNode(t *testing.T) {
+	canvas := NewCanvas(100, 50)
+
+	node := &workflow.StartNode{ID: "start"}
+	if err := canvas.AddNode(node, Position{X: 300, Y: 120}); err != nil {
+		t.Fatalf("AddNode() failed: %v", err)
+	}
+
+	if err := canvas.CenterOn("start"); err != nil {
+		t.Fatalf("CenterOn() failed: %v", err)
+	}
+
+	cNode := canvas.nodes["start"]
+	if want := cNode.position.X + cNode.width/2 - canvas.Width/2; canvas.ViewportX != want {
+		t.Errorf("Expected ViewportX=%d, got %d", want, canvas.ViewportX)
+	}
+	if want := cNode.position.Y + cNode.height/2 - canvas.Height/2; canvas.ViewportY != want {
+		t.Errorf("Expected ViewportY=%d, got %d", want, canvas.ViewportY)
+	}
+
+	if err := canvas.CenterOn("missing"); err == nil {
+		t.Error("Expected error for unknown node")
+	}
+}
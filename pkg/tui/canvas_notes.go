@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/dshills/goterm"
+)
+
+// Sticky notes are drawn to the right of their nodes, wrapped to
+// noteWidth columns and cut off after noteMaxLines lines
+const (
+	noteWidth    = 24
+	noteMaxLines = 4
+	noteGap      = 2 // Columns between a node and its note
+)
+
+// SetNotes sets the notes drawn beside the nodes, keyed by node ID
+func (c *Canvas) SetNotes(notes map[string]string) {
+	c.notes = notes
+}
+
+// renderNotes draws each visible node's note as a sticky note to its
+// right. Notes go under the nodes, so they never hide one.
+func (c *Canvas) renderNotes(scr cellScreen, screenWidth, screenHeight int) {
+	if len(c.notes) == 0 {
+		return
+	}
+	fg := CurrentTheme().NoteFg
+	bg := CurrentTheme().NoteBg
+
+	for id, node := range c.nodes {
+		text, ok := c.notes[id]
+		if !ok {
+			continue
+		}
+		left := node.position.X + node.width + noteGap - c.ViewportX
+		top := node.position.Y - c.ViewportY
+		for i, line := range wrapNote(text) {
+			y := top + i
+			if y < 0 || y >= screenHeight {
+				continue
+			}
+			runes := []rune(" " + line)
+			for x := 0; x < noteWidth; x++ {
+				ch := ' '
+				if x < len(runes) {
+					ch = runes[x]
+				}
+				if sx := left + x; sx >= 0 && sx < screenWidth {
+					scr.SetCell(sx, y, goterm.NewCell(ch, fg, bg, goterm.StyleNone))
+				}
+			}
+		}
+	}
+}
+
+// wrapNote breaks a note into lines that fit a sticky note, marking with
+// '…' where it was cut off
+func wrapNote(text string) []string {
+	const width = noteWidth - 2 // A space of margin on either side
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for len([]rune(word)) > width {
+				// Words too long for a line are broken
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) > noteMaxLines {
+		lines = lines[:noteMaxLines]
+		last := []rune(lines[noteMaxLines-1])
+		if len(last) >= width {
+			last = last[:width-1]
+		}
+		lines[noteMaxLines-1] = string(last) + "…"
+	}
+	return lines
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// SystemClipboard writes text to the clipboard shared with other programs
+type SystemClipboard interface {
+	WriteText(text string) error
+}
+
+// clipboardCommand is a program that copies its standard input to the
+// system clipboard
+type clipboardCommand struct {
+	name string
+	args []string
+}
+
+// commandClipboard writes to the system clipboard through the first
+// clipboard program found on PATH
+type commandClipboard struct {
+	commands []clipboardCommand
+}
+
+// NewSystemClipboard returns a SystemClipboard backed by the platform's
+// clipboard program: pbcopy on macOS, clip.exe on Windows, and wl-copy,
+// xclip or xsel elsewhere
+func NewSystemClipboard() SystemClipboard {
+	switch runtime.GOOS {
+	case "darwin":
+		return &commandClipboard{commands: []clipboardCommand{{name: "pbcopy"}}}
+	case "windows":
+		return &commandClipboard{commands: []clipboardCommand{{name: "clip.exe"}}}
+	}
+
+	commands := []clipboardCommand{
+		{name: "xclip", args: []string{"-selection", "clipboard"}},
+		{name: "xsel", args: []string{"--clipboard", "--input"}},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append([]clipboardCommand{{name: "wl-copy"}}, commands...)
+	}
+	return &commandClipboard{commands: commands}
+}
+
+// WriteText copies text to the system clipboard
+func (c *commandClipboard) WriteText(text string) error {
+	for _, command := range c.commands {
+		path, err := exec.LookPath(command.name)
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, command.args...)
+		cmd.Stdin = strings.NewReader(text)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s failed: %w: %s", command.name, err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+	return errors.New("no clipboard program found")
+}
+
+// TextClipboard copies text such as tool names, node snippets and error
+// details. Copied text always lands in an in-app register, which text
+// fields paste with Ctrl+v, and also on the system clipboard when a
+// clipboard program is available.
+type TextClipboard struct {
+	mu       sync.Mutex
+	system   SystemClipboard
+	register string
+}
+
+// NewTextClipboard creates a text clipboard that also copies to system;
+// nil keeps copies in the register only
+func NewTextClipboard(system SystemClipboard) *TextClipboard {
+	return &TextClipboard{system: system}
+}
+
+// defaultClipboard is shared by all views
+var defaultClipboard = NewTextClipboard(NewSystemClipboard())
+
+// Clipboard returns the text clipboard shared by all views
+func Clipboard() *TextClipboard {
+	return defaultClipboard
+}
+
+// Copy puts text in the register and on the system clipboard. An error
+// means only the register received it.
+func (c *TextClipboard) Copy(text string) error {
+	c.mu.Lock()
+	c.register = text
+	system := c.system
+	c.mu.Unlock()
+
+	if system == nil {
+		return errors.New("no system clipboard")
+	}
+	return system.WriteText(text)
+}
+
+// Paste returns the text last copied, "" if nothing was
+func (c *TextClipboard) Paste() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.register
+}
+
+// SetSystem replaces the system clipboard copies also go to, returning
+// the previous one; nil keeps copies in the register only
+func (c *TextClipboard) SetSystem(system SystemClipboard) SystemClipboard {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous := c.system
+	c.system = system
+	return previous
+}
+
+// copyText copies text to the shared clipboard, notifies where it went and
+// returns a status line saying so. what names the text, as in "tool name".
+func copyText(what, text string) string {
+	if err := Clipboard().Copy(text); err != nil {
+		Notifications().Notify(NotifyInfo, "Copied %s to the in-app register (%v); paste with Ctrl+v", what, err)
+		return fmt.Sprintf("Copied %s to the in-app register; paste with Ctrl+v", what)
+	}
+	Notifications().Notify(NotifySuccess, "Copied %s to the clipboard", what)
+	return fmt.Sprintf("Copied %s to the clipboard", what)
+}
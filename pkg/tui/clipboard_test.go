@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// useSystemClipboard makes the shared clipboard copy to system for the
+// rest of the test
+func useSystemClipboard(t *testing.T, system SystemClipboard) {
+	t.Helper()
+	previous := Clipboard().SetSystem(system)
+	t.Cleanup(func() { Clipboard().SetSystem(previous) })
+}
+
+func TestTextClipboard(t *testing.T) {
+	system := &fakeSystemClipboard{}
+	clipboard := NewTextClipboard(system)
+
+	if err := clipboard.Copy("read_file"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if system.text != "read_file" || clipboard.Paste() != "read_file" {
+		t.Errorf("system = %q, register = %q, want read_file in both", system.text, clipboard.Paste())
+	}
+
+	// Without a working system clipboard, the register still gets the text
+	system.err = errors.New("no clipboard program found")
+	if err := clipboard.Copy("write_file"); err == nil {
+		t.Error("expected the system clipboard error")
+	}
+	if clipboard.Paste() != "write_file" {
+		t.Errorf("register = %q, want write_file", clipboard.Paste())
+	}
+
+	clipboard.SetSystem(nil)
+	if err := clipboard.Copy("stat"); err == nil || clipboard.Paste() != "stat" {
+		t.Errorf("register-only copy: err = %v, register = %q", err, clipboard.Paste())
+	}
+}
+
+func TestServerRegistryView_CopyTool(t *testing.T) {
+	system := &fakeSystemClipboard{}
+	useSystemClipboard(t, system)
+	view := setupTestView(t, 1)
+	defer view.Cleanup()
+	server := view.servers[0]
+	server.Tools = []mcpserver.Tool{{Name: "search", InputSchema: &mcpserver.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"limit": map[string]interface{}{"type": "integer"},
+			"query": map[string]interface{}{"type": "string"},
+		},
+		Required: []string{"query"},
+	}}}
+	view.showToolSchema = true
+
+	if err := view.HandleKey(KeyEvent{Key: 'y'}); err != nil {
+		t.Fatal(err)
+	}
+	if system.text != "search" {
+		t.Errorf("copied %q, want the tool name", system.text)
+	}
+
+	if err := view.HandleKey(KeyEvent{Key: 'Y'}); err != nil {
+		t.Fatal(err)
+	}
+	wf, err := workflow.Parse([]byte("version: \"1.0\"\nname: snippet\n" + system.text))
+	if err != nil {
+		t.Fatalf("snippet does not parse: %v\n%s", err, system.text)
+	}
+	node, ok := wf.Nodes[0].(*workflow.MCPToolNode)
+	if !ok || node.ServerID != server.ID || node.ToolName != "search" || node.Parameters["query"] != "${query}" {
+		t.Errorf("snippet node = %+v", wf.Nodes[0])
+	}
+	if _, ok := node.Parameters["limit"]; ok {
+		t.Error("expected only required arguments in the snippet")
+	}
+
+	// Without a system clipboard the copy is kept for pasting in the app
+	system.err = errors.New("no clipboard program found")
+	if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: "Enter"}); err != nil {
+		t.Fatal(err)
+	}
+	if Clipboard().Paste() != "search" || !strings.Contains(view.statusMsg, "in-app register") {
+		t.Errorf("register = %q, status = %q", Clipboard().Paste(), view.statusMsg)
+	}
+}
+
+func TestErrorDetailPanel_DetailsText(t *testing.T) {
+	panel := NewErrorDetailPanel(0, 0, 80, 20)
+	if panel.DetailsText() != "" {
+		t.Error("expected no details without an error")
+	}
+	panel.SetError(&execution.ExecutionError{
+		Type:       execution.ErrorTypeConnection,
+		Message:    "server unreachable",
+		NodeID:     "fetch",
+		StackTrace: "goroutine 1\n",
+		Context:    map[string]interface{}{"server": "github", "attempt": 3},
+		Timestamp:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	want := "Type: connection\n" +
+		"Message: server unreachable\n" +
+		"Node: fetch\n" +
+		"Time: 2024-05-01T12:00:00Z\n" +
+		"Recoverable: false\n" +
+		"Context:\n" +
+		"  attempt: 3\n" +
+		"  server: github\n" +
+		"Stack trace:\n" +
+		"goroutine 1\n"
+	if got := panel.DetailsText(); got != want {
+		t.Errorf("DetailsText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWorkflowBuilder_PasteIntoField(t *testing.T) {
+	useSystemClipboard(t, nil)
+	builder := newCompletionTestBuilder(t)
+	if err := builder.EditNodeProperties("fetch"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+
+	_ = Clipboard().Copy("read_file\n")
+	typeKeys(t, builder, "Tab", "Tab", "Enter", "Ctrl+v", "Enter")
+	if got := getFieldValue(panel.fields, "Tool Name"); got != "read_file" {
+		t.Errorf("Tool Name = %q, want the pasted read_file", got)
+	}
+}
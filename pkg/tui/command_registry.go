@@ -0,0 +1,533 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxCommandHistory caps the number of command lines kept for recall
+const maxCommandHistory = 100
+
+// Command is a command-mode command such as :open <workflow>. Names may be
+// several words ("template apply"); the longest registered name that
+// prefixes the line wins and the remaining words are its arguments.
+type Command struct {
+	Name        string   // Command name, e.g. "open" or "server connect"
+	Aliases     []string // Alternative names, e.g. "e" for "open"
+	Usage       string   // Argument synopsis for help, e.g. "<workflow>"
+	Description string   // One-line description for help
+	MinArgs     int      // Fewest arguments accepted
+	MaxArgs     int      // Most arguments accepted; -1 for no limit
+
+	// Run executes the command with its arguments
+	Run func(args []string) error
+
+	// Complete returns candidates for the next argument, given the
+	// arguments before it. Candidates are filtered by what has been typed.
+	Complete func(args []string) []string
+}
+
+// CommandRegistrar is an optional interface for views that provide
+// command-mode commands. The App registers them once at startup.
+type CommandRegistrar interface {
+	RegisterCommands(registry *CommandRegistry) error
+}
+
+// CommandRegistry holds command-mode commands and the history of lines
+// executed through it
+type CommandRegistry struct {
+	mu       sync.RWMutex
+	commands map[string]*Command // By name and alias
+	history  []string
+}
+
+// NewCommandRegistry creates an empty command registry
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{
+		commands: make(map[string]*Command),
+		history:  make([]string, 0),
+	}
+}
+
+// RegisterBuiltinCommands registers :w, :q, :wq and :q!, which run through
+// ExecuteCommand with the given callbacks
+func RegisterBuiltinCommands(registry *CommandRegistry, config DefaultBindingsConfig) error {
+	builtins := []Command{
+		{Name: "w", Description: "Save workflow"},
+		{Name: "q", Description: "Quit"},
+		{Name: "wq", Description: "Save and quit"},
+		{Name: "q!", Description: "Force quit without saving"},
+	}
+	for _, cmd := range builtins {
+		name := cmd.Name
+		cmd.Run = func(args []string) error {
+			return ExecuteCommand(name, config)
+		}
+		if err := registry.Register(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register adds a command. Its name and aliases must not already be taken.
+func (r *CommandRegistry) Register(cmd Command) error {
+	if cmd.Run == nil {
+		return fmt.Errorf("command %q has no Run function", cmd.Name)
+	}
+
+	names := append([]string{cmd.Name}, cmd.Aliases...)
+	for i, name := range names {
+		names[i] = strings.Join(strings.Fields(name), " ")
+		if names[i] == "" {
+			return fmt.Errorf("command name cannot be empty")
+		}
+	}
+	cmd.Name = names[0]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range names {
+		if _, exists := r.commands[name]; exists {
+			return fmt.Errorf("command conflict: %s already registered", name)
+		}
+	}
+	for _, name := range names {
+		r.commands[name] = &cmd
+	}
+	return nil
+}
+
+// Unregister removes a command and its aliases by name
+func (r *CommandRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cmd, exists := r.commands[name]
+	if !exists {
+		return
+	}
+	for key, other := range r.commands {
+		if other == cmd {
+			delete(r.commands, key)
+		}
+	}
+}
+
+// Commands returns every registered command once, sorted by name
+func (r *CommandRegistry) Commands() []*Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[*Command]bool)
+	commands := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		if !seen[cmd] {
+			seen[cmd] = true
+			commands = append(commands, cmd)
+		}
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+	return commands
+}
+
+// History returns executed command lines, oldest first
+func (r *CommandRegistry) History() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.history...)
+}
+
+// Execute parses and runs a command line (without the leading ':'). The
+// line is added to the history whether or not it succeeds.
+func (r *CommandRegistry) Execute(line string) error {
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+	if line == "" {
+		return nil
+	}
+	r.addHistory(line)
+
+	words, err := splitCommandLine(line)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	cmd, n := r.resolve(words)
+	r.mu.RUnlock()
+	if cmd == nil {
+		return fmt.Errorf("unknown command: %s", words[0])
+	}
+
+	args := words[n:]
+	if len(args) < cmd.MinArgs || (cmd.MaxArgs >= 0 && len(args) > cmd.MaxArgs) {
+		return fmt.Errorf("usage: :%s", strings.TrimSpace(cmd.Name+" "+cmd.Usage))
+	}
+	return cmd.Run(args)
+}
+
+// Complete returns the lines a command line can be completed to: command
+// names while the name is being typed, then the command's argument
+// candidates. Candidates are sorted and share the typed prefix.
+func (r *CommandRegistry) Complete(line string) []string {
+	line = strings.TrimPrefix(line, ":")
+	words, err := splitCommandLine(line)
+	if err != nil {
+		return nil
+	}
+
+	// The last word is still being typed unless the line ends in a space
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	r.mu.RLock()
+	candidates := r.nameCandidates(words, partial)
+	cmd, n := r.resolve(words)
+	r.mu.RUnlock()
+
+	if cmd != nil && cmd.Complete != nil {
+		for _, candidate := range cmd.Complete(words[n:]) {
+			if strings.HasPrefix(candidate, partial) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+	lines := make([]string, 0, len(candidates))
+	prefix := joinCommandLine(words)
+	for i, candidate := range candidates {
+		if i > 0 && candidate == candidates[i-1] {
+			continue
+		}
+		lines = append(lines, strings.TrimSpace(prefix+" "+quoteCommandWord(candidate)))
+	}
+	return lines
+}
+
+// resolve finds the command with the longest name that prefixes words and
+// returns it with the number of words its name uses. Must be called with
+// the lock held.
+func (r *CommandRegistry) resolve(words []string) (*Command, int) {
+	for n := len(words); n > 0; n-- {
+		if cmd, exists := r.commands[strings.Join(words[:n], " ")]; exists {
+			return cmd, n
+		}
+	}
+	return nil, 0
+}
+
+// nameCandidates returns the next word of every command name that starts
+// with words and whose next word starts with partial. Must be called with
+// the lock held.
+func (r *CommandRegistry) nameCandidates(words []string, partial string) []string {
+	var candidates []string
+	for name := range r.commands {
+		nameWords := strings.Fields(name)
+		if len(nameWords) <= len(words) {
+			continue
+		}
+		matches := true
+		for i, word := range words {
+			if nameWords[i] != word {
+				matches = false
+				break
+			}
+		}
+		if matches && strings.HasPrefix(nameWords[len(words)], partial) {
+			candidates = append(candidates, nameWords[len(words)])
+		}
+	}
+	return candidates
+}
+
+// addHistory appends a line, skipping repeats of the previous line
+func (r *CommandRegistry) addHistory(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.history) > 0 && r.history[len(r.history)-1] == line {
+		return
+	}
+	r.history = append(r.history, line)
+	if len(r.history) > maxCommandHistory {
+		r.history = r.history[len(r.history)-maxCommandHistory:]
+	}
+}
+
+// splitCommandLine splits a command line into words. Single or double
+// quotes group words containing spaces.
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command: %s", line)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// joinCommandLine joins words back into a line, quoting where needed
+func joinCommandLine(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = quoteCommandWord(word)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quoteCommandWord quotes a word that contains spaces or quotes
+func quoteCommandWord(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\"'") {
+		return word
+	}
+	if strings.Contains(word, `"`) {
+		return "'" + word + "'"
+	}
+	return `"` + word + `"`
+}
+
+// CommandLine is the input line shown in command mode. It edits the line,
+// recalls history with Up/Down and completes with Tab/Shift-Tab.
+type CommandLine struct {
+	registry *CommandRegistry
+	active   bool
+	buffer   []rune
+	cursor   int
+
+	historyIdx int    // Index into history while browsing, else len(history)
+	draft      string // Line being typed before browsing history
+
+	completions   []string // Candidates from the last Tab
+	completionIdx int      // Candidate shown, -1 before the first Tab
+
+	message string // Result of the last command, shown until the next key
+	isError bool
+}
+
+// NewCommandLine creates a command line that runs commands from registry
+func NewCommandLine(registry *CommandRegistry) *CommandLine {
+	return &CommandLine{registry: registry}
+}
+
+// Open activates the command line with an empty buffer
+func (c *CommandLine) Open() {
+	c.active = true
+	c.buffer = c.buffer[:0]
+	c.cursor = 0
+	c.historyIdx = len(c.registry.History())
+	c.draft = ""
+	c.message = ""
+	c.resetCompletion()
+}
+
+// OpenWith activates the command line with text already typed
+func (c *CommandLine) OpenWith(text string) {
+	c.Open()
+	c.setText(text)
+}
+
+// Close deactivates the command line
+func (c *CommandLine) Close() {
+	c.active = false
+	c.resetCompletion()
+}
+
+// Active returns whether the command line is accepting input
+func (c *CommandLine) Active() bool {
+	return c.active
+}
+
+// Text returns the line typed so far
+func (c *CommandLine) Text() string {
+	return string(c.buffer)
+}
+
+// Cursor returns the cursor position within Text, in runes
+func (c *CommandLine) Cursor() int {
+	return c.cursor
+}
+
+// Completions returns the candidates offered by the last Tab
+func (c *CommandLine) Completions() []string {
+	return c.completions
+}
+
+// Message returns the result of the last command and whether it failed
+func (c *CommandLine) Message() (string, bool) {
+	return c.message, c.isError
+}
+
+// ShowError shows an error in place of the last command's result, and
+// keeps it in the message history
+func (c *CommandLine) ShowError(err error) {
+	c.message, c.isError = err.Error(), true
+	Notifications().Log(NotifyError, "%s", c.message)
+}
+
+// ClearMessage clears the result of the last command
+func (c *CommandLine) ClearMessage() {
+	c.message = ""
+	c.isError = false
+}
+
+// HandleKey edits the line. Enter runs it and Escape or Ctrl-c cancels;
+// either closes the command line. Command errors are returned and also
+// kept as the message.
+func (c *CommandLine) HandleKey(event KeyEvent) error {
+	if !c.active {
+		return nil
+	}
+
+	isTab := event.IsSpecial && event.Special == "Tab"
+	if !isTab {
+		c.resetCompletion()
+	}
+
+	switch {
+	case event.IsSpecial && event.Special == "Escape", event.Ctrl && event.Key == 'c':
+		c.Close()
+	case event.IsSpecial && event.Special == "Enter":
+		line := c.Text()
+		c.Close()
+		if err := c.registry.Execute(line); err != nil {
+			c.ShowError(err)
+			return err
+		}
+	case event.IsSpecial && event.Special == "Backspace":
+		if len(c.buffer) == 0 {
+			c.Close() // Backspace on an empty line leaves command mode, as in vim
+		} else if c.cursor > 0 {
+			c.buffer = append(c.buffer[:c.cursor-1], c.buffer[c.cursor:]...)
+			c.cursor--
+		}
+	case event.IsSpecial && event.Special == "Delete":
+		if c.cursor < len(c.buffer) {
+			c.buffer = append(c.buffer[:c.cursor], c.buffer[c.cursor+1:]...)
+		}
+	case event.IsSpecial && event.Special == "Left":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case event.IsSpecial && event.Special == "Right":
+		if c.cursor < len(c.buffer) {
+			c.cursor++
+		}
+	case event.IsSpecial && event.Special == "Home", event.Ctrl && event.Key == 'a':
+		c.cursor = 0
+	case event.IsSpecial && event.Special == "End", event.Ctrl && event.Key == 'e':
+		c.cursor = len(c.buffer)
+	case event.IsSpecial && event.Special == "Up":
+		c.recallHistory(-1)
+	case event.IsSpecial && event.Special == "Down":
+		c.recallHistory(1)
+	case isTab:
+		c.complete(event.Shift)
+	case !event.IsSpecial && !event.Ctrl && !event.Alt && event.Key != 0:
+		c.buffer = append(c.buffer[:c.cursor], append([]rune{event.Key}, c.buffer[c.cursor:]...)...)
+		c.cursor++
+	}
+	return nil
+}
+
+// recallHistory moves through history, keeping the line being typed so
+// Down past the newest entry restores it
+func (c *CommandLine) recallHistory(delta int) {
+	history := c.registry.History()
+	if c.historyIdx > len(history) {
+		c.historyIdx = len(history)
+	}
+	if c.historyIdx == len(history) {
+		c.draft = c.Text()
+	}
+
+	idx := c.historyIdx + delta
+	if idx < 0 || idx > len(history) {
+		return
+	}
+	c.historyIdx = idx
+	if idx == len(history) {
+		c.setText(c.draft)
+	} else {
+		c.setText(history[idx])
+	}
+}
+
+// complete replaces the line with the next (or previous) completion. The
+// first Tab computes candidates; a single candidate is taken outright.
+func (c *CommandLine) complete(backward bool) {
+	if c.completions == nil {
+		c.completions = c.registry.Complete(c.Text())
+		if len(c.completions) == 0 {
+			c.completions = nil
+			return
+		}
+	}
+
+	n := len(c.completions)
+	switch {
+	case c.completionIdx < 0 && backward:
+		c.completionIdx = n - 1
+	case c.completionIdx < 0:
+		c.completionIdx = 0
+	case backward:
+		c.completionIdx = (c.completionIdx - 1 + n) % n
+	default:
+		c.completionIdx = (c.completionIdx + 1) % n
+	}
+	c.setText(c.completions[c.completionIdx])
+
+	if n == 1 {
+		// Nothing to cycle through; ready for the next argument
+		c.setText(c.Text() + " ")
+		c.resetCompletion()
+	}
+}
+
+// resetCompletion forgets the current completion candidates
+func (c *CommandLine) resetCompletion() {
+	c.completions = nil
+	c.completionIdx = -1
+}
+
+// setText replaces the line and moves the cursor to its end
+func (c *CommandLine) setText(text string) {
+	c.buffer = []rune(text)
+	c.cursor = len(c.buffer)
+}
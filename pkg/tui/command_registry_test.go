@@ -0,0 +1,369 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// newTestCommandRegistry registers :open with completion, :template apply
+// and :template list, recording the arguments each receives
+func newTestCommandRegistry(t *testing.T) (*CommandRegistry, map[string][]string) {
+	t.Helper()
+	calls := make(map[string][]string)
+	registry := NewCommandRegistry()
+
+	commands := []Command{
+		{Name: "open", Aliases: []string{"e"}, Usage: "<workflow>", MinArgs: 1, MaxArgs: 1,
+			Complete: func(args []string) []string { return []string{"etl.yaml", "deploy.yaml", "daily report.yaml"} }},
+		{Name: "template apply", Usage: "<name>", MinArgs: 1, MaxArgs: 1},
+		{Name: "template list", MaxArgs: 0},
+		{Name: "set", MaxArgs: -1},
+	}
+	for _, cmd := range commands {
+		name := cmd.Name
+		cmd.Run = func(args []string) error {
+			calls[name] = args
+			return nil
+		}
+		if err := registry.Register(cmd); err != nil {
+			t.Fatalf("Register(%s) failed: %v", cmd.Name, err)
+		}
+	}
+	return registry, calls
+}
+
+func TestCommandRegistry_Execute(t *testing.T) {
+	registry, calls := newTestCommandRegistry(t)
+
+	tests := []struct {
+		line    string
+		command string
+		args    []string
+	}{
+		{":open etl.yaml", "open", []string{"etl.yaml"}},
+		{"e deploy.yaml", "open", []string{"deploy.yaml"}},
+		{"template  apply   basic", "template apply", []string{"basic"}},
+		{`open "daily report.yaml"`, "open", []string{"daily report.yaml"}},
+		{"set a=1 b=2 c='x y'", "set", []string{"a=1", "b=2", "c=x y"}},
+	}
+	for _, tt := range tests {
+		delete(calls, tt.command)
+		if err := registry.Execute(tt.line); err != nil {
+			t.Errorf("Execute(%q) error = %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(calls[tt.command], tt.args) {
+			t.Errorf("Execute(%q) args = %q, want %q", tt.line, calls[tt.command], tt.args)
+		}
+	}
+
+	errorTests := map[string]string{
+		"frobnicate":          "unknown command: frobnicate",
+		"open":                "usage: :open <workflow>",
+		"open a b":            "usage: :open <workflow>",
+		"template list extra": "usage: :template list",
+		"template":            "unknown command: template",
+		`open "unterminated`:  "unterminated quote",
+	}
+	for line, want := range errorTests {
+		err := registry.Execute(line)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Execute(%q) error = %v, want %q", line, err, want)
+		}
+	}
+
+	// Blank lines are ignored; everything else is remembered once in a row
+	_ = registry.Execute("  ")
+	_ = registry.Execute("template list")
+	_ = registry.Execute("template list")
+	history := registry.History()
+	if history[len(history)-1] != "template list" || history[len(history)-2] == "template list" {
+		t.Errorf("History() = %q", history)
+	}
+}
+
+func TestCommandRegistry_Register(t *testing.T) {
+	registry, _ := newTestCommandRegistry(t)
+	run := func(args []string) error { return nil }
+
+	if err := registry.Register(Command{Name: "edit", Aliases: []string{"e"}, Run: run}); err == nil {
+		t.Error("expected conflict for alias e")
+	}
+	if err := registry.Execute("edit x"); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Error("a rejected command must not be partly registered")
+	}
+	if err := registry.Register(Command{Name: " ", Run: run}); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if err := registry.Register(Command{Name: "nop"}); err == nil {
+		t.Error("expected error for missing Run")
+	}
+
+	registry.Unregister("e")
+	if err := registry.Execute("open x"); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("open still registered after unregistering its alias: %v", err)
+	}
+
+	names := make([]string, 0)
+	for _, cmd := range registry.Commands() {
+		names = append(names, cmd.Name)
+	}
+	if want := []string{"set", "template apply", "template list"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Commands() = %q, want %q", names, want)
+	}
+}
+
+func TestCommandRegistry_Complete(t *testing.T) {
+	registry, _ := newTestCommandRegistry(t)
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"te", []string{"template"}},
+		{"template ", []string{"template apply", "template list"}},
+		{":template l", []string{"template list"}},
+		{"open d", []string{`open "daily report.yaml"`, "open deploy.yaml"}},
+		{"open ", []string{`open "daily report.yaml"`, "open deploy.yaml", "open etl.yaml"}},
+		{"zzz", []string{}},
+	}
+	for _, tt := range tests {
+		got := registry.Complete(tt.line)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterBuiltinCommands(t *testing.T) {
+	var saved, quit int
+	registry := NewCommandRegistry()
+	err := RegisterBuiltinCommands(registry, DefaultBindingsConfig{
+		OnExecuteCommand: func(command string) error {
+			if command == "save" {
+				saved++
+			}
+			return nil
+		},
+		OnQuit: func() error { quit++; return nil },
+	})
+	if err != nil {
+		t.Fatalf("RegisterBuiltinCommands failed: %v", err)
+	}
+
+	for _, line := range []string{"w", "wq", "q"} {
+		if err := registry.Execute(line); err != nil {
+			t.Errorf("Execute(%q) error = %v", line, err)
+		}
+	}
+	if saved != 2 || quit != 2 {
+		t.Errorf("saved = %d, quit = %d, want 2 and 2", saved, quit)
+	}
+}
+
+// typeCommand types text into a command line
+func typeCommand(c *CommandLine, text string) {
+	for _, r := range text {
+		_ = c.HandleKey(KeyEvent{Key: r})
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	registry, calls := newTestCommandRegistry(t)
+	line := NewCommandLine(registry)
+	special := func(name string) KeyEvent { return KeyEvent{IsSpecial: true, Special: name} }
+
+	// Typing and editing
+	line.Open()
+	typeCommand(line, "opn")
+	_ = line.HandleKey(special("Left"))
+	typeCommand(line, "e")
+	_ = line.HandleKey(special("End"))
+	typeCommand(line, " etl.yaml")
+	if line.Text() != "open etl.yaml" {
+		t.Fatalf("Text() = %q, want %q", line.Text(), "open etl.yaml")
+	}
+	if err := line.HandleKey(special("Enter")); err != nil {
+		t.Fatalf("Enter error = %v", err)
+	}
+	if line.Active() || calls["open"][0] != "etl.yaml" {
+		t.Errorf("active = %v, open args = %q", line.Active(), calls["open"])
+	}
+
+	// Tab completes a single candidate outright and cycles through several
+	line.Open()
+	typeCommand(line, "templ")
+	_ = line.HandleKey(special("Tab"))
+	if line.Text() != "template " {
+		t.Errorf("Text() after Tab = %q, want %q", line.Text(), "template ")
+	}
+	_ = line.HandleKey(special("Tab"))
+	if line.Text() != "template apply" || len(line.Completions()) != 2 {
+		t.Errorf("Text() = %q, completions = %q", line.Text(), line.Completions())
+	}
+	_ = line.HandleKey(special("Tab"))
+	if line.Text() != "template list" {
+		t.Errorf("Text() after second Tab = %q", line.Text())
+	}
+	_ = line.HandleKey(KeyEvent{IsSpecial: true, Special: "Tab", Shift: true})
+	if line.Text() != "template apply" {
+		t.Errorf("Text() after Shift-Tab = %q", line.Text())
+	}
+
+	// Errors are returned and kept as the message
+	_ = line.HandleKey(special("Escape"))
+	line.Open()
+	typeCommand(line, "bogus")
+	if err := line.HandleKey(special("Enter")); err == nil {
+		t.Error("expected error for unknown command")
+	}
+	if message, isError := line.Message(); !isError || !strings.Contains(message, "bogus") {
+		t.Errorf("Message() = %q, %v", message, isError)
+	}
+
+	// History: Up recalls older lines, Down returns to the draft
+	line.Open()
+	typeCommand(line, "dra")
+	_ = line.HandleKey(special("Up"))
+	if line.Text() != "bogus" {
+		t.Errorf("Up = %q, want bogus", line.Text())
+	}
+	_ = line.HandleKey(special("Up"))
+	if line.Text() != "open etl.yaml" {
+		t.Errorf("second Up = %q, want open etl.yaml", line.Text())
+	}
+	_ = line.HandleKey(special("Down"))
+	_ = line.HandleKey(special("Down"))
+	if line.Text() != "dra" {
+		t.Errorf("Down past newest = %q, want draft", line.Text())
+	}
+
+	// Backspace on an empty line closes it
+	for range "dra" {
+		_ = line.HandleKey(special("Backspace"))
+	}
+	_ = line.HandleKey(special("Backspace"))
+	if line.Active() {
+		t.Error("Backspace on empty line did not close the command line")
+	}
+}
+
+func TestWorkflowBuilderView_Commands(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("..", "..", "examples", "simple-pipeline.yaml"))
+	if err != nil {
+		t.Skipf("example workflow not available: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pipeline.yaml"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewWorkflowBuilderView()
+	view.workflowsDir = dir
+	registry := NewCommandRegistry()
+	if err := view.RegisterCommands(registry); err != nil {
+		t.Fatalf("RegisterCommands failed: %v", err)
+	}
+
+	if got := registry.Complete("open p"); !reflect.DeepEqual(got, []string{"open pipeline.yaml"}) {
+		t.Errorf("Complete(open p) = %q", got)
+	}
+	if err := registry.Execute("open missing"); err == nil {
+		t.Error("expected error opening a missing workflow")
+	}
+	if err := registry.Execute("open pipeline"); err != nil {
+		t.Fatalf("open error = %v", err)
+	}
+	if view.builder == nil || view.workflowPath != filepath.Join(dir, "pipeline.yaml") {
+		t.Fatalf("workflow not loaded: path %q", view.workflowPath)
+	}
+
+	if err := registry.Execute("template apply nope"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+	if err := registry.Execute("template apply etl"); err != nil {
+		t.Fatalf("template apply error = %v", err)
+	}
+	if got := view.builder.GetWorkflow(); got == nil || len(got.Nodes) < 3 {
+		t.Errorf("template not applied: %+v", got)
+	}
+}
+
+func TestServerRegistryView_Commands(t *testing.T) {
+	view := setupTestView(t, 3)
+	registry := NewCommandRegistry()
+	if err := view.RegisterCommands(registry); err != nil {
+		t.Fatalf("RegisterCommands failed: %v", err)
+	}
+
+	if got := registry.Complete("server connect test"); len(got) != 3 {
+		t.Errorf("Complete() = %q, want 3 server IDs", got)
+	}
+
+	// Acts on the named server even when a filter hides it
+	view.filterQuery = "Server 1"
+	view.applyFilter()
+	if err := registry.Execute("server connect test2"); err != nil {
+		t.Fatalf("connect error = %v", err)
+	}
+	server := view.servers[view.selectedIdx]
+	if server.ID != "test2" || server.Connection.GetState() != mcpserver.StateConnected {
+		t.Errorf("selected %s in state %s, want test2 connected", server.ID, server.Connection.GetState())
+	}
+
+	if err := registry.Execute("server disconnect test2"); err != nil {
+		t.Fatalf("disconnect error = %v", err)
+	}
+	if server.Connection.GetState() == mcpserver.StateConnected {
+		t.Error("server still connected")
+	}
+	if err := registry.Execute("server test nope"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestServerRegistryView_Tags(t *testing.T) {
+	view := setupTestView(t, 3)
+	registry := NewCommandRegistry()
+	if err := view.RegisterCommands(registry); err != nil {
+		t.Fatalf("RegisterCommands failed: %v", err)
+	}
+
+	if err := registry.Execute("server tag test1 Prod db"); err != nil {
+		t.Fatalf("tag error = %v", err)
+	}
+	if err := registry.Execute("server tag test2 dev db"); err != nil {
+		t.Fatalf("tag error = %v", err)
+	}
+	if err := registry.Execute("server tag test3 bad!tag"); err == nil {
+		t.Error("expected an invalid tag to be rejected")
+	}
+	if got := view.servers[view.selectedIdx].Tags; strings.Join(got, ",") != "db,dev" {
+		t.Errorf("tags = %v, want normalized [db dev]", got)
+	}
+
+	for query, want := range map[string]int{"db": 2, "tag:prod": 1, "#dev": 1, "tag:db tag:prod": 1, "tag:pro": 0} {
+		view.filterQuery = query
+		view.applyFilter()
+		if len(view.servers) != want {
+			t.Errorf("filter %q matched %d servers, want %d", query, len(view.servers), want)
+		}
+	}
+
+	if err := registry.Execute("server tag test1"); err != nil {
+		t.Fatalf("clearing tags error = %v", err)
+	}
+	view.filterQuery = "tag:prod"
+	view.applyFilter()
+	if len(view.servers) != 0 {
+		t.Errorf("expected cleared tags, %d servers still tagged prod", len(view.servers))
+	}
+}
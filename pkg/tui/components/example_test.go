@@ -1,6 +1,8 @@
 package components_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/dshills/goflow/pkg/tui/components"
@@ -200,6 +202,72 @@ func TestListMultiSelect(t *testing.T) {
 	}
 }
 
+// TestListWindow tests the windowed visible range follows the selection
+func TestListWindow(t *testing.T) {
+	w := components.NewListWindow()
+
+	start, end := w.Visible(0, 100, 10)
+	if start != 0 || end != 10 {
+		t.Errorf("Visible(0) = [%d, %d), want [0, 10)", start, end)
+	}
+
+	// Scrolls just enough to show the selection
+	start, end = w.Visible(15, 100, 10)
+	if start != 6 || end != 16 {
+		t.Errorf("Visible(15) = [%d, %d), want [6, 16)", start, end)
+	}
+
+	// Moving within the window doesn't scroll
+	start, _ = w.Visible(8, 100, 10)
+	if start != 6 {
+		t.Errorf("Visible(8) start = %d, want 6", start)
+	}
+
+	// Shrinking the list doesn't leave empty rows
+	start, end = w.Visible(3, 12, 10)
+	if start != 2 || end != 12 {
+		t.Errorf("Visible after shrink = [%d, %d), want [2, 12)", start, end)
+	}
+
+	// Short lists fit entirely
+	start, end = w.Visible(2, 3, 10)
+	if start != 0 || end != 3 {
+		t.Errorf("Visible(short) = [%d, %d), want [0, 3)", start, end)
+	}
+
+	start, end = w.Visible(0, 0, 10)
+	if start != 0 || end != 0 {
+		t.Errorf("Visible(empty) = [%d, %d), want [0, 0)", start, end)
+	}
+}
+
+// TestPageStepAndRangeIndicator tests paging and the count indicator
+func TestPageStepAndRangeIndicator(t *testing.T) {
+	if got := components.PageStep(5, 50, 20, 1); got != 25 {
+		t.Errorf("PageStep down = %d, want 25", got)
+	}
+	if got := components.PageStep(45, 50, 20, 1); got != 49 {
+		t.Errorf("PageStep past end = %d, want 49", got)
+	}
+	if got := components.PageStep(5, 50, 20, -1); got != 0 {
+		t.Errorf("PageStep past start = %d, want 0", got)
+	}
+
+	tests := []struct {
+		start, end, shown, total int
+		want                     string
+	}{
+		{0, 20, 57, 57, "1-20 of 57"},
+		{20, 25, 25, 57, "21-25 of 25 (57 total)"},
+		{0, 0, 0, 57, "0 of 0 (57 total)"},
+	}
+	for _, tt := range tests {
+		if got := components.RangeIndicator(tt.start, tt.end, tt.shown, tt.total); got != tt.want {
+			t.Errorf("RangeIndicator(%d, %d, %d, %d) = %q, want %q", tt.start, tt.end, tt.shown, tt.total, got, tt.want)
+		}
+	}
+}
+
 // TestModalCreation tests creating modals
 func TestModalCreation(t *testing.T) {
 	modal := components.NewInfoModal("Test", "Test message", func() {
@@ -253,6 +321,57 @@ func TestModalInput(t *testing.T) {
 	}
 }
 
+// TestModalInputValidator tests inline validation of input modals
+func TestModalInputValidator(t *testing.T) {
+	var result string
+	closed := false
+
+	modal := components.NewInputModal("Path", "Enter directory:", "", func(ok bool, input string) {
+		closed = ok
+		result = input
+	})
+	modal.SetValidator(func(input string) (string, error) {
+		if input == "bad" {
+			return "", errors.New("directory does not exist")
+		}
+		return strings.ToUpper(input), nil
+	})
+	modal.Show()
+
+	// Invalid input keeps the modal open and shows the error
+	modal.SetInput("bad")
+	modal.HandleKey("Enter")
+	if closed {
+		t.Fatal("Modal should stay open when validation fails")
+	}
+	if !modal.IsVisible() {
+		t.Error("Modal should remain visible when validation fails")
+	}
+	if modal.ErrorMessage() != "directory does not exist" {
+		t.Errorf("ErrorMessage() = %q, want %q", modal.ErrorMessage(), "directory does not exist")
+	}
+
+	// Rendering with an error must not panic
+	screen := goterm.NewScreen(80, 24)
+	modal.Render(screen)
+
+	// Editing clears the error
+	modal.HandleKey("Backspace")
+	if modal.ErrorMessage() != "" {
+		t.Errorf("ErrorMessage() should be cleared after editing, got %q", modal.ErrorMessage())
+	}
+
+	// Valid input closes with the normalized value
+	modal.SetInput("good")
+	modal.HandleKey("Enter")
+	if !closed {
+		t.Fatal("Modal should close when validation succeeds")
+	}
+	if result != "GOOD" {
+		t.Errorf("Result = %q, want normalized %q", result, "GOOD")
+	}
+}
+
 // TestStatusBarCreation tests creating a status bar
 func TestStatusBarCreation(t *testing.T) {
 	statusBar := components.NewStatusBar(24, 80)
@@ -318,6 +437,32 @@ func TestStatusBarMessage(t *testing.T) {
 	}
 }
 
+// TestModalInputErrorTruncation tests that long inline errors are cut at
+// rune boundaries
+func TestModalInputErrorTruncation(t *testing.T) {
+	modal := components.NewInputModal("Path", "Enter directory:", "", nil)
+	modal.SetValidator(func(input string) (string, error) {
+		return "", errors.New("répertoire introuvable: " + strings.Repeat("é", 60))
+	})
+	modal.Show()
+	modal.SetInput("x")
+	modal.HandleKey("Enter")
+
+	screen := goterm.NewScreen(80, 24)
+	modal.Render(screen)
+
+	// The 50 column modal is centered, so its 46 column input starts at
+	// column 17 and the error is drawn on the line below it
+	var line []rune
+	for x := 17; x < 17+46; x++ {
+		line = append(line, screen.GetCell(x, 14).Ch)
+	}
+	got := string(line)
+	if !strings.HasPrefix(got, "répertoire introuvable: ééé") || !strings.HasSuffix(got, "é...") {
+		t.Errorf("error line = %q, want the message cut to 46 runes", got)
+	}
+}
+
 // TestComponentsRender tests that all components can render without panic
 func TestComponentsRender(t *testing.T) {
 	// Create a test screen
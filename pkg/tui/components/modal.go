@@ -38,6 +38,8 @@ type Modal struct {
 	okButton     *Button
 	cancelButton *Button
 	onClose      func(ModalResult)
+	validator    func(string) (string, error)
+	errorMsg     string
 	style        ModalStyle
 }
 
@@ -53,6 +55,7 @@ type ModalStyle struct {
 	BackdropBg goterm.Color
 	InputFg    goterm.Color
 	InputBg    goterm.Color
+	ErrorFg    goterm.Color
 }
 
 // DefaultModalStyle returns the default modal style
@@ -68,6 +71,7 @@ func DefaultModalStyle() ModalStyle {
 		BackdropBg: goterm.ColorRGB(0, 0, 0),
 		InputFg:    goterm.ColorRGB(255, 255, 255),
 		InputBg:    goterm.ColorRGB(30, 30, 30),
+		ErrorFg:    goterm.ColorRGB(255, 100, 100),
 	}
 }
 
@@ -102,7 +106,7 @@ func NewModal(title, message string, modalType ModalType, onClose func(ModalResu
 		})
 	} else {
 		m.okButton = NewButton("OK", 0, 0, func() {
-			m.Close(ModalResult{Confirmed: true, Input: m.input})
+			m.submit()
 		})
 		m.cancelButton = NewButton("Cancel", 0, 0, func() {
 			m.Close(ModalResult{Confirmed: false})
@@ -187,6 +191,34 @@ func (m *Modal) GetInput() string {
 	return m.input
 }
 
+// SetValidator sets a function that validates and normalizes input before
+// the modal is confirmed. If it returns an error the modal stays open and
+// the error is shown inline below the input field; otherwise the returned
+// value is passed to the close callback.
+func (m *Modal) SetValidator(validator func(string) (string, error)) {
+	m.validator = validator
+}
+
+// ErrorMessage returns the inline validation error, if any
+func (m *Modal) ErrorMessage() string {
+	return m.errorMsg
+}
+
+// submit validates the input and closes the modal on success
+func (m *Modal) submit() {
+	input := m.input
+	if m.validator != nil {
+		normalized, err := m.validator(input)
+		if err != nil {
+			m.errorMsg = err.Error()
+			return
+		}
+		input = normalized
+	}
+	m.errorMsg = ""
+	m.Close(ModalResult{Confirmed: true, Input: input})
+}
+
 // SetStyle sets the modal style
 func (m *Modal) SetStyle(style ModalStyle) {
 	m.style = style
@@ -340,6 +372,22 @@ func (m *Modal) drawInputField(screen *goterm.Screen, x, y int) {
 	if cursorX < inputX+inputWidth-1 {
 		screen.SetCell(cursorX, inputY, goterm.NewCell('_', fg, bg, goterm.StyleSlowBlink))
 	}
+
+	// Draw inline validation error on the line below the input, truncated
+	// by runes so multi-byte characters stay whole
+	if m.errorMsg != "" && inputWidth > 0 {
+		errText := []rune(m.errorMsg)
+		if len(errText) > inputWidth {
+			if inputWidth > 3 {
+				errText = append(errText[:inputWidth-3], '.', '.', '.')
+			} else {
+				errText = errText[:inputWidth]
+			}
+		}
+		for i, ch := range errText {
+			screen.SetCell(inputX+i, inputY+1, goterm.NewCell(ch, m.style.ErrorFg, m.style.MessageBg, goterm.StyleNone))
+		}
+	}
 }
 
 // drawButtons draws the modal buttons
@@ -385,6 +433,7 @@ func (m *Modal) HandleKey(key string) bool {
 	if m.modalType == ModalTypeInput {
 		switch key {
 		case "Backspace":
+			m.errorMsg = ""
 			if len(m.input) > 0 && m.cursorPos > 0 {
 				m.input = m.input[:m.cursorPos-1] + m.input[m.cursorPos:]
 				m.cursorPos--
@@ -414,6 +463,7 @@ func (m *Modal) HandleKey(key string) bool {
 		default:
 			// Regular character input
 			if len(key) == 1 {
+				m.errorMsg = ""
 				m.input = m.input[:m.cursorPos] + key + m.input[m.cursorPos:]
 				m.cursorPos++
 				return true
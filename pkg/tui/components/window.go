@@ -0,0 +1,97 @@
+package components
+
+import "fmt"
+
+// ListWindow tracks which rows of a long list are visible in a fixed-height
+// area. It scrolls just enough to keep the selected row on screen, so views
+// that draw their own rows can page through hundreds of items without
+// overflowing the screen.
+type ListWindow struct {
+	offset int
+}
+
+// NewListWindow creates a list window scrolled to the top
+func NewListWindow() *ListWindow {
+	return &ListWindow{}
+}
+
+// Visible returns the [start, end) range of rows to draw for a list of total
+// rows with the given selected row and height available rows.
+func (w *ListWindow) Visible(selected, total, height int) (start, end int) {
+	if total <= 0 || height <= 0 {
+		w.offset = 0
+		return 0, 0
+	}
+
+	if selected < 0 {
+		selected = 0
+	} else if selected >= total {
+		selected = total - 1
+	}
+
+	// Keep the selection inside the window
+	if selected < w.offset {
+		w.offset = selected
+	} else if selected >= w.offset+height {
+		w.offset = selected - height + 1
+	}
+
+	// Don't leave empty rows at the bottom when the list shrinks
+	if maxOffset := total - height; w.offset > maxOffset {
+		w.offset = maxOffset
+	}
+	if w.offset < 0 {
+		w.offset = 0
+	}
+
+	end = w.offset + height
+	if end > total {
+		end = total
+	}
+	return w.offset, end
+}
+
+// Offset returns the index of the first visible row
+func (w *ListWindow) Offset() int {
+	return w.offset
+}
+
+// Reset scrolls back to the top
+func (w *ListWindow) Reset() {
+	w.offset = 0
+}
+
+// PageStep returns the selection after moving one page (height rows) by
+// direction (-1 up, +1 down), clamped to the list bounds.
+func PageStep(selected, total, height, direction int) int {
+	if total <= 0 {
+		return 0
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	selected += direction * height
+	if selected < 0 {
+		return 0
+	}
+	if selected >= total {
+		return total - 1
+	}
+	return selected
+}
+
+// RangeIndicator formats a count indicator such as "21-40 of 57"; filtered
+// lists append the unfiltered total, e.g. "1-5 of 8 (57 total)".
+func RangeIndicator(start, end, shown, total int) string {
+	var indicator string
+	if shown == 0 {
+		indicator = "0 of 0"
+	} else {
+		indicator = fmt.Sprintf("%d-%d of %d", start+1, end, shown)
+	}
+	if shown != total {
+		indicator += fmt.Sprintf(" (%d total)", total)
+	}
+	return indicator
+}
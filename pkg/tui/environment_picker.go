@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// DefaultEnvironmentsPath returns environments.yaml in GOFLOW_CONFIG_DIR, or
+// in ~/.goflow when it is not set
+func DefaultEnvironmentsPath() string {
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "environments.yaml")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "environments.yaml")
+	}
+	return filepath.Join(homeDir, ".goflow", "environments.yaml")
+}
+
+// environmentFinderItems lists the environments in the config file at path
+// for the fuzzy finder, marking the default one
+func environmentFinderItems(path string) ([]FinderItem, error) {
+	envs, err := mcpserver.LoadEnvironments(path)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]FinderItem, 0, len(envs.Environments))
+	for _, name := range envs.Names() {
+		detail := fmt.Sprintf("%d server(s)", len(envs.Environments[name].Servers))
+		if name == envs.Default {
+			detail += "  (default)"
+		}
+		items = append(items, FinderItem{
+			Kind:   FinderEnvironment,
+			Label:  name,
+			Detail: detail,
+			Value:  name,
+		})
+	}
+	return items, nil
+}
+
+// useEnvironment makes the named environment in the config file at path
+// the default that workflow runs use
+func useEnvironment(path, name string) error {
+	envs, err := mcpserver.LoadEnvironments(path)
+	if err != nil {
+		return err
+	}
+	if err := envs.Use(name); err != nil {
+		return err
+	}
+	return envs.Save(path)
+}
+
+// registerEnvironmentCommand registers :environment, which picks the
+// environment workflow runs use
+func (a *App) registerEnvironmentCommand() error {
+	return a.commands.Register(Command{
+		Name:        "environment",
+		Usage:       "[name]",
+		Description: "Choose the server environment runs use",
+		MaxArgs:     1,
+		Run: func(args []string) error {
+			if len(args) == 1 {
+				return a.selectEnvironment(args[0])
+			}
+			items, err := environmentFinderItems(DefaultEnvironmentsPath())
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return fmt.Errorf("no environments defined; add one with: goflow environment set <name> <server-id>=<registered-id>")
+			}
+			a.finder.Open(items)
+			return nil
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			envs, err := mcpserver.LoadEnvironments(DefaultEnvironmentsPath())
+			if err != nil {
+				return nil
+			}
+			return envs.Names()
+		},
+	})
+}
+
+// selectEnvironment makes an environment the default and confirms it
+func (a *App) selectEnvironment(name string) error {
+	if err := useEnvironment(DefaultEnvironmentsPath(), name); err != nil {
+		return err
+	}
+	Notifications().Notify(NotifySuccess, "Runs now use environment %s", name)
+	return nil
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+func TestEnvironmentPicker(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOFLOW_CONFIG_DIR", dir)
+	path := DefaultEnvironmentsPath()
+	if path != filepath.Join(dir, "environments.yaml") {
+		t.Fatalf("DefaultEnvironmentsPath() = %s", path)
+	}
+
+	items, err := environmentFinderItems(path)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected no environments without a config file, got %v, %v", items, err)
+	}
+
+	config := "default: dev\nenvironments:\n  dev:\n    servers:\n      db: db-dev\n  prod:\n    servers:\n      db: db-prod\n"
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	items, err = environmentFinderItems(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Value != "dev" || items[1].Value != "prod" || items[0].Kind != FinderEnvironment {
+		t.Fatalf("unexpected finder items: %+v", items)
+	}
+	if !strings.Contains(items[0].Detail, "default") || strings.Contains(items[1].Detail, "default") {
+		t.Errorf("expected only dev marked as the default: %+v", items)
+	}
+
+	if err := useEnvironment(path, "staging"); err == nil {
+		t.Error("expected an unknown environment to be rejected")
+	}
+	if err := useEnvironment(path, "prod"); err != nil {
+		t.Fatal(err)
+	}
+	envs, err := mcpserver.LoadEnvironments(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envs.Default != "prod" {
+		t.Errorf("expected prod to be the default, got %q", envs.Default)
+	}
+}
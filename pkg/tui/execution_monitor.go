@@ -1,12 +1,13 @@
 package tui
 
 import (
+	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/dshills/goflow/pkg/audit"
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
 	execpkg "github.com/dshills/goflow/pkg/execution"
@@ -22,6 +23,12 @@ import (
 // - Execution log viewer with filtering
 // - Error detail view with stack traces
 // - Performance metrics display
+// - Scratchpad for evaluating expressions against the execution context
+// - Retry form for re-running a failed tool node with edited arguments
+// - Pausing between nodes to edit variables, with watch expressions
+// - Performance report, exportable as JSON and CSV
+// - Stopping the execution, with the reason recorded
+// - Approving or rejecting approval nodes waiting for a decision
 type ExecutionMonitor struct {
 	mu sync.RWMutex
 
@@ -42,9 +49,26 @@ type ExecutionMonitor struct {
 	errorPanel    *ErrorDetailPanel
 	metricsPanel  *MetricsPanel
 	helpView      *ExecutionHelpPanel
+	scratchPanel  *ScratchpadPanel
+	retryPanel    *RetryFormPanel
+	profilePanel  *ProfilePanel
+
+	// Manual node retry (nil disables the retry form)
+	retrier NodeRetrier
+
+	// Pausing between nodes (nil disables pausing and variable edits)
+	pauser ExecutionPauser
+
+	// Stopping the execution (nil disables X); stopArmed is set by the
+	// first X and confirmed by the second
+	canceller ExecutionCanceller
+	stopArmed bool
+
+	// Deciding approval nodes (nil disables a and n)
+	approver ExecutionApprover
 
 	// State
-	activePanel       string // "workflow", "variables", "logs", "error", "metrics", "help"
+	activePanel       string // "workflow", "variables", "logs", "error", "metrics", "help", "scratch", "retry", "profile"
 	lastAction        string
 	needsRefresh      bool
 	updatedComponents map[string]bool
@@ -55,6 +79,23 @@ type ExecutionMonitor struct {
 	height int
 }
 
+// ExecutionCanceller stops a running execution, recording the reason.
+// *execution.Engine implements it.
+type ExecutionCanceller interface {
+	Cancel(reason string) error
+}
+
+// monitorCancelReason is recorded for executions stopped from the monitor
+const monitorCancelReason = "stopped from the execution monitor"
+
+// ExecutionApprover lists and decides the approval nodes waiting for a
+// decision. *execution.Engine implements it.
+type ExecutionApprover interface {
+	PendingApprovals() []execpkg.PendingApproval
+	Approve(nodeID, by string) error
+	Reject(nodeID, by string) error
+}
+
 // NewExecutionMonitor creates a new execution monitor view.
 // It initializes all panels and subscribes to execution events.
 func NewExecutionMonitor(exec *execution.Execution, wf *workflow.Workflow, screen *goterm.Screen) *ExecutionMonitor {
@@ -106,6 +147,9 @@ func NewExecutionMonitor(exec *execution.Execution, wf *workflow.Workflow, scree
 	em.logPanel = NewLogViewerPanel(0, headerHeight+graphHeight, width, logHeight)
 	em.errorPanel = NewErrorDetailPanel(0, headerHeight, width, contentHeight)
 	em.helpView = NewExecutionHelpPanel(0, headerHeight, width, contentHeight)
+	em.scratchPanel = NewScratchpadPanel(0, headerHeight, width, contentHeight, NewScratchpad())
+	em.retryPanel = NewRetryFormPanel(0, headerHeight, width, contentHeight)
+	em.profilePanel = NewProfilePanel(0, headerHeight, width, contentHeight)
 
 	// Update panels with execution data
 	em.updatePanelsFromExecution()
@@ -150,9 +194,15 @@ func (em *ExecutionMonitor) handleEvent(event execpkg.ExecutionEvent) {
 		em.markUpdated("status", "metrics")
 	case execpkg.EventExecutionCompleted, execpkg.EventExecutionFailed, execpkg.EventExecutionCancelled:
 		em.markUpdated("status", "metrics", "logs")
+	case execpkg.EventExecutionPaused, execpkg.EventExecutionResumed,
+		execpkg.EventApprovalRequested, execpkg.EventApprovalDecided:
+		em.markUpdated("status", "logs")
 	case execpkg.EventNodeStarted, execpkg.EventNodeCompleted, execpkg.EventNodeFailed, execpkg.EventNodeSkipped:
 		em.workflowPanel.UpdateNodeStatus(event.NodeID, event.Status)
 		em.markUpdated("workflow", "logs", "metrics")
+	case execpkg.EventToolProgress:
+		em.workflowPanel.UpdateNodeProgress(event.NodeID, event.Metadata)
+		em.markUpdated("workflow", "logs")
 	case execpkg.EventVariableChanged:
 		em.variablePanel.UpdateVariables(event.Variables)
 		em.markUpdated("variables")
@@ -164,6 +214,12 @@ func (em *ExecutionMonitor) handleEvent(event execpkg.ExecutionEvent) {
 		em.markUpdated("metrics")
 	}
 
+	// Watches follow every event, not only variable changes
+	em.variablePanel.EvaluateWatches()
+	if em.activePanel == "profile" {
+		em.refreshProfile()
+	}
+
 	// Add log entry for this event
 	em.logPanel.AddEvent(event)
 
@@ -247,6 +303,9 @@ func (em *ExecutionMonitor) updatePanelsFromExecution() {
 	// Update metrics
 	em.updateMetrics()
 	updated["metrics"] = true
+	if em.activePanel == "profile" {
+		em.refreshProfile()
+	}
 
 	// Mark all updated components
 	for component := range updated {
@@ -298,6 +357,12 @@ func (em *ExecutionMonitor) Render() (time.Duration, error) {
 	// Render active panels based on view mode
 	if em.activePanel == "help" {
 		em.helpView.Render(em.screen)
+	} else if em.activePanel == "scratch" {
+		em.scratchPanel.Render(em.screen)
+	} else if em.activePanel == "retry" {
+		em.retryPanel.Render(em.screen)
+	} else if em.activePanel == "profile" {
+		em.profilePanel.Render(em.screen)
 	} else if em.activePanel == "error" && em.errorPanel.HasError() {
 		// Show error panel in full screen mode only if there's an error
 		em.errorPanel.Render(em.screen, true)
@@ -326,17 +391,31 @@ func (em *ExecutionMonitor) Render() (time.Duration, error) {
 
 // renderHeader draws the header section with execution info.
 func (em *ExecutionMonitor) renderHeader() {
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Title
 	title := fmt.Sprintf("Execution Monitor: %s", em.workflow.Name)
 	em.screen.DrawText(0, 0, title, fg, bg, goterm.StyleBold)
 
 	// Execution info
+	status := em.formatStatus(em.exec.Status)
+	if em.isPaused() {
+		status += " (paused)"
+	}
+	if em.stopArmed {
+		status += " | Press X again to stop the execution"
+	}
+	if approval, ok := em.pendingApproval(); ok {
+		status += fmt.Sprintf(" | Approval needed: %s", approval.NodeID)
+		if approval.Message != "" {
+			status += " - " + approval.Message
+		}
+		status += " (a: approve, n: reject)"
+	}
 	execInfo := fmt.Sprintf("ID: %s | Status: %s | Progress: %.0f%%",
 		em.exec.ID.String(),
-		em.formatStatus(em.exec.Status),
+		status,
 		em.metricsPanel.GetProgress().PercentComplete)
 	em.screen.DrawText(0, 1, execInfo, fg, bg, goterm.StyleNone)
 
@@ -347,14 +426,29 @@ func (em *ExecutionMonitor) renderHeader() {
 
 // renderStatusBar draws the status bar at the bottom.
 func (em *ExecutionMonitor) renderStatusBar() {
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
 	y := em.height - 1
 
-	status := fmt.Sprintf("[Tab: Switch] [j/k: Scroll] [e: Expand] [Esc: Back] [?: Help] | Active: %s",
+	status := fmt.Sprintf("[Tab: Switch] [j/k: Scroll] [e: Expand] [s: Scratchpad] [r: Retry] [Space: Pause] [X: Stop] [Esc: Back] [?: Help] | Active: %s",
 		em.activePanel)
+	if em.activePanel == "variables" && em.variablePanel.IsAddingWatch() {
+		status = "[Enter: Add watch] [$.path: JSONPath] [Esc: Cancel] | Active: variables"
+	} else if em.activePanel == "variables" && em.variablePanel.IsEditing() {
+		status = "[Enter: Set] [Esc: Cancel edit] | Active: variables"
+	} else if em.activePanel == "variables" {
+		status = "[j/k: Select] [e: Expand] [p: Pin] [w: Watch] [d: Unwatch] [Enter: Edit when paused] [Space: Pause] | Active: variables"
+	} else if em.activePanel == "scratch" {
+		status = "[Enter: Evaluate] [name = expr: Define] [:unset name] [:clear] [Esc: Back] | Active: scratch"
+	} else if em.activePanel == "retry" && em.retryPanel.IsEditing() {
+		status = "[Enter: Save] [Esc: Cancel edit] | Active: retry"
+	} else if em.activePanel == "retry" {
+		status = "[j/k: Select] [Enter: Edit] [r: Retry] [Esc: Back] | Active: retry"
+	} else if em.activePanel == "error" {
+		status = "[j/k: Scroll] [y: Copy details] [Tab: Switch] [Esc: Back] | Active: error"
+	} else if em.activePanel == "profile" {
+		status = "[j/k: Scroll] [x: Export JSON + CSV] [Esc: Back] | Active: profile"
+	}
 
-	em.screen.DrawText(0, y, status, fg, bg, goterm.StyleReverse)
+	drawStatusBar(em.screen, y, em.width, status, CurrentTheme().Foreground, goterm.StyleReverse)
 }
 
 // formatStatus returns a colored status string.
@@ -382,6 +476,33 @@ func (em *ExecutionMonitor) HandleKey(key rune) error {
 
 	em.lastAction = ""
 
+	// The scratchpad captures all typing until Esc
+	if em.activePanel == "scratch" {
+		em.handleScratchKey(key)
+		em.needsRefresh = true
+		return nil
+	}
+	if em.activePanel == "retry" {
+		em.handleRetryKey(key)
+		em.needsRefresh = true
+		return nil
+	}
+	if em.activePanel == "profile" {
+		em.handleProfileKey(key)
+		em.needsRefresh = true
+		return nil
+	}
+	if em.activePanel == "variables" && em.variablePanel.IsEditing() {
+		em.handleVariableInputKey(key)
+		em.needsRefresh = true
+		return nil
+	}
+
+	// X asks for confirmation; any other key disarms it
+	if key != 'X' {
+		em.stopArmed = false
+	}
+
 	switch key {
 	case '\t': // Tab
 		em.switchPanel(true)
@@ -409,6 +530,41 @@ func (em *ExecutionMonitor) HandleKey(key rune) error {
 			em.activePanel = "help"
 		}
 		em.lastAction = "show_help"
+	case 's':
+		em.activePanel = "scratch"
+		em.lastAction = "show_scratch"
+	case 'r':
+		em.openRetryForm()
+	case 'P':
+		em.openProfile()
+	case 'y':
+		em.copyErrorDetails()
+	case ' ':
+		em.togglePause()
+	case 'X':
+		em.stopExecution()
+	case 'a':
+		em.decideApproval(true)
+	case 'n':
+		em.decideApproval(false)
+	case 'p':
+		if em.activePanel == "variables" {
+			em.variablePanel.TogglePin()
+			em.lastAction = "pin"
+		}
+	case 'w':
+		if em.activePanel == "variables" {
+			em.variablePanel.BeginWatch()
+			em.lastAction = "watch"
+		}
+	case 'd':
+		if em.activePanel == "variables" && em.variablePanel.RemoveWatch() {
+			em.lastAction = "unwatch"
+		}
+	case '\r', '\n': // Enter
+		if em.activePanel == "variables" {
+			em.beginVariableEdit()
+		}
 	case 'q':
 		// Quit handled by app layer
 		em.lastAction = "quit"
@@ -418,6 +574,348 @@ func (em *ExecutionMonitor) HandleKey(key rune) error {
 	return nil
 }
 
+// copyErrorDetails copies the execution error's details to the clipboard.
+// Without a system clipboard they are kept in the in-app register.
+func (em *ExecutionMonitor) copyErrorDetails() {
+	if !em.errorPanel.HasError() {
+		em.lastAction = "copy_unavailable"
+		return
+	}
+	if err := Clipboard().Copy(em.errorPanel.DetailsText()); err != nil {
+		em.lastAction = "copy_register"
+		return
+	}
+	em.lastAction = "copy_error"
+}
+
+// handleScratchKey edits and submits the scratchpad input line.
+func (em *ExecutionMonitor) handleScratchKey(key rune) {
+	switch key {
+	case 27: // Esc
+		em.activePanel = "workflow"
+		em.lastAction = "close"
+	case '\r', '\n': // Enter
+		var base map[string]interface{}
+		if em.exec != nil && em.exec.Context != nil {
+			base = em.exec.Context.GetVariableSnapshot()
+		}
+		em.scratchPanel.Submit(base)
+		em.lastAction = "evaluate"
+	case 127, '\b': // Backspace
+		em.scratchPanel.Backspace()
+		em.lastAction = "edit"
+	default:
+		if key >= ' ' {
+			em.scratchPanel.TypeRune(key)
+			em.lastAction = "edit"
+		}
+	}
+}
+
+// openRetryForm opens the retry form for the most recent failed tool node.
+func (em *ExecutionMonitor) openRetryForm() {
+	if em.retrier == nil || em.exec == nil || em.exec.Status == execution.StatusRunning {
+		em.lastAction = "retry_unavailable"
+		return
+	}
+	failed, toolNode := latestFailedToolNode(em.exec, em.workflow)
+	if failed == nil {
+		em.lastAction = "retry_unavailable"
+		return
+	}
+
+	em.retryPanel.Load(failed, toolNode.ToolName)
+	em.activePanel = "retry"
+	em.lastAction = "show_retry"
+}
+
+// handleRetryKey navigates and edits the retry form and runs the retry.
+func (em *ExecutionMonitor) handleRetryKey(key rune) {
+	if em.retryPanel.IsEditing() {
+		switch key {
+		case 27: // Esc
+			em.retryPanel.CancelEdit()
+		case '\r', '\n': // Enter
+			em.retryPanel.CommitEdit()
+		case 127, '\b': // Backspace
+			em.retryPanel.Backspace()
+		default:
+			if key >= ' ' {
+				em.retryPanel.TypeRune(key)
+			}
+		}
+		em.lastAction = "edit"
+		return
+	}
+
+	switch key {
+	case 27: // Esc
+		em.activePanel = "workflow"
+		em.lastAction = "close"
+	case 'j':
+		em.retryPanel.Move(1)
+		em.lastAction = "select"
+	case 'k':
+		em.retryPanel.Move(-1)
+		em.lastAction = "select"
+	case '\r', '\n': // Enter
+		em.retryPanel.BeginEdit()
+		em.lastAction = "edit"
+	case 'r':
+		em.runRetry()
+		em.lastAction = "retry"
+	}
+}
+
+// runRetry retries the form's node with the entered arguments and adds the
+// new attempt to the panels.
+func (em *ExecutionMonitor) runRetry() {
+	args, err := em.retryPanel.Arguments()
+	if err != nil {
+		em.retryPanel.SetResult(nil, err)
+		return
+	}
+
+	nodeExec, err := em.retrier.RetryNode(context.Background(), em.workflow, em.exec, em.retryPanel.NodeID(), args)
+	em.retryPanel.SetResult(nodeExec, err)
+	if err != nil {
+		return
+	}
+
+	em.workflowPanel.UpdateNodeStatus(nodeExec.NodeID, nodeExec.Status)
+	em.logPanel.AddNodeExecution(nodeExec)
+	if em.exec.Context != nil {
+		em.variablePanel.UpdateVariables(em.exec.Context.GetVariableSnapshot())
+	}
+	em.updateMetrics()
+	em.markUpdated("workflow", "logs", "variables", "metrics")
+}
+
+// openProfile shows the performance report of the execution so far.
+func (em *ExecutionMonitor) openProfile() {
+	if err := em.profilePanel.Load(em.liveExecution()); err != nil {
+		em.lastAction = "profile_unavailable"
+		return
+	}
+	em.profilePanel.SetMessage("", false)
+	em.activePanel = "profile"
+	em.lastAction = "show_profile"
+}
+
+// refreshProfile rebuilds the shown report as the execution goes on.
+func (em *ExecutionMonitor) refreshProfile() {
+	if live := em.liveExecution(); live != nil {
+		_ = em.profilePanel.Load(live) // Only fails without an execution
+		em.markUpdated("profile")
+	}
+}
+
+// handleProfileKey scrolls and exports the performance report.
+func (em *ExecutionMonitor) handleProfileKey(key rune) {
+	switch key {
+	case 27: // Esc
+		em.activePanel = "workflow"
+		em.lastAction = "close"
+	case 'j':
+		em.profilePanel.Scroll(1)
+		em.lastAction = "scroll"
+	case 'k':
+		em.profilePanel.Scroll(-1)
+		em.lastAction = "scroll"
+	case 'x':
+		if _, err := em.profilePanel.Export(execpkg.DefaultReportsDir()); err != nil {
+			em.lastAction = "export_failed"
+			return
+		}
+		em.lastAction = "export"
+	}
+}
+
+// isPaused reports whether the execution is paused between nodes.
+func (em *ExecutionMonitor) isPaused() bool {
+	return em.pauser != nil && em.pauser.IsPaused()
+}
+
+// togglePause pauses the execution before its next node, or resumes it.
+func (em *ExecutionMonitor) togglePause() {
+	live := em.liveExecution()
+	switch {
+	case em.pauser == nil:
+		em.lastAction = "pause_unavailable"
+	case em.pauser.IsPaused():
+		em.variablePanel.CloseInput()
+		em.pauser.Resume()
+		em.lastAction = "resume"
+	case live == nil || (live.Status != execution.StatusPending && live.Status != execution.StatusRunning):
+		em.lastAction = "pause_unavailable"
+	default:
+		em.pauser.Pause()
+		em.lastAction = "pause"
+	}
+	em.markUpdated("status")
+}
+
+// stopExecution arms the stop on the first X and cancels the execution on
+// the second. Running nodes and MCP calls are abandoned.
+func (em *ExecutionMonitor) stopExecution() {
+	live := em.liveExecution()
+	if em.canceller == nil || live == nil || (live.Status != execution.StatusPending && live.Status != execution.StatusRunning) {
+		em.stopArmed = false
+		em.lastAction = "stop_unavailable"
+		return
+	}
+	if !em.stopArmed {
+		em.stopArmed = true
+		em.lastAction = "confirm_stop"
+		em.markUpdated("status")
+		return
+	}
+
+	em.stopArmed = false
+	if err := em.canceller.Cancel(monitorCancelReason); err != nil {
+		em.lastAction = "stop_unavailable"
+		return
+	}
+	em.lastAction = "stop"
+	em.markUpdated("status")
+}
+
+// pendingApproval returns the oldest approval waiting for a decision
+func (em *ExecutionMonitor) pendingApproval() (execpkg.PendingApproval, bool) {
+	if em.approver == nil {
+		return execpkg.PendingApproval{}, false
+	}
+	pending := em.approver.PendingApprovals()
+	if len(pending) == 0 {
+		return execpkg.PendingApproval{}, false
+	}
+	return pending[0], true
+}
+
+// decideApproval approves or rejects the oldest pending approval
+func (em *ExecutionMonitor) decideApproval(approved bool) {
+	approval, ok := em.pendingApproval()
+	if !ok {
+		em.lastAction = "approval_unavailable"
+		return
+	}
+
+	// The user running the monitor is recorded as the decider
+	nodeID, by := string(approval.NodeID), audit.LocalActor()
+	var err error
+	if approved {
+		err = em.approver.Approve(nodeID, by)
+	} else {
+		err = em.approver.Reject(nodeID, by)
+	}
+	switch {
+	case err != nil:
+		em.lastAction = "approval_unavailable"
+	case approved:
+		em.lastAction = "approve"
+	default:
+		em.lastAction = "reject"
+	}
+	em.markUpdated("status")
+}
+
+// beginVariableEdit opens the selected variable for editing; values can
+// only change while the execution is paused.
+func (em *ExecutionMonitor) beginVariableEdit() {
+	if !em.isPaused() || em.liveContext() == nil {
+		em.variablePanel.SetMessage("pause the execution (Space) to edit variables")
+		em.lastAction = "edit_unavailable"
+		return
+	}
+	if err := em.variablePanel.BeginEdit(); err != nil {
+		em.variablePanel.SetMessage(err.Error())
+		em.lastAction = "edit_unavailable"
+		return
+	}
+	em.lastAction = "edit"
+}
+
+// handleVariableInputKey edits the inspector's input line and applies it.
+func (em *ExecutionMonitor) handleVariableInputKey(key rune) {
+	switch key {
+	case 27: // Esc
+		em.variablePanel.CloseInput()
+		em.lastAction = "cancel"
+	case '\r', '\n': // Enter
+		if em.variablePanel.IsAddingWatch() {
+			em.addWatch()
+		} else {
+			em.commitVariableEdit()
+		}
+	case 127, '\b': // Backspace
+		em.variablePanel.Backspace()
+		em.lastAction = "edit"
+	default:
+		if key >= ' ' {
+			em.variablePanel.TypeRune(key)
+			em.lastAction = "edit"
+		}
+	}
+}
+
+// addWatch adds the watch expression typed on the input line.
+func (em *ExecutionMonitor) addWatch() {
+	if err := em.variablePanel.AddWatch(em.variablePanel.buffer); err != nil {
+		em.variablePanel.SetMessage(err.Error())
+		em.lastAction = "watch_invalid"
+		return
+	}
+	em.variablePanel.CloseInput()
+	em.markUpdated("variables")
+	em.lastAction = "add_watch"
+}
+
+// commitVariableEdit writes the edited value into the paused execution's
+// context, where the next node will see it.
+func (em *ExecutionMonitor) commitVariableEdit() {
+	ctx := em.liveContext()
+	if !em.isPaused() || ctx == nil {
+		em.variablePanel.CloseInput()
+		em.variablePanel.SetMessage("the execution resumed before the edit was saved")
+		em.lastAction = "edit_unavailable"
+		return
+	}
+	name, value, err := em.variablePanel.EditedValue()
+	if err == nil {
+		err = ctx.SetVariable(name, value)
+	}
+	if err != nil {
+		em.variablePanel.SetMessage(err.Error())
+		em.lastAction = "edit_invalid"
+		return
+	}
+
+	em.variablePanel.CloseInput()
+	em.variablePanel.SetMessage("")
+	em.variablePanel.UpdateVariables(ctx.GetVariableSnapshot())
+	em.markUpdated("variables")
+	em.lastAction = "set_variable"
+}
+
+// liveExecution returns the execution being run: the engine's, when
+// following one, since the monitor may have been given a placeholder.
+func (em *ExecutionMonitor) liveExecution() *execution.Execution {
+	if em.eventMonitor != nil {
+		if state := em.eventMonitor.GetExecutionState(); state != nil {
+			return state
+		}
+	}
+	return em.exec
+}
+
+// liveContext returns the live execution's context, if any.
+func (em *ExecutionMonitor) liveContext() *execution.ExecutionContext {
+	if live := em.liveExecution(); live != nil {
+		return live.Context
+	}
+	return nil
+}
+
 // switchPanel switches to the next or previous panel.
 func (em *ExecutionMonitor) switchPanel(forward bool) {
 	panels := []string{"workflow", "variables", "logs", "metrics"}
@@ -479,6 +977,69 @@ func (em *ExecutionMonitor) GetMetricsPanel() *MetricsPanel {
 	return em.metricsPanel
 }
 
+// GetScratchpad returns the scratchpad backing the scratch panel.
+func (em *ExecutionMonitor) GetScratchpad() *Scratchpad {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return em.scratchPanel.scratchpad
+}
+
+// SetScratchpad replaces the scratchpad, e.g. to keep scratch variables
+// across monitors within one session.
+func (em *ExecutionMonitor) SetScratchpad(scratchpad *Scratchpad) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	if scratchpad == nil {
+		scratchpad = NewScratchpad()
+	}
+	em.scratchPanel.scratchpad = scratchpad
+}
+
+// SetNodeRetrier enables the retry form, which re-runs failed tool nodes
+// through retrier (typically the engine that ran the execution).
+func (em *ExecutionMonitor) SetNodeRetrier(retrier NodeRetrier) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.retrier = retrier
+}
+
+// SetPauser enables pausing the execution between nodes (Space), and
+// editing variables while it is paused.
+func (em *ExecutionMonitor) SetPauser(pauser ExecutionPauser) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.pauser = pauser
+}
+
+// SetCanceller enables stopping the execution from the monitor (X, twice).
+func (em *ExecutionMonitor) SetCanceller(canceller ExecutionCanceller) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.canceller = canceller
+}
+
+// SetApprover enables approving (a) and rejecting (n) approval nodes that
+// wait for a decision.
+func (em *ExecutionMonitor) SetApprover(approver ExecutionApprover) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.approver = approver
+}
+
+// GetProfile returns the performance report panel.
+func (em *ExecutionMonitor) GetProfile() *ProfilePanel {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return em.profilePanel
+}
+
+// GetRetryForm returns the retry form panel.
+func (em *ExecutionMonitor) GetRetryForm() *RetryFormPanel {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return em.retryPanel
+}
+
 func (em *ExecutionMonitor) SetActivePanel(panel string) {
 	em.mu.Lock()
 	defer em.mu.Unlock()
@@ -537,6 +1098,7 @@ type WorkflowGraphPanel struct {
 	x, y, width, height int
 	workflow            *workflow.Workflow
 	nodeStatuses        map[types.NodeID]interface{} // execution.NodeStatus or execution.Status
+	nodeProgress        map[types.NodeID]string      // latest tool progress of running nodes
 	currentNode         types.NodeID
 }
 
@@ -548,6 +1110,7 @@ func NewWorkflowGraphPanel(x, y, width, height int, wf *workflow.Workflow) *Work
 		height:       height,
 		workflow:     wf,
 		nodeStatuses: make(map[types.NodeID]interface{}),
+		nodeProgress: make(map[types.NodeID]string),
 	}
 }
 
@@ -555,9 +1118,32 @@ func (p *WorkflowGraphPanel) UpdateNodeStatus(nodeID types.NodeID, status interf
 	p.nodeStatuses[nodeID] = status
 	if status == execution.NodeStatusRunning {
 		p.currentNode = nodeID
+	} else {
+		delete(p.nodeProgress, nodeID)
 	}
 }
 
+// UpdateNodeProgress records the latest progress a running tool node
+// reported; it is shown under the node until the node finishes.
+func (p *WorkflowGraphPanel) UpdateNodeProgress(nodeID types.NodeID, metadata map[string]interface{}) {
+	p.nodeProgress[nodeID] = formatToolProgress(metadata)
+}
+
+// formatToolProgress describes a tool.progress event's metadata, e.g.
+// "3/10 (30%) fetching page 3"
+func formatToolProgress(metadata map[string]interface{}) string {
+	progress, _ := metadata["progress"].(float64)
+	total, _ := metadata["total"].(float64)
+	text := fmt.Sprintf("%g", progress)
+	if total > 0 {
+		text = fmt.Sprintf("%g/%g (%d%%)", progress, total, int(progress/total*100))
+	}
+	if message, ok := metadata["message"].(string); ok && message != "" {
+		text += " " + message
+	}
+	return text
+}
+
 func (p *WorkflowGraphPanel) IsNodeHighlighted(nodeID types.NodeID) bool {
 	_, exists := p.nodeStatuses[nodeID]
 	return exists
@@ -589,8 +1175,8 @@ func (p *WorkflowGraphPanel) GetNodeHighlightStyle(nodeID types.NodeID) string {
 }
 
 func (p *WorkflowGraphPanel) Render(screen *goterm.Screen, active bool) {
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Border
 	titleStyle := goterm.StyleBold
@@ -634,8 +1220,8 @@ func (p *WorkflowGraphPanel) renderNodeTree(screen *goterm.Screen, node workflow
 	}
 	rendered[nodeID] = true
 
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Get node symbol and style
 	symbol, style := p.getNodeSymbol(types.NodeID(nodeID))
@@ -649,6 +1235,12 @@ func (p *WorkflowGraphPanel) renderNodeTree(screen *goterm.Screen, node workflow
 	screen.DrawText(p.x+1, y, line, fg, bg, style)
 	y++
 
+	// Show the latest progress of a running tool
+	if progress, ok := p.nodeProgress[types.NodeID(nodeID)]; ok && y < p.y+p.height-1 {
+		screen.DrawText(p.x+1, y, fmt.Sprintf("%s  Progress: %s", prefix, progress), fg, bg, goterm.StyleDim)
+		y++
+	}
+
 	// Show additional details for parallel and loop nodes
 	if parallelNode, ok := node.(*workflow.ParallelNode); ok && y < p.y+p.height-1 {
 		details := fmt.Sprintf("%s  Strategy: %s", prefix, parallelNode.MergeStrategy)
@@ -706,10 +1298,22 @@ func (p *WorkflowGraphPanel) getNodeType(node workflow.Node) string {
 		return "transform"
 	case *workflow.ConditionNode:
 		return "condition"
+	case *workflow.SwitchNode:
+		return fmt.Sprintf("switch[%d cases]", len(n.Cases))
 	case *workflow.LoopNode:
 		return fmt.Sprintf("loop[%d nodes]", len(n.Body))
 	case *workflow.ParallelNode:
 		return fmt.Sprintf("parallel[%d branches]", len(n.Branches))
+	case *workflow.TryNode:
+		return fmt.Sprintf("try[%d nodes]", len(n.Body))
+	case *workflow.CatchNode:
+		return "catch"
+	case *workflow.DelayNode:
+		return "delay"
+	case *workflow.ApprovalNode:
+		return "approval"
+	case *workflow.BatchToolNode:
+		return "batch"
 	default:
 		return "unknown"
 	}
@@ -730,166 +1334,4 @@ func (p *WorkflowGraphPanel) findChildren(nodeID types.NodeID) []workflow.Node {
 	return children
 }
 
-// VariableInspectorPanel displays workflow variables with expansion.
-type VariableInspectorPanel struct {
-	x, y, width, height int
-	variables           map[string]interface{}
-	expandedVars        map[string]bool
-	scrollOffset        int
-	selectedIdx         int
-}
-
-func NewVariableInspectorPanel(x, y, width, height int) *VariableInspectorPanel {
-	return &VariableInspectorPanel{
-		x:            x,
-		y:            y,
-		width:        width,
-		height:       height,
-		variables:    make(map[string]interface{}),
-		expandedVars: make(map[string]bool),
-	}
-}
-
-func (p *VariableInspectorPanel) UpdateVariables(vars map[string]interface{}) {
-	p.variables = vars
-}
-
-func (p *VariableInspectorPanel) ToggleExpand() {
-	varNames := p.getSortedVarNames()
-	if p.selectedIdx < len(varNames) {
-		varName := varNames[p.selectedIdx]
-		p.expandedVars[varName] = !p.expandedVars[varName]
-	}
-}
-
-func (p *VariableInspectorPanel) Scroll(delta int) {
-	p.scrollOffset += delta
-	if p.scrollOffset < 0 {
-		p.scrollOffset = 0
-	}
-}
-
-func (p *VariableInspectorPanel) IsVisible() bool {
-	return true
-}
-
-func (p *VariableInspectorPanel) GetDisplayedVariables() map[string]interface{} {
-	return p.variables
-}
-
-func (p *VariableInspectorPanel) Render(screen *goterm.Screen, active bool) {
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
-
-	// Border
-	titleStyle := goterm.StyleBold
-	if active {
-		titleStyle = goterm.StyleReverse
-	}
-	screen.DrawText(p.x, p.y, "┌─ Variables ", fg, bg, titleStyle)
-	screen.DrawText(p.x+12, p.y, strings.Repeat("─", p.width-13)+"┐", fg, bg, goterm.StyleNone)
-
-	y := p.y + 1
-	varNames := p.getSortedVarNames()
-
-	for i, name := range varNames {
-		if i < p.scrollOffset {
-			continue
-		}
-		if y >= p.y+p.height-1 {
-			break
-		}
-
-		value := p.variables[name]
-		valueStr := p.formatValue(value)
-
-		line := fmt.Sprintf("  %s = %s", name, valueStr)
-		if len(line) > p.width-2 {
-			line = line[:p.width-5] + "..."
-		}
-
-		screen.DrawText(p.x+1, y, line, fg, bg, goterm.StyleNone)
-		y++
-
-		// Show expanded view if toggled
-		if p.expandedVars[name] {
-			expandedLines := p.formatExpanded(value)
-			for _, expLine := range expandedLines {
-				if y >= p.y+p.height-1 {
-					break
-				}
-				screen.DrawText(p.x+3, y, expLine, fg, bg, goterm.StyleDim)
-				y++
-			}
-		}
-	}
-
-	// Bottom border
-	if y < p.y+p.height {
-		screen.DrawText(p.x, p.y+p.height-1, "└"+strings.Repeat("─", p.width-2)+"┘", fg, bg, goterm.StyleNone)
-	}
-}
-
-func (p *VariableInspectorPanel) getSortedVarNames() []string {
-	names := make([]string, 0, len(p.variables))
-	for name := range p.variables {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-	return names
-}
-
-func (p *VariableInspectorPanel) formatValue(value interface{}) string {
-	if value == nil {
-		return "null"
-	}
-
-	switch v := value.(type) {
-	case string:
-		if len(v) > 30 {
-			return fmt.Sprintf("%q...", v[:30])
-		}
-		return fmt.Sprintf("%q", v)
-	case []interface{}:
-		return fmt.Sprintf("[%d items]", len(v))
-	case map[string]interface{}:
-		return fmt.Sprintf("{%d fields}", len(v))
-	default:
-		return fmt.Sprintf("%v", v)
-	}
-}
-
-func (p *VariableInspectorPanel) formatExpanded(value interface{}) []string {
-	var lines []string
-
-	switch v := value.(type) {
-	case []interface{}:
-		for i, item := range v {
-			if i >= 10 { // Limit to 10 items
-				lines = append(lines, fmt.Sprintf("  ... and %d more", len(v)-10))
-				break
-			}
-			lines = append(lines, fmt.Sprintf("  [%d]: %v", i, item))
-		}
-	case map[string]interface{}:
-		keys := make([]string, 0, len(v))
-		for k := range v {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-
-		for i, k := range keys {
-			if i >= 10 {
-				lines = append(lines, fmt.Sprintf("  ... and %d more", len(keys)-10))
-				break
-			}
-			lines = append(lines, fmt.Sprintf("  %s: %v", k, v[k]))
-		}
-	default:
-		lines = append(lines, fmt.Sprintf("  %v", value))
-	}
-
-	return lines
-}
-
 // Continue in next chunk...
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -56,6 +57,42 @@ func (p *LogViewerPanel) AddEvent(event execpkg.ExecutionEvent) {
 	case execpkg.EventExecutionFailed:
 		entry.Level = "error"
 		entry.Message = "Execution failed"
+	case execpkg.EventExecutionPaused:
+		entry.Level = "info"
+		entry.Message = "Execution paused"
+		if node, ok := event.Metadata["current_node"].(string); ok {
+			entry.Message += fmt.Sprintf(" (finishing '%s')", node)
+		}
+	case execpkg.EventExecutionResumed:
+		entry.Level = "info"
+		entry.Message = "Execution resumed"
+	case execpkg.EventApprovalRequested:
+		entry.Level = "warn"
+		entry.Message = fmt.Sprintf("Node '%s' waiting for approval", event.NodeID)
+		if message, ok := event.Metadata["message"].(string); ok && message != "" {
+			entry.Message += ": " + message
+		}
+	case execpkg.EventApprovalDecided:
+		entry.Level = "info"
+		approved, _ := event.Metadata["approved"].(bool)
+		by, _ := event.Metadata["by"].(string)
+		switch timedOut, _ := event.Metadata["timed_out"].(bool); {
+		case timedOut && approved:
+			entry.Message = fmt.Sprintf("Node '%s' approval timed out, approved by default", event.NodeID)
+		case timedOut:
+			entry.Message = fmt.Sprintf("Node '%s' approval timed out, rejected by default", event.NodeID)
+		case approved:
+			entry.Message = fmt.Sprintf("Node '%s' approved by %s", event.NodeID, by)
+		default:
+			entry.Level = "warn"
+			entry.Message = fmt.Sprintf("Node '%s' rejected by %s", event.NodeID, by)
+		}
+	case execpkg.EventExecutionCancelled:
+		entry.Level = "error"
+		entry.Message = "Execution cancelled"
+		if reason, ok := event.Metadata["reason"].(string); ok && reason != "" {
+			entry.Message += ": " + reason
+		}
 	case execpkg.EventNodeStarted:
 		entry.Level = "info"
 		// Check if this is a loop or parallel node for special formatting
@@ -87,6 +124,12 @@ func (p *LogViewerPanel) AddEvent(event execpkg.ExecutionEvent) {
 	case execpkg.EventNodeFailed:
 		entry.Level = "error"
 		entry.Message = fmt.Sprintf("Node '%s' failed", event.NodeID)
+	case execpkg.EventToolProgress:
+		entry.Level = "debug"
+		entry.Message = fmt.Sprintf("Node '%s' progress: %s", event.NodeID, formatToolProgress(event.Metadata))
+		if text, ok := event.Metadata["text"].(string); ok {
+			entry.Message += fmt.Sprintf(" (+%d chars)", len(text))
+		}
 	case execpkg.EventVariableChanged:
 		entry.Level = "debug"
 		entry.Message = "Variables updated"
@@ -107,22 +150,28 @@ func (p *LogViewerPanel) AddEvent(event execpkg.ExecutionEvent) {
 }
 
 func (p *LogViewerPanel) AddNodeExecution(nodeExec *execution.NodeExecution) {
+	// Manual retries are logged as a distinct attempt
+	attempt := ""
+	if nodeExec.IsManualRetry() {
+		attempt = fmt.Sprintf(" (manual retry #%d)", nodeExec.RetryCount)
+	}
+
 	// Add start event
 	p.entries = append(p.entries, LogEntry{
 		Timestamp: nodeExec.StartedAt,
 		Level:     "info",
 		NodeID:    nodeExec.NodeID,
-		Message:   fmt.Sprintf("Node '%s' started", nodeExec.NodeID),
+		Message:   fmt.Sprintf("Node '%s' started%s", nodeExec.NodeID, attempt),
 	})
 
 	// Add completion event if finished
 	if !nodeExec.CompletedAt.IsZero() {
 		level := "info"
-		message := fmt.Sprintf("Node '%s' completed (%.2fs)", nodeExec.NodeID, nodeExec.Duration().Seconds())
+		message := fmt.Sprintf("Node '%s' completed%s (%.2fs)", nodeExec.NodeID, attempt, nodeExec.Duration().Seconds())
 
 		if nodeExec.Status == execution.NodeStatusFailed {
 			level = "error"
-			message = fmt.Sprintf("Node '%s' failed", nodeExec.NodeID)
+			message = fmt.Sprintf("Node '%s' failed%s", nodeExec.NodeID, attempt)
 		}
 
 		p.entries = append(p.entries, LogEntry{
@@ -182,8 +231,8 @@ func (p *LogViewerPanel) GetLogEntries() []LogEntry {
 }
 
 func (p *LogViewerPanel) Render(screen *goterm.Screen, active bool) {
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Border
 	titleStyle := goterm.StyleBold
@@ -246,6 +295,8 @@ func (p *LogViewerPanel) getLevelIcon(level string) string {
 		return "✗"
 	case "info":
 		return "▶"
+	case "warn":
+		return "⚠"
 	case "debug":
 		return "◆"
 	default:
@@ -289,14 +340,49 @@ func (p *ErrorDetailPanel) Scroll(delta int) {
 	}
 }
 
+// DetailsText returns the error's details as plain text, for copying into
+// an issue or a chat: type, message, node, time, context and stack trace
+func (p *ErrorDetailPanel) DetailsText() string {
+	if p.error == nil {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Type: %s\n", p.error.Type)
+	fmt.Fprintf(&b, "Message: %s\n", p.error.Message)
+	if p.error.NodeID != "" {
+		fmt.Fprintf(&b, "Node: %s\n", p.error.NodeID)
+	}
+	if !p.error.Timestamp.IsZero() {
+		fmt.Fprintf(&b, "Time: %s\n", p.error.Timestamp.Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "Recoverable: %t\n", p.error.Recoverable)
+	if len(p.error.Context) > 0 {
+		keys := make([]string, 0, len(p.error.Context))
+		for key := range p.error.Context {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b.WriteString("Context:\n")
+		for _, key := range keys {
+			fmt.Fprintf(&b, "  %s: %v\n", key, p.error.Context[key])
+		}
+	}
+	if p.error.StackTrace != "" {
+		b.WriteString("Stack trace:\n")
+		b.WriteString(strings.TrimRight(p.error.StackTrace, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 // Render draws the full error detail panel (full screen mode)
 func (p *ErrorDetailPanel) Render(screen *goterm.Screen, active bool) {
 	if p.error == nil {
 		return
 	}
 
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Border
 	titleStyle := goterm.StyleBold
@@ -377,8 +463,8 @@ func (p *ErrorDetailPanel) RenderInline(screen *goterm.Screen) {
 
 	// Get screen dimensions to place error at bottom
 	width, height := screen.Size()
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Draw error notification at the bottom (above status bar)
 	y := height - 3
@@ -392,10 +478,10 @@ func (p *ErrorDetailPanel) RenderInline(screen *goterm.Screen) {
 
 	// Show node ID if available
 	if p.error.NodeID != "" {
-		nodeLine := fmt.Sprintf("  Node: %s | Press 'e' for details", p.error.NodeID)
+		nodeLine := fmt.Sprintf("  Node: %s | Press 'e' for details, 'y' to copy them", p.error.NodeID)
 		screen.DrawText(0, y+1, nodeLine, fg, bg, goterm.StyleDim)
 	} else {
-		helpLine := "  Press 'e' for error details"
+		helpLine := "  Press 'e' for error details, 'y' to copy them"
 		screen.DrawText(0, y+1, helpLine, fg, bg, goterm.StyleDim)
 	}
 }
@@ -481,8 +567,8 @@ func (p *MetricsPanel) GetMetrics() map[string]interface{} {
 }
 
 func (p *MetricsPanel) Render(screen *goterm.Screen, active bool) {
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Border
 	titleStyle := goterm.StyleBold
@@ -615,8 +701,8 @@ func NewExecutionHelpPanel(x, y, width, height int) *ExecutionHelpPanel {
 }
 
 func (p *ExecutionHelpPanel) Render(screen *goterm.Screen) {
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 
 	// Border
 	screen.DrawText(p.x, p.y, "┌─ Help ", fg, bg, goterm.StyleBold)
@@ -632,6 +718,16 @@ func (p *ExecutionHelpPanel) Render(screen *goterm.Screen) {
 		{"Shift+Tab", "Switch backward"},
 		{"j / k", "Scroll down / up"},
 		{"e", "Expand variable details"},
+		{"Space", "Pause before the next node / resume"},
+		{"X X", "Stop the execution"},
+		{"a / n", "Approve / reject the oldest pending approval"},
+		{"Enter", "Edit the selected variable (while paused)"},
+		{"p", "Pin the selected variable to the top"},
+		{"w / d", "Add a watch expression / remove the selected one"},
+		{"s", "Open scratchpad (evaluate expressions)"},
+		{"r", "Retry the failed tool node (edit arguments)"},
+		{"P", "Performance report (x exports JSON and CSV)"},
+		{"y", "Copy the error details to the clipboard"},
 		{"Esc", "Close help or error view"},
 		{"?", "Toggle help"},
 		{"q", "Quit monitor"},
@@ -659,9 +755,12 @@ func (p *ExecutionHelpPanel) Render(screen *goterm.Screen) {
 		desc string
 	}{
 		{"Workflow", "Shows execution progress through workflow graph"},
-		{"Variables", "Displays current variable values"},
+		{"Variables", "Displays watch expressions and current variable values"},
 		{"Metrics", "Shows performance and progress metrics"},
 		{"Logs", "Chronological execution events"},
+		{"Scratchpad", "Session-only variables and expression evaluation"},
+		{"Retry", "Re-run a failed tool node with edited arguments"},
+		{"Performance", "Time per node, MCP calls vs. transforms, retries, payloads"},
 	}
 
 	for _, panel := range panels {
@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	execpkg "github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goterm"
+)
+
+// ProfilePanel shows an execution's performance report: where the time
+// went, node by node, and how much of it was spent on MCP calls.
+type ProfilePanel struct {
+	x, y, width, height int
+	report              *execpkg.PerformanceReport
+	scrollOffset        int
+	message             string // Export result or error
+	messageErr          bool
+}
+
+func NewProfilePanel(x, y, width, height int) *ProfilePanel {
+	return &ProfilePanel{
+		x:      x,
+		y:      y,
+		width:  width,
+		height: height,
+	}
+}
+
+// Load builds the report for exec, keeping the scroll position
+func (p *ProfilePanel) Load(exec *execution.Execution) error {
+	report, err := execpkg.BuildPerformanceReport(exec)
+	if err != nil {
+		return err
+	}
+	p.report = report
+	p.Scroll(0)
+	return nil
+}
+
+// Report returns the report shown, or nil before one is loaded
+func (p *ProfilePanel) Report() *execpkg.PerformanceReport {
+	return p.report
+}
+
+// Scroll moves the node table by delta rows
+func (p *ProfilePanel) Scroll(delta int) {
+	rows := 0
+	if p.report != nil {
+		rows = len(p.report.Nodes)
+	}
+	p.scrollOffset = max(0, min(p.scrollOffset+delta, rows-1))
+}
+
+// Export writes the report as JSON and CSV to dir
+func (p *ProfilePanel) Export(dir string) ([]string, error) {
+	if p.report == nil {
+		return nil, fmt.Errorf("no report to export")
+	}
+	paths, err := p.report.WriteFiles(dir)
+	if err != nil {
+		p.SetMessage(err.Error(), true)
+		return paths, err
+	}
+	p.SetMessage("Exported "+strings.Join(paths, ", "), false)
+	return paths, nil
+}
+
+// SetMessage shows a message at the bottom of the panel
+func (p *ProfilePanel) SetMessage(message string, isErr bool) {
+	p.message = message
+	p.messageErr = isErr
+}
+
+// Message returns the last export result or error
+func (p *ProfilePanel) Message() string {
+	return p.message
+}
+
+func (p *ProfilePanel) Render(screen *goterm.Screen) {
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+
+	title := "┌─ Performance "
+	if p.report != nil {
+		title = fmt.Sprintf("┌─ Performance %s ", p.report.ExecutionID)
+	}
+	screen.DrawText(p.x, p.y, title, fg, bg, goterm.StyleBold)
+	titleWidth := len([]rune(title))
+	screen.DrawText(p.x+titleWidth, p.y, strings.Repeat("─", max(p.width-titleWidth-1, 0))+"┐", fg, bg, goterm.StyleNone)
+
+	clip := func(line string) string {
+		return fitToWidth(line, p.width-2)
+	}
+	y := p.y + 1
+	bottom := p.y + p.height - 1
+
+	if p.report == nil {
+		screen.DrawText(p.x+1, y, "  (no execution to profile)", fg, bg, goterm.StyleDim)
+	} else {
+		r := p.report
+		summary := fmt.Sprintf("Duration %s | MCP %d calls %s (%s) | Transforms %d %s (%s) | Other %s",
+			formatProfileDuration(r.Duration),
+			r.MCPCalls, formatProfileDuration(r.MCPTime), profileShare(r.MCPTime, r),
+			r.Transforms, formatProfileDuration(r.TransformTime), profileShare(r.TransformTime, r),
+			formatProfileDuration(r.OtherTime))
+		screen.DrawText(p.x+1, y, clip(summary), fg, bg, goterm.StyleNone)
+		y++
+		payload := fmt.Sprintf("Retries %d | Payload in %s, out %s", r.Retries, formatPayloadSize(r.InputBytes), formatPayloadSize(r.OutputBytes))
+		screen.DrawText(p.x+1, y, clip(payload), fg, bg, goterm.StyleNone)
+		y += 2
+
+		header := fmt.Sprintf("%-20s %-12s %5s %7s %10s %10s %10s %9s %9s", "Node", "Type", "Runs", "Retries", "Total", "Avg", "Max", "In", "Out")
+		screen.DrawText(p.x+1, y, clip(header), fg, bg, goterm.StyleBold)
+		y++
+
+		for i, node := range r.Nodes {
+			if i < p.scrollOffset {
+				continue
+			}
+			if y >= bottom-1 {
+				break
+			}
+			line := fmt.Sprintf("%-20s %-12s %5d %7d %10s %10s %10s %9s %9s",
+				fitToWidth(string(node.NodeID), 20), fitToWidth(node.NodeType, 12),
+				node.Executions, node.Retries,
+				formatProfileDuration(node.TotalDuration), formatProfileDuration(node.AverageDuration()), formatProfileDuration(node.MaxDuration),
+				formatPayloadSize(node.InputBytes), formatPayloadSize(node.OutputBytes))
+			color := fg
+			if node.Failures > 0 {
+				color = theme.Error
+			}
+			screen.DrawText(p.x+1, y, clip(line), color, bg, goterm.StyleNone)
+			y++
+		}
+	}
+
+	if p.message != "" {
+		color := theme.Success
+		if p.messageErr {
+			color = theme.Error
+		}
+		screen.DrawText(p.x+1, bottom-1, clip(p.message), color, bg, goterm.StyleNone)
+	}
+
+	// Bottom border
+	screen.DrawText(p.x, bottom, "└"+strings.Repeat("─", p.width-2)+"┘", fg, bg, goterm.StyleNone)
+}
+
+// profileShare returns d as a percentage of the time spent in nodes
+func profileShare(d time.Duration, r *execpkg.PerformanceReport) string {
+	total := r.MCPTime + r.TransformTime + r.OtherTime
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(d)/float64(total)*100)
+}
+
+// formatProfileDuration rounds a duration for display
+func formatProfileDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(100 * time.Microsecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
+
+// formatPayloadSize formats a byte count
+func formatPayloadSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// NodeRetrier re-runs a single failed node of a finished execution.
+// *execution.Engine implements it.
+type NodeRetrier interface {
+	RetryNode(ctx context.Context, wf *workflow.Workflow, exec *execution.Execution, nodeID types.NodeID, params map[string]interface{}) (*execution.NodeExecution, error)
+}
+
+// retryField is one editable tool argument. Arguments that were not strings
+// are edited as JSON and parsed back on submit.
+type retryField struct {
+	name   string
+	value  string
+	isJSON bool
+}
+
+// RetryFormPanel lets the user review and edit the resolved arguments of a
+// failed tool node before retrying it.
+type RetryFormPanel struct {
+	x, y, width, height int
+	nodeID              types.NodeID
+	toolName            string
+	failure             string
+	fields              []retryField
+	selected            int
+	editing             bool
+	editBuffer          string
+	result              *execution.NodeExecution // Outcome of the last retry
+	message             string                   // Validation or retry error
+}
+
+func NewRetryFormPanel(x, y, width, height int) *RetryFormPanel {
+	return &RetryFormPanel{
+		x:      x,
+		y:      y,
+		width:  width,
+		height: height,
+	}
+}
+
+// Load fills the form from a failed node execution
+func (p *RetryFormPanel) Load(failed *execution.NodeExecution, toolName string) {
+	p.nodeID = failed.NodeID
+	p.toolName = toolName
+	p.failure = ""
+	if failed.Error != nil {
+		p.failure = failed.Error.Message
+	}
+
+	names := make([]string, 0, len(failed.Inputs))
+	for name := range failed.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p.fields = make([]retryField, 0, len(names))
+	for _, name := range names {
+		field := retryField{name: name}
+		if s, ok := failed.Inputs[name].(string); ok {
+			field.value = s
+		} else {
+			field.value = formatScratchValue(failed.Inputs[name])
+			field.isJSON = true
+		}
+		p.fields = append(p.fields, field)
+	}
+
+	p.selected = 0
+	p.editing = false
+	p.editBuffer = ""
+	p.result = nil
+	p.message = ""
+}
+
+// NodeID returns the node the form retries
+func (p *RetryFormPanel) NodeID() types.NodeID {
+	return p.nodeID
+}
+
+// IsEditing reports whether a field is being edited
+func (p *RetryFormPanel) IsEditing() bool {
+	return p.editing
+}
+
+// Move changes the selected field
+func (p *RetryFormPanel) Move(delta int) {
+	if len(p.fields) == 0 {
+		return
+	}
+	p.selected = max(0, min(p.selected+delta, len(p.fields)-1))
+}
+
+// BeginEdit starts editing the selected field
+func (p *RetryFormPanel) BeginEdit() {
+	if len(p.fields) == 0 {
+		return
+	}
+	p.editing = true
+	p.editBuffer = p.fields[p.selected].value
+}
+
+// CommitEdit stores the edited value in the selected field
+func (p *RetryFormPanel) CommitEdit() {
+	if !p.editing {
+		return
+	}
+	p.fields[p.selected].value = p.editBuffer
+	p.editing = false
+	p.editBuffer = ""
+}
+
+// CancelEdit discards the edited value
+func (p *RetryFormPanel) CancelEdit() {
+	p.editing = false
+	p.editBuffer = ""
+}
+
+// TypeRune appends a character to the value being edited
+func (p *RetryFormPanel) TypeRune(r rune) {
+	if p.editing {
+		p.editBuffer += string(r)
+	}
+}
+
+// Backspace deletes the last character of the value being edited
+func (p *RetryFormPanel) Backspace() {
+	if !p.editing || p.editBuffer == "" {
+		return
+	}
+	runes := []rune(p.editBuffer)
+	p.editBuffer = string(runes[:len(runes)-1])
+}
+
+// SetFieldValue replaces an argument's value as if typed into the form
+func (p *RetryFormPanel) SetFieldValue(name, value string) error {
+	for i := range p.fields {
+		if p.fields[i].name == name {
+			p.fields[i].value = value
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown argument: %s", name)
+}
+
+// Arguments returns the tool arguments as currently entered
+func (p *RetryFormPanel) Arguments() (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(p.fields))
+	for _, field := range p.fields {
+		if !field.isJSON {
+			args[field.name] = field.value
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(field.value), &value); err != nil {
+			return nil, fmt.Errorf("argument %s: invalid JSON: %w", field.name, err)
+		}
+		args[field.name] = value
+	}
+	return args, nil
+}
+
+// SetResult records the outcome of a retry attempt
+func (p *RetryFormPanel) SetResult(result *execution.NodeExecution, err error) {
+	p.result = result
+	p.message = ""
+	if err != nil {
+		p.message = err.Error()
+	}
+}
+
+// Result returns the node execution produced by the last retry, if any
+func (p *RetryFormPanel) Result() *execution.NodeExecution {
+	return p.result
+}
+
+// Message returns the last validation or retry error
+func (p *RetryFormPanel) Message() string {
+	return p.message
+}
+
+func (p *RetryFormPanel) Render(screen *goterm.Screen) {
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+	errFg := theme.Error
+	okFg := theme.Success
+
+	// Border
+	title := fmt.Sprintf("┌─ Retry %s (%s) ", p.nodeID, p.toolName)
+	screen.DrawText(p.x, p.y, title, fg, bg, goterm.StyleBold)
+	titleWidth := len([]rune(title))
+	screen.DrawText(p.x+titleWidth, p.y, strings.Repeat("─", max(p.width-titleWidth-1, 0))+"┐", fg, bg, goterm.StyleNone)
+
+	y := p.y + 1
+	bottom := p.y + p.height - 1
+	clip := func(line string) string {
+		if len(line) > p.width-2 {
+			return line[:p.width-5] + "..."
+		}
+		return line
+	}
+
+	if p.failure != "" {
+		screen.DrawText(p.x+1, y, clip("Failed: "+p.failure), errFg, bg, goterm.StyleNone)
+		y++
+	}
+	y++
+
+	screen.DrawText(p.x+1, y, "Arguments:", fg, bg, goterm.StyleBold)
+	y++
+	if len(p.fields) == 0 {
+		screen.DrawText(p.x+1, y, "  (no arguments)", fg, bg, goterm.StyleDim)
+		y++
+	}
+	for i, field := range p.fields {
+		if y >= bottom-3 {
+			break
+		}
+		value := field.value
+		style := goterm.StyleNone
+		if i == p.selected {
+			style = goterm.StyleReverse
+			if p.editing {
+				value = p.editBuffer + "_"
+			}
+		}
+		kind := ""
+		if field.isJSON {
+			kind = " (json)"
+		}
+		screen.DrawText(p.x+1, y, clip(fmt.Sprintf("  %s%s = %s", field.name, kind, value)), fg, bg, style)
+		y++
+	}
+
+	// Outcome of the last retry
+	switch {
+	case p.message != "":
+		screen.DrawText(p.x+1, bottom-1, clip("! "+p.message), errFg, bg, goterm.StyleNone)
+	case p.result != nil && p.result.Status == execution.NodeStatusCompleted:
+		line := fmt.Sprintf("Retry #%d completed (%.2fs)", p.result.RetryCount, p.result.Duration().Seconds())
+		screen.DrawText(p.x+1, bottom-1, clip(line), okFg, bg, goterm.StyleBold)
+	case p.result != nil && p.result.Error != nil:
+		line := fmt.Sprintf("Retry #%d failed: %s", p.result.RetryCount, p.result.Error.Message)
+		screen.DrawText(p.x+1, bottom-1, clip(line), errFg, bg, goterm.StyleBold)
+	}
+
+	// Bottom border
+	screen.DrawText(p.x, bottom, "└"+strings.Repeat("─", p.width-2)+"┘", fg, bg, goterm.StyleNone)
+}
+
+// latestFailedToolNode returns the most recent failed MCP tool node
+// execution, or nil if there is none to retry.
+func latestFailedToolNode(exec *execution.Execution, wf *workflow.Workflow) (*execution.NodeExecution, *workflow.MCPToolNode) {
+	if exec == nil || wf == nil {
+		return nil, nil
+	}
+	for i := len(exec.NodeExecutions) - 1; i >= 0; i-- {
+		nodeExec := exec.NodeExecutions[i]
+		if nodeExec.Status != execution.NodeStatusFailed || exec.LatestNodeExecution(nodeExec.NodeID) != nodeExec {
+			continue
+		}
+		for _, node := range wf.Nodes {
+			if toolNode, ok := node.(*workflow.MCPToolNode); ok && toolNode.ID == string(nodeExec.NodeID) {
+				return nodeExec, toolNode
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,468 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/transform"
+	"github.com/dshills/goterm"
+)
+
+// ExecutionPauser pauses and resumes a running execution between nodes.
+// *execution.Engine implements it.
+type ExecutionPauser interface {
+	Pause()
+	Resume()
+	IsPaused() bool
+}
+
+// VariableWatch is a watch expression and its value after the latest event.
+// Expressions starting with "$" are JSONPath queries over the variables;
+// anything else is an expr expression, as in the scratchpad.
+type VariableWatch struct {
+	Expression string
+	Value      interface{}
+	Err        error
+}
+
+// inspectorInput is what the inspector's input line is being used for
+type inspectorInput int
+
+const (
+	inspectorNoInput inspectorInput = iota
+	inspectorEditValue
+	inspectorAddWatch
+)
+
+// inspectorRow is one selectable row: a watch or a variable
+type inspectorRow struct {
+	watch int // Index into the watches, or -1 for a variable
+	name  string
+}
+
+// VariableInspectorPanel displays workflow variables with expansion. Watch
+// expressions are listed first, then pinned variables, then the rest.
+type VariableInspectorPanel struct {
+	x, y, width, height int
+	variables           map[string]interface{}
+	expandedVars        map[string]bool
+	pinned              map[string]bool
+	watches             []VariableWatch
+	scrollOffset        int
+	selectedIdx         int
+
+	// Input line, for editing a value or adding a watch
+	input    inspectorInput
+	buffer   string
+	editName string
+	editJSON bool // The edited value was not a string and is parsed as JSON
+	message  string
+	querier  transform.JSONPathQuerier
+}
+
+func NewVariableInspectorPanel(x, y, width, height int) *VariableInspectorPanel {
+	return &VariableInspectorPanel{
+		x:            x,
+		y:            y,
+		width:        width,
+		height:       height,
+		variables:    make(map[string]interface{}),
+		expandedVars: make(map[string]bool),
+		pinned:       make(map[string]bool),
+		querier:      transform.NewJSONPathQuerier(),
+	}
+}
+
+// UpdateVariables replaces the displayed variables and re-evaluates the
+// watches against them
+func (p *VariableInspectorPanel) UpdateVariables(vars map[string]interface{}) {
+	p.variables = vars
+	p.EvaluateWatches()
+	p.clampSelection()
+}
+
+func (p *VariableInspectorPanel) ToggleExpand() {
+	if row, ok := p.selectedRow(); ok && row.watch < 0 {
+		p.expandedVars[row.name] = !p.expandedVars[row.name]
+	}
+}
+
+// Scroll moves the selection by delta rows, scrolling to keep it in view
+func (p *VariableInspectorPanel) Scroll(delta int) {
+	p.selectedIdx += delta
+	p.clampSelection()
+
+	visible := max(p.height-3, 1) // Borders and the input line
+	if p.selectedIdx < p.scrollOffset {
+		p.scrollOffset = p.selectedIdx
+	} else if p.selectedIdx >= p.scrollOffset+visible {
+		p.scrollOffset = p.selectedIdx - visible + 1
+	}
+}
+
+func (p *VariableInspectorPanel) IsVisible() bool {
+	return true
+}
+
+func (p *VariableInspectorPanel) GetDisplayedVariables() map[string]interface{} {
+	return p.variables
+}
+
+// SelectedVariable returns the selected variable's name, or false if a
+// watch or nothing is selected
+func (p *VariableInspectorPanel) SelectedVariable() (string, bool) {
+	row, ok := p.selectedRow()
+	if !ok || row.watch >= 0 {
+		return "", false
+	}
+	return row.name, true
+}
+
+// TogglePin pins the selected variable to the top of the panel, or unpins
+// it. The selection follows the variable.
+func (p *VariableInspectorPanel) TogglePin() {
+	name, ok := p.SelectedVariable()
+	if !ok {
+		return
+	}
+	if p.pinned[name] {
+		delete(p.pinned, name)
+	} else {
+		p.pinned[name] = true
+	}
+	for i, row := range p.rows() {
+		if row.watch < 0 && row.name == name {
+			p.selectedIdx = i
+		}
+	}
+}
+
+// PinnedVariables returns the names of the pinned variables, sorted
+func (p *VariableInspectorPanel) PinnedVariables() []string {
+	names := make([]string, 0, len(p.pinned))
+	for name := range p.pinned {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddWatch adds a watch expression and evaluates it
+func (p *VariableInspectorPanel) AddWatch(expression string) error {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return fmt.Errorf("empty watch expression")
+	}
+	watch := VariableWatch{Expression: expression}
+	watch.Value, watch.Err = p.evaluate(expression)
+	p.watches = append(p.watches, watch)
+	return nil
+}
+
+// RemoveWatch removes the selected watch, returning false if no watch is
+// selected
+func (p *VariableInspectorPanel) RemoveWatch() bool {
+	row, ok := p.selectedRow()
+	if !ok || row.watch < 0 {
+		return false
+	}
+	p.watches = append(p.watches[:row.watch], p.watches[row.watch+1:]...)
+	p.clampSelection()
+	return true
+}
+
+// Watches returns the watch expressions and their latest values
+func (p *VariableInspectorPanel) Watches() []VariableWatch {
+	return append([]VariableWatch(nil), p.watches...)
+}
+
+// EvaluateWatches re-evaluates every watch against the current variables
+func (p *VariableInspectorPanel) EvaluateWatches() {
+	for i := range p.watches {
+		p.watches[i].Value, p.watches[i].Err = p.evaluate(p.watches[i].Expression)
+	}
+}
+
+// evaluate runs a watch expression against the variables
+func (p *VariableInspectorPanel) evaluate(expression string) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), scratchEvalTimeout)
+	defer cancel()
+
+	if strings.HasPrefix(expression, "$") {
+		return p.querier.Query(ctx, expression, p.variables)
+	}
+	// A fresh evaluator per call: compiled programs are cached by expression
+	// text and would go stale as the variables change type
+	return transform.NewExpressionEvaluator().Evaluate(ctx, expression, p.variables)
+}
+
+// BeginEdit opens the input line on the selected variable's value. String
+// values are edited as typed; other values are edited as JSON.
+func (p *VariableInspectorPanel) BeginEdit() error {
+	name, ok := p.SelectedVariable()
+	if !ok {
+		return fmt.Errorf("no variable selected")
+	}
+	p.input = inspectorEditValue
+	p.editName = name
+	p.message = ""
+	if s, ok := p.variables[name].(string); ok {
+		p.buffer, p.editJSON = s, false
+	} else {
+		p.buffer, p.editJSON = formatScratchValue(p.variables[name]), true
+	}
+	return nil
+}
+
+// BeginWatch opens the input line for a new watch expression
+func (p *VariableInspectorPanel) BeginWatch() {
+	p.input = inspectorAddWatch
+	p.buffer = ""
+	p.message = ""
+}
+
+// IsEditing reports whether the input line is open
+func (p *VariableInspectorPanel) IsEditing() bool {
+	return p.input != inspectorNoInput
+}
+
+// IsAddingWatch reports whether the input line holds a new watch expression
+func (p *VariableInspectorPanel) IsAddingWatch() bool {
+	return p.input == inspectorAddWatch
+}
+
+// TypeRune appends a character to the input line
+func (p *VariableInspectorPanel) TypeRune(r rune) {
+	if p.IsEditing() {
+		p.buffer += string(r)
+	}
+}
+
+// Backspace deletes the last character of the input line
+func (p *VariableInspectorPanel) Backspace() {
+	if !p.IsEditing() || p.buffer == "" {
+		return
+	}
+	runes := []rune(p.buffer)
+	p.buffer = string(runes[:len(runes)-1])
+}
+
+// CloseInput closes the input line, discarding what was typed
+func (p *VariableInspectorPanel) CloseInput() {
+	p.input = inspectorNoInput
+	p.buffer = ""
+	p.editName = ""
+}
+
+// EditedValue returns the variable being edited and its value as typed
+func (p *VariableInspectorPanel) EditedValue() (string, interface{}, error) {
+	if p.input != inspectorEditValue {
+		return "", nil, fmt.Errorf("no variable being edited")
+	}
+	if !p.editJSON {
+		return p.editName, p.buffer, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(p.buffer), &value); err != nil {
+		return "", nil, fmt.Errorf("%s: invalid JSON: %w", p.editName, err)
+	}
+	return p.editName, value, nil
+}
+
+// SetMessage shows a message, such as an error, on the input line
+func (p *VariableInspectorPanel) SetMessage(message string) {
+	p.message = message
+}
+
+// Message returns the message shown on the input line
+func (p *VariableInspectorPanel) Message() string {
+	return p.message
+}
+
+// rows returns the selectable rows in display order
+func (p *VariableInspectorPanel) rows() []inspectorRow {
+	rows := make([]inspectorRow, 0, len(p.watches)+len(p.variables))
+	for i := range p.watches {
+		rows = append(rows, inspectorRow{watch: i})
+	}
+	names := p.getSortedVarNames()
+	for _, name := range names {
+		if p.pinned[name] {
+			rows = append(rows, inspectorRow{watch: -1, name: name})
+		}
+	}
+	for _, name := range names {
+		if !p.pinned[name] {
+			rows = append(rows, inspectorRow{watch: -1, name: name})
+		}
+	}
+	return rows
+}
+
+func (p *VariableInspectorPanel) selectedRow() (inspectorRow, bool) {
+	rows := p.rows()
+	if p.selectedIdx < 0 || p.selectedIdx >= len(rows) {
+		return inspectorRow{}, false
+	}
+	return rows[p.selectedIdx], true
+}
+
+func (p *VariableInspectorPanel) clampSelection() {
+	p.selectedIdx = max(0, min(p.selectedIdx, len(p.rows())-1))
+	p.scrollOffset = max(0, min(p.scrollOffset, p.selectedIdx))
+}
+
+func (p *VariableInspectorPanel) Render(screen *goterm.Screen, active bool) {
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+
+	// Border
+	titleStyle := goterm.StyleBold
+	if active {
+		titleStyle = goterm.StyleReverse
+	}
+	screen.DrawText(p.x, p.y, "┌─ Variables ", fg, bg, titleStyle)
+	screen.DrawText(p.x+12, p.y, strings.Repeat("─", p.width-13)+"┐", fg, bg, goterm.StyleNone)
+
+	clip := func(line string) string {
+		if len(line) > p.width-2 {
+			return line[:p.width-5] + "..."
+		}
+		return line
+	}
+
+	y := p.y + 1
+	bottom := p.y + p.height - 1
+	if p.IsEditing() || p.message != "" {
+		bottom-- // Keep a line for the input
+	}
+
+	for i, row := range p.rows() {
+		if i < p.scrollOffset {
+			continue
+		}
+		if y >= bottom {
+			break
+		}
+
+		style := goterm.StyleNone
+		if active && i == p.selectedIdx {
+			style = goterm.StyleReverse
+		}
+
+		if row.watch >= 0 {
+			watch := p.watches[row.watch]
+			value := p.formatValue(watch.Value)
+			color := theme.Accent
+			if watch.Err != nil {
+				value = "! " + watch.Err.Error()
+				color = theme.Error
+			}
+			screen.DrawText(p.x+1, y, clip(fmt.Sprintf("  ◆ %s = %s", watch.Expression, value)), color, bg, style)
+			y++
+			continue
+		}
+
+		value := p.variables[row.name]
+		if p.input == inspectorEditValue && row.name == p.editName {
+			screen.DrawText(p.x+1, y, clip(fmt.Sprintf("  %s = %s_", row.name, p.buffer)), theme.InputFg, theme.InputBg, goterm.StyleNone)
+			y++
+			continue
+		}
+		marker := " "
+		if p.pinned[row.name] {
+			marker = "*"
+		}
+		screen.DrawText(p.x+1, y, clip(fmt.Sprintf(" %s%s = %s", marker, row.name, p.formatValue(value))), fg, bg, style)
+		y++
+
+		// Show expanded view if toggled
+		if p.expandedVars[row.name] {
+			expandedLines := p.formatExpanded(value)
+			for _, expLine := range expandedLines {
+				if y >= bottom {
+					break
+				}
+				screen.DrawText(p.x+3, y, expLine, fg, bg, goterm.StyleDim)
+				y++
+			}
+		}
+	}
+
+	// Input line
+	switch {
+	case p.input == inspectorAddWatch:
+		screen.DrawText(p.x+1, bottom, clip("watch> "+p.buffer+"_"), theme.InputFg, theme.InputBg, goterm.StyleNone)
+	case p.message != "":
+		screen.DrawText(p.x+1, bottom, clip("! "+p.message), theme.Error, bg, goterm.StyleNone)
+	}
+
+	// Bottom border
+	screen.DrawText(p.x, p.y+p.height-1, "└"+strings.Repeat("─", p.width-2)+"┘", fg, bg, goterm.StyleNone)
+}
+
+func (p *VariableInspectorPanel) getSortedVarNames() []string {
+	names := make([]string, 0, len(p.variables))
+	for name := range p.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *VariableInspectorPanel) formatValue(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+
+	switch v := value.(type) {
+	case string:
+		if len(v) > 30 {
+			return fmt.Sprintf("%q...", v[:30])
+		}
+		return fmt.Sprintf("%q", v)
+	case []interface{}:
+		return fmt.Sprintf("[%d items]", len(v))
+	case map[string]interface{}:
+		return fmt.Sprintf("{%d fields}", len(v))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (p *VariableInspectorPanel) formatExpanded(value interface{}) []string {
+	var lines []string
+
+	switch v := value.(type) {
+	case []interface{}:
+		for i, item := range v {
+			if i >= 10 { // Limit to 10 items
+				lines = append(lines, fmt.Sprintf("  ... and %d more", len(v)-10))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("  [%d]: %v", i, item))
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for i, k := range keys {
+			if i >= 10 {
+				lines = append(lines, fmt.Sprintf("  ... and %d more", len(keys)-10))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("  %s: %v", k, v[k]))
+		}
+	default:
+		lines = append(lines, fmt.Sprintf("  %v", value))
+	}
+
+	return lines
+}
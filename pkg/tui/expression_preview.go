@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goflow/pkg/storage"
+)
+
+// previewTimeout bounds evaluating an expression for its preview, which
+// happens on every keystroke
+const previewTimeout = 200 * time.Millisecond
+
+// previewSource evaluates the value typed into the field with the given
+// label against sample variables, given the values of all fields
+type previewSource func(label, value string, fields []propertyField) (interface{}, error)
+
+// SetSampleStore sets where the variables of the workflow's last run are
+// read from, as sample data for previewing expressions; nil previews with
+// the variables' defaults and any sample entered with SetSampleJSON
+func (b *WorkflowBuilder) SetSampleStore(store *storage.SampleStore) {
+	b.sampleStore = store
+}
+
+// SetSampleJSON sets sample variables for previewing expressions from a
+// JSON object. They override the variables of the last run; an empty
+// string removes them.
+func (b *WorkflowBuilder) SetSampleJSON(data string) error {
+	if data == "" {
+		b.sampleInput = nil
+		return nil
+	}
+	var variables map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &variables); err != nil {
+		return fmt.Errorf("sample must be a JSON object of variables: %w", err)
+	}
+	b.sampleInput = variables
+	return nil
+}
+
+// sampleVariables returns the variables expressions are previewed with:
+// the workflow's variable defaults, overridden by the variables of its
+// last run, overridden by the sample entered by the user
+func (b *WorkflowBuilder) sampleVariables() map[string]interface{} {
+	variables := make(map[string]interface{})
+	for _, v := range b.workflow.Variables {
+		if v != nil && v.DefaultValue != nil {
+			variables[v.Name] = v.DefaultValue
+		}
+	}
+	if b.sampleStore != nil {
+		lastRun, _ := b.sampleStore.Load(b.workflow.Name) // Best effort: preview with what is known
+		for name, value := range lastRun {
+			variables[name] = value
+		}
+	}
+	for name, value := range b.sampleInput {
+		variables[name] = value
+	}
+	return variables
+}
+
+// previewExpression evaluates a Transform expression or a condition the way
+// the engine would, against the sample variables
+func (b *WorkflowBuilder) previewExpression(label, value string, fields []propertyField) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+	defer cancel()
+
+	variables := b.sampleVariables()
+	if label == "Expression" {
+		inputVariable := getFieldValue(fields, "Input Variable")
+		input, ok := variables[inputVariable]
+		if !ok {
+			return nil, fmt.Errorf("no sample value for input variable '%s'", inputVariable)
+		}
+		return execution.EvaluateTransformAs(ctx, getFieldValue(fields, "Language"), value, input, variables)
+	}
+	return execution.EvaluateCondition(ctx, value, variables)
+}
+
+// SetPreviewSource sets how expressions and conditions being typed are
+// evaluated for their preview
+func (p *PropertyPanel) SetPreviewSource(source previewSource) {
+	p.previews = source
+}
+
+// Preview returns the result of evaluating the expression being typed
+// against sample data, and whether it is an error. It is empty if the
+// focused field is not previewed.
+func (p *PropertyPanel) Preview() (string, bool) {
+	return p.preview, p.previewFailed
+}
+
+// updatePreview evaluates the expression or condition being typed
+func (p *PropertyPanel) updatePreview() {
+	p.preview, p.previewFailed = "", false
+	if !p.editing || p.previews == nil {
+		return
+	}
+	field := p.fields[p.editIndex]
+	if field.fieldType != "expression" && field.fieldType != "condition" {
+		return
+	}
+	if p.editBuffer == "" {
+		return
+	}
+	result, err := p.previews(field.label, p.editBuffer, p.fields)
+	if err != nil {
+		p.preview, p.previewFailed = err.Error(), true
+		return
+	}
+	p.preview = formatScratchValue(result)
+}
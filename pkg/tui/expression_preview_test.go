@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+func TestWorkflowBuilder_PreviewTransformExpression(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	if err := builder.SetSampleJSON(`{"items": [1, 2, 3]}`); err != nil {
+		t.Fatalf("SetSampleJSON failed: %v", err)
+	}
+	if err := builder.EditNodeProperties("sum"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+
+	// The Expression field; its value x has no sample
+	typeKeys(t, builder, "Tab", "Tab", "Enter")
+	if preview, failed := panel.Preview(); !failed || !strings.Contains(preview, "unknown name x") {
+		t.Errorf("Preview() = %q, %v; want an unknown name", preview, failed)
+	}
+
+	typeKeys(t, builder, "Backspace", "len(items)")
+	if preview, failed := panel.Preview(); failed || preview != "3" {
+		t.Errorf("Preview() = %q, %v; want 3", preview, failed)
+	}
+
+	// JSONPath queries run against the input variable
+	for range "len(items)" {
+		typeKeys(t, builder, "Backspace")
+	}
+	typeKeys(t, builder, "$")
+	if preview, failed := panel.Preview(); failed || preview != "[1,2,3]" {
+		t.Errorf("Preview() = %q, %v; want the input", preview, failed)
+	}
+
+	typeKeys(t, builder, "Esc")
+	if preview, _ := panel.Preview(); preview != "" {
+		t.Errorf("Preview() after Esc = %q, want none", preview)
+	}
+}
+
+func TestWorkflowBuilder_PreviewCondition(t *testing.T) {
+	wf := &workflow.Workflow{
+		Name:    "preview",
+		Version: "1.0",
+		Nodes:   []workflow.Node{&workflow.ConditionNode{ID: "check", Condition: "size > limit"}},
+		Variables: []*workflow.Variable{
+			{Name: "size", Type: "number", DefaultValue: 10},
+			{Name: "limit", Type: "number", DefaultValue: 5},
+		},
+	}
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder failed: %v", err)
+	}
+	store := storage.NewSampleStore(filepath.Join(t.TempDir(), "samples"))
+	builder.SetSampleStore(store)
+	if err := builder.EditNodeProperties("check"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+
+	tests := []struct {
+		name    string
+		lastRun map[string]interface{}
+		sample  string
+		want    string
+	}{
+		{name: "variable defaults", want: "true"},
+		{name: "last run", lastRun: map[string]interface{}{"size": 1}, want: "false"},
+		{name: "sample overrides last run", lastRun: map[string]interface{}{"size": 1}, sample: `{"limit": 0}`, want: "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := store.Save(wf.Name, tt.lastRun); err != nil {
+				t.Fatal(err)
+			}
+			if err := builder.SetSampleJSON(tt.sample); err != nil {
+				t.Fatal(err)
+			}
+			typeKeys(t, builder, "Tab", "Enter")
+			if preview, failed := panel.Preview(); failed || preview != tt.want {
+				t.Errorf("Preview() = %q, %v; want %s", preview, failed, tt.want)
+			}
+			typeKeys(t, builder, "Esc", "Up")
+		})
+	}
+
+	typeKeys(t, builder, "Tab", "Enter", " +")
+	if _, failed := panel.Preview(); !failed {
+		t.Error("expected a syntax error to be previewed")
+	}
+}
+
+func TestWorkflowBuilder_SetSampleJSONRejectsNonObjects(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	for _, data := range []string{"[1, 2]", "{items:", "3"} {
+		if err := builder.SetSampleJSON(data); err == nil {
+			t.Errorf("SetSampleJSON(%q) succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// fileConflict is a change made to the workflow file outside the builder,
+// waiting for the user to reload, overwrite or merge
+type fileConflict struct {
+	data     []byte             // The file's contents on disk
+	theirs   *workflow.Workflow // Parsed from data, nil if it does not parse
+	parseErr error              // Why data does not parse
+	lines    []string           // Description for the prompt
+}
+
+// watch starts watching the workflow file for changes made outside the
+// builder. Without a watcher, changes are still caught when saving.
+func (v *WorkflowBuilderView) watch() {
+	v.stopWatching()
+	watcher, err := watchFile(v.workflowPath)
+	if err != nil {
+		v.statusMsg += " (not watching for changes: " + err.Error() + ")"
+		return
+	}
+	v.watcher = watcher
+}
+
+// stopWatching stops watching the workflow file
+func (v *WorkflowBuilderView) stopWatching() {
+	if v.watcher != nil {
+		_ = v.watcher.Close() // Best effort
+		v.watcher = nil
+	}
+}
+
+// checkDisk prompts the user if the workflow file has changed on disk since
+// it was loaded or saved
+func (v *WorkflowBuilderView) checkDisk() {
+	conflict, err := v.detectConflict()
+	if err != nil {
+		v.statusMsg = "Error: " + err.Error()
+		return
+	}
+	if conflict != nil {
+		v.conflict = conflict
+		v.statusMsg = filepath.Base(v.workflowPath) + " changed on disk"
+	}
+}
+
+// detectConflict compares the workflow file with the contents it had when
+// it was loaded or saved. It returns nil if the file is unchanged, or was
+// removed: saving then simply writes it again.
+func (v *WorkflowBuilderView) detectConflict() (*fileConflict, error) {
+	if v.workflowPath == "" || v.diskData == nil {
+		return nil, nil
+	}
+	data, err := os.ReadFile(v.workflowPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+	if bytes.Equal(data, v.diskData) {
+		return nil, nil
+	}
+
+	conflict := &fileConflict{data: data}
+	conflict.theirs, conflict.parseErr = workflow.Parse(data)
+	conflict.lines = v.conflictLines(conflict)
+	return conflict, nil
+}
+
+// conflictLines describes a conflict and the choices for the prompt
+func (v *WorkflowBuilderView) conflictLines(c *fileConflict) []string {
+	lines := []string{filepath.Base(v.workflowPath) + " was changed on disk outside the builder"}
+	if v.builder != nil && v.builder.modified {
+		lines = append(lines, "You have unsaved changes.")
+	} else {
+		lines = append(lines, "You have no unsaved changes.")
+	}
+
+	if c.parseErr != nil {
+		return append(lines,
+			"[o] overwrite with yours  [Esc] decide later",
+			"",
+			"The file on disk cannot be loaded: "+c.parseErr.Error())
+	}
+	lines = append(lines,
+		"[r] reload from disk  [o] overwrite with yours  [m] merge both  [Esc] decide later",
+		"",
+		"Changes on disk:")
+
+	base, err := workflow.Parse(v.diskData)
+	if err != nil {
+		return append(lines, "  (the previous version cannot be compared)")
+	}
+	diff, err := workflow.Diff(base, c.theirs)
+	if err != nil {
+		return append(lines, "  "+err.Error())
+	}
+	return append(lines, workflowDiffLines(diff)...)
+}
+
+// handleConflictKey handles keys while a change on disk is waiting for the
+// user to choose what to do
+func (v *WorkflowBuilderView) handleConflictKey(event KeyEvent) error {
+	c := v.conflict
+	name := filepath.Base(v.workflowPath)
+	switch {
+	case event.Key == 'r' && c.theirs != nil:
+		if err := v.builder.RestoreWorkflow(c.theirs); err != nil {
+			v.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		v.builder.modified = false
+		v.diskData, v.conflict = c.data, nil
+		v.statusMsg = "Reloaded " + name + " from disk (u to undo)"
+
+	case event.Key == 'o':
+		// Accept the file on disk as the version being replaced
+		v.diskData, v.conflict = c.data, nil
+		if err := v.Save(); err != nil {
+			v.statusMsg = "Error: " + err.Error()
+		}
+
+	case event.Key == 'm' && c.theirs != nil:
+		base, err := workflow.Parse(v.diskData)
+		if err != nil {
+			v.statusMsg = "Error: cannot merge: " + err.Error()
+			return nil
+		}
+		result, err := workflow.Merge(base, v.builder.GetWorkflow(), c.theirs)
+		if err == nil {
+			err = v.builder.RestoreWorkflow(result.Workflow)
+		}
+		if err != nil {
+			v.statusMsg = "Error: cannot merge: " + err.Error()
+			return nil
+		}
+		v.diskData, v.conflict = c.data, nil
+		v.statusMsg = "Merged changes from disk (u to undo, :w to save)"
+		if len(result.Conflicts) > 0 {
+			v.statusMsg = "Merged changes from disk, kept yours for " + strings.Join(result.Conflicts, ", ")
+		}
+
+	case event.Key == 'q' || (event.IsSpecial && event.Special == "Escape"):
+		v.conflict = nil
+		v.statusMsg = name + " changed on disk; :w will ask again"
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFileTestView opens a copy of the simple pipeline example in the builder
+func newFileTestView(t *testing.T) (*WorkflowBuilderView, string) {
+	t.Helper()
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir()) // Saves are audited
+	data, err := os.ReadFile(filepath.Join("..", "..", "examples", "simple-pipeline.yaml"))
+	if err != nil {
+		t.Skipf("example workflow not available: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewWorkflowBuilderView()
+	view.SetWorkflow(path)
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	t.Cleanup(view.stopWatching)
+	return view, path
+}
+
+// editOnDisk changes the workflow file as another editor would
+func editOnDisk(t *testing.T, path, old, new string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), old) {
+		t.Fatalf("%s does not contain %q", path, old)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), old, new, 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorkflowBuilderView_SaveDetectsChangesOnDisk(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    rune
+		check  func(t *testing.T, view *WorkflowBuilderView, path string)
+		closed bool
+	}{
+		{
+			name: "reload",
+			key:  'r',
+			check: func(t *testing.T, view *WorkflowBuilderView, path string) {
+				wf := view.builder.GetWorkflow()
+				if wf.Description != "Edited elsewhere" || wf.Version != "1.0" {
+					t.Errorf("reloaded version %q description %q", wf.Version, wf.Description)
+				}
+				if view.builder.modified {
+					t.Error("expected the reloaded workflow to be unmodified")
+				}
+			},
+		},
+		{
+			name: "overwrite",
+			key:  'o',
+			check: func(t *testing.T, view *WorkflowBuilderView, path string) {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.Contains(string(data), "Edited elsewhere") || !strings.Contains(string(data), "2.0") {
+					t.Errorf("file not overwritten:\n%s", data)
+				}
+			},
+		},
+		{
+			name: "merge",
+			key:  'm',
+			check: func(t *testing.T, view *WorkflowBuilderView, path string) {
+				wf := view.builder.GetWorkflow()
+				if wf.Description != "Edited elsewhere" || wf.Version != "2.0" {
+					t.Errorf("merged version %q description %q", wf.Version, wf.Description)
+				}
+				if err := view.Save(); err != nil {
+					t.Errorf("Save after merge failed: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, path := newFileTestView(t)
+			view.builder.GetWorkflow().Version = "2.0"
+			view.builder.modified = true
+			editOnDisk(t, path, "Read file, transform data, write output", "Edited elsewhere")
+
+			if err := view.Save(); err == nil || !strings.Contains(err.Error(), "changed on disk") {
+				t.Fatalf("Save error = %v, want a change on disk", err)
+			}
+			if view.conflict == nil {
+				t.Fatal("expected a conflict prompt")
+			}
+			screen := renderViewText(t, view, 120, 30)
+			if !strings.Contains(screen, "[m] merge both") || !strings.Contains(screen, "description") {
+				t.Errorf("prompt not rendered:\n%s", screen)
+			}
+
+			if err := view.HandleKey(KeyEvent{Key: tt.key}); err != nil {
+				t.Fatalf("HandleKey(%q) error = %v", tt.key, err)
+			}
+			if view.conflict != nil {
+				t.Fatalf("expected %q to resolve the conflict, status %q", tt.key, view.statusMsg)
+			}
+			tt.check(t, view, path)
+		})
+	}
+}
+
+func TestWorkflowBuilderView_UnparsableChangeOnDisk(t *testing.T) {
+	view, path := newFileTestView(t)
+	if err := os.WriteFile(path, []byte("not: [a workflow"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view.checkDisk()
+	if view.conflict == nil {
+		t.Fatal("expected a conflict prompt")
+	}
+	if lines := strings.Join(view.conflict.lines, "\n"); strings.Contains(lines, "[r]") || !strings.Contains(lines, "cannot be loaded") {
+		t.Errorf("prompt offers reload for an unparsable file:\n%s", lines)
+	}
+	if err := view.HandleKey(KeyEvent{Key: 'r'}); err != nil {
+		t.Fatal(err)
+	}
+	if view.conflict == nil {
+		t.Error("reload should not be possible")
+	}
+
+	// Dismissing leaves the check for the next save
+	if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"}); err != nil {
+		t.Fatal(err)
+	}
+	if view.conflict != nil {
+		t.Fatal("expected Escape to dismiss the prompt")
+	}
+	if err := view.Save(); err == nil {
+		t.Error("expected Save to ask again")
+	}
+}
+
+func TestWorkflowBuilderView_WatchesFile(t *testing.T) {
+	view, path := newFileTestView(t)
+	if view.watcher == nil {
+		t.Skipf("file watching unavailable: %s", view.statusMsg)
+	}
+
+	// Saving does not report the builder's own write
+	if err := view.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	editOnDisk(t, path, "Read file, transform data, write output", "Edited elsewhere")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for view.conflict == nil && time.Now().Before(deadline) {
+		view.Tick(time.Now())
+		time.Sleep(10 * time.Millisecond)
+	}
+	if view.conflict == nil {
+		t.Fatal("expected the change on disk to be detected")
+	}
+	if !strings.Contains(view.statusMsg, "changed on disk") {
+		t.Errorf("status = %q", view.statusMsg)
+	}
+}
+
+func TestWorkflowBuilderView_UpgradesOlderSchemaOnSave(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir()) // Saves are audited
+	data, err := os.ReadFile(filepath.Join("..", "..", "examples", "simple-pipeline.yaml"))
+	if err != nil {
+		t.Skipf("example workflow not available: %v", err)
+	}
+	legacy := strings.Replace(string(data), "schema_version: 1\n", "", 1)
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewWorkflowBuilderView()
+	view.SetWorkflow(path)
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	t.Cleanup(view.stopWatching)
+
+	// Opening the file migrates it in memory only
+	if onDisk, _ := os.ReadFile(path); string(onDisk) != legacy {
+		t.Error("opening the workflow rewrote the file")
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("opening the workflow wrote a backup")
+	}
+	if !strings.Contains(view.statusMsg, "saving upgrades it") {
+		t.Errorf("status = %q, want the pending upgrade noted", view.statusMsg)
+	}
+
+	if err := view.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if backup, err := os.ReadFile(path + ".bak"); err != nil || string(backup) != legacy {
+		t.Errorf("backup = %q, %v, want the original file", backup, err)
+	}
+	if onDisk, _ := os.ReadFile(path); !strings.Contains(string(onDisk), "schema_version: 1") {
+		t.Error("saved file does not have the current schema version")
+	}
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fileWatcher reports changes to a single file. It watches the file's
+// directory rather than the file itself, so replacing the file, as editors
+// and atomic saves do, is seen too.
+type fileWatcher struct {
+	watcher *fsnotify.Watcher
+	name    string        // Base name of the watched file
+	changed chan struct{} // Signalled, without blocking, on every change
+}
+
+// watchFile starts watching path for changes
+func watchFile(path string) (*fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", filepath.Base(path), err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", filepath.Base(path), err)
+	}
+
+	w := &fileWatcher{
+		watcher: watcher,
+		name:    filepath.Base(path),
+		changed: make(chan struct{}, 1),
+	}
+	go w.run()
+	return w, nil
+}
+
+// run forwards events for the watched file until the watcher is closed
+func (w *fileWatcher) run() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Base(event.Name) != w.name || event.Op == fsnotify.Chmod {
+				continue
+			}
+			select {
+			case w.changed <- struct{}{}:
+			default: // A change is already pending
+			}
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			// Dropped events are caught by the check on save
+		}
+	}
+}
+
+// Changed returns a channel that receives a value after the file changes.
+// Several changes may be reported as one.
+func (w *fileWatcher) Changed() <-chan struct{} {
+	return w.changed
+}
+
+// Close stops watching
+func (w *fileWatcher) Close() error {
+	return w.watcher.Close()
+}
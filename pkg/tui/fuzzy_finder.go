@@ -0,0 +1,353 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dshills/goterm"
+)
+
+// FinderKind says what an item in the fuzzy finder is and what choosing it
+// does
+type FinderKind string
+
+const (
+	// FinderNode is a node on the builder canvas; choosing it jumps to it
+	FinderNode FinderKind = "node"
+	// FinderWorkflow is a saved workflow; choosing it opens it
+	FinderWorkflow FinderKind = "workflow"
+	// FinderCommand is a registered command; choosing it runs it
+	FinderCommand FinderKind = "command"
+	// FinderEnvironment is a server environment; choosing it makes it the
+	// default for workflow runs
+	FinderEnvironment FinderKind = "env"
+)
+
+// finderPrefixes restrict a query to one kind of item
+var finderPrefixes = map[rune]FinderKind{
+	'@': FinderNode,
+	'/': FinderWorkflow,
+	':': FinderCommand,
+}
+
+// Fuzzy match scoring
+const (
+	scoreMatch       = 16 // Each matched character
+	scoreConsecutive = 8  // Match right after the previous match
+	scoreWordStart   = 10 // Match at the start of a word
+	scoreFirstChar   = 12 // Match at the start of the text
+	scoreGap         = -1 // Each skipped character after the first match
+	scoreDetailOnly  = -20
+)
+
+// FinderItem is one entry the fuzzy finder can search
+type FinderItem struct {
+	Kind   FinderKind
+	Label  string // Text searched and shown, e.g. a node ID
+	Detail string // Secondary text, also searched, e.g. the node type
+	Value  string // What to act on: node ID, workflow path or command name
+	Node   string // Node to jump to in the workflow opened, if any
+}
+
+// FinderMatch is an item that matched the query
+type FinderMatch struct {
+	Item      FinderItem
+	Score     int
+	Positions []int // Matched rune positions in Item.Label
+}
+
+// FuzzyMatch reports whether every rune of query appears in text in order,
+// ignoring case, and scores the match: runs of consecutive characters and
+// characters at word starts score higher. Positions are rune indexes of
+// the matched characters in text.
+func FuzzyMatch(query, text string) (score int, positions []int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(text)
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+	lower := []rune(strings.ToLower(text))
+
+	// Try every starting point for the first character and keep the best
+	// greedy alignment from there
+	best := -1 << 31
+	for start := range lower {
+		if lower[start] != q[0] {
+			continue
+		}
+		s, pos, matched := alignFrom(q, t, lower, start)
+		if matched && s > best {
+			best, positions, ok = s, pos, true
+		}
+	}
+	return best, positions, ok
+}
+
+// alignFrom matches q greedily in lower starting at start and scores it
+func alignFrom(q, t, lower []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(q))
+	score := 0
+	qi := 0
+	for i := start; i < len(lower) && qi < len(q); i++ {
+		if lower[i] != q[qi] {
+			if qi > 0 {
+				score += scoreGap
+			}
+			continue
+		}
+		score += scoreMatch
+		if i == 0 {
+			score += scoreFirstChar
+		} else if isWordStart(t, i) {
+			score += scoreWordStart
+		}
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += scoreConsecutive
+		}
+		positions = append(positions, i)
+		qi++
+	}
+	return score, positions, qi == len(q)
+}
+
+// isWordStart reports whether t[i] begins a word: after a separator or at
+// a lower-to-upper case change
+func isWordStart(t []rune, i int) bool {
+	prev := t[i-1]
+	if strings.ContainsRune(" _-./:", prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(t[i])
+}
+
+// FuzzyFinder is a Ctrl-P style overlay that filters items as the user
+// types and acts on the chosen one
+type FuzzyFinder struct {
+	items    []FinderItem
+	query    []rune
+	matches  []FinderMatch
+	selected int
+	visible  bool
+	onSelect func(item FinderItem) error
+}
+
+// NewFuzzyFinder creates a finder that calls onSelect with the chosen item
+func NewFuzzyFinder(onSelect func(item FinderItem) error) *FuzzyFinder {
+	return &FuzzyFinder{onSelect: onSelect}
+}
+
+// Open shows the finder over items with an empty query
+func (f *FuzzyFinder) Open(items []FinderItem) {
+	f.items = items
+	f.query = f.query[:0]
+	f.visible = true
+	f.refresh()
+}
+
+// Close hides the finder
+func (f *FuzzyFinder) Close() {
+	f.visible = false
+}
+
+// IsVisible returns whether the finder is open
+func (f *FuzzyFinder) IsVisible() bool {
+	return f.visible
+}
+
+// Query returns the text typed so far
+func (f *FuzzyFinder) Query() string {
+	return string(f.query)
+}
+
+// Matches returns the items matching the query, best first
+func (f *FuzzyFinder) Matches() []FinderMatch {
+	return f.matches
+}
+
+// Selected returns the highlighted match
+func (f *FuzzyFinder) Selected() (FinderMatch, bool) {
+	if f.selected < 0 || f.selected >= len(f.matches) {
+		return FinderMatch{}, false
+	}
+	return f.matches[f.selected], true
+}
+
+// SetQuery replaces the query and refilters
+func (f *FuzzyFinder) SetQuery(query string) {
+	f.query = []rune(query)
+	f.refresh()
+}
+
+// HandleKey edits the query and moves the selection. Enter closes the
+// finder and acts on the selection; Escape or Ctrl-c just closes it.
+func (f *FuzzyFinder) HandleKey(event KeyEvent) error {
+	if !f.visible {
+		return nil
+	}
+
+	switch {
+	case event.IsSpecial && event.Special == "Escape", event.Ctrl && event.Key == 'c':
+		f.Close()
+	case event.IsSpecial && event.Special == "Enter":
+		match, ok := f.Selected()
+		f.Close()
+		if ok && f.onSelect != nil {
+			return f.onSelect(match.Item)
+		}
+	case event.IsSpecial && (event.Special == "Up" || (event.Special == "Tab" && event.Shift)),
+		event.Ctrl && (event.Key == 'p' || event.Key == 'k'):
+		if f.selected > 0 {
+			f.selected--
+		}
+	case event.IsSpecial && (event.Special == "Down" || event.Special == "Tab"),
+		event.Ctrl && (event.Key == 'n' || event.Key == 'j'):
+		if f.selected < len(f.matches)-1 {
+			f.selected++
+		}
+	case event.IsSpecial && event.Special == "Backspace":
+		if len(f.query) > 0 {
+			f.query = f.query[:len(f.query)-1]
+			f.refresh()
+		}
+	case event.Ctrl && event.Key == 'u':
+		f.query = f.query[:0]
+		f.refresh()
+	case !event.IsSpecial && !event.Ctrl && !event.Alt && event.Key != 0:
+		f.query = append(f.query, event.Key)
+		f.refresh()
+	}
+	return nil
+}
+
+// refresh refilters the items and selects the best match
+func (f *FuzzyFinder) refresh() {
+	query := string(f.query)
+	kind := FinderKind("")
+	if runes := []rune(query); len(runes) > 0 {
+		if k, ok := finderPrefixes[runes[0]]; ok {
+			kind = k
+			query = string(runes[1:])
+		}
+	}
+	query = strings.TrimSpace(query)
+
+	f.matches = f.matches[:0]
+	for i, item := range f.items {
+		if kind != "" && item.Kind != kind {
+			continue
+		}
+		// Nodes of other workflows would crowd out everything else, so they
+		// are only searched for workflows
+		if item.Node != "" && kind != FinderWorkflow {
+			continue
+		}
+		if query == "" {
+			// Keep the given order: score by position
+			f.matches = append(f.matches, FinderMatch{Item: item, Score: -i})
+			continue
+		}
+		if score, positions, ok := FuzzyMatch(query, item.Label); ok {
+			f.matches = append(f.matches, FinderMatch{Item: item, Score: score, Positions: positions})
+		} else if score, _, ok := FuzzyMatch(query, item.Detail); ok {
+			f.matches = append(f.matches, FinderMatch{Item: item, Score: score + scoreDetailOnly})
+		}
+	}
+
+	sort.SliceStable(f.matches, func(i, j int) bool {
+		a, b := f.matches[i], f.matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return len(a.Item.Label) < len(b.Item.Label)
+	})
+	f.selected = 0
+}
+
+// Render draws the finder as a box centered near the top of the screen
+func (f *FuzzyFinder) Render(screen *goterm.Screen) {
+	if !f.visible {
+		return
+	}
+	screenWidth, screenHeight := screen.Size()
+
+	width := min(max(screenWidth*2/3, 40), screenWidth-2)
+	height := min(max(screenHeight/2, 8), screenHeight-2)
+	if width < 10 || height < 4 {
+		return
+	}
+	x := (screenWidth - width) / 2
+	y := max((screenHeight-height)/4, 0)
+
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+	dim := theme.Muted
+	accent := theme.Accent
+
+	// Border and background
+	for row := 0; row < height; row++ {
+		line := "│" + strings.Repeat(" ", width-2) + "│"
+		switch row {
+		case 0:
+			line = "┌" + strings.Repeat("─", width-2) + "┐"
+		case height - 1:
+			line = "└" + strings.Repeat("─", width-2) + "┘"
+		}
+		screen.DrawText(x, y+row, line, fg, bg, goterm.StyleNone)
+	}
+	title := fmt.Sprintf(" Find (%d) ", len(f.matches))
+	screen.DrawText(x+2, y, title, fg, bg, goterm.StyleBold)
+
+	// Query line
+	inner := width - 4
+	screen.DrawText(x+2, y+1, fitToWidth("> "+string(f.query), inner), fg, bg, goterm.StyleNone)
+	if cursor := 2 + len(f.query); cursor < inner {
+		screen.DrawText(x+2+cursor, y+1, " ", fg, bg, goterm.StyleReverse)
+	}
+
+	// Results, scrolled to keep the selection visible
+	rows := height - 3
+	offset := 0
+	if f.selected >= rows {
+		offset = f.selected - rows + 1
+	}
+	for row := 0; row < rows && offset+row < len(f.matches); row++ {
+		match := f.matches[offset+row]
+		style := goterm.StyleNone
+		if offset+row == f.selected {
+			style = goterm.StyleReverse
+		}
+		ry := y + 2 + row
+
+		tag := fmt.Sprintf("%-9s", string(match.Item.Kind))
+		screen.DrawText(x+2, ry, tag, dim, bg, style)
+
+		labelX := x + 2 + len(tag)
+		labelWidth := inner - len(tag)
+		matched := make(map[int]bool, len(match.Positions))
+		for _, p := range match.Positions {
+			matched[p] = true
+		}
+		col := 0
+		for i, r := range []rune(match.Item.Label) {
+			if col >= labelWidth {
+				break
+			}
+			color := fg
+			if matched[i] {
+				color = accent
+			}
+			screen.DrawText(labelX+col, ry, string(r), color, bg, style)
+			col++
+		}
+		if match.Item.Detail != "" && col+3 < labelWidth {
+			detail := fitToWidth("  "+match.Item.Detail, labelWidth-col)
+			screen.DrawText(labelX+col, ry, detail, dim, bg, style)
+		}
+	}
+	if len(f.matches) == 0 {
+		screen.DrawText(x+2, y+2, "No matches", dim, bg, goterm.StyleNone)
+	}
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query     string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"", "anything", true, nil},
+		{"fd", "fetch_data", true, []int{0, 6}},
+		{"FD", "fetch_data", true, []int{0, 6}},
+		{"data", "fetch_data", true, []int{6, 7, 8, 9}},
+		{"gu", "getUser", true, []int{0, 3}},
+		{"xyz", "fetch_data", false, nil},
+		{"atad", "fetch_data", false, nil},
+	}
+	for _, tt := range tests {
+		_, positions, ok := FuzzyMatch(tt.query, tt.text)
+		if ok != tt.ok || !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("FuzzyMatch(%q, %q) = %v, %v; want %v, %v",
+				tt.query, tt.text, positions, ok, tt.positions, tt.ok)
+		}
+	}
+
+	// Consecutive and word-start matches beat scattered ones
+	prefix, _, _ := FuzzyMatch("trans", "transform")
+	scattered, _, _ := FuzzyMatch("trans", "the_result_and_summary")
+	if prefix <= scattered {
+		t.Errorf("prefix score %d <= scattered score %d", prefix, scattered)
+	}
+	wordStart, _, _ := FuzzyMatch("sd", "save_data")
+	midWord, _, _ := FuzzyMatch("sd", "issued")
+	if wordStart <= midWord {
+		t.Errorf("word start score %d <= mid-word score %d", wordStart, midWord)
+	}
+}
+
+// finderLabels returns the labels of the finder's matches in order
+func finderLabels(f *FuzzyFinder) []string {
+	labels := make([]string, 0)
+	for _, match := range f.Matches() {
+		labels = append(labels, match.Item.Label)
+	}
+	return labels
+}
+
+func testFinderItems() []FinderItem {
+	return []FinderItem{
+		{Kind: FinderNode, Label: "start", Detail: "start", Value: "start"},
+		{Kind: FinderNode, Label: "fetch_data", Detail: "tool: http.get", Value: "fetch_data"},
+		{Kind: FinderNode, Label: "transform", Detail: "transform", Value: "transform"},
+		{Kind: FinderWorkflow, Label: "data-pipeline.yaml", Value: "data-pipeline.yaml"},
+		{Kind: FinderCommand, Label: "template apply", Detail: "<name>", Value: "template apply"},
+	}
+}
+
+func TestFuzzyFinder_Filter(t *testing.T) {
+	finder := NewFuzzyFinder(nil)
+	finder.Open(testFinderItems())
+
+	// An empty query lists everything in the given order
+	if got := finderLabels(finder); len(got) != 5 || got[0] != "start" {
+		t.Errorf("empty query matches = %q", got)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"data", []string{"data-pipeline.yaml", "fetch_data"}},
+		{"@data", []string{"fetch_data"}},
+		{"/", []string{"data-pipeline.yaml"}},
+		{":tap", []string{"template apply"}},
+		{"http", []string{"fetch_data"}}, // Matched on detail only
+		{"@zzz", []string{}},
+	}
+	for _, tt := range tests {
+		finder.SetQuery(tt.query)
+		if got := finderLabels(finder); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SetQuery(%q) matches = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyFinder_HandleKey(t *testing.T) {
+	var chosen []FinderItem
+	finder := NewFuzzyFinder(func(item FinderItem) error {
+		chosen = append(chosen, item)
+		return nil
+	})
+	special := func(name string) KeyEvent { return KeyEvent{IsSpecial: true, Special: name} }
+
+	// Keys are ignored while closed
+	_ = finder.HandleKey(KeyEvent{Key: 'x'})
+	if finder.Query() != "" {
+		t.Errorf("closed finder accepted input: %q", finder.Query())
+	}
+
+	finder.Open(testFinderItems())
+	for _, r := range "@ta" {
+		_ = finder.HandleKey(KeyEvent{Key: r})
+	}
+	if finder.Query() != "@ta" {
+		t.Errorf("Query() = %q, want @ta", finder.Query())
+	}
+
+	// Down and Ctrl-p move the selection within bounds
+	_ = finder.HandleKey(special("Down"))
+	_ = finder.HandleKey(special("Down"))
+	_ = finder.HandleKey(special("Down"))
+	last := len(finder.Matches()) - 1
+	if match, _ := finder.Selected(); match.Item != finder.Matches()[last].Item {
+		t.Errorf("selection did not stop at the last match")
+	}
+	_ = finder.HandleKey(KeyEvent{Key: 'p', Ctrl: true})
+
+	_ = finder.HandleKey(special("Backspace"))
+	if finder.Query() != "@t" {
+		t.Errorf("Query() after Backspace = %q, want @t", finder.Query())
+	}
+
+	// Enter closes and acts on the selection, which resets to the best match
+	best, _ := finder.Selected()
+	if err := finder.HandleKey(special("Enter")); err != nil {
+		t.Fatalf("Enter error = %v", err)
+	}
+	if finder.IsVisible() || len(chosen) != 1 || chosen[0] != best.Item {
+		t.Errorf("visible = %v, chosen = %+v, want %+v", finder.IsVisible(), chosen, best.Item)
+	}
+
+	// Escape closes without selecting; reopening starts with an empty query
+	finder.Open(testFinderItems())
+	if finder.Query() != "" {
+		t.Errorf("reopened with query %q", finder.Query())
+	}
+	_ = finder.HandleKey(special("Escape"))
+	if finder.IsVisible() || len(chosen) != 1 {
+		t.Errorf("Escape: visible = %v, %d selections", finder.IsVisible(), len(chosen))
+	}
+
+	// Enter with no matches does nothing but close
+	finder.Open(testFinderItems())
+	finder.SetQuery("zzz")
+	_ = finder.HandleKey(special("Enter"))
+	if len(chosen) != 1 {
+		t.Errorf("Enter with no matches selected %+v", chosen)
+	}
+}
@@ -93,7 +93,7 @@ func (h *HelpPanel) GetScrollOffset() int {
 }
 
 // GetBindingsForMode returns all key bindings for a specific mode
-// Mode can be "normal", "edit", "palette", or "*" for global bindings
+// Mode can be "normal", "visual", "edit", "palette", or "*" for global bindings
 func (h *HelpPanel) GetBindingsForMode(mode string) []HelpKeyBinding {
 	bindings := make([]HelpKeyBinding, 0)
 
@@ -224,6 +224,24 @@ func (h *HelpPanel) initializeKeyBindings() {
 			Category:    "Navigation",
 			Mode:        "normal",
 		},
+		{
+			Keys:        []string{"o"},
+			Description: "Toggle minimap",
+			Category:    "Navigation",
+			Mode:        "normal",
+		},
+		{
+			Keys:        []string{"/"},
+			Description: "Search nodes by ID, type, tool, expression or note",
+			Category:    "Navigation",
+			Mode:        "normal",
+		},
+		{
+			Keys:        []string{"n", "N"},
+			Description: "Jump to next/previous search match",
+			Category:    "Navigation",
+			Mode:        "normal",
+		},
 	}...)
 
 	// Node operation bindings (normal mode)
@@ -248,13 +266,13 @@ func (h *HelpPanel) initializeKeyBindings() {
 		},
 		{
 			Keys:        []string{"y"},
-			Description: "Yank (copy) selected node",
+			Description: "Yank (copy) selected nodes and the edges between them",
 			Category:    "Node Operations",
 			Mode:        "normal",
 		},
 		{
 			Keys:        []string{"p"},
-			Description: "Paste node",
+			Description: "Paste yanked nodes",
 			Category:    "Node Operations",
 			Mode:        "normal",
 		},
@@ -266,6 +284,74 @@ func (h *HelpPanel) initializeKeyBindings() {
 		},
 	}...)
 
+	// Layout bindings (normal mode)
+	h.keyBindings = append(h.keyBindings, []HelpKeyBinding{
+		{
+			Keys:        []string{"m"},
+			Description: "Add/remove selected node from multi-selection",
+			Category:    "Layout",
+			Mode:        "normal",
+		},
+		{
+			Keys:        []string{"M"},
+			Description: "Clear multi-selection",
+			Category:    "Layout",
+			Mode:        "normal",
+		},
+		{
+			Keys:        []string{"L", "T", "C"},
+			Description: "Align selected nodes left/top/center",
+			Category:    "Layout",
+			Mode:        "normal",
+		},
+		{
+			Keys:        []string{"H", "J"},
+			Description: "Distribute selected nodes horizontally/vertically",
+			Category:    "Layout",
+			Mode:        "normal",
+		},
+		{
+			Keys:        []string{"z"},
+			Description: "Collapse/expand the selected node's group",
+			Category:    "Layout",
+			Mode:        "normal",
+		},
+	}...)
+
+	// Visual mode bindings
+	h.keyBindings = append(h.keyBindings, []HelpKeyBinding{
+		{
+			Keys:        []string{"V"},
+			Description: "Start visual selection at selected node",
+			Category:    "Visual",
+			Mode:        "normal",
+		},
+		{
+			Keys:        []string{"h/j/k/l", "Tab"},
+			Description: "Extend selection to next node",
+			Category:    "Visual",
+			Mode:        "visual",
+		},
+		{
+			Keys:        []string{"Arrows"},
+			Description: "Move selected nodes",
+			Category:    "Visual",
+			Mode:        "visual",
+		},
+		{
+			Keys:        []string{"d", "y"},
+			Description: "Delete or yank selected nodes",
+			Category:    "Visual",
+			Mode:        "visual",
+		},
+		{
+			Keys:        []string{"L", "T", "C", "H", "J"},
+			Description: "Align or distribute selected nodes",
+			Category:    "Visual",
+			Mode:        "visual",
+		},
+	}...)
+
 	// Workflow operation bindings (normal mode)
 	h.keyBindings = append(h.keyBindings, []HelpKeyBinding{
 		{
@@ -320,6 +406,12 @@ func (h *HelpPanel) initializeKeyBindings() {
 			Category:    "Editing",
 			Mode:        "edit",
 		},
+		{
+			Keys:        []string{"Tab", "Shift+Tab"},
+			Description: "Complete server, tool or variable (while typing)",
+			Category:    "Editing",
+			Mode:        "edit",
+		},
 		{
 			Keys:        []string{"Ctrl+S"},
 			Description: "Save changes",
@@ -29,6 +29,7 @@ type KeyEvent struct {
 	Alt       bool   // Alt modifier
 	IsSpecial bool   // Whether this is a special key
 	Special   string // Special key name (Enter, Escape, Tab, etc.)
+	Count     int    // Numeric prefix typed before the key (0 if none)
 }
 
 // KeyHandler is a function that handles a key event
@@ -41,8 +42,13 @@ type KeyBinding struct {
 	Mode     Mode
 	IsGlobal bool   // If true, works in all modes
 	Label    string // Description for help text
+	Sequence string // Full key sequence for multi-key bindings (e.g., "gg")
+	Repeat   bool   // If true, the '.' operator can repeat this binding
 }
 
+// maxCount caps numeric prefixes so a stray "99999" cannot stall the UI
+const maxCount = 9999
+
 // KeyboardHandler manages vim-style keyboard input
 type KeyboardHandler struct {
 	mu sync.RWMutex
@@ -59,6 +65,27 @@ type KeyboardHandler struct {
 	// Pending key for multi-key sequences (e.g., 'gg')
 	pendingKey rune
 
+	// First keys of registered sequences, by mode
+	sequencePrefixes map[Mode]map[rune]bool
+
+	// Numeric prefix being typed (e.g., the 5 in 5j)
+	pendingCount int
+
+	// Last repeatable binding and the event that triggered it, for '.'
+	lastChange      *KeyBinding
+	lastChangeEvent KeyEvent
+
+	// Macro recording and playback (see macro.go)
+	macros           map[rune][]KeyEvent
+	awaitingRegister macroOp
+	macroCount       int
+	recording        rune
+	recordBuffer     []KeyEvent
+	commandStart     int
+	lastMacro        rune
+	macroDepth       int
+	macroPlayer      func(KeyEvent) error
+
 	// Buffer dimensions for boundary checks
 	maxX int
 	maxY int
@@ -70,11 +97,13 @@ type KeyboardHandler struct {
 // NewKeyboardHandler creates a new keyboard handler with default vim bindings
 func NewKeyboardHandler() *KeyboardHandler {
 	kh := &KeyboardHandler{
-		currentMode:    ModeNormal,
-		bindings:       make(map[Mode]map[string]*KeyBinding),
-		globalBindings: make(map[string]*KeyBinding),
-		pendingKey:     0,
-		pageSize:       20, // Default page size
+		currentMode:      ModeNormal,
+		bindings:         make(map[Mode]map[string]*KeyBinding),
+		globalBindings:   make(map[string]*KeyBinding),
+		pendingKey:       0,
+		sequencePrefixes: make(map[Mode]map[rune]bool),
+		macros:           make(map[rune][]KeyEvent),
+		pageSize:         20, // Default page size
 	}
 
 	// Initialize mode maps
@@ -92,6 +121,8 @@ func (kh *KeyboardHandler) SetMode(mode Mode) {
 
 	kh.currentMode = mode
 	kh.pendingKey = 0 // Clear pending keys on mode change
+	kh.pendingCount = 0
+	kh.awaitingRegister = macroNone
 }
 
 // GetMode returns the current input mode
@@ -182,50 +213,133 @@ func (kh *KeyboardHandler) UnregisterGlobalBinding(key KeyEvent) {
 	delete(kh.globalBindings, keyStr)
 }
 
-// HandleKey processes a key event and dispatches to the appropriate handler
+// HandleKey processes a key event and dispatches to the appropriate handler.
+// The handler runs without the lock held, so it may change modes. Any count
+// typed before the key is passed to the handler in event.Count.
 func (kh *KeyboardHandler) HandleKey(event KeyEvent) error {
+	_, err := kh.Dispatch(event)
+	return err
+}
+
+// Dispatch is HandleKey for callers that also pass keys on to a view. It
+// reports whether the key was consumed by the handler itself, such as the
+// register name after q-style macro keys, and should not be passed on.
+func (kh *KeyboardHandler) Dispatch(event KeyEvent) (consumed bool, err error) {
+	handler, event, consumed := kh.resolveKey(event)
+	if handler == nil {
+		return consumed, nil
+	}
+	return consumed, handler(event)
+}
+
+// resolveKey finds the handler for a key event, tracking counts, multi-key
+// sequences and macro registers, and returns the event with its count
+// filled in
+func (kh *KeyboardHandler) resolveKey(event KeyEvent) (KeyHandler, KeyEvent, bool) {
 	kh.mu.Lock()
 	defer kh.mu.Unlock()
 
+	kh.recordKey(event)
+
+	// The key after a macro key names its register
+	if kh.awaitingRegister != macroNone {
+		return kh.resolveRegister(event), event, true
+	}
+
 	keyStr := keyEventToString(event)
 
 	// Check for global bindings first
 	if binding, exists := kh.globalBindings[keyStr]; exists {
-		return binding.Handler(event)
+		kh.pendingCount = 0
+		return binding.Handler, event, false
 	}
 
-	// Check for mode-specific bindings
-	if binding, exists := kh.bindings[kh.currentMode][keyStr]; exists {
-		return binding.Handler(event)
+	// Accumulate a count prefix (e.g., the 10 in 10h)
+	if kh.isCountDigit(event, keyStr) {
+		kh.pendingCount = min(kh.pendingCount*10+int(event.Key-'0'), maxCount)
+		return nil, event, false // Wait for the command
 	}
 
-	// Handle multi-key sequences (e.g., gg)
+	event.Count = kh.pendingCount
+
+	// Complete a pending multi-key sequence (e.g., gg)
 	if kh.pendingKey != 0 {
-		sequence := string([]rune{kh.pendingKey, event.Key})
+		seqKeyStr := keyEventToString(KeyEvent{Key: kh.pendingKey}) + string(event.Key)
 		kh.pendingKey = 0 // Clear pending key
 
-		// Check for sequence bindings
-		seqKey := KeyEvent{Key: rune(sequence[0]), Shift: event.Shift}
-		seqKeyStr := keyEventToString(seqKey) + string(sequence[1])
-
-		if binding, exists := kh.bindings[kh.currentMode][seqKeyStr]; exists {
-			return binding.Handler(event)
+		if binding, exists := kh.bindings[kh.currentMode][seqKeyStr]; exists && !event.IsSpecial {
+			return kh.dispatch(binding, event), event, false
 		}
 	}
 
-	// Check if this starts a multi-key sequence
-	if event.Key == 'g' && kh.currentMode == ModeNormal {
-		kh.pendingKey = 'g'
-		return nil // Wait for next key
+	// Check for mode-specific bindings
+	if binding, exists := kh.bindings[kh.currentMode][keyStr]; exists {
+		return kh.dispatch(binding, event), event, false
 	}
 
-	// No binding found - in insert/command mode, this might be input
-	if kh.currentMode == ModeInsert || kh.currentMode == ModeCommand {
-		return nil // Allow character insertion
+	// Check if this starts a multi-key sequence; the count carries over
+	if !event.IsSpecial && !event.Ctrl && !event.Alt && kh.sequencePrefixes[kh.currentMode][event.Key] {
+		kh.pendingKey = event.Key
+		return nil, event, false // Wait for next key
 	}
 
-	// In normal/visual mode, unbound keys are ignored
-	return nil
+	// No binding found - in insert/command mode this might be input, and
+	// in normal/visual mode unbound keys are ignored
+	kh.pendingCount = 0
+	return nil, event, false
+}
+
+// isCountDigit reports whether a key extends the count prefix. Counts apply
+// in normal and visual mode; a digit bound to an action only counts once a
+// count has started, and 0 never starts one.
+func (kh *KeyboardHandler) isCountDigit(event KeyEvent, keyStr string) bool {
+	if event.IsSpecial || event.Ctrl || event.Alt || event.Key < '0' || event.Key > '9' {
+		return false
+	}
+	if kh.pendingKey != 0 || (kh.currentMode != ModeNormal && kh.currentMode != ModeVisual) {
+		return false
+	}
+	if kh.pendingCount > 0 {
+		return true
+	}
+	if _, bound := kh.bindings[kh.currentMode][keyStr]; bound {
+		return false
+	}
+	return event.Key != '0'
+}
+
+// dispatch consumes the count and records repeatable bindings for '.'
+func (kh *KeyboardHandler) dispatch(binding *KeyBinding, event KeyEvent) KeyHandler {
+	kh.pendingCount = 0
+	if binding.Repeat {
+		kh.lastChange = binding
+		kh.lastChangeEvent = event
+	}
+	return binding.Handler
+}
+
+// RepeatLastChange runs the last repeatable binding again, as vim's '.'
+// does. A non-zero count replaces the count it was first run with.
+func (kh *KeyboardHandler) RepeatLastChange(count int) error {
+	kh.mu.RLock()
+	binding, event := kh.lastChange, kh.lastChangeEvent
+	kh.mu.RUnlock()
+
+	if binding == nil {
+		return nil
+	}
+	if count > 0 {
+		event.Count = count
+	}
+	return binding.Handler(event)
+}
+
+// PendingCount returns the count prefix typed so far, or 0 if none
+func (kh *KeyboardHandler) PendingCount() int {
+	kh.mu.RLock()
+	defer kh.mu.RUnlock()
+
+	return kh.pendingCount
 }
 
 // GetBindings returns all bindings for a specific mode
@@ -280,12 +394,14 @@ func (kh *KeyboardHandler) GetAllBindings() map[Mode][]*KeyBinding {
 	return result
 }
 
-// ClearPendingKeys clears any pending multi-key sequences
+// ClearPendingKeys clears any pending multi-key sequence and count
 func (kh *KeyboardHandler) ClearPendingKeys() {
 	kh.mu.Lock()
 	defer kh.mu.Unlock()
 
 	kh.pendingKey = 0
+	kh.pendingCount = 0
+	kh.awaitingRegister = macroNone
 }
 
 // HasPendingKey returns true if there's a pending key in a multi-key sequence
@@ -293,7 +409,7 @@ func (kh *KeyboardHandler) HasPendingKey() bool {
 	kh.mu.RLock()
 	defer kh.mu.RUnlock()
 
-	return kh.pendingKey != 0
+	return kh.pendingKey != 0 || kh.awaitingRegister != macroNone
 }
 
 // keyEventToString converts a KeyEvent to a string for lookup
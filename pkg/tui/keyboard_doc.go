@@ -54,12 +54,29 @@ Normal Mode - Operations:
   y           - Copy (yank)
   p           - Paste
   u           - Undo
+  .           - Repeat last change
   Ctrl-r      - Redo
 
 Normal Mode - Search:
   /           - Start search
   n           - Next search result
   N           - Previous search result
+  Ctrl-p      - Fuzzy find nodes, workflows and commands
+  Ctrl-g      - Show the message history
+
+Normal Mode - Panes:
+  Ctrl-w v    - Split the screen side by side with the next view
+  Ctrl-w s    - Split the screen top and bottom with the next view
+  Ctrl-w w    - Move the focus to the other pane (h/j/k/l by position)
+  Ctrl-w >    - Grow the focused pane (< shrinks it, = evens them)
+  Ctrl-w x    - Swap the panes
+  Ctrl-w o    - Close the other pane (c closes the focused one)
+
+Normal Mode - Macros:
+  Q{reg}      - Record keys into register a-z, A-Z or 0-9
+  Q           - Stop recording
+  @{reg}      - Play the macro in a register
+  @@          - Play the last macro again
 
 Normal Mode - Help & Quit:
   ?           - Toggle help overlay
@@ -70,6 +87,25 @@ Command Mode:
   :q          - Quit
   :wq         - Save and quit
   :q!         - Force quit without saving
+  :open {wf}  - Open a workflow in the builder (:e, :edit)
+  :template apply {name}
+              - Replace the workflow in the builder with a template
+  :snapshot {name}
+              - Save the workflow as a named snapshot
+  :snapshots  - Compare or restore named snapshots
+  :commit {message}
+              - Save and commit to git (git_workflows)
+  :history    - Compare or check out git revisions (git_workflows)
+  :server connect|disconnect|test {id}
+              - Act on an MCP server by ID
+  :theme {name}
+              - Switch the color theme
+  :messages   - Show the message history
+  :vsplit [view], :split [view]
+              - Show a view beside or below the current one
+  :only       - Close the other pane
+  Tab         - Complete (Shift-Tab cycles backwards)
+  Up/Down     - Recall command history
   Escape      - Cancel command
   Enter       - Execute command
 
@@ -127,6 +163,32 @@ Register custom bindings for specific modes:
 			return nil
 		}, "Show help")
 
+User Keymaps
+
+Any default action can be remapped per mode in ~/.goflow/keymap.yaml (or
+$GOFLOW_CONFIG_DIR/keymap.yaml). The file only lists the actions it changes;
+an empty key unbinds an action:
+
+	normal:
+	  delete: x
+	  go_to_top: tt
+	visual:
+	  exit_visual: ""
+
+Load it and register the effective bindings:
+
+	keymap, err := LoadKeymap(DefaultKeymapPath())
+	if err != nil {
+		log.Fatal(err) // unknown actions, bad keys and conflicts
+	}
+	if err := kh.RegisterKeymapBindings(keymap, config); err != nil {
+		log.Fatal(err)
+	}
+
+Conflicts are rejected at load: two actions on one key, or a sequence such as
+"tt" alongside a single-key binding of "t". HelpText regenerates the help
+overlay from the bindings actually registered.
+
 Multi-Key Sequences
 
 The handler supports multi-key sequences like vim's 'gg':
@@ -135,6 +197,73 @@ The handler supports multi-key sequences like vim's 'gg':
 	// The handler tracks the first 'g' and waits for the second
 	// When both are received, OnGoToTop is called
 
+Counts and Repeat
+
+In normal and visual mode a number typed before a command is a count, as in
+vim. The handler collects it and passes it to the binding in event.Count;
+motions and operations from the default bindings run that many times:
+
+	5j          - Move down five times
+	10h         - Move left ten times
+	3d          - Delete three times
+
+Mode switches, gg/G, help and quit ignore counts. A digit bound to an action
+runs that action unless a count has already started, and 0 only continues a
+count. '.' repeats the last add, edge, delete or paste with its original
+count, or with a new one (2.). Custom bindings can read event.Count, and
+RepeatLastChange is available to views that bind their own repeat key.
+
+Macros
+
+Macros record raw keys, so anything typed while recording can be replayed,
+including edits handled by a view rather than a binding. Q is used because q
+quits; remap record_macro to q (and quit elsewhere) for vim's keys:
+
+	normal:
+	  quit: ""
+	  record_macro: q
+
+The key after Q or @ names the register and is consumed: Dispatch reports it
+so the caller does not pass it on. A count plays a macro that many times
+(3@a). Macros may play other macros, up to a fixed depth. By default macros
+replay through HandleKey; SetMacroPlayer replays through another path, as the
+App does so views receive the keys:
+
+	kh.SetMacroPlayer(app.handleKeyEvent)
+
+Command Registry
+
+Commands typed after ':' come from a CommandRegistry. Names may be several
+words, and views that implement CommandRegistrar add their own at startup:
+
+	func (v *MyView) RegisterCommands(registry *CommandRegistry) error {
+		return registry.Register(Command{
+			Name:     "server connect",
+			Usage:    "<id>",
+			MinArgs:  1,
+			MaxArgs:  1,
+			Run:      func(args []string) error { return v.connect(args[0]) },
+			Complete: func(args []string) []string { return v.serverIDs() },
+		})
+	}
+
+Arguments are split on spaces, with quotes for arguments containing them.
+Complete returns candidates for the next argument; the registry filters
+them by what has been typed. CommandLine provides the input line with
+editing, history and Tab completion. RegisterBuiltinCommands adds :w, :q,
+:wq and :q! on top of ExecuteCommand.
+
+Fuzzy Finder
+
+Ctrl-p opens a FuzzyFinder over the nodes on the builder canvas, the saved
+workflows and the registered commands. Typing filters them by subsequence
+match, ranking consecutive characters and word starts highest. A leading
+'@', '/' or ':' limits the search to nodes, workflows or commands. Enter
+jumps to a node, opens a workflow or runs a command; commands that take
+arguments open the command line instead. With '/', the nodes of the other
+saved workflows are searched too, by ID, tool and expression; choosing one
+opens its workflow at the node.
+
 Conflict Detection
 
 The system prevents keybinding conflicts within the same mode:
@@ -243,3 +243,99 @@ func TestKeyboardHandler_GetBindings(t *testing.T) {
 		t.Errorf("GetBindings(ModeInsert) count = %d, want 0", len(insertBindings))
 	}
 }
+
+// TestKeyboardHandler_CountPrefix tests vim-style counts before commands
+func TestKeyboardHandler_CountPrefix(t *testing.T) {
+	var down, top, inserted int
+	kh := NewKeyboardHandler()
+	err := kh.RegisterDefaultBindings(DefaultBindingsConfig{
+		OnMoveDown:        func() error { down++; return nil },
+		OnGoToTop:         func() error { top++; return nil },
+		OnEnterInsertMode: func() error { inserted++; return nil },
+	})
+	if err != nil {
+		t.Fatalf("RegisterDefaultBindings failed: %v", err)
+	}
+
+	// 10j moves down ten times
+	for _, key := range "10" {
+		_ = kh.HandleKey(KeyEvent{Key: key})
+	}
+	if kh.PendingCount() != 10 {
+		t.Errorf("PendingCount() = %d, want 10", kh.PendingCount())
+	}
+	_ = kh.HandleKey(KeyEvent{Key: 'j'})
+	if down != 10 || kh.PendingCount() != 0 {
+		t.Errorf("down = %d, pending = %d after 10j, want 10 and 0", down, kh.PendingCount())
+	}
+
+	// The count is consumed, so j alone moves once
+	_ = kh.HandleKey(KeyEvent{Key: 'j'})
+	if down != 11 {
+		t.Errorf("down = %d after j, want 11", down)
+	}
+
+	// A count carries across a sequence but non-repeating actions run once
+	for _, key := range "3gg" {
+		_ = kh.HandleKey(KeyEvent{Key: key})
+	}
+	if top != 1 {
+		t.Errorf("top = %d after 3gg, want 1", top)
+	}
+
+	// A bare 0 does not start a count
+	_ = kh.HandleKey(KeyEvent{Key: '0'})
+	if kh.PendingCount() != 0 {
+		t.Errorf("PendingCount() = %d after 0, want 0", kh.PendingCount())
+	}
+
+	// Switching modes drops a pending count, and digits are text in insert mode
+	_ = kh.HandleKey(KeyEvent{Key: '4'})
+	_ = kh.HandleKey(KeyEvent{Key: 'i'})
+	if inserted != 1 || kh.PendingCount() != 0 {
+		t.Errorf("inserted = %d, pending = %d, want 1 and 0", inserted, kh.PendingCount())
+	}
+	_ = kh.HandleKey(KeyEvent{Key: '5'})
+	if kh.PendingCount() != 0 {
+		t.Errorf("PendingCount() = %d in insert mode, want 0", kh.PendingCount())
+	}
+}
+
+// TestKeyboardHandler_RepeatLastChange tests the '.' operator
+func TestKeyboardHandler_RepeatLastChange(t *testing.T) {
+	var deleted, down int
+	kh := NewKeyboardHandler()
+	err := kh.RegisterDefaultBindings(DefaultBindingsConfig{
+		OnDelete:   func() error { deleted++; return nil },
+		OnMoveDown: func() error { down++; return nil },
+	})
+	if err != nil {
+		t.Fatalf("RegisterDefaultBindings failed: %v", err)
+	}
+
+	// Nothing to repeat yet
+	_ = kh.HandleKey(KeyEvent{Key: '.'})
+
+	press := func(keys string) {
+		for _, key := range keys {
+			_ = kh.HandleKey(KeyEvent{Key: key})
+		}
+	}
+
+	press("3d")
+	if deleted != 3 {
+		t.Fatalf("deleted = %d after 3d, want 3", deleted)
+	}
+
+	// Motions are not changes, so '.' still repeats the delete with its count
+	press("j.")
+	if deleted != 6 || down != 1 {
+		t.Errorf("deleted = %d, down = %d after j., want 6 and 1", deleted, down)
+	}
+
+	// A count on '.' replaces the original count
+	press("2.")
+	if deleted != 8 {
+		t.Errorf("deleted = %d after 2., want 8", deleted)
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -346,25 +347,41 @@ func (hf *HelpFormatter) FormatBindings(bindings []*KeyBinding) string {
 
 	var sb strings.Builder
 
+	// Registries are maps, so sort for a stable listing
+	sorted := make([]*KeyBinding, len(bindings))
+	copy(sorted, bindings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return formatBindingKey(sorted[i]) < formatBindingKey(sorted[j])
+	})
+
 	// Find max key width for alignment
 	maxWidth := 0
-	for _, binding := range bindings {
-		keyStr := FormatKeyEvent(binding.Key)
+	for _, binding := range sorted {
+		keyStr := formatBindingKey(binding)
 		if len(keyStr) > maxWidth && len(keyStr) < hf.maxKeyWidth {
 			maxWidth = len(keyStr)
 		}
 	}
 
 	// Format each binding
-	for _, binding := range bindings {
-		keyStr := FormatKeyEvent(binding.Key)
-		padding := strings.Repeat(" ", maxWidth-len(keyStr)+2)
+	for _, binding := range sorted {
+		keyStr := formatBindingKey(binding)
+		padding := strings.Repeat(" ", max(maxWidth-len(keyStr), 0)+2)
 		sb.WriteString(fmt.Sprintf("%s%s%s\n", keyStr, padding, binding.Label))
 	}
 
 	return sb.String()
 }
 
+// formatBindingKey returns the keys that trigger a binding, showing the full
+// sequence for multi-key bindings
+func formatBindingKey(binding *KeyBinding) string {
+	if binding.Sequence != "" {
+		return binding.Sequence
+	}
+	return FormatKeyEvent(binding.Key)
+}
+
 // FormatByMode formats all bindings grouped by mode
 func (hf *HelpFormatter) FormatByMode(allBindings map[Mode][]*KeyBinding) string {
 	var sb strings.Builder
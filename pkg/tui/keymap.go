@@ -0,0 +1,350 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keymap maps each action to its key, per mode. Keys use the syntax of
+// KeyEventFromString ("h", "Ctrl-d", "Escape"), or two plain characters for
+// a sequence such as "gg". An empty key leaves the action unbound.
+type Keymap map[Mode]map[string]string
+
+// keymapAction is one remappable action of the default bindings
+type keymapAction struct {
+	mode   Mode
+	name   string
+	key    string // Default key
+	label  string // Help text
+	action func(kh *KeyboardHandler, config DefaultBindingsConfig) KeyHandler
+}
+
+// keymapActions lists every action RegisterDefaultBindings can bind, in the
+// order they appear in help text
+var keymapActions = []keymapAction{
+	// Normal Mode - Navigation
+	{ModeNormal, "move_left", "h", "Move cursor left", counted(func(c DefaultBindingsConfig) func() error { return c.OnMoveLeft })},
+	{ModeNormal, "move_down", "j", "Move cursor down", counted(func(c DefaultBindingsConfig) func() error { return c.OnMoveDown })},
+	{ModeNormal, "move_up", "k", "Move cursor up", counted(func(c DefaultBindingsConfig) func() error { return c.OnMoveUp })},
+	{ModeNormal, "move_right", "l", "Move cursor right", counted(func(c DefaultBindingsConfig) func() error { return c.OnMoveRight })},
+	{ModeNormal, "word_forward", "w", "Move to next word", counted(func(c DefaultBindingsConfig) func() error { return c.OnWordForward })},
+	{ModeNormal, "word_backward", "b", "Move to previous word", counted(func(c DefaultBindingsConfig) func() error { return c.OnWordBackward })},
+	{ModeNormal, "go_to_top", "gg", "Go to top", callback(func(c DefaultBindingsConfig) func() error { return c.OnGoToTop })},
+	{ModeNormal, "go_to_bottom", "G", "Go to bottom", callback(func(c DefaultBindingsConfig) func() error { return c.OnGoToBottom })},
+	{ModeNormal, "page_up", "Ctrl-u", "Page up", counted(func(c DefaultBindingsConfig) func() error { return c.OnPageUp })},
+	{ModeNormal, "page_down", "Ctrl-d", "Page down", counted(func(c DefaultBindingsConfig) func() error { return c.OnPageDown })},
+
+	// Normal Mode - Mode switching
+	{ModeNormal, "insert_mode", "i", "Enter insert mode", switchMode(ModeInsert, func(c DefaultBindingsConfig) func() error { return c.OnEnterInsertMode })},
+	{ModeNormal, "visual_mode", "v", "Enter visual mode", switchMode(ModeVisual, func(c DefaultBindingsConfig) func() error { return c.OnEnterVisualMode })},
+	{ModeNormal, "command_mode", ":", "Enter command mode", switchMode(ModeCommand, func(c DefaultBindingsConfig) func() error { return c.OnEnterCommandMode })},
+
+	// Normal Mode - Operations
+	{ModeNormal, "add_node", "a", "Add node", counted(func(c DefaultBindingsConfig) func() error { return c.OnAddNode })},
+	{ModeNormal, "create_edge", "e", "Create edge", counted(func(c DefaultBindingsConfig) func() error { return c.OnCreateEdge })},
+	{ModeNormal, "delete", "d", "Delete", counted(func(c DefaultBindingsConfig) func() error { return c.OnDelete })},
+	{ModeNormal, "rename", "r", "Rename", callback(func(c DefaultBindingsConfig) func() error { return c.OnRename })},
+	{ModeNormal, "copy", "y", "Copy (yank)", callback(func(c DefaultBindingsConfig) func() error { return c.OnCopy })},
+	{ModeNormal, "paste", "p", "Paste", counted(func(c DefaultBindingsConfig) func() error { return c.OnPaste })},
+	{ModeNormal, "undo", "u", "Undo", counted(func(c DefaultBindingsConfig) func() error { return c.OnUndo })},
+	{ModeNormal, "repeat", ".", "Repeat last change", repeatChange},
+	{ModeNormal, "redo", "Ctrl-r", "Redo", counted(func(c DefaultBindingsConfig) func() error { return c.OnRedo })},
+
+	// Normal Mode - Search
+	{ModeNormal, "search", "/", "Search", switchMode(ModeCommand, func(c DefaultBindingsConfig) func() error { return c.OnSearch })},
+	{ModeNormal, "next_search", "n", "Next search result", counted(func(c DefaultBindingsConfig) func() error { return c.OnNextSearch })},
+	{ModeNormal, "prev_search", "N", "Previous search result", counted(func(c DefaultBindingsConfig) func() error { return c.OnPrevSearch })},
+
+	{ModeNormal, "find", "Ctrl-p", "Fuzzy find nodes, workflows and commands", callback(func(c DefaultBindingsConfig) func() error { return c.OnFind })},
+	{ModeNormal, "pane", "Ctrl-w", "Pane command: w focus, v/s split, </> resize, o only", callback(func(c DefaultBindingsConfig) func() error { return c.OnPane })},
+	{ModeNormal, "messages", "Ctrl-g", "Show the message history", callback(func(c DefaultBindingsConfig) func() error { return c.OnMessages })},
+
+	// Normal Mode - Macros
+	{ModeNormal, "record_macro", "Q", "Record macro into register (again to stop)", recordMacro},
+	{ModeNormal, "play_macro", "@", "Play macro from register (@@ for last)", playMacro},
+
+	// Normal Mode - Help, quit and overlays
+	{ModeNormal, "toggle_help", "?", "Toggle help", callback(func(c DefaultBindingsConfig) func() error { return c.OnToggleHelp })},
+	{ModeNormal, "quit", "q", "Quit", callback(func(c DefaultBindingsConfig) func() error { return c.OnQuit })},
+	{ModeNormal, "close_overlays", "Escape", "Close overlays", callback(func(c DefaultBindingsConfig) func() error { return c.OnToggleHelp })},
+
+	// Other modes return to normal mode
+	{ModeInsert, "normal_mode", "Escape", "Exit to normal mode", switchMode(ModeNormal, func(c DefaultBindingsConfig) func() error { return c.OnEnterNormalMode })},
+	{ModeVisual, "exit_visual", "v", "Exit visual mode", switchMode(ModeNormal, func(c DefaultBindingsConfig) func() error { return c.OnEnterNormalMode })},
+	{ModeVisual, "normal_mode", "Escape", "Exit to normal mode", switchMode(ModeNormal, func(c DefaultBindingsConfig) func() error { return c.OnEnterNormalMode })},
+	{ModeCommand, "cancel", "Escape", "Cancel command", switchMode(ModeNormal, func(c DefaultBindingsConfig) func() error { return c.OnEnterNormalMode })},
+	{ModeCommand, "execute", "Enter", "Execute command", switchMode(ModeNormal, func(c DefaultBindingsConfig) func() error { return c.OnEnterNormalMode })},
+}
+
+// callback binds an action to a DefaultBindingsConfig callback
+func callback(get func(DefaultBindingsConfig) func() error) func(*KeyboardHandler, DefaultBindingsConfig) KeyHandler {
+	return func(kh *KeyboardHandler, config DefaultBindingsConfig) KeyHandler {
+		return wrapHandler(get(config))
+	}
+}
+
+// counted binds an action to a callback that a count prefix repeats
+func counted(get func(DefaultBindingsConfig) func() error) func(*KeyboardHandler, DefaultBindingsConfig) KeyHandler {
+	return func(kh *KeyboardHandler, config DefaultBindingsConfig) KeyHandler {
+		return repeatHandler(get(config))
+	}
+}
+
+// repeatChange binds the '.' operator
+func repeatChange(kh *KeyboardHandler, config DefaultBindingsConfig) KeyHandler {
+	return func(event KeyEvent) error {
+		return kh.RepeatLastChange(event.Count)
+	}
+}
+
+// recordMacro binds the macro recording toggle
+func recordMacro(kh *KeyboardHandler, config DefaultBindingsConfig) KeyHandler {
+	return func(event KeyEvent) error {
+		kh.ToggleRecording()
+		return nil
+	}
+}
+
+// playMacro binds macro playback; a count plays the macro that many times
+func playMacro(kh *KeyboardHandler, config DefaultBindingsConfig) KeyHandler {
+	return func(event KeyEvent) error {
+		kh.PlayMacro(event.Count)
+		return nil
+	}
+}
+
+// repeatableActions are the changes '.' can repeat
+var repeatableActions = map[string]bool{
+	"add_node":    true,
+	"create_edge": true,
+	"delete":      true,
+	"paste":       true,
+}
+
+// switchMode binds an action that changes mode before running a callback
+func switchMode(mode Mode, get func(DefaultBindingsConfig) func() error) func(*KeyboardHandler, DefaultBindingsConfig) KeyHandler {
+	return func(kh *KeyboardHandler, config DefaultBindingsConfig) KeyHandler {
+		handler := get(config)
+		return func(event KeyEvent) error {
+			kh.SetMode(mode)
+			if handler != nil {
+				return handler()
+			}
+			return nil
+		}
+	}
+}
+
+// DefaultKeymap returns the default vim-style keymap
+func DefaultKeymap() Keymap {
+	keymap := make(Keymap)
+	for _, action := range keymapActions {
+		if keymap[action.mode] == nil {
+			keymap[action.mode] = make(map[string]string)
+		}
+		keymap[action.mode][action.name] = action.key
+	}
+	return keymap
+}
+
+// DefaultKeymapPath returns keymap.yaml in GOFLOW_CONFIG_DIR, or in
+// ~/.goflow when it is not set
+func DefaultKeymapPath() string {
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "keymap.yaml")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "keymap.yaml")
+	}
+	return filepath.Join(homeDir, ".goflow", "keymap.yaml")
+}
+
+// LoadKeymap reads a keymap file and applies it over the defaults, so the
+// file only needs the actions it remaps:
+//
+//	normal:
+//	  add_node: A
+//	  delete: x
+//	  go_to_top: tt
+//	visual:
+//	  exit_visual: ""   # unbind
+//
+// A missing file yields the default keymap. The result is validated, and
+// every unknown action, bad key and conflict is reported.
+func LoadKeymap(path string) (Keymap, error) {
+	keymap := DefaultKeymap()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return keymap, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keymap: %w", err)
+	}
+
+	var overrides map[string]map[string]string
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse keymap %s: %w", path, err)
+	}
+
+	var errs []error
+	for modeName, actions := range overrides {
+		mode := Mode(modeName)
+		if keymap[mode] == nil {
+			errs = append(errs, fmt.Errorf("unknown mode: %s", modeName))
+			continue
+		}
+		for name, key := range actions {
+			if _, ok := keymap[mode][name]; !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown action: %s", mode, name))
+				continue
+			}
+			keymap[mode][name] = key
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid keymap %s: %w", path, joinSorted(errs))
+	}
+
+	if err := keymap.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid keymap %s: %w", path, err)
+	}
+	return keymap, nil
+}
+
+// Validate checks that every key parses and that no two actions in the same
+// mode share a key. A sequence also conflicts with a binding of its first key.
+func (km Keymap) Validate() error {
+	var errs []error
+	for mode, actions := range km {
+		owners := make(map[string]string)   // binding key -> action
+		prefixes := make(map[string]string) // sequence prefix -> action
+
+		names := make([]string, 0, len(actions))
+		for name := range actions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			spec := actions[name]
+			if spec == "" {
+				continue
+			}
+			key, sequence, err := parseKeySpec(spec)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", mode, name, err))
+				continue
+			}
+
+			bindingKey := keyEventToString(key)
+			if sequence != "" {
+				bindingKey = keyEventToString(key) + string([]rune(sequence)[1])
+				if other, ok := owners[keyEventToString(key)]; ok {
+					errs = append(errs, fmt.Errorf("%s: %s (%s) conflicts with %s (%s)", mode, name, spec, other, actions[other]))
+				}
+				prefixes[keyEventToString(key)] = name
+			} else if other, ok := prefixes[bindingKey]; ok {
+				errs = append(errs, fmt.Errorf("%s: %s (%s) conflicts with %s (%s)", mode, name, spec, other, actions[other]))
+			}
+
+			if other, ok := owners[bindingKey]; ok {
+				errs = append(errs, fmt.Errorf("%s: %s and %s are both bound to %s", mode, other, name, spec))
+				continue
+			}
+			owners[bindingKey] = name
+		}
+	}
+	return joinSorted(errs)
+}
+
+// Key returns the key bound to an action, or "" if it is unbound
+func (km Keymap) Key(mode Mode, action string) string {
+	return km[mode][action]
+}
+
+// parseKeySpec parses a keymap key. Two plain characters are a sequence, in
+// which case key is the first and sequence is the whole spec.
+func parseKeySpec(spec string) (key KeyEvent, sequence string, err error) {
+	key, err = KeyEventFromString(spec)
+	if err != nil {
+		runes := []rune(spec)
+		if len(runes) == 2 && !strings.ContainsAny(spec, "- ") {
+			return KeyEvent{Key: runes[0]}, spec, nil
+		}
+		return KeyEvent{}, "", err
+	}
+	if !key.IsSpecial && key.Shift && key.Key >= 'a' && key.Key <= 'z' {
+		// "Shift-g" and "G" are the same key
+		key = KeyEvent{Key: key.Key - 32, Ctrl: key.Ctrl, Alt: key.Alt}
+	}
+	return key, "", nil
+}
+
+// joinSorted joins errors in message order so reports are stable
+func joinSorted(errs []error) error {
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+	return errors.Join(errs...)
+}
+
+// RegisterKeymapBindings registers the default actions on the keys given
+// by keymap. The keymap is validated first; actions with no key are skipped.
+func (kh *KeyboardHandler) RegisterKeymapBindings(keymap Keymap, config DefaultBindingsConfig) error {
+	if err := keymap.Validate(); err != nil {
+		return fmt.Errorf("invalid keymap: %w", err)
+	}
+
+	for _, action := range keymapActions {
+		spec := keymap.Key(action.mode, action.name)
+		if spec == "" {
+			continue
+		}
+		key, sequence, err := parseKeySpec(spec)
+		if err != nil {
+			return fmt.Errorf("register %s: %w", action.name, err)
+		}
+
+		handler := action.action(kh, config)
+		if sequence != "" {
+			err = kh.registerSequence(action.mode, sequence, handler, action.label)
+		} else {
+			err = kh.RegisterBinding(action.mode, key, handler, action.label)
+		}
+		if err != nil {
+			return fmt.Errorf("register %s: %w", spec, err)
+		}
+		if repeatableActions[action.name] {
+			kh.markRepeatable(action.mode, key, sequence)
+		}
+	}
+	return nil
+}
+
+// markRepeatable lets '.' repeat the binding registered for a key
+func (kh *KeyboardHandler) markRepeatable(mode Mode, key KeyEvent, sequence string) {
+	kh.mu.Lock()
+	defer kh.mu.Unlock()
+
+	keyStr := keyEventToString(key)
+	if sequence != "" {
+		keyStr += string([]rune(sequence)[1])
+	}
+	if binding, exists := kh.bindings[mode][keyStr]; exists {
+		binding.Repeat = true
+	}
+}
+
+// HelpText formats the effective bindings, grouped by mode, for the help
+// overlay. It reflects any keymap the bindings were registered from.
+func (kh *KeyboardHandler) HelpText() string {
+	return NewHelpFormatter().FormatByMode(kh.GetAllBindings())
+}
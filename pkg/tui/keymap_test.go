@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeKeymap writes a keymap file into a temp dir and returns its path
+func writeKeymap(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keymap.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write keymap: %v", err)
+	}
+	return path
+}
+
+func TestLoadKeymap(t *testing.T) {
+	// A missing file yields the defaults
+	keymap, err := LoadKeymap(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadKeymap(missing) error = %v", err)
+	}
+	if keymap.Key(ModeNormal, "delete") != "d" || keymap.Key(ModeNormal, "go_to_top") != "gg" {
+		t.Errorf("Unexpected default keymap: %v", keymap[ModeNormal])
+	}
+
+	keymap, err = LoadKeymap(writeKeymap(t, `
+normal:
+  delete: x
+  go_to_top: tt
+visual:
+  exit_visual: ""
+`))
+	if err != nil {
+		t.Fatalf("LoadKeymap() error = %v", err)
+	}
+	if got := keymap.Key(ModeNormal, "delete"); got != "x" {
+		t.Errorf("delete = %q, want x", got)
+	}
+	if got := keymap.Key(ModeNormal, "go_to_top"); got != "tt" {
+		t.Errorf("go_to_top = %q, want tt", got)
+	}
+	if got := keymap.Key(ModeVisual, "exit_visual"); got != "" {
+		t.Errorf("exit_visual = %q, want unbound", got)
+	}
+	if got := keymap.Key(ModeNormal, "move_left"); got != "h" {
+		t.Errorf("move_left = %q, want default h", got)
+	}
+}
+
+func TestLoadKeymap_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "unknown action and mode",
+			content: "normal:\n  teleport: t\nreplace:\n  quit: q\n",
+			want:    []string{"unknown action: teleport", "unknown mode: replace"},
+		},
+		{
+			name:    "duplicate key",
+			content: "normal:\n  delete: j\n",
+			want:    []string{"delete and move_down are both bound to j"},
+		},
+		{
+			name:    "sequence prefix conflict",
+			content: "normal:\n  go_to_top: dd\n",
+			want:    []string{"conflicts with delete (d)"},
+		},
+		{
+			name:    "bad key",
+			content: "normal:\n  quit: Ctrl-\n",
+			want:    []string{"normal: quit:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadKeymap(writeKeymap(t, tt.content))
+			if err == nil {
+				t.Fatal("LoadKeymap() succeeded, want error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterKeymapBindings(t *testing.T) {
+	keymap := DefaultKeymap()
+	keymap[ModeNormal]["delete"] = "x"
+	keymap[ModeNormal]["go_to_top"] = "tt"
+
+	var deleted, top, inserted int
+	config := DefaultBindingsConfig{
+		OnDelete:          func() error { deleted++; return nil },
+		OnGoToTop:         func() error { top++; return nil },
+		OnEnterInsertMode: func() error { inserted++; return nil },
+	}
+
+	kh := NewKeyboardHandler()
+	if err := kh.RegisterKeymapBindings(keymap, config); err != nil {
+		t.Fatalf("RegisterKeymapBindings() error = %v", err)
+	}
+
+	_ = kh.HandleKey(KeyEvent{Key: 'd'})
+	_ = kh.HandleKey(KeyEvent{Key: 'x'})
+	if deleted != 1 {
+		t.Errorf("OnDelete called %d times, want 1", deleted)
+	}
+
+	_ = kh.HandleKey(KeyEvent{Key: 'g'})
+	_ = kh.HandleKey(KeyEvent{Key: 'g'})
+	_ = kh.HandleKey(KeyEvent{Key: 't'})
+	_ = kh.HandleKey(KeyEvent{Key: 't'})
+	if top != 1 {
+		t.Errorf("OnGoToTop called %d times, want 1", top)
+	}
+
+	// Mode switching handlers run outside the handler's lock
+	_ = kh.HandleKey(KeyEvent{Key: 'i'})
+	if kh.GetMode() != ModeInsert || inserted != 1 {
+		t.Errorf("mode = %s, inserted = %d, want insert mode", kh.GetMode(), inserted)
+	}
+	_ = kh.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"})
+	if kh.GetMode() != ModeNormal {
+		t.Errorf("mode = %s after Escape, want normal", kh.GetMode())
+	}
+
+	help := kh.HelpText()
+	for _, want := range []string{"x", "Delete", "tt", "Go to top"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("HelpText() missing %q:\n%s", want, help)
+		}
+	}
+	if strings.Contains(help, " gg ") || strings.Contains(help, "\ngg") {
+		t.Errorf("HelpText() still shows the default gg binding:\n%s", help)
+	}
+}
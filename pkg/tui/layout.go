@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/dshills/goterm"
+)
+
+// The layout splits the screen between two views, such as the builder and
+// the execution monitor side by side. One pane has the focus: it shows the
+// current view, which gets the keys. Ctrl-w followed by a pane command
+// moves the focus, resizes the panes or closes one, as in vim.
+
+// SplitDirection is how the screen is divided between panes
+type SplitDirection int
+
+const (
+	// SplitNone gives the whole screen to the current view
+	SplitNone SplitDirection = iota
+	// SplitVertical puts the panes side by side
+	SplitVertical
+	// SplitHorizontal puts one pane above the other
+	SplitHorizontal
+)
+
+// Split sizes, as the percentage of the screen given to the first pane
+const (
+	defaultSplitRatio = 50
+	minSplitRatio     = 20
+	maxSplitRatio     = 80
+	splitResizeStep   = 5
+)
+
+// Layout tracks how the screen is split. The focused pane always shows the
+// view manager's current view; the layout remembers the view in the other
+// pane and where each pane is.
+type Layout struct {
+	direction  SplitDirection
+	ratio      int    // Percentage of the screen given to the first pane
+	other      string // View in the pane without the focus
+	focusFirst bool   // Whether the focused pane is the first one
+	pending    bool   // Ctrl-w pressed; the next key is a pane command
+
+	buffers [2]*goterm.Screen // Off-screen buffers the panes are drawn into
+}
+
+// NewLayout creates a layout with the screen unsplit
+func NewLayout() *Layout {
+	return &Layout{ratio: defaultSplitRatio, focusFirst: true}
+}
+
+// IsSplit returns whether the screen is split
+func (l *Layout) IsSplit() bool {
+	return l.direction != SplitNone
+}
+
+// Direction returns how the screen is split
+func (l *Layout) Direction() SplitDirection {
+	return l.direction
+}
+
+// Other returns the view in the pane without the focus, or "" if the
+// screen is not split
+func (l *Layout) Other() string {
+	return l.other
+}
+
+// Split divides the screen, showing the current view in the first pane,
+// with the focus, and other in the second
+func (l *Layout) Split(direction SplitDirection, other string) {
+	l.direction = direction
+	l.other = other
+	l.focusFirst = true
+}
+
+// Unsplit gives the whole screen back to the current view. It returns the
+// view that was in the other pane.
+func (l *Layout) Unsplit() string {
+	other := l.other
+	l.direction, l.other, l.pending = SplitNone, "", false
+	l.buffers = [2]*goterm.Screen{}
+	return other
+}
+
+// SwapFocus records that the focus moved to the other pane, which showed
+// next; current now shows in the pane left behind
+func (l *Layout) SwapFocus(current string) {
+	l.other = current
+	l.focusFirst = !l.focusFirst
+}
+
+// SwapPanes exchanges the panes' positions, keeping the focus on its view
+func (l *Layout) SwapPanes() {
+	l.focusFirst = !l.focusFirst
+}
+
+// Resize grows the focused pane by delta percent of the screen, or shrinks
+// it for a negative delta, within the limits
+func (l *Layout) Resize(delta int) {
+	if !l.focusFirst {
+		delta = -delta
+	}
+	l.ratio = max(minSplitRatio, min(l.ratio+delta, maxSplitRatio))
+}
+
+// Equalize gives both panes the same size
+func (l *Layout) Equalize() {
+	l.ratio = defaultSplitRatio
+}
+
+// Panes returns the areas of the first and second panes on a screen of the
+// given size, with a one-cell divider between them
+func (l *Layout) Panes(width, height int) (first, second Rect) {
+	switch l.direction {
+	case SplitVertical:
+		size := max(1, min((width-1)*l.ratio/100, width-2))
+		return Rect{Width: size, Height: height}, Rect{X: size + 1, Width: width - size - 1, Height: height}
+	case SplitHorizontal:
+		size := max(1, min((height-1)*l.ratio/100, height-2))
+		return Rect{Width: width, Height: size}, Rect{Y: size + 1, Width: width, Height: height - size - 1}
+	}
+	return Rect{Width: width, Height: height}, Rect{}
+}
+
+// Render draws the focused view and the other view in their panes. The
+// pane without the focus is dimmed.
+func (l *Layout) Render(screen *goterm.Screen, focused, other View) error {
+	width, height := screen.Size()
+	first, second := l.Panes(width, height)
+	if first.Width < 1 || first.Height < 1 || second.Width < 1 || second.Height < 1 {
+		// Too small to split
+		return focused.Render(screen)
+	}
+
+	views := [2]View{focused, other}
+	if !l.focusFirst {
+		views = [2]View{other, focused}
+	}
+	for i, area := range [2]Rect{first, second} {
+		buffer := l.buffers[i]
+		if buffer == nil {
+			buffer = goterm.NewScreen(area.Width, area.Height)
+			l.buffers[i] = buffer
+		} else if w, h := buffer.Size(); w != area.Width || h != area.Height {
+			buffer.Resize(area.Width, area.Height)
+		}
+		buffer.Clear()
+		if err := views[i].Render(buffer); err != nil {
+			return fmt.Errorf("%s pane: %w", views[i].Name(), err)
+		}
+
+		dim := views[i] != focused
+		for y := 0; y < area.Height; y++ {
+			for x := 0; x < area.Width; x++ {
+				cell := buffer.GetCell(x, y)
+				if dim {
+					cell.Style |= goterm.StyleDim
+				}
+				screen.SetCell(area.X+x, area.Y+y, cell)
+			}
+		}
+	}
+
+	// The divider
+	theme := CurrentTheme()
+	divider := goterm.NewCell('│', theme.PanelBorder, theme.Background, goterm.StyleNone)
+	if l.direction == SplitHorizontal {
+		divider.Ch = '─'
+		for x := 0; x < width; x++ {
+			screen.SetCell(x, first.Height, divider)
+		}
+		return nil
+	}
+	for y := 0; y < height; y++ {
+		screen.SetCell(first.Width, y, divider)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+)
+
+// maxMacroDepth limits macros that play other macros, so a macro that plays
+// itself stops instead of recursing forever
+const maxMacroDepth = 20
+
+// macroOp is a macro command waiting for its register name
+type macroOp int
+
+const (
+	macroNone macroOp = iota
+	macroRecord
+	macroPlay
+)
+
+// lastMacroRegister names the most recently played macro, as in @@
+const lastMacroRegister = '@'
+
+// ToggleRecording starts or stops q-style macro recording. Starting waits
+// for the next key, which names the register; stopping stores every key
+// typed since then, minus the keys of the command that stopped it.
+func (kh *KeyboardHandler) ToggleRecording() {
+	kh.mu.Lock()
+	defer kh.mu.Unlock()
+
+	if kh.recording == 0 {
+		kh.awaitingRegister = macroRecord
+		return
+	}
+
+	macro := make([]KeyEvent, kh.commandStart)
+	copy(macro, kh.recordBuffer[:kh.commandStart])
+	kh.macros[kh.recording] = macro
+	kh.recording = 0
+	kh.recordBuffer = nil
+}
+
+// PlayMacro waits for the next key and replays the macro in that register
+// count times (at least once); @ replays the last macro played
+func (kh *KeyboardHandler) PlayMacro(count int) {
+	kh.mu.Lock()
+	defer kh.mu.Unlock()
+
+	kh.awaitingRegister = macroPlay
+	kh.macroCount = count
+}
+
+// SetMacroPlayer sets the function macros are replayed through. It defaults
+// to HandleKey; an application that also passes keys to views replays
+// through its own key path so views see the macro too.
+func (kh *KeyboardHandler) SetMacroPlayer(player func(KeyEvent) error) {
+	kh.mu.Lock()
+	defer kh.mu.Unlock()
+
+	kh.macroPlayer = player
+}
+
+// RecordingRegister returns the register being recorded, or 0 if none
+func (kh *KeyboardHandler) RecordingRegister() rune {
+	kh.mu.RLock()
+	defer kh.mu.RUnlock()
+
+	return kh.recording
+}
+
+// Macro returns a copy of the keys stored in a register
+func (kh *KeyboardHandler) Macro(register rune) []KeyEvent {
+	kh.mu.RLock()
+	defer kh.mu.RUnlock()
+
+	return append([]KeyEvent(nil), kh.macros[register]...)
+}
+
+// SetMacro stores keys in a register, replacing any recorded macro
+func (kh *KeyboardHandler) SetMacro(register rune, keys []KeyEvent) error {
+	if !isMacroRegister(register) {
+		return fmt.Errorf("invalid macro register: %c", register)
+	}
+
+	kh.mu.Lock()
+	defer kh.mu.Unlock()
+
+	kh.macros[register] = append([]KeyEvent(nil), keys...)
+	return nil
+}
+
+// recordKey appends a key to the macro being recorded. Keys replayed from
+// a macro are not recorded again. Must be called with the lock held.
+func (kh *KeyboardHandler) recordKey(event KeyEvent) {
+	if kh.recording == 0 || kh.macroDepth > 0 {
+		return
+	}
+	if kh.pendingCount == 0 && kh.pendingKey == 0 && kh.awaitingRegister == macroNone {
+		kh.commandStart = len(kh.recordBuffer)
+	}
+	kh.recordBuffer = append(kh.recordBuffer, event)
+}
+
+// captureKey records a key that bypasses the handler, such as input to the
+// command line, into the macro being recorded
+func (kh *KeyboardHandler) captureKey(event KeyEvent) {
+	kh.mu.Lock()
+	defer kh.mu.Unlock()
+
+	kh.recordKey(event)
+}
+
+// resolveRegister consumes the register name after a macro key. Escape or
+// an invalid register cancels. Must be called with the lock held.
+func (kh *KeyboardHandler) resolveRegister(event KeyEvent) KeyHandler {
+	op, count := kh.awaitingRegister, kh.macroCount
+	kh.awaitingRegister = macroNone
+	kh.macroCount = 0
+	kh.pendingCount = 0
+
+	if event.IsSpecial || event.Ctrl || event.Alt {
+		return nil
+	}
+	register := event.Key
+
+	switch op {
+	case macroRecord:
+		if !isMacroRegister(register) {
+			return nil
+		}
+		kh.recording = register
+		kh.recordBuffer = nil
+		kh.commandStart = 0
+		return nil
+	case macroPlay:
+		if register != lastMacroRegister && !isMacroRegister(register) {
+			return nil
+		}
+		return func(KeyEvent) error {
+			return kh.playMacro(register, count)
+		}
+	}
+	return nil
+}
+
+// playMacro replays a register through the macro player
+func (kh *KeyboardHandler) playMacro(register rune, count int) error {
+	kh.mu.Lock()
+	if register == lastMacroRegister {
+		register = kh.lastMacro
+	}
+	macro, exists := kh.macros[register]
+	if !exists || len(macro) == 0 {
+		kh.mu.Unlock()
+		return fmt.Errorf("macro register %c is empty", register)
+	}
+	if kh.macroDepth >= maxMacroDepth {
+		kh.mu.Unlock()
+		return fmt.Errorf("macro @%c nested more than %d deep", register, maxMacroDepth)
+	}
+	kh.lastMacro = register
+	kh.macroDepth++
+	player := kh.macroPlayer
+	if player == nil {
+		player = kh.HandleKey
+	}
+	kh.mu.Unlock()
+
+	defer func() {
+		kh.mu.Lock()
+		kh.macroDepth--
+		kh.mu.Unlock()
+	}()
+
+	for i := 0; i < max(count, 1); i++ {
+		for _, event := range macro {
+			if err := player(event); err != nil {
+				return fmt.Errorf("macro @%c: %w", register, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isMacroRegister reports whether a key can name a macro register
+func isMacroRegister(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
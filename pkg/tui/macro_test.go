@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+// newMacroTestHandler returns a handler with default bindings that counts
+// node additions and downward moves
+func newMacroTestHandler(t *testing.T) (*KeyboardHandler, *int, *int) {
+	t.Helper()
+	var added, down int
+	kh := NewKeyboardHandler()
+	err := kh.RegisterDefaultBindings(DefaultBindingsConfig{
+		OnAddNode:  func() error { added++; return nil },
+		OnMoveDown: func() error { down++; return nil },
+	})
+	if err != nil {
+		t.Fatalf("RegisterDefaultBindings failed: %v", err)
+	}
+	return kh, &added, &down
+}
+
+// pressKeys sends plain character keys to a handler
+func pressKeys(t *testing.T, kh *KeyboardHandler, keys string) {
+	t.Helper()
+	for _, key := range keys {
+		if err := kh.HandleKey(KeyEvent{Key: key}); err != nil {
+			t.Fatalf("HandleKey(%c) failed: %v", key, err)
+		}
+	}
+}
+
+func TestKeyboardHandler_MacroRecordAndPlay(t *testing.T) {
+	kh, added, down := newMacroTestHandler(t)
+
+	// Qa starts recording; the register name is consumed
+	pressKeys(t, kh, "Q")
+	consumed, err := kh.Dispatch(KeyEvent{Key: 'a'})
+	if err != nil || !consumed {
+		t.Fatalf("Dispatch(register) = %v, %v, want consumed", consumed, err)
+	}
+	if kh.RecordingRegister() != 'a' || *added != 0 {
+		t.Fatalf("recording = %c, added = %d, want recording a", kh.RecordingRegister(), *added)
+	}
+
+	// Keys still run while recording; Q stops without being recorded
+	pressKeys(t, kh, "a2jQ")
+	if *added != 1 || *down != 2 {
+		t.Errorf("added = %d, down = %d while recording, want 1 and 2", *added, *down)
+	}
+	if kh.RecordingRegister() != 0 {
+		t.Errorf("still recording into %c", kh.RecordingRegister())
+	}
+	var recorded strings.Builder
+	for _, event := range kh.Macro('a') {
+		recorded.WriteRune(event.Key)
+	}
+	if recorded.String() != "a2j" {
+		t.Errorf("Macro(a) = %q, want a2j", recorded.String())
+	}
+
+	// @a replays once, 3@a three times, @@ the last macro again
+	pressKeys(t, kh, "@a")
+	if *added != 2 || *down != 4 {
+		t.Errorf("added = %d, down = %d after @a, want 2 and 4", *added, *down)
+	}
+	pressKeys(t, kh, "3@a")
+	if *added != 5 || *down != 10 {
+		t.Errorf("added = %d, down = %d after 3@a, want 5 and 10", *added, *down)
+	}
+	pressKeys(t, kh, "@@")
+	if *added != 6 {
+		t.Errorf("added = %d after @@, want 6", *added)
+	}
+}
+
+func TestKeyboardHandler_MacroErrors(t *testing.T) {
+	kh, added, _ := newMacroTestHandler(t)
+
+	if err := kh.HandleKey(KeyEvent{Key: '@'}); err != nil {
+		t.Fatalf("HandleKey(@) failed: %v", err)
+	}
+	if err := kh.HandleKey(KeyEvent{Key: 'z'}); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("@z error = %v, want empty register", err)
+	}
+
+	// Escape cancels the register prompt
+	pressKeys(t, kh, "Q")
+	_ = kh.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"})
+	if kh.RecordingRegister() != 0 || kh.HasPendingKey() {
+		t.Error("Escape did not cancel macro recording")
+	}
+
+	// A macro that plays itself stops at the depth limit
+	if err := kh.SetMacro('r', []KeyEvent{{Key: 'a'}, {Key: '@'}, {Key: 'r'}}); err != nil {
+		t.Fatalf("SetMacro failed: %v", err)
+	}
+	pressKeys(t, kh, "@")
+	err := kh.HandleKey(KeyEvent{Key: 'r'})
+	if err == nil || !strings.Contains(err.Error(), "nested") {
+		t.Errorf("recursive macro error = %v, want nesting limit", err)
+	}
+	if *added != maxMacroDepth {
+		t.Errorf("added = %d, want %d", *added, maxMacroDepth)
+	}
+
+	if err := kh.SetMacro('!', nil); err == nil {
+		t.Error("SetMacro accepted an invalid register")
+	}
+}
+
+func TestKeyboardHandler_MacroPlayer(t *testing.T) {
+	kh, _, _ := newMacroTestHandler(t)
+
+	// A custom player sees the replayed keys, e.g. to pass them to a view
+	var played []rune
+	kh.SetMacroPlayer(func(event KeyEvent) error {
+		played = append(played, event.Key)
+		return kh.HandleKey(event)
+	})
+	if err := kh.SetMacro('m', []KeyEvent{{Key: 'j'}, {Key: 'a'}}); err != nil {
+		t.Fatalf("SetMacro failed: %v", err)
+	}
+	pressKeys(t, kh, "@m")
+	if string(played) != "ja" {
+		t.Errorf("played = %q, want ja", string(played))
+	}
+}
@@ -58,6 +58,14 @@ func NewNodePalette() *NodePalette {
 					"expression": "",
 				},
 			},
+			{
+				typeName:    "Switch",
+				description: "Multi-way branching on labeled cases",
+				icon:        "🔀",
+				defaultConfig: map[string]interface{}{
+					"name": "switch",
+				},
+			},
 			{
 				typeName:    "Loop",
 				description: "Iterate over collections",
@@ -77,6 +85,51 @@ func NewNodePalette() *NodePalette {
 					"branches": 2,
 				},
 			},
+			{
+				typeName:    "Try",
+				description: "Error scope around body nodes",
+				icon:        "🛡",
+				defaultConfig: map[string]interface{}{
+					"name": "try",
+				},
+			},
+			{
+				typeName:    "Catch",
+				description: "Capture a failure into a variable",
+				icon:        "🩹",
+				defaultConfig: map[string]interface{}{
+					"name":          "catch",
+					"errorVariable": "error",
+				},
+			},
+			{
+				typeName:    "Delay",
+				description: "Wait for a duration or until a time",
+				icon:        "⏳",
+				defaultConfig: map[string]interface{}{
+					"name":     "delay",
+					"duration": "1m",
+				},
+			},
+			{
+				typeName:    "Approval",
+				description: "Wait for a human to approve",
+				icon:        "✋",
+				defaultConfig: map[string]interface{}{
+					"name":    "approval",
+					"message": "Approve to continue?",
+				},
+			},
+			{
+				typeName:    "Batch Tool",
+				description: "Execute an MCP tool for each item of a collection",
+				icon:        "🧰",
+				defaultConfig: map[string]interface{}{
+					"name":       "batch",
+					"collection": "items",
+					"item":       "item",
+				},
+			},
 			{
 				typeName:    "End",
 				description: "Exit point with output",
@@ -192,6 +245,15 @@ func (p *NodePalette) CreateNode() (workflow.Node, error) {
 			OutputVariable: "result",
 		}, nil
 
+	case "Batch Tool":
+		return &workflow.BatchToolNode{
+			ID:             nodeID,
+			Collection:     selected.defaultConfig["collection"].(string),
+			ItemVariable:   selected.defaultConfig["item"].(string),
+			Parameters:     make(map[string]string),
+			OutputVariable: "results",
+		}, nil
+
 	case "Transform":
 		return &workflow.TransformNode{
 			ID:             nodeID,
@@ -206,6 +268,12 @@ func (p *NodePalette) CreateNode() (workflow.Node, error) {
 			Condition: selected.defaultConfig["expression"].(string),
 		}, nil
 
+	case "Switch":
+		return &workflow.SwitchNode{
+			ID:    nodeID,
+			Cases: []workflow.SwitchCase{},
+		}, nil
+
 	case "Loop":
 		return &workflow.LoopNode{
 			ID:           nodeID,
@@ -226,6 +294,30 @@ func (p *NodePalette) CreateNode() (workflow.Node, error) {
 		}
 		return parallelNode, nil
 
+	case "Try":
+		return &workflow.TryNode{
+			ID:   nodeID,
+			Body: []string{},
+		}, nil
+
+	case "Catch":
+		return &workflow.CatchNode{
+			ID:            nodeID,
+			ErrorVariable: selected.defaultConfig["errorVariable"].(string),
+		}, nil
+
+	case "Delay":
+		return &workflow.DelayNode{
+			ID:       nodeID,
+			Duration: selected.defaultConfig["duration"].(string),
+		}, nil
+
+	case "Approval":
+		return &workflow.ApprovalNode{
+			ID:      nodeID,
+			Message: selected.defaultConfig["message"].(string),
+		}, nil
+
 	case "End":
 		return &workflow.EndNode{
 			ID:          nodeID,
@@ -245,13 +337,7 @@ func (p *NodePalette) Render(screen interface{}, x, y, width, height int) error
 		return nil
 	}
 
-	// Import goterm types
-	type Screen interface {
-		SetCell(cellX, cellY int, cell interface{})
-		Size() (int, int)
-	}
-
-	scr, ok := screen.(Screen)
+	scr, ok := screen.(cellScreen)
 	if !ok {
 		return fmt.Errorf("invalid screen type")
 	}
@@ -263,10 +349,11 @@ func (p *NodePalette) Render(screen interface{}, x, y, width, height int) error
 	}
 
 	// Colors using goterm
-	fgColor := goterm.ColorRGB(255, 255, 255)      // White text
-	bgColor := goterm.ColorRGB(30, 30, 30)         // Dark background
-	selectedBgColor := goterm.ColorRGB(58, 58, 58) // Gray background for selected
-	borderFg := goterm.ColorRGB(136, 136, 136)     // Gray border
+	theme := CurrentTheme()
+	fgColor := theme.PanelText
+	bgColor := theme.PanelBg
+	selectedBgColor := theme.PanelSelectedBg
+	borderFg := theme.PanelBorder
 
 	// Draw border
 	// Top border
@@ -13,9 +13,9 @@ func TestNewNodePalette(t *testing.T) {
 		t.Fatal("NewNodePalette() returned nil")
 	}
 
-	// Should have 6 node types (MCP Tool, Transform, Condition, Loop, Parallel, End)
-	if len(palette.nodeTypes) != 6 {
-		t.Errorf("expected 6 node types, got %d", len(palette.nodeTypes))
+	// Should have 12 node types (MCP Tool, Transform, Condition, Switch, Loop, Parallel, Try, Catch, Delay, Approval, Batch Tool, End)
+	if len(palette.nodeTypes) != 12 {
+		t.Errorf("expected 12 node types, got %d", len(palette.nodeTypes))
 	}
 
 	// Should start with index 0
@@ -84,7 +84,7 @@ func TestNodePaletteFilter(t *testing.T) {
 		{
 			name:          "empty filter shows all",
 			filterText:    "",
-			expectedCount: 6,
+			expectedCount: 12,
 			expectedFirst: "MCP Tool",
 		},
 		{
@@ -117,6 +117,18 @@ func TestNodePaletteFilter(t *testing.T) {
 			expectedCount: 1,
 			expectedFirst: "Parallel",
 		},
+		{
+			name:          "filter 'catch' matches Catch",
+			filterText:    "catch",
+			expectedCount: 1,
+			expectedFirst: "Catch",
+		},
+		{
+			name:          "filter 'approv' matches Approval",
+			filterText:    "approv",
+			expectedCount: 1,
+			expectedFirst: "Approval",
+		},
 		{
 			name:          "filter 'end' matches End",
 			filterText:    "end",
@@ -124,9 +136,9 @@ func TestNodePaletteFilter(t *testing.T) {
 			expectedFirst: "End",
 		},
 		{
-			name:          "filter 'tool' matches MCP Tool",
+			name:          "filter 'tool' matches MCP Tool and Batch Tool",
 			filterText:    "tool",
-			expectedCount: 1,
+			expectedCount: 2,
 			expectedFirst: "MCP Tool",
 		},
 		{
@@ -191,7 +203,7 @@ func TestNodePaletteNavigation(t *testing.T) {
 	}
 
 	// Test wrap-around at end
-	palette.selectedIndex = 5 // Last item
+	palette.selectedIndex = 11 // Last item
 	palette.Next()
 	if palette.selectedIndex != 0 {
 		t.Errorf("Next() should wrap to 0 at end, got %d", palette.selectedIndex)
@@ -207,7 +219,7 @@ func TestNodePaletteNavigation(t *testing.T) {
 	// Test wrap-around at start
 	palette.selectedIndex = 0
 	palette.Previous()
-	if palette.selectedIndex != 5 {
+	if palette.selectedIndex != 11 {
 		t.Errorf("Previous() should wrap to last item at start, got %d", palette.selectedIndex)
 	}
 }
@@ -335,6 +347,20 @@ func TestNodePaletteCreateNode(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:         "create Switch node",
+			selectType:   "switch",
+			expectedType: "switch",
+			validate: func(t *testing.T, node workflow.Node) {
+				switchNode, ok := node.(*workflow.SwitchNode)
+				if !ok {
+					t.Fatal("expected SwitchNode")
+				}
+				if len(switchNode.Cases) != 0 {
+					t.Errorf("expected no cases, got %d", len(switchNode.Cases))
+				}
+			},
+		},
 		{
 			name:         "create Loop node",
 			selectType:   "loop",
@@ -372,6 +398,76 @@ func TestNodePaletteCreateNode(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:         "create Try node",
+			selectType:   "try",
+			expectedType: "try",
+			validate: func(t *testing.T, node workflow.Node) {
+				tryNode, ok := node.(*workflow.TryNode)
+				if !ok {
+					t.Fatal("expected TryNode")
+				}
+				if tryNode.Body == nil {
+					t.Error("Body should not be nil")
+				}
+			},
+		},
+		{
+			name:         "create Catch node",
+			selectType:   "catch",
+			expectedType: "catch",
+			validate: func(t *testing.T, node workflow.Node) {
+				catchNode, ok := node.(*workflow.CatchNode)
+				if !ok {
+					t.Fatal("expected CatchNode")
+				}
+				if catchNode.ErrorVariable != "error" {
+					t.Errorf("expected ErrorVariable 'error', got %q", catchNode.ErrorVariable)
+				}
+			},
+		},
+		{
+			name:         "create Delay node",
+			selectType:   "delay",
+			expectedType: "delay",
+			validate: func(t *testing.T, node workflow.Node) {
+				delayNode, ok := node.(*workflow.DelayNode)
+				if !ok {
+					t.Fatal("expected DelayNode")
+				}
+				if delayNode.Duration != "1m" {
+					t.Errorf("expected Duration '1m', got %q", delayNode.Duration)
+				}
+			},
+		},
+		{
+			name:         "create Approval node",
+			selectType:   "approval",
+			expectedType: "approval",
+			validate: func(t *testing.T, node workflow.Node) {
+				approvalNode, ok := node.(*workflow.ApprovalNode)
+				if !ok {
+					t.Fatal("expected ApprovalNode")
+				}
+				if approvalNode.Message == "" {
+					t.Error("Message should have a default")
+				}
+			},
+		},
+		{
+			name:         "create Batch Tool node",
+			selectType:   "batch",
+			expectedType: "batch_tool",
+			validate: func(t *testing.T, node workflow.Node) {
+				batchNode, ok := node.(*workflow.BatchToolNode)
+				if !ok {
+					t.Fatal("expected BatchToolNode")
+				}
+				if batchNode.Collection == "" || batchNode.ItemVariable == "" {
+					t.Error("Collection and ItemVariable should have defaults")
+				}
+			},
+		},
 		{
 			name:         "create End node",
 			selectType:   "end",
@@ -439,12 +535,18 @@ func TestNodePaletteNodeTypeDefinitions(t *testing.T) {
 		icon        string
 		description string
 	}{
-		"MCP Tool":  {icon: "🔧", description: "Execute MCP server tool"},
-		"Transform": {icon: "🔄", description: "Transform data using JSONPath, template, or jq"},
-		"Condition": {icon: "❓", description: "Conditional branching"},
-		"Loop":      {icon: "🔁", description: "Iterate over collections"},
-		"Parallel":  {icon: "⚡", description: "Concurrent execution"},
-		"End":       {icon: "🏁", description: "Exit point with output"},
+		"MCP Tool":   {icon: "🔧", description: "Execute MCP server tool"},
+		"Transform":  {icon: "🔄", description: "Transform data using JSONPath, template, or jq"},
+		"Condition":  {icon: "❓", description: "Conditional branching"},
+		"Switch":     {icon: "🔀", description: "Multi-way branching on labeled cases"},
+		"Loop":       {icon: "🔁", description: "Iterate over collections"},
+		"Parallel":   {icon: "⚡", description: "Concurrent execution"},
+		"Try":        {icon: "🛡", description: "Error scope around body nodes"},
+		"Catch":      {icon: "🩹", description: "Capture a failure into a variable"},
+		"Delay":      {icon: "⏳", description: "Wait for a duration or until a time"},
+		"Approval":   {icon: "✋", description: "Wait for a human to approve"},
+		"Batch Tool": {icon: "🧰", description: "Execute an MCP tool for each item of a collection"},
+		"End":        {icon: "🏁", description: "Exit point with output"},
 	}
 
 	if len(palette.nodeTypes) != len(expectedTypes) {
@@ -502,6 +604,13 @@ func TestNodePaletteDefaultConfigs(t *testing.T) {
 				"name": "condition",
 			},
 		},
+		{
+			typeName:     "Switch",
+			expectedKeys: []string{"name"},
+			expectedValue: map[string]interface{}{
+				"name": "switch",
+			},
+		},
 		{
 			typeName:     "Loop",
 			expectedKeys: []string{"name", "collection", "variable"},
@@ -517,6 +626,43 @@ func TestNodePaletteDefaultConfigs(t *testing.T) {
 				"branches": 2,
 			},
 		},
+		{
+			typeName:     "Try",
+			expectedKeys: []string{"name"},
+			expectedValue: map[string]interface{}{
+				"name": "try",
+			},
+		},
+		{
+			typeName:     "Catch",
+			expectedKeys: []string{"name", "errorVariable"},
+			expectedValue: map[string]interface{}{
+				"name":          "catch",
+				"errorVariable": "error",
+			},
+		},
+		{
+			typeName:     "Delay",
+			expectedKeys: []string{"name", "duration"},
+			expectedValue: map[string]interface{}{
+				"name":     "delay",
+				"duration": "1m",
+			},
+		},
+		{
+			typeName:     "Approval",
+			expectedKeys: []string{"name", "message"},
+			expectedValue: map[string]interface{}{
+				"name": "approval",
+			},
+		},
+		{
+			typeName:     "Batch Tool",
+			expectedKeys: []string{"name", "collection", "item"},
+			expectedValue: map[string]interface{}{
+				"name": "batch",
+			},
+		},
 		{
 			typeName:     "End",
 			expectedKeys: []string{"name", "output"},
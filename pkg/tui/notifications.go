@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Notifications tell the user about things that happen away from the key
+// they pressed: background health checks and tool discovery, autosave and
+// failed commands. They are drawn on the right of every view's status bar
+// and kept in a history shown with Ctrl-g or :messages.
+
+// NotificationLevel is how important a notification is
+type NotificationLevel int
+
+const (
+	NotifyInfo NotificationLevel = iota
+	NotifySuccess
+	NotifyWarning
+	NotifyError
+)
+
+// String returns the level's name, as shown in the message history
+func (l NotificationLevel) String() string {
+	switch l {
+	case NotifySuccess:
+		return "ok"
+	case NotifyWarning:
+		return "warning"
+	case NotifyError:
+		return "error"
+	}
+	return "info"
+}
+
+const (
+	// toastDuration is how long a toast stays on the status bar
+	toastDuration = 4 * time.Second
+
+	// maxNotificationHistory caps the messages kept for the history
+	maxNotificationHistory = 200
+)
+
+// spinnerFrames animate the status bar while background tasks run
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// Notification is a message in the history
+type Notification struct {
+	Time  time.Time
+	Level NotificationLevel
+	Text  string
+}
+
+// Notifier holds the toast, persistent warnings and running background
+// tasks shown on the status bar, and the history of every message. It is
+// safe for use from background goroutines.
+type Notifier struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	toast    Notification
+	expires  time.Time
+	warnings map[string]Notification // By key, until cleared
+	tasks    map[int]string          // Labels of running tasks, by ID
+	nextTask int
+	history  []Notification
+}
+
+// NewNotifier creates a notifier with no messages
+func NewNotifier() *Notifier {
+	return &Notifier{
+		now:      time.Now,
+		warnings: make(map[string]Notification),
+		tasks:    make(map[int]string),
+	}
+}
+
+// defaultNotifier is shared by the views and their background work
+var defaultNotifier = NewNotifier()
+
+// Notifications returns the notifier shared by all views
+func Notifications() *Notifier {
+	return defaultNotifier
+}
+
+// Notify shows a toast on the status bar for a few seconds and adds it to
+// the history
+func (n *Notifier) Notify(level NotificationLevel, format string, args ...any) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.toast = n.record(level, fmt.Sprintf(format, args...))
+	n.expires = n.toast.Time.Add(toastDuration)
+}
+
+// Log adds a message to the history without showing it, for messages
+// already shown elsewhere
+func (n *Notifier) Log(level NotificationLevel, format string, args ...any) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.record(level, fmt.Sprintf(format, args...))
+}
+
+// Warn shows a warning on the status bar until ClearWarning is called with
+// the same key. Repeating a warning unchanged does not add to the history.
+func (n *Notifier) Warn(key, text string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if existing, ok := n.warnings[key]; ok && existing.Text == text {
+		return
+	}
+	n.warnings[key] = n.record(NotifyWarning, text)
+}
+
+// ClearWarning removes a persistent warning
+func (n *Notifier) ClearWarning(key string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.warnings, key)
+}
+
+// StartTask shows a spinner with label on the status bar until the
+// returned function is called
+func (n *Notifier) StartTask(label string) (done func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	id := n.nextTask
+	n.nextTask++
+	n.tasks[id] = label
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			delete(n.tasks, id)
+		})
+	}
+}
+
+// History returns the messages so far, oldest first
+func (n *Notifier) History() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notification(nil), n.history...)
+}
+
+// ClearHistory forgets the messages so far
+func (n *Notifier) ClearHistory() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.history = nil
+}
+
+// Status returns what the status bar shows: running tasks with a spinner,
+// then the toast or else the newest warning, and the level to color it
+// with. It returns "" when there is nothing to show.
+func (n *Notifier) Status() (string, NotificationLevel) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := n.now()
+
+	text, level := "", NotifyInfo
+	if len(n.tasks) > 0 {
+		ids := make([]int, 0, len(n.tasks))
+		for id := range n.tasks {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		frame := spinnerFrames[int(now.UnixMilli()/100)%len(spinnerFrames)]
+		text = string(frame) + " " + n.tasks[ids[0]]
+		if len(ids) > 1 {
+			text += fmt.Sprintf(" (+%d)", len(ids)-1)
+		}
+	}
+
+	message := Notification{}
+	switch {
+	case now.Before(n.expires):
+		message = n.toast
+	case len(n.warnings) > 0:
+		keys := make([]string, 0, len(n.warnings))
+		for key := range n.warnings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if w := n.warnings[key]; message.Text == "" || !w.Time.Before(message.Time) {
+				message = w
+			}
+		}
+		if len(n.warnings) > 1 {
+			message.Text += fmt.Sprintf(" (+%d warnings)", len(n.warnings)-1)
+		}
+	}
+	if message.Text != "" {
+		if text != "" {
+			text += "  "
+		}
+		text += message.Text
+		level = message.Level
+	}
+	return text, level
+}
+
+// record adds a message to the history, dropping the oldest past the cap.
+// The caller holds the lock.
+func (n *Notifier) record(level NotificationLevel, text string) Notification {
+	msg := Notification{Time: n.now(), Level: level, Text: text}
+	n.history = append(n.history, msg)
+	if len(n.history) > maxNotificationHistory {
+		n.history = n.history[len(n.history)-maxNotificationHistory:]
+	}
+	return msg
+}
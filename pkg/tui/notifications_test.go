@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goterm"
+)
+
+// useTestNotifier replaces the shared notifier with one on a fake clock
+// for the rest of the test
+func useTestNotifier(t *testing.T) (*Notifier, *time.Time) {
+	t.Helper()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	n := NewNotifier()
+	n.now = func() time.Time { return now }
+
+	previous := defaultNotifier
+	defaultNotifier = n
+	t.Cleanup(func() { defaultNotifier = previous })
+	return n, &now
+}
+
+// screenRow returns the characters of one row of a screen
+func screenRow(screen *goterm.Screen, y int) string {
+	width, _ := screen.Size()
+	var row strings.Builder
+	for x := 0; x < width; x++ {
+		row.WriteRune(screen.GetCell(x, y).Ch)
+	}
+	return row.String()
+}
+
+func TestNotifier_ToastExpires(t *testing.T) {
+	n, now := useTestNotifier(t)
+
+	n.Notify(NotifySuccess, "Saved %s", "flow.yaml")
+	if text, level := n.Status(); text != "Saved flow.yaml" || level != NotifySuccess {
+		t.Errorf("Status() = %q, %v", text, level)
+	}
+
+	*now = now.Add(toastDuration)
+	if text, _ := n.Status(); text != "" {
+		t.Errorf("Status() after the toast expired = %q", text)
+	}
+	if history := n.History(); len(history) != 1 || history[0].Text != "Saved flow.yaml" {
+		t.Errorf("History() = %+v", history)
+	}
+}
+
+func TestNotifier_Warnings(t *testing.T) {
+	n, now := useTestNotifier(t)
+
+	n.Warn("server:a", "Server a unhealthy")
+	n.Warn("server:a", "Server a unhealthy")
+	*now = now.Add(time.Second)
+	n.Warn("autosave", "autosave failed")
+	if text, level := n.Status(); text != "autosave failed (+1 warnings)" || level != NotifyWarning {
+		t.Errorf("Status() = %q, %v", text, level)
+	}
+	if got := len(n.History()); got != 2 {
+		t.Errorf("History() has %d messages, want repeated warnings once", got)
+	}
+
+	// A toast shows over the warnings until it expires
+	n.Notify(NotifyError, "discovery failed")
+	if text, _ := n.Status(); text != "discovery failed" {
+		t.Errorf("Status() = %q, want the toast", text)
+	}
+	*now = now.Add(toastDuration)
+	n.ClearWarning("autosave")
+	if text, _ := n.Status(); text != "Server a unhealthy" {
+		t.Errorf("Status() = %q, want the remaining warning", text)
+	}
+}
+
+func TestNotifier_Tasks(t *testing.T) {
+	n, _ := useTestNotifier(t)
+
+	doneA := n.StartTask("Checking a")
+	doneB := n.StartTask("Checking b")
+	n.Notify(NotifyInfo, "hello")
+	text, _ := n.Status()
+	if !strings.HasSuffix(text, " Checking a (+1)  hello") {
+		t.Errorf("Status() = %q", text)
+	}
+
+	doneA()
+	doneA() // Calling done again has no effect
+	if text, _ := n.Status(); !strings.Contains(text, "Checking b") || strings.Contains(text, "(+") {
+		t.Errorf("Status() = %q", text)
+	}
+	doneB()
+	if text, _ := n.Status(); text != "hello" {
+		t.Errorf("Status() = %q", text)
+	}
+}
+
+func TestNotifier_HistoryCap(t *testing.T) {
+	n, _ := useTestNotifier(t)
+	for i := 0; i < maxNotificationHistory+5; i++ {
+		n.Log(NotifyInfo, "message %d", i)
+	}
+	history := n.History()
+	if len(history) != maxNotificationHistory {
+		t.Fatalf("History() has %d messages", len(history))
+	}
+	if history[0].Text != "message 5" {
+		t.Errorf("oldest message = %q, want the oldest ones dropped", history[0].Text)
+	}
+}
+
+func TestDrawStatusBar(t *testing.T) {
+	n, _ := useTestNotifier(t)
+	screen := goterm.NewScreen(40, 2)
+
+	drawStatusBar(screen, 1, 40, "Status: Ready", CurrentTheme().Foreground, goterm.StyleNone)
+	if row := screenRow(screen, 1); strings.TrimSpace(row) != "Status: Ready" {
+		t.Errorf("row = %q", row)
+	}
+
+	// Notifications go on the right, cutting the view's text short
+	n.Warn("server:a", "Server a unhealthy")
+	drawStatusBar(screen, 1, 40, "Status: "+strings.Repeat("x", 40), CurrentTheme().Foreground, goterm.StyleNone)
+	row := screenRow(screen, 1)
+	if !strings.HasSuffix(row, " Server a unhealthy") || !strings.HasPrefix(row, "Status: xxx") {
+		t.Errorf("row = %q", row)
+	}
+}
+
+func TestMessageLog(t *testing.T) {
+	n, _ := useTestNotifier(t)
+	for i := 1; i <= 30; i++ {
+		n.Log(NotifyError, "failure %d", i)
+	}
+
+	log := NewMessageLog()
+	log.Open()
+	screen := goterm.NewScreen(60, 20)
+	log.Render(screen)
+	var text strings.Builder
+	for y := 0; y < 20; y++ {
+		text.WriteString(screenRow(screen, y) + "\n")
+	}
+	if !strings.Contains(text.String(), "Messages (30)") || !strings.Contains(text.String(), "error   failure 30") {
+		t.Errorf("newest messages not shown:\n%s", text.String())
+	}
+
+	for _, key := range "kk" {
+		log.HandleKey(KeyEvent{Key: key})
+	}
+	screen = goterm.NewScreen(60, 20)
+	log.Render(screen)
+	text.Reset()
+	for y := 0; y < 20; y++ {
+		text.WriteString(screenRow(screen, y) + "\n")
+	}
+	if strings.Contains(text.String(), "failure 30") || !strings.Contains(text.String(), "failure 28") {
+		t.Errorf("expected k to scroll back:\n%s", text.String())
+	}
+
+	log.HandleKey(KeyEvent{Key: 'c'})
+	if len(n.History()) != 0 {
+		t.Error("expected c to clear the history")
+	}
+	log.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"})
+	if log.IsVisible() {
+		t.Error("expected Escape to close the log")
+	}
+}
+
+func TestCommandLine_ErrorsKeptInHistory(t *testing.T) {
+	n, _ := useTestNotifier(t)
+	line := NewCommandLine(NewCommandRegistry())
+	line.ShowError(fmt.Errorf("unknown command: frob"))
+	if history := n.History(); len(history) != 1 || history[0].Level != NotifyError {
+		t.Errorf("History() = %+v", history)
+	}
+}
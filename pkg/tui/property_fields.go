@@ -3,8 +3,12 @@ package tui
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dshills/goflow/pkg/transform"
+	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/expr-lang/expr"
 )
 
@@ -26,12 +30,25 @@ func newPropertyField(label, value, fieldType string, required bool) propertyFie
 		field.validationFn = validateTextField
 	case "expression":
 		field.validationFn = validateExpressionField
+	case "jq":
+		field.validationFn = validateJQField
+	case "language":
+		field.validationFn = validateLanguageField
+		field.enum = transform.TransformTypeNames()
 	case "condition":
 		field.validationFn = validateConditionField
+	case "cases":
+		field.validationFn = validateCasesField
 	case "jsonpath":
 		field.validationFn = validateJSONPathField
 	case "template":
 		field.validationFn = validateTemplateField
+	case "duration":
+		field.validationFn = validateDurationField
+	case "timestamp":
+		field.validationFn = validateTimestampField
+	case "concurrency":
+		field.validationFn = validateConcurrencyField
 	default:
 		field.validationFn = validateTextField // fallback
 	}
@@ -57,12 +74,24 @@ func getFieldHelpText(fieldType string) string {
 		return "Enter text value"
 	case "expression":
 		return "Expression: e.g., total + 1, user.age * 2"
+	case "jq":
+		return "jq: e.g., .items | map(select(.qty >= $min)) | length"
+	case "language":
+		return "Language: auto, jsonpath, expr, template or jq"
 	case "condition":
 		return "Boolean: e.g., total > 10 && status == \"active\""
+	case "cases":
+		return "label: condition; ... e.g., high: total > 100; low: total <= 10"
 	case "jsonpath":
 		return "JSONPath: e.g., $.users[?(@.age > 18)].email"
 	case "template":
 		return "Template: e.g., \"Hello ${user.name}\""
+	case "duration":
+		return "Duration: e.g., 30s, 5m, 1h30m or ${delay}"
+	case "timestamp":
+		return "RFC 3339 time: e.g., 2030-01-01T09:00:00Z or ${release_at}"
+	case "concurrency":
+		return "Calls at once: e.g., 10 (empty for the default)"
 	default:
 		return "Enter text value" // Default help text
 	}
@@ -90,14 +119,13 @@ func validateExpressionField(value string) error {
 		return nil // Empty is valid (required check done separately)
 	}
 
-	// Check for unsafe operations
-	unsafePatterns := []string{
-		"os.", "exec.", "http.", "net.", "syscall.", "unsafe.",
+	if transform.IsJQExpression(value) {
+		return transform.CheckJQ(value)
 	}
-	for _, pattern := range unsafePatterns {
-		if strings.Contains(value, pattern) {
-			return fmt.Errorf("unsafe operation not allowed: %s", pattern)
-		}
+
+	// Check for unsafe operations
+	if err := transform.CheckExpressionSafety(value); err != nil {
+		return err
 	}
 
 	// Parse expression to validate syntax
@@ -111,6 +139,29 @@ func validateExpressionField(value string) error {
 	return nil
 }
 
+// validateJQField validates jq programs, bare or written as jq(program)
+func validateJQField(value string) error {
+	if value == "" {
+		return nil // Empty is valid (required check done separately)
+	}
+	return transform.CheckJQ(value)
+}
+
+// validateLanguageField validates the language of a Transform expression
+func validateLanguageField(value string) error {
+	_, err := transform.ParseTransformType(value)
+	return err
+}
+
+// expressionFieldType is the type of a Transform's Expression field for
+// the language chosen for it
+func expressionFieldType(language string) string {
+	if language == transform.TransformTypeJQ.String() {
+		return "jq"
+	}
+	return "expression"
+}
+
 // validateConditionField validates condition fields
 // Must be a boolean expression
 func validateConditionField(value string) error {
@@ -134,6 +185,118 @@ func validateConditionField(value string) error {
 	return nil
 }
 
+// validateCasesField validates switch cases written as
+// "label: condition; label: condition"
+func validateCasesField(value string) error {
+	if value == "" {
+		return nil // Empty is valid (required check done separately)
+	}
+
+	cases, err := parseSwitchCases(value)
+	if err != nil {
+		return err
+	}
+	for _, c := range cases {
+		if err := validateConditionField(c.Condition); err != nil {
+			return fmt.Errorf("case %q: %w", c.Label, err)
+		}
+	}
+	return (&workflow.SwitchNode{ID: "switch", Cases: cases}).Validate()
+}
+
+// formatSwitchCases formats switch cases for editing as
+// "label: condition; label: condition"
+func formatSwitchCases(cases []workflow.SwitchCase) string {
+	parts := make([]string, 0, len(cases))
+	for _, c := range cases {
+		parts = append(parts, c.Label+": "+c.Condition)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// parseSwitchCases parses switch cases written by formatSwitchCases. Each
+// label ends at the first colon, so conditions may contain colons.
+func parseSwitchCases(value string) ([]workflow.SwitchCase, error) {
+	var cases []workflow.SwitchCase
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		label, condition, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("case %q must be written as label: condition", part)
+		}
+		cases = append(cases, workflow.SwitchCase{
+			Label:     strings.TrimSpace(label),
+			Condition: strings.TrimSpace(condition),
+		})
+	}
+	return cases, nil
+}
+
+// parseNodeList parses a comma-separated list of node IDs, such as a loop or
+// try body
+func parseNodeList(value string) []string {
+	nodeIDs := []string{}
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			nodeIDs = append(nodeIDs, id)
+		}
+	}
+	return nodeIDs
+}
+
+// validateDurationField validates delay and timeout fields: a positive
+// duration such as 30s, or a template resolved when the node runs
+func validateDurationField(value string) error {
+	if value == "" || strings.Contains(value, "${") {
+		return nil
+	}
+	_, err := workflow.ParseDelayDuration(value)
+	return err
+}
+
+// parseMaxConcurrency parses a batch node's concurrency limit; empty means
+// the default
+func parseMaxConcurrency(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid max concurrency %q (use a positive number)", value)
+	}
+	return limit, nil
+}
+
+// validateConcurrencyField validates a batch node's concurrency limit
+func validateConcurrencyField(value string) error {
+	_, err := parseMaxConcurrency(value)
+	return err
+}
+
+// formatMaxConcurrency formats a batch node's concurrency limit, empty for
+// the default
+func formatMaxConcurrency(limit int) string {
+	if limit == 0 {
+		return ""
+	}
+	return strconv.Itoa(limit)
+}
+
+// validateTimestampField validates RFC 3339 timestamps, or a template
+// resolved when the node runs
+func validateTimestampField(value string) error {
+	if value == "" || strings.Contains(value, "${") {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return fmt.Errorf("invalid timestamp %q (use RFC 3339, e.g. 2030-01-01T09:00:00Z)", value)
+	}
+	return nil
+}
+
 // validateJSONPathField validates JSONPath fields
 // Uses gjson library to check syntax
 func validateJSONPathField(value string) error {
@@ -199,6 +362,17 @@ func validateTemplateField(value string) error {
 			return fmt.Errorf("placeholder cannot have leading or trailing whitespace: ${%s}", varName)
 		}
 
+		// Filters piped after the variable must be registered helpers
+		varName, filters, err := transform.ParseTemplatePipeline(varName)
+		if err != nil {
+			return err
+		}
+		for _, filter := range filters {
+			if _, ok := transform.LookupHelper(filter.Name); !ok {
+				return fmt.Errorf("unknown filter in template: %s", filter.Name)
+			}
+		}
+
 		// Variable names should match pattern: word characters, dots, brackets
 		validVarName := regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*|\[\d+\])*$`)
 		if !validVarName.MatchString(varName) {
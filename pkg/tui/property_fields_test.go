@@ -358,6 +358,16 @@ func TestValidateTemplateField(t *testing.T) {
 			value:     "${user_name}",
 			wantError: false,
 		},
+		{
+			name:      "filters",
+			value:     `Hello ${user.name | default:"guest" | upper}`,
+			wantError: false,
+		},
+		{
+			name:      "unknown filter",
+			value:     "Hello ${name | shout}",
+			wantError: true,
+		},
 		{
 			name:      "whitespace in placeholder",
 			value:     "${ user_name }",
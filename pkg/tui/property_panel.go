@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/dshills/goterm"
@@ -32,6 +34,7 @@ func (p *PropertyPanel) Show() {
 // Hide closes the panel
 func (p *PropertyPanel) Hide() {
 	p.visible = false
+	p.CancelEdit()
 }
 
 // IsVisible returns whether panel is open
@@ -104,15 +107,16 @@ func (p *PropertyPanel) SaveChanges() (*workflow.Node, error) {
 
 // CancelChanges discards all changes and rebuilds fields from node
 func (p *PropertyPanel) CancelChanges() {
-	p.fields = buildFieldsForNode(p.node)
+	p.fields = p.buildFields(p.node)
 	p.editIndex = 0
 	p.validationMessage = ""
+	p.CancelEdit()
 }
 
 // IsDirty returns true if unsaved changes exist
 func (p *PropertyPanel) IsDirty() bool {
 	// Compare current field values with node values
-	originalFields := buildFieldsForNode(p.node)
+	originalFields := p.buildFields(p.node)
 
 	if len(p.fields) != len(originalFields) {
 		return true
@@ -146,6 +150,251 @@ func (p *PropertyPanel) Validate() error {
 	return nil
 }
 
+// completionSource suggests values for the field with the given label,
+// given the values of all fields
+type completionSource func(label string, fields []propertyField) []string
+
+// SetCompletionSource sets where values typed into fields are completed from
+func (p *PropertyPanel) SetCompletionSource(source completionSource) {
+	p.candidates = source
+}
+
+// SetSchemaSource sets where tool input schemas come from, and rebuilds the
+// fields so a tool node's arguments follow its tool's schema
+func (p *PropertyPanel) SetSchemaSource(source schemaSource) {
+	p.schemas = source
+	p.fields = p.buildFields(p.node)
+}
+
+// buildFields creates the fields for a node. A tool node's arguments follow
+// its tool's schema, when known.
+func (p *PropertyPanel) buildFields(node workflow.Node) []propertyField {
+	fields := buildFieldsForNode(node)
+	if tool, ok := node.(*workflow.MCPToolNode); ok && p.schemas != nil {
+		fields = append(nodePropertyFields(fields), argumentFields(p.schemas(tool.ServerID, tool.ToolName), tool.Parameters)...)
+	}
+	return fields
+}
+
+// refreshArguments regenerates the argument fields for the tool now
+// chosen, keeping the values entered so far
+func (p *PropertyPanel) refreshArguments() {
+	if _, ok := p.node.(*workflow.MCPToolNode); !ok || p.schemas == nil {
+		return
+	}
+	schema := p.schemas(getFieldValue(p.fields, "Server ID"), getFieldValue(p.fields, "Tool Name"))
+	if schema == nil {
+		return // Unknown tool: keep the arguments as they are
+	}
+	p.fields = append(nodePropertyFields(p.fields), argumentFields(schema, argumentValues(p.fields))...)
+	for i := range p.fields {
+		if p.fields[i].value != "" {
+			_ = p.fields[i].validate() // Marks the field; errors show on save
+		}
+	}
+}
+
+// nodePropertyFields returns the fields that are not tool arguments
+func nodePropertyFields(fields []propertyField) []propertyField {
+	properties := make([]propertyField, 0, len(fields))
+	for _, field := range fields {
+		if field.param == "" {
+			properties = append(properties, field)
+		}
+	}
+	return properties
+}
+
+// IsEditing reports whether the focused field's value is being typed
+func (p *PropertyPanel) IsEditing() bool {
+	return p.editing
+}
+
+// BeginEdit starts typing into the focused field. The node ID cannot be
+// edited, since edges refer to it.
+func (p *PropertyPanel) BeginEdit() error {
+	if p.editIndex < 0 || p.editIndex >= len(p.fields) {
+		return fmt.Errorf("invalid field index: %d", p.editIndex)
+	}
+	if p.fields[p.editIndex].label == "Node ID" {
+		return fmt.Errorf("node ID cannot be edited")
+	}
+	p.editing = true
+	p.editBuffer = p.fields[p.editIndex].value
+	p.updateCompletions()
+	p.updatePreview()
+	return nil
+}
+
+// CommitEdit stores the typed value in the focused field and validates it.
+// The value is kept even if it is invalid, so it can be corrected.
+func (p *PropertyPanel) CommitEdit() error {
+	if !p.editing {
+		return nil
+	}
+	value := p.editBuffer
+	p.CancelEdit()
+	err := p.SetFieldValue(value)
+	switch p.fields[p.editIndex].label {
+	case "Server ID", "Tool Name":
+		p.refreshArguments()
+	case "Language":
+		p.retypeExpression()
+	}
+	return err
+}
+
+// retypeExpression validates a Transform's expression in the language now
+// chosen for it
+func (p *PropertyPanel) retypeExpression() {
+	for i := range p.fields {
+		if p.fields[i].label != "Expression" {
+			continue
+		}
+		retyped := newPropertyField("Expression", p.fields[i].value, expressionFieldType(getFieldValue(p.fields, "Language")), true)
+		p.fields[i].fieldType = retyped.fieldType
+		p.fields[i].helpText = retyped.helpText
+		p.fields[i].validationFn = retyped.validationFn
+		if p.fields[i].value != "" {
+			_ = p.fields[i].validate() // Marks the field; errors show on save
+		}
+	}
+}
+
+// CancelEdit discards the typed value
+func (p *PropertyPanel) CancelEdit() {
+	if p.editing {
+		p.validationMessage = ""
+	}
+	p.editing = false
+	p.editBuffer = ""
+	p.completer.Reset()
+	p.preview, p.previewFailed = "", false
+}
+
+// TypeRune appends a character to the value being typed
+func (p *PropertyPanel) TypeRune(r rune) {
+	if p.editing {
+		p.editBuffer += string(r)
+		p.updateCompletions()
+		p.validateBuffer()
+		p.updatePreview()
+	}
+}
+
+// TypeText appends pasted text to the value being typed. Values are a
+// single line, so line breaks become spaces.
+func (p *PropertyPanel) TypeText(text string) {
+	text = strings.TrimRight(text, "\r\n")
+	if !p.editing || text == "" {
+		return
+	}
+	p.editBuffer += strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+	p.updateCompletions()
+	p.validateBuffer()
+	p.updatePreview()
+}
+
+// Backspace deletes the last character of the value being typed
+func (p *PropertyPanel) Backspace() {
+	if !p.editing || p.editBuffer == "" {
+		return
+	}
+	runes := []rune(p.editBuffer)
+	p.editBuffer = string(runes[:len(runes)-1])
+	p.updateCompletions()
+	p.validateBuffer()
+	p.updatePreview()
+}
+
+// validateBuffer checks the value being typed, so mistakes show before
+// the value is stored
+func (p *PropertyPanel) validateBuffer() {
+	field := p.fields[p.editIndex]
+	p.validationMessage = ""
+	if field.validationFn != nil {
+		if err := field.validationFn(p.editBuffer); err != nil {
+			p.validationMessage = err.Error()
+		}
+	}
+}
+
+// Complete replaces the word being typed with the next (or previous)
+// completion
+func (p *PropertyPanel) Complete(backward bool) {
+	start := p.completionStart()
+	if !p.editing || start < 0 {
+		return
+	}
+	if match, ok := p.completer.Next(backward); ok {
+		p.editBuffer = p.editBuffer[:start] + match
+		p.validateBuffer()
+		p.updatePreview()
+	}
+}
+
+// Completions returns the completions for the word being typed and the
+// index of the one last inserted, or -1
+func (p *PropertyPanel) Completions() ([]string, int) {
+	return p.completer.Matches(), p.completer.Selected()
+}
+
+// updateCompletions finds the completions for the word being typed
+func (p *PropertyPanel) updateCompletions() {
+	start := p.completionStart()
+	if start < 0 {
+		p.completer.Reset()
+		return
+	}
+	field := p.fields[p.editIndex]
+	candidates := field.enum
+	if len(candidates) == 0 {
+		candidates = p.candidates(field.label, p.fields)
+		// A JSONPath is only completed with paths, an identifier with names
+		word := p.editBuffer[start:]
+		candidates = slices.DeleteFunc(slices.Clone(candidates), func(c string) bool {
+			return strings.HasPrefix(c, "$") != strings.HasPrefix(word, "$")
+		})
+	}
+	p.completer.Update(p.editBuffer[start:], candidates)
+}
+
+// completionStart returns where the word being completed starts in the
+// value being typed, or -1 if the field is not completed. Fields with a set
+// of allowed values and server, tool and input variable fields complete
+// their whole value; expressions and ${} placeholders complete the
+// variable name being typed.
+func (p *PropertyPanel) completionStart() int {
+	if !p.editing {
+		return -1
+	}
+	field := p.fields[p.editIndex]
+	if len(field.enum) > 0 {
+		return 0
+	}
+	if p.candidates == nil {
+		return -1
+	}
+	if field.param != "" {
+		if strings.LastIndex(p.editBuffer, "${") <= strings.LastIndex(p.editBuffer, "}") {
+			return -1
+		}
+		return identifierStart(p.editBuffer)
+	}
+	switch field.label {
+	case "Server ID", "Tool Name", "Input Variable":
+		return 0
+	}
+	switch field.fieldType {
+	case "expression", "jq", "condition", "template", "cases":
+		if start := jsonPathStart(p.editBuffer); start >= 0 {
+			return start
+		}
+		return identifierStart(p.editBuffer)
+	}
+	return -1
+}
+
 // GetFields returns the property fields (for testing)
 func (p *PropertyPanel) GetFields() []propertyField {
 	return p.fields
@@ -177,24 +426,19 @@ func (p *PropertyPanel) Render(screen interface{}, x, y, width, height int) erro
 		return nil
 	}
 
-	// Type assert to screen interface
-	type Screen interface {
-		SetCell(cellX, cellY int, cell interface{})
-		Size() (int, int)
-	}
-
-	scr, ok := screen.(Screen)
+	scr, ok := screen.(cellScreen)
 	if !ok {
 		return fmt.Errorf("invalid screen type")
 	}
 
 	// Colors
-	fgColor := goterm.ColorRGB(255, 255, 255)      // White text
-	bgColor := goterm.ColorRGB(30, 30, 30)         // Dark background
-	selectedBgColor := goterm.ColorRGB(58, 58, 58) // Gray background for selected
-	borderFg := goterm.ColorRGB(136, 136, 136)     // Gray border
-	errorFg := goterm.ColorRGB(255, 100, 100)      // Light red for errors
-	successFg := goterm.ColorRGB(100, 255, 100)    // Light green for valid fields
+	theme := CurrentTheme()
+	fgColor := theme.PanelText
+	bgColor := theme.PanelBg
+	selectedBgColor := theme.PanelSelectedBg
+	borderFg := theme.PanelBorder
+	errorFg := theme.Error
+	successFg := theme.Success // Valid fields
 
 	// Draw border
 	// Top border
@@ -253,7 +497,15 @@ func (p *PropertyPanel) Render(screen interface{}, x, y, width, height int) erro
 			requiredMark = "*"
 		}
 
-		content := fmt.Sprintf("%s %s%s: %s", validIndicator, field.label, requiredMark, field.value)
+		value := field.value
+		if i == p.editIndex && p.editing {
+			value = p.editBuffer + "_" // Cursor
+		}
+		label := field.label
+		if field.param != "" {
+			label = "  " + label // Arguments are indented below the tool
+		}
+		content := fmt.Sprintf("%s %s%s: %s", validIndicator, label, requiredMark, value)
 		if len(content) > width-4 {
 			content = content[:width-7] + "..."
 		}
@@ -276,19 +528,25 @@ func (p *PropertyPanel) Render(screen interface{}, x, y, width, height int) erro
 
 		currentY++
 
-		// Show help text for focused field
-		if i == p.editIndex && field.helpText != "" {
+		// Show completions, or else help text, for focused field
+		helpText := field.helpText
+		if i == p.editIndex {
+			if matches, selected := p.Completions(); len(matches) > 0 {
+				helpText = "Tab:" + formatCompletions(matches, selected)
+			}
+		}
+		if i == p.editIndex && helpText != "" {
 			// Help text line
 			if currentY < y+height-2 {
 				cell := goterm.NewCell('│', borderFg, bgColor, goterm.StyleNone)
 				scr.SetCell(x, currentY, cell)
 
-				helpContent := fmt.Sprintf("  ℹ %s", field.helpText)
+				helpContent := fmt.Sprintf("  ℹ %s", helpText)
 				if len(helpContent) > width-4 {
 					helpContent = helpContent[:width-7] + "..."
 				}
 
-				helpFg := goterm.ColorRGB(150, 150, 150) // Gray
+				helpFg := theme.Muted
 				for j := 0; j < width-2; j++ {
 					var ch rune
 					if j < len(helpContent) {
@@ -306,6 +564,37 @@ func (p *PropertyPanel) Render(screen interface{}, x, y, width, height int) erro
 				currentY++
 			}
 		}
+
+		// Show the result of the expression being typed
+		if preview, failed := p.Preview(); i == p.editIndex && preview != "" && currentY < y+height-2 {
+			cell := goterm.NewCell('│', borderFg, bgColor, goterm.StyleNone)
+			scr.SetCell(x, currentY, cell)
+
+			previewContent, previewFg := fmt.Sprintf("  = %s", preview), successFg
+			if failed {
+				previewContent, previewFg = fmt.Sprintf("  ✗ %s", preview), errorFg
+			}
+			runes := []rune(previewContent)
+			if len(runes) > width-4 {
+				runes = append(runes[:width-7], []rune("...")...)
+			}
+
+			for j := 0; j < width-2; j++ {
+				var ch rune
+				if j < len(runes) {
+					ch = runes[j]
+				} else {
+					ch = ' '
+				}
+				cell := goterm.NewCell(ch, previewFg, bgColor, goterm.StyleNone)
+				scr.SetCell(x+1+j, currentY, cell)
+			}
+
+			cell = goterm.NewCell('│', borderFg, bgColor, goterm.StyleNone)
+			scr.SetCell(x+width-1, currentY, cell)
+
+			currentY++
+		}
 	}
 
 	// Fill remaining space before validation message
@@ -369,6 +658,19 @@ func (p *PropertyPanel) Render(screen interface{}, x, y, width, height int) erro
 	return nil
 }
 
+// formatCompletions lists completions, bracketing the one last inserted
+func formatCompletions(matches []string, selected int) string {
+	var sb strings.Builder
+	for i, match := range matches {
+		if i == selected {
+			sb.WriteString(" [" + match + "]")
+		} else {
+			sb.WriteString(" " + match)
+		}
+	}
+	return sb.String()
+}
+
 // buildFieldsForNode creates property fields based on node type
 func buildFieldsForNode(node workflow.Node) []propertyField {
 	fields := make([]propertyField, 0)
@@ -388,13 +690,30 @@ func buildFieldsForNode(node workflow.Node) []propertyField {
 			newPropertyField("Server ID", n.ServerID, "text", true),
 			newPropertyField("Tool Name", n.ToolName, "text", true),
 			newPropertyField("Output Variable", n.OutputVariable, "text", true),
+			newPropertyField("Cache TTL", n.CacheTTL, "duration", false),
+			newPropertyField("Stream Output", n.StreamOutput, "text", false),
 		)
+		fields = append(fields, argumentFields(nil, n.Parameters)...)
+
+	case *workflow.BatchToolNode:
+		fields = append(fields,
+			newPropertyField("Server ID", n.ServerID, "text", true),
+			newPropertyField("Tool Name", n.ToolName, "text", true),
+			newPropertyField("Collection", n.Collection, "text", true),
+			newPropertyField("Item Variable", n.ItemVariable, "text", true),
+			newPropertyField("Max Concurrency", formatMaxConcurrency(n.MaxConcurrency), "concurrency", false),
+			newPropertyField("Output Variable", n.OutputVariable, "text", true),
+			newPropertyField("Error Variable", n.ErrorVariable, "text", false),
+		)
+		fields = append(fields, argumentFields(nil, n.Parameters)...)
 
 	case *workflow.TransformNode:
 		fields = append(fields,
 			newPropertyField("Input Variable", n.InputVariable, "text", true),
-			newPropertyField("Expression", n.Expression, "expression", true),
+			newPropertyField("Expression", n.Expression, expressionFieldType(n.Language), true),
+			newPropertyField("Language", n.Language, "language", false),
 			newPropertyField("Output Variable", n.OutputVariable, "text", true),
+			newPropertyField("Item Errors Output", n.ItemErrorsOutput, "text", false),
 		)
 
 	case *workflow.ConditionNode:
@@ -402,6 +721,11 @@ func buildFieldsForNode(node workflow.Node) []propertyField {
 			newPropertyField("Condition", n.Condition, "condition", true),
 		)
 
+	case *workflow.SwitchNode:
+		fields = append(fields,
+			newPropertyField("Cases", formatSwitchCases(n.Cases), "cases", true),
+		)
+
 	case *workflow.EndNode:
 		fields = append(fields,
 			newPropertyField("Return Value", n.ReturnValue, "template", false),
@@ -414,6 +738,30 @@ func buildFieldsForNode(node workflow.Node) []propertyField {
 			newPropertyField("Break Condition", n.BreakCondition, "condition", false),
 		)
 
+	case *workflow.TryNode:
+		fields = append(fields,
+			newPropertyField("Body Nodes", strings.Join(n.Body, ", "), "text", true),
+		)
+
+	case *workflow.CatchNode:
+		fields = append(fields,
+			newPropertyField("Error Variable", n.ErrorVariable, "text", true),
+		)
+
+	case *workflow.DelayNode:
+		fields = append(fields,
+			newPropertyField("Duration", n.Duration, "duration", false),
+			newPropertyField("Until", n.Until, "timestamp", false),
+		)
+
+	case *workflow.ApprovalNode:
+		fields = append(fields,
+			newPropertyField("Message", n.Message, "template", false),
+			newPropertyField("Timeout", n.Timeout, "duration", false),
+			newPropertyField("Default Action", n.DefaultAction, "text", false),
+			newPropertyField("Output Variable", n.OutputVariable, "text", false),
+		)
+
 		// StartNode and PassthroughNode have no editable fields beyond ID
 	}
 
@@ -430,18 +778,41 @@ func applyFieldsToNode(node workflow.Node, fields []propertyField) (workflow.Nod
 			ServerID:       getFieldValue(fields, "Server ID"),
 			ToolName:       getFieldValue(fields, "Tool Name"),
 			OutputVariable: getFieldValue(fields, "Output Variable"),
-			Parameters:     n.Parameters, // Keep existing parameters
-			Retry:          n.Retry,      // Keep existing retry policy
+			Parameters:     argumentValues(fields),
+			ContentOutputs: n.ContentOutputs, // Keep existing content routing
+			Retry:          n.Retry,          // Keep existing retry policy
+			CacheTTL:       getFieldValue(fields, "Cache TTL"),
+			StreamOutput:   getFieldValue(fields, "Stream Output"),
 		}
 		return updated, nil
 
-	case *workflow.TransformNode:
-		updated := &workflow.TransformNode{
+	case *workflow.BatchToolNode:
+		limit, err := parseMaxConcurrency(getFieldValue(fields, "Max Concurrency"))
+		if err != nil {
+			return nil, err
+		}
+		updated := &workflow.BatchToolNode{
 			ID:             n.ID,
-			InputVariable:  getFieldValue(fields, "Input Variable"),
-			Expression:     getFieldValue(fields, "Expression"),
+			ServerID:       getFieldValue(fields, "Server ID"),
+			ToolName:       getFieldValue(fields, "Tool Name"),
+			Collection:     getFieldValue(fields, "Collection"),
+			ItemVariable:   getFieldValue(fields, "Item Variable"),
+			Parameters:     argumentValues(fields),
+			MaxConcurrency: limit,
 			OutputVariable: getFieldValue(fields, "Output Variable"),
-			Retry:          n.Retry,
+			ErrorVariable:  getFieldValue(fields, "Error Variable"),
+		}
+		return updated, nil
+
+	case *workflow.TransformNode:
+		updated := &workflow.TransformNode{
+			ID:               n.ID,
+			InputVariable:    getFieldValue(fields, "Input Variable"),
+			Expression:       getFieldValue(fields, "Expression"),
+			Language:         getFieldValue(fields, "Language"),
+			OutputVariable:   getFieldValue(fields, "Output Variable"),
+			Retry:            n.Retry,
+			ItemErrorsOutput: getFieldValue(fields, "Item Errors Output"),
 		}
 		return updated, nil
 
@@ -452,6 +823,17 @@ func applyFieldsToNode(node workflow.Node, fields []propertyField) (workflow.Nod
 		}
 		return updated, nil
 
+	case *workflow.SwitchNode:
+		cases, err := parseSwitchCases(getFieldValue(fields, "Cases"))
+		if err != nil {
+			return nil, err
+		}
+		updated := &workflow.SwitchNode{
+			ID:    n.ID,
+			Cases: cases,
+		}
+		return updated, nil
+
 	case *workflow.EndNode:
 		updated := &workflow.EndNode{
 			ID:          n.ID,
@@ -469,6 +851,38 @@ func applyFieldsToNode(node workflow.Node, fields []propertyField) (workflow.Nod
 		}
 		return updated, nil
 
+	case *workflow.TryNode:
+		updated := &workflow.TryNode{
+			ID:   n.ID,
+			Body: parseNodeList(getFieldValue(fields, "Body Nodes")),
+		}
+		return updated, nil
+
+	case *workflow.CatchNode:
+		updated := &workflow.CatchNode{
+			ID:            n.ID,
+			ErrorVariable: getFieldValue(fields, "Error Variable"),
+		}
+		return updated, nil
+
+	case *workflow.DelayNode:
+		updated := &workflow.DelayNode{
+			ID:       n.ID,
+			Duration: getFieldValue(fields, "Duration"),
+			Until:    getFieldValue(fields, "Until"),
+		}
+		return updated, nil
+
+	case *workflow.ApprovalNode:
+		updated := &workflow.ApprovalNode{
+			ID:             n.ID,
+			Message:        getFieldValue(fields, "Message"),
+			Timeout:        getFieldValue(fields, "Timeout"),
+			DefaultAction:  getFieldValue(fields, "Default Action"),
+			OutputVariable: getFieldValue(fields, "Output Variable"),
+		}
+		return updated, nil
+
 	case *workflow.StartNode:
 		// StartNode has no editable fields
 		return n, nil
@@ -244,6 +244,44 @@ func TestPropertyPanelSaveChanges(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "save TransformNode switched to jq",
+			node: &workflow.TransformNode{
+				ID:             "transform-1",
+				InputVariable:  "orders",
+				Expression:     "orders",
+				OutputVariable: "count",
+			},
+			modifications: func(p *PropertyPanel) {
+				p.editIndex = 3 // Language
+				_ = p.BeginEdit()
+				p.editBuffer = "jq"
+				_ = p.CommitEdit()
+				p.editIndex = 2 // Expression, now checked as jq
+				_ = p.SetFieldValue(".items | map(select(.qty >= $min)) | length")
+			},
+			wantError: false,
+			validate: func(t *testing.T, n workflow.Node) {
+				transform := n.(*workflow.TransformNode)
+				if transform.Language != "jq" || !transform.IsJQ() {
+					t.Errorf("Language = %q, want jq", transform.Language)
+				}
+			},
+		},
+		{
+			name: "save fails with unknown language",
+			node: &workflow.TransformNode{
+				ID:             "transform-1",
+				InputVariable:  "input",
+				Expression:     "valid",
+				OutputVariable: "output",
+			},
+			modifications: func(p *PropertyPanel) {
+				p.editIndex = 3 // Language
+				_ = p.SetFieldValue("xpath")
+			},
+			wantError: true,
+		},
 		{
 			name: "save fails with invalid expression",
 			node: &workflow.TransformNode{
@@ -493,8 +531,8 @@ func TestBuildFieldsForNode(t *testing.T) {
 				ToolName:       "tool",
 				OutputVariable: "result",
 			},
-			expectedFields: 4, // ID, ServerID, ToolName, OutputVariable
-			checkLabels:    []string{"Node ID", "Server ID", "Tool Name", "Output Variable"},
+			expectedFields: 6, // ID, ServerID, ToolName, OutputVariable, CacheTTL, StreamOutput
+			checkLabels:    []string{"Node ID", "Server ID", "Tool Name", "Output Variable", "Cache TTL", "Stream Output"},
 		},
 		{
 			name: "TransformNode",
@@ -504,8 +542,8 @@ func TestBuildFieldsForNode(t *testing.T) {
 				Expression:     "expr",
 				OutputVariable: "output",
 			},
-			expectedFields: 4, // ID, InputVariable, Expression, OutputVariable
-			checkLabels:    []string{"Node ID", "Input Variable", "Expression", "Output Variable"},
+			expectedFields: 6, // ID, InputVariable, Expression, Language, OutputVariable, ItemErrorsOutput
+			checkLabels:    []string{"Node ID", "Input Variable", "Expression", "Language", "Output Variable", "Item Errors Output"},
 		},
 		{
 			name: "ConditionNode",
@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/goflow/pkg/transform"
+	"github.com/dshills/goterm"
+)
+
+// scratchEvalTimeout bounds each scratchpad evaluation
+const scratchEvalTimeout = time.Second
+
+// scratchAssignPattern matches "name = expression" (but not "name == ...")
+var scratchAssignPattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=([^=].*)$`)
+
+// ScratchEntry is one evaluated scratchpad input and its outcome.
+type ScratchEntry struct {
+	Input  string      // Line as typed
+	Name   string      // Variable assigned, empty for plain expressions
+	Result interface{} // Evaluated value (nil on error)
+	Err    error       // Evaluation error, if any
+}
+
+// Scratchpad holds temporary variables and expression history for
+// experimenting against an execution's context. It lives only in memory for
+// the session: nothing is written to the workflow or the execution, and a
+// single Scratchpad can be shared across monitors to keep its variables.
+type Scratchpad struct {
+	vars    map[string]interface{}
+	history []ScratchEntry
+}
+
+// NewScratchpad creates an empty scratchpad
+func NewScratchpad() *Scratchpad {
+	return &Scratchpad{
+		vars: make(map[string]interface{}),
+	}
+}
+
+// Submit processes one line of input against base (typically the last
+// execution's variables). Supported forms:
+//
+//	expression        evaluate and show the result
+//	name = expression evaluate and store as a scratch variable
+//	:unset name       remove a scratch variable
+//	:clear            remove all scratch variables and history
+func (s *Scratchpad) Submit(line string, base map[string]interface{}) ScratchEntry {
+	input := strings.TrimSpace(line)
+	entry := ScratchEntry{Input: input}
+
+	switch {
+	case input == "":
+		entry.Err = fmt.Errorf("empty expression")
+	case input == ":clear":
+		s.vars = make(map[string]interface{})
+		s.history = nil
+		return entry
+	case strings.HasPrefix(input, ":unset "):
+		name := strings.TrimSpace(strings.TrimPrefix(input, ":unset "))
+		if _, exists := s.vars[name]; !exists {
+			entry.Err = fmt.Errorf("no scratch variable: %s", name)
+		} else {
+			delete(s.vars, name)
+		}
+	case strings.HasPrefix(input, ":"):
+		entry.Err = fmt.Errorf("unknown command: %s", input)
+	default:
+		expression := input
+		if match := scratchAssignPattern.FindStringSubmatch(input); match != nil {
+			entry.Name = match[1]
+			expression = strings.TrimSpace(match[2])
+		}
+		entry.Result, entry.Err = s.Evaluate(expression, base)
+		if entry.Err == nil && entry.Name != "" {
+			s.vars[entry.Name] = entry.Result
+		}
+	}
+
+	s.history = append(s.history, entry)
+	return entry
+}
+
+// Evaluate evaluates an expression against base overlaid with the scratch
+// variables, without recording it in the history.
+func (s *Scratchpad) Evaluate(expression string, base map[string]interface{}) (interface{}, error) {
+	// A fresh evaluator per call: compiled programs are cached by expression
+	// text and would go stale as scratch variables change type
+	evaluator := transform.NewExpressionEvaluator()
+
+	ctx, cancel := context.WithTimeout(context.Background(), scratchEvalTimeout)
+	defer cancel()
+
+	return evaluator.Evaluate(ctx, expression, s.Context(base))
+}
+
+// Context returns base overlaid with the scratch variables; scratch
+// variables shadow execution variables of the same name.
+func (s *Scratchpad) Context(base map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(s.vars))
+	for name, value := range base {
+		merged[name] = value
+	}
+	for name, value := range s.vars {
+		merged[name] = value
+	}
+	return merged
+}
+
+// Variables returns a copy of the scratch variables
+func (s *Scratchpad) Variables() map[string]interface{} {
+	vars := make(map[string]interface{}, len(s.vars))
+	for name, value := range s.vars {
+		vars[name] = value
+	}
+	return vars
+}
+
+// History returns the evaluated entries, oldest first
+func (s *Scratchpad) History() []ScratchEntry {
+	return append([]ScratchEntry(nil), s.history...)
+}
+
+// formatScratchValue renders a value compactly as JSON where possible
+func formatScratchValue(value interface{}) string {
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// ScratchpadPanel renders a Scratchpad with an input line.
+type ScratchpadPanel struct {
+	x, y, width, height int
+	scratchpad          *Scratchpad
+	input               string
+}
+
+func NewScratchpadPanel(x, y, width, height int, scratchpad *Scratchpad) *ScratchpadPanel {
+	return &ScratchpadPanel{
+		x:          x,
+		y:          y,
+		width:      width,
+		height:     height,
+		scratchpad: scratchpad,
+	}
+}
+
+// Input returns the current, unsubmitted input line
+func (p *ScratchpadPanel) Input() string {
+	return p.input
+}
+
+// TypeRune appends a character to the input line
+func (p *ScratchpadPanel) TypeRune(r rune) {
+	p.input += string(r)
+}
+
+// Backspace deletes the last character of the input line
+func (p *ScratchpadPanel) Backspace() {
+	if p.input == "" {
+		return
+	}
+	runes := []rune(p.input)
+	p.input = string(runes[:len(runes)-1])
+}
+
+// Submit evaluates the input line against base and clears it
+func (p *ScratchpadPanel) Submit(base map[string]interface{}) ScratchEntry {
+	entry := p.scratchpad.Submit(p.input, base)
+	p.input = ""
+	return entry
+}
+
+func (p *ScratchpadPanel) Render(screen *goterm.Screen) {
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+	errFg := theme.Error
+
+	// Border
+	screen.DrawText(p.x, p.y, "┌─ Scratchpad (session only) ", fg, bg, goterm.StyleBold)
+	screen.DrawText(p.x+29, p.y, strings.Repeat("─", max(p.width-30, 0))+"┐", fg, bg, goterm.StyleNone)
+
+	y := p.y + 1
+	bottom := p.y + p.height - 1
+	clip := func(line string) string {
+		if len(line) > p.width-2 {
+			return line[:p.width-5] + "..."
+		}
+		return line
+	}
+
+	// Scratch variables
+	vars := p.scratchpad.Variables()
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	screen.DrawText(p.x+1, y, "Scratch variables:", fg, bg, goterm.StyleBold)
+	y++
+	if len(names) == 0 {
+		screen.DrawText(p.x+1, y, "  (none - define with name = expression)", fg, bg, goterm.StyleDim)
+		y++
+	}
+	for _, name := range names {
+		if y >= bottom-3 {
+			break
+		}
+		line := fmt.Sprintf("  %s = %s", name, formatScratchValue(vars[name]))
+		screen.DrawText(p.x+1, y, clip(line), fg, bg, goterm.StyleNone)
+		y++
+	}
+	y++
+
+	// History: show as many of the latest entries as fit above the input
+	var lines []string
+	var isErr []bool
+	for _, entry := range p.scratchpad.History() {
+		lines = append(lines, "> "+entry.Input)
+		isErr = append(isErr, false)
+		if entry.Err != nil {
+			lines = append(lines, "! "+entry.Err.Error())
+			isErr = append(isErr, true)
+		} else if entry.Name != "" || !strings.HasPrefix(entry.Input, ":") {
+			lines = append(lines, "= "+formatScratchValue(entry.Result))
+			isErr = append(isErr, false)
+		}
+	}
+	available := bottom - 2 - y
+	start := max(len(lines)-available, 0)
+	for i := start; i < len(lines); i++ {
+		lineFg := fg
+		if isErr[i] {
+			lineFg = errFg
+		}
+		screen.DrawText(p.x+1, y, clip("  "+lines[i]), lineFg, bg, goterm.StyleNone)
+		y++
+	}
+
+	// Input line
+	screen.DrawText(p.x+1, bottom-1, clip("> "+p.input+"_"), fg, bg, goterm.StyleBold)
+
+	// Bottom border
+	screen.DrawText(p.x, bottom, "└"+strings.Repeat("─", p.width-2)+"┘", fg, bg, goterm.StyleNone)
+}
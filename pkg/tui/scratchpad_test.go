@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScratchpad_Submit(t *testing.T) {
+	base := map[string]interface{}{
+		"count": 42,
+		"name":  "goflow",
+	}
+
+	tests := []struct {
+		name    string
+		lines   []string
+		want    interface{}
+		wantErr bool
+	}{
+		{"expression against base", []string{"count * 2"}, 84, false},
+		{"assignment then use", []string{"factor = 3", "count * factor"}, 126, false},
+		{"scratch shadows base", []string{"count = 1", "count + 1"}, 2, false},
+		{"comparison is not assignment", []string{"count == 42"}, true, false},
+		{"string operators", []string{`name contains "flow"`}, true, false},
+		{"undefined variable", []string{"missing + 1"}, nil, true},
+		{"unset removes variable", []string{"x = 1", ":unset x", "x"}, nil, true},
+		{"unknown command", []string{":frobnicate"}, nil, true},
+		{"empty input", []string{"   "}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pad := NewScratchpad()
+			var entry ScratchEntry
+			for _, line := range tt.lines {
+				entry = pad.Submit(line, base)
+			}
+
+			if (entry.Err != nil) != tt.wantErr {
+				t.Fatalf("Submit(%q) error = %v, wantErr %v", entry.Input, entry.Err, tt.wantErr)
+			}
+			if !tt.wantErr && entry.Result != tt.want {
+				t.Errorf("Submit(%q) = %v (%T), want %v (%T)", entry.Input, entry.Result, entry.Result, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestScratchpad_DoesNotModifyBase(t *testing.T) {
+	base := map[string]interface{}{"count": 42}
+	pad := NewScratchpad()
+
+	pad.Submit("count = 7", base)
+	pad.Submit("extra = count + 1", base)
+
+	if base["count"] != 42 || len(base) != 1 {
+		t.Errorf("Base context was modified: %v", base)
+	}
+	vars := pad.Variables()
+	if vars["count"] != 7 || vars["extra"] != 8 {
+		t.Errorf("Unexpected scratch variables: %v", vars)
+	}
+}
+
+func TestScratchpad_Clear(t *testing.T) {
+	pad := NewScratchpad()
+	pad.Submit("a = 1", nil)
+	pad.Submit("a + 1", nil)
+	if len(pad.History()) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d", len(pad.History()))
+	}
+
+	pad.Submit(":clear", nil)
+	if len(pad.History()) != 0 || len(pad.Variables()) != 0 {
+		t.Errorf("Expected empty scratchpad after :clear, got vars=%v history=%v", pad.Variables(), pad.History())
+	}
+}
+
+func TestScratchpad_TypeChangeReevaluates(t *testing.T) {
+	pad := NewScratchpad()
+	pad.Submit(`v = "a"`, nil)
+	if entry := pad.Submit("v + v", nil); entry.Result != "aa" {
+		t.Fatalf("Expected \"aa\", got %v (err=%v)", entry.Result, entry.Err)
+	}
+
+	// The same expression text must be recompiled for the new type
+	pad.Submit("v = 2", nil)
+	if entry := pad.Submit("v + v", nil); entry.Result != 4 {
+		t.Errorf("Expected 4, got %v (err=%v)", entry.Result, entry.Err)
+	}
+}
+
+func TestScratchpadPanel_Input(t *testing.T) {
+	panel := NewScratchpadPanel(0, 0, 80, 20, NewScratchpad())
+	for _, r := range "1 + 22" {
+		panel.TypeRune(r)
+	}
+	panel.Backspace()
+	if panel.Input() != "1 + 2" {
+		t.Fatalf("Input() = %q, want %q", panel.Input(), "1 + 2")
+	}
+
+	entry := panel.Submit(nil)
+	if entry.Result != 3 || panel.Input() != "" {
+		t.Errorf("Submit() = %v, input after submit = %q", entry.Result, panel.Input())
+	}
+	if !strings.Contains(formatScratchValue(map[string]interface{}{"a": 1}), `"a":1`) {
+		t.Error("formatScratchValue should render maps as JSON")
+	}
+}
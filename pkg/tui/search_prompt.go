@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// searchPrompt is the query being typed after '/' in the builder. The
+// canvas follows the query as it is typed.
+type searchPrompt struct {
+	query    []rune
+	previous string // Search before the prompt opened, restored on Escape
+}
+
+// openSearch starts typing a search query
+func (v *WorkflowBuilderView) openSearch() {
+	v.search = &searchPrompt{previous: v.builder.searchQuery}
+}
+
+// handleSearchKey edits the search query. Enter keeps the search, for n
+// and N; Escape goes back to the search before.
+func (v *WorkflowBuilderView) handleSearchKey(event KeyEvent) error {
+	p := v.search
+	switch {
+	case event.IsSpecial && event.Special == "Enter":
+		v.search = nil
+		count, err := v.builder.Search(string(p.query))
+		switch {
+		case err != nil:
+			v.statusMsg = "Error: " + err.Error()
+		case count > 0:
+			v.statusMsg = fmt.Sprintf("%d matching nodes: n/N for next/previous", count)
+		default:
+			v.statusMsg = "Ready"
+		}
+		return nil
+	case event.IsSpecial && event.Special == "Escape", event.Ctrl && event.Key == 'c':
+		v.search = nil
+		_, _ = v.builder.Search(p.previous) // Found before
+		v.statusMsg = "Ready"
+		return nil
+	case event.IsSpecial && event.Special == "Backspace":
+		if len(p.query) == 0 {
+			v.search = nil
+			v.builder.ClearSearch()
+			return nil
+		}
+		p.query = p.query[:len(p.query)-1]
+	case event.Ctrl && event.Key == 'u':
+		p.query = p.query[:0]
+	case !event.IsSpecial && !event.Ctrl && !event.Alt && event.Key != 0:
+		p.query = append(p.query, event.Key)
+	default:
+		return nil
+	}
+	_, _ = v.builder.Search(string(p.query)) // Misses are shown in the prompt
+	return nil
+}
+
+// searchStatus returns what the status bar shows of the search: the query
+// being typed, or the search n and N step through
+func (v *WorkflowBuilderView) searchStatus() string {
+	if v.search != nil {
+		status := "/" + string(v.search.query)
+		if len(v.search.query) > 0 && len(v.builder.searchMatches()) == 0 {
+			status += " (no matches)"
+		}
+		return status
+	}
+	return v.builder.SearchStatus()
+}
+
+// savedNodeItems lists the nodes of the saved workflows other than the one
+// being built, for the fuzzy finder: each is searched by its ID and what
+// the builder's search looks at, and choosing it opens the workflow at
+// the node. Files that cannot be parsed are skipped.
+func (v *WorkflowBuilderView) savedNodeItems() []FinderItem {
+	workflows, _ := listWorkflowFiles(v.workflowsDir)
+	current, _ := filepath.Abs(v.workflowPath)
+
+	var items []FinderItem
+	for _, name := range workflows {
+		path := filepath.Join(v.workflowsDir, name)
+		if abs, _ := filepath.Abs(path); abs == current {
+			continue // Its nodes are listed as the canvas's
+		}
+		wf, err := workflow.ParseFile(path)
+		if err != nil {
+			continue
+		}
+		for _, node := range wf.Nodes {
+			text := nodeSearchText(node)
+			if note := wf.Note(node.GetID()); note != "" {
+				text = append(text, note)
+			}
+			items = append(items, FinderItem{
+				Kind:   FinderWorkflow,
+				Label:  name + " › " + node.GetID(),
+				Detail: strings.Join(nonEmpty(text[1:]), "  "),
+				Value:  name,
+				Node:   node.GetID(),
+			})
+		}
+	}
+	return items
+}
+
+// nonEmpty returns the strings that are not empty
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -1,13 +1,23 @@
 package tui
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/dshills/goflow/pkg/audit"
+	"github.com/dshills/goflow/pkg/events"
 	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
 	"github.com/dshills/goflow/pkg/tui/components"
+	"github.com/dshills/goflow/pkg/validation"
+	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/dshills/goterm"
 )
 
@@ -20,18 +30,30 @@ type ServerRegistryView struct {
 	name           string
 	active         bool
 	registry       mcpserver.ServerRepository
-	servers        []*mcpserver.MCPServer
+	allServers     []*mcpserver.MCPServer // All registered servers
+	servers        []*mcpserver.MCPServer // Servers matching filterQuery
 	selectedIdx    int
+	filterMode     bool                   // Editing the filter query
+	filterQuery    string                 // Filter by name, ID, transport, health, or tag
+	listWindow     *components.ListWindow // Visible slice of the server list
+	listRows       int                    // Rows available to the list at last render
 	statusMsg      string
 	initialized    bool
-	showDetails    bool              // T199: Show detailed server info and tools
-	showToolSchema bool              // T199: Show tool schema details
-	selectedTool   int               // T199: Selected tool index in schema view
-	currentModal   *components.Modal // T197: Modal for add/edit dialogs
+	showDetails    bool                     // T199: Show detailed server info and tools
+	showToolSchema bool                     // T199: Show tool schema details
+	selectedTool   int                      // T199: Selected tool index in schema view
+	playground     *toolPlayground          // Tool being tried from the schema view, if any
+	toolSamples    *storage.ToolSampleStore // Records the playground's responses
+	currentModal   *components.Modal        // T197: Modal for add/edit dialogs
 	addDialogState *addServerDialogState
 	autoRefresh    bool      // T198: Auto-refresh health status
 	lastRefresh    time.Time // T198: Last health check time
-	errorMsg       string    // Error message display
+	healthMonitor  *mcpserver.HealthMonitor
+	unwatchHealth  func()                    // Ends the health change subscription
+	errorMsg       string                    // Error message display
+	registryErr    error                     // Why the persistent registry is unavailable
+	toolSchemas    string                    // File of known tool schemas rediscoveries are compared with; empty compares nothing
+	openWorkflow   func() *workflow.Workflow // Returns the workflow open in the builder, if any
 	width          int
 	height         int
 	viewSwitcher   ViewSwitcher // For switching to other views
@@ -43,10 +65,20 @@ type addServerDialogState struct {
 	serverID      string
 	serverName    string
 	transportType mcpserver.TransportType
-	command       string // For stdio
-	args          string // For stdio (comma-separated)
-	url           string // For SSE/HTTP
-	currentField  string // Current field being edited
+	command       string               // For stdio
+	args          string               // For stdio (comma-separated)
+	url           string               // For SSE/HTTP
+	currentField  string               // Current field being edited
+	editing       *mcpserver.MCPServer // Server being edited; nil when adding
+}
+
+// title returns the dialog title for a step of the add dialog; the edit
+// dialog skips the ID step, so its steps are numbered from the name
+func (s *addServerDialogState) title(step int) string {
+	if s.editing != nil {
+		return fmt.Sprintf("Edit MCP Server - Step %d/3", step-1)
+	}
+	return fmt.Sprintf("Add MCP Server - Step %d/4", step)
 }
 
 // NewServerRegistryView creates a new server registry view
@@ -55,21 +87,69 @@ func NewServerRegistryView() *ServerRegistryView {
 		name:           "registry",
 		active:         false,
 		registry:       mcpserver.NewRegistry(), // Default in-memory registry
+		allServers:     make([]*mcpserver.MCPServer, 0),
 		servers:        make([]*mcpserver.MCPServer, 0),
 		selectedIdx:    0,
+		listWindow:     components.NewListWindow(),
 		showDetails:    false,
 		showToolSchema: false,
 		selectedTool:   0,
 		autoRefresh:    true, // T198: Enable auto-refresh by default
 		lastRefresh:    time.Time{},
+		toolSamples:    storage.NewToolSampleStore(storage.DefaultToolSamplesDir()),
+	}
+}
+
+// DefaultServersPath returns servers.yaml in GOFLOW_CONFIG_DIR, or in
+// ~/.goflow when it is not set
+func DefaultServersPath() string {
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "servers.yaml")
 	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "servers.yaml")
+	}
+	return filepath.Join(homeDir, ".goflow", "servers.yaml")
+}
+
+// UsePersistentRegistry switches to the servers saved in the config file at
+// path, so servers added or deleted in the view persist across restarts.
+// When the file cannot be loaded the view keeps its in-memory registry and
+// reports why once initialized. Entries that are not valid servers are
+// kept in the file and reported the same way.
+func (v *ServerRegistryView) UsePersistentRegistry(path string) {
+	repo, err := mcpserver.NewFileRepository(path)
+	if err != nil {
+		v.registryErr = fmt.Errorf("servers will not be saved: %w", err)
+		return
+	}
+	v.stopHealthMonitor()
+	v.registry = repo
+	v.toolSchemas = filepath.Join(filepath.Dir(path), "tool_schemas.json")
+	if loadErrs := repo.LoadErrors(); len(loadErrs) > 0 {
+		v.registryErr = fmt.Errorf("%d server(s) in %s not loaded: %w", len(loadErrs), path, loadErrs[0])
+	}
+}
+
+// SetOpenWorkflow sets how the view finds the workflow open in the
+// builder, which it warns about when tools it calls change
+func (v *ServerRegistryView) SetOpenWorkflow(openWorkflow func() *workflow.Workflow) {
+	v.openWorkflow = openWorkflow
 }
 
 // SetRegistry sets the server repository to use
 func (v *ServerRegistryView) SetRegistry(registry mcpserver.ServerRepository) {
+	v.stopHealthMonitor()
 	v.registry = registry
 }
 
+// CapturingText reports whether tool arguments are being typed, so the app
+// passes every key to the view
+func (v *ServerRegistryView) CapturingText() bool {
+	return v.playground != nil
+}
+
 // Name returns the unique identifier for this view
 func (v *ServerRegistryView) Name() string {
 	return v.name
@@ -82,6 +162,7 @@ func (v *ServerRegistryView) SetViewSwitcher(switcher ViewSwitcher) {
 
 // Init initializes the server registry view
 func (v *ServerRegistryView) Init() error {
+	v.startHealthMonitor()
 	if v.initialized {
 		// Refresh server list
 		return v.loadServers()
@@ -95,6 +176,9 @@ func (v *ServerRegistryView) Init() error {
 
 	v.selectedIdx = 0
 	v.statusMsg = "Ready"
+	if v.registryErr != nil {
+		v.statusMsg = fmt.Sprintf("Server registry: %v", v.registryErr)
+	}
 	v.initialized = true
 	v.lastRefresh = time.Now()
 
@@ -112,21 +196,139 @@ func (v *ServerRegistryView) loadServers() error {
 		return err
 	}
 
-	v.servers = servers
+	// Stable order so the list doesn't reshuffle between refreshes
+	sort.SliceStable(servers, func(i, j int) bool {
+		if servers[i].Name != servers[j].Name {
+			return servers[i].Name < servers[j].Name
+		}
+		return servers[i].ID < servers[j].ID
+	})
+
+	v.allServers = servers
+	v.applyFilter()
 
-	// Adjust selected index if needed
+	return nil
+}
+
+// applyFilter rebuilds the visible server list from filterQuery, keeping
+// the current server selected when it still matches
+func (v *ServerRegistryView) applyFilter() {
+	var selectedID string
+	if v.selectedIdx >= 0 && v.selectedIdx < len(v.servers) {
+		selectedID = v.servers[v.selectedIdx].ID
+	}
+
+	if strings.TrimSpace(v.filterQuery) == "" {
+		v.servers = v.allServers
+	} else {
+		filtered := make([]*mcpserver.MCPServer, 0, len(v.allServers))
+		for _, server := range v.allServers {
+			if v.matchesFilter(server) {
+				filtered = append(filtered, server)
+			}
+		}
+		v.servers = filtered
+	}
+
+	// Restore selection, or clamp it to the new list
+	for i, server := range v.servers {
+		if selectedID != "" && server.ID == selectedID {
+			v.selectedIdx = i
+			return
+		}
+	}
 	if v.selectedIdx >= len(v.servers) && len(v.servers) > 0 {
 		v.selectedIdx = len(v.servers) - 1
 	} else if len(v.servers) == 0 {
 		v.selectedIdx = 0
 	}
+}
+
+// matchesFilter reports whether every term of filterQuery matches the
+// server's name, ID, transport type, health status, or tags. Terms written
+// tag:x or #x match only servers tagged x.
+func (v *ServerRegistryView) matchesFilter(server *mcpserver.MCPServer) bool {
+	fields := []string{
+		strings.ToLower(server.Name),
+		strings.ToLower(server.ID),
+		strings.ToLower(v.getHealthStatusLabel(server)),
+	}
+	if server.Transport != nil {
+		fields = append(fields, strings.ToLower(string(server.Transport.Type())))
+	}
+	fields = append(fields, server.Tags...)
+
+	for _, term := range strings.Fields(strings.ToLower(v.filterQuery)) {
+		// tag:prod and #prod match the tag exactly
+		if tag, ok := strings.CutPrefix(term, "tag:"); ok || strings.HasPrefix(term, "#") {
+			if !ok {
+				tag = term[1:]
+			}
+			if !server.HasTags(tag) {
+				return false
+			}
+			continue
+		}
+
+		matched := false
+		for _, field := range fields {
+			if strings.Contains(field, term) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// handleFilterKeys handles keyboard input while editing the filter
+func (v *ServerRegistryView) handleFilterKeys(event KeyEvent) error {
+	switch {
+	case event.IsSpecial && event.Special == "Enter":
+		v.filterMode = false
+		v.statusMsg = "Ready"
+	case event.IsSpecial && event.Special == "Escape":
+		v.filterMode = false
+		v.filterQuery = ""
+		v.applyFilter()
+		v.statusMsg = "Filter cleared"
+	case event.IsSpecial && event.Special == "Backspace":
+		if len(v.filterQuery) > 0 {
+			v.filterQuery = v.filterQuery[:len(v.filterQuery)-1]
+			v.applyFilter()
+		}
+	case event.IsSpecial && event.Special == "Down":
+		if v.selectedIdx < len(v.servers)-1 {
+			v.selectedIdx++
+		}
+	case event.IsSpecial && event.Special == "Up":
+		if v.selectedIdx > 0 {
+			v.selectedIdx--
+		}
+	case !event.IsSpecial && !event.Ctrl && event.Key >= 32 && event.Key <= 126:
+		v.filterQuery += string(event.Key)
+		v.applyFilter()
+	}
 
 	return nil
 }
 
+// pageRows returns how many rows one page of the server list spans
+func (v *ServerRegistryView) pageRows() int {
+	if v.listRows > 0 {
+		return v.listRows
+	}
+	return 10
+}
+
 // Cleanup releases resources when view is deactivated
 func (v *ServerRegistryView) Cleanup() error {
-	// Preserve state for when we return to this view
+	// Preserve state for when we return to this view, but stop checking
+	// health until then
+	v.stopHealthMonitor()
 	return nil
 }
 
@@ -139,11 +341,21 @@ func (v *ServerRegistryView) HandleKey(event KeyEvent) error {
 		return nil
 	}
 
+	// The tool playground takes every key while open
+	if v.playground != nil {
+		return v.handlePlaygroundKey(event)
+	}
+
 	// Tool schema view navigation (T199)
 	if v.showToolSchema {
 		return v.handleToolSchemaKeys(event)
 	}
 
+	// Filter input
+	if v.filterMode {
+		return v.handleFilterKeys(event)
+	}
+
 	// Normal view navigation
 	switch {
 	case event.Key == 'j' || (event.IsSpecial && event.Special == "Down"):
@@ -174,6 +386,23 @@ func (v *ServerRegistryView) HandleKey(event KeyEvent) error {
 			v.showDetails = false
 			v.showToolSchema = false
 		}
+	case event.IsSpecial && (event.Special == "PageDown" || event.Special == "PageUp"):
+		// Move one page of the list
+		if len(v.servers) > 0 {
+			direction := 1
+			if event.Special == "PageUp" {
+				direction = -1
+			}
+			v.selectedIdx = components.PageStep(v.selectedIdx, len(v.servers), v.pageRows(), direction)
+			v.showDetails = false
+			v.showToolSchema = false
+		}
+	case event.Key == '/':
+		// Filter servers by name, transport, or health
+		v.filterMode = true
+		v.showDetails = false
+		v.showToolSchema = false
+		v.statusMsg = "Filter: type to match name/transport/health/tag, tag:x for a tag (Enter: apply, Esc: clear)"
 	case event.IsSpecial && event.Special == "Enter":
 		// Toggle detailed info view (T198, T199)
 		if len(v.servers) > 0 {
@@ -205,7 +434,7 @@ func (v *ServerRegistryView) HandleKey(event KeyEvent) error {
 			v.showDetails = false
 			v.selectedTool = 0
 			if v.showToolSchema {
-				v.statusMsg = "Viewing tool schemas (j/k: navigate, Enter: details, Esc: back)"
+				v.statusMsg = "Viewing tool schemas (j/k: navigate, y: copy name, Y: copy node, t: try, Esc: back)"
 			} else {
 				v.statusMsg = "Ready"
 			}
@@ -213,6 +442,14 @@ func (v *ServerRegistryView) HandleKey(event KeyEvent) error {
 	case event.Key == 'a':
 		// T197: Add new server dialog
 		v.showAddServerDialog()
+	case event.Key == 'e':
+		// Edit the selected server
+		if len(v.servers) > 0 {
+			v.showEditServerDialog()
+		}
+	case event.Key == 'I':
+		// Import servers from an MCP client config
+		v.showImportDialog()
 	case event.Key == 'd':
 		// Delete server
 		if len(v.servers) > 0 {
@@ -240,21 +477,29 @@ func (v *ServerRegistryView) HandleKey(event KeyEvent) error {
 		// T198: Toggle auto-refresh
 		v.autoRefresh = !v.autoRefresh
 		if v.autoRefresh {
+			v.startHealthMonitor()
 			v.statusMsg = "Auto-refresh enabled"
 			v.lastRefresh = time.Now()
 		} else {
+			if v.healthMonitor != nil {
+				v.healthMonitor.Stop()
+			}
 			v.statusMsg = "Auto-refresh disabled"
 		}
 	case event.Key == '?':
 		// Show help
 		v.showHelp()
 	case event.IsSpecial && event.Special == "Escape":
-		// Exit details/schema view
+		// Exit details/schema view, then clear an active filter
 		if v.showDetails || v.showToolSchema {
 			v.showDetails = false
 			v.showToolSchema = false
 			v.selectedTool = 0
 			v.statusMsg = "Ready"
+		} else if v.filterQuery != "" {
+			v.filterQuery = ""
+			v.applyFilter()
+			v.statusMsg = "Filter cleared"
 		}
 	}
 
@@ -289,17 +534,55 @@ func (v *ServerRegistryView) handleToolSchemaKeys(event KeyEvent) error {
 		v.showToolSchema = false
 		v.selectedTool = 0
 		v.statusMsg = "Ready"
-	case event.IsSpecial && event.Special == "Enter":
-		// TODO: Copy tool name to clipboard for workflow building
+	case (event.IsSpecial && event.Special == "Enter") || event.Key == 'y':
+		// Copy the tool name for workflow building
+		if v.selectedTool < toolCount {
+			v.statusMsg = copyText("tool name "+server.Tools[v.selectedTool].Name, server.Tools[v.selectedTool].Name)
+		}
+	case event.Key == 'Y':
+		// Copy a tool node calling the tool, to paste into a workflow file
 		if v.selectedTool < toolCount {
-			toolName := server.Tools[v.selectedTool].Name
-			v.statusMsg = fmt.Sprintf("Selected: %s", toolName)
+			snippet, err := toolNodeSnippet(server.ID, server.Tools[v.selectedTool])
+			if err != nil {
+				v.statusMsg = fmt.Sprintf("Failed to build node snippet: %v", err)
+				return nil
+			}
+			v.statusMsg = copyText("node snippet for "+server.Tools[v.selectedTool].Name, snippet)
+		}
+	case event.Key == 't':
+		// Try the selected tool, or discover tools if there are none yet
+		if toolCount == 0 {
+			v.testServerConnection()
+		} else {
+			v.openToolPlayground()
 		}
 	}
 
 	return nil
 }
 
+// toolNodeSnippet returns the YAML of a tool node calling a server's tool,
+// with a placeholder for each required argument
+func toolNodeSnippet(serverID string, tool mcpserver.Tool) (string, error) {
+	node := &workflow.MCPToolNode{
+		ID:             tool.Name,
+		ServerID:       serverID,
+		ToolName:       tool.Name,
+		OutputVariable: tool.Name + "_result",
+	}
+	if tool.InputSchema != nil && len(tool.InputSchema.Required) > 0 {
+		node.Parameters = make(map[string]string, len(tool.InputSchema.Required))
+		for _, param := range tool.InputSchema.Required {
+			node.Parameters[param] = "${" + param + "}"
+		}
+	}
+	data, err := workflow.NodesToYAML([]workflow.Node{node}, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // keyEventToString converts KeyEvent to string for modal handling
 func (v *ServerRegistryView) keyEventToString(event KeyEvent) string {
 	if event.IsSpecial {
@@ -339,12 +622,40 @@ func (v *ServerRegistryView) showAddServerDialog() {
 	modal.Show()
 }
 
+// showEditServerDialog opens the add dialog's name, transport and
+// transport config steps pre-populated from the selected server
+func (v *ServerRegistryView) showEditServerDialog() {
+	if v.selectedIdx >= len(v.servers) {
+		return
+	}
+	server := v.servers[v.selectedIdx]
+
+	transportType := mcpserver.TransportStdio
+	if server.Transport != nil {
+		transportType = server.Transport.Type()
+	}
+	v.addDialogState = &addServerDialogState{
+		step:          1,
+		serverID:      server.ID,
+		serverName:    server.Name,
+		transportType: transportType,
+		currentField:  "Name",
+		editing:       server,
+	}
+	v.showAddServerDialogStep2()
+}
+
 // showAddServerDialogStep2 shows step 2 of add dialog (server name)
 func (v *ServerRegistryView) showAddServerDialogStep2() {
+	defaultName := v.addDialogState.serverID // Default to ID
+	if v.addDialogState.editing != nil {
+		defaultName = v.addDialogState.editing.Name
+	}
+
 	modal := components.NewInputModal(
-		"Add MCP Server - Step 2/4",
+		v.addDialogState.title(2),
 		"Enter server name (display name):",
-		v.addDialogState.serverID, // Default to ID
+		defaultName,
 		func(confirmed bool, input string) {
 			if confirmed && input != "" {
 				v.addDialogState.serverName = input
@@ -364,9 +675,9 @@ func (v *ServerRegistryView) showAddServerDialogStep2() {
 // showAddServerDialogStep3 shows step 3 of add dialog (transport type)
 func (v *ServerRegistryView) showAddServerDialogStep3() {
 	modal := components.NewInputModal(
-		"Add MCP Server - Step 3/4",
+		v.addDialogState.title(3),
 		"Enter transport type (stdio, sse, or http):",
-		"stdio",
+		string(v.addDialogState.transportType),
 		func(confirmed bool, input string) {
 			if !confirmed {
 				v.currentModal = nil
@@ -399,7 +710,7 @@ func (v *ServerRegistryView) showAddServerDialogStep4() {
 
 	switch v.addDialogState.transportType {
 	case mcpserver.TransportStdio:
-		prompt = "Enter command (e.g., 'mcp-server-filesystem'):\nOptional args can be added after, separated by commas"
+		prompt = "Enter command (e.g., 'mcp-server-filesystem' or '~/bin/server'):\nOptional args can be added after, separated by commas"
 		defaultVal = ""
 	case mcpserver.TransportSSE:
 		prompt = "Enter SSE URL (e.g., 'http://localhost:3000/sse'):"
@@ -409,8 +720,17 @@ func (v *ServerRegistryView) showAddServerDialogStep4() {
 		defaultVal = ""
 	}
 
+	// Edits start from the server's config while the transport is unchanged
+	if editing := v.addDialogState.editing; editing != nil && editing.Transport != nil &&
+		editing.Transport.Type() == v.addDialogState.transportType {
+		defaultVal = editing.Command
+		if v.addDialogState.transportType == mcpserver.TransportStdio && len(editing.Args) > 0 {
+			defaultVal += ", " + strings.Join(editing.Args, ", ")
+		}
+	}
+
 	modal := components.NewInputModal(
-		"Add MCP Server - Step 4/4",
+		v.addDialogState.title(4),
 		prompt,
 		defaultVal,
 		func(confirmed bool, input string) {
@@ -435,22 +755,70 @@ func (v *ServerRegistryView) showAddServerDialogStep4() {
 				v.addDialogState.url = strings.TrimSpace(input)
 			}
 
+			if v.addDialogState.editing != nil {
+				v.applyServerEdit()
+				return
+			}
+
 			// Create the server
 			v.createServerFromDialog()
 		},
 	)
 
+	if v.addDialogState.transportType == mcpserver.TransportStdio {
+		modal.SetValidator(validateStdioCommandInput)
+	} else {
+		modal.SetValidator(validateServerURLInput)
+	}
+
 	v.currentModal = modal
 	modal.Show()
 }
 
-// createServerFromDialog creates and registers a server from dialog state
-func (v *ServerRegistryView) createServerFromDialog() {
-	state := v.addDialogState
+// validateStdioCommandInput normalizes the command portion of
+// "command, arg1, arg2" input (tilde/env expansion, native separators)
+// and rejects paths that do not exist so the dialog can report it inline
+func validateStdioCommandInput(input string) (string, error) {
+	parts := strings.Split(input, ",")
+	command, err := validation.ValidateCommandInput(parts[0])
+	if err != nil {
+		return "", inlinePathError(err)
+	}
+	parts[0] = command
+	return strings.Join(parts, ","), nil
+}
+
+// validateServerURLInput rejects SSE/HTTP endpoints that are not absolute
+// http(s) URLs so the dialog can report it inline
+func validateServerURLInput(input string) (string, error) {
+	trimmed := strings.TrimSpace(input)
+	parsed, err := url.Parse(trimmed)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", errors.New("enter an http:// or https:// URL")
+	}
+	return trimmed, nil
+}
+
+// inlinePathError reduces a path validation error to its reason, which is
+// all that fits in a dialog's inline error line
+func inlinePathError(err error) error {
+	var validationErr *validation.ValidationError
+	if errors.As(err, &validationErr) {
+		return errors.New(validationErr.Reason)
+	}
+	return err
+}
+
+// dialogServerConfig returns the command and args entered in the dialog;
+// for SSE/HTTP the command is the URL
+func (s *addServerDialogState) dialogServerConfig() (string, []string) {
+	if s.transportType == mcpserver.TransportSSE || s.transportType == mcpserver.TransportHTTP {
+		return s.url, nil
+	}
 
 	var args []string
-	if state.args != "" {
-		argParts := strings.Split(state.args, ",")
+	if s.args != "" {
+		argParts := strings.Split(s.args, ",")
 		for _, arg := range argParts {
 			trimmed := strings.TrimSpace(arg)
 			if trimmed != "" {
@@ -458,12 +826,13 @@ func (v *ServerRegistryView) createServerFromDialog() {
 			}
 		}
 	}
+	return s.command, args
+}
 
-	// For SSE/HTTP, command is the URL
-	command := state.command
-	if state.transportType == mcpserver.TransportSSE || state.transportType == mcpserver.TransportHTTP {
-		command = state.url
-	}
+// createServerFromDialog creates and registers a server from dialog state
+func (v *ServerRegistryView) createServerFromDialog() {
+	state := v.addDialogState
+	command, args := state.dialogServerConfig()
 
 	// Create server
 	server, err := mcpserver.NewMCPServer(state.serverID, command, args, state.transportType)
@@ -489,6 +858,8 @@ func (v *ServerRegistryView) createServerFromDialog() {
 		return
 	}
 
+	recordAudit(audit.ServerAdded, server.ID, "")
+
 	// Reload servers
 	_ = v.loadServers()
 
@@ -505,6 +876,169 @@ func (v *ServerRegistryView) createServerFromDialog() {
 	v.addDialogState = nil
 }
 
+// applyServerEdit applies the edit dialog to the server being edited. A
+// rename keeps the server and its connection; a changed transport config
+// replaces the server, keeping its environment, headers, auth, limits and tags,
+// and reconnects it if the old one was connected.
+func (v *ServerRegistryView) applyServerEdit() {
+	state := v.addDialogState
+	original := state.editing
+	v.currentModal = nil
+	v.addDialogState = nil
+
+	name := state.serverName
+	if name == "" {
+		name = original.ID
+	}
+	command, args := state.dialogServerConfig()
+	updated, err := mcpserver.NewMCPServer(original.ID, command, args, state.transportType)
+	if err == nil {
+		err = updated.Transport.Validate()
+	}
+	if err != nil {
+		v.statusMsg = fmt.Sprintf("Invalid server configuration: %v", err)
+		v.errorMsg = err.Error()
+		return
+	}
+
+	if original.Transport != nil && original.Transport.Type() == updated.Transport.Type() &&
+		original.Command == updated.Command && slices.Equal(original.Args, updated.Args) {
+		if name == original.Name {
+			v.statusMsg = "No changes"
+			return
+		}
+		previousName := original.Name
+		original.Name = name
+		if err := v.registry.Update(original); err != nil {
+			original.Name = previousName
+			v.statusMsg = fmt.Sprintf("Error updating server: %v", err)
+			v.errorMsg = err.Error()
+			return
+		}
+		v.finishServerEdit(original.ID, fmt.Sprintf("Server '%s' renamed to '%s'", original.ID, name))
+		return
+	}
+
+	carryTransportSettings(original.Transport, updated.Transport)
+	updated.Name = name
+	updated.Limits = original.Limits
+	updated.Tags = original.Tags
+	wasConnected := original.Connection.GetState() == mcpserver.StateConnected
+	if err := v.registry.Update(updated); err != nil {
+		v.statusMsg = fmt.Sprintf("Error updating server: %v", err)
+		v.errorMsg = err.Error()
+		return
+	}
+
+	status := fmt.Sprintf("Server '%s' updated", original.ID)
+	if wasConnected {
+		_ = original.Disconnect()
+		if err := updated.Connect(); err == nil {
+			err = updated.CompleteConnection()
+		}
+		if err != nil {
+			_ = updated.FailConnection(err.Error())
+			v.errorMsg = err.Error()
+			status += fmt.Sprintf("; reconnect failed: %v", err)
+		} else {
+			status += " and reconnected"
+		}
+	}
+	v.finishServerEdit(original.ID, status)
+}
+
+// finishServerEdit reloads the list with the edited server selected
+func (v *ServerRegistryView) finishServerEdit(id, status string) {
+	_ = v.loadServers()
+	for i, s := range v.servers {
+		if s.ID == id {
+			v.selectedIdx = i
+			break
+		}
+	}
+	v.showDetails = false
+	v.showToolSchema = false
+	v.statusMsg = status
+}
+
+// carryTransportSettings copies the settings the edit dialog does not show
+// (environment, headers and auth) to a replacement transport of the same
+// kind
+func carryTransportSettings(from, to mcpserver.Transport) {
+	switch to := to.(type) {
+	case *mcpserver.StdioTransportConfig:
+		if from, ok := from.(*mcpserver.StdioTransportConfig); ok {
+			to.Env = from.Env
+		}
+	case *mcpserver.SSETransportConfig:
+		switch from := from.(type) {
+		case *mcpserver.SSETransportConfig:
+			to.Headers, to.Auth = from.Headers, from.Auth
+		case *mcpserver.HTTPTransportConfig:
+			to.Headers, to.Auth = from.Headers, from.Auth
+		}
+	case *mcpserver.HTTPTransportConfig:
+		switch from := from.(type) {
+		case *mcpserver.SSETransportConfig:
+			to.Headers, to.Auth = from.Headers, from.Auth
+		case *mcpserver.HTTPTransportConfig:
+			to.Headers, to.Auth = from.Headers, from.Auth
+		}
+	}
+}
+
+// showImportDialog asks for an MCP client config to import servers from,
+// defaulting to Claude Desktop's
+func (v *ServerRegistryView) showImportDialog() {
+	modal := components.NewInputModal(
+		"Import MCP Servers",
+		"mcpServers config file (Claude Desktop format):",
+		mcpserver.ClaudeDesktopConfigPath(),
+		func(confirmed bool, input string) {
+			v.currentModal = nil
+			if !confirmed || strings.TrimSpace(input) == "" {
+				v.statusMsg = "Cancelled"
+				return
+			}
+			_ = v.importServers(strings.TrimSpace(input)) // Reported in the status line
+		},
+	)
+
+	v.currentModal = modal
+	modal.Show()
+}
+
+// importServers registers the servers of a standard mcpServers config file,
+// leaving servers already registered unchanged
+func (v *ServerRegistryView) importServers(path string) error {
+	fail := func(err error) error {
+		v.statusMsg = fmt.Sprintf("Error importing servers: %v", err)
+		v.errorMsg = err.Error()
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fail(err)
+	}
+	servers, skipped, err := mcpserver.ImportMCPConfig(data)
+	if err != nil {
+		return fail(err)
+	}
+	imported, existing, err := mcpserver.ImportServers(v.registry, servers)
+	_ = v.loadServers()
+	if err != nil {
+		return fail(err)
+	}
+
+	v.errorMsg = ""
+	v.statusMsg = fmt.Sprintf("Imported %d server(s) from %s", len(imported), filepath.Base(path))
+	if unchanged := len(existing) + len(skipped); unchanged > 0 {
+		v.statusMsg += fmt.Sprintf(" (%d already registered, %d skipped)", len(existing), len(skipped))
+	}
+	return nil
+}
+
 // showDeleteConfirmation shows delete confirmation dialog
 func (v *ServerRegistryView) showDeleteConfirmation() {
 	if v.selectedIdx >= len(v.servers) {
@@ -547,6 +1081,7 @@ func (v *ServerRegistryView) deleteSelectedServer() {
 		return
 	}
 
+	recordAudit(audit.ServerRemoved, server.ID, "")
 	v.statusMsg = fmt.Sprintf("Server '%s' deleted", server.Name)
 	_ = v.loadServers()
 }
@@ -584,37 +1119,295 @@ func (v *ServerRegistryView) testServerConnection() {
 		return
 	}
 
-	// Discover tools if not already done
-	if len(server.Tools) == 0 {
-		if err := server.DiscoverTools(); err != nil {
-			v.statusMsg = fmt.Sprintf("Tool discovery failed: %v", err)
-			v.errorMsg = err.Error()
-			return
-		}
+	// Rediscover tools, since the server may have changed them
+	if err := server.DiscoverTools(); err != nil {
+		v.statusMsg = fmt.Sprintf("Tool discovery failed: %v", err)
+		v.errorMsg = err.Error()
+		Notifications().Notify(NotifyError, "%s: tool discovery failed: %v", server.Name, err)
+		return
 	}
+	Notifications().Notify(NotifySuccess, "%s: discovered %d tools", server.Name, len(server.Tools))
 
 	v.statusMsg = fmt.Sprintf("Connection test successful - %d tools available", len(server.Tools))
 	v.lastRefresh = time.Now()
+	v.reviewToolChanges(server)
 }
 
-// refreshServerStatus refreshes health status for all servers (T198)
-func (v *ServerRegistryView) refreshServerStatus() {
-	v.statusMsg = "Refreshing server status..."
+// reviewToolChanges compares a server's discovered tools with those known
+// from its last discovery and records them. Changes are reported, with a
+// warning that stays up until the next discovery when the open workflow
+// calls a changed or removed tool.
+func (v *ServerRegistryView) reviewToolChanges(server *mcpserver.MCPServer) {
+	if v.toolSchemas == "" {
+		return
+	}
+	known, err := mcpserver.LoadKnownTools(v.toolSchemas)
+	if err != nil {
+		Notifications().Notify(NotifyError, "%s: %v", server.Name, err)
+		return
+	}
+	changes := known.Update(server.ID, server.Tools)
+	if err := known.Save(v.toolSchemas); err != nil {
+		Notifications().Notify(NotifyError, "%s: failed to save tool schemas: %v", server.Name, err)
+	}
 
-	healthyCount := 0
-	errorCount := 0
+	warningKey := "tools:" + server.ID
+	if len(changes) == 0 {
+		Notifications().ClearWarning(warningKey)
+		return
+	}
+	summaries := make([]string, len(changes))
+	for i, change := range changes {
+		summaries[i] = change.String()
+	}
+	Notifications().Notify(NotifyInfo, "%s: tools changed: %s", server.Name, strings.Join(summaries, ", "))
 
-	for _, server := range v.servers {
-		// Only check health for connected servers
-		if server.Connection.GetState() == mcpserver.StateConnected {
-			if err := server.HealthCheck(); err != nil {
-				errorCount++
-			} else {
-				healthyCount++
+	var wf *workflow.Workflow
+	if v.openWorkflow != nil {
+		wf = v.openWorkflow()
+	}
+	affected := changedToolNodes(wf, server.ID, changes)
+	if len(affected) == 0 {
+		Notifications().ClearWarning(warningKey)
+		return
+	}
+	warning := fmt.Sprintf("Workflow %s calls changed tools of %s: %s", wf.Name, server.ID, strings.Join(affected, ", "))
+	Notifications().Warn(warningKey, warning)
+	v.statusMsg = warning
+}
+
+// changedToolNodes lists the nodes of wf, as "node (tool)", that call a tool
+// of the server that changed or was removed
+func changedToolNodes(wf *workflow.Workflow, serverID string, changes []mcpserver.ToolChange) []string {
+	if wf == nil {
+		return nil
+	}
+	breaking := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if change.Breaking() {
+			breaking[change.Tool] = true
+		}
+	}
+
+	var nodes []string
+	for _, node := range wf.Nodes {
+		var nodeID, nodeServer, toolName string
+		switch n := node.(type) {
+		case *workflow.MCPToolNode:
+			nodeID, nodeServer, toolName = n.ID, n.ServerID, n.ToolName
+		case *workflow.BatchToolNode:
+			nodeID, nodeServer, toolName = n.ID, n.ServerID, n.ToolName
+		}
+		if nodeServer == serverID && breaking[toolName] {
+			nodes = append(nodes, fmt.Sprintf("%s (%s)", nodeID, toolName))
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// RegisterCommands registers the :server commands, which act on a server
+// by ID rather than on the selection
+func (v *ServerRegistryView) RegisterCommands(registry *CommandRegistry) error {
+	actions := []struct {
+		name        string
+		description string
+		run         func()
+	}{
+		{"connect", "Connect to an MCP server", v.connectServer},
+		{"disconnect", "Disconnect from an MCP server", v.disconnectServer},
+		{"test", "Test an MCP server connection", v.testServerConnection},
+	}
+
+	if err := registry.Register(Command{
+		Name:        "server tag",
+		Usage:       "<id> [tag...]",
+		Description: "Set the tags of an MCP server (none clears them)",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			return v.tagServer(args[0], args[1:])
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			ids := make([]string, 0, len(v.allServers))
+			for _, server := range v.allServers {
+				ids = append(ids, server.ID)
+			}
+			return ids
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "server import",
+		Usage:       "[config-file]",
+		Description: "Import MCP servers from a Claude Desktop config",
+		MaxArgs:     1,
+		Run: func(args []string) error {
+			path := mcpserver.ClaudeDesktopConfigPath()
+			if len(args) > 0 {
+				path = args[0]
 			}
+			return v.importServers(path)
+		},
+	}); err != nil {
+		return err
+	}
+
+	for _, action := range actions {
+		run := action.run
+		if err := registry.Register(Command{
+			Name:        "server " + action.name,
+			Usage:       "<id>",
+			Description: action.description,
+			MinArgs:     1,
+			MaxArgs:     1,
+			Run: func(args []string) error {
+				if err := v.selectServer(args[0]); err != nil {
+					return err
+				}
+				v.errorMsg = ""
+				run()
+				if v.errorMsg != "" {
+					return errors.New(v.statusMsg)
+				}
+				return nil
+			},
+			Complete: func(args []string) []string {
+				if len(args) > 0 {
+					return nil
+				}
+				if len(v.allServers) == 0 {
+					_ = v.loadServers()
+				}
+				ids := make([]string, 0, len(v.allServers))
+				for _, server := range v.allServers {
+					ids = append(ids, server.ID)
+				}
+				return ids
+			},
+		}); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// tagServer replaces the tags of a server, saving them to its repository
+func (v *ServerRegistryView) tagServer(id string, tags []string) error {
+	tags, err := mcpserver.NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	if err := v.selectServer(id); err != nil {
+		return err
+	}
+	server := v.servers[v.selectedIdx]
+	previous := server.Tags
+	server.Tags = tags
+	if err := v.registry.Update(server); err != nil {
+		server.Tags = previous
+		return err
+	}
 
+	v.applyFilter()
+	if len(tags) == 0 {
+		v.statusMsg = fmt.Sprintf("Cleared tags of '%s'", server.Name)
+	} else {
+		v.statusMsg = fmt.Sprintf("Tagged '%s': %s", server.Name, strings.Join(tags, ", "))
+	}
+	return nil
+}
+
+// selectServer selects a server by ID, clearing a filter that hides it
+func (v *ServerRegistryView) selectServer(id string) error {
+	if err := v.loadServers(); err != nil {
+		return err
+	}
+	find := func() int {
+		for i, server := range v.servers {
+			if server.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	idx := find()
+	if idx < 0 && v.filterQuery != "" {
+		v.filterQuery = ""
+		v.applyFilter()
+		idx = find()
+	}
+	if idx < 0 {
+		return fmt.Errorf("server not found: %s", id)
+	}
+	v.selectedIdx = idx
+	return nil
+}
+
+// startHealthMonitor starts watching server health changes and, with
+// auto-refresh on, checking the registry's servers in the background
+func (v *ServerRegistryView) startHealthMonitor() {
+	if v.healthMonitor == nil {
+		v.healthMonitor = mcpserver.NewHealthMonitor(v.registry, mcpserver.HealthMonitorConfig{
+			Jitter: mcpserver.DefaultHealthCheckJitter,
+		})
+	}
+	if v.unwatchHealth == nil {
+		v.unwatchHealth = events.Default().Subscribe(events.SubscriberFunc(notifyHealthChange(v.registry)), events.TopicServer)
+	}
+	if v.autoRefresh {
+		v.healthMonitor.Start()
+	}
+}
+
+// stopHealthMonitor stops background health checks, waiting for a round in
+// progress, and the health change subscription
+func (v *ServerRegistryView) stopHealthMonitor() {
+	if v.healthMonitor != nil {
+		v.healthMonitor.Stop()
+		v.healthMonitor = nil
+	}
+	if v.unwatchHealth != nil {
+		v.unwatchHealth()
+		v.unwatchHealth = nil
+	}
+}
+
+// notifyHealthChange returns a handler for health change events that keeps
+// a warning on the status bar while a server is unhealthy
+func notifyHealthChange(repo mcpserver.ServerRepository) func(events.Event) {
+	return func(event events.Event) {
+		change, ok := event.Payload.(events.ServerHealthChange)
+		if !ok {
+			return
+		}
+		key := "server:" + change.ServerID
+		if change.Current != string(mcpserver.HealthUnhealthy) {
+			Notifications().ClearWarning(key)
+			return
+		}
+		name := change.ServerID
+		if server, err := repo.Get(change.ServerID); err == nil {
+			name = server.Name
+		}
+		Notifications().Warn(key, fmt.Sprintf("Server %s unhealthy: %s", name, change.Error))
+	}
+}
+
+// refreshServerStatus refreshes health status for all servers (T198)
+func (v *ServerRegistryView) refreshServerStatus() {
+	v.statusMsg = "Refreshing server status..."
+
+	if v.healthMonitor == nil {
+		v.startHealthMonitor()
+	}
+	healthyCount, errorCount := v.healthMonitor.CheckNow()
 	v.lastRefresh = time.Now()
 
 	if errorCount > 0 {
@@ -683,12 +1476,16 @@ func (v *ServerRegistryView) showHelp() {
 Navigation:
   j/k       Move up/down
   g/G       Go to top/bottom
+  PgUp/PgDn Move one page
+  /         Filter by name, transport, health, or tag (tag:prod)
   Enter/i   Toggle server details
-  s         View tool schemas
-  Esc       Exit details/schema view
+  s         View tool schemas (t tries the selected tool)
+  Esc       Exit details/schema view, clear filter
 
 Server Management:
   a         Add new server
+  e         Edit selected server
+  I         Import servers from a Claude Desktop config
   d         Delete selected server
   t         Test server connection
   c         Connect to server
@@ -725,38 +1522,23 @@ func (v *ServerRegistryView) Render(screen *goterm.Screen) error {
 	v.width = width
 	v.height = height
 
-	// Auto-refresh health status (T198)
-	if v.autoRefresh && time.Since(v.lastRefresh) > 10*time.Second {
-		// Perform background health check for connected servers
-		for _, server := range v.servers {
-			if server.Connection.GetState() == mcpserver.StateConnected {
-				// Non-blocking health check
-				go func(s *mcpserver.MCPServer) {
-					ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-					defer cancel()
-
-					// Simple ping check
-					select {
-					case <-ctx.Done():
-						s.RecordUnhealthy("ping timeout")
-					default:
-						_ = s.HealthCheck()
-					}
-				}(server)
-			}
+	// Auto-refresh health status runs in the health monitor (T198)
+	if v.healthMonitor != nil {
+		if last := v.healthMonitor.LastCheck(); last.After(v.lastRefresh) {
+			v.lastRefresh = last
 		}
-		v.lastRefresh = time.Now()
 	}
 
 	// Clear screen
 	screen.Clear()
 
-	fg := goterm.ColorRGB(220, 220, 220)
-	bg := goterm.ColorDefault()
+	theme := CurrentTheme()
+	fg := theme.Text
+	bg := theme.Background
 
 	// Title bar
 	title := "Server Registry"
-	helpLine := "[j/k: Navigate] [i: Details] [s: Tools] [a: Add] [d: Delete] [t: Test] [r: Refresh] [?: Help]"
+	helpLine := "[j/k: Navigate] [/: Filter] [i: Details] [s: Tools] [a: Add] [d: Delete] [t: Test] [r: Refresh] [?: Help]"
 
 	// Draw title
 	for i, ch := range title {
@@ -772,7 +1554,7 @@ func (v *ServerRegistryView) Render(screen *goterm.Screen) error {
 		for i, ch := range helpLine {
 			x := helpX + i
 			if x < width {
-				screen.SetCell(x, 0, goterm.NewCell(ch, goterm.ColorRGB(150, 150, 150), bg, goterm.StyleNone))
+				screen.SetCell(x, 0, goterm.NewCell(ch, theme.Muted, bg, goterm.StyleNone))
 			}
 		}
 	}
@@ -780,7 +1562,9 @@ func (v *ServerRegistryView) Render(screen *goterm.Screen) error {
 	y := 2
 
 	// Render based on current mode
-	if v.showToolSchema && v.selectedIdx < len(v.servers) {
+	if v.playground != nil {
+		_ = v.renderToolPlayground(screen, y)
+	} else if v.showToolSchema && v.selectedIdx < len(v.servers) {
 		// T199: Tool schema viewer
 		_ = v.renderToolSchemaView(screen, y)
 	} else if v.showDetails && v.selectedIdx < len(v.servers) {
@@ -808,20 +1592,11 @@ func (v *ServerRegistryView) Render(screen *goterm.Screen) error {
 		statusLine += fmt.Sprintf(" (Last: %ds ago)", int(timeSince.Seconds()))
 	}
 
-	// Truncate if too long
-	if len(statusLine) > width {
-		statusLine = statusLine[:width]
-	}
-
-	for i, ch := range statusLine {
-		if i >= width {
-			break
-		}
-		screen.SetCell(i, statusY, goterm.NewCell(ch, goterm.ColorRGB(150, 150, 150), bg, goterm.StyleNone))
-	}
+	drawStatusBar(screen, statusY, width, statusLine, theme.Muted, goterm.StyleNone)
 
 	// Render modal if visible
 	if v.currentModal != nil && v.currentModal.IsVisible() {
+		v.currentModal.SetStyle(theme.ModalStyle())
 		v.currentModal.Render(screen)
 	}
 
@@ -830,24 +1605,49 @@ func (v *ServerRegistryView) Render(screen *goterm.Screen) error {
 
 // renderServerListView renders the main server list (T196)
 func (v *ServerRegistryView) renderServerListView(screen *goterm.Screen, startY int) int {
-	fg := goterm.ColorRGB(220, 220, 220)
-	bg := goterm.ColorDefault()
+	theme := CurrentTheme()
+	fg := theme.Text
+	bg := theme.Background
 
 	// Header
 	screen.DrawText(0, startY, "MCP Servers:", fg, bg, goterm.StyleBold)
 	y := startY + 1
 
+	// Filter line
+	if v.filterMode {
+		screen.DrawText(0, y, "Filter: "+v.filterQuery+"_", theme.InputFg, theme.InputBg, goterm.StyleNone)
+		y++
+	} else if v.filterQuery != "" {
+		screen.DrawText(0, y, fmt.Sprintf("Filter: %s (press / to edit, Esc to clear)", v.filterQuery), theme.FilterFg, bg, goterm.StyleNone)
+		y++
+	}
+
 	if len(v.servers) == 0 {
-		screen.DrawText(0, y+1, "  No servers registered", goterm.ColorRGB(150, 150, 150), bg, goterm.StyleDim)
-		screen.DrawText(0, y+2, "  Press 'a' to add a server", goterm.ColorRGB(150, 150, 150), bg, goterm.StyleDim)
+		v.listWindow.Reset()
+		if len(v.allServers) > 0 {
+			screen.DrawText(0, y+1, "  No servers match the filter", theme.Muted, bg, goterm.StyleDim)
+			screen.DrawText(0, y+2, "  Press Esc to clear it", theme.Muted, bg, goterm.StyleDim)
+			return y + 3
+		}
+		screen.DrawText(0, y+1, "  No servers registered", theme.Muted, bg, goterm.StyleDim)
+		screen.DrawText(0, y+2, "  Press 'a' to add a server", theme.Muted, bg, goterm.StyleDim)
 		return y + 3
 	}
 
+	// Only the rows that fit between the header and the status bar are drawn
+	v.listRows = v.height - 2 - y
+	if v.listRows < 1 {
+		v.listRows = 1
+	}
+	start, end := v.listWindow.Visible(v.selectedIdx, len(v.servers), v.listRows)
+
+	// Count indicator next to the header
+	indicator := components.RangeIndicator(start, end, len(v.servers), len(v.allServers))
+	screen.DrawText(len("MCP Servers: "), startY, indicator, theme.Muted, bg, goterm.StyleNone)
+
 	// Server list with health status indicators (T198)
-	for i, server := range v.servers {
-		if y >= v.height-2 {
-			break
-		}
+	for i := start; i < end; i++ {
+		server := v.servers[i]
 
 		prefix := "  "
 		style := goterm.StyleNone
@@ -856,8 +1656,8 @@ func (v *ServerRegistryView) renderServerListView(screen *goterm.Screen, startY
 
 		if i == v.selectedIdx {
 			prefix = "> "
-			itemFg = goterm.ColorRGB(0, 0, 0)
-			itemBg = goterm.ColorRGB(100, 200, 255)
+			itemFg = theme.SelectedFg
+			itemBg = theme.SelectedBg
 			style = goterm.StyleBold
 		}
 
@@ -872,6 +1672,13 @@ func (v *ServerRegistryView) renderServerListView(screen *goterm.Screen, startY
 		if len(server.Tools) > 0 {
 			line += fmt.Sprintf(" - %d tools", len(server.Tools))
 		}
+		// Flag servers whose calls fail
+		if stats := server.Stats(); stats.Errors > 0 {
+			line += fmt.Sprintf(" - %.0f%% errors", stats.ErrorRate()*100)
+		}
+		for _, tag := range server.Tags {
+			line += " #" + tag
+		}
 
 		// Truncate if too long
 		if len(line) > v.width {
@@ -900,8 +1707,9 @@ func (v *ServerRegistryView) renderServerListView(screen *goterm.Screen, startY
 func (v *ServerRegistryView) renderServerDetailsView(screen *goterm.Screen, startY int) int {
 	server := v.servers[v.selectedIdx]
 
-	fg := goterm.ColorRGB(220, 220, 220)
-	bg := goterm.ColorDefault()
+	theme := CurrentTheme()
+	fg := theme.Text
+	bg := theme.Background
 
 	screen.DrawText(0, startY, "Server Details:", fg, bg, goterm.StyleBold)
 	y := startY + 2
@@ -914,6 +1722,7 @@ func (v *ServerRegistryView) renderServerDetailsView(screen *goterm.Screen, star
 		{"ID:", server.ID},
 		{"Name:", server.Name},
 		{"Transport:", string(server.Transport.Type())},
+		{"Tags:", strings.Join(server.Tags, ", ")},
 		{"Status:", v.getConnectionStateLabel(server)},
 		{"Health:", v.getHealthStatusLabel(server)},
 	}
@@ -937,7 +1746,7 @@ func (v *ServerRegistryView) renderServerDetailsView(screen *goterm.Screen, star
 	switch cfg := server.Transport.(type) {
 	case *mcpserver.StdioTransportConfig:
 		if y < v.height-2 {
-			screen.DrawText(0, y, fmt.Sprintf("  Command:  %s", cfg.Command), fg, bg, goterm.StyleNone)
+			screen.DrawText(0, y, fmt.Sprintf("  Command:  %s", validation.DisplayPath(cfg.Command)), fg, bg, goterm.StyleNone)
 			y++
 		}
 		if len(cfg.Args) > 0 && y < v.height-2 {
@@ -988,7 +1797,7 @@ func (v *ServerRegistryView) renderServerDetailsView(screen *goterm.Screen, star
 	}
 
 	if lastError != "" && y < v.height-2 {
-		screen.DrawText(0, y, fmt.Sprintf("  Last Error:   %s", lastError), goterm.ColorRGB(255, 100, 100), bg, goterm.StyleNone)
+		screen.DrawText(0, y, fmt.Sprintf("  Last Error:   %s", lastError), theme.Error, bg, goterm.StyleNone)
 		y++
 	}
 
@@ -999,6 +1808,24 @@ func (v *ServerRegistryView) renderServerDetailsView(screen *goterm.Screen, star
 		y++
 	}
 
+	// Tool call statistics
+	y++
+	if y < v.height-2 {
+		screen.DrawText(0, y, "Call Statistics:", fg, bg, goterm.StyleBold)
+		y++
+	}
+	for _, line := range callStatsLines(server.Stats(), 5) {
+		if y >= v.height-2 {
+			break
+		}
+		color := fg
+		if line.failed {
+			color = theme.Error
+		}
+		screen.DrawText(0, y, line.text, color, bg, goterm.StyleNone)
+		y++
+	}
+
 	// Tools summary
 	if len(server.Tools) > 0 {
 		y++
@@ -1012,7 +1839,7 @@ func (v *ServerRegistryView) renderServerDetailsView(screen *goterm.Screen, star
 		for i, tool := range server.Tools {
 			if i >= toolsToShow || y >= v.height-2 {
 				if len(server.Tools) > toolsToShow && y < v.height-2 {
-					screen.DrawText(0, y, fmt.Sprintf("  ... and %d more (press 's' to view all)", len(server.Tools)-toolsToShow), goterm.ColorRGB(150, 150, 150), bg, goterm.StyleDim)
+					screen.DrawText(0, y, fmt.Sprintf("  ... and %d more (press 's' to view all)", len(server.Tools)-toolsToShow), theme.Muted, bg, goterm.StyleDim)
 				}
 				break
 			}
@@ -1024,19 +1851,61 @@ func (v *ServerRegistryView) renderServerDetailsView(screen *goterm.Screen, star
 	return y
 }
 
+// callStatsLine is one line of a server's call statistics
+type callStatsLine struct {
+	text   string
+	failed bool // A failed call, drawn in the error color
+}
+
+// callStatsLines formats a server's call counts, latency percentiles and up
+// to recent of its most recent calls
+func callStatsLines(stats mcpserver.CallStatsSnapshot, recent int) []callStatsLine {
+	if stats.Calls() == 0 {
+		return []callStatsLine{{text: "  No tool calls yet"}}
+	}
+	lines := []callStatsLine{
+		{text: fmt.Sprintf("  Calls:        %d (%d errors, %.1f%%)", stats.Calls(), stats.Errors, stats.ErrorRate()*100)},
+		{text: fmt.Sprintf("  Latency:      p50 %v  p90 %v  p99 %v", roundLatency(stats.P50), roundLatency(stats.P90), roundLatency(stats.P99))},
+		{text: "  Recent Calls:"},
+	}
+	for i, sample := range stats.Recent {
+		if i >= recent {
+			break
+		}
+		text := fmt.Sprintf("    %s  %-20s %8v", sample.Time.Format("15:04:05"), sample.Tool, roundLatency(sample.Duration))
+		if sample.Error != "" {
+			text += "  " + sample.Error
+		}
+		lines = append(lines, callStatsLine{text: text, failed: sample.Error != ""})
+	}
+	return lines
+}
+
+// roundLatency rounds a call latency for display
+func roundLatency(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond)
+	}
+	if d >= time.Millisecond {
+		return d.Round(100 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
+
 // renderToolSchemaView renders the tool schema viewer (T199)
 func (v *ServerRegistryView) renderToolSchemaView(screen *goterm.Screen, startY int) int {
 	server := v.servers[v.selectedIdx]
 
-	fg := goterm.ColorRGB(220, 220, 220)
-	bg := goterm.ColorDefault()
+	theme := CurrentTheme()
+	fg := theme.Text
+	bg := theme.Background
 
 	screen.DrawText(0, startY, fmt.Sprintf("Tool Schemas - %s:", server.Name), fg, bg, goterm.StyleBold)
 	y := startY + 2
 
 	if len(server.Tools) == 0 {
-		screen.DrawText(0, y, "  No tools discovered yet", goterm.ColorRGB(150, 150, 150), bg, goterm.StyleDim)
-		screen.DrawText(0, y+1, "  Press 't' to test connection and discover tools", goterm.ColorRGB(150, 150, 150), bg, goterm.StyleDim)
+		screen.DrawText(0, y, "  No tools discovered yet", theme.Muted, bg, goterm.StyleDim)
+		screen.DrawText(0, y+1, "  Press 't' to test connection and discover tools", theme.Muted, bg, goterm.StyleDim)
 		return y + 2
 	}
 
@@ -1049,7 +1918,7 @@ func (v *ServerRegistryView) renderToolSchemaView(screen *goterm.Screen, startY
 	for i, tool := range server.Tools {
 		if i >= maxToolsToShow || y >= v.height-2 {
 			if len(server.Tools) > maxToolsToShow && y < v.height-2 {
-				screen.DrawText(0, y, fmt.Sprintf("  ... and %d more tools", len(server.Tools)-maxToolsToShow), goterm.ColorRGB(150, 150, 150), bg, goterm.StyleDim)
+				screen.DrawText(0, y, fmt.Sprintf("  ... and %d more tools", len(server.Tools)-maxToolsToShow), theme.Muted, bg, goterm.StyleDim)
 				y++
 			}
 			break
@@ -1062,8 +1931,8 @@ func (v *ServerRegistryView) renderToolSchemaView(screen *goterm.Screen, startY
 
 		if i == v.selectedTool {
 			prefix = "> "
-			itemFg = goterm.ColorRGB(0, 0, 0)
-			itemBg = goterm.ColorRGB(100, 200, 255)
+			itemFg = theme.SelectedFg
+			itemBg = theme.SelectedBg
 			style = goterm.StyleBold
 		}
 
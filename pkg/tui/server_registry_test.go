@@ -1,11 +1,17 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/dshills/goflow/pkg/events"
 	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/dshills/goterm"
 )
 
@@ -64,6 +70,80 @@ func TestServerRegistryView_Init(t *testing.T) {
 	}
 }
 
+// TestServerRegistryView_PersistentRegistry tests that added servers are
+// saved to the config file and reloaded by a new view
+func TestServerRegistryView_PersistentRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+
+	view := NewServerRegistryView()
+	view.UsePersistentRegistry(path)
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	server, _ := mcpserver.NewMCPServer("saved", "echo", nil, mcpserver.TransportStdio)
+	if err := view.registry.Register(server); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	restarted := NewServerRegistryView()
+	restarted.UsePersistentRegistry(path)
+	if err := restarted.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if len(restarted.servers) != 1 || restarted.servers[0].ID != "saved" {
+		t.Errorf("expected the saved server after a restart, got %d servers", len(restarted.servers))
+	}
+
+	// An unreadable file leaves the in-memory registry and says why
+	if err := os.WriteFile(path, []byte("servers: [not a map"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	broken := NewServerRegistryView()
+	broken.UsePersistentRegistry(path)
+	if err := broken.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if !strings.Contains(broken.statusMsg, "will not be saved") {
+		t.Errorf("expected the load error in the status, got %q", broken.statusMsg)
+	}
+}
+
+// TestServerRegistryView_ImportServers tests importing servers from an
+// mcpServers config
+func TestServerRegistryView_ImportServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claude_desktop_config.json")
+	config := `{"mcpServers": {"files": {"command": "npx"}, "web": {"url": "https://example.com/mcp"}, "off": {"command": "x", "disabled": true}}}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewServerRegistryView()
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if err := view.importServers(path); err != nil {
+		t.Fatalf("importServers failed: %v", err)
+	}
+	if len(view.servers) != 2 {
+		t.Errorf("expected 2 imported servers, got %d", len(view.servers))
+	}
+	if !strings.Contains(view.statusMsg, "Imported 2 server(s)") || !strings.Contains(view.statusMsg, "1 skipped") {
+		t.Errorf("unexpected status %q", view.statusMsg)
+	}
+
+	// Importing again leaves the registered servers alone
+	if err := view.importServers(path); err != nil {
+		t.Fatalf("importServers failed: %v", err)
+	}
+	if !strings.Contains(view.statusMsg, "Imported 0 server(s)") || !strings.Contains(view.statusMsg, "2 already registered") {
+		t.Errorf("unexpected status %q", view.statusMsg)
+	}
+
+	if err := view.importServers(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing config")
+	}
+}
+
 // TestServerRegistryView_HandleKey_Navigation tests j/k navigation
 func TestServerRegistryView_HandleKey_Navigation(t *testing.T) {
 	view := setupTestView(t, 3)
@@ -258,6 +338,81 @@ func TestServerRegistryView_ConnectServer(t *testing.T) {
 	}
 }
 
+// TestServerRegistryView_EditServer tests the edit dialog: it starts from
+// the server's config, and a changed config replaces and reconnects it
+func TestServerRegistryView_EditServer(t *testing.T) {
+	view := NewServerRegistryView()
+	registry := mcpserver.NewRegistry()
+	view.SetRegistry(registry)
+
+	server, _ := mcpserver.NewMCPServer("files", "echo", []string{"one"}, mcpserver.TransportStdio)
+	server.Transport.(*mcpserver.StdioTransportConfig).Env = map[string]string{"TOKEN": "x"}
+	registry.Register(server)
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	view.connectServer()
+
+	enter := KeyEvent{IsSpecial: true, Special: "Enter"}
+	view.HandleKey(KeyEvent{Key: 'e'})
+	if view.currentModal == nil || view.currentModal.GetInput() != "files" {
+		t.Fatal("expected the name step pre-populated with the server name")
+	}
+	view.currentModal.SetInput("Files")
+	view.HandleKey(enter)
+	if got := view.currentModal.GetInput(); got != "stdio" {
+		t.Errorf("expected the current transport, got %q", got)
+	}
+	view.HandleKey(enter)
+	if got := view.currentModal.GetInput(); got != "echo, one" {
+		t.Errorf("expected the current command and args, got %q", got)
+	}
+	view.currentModal.SetInput("echo, two")
+	view.HandleKey(enter)
+
+	updated, err := registry.Get("files")
+	if err != nil {
+		t.Fatalf("server missing after edit: %v", err)
+	}
+	if updated == server {
+		t.Fatal("expected a changed config to replace the server")
+	}
+	if updated.Name != "Files" || len(updated.Args) != 1 || updated.Args[0] != "two" {
+		t.Errorf("edit not applied: name %q args %v", updated.Name, updated.Args)
+	}
+	if updated.Transport.(*mcpserver.StdioTransportConfig).Env["TOKEN"] != "x" {
+		t.Error("expected the environment to be kept")
+	}
+	if updated.Connection.GetState() != mcpserver.StateConnected {
+		t.Error("expected the connected server to be reconnected")
+	}
+	if !strings.Contains(view.statusMsg, "reconnected") {
+		t.Errorf("unexpected status %q", view.statusMsg)
+	}
+
+	// A rename keeps the server and its connection
+	view.HandleKey(KeyEvent{Key: 'e'})
+	view.currentModal.SetInput("Renamed")
+	view.HandleKey(enter)
+	view.HandleKey(enter)
+	view.HandleKey(enter)
+	renamed, _ := registry.Get("files")
+	if renamed != updated || renamed.Name != "Renamed" {
+		t.Error("expected a rename to update the server in place")
+	}
+
+	// Invalid configs are rejected
+	view.HandleKey(KeyEvent{Key: 'e'})
+	view.HandleKey(enter)
+	view.currentModal.SetInput("http")
+	view.HandleKey(enter)
+	view.currentModal.SetInput("not a url")
+	view.HandleKey(enter)
+	if current, _ := registry.Get("files"); current != renamed {
+		t.Error("expected an invalid edit to leave the server unchanged")
+	}
+}
+
 // TestServerRegistryView_DisconnectServer tests server disconnection
 func TestServerRegistryView_DisconnectServer(t *testing.T) {
 	view := setupTestView(t, 1)
@@ -369,6 +524,97 @@ func TestServerRegistryView_Render(t *testing.T) {
 	}
 }
 
+// TestServerRegistryView_Filter tests '/' filtering by name, transport, and health
+func TestServerRegistryView_Filter(t *testing.T) {
+	view := setupTestView(t, 12)
+
+	sseServer, _ := mcpserver.NewMCPServer("remote", "http://localhost:3000", nil, mcpserver.TransportSSE)
+	if err := view.registry.Register(sseServer); err != nil {
+		t.Fatalf("failed to register server: %v", err)
+	}
+	if err := view.RefreshServers(); err != nil {
+		t.Fatalf("RefreshServers failed: %v", err)
+	}
+
+	typeFilter := func(query string) {
+		for _, ch := range query {
+			view.HandleKey(KeyEvent{Key: ch})
+		}
+	}
+
+	// Enter filter mode; typed keys go to the query, not to actions like 'd'
+	view.HandleKey(KeyEvent{Key: '/'})
+	if !view.filterMode {
+		t.Fatal("expected filter mode after '/'")
+	}
+	typeFilter("sse")
+	if len(view.servers) != 1 || view.servers[0].ID != "remote" {
+		t.Fatalf("expected only the SSE server, got %d servers", len(view.servers))
+	}
+
+	// Enter keeps the filter and returns to navigation
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "Enter"})
+	if view.filterMode || view.filterQuery != "sse" {
+		t.Errorf("expected filter 'sse' applied, got mode=%v query=%q", view.filterMode, view.filterQuery)
+	}
+
+	// Terms are ANDed and match names and health
+	view.HandleKey(KeyEvent{Key: '/'})
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"})
+	if len(view.servers) != 13 {
+		t.Errorf("expected 13 servers after clearing filter, got %d", len(view.servers))
+	}
+	view.HandleKey(KeyEvent{Key: '/'})
+	typeFilter("server 1 unknown")
+	// "Test Server 1", "Test Server 10" .. "Test Server 12"
+	if len(view.servers) != 4 {
+		t.Errorf("expected 4 servers matching 'server 1 unknown', got %d", len(view.servers))
+	}
+
+	// Backspace edits the query
+	for i := 0; i < len(" unknown"); i++ {
+		view.HandleKey(KeyEvent{IsSpecial: true, Special: "Backspace"})
+	}
+	if view.filterQuery != "server 1" {
+		t.Errorf("expected query 'server 1', got %q", view.filterQuery)
+	}
+
+	// Escape in normal mode clears an applied filter
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "Enter"})
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"})
+	if view.filterQuery != "" || len(view.servers) != 13 {
+		t.Errorf("expected filter cleared, got query=%q servers=%d", view.filterQuery, len(view.servers))
+	}
+}
+
+// TestServerRegistryView_Paging tests that PageDown/PageUp move by a page
+func TestServerRegistryView_Paging(t *testing.T) {
+	view := setupTestView(t, 60)
+	view.listRows = 20
+
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "PageDown"})
+	if view.selectedIdx != 20 {
+		t.Errorf("expected selection 20 after PageDown, got %d", view.selectedIdx)
+	}
+
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "PageDown"})
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "PageDown"})
+	if view.selectedIdx != 59 {
+		t.Errorf("expected selection clamped to 59, got %d", view.selectedIdx)
+	}
+
+	view.HandleKey(KeyEvent{IsSpecial: true, Special: "PageUp"})
+	if view.selectedIdx != 39 {
+		t.Errorf("expected selection 39 after PageUp, got %d", view.selectedIdx)
+	}
+
+	// The window follows the selection
+	start, end := view.listWindow.Visible(view.selectedIdx, len(view.servers), view.listRows)
+	if view.selectedIdx < start || view.selectedIdx >= end || end-start != 20 {
+		t.Errorf("selection %d outside window [%d, %d)", view.selectedIdx, start, end)
+	}
+}
+
 // TestServerRegistryView_GetHealthStatusIcon tests health status icons (T198)
 func TestServerRegistryView_GetHealthStatusIcon(t *testing.T) {
 	view := NewServerRegistryView()
@@ -538,32 +784,120 @@ func TestServerRegistryView_RefreshServers(t *testing.T) {
 	}
 }
 
-// TestServerRegistryView_AutoRefreshBehavior tests auto-refresh mechanism (T198)
+// TestServerRegistryView_AutoRefreshBehavior tests that the health monitor
+// runs while the view is shown with auto-refresh on (T198)
 func TestServerRegistryView_AutoRefreshBehavior(t *testing.T) {
 	view := setupTestView(t, 1)
-	server := view.servers[0]
+	defer view.Cleanup()
 
-	// Connect server
-	server.Connect()
-	server.CompleteConnection()
+	if view.healthMonitor == nil || !view.healthMonitor.Running() {
+		t.Fatal("expected Init to start the health monitor")
+	}
 
-	// Set last refresh to past time
-	view.lastRefresh = time.Now().Add(-15 * time.Second)
+	// R toggles auto-refresh off and on again
+	view.HandleKey(KeyEvent{Key: 'R'})
+	if view.healthMonitor.Running() {
+		t.Error("expected auto-refresh off to stop the health monitor")
+	}
+	view.HandleKey(KeyEvent{Key: 'R'})
+	if !view.healthMonitor.Running() {
+		t.Error("expected auto-refresh on to start the health monitor")
+	}
 
-	// Create mock screen for render
-	screen, err := goterm.Init()
+	if err := view.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if view.healthMonitor != nil || view.unwatchHealth != nil {
+		t.Error("expected Cleanup to stop the health monitor and its subscription")
+	}
+}
+
+// TestNotifyHealthChange tests the status bar warning kept while a server
+// is unhealthy
+func TestNotifyHealthChange(t *testing.T) {
+	view := setupTestView(t, 1)
+	defer view.Cleanup()
+	server := view.servers[0]
+	handle := notifyHealthChange(view.registry)
+
+	handle(events.Event{Payload: events.ServerHealthChange{
+		ServerID: server.ID,
+		Current:  string(mcpserver.HealthUnhealthy),
+		Error:    "ping failed",
+	}})
+	history := Notifications().History()
+	if len(history) == 0 || history[len(history)-1].Text != "Server Test Server 1 unhealthy: ping failed" {
+		t.Errorf("expected an unhealthy warning, got %+v", history)
+	}
+
+	handle(events.Event{Payload: events.ServerHealthChange{
+		ServerID: server.ID,
+		Previous: string(mcpserver.HealthUnhealthy),
+		Current:  string(mcpserver.HealthHealthy),
+	}})
+	n := Notifications()
+	n.mu.Lock()
+	_, warned := n.warnings["server:"+server.ID]
+	n.mu.Unlock()
+	if warned {
+		t.Error("expected the warning to clear")
+	}
+}
+
+func TestReviewToolChanges(t *testing.T) {
+	view := setupTestView(t, 1)
+	defer view.Cleanup()
+	view.toolSchemas = filepath.Join(t.TempDir(), "tool_schemas.json")
+	server := view.servers[0]
+
+	wf, err := workflow.NewWorkflow("reader", "")
 	if err != nil {
-		t.Skip("Cannot initialize terminal for auto-refresh test")
+		t.Fatal(err)
+	}
+	if err := wf.AddNode(&workflow.MCPToolNode{ID: "read", ServerID: server.ID, ToolName: "read_file", OutputVariable: "content"}); err != nil {
+		t.Fatal(err)
+	}
+	view.SetOpenWorkflow(func() *workflow.Workflow { return wf })
+	warned := func() (string, bool) {
+		n := Notifications()
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		warning, ok := n.warnings["tools:"+server.ID]
+		return warning.Text, ok
 	}
-	defer screen.Close()
 
-	// Render should trigger auto-refresh
-	oldRefreshTime := view.lastRefresh
-	view.Render(screen)
+	server.Tools = []mcpserver.Tool{{Name: "read_file"}, {Name: "stat"}}
+	view.reviewToolChanges(server)
+	if _, ok := warned(); ok {
+		t.Fatal("expected no warning on the first discovery")
+	}
 
-	// Verify refresh time was updated
-	if !view.lastRefresh.After(oldRefreshTime) {
-		t.Error("expected auto-refresh to update lastRefresh time")
+	// Removing a tool the workflow does not call only reports the change
+	server.Tools = []mcpserver.Tool{{Name: "read_file"}}
+	view.reviewToolChanges(server)
+	if _, ok := warned(); ok {
+		t.Fatal("expected no warning for a tool the workflow does not call")
+	}
+	history := Notifications().History()
+	if len(history) == 0 || history[len(history)-1].Text != "Test Server 1: tools changed: stat removed" {
+		t.Errorf("expected the change to be reported, got %+v", history)
+	}
+
+	server.Tools = []mcpserver.Tool{{Name: "read_file", InputSchema: &mcpserver.ToolSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{"path": map[string]interface{}{"type": "string"}},
+		Required:   []string{"path"},
+	}}}
+	view.reviewToolChanges(server)
+	warning, ok := warned()
+	if !ok || warning != "Workflow reader calls changed tools of test1: read (read_file)" {
+		t.Errorf("expected a warning about the read node, got %q", warning)
+	}
+
+	// Rediscovering without changes clears the warning
+	view.reviewToolChanges(server)
+	if _, ok := warned(); ok {
+		t.Error("expected the warning to clear")
 	}
 }
 
@@ -587,6 +921,42 @@ func TestServerRegistryView_EmptyServerList(t *testing.T) {
 	}
 }
 
+// TestValidateStdioCommandInput tests command normalization in the add server dialog
+func TestValidateStdioCommandInput(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	serverPath := filepath.Join(home, "server")
+	if err := os.WriteFile(serverPath, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := validateStdioCommandInput("~/server, --root, /tmp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := serverPath + ", --root, /tmp"; got != want {
+		t.Errorf("validateStdioCommandInput() = %q, want %q", got, want)
+	}
+
+	got, err = validateStdioCommandInput("npx,-y,server")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "npx,-y,server" {
+		t.Errorf("bare command should be unchanged, got %q", got)
+	}
+
+	_, err = validateStdioCommandInput("~/missing")
+	if err == nil {
+		t.Fatal("expected error for missing command path")
+	}
+	if strings.HasPrefix(err.Error(), "path validation failed") {
+		t.Errorf("inline error should contain only the reason, got %q", err.Error())
+	}
+}
+
 // Helper function to set up a test view with servers
 func setupTestView(t *testing.T, serverCount int) *ServerRegistryView {
 	t.Helper()
@@ -620,3 +990,29 @@ func setupTestView(t *testing.T, serverCount int) *ServerRegistryView {
 
 	return view
 }
+
+// TestCallStatsLines tests the call statistics shown in server details
+func TestCallStatsLines(t *testing.T) {
+	lines := callStatsLines(mcpserver.CallStatsSnapshot{}, 5)
+	if len(lines) != 1 || !strings.Contains(lines[0].text, "No tool calls") {
+		t.Errorf("expected a no-calls line, got %+v", lines)
+	}
+
+	stats := mcpserver.NewCallStats(0)
+	started := time.Now().Add(-12 * time.Millisecond)
+	stats.Record("read_file", started, nil)
+	stats.Record("write_file", started, errors.New("disk full"))
+	lines = callStatsLines(stats.Snapshot(), 1)
+	if len(lines) != 4 {
+		t.Fatalf("expected counts, latency, heading and one call, got %+v", lines)
+	}
+	if !strings.Contains(lines[0].text, "2 (1 errors, 50.0%)") {
+		t.Errorf("unexpected counts line %q", lines[0].text)
+	}
+	if !strings.Contains(lines[1].text, "p50") || !strings.Contains(lines[1].text, "p99") {
+		t.Errorf("unexpected latency line %q", lines[1].text)
+	}
+	if last := lines[3]; !last.failed || !strings.Contains(last.text, "write_file") || !strings.Contains(last.text, "disk full") {
+		t.Errorf("expected the newest, failed call, got %+v", last)
+	}
+}
@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sessionVersion is the format version of saved sessions
+const sessionVersion = 1
+
+// maxRecentWorkflows is how many recently edited workflows a session keeps
+const maxRecentWorkflows = 20
+
+// FinderRecent is a recently edited workflow; choosing it opens it
+const FinderRecent FinderKind = "recent"
+
+// Session is the TUI state saved when it closes and restored when it next
+// starts: what was on screen, and the workflows edited recently
+type Session struct {
+	Version    int              `json:"version"`
+	View       string           `json:"view,omitempty"`     // View with the focus
+	Workflow   string           `json:"workflow,omitempty"` // Absolute path of the workflow in the builder
+	Selected   string           `json:"selected,omitempty"` // Node selected on the canvas
+	ViewportX  int              `json:"viewport_x,omitempty"`
+	ViewportY  int              `json:"viewport_y,omitempty"`
+	Panels     []string         `json:"panels,omitempty"`      // Builder panels open: "properties", "help", "minimap"
+	Split      string           `json:"split,omitempty"`       // "vertical" or "horizontal" if the screen was split
+	SplitView  string           `json:"split_view,omitempty"`  // View in the other pane
+	SplitRatio int              `json:"split_ratio,omitempty"` // Percentage of the screen given to the first pane
+	Recent     []RecentWorkflow `json:"recent,omitempty"`      // Most recently opened first
+}
+
+// RecentWorkflow is a workflow file opened in the builder
+type RecentWorkflow struct {
+	Path   string    `json:"path"` // Absolute path
+	Opened time.Time `json:"opened"`
+}
+
+// NewSession returns an empty session
+func NewSession() *Session {
+	return &Session{Version: sessionVersion}
+}
+
+// DefaultSessionPath returns session.json in GOFLOW_CONFIG_DIR, or in
+// ~/.goflow when it is not set
+func DefaultSessionPath() string {
+	if dir := os.Getenv("GOFLOW_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "session.json")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "session.json")
+	}
+	return filepath.Join(homeDir, ".goflow", "session.json")
+}
+
+// LoadSession reads the session saved at path. A missing file, or one
+// saved in another format version, gives an empty session.
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewSession(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	if session.Version != sessionVersion {
+		return NewSession(), nil
+	}
+	return &session, nil
+}
+
+// Save writes the session to path
+func (s *Session) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize session: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	// Write then rename, so a crash never leaves a truncated session
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp) // Best effort cleanup
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// AddRecent records that the workflow file at path was opened, moving it
+// to the front of the recent workflows
+func (s *Session) AddRecent(path string, opened time.Time) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	s.Recent = slices.DeleteFunc(s.Recent, func(r RecentWorkflow) bool { return r.Path == path })
+	s.Recent = slices.Insert(s.Recent, 0, RecentWorkflow{Path: path, Opened: opened})
+	if len(s.Recent) > maxRecentWorkflows {
+		s.Recent = s.Recent[:maxRecentWorkflows]
+	}
+}
+
+// recentFinderItems lists the recent workflows that still exist for the
+// fuzzy finder, most recent first. Workflows in workflowsDir are labeled by
+// their path there.
+func (s *Session) recentFinderItems(workflowsDir string) []FinderItem {
+	items := make([]FinderItem, 0, len(s.Recent))
+	for _, recent := range s.Recent {
+		if _, err := os.Stat(recent.Path); err != nil {
+			continue
+		}
+		label := recent.Path
+		if rel, err := filepath.Rel(workflowsDir, recent.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			label = rel
+		}
+		items = append(items, FinderItem{
+			Kind:   FinderRecent,
+			Label:  label,
+			Detail: "opened " + recent.Opened.Format("2006-01-02 15:04"),
+			Value:  recent.Path,
+		})
+	}
+	return items
+}
+
+// splitNames names split directions in saved sessions
+var splitNames = map[SplitDirection]string{
+	SplitVertical:   "vertical",
+	SplitHorizontal: "horizontal",
+}
+
+// captureSession records the workflow being edited, its selection,
+// viewport and open panels in s
+func (v *WorkflowBuilderView) captureSession(s *Session) {
+	s.Workflow, s.Selected, s.ViewportX, s.ViewportY, s.Panels = "", "", 0, 0, nil
+	if v.builder == nil || v.workflowPath == "" {
+		return
+	}
+	s.Workflow = v.workflowPath
+	if abs, err := filepath.Abs(v.workflowPath); err == nil {
+		s.Workflow = abs
+	}
+	s.Selected = v.builder.GetSelectedNodeID()
+	s.ViewportX, s.ViewportY = v.builder.canvas.ViewportX, v.builder.canvas.ViewportY
+	if v.builder.mode == "edit" && v.builder.propertyPanel.IsVisible() {
+		s.Panels = append(s.Panels, "properties")
+	}
+	if v.builder.helpPanel.visible {
+		s.Panels = append(s.Panels, "help")
+	}
+	if v.builder.canvas.MinimapEnabled() {
+		s.Panels = append(s.Panels, "minimap")
+	}
+}
+
+// restoreSession puts back the selection, viewport and panels recorded in
+// s, if the builder shows the workflow they were recorded with. Nodes that
+// no longer exist are not selected.
+func (v *WorkflowBuilderView) restoreSession(s *Session) {
+	if v.builder == nil || s.Workflow == "" {
+		return
+	}
+	if abs, err := filepath.Abs(v.workflowPath); err != nil || abs != s.Workflow {
+		return
+	}
+	b := v.builder
+	b.canvas.ViewportX, b.canvas.ViewportY = s.ViewportX, s.ViewportY
+	b.canvas.ShowMinimap(slices.Contains(s.Panels, "minimap"))
+	selected := s.Selected != "" && b.SelectNode(s.Selected) == nil
+	switch {
+	case selected && slices.Contains(s.Panels, "properties"):
+		_ = b.EditNodeProperties(b.GetSelectedNodeID()) // The node was just found
+	case slices.Contains(s.Panels, "help"):
+		b.helpPanel.visible = true
+		b.mode = "help"
+		b.updateKeyStates()
+	}
+	v.statusMsg = "Restored the last session"
+}
+
+// builderView returns the workflow builder view
+func (a *App) builderView() (*WorkflowBuilderView, error) {
+	view, err := a.viewManager.GetView("builder")
+	if err != nil {
+		return nil, err
+	}
+	builderView, ok := view.(*WorkflowBuilderView)
+	if !ok {
+		return nil, fmt.Errorf("builder view unavailable")
+	}
+	return builderView, nil
+}
+
+// recordRecent adds a workflow file the builder loaded to the recent
+// workflows
+func (a *App) recordRecent(path string) {
+	a.session.AddRecent(path, time.Now())
+}
+
+// RestoreSession shows the views and workflow that were open when the TUI
+// last closed. A workflow that no longer exists is not reopened. Problems
+// are shown on the command line; the TUI then starts as it would have.
+func (a *App) RestoreSession() {
+	s := a.session
+	builderView, err := a.builderView()
+	if err != nil {
+		a.commandLine.ShowError(err)
+		return
+	}
+
+	view := s.View
+	if view == "run" || view == "monitor" {
+		view = "builder" // Runs are not restored
+	}
+	if s.Workflow != "" {
+		if _, err := os.Stat(s.Workflow); err == nil {
+			builderView.SetWorkflow(s.Workflow)
+		} else if view == "builder" {
+			view = ""
+		}
+	}
+	if _, err := a.viewManager.GetView(view); err != nil {
+		return // Nothing to restore, or a view this version doesn't have
+	}
+	if err := a.viewManager.SwitchTo(view); err != nil {
+		a.commandLine.ShowError(fmt.Errorf("failed to restore the last session: %w", err))
+		return
+	}
+
+	for direction, name := range splitNames {
+		if s.Split != name || s.SplitView == view {
+			continue
+		}
+		if _, err := a.viewManager.GetView(s.SplitView); err != nil {
+			break
+		}
+		if err := a.splitScreen(direction, s.SplitView); err != nil {
+			a.commandLine.ShowError(fmt.Errorf("failed to restore the split: %w", err))
+			break
+		}
+		if s.SplitRatio != 0 {
+			a.layout.ratio = max(minSplitRatio, min(s.SplitRatio, maxSplitRatio))
+		}
+	}
+
+	if view == "builder" || (a.layout.IsSplit() && a.layout.Other() == "builder") {
+		builderView.restoreSession(s)
+	}
+}
+
+// saveSession records what is on screen in the session and writes it to
+// the session file
+func (a *App) saveSession() error {
+	s := a.session
+	s.View = ""
+	if current := a.viewManager.GetCurrentView(); current != nil {
+		s.View = current.Name()
+	}
+	if builderView, err := a.builderView(); err == nil {
+		builderView.captureSession(s)
+	}
+	s.Split, s.SplitView, s.SplitRatio = "", "", 0
+	if a.layout.IsSplit() {
+		s.Split = splitNames[a.layout.Direction()]
+		s.SplitView = a.layout.Other()
+		s.SplitRatio = a.layout.ratio
+	}
+	return s.Save(a.sessionPath)
+}
+
+// registerRecentCommand registers :recent, which lists recently edited
+// workflows in the fuzzy finder
+func (a *App) registerRecentCommand() error {
+	return a.commands.Register(Command{
+		Name:        "recent",
+		Description: "Open a recently edited workflow",
+		Run: func(args []string) error {
+			items := a.session.recentFinderItems(defaultWorkflowsDir())
+			if len(items) == 0 {
+				return fmt.Errorf("no recent workflows")
+			}
+			a.finder.Open(items)
+			return nil
+		},
+	})
+}
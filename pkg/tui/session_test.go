@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSession_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.json")
+
+	session, err := LoadSession(path)
+	if err != nil || session.Version != sessionVersion || len(session.Recent) != 0 {
+		t.Fatalf("LoadSession() of a missing file = %+v, %v", session, err)
+	}
+
+	opened := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < maxRecentWorkflows+2; i++ {
+		session.AddRecent(filepath.Join(dir, fmt.Sprintf("w%d.yaml", i)), opened)
+	}
+	session.AddRecent(filepath.Join(dir, "w5.yaml"), opened.Add(time.Hour))
+	if len(session.Recent) != maxRecentWorkflows {
+		t.Fatalf("kept %d recent workflows, want %d", len(session.Recent), maxRecentWorkflows)
+	}
+	if session.Recent[0].Path != filepath.Join(dir, "w5.yaml") || session.Recent[1].Path != filepath.Join(dir, fmt.Sprintf("w%d.yaml", maxRecentWorkflows+1)) {
+		t.Errorf("recent = %v, want the reopened workflow first", session.Recent[:2])
+	}
+
+	session.View, session.Workflow, session.Panels = "builder", filepath.Join(dir, "w5.yaml"), []string{"minimap"}
+	if err := session.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, session) {
+		t.Errorf("loaded %+v, want %+v", loaded, session)
+	}
+
+	// Sessions of another format version are dropped
+	if err := os.WriteFile(path, []byte(`{"version": 99, "view": "builder"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := LoadSession(path); err != nil || loaded.View != "" {
+		t.Errorf("LoadSession() of another version = %+v, %v", loaded, err)
+	}
+}
+
+// newSessionTestApp creates an app with the explorer, builder and monitor
+// views and the session saved at path, showing the explorer
+func newSessionTestApp(t *testing.T, path string) *App {
+	t.Helper()
+	session, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := NewViewManager()
+	commands := NewCommandRegistry()
+	app := &App{
+		viewManager: vm,
+		commands:    commands,
+		commandLine: NewCommandLine(commands),
+		layout:      NewLayout(),
+		session:     session,
+		sessionPath: path,
+	}
+	app.finder = NewFuzzyFinder(app.selectFinderItem)
+	builderView := NewWorkflowBuilderView()
+	builderView.SetOnLoad(app.recordRecent)
+	for _, view := range []View{NewWorkflowExplorerView(), builderView, NewExecutionMonitorView()} {
+		if err := vm.RegisterView(view); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vm.AddSwitchHook(app.followSwitch)
+	if err := app.registerRecentCommand(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Initialize("explorer"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = vm.Shutdown() })
+	return app
+}
+
+func TestApp_RestoreSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	sessionPath := filepath.Join(dir, "session.json")
+	workflowPath := filepath.Join(dir, "greet.yaml")
+	writeBrowserWorkflow(t, dir, "greet.yaml", newRunTestWorkflow(t), time.Now())
+
+	// Edit a workflow with the builder split beside the monitor
+	app := newSessionTestApp(t, sessionPath)
+	builderView, err := app.builderView()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := builderView.OpenFile(workflowPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := builderView.builder.SelectNode("end"); err != nil {
+		t.Fatal(err)
+	}
+	builderView.builder.canvas.ShowMinimap(false)
+	builderView.builder.canvas.ViewportX = 7
+	if err := app.splitScreen(SplitVertical, "monitor"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.saveSession(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next start shows the same
+	app = newSessionTestApp(t, sessionPath)
+	app.RestoreSession()
+	if message, isError := app.commandLine.Message(); isError {
+		t.Fatalf("restore failed: %s", message)
+	}
+	if current := app.viewManager.GetCurrentView().Name(); current != "builder" {
+		t.Fatalf("restored view %q, want the builder", current)
+	}
+	if app.layout.Direction() != SplitVertical || app.layout.Other() != "monitor" {
+		t.Errorf("restored split %v with %q, want the monitor beside", app.layout.Direction(), app.layout.Other())
+	}
+	builderView, _ = app.builderView()
+	b := builderView.builder
+	if b.GetSelectedNodeID() != "end" || b.canvas.ViewportX != 7 || b.canvas.MinimapEnabled() {
+		t.Errorf("restored selection %q, viewport x %d, minimap %v", b.GetSelectedNodeID(), b.canvas.ViewportX, b.canvas.MinimapEnabled())
+	}
+
+	// :recent lists the workflow, and choosing it opens it
+	if err := app.commands.Execute("recent"); err != nil {
+		t.Fatal(err)
+	}
+	match, ok := app.finder.Selected()
+	if !ok || match.Item.Kind != FinderRecent || match.Item.Value != workflowPath {
+		t.Fatalf("recent items = %+v", app.finder.Matches())
+	}
+	if err := app.selectFinderItem(match.Item); err != nil {
+		t.Fatal(err)
+	}
+
+	// A workflow deleted since is neither restored nor listed
+	if err := os.Remove(workflowPath); err != nil {
+		t.Fatal(err)
+	}
+	app = newSessionTestApp(t, sessionPath)
+	app.RestoreSession()
+	if current := app.viewManager.GetCurrentView().Name(); current != "explorer" {
+		t.Errorf("restored view %q without the workflow, want the explorer", current)
+	}
+	if err := app.commands.Execute("recent"); err == nil {
+		t.Error("expected no recent workflows")
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dshills/goterm"
+)
+
+// drawStatusBar draws a view's status bar on row y: the view's own text on
+// the left, in fg, and the shared notifications on the right. The view's
+// text is cut short rather than the notifications.
+func drawStatusBar(screen *goterm.Screen, y, width int, text string, fg goterm.Color, style goterm.Style) {
+	if width <= 0 {
+		return
+	}
+	theme := CurrentTheme()
+	bg := theme.Background
+
+	notice, level := Notifications().Status()
+	notice = fitToWidth(notice, width)
+	noticeWidth := len([]rune(notice))
+	if noticeWidth > 0 {
+		noticeWidth++ // Keep a space from the view's text
+	}
+
+	left := fitToWidth(text, width-noticeWidth)
+	line := left + strings.Repeat(" ", width-len([]rune(left)))
+	screen.DrawText(0, y, line, fg, bg, style)
+	if notice != "" {
+		screen.DrawText(width-len([]rune(notice)), y, notice, notificationColor(theme, level), bg, style)
+	}
+}
+
+// notificationColor returns the color notifications of a level are drawn in
+func notificationColor(theme *Theme, level NotificationLevel) goterm.Color {
+	switch level {
+	case NotifySuccess:
+		return theme.Success
+	case NotifyWarning:
+		return theme.Warning
+	case NotifyError:
+		return theme.Error
+	}
+	return theme.Accent
+}
+
+// MessageLog is an overlay listing the notification history, newest at
+// the bottom
+type MessageLog struct {
+	visible bool
+	scroll  int // Lines scrolled up from the newest
+}
+
+// NewMessageLog creates a hidden message log
+func NewMessageLog() *MessageLog {
+	return &MessageLog{}
+}
+
+// Open shows the log scrolled to the newest message
+func (m *MessageLog) Open() {
+	m.visible = true
+	m.scroll = 0
+}
+
+// Close hides the log
+func (m *MessageLog) Close() {
+	m.visible = false
+}
+
+// IsVisible returns whether the log is open
+func (m *MessageLog) IsVisible() bool {
+	return m.visible
+}
+
+// HandleKey scrolls the log with j/k or the arrows; c clears the history
+// and Escape, q or Enter closes the log
+func (m *MessageLog) HandleKey(event KeyEvent) {
+	switch {
+	case event.IsSpecial && (event.Special == "Escape" || event.Special == "Enter"), event.Key == 'q':
+		m.Close()
+	case event.IsSpecial && event.Special == "Up", event.Key == 'k':
+		m.scroll = min(m.scroll+1, max(len(Notifications().History())-1, 0))
+	case event.IsSpecial && event.Special == "Down", event.Key == 'j':
+		m.scroll = max(m.scroll-1, 0)
+	case event.Key == 'c':
+		Notifications().ClearHistory()
+		m.scroll = 0
+	}
+}
+
+// Render draws the log as a box over the lower half of the screen
+func (m *MessageLog) Render(screen *goterm.Screen) {
+	if !m.visible {
+		return
+	}
+	screenWidth, screenHeight := screen.Size()
+	width := min(max(screenWidth*3/4, 40), screenWidth-2)
+	height := min(max(screenHeight/2, 6), screenHeight-2)
+	if width < 10 || height < 4 {
+		return
+	}
+	x := (screenWidth - width) / 2
+	y := screenHeight - height - 1
+
+	theme := CurrentTheme()
+	fg, bg := theme.Foreground, theme.Background
+	for row := 0; row < height; row++ {
+		line := "│" + strings.Repeat(" ", width-2) + "│"
+		switch row {
+		case 0:
+			line = "┌" + strings.Repeat("─", width-2) + "┐"
+		case height - 1:
+			line = "└" + strings.Repeat("─", width-2) + "┘"
+		}
+		screen.DrawText(x, y+row, line, fg, bg, goterm.StyleNone)
+	}
+
+	history := Notifications().History()
+	title := fmt.Sprintf(" Messages (%d) · j/k scroll · c clear · Esc close ", len(history))
+	screen.DrawText(x+2, y, fitToWidth(title, width-4), fg, bg, goterm.StyleBold)
+	if len(history) == 0 {
+		screen.DrawText(x+2, y+1, "No messages", theme.Muted, bg, goterm.StyleDim)
+		return
+	}
+
+	rows := height - 2
+	end := len(history) - min(m.scroll, len(history)-1)
+	start := max(end-rows, 0)
+	for i, msg := range history[start:end] {
+		stamp := msg.Time.Format("15:04:05")
+		level := fmt.Sprintf("%-7s", msg.Level)
+		line := fitToWidth(fmt.Sprintf("%s %s %s", stamp, level, msg.Text), width-4)
+		screen.DrawText(x+2, y+1+i, line, fg, bg, goterm.StyleNone)
+		screen.DrawText(x+2+len(stamp)+1, y+1+i, fitToWidth(level, width-4-len(stamp)-1), notificationColor(theme, msg.Level), bg, goterm.StyleNone)
+	}
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// templateUpgradePrompt is a planned template upgrade waiting for the user
+// to confirm it
+type templateUpgradePrompt struct {
+	path    string                    // Workflow file to overwrite
+	upgrade *workflow.TemplateUpgrade // Planned upgrade
+	lines   []string                  // Description of what will change
+	scroll  int                       // First visible line
+}
+
+// defaultTemplatesDir returns GOFLOW_TEMPLATES_DIR or ~/.goflow/templates
+func defaultTemplatesDir() string {
+	if dir := os.Getenv("GOFLOW_TEMPLATES_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "templates"
+	}
+	return filepath.Join(homeDir, ".goflow", "templates")
+}
+
+// planTemplateUpgrade loads the workflow at path and plans its upgrade to
+// the version of its template found in templatesDir
+func planTemplateUpgrade(path, templatesDir string) (*templateUpgradePrompt, error) {
+	wf, err := workflow.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if wf.Metadata.Template == nil {
+		return nil, fmt.Errorf("%s was not created from a template", filepath.Base(path))
+	}
+
+	resolve := workflow.DirectoryTemplateResolver(templatesDir)
+	template, err := resolve(wf.Metadata.Template.Name)
+	if err != nil {
+		return nil, err
+	}
+	template, err = workflow.ComposeTemplate(template, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade, err := workflow.UpgradeWorkflow(context.Background(), wf, template)
+	if err != nil {
+		return nil, err
+	}
+	return &templateUpgradePrompt{
+		path:    path,
+		upgrade: upgrade,
+		lines:   templateUpgradeLines(wf.Name, upgrade),
+	}, nil
+}
+
+// apply writes the upgraded workflow over the original file
+func (p *templateUpgradePrompt) apply() error {
+	data, err := workflow.ToYAML(p.upgrade.Workflow)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, data, 0644)
+}
+
+// templateUpgradeLines describes an upgrade for the confirmation screen
+func templateUpgradeLines(name string, upgrade *workflow.TemplateUpgrade) []string {
+	lines := []string{
+		fmt.Sprintf("Upgrade %s from template %s to %s", name, upgrade.From, upgrade.To),
+		"",
+	}
+
+	for _, migration := range upgrade.Migrations {
+		line := fmt.Sprintf("Migration %s -> %s", migration.From, migration.To)
+		if migration.Description != "" {
+			line += ": " + migration.Description
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, renameLines("parameter", upgrade.RenamedParameters)...)
+	lines = append(lines, renameLines("node", upgrade.RenamedNodes)...)
+	for _, name := range upgrade.RemovedParameters {
+		lines = append(lines, fmt.Sprintf("  - parameter %s (no longer used)", name))
+	}
+
+	lines = append(lines, workflowDiffLines(upgrade.Diff)...)
+	return append(lines, "", "Apply upgrade? [y] yes  [n] cancel  [j/k] scroll")
+}
+
+// workflowDiffLines describes a workflow diff: changed workflow fields,
+// then added (+), removed (-) and changed (~) nodes and edges
+func workflowDiffLines(diff *workflow.WorkflowDiff) []string {
+	if diff == nil || diff.Empty() {
+		return []string{"", "No changes to nodes or edges"}
+	}
+
+	var lines []string
+	for _, field := range diff.Fields {
+		lines = append(lines, fmt.Sprintf("  ~ %s: %v -> %v", field.Path, field.Old, field.New))
+	}
+	sections := []struct {
+		title   string
+		entries []workflow.DiffEntry
+	}{
+		{"Nodes", diff.Nodes},
+		{"Edges", diff.Edges},
+	}
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		lines = append(lines, "", section.title+":")
+		for _, entry := range section.entries {
+			marker := "~"
+			switch entry.Change {
+			case workflow.ChangeAdded:
+				marker = "+"
+			case workflow.ChangeRemoved:
+				marker = "-"
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s", marker, entry.ID))
+			for _, field := range entry.Fields {
+				lines = append(lines, fmt.Sprintf("      %s: %v -> %v", field.Path, field.Old, field.New))
+			}
+		}
+	}
+	return lines
+}
+
+// renameLines lists renames in a stable order
+func renameLines(kind string, renames map[string]string) []string {
+	olds := make([]string, 0, len(renames))
+	for old := range renames {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+
+	lines := make([]string, 0, len(olds))
+	for _, old := range olds {
+		lines = append(lines, fmt.Sprintf("  ~ %s %s renamed to %s", kind, old, renames[old]))
+	}
+	return lines
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+const upgradeTemplateV2 = `
+name: notifier
+version: "2.0.0"
+parameters:
+  - name: channel
+    type: string
+    required: true
+migrations:
+  - from: "1.0.0"
+    to: "2.0.0"
+    description: "Rename target to channel"
+    rename_parameters:
+      target: channel
+    rename_nodes:
+      notify: send
+workflow_spec:
+  nodes:
+    - id: start
+      type: start
+    - id: send
+      type: mcp_tool
+      config:
+        server: slack
+        tool: post
+        output: result
+        parameters:
+          channel: "{{channel}}"
+    - id: end
+      type: end
+  edges:
+    - from: start
+      to: send
+    - from: send
+      to: end
+`
+
+// TestWorkflowExplorerView_TemplateUpgrade tests previewing and applying a
+// template upgrade from the explorer
+func TestWorkflowExplorerView_TemplateUpgrade(t *testing.T) {
+	workflowsDir := t.TempDir()
+	templatesDir := t.TempDir()
+
+	v1 := &workflow.WorkflowTemplate{
+		Name:       "notifier",
+		Version:    "1.0.0",
+		Parameters: []workflow.TemplateParameter{{Name: "target", Type: workflow.ParameterTypeString, Required: true}},
+		WorkflowSpec: workflow.WorkflowSpec{
+			Nodes: []workflow.NodeSpec{
+				{ID: "start", Type: "start"},
+				{ID: "notify", Type: "mcp_tool", Config: map[string]interface{}{
+					"server": "slack", "tool": "post", "output": "result",
+					"parameters": map[string]interface{}{"channel": "{{target}}"},
+				}},
+				{ID: "end", Type: "end"},
+			},
+			Edges: []workflow.EdgeSpec{{From: "start", To: "notify"}, {From: "notify", To: "end"}},
+		},
+	}
+	wf, err := workflow.InstantiateTemplate(context.Background(), v1, map[string]interface{}{"target": "#ops"})
+	if err != nil {
+		t.Fatalf("InstantiateTemplate failed: %v", err)
+	}
+	data, err := workflow.ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML failed: %v", err)
+	}
+	workflowPath := filepath.Join(workflowsDir, "notifier.yaml")
+	if err := os.WriteFile(workflowPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, "notifier.yaml"), []byte(upgradeTemplateV2), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	view := NewWorkflowExplorerView()
+	view.workflowsDir = workflowsDir
+	view.templatesDir = templatesDir
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	// Cancelling leaves the file untouched
+	_ = view.HandleKey(KeyEvent{Key: 'u'})
+	if view.upgrade == nil {
+		t.Fatalf("Expected upgrade preview, status: %s", view.statusMsg)
+	}
+	preview := strings.Join(view.upgrade.lines, "\n")
+	for _, want := range []string{
+		"from template 1.0.0 to 2.0.0",
+		"Rename target to channel",
+		"parameter target renamed to channel",
+		"node notify renamed to send",
+		"- notify",
+		"+ send",
+	} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, preview)
+		}
+	}
+	_ = view.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"})
+	if view.upgrade != nil {
+		t.Fatal("Expected Escape to cancel the upgrade")
+	}
+
+	_ = view.HandleKey(KeyEvent{Key: 'u'})
+	_ = view.HandleKey(KeyEvent{Key: 'y'})
+	if !strings.Contains(view.statusMsg, "2.0.0") {
+		t.Errorf("Unexpected status after upgrade: %s", view.statusMsg)
+	}
+
+	upgraded, err := workflow.ParseFile(workflowPath)
+	if err != nil {
+		t.Fatalf("Failed to reload upgraded workflow: %v", err)
+	}
+	if upgraded.Metadata.Template.Version != "2.0.0" || upgraded.Metadata.Template.Parameters["channel"] != "#ops" {
+		t.Errorf("Unexpected template source after upgrade: %+v", upgraded.Metadata.Template)
+	}
+
+	// Already at the latest version
+	_ = view.HandleKey(KeyEvent{Key: 'u'})
+	if view.upgrade != nil || !strings.Contains(view.statusMsg, "not newer") {
+		t.Errorf("Expected no upgrade to be offered, status: %s", view.statusMsg)
+	}
+}
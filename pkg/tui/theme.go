@@ -0,0 +1,479 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dshills/goflow/pkg/tui/components"
+	"github.com/dshills/goterm"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultThemeName is the theme used unless the config file names another
+const DefaultThemeName = "dark"
+
+// Theme is a color scheme for the TUI. Each color has a role, so the
+// canvas, panels, overlays and status bars are drawn consistently and a
+// theme changes them all at once.
+type Theme struct {
+	Name string
+
+	// Plain text and the bars at the top and bottom of views, usually the
+	// terminal's own colors
+	Foreground goterm.Color
+	Background goterm.Color
+
+	// Text in lists and details, and quieter text around it
+	Text  goterm.Color
+	Muted goterm.Color
+	Faint goterm.Color
+
+	Accent  goterm.Color // Matches and other things to notice
+	Error   goterm.Color
+	Warning goterm.Color
+	Success goterm.Color
+
+	SelectedFg goterm.Color // The selected row of a list
+	SelectedBg goterm.Color
+	InputFg    goterm.Color // Filters being typed
+	InputBg    goterm.Color
+	FilterFg   goterm.Color // Filters applied
+
+	// Side panels, palettes and the modal dialogs
+	PanelText       goterm.Color
+	PanelBg         goterm.Color
+	PanelSelectedBg goterm.Color
+	PanelBorder     goterm.Color
+	ModalTitleFg    goterm.Color
+	ModalTitleBg    goterm.Color
+	ModalBorder     goterm.Color
+
+	// The builder canvas
+	CanvasBg       goterm.Color
+	NodeText       goterm.Color
+	NodeSelectedBg goterm.Color
+	NodeError      goterm.Color
+	NodeWarning    goterm.Color
+	SearchMatchBg  goterm.Color
+	Edge           goterm.Color
+	EdgeSelected   goterm.Color
+	EdgeLabel      goterm.Color
+	EdgeError      goterm.Color
+	GroupFrame     goterm.Color
+	NoteFg         goterm.Color
+	NoteBg         goterm.Color
+	MinimapBg      goterm.Color
+	MinimapView    goterm.Color
+	MinimapNode    goterm.Color
+
+	// Node borders by node type
+	NodeTypes map[string]goterm.Color
+}
+
+// builtinThemes are the themes that need no theme file
+var builtinThemes = map[string]func() *Theme{
+	"dark":          darkTheme,
+	"light":         lightTheme,
+	"high-contrast": highContrastTheme,
+}
+
+// darkTheme is the default theme, for dark terminals
+func darkTheme() *Theme {
+	return &Theme{
+		Name:       "dark",
+		Foreground: goterm.ColorDefault(),
+		Background: goterm.ColorDefault(),
+
+		Text:  goterm.ColorRGB(220, 220, 220),
+		Muted: goterm.ColorRGB(150, 150, 150),
+		Faint: goterm.ColorRGB(100, 100, 100),
+
+		Accent:  goterm.ColorRGB(255, 200, 0),
+		Error:   goterm.ColorRGB(255, 100, 100),
+		Warning: goterm.ColorRGB(255, 200, 100),
+		Success: goterm.ColorRGB(100, 255, 100),
+
+		SelectedFg: goterm.ColorRGB(0, 0, 0),
+		SelectedBg: goterm.ColorRGB(100, 200, 255),
+		InputFg:    goterm.ColorRGB(255, 255, 0),
+		InputBg:    goterm.ColorRGB(40, 40, 40),
+		FilterFg:   goterm.ColorRGB(200, 200, 100),
+
+		PanelText:       goterm.ColorRGB(255, 255, 255),
+		PanelBg:         goterm.ColorRGB(30, 30, 30),
+		PanelSelectedBg: goterm.ColorRGB(58, 58, 58),
+		PanelBorder:     goterm.ColorRGB(136, 136, 136),
+		ModalTitleFg:    goterm.ColorRGB(255, 255, 255),
+		ModalTitleBg:    goterm.ColorRGB(40, 80, 120),
+		ModalBorder:     goterm.ColorRGB(150, 150, 200),
+
+		CanvasBg:       goterm.ColorRGB(0, 0, 0),
+		NodeText:       goterm.ColorRGB(220, 220, 220),
+		NodeSelectedBg: goterm.ColorRGB(0, 100, 200),
+		NodeError:      goterm.ColorRGB(255, 0, 0),
+		NodeWarning:    goterm.ColorRGB(255, 170, 0),
+		SearchMatchBg:  goterm.ColorRGB(90, 75, 0),
+		Edge:           goterm.ColorRGB(170, 170, 170),
+		EdgeSelected:   goterm.ColorRGB(0, 255, 255),
+		EdgeLabel:      goterm.ColorRGB(255, 200, 0),
+		EdgeError:      goterm.ColorRGB(255, 90, 90),
+		GroupFrame:     goterm.ColorRGB(120, 120, 200),
+		NoteFg:         goterm.ColorRGB(40, 40, 40),
+		NoteBg:         goterm.ColorRGB(240, 220, 120),
+		MinimapBg:      goterm.ColorRGB(30, 30, 30),
+		MinimapView:    goterm.ColorRGB(60, 60, 90),
+		MinimapNode:    goterm.ColorRGB(200, 200, 200),
+
+		NodeTypes: map[string]goterm.Color{
+			"start":      goterm.ColorRGB(0, 255, 0),
+			"end":        goterm.ColorRGB(255, 0, 0),
+			"mcp_tool":   goterm.ColorRGB(0, 170, 255),
+			"batch_tool": goterm.ColorRGB(0, 130, 230),
+			"transform":  goterm.ColorRGB(255, 170, 0),
+			"condition":  goterm.ColorRGB(255, 255, 0),
+			"switch":     goterm.ColorRGB(255, 255, 0),
+			"loop":       goterm.ColorRGB(255, 0, 255),
+			"parallel":   goterm.ColorRGB(0, 255, 255),
+			"try":        goterm.ColorRGB(255, 140, 60),
+			"catch":      goterm.ColorRGB(255, 90, 90),
+			"delay":      goterm.ColorRGB(140, 200, 200),
+			"approval":   goterm.ColorRGB(150, 230, 110),
+			"group":      goterm.ColorRGB(150, 150, 255),
+		},
+	}
+}
+
+// lightTheme is for light terminals: dark text on pale backgrounds
+func lightTheme() *Theme {
+	t := darkTheme()
+	t.Name = "light"
+
+	t.Text = goterm.ColorRGB(40, 40, 40)
+	t.Muted = goterm.ColorRGB(110, 110, 110)
+	t.Faint = goterm.ColorRGB(160, 160, 160)
+
+	t.Accent = goterm.ColorRGB(180, 100, 0)
+	t.Error = goterm.ColorRGB(200, 0, 0)
+	t.Warning = goterm.ColorRGB(180, 110, 0)
+	t.Success = goterm.ColorRGB(0, 140, 0)
+
+	t.SelectedFg = goterm.ColorRGB(255, 255, 255)
+	t.SelectedBg = goterm.ColorRGB(30, 100, 200)
+	t.InputFg = goterm.ColorRGB(0, 0, 0)
+	t.InputBg = goterm.ColorRGB(255, 240, 170)
+	t.FilterFg = goterm.ColorRGB(130, 110, 0)
+
+	t.PanelText = goterm.ColorRGB(20, 20, 20)
+	t.PanelBg = goterm.ColorRGB(235, 235, 235)
+	t.PanelSelectedBg = goterm.ColorRGB(200, 215, 240)
+	t.PanelBorder = goterm.ColorRGB(140, 140, 140)
+	t.ModalTitleFg = goterm.ColorRGB(255, 255, 255)
+	t.ModalTitleBg = goterm.ColorRGB(50, 100, 170)
+	t.ModalBorder = goterm.ColorRGB(90, 90, 160)
+
+	t.CanvasBg = goterm.ColorRGB(250, 250, 250)
+	t.NodeText = goterm.ColorRGB(30, 30, 30)
+	t.NodeSelectedBg = goterm.ColorRGB(190, 215, 255)
+	t.NodeError = goterm.ColorRGB(200, 0, 0)
+	t.NodeWarning = goterm.ColorRGB(200, 110, 0)
+	t.SearchMatchBg = goterm.ColorRGB(255, 235, 150)
+	t.Edge = goterm.ColorRGB(120, 120, 120)
+	t.EdgeSelected = goterm.ColorRGB(0, 120, 200)
+	t.EdgeLabel = goterm.ColorRGB(170, 90, 0)
+	t.EdgeError = goterm.ColorRGB(200, 0, 0)
+	t.GroupFrame = goterm.ColorRGB(110, 110, 190)
+	t.NoteFg = goterm.ColorRGB(40, 40, 40)
+	t.NoteBg = goterm.ColorRGB(255, 240, 150)
+	t.MinimapBg = goterm.ColorRGB(230, 230, 230)
+	t.MinimapView = goterm.ColorRGB(200, 210, 240)
+	t.MinimapNode = goterm.ColorRGB(80, 80, 80)
+
+	t.NodeTypes = map[string]goterm.Color{
+		"start":      goterm.ColorRGB(0, 140, 0),
+		"end":        goterm.ColorRGB(190, 0, 0),
+		"mcp_tool":   goterm.ColorRGB(0, 100, 190),
+		"batch_tool": goterm.ColorRGB(0, 80, 160),
+		"transform":  goterm.ColorRGB(190, 100, 0),
+		"condition":  goterm.ColorRGB(150, 130, 0),
+		"switch":     goterm.ColorRGB(150, 130, 0),
+		"loop":       goterm.ColorRGB(160, 0, 160),
+		"parallel":   goterm.ColorRGB(0, 140, 140),
+		"try":        goterm.ColorRGB(190, 80, 0),
+		"catch":      goterm.ColorRGB(180, 30, 30),
+		"delay":      goterm.ColorRGB(60, 120, 120),
+		"approval":   goterm.ColorRGB(50, 130, 20),
+		"group":      goterm.ColorRGB(90, 90, 200),
+	}
+	return t
+}
+
+// highContrastTheme uses pure black, white and saturated colors only
+func highContrastTheme() *Theme {
+	black := goterm.ColorRGB(0, 0, 0)
+	white := goterm.ColorRGB(255, 255, 255)
+	yellow := goterm.ColorRGB(255, 255, 0)
+
+	t := darkTheme()
+	t.Name = "high-contrast"
+
+	t.Text, t.Muted, t.Faint = white, white, goterm.ColorRGB(200, 200, 200)
+	t.Accent = yellow
+	t.Error = goterm.ColorRGB(255, 60, 60)
+	t.Warning = yellow
+	t.Success = goterm.ColorRGB(0, 255, 0)
+
+	t.SelectedFg, t.SelectedBg = black, yellow
+	t.InputFg, t.InputBg = black, white
+	t.FilterFg = yellow
+
+	t.PanelText, t.PanelBg = white, black
+	t.PanelSelectedBg = goterm.ColorRGB(0, 0, 160)
+	t.PanelBorder = white
+	t.ModalTitleFg, t.ModalTitleBg = black, white
+	t.ModalBorder = white
+
+	t.CanvasBg = black
+	t.NodeText = white
+	t.NodeSelectedBg = goterm.ColorRGB(0, 0, 200)
+	t.NodeError = goterm.ColorRGB(255, 60, 60)
+	t.NodeWarning = yellow
+	t.SearchMatchBg = goterm.ColorRGB(130, 0, 130)
+	t.Edge = white
+	t.EdgeSelected = yellow
+	t.EdgeLabel = yellow
+	t.EdgeError = goterm.ColorRGB(255, 60, 60)
+	t.GroupFrame = white
+	t.NoteFg, t.NoteBg = black, yellow
+	t.MinimapBg = black
+	t.MinimapView = goterm.ColorRGB(0, 0, 160)
+	t.MinimapNode = white
+
+	t.NodeTypes = map[string]goterm.Color{
+		"start":      goterm.ColorRGB(0, 255, 0),
+		"end":        goterm.ColorRGB(255, 60, 60),
+		"mcp_tool":   goterm.ColorRGB(0, 200, 255),
+		"batch_tool": goterm.ColorRGB(0, 200, 255),
+		"transform":  goterm.ColorRGB(255, 170, 0),
+		"condition":  yellow,
+		"switch":     yellow,
+		"loop":       goterm.ColorRGB(255, 0, 255),
+		"parallel":   goterm.ColorRGB(0, 255, 255),
+		"try":        goterm.ColorRGB(255, 140, 0),
+		"catch":      goterm.ColorRGB(255, 60, 60),
+		"delay":      white,
+		"approval":   goterm.ColorRGB(0, 255, 0),
+		"group":      white,
+	}
+	return t
+}
+
+// activeTheme is the theme views are drawn with
+var activeTheme atomic.Pointer[Theme]
+
+// CurrentTheme returns the theme the TUI is drawn with
+func CurrentTheme() *Theme {
+	if t := activeTheme.Load(); t != nil {
+		return t
+	}
+	return defaultTheme
+}
+
+// defaultTheme is drawn with until another theme is set
+var defaultTheme = darkTheme()
+
+// SetTheme changes the theme the TUI is drawn with; views use it from
+// their next frame
+func SetTheme(t *Theme) {
+	activeTheme.Store(t)
+}
+
+// NodeColor returns the color of nodes of a type, or the node text color
+// for types the theme does not color
+func (t *Theme) NodeColor(nodeType string) goterm.Color {
+	if c, ok := t.NodeTypes[nodeType]; ok {
+		return c
+	}
+	return t.NodeText
+}
+
+// ModalStyle returns the style modal dialogs are drawn with in the theme
+func (t *Theme) ModalStyle() components.ModalStyle {
+	return components.ModalStyle{
+		TitleFg:    t.ModalTitleFg,
+		TitleBg:    t.ModalTitleBg,
+		BorderFg:   t.ModalBorder,
+		BorderBg:   t.Background,
+		MessageFg:  t.Text,
+		MessageBg:  t.Background,
+		BackdropFg: goterm.ColorRGB(0, 0, 0),
+		BackdropBg: goterm.ColorRGB(0, 0, 0),
+		InputFg:    t.PanelText,
+		InputBg:    t.PanelBg,
+		ErrorFg:    t.Error,
+	}
+}
+
+// themeFile is a user theme as written in a theme file: the theme it
+// starts from and the colors it changes, by role name
+type themeFile struct {
+	Base   string            `yaml:"base"`
+	Colors map[string]string `yaml:"colors"`
+}
+
+// DefaultThemesDir returns where user theme files are kept:
+// $GOFLOW_THEMES_DIR, or ~/.goflow/themes
+func DefaultThemesDir() string {
+	if dir := os.Getenv("GOFLOW_THEMES_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "themes")
+	}
+	return filepath.Join(homeDir, ".goflow", "themes")
+}
+
+// LoadTheme returns a built-in theme, or the theme in <name>.yaml in dir
+func LoadTheme(name, dir string) (*Theme, error) {
+	if builtin, ok := builtinThemes[name]; ok {
+		return builtin(), nil
+	}
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid theme name: %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".yaml"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("theme not found: %s (built-in themes: %s)", name, strings.Join(builtinThemeNames(), ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme %s: %w", name, err)
+	}
+	return parseTheme(name, data)
+}
+
+// parseTheme reads a theme file. Colors are "#rrggbb" or "default" for the
+// terminal's own color; node type colors are named "node.<type>".
+func parseTheme(name string, data []byte) (*Theme, error) {
+	var file themeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", name, err)
+	}
+	base := file.Base
+	if base == "" {
+		base = DefaultThemeName
+	}
+	builtin, ok := builtinThemes[base]
+	if !ok {
+		return nil, fmt.Errorf("invalid theme %s: unknown base theme %q", name, base)
+	}
+
+	t := builtin()
+	t.Name = name
+	roles := t.colorRoles()
+	for role, value := range file.Colors {
+		c, err := parseColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid theme %s: %s: %w", name, role, err)
+		}
+		if nodeType, ok := strings.CutPrefix(role, "node."); ok {
+			t.NodeTypes[nodeType] = c
+			continue
+		}
+		field, ok := roles[role]
+		if !ok {
+			return nil, fmt.Errorf("invalid theme %s: unknown color %q", name, role)
+		}
+		*field = c
+	}
+	return t, nil
+}
+
+// colorRoles maps the names used in theme files to the theme's colors
+func (t *Theme) colorRoles() map[string]*goterm.Color {
+	return map[string]*goterm.Color{
+		"foreground":        &t.Foreground,
+		"background":        &t.Background,
+		"text":              &t.Text,
+		"muted":             &t.Muted,
+		"faint":             &t.Faint,
+		"accent":            &t.Accent,
+		"error":             &t.Error,
+		"warning":           &t.Warning,
+		"success":           &t.Success,
+		"selected_fg":       &t.SelectedFg,
+		"selected_bg":       &t.SelectedBg,
+		"input_fg":          &t.InputFg,
+		"input_bg":          &t.InputBg,
+		"filter_fg":         &t.FilterFg,
+		"panel_text":        &t.PanelText,
+		"panel_bg":          &t.PanelBg,
+		"panel_selected_bg": &t.PanelSelectedBg,
+		"panel_border":      &t.PanelBorder,
+		"modal_title_fg":    &t.ModalTitleFg,
+		"modal_title_bg":    &t.ModalTitleBg,
+		"modal_border":      &t.ModalBorder,
+		"canvas_bg":         &t.CanvasBg,
+		"node_text":         &t.NodeText,
+		"node_selected_bg":  &t.NodeSelectedBg,
+		"node_error":        &t.NodeError,
+		"node_warning":      &t.NodeWarning,
+		"search_match_bg":   &t.SearchMatchBg,
+		"edge":              &t.Edge,
+		"edge_selected":     &t.EdgeSelected,
+		"edge_label":        &t.EdgeLabel,
+		"edge_error":        &t.EdgeError,
+		"group_frame":       &t.GroupFrame,
+		"note_fg":           &t.NoteFg,
+		"note_bg":           &t.NoteBg,
+		"minimap_bg":        &t.MinimapBg,
+		"minimap_view":      &t.MinimapView,
+		"minimap_node":      &t.MinimapNode,
+	}
+}
+
+// parseColor reads "#rrggbb", or "default" for the terminal's own color
+func parseColor(value string) (goterm.Color, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "default") {
+		return goterm.ColorDefault(), nil
+	}
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok || len(hex) != 6 {
+		return goterm.Color{}, fmt.Errorf("color %q is not #rrggbb or default", value)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return goterm.Color{}, fmt.Errorf("color %q is not #rrggbb or default", value)
+	}
+	return goterm.ColorRGB(uint8(rgb>>16), uint8(rgb>>8), uint8(rgb)), nil
+}
+
+// ThemeNames lists the built-in themes and the theme files in dir
+func ThemeNames(dir string) []string {
+	names := builtinThemeNames()
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if ok && !entry.IsDir() {
+			if _, builtin := builtinThemes[name]; !builtin {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// builtinThemeNames lists the built-in themes, sorted
+func builtinThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goterm"
+)
+
+func TestLoadTheme_Builtin(t *testing.T) {
+	for _, name := range []string{"dark", "light", "high-contrast"} {
+		theme, err := LoadTheme(name, t.TempDir())
+		if err != nil {
+			t.Fatalf("LoadTheme(%q) failed: %v", name, err)
+		}
+		if theme.Name != name {
+			t.Errorf("theme name = %q, want %q", theme.Name, name)
+		}
+		if _, ok := theme.NodeTypes["mcp_tool"]; !ok {
+			t.Errorf("%s theme has no mcp_tool color", name)
+		}
+	}
+
+	if _, err := LoadTheme("solarized", t.TempDir()); err == nil || !strings.Contains(err.Error(), "theme not found") {
+		t.Errorf("LoadTheme error = %v", err)
+	}
+	if _, err := LoadTheme("../config", t.TempDir()); err == nil {
+		t.Error("expected error for a theme name outside the themes directory")
+	}
+}
+
+func TestLoadTheme_File(t *testing.T) {
+	dir := t.TempDir()
+	data := `base: light
+colors:
+  accent: "#ff8800"
+  background: default
+  node.mcp_tool: "#00CCFF"
+`
+	if err := os.WriteFile(filepath.Join(dir, "mine.yaml"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	theme, err := LoadTheme("mine", dir)
+	if err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	if theme.Accent != goterm.ColorRGB(255, 136, 0) {
+		t.Errorf("accent = %+v", theme.Accent)
+	}
+	if theme.Background != goterm.ColorDefault() {
+		t.Errorf("background = %+v, want the terminal default", theme.Background)
+	}
+	if theme.NodeColor("mcp_tool") != goterm.ColorRGB(0, 204, 255) {
+		t.Errorf("mcp_tool color = %+v", theme.NodeColor("mcp_tool"))
+	}
+	// Colors the file leaves alone come from its base
+	if theme.CanvasBg != lightTheme().CanvasBg {
+		t.Errorf("canvas background = %+v, want light's", theme.CanvasBg)
+	}
+
+	names := ThemeNames(dir)
+	if strings.Join(names, " ") != "dark high-contrast light mine" {
+		t.Errorf("ThemeNames() = %v", names)
+	}
+}
+
+func TestParseTheme_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown base", "base: neon\n", "unknown base theme"},
+		{"unknown color", "colors:\n  sparkle: \"#ffffff\"\n", "unknown color"},
+		{"bad color", "colors:\n  accent: orange\n", "not #rrggbb"},
+		{"bad hex", "colors:\n  accent: \"#ggggggg\"\n", "not #rrggbb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTheme("broken", []byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseTheme error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTheme_Canvas(t *testing.T) {
+	t.Cleanup(func() { SetTheme(darkTheme()) })
+	builder := newGroupTestBuilder(t)
+	if err := builder.canvas.SelectNode("start"); err != nil {
+		t.Fatal(err)
+	}
+
+	SetTheme(lightTheme())
+	_, bg, _ := builder.canvas.getNodeColors(builder.canvas.nodes["end"])
+	if bg != lightTheme().CanvasBg {
+		t.Errorf("node background = %+v, want the light theme's", bg)
+	}
+	_, bg, _ = builder.canvas.getNodeColors(builder.canvas.nodes["start"])
+	if bg != lightTheme().NodeSelectedBg {
+		t.Errorf("selected node background = %+v, want the light theme's", bg)
+	}
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+)
+
+// maxArgumentLength bounds argument values, which may hold JSON
+const maxArgumentLength = 4096
+
+// schemaSource returns the input schema of a server's tool, or nil if it is
+// not known
+type schemaSource func(serverID, toolName string) *mcpserver.ToolSchema
+
+// argumentFields creates a field for each tool argument. With a schema
+// there is one field per property, required ones first, typed and checked
+// against the schema; arguments the schema does not list are kept after
+// them. Without a schema every argument is free text.
+func argumentFields(schema *mcpserver.ToolSchema, values map[string]string) []propertyField {
+	var fields []propertyField
+	listed := make(map[string]bool)
+	if schema != nil {
+		required := make(map[string]bool, len(schema.Required))
+		for _, name := range schema.Required {
+			required[name] = true
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if required[names[i]] != required[names[j]] {
+				return required[names[i]]
+			}
+			return names[i] < names[j]
+		})
+
+		for _, name := range names {
+			listed[name] = true
+			property, _ := schema.Properties[name].(map[string]interface{})
+			fields = append(fields, newArgumentField(name, values[name], required[name], property))
+		}
+	}
+
+	var extra []string
+	for name := range values {
+		if !listed[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		field := newArgumentField(name, values[name], false, nil)
+		if schema != nil && schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+			field.validationFn = func(string) error {
+				return fmt.Errorf("the tool takes no argument %q", name)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// newArgumentField creates the field for one tool argument, described by
+// its JSON Schema property (nil if unknown)
+func newArgumentField(name, value string, required bool, property map[string]interface{}) propertyField {
+	argType, _ := property["type"].(string)
+	description, _ := property["description"].(string)
+
+	var enum []string
+	if values, ok := property["enum"].([]interface{}); ok {
+		for _, v := range values {
+			enum = append(enum, fmt.Sprint(v))
+		}
+	} else if argType == "boolean" {
+		enum = []string{"true", "false"}
+	}
+
+	var hints []string
+	if argType != "" {
+		hints = append(hints, argType)
+	}
+	if len(enum) > 0 {
+		hints = append(hints, "one of "+strings.Join(enum, ", "))
+	}
+	if description != "" {
+		hints = append(hints, description)
+	}
+	helpText := "Argument value; ${var} inserts a variable"
+	if len(hints) > 0 {
+		helpText = strings.Join(hints, "; ")
+	}
+
+	field := propertyField{
+		label:     name,
+		value:     value,
+		required:  required,
+		fieldType: "argument",
+		helpText:  helpText,
+		param:     name,
+		enum:      enum,
+	}
+	field.validationFn = func(value string) error {
+		return validateArgument(value, argType, enum)
+	}
+	return field
+}
+
+// validateArgument checks a tool argument against its schema type and
+// allowed values. Values with ${} placeholders are resolved when the
+// workflow runs, so only their template syntax is checked.
+func validateArgument(value, argType string, enum []string) error {
+	if value == "" {
+		return nil // Empty is valid (required check done separately)
+	}
+	if strings.Contains(value, "${") {
+		return validateTemplateField(value)
+	}
+	if len(value) > maxArgumentLength {
+		return fmt.Errorf("value exceeds maximum length of %d characters", maxArgumentLength)
+	}
+
+	if len(enum) > 0 {
+		for _, allowed := range enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(enum, ", "))
+	}
+
+	switch argType {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("must be a number")
+		}
+	case "array", "object":
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return fmt.Errorf("must be a JSON %s", argType)
+		}
+		_, isArray := parsed.([]interface{})
+		_, isObject := parsed.(map[string]interface{})
+		if (argType == "array" && !isArray) || (argType == "object" && !isObject) {
+			return fmt.Errorf("must be a JSON %s", argType)
+		}
+	}
+	return nil
+}
+
+// argumentValues collects the tool arguments entered in fields, leaving out
+// empty ones. It returns nil if there are none.
+func argumentValues(fields []propertyField) map[string]string {
+	var values map[string]string
+	for _, field := range fields {
+		if field.param == "" || field.value == "" {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[field.param] = field.value
+	}
+	return values
+}
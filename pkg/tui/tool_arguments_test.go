@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// newArgumentTestSchema describes a tool with required, typed, enum and
+// boolean arguments
+func newArgumentTestSchema() *mcpserver.ToolSchema {
+	schema := mcpserver.NewToolSchema("object")
+	schema.AddProperty("path", map[string]interface{}{"type": "string", "description": "File to write"})
+	schema.AddProperty("mode", map[string]interface{}{"type": "string", "enum": []interface{}{"overwrite", "append"}})
+	schema.AddProperty("retries", map[string]interface{}{"type": "integer"})
+	schema.AddProperty("backup", map[string]interface{}{"type": "boolean"})
+	schema.AddRequired("path")
+	schema.AddRequired("mode")
+	return schema
+}
+
+func TestArgumentFields(t *testing.T) {
+	noExtra := false
+	schema := newArgumentTestSchema()
+	schema.AdditionalProperties = &noExtra
+
+	fields := argumentFields(schema, map[string]string{"path": "/tmp/out", "typo": "x"})
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.param)
+	}
+	want := []string{"mode", "path", "backup", "retries", "typo"}
+	if len(names) != len(want) {
+		t.Fatalf("fields = %q, want %q", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("fields = %q, want %q (required first)", names, want)
+		}
+	}
+
+	if !fields[0].required || fields[2].required {
+		t.Error("required markers do not follow the schema")
+	}
+	if fields[1].value != "/tmp/out" || fields[1].helpText != "string; File to write" {
+		t.Errorf("path field = %+v", fields[1])
+	}
+	if len(fields[2].enum) != 2 {
+		t.Errorf("boolean field offers %q, want true and false", fields[2].enum)
+	}
+	if err := fields[4].validate(); err == nil {
+		t.Error("expected an argument the schema rejects to be invalid")
+	}
+
+	if values := argumentValues(fields); len(values) != 2 || values["path"] != "/tmp/out" {
+		t.Errorf("argumentValues() = %v", values)
+	}
+}
+
+func TestValidateArgument(t *testing.T) {
+	tests := []struct {
+		value   string
+		argType string
+		enum    []string
+		wantErr bool
+	}{
+		{"", "integer", nil, false},
+		{"3", "integer", nil, false},
+		{"3.5", "integer", nil, true},
+		{"3.5", "number", nil, false},
+		{"many", "number", nil, true},
+		{"[1, 2]", "array", nil, false},
+		{`{"a": 1}`, "array", nil, true},
+		{`{"a": 1}`, "object", nil, false},
+		{"append", "string", []string{"overwrite", "append"}, false},
+		{"replace", "string", []string{"overwrite", "append"}, true},
+		{"${count}", "integer", nil, false},
+		{"${}", "integer", nil, true},
+		{"anything", "", nil, false},
+	}
+	for _, tt := range tests {
+		err := validateArgument(tt.value, tt.argType, tt.enum)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateArgument(%q, %q, %q) error = %v, wantErr %v", tt.value, tt.argType, tt.enum, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWorkflowBuilder_ToolArgumentForm(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	if err := builder.EditNodeProperties("fetch"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+	if n := len(panel.fields); n != 6 {
+		t.Fatalf("%d fields before choosing a tool, want 6", n)
+	}
+
+	// Choosing a tool with a schema adds a field per argument
+	typeKeys(t, builder, "Tab", "Enter", "filesystem", "Enter", "Tab", "Enter", "write_file", "Enter")
+	if n := len(panel.fields); n != 10 {
+		t.Fatalf("%d fields after choosing write_file, want 10", n)
+	}
+
+	// The enum argument offers its values and is checked while typing
+	typeKeys(t, builder, "Tab", "Tab", "Tab", "Tab", "Enter")
+	if panel.fields[panel.editIndex].param != "mode" {
+		t.Fatalf("editing %q, want mode", panel.fields[panel.editIndex].label)
+	}
+	if matches, _ := panel.Completions(); len(matches) != 2 {
+		t.Errorf("mode completions = %q", matches)
+	}
+	typeKeys(t, builder, "x")
+	if panel.GetValidationMessage() == "" {
+		t.Error("expected an invalid mode to be reported while typing")
+	}
+	typeKeys(t, builder, "Backspace", "Tab", "Enter")
+	if panel.GetValidationMessage() != "" {
+		t.Errorf("validation message = %q after completing", panel.GetValidationMessage())
+	}
+
+	// Required arguments must be filled in to save
+	if err := builder.HandleKey("Ctrl+s"); err == nil {
+		t.Fatal("expected saving without the required path to fail")
+	}
+	typeKeys(t, builder, "Tab", "Enter", "${it")
+	if matches, _ := panel.Completions(); len(matches) != 1 || matches[0] != "items" {
+		t.Errorf("placeholder completions = %q", matches)
+	}
+	typeKeys(t, builder, "Tab", "}", "Enter", "Ctrl+s")
+
+	node := builder.workflow.Nodes[0].(*workflow.MCPToolNode)
+	if len(node.Parameters) != 2 || node.Parameters["mode"] != "overwrite" || node.Parameters["path"] != "${items}" {
+		t.Errorf("saved parameters = %v", node.Parameters)
+	}
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goterm"
+)
+
+// toolPlayground is a form for trying a tool of a connected server from the
+// tool schema viewer: one field per argument of the tool's input schema,
+// and the tool's response once invoked
+type toolPlayground struct {
+	server   *mcpserver.MCPServer
+	tool     mcpserver.Tool
+	fields   []propertyField
+	selected int      // Field being typed in
+	response []string // Pretty-printed response, or why the call failed
+	failed   bool
+	scroll   int                      // First response line shown
+	samples  *storage.ToolSampleStore // Where responses are recorded, if set
+}
+
+// openToolPlayground opens the playground for the selected tool. Tools
+// are invoked as typed, without ${} substitution, so argument fields take
+// plain values. Responses are recorded as samples of the tool's output.
+func (v *ServerRegistryView) openToolPlayground() {
+	if v.selectedIdx >= len(v.servers) {
+		return
+	}
+	server := v.servers[v.selectedIdx]
+	if v.selectedTool >= len(server.Tools) {
+		return
+	}
+	if server.Connection.GetState() != mcpserver.StateConnected {
+		v.statusMsg = fmt.Sprintf("Connect to '%s' to try its tools", server.Name)
+		return
+	}
+
+	tool := server.Tools[v.selectedTool]
+	fields := argumentFields(tool.InputSchema, nil)
+	for i := range fields {
+		fields[i].helpText = strings.Replace(fields[i].helpText, "; ${var} inserts a variable", "", 1)
+	}
+	v.playground = &toolPlayground{server: server, tool: tool, fields: fields, samples: v.toolSamples}
+	v.statusMsg = fmt.Sprintf("Trying %s (Tab: next field, Enter: invoke, Esc: back)", tool.Name)
+}
+
+// handlePlaygroundKey edits the playground's arguments. Enter invokes the
+// tool; Escape closes the playground.
+func (v *ServerRegistryView) handlePlaygroundKey(event KeyEvent) error {
+	p := v.playground
+	switch {
+	case event.IsSpecial && event.Special == "Escape", event.Ctrl && event.Key == 'c':
+		v.playground = nil
+		v.statusMsg = "Viewing tool schemas (j/k: navigate, y: copy name, Y: copy node, t: try, Esc: back)"
+	case event.IsSpecial && event.Special == "Enter":
+		p.invoke()
+		if p.failed {
+			v.statusMsg = fmt.Sprintf("%s failed", p.tool.Name)
+		} else {
+			v.statusMsg = fmt.Sprintf("%s returned %d lines (PgUp/PgDn: scroll)", p.tool.Name, len(p.response))
+		}
+	case event.IsSpecial && (event.Special == "Tab" && !event.Shift || event.Special == "Down"):
+		if len(p.fields) > 0 {
+			p.selected = (p.selected + 1) % len(p.fields)
+		}
+	case event.IsSpecial && (event.Special == "Tab" && event.Shift || event.Special == "Up"):
+		if len(p.fields) > 0 {
+			p.selected = (p.selected + len(p.fields) - 1) % len(p.fields)
+		}
+	case event.IsSpecial && event.Special == "PageDown":
+		if p.scroll < len(p.response)-1 {
+			p.scroll++
+		}
+	case event.IsSpecial && event.Special == "PageUp":
+		if p.scroll > 0 {
+			p.scroll--
+		}
+	case event.IsSpecial && event.Special == "Backspace":
+		if field := p.field(); field != nil && field.value != "" {
+			runes := []rune(field.value)
+			field.value = string(runes[:len(runes)-1])
+		}
+	case event.Ctrl && event.Key == 'v':
+		if field := p.field(); field != nil {
+			field.value += strings.TrimRight(Clipboard().Paste(), "\r\n")
+		}
+	case event.Ctrl && event.Key == 'u':
+		if field := p.field(); field != nil {
+			field.value = ""
+		}
+	case !event.IsSpecial && !event.Ctrl && !event.Alt && event.Key != 0:
+		if field := p.field(); field != nil {
+			field.value += string(event.Key)
+		}
+	}
+	return nil
+}
+
+// field returns the field being typed in, or nil if the tool takes no
+// arguments
+func (p *toolPlayground) field() *propertyField {
+	if p.selected >= len(p.fields) {
+		return nil
+	}
+	return &p.fields[p.selected]
+}
+
+// invoke calls the tool with the entered arguments and keeps its response
+func (p *toolPlayground) invoke() {
+	p.scroll = 0
+	params, err := p.arguments()
+	if err == nil {
+		var result interface{}
+		result, err = p.server.InvokeTool(p.tool.Name, params)
+		if err == nil {
+			p.response, err = prettyResponse(result)
+		}
+		if err == nil && p.samples != nil {
+			_ = p.samples.Record(p.server.ID, p.tool.Name, result) // Best effort: only feeds field suggestions
+		}
+	}
+	p.failed = err != nil
+	if p.failed {
+		p.response = []string{err.Error()}
+	}
+}
+
+// arguments checks the entered arguments and converts them to the types
+// of the input schema. Empty fields are left out.
+func (p *toolPlayground) arguments() (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	for i := range p.fields {
+		field := &p.fields[i]
+		if field.value == "" {
+			if field.required {
+				return nil, fmt.Errorf("%s is required", field.label)
+			}
+			continue
+		}
+		if err := field.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", field.label, err)
+		}
+		params[field.param] = typedArgument(field.value, p.argumentType(field.param))
+	}
+	return params, nil
+}
+
+// argumentType returns the schema type of an argument, "" if unknown
+func (p *toolPlayground) argumentType(name string) string {
+	if p.tool.InputSchema == nil {
+		return ""
+	}
+	property, _ := p.tool.InputSchema.Properties[name].(map[string]interface{})
+	argType, _ := property["type"].(string)
+	return argType
+}
+
+// typedArgument converts an entered argument to its schema type. Values
+// that do not parse are passed as typed, for the server to reject.
+func typedArgument(value, argType string) interface{} {
+	switch argType {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "array", "object":
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// prettyResponse formats a tool response as indented JSON lines
+func prettyResponse(result interface{}) ([]string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n"), nil
+}
+
+// renderToolPlayground renders the playground's argument form and the
+// tool's response below it
+func (v *ServerRegistryView) renderToolPlayground(screen *goterm.Screen, startY int) int {
+	p := v.playground
+	theme := CurrentTheme()
+	fg := theme.Text
+	bg := theme.Background
+
+	screen.DrawText(0, startY, fmt.Sprintf("Try %s - %s:", p.tool.Name, p.server.Name), fg, bg, goterm.StyleBold)
+	y := startY + 2
+
+	if len(p.fields) == 0 {
+		screen.DrawText(0, y, "  No arguments; press Enter to invoke", theme.Muted, bg, goterm.StyleDim)
+		y++
+	}
+	labelWidth := 0
+	for _, field := range p.fields {
+		labelWidth = max(labelWidth, len(field.label)+1)
+	}
+	for i, field := range p.fields {
+		if y >= v.height-2 {
+			break
+		}
+		label := field.label
+		if field.required {
+			label += "*"
+		}
+		line := fmt.Sprintf("  %-*s  ", labelWidth, label)
+		screen.DrawText(0, y, line, fg, bg, goterm.StyleNone)
+		value := field.value
+		valueFg, valueBg := fg, bg
+		if i == p.selected {
+			value += "_"
+			valueFg, valueBg = theme.InputFg, theme.InputBg
+		}
+		screen.DrawText(len(line), y, value, valueFg, valueBg, goterm.StyleNone)
+		y++
+	}
+	if field := p.field(); field != nil && y < v.height-2 {
+		screen.DrawText(2, y, field.helpText, theme.Muted, bg, goterm.StyleDim)
+		y++
+	}
+
+	if p.response == nil {
+		return y
+	}
+	y++
+	if y < v.height-2 {
+		heading := "Response:"
+		if p.failed {
+			heading = "Error:"
+		}
+		screen.DrawText(0, y, heading, fg, bg, goterm.StyleBold)
+		y++
+	}
+	responseFg := fg
+	if p.failed {
+		responseFg = theme.Error
+	}
+	for _, line := range p.response[p.scroll:] {
+		if y >= v.height-2 {
+			break
+		}
+		if len(line) > v.width-2 && v.width > 2 {
+			line = line[:v.width-2]
+		}
+		screen.DrawText(2, y, line, responseFg, bg, goterm.StyleNone)
+		y++
+	}
+	return y
+}
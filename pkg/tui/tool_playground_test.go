@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
+)
+
+func TestToolPlayground(t *testing.T) {
+	t.Setenv("GOFLOW_SAMPLES_DIR", t.TempDir())
+	view := setupTestView(t, 1)
+	defer view.Cleanup()
+	server := view.servers[0]
+	server.Tools = []mcpserver.Tool{{Name: "search", InputSchema: &mcpserver.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"limit": map[string]interface{}{"type": "integer"},
+			"query": map[string]interface{}{"type": "string"},
+		},
+		Required: []string{"query"},
+	}}}
+	view.showToolSchema = true
+
+	press := func(keys string) {
+		for _, key := range keys {
+			if err := view.HandleKey(KeyEvent{Key: key}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	special := func(name string) {
+		if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Tools are only tried on connected servers
+	press("t")
+	if view.playground != nil {
+		t.Fatal("expected no playground for a disconnected server")
+	}
+	if err := server.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.CompleteConnection(); err != nil {
+		t.Fatal(err)
+	}
+
+	press("t")
+	p := view.playground
+	if p == nil || !view.CapturingText() {
+		t.Fatal("expected the playground to open and capture keys")
+	}
+	if len(p.fields) != 2 || p.fields[0].param != "query" {
+		t.Fatalf("expected the required query field first, got %+v", p.fields)
+	}
+
+	special("Enter")
+	if !p.failed || p.response[0] != "query is required" {
+		t.Errorf("expected the missing argument to be reported, got %v", p.response)
+	}
+
+	press("goflow")
+	special("Tab")
+	press("x")
+	special("Enter")
+	if !p.failed || !strings.HasPrefix(p.response[0], "limit:") {
+		t.Errorf("expected the invalid limit to be reported, got %v", p.response)
+	}
+
+	special("Backspace")
+	press("5")
+	params, err := p.arguments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"query": "goflow", "limit": int64(5)}; !reflect.DeepEqual(params, want) {
+		t.Errorf("arguments = %v, want %v", params, want)
+	}
+	special("Enter")
+	if p.failed || !strings.Contains(strings.Join(p.response, "\n"), `"result": "mock result"`) {
+		t.Errorf("expected the pretty-printed response, got %v", p.response)
+	}
+
+	samples, err := storage.NewToolSampleStore(storage.DefaultToolSamplesDir()).Load(server.ID, "search")
+	if err != nil || len(samples) != 1 {
+		t.Errorf("expected the response to be recorded as a sample, got %v, %v", samples, err)
+	}
+
+	special("Escape")
+	if view.playground != nil || view.CapturingText() {
+		t.Error("expected Escape to close the playground")
+	}
+	if !view.showToolSchema {
+		t.Error("expected to be back in the tool schema view")
+	}
+}
+
+func TestTypedArgument(t *testing.T) {
+	tests := []struct {
+		value   string
+		argType string
+		want    interface{}
+	}{
+		{"42", "integer", int64(42)},
+		{"1.5", "number", 1.5},
+		{"true", "boolean", true},
+		{`["a"]`, "array", []interface{}{"a"}},
+		{`{"a":1}`, "object", map[string]interface{}{"a": float64(1)}},
+		{"42", "string", "42"},
+		{"many", "integer", "many"},
+		{"text", "", "text"},
+	}
+	for _, tt := range tests {
+		if got := typedArgument(tt.value, tt.argType); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("typedArgument(%q, %q) = %#v, want %#v", tt.value, tt.argType, got, tt.want)
+		}
+	}
+}
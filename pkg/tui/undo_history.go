@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// undoHistoryVersion is the format version of saved undo histories
+const undoHistoryVersion = 1
+
+// undoHistory is the on-disk form of an UndoStack
+type undoHistory struct {
+	Version  int                `json:"version"`
+	Checksum string             `json:"checksum"` // Of the workflow file the history was saved with
+	Cursor   int                `json:"cursor"`
+	Entries  []undoHistoryEntry `json:"entries"`
+}
+
+// undoHistoryEntry is one saved snapshot
+type undoHistoryEntry struct {
+	Workflow  string              `json:"workflow"` // Nodes and edges as a YAML fragment
+	Positions map[string]Position `json:"positions,omitempty"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// WorkflowChecksum identifies the contents of a workflow file, so a saved
+// undo history is only restored onto the file it was saved with
+func WorkflowChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// defaultUndoHistoryDir returns GOFLOW_UNDO_DIR or ~/.goflow/undo
+func defaultUndoHistoryDir() string {
+	if dir := os.Getenv("GOFLOW_UNDO_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".goflow", "undo")
+	}
+	return filepath.Join(homeDir, ".goflow", "undo")
+}
+
+// undoHistoryPath returns where the undo history of a workflow file is
+// kept: one file per workflow in dir, named after its absolute path
+func undoHistoryPath(dir, workflowPath string) string {
+	if abs, err := filepath.Abs(workflowPath); err == nil {
+		workflowPath = abs
+	}
+	sum := sha256.Sum256([]byte(workflowPath))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// SaveHistory writes the history to path, tagged with the checksum of the
+// workflow file it belongs to
+func (u *UndoStack) SaveHistory(path, checksum string) error {
+	history := undoHistory{
+		Version:  undoHistoryVersion,
+		Checksum: checksum,
+		Cursor:   u.cursor,
+		Entries:  make([]undoHistoryEntry, 0, len(u.snapshots)),
+	}
+	for _, s := range u.snapshots {
+		data, err := workflow.NodesToYAML(s.Nodes, s.Edges)
+		if err != nil {
+			return fmt.Errorf("failed to serialize undo history: %w", err)
+		}
+		history.Entries = append(history.Entries, undoHistoryEntry{
+			Workflow:  string(data),
+			Positions: s.CanvasState,
+			Timestamp: s.Timestamp,
+		})
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to serialize undo history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create undo history directory: %w", err)
+	}
+
+	// Write then rename, so a crash never leaves a truncated history
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write undo history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp) // Best effort cleanup
+		return fmt.Errorf("failed to write undo history: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory replaces the history with the one saved at path, if it was
+// saved with a workflow file matching checksum. It reports whether a
+// history was restored; a missing history, or one saved with a different
+// version of the file, is not an error.
+func (u *UndoStack) LoadHistory(path, checksum string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read undo history: %w", err)
+	}
+
+	var history undoHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return false, fmt.Errorf("failed to parse undo history: %w", err)
+	}
+	if history.Version != undoHistoryVersion || history.Checksum != checksum {
+		return false, nil
+	}
+
+	snapshots := make([]workflowSnapshot, 0, len(history.Entries))
+	for i, entry := range history.Entries {
+		nodes, edges, err := workflow.NodesFromYAML([]byte(entry.Workflow))
+		if err != nil {
+			return false, fmt.Errorf("failed to parse undo history entry %d: %w", i, err)
+		}
+		var prev *workflowSnapshot
+		if len(snapshots) > 0 {
+			prev = &snapshots[len(snapshots)-1]
+		}
+		snapshot, _ := u.deltaSnapshot(prev, &workflow.Workflow{Nodes: nodes, Edges: edges}, entry.Positions)
+		snapshot.Timestamp = entry.Timestamp
+		snapshots = append(snapshots, snapshot)
+	}
+
+	u.Clear()
+	u.snapshots = snapshots
+	u.cursor = clamp(history.Cursor, -1, len(snapshots)-1)
+	for _, s := range snapshots {
+		u.memoryUsed += s.size
+	}
+	u.evict()
+	return true, nil
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+func TestUndoStack_SaveLoadHistory(t *testing.T) {
+	stack := NewUndoStack(10)
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+	if err := stack.Push(wf, map[string]Position{"start": {X: 1, Y: 2}}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	wf.AddNode(&workflow.EndNode{ID: "end"})
+	wf.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "start", ToNodeID: "end", Condition: "true"})
+	if err := stack.Push(wf, map[string]Position{"start": {X: 1, Y: 2}, "end": {X: 1, Y: 9}}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if _, err := stack.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "undo", "history.json")
+	if err := stack.SaveHistory(path, "sum-1"); err != nil {
+		t.Fatalf("SaveHistory failed: %v", err)
+	}
+
+	loaded := NewUndoStack(10)
+	restored, err := loaded.LoadHistory(path, "sum-1")
+	if err != nil || !restored {
+		t.Fatalf("LoadHistory = %v, %v; want restored", restored, err)
+	}
+	if loaded.Size() != 2 || loaded.cursor != stack.cursor {
+		t.Fatalf("restored %d snapshots at cursor %d, want 2 at %d", loaded.Size(), loaded.cursor, stack.cursor)
+	}
+	if loaded.MemoryUsage() <= 0 {
+		t.Errorf("expected restored snapshots to be counted, got %d", loaded.MemoryUsage())
+	}
+	snapshot, err := loaded.Redo()
+	if err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if len(snapshot.Nodes) != 2 || len(snapshot.Edges) != 1 || snapshot.Edges[0].Condition != "true" {
+		t.Errorf("restored snapshot = %d nodes, %+v", len(snapshot.Nodes), snapshot.Edges)
+	}
+	if snapshot.CanvasState["end"] != (Position{X: 1, Y: 9}) {
+		t.Errorf("restored positions = %+v", snapshot.CanvasState)
+	}
+
+	// A history saved with other file contents is ignored
+	other := NewUndoStack(10)
+	if restored, err := other.LoadHistory(path, "sum-2"); err != nil || restored {
+		t.Errorf("LoadHistory with stale checksum = %v, %v; want not restored", restored, err)
+	}
+	if restored, err := other.LoadHistory(filepath.Join(t.TempDir(), "missing.json"), "sum-1"); err != nil || restored {
+		t.Errorf("LoadHistory of missing file = %v, %v; want not restored", restored, err)
+	}
+}
+
+func TestWorkflowBuilderView_PersistUndo(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir()) // Saves are audited
+	data, err := os.ReadFile(filepath.Join("..", "..", "examples", "simple-pipeline.yaml"))
+	if err != nil {
+		t.Skipf("example workflow not available: %v", err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pipeline.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() *WorkflowBuilderView {
+		t.Helper()
+		view := NewWorkflowBuilderView()
+		view.undoDir = filepath.Join(dir, "undo")
+		view.tunables.PersistUndo = true
+		view.SetWorkflow(path)
+		if err := view.Init(); err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+		return view
+	}
+
+	view := open()
+	nodeID := view.builder.GetWorkflow().Nodes[0].GetID()
+	before := view.builder.canvas.nodes[nodeID].position
+	if err := view.builder.MoveNodeBy(nodeID, 3, 1); err != nil {
+		t.Fatalf("MoveNodeBy failed: %v", err)
+	}
+	if err := view.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Reopening the saved file brings the history back
+	view = open()
+	if !view.builder.CanUndo() {
+		t.Fatal("expected undo history to be restored")
+	}
+	if err := view.builder.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if got := view.builder.canvas.nodes[nodeID].position; got != before {
+		t.Errorf("position after undo = %+v, want %+v", got, before)
+	}
+
+	// Once the file changes outside the builder the history no longer applies
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if view := open(); view.builder.CanUndo() {
+		t.Error("expected undo history of a changed file to be ignored")
+	}
+}
@@ -1,85 +1,127 @@
 package tui
 
 import (
+	"encoding/json"
 	"errors"
+	"maps"
+	"reflect"
 	"time"
 
 	"github.com/dshills/goflow/pkg/workflow"
 )
 
 // workflowSnapshot represents a point-in-time state of the workflow
+//
+// Snapshots are stored as deltas: nodes, edges and positions that are
+// unchanged since the previous snapshot are shared with it rather than
+// copied, so a snapshot only costs the memory of what its operation changed.
+// Shared values are never modified; Undo and Redo hand out copies.
 type workflowSnapshot struct {
 	Nodes       []workflow.Node     // Deep copy of nodes
 	Edges       []*workflow.Edge    // Deep copy of edges
 	CanvasState map[string]Position // Node positions on canvas
 	Timestamp   time.Time           // When snapshot was created
+	size        int                 // Estimated bytes not shared with the previous snapshot
 }
 
+// Default limits
+const (
+	DefaultUndoDepth       = 100
+	DefaultUndoMemoryLimit = 64 << 20 // 64 MiB
+)
+
+// Estimated per-value overheads used for memory accounting
+const (
+	nodeOverhead     = 64 // Node header and interface slot
+	edgeOverhead     = 80 // Edge struct and pointer slot
+	positionOverhead = 48 // Map entry and key header
+)
+
 // UndoStack manages undo/redo history with a circular buffer
 type UndoStack struct {
-	snapshots []workflowSnapshot // Circular buffer of snapshots
-	cursor    int                // Current position (-1 if empty)
-	capacity  int                // Maximum number of snapshots
+	snapshots   []workflowSnapshot // Circular buffer of snapshots
+	cursor      int                // Current position (-1 if empty)
+	capacity    int                // Maximum number of snapshots
+	memoryLimit int                // Maximum estimated bytes held (0 = unlimited)
+	memoryUsed  int                // Estimated bytes held by all snapshots
+	lastKey     string             // Coalescing key of the last push ("" = none)
 }
 
 // NewUndoStack creates a new undo stack with the specified capacity
 func NewUndoStack(capacity int) *UndoStack {
 	if capacity <= 0 {
-		capacity = 100 // Default capacity
+		capacity = DefaultUndoDepth // Default capacity
 	}
 
 	return &UndoStack{
-		snapshots: make([]workflowSnapshot, 0, capacity),
-		cursor:    -1,
-		capacity:  capacity,
+		snapshots:   make([]workflowSnapshot, 0, capacity),
+		cursor:      -1,
+		capacity:    capacity,
+		memoryLimit: DefaultUndoMemoryLimit,
 	}
 }
 
 // Push adds a new snapshot to the stack
-// This clears any redo history beyond the current cursor
+// This clears any redo history beyond the current cursor. A snapshot
+// identical to the one at the cursor isn't added again.
 func (u *UndoStack) Push(wf *workflow.Workflow, canvasPositions map[string]Position) error {
 	if wf == nil {
 		return errors.New("cannot push nil workflow")
 	}
-
-	// Create snapshot with deep copies
-	snapshot := workflowSnapshot{
-		Nodes:       u.deepCopyNodes(wf.Nodes),
-		Edges:       u.deepCopyEdges(wf.Edges),
-		CanvasState: u.deepCopyPositions(canvasPositions),
-		Timestamp:   time.Now(),
-	}
+	u.lastKey = ""
 
 	// If cursor is not at the end, we need to clear the redo stack
 	// (any snapshots beyond cursor position)
 	if u.cursor < len(u.snapshots)-1 {
 		// Truncate snapshots to cursor+1 length
+		for _, dropped := range u.snapshots[u.cursor+1:] {
+			u.memoryUsed -= dropped.size
+		}
 		u.snapshots = u.snapshots[:u.cursor+1]
 	}
 
-	// Check if we're at capacity
-	if len(u.snapshots) >= u.capacity {
-		// Circular buffer: remove oldest snapshot (index 0)
-		// Shift all snapshots left and replace last one
-		copy(u.snapshots, u.snapshots[1:])
-		u.snapshots[len(u.snapshots)-1] = snapshot
-		u.cursor = len(u.snapshots) - 1
-	} else {
-		// Still have room, append normally
-		u.snapshots = append(u.snapshots, snapshot)
-		u.cursor = len(u.snapshots) - 1
+	// Create the snapshot as a delta against the one before it
+	var prev *workflowSnapshot
+	if len(u.snapshots) > 0 {
+		prev = &u.snapshots[len(u.snapshots)-1]
+	}
+	snapshot, changed := u.deltaSnapshot(prev, wf, canvasPositions)
+	if !changed {
+		return nil
 	}
 
+	u.snapshots = append(u.snapshots, snapshot)
+	u.cursor = len(u.snapshots) - 1
+	u.memoryUsed += snapshot.size
+	u.evict()
+
 	return nil
 }
 
-// Undo moves back one snapshot and returns it
+// PushCoalesced adds a snapshot like Push, unless the previous push had the
+// same key and nothing has been undone since. The earlier snapshot already
+// holds the state from before the run of operations, so the whole run is
+// undone in one step. Keys name an operation and what it acts on, such as
+// "move:node-1"; an empty key never coalesces.
+func (u *UndoStack) PushCoalesced(key string, wf *workflow.Workflow, canvasPositions map[string]Position) error {
+	if key != "" && key == u.lastKey && u.cursor == len(u.snapshots)-1 {
+		return nil
+	}
+	if err := u.Push(wf, canvasPositions); err != nil {
+		return err
+	}
+	u.lastKey = key
+	return nil
+}
+
+// Undo moves back one snapshot and returns a copy of it
 // When at cursor position 0, undoing moves to -1 (before first snapshot)
 // and returns nil to indicate "no snapshot state"
 func (u *UndoStack) Undo() (*workflowSnapshot, error) {
 	if !u.CanUndo() {
 		return nil, errors.New("nothing to undo")
 	}
+	u.lastKey = ""
 
 	// Move cursor back
 	u.cursor--
@@ -90,20 +132,21 @@ func (u *UndoStack) Undo() (*workflowSnapshot, error) {
 		return nil, nil
 	}
 
-	return &u.snapshots[u.cursor], nil
+	return u.copySnapshot(&u.snapshots[u.cursor]), nil
 }
 
-// Redo moves forward one snapshot and returns it
+// Redo moves forward one snapshot and returns a copy of it
 func (u *UndoStack) Redo() (*workflowSnapshot, error) {
 	if !u.CanRedo() {
 		return nil, errors.New("nothing to redo")
 	}
+	u.lastKey = ""
 
 	// Move cursor forward
 	u.cursor++
 
 	// Return snapshot at new cursor position
-	return &u.snapshots[u.cursor], nil
+	return u.copySnapshot(&u.snapshots[u.cursor]), nil
 }
 
 // CanUndo returns true if undo is available
@@ -125,6 +168,8 @@ func (u *UndoStack) CanRedo() bool {
 func (u *UndoStack) Clear() {
 	u.snapshots = make([]workflowSnapshot, 0, u.capacity)
 	u.cursor = -1
+	u.memoryUsed = 0
+	u.lastKey = ""
 }
 
 // Size returns the current number of snapshots
@@ -132,6 +177,167 @@ func (u *UndoStack) Size() int {
 	return len(u.snapshots)
 }
 
+// SetCapacity changes the maximum number of snapshots, dropping the oldest
+// if there are more
+func (u *UndoStack) SetCapacity(capacity int) {
+	if capacity <= 0 {
+		capacity = DefaultUndoDepth
+	}
+	u.capacity = capacity
+	u.evict()
+}
+
+// SetMemoryLimit changes the maximum estimated memory the history may hold,
+// dropping the oldest snapshots while it is exceeded. The newest snapshot is
+// always kept. Zero removes the limit.
+func (u *UndoStack) SetMemoryLimit(bytes int) {
+	u.memoryLimit = max(bytes, 0)
+	u.evict()
+}
+
+// MemoryUsage returns the estimated bytes held by the history. Values shared
+// between snapshots are counted once.
+func (u *UndoStack) MemoryUsage() int {
+	return u.memoryUsed
+}
+
+// evict drops the oldest snapshots until the stack is within its capacity
+// and memory limit
+func (u *UndoStack) evict() {
+	for len(u.snapshots) > u.capacity ||
+		(u.memoryLimit > 0 && u.memoryUsed > u.memoryLimit && len(u.snapshots) > 1) {
+		u.memoryUsed -= u.snapshots[0].size
+		u.snapshots = u.snapshots[1:]
+		u.cursor = max(u.cursor-1, -1)
+
+		// The new oldest snapshot no longer has anything to share with, so
+		// it is charged for everything it holds
+		if len(u.snapshots) > 0 {
+			u.memoryUsed -= u.snapshots[0].size
+			u.snapshots[0].size = snapshotSize(nil, &u.snapshots[0])
+			u.memoryUsed += u.snapshots[0].size
+		}
+	}
+}
+
+// deltaSnapshot builds a snapshot of wf that shares unchanged nodes, edges
+// and positions with prev. It reports false if nothing changed since prev.
+func (u *UndoStack) deltaSnapshot(prev *workflowSnapshot, wf *workflow.Workflow, canvasPositions map[string]Position) (workflowSnapshot, bool) {
+	if prev == nil {
+		snapshot := workflowSnapshot{
+			Nodes:       u.deepCopyNodes(wf.Nodes),
+			Edges:       u.deepCopyEdges(wf.Edges),
+			CanvasState: u.deepCopyPositions(canvasPositions),
+			Timestamp:   time.Now(),
+		}
+		snapshot.size = snapshotSize(nil, &snapshot)
+		return snapshot, true
+	}
+
+	snapshot := workflowSnapshot{
+		Nodes:     make([]workflow.Node, len(wf.Nodes)),
+		Edges:     make([]*workflow.Edge, len(wf.Edges)),
+		Timestamp: time.Now(),
+	}
+
+	changed := len(wf.Nodes) != len(prev.Nodes) || len(wf.Edges) != len(prev.Edges)
+
+	prevNodes := make(map[string]workflow.Node, len(prev.Nodes))
+	for _, node := range prev.Nodes {
+		if node != nil {
+			prevNodes[node.GetID()] = node
+		}
+	}
+	for i, node := range wf.Nodes {
+		if node == nil {
+			changed = true
+			continue
+		}
+		if old, exists := prevNodes[node.GetID()]; exists && reflect.DeepEqual(old, node) {
+			snapshot.Nodes[i] = old
+			changed = changed || prev.Nodes[i] != old
+			continue
+		}
+		snapshot.Nodes[i] = u.deepCopyNode(node)
+		changed = true
+	}
+
+	for i, edge := range wf.Edges {
+		if i < len(prev.Edges) && edge != nil && prev.Edges[i] != nil && *prev.Edges[i] == *edge {
+			snapshot.Edges[i] = prev.Edges[i]
+			continue
+		}
+		snapshot.Edges[i] = u.deepCopyEdges([]*workflow.Edge{edge})[0]
+		changed = true
+	}
+
+	if maps.Equal(prev.CanvasState, canvasPositions) {
+		snapshot.CanvasState = prev.CanvasState
+	} else {
+		snapshot.CanvasState = u.deepCopyPositions(canvasPositions)
+		changed = true
+	}
+
+	snapshot.size = snapshotSize(prev, &snapshot)
+	return snapshot, changed
+}
+
+// copySnapshot returns a deep copy of a stored snapshot, so the workflow it
+// is restored into can be edited without touching the history
+func (u *UndoStack) copySnapshot(s *workflowSnapshot) *workflowSnapshot {
+	return &workflowSnapshot{
+		Nodes:       u.deepCopyNodes(s.Nodes),
+		Edges:       u.deepCopyEdges(s.Edges),
+		CanvasState: u.deepCopyPositions(s.CanvasState),
+		Timestamp:   s.Timestamp,
+		size:        s.size,
+	}
+}
+
+// snapshotSize estimates the bytes s holds that it doesn't share with prev
+func snapshotSize(prev, s *workflowSnapshot) int {
+	size := 16*len(s.Nodes) + 8*len(s.Edges) // The slices themselves
+
+	shared := make(map[any]bool)
+	if prev != nil {
+		for _, node := range prev.Nodes {
+			shared[node] = true
+		}
+		for _, edge := range prev.Edges {
+			shared[edge] = true
+		}
+	}
+
+	for _, node := range s.Nodes {
+		if node == nil || shared[node] {
+			continue
+		}
+		data, err := json.Marshal(node)
+		if err != nil {
+			data = nil
+		}
+		size += nodeOverhead + len(data)
+	}
+	for _, edge := range s.Edges {
+		if edge == nil || shared[edge] {
+			continue
+		}
+		size += edgeOverhead + len(edge.ID) + len(edge.FromNodeID) + len(edge.ToNodeID) +
+			len(edge.Condition) + len(edge.Label)
+	}
+	if prev == nil || !sameMap(prev.CanvasState, s.CanvasState) {
+		for id := range s.CanvasState {
+			size += positionOverhead + len(id)
+		}
+	}
+	return size
+}
+
+// sameMap reports whether a and b are the same map, not just equal ones
+func sameMap(a, b map[string]Position) bool {
+	return reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer()
+}
+
 // deepCopyNodes creates a deep copy of the nodes slice
 func (u *UndoStack) deepCopyNodes(nodes []workflow.Node) []workflow.Node {
 	if nodes == nil {
@@ -166,10 +372,22 @@ func (u *UndoStack) deepCopyNode(node workflow.Node) workflow.Node {
 		return u.copyTransformNode(n)
 	case *workflow.ConditionNode:
 		return u.copyConditionNode(n)
+	case *workflow.SwitchNode:
+		return u.copySwitchNode(n)
 	case *workflow.LoopNode:
 		return u.copyLoopNode(n)
 	case *workflow.ParallelNode:
 		return u.copyParallelNode(n)
+	case *workflow.TryNode:
+		return u.copyTryNode(n)
+	case *workflow.CatchNode:
+		return u.copyCatchNode(n)
+	case *workflow.DelayNode:
+		return u.copyDelayNode(n)
+	case *workflow.ApprovalNode:
+		return u.copyApprovalNode(n)
+	case *workflow.BatchToolNode:
+		return u.copyBatchToolNode(n)
 	default:
 		// Fallback: return the node as-is (may not be safe)
 		return node
@@ -209,6 +427,15 @@ func (u *UndoStack) copyMCPToolNode(n *workflow.MCPToolNode) workflow.Node {
 		params[k] = v
 	}
 
+	// Deep copy content routing map if present
+	var contentOutputs map[string]string
+	if n.ContentOutputs != nil {
+		contentOutputs = make(map[string]string, len(n.ContentOutputs))
+		for k, v := range n.ContentOutputs {
+			contentOutputs[k] = v
+		}
+	}
+
 	// Deep copy retry policy if present
 	var retry *workflow.RetryPolicy
 	if n.Retry != nil {
@@ -226,7 +453,10 @@ func (u *UndoStack) copyMCPToolNode(n *workflow.MCPToolNode) workflow.Node {
 		ToolName:       n.ToolName,
 		Parameters:     params,
 		OutputVariable: n.OutputVariable,
+		ContentOutputs: contentOutputs,
 		Retry:          retry,
+		CacheTTL:       n.CacheTTL,
+		StreamOutput:   n.StreamOutput,
 	}
 	return copy
 }
@@ -248,11 +478,16 @@ func (u *UndoStack) copyTransformNode(n *workflow.TransformNode) workflow.Node {
 	}
 
 	copy := &workflow.TransformNode{
-		ID:             n.ID,
-		Expression:     n.Expression,
-		InputVariable:  n.InputVariable,
-		OutputVariable: n.OutputVariable,
-		Retry:          retry,
+		ID:                n.ID,
+		Expression:        n.Expression,
+		InputVariable:     n.InputVariable,
+		OutputVariable:    n.OutputVariable,
+		Retry:             retry,
+		ExpressionTimeout: n.ExpressionTimeout,
+		Language:          n.Language,
+		ItemErrorsOutput:  n.ItemErrorsOutput,
+		// The editor replaces the schema rather than changing it in place
+		OutputSchema: n.OutputSchema,
 	}
 	return copy
 }
@@ -262,8 +497,21 @@ func (u *UndoStack) copyConditionNode(n *workflow.ConditionNode) workflow.Node {
 		return nil
 	}
 	copy := &workflow.ConditionNode{
-		ID:        n.ID,
-		Condition: n.Condition,
+		ID:                n.ID,
+		Condition:         n.Condition,
+		ExpressionTimeout: n.ExpressionTimeout,
+	}
+	return copy
+}
+
+func (u *UndoStack) copySwitchNode(n *workflow.SwitchNode) workflow.Node {
+	if n == nil {
+		return nil
+	}
+	copy := &workflow.SwitchNode{
+		ID:                n.ID,
+		Cases:             append([]workflow.SwitchCase(nil), n.Cases...),
+		ExpressionTimeout: n.ExpressionTimeout,
 	}
 	return copy
 }
@@ -278,15 +526,68 @@ func (u *UndoStack) copyLoopNode(n *workflow.LoopNode) workflow.Node {
 	copy(body, n.Body)
 
 	nodeCopy := &workflow.LoopNode{
-		ID:             n.ID,
-		Collection:     n.Collection,
-		ItemVariable:   n.ItemVariable,
-		Body:           body,
-		BreakCondition: n.BreakCondition,
+		ID:                n.ID,
+		Collection:        n.Collection,
+		ItemVariable:      n.ItemVariable,
+		Body:              body,
+		BreakCondition:    n.BreakCondition,
+		Scope:             n.Scope,
+		Promote:           append([]string(nil), n.Promote...),
+		ExpressionTimeout: n.ExpressionTimeout,
 	}
 	return nodeCopy
 }
 
+func (u *UndoStack) copyTryNode(n *workflow.TryNode) workflow.Node {
+	if n == nil {
+		return nil
+	}
+	return &workflow.TryNode{
+		ID:   n.ID,
+		Body: append([]string(nil), n.Body...),
+	}
+}
+
+func (u *UndoStack) copyCatchNode(n *workflow.CatchNode) workflow.Node {
+	if n == nil {
+		return nil
+	}
+	return &workflow.CatchNode{
+		ID:            n.ID,
+		ErrorVariable: n.ErrorVariable,
+	}
+}
+
+func (u *UndoStack) copyDelayNode(n *workflow.DelayNode) workflow.Node {
+	if n == nil {
+		return nil
+	}
+	nodeCopy := *n
+	return &nodeCopy
+}
+
+func (u *UndoStack) copyApprovalNode(n *workflow.ApprovalNode) workflow.Node {
+	if n == nil {
+		return nil
+	}
+	nodeCopy := *n
+	return &nodeCopy
+}
+
+func (u *UndoStack) copyBatchToolNode(n *workflow.BatchToolNode) workflow.Node {
+	if n == nil {
+		return nil
+	}
+	nodeCopy := *n
+	if n.Parameters != nil {
+		nodeCopy.Parameters = make(map[string]string, len(n.Parameters))
+		for k, v := range n.Parameters {
+			nodeCopy.Parameters[k] = v
+		}
+	}
+	return &nodeCopy
+}
+
 func (u *UndoStack) copyParallelNode(n *workflow.ParallelNode) workflow.Node {
 	if n == nil {
 		return nil
@@ -303,6 +604,9 @@ func (u *UndoStack) copyParallelNode(n *workflow.ParallelNode) workflow.Node {
 		ID:            n.ID,
 		Branches:      branches,
 		MergeStrategy: n.MergeStrategy,
+		Scope:         n.Scope,
+		Promote:       append([]string(nil), n.Promote...),
+		OnConflict:    n.OnConflict,
 	}
 	return nodeCopy
 }
@@ -321,6 +625,8 @@ func (u *UndoStack) deepCopyEdges(edges []*workflow.Edge) []*workflow.Edge {
 				FromNodeID: edge.FromNodeID,
 				ToNodeID:   edge.ToNodeID,
 				Condition:  edge.Condition,
+				Label:      edge.Label,
+				OnError:    edge.OnError,
 			}
 		}
 	}
@@ -465,3 +465,149 @@ func TestUndoStack_TimestampOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestUndoStack_PushSkipsUnchanged(t *testing.T) {
+	stack := NewUndoStack(10)
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+	positions := map[string]Position{"start": {X: 1, Y: 1}}
+
+	for i := 0; i < 3; i++ {
+		if err := stack.Push(wf, positions); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	if stack.Size() != 1 {
+		t.Errorf("expected identical pushes to be recorded once, got %d", stack.Size())
+	}
+}
+
+func TestUndoStack_PushCoalesced(t *testing.T) {
+	stack := NewUndoStack(10)
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+
+	for x := 0; x < 5; x++ {
+		if err := stack.PushCoalesced("move:start", wf, map[string]Position{"start": {X: x}}); err != nil {
+			t.Fatalf("PushCoalesced failed: %v", err)
+		}
+	}
+	if stack.Size() != 1 {
+		t.Fatalf("expected consecutive moves to share one entry, got %d", stack.Size())
+	}
+	if got := stack.snapshots[0].CanvasState["start"]; got.X != 0 {
+		t.Errorf("expected the entry to hold the state before the first move, got %+v", got)
+	}
+
+	// A different key, or an undo in between, starts a new entry
+	if err := stack.PushCoalesced("move:other", wf, map[string]Position{"start": {X: 5}}); err != nil {
+		t.Fatalf("PushCoalesced failed: %v", err)
+	}
+	if _, err := stack.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if err := stack.PushCoalesced("move:other", wf, map[string]Position{"start": {X: 6}}); err != nil {
+		t.Fatalf("PushCoalesced failed: %v", err)
+	}
+	if stack.Size() != 2 {
+		t.Errorf("expected 2 entries, got %d", stack.Size())
+	}
+}
+
+func TestUndoStack_DeltasShareUnchangedValues(t *testing.T) {
+	stack := NewUndoStack(10)
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+	wf.AddNode(&workflow.EndNode{ID: "end"})
+	wf.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "start", ToNodeID: "end"})
+
+	if err := stack.Push(wf, map[string]Position{"start": {X: 1}, "end": {X: 2}}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	first := stack.MemoryUsage()
+	if first <= 0 {
+		t.Fatalf("expected memory usage to be counted, got %d", first)
+	}
+
+	// Only positions change, so nodes and edges are shared
+	if err := stack.Push(wf, map[string]Position{"start": {X: 5}, "end": {X: 2}}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	a, b := stack.snapshots[0], stack.snapshots[1]
+	if a.Nodes[0] != b.Nodes[0] || a.Edges[0] != b.Edges[0] {
+		t.Error("expected unchanged nodes and edges to be shared between snapshots")
+	}
+	if grown := stack.MemoryUsage() - first; grown <= 0 || grown >= first {
+		t.Errorf("expected a position-only change to cost less than the first snapshot, grew by %d of %d", grown, first)
+	}
+
+	// Sharing must not leak into restored copies
+	snapshot, err := stack.Undo()
+	if err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if snapshot.Nodes[0] == a.Nodes[0] {
+		t.Error("expected Undo to return a copy")
+	}
+}
+
+func TestUndoStack_MemoryLimit(t *testing.T) {
+	stack := NewUndoStack(10)
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+	for x := 0; x < 5; x++ {
+		if err := stack.Push(wf, map[string]Position{"start": {X: x}}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	stack.SetMemoryLimit(1)
+	if stack.Size() != 1 {
+		t.Fatalf("expected only the newest snapshot to survive, got %d", stack.Size())
+	}
+	if got := stack.snapshots[0].CanvasState["start"]; got.X != 4 {
+		t.Errorf("expected the newest snapshot to be kept, got %+v", got)
+	}
+	if stack.MemoryUsage() != stack.snapshots[0].size {
+		t.Errorf("memory usage %d does not match the remaining snapshot %d", stack.MemoryUsage(), stack.snapshots[0].size)
+	}
+
+	stack.SetCapacity(2)
+	stack.SetMemoryLimit(0)
+	for x := 5; x < 10; x++ {
+		if err := stack.Push(wf, map[string]Position{"start": {X: x}}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	if stack.Size() != 2 {
+		t.Errorf("expected capacity 2 to be enforced, got %d", stack.Size())
+	}
+}
+
+func TestWorkflowBuilder_UndoMoves(t *testing.T) {
+	builder := newVisualTestBuilder(t, Position{X: 5, Y: 5})
+	nodeID := builder.selectedNodeID
+
+	pressBuilderKeys(t, builder, "l", "l", "j")
+	if got := builder.canvas.nodes[nodeID].position; got != (Position{X: 7, Y: 6}) {
+		t.Fatalf("position after moves = %+v", got)
+	}
+
+	// The run of moves is undone in one step, back to where it started
+	if err := builder.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if got := builder.canvas.nodes[nodeID].position; got != (Position{X: 5, Y: 5}) {
+		t.Errorf("position after undo = %+v, want {5 5}", got)
+	}
+	if len(builder.workflow.Nodes) != 1 {
+		t.Errorf("expected undo to keep the node, got %d nodes", len(builder.workflow.Nodes))
+	}
+
+	if err := builder.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if got := builder.canvas.nodes[nodeID].position; got != (Position{X: 7, Y: 6}) {
+		t.Errorf("position after redo = %+v, want {7 6}", got)
+	}
+}
@@ -7,6 +7,26 @@ import (
 	"github.com/dshills/goflow/pkg/workflow"
 )
 
+// Warning rules of the editor's validation. Like lint rules, a workflow can
+// set their severity in metadata.lint and silence them on a node with
+// suppress.
+const (
+	RuleUnknownCollection = "unknown-collection"
+	RuleUnknownIdentifier = "unknown-identifier"
+	RuleUnusedWrite       = "unused-write"
+	RuleBranchConflict    = "branch-conflict"
+)
+
+// warningRules are the rules ValidateWorkflow reports as warnings unless the
+// workflow raises them to errors
+var warningRules = map[string]bool{
+	workflow.RuleUnreachableNode: true,
+	RuleUnknownCollection:        true,
+	RuleUnknownIdentifier:        true,
+	RuleUnusedWrite:              true,
+	RuleBranchConflict:           true,
+}
+
 // ValidateWorkflow performs full workflow validation
 // Returns ValidationStatus with all errors and warnings
 // Complexity: O(V + E) where V = nodes, E = edges
@@ -66,6 +86,9 @@ func ValidateWorkflow(wf *workflow.Workflow) *ValidationStatus {
 	// Check domain-specific rules (O(V + E))
 	checkDomainRules(wf, status)
 
+	// Check variables are written before they are read (O(V * (V + E)))
+	checkDataFlow(wf, status)
+
 	status.SetValidated()
 	return status
 }
@@ -209,6 +232,59 @@ func ValidateNode(node workflow.Node, wf *workflow.Workflow) []ValidationError {
 			}
 		}
 
+	case *workflow.SwitchNode:
+		if len(n.Cases) == 0 {
+			errors = append(errors, ValidationError{
+				NodeID:    nodeID,
+				ErrorType: "missing_required_field",
+				Message:   "Switch node must have at least one case",
+			})
+		}
+		for _, c := range n.Cases {
+			if c.Label == "" || c.Condition == "" {
+				errors = append(errors, ValidationError{
+					NodeID:    nodeID,
+					ErrorType: "missing_required_field",
+					Message:   "Switch cases need a label and a condition",
+				})
+				continue
+			}
+			if err := workflow.ValidateExpressionSyntax(c.Condition); err != nil {
+				errors = append(errors, ValidationError{
+					NodeID:    nodeID,
+					ErrorType: "invalid_expression",
+					Message:   fmt.Sprintf("Invalid expression syntax in case '%s': %v", c.Label, err),
+				})
+			}
+		}
+
+	case *workflow.TryNode:
+		if len(n.Body) == 0 {
+			errors = append(errors, ValidationError{
+				NodeID:    nodeID,
+				ErrorType: "missing_required_field",
+				Message:   "Try node must have at least one body node",
+			})
+		}
+
+	case *workflow.CatchNode:
+		if n.ErrorVariable == "" {
+			errors = append(errors, ValidationError{
+				NodeID:    nodeID,
+				ErrorType: "missing_required_field",
+				Message:   "Required field 'error_variable' missing in catch node",
+			})
+		}
+
+	case *workflow.DelayNode, *workflow.ApprovalNode, *workflow.BatchToolNode:
+		if err := n.Validate(); err != nil {
+			errors = append(errors, ValidationError{
+				NodeID:    nodeID,
+				ErrorType: "invalid_config",
+				Message:   err.Error(),
+			})
+		}
+
 	case *workflow.LoopNode:
 		if n.Collection == "" {
 			errors = append(errors, ValidationError{
@@ -344,6 +420,8 @@ func checkReachability(wf *workflow.Workflow, status *ValidationStatus) error {
 			}
 		case *workflow.LoopNode:
 			adjacency[n.ID] = append(adjacency[n.ID], n.Body...)
+		case *workflow.TryNode:
+			adjacency[n.ID] = append(adjacency[n.ID], n.Body...)
 		}
 	}
 
@@ -368,7 +446,7 @@ func checkReachability(wf *workflow.Workflow, status *ValidationStatus) error {
 	for _, node := range wf.Nodes {
 		nodeID := node.GetID()
 		if !reachable[nodeID] {
-			status.AddWarning(nodeID, fmt.Sprintf("Node '%s' is not reachable from start", nodeID))
+			reportWarning(wf, status, nodeID, workflow.RuleUnreachableNode, fmt.Sprintf("Node '%s' is not reachable from start", nodeID))
 		}
 	}
 
@@ -377,15 +455,25 @@ func checkReachability(wf *workflow.Workflow, status *ValidationStatus) error {
 
 // checkDomainRules validates domain-specific rules
 func checkDomainRules(wf *workflow.Workflow, status *ValidationStatus) {
-	// Build edge count map
+	// Build edge count map; error edges are only followed on failure
 	outgoingEdges := make(map[string]int)
 	for _, edge := range wf.Edges {
-		outgoingEdges[edge.FromNodeID]++
+		if !edge.OnError {
+			outgoingEdges[edge.FromNodeID]++
+		}
 	}
 
+	// Expressions may only reference declared or written variables
+	known := wf.KnownVariables()
+
 	for _, node := range wf.Nodes {
 		nodeID := node.GetID()
 
+		// Error edges and try/catch scopes
+		for _, msg := range wf.ErrorHandlingErrors(node) {
+			status.AddError(nodeID, "invalid_error_handling", msg)
+		}
+
 		switch n := node.(type) {
 		case *workflow.ConditionNode:
 			// Condition nodes must have exactly 2 outgoing edges
@@ -397,6 +485,26 @@ func checkDomainRules(wf *workflow.Workflow, status *ValidationStatus) {
 					fmt.Sprintf("Condition node '%s' must have exactly 2 outgoing edges (true/false), found %d", nodeID, count),
 				)
 			}
+			for _, name := range workflow.UnknownConditionIdentifiers(n.Condition, known) {
+				reportWarning(wf, status, nodeID, RuleUnknownIdentifier, fmt.Sprintf("Condition of node '%s' references unknown identifier '%s'", nodeID, name))
+			}
+
+		case *workflow.SwitchNode:
+			// Switch nodes need an edge per case, and a default edge
+			// unless a case always matches
+			for _, msg := range wf.SwitchEdgeErrors(n) {
+				status.AddError(nodeID, "invalid_switch_edges", msg)
+			}
+			for _, c := range n.Cases {
+				for _, name := range workflow.UnknownConditionIdentifiers(c.Condition, known) {
+					reportWarning(wf, status, nodeID, RuleUnknownIdentifier, fmt.Sprintf("Case '%s' of node '%s' references unknown identifier '%s'", c.Label, nodeID, name))
+				}
+			}
+
+		case *workflow.TransformNode:
+			for _, name := range workflow.UnknownTransformIdentifiers(n.Expression, known) {
+				reportWarning(wf, status, nodeID, RuleUnknownIdentifier, fmt.Sprintf("Expression of node '%s' references unknown identifier '%s'", nodeID, name))
+			}
 
 		case *workflow.LoopNode:
 			// Loop nodes should have valid collection source
@@ -416,8 +524,11 @@ func checkDomainRules(wf *workflow.Workflow, status *ValidationStatus) {
 				}
 
 				if !found {
-					status.AddWarning(
+					reportWarning(
+						wf,
+						status,
 						nodeID,
+						RuleUnknownCollection,
 						fmt.Sprintf("Loop node '%s' references undefined collection variable '%s'", nodeID, collection),
 					)
 				}
@@ -432,10 +543,82 @@ func checkDomainRules(wf *workflow.Workflow, status *ValidationStatus) {
 					fmt.Sprintf("Parallel node '%s' must have at least 2 branches, found %d", nodeID, len(n.Branches)),
 				)
 			}
+			// Without an explicit on_conflict policy, branches writing
+			// the same variable silently keep the last branch's value
+			if n.OnConflict == "" {
+				for _, conflict := range wf.BranchWriteConflicts(n) {
+					reportWarning(wf, status, nodeID, RuleBranchConflict, fmt.Sprintf(
+						"Branches %s of parallel node '%s' write '%s'; set on_conflict to %s, %s or %s",
+						formatBranches(conflict.Branches), nodeID, conflict.Variable,
+						workflow.ConflictLastBranch, workflow.ConflictError, workflow.ConflictCollect,
+					))
+				}
+			}
 		}
 	}
 }
 
+// checkDataFlow reports reads of variables only later nodes write as errors
+// and writes no node reads as warnings. Unknown identifiers are reported by
+// checkDomainRules.
+func checkDataFlow(wf *workflow.Workflow, status *ValidationStatus) {
+	known := wf.KnownVariables()
+	for _, issue := range wf.DataFlowIssues() {
+		switch {
+		case issue.IsOrderingError(known):
+			status.AddError(
+				issue.NodeID,
+				string(issue.Kind),
+				fmt.Sprintf("Node '%s' reads '%s', which no upstream node writes", issue.NodeID, issue.Variable),
+			)
+		case issue.Kind == workflow.DataFlowUnusedWrite:
+			reportWarning(
+				wf,
+				status,
+				issue.NodeID,
+				RuleUnusedWrite,
+				fmt.Sprintf("Node '%s' writes '%s', which no node reads", issue.NodeID, issue.Variable),
+			)
+		}
+	}
+}
+
+// reportWarning records a finding of a warning rule at the severity the
+// workflow gives it: dropped when off or suppressed on the node, an error
+// when raised to error
+func reportWarning(wf *workflow.Workflow, status *ValidationStatus, nodeID, rule, message string) {
+	switch wf.RuleSeverity(rule, nodeID, workflow.LintWarning) {
+	case workflow.LintOff:
+	case workflow.LintError:
+		status.AddError(nodeID, rule, message)
+	default:
+		status.AddRuleWarning(nodeID, rule, message)
+	}
+}
+
+// LintFindings returns the warnings of ValidateWorkflow, and the warning
+// rules the workflow raises to errors. Unlike the structural errors of
+// workflow.Validate, these come with rule names.
+func LintFindings(wf *workflow.Workflow) ([]ValidationWarning, []ValidationError) {
+	status := ValidateWorkflow(wf)
+	var errs []ValidationError
+	for _, err := range status.GetErrors() {
+		if warningRules[err.ErrorType] {
+			errs = append(errs, err)
+		}
+	}
+	return status.GetWarnings(), errs
+}
+
+// formatBranches lists branch indexes as 1-based branch numbers
+func formatBranches(branches []int) string {
+	numbers := make([]string, len(branches))
+	for i, branch := range branches {
+		numbers[i] = fmt.Sprint(branch + 1)
+	}
+	return strings.Join(numbers, ", ")
+}
+
 // buildNodeIDSet creates a set of all node IDs for quick lookup
 func buildNodeIDSet(wf *workflow.Workflow) map[string]bool {
 	nodeIDs := make(map[string]bool)
@@ -18,6 +18,7 @@ type ValidationError struct {
 // ValidationWarning represents a non-blocking warning
 type ValidationWarning struct {
 	NodeID  string // Node with warning ("" for global warnings)
+	Rule    string // Rule that raised it, for severities and suppression ("" if none)
 	Message string // Human-readable warning message
 }
 
@@ -65,6 +66,19 @@ func (v *ValidationStatus) AddWarning(nodeID, message string) {
 	})
 }
 
+// AddRuleWarning adds a validation warning raised by a named rule
+// (thread-safe)
+func (v *ValidationStatus) AddRuleWarning(nodeID, rule, message string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.Warnings = append(v.Warnings, ValidationWarning{
+		NodeID:  nodeID,
+		Rule:    rule,
+		Message: message,
+	})
+}
+
 // Clear resets all validation results (thread-safe)
 func (v *ValidationStatus) Clear() {
 	v.mu.Lock()
@@ -337,25 +351,20 @@ func (p *ValidationPanel) Render(screen interface{}, x, y, width, height int) er
 		return nil
 	}
 
-	// Type assert to screen interface
-	type Screen interface {
-		SetCell(cellX, cellY int, cell interface{})
-		Size() (int, int)
-	}
-
-	scr, ok := screen.(Screen)
+	scr, ok := screen.(cellScreen)
 	if !ok {
 		return fmt.Errorf("invalid screen type")
 	}
 
 	// Colors
-	fgColor := goterm.ColorRGB(255, 255, 255)      // White text
-	bgColor := goterm.ColorRGB(30, 30, 30)         // Dark background
-	selectedBgColor := goterm.ColorRGB(58, 58, 58) // Gray background for selected
-	borderFg := goterm.ColorRGB(136, 136, 136)     // Gray border
-	errorFg := goterm.ColorRGB(255, 100, 100)      // Light red for errors
-	warningFg := goterm.ColorRGB(255, 200, 100)    // Orange for warnings
-	successFg := goterm.ColorRGB(100, 255, 100)    // Light green for success
+	theme := CurrentTheme()
+	fgColor := theme.PanelText
+	bgColor := theme.PanelBg
+	selectedBgColor := theme.PanelSelectedBg
+	borderFg := theme.PanelBorder
+	errorFg := theme.Error
+	warningFg := theme.Warning
+	successFg := theme.Success
 
 	// Draw border
 	// Top border
@@ -502,6 +511,9 @@ func (p *ValidationPanel) Render(screen interface{}, x, y, width, height int) er
 			nodeID = "global"
 		}
 		content := fmt.Sprintf("⚠ [%s] %s", nodeID, warn.Message)
+		if warn.Rule != "" {
+			content += fmt.Sprintf(" (%s)", warn.Rule)
+		}
 		if len(content) > width-4 {
 			content = content[:width-7] + "..."
 		}
@@ -640,6 +640,178 @@ func createBenchmarkWorkflow(n int) *workflow.Workflow {
 	return wf
 }
 
+// TestValidateWorkflow_DataFlow tests that reads of variables written later
+// are errors and writes nothing reads are warnings
+func TestValidateWorkflow_DataFlow(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+	wf.AddNode(&workflow.TransformNode{ID: "early", InputVariable: "later", Expression: "$.a", OutputVariable: "unused"})
+	wf.AddNode(&workflow.TransformNode{ID: "late", InputVariable: "early_input", Expression: "$.b", OutputVariable: "later"})
+	wf.AddNode(&workflow.EndNode{ID: "end"})
+	wf.AddVariable(&workflow.Variable{Name: "early_input", Type: "object"})
+	wf.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "start", ToNodeID: "early"})
+	wf.AddEdge(&workflow.Edge{ID: "e2", FromNodeID: "early", ToNodeID: "late"})
+	wf.AddEdge(&workflow.Edge{ID: "e3", FromNodeID: "late", ToNodeID: "end"})
+
+	status := ValidateWorkflow(wf)
+
+	var readErrors []ValidationError
+	for _, err := range status.GetErrors() {
+		if err.ErrorType == string(workflow.DataFlowUnwrittenRead) {
+			readErrors = append(readErrors, err)
+		}
+	}
+	if len(readErrors) != 1 || readErrors[0].NodeID != "early" || !strings.Contains(readErrors[0].Message, "'later'") {
+		t.Errorf("data-flow errors = %+v, want early's read of later", readErrors)
+	}
+
+	found := false
+	for _, warning := range status.GetWarnings() {
+		if warning.NodeID == "early" && strings.Contains(warning.Message, "writes 'unused'") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %+v, want early's unused write", status.GetWarnings())
+	}
+}
+
+// TestValidateWorkflow_BranchConflicts tests that parallel branches writing
+// the same variable are warned about until the node sets on_conflict
+func TestValidateWorkflow_BranchConflicts(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddVariable(&workflow.Variable{Name: "count", Type: "number"})
+	parallel := &workflow.ParallelNode{ID: "fan_out", Branches: [][]string{{"left"}, {"middle"}, {"right"}}, MergeStrategy: "wait_all"}
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+	wf.AddNode(parallel)
+	wf.AddNode(&workflow.TransformNode{ID: "left", InputVariable: "count", Expression: "count + 1", OutputVariable: "total"})
+	wf.AddNode(&workflow.TransformNode{ID: "middle", InputVariable: "count", Expression: "count + 2", OutputVariable: "other"})
+	wf.AddNode(&workflow.TransformNode{ID: "right", InputVariable: "count", Expression: "count + 3", OutputVariable: "total"})
+	wf.AddNode(&workflow.EndNode{ID: "end", ReturnValue: "${total} ${other}"})
+	wf.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "start", ToNodeID: "fan_out"})
+	wf.AddEdge(&workflow.Edge{ID: "e2", FromNodeID: "fan_out", ToNodeID: "end"})
+
+	conflicts := func() []ValidationWarning {
+		var found []ValidationWarning
+		for _, warning := range ValidateWorkflow(wf).GetWarnings() {
+			if warning.Rule == RuleBranchConflict {
+				found = append(found, warning)
+			}
+		}
+		return found
+	}
+
+	found := conflicts()
+	want := "Branches 1, 3 of parallel node 'fan_out' write 'total'; set on_conflict to last_branch, error or collect"
+	if len(found) != 1 || found[0].NodeID != "fan_out" || found[0].Message != want {
+		t.Errorf("branch conflict warnings = %+v, want %q", found, want)
+	}
+
+	parallel.OnConflict = workflow.ConflictCollect
+	if found := conflicts(); len(found) != 0 {
+		t.Errorf("warnings with on_conflict set = %+v, want none", found)
+	}
+
+	parallel.OnConflict = ""
+	parallel.Scope = workflow.ScopeIsolated
+	parallel.Promote = []string{"other"}
+	if found := conflicts(); len(found) != 0 {
+		t.Errorf("warnings for unpromoted writes = %+v, want none", found)
+	}
+}
+
+// TestValidateWorkflow_UnknownIdentifiers tests that conditions and transform
+// expressions referencing unknown identifiers are warned about
+func TestValidateWorkflow_UnknownIdentifiers(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	wf.AddVariable(&workflow.Variable{Name: "count", Type: "number"})
+	wf.AddNode(&workflow.StartNode{ID: "start"})
+	wf.AddNode(&workflow.ConditionNode{ID: "check", Condition: "count > limit"})
+	wf.AddNode(&workflow.TransformNode{ID: "scale", InputVariable: "count", Expression: "count * factr", OutputVariable: "scaled"})
+	wf.AddNode(&workflow.EndNode{ID: "end", ReturnValue: "${scaled}"})
+	wf.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "start", ToNodeID: "check"})
+	wf.AddEdge(&workflow.Edge{ID: "e2", FromNodeID: "check", ToNodeID: "scale", Condition: "true"})
+	wf.AddEdge(&workflow.Edge{ID: "e3", FromNodeID: "check", ToNodeID: "end", Condition: "false"})
+	wf.AddEdge(&workflow.Edge{ID: "e4", FromNodeID: "scale", ToNodeID: "end"})
+
+	status := ValidateWorkflow(wf)
+
+	want := map[string]string{
+		"check": "Condition of node 'check' references unknown identifier 'limit'",
+		"scale": "Expression of node 'scale' references unknown identifier 'factr'",
+	}
+	for _, warning := range status.GetWarnings() {
+		if want[warning.NodeID] == warning.Message {
+			delete(want, warning.NodeID)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("missing warnings %v in %+v", want, status.GetWarnings())
+	}
+	for _, err := range status.GetErrors() {
+		if err.ErrorType == string(workflow.DataFlowUnwrittenRead) {
+			t.Errorf("unknown identifier reported as a data-flow error: %+v", err)
+		}
+	}
+}
+
+// TestValidateWorkflow_RuleSeverities tests that warnings carry their rule,
+// that a workflow can silence or raise them, and that only raised warnings
+// block saving
+func TestValidateWorkflow_RuleSeverities(t *testing.T) {
+	newWorkflow := func() *workflow.Workflow {
+		wf, _ := workflow.NewWorkflow("test", "test workflow")
+		wf.AddVariable(&workflow.Variable{Name: "count", Type: "number"})
+		wf.AddNode(&workflow.StartNode{ID: "start"})
+		wf.AddNode(&workflow.TransformNode{ID: "scale", InputVariable: "count", Expression: "count * factor", OutputVariable: "scaled"})
+		wf.AddNode(&workflow.EndNode{ID: "end", ReturnValue: "${scaled}"})
+		wf.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "start", ToNodeID: "scale"})
+		wf.AddEdge(&workflow.Edge{ID: "e2", FromNodeID: "scale", ToNodeID: "end"})
+		return wf
+	}
+
+	wf := newWorkflow()
+	status := ValidateWorkflow(wf)
+	warnings := status.GetWarnings()
+	if len(warnings) != 1 || warnings[0].Rule != RuleUnknownIdentifier || warnings[0].NodeID != "scale" {
+		t.Fatalf("warnings = %+v, want an unknown-identifier warning on scale", warnings)
+	}
+
+	// Warnings do not block saving
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder() error = %v", err)
+	}
+	if got := builder.GetValidationStatus().GetWarnings(); len(got) != 1 {
+		t.Errorf("builder warnings = %+v, want the unknown identifier", got)
+	}
+	if err := builder.SaveWorkflow(); err != nil {
+		t.Errorf("SaveWorkflow() error = %v, want warnings to allow saving", err)
+	}
+
+	// An inline suppression silences the warning on its node
+	wf = newWorkflow()
+	wf.Metadata.Suppressions = map[string][]string{"scale": {RuleUnknownIdentifier}}
+	if status := ValidateWorkflow(wf); status.HasWarnings() || status.HasErrors() {
+		t.Errorf("suppressed status = %+v, want no findings", status)
+	}
+
+	// Raised to an error, it blocks saving
+	wf = newWorkflow()
+	wf.Metadata.Lint = map[string]workflow.LintSeverity{RuleUnknownIdentifier: workflow.LintError}
+	builder, err = NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder() error = %v", err)
+	}
+	if builder.GetValidationStatus().IsValid {
+		t.Error("builder status is valid, want the raised warning as an error")
+	}
+	err = builder.SaveWorkflow()
+	if err == nil || !strings.Contains(err.Error(), "unknown identifier 'factor' (unknown-identifier)") {
+		t.Errorf("SaveWorkflow() error = %v, want the raised rule to block saving", err)
+	}
+}
+
 // TestValidateWorkflow_Performance ensures validation meets performance targets
 func TestValidateWorkflow_Performance(t *testing.T) {
 	if testing.Short() {
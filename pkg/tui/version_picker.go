@@ -0,0 +1,260 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// workflowVersion is an earlier version of the workflow being built: a
+// named snapshot or a git revision
+type workflowVersion struct {
+	id     string // Snapshot name or commit hash
+	name   string // Shown in the list and messages
+	detail string // Commit message, if any
+	time   time.Time
+}
+
+// versionPicker lists earlier versions of the workflow being built, to
+// compare the workflow against one of them or restore it
+type versionPicker struct {
+	title    string
+	versions []workflowVersion // Newest first
+	load     func(id string) (*workflow.Workflow, error)
+	remove   func(id string) error // nil when versions cannot be deleted
+	selected int
+	diff     []string // Diff of the selected version, while it is shown
+	scroll   int      // First visible diff line
+}
+
+// openSnapshots shows the named snapshots of the workflow being built
+func (v *WorkflowBuilderView) openSnapshots() error {
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	name := v.builder.GetWorkflow().Name
+	snapshots, err := v.snapshots.List(name)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no snapshots of %s: save one with :snapshot <name>", name)
+	}
+
+	versions := make([]workflowVersion, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		versions = append(versions, workflowVersion{id: snapshot.Name, name: snapshot.Name, time: snapshot.CreatedAt})
+	}
+	v.picker = &versionPicker{
+		title:    "Snapshots of " + name,
+		versions: versions,
+		load:     func(id string) (*workflow.Workflow, error) { return v.snapshots.Load(name, id) },
+		remove:   func(id string) error { return v.snapshots.Delete(name, id) },
+	}
+	v.statusMsg = "Select a snapshot"
+	return nil
+}
+
+// openHistory shows the git history of the workflow file being built
+func (v *WorkflowBuilderView) openHistory() error {
+	repo, err := v.gitRepository()
+	if err != nil {
+		return err
+	}
+	revisions, err := repo.History(v.workflowPath)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		return fmt.Errorf("%s has no history yet: save it with :w", filepath.Base(v.workflowPath))
+	}
+
+	versions := make([]workflowVersion, 0, len(revisions))
+	for _, revision := range revisions {
+		versions = append(versions, workflowVersion{
+			id:     revision.Hash,
+			name:   revision.ShortHash(),
+			detail: revision.Message,
+			time:   revision.Time,
+		})
+	}
+	path := v.workflowPath
+	v.picker = &versionPicker{
+		title:    "History of " + filepath.Base(path),
+		versions: versions,
+		load:     func(id string) (*workflow.Workflow, error) { return repo.Revision(path, id) },
+	}
+	v.statusMsg = "Select a revision"
+	return nil
+}
+
+// saveSnapshot saves the workflow being built as a named snapshot
+func (v *WorkflowBuilderView) saveSnapshot(name string) error {
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	if _, err := v.snapshots.Save(v.builder.GetWorkflow(), name); err != nil {
+		return err
+	}
+	v.statusMsg = "Saved snapshot " + name
+	return nil
+}
+
+// snapshotNames lists the snapshots of the workflow being built
+func (v *WorkflowBuilderView) snapshotNames() []string {
+	if v.builder == nil {
+		return nil
+	}
+	snapshots, _ := v.snapshots.List(v.builder.GetWorkflow().Name)
+	names := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Name)
+	}
+	return names
+}
+
+// handleVersionKey handles keys while the version picker is open. In the
+// list, j/k select a version; in a diff they scroll.
+func (v *WorkflowBuilderView) handleVersionKey(event KeyEvent) error {
+	p := v.picker
+	switch {
+	case event.Key == 'j':
+		if p.diff != nil {
+			if p.scroll < len(p.diff)-1 {
+				p.scroll++
+			}
+		} else if p.selected < len(p.versions)-1 {
+			p.selected++
+		}
+	case event.Key == 'k':
+		if p.diff != nil {
+			if p.scroll > 0 {
+				p.scroll--
+			}
+		} else if p.selected > 0 {
+			p.selected--
+		}
+	case event.Key == 'd' || (event.IsSpecial && event.Special == "Enter" && p.diff == nil):
+		lines, err := v.versionDiff(p.versions[p.selected])
+		if err != nil {
+			v.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		p.diff, p.scroll = lines, 0
+	case event.Key == 'r':
+		if err := v.builder.checkWritable(); err != nil {
+			v.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		version := p.versions[p.selected]
+		wf, err := p.load(version.id)
+		if err == nil {
+			err = v.builder.RestoreWorkflow(wf)
+		}
+		if err != nil {
+			v.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		v.picker = nil
+		v.statusMsg = "Restored " + version.name + " (u to undo)"
+	case event.Key == 'x' && p.remove != nil && p.diff == nil:
+		version := p.versions[p.selected]
+		if err := p.remove(version.id); err != nil {
+			v.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		p.versions = append(p.versions[:p.selected], p.versions[p.selected+1:]...)
+		p.selected = min(p.selected, len(p.versions)-1)
+		v.statusMsg = "Deleted " + version.name
+		if len(p.versions) == 0 {
+			v.picker = nil
+		}
+	case event.Key == 'q' || (event.IsSpecial && event.Special == "Escape"):
+		if p.diff != nil {
+			p.diff = nil
+			return nil
+		}
+		v.picker = nil
+		v.statusMsg = "Ready"
+	}
+	return nil
+}
+
+// versionDiff describes what restoring a version would change in the
+// workflow being built
+func (v *WorkflowBuilderView) versionDiff(version workflowVersion) ([]string, error) {
+	wf, err := v.picker.load(version.id)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := workflow.Diff(v.builder.GetWorkflow(), wf)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{fmt.Sprintf("Restoring %s would change:", version.name)}
+	return append(lines, workflowDiffLines(diff)...), nil
+}
+
+// lines returns the picker's contents: the version list, or the diff of
+// the selected version from its scroll position
+func (p *versionPicker) lines() []string {
+	if p.diff != nil {
+		lines := []string{"[j/k] scroll  [r] restore  [Esc] back", ""}
+		return append(lines, p.diff[p.scroll:]...)
+	}
+
+	keys := "[j/k] select  [d/Enter] diff  [r] restore  [Esc] close"
+	if p.remove != nil {
+		keys = "[j/k] select  [d/Enter] diff  [r] restore  [x] delete  [Esc] close"
+	}
+	lines := []string{p.title, keys, ""}
+	for i, version := range p.versions {
+		prefix := "  "
+		if i == p.selected {
+			prefix = "> "
+		}
+		line := fmt.Sprintf("%s%-24s %s", prefix, version.name, version.time.Format("2006-01-02 15:04"))
+		if version.detail != "" {
+			line += "  " + version.detail
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// renderVersionPicker draws the picker over the canvas, between the title
+// and status bars
+func (v *WorkflowBuilderView) renderVersionPicker(screen *goterm.Screen, width, height int) {
+	highlight := -1
+	if v.picker.diff == nil {
+		highlight = 3 + v.picker.selected // After the title, keys and a blank line
+	}
+	renderOverlayLines(screen, width, height, v.picker.lines(), highlight)
+}
+
+// gitRepository returns the git repository the workflow file being built
+// is committed to, creating one in its directory if needed
+func (v *WorkflowBuilderView) gitRepository() (*storage.GitWorkflowRepository, error) {
+	if !v.tunables.GitWorkflows {
+		return nil, fmt.Errorf("git history is off: enable git_workflows in the config file")
+	}
+	if v.workflowPath == "" {
+		return nil, fmt.Errorf("no file name: open a workflow with :open <workflow>")
+	}
+	dir, err := filepath.Abs(filepath.Dir(v.workflowPath))
+	if err != nil {
+		return nil, err
+	}
+	if v.git == nil || v.git.Dir() != dir {
+		repo, err := storage.NewGitWorkflowRepository(dir)
+		if err != nil {
+			return nil, err
+		}
+		v.git = repo
+	}
+	return v.git, nil
+}
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/storage"
+)
+
+func TestWorkflowBuilderView_Snapshots(t *testing.T) {
+	view := NewWorkflowBuilderView()
+	view.snapshots = storage.NewSnapshotStore(t.TempDir())
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	registry := NewCommandRegistry()
+	if err := view.RegisterCommands(registry); err != nil {
+		t.Fatalf("RegisterCommands failed: %v", err)
+	}
+
+	if err := registry.Execute("snapshots"); err == nil {
+		t.Error("expected error opening the picker without snapshots")
+	}
+	if err := registry.Execute("snapshot ../bad"); err == nil {
+		t.Error("expected error for an invalid snapshot name")
+	}
+	if err := registry.Execute("snapshot before"); err != nil {
+		t.Fatalf("snapshot error = %v", err)
+	}
+	if got := registry.Complete("snapshot b"); len(got) != 1 || got[0] != "snapshot before" {
+		t.Errorf("Complete(snapshot b) = %q", got)
+	}
+
+	if err := view.builder.AddNodeAtPosition("Transform", Position{X: 30, Y: 2}); err != nil {
+		t.Fatalf("AddNodeAtPosition failed: %v", err)
+	}
+	if err := registry.Execute("snapshots"); err != nil {
+		t.Fatalf("snapshots error = %v", err)
+	}
+	if view.picker == nil || len(view.picker.versions) != 1 {
+		t.Fatalf("picker = %+v, want one snapshot", view.picker)
+	}
+
+	// The diff shows that restoring removes the new node
+	press := func(key rune) {
+		t.Helper()
+		if err := view.HandleKey(KeyEvent{Key: key}); err != nil {
+			t.Fatalf("HandleKey(%q) error = %v", key, err)
+		}
+	}
+	press('d')
+	if diff := strings.Join(view.picker.diff, "\n"); !strings.Contains(diff, "- transform-") {
+		t.Errorf("diff does not show the removed node:\n%s", diff)
+	}
+	if screen := renderViewText(t, view, 100, 30); !strings.Contains(screen, "Restoring before would change") {
+		t.Errorf("picker not rendered:\n%s", screen)
+	}
+
+	press('r')
+	if view.picker != nil {
+		t.Error("expected restore to close the picker")
+	}
+	if n := len(view.builder.GetWorkflow().Nodes); n != 1 {
+		t.Fatalf("restored workflow has %d nodes, want 1", n)
+	}
+	if err := view.builder.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if n := len(view.builder.GetWorkflow().Nodes); n != 2 {
+		t.Errorf("undo of restore left %d nodes, want 2", n)
+	}
+
+	// Deleting the last snapshot closes the picker
+	if err := registry.Execute("snapshots"); err != nil {
+		t.Fatalf("snapshots error = %v", err)
+	}
+	press('x')
+	if view.picker != nil || len(view.snapshotNames()) != 0 {
+		t.Error("expected the snapshot to be deleted and the picker closed")
+	}
+}
+
+func TestWorkflowBuilderView_GitHistory(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir()) // Saves are audited
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	data, err := os.ReadFile(filepath.Join("..", "..", "examples", "simple-pipeline.yaml"))
+	if err != nil {
+		t.Skipf("example workflow not available: %v", err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pipeline.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewWorkflowBuilderView()
+	view.SetWorkflow(path)
+	if err := view.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	registry := NewCommandRegistry()
+	if err := view.RegisterCommands(registry); err != nil {
+		t.Fatalf("RegisterCommands failed: %v", err)
+	}
+	if err := registry.Execute("history"); err == nil {
+		t.Error("expected error showing history with git_workflows off")
+	}
+
+	view.tunables.GitWorkflows = true
+	if err := registry.Execute("history"); err == nil {
+		t.Error("expected error showing history before the first commit")
+	}
+	if err := view.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if !strings.HasSuffix(view.statusMsg, "and committed") {
+		t.Errorf("status = %q, want the save committed", view.statusMsg)
+	}
+	description := view.builder.GetWorkflow().Description
+	view.builder.GetWorkflow().Description = "Changed"
+	if err := registry.Execute("commit Describe the pipeline"); err != nil {
+		t.Fatalf("commit error = %v", err)
+	}
+
+	if err := registry.Execute("history"); err != nil {
+		t.Fatalf("history error = %v", err)
+	}
+	versions := view.picker.versions
+	if len(versions) != 2 || versions[0].detail != "Describe the pipeline" || versions[1].detail != "Add pipeline.yaml" {
+		t.Fatalf("history = %+v", versions)
+	}
+	if strings.Contains(strings.Join(view.picker.lines(), "\n"), "[x] delete") {
+		t.Error("revisions cannot be deleted")
+	}
+
+	// Check out the first revision into the builder
+	for _, key := range []rune{'j', 'r'} {
+		if err := view.HandleKey(KeyEvent{Key: key}); err != nil {
+			t.Fatalf("HandleKey(%q) error = %v", key, err)
+		}
+	}
+	if got := view.builder.GetWorkflow().Description; got != description {
+		t.Errorf("checked out description = %q, want %q", got, description)
+	}
+	if !view.builder.modified {
+		t.Error("expected the checked out revision to be unsaved")
+	}
+}
@@ -0,0 +1,288 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/audit"
+	execpkg "github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goterm"
+)
+
+// recordAudit adds an entry for the user running the TUI to the audit log.
+// A failure is shown as a warning, since the audited operation succeeded.
+func recordAudit(action audit.Action, target, detail string) {
+	if err := audit.Default().Record(audit.LocalActor(), action, target, detail); err != nil {
+		Notifications().Warn("audit", err.Error())
+	}
+}
+
+// auditRunEvents returns an engine event handler recording who started
+// and cancelled the executions of the named workflow
+func auditRunEvents(workflowName string) func(execpkg.ExecutionEvent) {
+	return func(event execpkg.ExecutionEvent) {
+		switch event.Type {
+		case execpkg.EventExecutionStarted:
+			recordAudit(audit.ExecutionStarted, event.ExecutionID.String(), workflowName)
+		case execpkg.EventExecutionCancelled:
+			recordAudit(audit.ExecutionCancelled, event.ExecutionID.String(), workflowName)
+		}
+	}
+}
+
+// AuditView lists the audit log, newest first: who changed which workflow
+// or server and who started which execution. '/' filters the entries by
+// fuzzy search over actor, action, target and detail.
+type AuditView struct {
+	name         string
+	active       bool
+	log          *audit.Log
+	entries      []audit.Entry
+	matches      []int  // Indexes of the entries matching the query, newest first
+	query        []rune // Fuzzy search query
+	searching    bool   // Whether the query is being typed
+	selectedIdx  int    // Index into matches
+	statusMsg    string // Status message to display
+	initialized  bool
+	width        int          // View width
+	height       int          // View height
+	viewSwitcher ViewSwitcher // For switching to other views
+}
+
+// NewAuditView creates a new audit view of the default audit log
+func NewAuditView() *AuditView {
+	return &AuditView{
+		name: "audit",
+		log:  audit.Default(),
+	}
+}
+
+// Name returns the unique identifier for this view
+func (v *AuditView) Name() string {
+	return v.name
+}
+
+// SetViewSwitcher stores the ViewSwitcher for requesting view changes
+func (v *AuditView) SetViewSwitcher(switcher ViewSwitcher) {
+	v.viewSwitcher = switcher
+}
+
+// Init loads the audit log on first use
+func (v *AuditView) Init() error {
+	if v.initialized {
+		return nil // already initialized, preserve state
+	}
+	v.reload()
+	v.initialized = true
+	return nil
+}
+
+// reload reads the audit log again, keeping the query
+func (v *AuditView) reload() {
+	entries, err := v.log.Query(audit.Filter{})
+	v.entries = entries
+	v.filter()
+	switch {
+	case err != nil:
+		v.statusMsg = "Error loading audit log: " + err.Error()
+	case len(v.entries) == 0:
+		v.statusMsg = "No audit entries yet"
+	default:
+		v.statusMsg = fmt.Sprintf("%d audit entries (/: search, r: reload)", len(v.entries))
+	}
+}
+
+// filter lists the entries matching the query, keeping the newest first
+// among equally good matches; all of them without a query
+func (v *AuditView) filter() {
+	v.matches = v.matches[:0]
+	v.selectedIdx = 0
+	query := string(v.query)
+	scores := make(map[int]int)
+	for i, entry := range v.entries {
+		if query == "" {
+			v.matches = append(v.matches, i)
+			continue
+		}
+		best, found := 0, false
+		for _, text := range []string{entry.Actor, string(entry.Action), entry.Target, entry.Detail} {
+			if score, _, ok := FuzzyMatch(query, text); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if found {
+			scores[i] = best
+			v.matches = append(v.matches, i)
+		}
+	}
+	sort.SliceStable(v.matches, func(a, b int) bool {
+		return scores[v.matches[a]] > scores[v.matches[b]]
+	})
+}
+
+// Cleanup releases resources when view is deactivated
+func (v *AuditView) Cleanup() error {
+	// Preserve state for when we return to this view
+	return nil
+}
+
+// CapturingText reports whether a search query is being typed
+func (v *AuditView) CapturingText() bool {
+	return v.searching
+}
+
+// HandleKey processes keyboard input events
+func (v *AuditView) HandleKey(event KeyEvent) error {
+	if v.searching {
+		v.handleSearchKey(event)
+		return nil
+	}
+
+	switch {
+	case event.Key == 'j' || (event.IsSpecial && event.Special == "Down"):
+		if v.selectedIdx < len(v.matches)-1 {
+			v.selectedIdx++
+		}
+	case event.Key == 'k' || (event.IsSpecial && event.Special == "Up"):
+		if v.selectedIdx > 0 {
+			v.selectedIdx--
+		}
+	case event.Key == 'g':
+		v.selectedIdx = 0
+	case event.Key == 'G':
+		if len(v.matches) > 0 {
+			v.selectedIdx = len(v.matches) - 1
+		}
+	case event.Key == '/':
+		v.searching = true
+		v.statusMsg = "Search: type to filter (Enter: keep, Esc: clear)"
+	case event.IsSpecial && event.Special == "Escape":
+		if len(v.query) > 0 {
+			v.query = v.query[:0]
+			v.filter()
+			v.statusMsg = "Search cleared"
+		}
+	case event.Key == 'r':
+		v.reload()
+	}
+	return nil
+}
+
+// handleSearchKey edits the search query; the list follows it as it is
+// typed
+func (v *AuditView) handleSearchKey(event KeyEvent) {
+	switch {
+	case event.IsSpecial && event.Special == "Enter":
+		v.searching = false
+		v.statusMsg = fmt.Sprintf("%d matching entries (Esc: clear search)", len(v.matches))
+		return
+	case event.IsSpecial && event.Special == "Escape", event.Ctrl && event.Key == 'c':
+		v.searching = false
+		v.query = v.query[:0]
+		v.statusMsg = "Search cleared"
+	case event.IsSpecial && event.Special == "Backspace":
+		if len(v.query) > 0 {
+			v.query = v.query[:len(v.query)-1]
+		}
+	case event.Ctrl && event.Key == 'u':
+		v.query = v.query[:0]
+	case !event.IsSpecial && !event.Ctrl && !event.Alt && event.Key != 0:
+		v.query = append(v.query, event.Key)
+	default:
+		return
+	}
+	v.filter()
+}
+
+// RegisterCommands registers :audit, which shows the audit log reloaded
+func (v *AuditView) RegisterCommands(registry *CommandRegistry) error {
+	return registry.Register(Command{
+		Name:        "audit",
+		Usage:       "[query]",
+		Description: "Show who changed workflows and servers and who ran executions",
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			v.query = []rune(strings.Join(args, " "))
+			v.reload()
+			v.initialized = true
+			if v.viewSwitcher != nil && !v.active {
+				return v.viewSwitcher.SwitchToView(v.name)
+			}
+			return nil
+		},
+	})
+}
+
+// Render draws the audit entries, with the selected entry's detail in full
+// below them
+func (v *AuditView) Render(screen *goterm.Screen) error {
+	width, height := screen.Size()
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+
+	screen.Clear()
+
+	title := "Audit Log"
+	if len(v.query) > 0 || v.searching {
+		title += fmt.Sprintf("  /%s", string(v.query))
+		if v.searching {
+			title += "_"
+		}
+		title += fmt.Sprintf("  (%d of %d)", len(v.matches), len(v.entries))
+	}
+	screen.DrawText(0, 0, title, fg, bg, goterm.StyleBold)
+
+	y := 2
+	header := fmt.Sprintf("  %-19s  %-16s  %-19s  %-24s  %s", "Time", "Actor", "Action", "Target", "Detail")
+	screen.DrawText(0, y, fitToWidth(header, width), theme.Muted, bg, goterm.StyleBold)
+	y++
+
+	// Keep the selection in view, leaving room for the detail line
+	rows := max(height-y-3, 1)
+	start := 0
+	if v.selectedIdx >= rows {
+		start = v.selectedIdx - rows + 1
+	}
+	for i := start; i < len(v.matches) && y < height-3; i++ {
+		entry := v.entries[v.matches[i]]
+		line := fmt.Sprintf("  %-19s  %-16s  %-19s  %-24s  %s", entry.Time.Local().Format("2006-01-02 15:04:05"),
+			fitToWidth(entry.Actor, 16), entry.Action, fitToWidth(entry.Target, 24), entry.Detail)
+		style := goterm.StyleNone
+		if i == v.selectedIdx {
+			line = ">" + line[1:]
+			style = goterm.StyleReverse
+		}
+		screen.DrawText(0, y, fitToWidth(line, width), fg, bg, style)
+		y++
+	}
+
+	if v.selectedIdx < len(v.matches) {
+		entry := v.entries[v.matches[v.selectedIdx]]
+		detail := fmt.Sprintf("%s %s %s", entry.Actor, entry.Action, entry.Target)
+		if entry.Detail != "" {
+			detail += ": " + entry.Detail
+		}
+		screen.DrawText(0, height-2, fitToWidth(detail, width), theme.Muted, bg, goterm.StyleDim)
+	}
+
+	drawStatusBar(screen, height-1, width, "Status: "+v.statusMsg, fg, goterm.StyleNone)
+	return nil
+}
+
+// IsActive returns whether this view is currently active
+func (v *AuditView) IsActive() bool {
+	return v.active
+}
+
+// SetActive updates the active state of the view
+func (v *AuditView) SetActive(active bool) {
+	v.active = active
+}
+
+// SetBounds sets the view dimensions
+func (v *AuditView) SetBounds(width, height int) {
+	v.width = width
+	v.height = height
+}
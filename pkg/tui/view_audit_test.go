@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/audit"
+	"github.com/dshills/goterm"
+)
+
+func TestAuditView(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir())
+	if err := audit.Default().Record("ci", audit.ServerAdded, "db", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving in the builder is audited as the local user
+	dir := t.TempDir()
+	writeBrowserWorkflow(t, dir, "greet.yaml", newRunTestWorkflow(t), time.Now())
+	builderView := NewWorkflowBuilderView()
+	defer builderView.stopWatching()
+	if err := builderView.OpenFile(filepath.Join(dir, "greet.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := builderView.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewAuditView()
+	if err := view.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if len(view.matches) != 2 {
+		t.Fatalf("listed %d entries, want 2: %s", len(view.matches), view.statusMsg)
+	}
+	newest := view.entries[view.matches[0]]
+	if newest.Action != audit.WorkflowSaved || newest.Target != "greet" || newest.Actor != audit.LocalActor() {
+		t.Errorf("newest entry = %+v, want the builder save", newest)
+	}
+
+	// '/' filters by actor, action, target and detail
+	for _, key := range []KeyEvent{{Key: '/'}, {Key: 'c'}, {Key: 'i'}, {IsSpecial: true, Special: "Enter"}} {
+		if err := view.HandleKey(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(view.matches) != 1 || view.entries[view.matches[0]].Actor != "ci" {
+		t.Errorf("search ci matched %v", view.matches)
+	}
+
+	screen := goterm.NewScreen(120, 20)
+	if err := view.Render(screen); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(view.statusMsg, "1 matching") {
+		t.Errorf("status = %q", view.statusMsg)
+	}
+}
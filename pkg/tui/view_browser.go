@@ -0,0 +1,461 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// browserEntry is a saved workflow as listed by the browser
+type browserEntry struct {
+	path        string // Relative to the workflows directory
+	name        string
+	description string
+	nodes       int
+	modified    time.Time
+	errors      int
+	warnings    int
+	workflow    *workflow.Workflow // Nil if the file cannot be parsed
+	err         error              // Why the file cannot be parsed
+}
+
+// status summarizes the entry's lint findings, or why it cannot be parsed
+func (e browserEntry) status() string {
+	switch {
+	case e.err != nil:
+		return "✗ unreadable"
+	case e.errors > 0:
+		return fmt.Sprintf("✗ %d %s", e.errors, plural(e.errors, "error", "errors"))
+	case e.warnings > 0:
+		return fmt.Sprintf("! %d %s", e.warnings, plural(e.warnings, "warning", "warnings"))
+	}
+	return "✓ valid"
+}
+
+// plural returns one or many depending on n
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// loadBrowserEntries parses and lints the saved workflows under dir, most
+// recently modified first
+func loadBrowserEntries(dir string) ([]browserEntry, error) {
+	files, err := listWorkflowFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]browserEntry, 0, len(files))
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		entry := browserEntry{path: file, name: strings.TrimSuffix(file, filepath.Ext(file))}
+		if info, err := os.Stat(path); err == nil {
+			entry.modified = info.ModTime()
+		}
+		wf, err := workflow.ParseFile(path)
+		if err != nil {
+			entry.err = err
+			entries = append(entries, entry)
+			continue
+		}
+		entry.workflow = wf
+		entry.name = wf.Name
+		entry.description = wf.Description
+		entry.nodes = len(wf.Nodes)
+		findings, _ := workflow.Lint(wf, workflow.LintOptions{}) // Default options are valid
+		for _, finding := range findings {
+			if finding.Severity == workflow.LintError {
+				entry.errors++
+			} else {
+				entry.warnings++
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].modified.After(entries[j].modified)
+	})
+	return entries, nil
+}
+
+// WorkflowBrowserView lists the saved workflows with their name,
+// description, node count, last modification and validation status. '/'
+// filters the list by fuzzy search, the selected workflow's graph is
+// previewed beside it, and Enter opens it in the builder.
+type WorkflowBrowserView struct {
+	name         string
+	active       bool
+	entries      []browserEntry
+	matches      []int  // Indexes of the entries matching the query, best first
+	query        []rune // Fuzzy search query
+	searching    bool   // Whether the query is being typed
+	selectedIdx  int    // Index into matches
+	statusMsg    string // Status message to display
+	initialized  bool
+	width        int          // View width
+	height       int          // View height
+	viewSwitcher ViewSwitcher // For switching to other views
+	workflowsDir string       // Directory containing workflows
+	openFile     func(path string) error
+}
+
+// NewWorkflowBrowserView creates a new workflow browser view
+func NewWorkflowBrowserView() *WorkflowBrowserView {
+	return &WorkflowBrowserView{
+		name:         "browser",
+		workflowsDir: defaultWorkflowsDir(),
+	}
+}
+
+// Name returns the unique identifier for this view
+func (v *WorkflowBrowserView) Name() string {
+	return v.name
+}
+
+// SetViewSwitcher stores the ViewSwitcher for requesting view changes
+func (v *WorkflowBrowserView) SetViewSwitcher(switcher ViewSwitcher) {
+	v.viewSwitcher = switcher
+}
+
+// SetOpener sets how a chosen workflow file is opened in the builder
+func (v *WorkflowBrowserView) SetOpener(openFile func(path string) error) {
+	v.openFile = openFile
+}
+
+// Init loads the saved workflows on first use
+func (v *WorkflowBrowserView) Init() error {
+	if v.initialized {
+		return nil // already initialized, preserve state
+	}
+	v.reload()
+	v.initialized = true
+	return nil
+}
+
+// reload parses the saved workflows again, keeping the query
+func (v *WorkflowBrowserView) reload() {
+	entries, err := loadBrowserEntries(v.workflowsDir)
+	v.entries = entries
+	v.filter()
+	switch {
+	case err != nil:
+		v.statusMsg = "Error loading workflows: " + err.Error()
+	case len(v.entries) == 0:
+		v.statusMsg = "No workflows found. Create one with 'goflow init <name>'"
+	default:
+		v.statusMsg = fmt.Sprintf("%d workflows (/: search, Enter: open, r: reload)", len(v.entries))
+	}
+}
+
+// filter lists the entries matching the query by name, path and
+// description, best match first; all of them without a query
+func (v *WorkflowBrowserView) filter() {
+	v.matches = v.matches[:0]
+	v.selectedIdx = 0
+	query := string(v.query)
+	if query == "" {
+		for i := range v.entries {
+			v.matches = append(v.matches, i)
+		}
+		return
+	}
+
+	scores := make(map[int]int)
+	for i, entry := range v.entries {
+		best, found := 0, false
+		for _, text := range []string{entry.name, entry.path, entry.description} {
+			if score, _, ok := FuzzyMatch(query, text); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if found {
+			scores[i] = best
+			v.matches = append(v.matches, i)
+		}
+	}
+	sort.SliceStable(v.matches, func(a, b int) bool {
+		return scores[v.matches[a]] > scores[v.matches[b]]
+	})
+}
+
+// selected returns the selected entry, or nil if none match
+func (v *WorkflowBrowserView) selected() *browserEntry {
+	if v.selectedIdx >= len(v.matches) {
+		return nil
+	}
+	return &v.entries[v.matches[v.selectedIdx]]
+}
+
+// Cleanup releases resources when view is deactivated
+func (v *WorkflowBrowserView) Cleanup() error {
+	// Preserve state for when we return to this view
+	return nil
+}
+
+// CapturingText reports whether a search query is being typed
+func (v *WorkflowBrowserView) CapturingText() bool {
+	return v.searching
+}
+
+// HandleKey processes keyboard input events
+func (v *WorkflowBrowserView) HandleKey(event KeyEvent) error {
+	if v.searching {
+		v.handleSearchKey(event)
+		return nil
+	}
+
+	switch {
+	case event.Key == 'j' || (event.IsSpecial && event.Special == "Down"):
+		if v.selectedIdx < len(v.matches)-1 {
+			v.selectedIdx++
+		}
+	case event.Key == 'k' || (event.IsSpecial && event.Special == "Up"):
+		if v.selectedIdx > 0 {
+			v.selectedIdx--
+		}
+	case event.Key == 'g':
+		v.selectedIdx = 0
+	case event.Key == 'G':
+		if len(v.matches) > 0 {
+			v.selectedIdx = len(v.matches) - 1
+		}
+	case event.Key == '/':
+		v.searching = true
+		v.statusMsg = "Search: type to filter (Enter: keep, Esc: clear)"
+	case event.IsSpecial && event.Special == "Escape":
+		if len(v.query) > 0 {
+			v.query = v.query[:0]
+			v.filter()
+			v.statusMsg = "Search cleared"
+		}
+	case event.Key == 'r':
+		v.reload()
+	case event.IsSpecial && event.Special == "Enter":
+		v.open()
+	}
+	return nil
+}
+
+// handleSearchKey edits the search query; the list follows it as it is
+// typed
+func (v *WorkflowBrowserView) handleSearchKey(event KeyEvent) {
+	switch {
+	case event.IsSpecial && event.Special == "Enter":
+		v.searching = false
+		v.statusMsg = fmt.Sprintf("%d matching workflows (Enter: open, Esc: clear search)", len(v.matches))
+		return
+	case event.IsSpecial && event.Special == "Escape", event.Ctrl && event.Key == 'c':
+		v.searching = false
+		v.query = v.query[:0]
+		v.statusMsg = "Search cleared"
+	case event.IsSpecial && (event.Special == "Down" || event.Special == "Tab"):
+		if v.selectedIdx < len(v.matches)-1 {
+			v.selectedIdx++
+		}
+		return
+	case event.IsSpecial && event.Special == "Up":
+		if v.selectedIdx > 0 {
+			v.selectedIdx--
+		}
+		return
+	case event.IsSpecial && event.Special == "Backspace":
+		if len(v.query) > 0 {
+			v.query = v.query[:len(v.query)-1]
+		}
+	case event.Ctrl && event.Key == 'u':
+		v.query = v.query[:0]
+	case !event.IsSpecial && !event.Ctrl && !event.Alt && event.Key != 0:
+		v.query = append(v.query, event.Key)
+	default:
+		return
+	}
+	v.filter()
+}
+
+// open opens the selected workflow in the builder
+func (v *WorkflowBrowserView) open() {
+	entry := v.selected()
+	if entry == nil {
+		v.statusMsg = "No workflow to open"
+		return
+	}
+	if entry.err != nil {
+		v.statusMsg = fmt.Sprintf("Cannot open %s: %v", entry.path, entry.err)
+		return
+	}
+	if v.openFile == nil {
+		v.statusMsg = "Cannot open workflows: no builder configured"
+		return
+	}
+	if err := v.openFile(filepath.Join(v.workflowsDir, entry.path)); err != nil {
+		v.statusMsg = "Error opening workflow: " + err.Error()
+		return
+	}
+	v.statusMsg = "Opened " + entry.path
+}
+
+// RegisterCommands registers :browse, which shows the browser with the
+// saved workflows reloaded
+func (v *WorkflowBrowserView) RegisterCommands(registry *CommandRegistry) error {
+	return registry.Register(Command{
+		Name:        "browse",
+		Aliases:     []string{"workflows"},
+		Usage:       "[query]",
+		Description: "Browse the saved workflows, filtered by a fuzzy query",
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			v.query = []rune(strings.Join(args, " "))
+			v.reload()
+			v.initialized = true
+			if v.viewSwitcher != nil && !v.active {
+				return v.viewSwitcher.SwitchToView(v.name)
+			}
+			return nil
+		},
+	})
+}
+
+// Render draws the workflow list, with the selected workflow's details
+// and graph in a preview pane on the right
+func (v *WorkflowBrowserView) Render(screen *goterm.Screen) error {
+	width, height := screen.Size()
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+
+	screen.Clear()
+
+	title := "Workflows"
+	if len(v.query) > 0 || v.searching {
+		title += fmt.Sprintf("  /%s", string(v.query))
+		if v.searching {
+			title += "_"
+		}
+		title += fmt.Sprintf("  (%d of %d)", len(v.matches), len(v.entries))
+	}
+	screen.DrawText(0, 0, title, fg, bg, goterm.StyleBold)
+
+	listWidth := width * 6 / 10
+	previewX := listWidth + 1
+	nameWidth := max(listWidth-40, 12)
+
+	y := 2
+	header := fmt.Sprintf("  %-*s %5s  %-16s  %s", nameWidth, "Name", "Nodes", "Modified", "Status")
+	screen.DrawText(0, y, fitToWidth(header, listWidth), theme.Muted, bg, goterm.StyleBold)
+	y++
+
+	// Keep the selection in view
+	rows := max(height-y-1, 1)
+	start := 0
+	if v.selectedIdx >= rows {
+		start = v.selectedIdx - rows + 1
+	}
+	for i := start; i < len(v.matches) && y < height-1; i++ {
+		entry := v.entries[v.matches[i]]
+		name := entry.name
+		if len(name) > nameWidth {
+			name = name[:nameWidth-1] + "…"
+		}
+		modified := "-"
+		if !entry.modified.IsZero() {
+			modified = entry.modified.Format("2006-01-02 15:04")
+		}
+		line := fmt.Sprintf("  %-*s %5d  %-16s  %s", nameWidth, name, entry.nodes, modified, entry.status())
+
+		style := goterm.StyleNone
+		if i == v.selectedIdx {
+			line = ">" + line[1:]
+			style = goterm.StyleReverse
+		}
+		screen.DrawText(0, y, fitToWidth(line, listWidth), fg, bg, style)
+		y++
+	}
+
+	if entry := v.selected(); entry != nil && previewX < width-10 {
+		v.renderPreview(screen, *entry, previewX, 2, width-previewX, height-3)
+	}
+
+	drawStatusBar(screen, height-1, width, "Status: "+v.statusMsg, fg, goterm.StyleNone)
+	return nil
+}
+
+// renderPreview draws an entry's details and graph in the given area
+func (v *WorkflowBrowserView) renderPreview(screen *goterm.Screen, entry browserEntry, x, y, width, height int) {
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
+	bottom := y + height
+
+	screen.DrawText(x, y, fitToWidth(entry.name, width), fg, bg, goterm.StyleBold)
+	y++
+	screen.DrawText(x, y, fitToWidth(entry.path, width), theme.Muted, bg, goterm.StyleDim)
+	y++
+	if entry.err != nil {
+		for _, line := range wrapWords(entry.err.Error(), width) {
+			if y >= bottom {
+				return
+			}
+			screen.DrawText(x, y, line, theme.Error, bg, goterm.StyleNone)
+			y++
+		}
+		return
+	}
+	for _, line := range wrapWords(entry.description, width) {
+		if y >= bottom-3 {
+			break
+		}
+		screen.DrawText(x, y, line, fg, bg, goterm.StyleNone)
+		y++
+	}
+	y++
+
+	if bottom-y >= 3 && width >= 20 {
+		NewWorkflowGraphPanel(x, y, width, bottom-y, entry.workflow).Render(screen, false)
+	}
+}
+
+// wrapWords wraps text into lines of at most width characters, breaking
+// between words
+func wrapWords(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, fitToWidth(line, width))
+	}
+	return lines
+}
+
+// IsActive returns whether this view is currently active
+func (v *WorkflowBrowserView) IsActive() bool {
+	return v.active
+}
+
+// SetActive updates the active state of the view
+func (v *WorkflowBrowserView) SetActive(active bool) {
+	v.active = active
+}
+
+// SetBounds sets the view dimensions
+func (v *WorkflowBrowserView) SetBounds(width, height int) {
+	v.width = width
+	v.height = height
+}
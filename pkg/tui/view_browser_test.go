@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// writeBrowserWorkflow saves wf under dir as file, modified at the given
+// time
+func writeBrowserWorkflow(t *testing.T, dir, file string, wf *workflow.Workflow, modified time.Time) {
+	t.Helper()
+	data, err := workflow.ToYAML(wf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorkflowBrowserView(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	greet := newRunTestWorkflow(t)
+	greet.Description = "Say hello to everyone"
+	writeBrowserWorkflow(t, dir, "greet.yaml", greet, now.Add(-time.Hour))
+
+	stray := newRunTestWorkflow(t)
+	stray.Name = "data-pipeline"
+	if err := stray.AddNode(&workflow.TransformNode{ID: "stray", InputVariable: "x", Expression: "$", OutputVariable: "y"}); err != nil {
+		t.Fatal(err)
+	}
+	writeBrowserWorkflow(t, dir, "pipeline.yaml", stray, now)
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("nodes: [oops"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(dir, "broken.yaml"), now.Add(-2*time.Hour), now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewWorkflowBrowserView()
+	view.workflowsDir = dir
+	var opened []string
+	view.SetOpener(func(path string) error {
+		opened = append(opened, path)
+		return nil
+	})
+	if err := view.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Most recently modified first, with metadata and validation status
+	var names, statuses []string
+	for _, index := range view.matches {
+		names = append(names, view.entries[index].name)
+		statuses = append(statuses, view.entries[index].status())
+	}
+	if want := []string{"data-pipeline", "greet", "broken"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+	if statuses[0] == "✓ valid" || statuses[1] != "✓ valid" || statuses[2] != "✗ unreadable" {
+		t.Errorf("statuses = %v", statuses)
+	}
+	if view.entries[view.matches[1]].nodes != 2 || view.entries[view.matches[1]].description != greet.Description {
+		t.Errorf("greet entry = %+v", view.entries[view.matches[1]])
+	}
+
+	// Rendering includes the preview of the selected workflow
+	if err := view.Render(goterm.NewScreen(120, 30)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The search follows the query as it is typed, and matches descriptions
+	if err := view.HandleKey(KeyEvent{Key: '/'}); err != nil {
+		t.Fatal(err)
+	}
+	if !view.CapturingText() {
+		t.Fatal("expected the search to capture keys")
+	}
+	for _, key := range "hello" {
+		if err := view.HandleKey(KeyEvent{Key: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(view.matches) != 1 || view.selected().name != "greet" {
+		t.Fatalf("matches for hello = %v", view.matches)
+	}
+	for _, name := range []string{"Enter", "Enter"} {
+		if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{filepath.Join(dir, "greet.yaml")}; !reflect.DeepEqual(opened, want) {
+		t.Errorf("opened %v, want %v", opened, want)
+	}
+
+	// Escape clears the search; unreadable workflows are not opened
+	if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(view.matches) != 3 {
+		t.Fatalf("matches after clearing = %v", view.matches)
+	}
+	_ = view.HandleKey(KeyEvent{Key: 'G'})
+	_ = view.HandleKey(KeyEvent{IsSpecial: true, Special: "Enter"})
+	if len(opened) != 1 {
+		t.Errorf("expected the unreadable workflow not to open, opened %v", opened)
+	}
+}
@@ -1,9 +1,18 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dshills/goflow/pkg/audit"
+	"github.com/dshills/goflow/pkg/config"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
 	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/dshills/goterm"
 )
@@ -20,15 +29,36 @@ type WorkflowBuilderView struct {
 	height       int          // View height
 	viewSwitcher ViewSwitcher // For switching to other views
 	workflowPath string       // Path to the workflow file being edited
+	workflowsDir string       // Directory :open resolves workflow names in
+	undoDir      string       // Directory undo histories are persisted in
+	snapshots    *storage.SnapshotStore
+	samples      *storage.SampleStore           // Variables of last runs, for expression previews
+	toolSamples  *storage.ToolSampleStore       // Recent tool outputs, for field suggestions
+	sample       string                         // Sample variables entered with :sample, as JSON
+	git          *storage.GitWorkflowRepository // Repository of the workflow's directory, once used
+	picker       *versionPicker                 // Open version picker, if any
+	watcher      *fileWatcher                   // Watches the workflow file, if any
+	diskData     []byte                         // The file's contents when last loaded or saved
+	conflict     *fileConflict                  // Change on disk awaiting a decision, if any
+	search       *searchPrompt                  // Search query being typed, if any
+	servers      mcpserver.ServerRepository     // Completes server IDs and tool names, if set
+	onLoad       func(path string)              // Called with each workflow file loaded, if set
+	tunables     config.Tunables
 }
 
 // NewWorkflowBuilderView creates a new workflow builder view
 func NewWorkflowBuilderView() *WorkflowBuilderView {
 	return &WorkflowBuilderView{
-		name:        "builder",
-		active:      false,
-		statusMsg:   "Ready",
-		initialized: false,
+		name:         "builder",
+		active:       false,
+		statusMsg:    "Ready",
+		initialized:  false,
+		workflowsDir: defaultWorkflowsDir(),
+		undoDir:      defaultUndoHistoryDir(),
+		snapshots:    storage.NewSnapshotStore(storage.DefaultSnapshotsDir()),
+		samples:      storage.NewSampleStore(storage.DefaultSamplesDir()),
+		toolSamples:  storage.NewToolSampleStore(storage.DefaultToolSamplesDir()),
+		tunables:     config.DefaultTunables(),
 	}
 }
 
@@ -37,6 +67,30 @@ func (v *WorkflowBuilderView) Name() string {
 	return v.name
 }
 
+// SetServerRegistry sets the registry server IDs and tool names are
+// completed from in the property panel
+func (v *WorkflowBuilderView) SetServerRegistry(servers mcpserver.ServerRepository) {
+	v.servers = servers
+	if v.builder != nil {
+		v.builder.SetServerRegistry(servers)
+	}
+}
+
+// SetOnLoad sets a function called with the path of each workflow file the
+// builder loads
+func (v *WorkflowBuilderView) SetOnLoad(onLoad func(path string)) {
+	v.onLoad = onLoad
+}
+
+// CapturingText reports whether a property value or search query is being
+// typed, so the app passes every key to the view
+func (v *WorkflowBuilderView) CapturingText() bool {
+	if v.search != nil {
+		return true
+	}
+	return v.builder != nil && v.builder.mode == "edit" && v.builder.propertyPanel.IsEditing()
+}
+
 // SetViewSwitcher stores the ViewSwitcher for requesting view changes
 func (v *WorkflowBuilderView) SetViewSwitcher(switcher ViewSwitcher) {
 	v.viewSwitcher = switcher
@@ -66,7 +120,15 @@ func (v *WorkflowBuilderView) Init() error {
 		}
 
 		v.builder = builder
-		v.statusMsg = "New workflow created"
+		v.builder.SetServerRegistry(v.servers)
+		v.builder.SetSampleStore(v.samples)
+		v.builder.SetToolSampleStore(v.toolSamples)
+		_ = v.builder.SetSampleJSON(v.sample) // Checked when it was set
+		v.picker = nil
+		v.conflict, v.diskData = nil, nil
+		v.stopWatching()
+		v.ApplyTunables(v.tunables)
+		v.statusMsg = "New workflow created" + v.readOnlyNote()
 		v.initialized = true
 		return nil
 	}
@@ -78,8 +140,17 @@ func (v *WorkflowBuilderView) Init() error {
 		_ = os.Unsetenv("GOFLOW_CURRENT_WORKFLOW") // Best effort; ignore error
 	}
 
-	// Load workflow from file
-	wf, err := workflow.ParseFile(v.workflowPath)
+	// Load workflow from file. Parse migrates files saved with an older
+	// schema in memory; the file is upgraded, with a backup, when saved.
+	data, err := os.ReadFile(v.workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+	version, err := workflow.SchemaVersion(data)
+	if err != nil {
+		return fmt.Errorf("failed to load workflow: %w", err)
+	}
+	wf, err := workflow.Parse(data)
 	if err != nil {
 		return fmt.Errorf("failed to load workflow: %w", err)
 	}
@@ -91,9 +162,36 @@ func (v *WorkflowBuilderView) Init() error {
 	}
 
 	v.builder = builder
+	v.builder.SetServerRegistry(v.servers)
+	v.builder.SetSampleStore(v.samples)
+	v.builder.SetToolSampleStore(v.toolSamples)
+	_ = v.builder.SetSampleJSON(v.sample) // Checked when it was set
+	v.picker = nil
+	v.conflict, v.diskData = nil, data
+	v.ApplyTunables(v.tunables)
 	v.statusMsg = "Workflow loaded"
+	if version < workflow.CurrentSchemaVersion {
+		v.statusMsg = fmt.Sprintf("Workflow uses schema version %d; saving upgrades it to %d, keeping the original as %s.bak", version, workflow.CurrentSchemaVersion, filepath.Base(v.workflowPath))
+	}
 	v.initialized = true
 
+	// Restore the undo history saved with this version of the file
+	if v.tunables.PersistUndo {
+		path := undoHistoryPath(v.undoDir, v.workflowPath)
+		restored, err := builder.undoStack.LoadHistory(path, WorkflowChecksum(data))
+		switch {
+		case err != nil:
+			v.statusMsg = "Workflow loaded, undo history not restored: " + err.Error()
+		case restored:
+			v.statusMsg = "Workflow loaded with undo history"
+		}
+	}
+
+	if v.onLoad != nil {
+		v.onLoad(v.workflowPath)
+	}
+	v.statusMsg += v.readOnlyNote()
+	v.watch()
 	return nil
 }
 
@@ -109,6 +207,19 @@ func (v *WorkflowBuilderView) HandleKey(event KeyEvent) error {
 	if v.builder == nil {
 		return fmt.Errorf("builder not initialized")
 	}
+	if v.conflict != nil {
+		return v.handleConflictKey(event)
+	}
+	if v.picker != nil {
+		return v.handleVersionKey(event)
+	}
+	if v.search != nil {
+		return v.handleSearchKey(event)
+	}
+	if event.Key == '/' && !event.IsSpecial && !event.Ctrl && v.builder.mode == "normal" {
+		v.openSearch()
+		return nil
+	}
 
 	// Convert KeyEvent to string key for WorkflowBuilder
 	// This is a simplified conversion - the WorkflowBuilder expects string keys
@@ -141,8 +252,8 @@ func (v *WorkflowBuilderView) HandleKey(event KeyEvent) error {
 func (v *WorkflowBuilderView) Render(screen *goterm.Screen) error {
 	if v.builder == nil {
 		// Render error message if builder not initialized
-		fg := goterm.ColorDefault()
-		bg := goterm.ColorDefault()
+		fg := CurrentTheme().Foreground
+		bg := CurrentTheme().Background
 		screen.Clear()
 		screen.DrawText(0, 0, "Error: Workflow Builder not initialized", fg, bg, goterm.StyleBold)
 		return nil
@@ -169,27 +280,137 @@ func (v *WorkflowBuilderView) Render(screen *goterm.Screen) error {
 	// This will render canvas, panels, palette, etc.
 	if err := v.builder.Render(screen, width, height-1); err != nil {
 		// If rendering fails, show error message
-		screen.DrawText(0, 2, fmt.Sprintf("Render error: %v", err), goterm.ColorRGB(255, 100, 100), goterm.ColorDefault(), goterm.StyleNone)
+		screen.DrawText(0, 2, fmt.Sprintf("Render error: %v", err), CurrentTheme().Error, CurrentTheme().Background, goterm.StyleNone)
+	}
+
+	switch {
+	case v.conflict != nil:
+		renderOverlayLines(screen, width, height, v.conflict.lines, -1)
+	case v.picker != nil:
+		v.renderVersionPicker(screen, width, height)
 	}
 
 	// Title bar (drawn on top of everything)
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
 	title := fmt.Sprintf("Workflow Builder: %s [Mode: %s]",
 		v.builder.workflow.Name,
 		v.builder.mode)
+	if v.builder.ReadOnly() != "" {
+		title += " [read-only]"
+	}
 	screen.DrawText(0, 0, title, fg, bg, goterm.StyleBold)
 
 	// Status bar at bottom
 	statusLine := fmt.Sprintf("Status: %s | Keys: ? = help, q = quit, Tab = switch view", v.statusMsg)
+	if search := v.searchStatus(); search != "" {
+		statusLine = fmt.Sprintf("Status: %s | %s", v.statusMsg, search)
+	}
 	if v.builder.modified {
 		statusLine += " [modified]"
 	}
-	screen.DrawText(0, height-1, statusLine, fg, bg, goterm.StyleReverse)
+	drawStatusBar(screen, height-1, width, statusLine, fg, goterm.StyleReverse)
 
 	return nil
 }
 
+// readOnlyReason says why the workflow being edited is read-only, or ""
+// if it can be edited: the user's role, the workflow's own metadata, or a
+// file the user can't write, such as one mounted from a shared repository
+func (v *WorkflowBuilderView) readOnlyReason() string {
+	switch {
+	case v.tunables.Role == config.RoleViewer:
+		return "the config file sets role: viewer"
+	case v.builder != nil && v.builder.GetWorkflow().Metadata.ReadOnly:
+		return "the workflow's metadata sets read_only"
+	}
+	if v.workflowPath != "" {
+		file, err := os.OpenFile(v.workflowPath, os.O_WRONLY, 0)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "the file is not writable"
+		}
+		if err == nil {
+			_ = file.Close() // Opened only to check; nothing written
+		}
+	}
+	return ""
+}
+
+// readOnlyNote describes a read-only workflow for the status line after
+// loading it
+func (v *WorkflowBuilderView) readOnlyNote() string {
+	if reason := v.builder.ReadOnly(); reason != "" {
+		return ", read-only because " + reason
+	}
+	return ""
+}
+
+// renderOverlayLines draws lines over the canvas, between the title and
+// status bars, highlighting the line at index highlight (-1 for none)
+func renderOverlayLines(screen *goterm.Screen, width, height int, lines []string, highlight int) {
+	fg := CurrentTheme().Foreground
+	bg := CurrentTheme().Background
+	for y := 1; y < height-1; y++ {
+		line := ""
+		if i := y - 1; i < len(lines) {
+			line = lines[i]
+		}
+		if len(line) < width {
+			line += strings.Repeat(" ", width-len(line))
+		}
+		style := goterm.StyleNone
+		if y-1 == highlight {
+			style = goterm.StyleReverse
+		}
+		screen.DrawText(0, y, line, fg, bg, style)
+	}
+}
+
+// ApplyTunables applies validation debounce, autosave, undo, clipboard and
+// role settings to the builder
+func (v *WorkflowBuilderView) ApplyTunables(t config.Tunables) {
+	v.tunables = t
+	if v.builder != nil {
+		v.builder.SetReadOnly(v.readOnlyReason())
+		v.builder.SetValidationDebounce(t.ValidationDebounce())
+		v.builder.SetAutosaveInterval(t.AutosaveInterval())
+		v.builder.SetUndoLimits(t.UndoDepth, t.UndoMemoryMB<<20)
+		if t.SystemClipboard {
+			v.builder.SetSystemClipboard(NewSystemClipboard())
+		} else {
+			v.builder.SetSystemClipboard(nil)
+		}
+	}
+}
+
+// Tick runs pending debounced validation and autosave
+func (v *WorkflowBuilderView) Tick(now time.Time) {
+	if v.builder == nil {
+		return
+	}
+	if err := v.builder.Tick(now); err != nil {
+		v.statusMsg = "Error: " + err.Error()
+		Notifications().Warn("autosave", err.Error())
+	} else if !v.builder.modified {
+		Notifications().ClearWarning("autosave")
+	}
+	if v.watcher != nil && v.conflict == nil {
+		select {
+		case <-v.watcher.Changed():
+			v.checkDisk()
+		default:
+		}
+	}
+}
+
+// FlushAutosave saves pending changes immediately when autosave is enabled
+func (v *WorkflowBuilderView) FlushAutosave() error {
+	if v.builder == nil {
+		return nil
+	}
+	return v.builder.FlushAutosave()
+}
+
 // IsActive returns whether this view is currently active
 func (v *WorkflowBuilderView) IsActive() bool {
 	return v.active
@@ -200,12 +421,409 @@ func (v *WorkflowBuilderView) SetActive(active bool) {
 	v.active = active
 }
 
+// Save validates the workflow and writes it to the file it was loaded
+// from, as :w does
+func (v *WorkflowBuilderView) Save() error {
+	return v.save("")
+}
+
+// save writes the workflow like Save. When git_workflows is enabled the
+// file is also committed, with message or else a default one.
+func (v *WorkflowBuilderView) save(message string) error {
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	if v.workflowPath == "" {
+		return fmt.Errorf("no file name: open a workflow with :open <workflow>")
+	}
+	if reason := v.builder.ReadOnly(); reason != "" {
+		return fmt.Errorf("cannot save %s: it is read-only because %s", filepath.Base(v.workflowPath), reason)
+	}
+
+	// Never overwrite changes made on disk without asking
+	conflict, err := v.detectConflict()
+	if err != nil {
+		return err
+	}
+	if conflict != nil {
+		v.conflict = conflict
+		return fmt.Errorf("%s changed on disk: reload, overwrite or merge", filepath.Base(v.workflowPath))
+	}
+
+	if err := v.builder.SaveWorkflow(); err != nil {
+		return err
+	}
+
+	data, err := workflow.ToYAML(v.builder.GetWorkflow())
+	if err != nil {
+		return fmt.Errorf("failed to serialize workflow: %w", err)
+	}
+	// Saving upgrades a file of an older schema version; keep the original
+	if version, err := workflow.SchemaVersion(v.diskData); err == nil && version < workflow.CurrentSchemaVersion {
+		if err := workflow.WriteBackup(v.workflowPath, v.diskData); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(v.workflowPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write workflow: %w", err)
+	}
+	v.diskData = data
+	v.statusMsg = "Saved " + filepath.Base(v.workflowPath)
+	recordAudit(audit.WorkflowSaved, v.builder.GetWorkflow().Name, v.workflowPath)
+	Notifications().ClearWarning("autosave")
+
+	if v.tunables.GitWorkflows {
+		repo, err := v.gitRepository()
+		if err == nil {
+			err = repo.CommitFile(v.workflowPath, message)
+		}
+		if err != nil {
+			v.statusMsg += ", not committed: " + err.Error()
+		} else {
+			v.statusMsg += " and committed"
+		}
+	}
+
+	// The history is tied to the saved contents, so it is only restored if
+	// the file is unchanged when next opened
+	if v.tunables.PersistUndo {
+		path := undoHistoryPath(v.undoDir, v.workflowPath)
+		if err := v.builder.undoStack.SaveHistory(path, WorkflowChecksum(data)); err != nil {
+			v.statusMsg += ", undo history not saved: " + err.Error()
+		}
+	}
+	return nil
+}
+
+// RegisterCommands registers the builder's command-mode commands
+func (v *WorkflowBuilderView) RegisterCommands(registry *CommandRegistry) error {
+	if err := registry.Register(Command{
+		Name:        "open",
+		Aliases:     []string{"e", "edit"},
+		Usage:       "<workflow>",
+		Description: "Open a workflow in the builder",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run: func(args []string) error {
+			return v.open(args[0])
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			workflows, _ := listWorkflowFiles(v.workflowsDir)
+			return workflows
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "snapshot",
+		Usage:       "<name>",
+		Description: "Save the workflow as a named snapshot",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run: func(args []string) error {
+			return v.saveSnapshot(args[0])
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return v.snapshotNames()
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "sample",
+		Usage:       "[json]",
+		Description: "Set sample variables for expression previews, or clear them",
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			return v.setSample(strings.Join(args, " "))
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "group",
+		Usage:       "<name>",
+		Description: "Group the selected nodes under a name",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			return v.group(strings.Join(args, " "))
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "ungroup",
+		Usage:       "[name]",
+		Description: "Remove a group, or the selected node's, leaving its nodes in place",
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			return v.ungroup(strings.Join(args, " "))
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 0 || v.builder == nil {
+				return nil
+			}
+			var names []string
+			for _, group := range v.builder.GetWorkflow().Metadata.Groups {
+				if group != nil {
+					names = append(names, group.Name)
+				}
+			}
+			return names
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "note",
+		Usage:       "[text]",
+		Description: "Attach a note to the selected node, or remove its note",
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			return v.note(strings.Join(args, " "))
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "commit",
+		Usage:       "<message>",
+		Description: "Save the workflow and commit it to git with a message",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run: func(args []string) error {
+			if !v.tunables.GitWorkflows {
+				return fmt.Errorf("git history is off: enable git_workflows in the config file")
+			}
+			return v.save(strings.Join(args, " "))
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "history",
+		Description: "Compare or check out earlier git revisions of the workflow",
+		Run: func(args []string) error {
+			return v.openHistory()
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := registry.Register(Command{
+		Name:        "snapshots",
+		Description: "Compare or restore named snapshots of the workflow",
+		Run: func(args []string) error {
+			return v.openSnapshots()
+		},
+	}); err != nil {
+		return err
+	}
+
+	return registry.Register(Command{
+		Name:        "template apply",
+		Usage:       "<template>",
+		Description: "Replace the workflow being built with a template",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run: func(args []string) error {
+			if _, exists := WorkflowTemplates[args[0]]; !exists {
+				return fmt.Errorf("template not found: %s", args[0])
+			}
+			// Switch first: activating the builder reloads its workflow
+			if v.viewSwitcher != nil && !v.active {
+				if err := v.viewSwitcher.SwitchToView(v.name); err != nil {
+					return err
+				}
+			}
+			if v.builder == nil {
+				if err := v.Init(); err != nil {
+					return err
+				}
+			}
+			if err := v.builder.ApplyTemplate(args[0]); err != nil {
+				return err
+			}
+			v.statusMsg = "Applied template " + args[0]
+			return nil
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			names := make([]string, 0, len(WorkflowTemplates))
+			for name := range WorkflowTemplates {
+				names = append(names, name)
+			}
+			return names
+		},
+	})
+}
+
+// setSample sets the sample variables expressions are previewed with, or
+// clears them if data is empty
+func (v *WorkflowBuilderView) setSample(data string) error {
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	if err := v.builder.SetSampleJSON(data); err != nil {
+		return err
+	}
+	v.sample = data
+	if data == "" {
+		v.statusMsg = "Cleared sample variables"
+	} else {
+		v.statusMsg = "Previewing expressions with the sample variables"
+	}
+	return nil
+}
+
+// group groups the selected nodes under a name
+func (v *WorkflowBuilderView) group(name string) error {
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	if err := v.builder.GroupSelectedNodes(name); err != nil {
+		return err
+	}
+	v.statusMsg = "Grouped nodes as " + name + " (z to collapse)"
+	return nil
+}
+
+// ungroup removes a group, or the selected node's if name is empty
+func (v *WorkflowBuilderView) ungroup(name string) error {
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	if err := v.builder.UngroupNodes(name); err != nil {
+		return err
+	}
+	v.statusMsg = "Ungrouped nodes"
+	return nil
+}
+
+// note attaches a note to the selected node, or removes it if text is empty
+func (v *WorkflowBuilderView) note(text string) error {
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	if err := v.builder.SetNodeNote(text); err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		v.statusMsg = "Removed the note of " + v.builder.GetSelectedNodeID()
+	} else {
+		v.statusMsg = "Added a note to " + v.builder.GetSelectedNodeID()
+	}
+	return nil
+}
+
+// open loads a workflow from the workflows directory, by its path there
+// with or without the extension, and shows it in the builder
+func (v *WorkflowBuilderView) open(name string) error {
+	workflows, err := listWorkflowFiles(v.workflowsDir)
+	if err != nil {
+		return err
+	}
+	path := ""
+	for _, candidate := range workflows {
+		if candidate == name || strings.TrimSuffix(candidate, filepath.Ext(candidate)) == name {
+			path = filepath.Join(v.workflowsDir, candidate)
+			break
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("workflow not found: %s", name)
+	}
+	return v.OpenFile(path)
+}
+
+// OpenFile opens the workflow file at path in the builder and switches to
+// it
+func (v *WorkflowBuilderView) OpenFile(path string) error {
+	v.SetWorkflow(path)
+	if v.viewSwitcher != nil && !v.active {
+		return v.viewSwitcher.SwitchToView(v.name)
+	}
+	return v.Init()
+}
+
+// FinderItems returns the nodes on the canvas, the saved workflows and
+// their nodes for the fuzzy finder
+func (v *WorkflowBuilderView) FinderItems() []FinderItem {
+	var items []FinderItem
+	if v.builder != nil && v.builder.GetWorkflow() != nil {
+		for _, node := range v.builder.GetWorkflow().Nodes {
+			items = append(items, FinderItem{
+				Kind:   FinderNode,
+				Label:  node.GetID(),
+				Detail: nodeFinderDetail(node),
+				Value:  node.GetID(),
+			})
+		}
+	}
+
+	workflows, _ := listWorkflowFiles(v.workflowsDir)
+	for _, name := range workflows {
+		items = append(items, FinderItem{Kind: FinderWorkflow, Label: name, Value: name})
+	}
+	return append(items, v.savedNodeItems()...)
+}
+
+// JumpToNode shows the builder with a node selected and centered
+func (v *WorkflowBuilderView) JumpToNode(nodeID string) error {
+	if v.viewSwitcher != nil && !v.active {
+		if err := v.viewSwitcher.SwitchToView(v.name); err != nil {
+			return err
+		}
+	}
+	if v.builder == nil {
+		return fmt.Errorf("no workflow open")
+	}
+	if err := v.builder.JumpToNode(nodeID); err != nil {
+		return err
+	}
+	v.statusMsg = "Jumped to " + nodeID
+	return nil
+}
+
+// nodeFinderDetail describes a node for the fuzzy finder: its type, and for
+// tool nodes the tool it calls
+func nodeFinderDetail(node workflow.Node) string {
+	if tool, ok := node.(*workflow.MCPToolNode); ok {
+		return fmt.Sprintf("%s %s.%s", node.Type(), tool.ServerID, tool.ToolName)
+	}
+	return node.Type()
+}
+
 // SetWorkflow sets the workflow to be edited
 func (v *WorkflowBuilderView) SetWorkflow(workflowPath string) {
 	v.workflowPath = workflowPath
 	v.initialized = false // force reload on next Init()
 }
 
+// Workflow returns the workflow being edited, or nil before one is loaded
+func (v *WorkflowBuilderView) Workflow() *workflow.Workflow {
+	if v.builder == nil {
+		return nil
+	}
+	return v.builder.GetWorkflow()
+}
+
 // SetBounds sets the view dimensions
 func (v *WorkflowBuilderView) SetBounds(width, height int) {
 	v.width = width
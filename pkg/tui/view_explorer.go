@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -22,28 +23,68 @@ type WorkflowExplorerView struct {
 	height       int          // View height
 	viewSwitcher ViewSwitcher // For switching to other views
 	workflowsDir string       // Directory containing workflows
+	templatesDir string       // Directory containing workflow templates
+
+	upgrade *templateUpgradePrompt // Pending template upgrade, if any
 }
 
 // NewWorkflowExplorerView creates a new workflow explorer view
 func NewWorkflowExplorerView() *WorkflowExplorerView {
-	// Get workflows directory from environment or use default
-	workflowsDir := os.Getenv("GOFLOW_WORKFLOWS_DIR")
-	if workflowsDir == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			workflowsDir = "."
-		} else {
-			workflowsDir = filepath.Join(homeDir, ".goflow", "workflows")
-		}
-	}
-
 	return &WorkflowExplorerView{
 		name:         "explorer",
 		active:       false,
 		workflows:    make([]string, 0),
 		selectedIdx:  0,
-		workflowsDir: workflowsDir,
+		workflowsDir: defaultWorkflowsDir(),
+		templatesDir: defaultTemplatesDir(),
+	}
+}
+
+// defaultWorkflowsDir returns GOFLOW_WORKFLOWS_DIR or ~/.goflow/workflows
+func defaultWorkflowsDir() string {
+	if dir := os.Getenv("GOFLOW_WORKFLOWS_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "."
 	}
+	return filepath.Join(homeDir, ".goflow", "workflows")
+}
+
+// listWorkflowFiles returns the .yaml and .yml files under dir, relative to
+// it. Files that cannot be accessed are skipped.
+func listWorkflowFiles(dir string) ([]string, error) {
+	workflows := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip files we can't access
+		}
+
+		// Skip directories, and git's entirely
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Only include .yaml and .yml files
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		// Get relative path from workflows directory
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			relPath = filepath.Base(path)
+		}
+
+		workflows = append(workflows, relPath)
+		return nil
+	})
+	return workflows, err
 }
 
 // SetViewSwitcher stores the ViewSwitcher for requesting view changes
@@ -74,32 +115,8 @@ func (v *WorkflowExplorerView) Init() error {
 	}
 
 	// Walk the workflows directory
-	err := filepath.WalkDir(v.workflowsDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil // skip files we can't access
-		}
-
-		// Skip directories
-		if d.IsDir() {
-			return nil
-		}
-
-		// Only include .yaml and .yml files
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext != ".yaml" && ext != ".yml" {
-			return nil
-		}
-
-		// Get relative path from workflows directory
-		relPath, err := filepath.Rel(v.workflowsDir, path)
-		if err != nil {
-			relPath = filepath.Base(path)
-		}
-
-		v.workflows = append(v.workflows, relPath)
-		return nil
-	})
-
+	workflows, err := listWorkflowFiles(v.workflowsDir)
+	v.workflows = workflows
 	if err != nil {
 		v.statusMsg = "Error loading workflows: " + err.Error()
 		v.initialized = true
@@ -132,6 +149,11 @@ func (v *WorkflowExplorerView) HandleKey(event KeyEvent) error {
 	// - n: create new workflow (not yet implemented)
 	// - d: delete selected workflow (not yet implemented)
 	// - r: rename selected workflow (not yet implemented)
+	// - u: upgrade selected workflow to the latest version of its template
+
+	if v.upgrade != nil {
+		return v.handleUpgradeKey(event)
+	}
 
 	switch {
 	case event.Key == 'j':
@@ -184,11 +206,49 @@ func (v *WorkflowExplorerView) HandleKey(event KeyEvent) error {
 	case event.Key == 'n':
 		// Create new workflow
 		v.statusMsg = "Create new workflow (not yet implemented)"
+	case event.Key == 'u':
+		// Preview an upgrade to the latest template version
+		if v.selectedIdx >= len(v.workflows) {
+			v.statusMsg = "No workflow selected"
+			return nil
+		}
+		prompt, err := planTemplateUpgrade(filepath.Join(v.workflowsDir, v.workflows[v.selectedIdx]), v.templatesDir)
+		if err != nil {
+			v.statusMsg = "Cannot upgrade: " + err.Error()
+			return nil
+		}
+		v.upgrade = prompt
+		v.statusMsg = "Review template upgrade"
 	}
 
 	return nil
 }
 
+// handleUpgradeKey handles keys while a template upgrade is being reviewed
+func (v *WorkflowExplorerView) handleUpgradeKey(event KeyEvent) error {
+	switch {
+	case event.Key == 'y':
+		if err := v.upgrade.apply(); err != nil {
+			v.statusMsg = "Upgrade failed: " + err.Error()
+		} else {
+			v.statusMsg = fmt.Sprintf("Upgraded to template version %s", v.upgrade.upgrade.To)
+		}
+		v.upgrade = nil
+	case event.Key == 'n' || (event.IsSpecial && event.Special == "Escape"):
+		v.upgrade = nil
+		v.statusMsg = "Upgrade cancelled"
+	case event.Key == 'j':
+		if v.upgrade.scroll < len(v.upgrade.lines)-1 {
+			v.upgrade.scroll++
+		}
+	case event.Key == 'k':
+		if v.upgrade.scroll > 0 {
+			v.upgrade.scroll--
+		}
+	}
+	return nil
+}
+
 // Render draws the workflow explorer to the screen
 func (v *WorkflowExplorerView) Render(screen *goterm.Screen) error {
 	// TODO: Implement rendering
@@ -205,9 +265,10 @@ func (v *WorkflowExplorerView) Render(screen *goterm.Screen) error {
 	// | Status: Ready          [?: Help] |
 	// +----------------------------------+
 
-	_, height := screen.Size()
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	width, height := screen.Size()
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
 
 	// Clear screen
 	screen.Clear()
@@ -216,6 +277,19 @@ func (v *WorkflowExplorerView) Render(screen *goterm.Screen) error {
 	title := "Workflow Explorer [Tab: Switch View] [?: Help]"
 	screen.DrawText(0, 0, title, fg, bg, goterm.StyleBold)
 
+	// Template upgrade preview replaces the list until confirmed or cancelled
+	if v.upgrade != nil {
+		for i, line := range v.upgrade.lines[v.upgrade.scroll:] {
+			y := 2 + i
+			if y >= height-1 {
+				break
+			}
+			screen.DrawText(0, y, line, fg, bg, goterm.StyleNone)
+		}
+		drawStatusBar(screen, height-1, width, "Status: "+v.statusMsg, fg, goterm.StyleNone)
+		return nil
+	}
+
 	// Workflow list
 	for i, workflow := range v.workflows {
 		y := 2 + i
@@ -235,7 +309,7 @@ func (v *WorkflowExplorerView) Render(screen *goterm.Screen) error {
 
 	// Status bar (bottom line)
 	statusLine := "Status: " + v.statusMsg
-	screen.DrawText(0, height-1, statusLine, fg, bg, goterm.StyleNone)
+	drawStatusBar(screen, height-1, width, statusLine, fg, goterm.StyleNone)
 
 	return nil
 }
@@ -1,6 +1,13 @@
 package tui
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	"github.com/dshills/goflow/pkg/domain/types"
+	execpkg "github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/dshills/goterm"
 )
 
@@ -16,10 +23,28 @@ type ExecutionMonitorView struct {
 	autoScroll   bool     // Auto-scroll to latest log entry
 	statusMsg    string   // Status message to display
 	initialized  bool
-	showLogs     bool         // Toggle between node view and log view
-	width        int          // View width
-	height       int          // View height
-	viewSwitcher ViewSwitcher // For switching to other views
+	showLogs     bool                                               // Toggle between node view and log view
+	width        int                                                // View width
+	height       int                                                // View height
+	viewSwitcher ViewSwitcher                                       // For switching to other views
+	run          *monitoredRun                                      // Execution launched from the TUI, if any
+	newEngine    func(opts ...execpkg.EngineOption) *execpkg.Engine // Creates the engine of each launched execution
+	dispatcher   *execpkg.Dispatcher                                // Queues launched executions as interactive runs
+}
+
+// monitoredRun is an execution launched from the TUI, shown live in the
+// execution monitor
+type monitoredRun struct {
+	engine     *execpkg.Engine
+	dispatcher *execpkg.Dispatcher
+	workflow   *workflow.Workflow
+	inputs     map[string]interface{}
+	cancel     context.CancelFunc
+	monitor    *ExecutionMonitor // Created on the first render, which knows the screen
+	attached   bool              // Whether the monitor follows the engine's events
+	done       chan struct{}     // Closed when the execution ends, after exec is set
+	exec       *execution.Execution
+	shown      bool // Whether the final state was passed to the monitor
 }
 
 // NewExecutionMonitorView creates a new execution monitor view
@@ -32,6 +57,8 @@ func NewExecutionMonitorView() *ExecutionMonitorView {
 		selectedIdx: 0,
 		autoScroll:  true,
 		showLogs:    false,
+		newEngine:   execpkg.NewEngine,
+		dispatcher:  execpkg.SharedDispatcher(),
 	}
 }
 
@@ -79,8 +106,161 @@ func (v *ExecutionMonitorView) Cleanup() error {
 	return nil
 }
 
+// Launch runs a workflow with the given input variables and shows the
+// execution live in the monitor, in place of the one shown before. One
+// launched execution runs at a time.
+func (v *ExecutionMonitorView) Launch(wf *workflow.Workflow, inputs map[string]interface{}) error {
+	if v.run != nil && !v.run.finished() {
+		return fmt.Errorf("%s is still running; stop it with X X in the monitor first", v.run.workflow.Name)
+	}
+	v.closeRun()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &monitoredRun{
+		engine:     v.newEngine(execpkg.WithEventHandler(auditRunEvents(wf.Name)), execpkg.WithToolCache(execpkg.SharedToolCache())),
+		dispatcher: v.dispatcher,
+		workflow:   wf,
+		inputs:     inputs,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go run.execute(ctx)
+
+	v.run = run
+	v.executionID = ""
+	v.statusMsg = "Running " + wf.Name
+	return nil
+}
+
+// execute runs the workflow, ahead of queued background runs, and
+// announces how the execution ended
+func (r *monitoredRun) execute(ctx context.Context) {
+	exec, err := r.dispatcher.Execute(ctx, r.engine, r.workflow, r.inputs, execpkg.PriorityInteractive)
+	r.exec = exec
+	close(r.done)
+
+	switch {
+	case err != nil:
+		Notifications().Notify(NotifyError, "Run of %s failed: %v", r.workflow.Name, err)
+	case exec != nil && exec.Status == execution.StatusCompleted:
+		Notifications().Notify(NotifySuccess, "Run of %s completed", r.workflow.Name)
+	case exec != nil:
+		Notifications().Notify(NotifyWarning, "Run of %s %s", r.workflow.Name, exec.Status)
+	}
+}
+
+// finished reports whether the execution has ended
+func (r *monitoredRun) finished() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// render draws the execution monitor of the run, creating it on the
+// first call and following the engine's events once they start
+func (r *monitoredRun) render(screen *goterm.Screen) error {
+	if r.monitor == nil {
+		placeholder, err := execution.NewExecution(types.WorkflowID(r.workflow.ID), r.workflow.Version, r.inputs)
+		if err != nil {
+			return err
+		}
+		r.monitor = NewExecutionMonitor(placeholder, r.workflow, screen)
+		r.monitor.SetNodeRetrier(r.engine)
+		r.monitor.SetPauser(r.engine)
+		r.monitor.SetCanceller(r.engine)
+		r.monitor.SetApprover(r.engine)
+	}
+	if !r.attached {
+		if events := r.engine.GetMonitor(); events != nil {
+			r.monitor.SetEventMonitor(events)
+			r.attached = true
+		}
+	}
+
+	if !r.shown && r.finished() {
+		r.shown = true
+		if r.exec != nil {
+			r.monitor.OnExecutionEvent(r.exec)
+		}
+	}
+
+	_, err := r.monitor.Render()
+	return err
+}
+
+// close stops the run, if still going, and releases its engine
+func (r *monitoredRun) close() {
+	r.cancel()
+	<-r.done
+	if r.monitor != nil {
+		r.monitor.Close()
+	}
+	_ = r.engine.Close() // Best effort: releases server connections
+}
+
+// closeRun releases the launched execution, if any
+func (v *ExecutionMonitorView) closeRun() {
+	if v.run != nil {
+		v.run.close()
+		v.run = nil
+	}
+}
+
+// CapturingText reports whether the view takes every key, which it does
+// while showing a launched execution: the monitor has its own Tab, Esc
+// and typed input
+func (v *ExecutionMonitorView) CapturingText() bool {
+	return v.run != nil
+}
+
+// monitorKey converts a key event to the key the execution monitor
+// expects, 0 if it has none
+func monitorKey(event KeyEvent) rune {
+	if !event.IsSpecial {
+		if event.Ctrl || event.Alt {
+			return 0
+		}
+		return event.Key
+	}
+	switch event.Special {
+	case "Tab":
+		return '\t'
+	case "Enter":
+		return '\r'
+	case "Escape":
+		return 27
+	case "Backspace":
+		return 127
+	case "Down":
+		return 'j'
+	case "Up":
+		return 'k'
+	}
+	return 0
+}
+
 // HandleKey processes keyboard input events
 func (v *ExecutionMonitorView) HandleKey(event KeyEvent) error {
+	// A launched execution takes every key; q or Ctrl-c goes back to the
+	// builder and leaves it running
+	if v.run != nil && v.run.monitor != nil {
+		if event.Ctrl && event.Key == 'c' {
+			event = KeyEvent{Key: 'q'}
+		}
+		if key := monitorKey(event); key != 0 {
+			if err := v.run.monitor.HandleKey(key); err != nil {
+				return err
+			}
+		}
+		if v.run.monitor.GetLastAction() == "quit" && v.viewSwitcher != nil {
+			return v.viewSwitcher.SwitchToView("builder")
+		}
+		return nil
+	}
+
 	// TODO: Implement keyboard navigation
 	// - j/k: scroll through nodes or logs
 	// - l: toggle log view
@@ -157,9 +337,14 @@ func (v *ExecutionMonitorView) Render(screen *goterm.Screen) error {
 	// | AutoScroll: ON  [l: toggle logs] |
 	// +----------------------------------+
 
-	_, height := screen.Size()
-	fg := goterm.ColorDefault()
-	bg := goterm.ColorDefault()
+	if v.run != nil {
+		return v.run.render(screen)
+	}
+
+	width, height := screen.Size()
+	theme := CurrentTheme()
+	fg := theme.Foreground
+	bg := theme.Background
 
 	// Clear screen
 	screen.Clear()
@@ -229,7 +414,7 @@ func (v *ExecutionMonitorView) Render(screen *goterm.Screen) error {
 		scrollStatus = "AutoScroll: ON"
 	}
 	statusLine := scrollStatus + "    " + v.statusMsg
-	screen.DrawText(0, height-1, statusLine, fg, bg, goterm.StyleNone)
+	drawStatusBar(screen, height-1, width, statusLine, fg, goterm.StyleNone)
 
 	return nil
 }
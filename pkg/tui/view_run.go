@@ -0,0 +1,418 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// WorkflowLauncher starts an execution of a workflow with the given input
+// variables
+type WorkflowLauncher func(wf *workflow.Workflow, inputs map[string]interface{}) error
+
+// RunWorkflowView asks for the input variables of a workflow before running
+// it: one field per declared variable, prefilled with its default and
+// checked against its type. Enter launches the execution into the
+// execution monitor.
+type RunWorkflowView struct {
+	name         string
+	active       bool
+	workflow     *workflow.Workflow
+	fields       []propertyField // One per declared variable, in order
+	types        []string        // Declared type of each field's variable
+	selected     int             // Field being typed in
+	statusMsg    string
+	width        int
+	height       int
+	viewSwitcher ViewSwitcher
+	openWorkflow func() *workflow.Workflow // Workflow run by :run
+	launch       WorkflowLauncher
+}
+
+// NewRunWorkflowView creates a new run workflow view
+func NewRunWorkflowView() *RunWorkflowView {
+	return &RunWorkflowView{
+		name: "run",
+	}
+}
+
+// Name returns the unique identifier for this view
+func (v *RunWorkflowView) Name() string {
+	return v.name
+}
+
+// SetViewSwitcher stores the ViewSwitcher for requesting view changes
+func (v *RunWorkflowView) SetViewSwitcher(switcher ViewSwitcher) {
+	v.viewSwitcher = switcher
+}
+
+// SetOpenWorkflow sets how to get the workflow open in the builder, which
+// :run asks the inputs of
+func (v *RunWorkflowView) SetOpenWorkflow(openWorkflow func() *workflow.Workflow) {
+	v.openWorkflow = openWorkflow
+}
+
+// SetLauncher sets how executions are started; without one, Enter only
+// checks the inputs
+func (v *RunWorkflowView) SetLauncher(launch WorkflowLauncher) {
+	v.launch = launch
+}
+
+// SetWorkflow shows the input form of a workflow, discarding the values
+// entered for the previous one
+func (v *RunWorkflowView) SetWorkflow(wf *workflow.Workflow) {
+	v.workflow = wf
+	v.fields = nil
+	v.types = nil
+	v.selected = 0
+	if wf == nil {
+		v.statusMsg = "No workflow to run: open one in the builder, then :run"
+		return
+	}
+	for _, variable := range wf.Variables {
+		if variable == nil {
+			continue
+		}
+		v.fields = append(v.fields, newInputField(variable))
+		v.types = append(v.types, variable.Type)
+	}
+	v.statusMsg = fmt.Sprintf("Run %s (Tab: next field, Enter: run, Esc: back)", wf.Name)
+}
+
+// newInputField creates the field for one input variable, prefilled with
+// its default
+func newInputField(variable *workflow.Variable) propertyField {
+	var hints []string
+	if variable.Type != "" {
+		hints = append(hints, variable.Type)
+	}
+	if variable.Description != "" {
+		hints = append(hints, variable.Description)
+	}
+	if variable.Required {
+		hints = append(hints, "required")
+	}
+
+	field := propertyField{
+		label:     variable.Name,
+		value:     formatInputValue(variable.DefaultValue),
+		required:  variable.Required,
+		fieldType: "input",
+		helpText:  strings.Join(hints, "; "),
+		param:     variable.Name,
+	}
+	if variable.Type == "boolean" {
+		field.enum = []string{"true", "false"}
+	}
+	varType := variable.Type
+	field.validationFn = func(value string) error {
+		if value == "" {
+			return nil // Empty is valid (required check done separately)
+		}
+		_, err := parseInputValue(value, varType)
+		return err
+	}
+	return field
+}
+
+// formatInputValue formats a default value for a field: strings as is,
+// other values as JSON
+func formatInputValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// parseInputValue converts an entered value to its variable type
+func parseInputValue(raw, varType string) (interface{}, error) {
+	switch varType {
+	case "string":
+		return raw, nil
+	case "number":
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", raw)
+		}
+		return number, nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", raw)
+		}
+		return b, nil
+	case "object":
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &object); err != nil {
+			return nil, fmt.Errorf("expected a JSON object")
+		}
+		return object, nil
+	case "array":
+		var array []interface{}
+		if err := json.Unmarshal([]byte(raw), &array); err != nil {
+			return nil, fmt.Errorf("expected a JSON array")
+		}
+		return array, nil
+	default:
+		// Untyped: accept JSON values, otherwise keep the raw string
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value, nil
+		}
+		return raw, nil
+	}
+}
+
+// Inputs returns the entered input variables, converted to their types.
+// Empty fields are left out, so their variables keep their defaults. The
+// error names the first field that is missing or invalid.
+func (v *RunWorkflowView) Inputs() (map[string]interface{}, error) {
+	inputs := make(map[string]interface{}, len(v.fields))
+	for i := range v.fields {
+		field := &v.fields[i]
+		if field.value == "" {
+			if field.required {
+				return nil, fmt.Errorf("%s is required", field.label)
+			}
+			continue
+		}
+		value, err := parseInputValue(field.value, v.types[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.label, err)
+		}
+		inputs[field.param] = value
+	}
+	return inputs, nil
+}
+
+// Init initializes the run workflow view
+func (v *RunWorkflowView) Init() error {
+	if v.workflow == nil {
+		v.SetWorkflow(nil)
+	}
+	return nil
+}
+
+// Cleanup releases resources when view is deactivated
+func (v *RunWorkflowView) Cleanup() error {
+	// Keep the entered values for when we return to this view
+	return nil
+}
+
+// CapturingText reports whether the form takes typed keys, which it does
+// while it has a workflow
+func (v *RunWorkflowView) CapturingText() bool {
+	return v.workflow != nil
+}
+
+// HandleKey edits the input fields. Enter runs the workflow; Escape goes
+// back to the builder.
+func (v *RunWorkflowView) HandleKey(event KeyEvent) error {
+	switch {
+	case event.IsSpecial && event.Special == "Escape", event.Ctrl && event.Key == 'c':
+		v.back()
+	case v.workflow == nil:
+		return nil
+	case event.IsSpecial && event.Special == "Enter":
+		v.run()
+	case event.IsSpecial && (event.Special == "Tab" && !event.Shift || event.Special == "Down"):
+		if len(v.fields) > 0 {
+			v.selected = (v.selected + 1) % len(v.fields)
+		}
+	case event.IsSpecial && (event.Special == "Tab" && event.Shift || event.Special == "Up"):
+		if len(v.fields) > 0 {
+			v.selected = (v.selected + len(v.fields) - 1) % len(v.fields)
+		}
+	case event.IsSpecial && event.Special == "Backspace":
+		if field := v.field(); field != nil && field.value != "" {
+			runes := []rune(field.value)
+			field.value = string(runes[:len(runes)-1])
+		}
+	case event.Ctrl && event.Key == 'u':
+		if field := v.field(); field != nil {
+			field.value = ""
+		}
+	case event.Ctrl && event.Key == 'v':
+		if field := v.field(); field != nil {
+			field.value += strings.TrimRight(Clipboard().Paste(), "\r\n")
+		}
+	case !event.IsSpecial && !event.Ctrl && !event.Alt && event.Key != 0:
+		if field := v.field(); field != nil {
+			field.value += string(event.Key)
+		}
+	}
+	return nil
+}
+
+// field returns the field being typed in, or nil if the workflow declares
+// no variables
+func (v *RunWorkflowView) field() *propertyField {
+	if v.selected >= len(v.fields) {
+		return nil
+	}
+	return &v.fields[v.selected]
+}
+
+// back returns to the builder
+func (v *RunWorkflowView) back() {
+	if v.viewSwitcher != nil {
+		_ = v.viewSwitcher.SwitchToView("builder") // Registered by the app
+	}
+}
+
+// run checks the inputs and launches the workflow, then shows the
+// execution monitor. The execution runs a copy of the workflow, so edits
+// made in the builder meanwhile do not affect it.
+func (v *RunWorkflowView) run() {
+	inputs, err := v.Inputs()
+	if err != nil {
+		v.statusMsg = err.Error()
+		v.selectInvalidField()
+		return
+	}
+	if err := v.workflow.Validate(); err != nil {
+		v.statusMsg = fmt.Sprintf("Cannot run %s: %v", v.workflow.Name, err)
+		return
+	}
+	if v.launch == nil {
+		v.statusMsg = "Inputs are valid, but running is not available here"
+		return
+	}
+
+	data, err := workflow.ToYAML(v.workflow)
+	if err != nil {
+		v.statusMsg = fmt.Sprintf("Cannot run %s: %v", v.workflow.Name, err)
+		return
+	}
+	snapshot, err := workflow.Parse(data)
+	if err != nil {
+		v.statusMsg = fmt.Sprintf("Cannot run %s: %v", v.workflow.Name, err)
+		return
+	}
+	if err := v.launch(snapshot, inputs); err != nil {
+		v.statusMsg = fmt.Sprintf("Failed to run %s: %v", v.workflow.Name, err)
+		return
+	}
+	v.statusMsg = fmt.Sprintf("Running %s", v.workflow.Name)
+	if v.viewSwitcher != nil {
+		_ = v.viewSwitcher.SwitchToView("monitor") // Registered by the app
+	}
+}
+
+// selectInvalidField moves to the first field that is missing or invalid
+func (v *RunWorkflowView) selectInvalidField() {
+	for i := range v.fields {
+		field := &v.fields[i]
+		if (field.value == "" && field.required) || field.validate() != nil {
+			v.selected = i
+			return
+		}
+	}
+}
+
+// RegisterCommands registers :run, which asks for the inputs of the
+// workflow open in the builder
+func (v *RunWorkflowView) RegisterCommands(registry *CommandRegistry) error {
+	return registry.Register(Command{
+		Name:        "run",
+		Description: "Run the open workflow, asking for its input variables",
+		Run: func(args []string) error {
+			var wf *workflow.Workflow
+			if v.openWorkflow != nil {
+				wf = v.openWorkflow()
+			}
+			if wf == nil {
+				return fmt.Errorf("no workflow is open in the builder")
+			}
+			if wf != v.workflow {
+				v.SetWorkflow(wf)
+			}
+			if v.viewSwitcher != nil && !v.active {
+				return v.viewSwitcher.SwitchToView(v.name)
+			}
+			return nil
+		},
+	})
+}
+
+// Render draws the input form
+func (v *RunWorkflowView) Render(screen *goterm.Screen) error {
+	width, height := screen.Size()
+	theme := CurrentTheme()
+	fg := theme.Text
+	bg := theme.Background
+
+	screen.Clear()
+
+	title := "Run Workflow"
+	if v.workflow != nil {
+		title += " - " + v.workflow.Name
+	}
+	screen.DrawText(0, 0, title, fg, bg, goterm.StyleBold)
+
+	y := 2
+	if v.workflow != nil && len(v.fields) == 0 {
+		screen.DrawText(0, y, "  No input variables; press Enter to run", theme.Muted, bg, goterm.StyleDim)
+		y++
+	}
+	labelWidth := 0
+	for _, field := range v.fields {
+		labelWidth = max(labelWidth, len(field.label)+1)
+	}
+	for i := range v.fields {
+		if y >= height-3 {
+			break
+		}
+		field := &v.fields[i]
+		label := field.label
+		if field.required {
+			label += "*"
+		}
+		line := fmt.Sprintf("  %-*s  ", labelWidth, label)
+		screen.DrawText(0, y, line, fg, bg, goterm.StyleNone)
+		value := field.value
+		valueFg, valueBg := fg, bg
+		if i == v.selected {
+			value += "_"
+			valueFg, valueBg = theme.InputFg, theme.InputBg
+		}
+		screen.DrawText(len(line), y, value, valueFg, valueBg, goterm.StyleNone)
+		if err := field.validate(); err != nil {
+			screen.DrawText(len(line)+len(value)+2, y, "✗ "+err.Error(), theme.Error, bg, goterm.StyleNone)
+		}
+		y++
+	}
+	if field := v.field(); field != nil && field.helpText != "" && y < height-2 {
+		screen.DrawText(2, y, field.helpText, theme.Muted, bg, goterm.StyleDim)
+	}
+
+	drawStatusBar(screen, height-1, width, v.statusMsg, fg, goterm.StyleNone)
+	return nil
+}
+
+// IsActive returns whether this view is currently active
+func (v *RunWorkflowView) IsActive() bool {
+	return v.active
+}
+
+// SetActive updates the active state of the view
+func (v *RunWorkflowView) SetActive(active bool) {
+	v.active = active
+}
+
+// SetBounds sets the view dimensions
+func (v *RunWorkflowView) SetBounds(width, height int) {
+	v.width = width
+	v.height = height
+}
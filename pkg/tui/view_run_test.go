@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	execpkg "github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// recordingSwitcher records the views switched to
+type recordingSwitcher struct {
+	views []string
+}
+
+func (s *recordingSwitcher) SwitchToView(name string) error {
+	s.views = append(s.views, name)
+	return nil
+}
+
+// newRunTestWorkflow returns a start -> end workflow declaring the given
+// variables
+func newRunTestWorkflow(t *testing.T, variables ...*workflow.Variable) *workflow.Workflow {
+	t.Helper()
+	wf, err := workflow.NewWorkflow("greet", "run form test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range []workflow.Node{&workflow.StartNode{ID: "start"}, &workflow.EndNode{ID: "end"}} {
+		if err := wf.AddNode(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := wf.AddEdge(&workflow.Edge{ID: "e1", FromNodeID: "start", ToNodeID: "end"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, variable := range variables {
+		if err := wf.AddVariable(variable); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return wf
+}
+
+func TestRunWorkflowView(t *testing.T) {
+	wf := newRunTestWorkflow(t,
+		&workflow.Variable{Name: "name", Type: "string", Required: true},
+		&workflow.Variable{Name: "count", Type: "number", DefaultValue: 3},
+		&workflow.Variable{Name: "tags", Type: "array", DefaultValue: []interface{}{"a"}},
+		&workflow.Variable{Name: "verbose", Type: "boolean"},
+	)
+	view := NewRunWorkflowView()
+	switcher := &recordingSwitcher{}
+	view.SetViewSwitcher(switcher)
+	var launched *workflow.Workflow
+	var inputs map[string]interface{}
+	view.SetLauncher(func(wf *workflow.Workflow, in map[string]interface{}) error {
+		launched, inputs = wf, in
+		return nil
+	})
+	view.SetWorkflow(wf)
+
+	press := func(keys string) {
+		for _, key := range keys {
+			if err := view.HandleKey(KeyEvent{Key: key}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	special := func(name string) {
+		if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !view.CapturingText() {
+		t.Error("expected the form to capture keys")
+	}
+	var values []string
+	for _, field := range view.fields {
+		values = append(values, field.value)
+	}
+	if want := []string{"", "3", `["a"]`, ""}; !reflect.DeepEqual(values, want) {
+		t.Errorf("prefilled values = %q, want %q", values, want)
+	}
+
+	// Missing and invalid inputs are reported on their field
+	special("Enter")
+	if launched != nil || view.statusMsg != "name is required" {
+		t.Fatalf("expected the missing name to be reported, got %q", view.statusMsg)
+	}
+	press("ada")
+	special("Tab")
+	press("x")
+	special("Enter")
+	if launched != nil || !strings.HasPrefix(view.statusMsg, "count:") || view.selected != 1 {
+		t.Fatalf("expected the invalid count to be reported, got %q on field %d", view.statusMsg, view.selected)
+	}
+
+	special("Backspace")
+	special("Backspace")
+	press("2.5")
+	special("Enter")
+	if launched == nil {
+		t.Fatalf("expected the workflow to be launched, got %q", view.statusMsg)
+	}
+	want := map[string]interface{}{"name": "ada", "count": 2.5, "tags": []interface{}{"a"}}
+	if !reflect.DeepEqual(inputs, want) {
+		t.Errorf("inputs = %v, want %v", inputs, want)
+	}
+	if launched == wf || launched.Name != wf.Name {
+		t.Error("expected a copy of the workflow to be launched")
+	}
+	if !reflect.DeepEqual(switcher.views, []string{"monitor"}) {
+		t.Errorf("switched to %v, want the monitor", switcher.views)
+	}
+
+	special("Escape")
+	if switcher.views[len(switcher.views)-1] != "builder" {
+		t.Error("expected Escape to go back to the builder")
+	}
+}
+
+func TestRunWorkflowView_RunCommand(t *testing.T) {
+	var open *workflow.Workflow
+	view := NewRunWorkflowView()
+	view.SetOpenWorkflow(func() *workflow.Workflow { return open })
+	switcher := &recordingSwitcher{}
+	view.SetViewSwitcher(switcher)
+	registry := NewCommandRegistry()
+	if err := view.RegisterCommands(registry); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := registry.Execute("run"); err == nil {
+		t.Error("expected an error without an open workflow")
+	}
+	open = newRunTestWorkflow(t, &workflow.Variable{Name: "name", Type: "string"})
+	if err := registry.Execute("run"); err != nil {
+		t.Fatal(err)
+	}
+	if view.workflow != open || len(view.fields) != 1 || !reflect.DeepEqual(switcher.views, []string{"run"}) {
+		t.Errorf("expected the form of the open workflow, got %d fields, views %v", len(view.fields), switcher.views)
+	}
+}
+
+func TestParseInputValue(t *testing.T) {
+	tests := []struct {
+		raw     string
+		varType string
+		want    interface{}
+		wantErr bool
+	}{
+		{"hello", "string", "hello", false},
+		{"42", "string", "42", false},
+		{"1.5", "number", 1.5, false},
+		{"many", "number", nil, true},
+		{"true", "boolean", true, false},
+		{"yes", "boolean", nil, true},
+		{`{"a":1}`, "object", map[string]interface{}{"a": float64(1)}, false},
+		{`[1]`, "object", nil, true},
+		{`["a"]`, "array", []interface{}{"a"}, false},
+		{"7", "any", float64(7), false},
+		{"text", "any", "text", false},
+	}
+	for _, tt := range tests {
+		got, err := parseInputValue(tt.raw, tt.varType)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseInputValue(%q, %q) = %#v, %v", tt.raw, tt.varType, got, err)
+		}
+	}
+}
+
+func TestExecutionMonitorView_Launch(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir()) // Runs are audited
+	view := NewExecutionMonitorView()
+	view.newEngine = func(opts ...execpkg.EngineOption) *execpkg.Engine {
+		return execpkg.NewEngineWithRepository(nil, opts...)
+	}
+	defer view.closeRun()
+	wf := newRunTestWorkflow(t, &workflow.Variable{Name: "name", Type: "string"})
+
+	if err := view.Launch(wf, map[string]interface{}{"name": "ada"}); err != nil {
+		t.Fatal(err)
+	}
+	if !view.CapturingText() {
+		t.Error("expected the monitor to take every key while showing a run")
+	}
+	select {
+	case <-view.run.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution did not finish")
+	}
+	if err := view.Render(goterm.NewScreen(80, 24)); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := view.run.exec
+	if exec == nil || exec.Status != execution.StatusCompleted {
+		t.Fatalf("expected a completed execution, got %+v", exec)
+	}
+	if name, _ := exec.Context.GetVariable("name"); name != "ada" {
+		t.Errorf("name = %v, want the launched input", name)
+	}
+	if view.run.monitor == nil || !view.run.shown {
+		t.Error("expected the monitor to show the final state")
+	}
+
+	// q goes back to the builder
+	switcher := &recordingSwitcher{}
+	view.SetViewSwitcher(switcher)
+	if err := view.HandleKey(KeyEvent{Key: 'q'}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(switcher.views, []string{"builder"}) {
+		t.Errorf("switched to %v, want the builder", switcher.views)
+	}
+}
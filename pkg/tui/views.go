@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/dshills/goterm"
 )
@@ -20,6 +21,20 @@ type ViewSwitcherAware interface {
 	SetViewSwitcher(switcher ViewSwitcher)
 }
 
+// Ticker is an optional interface for views that perform periodic work
+// (debounced validation, autosave). Tick is called on every frame of the
+// active view from the application loop.
+type Ticker interface {
+	Tick(now time.Time)
+}
+
+// TextCapturer is an optional interface for views that take text input.
+// While CapturingText returns true the app passes every key to the view,
+// including those bound globally such as Tab and q.
+type TextCapturer interface {
+	CapturingText() bool
+}
+
 // View defines the interface that all TUI views must implement
 type View interface {
 	// Name returns the unique identifier for this view
@@ -152,6 +167,22 @@ func (vm *ViewManager) SwitchTo(viewName string) error {
 	return nil
 }
 
+// Focus makes a view that is already open, in a pane of a split screen,
+// the current view. Unlike SwitchTo, neither view is cleaned up or
+// initialized: both stay on screen with their state.
+func (vm *ViewManager) Focus(viewName string) error {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	view, exists := vm.views[viewName]
+	if !exists {
+		return fmt.Errorf("view %q not found", viewName)
+	}
+	view.SetActive(true)
+	vm.activeView = view
+	return nil
+}
+
 // GetCurrentView returns the currently active view
 func (vm *ViewManager) GetCurrentView() View {
 	vm.mu.RLock()
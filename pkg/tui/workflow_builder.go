@@ -4,7 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/dshills/goflow/pkg/events"
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goflow/pkg/transform"
 	"github.com/dshills/goflow/pkg/workflow"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -21,14 +26,33 @@ type WorkflowBuilder struct {
 	helpPanel        *HelpPanel
 	validationPanel  *ValidationPanel
 	selectedNodeID   string
-	mode             string // "normal", "edit", "palette", "help"
+	markedNodeIDs    map[string]bool // Multi-selection for align/distribute
+	clipboard        *nodeClipboard  // Subgraph yanked for paste
+	systemClipboard  SystemClipboard // Also receives yanks as YAML, if set
+	mode             string          // "normal", "visual", "edit", "palette", "help"
 	edgeCreationMode bool
 	edgeSourceID     string
 	modified         bool
 	validationStatus *ValidationStatus
 	undoStack        *UndoStack
 	repository       workflow.WorkflowRepository
+	servers          mcpserver.ServerRepository // Completes server IDs and tool names, if set
+	sampleStore      *storage.SampleStore       // Variables of the last run, for previews, if set
+	toolSamples      *storage.ToolSampleStore   // Recent tool outputs, for field suggestions, if set
+	sampleInput      map[string]interface{}     // Sample variables entered by the user
 	keyEnabled       map[string]bool
+	readOnly         string // Why the workflow can't be changed, if it can't
+
+	// Search (see Search)
+	searchQuery string
+	searchIndex int // Match last jumped to
+
+	// Validation debounce and autosave (see Tick)
+	validationDebounce time.Duration // 0 = validate immediately
+	validationPending  bool
+	validationDue      time.Time
+	autosaveInterval   time.Duration // 0 = autosave disabled
+	lastSave           time.Time
 }
 
 // workflowSnapshot is defined in undo_stack.go
@@ -46,6 +70,14 @@ type PropertyPanel struct {
 	editIndex         int
 	visible           bool
 	validationMessage string
+	editing           bool             // The focused field's value is being typed
+	editBuffer        string           // Value being typed
+	completer         Autocomplete     // Completions for the value being typed
+	candidates        completionSource // Suggests values for fields, if set
+	schemas           schemaSource     // Describes tool arguments, if set
+	previews          previewSource    // Evaluates expressions being typed, if set
+	preview           string           // Result of the expression being typed
+	previewFailed     bool             // preview is an error
 }
 
 // propertyField represents an editable property
@@ -57,6 +89,8 @@ type propertyField struct {
 	fieldType    string             // "text", "expression", "condition", "jsonpath", "template"
 	validationFn func(string) error // Validation function
 	helpText     string             // Syntax hints
+	param        string             // Tool argument name, for argument fields
+	enum         []string           // Allowed values, offered as completions
 }
 
 // HelpPanel is defined in help_panel.go
@@ -82,12 +116,15 @@ func NewWorkflowBuilder(wf *workflow.Workflow) (*WorkflowBuilder, error) {
 		validationPanel:  NewValidationPanel(NewValidationStatus()),
 		mode:             "normal",
 		validationStatus: NewValidationStatus(),
-		undoStack:        NewUndoStack(100),
+		undoStack:        NewUndoStack(DefaultUndoDepth),
 		keyEnabled:       make(map[string]bool),
+		markedNodeIDs:    make(map[string]bool),
+		lastSave:         time.Now(),
 	}
 
-	// Initialize canvas with workflow nodes
+	// Initialize canvas with workflow nodes, laid out as when last saved
 	builder.layoutNodes()
+	builder.applyCanvasView()
 
 	// Run initial validation
 	builder.validateWorkflow()
@@ -129,11 +166,17 @@ func (b *WorkflowBuilder) SelectNode(nodeID string) error {
 			break
 		}
 	}
+	if _, isGroup := groupName(nodeID); isGroup {
+		_, found = b.canvas.nodes[nodeID] // A collapsed group
+	}
 
 	if !found {
 		return fmt.Errorf("node not found: %s", nodeID)
 	}
 
+	// A node hidden in a collapsed group selects the group
+	nodeID = b.visibleNodeID(nodeID)
+
 	b.selectedNodeID = nodeID
 	b.canvas.selectedID = nodeID
 	// Reset navigation state for both directions
@@ -142,6 +185,14 @@ func (b *WorkflowBuilder) SelectNode(nodeID string) error {
 	return nil
 }
 
+// JumpToNode selects a node and centers the canvas on it
+func (b *WorkflowBuilder) JumpToNode(nodeID string) error {
+	if err := b.SelectNode(nodeID); err != nil {
+		return err
+	}
+	return b.canvas.CenterOn(b.selectedNodeID)
+}
+
 // GetSelectedNodeID returns the currently selected node ID
 func (b *WorkflowBuilder) GetSelectedNodeID() string {
 	return b.selectedNodeID
@@ -155,6 +206,11 @@ func (b *WorkflowBuilder) IsNodeHighlighted(nodeID string) bool {
 // HandleKey processes keyboard input
 // This implements T079 from Phase 10: Keyboard Handling (dispatcher)
 func (b *WorkflowBuilder) HandleKey(key string) error {
+	// A property value being typed takes every key
+	if b.mode == "edit" && b.propertyPanel.IsEditing() {
+		return b.handleFieldInput(key)
+	}
+
 	// Global keys work in all modes
 	switch key {
 	case "?":
@@ -168,15 +224,19 @@ func (b *WorkflowBuilder) HandleKey(key string) error {
 		b.updateKeyStates()
 		return nil
 
-	case "Esc":
+	case "Esc", "Escape":
 		// Escape returns to normal mode from any mode
 		switch b.mode {
+		case "visual":
+			b.ClearNodeSelection()
 		case "edit":
 			b.CancelPropertyEdit()
 		case "palette":
 			b.palette.Hide()
 		case "help":
 			b.helpPanel.visible = false
+		case "normal":
+			b.ClearSearch()
 		}
 		b.mode = "normal"
 		b.edgeCreationMode = false
@@ -189,6 +249,11 @@ func (b *WorkflowBuilder) HandleKey(key string) error {
 		return fmt.Errorf("quit requested")
 	}
 
+	// A read-only workflow can be explored but not changed
+	if b.readOnly != "" && editingKeys[b.mode][key] {
+		return b.checkWritable()
+	}
+
 	// Handle Tab/Shift+Tab for node navigation (works in normal mode)
 	if b.mode == "normal" {
 		switch key {
@@ -209,6 +274,8 @@ func (b *WorkflowBuilder) HandleKey(key string) error {
 	switch b.mode {
 	case "normal":
 		return b.handleNormalMode(key)
+	case "visual":
+		return b.handleVisualMode(key)
 	case "edit":
 		return b.handleEditMode(key)
 	case "palette":
@@ -228,6 +295,10 @@ func (b *WorkflowBuilder) GetValidationStatus() *ValidationStatus {
 // SaveWorkflow saves the workflow to storage
 // This implements T070 from Phase 8 integration tasks
 func (b *WorkflowBuilder) SaveWorkflow() error {
+	if err := b.checkWritable(); err != nil {
+		return fmt.Errorf("cannot save workflow: %w", err)
+	}
+
 	// Step 1: Validate workflow (run validation)
 	if err := b.workflow.Validate(); err != nil {
 		// Step 2: If errors, show validation panel and prevent save
@@ -236,9 +307,15 @@ func (b *WorkflowBuilder) SaveWorkflow() error {
 		return fmt.Errorf("cannot save invalid workflow: %w", err)
 	}
 
+	// Warnings do not block saving, but rules the workflow raises to
+	// errors do
+	if _, lintErrors := LintFindings(b.workflow); len(lintErrors) > 0 {
+		b.validationStatus.IsValid = false
+		return fmt.Errorf("cannot save workflow: %s (%s)", lintErrors[0].Message, lintErrors[0].ErrorType)
+	}
+
 	// Step 3: Persist canvas state to workflow metadata (positions, zoom)
-	// TODO: Add canvas metadata to workflow when metadata structure is defined
-	// For now, canvas positions are saved in undo snapshots
+	b.workflow.Metadata.Canvas = b.canvasLayout()
 
 	// Step 4: Call repository.Save(workflow)
 	if b.repository != nil {
@@ -249,6 +326,7 @@ func (b *WorkflowBuilder) SaveWorkflow() error {
 
 	// Step 5: Clear modified flag
 	b.modified = false
+	b.lastSave = time.Now()
 
 	// Step 6: Show status message (in real TUI)
 	// Status message would appear in status bar
@@ -279,7 +357,10 @@ func (b *WorkflowBuilder) LoadWorkflow(name string) error {
 	}
 
 	b.workflow = wf
+	b.canvas.forgetGroups()
+	b.canvas.nodes = make(map[string]*canvasNode)
 	b.layoutNodes()
+	b.applyCanvasView()
 	b.validateWorkflow()
 	b.modified = false
 
@@ -384,6 +465,15 @@ func (b *WorkflowBuilder) Undo() error {
 		return errors.New("nothing to undo")
 	}
 
+	// Snapshots are pushed before each change, so the current state is only
+	// on the stack if it was reached by undo or redo. Record it so the
+	// change can be redone.
+	if !b.undoStack.CanRedo() {
+		if err := b.undoStack.Push(b.workflow, b.getCanvasPositions()); err != nil {
+			return fmt.Errorf("undo failed: %w", err)
+		}
+	}
+
 	// Step 2: Pop snapshot from undo stack (redo is handled internally)
 	snapshot, err := b.undoStack.Undo()
 	if err != nil {
@@ -401,6 +491,7 @@ func (b *WorkflowBuilder) Undo() error {
 		// Snapshot is nil, meaning we've undone to before first snapshot (empty state)
 		b.workflow.Nodes = []workflow.Node{}
 		b.workflow.Edges = []*workflow.Edge{}
+		b.canvas.forgetGroups()
 		b.canvas.nodes = make(map[string]*canvasNode)
 		b.canvas.edges = make([]*canvasEdge, 0)
 	}
@@ -440,6 +531,13 @@ func (b *WorkflowBuilder) Redo() error {
 	return nil
 }
 
+// SetUndoLimits sets how many undo steps are kept and the estimated memory
+// they may use; zero memory means no limit
+func (b *WorkflowBuilder) SetUndoLimits(depth, memoryBytes int) {
+	b.undoStack.SetCapacity(depth)
+	b.undoStack.SetMemoryLimit(memoryBytes)
+}
+
 // CanUndo returns whether undo is available
 func (b *WorkflowBuilder) CanUndo() bool {
 	return b.undoStack.CanUndo()
@@ -482,6 +580,15 @@ func (b *WorkflowBuilder) AddNodeAtPosition(nodeType string, pos Position) error
 			ToolName:       "",
 			OutputVariable: "",
 		}
+	case "Batch Tool":
+		node = &workflow.BatchToolNode{
+			ID:             nodeID,
+			ServerID:       "",
+			ToolName:       "",
+			Collection:     "",
+			ItemVariable:   "item",
+			OutputVariable: "",
+		}
 	case "Transform":
 		node = &workflow.TransformNode{
 			ID:             nodeID,
@@ -494,6 +601,11 @@ func (b *WorkflowBuilder) AddNodeAtPosition(nodeType string, pos Position) error
 			ID:        nodeID,
 			Condition: "",
 		}
+	case "Switch":
+		node = &workflow.SwitchNode{
+			ID:    nodeID,
+			Cases: []workflow.SwitchCase{},
+		}
 	case "Loop":
 		node = &workflow.LoopNode{
 			ID:           nodeID,
@@ -507,6 +619,26 @@ func (b *WorkflowBuilder) AddNodeAtPosition(nodeType string, pos Position) error
 			Branches:      [][]string{},
 			MergeStrategy: "wait_all",
 		}
+	case "Try":
+		node = &workflow.TryNode{
+			ID:   nodeID,
+			Body: []string{},
+		}
+	case "Catch":
+		node = &workflow.CatchNode{
+			ID:            nodeID,
+			ErrorVariable: "error",
+		}
+	case "Delay":
+		node = &workflow.DelayNode{
+			ID:       nodeID,
+			Duration: "1m",
+		}
+	case "Approval":
+		node = &workflow.ApprovalNode{
+			ID:      nodeID,
+			Message: "Approve to continue?",
+		}
 	default:
 		return fmt.Errorf("unknown node type: %s", nodeType)
 	}
@@ -594,6 +726,7 @@ func (b *WorkflowBuilder) DeleteNode(nodeID string) error {
 	if b.selectedNodeID == nodeID {
 		b.selectedNodeID = ""
 	}
+	delete(b.markedNodeIDs, nodeID)
 
 	return nil
 }
@@ -626,10 +759,16 @@ func (b *WorkflowBuilder) CreateEdge(fromID, toID string) error {
 		return fmt.Errorf("failed to save undo snapshot: %w", err)
 	}
 
-	// Step 3: Create edge
+	// Step 3: Create edge; edges leaving a switch take the next unconnected
+	// case, and edges into a catch node are error edges
 	edge := &workflow.Edge{
 		FromNodeID: fromID,
 		ToNodeID:   toID,
+		Condition:  b.nextSwitchCase(fromID),
+	}
+	if b.isCatchNode(toID) {
+		edge.Condition = ""
+		edge.OnError = true
 	}
 
 	// Step 4: Add to workflow (validates circular dependency internally)
@@ -726,100 +865,200 @@ func (b *WorkflowBuilder) GetActionForKey(key string) (string, error) {
 // Internal helper methods
 
 func (b *WorkflowBuilder) layoutNodes() {
-	// Simple vertical layout
+	b.canvas.expandGroups()
+
+	// Nodes keep their place on the canvas, or else the place saved with
+	// the workflow
+	positions := make(map[string]Position, len(b.workflow.Nodes))
+	for _, node := range b.workflow.Nodes {
+		nodeID := node.GetID()
+		if cNode, exists := b.canvas.nodes[nodeID]; exists {
+			positions[nodeID] = cNode.position
+		} else if pos, ok := b.savedPosition(nodeID); ok {
+			positions[nodeID] = pos
+		}
+	}
+
+	// Simple vertical layout for the rest, below the placed nodes
 	y := 2
 	x := 5
+	for _, pos := range positions {
+		if pos.Y+4 > y {
+			y = pos.Y + 4
+		}
+	}
 
 	for _, node := range b.workflow.Nodes {
 		nodeID := node.GetID()
+		pos, placed := positions[nodeID]
+		if !placed {
+			pos = Position{X: x, Y: y}
+			y += 4
+		}
 		b.canvas.nodes[nodeID] = &canvasNode{
-			node: node,
-			position: Position{
-				X: x,
-				Y: y,
-			},
-			width:  20,
-			height: 3,
+			node:     node,
+			position: pos,
+			width:    20,
+			height:   3,
 		}
-		y += 4
 	}
 
 	// Update edges with positions
 	b.canvas.edges = make([]*canvasEdge, 0)
 	for _, edge := range b.workflow.Edges {
-		fromNode, fromExists := b.canvas.nodes[edge.FromNodeID]
-		toNode, toExists := b.canvas.nodes[edge.ToNodeID]
+		_, fromExists := b.canvas.nodes[edge.FromNodeID]
+		_, toExists := b.canvas.nodes[edge.ToNodeID]
 
 		if fromExists && toExists {
-			b.canvas.edges = append(b.canvas.edges, &canvasEdge{
-				edge:          edge,
-				routingPoints: []Position{fromNode.position, toNode.position},
-			})
+			b.canvas.edges = append(b.canvas.edges, &canvasEdge{edge: edge})
 		}
 	}
+	b.canvas.routeEdges()
+	b.applyGroups()
+	b.canvas.SetNotes(b.workflow.Metadata.Notes)
+}
+
+// SetValidationDebounce delays validation until edits pause for d.
+// Zero validates immediately after every change.
+func (b *WorkflowBuilder) SetValidationDebounce(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	b.validationDebounce = d
+	if d == 0 && b.validationPending {
+		b.runValidation()
+	}
+}
+
+// SetAutosaveInterval saves modified workflows every d through the
+// configured repository. Zero disables autosave.
+func (b *WorkflowBuilder) SetAutosaveInterval(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	b.autosaveInterval = d
+}
+
+// ValidationPending returns whether a debounced validation has not run yet
+func (b *WorkflowBuilder) ValidationPending() bool {
+	return b.validationPending
+}
+
+// Tick runs time-based work: pending debounced validation and autosave.
+// It is called periodically by the view. Returns an error if autosave fails;
+// invalid workflows are skipped and retried on the next interval.
+func (b *WorkflowBuilder) Tick(now time.Time) error {
+	if b.validationPending && !now.Before(b.validationDue) {
+		b.runValidation()
+	}
+
+	if b.autosaveInterval <= 0 || b.repository == nil || !b.modified || b.readOnly != "" {
+		return nil
+	}
+	if now.Sub(b.lastSave) < b.autosaveInterval {
+		return nil
+	}
+
+	// Record the attempt so failures are retried on the next interval,
+	// not on every tick
+	b.lastSave = now
+
+	if err := b.workflow.Validate(); err != nil {
+		return nil
+	}
+	if err := b.SaveWorkflow(); err != nil {
+		return fmt.Errorf("autosave failed: %w", err)
+	}
+	return nil
 }
 
+// FlushAutosave saves modified workflows right away instead of waiting for
+// the next autosave interval. It is called on shutdown and does nothing when
+// autosave is disabled or the workflow is invalid, matching Tick.
+func (b *WorkflowBuilder) FlushAutosave() error {
+	if b.autosaveInterval <= 0 || b.repository == nil || !b.modified || b.readOnly != "" {
+		return nil
+	}
+	if err := b.workflow.Validate(); err != nil {
+		return nil
+	}
+	if err := b.SaveWorkflow(); err != nil {
+		return fmt.Errorf("autosave failed: %w", err)
+	}
+	return nil
+}
+
+// validateWorkflow validates after a change, honoring the debounce interval
 func (b *WorkflowBuilder) validateWorkflow() {
-	err := b.workflow.Validate()
-	if err == nil {
-		b.validationStatus = &ValidationStatus{
-			IsValid: true,
-			Errors:  []ValidationError{},
-		}
+	if b.validationDebounce > 0 {
+		b.validationPending = true
+		b.validationDue = time.Now().Add(b.validationDebounce)
 		return
 	}
+	b.runValidation()
+}
 
-	// Parse error message to extract validation errors
-	// Split compound errors on common separators: semicolon and newline
-	errMsg := err.Error()
-	var errorMessages []string
+// runValidation validates the workflow and updates the validation status:
+// the structural errors of workflow.Validate, plus the lint warnings of
+// ValidateWorkflow at the severities the workflow gives them
+func (b *WorkflowBuilder) runValidation() {
+	b.validationPending = false
 
-	// Try splitting on semicolon first (most common for compound errors)
-	if strings.Contains(errMsg, ";") {
-		parts := strings.Split(errMsg, ";")
-		for _, part := range parts {
-			trimmed := strings.TrimSpace(part)
-			if trimmed != "" {
-				errorMessages = append(errorMessages, trimmed)
-			}
-		}
-	} else if strings.Contains(errMsg, "\n") {
-		// Try splitting on newline
-		parts := strings.Split(errMsg, "\n")
-		for _, part := range parts {
-			trimmed := strings.TrimSpace(part)
-			if trimmed != "" {
-				errorMessages = append(errorMessages, trimmed)
-			}
+	status := NewValidationStatus()
+	var errorMessages []string
+	if err := b.workflow.Validate(); err != nil {
+		errorMessages = splitValidationErrors(err)
+		for _, msg := range errorMessages {
+			status.AddError("", "", msg)
 		}
-	} else {
-		// Single error message
-		errorMessages = []string{errMsg}
 	}
 
-	// Convert to ValidationError slice
-	errors := make([]ValidationError, 0, len(errorMessages))
-	for _, msg := range errorMessages {
-		errors = append(errors, ValidationError{
-			Message: msg,
-			NodeID:  "",
-		})
+	warnings, lintErrors := LintFindings(b.workflow)
+	status.Warnings = append(status.Warnings, warnings...)
+	for _, lintErr := range lintErrors {
+		status.AddError(lintErr.NodeID, lintErr.ErrorType, lintErr.Message)
+		errorMessages = append(errorMessages, lintErr.Message)
+	}
+	status.SetValidated()
+
+	b.validationStatus = status
+	if len(errorMessages) > 0 {
+		events.Default().Publish(events.ValidationFailed(b.workflow.Name, errorMessages...))
+	}
+}
+
+// splitValidationErrors splits a compound validation error into its
+// messages, on semicolons or else newlines
+func splitValidationErrors(err error) []string {
+	errMsg := err.Error()
+	separator := ""
+	switch {
+	case strings.Contains(errMsg, ";"):
+		separator = ";"
+	case strings.Contains(errMsg, "\n"):
+		separator = "\n"
+	default:
+		return []string{errMsg}
 	}
 
-	b.validationStatus = &ValidationStatus{
-		IsValid: false,
-		Errors:  errors,
+	var messages []string
+	for _, part := range strings.Split(errMsg, separator) {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			messages = append(messages, trimmed)
+		}
 	}
+	return messages
 }
 
 func (b *WorkflowBuilder) selectNextNode() error {
-	if len(b.workflow.Nodes) == 0 {
+	order := b.navigationOrder()
+	if len(order) == 0 {
 		return nil
 	}
 
 	// If nothing selected, select first node (don't advance)
 	if b.selectedNodeID == "" {
-		b.selectedNodeID = b.workflow.Nodes[0].GetID()
+		b.selectedNodeID = order[0]
 		b.canvas.selectedID = b.selectedNodeID
 		b.keyEnabled["forward"] = true // Mark that next forward navigation should work
 		return nil
@@ -834,8 +1073,8 @@ func (b *WorkflowBuilder) selectNextNode() error {
 
 	// Find current index
 	currentIdx := -1
-	for i, node := range b.workflow.Nodes {
-		if node.GetID() == b.selectedNodeID {
+	for i, id := range order {
+		if id == b.selectedNodeID {
 			currentIdx = i
 			break
 		}
@@ -843,20 +1082,21 @@ func (b *WorkflowBuilder) selectNextNode() error {
 
 	// If current not found, select first
 	if currentIdx == -1 {
-		b.selectedNodeID = b.workflow.Nodes[0].GetID()
+		b.selectedNodeID = order[0]
 		b.canvas.selectedID = b.selectedNodeID
 		return nil
 	}
 
-	nextIdx := (currentIdx + 1) % len(b.workflow.Nodes)
-	b.selectedNodeID = b.workflow.Nodes[nextIdx].GetID()
+	nextIdx := (currentIdx + 1) % len(order)
+	b.selectedNodeID = order[nextIdx]
 	b.canvas.selectedID = b.selectedNodeID
 
 	return nil
 }
 
 func (b *WorkflowBuilder) selectPreviousNode() error {
-	if len(b.workflow.Nodes) == 0 {
+	order := b.navigationOrder()
+	if len(order) == 0 {
 		return nil
 	}
 
@@ -869,8 +1109,8 @@ func (b *WorkflowBuilder) selectPreviousNode() error {
 
 	// Find current index
 	currentIdx := -1
-	for i, node := range b.workflow.Nodes {
-		if node.GetID() == b.selectedNodeID {
+	for i, id := range order {
+		if id == b.selectedNodeID {
 			currentIdx = i
 			break
 		}
@@ -878,10 +1118,10 @@ func (b *WorkflowBuilder) selectPreviousNode() error {
 
 	prevIdx := currentIdx - 1
 	if prevIdx < 0 {
-		prevIdx = len(b.workflow.Nodes) - 1
+		prevIdx = len(order) - 1
 	}
 
-	b.selectedNodeID = b.workflow.Nodes[prevIdx].GetID()
+	b.selectedNodeID = order[prevIdx]
 	b.canvas.selectedID = b.selectedNodeID
 
 	return nil
@@ -904,6 +1144,9 @@ func (b *WorkflowBuilder) EditNodeProperties(nodeID string) error {
 
 	// Step 2: Open property panel for selected node
 	b.propertyPanel = NewPropertyPanel(node)
+	b.propertyPanel.SetCompletionSource(b.completionCandidates)
+	b.propertyPanel.SetSchemaSource(b.toolSchema)
+	b.propertyPanel.SetPreviewSource(b.previewExpression)
 	b.propertyPanel.Show()
 
 	// Step 3: Enter edit mode
@@ -970,7 +1213,12 @@ func (b *WorkflowBuilder) CancelPropertyEdit() {
 func (b *WorkflowBuilder) getCanvasPositions() map[string]Position {
 	positions := make(map[string]Position)
 	for nodeID, canvasNode := range b.canvas.nodes {
-		positions[nodeID] = canvasNode.position
+		if _, isGroup := groupName(nodeID); !isGroup {
+			positions[nodeID] = canvasNode.position
+		}
+	}
+	for _, canvasNode := range b.canvas.hiddenNodes() {
+		positions[canvasNode.node.GetID()] = canvasNode.position
 	}
 	return positions
 }
@@ -978,6 +1226,7 @@ func (b *WorkflowBuilder) getCanvasPositions() map[string]Position {
 // restoreCanvasPositions restores node positions from snapshot
 func (b *WorkflowBuilder) restoreCanvasPositions(positions map[string]Position) {
 	// Clear current canvas nodes
+	b.canvas.forgetGroups()
 	b.canvas.nodes = make(map[string]*canvasNode)
 
 	// Recreate canvas nodes with restored positions
@@ -995,17 +1244,19 @@ func (b *WorkflowBuilder) restoreCanvasPositions(positions map[string]Position)
 			position:         pos,
 			width:            width,
 			height:           height,
-			selected:         nodeID == b.selectedNodeID,
+			selected:         b.isNodeSelected(nodeID),
 			highlighted:      false,
 			validationStatus: "valid",
 		}
 	}
+	b.pruneNodeSelection()
 
 	// Rebuild edges
 	b.canvas.edges = make([]*canvasEdge, 0)
 	for _, edge := range b.workflow.Edges {
 		_ = b.canvas.AddEdge(edge) // Ignore error in restore - best effort
 	}
+	b.applyGroups()
 }
 
 // getNextAutoPosition calculates the next auto-position for a new node
@@ -1057,6 +1308,9 @@ func (b *WorkflowBuilder) ShowPropertyPanel(nodeID string) error {
 	// Build property fields based on node type
 	b.propertyPanel.node = node
 	b.propertyPanel.fields = b.buildPropertyFields(node)
+	b.propertyPanel.SetCompletionSource(b.completionCandidates)
+	b.propertyPanel.SetPreviewSource(b.previewExpression)
+	b.propertyPanel.CancelEdit()
 	b.propertyPanel.visible = true
 	b.propertyPanel.editIndex = 0
 	b.propertyPanel.validationMessage = ""
@@ -1089,6 +1343,16 @@ func (b *WorkflowBuilder) buildPropertyFields(node workflow.Node) []propertyFiel
 			},
 		})
 
+	case *workflow.SwitchNode:
+		fields = append(fields, propertyField{
+			label:        "Cases",
+			value:        formatSwitchCases(n.Cases),
+			required:     true,
+			valid:        true,
+			fieldType:    "cases",
+			validationFn: validateCasesField,
+		})
+
 	case *workflow.TransformNode:
 		fields = append(fields,
 			propertyField{
@@ -1106,6 +1370,9 @@ func (b *WorkflowBuilder) buildPropertyFields(node workflow.Node) []propertyFiel
 				fieldType: "expression",
 				validationFn: func(expr string) error {
 					// Detect expression type and validate accordingly
+					if n.IsJQ() {
+						return transform.CheckJQ(expr)
+					}
 					if len(expr) > 0 && expr[0] == '$' {
 						return workflow.ValidateJSONPathSyntax(expr)
 					}
@@ -1115,6 +1382,14 @@ func (b *WorkflowBuilder) buildPropertyFields(node workflow.Node) []propertyFiel
 					return workflow.ValidateExpressionSyntax(expr)
 				},
 			},
+			propertyField{
+				label:        "Language",
+				value:        n.Language,
+				valid:        true,
+				fieldType:    "language",
+				validationFn: validateLanguageField,
+				enum:         transform.TransformTypeNames(),
+			},
 			propertyField{
 				label:     "Output Variable",
 				value:     n.OutputVariable,
@@ -1122,6 +1397,13 @@ func (b *WorkflowBuilder) buildPropertyFields(node workflow.Node) []propertyFiel
 				valid:     true,
 				fieldType: "text",
 			},
+			propertyField{
+				label:     "Item Errors Output",
+				value:     n.ItemErrorsOutput,
+				required:  false,
+				valid:     true,
+				fieldType: "text",
+			},
 		)
 
 	case *workflow.MCPToolNode:
@@ -1147,6 +1429,75 @@ func (b *WorkflowBuilder) buildPropertyFields(node workflow.Node) []propertyFiel
 				valid:     true,
 				fieldType: "text",
 			},
+			propertyField{
+				label:        "Cache TTL",
+				value:        n.CacheTTL,
+				required:     false,
+				valid:        true,
+				fieldType:    "text",
+				validationFn: validateDurationField,
+			},
+			propertyField{
+				label:     "Stream Output",
+				value:     n.StreamOutput,
+				required:  false,
+				valid:     true,
+				fieldType: "text",
+			},
+		)
+
+	case *workflow.BatchToolNode:
+		fields = append(fields,
+			propertyField{
+				label:     "Server ID",
+				value:     n.ServerID,
+				required:  true,
+				valid:     true,
+				fieldType: "text",
+			},
+			propertyField{
+				label:     "Tool Name",
+				value:     n.ToolName,
+				required:  true,
+				valid:     true,
+				fieldType: "text",
+			},
+			propertyField{
+				label:     "Collection",
+				value:     n.Collection,
+				required:  true,
+				valid:     true,
+				fieldType: "text",
+			},
+			propertyField{
+				label:     "Item Variable",
+				value:     n.ItemVariable,
+				required:  true,
+				valid:     true,
+				fieldType: "text",
+			},
+			propertyField{
+				label:        "Max Concurrency",
+				value:        formatMaxConcurrency(n.MaxConcurrency),
+				required:     false,
+				valid:        true,
+				fieldType:    "text",
+				validationFn: validateConcurrencyField,
+			},
+			propertyField{
+				label:     "Output Variable",
+				value:     n.OutputVariable,
+				required:  true,
+				valid:     true,
+				fieldType: "text",
+			},
+			propertyField{
+				label:     "Error Variable",
+				value:     n.ErrorVariable,
+				required:  false,
+				valid:     true,
+				fieldType: "text",
+			},
 		)
 
 	case *workflow.LoopNode:
@@ -1221,6 +1572,98 @@ func (b *WorkflowBuilder) buildPropertyFields(node workflow.Node) []propertyFiel
 					return nil
 				},
 			},
+			propertyField{
+				label:     "On Conflict",
+				value:     n.OnConflict,
+				required:  false,
+				valid:     true,
+				fieldType: "select",
+				validationFn: func(policy string) error {
+					switch policy {
+					case "", workflow.ConflictLastBranch, workflow.ConflictError, workflow.ConflictCollect:
+						return nil
+					}
+					return fmt.Errorf("invalid on_conflict policy: %s (use last_branch, error, or collect)", policy)
+				},
+			},
+		)
+
+	case *workflow.TryNode:
+		fields = append(fields, propertyField{
+			label:     "Body Nodes",
+			value:     strings.Join(n.Body, ", "),
+			required:  true,
+			valid:     true,
+			fieldType: "node_list",
+		})
+
+	case *workflow.CatchNode:
+		fields = append(fields, propertyField{
+			label:     "Error Variable",
+			value:     n.ErrorVariable,
+			required:  true,
+			valid:     true,
+			fieldType: "text",
+		})
+
+	case *workflow.DelayNode:
+		fields = append(fields,
+			propertyField{
+				label:        "Duration",
+				value:        n.Duration,
+				required:     false,
+				valid:        true,
+				fieldType:    "text",
+				validationFn: validateDurationField,
+			},
+			propertyField{
+				label:        "Until",
+				value:        n.Until,
+				required:     false,
+				valid:        true,
+				fieldType:    "text",
+				validationFn: validateTimestampField,
+			},
+		)
+
+	case *workflow.ApprovalNode:
+		fields = append(fields,
+			propertyField{
+				label:     "Message",
+				value:     n.Message,
+				required:  false,
+				valid:     true,
+				fieldType: "text",
+			},
+			propertyField{
+				label:        "Timeout",
+				value:        n.Timeout,
+				required:     false,
+				valid:        true,
+				fieldType:    "text",
+				validationFn: validateDurationField,
+			},
+			propertyField{
+				label:     "Default Action",
+				value:     n.DefaultAction,
+				required:  false,
+				valid:     true,
+				fieldType: "select",
+				validationFn: func(action string) error {
+					switch action {
+					case "", workflow.ApprovalApprove, workflow.ApprovalReject:
+						return nil
+					}
+					return fmt.Errorf("invalid default action: %s (use approve or reject)", action)
+				},
+			},
+			propertyField{
+				label:     "Output Variable",
+				value:     n.OutputVariable,
+				required:  false,
+				valid:     true,
+				fieldType: "text",
+			},
 		)
 	}
 
@@ -1268,6 +1711,17 @@ func (b *WorkflowBuilder) applyPropertyChanges() error {
 			}
 		}
 
+	case *workflow.SwitchNode:
+		for _, field := range fields {
+			if field.label == "Cases" {
+				cases, err := parseSwitchCases(field.value)
+				if err != nil {
+					return err
+				}
+				n.Cases = cases
+			}
+		}
+
 	case *workflow.TransformNode:
 		for _, field := range fields {
 			switch field.label {
@@ -1275,8 +1729,12 @@ func (b *WorkflowBuilder) applyPropertyChanges() error {
 				n.InputVariable = field.value
 			case "Expression":
 				n.Expression = field.value
+			case "Language":
+				n.Language = field.value
 			case "Output Variable":
 				n.OutputVariable = field.value
+			case "Item Errors Output":
+				n.ItemErrorsOutput = field.value
 			}
 		}
 
@@ -1289,6 +1747,34 @@ func (b *WorkflowBuilder) applyPropertyChanges() error {
 				n.ToolName = field.value
 			case "Output Variable":
 				n.OutputVariable = field.value
+			case "Cache TTL":
+				n.CacheTTL = field.value
+			case "Stream Output":
+				n.StreamOutput = field.value
+			}
+		}
+
+	case *workflow.BatchToolNode:
+		for _, field := range fields {
+			switch field.label {
+			case "Server ID":
+				n.ServerID = field.value
+			case "Tool Name":
+				n.ToolName = field.value
+			case "Collection":
+				n.Collection = field.value
+			case "Item Variable":
+				n.ItemVariable = field.value
+			case "Max Concurrency":
+				limit, err := parseMaxConcurrency(field.value)
+				if err != nil {
+					return err
+				}
+				n.MaxConcurrency = limit
+			case "Output Variable":
+				n.OutputVariable = field.value
+			case "Error Variable":
+				n.ErrorVariable = field.value
 			}
 		}
 
@@ -1300,20 +1786,7 @@ func (b *WorkflowBuilder) applyPropertyChanges() error {
 			case "Item Variable":
 				n.ItemVariable = field.value
 			case "Body Nodes":
-				// Parse comma-separated node IDs
-				if field.value != "" {
-					nodeIDs := strings.Split(field.value, ",")
-					cleanedIDs := make([]string, 0, len(nodeIDs))
-					for _, id := range nodeIDs {
-						id = strings.TrimSpace(id)
-						if id != "" {
-							cleanedIDs = append(cleanedIDs, id)
-						}
-					}
-					n.Body = cleanedIDs
-				} else {
-					n.Body = []string{}
-				}
+				n.Body = parseNodeList(field.value)
 			case "Break Condition":
 				n.BreakCondition = field.value
 			}
@@ -1351,6 +1824,46 @@ func (b *WorkflowBuilder) applyPropertyChanges() error {
 				}
 			case "Merge Strategy":
 				n.MergeStrategy = field.value
+			case "On Conflict":
+				n.OnConflict = field.value
+			}
+		}
+
+	case *workflow.TryNode:
+		for _, field := range fields {
+			if field.label == "Body Nodes" {
+				n.Body = parseNodeList(field.value)
+			}
+		}
+
+	case *workflow.CatchNode:
+		for _, field := range fields {
+			if field.label == "Error Variable" {
+				n.ErrorVariable = field.value
+			}
+		}
+
+	case *workflow.DelayNode:
+		for _, field := range fields {
+			switch field.label {
+			case "Duration":
+				n.Duration = field.value
+			case "Until":
+				n.Until = field.value
+			}
+		}
+
+	case *workflow.ApprovalNode:
+		for _, field := range fields {
+			switch field.label {
+			case "Message":
+				n.Message = field.value
+			case "Timeout":
+				n.Timeout = field.value
+			case "Default Action":
+				n.DefaultAction = field.value
+			case "Output Variable":
+				n.OutputVariable = field.value
 			}
 		}
 	}
@@ -1374,16 +1887,24 @@ func (b *WorkflowBuilder) GetVariableList() []string {
 	return vars
 }
 
-// GetEdgeLabel returns the label for an edge (e.g., "true"/"false" for condition edges)
+// GetEdgeLabel returns the label for an edge (e.g., "true"/"false" for condition edges,
+// the case for switch edges, or "on error" for error edges)
 func (b *WorkflowBuilder) GetEdgeLabel(edge *workflow.Edge) string {
 	if edge.Condition != "" {
 		return edge.Condition
 	}
+	if edge.OnError {
+		return "on error"
+	}
 	return ""
 }
 
 // GetEdgeStyle returns style information for an edge
 func (b *WorkflowBuilder) GetEdgeStyle(edge *workflow.Edge) string {
+	if edge.OnError {
+		return "dashed"
+	}
+
 	// Check if this edge is from a condition node
 	for _, node := range b.workflow.Nodes {
 		if node.GetID() == edge.FromNodeID && node.Type() == "condition" {
@@ -1398,30 +1919,37 @@ func (b *WorkflowBuilder) GetEdgeStyle(edge *workflow.Edge) string {
 	return "solid"
 }
 
-// CreateConditionalEdge creates an edge with a condition label
+// CreateConditionalEdge creates an edge with a condition label: "true" or
+// "false" from a condition node, or a case label or "default" from a switch
+// node
 func (b *WorkflowBuilder) CreateConditionalEdge(fromID, toID, condition string) error {
-	// Verify source is a condition node
-	var isConditionNode bool
+	// Verify source is a condition or switch node
+	var source workflow.Node
 	for _, node := range b.workflow.Nodes {
-		if node.GetID() == fromID && node.Type() == "condition" {
-			isConditionNode = true
+		if node.GetID() == fromID {
+			source = node
 			break
 		}
 	}
 
-	if !isConditionNode {
-		return fmt.Errorf("source node %s is not a condition node", fromID)
-	}
-
 	// Verify condition value
-	if condition != "true" && condition != "false" {
-		return fmt.Errorf("condition must be 'true' or 'false', got: %s", condition)
+	switch n := source.(type) {
+	case *workflow.ConditionNode:
+		if condition != "true" && condition != "false" {
+			return fmt.Errorf("condition must be 'true' or 'false', got: %s", condition)
+		}
+	case *workflow.SwitchNode:
+		if condition != workflow.SwitchDefaultCase && !n.HasCase(condition) {
+			return fmt.Errorf("switch node %s has no case %q", fromID, condition)
+		}
+	default:
+		return fmt.Errorf("source node %s is not a condition node", fromID)
 	}
 
 	// Check if this condition already has an edge
 	for _, edge := range b.workflow.Edges {
 		if edge.FromNodeID == fromID && edge.Condition == condition {
-			return fmt.Errorf("condition node already has a %s edge", condition)
+			return fmt.Errorf("%s node already has a %s edge", source.Type(), condition)
 		}
 	}
 
@@ -1448,6 +1976,95 @@ func (b *WorkflowBuilder) CreateConditionalEdge(fromID, toID, condition string)
 	return nil
 }
 
+// CreateErrorEdge creates an error edge, followed only when the source node
+// fails. Start and end nodes cannot have error edges.
+func (b *WorkflowBuilder) CreateErrorEdge(fromID, toID string) error {
+	var source workflow.Node
+	targetExists := false
+	for _, node := range b.workflow.Nodes {
+		if node.GetID() == fromID {
+			source = node
+		}
+		if node.GetID() == toID {
+			targetExists = true
+		}
+	}
+	if source == nil {
+		return fmt.Errorf("source node not found: %s", fromID)
+	}
+	if !targetExists {
+		return fmt.Errorf("target node not found: %s", toID)
+	}
+	switch source.Type() {
+	case "start", "end":
+		return fmt.Errorf("%s node %s cannot have an error edge", source.Type(), fromID)
+	}
+
+	// Push undo snapshot
+	canvasPositions := b.getCanvasPositions()
+	if err := b.undoStack.Push(b.workflow, canvasPositions); err != nil {
+		return err
+	}
+
+	edge := &workflow.Edge{
+		FromNodeID: fromID,
+		ToNodeID:   toID,
+		OnError:    true,
+	}
+
+	if err := b.workflow.AddEdge(edge); err != nil {
+		return err
+	}
+
+	b.layoutNodes()
+	b.validateWorkflow()
+	b.modified = true
+
+	return nil
+}
+
+// isCatchNode reports whether nodeID is a catch node
+func (b *WorkflowBuilder) isCatchNode(nodeID string) bool {
+	for _, node := range b.workflow.Nodes {
+		if node.GetID() == nodeID {
+			return node.Type() == "catch"
+		}
+	}
+	return false
+}
+
+// nextSwitchCase returns the first case of switch node nodeID without an
+// edge, then "default", or "" when nodeID is not a switch node or all its
+// branches are connected
+func (b *WorkflowBuilder) nextSwitchCase(nodeID string) string {
+	var sw *workflow.SwitchNode
+	for _, node := range b.workflow.Nodes {
+		if n, ok := node.(*workflow.SwitchNode); ok && n.ID == nodeID {
+			sw = n
+			break
+		}
+	}
+	if sw == nil {
+		return ""
+	}
+
+	connected := make(map[string]bool)
+	for _, edge := range b.workflow.Edges {
+		if edge.FromNodeID == nodeID {
+			connected[edge.Condition] = true
+		}
+	}
+	for _, c := range sw.Cases {
+		if !connected[c.Label] {
+			return c.Label
+		}
+	}
+	if !connected[workflow.SwitchDefaultCase] {
+		return workflow.SwitchDefaultCase
+	}
+	return ""
+}
+
 // Canvas methods
 
 // GetNodeCount returns the number of nodes on the canvas
@@ -1497,6 +2114,8 @@ func (p *PropertyPanel) RenderPropertyPanel() string {
 			}
 		} else if field.fieldType == "expression" {
 			sb.WriteString("     (JSONPath: $.field, Template: ${var}, or expression)\n")
+		} else if field.fieldType == "cases" {
+			sb.WriteString("     (Format: label: condition; label: condition, checked in order)\n")
 		} else if field.fieldType == "branches" {
 			sb.WriteString("     (Format: [node1,node2];[node3,node4] for parallel branches)\n")
 		} else if field.fieldType == "select" && field.label == "Merge Strategy" {
@@ -1523,17 +2142,16 @@ func (b *WorkflowBuilder) Render(screen interface{}, screenWidth, screenHeight i
 		return fmt.Errorf("workflow builder not initialized")
 	}
 
-	// Type assert to screen interface
-	type Screen interface {
-		SetCell(cellX, cellY int, cell interface{})
-		Size() (int, int)
-	}
-
-	_, ok := screen.(Screen)
+	_, ok := screen.(cellScreen)
 	if !ok {
 		return fmt.Errorf("invalid screen type")
 	}
 
+	// Search highlights follow edits made since the search
+	if b.searchQuery != "" {
+		b.searchMatches()
+	}
+
 	// Layout configuration
 	// Main canvas takes most of the screen
 	// Panels appear on the right side or overlay the canvas
@@ -1605,6 +2223,10 @@ func (b *WorkflowBuilder) Render(screen interface{}, screenWidth, screenHeight i
 // ApplyTemplate applies a workflow template by name
 // This implements T077 from Phase 9: Workflow Templates
 func (b *WorkflowBuilder) ApplyTemplate(templateName string) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+
 	// Step 1: Get template function from registry
 	createFn, exists := WorkflowTemplates[templateName]
 	if !exists {
@@ -1625,6 +2247,7 @@ func (b *WorkflowBuilder) ApplyTemplate(templateName string) error {
 	b.workflow = templateWf
 
 	// Step 5: Load into canvas using LoadWorkflow
+	b.canvas.forgetGroups()
 	b.canvas.nodes = make(map[string]*canvasNode)
 	b.canvas.edges = make([]*canvasEdge, 0)
 
@@ -1635,7 +2258,7 @@ func (b *WorkflowBuilder) ApplyTemplate(templateName string) error {
 	b.modified = false
 
 	// Step 8: Clear undo stack (new workflow, no history)
-	b.undoStack = NewUndoStack(100)
+	b.undoStack.Clear()
 
 	// Step 9: Run validation
 	b.validateWorkflow()
@@ -1643,6 +2266,37 @@ func (b *WorkflowBuilder) ApplyTemplate(templateName string) error {
 	return nil
 }
 
+// RestoreWorkflow replaces the workflow being built with an earlier version
+// of it, such as a named snapshot. The workflow keeps its identity and
+// metadata; nodes that exist in both versions keep their canvas positions.
+// The change to nodes and edges can be undone.
+func (b *WorkflowBuilder) RestoreWorkflow(version *workflow.Workflow) error {
+	if version == nil {
+		return fmt.Errorf("cannot restore nil workflow")
+	}
+
+	positions := b.getCanvasPositions()
+	if err := b.undoStack.Push(b.workflow, positions); err != nil {
+		return fmt.Errorf("failed to save undo snapshot: %w", err)
+	}
+
+	b.workflow.Version = version.Version
+	b.workflow.Description = version.Description
+	b.workflow.Variables = version.Variables
+	b.workflow.ServerConfigs = version.ServerConfigs
+	b.workflow.Nodes = version.Nodes
+	b.workflow.Edges = version.Edges
+	b.restoreCanvasPositions(positions)
+
+	if _, exists := b.canvas.nodes[b.selectedNodeID]; !exists {
+		b.selectedNodeID = ""
+		b.canvas.selectedID = ""
+	}
+	b.modified = true
+	b.validateWorkflow()
+	return nil
+}
+
 // HandleResize handles terminal resize events
 // This implements T084 from Phase 11: Polish
 func (b *WorkflowBuilder) HandleResize(newWidth, newHeight int) {
@@ -1689,6 +2343,16 @@ func (b *WorkflowBuilder) HandleResize(newWidth, newHeight int) {
 // handleNormalMode processes keyboard shortcuts in normal mode
 // This implements T080 from Phase 10: Keyboard Handling
 func (b *WorkflowBuilder) handleNormalMode(key string) error {
+	// A collapsed group stands in for its nodes; it opens rather than edits
+	if _, isGroup := groupName(b.selectedNodeID); isGroup {
+		switch key {
+		case "Enter":
+			return b.ToggleGroup()
+		case "d", "c", "y", "V", "h", "j", "k", "l", "m":
+			return b.collapsedGroupSelected()
+		}
+	}
+
 	switch key {
 	// Node operations
 	case "a":
@@ -1707,11 +2371,18 @@ func (b *WorkflowBuilder) handleNormalMode(key string) error {
 		}
 		return fmt.Errorf("no node selected")
 	case "y":
-		// Yank (copy) node - future feature
-		return fmt.Errorf("yank not yet implemented")
+		// Yank the current node and any marked nodes
+		return b.YankSelectedNodes()
 	case "p":
-		// Paste node - future feature
-		return fmt.Errorf("paste not yet implemented")
+		return b.PasteNodes()
+	case "V":
+		return b.EnterVisualMode()
+	case "z":
+		return b.ToggleGroup()
+	case "n":
+		return b.NextMatch(false)
+	case "N":
+		return b.NextMatch(true)
 
 	// Workflow operations
 	case "s":
@@ -1739,6 +2410,9 @@ func (b *WorkflowBuilder) handleNormalMode(key string) error {
 	case "f":
 		b.canvas.FitAll()
 		return nil
+	case "o":
+		b.canvas.ToggleMinimap()
+		return nil
 
 	// Navigation (canvas pan)
 	case "Shift+Up":
@@ -1771,33 +2445,45 @@ func (b *WorkflowBuilder) handleNormalMode(key string) error {
 	// Node movement (h/j/k/l)
 	case "h":
 		if b.selectedNodeID != "" {
-			if node, exists := b.canvas.nodes[b.selectedNodeID]; exists {
-				return b.canvas.MoveNode(b.selectedNodeID, Position{X: node.position.X - 1, Y: node.position.Y})
-			}
+			return b.MoveNodeBy(b.selectedNodeID, -1, 0)
 		}
 		return fmt.Errorf("no node selected")
 	case "j":
 		if b.selectedNodeID != "" {
-			if node, exists := b.canvas.nodes[b.selectedNodeID]; exists {
-				return b.canvas.MoveNode(b.selectedNodeID, Position{X: node.position.X, Y: node.position.Y + 1})
-			}
+			return b.MoveNodeBy(b.selectedNodeID, 0, 1)
 		}
 		return fmt.Errorf("no node selected")
 	case "k":
 		if b.selectedNodeID != "" {
-			if node, exists := b.canvas.nodes[b.selectedNodeID]; exists {
-				return b.canvas.MoveNode(b.selectedNodeID, Position{X: node.position.X, Y: node.position.Y - 1})
-			}
+			return b.MoveNodeBy(b.selectedNodeID, 0, -1)
 		}
 		return fmt.Errorf("no node selected")
 	case "l":
 		if b.selectedNodeID != "" {
-			if node, exists := b.canvas.nodes[b.selectedNodeID]; exists {
-				return b.canvas.MoveNode(b.selectedNodeID, Position{X: node.position.X + 1, Y: node.position.Y})
-			}
+			return b.MoveNodeBy(b.selectedNodeID, 1, 0)
 		}
 		return fmt.Errorf("no node selected")
 
+	// Multi-selection layout
+	case "m":
+		if b.selectedNodeID != "" {
+			return b.ToggleNodeSelection(b.selectedNodeID)
+		}
+		return fmt.Errorf("no node selected")
+	case "M":
+		b.ClearNodeSelection()
+		return nil
+	case "L":
+		return b.AlignSelectedNodes(AlignLeft)
+	case "T":
+		return b.AlignSelectedNodes(AlignTop)
+	case "C":
+		return b.AlignSelectedNodes(AlignCenter)
+	case "H":
+		return b.DistributeSelectedNodes(DistributeHorizontal)
+	case "J":
+		return b.DistributeSelectedNodes(DistributeVertical)
+
 	default:
 		return fmt.Errorf("unrecognized key in normal mode: %s", key)
 	}
@@ -1817,8 +2503,7 @@ func (b *WorkflowBuilder) handleEditMode(key string) error {
 
 	// Edit operations
 	case "Enter":
-		// Would start editing the field in real TUI
-		return nil
+		return b.propertyPanel.BeginEdit()
 	case "Ctrl+s":
 		return b.SavePropertyChanges()
 	case "Ctrl+r":
@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Alignment and distribution commands operate on the multi-selection: the
+// nodes marked with ToggleNodeSelection plus the currently selected node.
+
+// Alignment modes for AlignSelectedNodes
+const (
+	AlignLeft   = "left"   // Same left edge (leftmost node)
+	AlignTop    = "top"    // Same top edge (topmost node)
+	AlignCenter = "center" // Same horizontal center (center of the selection)
+)
+
+// Distribution directions for DistributeSelectedNodes
+const (
+	DistributeHorizontal = "horizontal"
+	DistributeVertical   = "vertical"
+)
+
+// ToggleNodeSelection adds a node to, or removes it from, the multi-selection
+func (b *WorkflowBuilder) ToggleNodeSelection(nodeID string) error {
+	cNode, exists := b.canvas.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+
+	if b.markedNodeIDs[nodeID] {
+		delete(b.markedNodeIDs, nodeID)
+	} else {
+		b.markedNodeIDs[nodeID] = true
+	}
+	cNode.selected = b.isNodeSelected(nodeID)
+	return nil
+}
+
+// ClearNodeSelection empties the multi-selection (the current node stays selected)
+func (b *WorkflowBuilder) ClearNodeSelection() {
+	for nodeID := range b.markedNodeIDs {
+		if cNode, exists := b.canvas.nodes[nodeID]; exists {
+			cNode.selected = nodeID == b.selectedNodeID
+		}
+	}
+	b.markedNodeIDs = make(map[string]bool)
+}
+
+// GetSelectedNodeIDs returns the multi-selection in workflow order
+func (b *WorkflowBuilder) GetSelectedNodeIDs() []string {
+	var ids []string
+	for _, node := range b.workflow.Nodes {
+		if b.isNodeSelected(node.GetID()) {
+			ids = append(ids, node.GetID())
+		}
+	}
+	return ids
+}
+
+// isNodeSelected reports whether a node is the current node or marked
+func (b *WorkflowBuilder) isNodeSelected(nodeID string) bool {
+	return nodeID == b.selectedNodeID || b.markedNodeIDs[nodeID]
+}
+
+// pruneNodeSelection drops marks for nodes no longer on the canvas
+func (b *WorkflowBuilder) pruneNodeSelection() {
+	for nodeID := range b.markedNodeIDs {
+		if _, exists := b.canvas.nodes[nodeID]; !exists {
+			delete(b.markedNodeIDs, nodeID)
+		}
+	}
+}
+
+// selectedCanvasNodes returns the canvas nodes of the multi-selection
+func (b *WorkflowBuilder) selectedCanvasNodes() []*canvasNode {
+	var nodes []*canvasNode
+	for _, nodeID := range b.GetSelectedNodeIDs() {
+		if cNode, exists := b.canvas.nodes[nodeID]; exists {
+			nodes = append(nodes, cNode)
+		}
+	}
+	return nodes
+}
+
+// AlignSelectedNodes lines up the selected nodes along their left edges, top
+// edges, or horizontal centers. The change is recorded as one undo entry.
+func (b *WorkflowBuilder) AlignSelectedNodes(alignment string) error {
+	nodes := b.selectedCanvasNodes()
+	if len(nodes) < 2 {
+		return fmt.Errorf("select at least 2 nodes to align")
+	}
+
+	positions := make(map[string]Position, len(nodes))
+	switch alignment {
+	case AlignLeft:
+		minX := nodes[0].position.X
+		for _, n := range nodes[1:] {
+			minX = min(minX, n.position.X)
+		}
+		for _, n := range nodes {
+			positions[n.node.GetID()] = Position{X: minX, Y: n.position.Y}
+		}
+	case AlignTop:
+		minY := nodes[0].position.Y
+		for _, n := range nodes[1:] {
+			minY = min(minY, n.position.Y)
+		}
+		for _, n := range nodes {
+			positions[n.node.GetID()] = Position{X: n.position.X, Y: minY}
+		}
+	case AlignCenter:
+		left := nodes[0].position.X
+		right := nodes[0].position.X + nodes[0].width
+		for _, n := range nodes[1:] {
+			left = min(left, n.position.X)
+			right = max(right, n.position.X+n.width)
+		}
+		center := (left + right) / 2
+		for _, n := range nodes {
+			positions[n.node.GetID()] = Position{X: max(center-n.width/2, 0), Y: n.position.Y}
+		}
+	default:
+		return fmt.Errorf("unknown alignment: %s", alignment)
+	}
+
+	return b.applyNodePositions(positions)
+}
+
+// DistributeSelectedNodes spaces the selected nodes evenly between the
+// outermost two, horizontally or vertically. Gaps between node edges are made
+// equal; if the nodes are too large to fit without overlapping, their left
+// (or top) edges are spaced evenly instead. The change is recorded as one
+// undo entry.
+func (b *WorkflowBuilder) DistributeSelectedNodes(direction string) error {
+	nodes := b.selectedCanvasNodes()
+	if len(nodes) < 3 {
+		return fmt.Errorf("select at least 3 nodes to distribute")
+	}
+
+	// Project onto the distribution axis
+	var coord func(n *canvasNode) int
+	var size func(n *canvasNode) int
+	var place func(n *canvasNode, v int) Position
+	switch direction {
+	case DistributeHorizontal:
+		coord = func(n *canvasNode) int { return n.position.X }
+		size = func(n *canvasNode) int { return n.width }
+		place = func(n *canvasNode, v int) Position { return Position{X: v, Y: n.position.Y} }
+	case DistributeVertical:
+		coord = func(n *canvasNode) int { return n.position.Y }
+		size = func(n *canvasNode) int { return n.height }
+		place = func(n *canvasNode, v int) Position { return Position{X: n.position.X, Y: v} }
+	default:
+		return fmt.Errorf("unknown distribution direction: %s", direction)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if coord(nodes[i]) != coord(nodes[j]) {
+			return coord(nodes[i]) < coord(nodes[j])
+		}
+		return nodes[i].node.GetID() < nodes[j].node.GetID()
+	})
+
+	first, last := nodes[0], nodes[len(nodes)-1]
+	gaps := len(nodes) - 1
+	free := coord(last) + size(last) - coord(first)
+	for _, n := range nodes {
+		free -= size(n)
+	}
+
+	positions := make(map[string]Position, len(nodes))
+	if free >= 0 {
+		// Equal gaps between edges; spread any remainder over the first gaps
+		gap, extra := free/gaps, free%gaps
+		v := coord(first)
+		for i, n := range nodes {
+			positions[n.node.GetID()] = place(n, v)
+			v += size(n) + gap
+			if i < extra {
+				v++
+			}
+		}
+	} else {
+		span := coord(last) - coord(first)
+		for i, n := range nodes {
+			positions[n.node.GetID()] = place(n, coord(first)+span*i/gaps)
+		}
+	}
+
+	return b.applyNodePositions(positions)
+}
+
+// MoveNodeBy shifts a node by dx, dy. A run of moves of the same node is
+// undone in one step.
+func (b *WorkflowBuilder) MoveNodeBy(nodeID string, dx, dy int) error {
+	cNode, exists := b.canvas.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+	pos := Position{X: cNode.position.X + dx, Y: cNode.position.Y + dy}
+	if pos.X < 0 || pos.Y < 0 {
+		return fmt.Errorf("invalid position: coordinates cannot be negative")
+	}
+	return b.applyNodePositionsCoalesced("move:"+nodeID, map[string]Position{nodeID: pos})
+}
+
+// applyNodePositions moves nodes as a single undoable change. Nothing is
+// recorded if no node actually moves.
+func (b *WorkflowBuilder) applyNodePositions(positions map[string]Position) error {
+	return b.applyNodePositionsCoalesced("", positions)
+}
+
+// applyNodePositionsCoalesced moves nodes like applyNodePositions, merging
+// the undo entry with the previous one if it had the same key
+func (b *WorkflowBuilder) applyNodePositionsCoalesced(key string, positions map[string]Position) error {
+	changed := false
+	for nodeID, pos := range positions {
+		if b.canvas.nodes[nodeID].position != pos {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	canvasPositions := b.getCanvasPositions()
+	if err := b.undoStack.PushCoalesced(key, b.workflow, canvasPositions); err != nil {
+		return fmt.Errorf("failed to save undo snapshot: %w", err)
+	}
+
+	for nodeID, pos := range positions {
+		if err := b.canvas.MoveNode(nodeID, pos); err != nil {
+			return err
+		}
+	}
+
+	b.modified = true
+	return nil
+}
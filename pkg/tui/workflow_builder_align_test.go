@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// newAlignTestBuilder creates a builder with three transform nodes (20x5)
+// multi-selected, at the given positions
+func newAlignTestBuilder(t *testing.T, positions ...Position) *WorkflowBuilder {
+	t.Helper()
+
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+
+	for _, pos := range positions {
+		if err := builder.AddNodeAtPosition("Transform", pos); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+	for i, node := range wf.Nodes {
+		if err := builder.SelectNode(node.GetID()); err != nil {
+			t.Fatalf("Failed to select node: %v", err)
+		}
+		// Leave the last node as the current selection rather than marking it
+		if i < len(wf.Nodes)-1 {
+			if err := builder.HandleKey("m"); err != nil {
+				t.Fatalf("HandleKey('m') returned error: %v", err)
+			}
+		}
+	}
+	return builder
+}
+
+// nodePositions returns the canvas position of each node in workflow order
+func nodePositions(builder *WorkflowBuilder) []Position {
+	var positions []Position
+	for _, node := range builder.workflow.Nodes {
+		positions = append(positions, builder.canvas.nodes[node.GetID()].position)
+	}
+	return positions
+}
+
+func TestWorkflowBuilder_AlignSelectedNodes(t *testing.T) {
+	start := []Position{{X: 5, Y: 2}, {X: 40, Y: 10}, {X: 12, Y: 30}}
+
+	tests := []struct {
+		key  string
+		want []Position
+	}{
+		{"L", []Position{{X: 5, Y: 2}, {X: 5, Y: 10}, {X: 5, Y: 30}}},
+		{"T", []Position{{X: 5, Y: 2}, {X: 40, Y: 2}, {X: 12, Y: 2}}},
+		// Selection spans X 5..60, so every center moves to 32
+		{"C", []Position{{X: 22, Y: 2}, {X: 22, Y: 10}, {X: 22, Y: 30}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			builder := newAlignTestBuilder(t, start...)
+			undoSize := builder.undoStack.Size()
+
+			if err := builder.HandleKey(tt.key); err != nil {
+				t.Fatalf("HandleKey(%q) returned error: %v", tt.key, err)
+			}
+
+			got := nodePositions(builder)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("node %d: position = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+
+			// One undo entry capturing the positions before alignment
+			if builder.undoStack.Size() != undoSize+1 {
+				t.Errorf("Expected 1 undo entry, got %d", builder.undoStack.Size()-undoSize)
+			}
+			snapshot := builder.undoStack.snapshots[builder.undoStack.cursor]
+			for i, node := range builder.workflow.Nodes {
+				if snapshot.CanvasState[node.GetID()] != start[i] {
+					t.Errorf("Snapshot position for %s = %+v, want %+v",
+						node.GetID(), snapshot.CanvasState[node.GetID()], start[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowBuilder_DistributeSelectedNodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		start []Position
+		want  []Position
+	}{
+		{
+			name:  "horizontal equal gaps",
+			key:   "H",
+			start: []Position{{X: 5, Y: 2}, {X: 12, Y: 9}, {X: 80, Y: 4}},
+			want:  []Position{{X: 5, Y: 2}, {X: 43, Y: 9}, {X: 80, Y: 4}},
+		},
+		{
+			name:  "vertical equal gaps",
+			key:   "J",
+			start: []Position{{X: 5, Y: 2}, {X: 30, Y: 30}, {X: 9, Y: 10}},
+			want:  []Position{{X: 5, Y: 2}, {X: 30, Y: 30}, {X: 9, Y: 16}},
+		},
+		{
+			name:  "horizontal overlapping falls back to even edges",
+			key:   "H",
+			start: []Position{{X: 5, Y: 2}, {X: 12, Y: 9}, {X: 40, Y: 4}},
+			want:  []Position{{X: 5, Y: 2}, {X: 22, Y: 9}, {X: 40, Y: 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newAlignTestBuilder(t, tt.start...)
+			undoSize := builder.undoStack.Size()
+
+			if err := builder.HandleKey(tt.key); err != nil {
+				t.Fatalf("HandleKey(%q) returned error: %v", tt.key, err)
+			}
+
+			got := nodePositions(builder)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("node %d: position = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if builder.undoStack.Size() != undoSize+1 {
+				t.Errorf("Expected 1 undo entry, got %d", builder.undoStack.Size()-undoSize)
+			}
+		})
+	}
+}
+
+func TestWorkflowBuilder_AlignSelectionErrors(t *testing.T) {
+	builder := newAlignTestBuilder(t, Position{X: 5, Y: 2}, Position{X: 40, Y: 10})
+
+	// Two nodes can be aligned but not distributed
+	if err := builder.HandleKey("H"); err == nil {
+		t.Error("Expected error distributing 2 nodes")
+	}
+
+	// Already aligned: no undo entry is recorded
+	if err := builder.HandleKey("L"); err != nil {
+		t.Fatalf("HandleKey('L') returned error: %v", err)
+	}
+	undoSize := builder.undoStack.Size()
+	if err := builder.HandleKey("L"); err != nil {
+		t.Fatalf("HandleKey('L') returned error: %v", err)
+	}
+	if builder.undoStack.Size() != undoSize {
+		t.Error("No-op alignment should not record an undo entry")
+	}
+
+	// Clearing the multi-selection leaves only the current node
+	if err := builder.HandleKey("M"); err != nil {
+		t.Fatalf("HandleKey('M') returned error: %v", err)
+	}
+	if ids := builder.GetSelectedNodeIDs(); len(ids) != 1 {
+		t.Errorf("Expected only the current node selected, got %v", ids)
+	}
+	if err := builder.HandleKey("T"); err == nil {
+		t.Error("Expected error aligning a single node")
+	}
+
+	if err := builder.AlignSelectedNodes("diagonal"); err == nil {
+		t.Error("Expected error for unknown alignment")
+	}
+}
+
+func TestWorkflowBuilder_ToggleNodeSelection(t *testing.T) {
+	builder := newAlignTestBuilder(t, Position{X: 5, Y: 2}, Position{X: 40, Y: 10}, Position{X: 12, Y: 30})
+
+	if ids := builder.GetSelectedNodeIDs(); len(ids) != 3 {
+		t.Fatalf("Expected 3 selected nodes, got %v", ids)
+	}
+	if !builder.canvas.nodes["transform-0"].selected {
+		t.Error("Marked node should render as selected")
+	}
+
+	// Toggling again unmarks
+	if err := builder.ToggleNodeSelection("transform-0"); err != nil {
+		t.Fatalf("ToggleNodeSelection returned error: %v", err)
+	}
+	if builder.canvas.nodes["transform-0"].selected {
+		t.Error("Unmarked node should not render as selected")
+	}
+
+	// Deleting a marked node drops it from the selection
+	if err := builder.DeleteNode("transform-1"); err != nil {
+		t.Fatalf("DeleteNode returned error: %v", err)
+	}
+	if ids := builder.GetSelectedNodeIDs(); len(ids) != 1 || ids[0] != "transform-2" {
+		t.Errorf("Expected only transform-2 selected, got %v", ids)
+	}
+
+	if err := builder.ToggleNodeSelection("missing"); err == nil {
+		t.Error("Expected error for unknown node")
+	}
+}
@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// The builder clipboard holds a subgraph yanked with 'y': a node, or a
+// multi-selection with the edges between its nodes. Pasting adds a copy
+// with fresh IDs. With a SystemClipboard set, yanks are also written there
+// as YAML.
+
+// pasteOffset is how far each paste is shifted from the previous copy
+var pasteOffset = Position{X: 4, Y: 2}
+
+// nodeClipboard holds a yanked subgraph: copies of the nodes, the edges
+// between them, and their canvas positions
+type nodeClipboard struct {
+	nodes     []workflow.Node
+	edges     []*workflow.Edge
+	positions map[string]Position
+}
+
+// YankSelectedNodes copies the selected nodes, the edges between them and
+// their layout to the builder's clipboard
+func (b *WorkflowBuilder) YankSelectedNodes() error {
+	ids := b.GetSelectedNodeIDs()
+	if len(ids) == 0 {
+		return fmt.Errorf("no node selected")
+	}
+
+	selected := make(map[string]bool, len(ids))
+	for _, nodeID := range ids {
+		selected[nodeID] = true
+	}
+
+	clip := &nodeClipboard{positions: make(map[string]Position, len(ids))}
+	for _, node := range b.workflow.Nodes {
+		nodeID := node.GetID()
+		if !selected[nodeID] {
+			continue
+		}
+		copied, err := b.copyNode(node, nodeID)
+		if err != nil {
+			return err
+		}
+		clip.nodes = append(clip.nodes, copied)
+		if cNode, exists := b.canvas.nodes[nodeID]; exists {
+			clip.positions[nodeID] = cNode.position
+		}
+	}
+	for _, edge := range b.workflow.Edges {
+		if selected[edge.FromNodeID] && selected[edge.ToNodeID] {
+			copied := *edge
+			clip.edges = append(clip.edges, &copied)
+		}
+	}
+
+	b.clipboard = clip
+
+	if b.systemClipboard != nil {
+		yamlBytes, err := workflow.NodesToYAML(clip.nodes, clip.edges)
+		if err != nil {
+			return fmt.Errorf("yanked, but not copied to system clipboard: %w", err)
+		}
+		if err := b.systemClipboard.WriteText(string(yamlBytes)); err != nil {
+			return fmt.Errorf("yanked, but not copied to system clipboard: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetSystemClipboard makes yanks also copy YAML to clipboard; nil stops it
+func (b *WorkflowBuilder) SetSystemClipboard(clipboard SystemClipboard) {
+	b.systemClipboard = clipboard
+}
+
+// ClipboardSize returns the number of nodes yanked to the clipboard
+func (b *WorkflowBuilder) ClipboardSize() int {
+	if b.clipboard == nil {
+		return 0
+	}
+	return len(b.clipboard.nodes)
+}
+
+// PasteNodes adds a copy of the clipboard's subgraph to the workflow as a
+// single undoable change. Copies get fresh IDs ("fetch-copy", "fetch-copy-2")
+// and references between them, such as loop bodies, follow the new IDs. The
+// pasted nodes become the selection, offset from where they were yanked so
+// repeated pastes cascade.
+func (b *WorkflowBuilder) PasteNodes() error {
+	if b.ClipboardSize() == 0 {
+		return fmt.Errorf("nothing to paste")
+	}
+	clip := b.clipboard
+
+	canvasPositions := b.getCanvasPositions()
+	if err := b.undoStack.Push(b.workflow, canvasPositions); err != nil {
+		return fmt.Errorf("failed to save undo snapshot: %w", err)
+	}
+
+	// Pick every new ID first so references can be remapped
+	newIDs := make(map[string]string, len(clip.nodes))
+	for _, node := range clip.nodes {
+		newIDs[node.GetID()] = b.uniqueNodeID(node.GetID()+"-copy", newIDs)
+	}
+
+	b.ClearNodeSelection()
+	var pasted []string
+	for _, node := range clip.nodes {
+		oldID := node.GetID()
+		copied, err := b.copyNode(node, newIDs[oldID])
+		if err != nil {
+			return err
+		}
+		remapNodeRefs(copied, newIDs)
+
+		pos := clip.positions[oldID]
+		pos = Position{X: pos.X + pasteOffset.X, Y: pos.Y + pasteOffset.Y}
+		clip.positions[oldID] = pos
+
+		if err := b.workflow.AddNode(copied); err != nil {
+			return err
+		}
+		if err := b.canvas.AddNode(copied, pos); err != nil {
+			return err
+		}
+		pasted = append(pasted, newIDs[oldID])
+	}
+
+	for _, edge := range clip.edges {
+		copied := *edge
+		copied.ID = "" // AddEdge assigns a new one
+		copied.FromNodeID = newIDs[edge.FromNodeID]
+		copied.ToNodeID = newIDs[edge.ToNodeID]
+		if err := b.workflow.AddEdge(&copied); err != nil {
+			return err
+		}
+		if err := b.canvas.AddEdge(&copied); err != nil {
+			return err
+		}
+	}
+
+	b.selectedNodeID = pasted[0]
+	b.canvas.selectedID = pasted[0]
+	for _, nodeID := range pasted {
+		b.markNode(nodeID)
+	}
+
+	b.modified = true
+	b.validateWorkflow()
+	return nil
+}
+
+// uniqueNodeID returns base, or base with a numeric suffix, so that it is
+// used by no node on the canvas and by none of the IDs already chosen
+func (b *WorkflowBuilder) uniqueNodeID(base string, chosen map[string]string) string {
+	taken := func(id string) bool {
+		if _, exists := b.canvas.nodes[id]; exists {
+			return true
+		}
+		for _, other := range chosen {
+			if other == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	id := base
+	for n := 2; taken(id); n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+// copyNode returns a deep copy of node with a new ID
+func (b *WorkflowBuilder) copyNode(node workflow.Node, id string) (workflow.Node, error) {
+	switch n := b.undoStack.deepCopyNode(node).(type) {
+	case *workflow.StartNode:
+		n.ID = id
+		return n, nil
+	case *workflow.EndNode:
+		n.ID = id
+		return n, nil
+	case *workflow.MCPToolNode:
+		n.ID = id
+		return n, nil
+	case *workflow.TransformNode:
+		n.ID = id
+		return n, nil
+	case *workflow.ConditionNode:
+		n.ID = id
+		return n, nil
+	case *workflow.SwitchNode:
+		n.ID = id
+		return n, nil
+	case *workflow.LoopNode:
+		n.ID = id
+		return n, nil
+	case *workflow.ParallelNode:
+		n.ID = id
+		return n, nil
+	case *workflow.TryNode:
+		n.ID = id
+		return n, nil
+	case *workflow.CatchNode:
+		n.ID = id
+		return n, nil
+	case *workflow.DelayNode:
+		n.ID = id
+		return n, nil
+	case *workflow.ApprovalNode:
+		n.ID = id
+		return n, nil
+	case *workflow.BatchToolNode:
+		n.ID = id
+		return n, nil
+	case *workflow.PassthroughNode:
+		// Not deep-copied by the undo stack; it has no reference fields
+		copied := *n
+		copied.ID = id
+		return &copied, nil
+
+	// Nodes instantiated from templates, also not deep-copied
+	case *workflow.GenericMCPToolNode:
+		return &workflow.GenericMCPToolNode{ID: id, Config: maps.Clone(n.Config)}, nil
+	case *workflow.GenericTransformNode:
+		return &workflow.GenericTransformNode{ID: id, Config: maps.Clone(n.Config)}, nil
+	case *workflow.GenericConditionNode:
+		copied := &workflow.GenericConditionNode{BaseCondition: n.BaseCondition, Config: maps.Clone(n.Config)}
+		copied.BaseCondition.ID = id
+		return copied, nil
+	default:
+		return nil, fmt.Errorf("cannot copy node %s of type %T", node.GetID(), node)
+	}
+}
+
+// remapNodeRefs rewrites the node IDs a loop or try body or parallel
+// branches refer to, for references to nodes that were copied along with them
+func remapNodeRefs(node workflow.Node, newIDs map[string]string) {
+	remap := func(ids []string) {
+		for i, id := range ids {
+			if newID, ok := newIDs[id]; ok {
+				ids[i] = newID
+			}
+		}
+	}
+
+	switch n := node.(type) {
+	case *workflow.LoopNode:
+		remap(n.Body)
+	case *workflow.TryNode:
+		remap(n.Body)
+	case *workflow.ParallelNode:
+		for _, branch := range n.Branches {
+			remap(branch)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// fakeSystemClipboard records what is written to it
+type fakeSystemClipboard struct {
+	text string
+	err  error
+}
+
+func (c *fakeSystemClipboard) WriteText(text string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.text = text
+	return nil
+}
+
+func TestWorkflowBuilder_PasteRemapsReferences(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	loop := &workflow.LoopNode{ID: "loop", Collection: "items", ItemVariable: "item", Body: []string{"body", "outside"}}
+	body := &workflow.TransformNode{ID: "body", InputVariable: "item", Expression: "$", OutputVariable: "out"}
+	for _, node := range []workflow.Node{loop, body} {
+		if err := wf.AddNode(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+
+	if err := builder.SelectNode("loop"); err != nil {
+		t.Fatal(err)
+	}
+	pressBuilderKeys(t, builder, "m")
+	if err := builder.SelectNode("body"); err != nil {
+		t.Fatal(err)
+	}
+	pressBuilderKeys(t, builder, "y", "p")
+
+	copied, ok := wf.Nodes[2].(*workflow.LoopNode)
+	if !ok || copied.ID != "loop-copy" {
+		t.Fatalf("pasted node = %+v", wf.Nodes[2])
+	}
+	if copied.Body[0] != "body-copy" || copied.Body[1] != "outside" {
+		t.Errorf("pasted loop body = %v, want [body-copy outside]", copied.Body)
+	}
+	if loop.Body[0] != "body" {
+		t.Errorf("original loop body changed: %v", loop.Body)
+	}
+
+	builder.clipboard = nil
+	if err := builder.PasteNodes(); err == nil {
+		t.Error("expected error pasting an empty clipboard")
+	}
+}
+
+func TestWorkflowBuilder_YankToSystemClipboard(t *testing.T) {
+	builder := newVisualTestBuilder(t, Position{X: 5, Y: 2}, Position{X: 5, Y: 10})
+	if err := builder.CreateEdge("transform-0", "transform-1"); err != nil {
+		t.Fatalf("CreateEdge failed: %v", err)
+	}
+
+	// Off by default: yanking only fills the internal clipboard
+	pressBuilderKeys(t, builder, "y")
+	if builder.ClipboardSize() != 1 {
+		t.Errorf("ClipboardSize() = %d, want 1", builder.ClipboardSize())
+	}
+
+	system := &fakeSystemClipboard{}
+	builder.SetSystemClipboard(system)
+	pressBuilderKeys(t, builder, "V", "j", "y")
+	for _, want := range []string{"nodes:", "id: transform-0", "id: transform-1", "edges:", "from: transform-0"} {
+		if !strings.Contains(system.text, want) {
+			t.Errorf("system clipboard missing %q:\n%s", want, system.text)
+		}
+	}
+
+	// A failing system clipboard is reported, but the yank still happens
+	system.err = errors.New("no display")
+	builder.clipboard = nil
+	err := builder.HandleKey("y")
+	if err == nil || !strings.Contains(err.Error(), "no display") {
+		t.Errorf("HandleKey(y) error = %v", err)
+	}
+	if builder.ClipboardSize() != 1 {
+		t.Errorf("ClipboardSize() = %d after failed system copy, want 1", builder.ClipboardSize())
+	}
+}
+
+func TestWorkflowBuilder_CopyTemplateNodes(t *testing.T) {
+	builder := newVisualTestBuilder(t, Position{X: 5, Y: 2})
+	tool := &workflow.GenericMCPToolNode{ID: "fetch", Config: map[string]interface{}{"server": "http", "tool": "get"}}
+	cond := &workflow.GenericConditionNode{BaseCondition: workflow.ConditionNode{ID: "check", Condition: "ok"}}
+
+	for _, node := range []workflow.Node{tool, cond} {
+		copied, err := builder.copyNode(node, node.GetID()+"-2")
+		if err != nil {
+			t.Fatalf("copyNode(%s) failed: %v", node.GetID(), err)
+		}
+		if copied.GetID() != node.GetID()+"-2" || copied == node {
+			t.Errorf("copy of %s = %+v", node.GetID(), copied)
+		}
+	}
+
+	// The copy's config is independent of the original's
+	copied, _ := builder.copyNode(tool, "fetch-2")
+	copied.(*workflow.GenericMCPToolNode).Config["tool"] = "post"
+	if tool.Config["tool"] != "get" {
+		t.Errorf("original config changed: %v", tool.Config)
+	}
+	if cond.BaseCondition.ID != "check" {
+		t.Errorf("original condition ID changed: %s", cond.BaseCondition.ID)
+	}
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// SetServerRegistry sets the registry server IDs and tool names are
+// completed from when editing tool nodes; nil completes only the servers
+// the workflow declares
+func (b *WorkflowBuilder) SetServerRegistry(servers mcpserver.ServerRepository) {
+	b.servers = servers
+}
+
+// handleFieldInput processes keys while a property value is being typed.
+// Tab and Shift+Tab (or Down and Up) cycle through completions, Enter
+// stores the value and Esc discards it.
+func (b *WorkflowBuilder) handleFieldInput(key string) error {
+	panel := b.propertyPanel
+	switch key {
+	case "Enter":
+		return panel.CommitEdit()
+	case "Esc", "Escape":
+		panel.CancelEdit()
+	case "Tab", "Down":
+		panel.Complete(false)
+	case "Shift+Tab", "Up":
+		panel.Complete(true)
+	case "Backspace":
+		panel.Backspace()
+	case "Ctrl+v":
+		panel.TypeText(Clipboard().Paste())
+	default:
+		if r, size := utf8.DecodeRuneInString(key); size == len(key) && r != utf8.RuneError {
+			panel.TypeRune(r)
+		}
+	}
+	return nil
+}
+
+// SetToolSampleStore sets where recent tool outputs are read from, to
+// suggest the fields of a tool's output in JSONPath expressions; nil
+// suggests none
+func (b *WorkflowBuilder) SetToolSampleStore(store *storage.ToolSampleStore) {
+	b.toolSamples = store
+}
+
+// completionCandidates suggests values for a property field: server IDs
+// declared by the workflow or registered, the tools of the chosen server,
+// and the workflow's variables for variable fields and expressions. A
+// transform's expression is also completed with the JSONPath of each field
+// of its input, when the input is a tool's output or a transform's output
+// with a declared schema.
+func (b *WorkflowBuilder) completionCandidates(label string, fields []propertyField) []string {
+	switch label {
+	case "Server ID":
+		return b.serverIDs()
+	case "Tool Name":
+		return b.toolNames(getFieldValue(fields, "Server ID"))
+	case "Expression":
+		return append(b.GetVariableList(), b.outputFieldPaths(getFieldValue(fields, "Input Variable"))...)
+	}
+	return b.GetVariableList()
+}
+
+// outputFieldPaths lists the JSONPath of each field of a variable set by a
+// transform node's output schema, or by a tool node, inferred from the
+// tool's recent outputs
+func (b *WorkflowBuilder) outputFieldPaths(variable string) []string {
+	if variable == "" {
+		return nil
+	}
+	for _, node := range b.workflow.Nodes {
+		if n, ok := node.(*workflow.TransformNode); ok && n.OutputVariable == variable && n.OutputSchema != nil {
+			return mcpserver.OutputFieldPaths(n.OutputSchema)
+		}
+	}
+	if b.toolSamples == nil {
+		return nil
+	}
+	for _, node := range b.workflow.Nodes {
+		n, ok := node.(*workflow.MCPToolNode)
+		if !ok || n.OutputVariable != variable || n.ServerID == "" || n.ToolName == "" {
+			continue
+		}
+		samples, err := b.toolSamples.Load(n.ServerID, n.ToolName)
+		if err != nil || len(samples) == 0 {
+			return nil // Best effort: suggest what is known
+		}
+		return mcpserver.OutputFieldPaths(mcpserver.InferOutputSchema(samples))
+	}
+	return nil
+}
+
+// serverIDs lists the servers the workflow declares, then other registered
+// servers, each sorted
+func (b *WorkflowBuilder) serverIDs() []string {
+	seen := make(map[string]bool)
+	var declared, registered []string
+	for _, server := range b.workflow.ServerConfigs {
+		if server != nil && !seen[server.ID] {
+			seen[server.ID] = true
+			declared = append(declared, server.ID)
+		}
+	}
+	if b.servers != nil {
+		servers, _ := b.servers.List() // Best effort: suggest what is available
+		for _, server := range servers {
+			if server != nil && !seen[server.ID] {
+				seen[server.ID] = true
+				registered = append(registered, server.ID)
+			}
+		}
+	}
+	sort.Strings(declared)
+	sort.Strings(registered)
+	return append(declared, registered...)
+}
+
+// toolNames lists the tools a registered server offers, once discovered
+func (b *WorkflowBuilder) toolNames(serverID string) []string {
+	if b.servers == nil || serverID == "" {
+		return nil
+	}
+	server, err := b.servers.Get(serverID)
+	if err != nil || server == nil {
+		return nil
+	}
+	names := make([]string, 0, len(server.Tools))
+	for _, tool := range server.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toolSchema returns the input schema of a registered server's tool, once
+// discovered
+func (b *WorkflowBuilder) toolSchema(serverID, toolName string) *mcpserver.ToolSchema {
+	if b.servers == nil || serverID == "" || toolName == "" {
+		return nil
+	}
+	server, err := b.servers.Get(serverID)
+	if err != nil || server == nil {
+		return nil
+	}
+	for _, tool := range server.Tools {
+		if tool.Name == toolName {
+			return tool.InputSchema
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/mcpserver"
+	"github.com/dshills/goflow/pkg/storage"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// newCompletionTestBuilder returns a builder for a workflow with variables,
+// a declared server and a tool node, with a registry of two servers. The
+// write_file tool has an input schema.
+func newCompletionTestBuilder(t *testing.T) *WorkflowBuilder {
+	t.Helper()
+	wf := &workflow.Workflow{
+		Name:    "completion",
+		Version: "1.0",
+		Nodes: []workflow.Node{
+			&workflow.MCPToolNode{ID: "fetch", OutputVariable: "items"},
+			&workflow.TransformNode{ID: "sum", InputVariable: "items", Expression: "x", OutputVariable: "total"},
+		},
+		Variables: []*workflow.Variable{
+			{Name: "items", Type: "array"},
+			{Name: "total", Type: "number"},
+		},
+		ServerConfigs: []*workflow.ServerConfig{{ID: "filesystem"}},
+	}
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder failed: %v", err)
+	}
+
+	registry := mcpserver.NewRegistry()
+	for id, tools := range map[string][]string{
+		"filesystem": {"write_file", "read_file"},
+		"github":     {"create_issue"},
+	} {
+		server, err := mcpserver.NewMCPServer(id, "server-"+id, nil, mcpserver.TransportStdio)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range tools {
+			tool := mcpserver.Tool{Name: name}
+			if name == "write_file" {
+				tool.InputSchema = newArgumentTestSchema()
+			}
+			server.Tools = append(server.Tools, tool)
+		}
+		if err := registry.Register(server); err != nil {
+			t.Fatal(err)
+		}
+	}
+	builder.SetServerRegistry(registry)
+	return builder
+}
+
+// namedKeys are the keys typeKeys sends as is rather than character by
+// character
+var namedKeys = map[string]bool{"Enter": true, "Tab": true, "Up": true, "Esc": true, "Backspace": true, "Ctrl+s": true, "Ctrl+v": true}
+
+// typeKeys sends named keys, and the characters of other strings, to the
+// builder
+func typeKeys(t *testing.T, builder *WorkflowBuilder, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		chars := []string{key}
+		if !namedKeys[key] {
+			chars = strings.Split(key, "")
+		}
+		for _, char := range chars {
+			if err := builder.HandleKey(char); err != nil {
+				t.Fatalf("HandleKey(%q) error = %v", char, err)
+			}
+		}
+	}
+}
+
+func TestWorkflowBuilder_CompleteToolFields(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	if err := builder.EditNodeProperties("fetch"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+
+	if err := builder.HandleKey("Enter"); err == nil {
+		t.Error("expected the node ID to be read-only")
+	}
+
+	// Declared servers are offered before other registered ones
+	typeKeys(t, builder, "Tab", "Enter")
+	if matches, _ := panel.Completions(); !reflect.DeepEqual(matches, []string{"filesystem", "github"}) {
+		t.Errorf("server completions = %q", matches)
+	}
+	typeKeys(t, builder, "Tab", "Tab", "Up", "Enter")
+	if got := getFieldValue(panel.fields, "Server ID"); got != "filesystem" {
+		t.Errorf("Server ID = %q, want filesystem", got)
+	}
+
+	// Tools come from the chosen server
+	typeKeys(t, builder, "Tab", "Enter", "wr")
+	if matches, _ := panel.Completions(); !reflect.DeepEqual(matches, []string{"write_file"}) {
+		t.Errorf("tool completions = %q", matches)
+	}
+	typeKeys(t, builder, "Tab", "Enter")
+	if got := getFieldValue(panel.fields, "Tool Name"); got != "write_file" {
+		t.Errorf("Tool Name = %q, want write_file", got)
+	}
+}
+
+func TestWorkflowBuilder_CompleteExpressionVariables(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	if err := builder.EditNodeProperties("sum"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+
+	// Expression field; global keys such as q and ? are typed, not run
+	typeKeys(t, builder, "Tab", "Tab", "Enter", "Backspace", "q?", "Backspace", "Backspace", "to", "Tab")
+	if panel.editBuffer != "total" {
+		t.Fatalf("editBuffer = %q, want total", panel.editBuffer)
+	}
+	typeKeys(t, builder, " + len(it", "Tab", ")")
+	if panel.editBuffer != "total + len(items)" {
+		t.Fatalf("editBuffer = %q", panel.editBuffer)
+	}
+
+	// Fields of variables are not known, so are not completed
+	typeKeys(t, builder, " + items.t")
+	if matches, _ := panel.Completions(); len(matches) != 0 {
+		t.Errorf("completions after a field access = %q", matches)
+	}
+
+	// Esc discards the typed value but stays in edit mode
+	typeKeys(t, builder, "Esc")
+	if panel.IsEditing() || builder.Mode() != "edit" {
+		t.Fatalf("editing = %v, mode = %q after Esc", panel.IsEditing(), builder.Mode())
+	}
+	if got := getFieldValue(panel.fields, "Expression"); got != "x" {
+		t.Errorf("Expression = %q after Esc, want x", got)
+	}
+}
+
+func TestWorkflowBuilder_CompleteOutputFieldPaths(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	fetch := builder.workflow.Nodes[0].(*workflow.MCPToolNode)
+	fetch.ServerID, fetch.ToolName = "github", "list_issues"
+	samples := storage.NewToolSampleStore(t.TempDir())
+	for _, output := range []interface{}{
+		map[string]interface{}{"issues": []interface{}{map[string]interface{}{"title": "Bug", "number": 1}}},
+		map[string]interface{}{"issues": []interface{}{}, "total": 0},
+	} {
+		if err := samples.Record("github", "list_issues", output); err != nil {
+			t.Fatal(err)
+		}
+	}
+	builder.SetToolSampleStore(samples)
+	if err := builder.EditNodeProperties("sum"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+
+	// Paths are inferred from the outputs of the tool setting the input
+	typeKeys(t, builder, "Tab", "Tab", "Enter", "Backspace", "$.iss")
+	matches, _ := panel.Completions()
+	if want := []string{"$.issues", "$.issues[*].number", "$.issues[*].title"}; !reflect.DeepEqual(matches, want) {
+		t.Errorf("path completions = %q, want %q", matches, want)
+	}
+	typeKeys(t, builder, "Tab", "Tab", "Tab")
+	if panel.editBuffer != "$.issues[*].title" {
+		t.Errorf("editBuffer = %q, want $.issues[*].title", panel.editBuffer)
+	}
+
+	// Identifiers are still completed with variables only
+	typeKeys(t, builder, "Esc", "Enter", "Backspace", "to")
+	if matches, _ := panel.Completions(); !reflect.DeepEqual(matches, []string{"total"}) {
+		t.Errorf("variable completions = %q, want total", matches)
+	}
+}
+
+func TestWorkflowBuilder_CompleteOutputSchemaPaths(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	builder.workflow.Nodes = append(builder.workflow.Nodes, &workflow.TransformNode{
+		ID: "shape", InputVariable: "items", Expression: "$.data", OutputVariable: "shaped",
+		OutputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"owner": map[string]interface{}{"type": "string"},
+				"rows":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"properties": map[string]interface{}{"id": map[string]interface{}{}}}},
+			},
+		},
+	})
+	builder.workflow.Nodes[1].(*workflow.TransformNode).InputVariable = "shaped"
+	if err := builder.EditNodeProperties("sum"); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	panel := builder.GetPropertyPanel()
+
+	// Paths come from the schema declared by the transform setting the input
+	typeKeys(t, builder, "Tab", "Tab", "Enter", "Backspace", "$.")
+	matches, _ := panel.Completions()
+	if want := []string{"$.owner", "$.rows", "$.rows[*].id"}; !reflect.DeepEqual(matches, want) {
+		t.Errorf("path completions = %q, want %q", matches, want)
+	}
+}
+
+func TestWorkflowBuilderView_CapturesTextWhileEditing(t *testing.T) {
+	view, _ := newFileTestView(t)
+	if view.CapturingText() {
+		t.Fatal("expected no text input before editing")
+	}
+	nodeID := view.builder.workflow.Nodes[1].GetID()
+	if err := view.builder.EditNodeProperties(nodeID); err != nil {
+		t.Fatalf("EditNodeProperties failed: %v", err)
+	}
+	if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: "Tab"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: "Enter"}); err != nil {
+		t.Fatal(err)
+	}
+	if !view.CapturingText() {
+		t.Errorf("expected text input while editing, status %q", view.statusMsg)
+	}
+}
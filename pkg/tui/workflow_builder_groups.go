@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+)
+
+// Node groups organize a large workflow into named stages. A group is
+// framed on the canvas and can be collapsed into a single box, whose edges
+// stand for those of the nodes inside. Groups are saved in the workflow's
+// metadata; they are not part of the undo history.
+
+// GroupSelectedNodes groups the multi-selection under a new name
+func (b *WorkflowBuilder) GroupSelectedNodes(name string) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+	ids := b.GetSelectedNodeIDs()
+	if len(ids) == 0 {
+		return fmt.Errorf("no node selected")
+	}
+	if _, err := b.workflow.AddGroup(name, ids); err != nil {
+		return err
+	}
+	b.applyGroups()
+	b.ClearNodeSelection()
+	b.modified = true
+	return nil
+}
+
+// UngroupNodes removes a group, leaving its nodes in place. An empty name
+// removes the group of the current node.
+func (b *WorkflowBuilder) UngroupNodes(name string) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+	if name == "" {
+		group, err := b.selectedGroupName()
+		if err != nil {
+			return err
+		}
+		name = group
+	}
+	if err := b.workflow.RemoveGroup(name); err != nil {
+		return err
+	}
+	b.applyGroups()
+	b.selectedNodeID = b.canvas.selectedID
+	b.modified = true
+	return nil
+}
+
+// ToggleGroup collapses the group of the current node, or expands the
+// collapsed group selected
+func (b *WorkflowBuilder) ToggleGroup() error {
+	name, err := b.selectedGroupName()
+	if err != nil {
+		return err
+	}
+	group := b.workflow.Group(name)
+	group.Collapsed = !group.Collapsed
+	if group.Collapsed {
+		// Marks on hidden nodes would reach them through bulk operations
+		b.ClearNodeSelection()
+	}
+	b.applyGroups()
+	b.selectedNodeID = b.canvas.selectedID
+	b.modified = true
+	return nil
+}
+
+// selectedGroupName returns the group of the current node, or the name of
+// the collapsed group selected
+func (b *WorkflowBuilder) selectedGroupName() (string, error) {
+	if b.selectedNodeID == "" {
+		return "", fmt.Errorf("no node selected")
+	}
+	if name, ok := groupName(b.selectedNodeID); ok {
+		return name, nil
+	}
+	group := b.workflow.GroupOf(b.selectedNodeID)
+	if group == nil {
+		return "", fmt.Errorf("node %s is not in a group", b.selectedNodeID)
+	}
+	return group.Name, nil
+}
+
+// collapsedGroupSelected returns an error naming the collapsed group
+// selected, for operations that need a node; nil if a node is selected
+func (b *WorkflowBuilder) collapsedGroupSelected() error {
+	if name, ok := groupName(b.selectedNodeID); ok {
+		return fmt.Errorf("%s is a collapsed group: press z to expand it", name)
+	}
+	return nil
+}
+
+// applyGroups draws the workflow's groups on the canvas
+func (b *WorkflowBuilder) applyGroups() {
+	b.canvas.SetGroups(b.workflow.Metadata.Groups)
+}
+
+// visibleNodeID returns the canvas ID standing for a node: the node's own,
+// or its collapsed group's if it is hidden
+func (b *WorkflowBuilder) visibleNodeID(nodeID string) string {
+	if _, exists := b.canvas.nodes[nodeID]; exists {
+		return nodeID
+	}
+	if group := b.workflow.GroupOf(nodeID); group != nil {
+		if _, exists := b.canvas.nodes[groupNodeID(group.Name)]; exists {
+			return groupNodeID(group.Name)
+		}
+	}
+	return nodeID
+}
+
+// navigationOrder returns the canvas IDs Tab cycles through: the workflow's
+// nodes in order, with each collapsed group in place of its first node
+func (b *WorkflowBuilder) navigationOrder() []string {
+	ids := make([]string, 0, len(b.workflow.Nodes))
+	seen := make(map[string]bool)
+	for _, node := range b.workflow.Nodes {
+		id := b.visibleNodeID(node.GetID())
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+	"github.com/dshills/goterm"
+)
+
+// newGroupTestBuilder creates a builder for start -> fetch -> sum -> end
+// with fetch and sum grouped as "stage"
+func newGroupTestBuilder(t *testing.T) *WorkflowBuilder {
+	t.Helper()
+	wf := &workflow.Workflow{
+		Name: "groups",
+		Nodes: []workflow.Node{
+			&workflow.StartNode{ID: "start"},
+			&workflow.TransformNode{ID: "fetch", InputVariable: "in", Expression: "x", OutputVariable: "items"},
+			&workflow.TransformNode{ID: "sum", InputVariable: "items", Expression: "x", OutputVariable: "total"},
+			&workflow.EndNode{ID: "end"},
+		},
+		Edges: []*workflow.Edge{
+			{ID: "e1", FromNodeID: "start", ToNodeID: "fetch"},
+			{ID: "e2", FromNodeID: "fetch", ToNodeID: "sum"},
+			{ID: "e3", FromNodeID: "sum", ToNodeID: "end"},
+		},
+	}
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder failed: %v", err)
+	}
+
+	for _, id := range []string{"fetch", "sum"} {
+		if err := builder.SelectNode(id); err != nil {
+			t.Fatal(err)
+		}
+		if id == "fetch" {
+			if err := builder.HandleKey("m"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := builder.GroupSelectedNodes("stage"); err != nil {
+		t.Fatalf("GroupSelectedNodes failed: %v", err)
+	}
+	return builder
+}
+
+// canvasEdges returns the canvas's edges as sorted "from->to" strings
+func canvasEdges(builder *WorkflowBuilder) []string {
+	var edges []string
+	for _, e := range builder.canvas.edges {
+		edges = append(edges, e.edge.FromNodeID+"->"+e.edge.ToNodeID)
+	}
+	sort.Strings(edges)
+	return edges
+}
+
+// renderCanvasText renders the builder's canvas and returns its characters
+func renderCanvasText(t *testing.T, builder *WorkflowBuilder) string {
+	t.Helper()
+	screen := goterm.NewScreen(builder.canvas.Width, builder.canvas.Height)
+	if err := builder.canvas.RenderToScreen(screen); err != nil {
+		t.Fatalf("RenderToScreen failed: %v", err)
+	}
+	var text strings.Builder
+	for y := 0; y < builder.canvas.Height; y++ {
+		for x := 0; x < builder.canvas.Width; x++ {
+			text.WriteRune(screen.GetCell(x, y).Ch)
+		}
+		text.WriteRune('\n')
+	}
+	return text.String()
+}
+
+func TestWorkflowBuilder_CollapseGroup(t *testing.T) {
+	builder := newGroupTestBuilder(t)
+	if text := renderCanvasText(t, builder); !strings.Contains(text, "╭─ stage ") {
+		t.Errorf("expanded group not framed:\n%s", text)
+	}
+
+	if err := builder.HandleKey("z"); err != nil {
+		t.Fatalf("HandleKey('z') failed: %v", err)
+	}
+	if !builder.workflow.Group("stage").Collapsed {
+		t.Error("expected the group to be saved collapsed")
+	}
+	if _, exists := builder.canvas.nodes["fetch"]; exists {
+		t.Error("expected fetch to be hidden")
+	}
+	if got := builder.GetSelectedNodeID(); got != "group:stage" {
+		t.Errorf("selected %q, want the collapsed group", got)
+	}
+	want := []string{"group:stage->end", "start->group:stage"}
+	if got := canvasEdges(builder); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+	if text := renderCanvasText(t, builder); !strings.Contains(text, "stage (2)") {
+		t.Errorf("collapsed group not drawn:\n%s", text)
+	}
+
+	// The group stands in for its nodes until expanded
+	if err := builder.HandleKey("d"); err == nil || !strings.Contains(err.Error(), "collapsed group") {
+		t.Errorf("HandleKey('d') error = %v", err)
+	}
+	if len(builder.workflow.Nodes) != 4 {
+		t.Errorf("expected no node deleted, have %d", len(builder.workflow.Nodes))
+	}
+
+	if err := builder.HandleKey("Enter"); err != nil {
+		t.Fatalf("HandleKey('Enter') failed: %v", err)
+	}
+	if builder.workflow.Group("stage").Collapsed {
+		t.Error("expected Enter to expand the group")
+	}
+	if got := builder.GetSelectedNodeID(); got != "fetch" {
+		t.Errorf("selected %q, want the group's first node", got)
+	}
+	want = []string{"fetch->sum", "start->fetch", "sum->end"}
+	if got := canvasEdges(builder); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+}
+
+func TestWorkflowBuilder_CollapsedGroupSurvivesUndo(t *testing.T) {
+	builder := newGroupTestBuilder(t)
+	if err := builder.HandleKey("z"); err != nil {
+		t.Fatal(err)
+	}
+
+	positions := builder.getCanvasPositions()
+	if _, exists := positions["sum"]; !exists {
+		t.Error("expected positions of hidden nodes to be kept")
+	}
+	if _, exists := positions["group:stage"]; exists {
+		t.Error("expected no position for the collapsed group")
+	}
+
+	if err := builder.AddNodeAtPosition("Transform", Position{X: 60, Y: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if _, exists := builder.canvas.nodes["group:stage"]; !exists {
+		t.Error("expected the group to stay collapsed after undo")
+	}
+	if _, exists := builder.canvas.nodes["sum"]; exists {
+		t.Error("expected sum to stay hidden after undo")
+	}
+}
+
+func TestWorkflowBuilder_NavigateCollapsedGroup(t *testing.T) {
+	builder := newGroupTestBuilder(t)
+	if err := builder.HandleKey("z"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builder.SelectNode("start"); err != nil {
+		t.Fatal(err)
+	}
+	var visited []string
+	for i := 0; i < 4; i++ {
+		if err := builder.HandleKey("Tab"); err != nil {
+			t.Fatal(err)
+		}
+		visited = append(visited, builder.GetSelectedNodeID())
+	}
+	want := []string{"start", "group:stage", "end", "start"}
+	if strings.Join(visited, " ") != strings.Join(want, " ") {
+		t.Errorf("Tab visited %v, want %v", visited, want)
+	}
+
+	// Selecting a hidden node selects its group
+	if err := builder.SelectNode("sum"); err != nil {
+		t.Fatal(err)
+	}
+	if got := builder.GetSelectedNodeID(); got != "group:stage" {
+		t.Errorf("selected %q, want the collapsed group", got)
+	}
+}
+
+func TestWorkflowBuilder_UngroupNodes(t *testing.T) {
+	builder := newGroupTestBuilder(t)
+	if err := builder.HandleKey("z"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builder.UngroupNodes(""); err != nil {
+		t.Fatalf("UngroupNodes failed: %v", err)
+	}
+	if builder.workflow.Group("stage") != nil {
+		t.Error("expected the group to be removed")
+	}
+	for _, id := range []string{"fetch", "sum"} {
+		if _, exists := builder.canvas.nodes[id]; !exists {
+			t.Errorf("expected %s back on the canvas", id)
+		}
+	}
+	if err := builder.HandleKey("z"); err == nil {
+		t.Error("expected z to fail for a node in no group")
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"testing"
+	"time"
 
 	"github.com/dshills/goflow/pkg/workflow"
 )
@@ -537,6 +538,180 @@ func TestWorkflowBuilderComplexWorkflow(t *testing.T) {
 	}
 }
 
+// countingRepository records saves for autosave tests
+type countingRepository struct {
+	saves int
+}
+
+func (r *countingRepository) Save(wf *workflow.Workflow) error { r.saves++; return nil }
+func (r *countingRepository) FindByID(id string) (*workflow.Workflow, error) {
+	return nil, workflow.ErrWorkflowNotFound
+}
+func (r *countingRepository) FindByName(name string) (*workflow.Workflow, error) {
+	return nil, workflow.ErrWorkflowNotFound
+}
+func (r *countingRepository) List() ([]*workflow.Workflow, error) { return nil, nil }
+func (r *countingRepository) Delete(id string) error              { return nil }
+
+// TestWorkflowBuilderTimings tests validation debounce and autosave
+func TestWorkflowBuilderTimings(t *testing.T) {
+	newValidWorkflow := func() *workflow.Workflow {
+		return &workflow.Workflow{
+			Name:    "timed-workflow",
+			Version: "1.0",
+			Nodes: []workflow.Node{
+				&workflow.StartNode{ID: "start"},
+				&workflow.EndNode{ID: "end"},
+			},
+			Edges: []*workflow.Edge{{ID: "edge-1", FromNodeID: "start", ToNodeID: "end"}},
+		}
+	}
+
+	t.Run("validation is immediate by default", func(t *testing.T) {
+		builder, err := NewWorkflowBuilder(newValidWorkflow())
+		if err != nil {
+			t.Fatalf("Failed to create builder: %v", err)
+		}
+
+		builder.validateWorkflow()
+		if builder.ValidationPending() {
+			t.Error("Expected no pending validation without debounce")
+		}
+	})
+
+	t.Run("debounced validation runs on tick", func(t *testing.T) {
+		builder, err := NewWorkflowBuilder(newValidWorkflow())
+		if err != nil {
+			t.Fatalf("Failed to create builder: %v", err)
+		}
+		builder.SetValidationDebounce(200 * time.Millisecond)
+
+		// Break the workflow; validation is deferred
+		builder.workflow.Nodes = builder.workflow.Nodes[:1]
+		builder.validateWorkflow()
+		if !builder.ValidationPending() {
+			t.Fatal("Expected validation to be pending")
+		}
+		if !builder.GetValidationStatus().IsValid {
+			t.Error("Expected previous status until debounce elapses")
+		}
+
+		builder.Tick(time.Now())
+		if !builder.ValidationPending() {
+			t.Error("Expected validation to stay pending before debounce elapses")
+		}
+
+		builder.Tick(time.Now().Add(time.Second))
+		if builder.ValidationPending() {
+			t.Error("Expected pending validation to run after debounce")
+		}
+		if builder.GetValidationStatus().IsValid {
+			t.Error("Expected workflow to be invalid after debounced validation")
+		}
+	})
+
+	t.Run("disabling debounce flushes pending validation", func(t *testing.T) {
+		builder, err := NewWorkflowBuilder(newValidWorkflow())
+		if err != nil {
+			t.Fatalf("Failed to create builder: %v", err)
+		}
+		builder.SetValidationDebounce(time.Second)
+		builder.validateWorkflow()
+
+		builder.SetValidationDebounce(0)
+		if builder.ValidationPending() {
+			t.Error("Expected pending validation to run when debounce is disabled")
+		}
+	})
+
+	t.Run("autosave saves modified workflow after interval", func(t *testing.T) {
+		builder, err := NewWorkflowBuilder(newValidWorkflow())
+		if err != nil {
+			t.Fatalf("Failed to create builder: %v", err)
+		}
+		repo := &countingRepository{}
+		builder.SetRepository(repo)
+		builder.SetAutosaveInterval(30 * time.Second)
+
+		start := time.Now()
+
+		// Unmodified workflows are not saved
+		if err := builder.Tick(start.Add(time.Minute)); err != nil {
+			t.Fatalf("Tick() error = %v", err)
+		}
+		if repo.saves != 0 {
+			t.Errorf("Expected no save for unmodified workflow, got %d", repo.saves)
+		}
+
+		builder.MarkModified()
+		if err := builder.Tick(start.Add(2 * time.Minute)); err != nil {
+			t.Fatalf("Tick() error = %v", err)
+		}
+		if repo.saves != 1 {
+			t.Errorf("Expected 1 autosave, got %d", repo.saves)
+		}
+		if builder.IsModified() {
+			t.Error("Expected modified flag to be cleared by autosave")
+		}
+	})
+
+	t.Run("flush saves pending changes before the interval", func(t *testing.T) {
+		builder, err := NewWorkflowBuilder(newValidWorkflow())
+		if err != nil {
+			t.Fatalf("Failed to create builder: %v", err)
+		}
+		repo := &countingRepository{}
+		builder.SetRepository(repo)
+		builder.SetAutosaveInterval(time.Hour)
+		if err := builder.SaveWorkflow(); err != nil {
+			t.Fatalf("SaveWorkflow() error = %v", err)
+		}
+
+		builder.MarkModified()
+		if err := builder.FlushAutosave(); err != nil {
+			t.Fatalf("FlushAutosave() error = %v", err)
+		}
+		if repo.saves != 2 || builder.IsModified() {
+			t.Errorf("Expected flush to save immediately, got %d saves, modified=%v", repo.saves, builder.IsModified())
+		}
+
+		// Nothing pending: no further save
+		if err := builder.FlushAutosave(); err != nil {
+			t.Fatalf("FlushAutosave() error = %v", err)
+		}
+		if repo.saves != 2 {
+			t.Errorf("Expected no save without changes, got %d", repo.saves)
+		}
+
+		// Autosave disabled: flushing does not save on the user's behalf
+		builder.SetAutosaveInterval(0)
+		builder.MarkModified()
+		if err := builder.FlushAutosave(); err != nil {
+			t.Fatalf("FlushAutosave() error = %v", err)
+		}
+		if repo.saves != 2 {
+			t.Errorf("Expected no save with autosave disabled, got %d", repo.saves)
+		}
+	})
+
+	t.Run("autosave disabled by default", func(t *testing.T) {
+		builder, err := NewWorkflowBuilder(newValidWorkflow())
+		if err != nil {
+			t.Fatalf("Failed to create builder: %v", err)
+		}
+		repo := &countingRepository{}
+		builder.SetRepository(repo)
+		builder.MarkModified()
+
+		if err := builder.Tick(time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("Tick() error = %v", err)
+		}
+		if repo.saves != 0 {
+			t.Errorf("Expected no autosave when disabled, got %d", repo.saves)
+		}
+	})
+}
+
 // Helper function (using unique name to avoid conflicts)
 func containsSubstring(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && findSubstringIn(s, substr))
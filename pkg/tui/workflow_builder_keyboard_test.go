@@ -540,7 +540,7 @@ func TestHandleKey_InvalidKeys(t *testing.T) {
 		key  string
 	}{
 		{"normal", "x"},
-		{"normal", "z"},
+		{"normal", "g"},
 		{"edit", "a"},
 		{"palette", "Ctrl+x"},
 	}
@@ -0,0 +1,48 @@
+package tui
+
+import "github.com/dshills/goflow/pkg/workflow"
+
+// canvasLayout captures the canvas as the layout saved with the workflow:
+// every node's position, including nodes hidden in collapsed groups, with
+// the zoom and viewport
+func (b *WorkflowBuilder) canvasLayout() *workflow.CanvasLayout {
+	positions := b.getCanvasPositions()
+	layout := &workflow.CanvasLayout{
+		Positions: make(map[string]workflow.CanvasPosition, len(positions)),
+		ViewportX: b.canvas.ViewportX,
+		ViewportY: b.canvas.ViewportY,
+	}
+	for nodeID, pos := range positions {
+		layout.Positions[nodeID] = workflow.CanvasPosition{X: pos.X, Y: pos.Y}
+	}
+	if b.canvas.ZoomLevel != 1.0 {
+		layout.Zoom = b.canvas.ZoomLevel
+	}
+	return layout
+}
+
+// applyCanvasView restores the zoom and viewport saved with the workflow.
+// Out of range values are ignored.
+func (b *WorkflowBuilder) applyCanvasView() {
+	layout := b.workflow.Metadata.Canvas
+	if layout == nil {
+		return
+	}
+	if layout.Zoom != 0 {
+		_ = b.canvas.Zoom(layout.Zoom) // Out of range zoom keeps the default
+	}
+	if layout.ViewportX >= 0 && layout.ViewportY >= 0 {
+		b.canvas.ViewportX = layout.ViewportX
+		b.canvas.ViewportY = layout.ViewportY
+	}
+}
+
+// savedPosition returns a node's position from the layout saved with the
+// workflow
+func (b *WorkflowBuilder) savedPosition(nodeID string) (Position, bool) {
+	pos, ok := b.workflow.Metadata.Canvas.Position(nodeID)
+	if !ok || pos.X < 0 || pos.Y < 0 {
+		return Position{}, false
+	}
+	return Position{X: pos.X, Y: pos.Y}, true
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+const layoutTestWorkflowYAML = `
+version: "1.0.0"
+name: "layout"
+variables:
+  - name: "in"
+    type: "string"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "shape"
+    type: "transform"
+    input: "in"
+    expression: "$.name"
+    output: "name"
+  - id: "end"
+    type: "end"
+    return: "${name}"
+edges:
+  - from: "start"
+    to: "shape"
+  - from: "shape"
+    to: "end"
+`
+
+func TestWorkflowBuilder_CanvasLayoutSaved(t *testing.T) {
+	wf, err := workflow.Parse([]byte(layoutTestWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder failed: %v", err)
+	}
+
+	if err := builder.MoveNodeBy("shape", 25, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.canvas.Zoom(1.5); err != nil {
+		t.Fatal(err)
+	}
+	builder.canvas.ViewportX, builder.canvas.ViewportY = 7, 3
+	want := builder.getCanvasPositions()
+
+	if err := builder.SaveWorkflow(); err != nil {
+		t.Fatalf("SaveWorkflow failed: %v", err)
+	}
+	data, err := workflow.ToYAML(builder.GetWorkflow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := workflow.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse of saved workflow failed: %v", err)
+	}
+
+	again, err := NewWorkflowBuilder(reopened)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder failed: %v", err)
+	}
+	if got := again.getCanvasPositions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("reopened positions = %v, want %v", got, want)
+	}
+	if again.canvas.ZoomLevel != 1.5 || again.canvas.ViewportX != 7 || again.canvas.ViewportY != 3 {
+		t.Errorf("reopened zoom %v viewport (%d,%d), want 1.5 (7,3)", again.canvas.ZoomLevel, again.canvas.ViewportX, again.canvas.ViewportY)
+	}
+
+	// Adding a node keeps the others where they were and stacks the new
+	// one below them
+	if err := again.AddNodeToCanvas(&workflow.PassthroughNode{ID: "log"}); err != nil {
+		t.Fatal(err)
+	}
+	got := again.getCanvasPositions()
+	for nodeID, pos := range want {
+		if got[nodeID] != pos {
+			t.Errorf("%s moved to %v, want %v", nodeID, got[nodeID], pos)
+		}
+	}
+	if got["log"].Y <= want["end"].Y {
+		t.Errorf("new node at %v, want it below %v", got["log"], want["end"])
+	}
+}
+
+func TestWorkflowBuilder_CanvasLayoutIgnoresInvalidView(t *testing.T) {
+	wf, err := workflow.Parse([]byte(layoutTestWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	wf.Metadata.Canvas = &workflow.CanvasLayout{
+		Positions: map[string]workflow.CanvasPosition{"shape": {X: -1, Y: 4}},
+		Zoom:      9,
+		ViewportX: -5,
+	}
+
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("NewWorkflowBuilder failed: %v", err)
+	}
+	if builder.canvas.ZoomLevel != 1.0 || builder.canvas.ViewportX != 0 {
+		t.Errorf("zoom %v viewport x %d, want the defaults", builder.canvas.ZoomLevel, builder.canvas.ViewportX)
+	}
+	if pos := builder.canvas.nodes["shape"].position; pos.X < 0 {
+		t.Errorf("shape at %v, want the invalid saved position ignored", pos)
+	}
+}
@@ -0,0 +1,24 @@
+package tui
+
+import "fmt"
+
+// SetNodeNote attaches a note to the current node, drawn beside it on the
+// canvas and saved with the workflow. An empty note removes it. Notes are
+// not part of the undo history.
+func (b *WorkflowBuilder) SetNodeNote(text string) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+	if b.selectedNodeID == "" {
+		return fmt.Errorf("no node selected")
+	}
+	if err := b.collapsedGroupSelected(); err != nil {
+		return err
+	}
+	if err := b.workflow.SetNote(b.selectedNodeID, text); err != nil {
+		return err
+	}
+	b.canvas.SetNotes(b.workflow.Metadata.Notes)
+	b.modified = true
+	return nil
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrapNote(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"short", "Fetches users", []string{"Fetches users"}},
+		{"wrapped", "Fetches every page of users from the API", []string{"Fetches every page of", "users from the API"}},
+		{"paragraphs", "First\nSecond", []string{"First", "Second"}},
+		{"long word", "abcdefghijklmnopqrstuvwxyz", []string{"abcdefghijklmnopqrstuv", "wxyz"}},
+		{"cut off", "one\ntwo\nthree\nfour\nfive", []string{"one", "two", "three", "four…"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapNote(tt.text); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrapNote(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowBuilder_SetNodeNote(t *testing.T) {
+	builder := newGroupTestBuilder(t)
+	if err := builder.SelectNode("start"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builder.SetNodeNote("Kicks off the nightly import"); err != nil {
+		t.Fatalf("SetNodeNote failed: %v", err)
+	}
+	if got := builder.GetWorkflow().Note("start"); got != "Kicks off the nightly import" {
+		t.Errorf("note = %q", got)
+	}
+	if !builder.IsModified() {
+		t.Error("expected the workflow to be modified")
+	}
+	if text := renderCanvasText(t, builder); !strings.Contains(text, "Kicks off the nightly") {
+		t.Errorf("note not drawn:\n%s", text)
+	}
+
+	if err := builder.SetNodeNote(""); err != nil {
+		t.Fatalf("SetNodeNote failed: %v", err)
+	}
+	if text := renderCanvasText(t, builder); strings.Contains(text, "Kicks off") {
+		t.Errorf("removed note still drawn:\n%s", text)
+	}
+
+	// A collapsed group is not a node
+	if err := builder.SelectNode("fetch"); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.HandleKey("z"); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.SetNodeNote("hidden"); err == nil {
+		t.Error("expected an error for a collapsed group")
+	}
+}
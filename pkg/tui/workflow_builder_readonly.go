@@ -0,0 +1,44 @@
+package tui
+
+import "fmt"
+
+// editingKeys are the keys that change the workflow, by mode. A read-only
+// workflow refuses them; navigation, search, yanking, validation and
+// viewing node properties still work.
+var editingKeys = map[string]map[string]bool{
+	"normal": {
+		"a": true, "d": true, "c": true, "p": true, "s": true, "u": true, "Ctrl+r": true,
+		"h": true, "j": true, "k": true, "l": true,
+		"L": true, "T": true, "C": true, "H": true, "J": true,
+	},
+	"visual": {
+		"Left": true, "Right": true, "Up": true, "Down": true,
+		"L": true, "T": true, "C": true, "H": true, "J": true,
+		"d": true, "x": true,
+	},
+	"edit": {"Enter": true, "Ctrl+s": true},
+}
+
+// SetReadOnly makes the workflow read-only for the given reason, or
+// editable again if reason is empty
+func (b *WorkflowBuilder) SetReadOnly(reason string) {
+	b.readOnly = reason
+	if reason != "" && b.propertyPanel.IsEditing() {
+		b.propertyPanel.CancelEdit()
+	}
+}
+
+// ReadOnly returns why the workflow is read-only, or "" if it can be
+// edited
+func (b *WorkflowBuilder) ReadOnly() string {
+	return b.readOnly
+}
+
+// checkWritable returns an error saying why the workflow can't be changed,
+// or nil if it can
+func (b *WorkflowBuilder) checkWritable() error {
+	if b.readOnly == "" {
+		return nil
+	}
+	return fmt.Errorf("read-only because %s", b.readOnly)
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/config"
+	"github.com/dshills/goterm"
+)
+
+func TestWorkflowBuilder_ReadOnly(t *testing.T) {
+	builder := newCompletionTestBuilder(t)
+	builder.SetReadOnly("the workflow's metadata sets read_only")
+	if err := builder.SelectNode("fetch"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Editing keys are refused, with the reason
+	for _, key := range []string{"a", "d", "p", "u", "l", "s"} {
+		err := builder.HandleKey(key)
+		if err == nil || !strings.Contains(err.Error(), "read_only") {
+			t.Errorf("HandleKey(%q) error = %v, want the read-only reason", key, err)
+		}
+	}
+	if len(builder.GetWorkflow().Nodes) != 2 || builder.IsModified() {
+		t.Fatal("expected the workflow to be unchanged")
+	}
+	if err := builder.SetNodeNote("note"); err == nil {
+		t.Error("expected notes to be refused")
+	}
+	if err := builder.SaveWorkflow(); err == nil {
+		t.Error("expected saving to be refused")
+	}
+
+	// Exploring still works: moving the selection, validating, and viewing
+	// node properties without editing them
+	typeKeys(t, builder, "Tab", "Tab", "v", "Enter")
+	if builder.GetSelectedNodeID() != "sum" || !builder.GetPropertyPanel().IsVisible() {
+		t.Errorf("selected %q, property panel visible %v", builder.GetSelectedNodeID(), builder.GetPropertyPanel().IsVisible())
+	}
+	if err := builder.HandleKey("Enter"); err == nil || builder.GetPropertyPanel().IsEditing() {
+		t.Error("expected field editing to be refused")
+	}
+
+	builder.SetReadOnly("")
+	typeKeys(t, builder, "Esc", "l")
+	if !builder.IsModified() {
+		t.Error("expected edits once the workflow is editable again")
+	}
+}
+
+func TestWorkflowBuilderView_ReadOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	wf := newRunTestWorkflow(t)
+	wf.Metadata.ReadOnly = true
+	writeBrowserWorkflow(t, dir, "shared.yaml", wf, time.Now())
+	writeBrowserWorkflow(t, dir, "own.yaml", newRunTestWorkflow(t), time.Now())
+
+	view := NewWorkflowBuilderView()
+	defer view.stopWatching()
+	if err := view.OpenFile(filepath.Join(dir, "shared.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(view.statusMsg, "read-only because the workflow's metadata sets read_only") {
+		t.Errorf("status = %q, want the read-only reason", view.statusMsg)
+	}
+	screen := goterm.NewScreen(100, 30)
+	if err := view.Render(screen); err != nil {
+		t.Fatal(err)
+	}
+
+	// :w explains why it is blocked, and the file is left alone
+	before, _ := os.ReadFile(filepath.Join(dir, "shared.yaml"))
+	err := view.Save()
+	if err == nil || !strings.Contains(err.Error(), "cannot save shared.yaml: it is read-only because the workflow's metadata sets read_only") {
+		t.Errorf("Save() error = %v", err)
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, "shared.yaml"))
+	if string(before) != string(after) {
+		t.Error("expected the read-only file to be unchanged")
+	}
+
+	// The viewer role makes every workflow read-only
+	if err := view.OpenFile(filepath.Join(dir, "own.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if view.builder.ReadOnly() != "" {
+		t.Fatalf("own.yaml is read-only: %s", view.builder.ReadOnly())
+	}
+	tunables := config.DefaultTunables()
+	tunables.Role = config.RoleViewer
+	view.ApplyTunables(tunables)
+	if err := view.Save(); err == nil || !strings.Contains(err.Error(), "role: viewer") {
+		t.Errorf("Save() as a viewer error = %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// Search finds the nodes whose ID, type, tool, expressions or note contain
+// a query, ignoring case. Matches are highlighted on the canvas; n and N
+// jump between them.
+
+// Search highlights the nodes matching query and jumps to the first match
+// at or after the current node. It returns the number of matches; an
+// empty query clears the search.
+func (b *WorkflowBuilder) Search(query string) (int, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		b.ClearSearch()
+		return 0, nil
+	}
+	b.searchQuery = query
+	matches := b.searchMatches()
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("pattern not found: %s", query)
+	}
+
+	// Start from the current node, in workflow order
+	order := make(map[string]int, len(b.workflow.Nodes))
+	for i, node := range b.workflow.Nodes {
+		order[node.GetID()] = i
+	}
+	current := -1
+	if idx, exists := order[b.selectedNodeID]; exists {
+		current = idx
+	}
+	b.searchIndex = 0
+	for i, id := range matches {
+		if order[id] >= current {
+			b.searchIndex = i
+			break
+		}
+	}
+	return len(matches), b.JumpToNode(matches[b.searchIndex])
+}
+
+// NextMatch jumps to the next search match, or the previous one if
+// backward is set, wrapping around
+func (b *WorkflowBuilder) NextMatch(backward bool) error {
+	if b.searchQuery == "" {
+		return fmt.Errorf("no search: press / to search")
+	}
+	matches := b.searchMatches()
+	if len(matches) == 0 {
+		return fmt.Errorf("pattern not found: %s", b.searchQuery)
+	}
+
+	delta := 1
+	if backward {
+		delta = -1
+	}
+	// Matches hidden in a collapsed group select the group, so compare
+	// the node each match is drawn as
+	current := -1
+	if b.searchIndex < len(matches) && b.visibleNodeID(matches[b.searchIndex]) == b.selectedNodeID {
+		current = b.searchIndex
+	}
+	switch {
+	case current >= 0:
+		b.searchIndex = WrapSearchIndex(current, delta, len(matches))
+	case backward:
+		b.searchIndex = len(matches) - 1
+	default:
+		b.searchIndex = 0
+	}
+	return b.JumpToNode(matches[b.searchIndex])
+}
+
+// ClearSearch forgets the search and removes its highlights
+func (b *WorkflowBuilder) ClearSearch() {
+	b.searchQuery = ""
+	b.searchIndex = 0
+	b.canvas.SetSearchMatches(nil)
+}
+
+// SearchStatus describes the search, such as "/fetch 2/5", or returns ""
+// if there is none
+func (b *WorkflowBuilder) SearchStatus() string {
+	if b.searchQuery == "" {
+		return ""
+	}
+	matches := b.searchMatches()
+	if len(matches) == 0 {
+		return fmt.Sprintf("/%s no matches", b.searchQuery)
+	}
+	return fmt.Sprintf("/%s %d/%d", b.searchQuery, min(b.searchIndex+1, len(matches)), len(matches))
+}
+
+// searchMatches returns the IDs of the nodes matching the search, in
+// workflow order, and highlights them. Matches are found afresh each time,
+// so they follow edits made since the search.
+func (b *WorkflowBuilder) searchMatches() []string {
+	query := strings.ToLower(b.searchQuery)
+	var matches []string
+	highlight := make(map[string]bool)
+	for _, node := range b.workflow.Nodes {
+		text := strings.ToLower(strings.Join(nodeSearchText(node), "\n") + "\n" + b.workflow.Note(node.GetID()))
+		if strings.Contains(text, query) {
+			matches = append(matches, node.GetID())
+			highlight[node.GetID()] = true
+		}
+	}
+	b.canvas.SetSearchMatches(highlight)
+	return matches
+}
+
+// nodeSearchText returns what search looks at in a node: its ID, type,
+// tool and the expressions and variables it uses
+func nodeSearchText(node workflow.Node) []string {
+	fields := []string{node.GetID(), node.Type()}
+	switch n := node.(type) {
+	case *workflow.MCPToolNode:
+		fields = append(fields, n.ServerID+"."+n.ToolName, n.OutputVariable)
+		names := make([]string, 0, len(n.Parameters))
+		for name := range n.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fields = append(fields, name+": "+n.Parameters[name])
+		}
+	case *workflow.TransformNode:
+		fields = append(fields, n.InputVariable, n.Expression, n.OutputVariable)
+	case *workflow.ConditionNode:
+		fields = append(fields, n.Condition)
+	case *workflow.SwitchNode:
+		for _, c := range n.Cases {
+			fields = append(fields, c.Label+": "+c.Condition)
+		}
+	case *workflow.LoopNode:
+		fields = append(fields, n.Collection, n.ItemVariable, n.BreakCondition)
+	case *workflow.TryNode:
+		fields = append(fields, n.Body...)
+	case *workflow.CatchNode:
+		fields = append(fields, n.ErrorVariable)
+	case *workflow.DelayNode:
+		fields = append(fields, n.Duration, n.Until)
+	case *workflow.ApprovalNode:
+		fields = append(fields, n.Message, n.OutputVariable)
+	case *workflow.BatchToolNode:
+		fields = append(fields, n.ServerID+"."+n.ToolName, n.Collection, n.ItemVariable, n.OutputVariable, n.ErrorVariable)
+		names := make([]string, 0, len(n.Parameters))
+		for name := range n.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fields = append(fields, name+": "+n.Parameters[name])
+		}
+	case *workflow.EndNode:
+		fields = append(fields, n.ReturnValue)
+	}
+	return fields
+}
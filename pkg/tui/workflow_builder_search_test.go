@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkflowBuilder_Search(t *testing.T) {
+	builder := newGroupTestBuilder(t)
+	if err := builder.SelectNode("start"); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.SetNodeNote("Imports the nightly ITEMS"); err != nil {
+		t.Fatal(err)
+	}
+
+	// "items" is sum's input, fetch's output and start's note
+	count, err := builder.Search("items")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Search found %d nodes, want 3", count)
+	}
+	if got := builder.GetSelectedNodeID(); got != "start" {
+		t.Errorf("selected %q, want the current node, which matches", got)
+	}
+	if text := renderCanvasText(t, builder); !strings.Contains(text, "Imports") {
+		t.Errorf("canvas not drawn:\n%s", text)
+	}
+	if !builder.canvas.isSearchMatch(builder.canvas.nodes["sum"]) || builder.canvas.isSearchMatch(builder.canvas.nodes["end"]) {
+		t.Error("expected only matches highlighted")
+	}
+
+	var visited []string
+	for _, key := range []string{"n", "n", "n", "N"} {
+		if err := builder.HandleKey(key); err != nil {
+			t.Fatalf("HandleKey(%q) failed: %v", key, err)
+		}
+		visited = append(visited, builder.GetSelectedNodeID())
+	}
+	if want := "fetch sum start sum"; strings.Join(visited, " ") != want {
+		t.Errorf("n/N visited %v, want %s", visited, want)
+	}
+	if got := builder.SearchStatus(); got != "/items 3/3" {
+		t.Errorf("SearchStatus() = %q", got)
+	}
+
+	if _, err := builder.Search("no such thing"); err == nil || !strings.Contains(err.Error(), "pattern not found") {
+		t.Errorf("Search error = %v", err)
+	}
+
+	// Escape ends the search
+	if err := builder.HandleKey("Esc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.HandleKey("n"); err == nil {
+		t.Error("expected n to fail without a search")
+	}
+}
+
+func TestWorkflowBuilder_SearchCollapsedGroup(t *testing.T) {
+	builder := newGroupTestBuilder(t)
+	if err := builder.HandleKey("z"); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.SelectNode("start"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both matches are hidden in the group, so n steps through them on it
+	if _, err := builder.Search("transform"); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if got := builder.GetSelectedNodeID(); got != "group:stage" {
+		t.Errorf("selected %q, want the collapsed group", got)
+	}
+	if !builder.canvas.isSearchMatch(builder.canvas.nodes["group:stage"]) {
+		t.Error("expected the group highlighted")
+	}
+	if err := builder.NextMatch(false); err != nil {
+		t.Fatal(err)
+	}
+	if got := builder.SearchStatus(); got != "/transform 2/2" {
+		t.Errorf("SearchStatus() = %q", got)
+	}
+}
+
+func TestWorkflowBuilderView_SearchPrompt(t *testing.T) {
+	view, _ := newFileTestView(t)
+
+	for _, key := range "/jq(" {
+		if err := view.HandleKey(KeyEvent{Key: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !view.CapturingText() {
+		t.Error("expected the prompt to capture keys")
+	}
+	if got := view.builder.GetSelectedNodeID(); got != "transform" {
+		t.Errorf("selected %q while typing, want the matching node", got)
+	}
+	if screen := renderViewText(t, view, 120, 30); !strings.Contains(screen, "/jq(") {
+		t.Errorf("query not shown:\n%s", screen)
+	}
+
+	if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: "Enter"}); err != nil {
+		t.Fatal(err)
+	}
+	if view.search != nil || view.builder.searchQuery != "jq(" {
+		t.Errorf("expected Enter to keep the search, have %q", view.builder.searchQuery)
+	}
+
+	// Escape restores the search from before the prompt
+	for _, key := range "/wri" {
+		if err := view.HandleKey(KeyEvent{Key: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := view.HandleKey(KeyEvent{IsSpecial: true, Special: "Escape"}); err != nil {
+		t.Fatal(err)
+	}
+	if view.builder.searchQuery != "jq(" {
+		t.Errorf("search after Escape = %q, want jq(", view.builder.searchQuery)
+	}
+}
+
+func TestWorkflowBuilderView_FinderSearchesSavedWorkflows(t *testing.T) {
+	view, path := newFileTestView(t)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Dir(path)
+	other := strings.ReplaceAll(string(data), "jq(.data | map(.price) | add)", "jq(.data | map(.quantity) | add)")
+	if err := os.WriteFile(filepath.Join(dir, "inventory.yaml"), []byte(other), 0644); err != nil {
+		t.Fatal(err)
+	}
+	view.workflowsDir = dir
+
+	finder := NewFuzzyFinder(nil)
+	finder.Open(view.FinderItems())
+	finder.SetQuery("/quantity")
+	match, ok := finder.Selected()
+	if !ok || match.Item.Value != "inventory.yaml" || match.Item.Node != "transform" {
+		t.Fatalf("best match = %+v, want inventory.yaml's transform node", match.Item)
+	}
+
+	// Without the prefix, other workflows' nodes are left out
+	finder.SetQuery("quantity")
+	if len(finder.Matches()) != 0 {
+		t.Errorf("unprefixed query matched %+v", finder.Matches())
+	}
+}
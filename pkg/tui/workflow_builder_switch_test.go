@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+func newSwitchTestBuilder(t *testing.T) *WorkflowBuilder {
+	t.Helper()
+	wf, err := workflow.NewWorkflow("test", "test workflow")
+	if err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+
+	builder.AddNodeToCanvas(&workflow.SwitchNode{
+		ID: "size",
+		Cases: []workflow.SwitchCase{
+			{Label: "large", Condition: "total > 100"},
+			{Label: "medium", Condition: "total > 10"},
+		},
+	})
+	for _, id := range []string{"large_path", "medium_path", "small_path", "extra_path"} {
+		builder.AddNodeToCanvas(&workflow.PassthroughNode{ID: id})
+	}
+	return builder
+}
+
+func TestCreateEdgeFromSwitchLabelsCases(t *testing.T) {
+	builder := newSwitchTestBuilder(t)
+
+	// Each new edge takes the next unconnected case, then the default
+	for _, to := range []string{"large_path", "medium_path", "small_path", "extra_path"} {
+		if err := builder.CreateEdge("size", to); err != nil {
+			t.Fatalf("CreateEdge(size, %s) error = %v", to, err)
+		}
+	}
+
+	labels := make(map[string]string)
+	for _, edge := range builder.GetWorkflow().Edges {
+		labels[edge.ToNodeID] = builder.GetEdgeLabel(edge)
+	}
+	want := map[string]string{
+		"large_path":  "large",
+		"medium_path": "medium",
+		"small_path":  workflow.SwitchDefaultCase,
+		"extra_path":  "",
+	}
+	for to, label := range want {
+		if labels[to] != label {
+			t.Errorf("edge to %s labeled %q, want %q", to, labels[to], label)
+		}
+	}
+}
+
+func TestCreateConditionalEdgeFromSwitch(t *testing.T) {
+	builder := newSwitchTestBuilder(t)
+
+	if err := builder.CreateConditionalEdge("size", "medium_path", "medium"); err != nil {
+		t.Fatalf("CreateConditionalEdge(medium) error = %v", err)
+	}
+	if err := builder.CreateConditionalEdge("size", "small_path", workflow.SwitchDefaultCase); err != nil {
+		t.Fatalf("CreateConditionalEdge(default) error = %v", err)
+	}
+	if err := builder.CreateConditionalEdge("size", "large_path", "tiny"); err == nil {
+		t.Error("expected an error for an unknown case")
+	}
+	if err := builder.CreateConditionalEdge("size", "large_path", "medium"); err == nil {
+		t.Error("expected an error for a case that already has an edge")
+	}
+
+	// The first unconnected case is still offered to CreateEdge
+	if got := builder.nextSwitchCase("size"); got != "large" {
+		t.Errorf("nextSwitchCase() = %q, want large", got)
+	}
+}
+
+func TestSwitchCasesPropertyField(t *testing.T) {
+	cases := []workflow.SwitchCase{
+		{Label: "large", Condition: "total > 100"},
+		{Label: "tagged", Condition: `tag == "a:b"`},
+	}
+
+	formatted := formatSwitchCases(cases)
+	if formatted != `large: total > 100; tagged: tag == "a:b"` {
+		t.Errorf("formatSwitchCases() = %q", formatted)
+	}
+	parsed, err := parseSwitchCases(formatted)
+	if err != nil {
+		t.Fatalf("parseSwitchCases() error = %v", err)
+	}
+	if len(parsed) != 2 || parsed[0] != cases[0] || parsed[1] != cases[1] {
+		t.Errorf("parseSwitchCases() = %+v, want %+v", parsed, cases)
+	}
+
+	for _, invalid := range []string{"no colon", "a: x > 1; a: x > 2", "default: true", "a: x >"} {
+		if err := validateCasesField(invalid); err == nil {
+			t.Errorf("validateCasesField(%q) should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+func newTryCatchTestBuilder(t *testing.T) *WorkflowBuilder {
+	t.Helper()
+	wf, err := workflow.NewWorkflow("test", "test workflow")
+	if err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+
+	builder.AddNodeToCanvas(&workflow.StartNode{ID: "start"})
+	builder.AddNodeToCanvas(&workflow.TryNode{ID: "guard", Body: []string{"fetch"}})
+	builder.AddNodeToCanvas(&workflow.PassthroughNode{ID: "fetch"})
+	builder.AddNodeToCanvas(&workflow.CatchNode{ID: "handle", ErrorVariable: "failure"})
+	builder.AddNodeToCanvas(&workflow.PassthroughNode{ID: "cleanup"})
+	return builder
+}
+
+func TestCreateEdgeIntoCatchIsErrorEdge(t *testing.T) {
+	builder := newTryCatchTestBuilder(t)
+
+	if err := builder.CreateEdge("guard", "handle"); err != nil {
+		t.Fatalf("CreateEdge(guard, handle) error = %v", err)
+	}
+	if err := builder.CreateEdge("guard", "cleanup"); err != nil {
+		t.Fatalf("CreateEdge(guard, cleanup) error = %v", err)
+	}
+
+	for _, edge := range builder.GetWorkflow().Edges {
+		wantError := edge.ToNodeID == "handle"
+		if edge.OnError != wantError {
+			t.Errorf("edge to %s OnError = %v, want %v", edge.ToNodeID, edge.OnError, wantError)
+		}
+	}
+}
+
+func TestCreateErrorEdge(t *testing.T) {
+	builder := newTryCatchTestBuilder(t)
+
+	if err := builder.CreateErrorEdge("fetch", "cleanup"); err != nil {
+		t.Fatalf("CreateErrorEdge(fetch, cleanup) error = %v", err)
+	}
+	if err := builder.CreateErrorEdge("start", "cleanup"); err == nil {
+		t.Error("expected an error for an error edge from the start node")
+	}
+	if err := builder.CreateErrorEdge("fetch", "missing"); err == nil {
+		t.Error("expected an error for an unknown target node")
+	}
+
+	edges := builder.GetWorkflow().Edges
+	if len(edges) != 1 || !edges[0].OnError {
+		t.Fatalf("edges = %+v, want one error edge", edges)
+	}
+	if got := builder.GetEdgeLabel(edges[0]); got != "on error" {
+		t.Errorf("GetEdgeLabel() = %q, want %q", got, "on error")
+	}
+	if got := builder.GetEdgeStyle(edges[0]); got != "dashed" {
+		t.Errorf("GetEdgeStyle() = %q, want dashed", got)
+	}
+}
+
+func TestTryNodeBodyPropertyField(t *testing.T) {
+	if got := parseNodeList(" fetch, ,store "); len(got) != 2 || got[0] != "fetch" || got[1] != "store" {
+		t.Errorf("parseNodeList() = %v, want [fetch store]", got)
+	}
+}
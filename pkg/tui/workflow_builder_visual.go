@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// Visual mode builds a multi-selection for bulk operations. It starts at the
+// current node; movement keys extend the selection to the nearest node in
+// that direction, and the operations act on every selected node.
+
+// EnterVisualMode starts a multi-selection at the current node
+func (b *WorkflowBuilder) EnterVisualMode() error {
+	if b.selectedNodeID == "" {
+		return fmt.Errorf("no node selected")
+	}
+	b.ClearNodeSelection()
+	b.markNode(b.selectedNodeID)
+	b.mode = "visual"
+	b.updateKeyStates()
+	return nil
+}
+
+// ExitVisualMode returns to normal mode and clears the multi-selection
+func (b *WorkflowBuilder) ExitVisualMode() {
+	b.ClearNodeSelection()
+	b.mode = "normal"
+	b.updateKeyStates()
+}
+
+// ExtendSelection moves the current node to the nearest node in a direction
+// ("left", "right", "up" or "down") and adds it to the multi-selection.
+// Nothing happens if there is no node that way.
+func (b *WorkflowBuilder) ExtendSelection(direction string) error {
+	target, err := b.nearestNode(direction)
+	if err != nil || target == "" {
+		return err
+	}
+	b.extendSelectionTo(target)
+	return nil
+}
+
+// extendSelectionTo makes nodeID the current node and marks it
+func (b *WorkflowBuilder) extendSelectionTo(nodeID string) {
+	b.selectedNodeID = nodeID
+	b.canvas.selectedID = nodeID
+	b.markNode(nodeID)
+}
+
+// markNode adds a node to the multi-selection
+func (b *WorkflowBuilder) markNode(nodeID string) {
+	b.markedNodeIDs[nodeID] = true
+	if cNode, exists := b.canvas.nodes[nodeID]; exists {
+		cNode.selected = true
+	}
+}
+
+// nearestNode finds the node closest to the current one in a direction,
+// comparing node centers. Distance across the direction counts double so
+// nodes in line are preferred over diagonal ones.
+func (b *WorkflowBuilder) nearestNode(direction string) (string, error) {
+	from, exists := b.canvas.nodes[b.selectedNodeID]
+	if !exists {
+		return "", fmt.Errorf("no node selected")
+	}
+	fromX := from.position.X + from.width/2
+	fromY := from.position.Y + from.height/2
+
+	best, bestScore := "", 0
+	for nodeID, cNode := range b.canvas.nodes {
+		if nodeID == b.selectedNodeID {
+			continue
+		}
+		dx := cNode.position.X + cNode.width/2 - fromX
+		dy := cNode.position.Y + cNode.height/2 - fromY
+
+		var along, across int
+		switch direction {
+		case "left":
+			along, across = -dx, dy
+		case "right":
+			along, across = dx, dy
+		case "up":
+			along, across = -dy, dx
+		case "down":
+			along, across = dy, dx
+		default:
+			return "", fmt.Errorf("unknown direction: %s", direction)
+		}
+		if along <= 0 {
+			continue
+		}
+
+		score := along + 2*max(across, -across)
+		if best == "" || score < bestScore || (score == bestScore && nodeID < best) {
+			best, bestScore = nodeID, score
+		}
+	}
+	return best, nil
+}
+
+// DeleteSelectedNodes removes every selected node and its edges as a single
+// undoable change
+func (b *WorkflowBuilder) DeleteSelectedNodes() error {
+	ids := b.GetSelectedNodeIDs()
+	if len(ids) == 0 {
+		return fmt.Errorf("no node selected")
+	}
+
+	canvasPositions := b.getCanvasPositions()
+	if err := b.undoStack.Push(b.workflow, canvasPositions); err != nil {
+		return fmt.Errorf("failed to save undo snapshot: %w", err)
+	}
+
+	deleted := make(map[string]bool, len(ids))
+	for _, nodeID := range ids {
+		deleted[nodeID] = true
+		if err := b.canvas.RemoveNode(nodeID); err != nil {
+			return fmt.Errorf("failed to remove node from canvas: %w", err)
+		}
+	}
+
+	newNodes := make([]workflow.Node, 0, len(b.workflow.Nodes)-len(ids))
+	for _, node := range b.workflow.Nodes {
+		if !deleted[node.GetID()] {
+			newNodes = append(newNodes, node)
+		}
+	}
+	b.workflow.Nodes = newNodes
+
+	newEdges := make([]*workflow.Edge, 0, len(b.workflow.Edges))
+	for _, edge := range b.workflow.Edges {
+		if !deleted[edge.FromNodeID] && !deleted[edge.ToNodeID] {
+			newEdges = append(newEdges, edge)
+		}
+	}
+	b.workflow.Edges = newEdges
+
+	b.selectedNodeID = ""
+	b.markedNodeIDs = make(map[string]bool)
+	b.modified = true
+	b.validateWorkflow()
+	return nil
+}
+
+// MoveSelectedNodes shifts every selected node by dx, dy as a single
+// undoable change; a run of moves of the same selection is undone in one
+// step. The move is refused if any node would leave the canvas.
+func (b *WorkflowBuilder) MoveSelectedNodes(dx, dy int) error {
+	nodes := b.selectedCanvasNodes()
+	if len(nodes) == 0 {
+		return fmt.Errorf("no node selected")
+	}
+
+	positions := make(map[string]Position, len(nodes))
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.node.GetID())
+		pos := Position{X: n.position.X + dx, Y: n.position.Y + dy}
+		if pos.X < 0 || pos.Y < 0 {
+			return fmt.Errorf("cannot move nodes past the canvas edge")
+		}
+		positions[n.node.GetID()] = pos
+	}
+	sort.Strings(ids)
+	return b.applyNodePositionsCoalesced("move:"+strings.Join(ids, ","), positions)
+}
+
+// handleVisualMode processes keyboard shortcuts in visual mode
+func (b *WorkflowBuilder) handleVisualMode(key string) error {
+	switch key {
+	// Extend the selection
+	case "h":
+		return b.ExtendSelection("left")
+	case "j":
+		return b.ExtendSelection("down")
+	case "k":
+		return b.ExtendSelection("up")
+	case "l":
+		return b.ExtendSelection("right")
+	case "Tab", "Shift+Tab":
+		if len(b.workflow.Nodes) == 0 {
+			return nil
+		}
+		idx := 0
+		for i, node := range b.workflow.Nodes {
+			if node.GetID() == b.selectedNodeID {
+				idx = i
+				break
+			}
+		}
+		step := 1
+		if key == "Shift+Tab" {
+			step = len(b.workflow.Nodes) - 1
+		}
+		b.extendSelectionTo(b.workflow.Nodes[(idx+step)%len(b.workflow.Nodes)].GetID())
+		return nil
+
+	// Bulk move
+	case "Left":
+		return b.MoveSelectedNodes(-1, 0)
+	case "Down":
+		return b.MoveSelectedNodes(0, 1)
+	case "Up":
+		return b.MoveSelectedNodes(0, -1)
+	case "Right":
+		return b.MoveSelectedNodes(1, 0)
+
+	// Alignment
+	case "L":
+		return b.AlignSelectedNodes(AlignLeft)
+	case "T":
+		return b.AlignSelectedNodes(AlignTop)
+	case "C":
+		return b.AlignSelectedNodes(AlignCenter)
+	case "H":
+		return b.DistributeSelectedNodes(DistributeHorizontal)
+	case "J":
+		return b.DistributeSelectedNodes(DistributeVertical)
+
+	// Operations that end visual mode
+	case "d", "x":
+		if err := b.DeleteSelectedNodes(); err != nil {
+			return err
+		}
+		b.ExitVisualMode()
+		return nil
+	case "y":
+		if err := b.YankSelectedNodes(); err != nil {
+			return err
+		}
+		b.ExitVisualMode()
+		return nil
+	case "V":
+		b.ExitVisualMode()
+		return nil
+
+	default:
+		return fmt.Errorf("unrecognized key in visual mode: %s", key)
+	}
+}
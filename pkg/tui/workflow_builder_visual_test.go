@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// newVisualTestBuilder creates a builder with transform nodes at the given
+// positions, the first one selected
+func newVisualTestBuilder(t *testing.T, positions ...Position) *WorkflowBuilder {
+	t.Helper()
+
+	wf, _ := workflow.NewWorkflow("test", "test workflow")
+	builder, err := NewWorkflowBuilder(wf)
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+	for _, pos := range positions {
+		if err := builder.AddNodeAtPosition("Transform", pos); err != nil {
+			t.Fatalf("Failed to add node: %v", err)
+		}
+	}
+	if err := builder.SelectNode(wf.Nodes[0].GetID()); err != nil {
+		t.Fatalf("Failed to select node: %v", err)
+	}
+	return builder
+}
+
+// pressBuilderKeys sends keys to the builder, failing on the first error
+func pressBuilderKeys(t *testing.T, builder *WorkflowBuilder, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if err := builder.HandleKey(key); err != nil {
+			t.Fatalf("HandleKey(%q) returned error: %v", key, err)
+		}
+	}
+}
+
+func TestWorkflowBuilder_VisualModeSelection(t *testing.T) {
+	// transform-0 top left, transform-1 to its right, transform-2 below it
+	builder := newVisualTestBuilder(t, Position{X: 5, Y: 2}, Position{X: 40, Y: 3}, Position{X: 6, Y: 20})
+
+	pressBuilderKeys(t, builder, "V")
+	if builder.Mode() != "visual" {
+		t.Fatalf("Mode = %q, want visual", builder.Mode())
+	}
+
+	// No node above: the selection stays put
+	pressBuilderKeys(t, builder, "k", "l", "h", "j")
+	got := builder.GetSelectedNodeIDs()
+	if len(got) != 3 || builder.GetSelectedNodeID() != "transform-2" {
+		t.Errorf("selection = %v, current = %s", got, builder.GetSelectedNodeID())
+	}
+	for _, id := range got {
+		if !builder.canvas.nodes[id].selected {
+			t.Errorf("node %s not highlighted", id)
+		}
+	}
+
+	// Escape leaves visual mode and drops the selection
+	pressBuilderKeys(t, builder, "Escape")
+	if builder.Mode() != "normal" || len(builder.GetSelectedNodeIDs()) != 1 {
+		t.Errorf("after Escape: mode %q, selection %v", builder.Mode(), builder.GetSelectedNodeIDs())
+	}
+
+	// Visual mode needs a starting node
+	builder.selectedNodeID = ""
+	if err := builder.HandleKey("V"); err == nil {
+		t.Error("expected error entering visual mode without a selected node")
+	}
+}
+
+func TestWorkflowBuilder_VisualModeBulkOperations(t *testing.T) {
+	builder := newVisualTestBuilder(t, Position{X: 5, Y: 2}, Position{X: 5, Y: 10}, Position{X: 5, Y: 18})
+	for _, edge := range [][2]string{{"transform-0", "transform-1"}, {"transform-1", "transform-2"}} {
+		if err := builder.CreateEdge(edge[0], edge[1]); err != nil {
+			t.Fatalf("CreateEdge failed: %v", err)
+		}
+	}
+
+	// Move the first two nodes together
+	pressBuilderKeys(t, builder, "V", "j")
+	undoSize := builder.undoStack.Size()
+	pressBuilderKeys(t, builder, "Right", "Right", "Down")
+	got := nodePositions(builder)
+	want := []Position{{X: 7, Y: 3}, {X: 7, Y: 11}, {X: 5, Y: 18}}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("node %d: position = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if builder.undoStack.Size() != undoSize+1 {
+		t.Errorf("Expected consecutive moves to share one undo entry, got %d", builder.undoStack.Size()-undoSize)
+	}
+	if err := builder.HandleKey("Up"); err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+	if err := builder.MoveSelectedNodes(-10, 0); err == nil {
+		t.Error("expected error moving nodes past the canvas edge")
+	}
+
+	// Alignment acts on the visual selection
+	pressBuilderKeys(t, builder, "T")
+	if a, b := builder.canvas.nodes["transform-0"].position, builder.canvas.nodes["transform-1"].position; a.Y != b.Y {
+		t.Errorf("align top: %+v and %+v", a, b)
+	}
+
+	// Yank copies the edge between the selected nodes, not the one leaving them
+	pressBuilderKeys(t, builder, "y")
+	if builder.Mode() != "normal" || builder.ClipboardSize() != 2 || len(builder.clipboard.edges) != 1 {
+		t.Fatalf("after yank: mode %q, %d nodes, %d edges", builder.Mode(), builder.ClipboardSize(), len(builder.clipboard.edges))
+	}
+
+	// Paste twice: fresh IDs, the internal edge, offset positions
+	pressBuilderKeys(t, builder, "p", "p")
+	wf := builder.GetWorkflow()
+	if len(wf.Nodes) != 7 || len(wf.Edges) != 4 {
+		t.Fatalf("after paste: %d nodes, %d edges", len(wf.Nodes), len(wf.Edges))
+	}
+	for _, id := range []string{"transform-0-copy", "transform-1-copy", "transform-0-copy-2", "transform-1-copy-2"} {
+		if _, exists := builder.canvas.nodes[id]; !exists {
+			t.Errorf("pasted node %s not on canvas", id)
+		}
+	}
+	first := builder.canvas.nodes["transform-0-copy"].position
+	second := builder.canvas.nodes["transform-0-copy-2"].position
+	if second.X-first.X != pasteOffset.X || second.Y-first.Y != pasteOffset.Y {
+		t.Errorf("pastes at %+v and %+v, want offset %+v", first, second, pasteOffset)
+	}
+	last := wf.Edges[len(wf.Edges)-1]
+	if last.FromNodeID != "transform-0-copy-2" || last.ToNodeID != "transform-1-copy-2" || last.ID == "" {
+		t.Errorf("pasted edge = %+v", last)
+	}
+	if got := builder.GetSelectedNodeIDs(); len(got) != 2 || got[0] != "transform-0-copy-2" {
+		t.Errorf("selection after paste = %v", got)
+	}
+
+	// Bulk delete removes the pasted pair and its edge as one undo entry
+	undoSize = builder.undoStack.Size()
+	pressBuilderKeys(t, builder, "V", "Tab", "d")
+	if len(wf.Nodes) != 5 || len(wf.Edges) != 3 || builder.undoStack.Size() != undoSize+1 {
+		t.Errorf("after delete: %d nodes, %d edges, %d undo entries", len(wf.Nodes), len(wf.Edges), builder.undoStack.Size()-undoSize)
+	}
+	if builder.Mode() != "normal" || builder.GetSelectedNodeID() != "" {
+		t.Errorf("after delete: mode %q, current %q", builder.Mode(), builder.GetSelectedNodeID())
+	}
+}
@@ -20,6 +20,7 @@ type WorkflowExplorer struct {
 	searchMode        bool
 	searchQuery       string
 	currentModal      *components.Modal
+	listWindow        *components.ListWindow
 
 	// Callbacks
 	onSelectCallback            func(*workflow.Workflow)
@@ -38,6 +39,7 @@ func NewWorkflowExplorer(repo workflow.WorkflowRepository, screen *goterm.Screen
 		selectedIndex:     0,
 		searchMode:        false,
 		searchQuery:       "",
+		listWindow:        components.NewListWindow(),
 	}
 
 	// Load workflows from repository
@@ -438,8 +440,9 @@ func (e *WorkflowExplorer) Render() (string, error) {
 	// Clear screen
 	e.screen.Clear()
 
-	fg := goterm.ColorRGB(220, 220, 220)
-	bg := goterm.ColorDefault()
+	theme := CurrentTheme()
+	fg := theme.Text
+	bg := theme.Background
 
 	// Draw title bar
 	titleText := "Workflow Explorer"
@@ -460,7 +463,7 @@ func (e *WorkflowExplorer) Render() (string, error) {
 		for i, ch := range helpText {
 			x := helpStartX + i
 			if x < width {
-				e.screen.SetCell(x, 0, goterm.NewCell(ch, goterm.ColorRGB(150, 150, 150), bg, goterm.StyleNone))
+				e.screen.SetCell(x, 0, goterm.NewCell(ch, theme.Muted, bg, goterm.StyleNone))
 			}
 		}
 	} else if width >= 60 {
@@ -469,7 +472,7 @@ func (e *WorkflowExplorer) Render() (string, error) {
 		for i, ch := range helpText {
 			x := helpStartX + i
 			if x < width {
-				e.screen.SetCell(x, 0, goterm.NewCell(ch, goterm.ColorRGB(150, 150, 150), bg, goterm.StyleNone))
+				e.screen.SetCell(x, 0, goterm.NewCell(ch, theme.Muted, bg, goterm.StyleNone))
 			}
 		}
 	}
@@ -483,7 +486,7 @@ func (e *WorkflowExplorer) Render() (string, error) {
 			if i >= width {
 				break
 			}
-			e.screen.SetCell(i, contentY, goterm.NewCell(ch, goterm.ColorRGB(255, 255, 0), goterm.ColorRGB(40, 40, 40), goterm.StyleNone))
+			e.screen.SetCell(i, contentY, goterm.NewCell(ch, theme.InputFg, theme.InputBg, goterm.StyleNone))
 		}
 		contentY++
 	} else if e.searchQuery != "" {
@@ -493,7 +496,7 @@ func (e *WorkflowExplorer) Render() (string, error) {
 			if i >= width {
 				break
 			}
-			e.screen.SetCell(i, contentY, goterm.NewCell(ch, goterm.ColorRGB(200, 200, 100), bg, goterm.StyleNone))
+			e.screen.SetCell(i, contentY, goterm.NewCell(ch, theme.FilterFg, bg, goterm.StyleNone))
 		}
 		contentY++
 	}
@@ -501,6 +504,7 @@ func (e *WorkflowExplorer) Render() (string, error) {
 	contentY++ // Add spacing
 
 	// Draw workflow list or empty state
+	var visibleStart, visibleEnd int
 	if len(e.filteredWorkflows) == 0 {
 		emptyMsg := "No workflows found"
 		helpMsg := "Press 'n' to create a new workflow"
@@ -518,7 +522,7 @@ func (e *WorkflowExplorer) Render() (string, error) {
 			if emptyX+i >= width {
 				break
 			}
-			e.screen.SetCell(emptyX+i, emptyY, goterm.NewCell(ch, goterm.ColorRGB(150, 150, 150), bg, goterm.StyleNone))
+			e.screen.SetCell(emptyX+i, emptyY, goterm.NewCell(ch, theme.Muted, bg, goterm.StyleNone))
 		}
 
 		helpX := (width - len(helpMsg)) / 2
@@ -529,15 +533,19 @@ func (e *WorkflowExplorer) Render() (string, error) {
 			if helpX+i >= width {
 				break
 			}
-			e.screen.SetCell(helpX+i, emptyY+1, goterm.NewCell(ch, goterm.ColorRGB(100, 100, 100), bg, goterm.StyleNone))
+			e.screen.SetCell(helpX+i, emptyY+1, goterm.NewCell(ch, theme.Faint, bg, goterm.StyleNone))
 		}
 	} else {
-		// Draw workflow list
+		// Draw the visible window of the workflow list, leaving room for the status bar
+		rows := height - 2 - contentY
+		if rows < 1 {
+			rows = 1
+		}
+		visibleStart, visibleEnd = e.listWindow.Visible(e.selectedIndex, len(e.filteredWorkflows), rows)
+
 		y := contentY
-		for i, wf := range e.filteredWorkflows {
-			if y >= height-2 {
-				break // Leave room for status bar
-			}
+		for i := visibleStart; i < visibleEnd; i++ {
+			wf := e.filteredWorkflows[i]
 
 			isSelected := i == e.selectedIndex
 			itemFg := fg
@@ -545,8 +553,8 @@ func (e *WorkflowExplorer) Render() (string, error) {
 			style := goterm.StyleNone
 
 			if isSelected {
-				itemFg = goterm.ColorRGB(0, 0, 0)
-				itemBg = goterm.ColorRGB(100, 200, 255)
+				itemFg = theme.SelectedFg
+				itemBg = theme.SelectedBg
 				style = goterm.StyleBold
 			}
 
@@ -598,16 +606,15 @@ func (e *WorkflowExplorer) Render() (string, error) {
 	if len(e.filteredWorkflows) != 1 {
 		statusText += "s"
 	}
-
-	for i, ch := range statusText {
-		if i >= width {
-			break
-		}
-		e.screen.SetCell(i, statusY, goterm.NewCell(ch, goterm.ColorRGB(150, 150, 150), bg, goterm.StyleNone))
+	if visibleEnd-visibleStart < len(e.filteredWorkflows) {
+		statusText += " (" + components.RangeIndicator(visibleStart, visibleEnd, len(e.filteredWorkflows), len(e.workflows)) + ")"
 	}
 
+	drawStatusBar(e.screen, statusY, width, statusText, theme.Muted, goterm.StyleNone)
+
 	// Render modal if open
 	if e.currentModal != nil && e.currentModal.IsVisible() {
+		e.currentModal.SetStyle(theme.ModalStyle())
 		e.currentModal.Render(e.screen)
 	}
 
@@ -0,0 +1,215 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// NormalizeUserPath converts a path typed by a user into a clean path using
+// the native separator of the current platform.
+//
+// Normalization steps:
+//   - Surrounding whitespace and matching quotes are removed
+//   - A leading "~" is expanded to the user's home directory
+//   - $VAR and ${VAR} references (and %VAR% on Windows) are expanded
+//   - Forward slashes are converted to the platform separator and the path is cleaned
+//
+// Unlike PathValidator.Validate, absolute paths are allowed: this function is
+// intended for configuration input (server commands, directories) rather than
+// untrusted paths that must stay inside a base directory.
+//
+// Returns a *ValidationError if the input is empty, contains a null byte,
+// references an undefined environment variable, or uses "~user" syntax.
+func NormalizeUserPath(input string) (string, error) {
+	path := strings.TrimSpace(input)
+	path = trimMatchingQuotes(path)
+
+	if path == "" {
+		return "", newUserPathError(input, "path cannot be empty")
+	}
+
+	if strings.ContainsRune(path, 0) {
+		return "", newUserPathError(input, "path contains null byte")
+	}
+
+	expanded, err := expandHome(path)
+	if err != nil {
+		return "", newUserPathError(input, err.Error())
+	}
+
+	expanded, err = expandEnv(expanded)
+	if err != nil {
+		return "", newUserPathError(input, err.Error())
+	}
+
+	// FromSlash makes forward slashes work on Windows; on Unix a backslash is
+	// a legal filename character and is left untouched.
+	expanded = filepath.Clean(filepath.FromSlash(expanded))
+
+	if len(expanded) > 1024 {
+		return "", newUserPathError(input, "path exceeds maximum length of 1024 characters")
+	}
+
+	return expanded, nil
+}
+
+// ValidateCommandInput normalizes a user-entered executable for a stdio server.
+//
+// Bare command names (e.g. "npx", "mcp-server-filesystem") are returned
+// unchanged because they are resolved through PATH when the server starts.
+// Anything that looks like a path (contains a separator or starts with "~" or
+// "$") is normalized and must refer to an existing regular file.
+func ValidateCommandInput(input string) (string, error) {
+	trimmed := trimMatchingQuotes(strings.TrimSpace(input))
+	if trimmed == "" {
+		return "", newUserPathError(input, "command cannot be empty")
+	}
+
+	if !looksLikePath(trimmed) {
+		if strings.ContainsAny(trimmed, " \t") {
+			return "", newUserPathError(input, "command name cannot contain spaces; pass arguments separately")
+		}
+		return trimmed, nil
+	}
+
+	path, err := NormalizeUserPath(trimmed)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", newUserPathError(input, fmt.Sprintf("command not found: %s", DisplayPath(path)))
+		}
+		return "", newUserPathError(input, fmt.Sprintf("cannot access command: %v", err))
+	}
+	if info.IsDir() {
+		return "", newUserPathError(input, fmt.Sprintf("command is a directory: %s", DisplayPath(path)))
+	}
+
+	return path, nil
+}
+
+// DisplayPath formats a path for display: the user's home directory is
+// abbreviated to "~" and the platform separator is used throughout.
+func DisplayPath(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	display := filepath.FromSlash(path)
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return display
+	}
+
+	home = filepath.Clean(home)
+	if display == home {
+		return "~"
+	}
+	if rel, ok := strings.CutPrefix(display, home+string(filepath.Separator)); ok {
+		return "~" + string(filepath.Separator) + rel
+	}
+	return display
+}
+
+// looksLikePath reports whether a command string refers to a file path
+// rather than a bare executable name
+func looksLikePath(s string) bool {
+	return strings.ContainsAny(s, `/\`) ||
+		strings.HasPrefix(s, "~") ||
+		strings.HasPrefix(s, "$") ||
+		strings.HasPrefix(s, "%") ||
+		filepath.VolumeName(s) != ""
+}
+
+// expandHome expands a leading "~" to the current user's home directory
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	if len(path) > 1 && path[1] != '/' && path[1] != '\\' {
+		return "", fmt.Errorf("~user expansion is not supported: %s", path)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %v", err)
+	}
+
+	return home + path[1:], nil
+}
+
+// expandEnv expands $VAR, ${VAR} and (on Windows) %VAR% references,
+// failing on variables that are not set so typos are reported clearly
+func expandEnv(path string) (string, error) {
+	var missing []string
+	expanded := os.Expand(path, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+
+	if runtime.GOOS == "windows" {
+		expanded = expandWindowsEnv(expanded, &missing)
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("undefined environment variable: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// expandWindowsEnv expands %VAR% references
+func expandWindowsEnv(path string, missing *[]string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '%')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(path[start+1:], '%')
+		if end == -1 {
+			break
+		}
+		name := path[start+1 : start+1+end]
+		b.WriteString(path[:start])
+		if value, ok := os.LookupEnv(name); ok && name != "" {
+			b.WriteString(value)
+		} else {
+			*missing = append(*missing, name)
+		}
+		path = path[start+end+2:]
+	}
+	b.WriteString(path)
+	return b.String()
+}
+
+// trimMatchingQuotes removes a single pair of surrounding quotes, which users
+// commonly include when pasting paths containing spaces
+func trimMatchingQuotes(s string) string {
+	if len(s) >= 2 {
+		first, last := s[0], s[len(s)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
+
+// newUserPathError creates a ValidationError for user path input
+func newUserPathError(input, reason string) *ValidationError {
+	return &ValidationError{
+		UserPath:  input,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeUserPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GOFLOW_TEST_DIR", filepath.Join(home, "data"))
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"plain relative path", "templates", "templates", ""},
+		{"surrounding whitespace", "  templates  ", "templates", ""},
+		{"double quoted", `"my templates"`, "my templates", ""},
+		{"single quoted", `'my templates'`, "my templates", ""},
+		{"tilde alone", "~", home, ""},
+		{"tilde prefix", "~/workflows", filepath.Join(home, "workflows"), ""},
+		{"env var", "$GOFLOW_TEST_DIR/x", filepath.Join(home, "data", "x"), ""},
+		{"braced env var", "${GOFLOW_TEST_DIR}", filepath.Join(home, "data"), ""},
+		{"cleans dot segments", "a/./b/../c", filepath.Join("a", "c"), ""},
+		{"empty", "   ", "", "path cannot be empty"},
+		{"empty quotes", `""`, "", "path cannot be empty"},
+		{"null byte", "a\x00b", "", "null byte"},
+		{"undefined env var", "$GOFLOW_UNDEFINED_VAR/x", "", "undefined environment variable: GOFLOW_UNDEFINED_VAR"},
+		{"tilde user", "~bob/x", "", "~user expansion is not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeUserPath(tt.input)
+			if tt.wantErr != "" {
+				var ve *ValidationError
+				if !errors.As(err, &ve) {
+					t.Fatalf("NormalizeUserPath(%q) error = %v, want *ValidationError", tt.input, err)
+				}
+				if !strings.Contains(ve.Reason, tt.wantErr) {
+					t.Errorf("NormalizeUserPath(%q) reason = %q, want containing %q", tt.input, ve.Reason, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeUserPath(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeUserPath(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCommandInput(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	binDir := filepath.Join(home, "bin")
+	if err := os.Mkdir(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	serverPath := filepath.Join(binDir, "server")
+	if err := os.WriteFile(serverPath, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"bare command", "npx", "npx", ""},
+		{"bare command trimmed", "  mcp-server  ", "mcp-server", ""},
+		{"path with tilde", "~/bin/server", serverPath, ""},
+		{"absolute path", serverPath, serverPath, ""},
+		{"missing path", "~/bin/nope", "", "command not found"},
+		{"directory", "~/bin", "", "command is a directory"},
+		{"spaces in bare command", "npx -y server", "", "cannot contain spaces"},
+		{"empty", "", "", "command cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateCommandInput(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ValidateCommandInput(%q) error = %v, want containing %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateCommandInput(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateCommandInput(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"empty", "", ""},
+		{"home", home, "~"},
+		{"under home", filepath.Join(home, "a", "b"), "~" + string(filepath.Separator) + filepath.Join("a", "b")},
+		{"home prefix but different dir", home + "x", home + "x"},
+		{"outside home", filepath.Join(string(filepath.Separator), "opt", "tool"), filepath.Join(string(filepath.Separator), "opt", "tool")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DisplayPath(tt.path); got != tt.want {
+				t.Errorf("DisplayPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const delayApprovalWorkflowYAML = `
+version: "1.0.0"
+name: "delay-approval-test"
+variables:
+  - name: "release_at"
+    type: "string"
+    default: "2030-01-01T09:00:00Z"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "cooldown"
+    type: "delay"
+    duration: "5m"
+  - id: "window"
+    type: "delay"
+    until: "${release_at}"
+  - id: "review"
+    type: "approval"
+    message: "Release at ${release_at}?"
+    timeout: "1h"
+    default_action: "approve"
+    output: "decision"
+  - id: "end"
+    type: "end"
+    return: "${decision.by}"
+edges:
+  - from: "start"
+    to: "cooldown"
+  - from: "cooldown"
+    to: "window"
+  - from: "window"
+    to: "review"
+  - from: "review"
+    to: "end"
+`
+
+func TestDelayApproval_ParseAndRoundTrip(t *testing.T) {
+	wf, err := Parse([]byte(delayApprovalWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := ValidateWorkflow(wf); err != nil {
+		t.Fatalf("ValidateWorkflow() error = %v", err)
+	}
+
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() of serialized workflow error = %v", err)
+	}
+
+	for _, w := range []*Workflow{wf, again} {
+		if delay, ok := w.Nodes[1].(*DelayNode); !ok || delay.Duration != "5m" {
+			t.Errorf("delay node = %#v", w.Nodes[1])
+		}
+		if delay, ok := w.Nodes[2].(*DelayNode); !ok || delay.Until != "${release_at}" {
+			t.Errorf("delay node = %#v", w.Nodes[2])
+		}
+		want := ApprovalNode{ID: "review", Message: "Release at ${release_at}?", Timeout: "1h", DefaultAction: "approve", OutputVariable: "decision"}
+		if approval, ok := w.Nodes[3].(*ApprovalNode); !ok || *approval != want {
+			t.Errorf("approval node = %#v", w.Nodes[3])
+		}
+	}
+}
+
+func TestDelayApproval_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+		want string
+	}{
+		{name: "duration", node: &DelayNode{ID: "d", Duration: "30s"}},
+		{name: "templated duration", node: &DelayNode{ID: "d", Duration: "${wait}"}},
+		{name: "until", node: &DelayNode{ID: "d", Until: "2030-01-01T00:00:00Z"}},
+		{name: "no duration or until", node: &DelayNode{ID: "d"}, want: "exactly one of duration and until"},
+		{name: "duration and until", node: &DelayNode{ID: "d", Duration: "1s", Until: "2030-01-01T00:00:00Z"}, want: "exactly one of duration and until"},
+		{name: "invalid duration", node: &DelayNode{ID: "d", Duration: "soon"}, want: "invalid duration"},
+		{name: "negative duration", node: &DelayNode{ID: "d", Duration: "-1s"}, want: "must be positive"},
+		{name: "invalid until", node: &DelayNode{ID: "d", Until: "tomorrow"}, want: "invalid until timestamp"},
+		{name: "approval without timeout", node: &ApprovalNode{ID: "a", Message: "ok?"}},
+		{name: "approval with timeout", node: &ApprovalNode{ID: "a", Timeout: "10m", DefaultAction: ApprovalReject}},
+		{name: "invalid timeout", node: &ApprovalNode{ID: "a", Timeout: "later"}, want: "invalid duration"},
+		{name: "invalid default action", node: &ApprovalNode{ID: "a", Timeout: "1m", DefaultAction: "skip"}, want: "invalid default action"},
+		{name: "default action without timeout", node: &ApprovalNode{ID: "a", DefaultAction: ApprovalApprove}, want: "requires a timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.node.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelayApproval_UndefinedTemplateVariable(t *testing.T) {
+	wf, err := Parse([]byte(delayApprovalWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	wf.Nodes[3].(*ApprovalNode).Message = "Ship ${missing}?"
+
+	err = wf.Validate()
+	if err == nil || !strings.Contains(err.Error(), "node review: undefined variable: missing") {
+		t.Errorf("Validate() error = %v, want an undefined variable error", err)
+	}
+}
@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const batchWorkflowYAML = `
+version: "1.0.0"
+name: "batch-test"
+variables:
+  - name: "user_ids"
+    type: "array"
+  - name: "fields"
+    type: "string"
+servers:
+  - id: "api"
+    command: "api-server"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "fetch"
+    type: "batch_tool"
+    server: "api"
+    tool: "get_user"
+    collection: "user_ids"
+    item: "user_id"
+    parameters:
+      id: "${user_id}"
+      fields: "${fields}"
+    max_concurrency: 3
+    output: "users"
+  - id: "end"
+    type: "end"
+    return: "${users}"
+edges:
+  - from: "start"
+    to: "fetch"
+  - from: "fetch"
+    to: "end"
+`
+
+func TestBatchTool_Parse(t *testing.T) {
+	wf, err := Parse([]byte(batchWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	batch, ok := wf.Nodes[1].(*BatchToolNode)
+	if !ok {
+		t.Fatalf("node = %#v, want a batch tool node", wf.Nodes[1])
+	}
+	if batch.ItemVariable != "user_id" || batch.Concurrency() != 3 || batch.Parameters["id"] != "${user_id}" {
+		t.Errorf("batch node = %#v", batch)
+	}
+	if (&BatchToolNode{}).Concurrency() != DefaultBatchConcurrency {
+		t.Error("unset max_concurrency should use the default")
+	}
+
+	reads, _, writes := inferNodeDataFlow(batch)
+	if strings.Join(reads, ",") != "user_ids,fields" || strings.Join(writes, ",") != "users" {
+		t.Errorf("reads = %v, writes = %v; the item is bound per call", reads, writes)
+	}
+}
+
+func TestBatchTool_Validation(t *testing.T) {
+	valid := BatchToolNode{ID: "b", ServerID: "api", ToolName: "t", Collection: "xs", ItemVariable: "x", OutputVariable: "out"}
+	tests := []struct {
+		name   string
+		modify func(*BatchToolNode)
+		want   string
+	}{
+		{name: "valid", modify: func(*BatchToolNode) {}},
+		{name: "error variable", modify: func(n *BatchToolNode) { n.ErrorVariable = "errs" }},
+		{name: "no tool", modify: func(n *BatchToolNode) { n.ToolName = "" }, want: "empty tool name"},
+		{name: "no collection", modify: func(n *BatchToolNode) { n.Collection = "" }, want: "empty collection"},
+		{name: "invalid item", modify: func(n *BatchToolNode) { n.ItemVariable = "1x" }, want: "invalid item variable"},
+		{name: "no output", modify: func(n *BatchToolNode) { n.OutputVariable = "" }, want: "empty output variable"},
+		{name: "error variable is output", modify: func(n *BatchToolNode) { n.ErrorVariable = "out" }, want: "conflicts with output variable"},
+		{name: "negative concurrency", modify: func(n *BatchToolNode) { n.MaxConcurrency = -1 }, want: "cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := valid
+			tt.modify(&node)
+			err := node.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
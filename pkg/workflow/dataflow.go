@@ -0,0 +1,479 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NodeContract declares the variables a node reads and writes. Data-flow
+// validation combines it with what the node's configuration already shows
+// (templates, conditions, output variables), so a contract only needs to
+// name what the configuration does not, such as the side effects of a tool.
+type NodeContract struct {
+	Reads  []string `json:"reads,omitempty" yaml:"reads,omitempty"`
+	Writes []string `json:"writes,omitempty" yaml:"writes,omitempty"`
+}
+
+// DataFlowIssueKind classifies a data-flow finding
+type DataFlowIssueKind string
+
+// Data-flow issue kinds
+const (
+	// DataFlowUnwrittenRead is a read of a variable that is neither a
+	// workflow variable nor written by a node that runs earlier
+	DataFlowUnwrittenRead DataFlowIssueKind = "unwritten_read"
+	// DataFlowUnusedWrite is a write of a variable that no node reads
+	DataFlowUnusedWrite DataFlowIssueKind = "unused_write"
+)
+
+// DataFlowIssue is a single finding of the data-flow analysis
+type DataFlowIssue struct {
+	Kind     DataFlowIssueKind `json:"kind"`
+	NodeID   string            `json:"node_id"`
+	Variable string            `json:"variable"`
+	// Declared reports whether the read or write comes from the node's
+	// contract rather than its configuration
+	Declared bool   `json:"declared,omitempty"`
+	Message  string `json:"message"`
+}
+
+// SetContract declares the variables a node reads and writes. An empty
+// contract removes the node's contract.
+func (w *Workflow) SetContract(nodeID string, contract NodeContract) error {
+	found := false
+	for _, node := range w.Nodes {
+		if node.GetID() == nodeID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+
+	reads, err := contractNames(contract.Reads)
+	if err != nil {
+		return fmt.Errorf("reads: %w", err)
+	}
+	writes, err := contractNames(contract.Writes)
+	if err != nil {
+		return fmt.Errorf("writes: %w", err)
+	}
+
+	if len(reads) == 0 && len(writes) == 0 {
+		if _, exists := w.Metadata.Contracts[nodeID]; exists {
+			delete(w.Metadata.Contracts, nodeID)
+			w.Metadata.LastModified = time.Now()
+		}
+		return nil
+	}
+
+	if w.Metadata.Contracts == nil {
+		w.Metadata.Contracts = make(map[string]*NodeContract)
+	}
+	w.Metadata.Contracts[nodeID] = &NodeContract{Reads: reads, Writes: writes}
+	w.Metadata.LastModified = time.Now()
+	return nil
+}
+
+// Contract returns a node's declared contract, or nil if it has none
+func (w *Workflow) Contract(nodeID string) *NodeContract {
+	return w.Metadata.Contracts[nodeID]
+}
+
+// contractNames trims and checks the variable names of a contract
+func contractNames(names []string) ([]string, error) {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := ValidateVariableName(name); err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			cleaned = append(cleaned, name)
+		}
+	}
+	return cleaned, nil
+}
+
+// contractErrors checks that contracts belong to existing nodes and name
+// valid variables
+func (w *Workflow) contractErrors(nodeIDs map[string]bool) []string {
+	var errs []string
+	ids := make([]string, 0, len(w.Metadata.Contracts))
+	for id := range w.Metadata.Contracts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if !nodeIDs[id] {
+			errs = append(errs, fmt.Sprintf("contract references invalid node: %s", id))
+			continue
+		}
+		contract := w.Metadata.Contracts[id]
+		if contract == nil {
+			continue
+		}
+		for _, name := range append(append([]string{}, contract.Reads...), contract.Writes...) {
+			if err := ValidateVariableName(name); err != nil {
+				errs = append(errs, fmt.Sprintf("node %s: contract: %v", id, err))
+			}
+		}
+	}
+	return errs
+}
+
+// dataFlowNode is the analysis view of one node
+type dataFlowNode struct {
+	id string
+	// reads must be written before the node runs; uses only consume
+	// variables (edge and break conditions)
+	reads, uses, writes     []string
+	declaredReads, declared map[string]bool
+}
+
+// DataFlowIssues runs the data-flow analysis: every variable a node reads
+// must be a workflow variable or be written by a node that runs before it,
+// and every variable a node writes should be read by some node. Reads and
+// writes come from each node's configuration and its declared contract.
+// Issues are ordered by node, reads before writes.
+func (w *Workflow) DataFlowIssues() []DataFlowIssue {
+	nodes := w.dataFlowNodes()
+
+	// Containers run their bodies, so what a body writes is written by the
+	// time the nodes after its container run
+	bodies := make(map[string][]string)
+	for _, node := range w.Nodes {
+		if node != nil {
+			bodies[node.GetID()] = containedNodes(node)
+		}
+	}
+
+	predecessors := w.dataFlowPredecessors()
+	scopes := w.isolatedScopes()
+	consumed := make(map[string]bool)
+	for _, n := range nodes {
+		for _, name := range n.reads {
+			consumed[name] = true
+		}
+		for _, name := range n.uses {
+			consumed[name] = true
+		}
+	}
+	byID := make(map[string]*dataFlowNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.id] = n
+	}
+
+	var issues []DataFlowIssue
+	for _, n := range nodes {
+		available := w.availableVariables(n.id, predecessors, byID, bodies, scopes)
+		for _, name := range n.reads {
+			if available[name] {
+				continue
+			}
+			issues = append(issues, DataFlowIssue{
+				Kind:     DataFlowUnwrittenRead,
+				NodeID:   n.id,
+				Variable: name,
+				Declared: n.declaredReads[name],
+				Message:  fmt.Sprintf("node %s reads %s, which no upstream node writes", n.id, name),
+			})
+		}
+		for _, name := range n.writes {
+			if consumed[name] {
+				continue
+			}
+			issues = append(issues, DataFlowIssue{
+				Kind:     DataFlowUnusedWrite,
+				NodeID:   n.id,
+				Variable: name,
+				Declared: n.declared[name],
+				Message:  fmt.Sprintf("node %s writes %s, which no node reads", n.id, name),
+			})
+		}
+	}
+	return issues
+}
+
+// IsOrderingError reports whether the issue makes a workflow invalid: a read
+// of a variable that only nodes running later write, or a declared read that
+// nothing writes. Reads of variables nothing writes at all are reported as
+// undefined variables instead.
+func (i DataFlowIssue) IsOrderingError(known map[string]bool) bool {
+	return i.Kind == DataFlowUnwrittenRead && (i.Declared || known[i.Variable])
+}
+
+// dataFlowErrors returns the messages of the data-flow issues that make a
+// workflow invalid
+func (w *Workflow) dataFlowErrors() []string {
+	var errs []string
+	known := w.KnownVariables()
+	for _, issue := range w.DataFlowIssues() {
+		if issue.IsOrderingError(known) {
+			errs = append(errs, issue.Message)
+		}
+	}
+	return errs
+}
+
+// availableVariables returns the variables set before node id runs: the
+// workflow variables and everything written by the nodes that run earlier.
+// Of the writes inside an isolated scope, only promoted variables are
+// visible after it.
+func (w *Workflow) availableVariables(id string, predecessors map[string][]string, nodes map[string]*dataFlowNode, bodies map[string][]string, scopes map[string]map[string]bool) map[string]bool {
+	available := make(map[string]bool)
+	for _, variable := range w.Variables {
+		if variable != nil {
+			available[variable.Name] = true
+		}
+	}
+
+	// only, when set, limits the writes to the variables promoted out of
+	// the enclosing isolated scopes
+	var addWrites func(nodeID string, seen, only map[string]bool)
+	addWrites = func(nodeID string, seen, only map[string]bool) {
+		if seen[nodeID] {
+			return
+		}
+		seen[nodeID] = true
+		if n := nodes[nodeID]; n != nil {
+			for _, name := range n.writes {
+				if only == nil || only[name] {
+					available[name] = true
+				}
+			}
+		}
+		if promoted, isolated := scopes[nodeID]; isolated {
+			narrowed := make(map[string]bool, len(promoted))
+			for name := range promoted {
+				if only == nil || only[name] {
+					narrowed[name] = true
+				}
+			}
+			only = narrowed
+		}
+		for _, child := range bodies[nodeID] {
+			addWrites(child, seen, only)
+		}
+	}
+
+	visited := map[string]bool{id: true}
+	queue := append([]string{}, predecessors[id]...)
+	written := make(map[string]bool)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		addWrites(current, written, nil)
+		queue = append(queue, predecessors[current]...)
+	}
+	return available
+}
+
+// dataFlowPredecessors maps each node to the nodes that can run directly
+// before it: edge sources (including error edges), the container of a
+// body's first node, and the previous node of a body or branch
+func (w *Workflow) dataFlowPredecessors() map[string][]string {
+	predecessors := make(map[string][]string)
+	for _, edge := range w.Edges {
+		if edge != nil {
+			predecessors[edge.ToNodeID] = append(predecessors[edge.ToNodeID], edge.FromNodeID)
+		}
+	}
+
+	chain := func(containerID string, body []string) {
+		previous := containerID
+		for _, nodeID := range body {
+			predecessors[nodeID] = append(predecessors[nodeID], previous)
+			previous = nodeID
+		}
+	}
+	for _, node := range w.Nodes {
+		switch n := node.(type) {
+		case *ParallelNode:
+			for _, branch := range n.Branches {
+				chain(n.ID, branch)
+			}
+		case *LoopNode:
+			chain(n.ID, n.Body)
+		case *TryNode:
+			chain(n.ID, n.Body)
+		}
+	}
+	return predecessors
+}
+
+// containedNodes returns the body or branch nodes a container runs
+func containedNodes(node Node) []string {
+	switch n := node.(type) {
+	case *ParallelNode:
+		var nodes []string
+		for _, branch := range n.Branches {
+			nodes = append(nodes, branch...)
+		}
+		return nodes
+	case *LoopNode:
+		return n.Body
+	case *TryNode:
+		return n.Body
+	}
+	return nil
+}
+
+// dataFlowNodes collects the reads and writes of every node, in node order
+func (w *Workflow) dataFlowNodes() []*dataFlowNode {
+	edgeUses := make(map[string][]string)
+	for _, edge := range w.Edges {
+		if edge != nil && edge.Condition != "" {
+			edgeUses[edge.FromNodeID] = append(edgeUses[edge.FromNodeID], extractVariableReferences(edge.Condition)...)
+		}
+	}
+
+	nodes := make([]*dataFlowNode, 0, len(w.Nodes))
+	for _, node := range w.Nodes {
+		if node == nil {
+			continue
+		}
+		reads, uses, writes := inferNodeDataFlow(node)
+		n := &dataFlowNode{
+			id:            node.GetID(),
+			uses:          append(uses, edgeUses[node.GetID()]...),
+			declaredReads: make(map[string]bool),
+			declared:      make(map[string]bool),
+		}
+		n.reads = uniqueNames(reads, nil)
+		n.writes = uniqueNames(writes, nil)
+		if contract := w.Contract(n.id); contract != nil {
+			n.reads = uniqueNames(n.reads, n.declaredReads, contract.Reads...)
+			n.writes = uniqueNames(n.writes, n.declared, contract.Writes...)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// uniqueNames appends names to list by their base variable name, skipping
+// duplicates and anything that is not a variable name (template
+// expressions validation reports separately). Names not already in list
+// are recorded in added.
+func uniqueNames(list []string, added map[string]bool, names ...string) []string {
+	seen := make(map[string]bool, len(list))
+	var result []string
+	add := func(name string) bool {
+		base := extractBaseVariable(strings.TrimSpace(name))
+		if !validVariableNameRegex.MatchString(base) || seen[base] {
+			return false
+		}
+		seen[base] = true
+		result = append(result, base)
+		return true
+	}
+	for _, name := range list {
+		add(name)
+	}
+	for _, name := range names {
+		if add(name) && added != nil {
+			added[extractBaseVariable(strings.TrimSpace(name))] = true
+		}
+	}
+	return result
+}
+
+// inferNodeDataFlow returns the variables a node's configuration reads and
+// writes. Uses are read too, but only to tell whether a write is consumed:
+// a loop's break condition sees the variables its body writes.
+func inferNodeDataFlow(node Node) (reads, uses, writes []string) {
+	switch n := node.(type) {
+	case *MCPToolNode:
+		keys := make([]string, 0, len(n.Parameters))
+		for key := range n.Parameters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			reads = append(reads, extractTemplateVariables(n.Parameters[key])...)
+		}
+		if n.OutputVariable != "" {
+			writes = append(writes, n.OutputVariable)
+		}
+		contentTypes := make([]string, 0, len(n.ContentOutputs))
+		for contentType := range n.ContentOutputs {
+			contentTypes = append(contentTypes, contentType)
+		}
+		sort.Strings(contentTypes)
+		for _, contentType := range contentTypes {
+			writes = append(writes, n.ContentOutputs[contentType])
+		}
+		if n.StreamOutput != "" {
+			writes = append(writes, n.StreamOutput)
+		}
+	case *TransformNode:
+		reads = append(reads, templateOrNameReferences(n.InputVariable)...)
+		reads = append(reads, transformExpressionReferences(n)...)
+		if n.OutputVariable != "" {
+			writes = append(writes, n.OutputVariable)
+		}
+		if n.ItemErrorsOutput != "" {
+			writes = append(writes, n.ItemErrorsOutput)
+		}
+	case *ConditionNode:
+		reads = append(reads, extractVariableReferences(n.Condition)...)
+	case *SwitchNode:
+		for _, c := range n.Cases {
+			reads = append(reads, extractVariableReferences(c.Condition)...)
+		}
+	case *LoopNode:
+		reads = append(reads, templateOrNameReferences(n.Collection)...)
+		uses = append(uses, extractVariableReferences(n.BreakCondition)...)
+		if n.ItemVariable != "" {
+			writes = append(writes, n.ItemVariable)
+		}
+	case *CatchNode:
+		if n.ErrorVariable != "" {
+			writes = append(writes, n.ErrorVariable)
+		}
+	case *EndNode:
+		reads = append(reads, extractTemplateVariables(n.ReturnValue)...)
+	case *DelayNode:
+		reads = append(reads, extractTemplateVariables(n.Duration)...)
+		reads = append(reads, extractTemplateVariables(n.Until)...)
+	case *BatchToolNode:
+		reads = append(reads, templateOrNameReferences(n.Collection)...)
+		keys := make([]string, 0, len(n.Parameters))
+		for key := range n.Parameters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, name := range extractTemplateVariables(n.Parameters[key]) {
+				// The item is bound per call, not read from the workflow
+				if name != n.ItemVariable {
+					reads = append(reads, name)
+				}
+			}
+		}
+		writes = append(writes, n.OutputVariable)
+		if n.ErrorVariable != "" {
+			writes = append(writes, n.ErrorVariable)
+		}
+	case *ApprovalNode:
+		reads = append(reads, extractTemplateVariables(n.Message)...)
+		reads = append(reads, extractTemplateVariables(n.Timeout)...)
+		if n.OutputVariable != "" {
+			writes = append(writes, n.OutputVariable)
+		}
+	}
+	return reads, uses, writes
+}
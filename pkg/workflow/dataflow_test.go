@@ -0,0 +1,234 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const dataFlowWorkflowYAML = `
+version: "1.0.0"
+name: "data-flow-test"
+metadata:
+  contracts:
+    login:
+      writes: ["session", "audit_id"]
+    report:
+      reads: ["session"]
+variables:
+  - name: "user"
+    type: "string"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "login"
+    type: "mcp_tool"
+    server: "auth"
+    tool: "login"
+    parameters:
+      user: "${user}"
+    output: "login_result"
+  - id: "fetch"
+    type: "mcp_tool"
+    server: "auth"
+    tool: "orders"
+    parameters:
+      token: "${login_result.token}"
+    output: "orders"
+  - id: "report"
+    type: "transform"
+    input: "orders"
+    expression: "$.total"
+    output: "total"
+  - id: "end"
+    type: "end"
+    return: "${total}"
+servers:
+  - id: "auth"
+    command: "auth-server"
+edges:
+  - from: "start"
+    to: "login"
+  - from: "login"
+    to: "fetch"
+  - from: "fetch"
+    to: "report"
+  - from: "report"
+    to: "end"
+`
+
+func TestDataFlow_Contracts(t *testing.T) {
+	wf, err := Parse([]byte(dataFlowWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	issues := wf.DataFlowIssues()
+	if len(issues) != 1 {
+		t.Fatalf("DataFlowIssues() = %+v, want one unused write", issues)
+	}
+	want := DataFlowIssue{
+		Kind:     DataFlowUnusedWrite,
+		NodeID:   "login",
+		Variable: "audit_id",
+		Declared: true,
+		Message:  "node login writes audit_id, which no node reads",
+	}
+	if issues[0] != want {
+		t.Errorf("issue = %+v, want %+v", issues[0], want)
+	}
+
+	// Contracts are saved with the workflow
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := parsed.Contract("login"); got == nil || len(got.Writes) != 2 || got.Writes[0] != "session" {
+		t.Errorf("parsed contract = %+v", got)
+	}
+
+	// Removing the writer leaves the declared read unsatisfied
+	if err := wf.SetContract("login", NodeContract{}); err != nil {
+		t.Fatalf("SetContract() error = %v", err)
+	}
+	if wf.Contract("login") != nil {
+		t.Errorf("empty contract was kept: %+v", wf.Contract("login"))
+	}
+	err = wf.Validate()
+	if err == nil || !strings.Contains(err.Error(), "node report reads session, which no upstream node writes") {
+		t.Errorf("Validate() error = %v, want an unwritten read", err)
+	}
+
+	// As does removing the reader's node
+	if err := wf.RemoveNode("report"); err != nil {
+		t.Fatal(err)
+	}
+	if len(wf.Metadata.Contracts) != 0 {
+		t.Errorf("contracts = %v, want none", wf.Metadata.Contracts)
+	}
+}
+
+func TestDataFlow_ReadBeforeWrite(t *testing.T) {
+	wf, err := Parse([]byte(dataFlowWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	// login now reads the orders that fetch only writes later
+	wf.Nodes[1].(*MCPToolNode).Parameters["user"] = "${orders.owner}"
+
+	err = wf.Validate()
+	if err == nil || !strings.Contains(err.Error(), "node login reads orders, which no upstream node writes") {
+		t.Errorf("Validate() error = %v, want an unwritten read", err)
+	}
+}
+
+func TestDataFlow_JQReads(t *testing.T) {
+	wf, err := Parse([]byte(dataFlowWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	report := wf.Nodes[3].(*TransformNode)
+	report.Expression = ".total + $orders.tax"
+	report.Language = "jq"
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// The program now reads the total it writes itself
+	report.Expression = ".items | reduce .[] as $item ($total; . + $item.price)"
+	err = wf.Validate()
+	if err == nil || !strings.Contains(err.Error(), "node report reads total, which no upstream node writes") {
+		t.Errorf("Validate() error = %v, want an unwritten read", err)
+	}
+}
+
+func TestDataFlow_Bodies(t *testing.T) {
+	yaml := `
+version: "1.0.0"
+name: "data-flow-bodies"
+variables:
+  - name: "items"
+    type: "array"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "each"
+    type: "loop"
+    collection: "items"
+    item: "item"
+    body: ["double", "label"]
+  - id: "double"
+    type: "transform"
+    input: "item"
+    expression: "$.value"
+    output: "doubled"
+  - id: "label"
+    type: "transform"
+    input: "doubled"
+    expression: "$.name"
+    output: "labeled"
+  - id: "end"
+    type: "end"
+    return: "${labeled}"
+edges:
+  - from: "start"
+    to: "each"
+  - from: "each"
+    to: "end"
+`
+	wf, err := Parse([]byte(yaml))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if issues := wf.DataFlowIssues(); len(issues) != 0 {
+		t.Errorf("DataFlowIssues() = %+v, want none: bodies run in order and before the nodes after their loop", issues)
+	}
+}
+
+func TestWorkflow_SetContract(t *testing.T) {
+	wf, err := Parse([]byte(dataFlowWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if err := wf.SetContract("fetch", NodeContract{Reads: []string{" session ", "session", ""}}); err != nil {
+		t.Fatalf("SetContract() error = %v", err)
+	}
+	if got := wf.Contract("fetch").Reads; len(got) != 1 || got[0] != "session" {
+		t.Errorf("reads = %q, want [session]", got)
+	}
+
+	tests := []struct {
+		name     string
+		nodeID   string
+		contract NodeContract
+		want     string
+	}{
+		{"unknown node", "missing", NodeContract{Reads: []string{"x"}}, "node not found"},
+		{"invalid read", "fetch", NodeContract{Reads: []string{"1st"}}, "reads:"},
+		{"invalid write", "fetch", NodeContract{Writes: []string{"a b"}}, "writes:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wf.SetContract(tt.nodeID, tt.contract)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SetContract() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	wf.Metadata.Contracts["ghost"] = &NodeContract{Reads: []string{"session"}}
+	err = wf.Validate()
+	if err == nil || !strings.Contains(err.Error(), "contract references invalid node: ghost") {
+		t.Errorf("Validate() error = %v, want a contract for an unknown node", err)
+	}
+}
@@ -0,0 +1,248 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeType describes how an element differs between two workflows
+type ChangeType string
+
+// Change types
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// FieldChange is a changed field. Path is dotted for nested values, so a
+// tool parameter change has a path like "parameters.path".
+type FieldChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// DiffEntry is an added, removed or changed workflow element. Nodes are
+// identified by node ID, edges by "from -> to", variables by name and
+// servers by server ID.
+type DiffEntry struct {
+	ID       string        `json:"id"`
+	Change   ChangeType    `json:"change"`
+	NodeType string        `json:"node_type,omitempty"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+}
+
+// WorkflowDiff is a semantic diff between two workflows
+type WorkflowDiff struct {
+	Fields    []FieldChange `json:"fields"`
+	Nodes     []DiffEntry   `json:"nodes"`
+	Edges     []DiffEntry   `json:"edges"`
+	Variables []DiffEntry   `json:"variables"`
+	Servers   []DiffEntry   `json:"servers"`
+}
+
+// Empty reports whether the workflows are semantically identical
+func (d *WorkflowDiff) Empty() bool {
+	return len(d.Fields) == 0 && len(d.Nodes) == 0 && len(d.Edges) == 0 &&
+		len(d.Variables) == 0 && len(d.Servers) == 0
+}
+
+// Diff compares two workflows element by element rather than line by line.
+// Element order and generated edge IDs are ignored, as are creation and
+// modification timestamps.
+func Diff(a, b *Workflow) (*WorkflowDiff, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("workflow cannot be nil")
+	}
+
+	diff := &WorkflowDiff{
+		Fields:    []FieldChange{},
+		Nodes:     []DiffEntry{},
+		Edges:     []DiffEntry{},
+		Variables: []DiffEntry{},
+		Servers:   []DiffEntry{},
+	}
+
+	header := func(wf *Workflow) map[string]interface{} {
+		return map[string]interface{}{
+			"name":        wf.Name,
+			"version":     wf.Version,
+			"description": wf.Description,
+			"author":      wf.Metadata.Author,
+			"tags":        wf.Metadata.Tags,
+		}
+	}
+	diff.Fields = compareFields(header(a), header(b))
+
+	var err error
+	if diff.Nodes, err = diffElements(nodeElements(a), nodeElements(b)); err != nil {
+		return nil, err
+	}
+	if diff.Edges, err = diffElements(edgeElements(a), edgeElements(b)); err != nil {
+		return nil, err
+	}
+	if diff.Variables, err = diffElements(variableElements(a), variableElements(b)); err != nil {
+		return nil, err
+	}
+	if diff.Servers, err = diffElements(serverElements(a), serverElements(b)); err != nil {
+		return nil, err
+	}
+
+	// Report the node type of every node entry, from whichever side has it
+	types := make(map[string]string)
+	for _, wf := range []*Workflow{a, b} {
+		for _, node := range wf.Nodes {
+			types[node.GetID()] = node.Type()
+		}
+	}
+	for i := range diff.Nodes {
+		diff.Nodes[i].NodeType = types[diff.Nodes[i].ID]
+	}
+	return diff, nil
+}
+
+func nodeElements(wf *Workflow) map[string]interface{} {
+	elements := make(map[string]interface{}, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		elements[node.GetID()] = node
+	}
+	return elements
+}
+
+// edgeElements keys edges by endpoints, since edge IDs are often generated
+func edgeElements(wf *Workflow) map[string]interface{} {
+	elements := make(map[string]interface{}, len(wf.Edges))
+	for _, edge := range wf.Edges {
+		if edge == nil {
+			continue
+		}
+		elements[edge.FromNodeID+" -> "+edge.ToNodeID] = map[string]interface{}{
+			"condition": edge.Condition,
+			"label":     edge.Label,
+		}
+	}
+	return elements
+}
+
+func variableElements(wf *Workflow) map[string]interface{} {
+	elements := make(map[string]interface{}, len(wf.Variables))
+	for _, variable := range wf.Variables {
+		if variable != nil {
+			elements[variable.Name] = variable
+		}
+	}
+	return elements
+}
+
+func serverElements(wf *Workflow) map[string]interface{} {
+	elements := make(map[string]interface{}, len(wf.ServerConfigs))
+	for _, server := range wf.ServerConfigs {
+		if server != nil {
+			elements[server.ID] = server
+		}
+	}
+	return elements
+}
+
+// diffElements compares two keyed sets of elements via their JSON form
+func diffElements(a, b map[string]interface{}) ([]DiffEntry, error) {
+	entries := []DiffEntry{}
+	for id, element := range a {
+		if _, ok := b[id]; !ok {
+			entries = append(entries, DiffEntry{ID: id, Change: ChangeRemoved})
+			continue
+		}
+		oldFields, err := flattenJSON(element)
+		if err != nil {
+			return nil, err
+		}
+		newFields, err := flattenJSON(b[id])
+		if err != nil {
+			return nil, err
+		}
+		if changes := compareFields(oldFields, newFields); len(changes) > 0 {
+			entries = append(entries, DiffEntry{ID: id, Change: ChangeChanged, Fields: changes})
+		}
+	}
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			entries = append(entries, DiffEntry{ID: id, Change: ChangeAdded})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+// compareFields returns the fields that differ, sorted by path. Missing and
+// empty values are treated as equal.
+func compareFields(a, b map[string]interface{}) []FieldChange {
+	paths := make(map[string]bool, len(a)+len(b))
+	for path := range a {
+		paths[path] = true
+	}
+	for path := range b {
+		paths[path] = true
+	}
+
+	changes := []FieldChange{}
+	for path := range paths {
+		oldValue, newValue := a[path], b[path]
+		if isEmptyValue(oldValue) && isEmptyValue(newValue) {
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// flattenJSON converts a value to a map of dotted paths to leaf values.
+// Arrays are leaves so that reordering a list shows as one change.
+func flattenJSON(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode element: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode element: %w", err)
+	}
+
+	fields := make(map[string]interface{})
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fields[prefix] = value
+			return
+		}
+		for key, child := range object {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			walk(path, child)
+		}
+	}
+	walk("", decoded)
+	return fields, nil
+}
+
+// isEmptyValue reports whether a decoded JSON value is absent or zero
+func isEmptyValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return false
+}
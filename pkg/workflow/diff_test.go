@@ -0,0 +1,85 @@
+package workflow
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := newLintWorkflow(t)
+	after := newLintWorkflow(t)
+
+	// Reordering and regenerated edge IDs are not differences
+	after.Nodes[0], after.Nodes[2] = after.Nodes[2], after.Nodes[0]
+	for _, edge := range after.Edges {
+		edge.ID = NewEdgeID().String()
+	}
+	diff, err := Diff(before, after)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("Diff() of reordered workflow = %+v, want empty", diff)
+	}
+
+	after.Version = "2.0.0"
+	after.Nodes[1].(*MCPToolNode).Parameters["path"] = "${other}"
+	after.Nodes[1].(*MCPToolNode).Parameters["mode"] = "r"
+	_ = after.AddNode(&TransformNode{ID: "shape", InputVariable: "content", Expression: "$.x", OutputVariable: "out"})
+	after.Nodes = append(after.Nodes[:0], after.Nodes[1:]...) // Drop the end node
+	after.Edges[1].Condition = "ok == true"
+	_ = after.AddEdge(&Edge{FromNodeID: "read", ToNodeID: "shape"})
+	after.Variables[0].Type = "object"
+
+	diff, err = Diff(before, after)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	if len(diff.Fields) != 1 || diff.Fields[0].Path != "version" || diff.Fields[0].New != "2.0.0" {
+		t.Errorf("Fields = %+v, want version change", diff.Fields)
+	}
+
+	wantNodes := []struct {
+		id       string
+		change   ChangeType
+		nodeType string
+		fields   []string
+	}{
+		{"end", ChangeRemoved, "end", nil},
+		{"read", ChangeChanged, "mcp_tool", []string{"parameters.mode", "parameters.path"}},
+		{"shape", ChangeAdded, "transform", nil},
+	}
+	if len(diff.Nodes) != len(wantNodes) {
+		t.Fatalf("Nodes = %+v, want %d entries", diff.Nodes, len(wantNodes))
+	}
+	for i, want := range wantNodes {
+		got := diff.Nodes[i]
+		if got.ID != want.id || got.Change != want.change || got.NodeType != want.nodeType {
+			t.Errorf("node %d = %s %s (%s), want %s %s (%s)", i, got.Change, got.ID, got.NodeType, want.change, want.id, want.nodeType)
+		}
+		if len(got.Fields) != len(want.fields) {
+			t.Errorf("node %s fields = %+v, want %v", got.ID, got.Fields, want.fields)
+			continue
+		}
+		for j, path := range want.fields {
+			if got.Fields[j].Path != path {
+				t.Errorf("node %s field %d = %s, want %s", got.ID, j, got.Fields[j].Path, path)
+			}
+		}
+	}
+	if field := diff.Nodes[1].Fields[1]; field.Old != "${path}" || field.New != "${other}" {
+		t.Errorf("parameters.path change = %v -> %v", field.Old, field.New)
+	}
+
+	if len(diff.Edges) != 2 ||
+		diff.Edges[0].ID != "read -> end" || diff.Edges[0].Change != ChangeChanged ||
+		diff.Edges[1].ID != "read -> shape" || diff.Edges[1].Change != ChangeAdded {
+		t.Errorf("Edges = %+v", diff.Edges)
+	}
+	if len(diff.Variables) != 1 || diff.Variables[0].Fields[0].Path != "type" {
+		t.Errorf("Variables = %+v, want type change", diff.Variables)
+	}
+	if len(diff.Servers) != 0 {
+		t.Errorf("Servers = %+v, want none", diff.Servers)
+	}
+}
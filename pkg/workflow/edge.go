@@ -13,6 +13,8 @@ type Edge struct {
 	ToNodeID   string `json:"to_node_id" yaml:"to,omitempty"`
 	Condition  string `json:"condition,omitempty" yaml:"condition,omitempty"`
 	Label      string `json:"label,omitempty" yaml:"label,omitempty"`
+	// OnError marks an error edge, followed only when the from node fails
+	OnError bool `json:"on_error,omitempty" yaml:"on_error,omitempty"`
 }
 
 // Validate checks if the edge is valid
@@ -29,6 +31,9 @@ func (e *Edge) Validate() error {
 	if e.FromNodeID == e.ToNodeID {
 		return fmt.Errorf("edge: self-loop detected (node %s to itself)", e.FromNodeID)
 	}
+	if e.OnError && e.Condition != "" {
+		return fmt.Errorf("edge: error edge from %s cannot have a condition", e.FromNodeID)
+	}
 	return nil
 }
 
@@ -0,0 +1,322 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/workflow/workflowpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// encodingWorkflowYAML uses every node type and every kind of metadata
+const encodingWorkflowYAML = `
+schema_version: 1
+version: "1.0.0"
+name: "encoding-test"
+description: "Every node type"
+metadata:
+  author: "ada"
+  created: 2026-01-02T03:04:05Z
+  last_modified: 2026-02-03T04:05:06.5Z
+  tags: ["etl", "demo"]
+  icon: "🚚"
+  read_only: true
+  expression_timeout: "3s"
+  template:
+    name: "etl"
+    version: "1.2.0"
+    parameters:
+      region: "eu"
+      batch: 50
+      dry_run: true
+    nodes: ["fetch"]
+  groups:
+    - name: "Ingest"
+      nodes: ["fetch", "shape"]
+      collapsed: true
+  notes:
+    fetch: "Calls the orders API"
+  contracts:
+    fetch:
+      reads: ["region"]
+      writes: ["orders"]
+  lint:
+    unused-write: "off"
+  canvas:
+    positions:
+      start: {x: 5, y: 2}
+      fetch: {x: 30, y: 2}
+    zoom: 1.5
+    viewport_x: 4
+    viewport_y: 1
+variables:
+  - name: "region"
+    type: "string"
+    required: true
+    default: "eu"
+  - name: "limits"
+    type: "object"
+    default:
+      max: 10
+      ratio: 0.5
+      tags: ["a", "b"]
+  - name: "items"
+    type: "array"
+    description: "Items to process"
+servers:
+  - id: "api"
+    command: "api-server"
+    args: ["--port", "0"]
+    env:
+      MODE: "test"
+    credential_ref: "api-token"
+    limits:
+      max_concurrent: 2
+      requests_per_second: 5.5
+      burst: 3
+      queue_timeout: 10s
+  - id: "remote"
+    transport: "sse"
+    url: "https://example.com/mcp"
+    headers:
+      X-Team: "data"
+    auth:
+      type: "oauth2_client_credentials"
+      token_url: "https://auth.example.com/token"
+      client_id: "goflow"
+      client_secret_ref: "remote:client-secret"
+      scopes: ["tools:call", "tools:list"]
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "fetch"
+    type: "mcp_tool"
+    server: "api"
+    tool: "orders"
+    parameters:
+      region: "${region}"
+    output: "orders"
+    content_outputs:
+      image: "chart"
+    suppress: ["unused-write"]
+  - id: "shape"
+    type: "transform"
+    input: "orders"
+    expression: "$.items[*]"
+    language: "jsonpath"
+    output: "shaped"
+    item_errors_output: "shape_errors"
+    expression_timeout: "500ms"
+    output_schema:
+      type: "array"
+      items:
+        type: "object"
+        required: ["id"]
+  - id: "check"
+    type: "condition"
+    condition: "len(shaped) > 0"
+    expression_timeout: "1s"
+  - id: "route"
+    type: "switch"
+    cases:
+      - label: "big"
+        condition: "len(shaped) > 100"
+      - label: "small"
+        condition: "len(shaped) <= 100"
+  - id: "noop"
+    type: "passthrough"
+  - id: "fan"
+    type: "parallel"
+    branches: [["noop"], ["wait"]]
+    merge_strategy: "wait_all"
+  - id: "each"
+    type: "loop"
+    collection: "items"
+    item: "item"
+    body: ["wait"]
+    break_condition: "item == 'stop'"
+  - id: "guard"
+    type: "try"
+    body: ["approve"]
+  - id: "recover"
+    type: "catch"
+    error_variable: "failure"
+  - id: "wait"
+    type: "delay"
+    duration: "5m"
+  - id: "later"
+    type: "delay"
+    until: "2030-01-01T00:00:00Z"
+  - id: "approve"
+    type: "approval"
+    message: "Ship it?"
+    timeout: "1h"
+    default_action: "reject"
+    output: "decision"
+  - id: "lookup"
+    type: "batch_tool"
+    server: "api"
+    tool: "order"
+    collection: "items"
+    item: "order_id"
+    parameters:
+      id: "${order_id}"
+      region: "${region}"
+    max_concurrency: 4
+    output: "details"
+    error_variable: "lookup_errors"
+  - id: "end"
+    type: "end"
+    return: "${shaped}"
+edges:
+  - from: "start"
+    to: "fetch"
+  - from: "fetch"
+    to: "shape"
+  - from: "shape"
+    to: "check"
+  - from: "check"
+    to: "route"
+    condition: "true"
+  - from: "check"
+    to: "end"
+    condition: "false"
+  - from: "route"
+    to: "fan"
+    label: "big"
+  - from: "route"
+    to: "each"
+    label: "small"
+  - from: "fan"
+    to: "guard"
+  - from: "each"
+    to: "guard"
+  - from: "guard"
+    to: "later"
+  - from: "later"
+    to: "lookup"
+  - from: "lookup"
+    to: "end"
+  - from: "guard"
+    to: "recover"
+    on_error: true
+  - from: "recover"
+    to: "end"
+`
+
+func TestEncodings_RoundTrip(t *testing.T) {
+	wf, err := Parse([]byte(encodingWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	types := make(map[string]bool)
+	for _, node := range wf.Nodes {
+		types[node.Type()] = true
+	}
+	if len(types) != 14 {
+		t.Fatalf("fixture covers %d node types, want all 14", len(types))
+	}
+	want, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		encode func(*Workflow) ([]byte, error)
+		decode func([]byte) (*Workflow, error)
+	}{
+		{"json", ToJSON, ParseJSON},
+		{"protobuf", ToProto, ParseProto},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.encode(wf)
+			if err != nil {
+				t.Fatalf("encode error = %v", err)
+			}
+			decoded, err := tt.decode(data)
+			if err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			// Parsing adds nodes, which touches last_modified in every encoding
+			decoded.Metadata.LastModified = wf.Metadata.LastModified
+			got, err := ToYAML(decoded)
+			if err != nil {
+				t.Fatalf("ToYAML() error = %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("round trip changed the workflow\ngot:\n%s\nwant:\n%s", got, want)
+			}
+			if suppressed := decoded.Metadata.Suppressions["fetch"]; len(suppressed) != 1 || suppressed[0] != "unused-write" {
+				t.Errorf("suppressions = %v", decoded.Metadata.Suppressions)
+			}
+		})
+	}
+}
+
+func TestToJSON_Fields(t *testing.T) {
+	wf, err := Parse([]byte(encodingWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	data, err := ToJSON(wf)
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+
+	// JSON uses the YAML field names, and suppressions stay on their nodes
+	for _, want := range []string{`"schema_version":1`, `"merge_strategy":"wait_all"`, `"suppress":["unused-write"]`, `"on_error":true`, `"queue_timeout":"10s"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("ToJSON() = %s, want it to contain %s", data, want)
+		}
+	}
+	if strings.Contains(string(data), `"suppressions"`) {
+		t.Errorf("ToJSON() = %s, want suppressions only on nodes", data)
+	}
+}
+
+func TestParseJSON_MigratesLegacyWorkflow(t *testing.T) {
+	legacy := `{"version": "1.0.0", "name": "legacy",
+		"nodes": [{"id": "start", "type": "start"}, {"id": "end", "type": "end", "return_value": "done"}],
+		"edges": [{"from": "start", "to": "end"}]}`
+	wf, err := ParseJSON([]byte(legacy))
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+	if end, ok := wf.Nodes[1].(*EndNode); !ok || end.ReturnValue != "done" {
+		t.Errorf("end node = %#v, want the migrated return value", wf.Nodes[1])
+	}
+
+	if _, err := ParseJSON([]byte(`{"schema_version": 99, "version": "1", "name": "x"}`)); err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Errorf("ParseJSON() error = %v, want a newer schema error", err)
+	}
+	if _, err := ParseJSON([]byte(`{"name": `)); err == nil || !strings.Contains(err.Error(), "failed to parse JSON") {
+		t.Errorf("ParseJSON() error = %v, want a JSON error", err)
+	}
+}
+
+func TestParseProto_Errors(t *testing.T) {
+	newer, err := proto.Marshal(&workflowpb.Workflow{SchemaVersion: 99, Version: "1", Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "empty Protobuf input"},
+		{"garbage", []byte{0xff, 0xff, 0xff}, "failed to parse Protobuf"},
+		{"newer schema", newer, "newer than supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProto(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseProto() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -496,6 +496,11 @@ func deepCopyWorkflow(wf *Workflow) *Workflow {
 				Transport:     sc.Transport,
 				Env:           deepCopyStringMap(sc.Env),
 				CredentialRef: sc.CredentialRef,
+				Tags:          append([]string(nil), sc.Tags...),
+			}
+			if sc.Limits != nil {
+				limits := *sc.Limits
+				wfCopy.ServerConfigs[i].Limits = &limits
 			}
 		}
 	}
@@ -513,6 +518,7 @@ func deepCopyWorkflow(wf *Workflow) *Workflow {
 				ToNodeID:   e.ToNodeID,
 				Condition:  e.Condition,
 				Label:      e.Label,
+				OnError:    e.OnError,
 			}
 		}
 	}
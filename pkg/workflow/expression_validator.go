@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/dshills/goflow/pkg/transform"
@@ -120,6 +122,11 @@ func isKeyword(s string) bool {
 		"len":      true,
 		"length":   true,
 		"test":     true, // Common literal in tests
+		// Builtins testing the elements of a collection
+		"all":  true,
+		"any":  true,
+		"none": true,
+		"one":  true,
 	}
 	return keywords[s]
 }
@@ -128,24 +135,8 @@ func isKeyword(s string) bool {
 // Uses the transform package's expression evaluator for validation
 func validateExpressionSyntax(expr string) error {
 	// Check for unsafe operations first
-	unsafePatterns := []string{
-		"os.",
-		"exec.",
-		"http.",
-		"net.",
-		"syscall.",
-		"unsafe.",
-		"__proto__",
-		"ReadFile",
-		"WriteFile",
-		"Command",
-	}
-
-	lowerExpr := strings.ToLower(expr)
-	for _, pattern := range unsafePatterns {
-		if strings.Contains(lowerExpr, strings.ToLower(pattern)) {
-			return fmt.Errorf("unsafe operation detected: %s", pattern)
-		}
+	if err := transform.CheckExpressionSafety(expr); err != nil {
+		return err
 	}
 
 	// Try to compile the expression with a minimal context
@@ -171,7 +162,9 @@ func validateExpressionSyntax(expr string) error {
 		// Filter out type mismatch errors since we're using dummy context
 		if strings.Contains(err.Error(), "undefined") ||
 			strings.Contains(err.Error(), "mismatched types") ||
-			strings.Contains(err.Error(), "type mismatch") {
+			strings.Contains(err.Error(), "type mismatch") ||
+			strings.Contains(err.Error(), "has no field") ||
+			strings.Contains(err.Error(), "invalid argument for") {
 			// This is OK during validation - we just want to check syntax
 			return nil
 		}
@@ -295,31 +288,39 @@ func validateTemplateSyntax(template string) error {
 	return nil
 }
 
+// templatePathPattern matches a variable path in a template, such as
+// user.name, items.0.sku or items[0]["sku"]
+var templatePathPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*(\.[a-zA-Z0-9_]+|\[\s*([0-9]+|"[^"]*"|'[^']*')\s*\])*$`)
+
 // isValidTemplateExpression checks if a template expression is valid
-// Valid forms: variableName, variable.field, functionName(args)
+// Valid forms: variableName, variable.field, items[0], functionName(args),
+// each optionally piped through registered helpers: value | filter:args
 func isValidTemplateExpression(expr string) bool {
-	// Allow variable names with dots (for nested access)
+	head, filters, err := transform.ParseTemplatePipeline(expr)
+	if err != nil {
+		return false
+	}
+	for _, filter := range filters {
+		if _, ok := transform.LookupHelper(filter.Name); !ok {
+			return false
+		}
+	}
+
 	// Allow function calls
-	if strings.Contains(expr, "(") {
+	if strings.Contains(head, "(") {
 		// Function call - should have matching parentheses
-		openCount := strings.Count(expr, "(")
-		closeCount := strings.Count(expr, ")")
+		openCount := strings.Count(head, "(")
+		closeCount := strings.Count(head, ")")
 		return openCount == closeCount
 	}
 
-	// Simple variable reference - should be a valid identifier possibly with dots
-	parts := strings.Split(expr, ".")
-	for _, part := range parts {
-		if part == "" {
-			return false
-		}
-		// Each part should be a valid identifier
-		if !regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`).MatchString(part) {
-			return false
-		}
+	// Allow string literals
+	if len(head) >= 2 && (head[0] == '"' || head[0] == '\'') && head[len(head)-1] == head[0] {
+		return true
 	}
 
-	return true
+	// Variable reference - an identifier possibly with fields and indexes
+	return templatePathPattern.MatchString(head)
 }
 
 // extractTemplateVariables extracts variable names from template syntax
@@ -345,14 +346,23 @@ func extractTemplateVariables(template string) []string {
 			}
 			j += i + 2
 
-			// Extract expression
+			// Extract expression and the variables its filters read
 			expr := template[i+2 : j]
+			names := []string{expr}
+			if head, filters, err := transform.ParseTemplatePipeline(expr); err == nil {
+				names = []string{head}
+				for _, filter := range filters {
+					names = append(names, filterArgumentVariables(filter.Args)...)
+				}
+			}
 
 			// Extract variable name (first part before . or ()
-			varName := extractBaseVariable(expr)
-			if varName != "" && !seen[varName] {
-				seen[varName] = true
-				vars = append(vars, varName)
+			for _, name := range names {
+				varName := extractBaseVariable(strings.TrimSpace(name))
+				if varName != "" && !seen[varName] {
+					seen[varName] = true
+					vars = append(vars, varName)
+				}
 			}
 
 			i = j + 1
@@ -383,18 +393,123 @@ func extractBaseVariable(expr string) string {
 		return ""
 	}
 
-	// Handle dot notation - return first part
-	if strings.Contains(expr, ".") {
-		parts := strings.Split(expr, ".")
-		if len(parts) > 0 {
-			return parts[0]
-		}
+	// Handle dot and index notation - return first part
+	if end := strings.IndexAny(expr, ".["); end != -1 {
+		return expr[:end]
 	}
 
 	// Simple variable name
 	return expr
 }
 
+// filterArgumentVariables returns the arguments of a template filter that
+// are variable paths rather than literals
+func filterArgumentVariables(args string) []string {
+	var names []string
+	for _, arg := range strings.Split(args, ",") {
+		arg = strings.TrimSpace(arg)
+		if arg == "true" || arg == "false" || !templatePathPattern.MatchString(arg) {
+			continue
+		}
+		names = append(names, arg)
+	}
+	return names
+}
+
+// conditionVariablePattern matches the $.name references conditions may use
+// for variables
+var conditionVariablePattern = regexp.MustCompile(`\$\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// functionCallRegex matches identifiers used as functions, such as upper(
+var functionCallRegex = regexp.MustCompile(`\b([a-zA-Z][a-zA-Z0-9_]*)\s*\(`)
+
+// expressionOperators are the word operators of the expression language,
+// which look like identifiers
+var expressionOperators = map[string]bool{
+	"in":         true,
+	"matches":    true,
+	"startsWith": true,
+	"endsWith":   true,
+	"let":        true,
+	"if":         true,
+	"else":       true,
+}
+
+// KnownVariables returns the variables expressions can reference: the
+// declared workflow variables and every variable a node writes, including
+// loop items, caught errors and contract writes
+func (w *Workflow) KnownVariables() map[string]bool {
+	known := make(map[string]bool)
+	for _, variable := range w.Variables {
+		if variable != nil && variable.Name != "" {
+			known[variable.Name] = true
+		}
+	}
+	for _, node := range w.dataFlowNodes() {
+		for _, name := range node.writes {
+			known[name] = true
+		}
+	}
+	return known
+}
+
+// UnknownConditionIdentifiers returns the identifiers a condition references
+// that are not in known, sorted. $.name references resolve to the variable
+// name, as they do when the condition runs.
+func UnknownConditionIdentifiers(condition string, known map[string]bool) []string {
+	if containsTemplate(condition) {
+		return unknownIdentifiers(extractTemplateVariables(condition), known)
+	}
+	return unknownExpressionIdentifiers(conditionVariablePattern.ReplaceAllString(condition, "$1"), known)
+}
+
+// UnknownTransformIdentifiers returns the identifiers a transform expression
+// references that are not in known, sorted. JSONPath queries read the
+// transform's input rather than variables, and input is always known.
+func UnknownTransformIdentifiers(expression string, known map[string]bool) []string {
+	withInput := map[string]bool{"input": true}
+	for name := range known {
+		withInput[name] = true
+	}
+
+	switch {
+	case containsTemplate(expression):
+		return unknownIdentifiers(extractTemplateVariables(expression), withInput)
+	case strings.HasPrefix(strings.TrimSpace(expression), "$"):
+		return nil
+	}
+	return unknownExpressionIdentifiers(expression, withInput)
+}
+
+// unknownExpressionIdentifiers resolves the identifiers of an expression
+// that are not functions or operators
+func unknownExpressionIdentifiers(expr string, known map[string]bool) []string {
+	functions := make(map[string]bool)
+	for _, match := range functionCallRegex.FindAllStringSubmatch(removeStringLiterals(expr), -1) {
+		functions[match[1]] = true
+	}
+
+	var names []string
+	for _, name := range extractVariableReferences(expr) {
+		if !functions[name] && !expressionOperators[name] {
+			names = append(names, name)
+		}
+	}
+	return unknownIdentifiers(names, known)
+}
+
+// unknownIdentifiers returns the names not in known, sorted
+func unknownIdentifiers(names []string, known map[string]bool) []string {
+	var unknown []string
+	for _, name := range names {
+		if !known[name] && !slices.Contains(unknown, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
 // Exported validation functions for TUI use
 
 // ValidateExpressionSyntax validates the syntax of a condition expression (exported)
@@ -87,6 +87,16 @@ func TestValidateExpressionSyntax(t *testing.T) {
 			expr:    "email contains 'test'",
 			wantErr: false,
 		},
+		{
+			name:    "valid field access on object variable",
+			expr:    "result.success == true",
+			wantErr: false,
+		},
+		{
+			name:    "valid builtin call on collection variable",
+			expr:    "len(items) > 0",
+			wantErr: false,
+		},
 		{
 			name:    "invalid syntax - unclosed parenthesis",
 			expr:    "contains(email, 'test'",
@@ -140,6 +150,26 @@ func TestValidateJSONPathSyntax(t *testing.T) {
 			path:    "$.products[?(@.price < 100)]",
 			wantErr: false,
 		},
+		{
+			name:    "valid index union",
+			path:    "$.items[0,2,4]",
+			wantErr: false,
+		},
+		{
+			name:    "valid field union",
+			path:    "$.user['name','email']",
+			wantErr: false,
+		},
+		{
+			name:    "empty union entry",
+			path:    "$.items[0,,2]",
+			wantErr: true,
+		},
+		{
+			name:    "field union before another step",
+			path:    "$.user['name','email'].first",
+			wantErr: true,
+		},
 		{
 			name:    "missing dollar sign",
 			path:    "users[0].name",
@@ -236,6 +266,26 @@ func TestValidateTemplateSyntax(t *testing.T) {
 			tmpl:    "Hello ${user.name}",
 			wantErr: false,
 		},
+		{
+			name:    "valid index access",
+			tmpl:    "Hello ${users[0].name} ${users.1.name}",
+			wantErr: false,
+		},
+		{
+			name:    "valid filters",
+			tmpl:    `Hello ${name | trim | default:"guest" | upper}`,
+			wantErr: false,
+		},
+		{
+			name:    "unknown filter",
+			tmpl:    "Hello ${name | shout}",
+			wantErr: true,
+		},
+		{
+			name:    "filter without value",
+			tmpl:    "Hello ${ | upper}",
+			wantErr: true,
+		},
 		{
 			name:    "unclosed brace",
 			tmpl:    "Hello ${name",
@@ -294,6 +344,16 @@ func TestExtractTemplateVariables(t *testing.T) {
 			tmpl:     "Hello ${upper(name)}",
 			expected: []string{"name"},
 		},
+		{
+			name:     "index access",
+			tmpl:     "Hello ${users[0].name}",
+			expected: []string{"users"},
+		},
+		{
+			name:     "filters with variable argument",
+			tmpl:     `Hello ${nickname | default:name | truncate:10} ${title | default:"none"}`,
+			expected: []string{"nickname", "name", "title"},
+		},
 		{
 			name:     "no variables",
 			tmpl:     "Hello world",
@@ -487,6 +547,56 @@ func TestWorkflowValidateTransformConfig(t *testing.T) {
 			wantErr:   true,
 			errSubstr: "undefined variable in template",
 		},
+		{
+			name: "valid jq transform",
+			workflow: &Workflow{
+				Variables: []*Variable{
+					{Name: "orders", Type: "object"},
+					{Name: "min_qty", Type: "number"},
+				},
+			},
+			node: &TransformNode{
+				ID:             "trans1",
+				InputVariable:  "orders",
+				Expression:     ".items | map(select(.qty >= $min_qty)) | reduce .[] as $item (0; . + $item.price)",
+				Language:       "jq",
+				OutputVariable: "total",
+			},
+			wantErr: false,
+		},
+		{
+			name: "undefined jq variable",
+			workflow: &Workflow{
+				Variables: []*Variable{
+					{Name: "orders", Type: "object"},
+				},
+			},
+			node: &TransformNode{
+				ID:             "trans1",
+				InputVariable:  "orders",
+				Expression:     "jq(.items | map(select(.qty >= $min_qty)))",
+				OutputVariable: "total",
+			},
+			wantErr:   true,
+			errSubstr: "undefined variable in jq program: $min_qty",
+		},
+		{
+			name: "invalid jq syntax",
+			workflow: &Workflow{
+				Variables: []*Variable{
+					{Name: "orders", Type: "object"},
+				},
+			},
+			node: &TransformNode{
+				ID:             "trans1",
+				InputVariable:  "orders",
+				Expression:     ".items | map(",
+				Language:       "jq",
+				OutputVariable: "total",
+			},
+			wantErr:   true,
+			errSubstr: "invalid jq program",
+		},
 		{
 			name: "invalid JSONPath syntax",
 			workflow: &Workflow{
@@ -552,3 +662,55 @@ func hasSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestUnknownIdentifiers(t *testing.T) {
+	known := map[string]bool{"user": true, "orders": true, "threshold": true}
+
+	tests := []struct {
+		name     string
+		resolve  func(string, map[string]bool) []string
+		expr     string
+		expected []string
+	}{
+		{"condition with known variables", UnknownConditionIdentifiers, "user.age >= 18 && len(orders) > threshold", nil},
+		{"condition with unknown variables", UnknownConditionIdentifiers, "totl > threshold || flag", []string{"flag", "totl"}},
+		{"condition with $. references", UnknownConditionIdentifiers, "$.size > $.limit", []string{"limit", "size"}},
+		{"condition with template", UnknownConditionIdentifiers, "${user.age} > ${minimum}", []string{"minimum"}},
+		{"condition with functions and operators", UnknownConditionIdentifiers, "upper(user.name) startsWith 'A' && 'x' in orders", nil},
+		{"condition string literals", UnknownConditionIdentifiers, "user.role == 'admin'", nil},
+		{"transform JSONPath", UnknownTransformIdentifiers, "$.items[*].name", nil},
+		{"transform template", UnknownTransformIdentifiers, "${input.total} of ${budget}", []string{"budget"}},
+		{"transform expression", UnknownTransformIdentifiers, "map(orders, {.total}) + discount", []string{"discount"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.resolve(tt.expr, known)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("unknown identifiers = %q, want %q", result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("unknown identifiers = %q, want %q", result, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowKnownVariables(t *testing.T) {
+	wf, err := Parse([]byte(dataFlowWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	known := wf.KnownVariables()
+	for _, name := range []string{"user", "login_result", "orders", "total", "session", "audit_id"} {
+		if !known[name] {
+			t.Errorf("KnownVariables() is missing %s", name)
+		}
+	}
+	if known["missing"] {
+		t.Error("KnownVariables() includes a variable nothing declares or writes")
+	}
+}
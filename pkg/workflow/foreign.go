@@ -0,0 +1,276 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ForeignFormat identifies a workflow format from another automation engine
+// that can be converted into a GoFlow workflow
+type ForeignFormat string
+
+const (
+	// FormatGitHubActions is a GitHub Actions workflow file (.github/workflows/*.yml)
+	FormatGitHubActions ForeignFormat = "github-actions"
+	// FormatN8n is an n8n workflow export (JSON)
+	FormatN8n ForeignFormat = "n8n"
+)
+
+// Imported steps call these MCP servers and tools. Register servers under
+// these IDs to run an imported workflow.
+const (
+	// ImportShellServer runs shell commands (GitHub Actions run steps, n8n
+	// Execute Command nodes) with its ImportShellTool tool
+	ImportShellServer = "shell"
+	ImportShellTool   = "run_command"
+
+	// ImportHTTPServer makes HTTP requests (n8n HTTP Request nodes) with its
+	// ImportHTTPTool tool
+	ImportHTTPServer = "http"
+	ImportHTTPTool   = "http_request"
+)
+
+// ImportWarning flags a construct that could not be converted faithfully and
+// needs review before the imported workflow is run
+type ImportWarning struct {
+	// NodeID is the node the construct was imported as, if any
+	NodeID  string
+	Message string
+}
+
+// String formats the warning for display
+func (w ImportWarning) String() string {
+	if w.NodeID == "" {
+		return w.Message
+	}
+	return w.NodeID + ": " + w.Message
+}
+
+// DetectForeignFormat reports which engine a workflow file was exported from
+func DetectForeignFormat(data []byte) (ForeignFormat, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return "", errors.New("empty workflow file")
+	}
+
+	if trimmed[0] == '{' {
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &doc); err == nil {
+			if _, ok := doc["nodes"]; ok {
+				if _, ok := doc["connections"]; ok {
+					return FormatN8n, nil
+				}
+			}
+		}
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(trimmed, &doc); err == nil {
+		if _, ok := doc["jobs"]; ok {
+			return FormatGitHubActions, nil
+		}
+	}
+
+	return "", fmt.Errorf("unrecognized workflow format (supported: %s, %s)", FormatGitHubActions, FormatN8n)
+}
+
+// ImportForeign converts a workflow from another engine into a GoFlow
+// workflow. Steps become mcp_tool nodes where GoFlow has an equivalent;
+// other constructs become passthrough placeholders with a note and are
+// reported in the returned warnings.
+func ImportForeign(data []byte, format ForeignFormat) (*Workflow, []ImportWarning, error) {
+	switch format {
+	case FormatGitHubActions:
+		return importGitHubActions(data)
+	case FormatN8n:
+		return importN8n(data)
+	default:
+		return nil, nil, fmt.Errorf("unsupported import format %q (supported: %s, %s)", format, FormatGitHubActions, FormatN8n)
+	}
+}
+
+// foreignImporter accumulates the workflow, node IDs, and warnings while a
+// foreign workflow is converted
+type foreignImporter struct {
+	wf       *Workflow
+	ids      map[string]bool
+	warnings []ImportWarning
+}
+
+func newForeignImporter(name, description string) (*foreignImporter, error) {
+	slug := importSlug(name, "-")
+	if slug == "" {
+		slug = "imported-workflow"
+	}
+	wf, err := NewWorkflow(slug, description)
+	if err != nil {
+		return nil, err
+	}
+	return &foreignImporter{wf: wf, ids: make(map[string]bool)}, nil
+}
+
+// nodeID derives a unique node ID from a step or node name
+func (im *foreignImporter) nodeID(name string) string {
+	base := importIdentifier(name, "step")
+	id := base
+	for i := 2; im.ids[id]; i++ {
+		id = fmt.Sprintf("%s_%d", base, i)
+	}
+	im.ids[id] = true
+	return id
+}
+
+// add appends a node to the workflow
+func (im *foreignImporter) add(node Node) {
+	_ = im.wf.AddNode(node) // Only fails for nil nodes
+}
+
+// connect adds an edge between two nodes
+func (im *foreignImporter) connect(from, to, condition string) *Edge {
+	edge := &Edge{
+		ID:         NewEdgeID().String(),
+		FromNodeID: from,
+		ToNodeID:   to,
+		Condition:  condition,
+	}
+	im.wf.Edges = append(im.wf.Edges, edge)
+	return edge
+}
+
+// warn records a construct that needs review
+func (im *foreignImporter) warn(nodeID, format string, args ...interface{}) {
+	im.warnings = append(im.warnings, ImportWarning{NodeID: nodeID, Message: fmt.Sprintf(format, args...)})
+}
+
+// note attaches a note to an imported node, truncated to fit
+func (im *foreignImporter) note(nodeID, text string) {
+	if len(text) > maxNoteLength {
+		text = text[:maxNoteLength-3] + "..."
+	}
+	_ = im.wf.SetNote(nodeID, text) // The node was just added
+}
+
+// placeholder adds a passthrough node standing in for a construct GoFlow
+// cannot run, noted and reported so it is replaced before the workflow runs
+func (im *foreignImporter) placeholder(nodeID, construct string) {
+	im.add(&PassthroughNode{ID: nodeID})
+	im.note(nodeID, "Imported placeholder: "+construct+" has no GoFlow equivalent. Replace this node with an MCP tool node.")
+	im.warn(nodeID, "%s has no GoFlow equivalent; imported as a passthrough placeholder", construct)
+}
+
+// toolNode adds an mcp_tool node calling one of the import servers
+func (im *foreignImporter) toolNode(nodeID, serverID, tool string, params map[string]string) {
+	im.useServer(serverID)
+	im.add(&MCPToolNode{
+		ID:             nodeID,
+		ServerID:       serverID,
+		ToolName:       tool,
+		Parameters:     params,
+		OutputVariable: nodeID + "_result",
+	})
+}
+
+// importServerNames name the import servers in the workflow's server list
+var importServerNames = map[string]string{
+	ImportShellServer: "Shell commands",
+	ImportHTTPServer:  "HTTP requests",
+}
+
+// useServer declares an import server on the workflow. Its command is a
+// placeholder (mcp-server-<id>) to be pointed at a real MCP server.
+func (im *foreignImporter) useServer(serverID string) {
+	for _, sc := range im.wf.ServerConfigs {
+		if sc.ID == serverID {
+			return
+		}
+	}
+	im.wf.ServerConfigs = append(im.wf.ServerConfigs, &ServerConfig{
+		ID:      serverID,
+		Name:    importServerNames[serverID],
+		Command: "mcp-server-" + serverID,
+	})
+}
+
+// variable declares a workflow variable, returning its name. An existing
+// variable of the same name is reused.
+func (im *foreignImporter) variable(name, typ string, defaultValue interface{}, description string) string {
+	name = importIdentifier(name, "var")
+	for _, v := range im.wf.Variables {
+		if v.Name == name {
+			return name
+		}
+	}
+	im.wf.Variables = append(im.wf.Variables, &Variable{
+		Name:         name,
+		Type:         typ,
+		DefaultValue: defaultValue,
+		Description:  description,
+	})
+	return name
+}
+
+// reaches reports whether to is reachable from from along the edges added so
+// far
+func (im *foreignImporter) reaches(from, to string) bool {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return true
+		}
+		for _, edge := range im.wf.Edges {
+			if edge.FromNodeID == current && !seen[edge.ToNodeID] {
+				seen[edge.ToNodeID] = true
+				queue = append(queue, edge.ToNodeID)
+			}
+		}
+	}
+	return false
+}
+
+// escapeTemplates escapes ${ so text from another engine, such as shell
+// variables in commands, is not read as a GoFlow template
+func escapeTemplates(s string) string {
+	return strings.ReplaceAll(s, "${", `\${`)
+}
+
+// importIdentifier converts a name into a node ID or variable name: lower
+// case letters, digits, and underscores, starting with a letter
+func importIdentifier(name, prefix string) string {
+	id := importSlug(name, "_")
+	if id == "" {
+		return prefix
+	}
+	if id[0] >= '0' && id[0] <= '9' {
+		id = prefix + "_" + id
+	}
+	return id
+}
+
+// importSlug lower-cases name and joins its runs of letters and digits with
+// sep
+func importSlug(name, sep string) string {
+	var words []string
+	var word strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			word.WriteRune(r)
+			continue
+		}
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return strings.Join(words, sep)
+}
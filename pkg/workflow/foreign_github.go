@@ -0,0 +1,453 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ghWorkflow is a GitHub Actions workflow file. Jobs are kept as a node so
+// they import in file order.
+type ghWorkflow struct {
+	Name        string            `yaml:"name"`
+	On          yaml.Node         `yaml:"on"`
+	Env         map[string]string `yaml:"env"`
+	Defaults    ghDefaults        `yaml:"defaults"`
+	Permissions yaml.Node         `yaml:"permissions"`
+	Concurrency yaml.Node         `yaml:"concurrency"`
+	Jobs        yaml.Node         `yaml:"jobs"`
+}
+
+type ghDefaults struct {
+	Run struct {
+		Shell            string `yaml:"shell"`
+		WorkingDirectory string `yaml:"working-directory"`
+	} `yaml:"run"`
+}
+
+type ghInput struct {
+	Description string      `yaml:"description"`
+	Type        string      `yaml:"type"`
+	Default     interface{} `yaml:"default"`
+}
+
+type ghJob struct {
+	Name      string            `yaml:"name"`
+	Needs     ghStringList      `yaml:"needs"`
+	If        string            `yaml:"if"`
+	Uses      string            `yaml:"uses"`
+	Strategy  yaml.Node         `yaml:"strategy"`
+	Services  yaml.Node         `yaml:"services"`
+	Container yaml.Node         `yaml:"container"`
+	Env       map[string]string `yaml:"env"`
+	Defaults  ghDefaults        `yaml:"defaults"`
+	Steps     []ghStep          `yaml:"steps"`
+}
+
+type ghStep struct {
+	ID               string            `yaml:"id"`
+	Name             string            `yaml:"name"`
+	Uses             string            `yaml:"uses"`
+	Run              string            `yaml:"run"`
+	Shell            string            `yaml:"shell"`
+	WorkingDirectory string            `yaml:"working-directory"`
+	With             map[string]string `yaml:"with"`
+	Env              map[string]string `yaml:"env"`
+	If               string            `yaml:"if"`
+	ContinueOnError  string            `yaml:"continue-on-error"`
+	TimeoutMinutes   string            `yaml:"timeout-minutes"`
+}
+
+// ghStringList accepts a single string or a list, as needs does
+type ghStringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (l *ghStringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = ghStringList{node.Value}
+		return nil
+	}
+	var values []string
+	if err := node.Decode(&values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
+
+// ghExpressionPattern matches a ${{ ... }} expression
+var ghExpressionPattern = regexp.MustCompile(`\$\{\{\s*(.*?)\s*\}\}`)
+
+// githubImporter converts a GitHub Actions workflow, mapping inputs, env,
+// and secrets to workflow variables
+type githubImporter struct {
+	*foreignImporter
+	inputs  map[string]string
+	env     map[string]string
+	secrets map[string]string
+}
+
+// importGitHubActions converts a GitHub Actions workflow. Jobs run one after
+// another in dependency order; run steps call the shell server and other
+// steps become placeholders.
+func importGitHubActions(data []byte) (*Workflow, []ImportWarning, error) {
+	var doc ghWorkflow
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse GitHub Actions workflow: %w", err)
+	}
+	if doc.Jobs.Kind != yaml.MappingNode {
+		return nil, nil, errors.New("GitHub Actions workflow has no jobs")
+	}
+
+	name := doc.Name
+	if name == "" {
+		name = "github-actions"
+	}
+	base, err := newForeignImporter(name, fmt.Sprintf("Imported from GitHub Actions workflow %q", name))
+	if err != nil {
+		return nil, nil, err
+	}
+	im := &githubImporter{
+		foreignImporter: base,
+		inputs:          make(map[string]string),
+		env:             make(map[string]string),
+		secrets:         make(map[string]string),
+	}
+
+	if err := im.importTriggers(&doc.On); err != nil {
+		return nil, nil, err
+	}
+	im.importEnv(doc.Env)
+	if !doc.Permissions.IsZero() {
+		im.warn("", "permissions are not imported")
+	}
+	if !doc.Concurrency.IsZero() {
+		im.warn("", "concurrency settings are not imported")
+	}
+
+	keys, jobs, err := orderGitHubJobs(&doc.Jobs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	last := im.nodeID("start")
+	im.add(&StartNode{ID: last})
+	for i, key := range keys {
+		job := jobs[key]
+		if i > 0 && !slices.Contains(job.Needs, keys[i-1]) {
+			im.warn("", "job %s ran alongside job %s in GitHub Actions; imported to run after it", key, keys[i-1])
+		}
+
+		prefix := ""
+		if len(keys) > 1 {
+			prefix = key + " "
+		}
+
+		var jobNodes []string
+		if job.Uses != "" {
+			nodeID := im.nodeID(prefix + "call")
+			im.placeholder(nodeID, fmt.Sprintf("reusable workflow %s", job.Uses))
+			jobNodes = append(jobNodes, nodeID)
+		}
+		for j, step := range job.Steps {
+			label := step.ID
+			if label == "" {
+				label = step.Name
+			}
+			if label == "" && step.Uses != "" {
+				// actions/checkout@v4 is checkout
+				action, _, _ := strings.Cut(step.Uses, "@")
+				label = action[strings.LastIndex(action, "/")+1:]
+			}
+			if label == "" {
+				label = fmt.Sprintf("step %d", j+1)
+			}
+			nodeID := im.nodeID(prefix + label)
+			im.importStep(nodeID, step, job, doc.Defaults)
+			jobNodes = append(jobNodes, nodeID)
+		}
+		if len(jobNodes) == 0 {
+			im.warn("", "job %s has no steps and was skipped", key)
+			continue
+		}
+
+		first := jobNodes[0]
+		if job.If != "" {
+			im.warn(first, "job condition %q is not imported; the job always runs", job.If)
+		}
+		if !job.Strategy.IsZero() {
+			im.warn(first, "job %s strategy (matrix) is not imported; its steps run once", key)
+		}
+		if !job.Services.IsZero() || !job.Container.IsZero() {
+			im.warn(first, "job %s containers and services are not imported", key)
+		}
+
+		for _, nodeID := range jobNodes {
+			im.connect(last, nodeID, "")
+			last = nodeID
+		}
+	}
+
+	end := im.nodeID("end")
+	im.add(&EndNode{ID: end})
+	im.connect(last, end, "")
+
+	return im.wf, im.warnings, nil
+}
+
+// importTriggers declares workflow_dispatch and workflow_call inputs as
+// variables and flags the other triggers
+func (im *githubImporter) importTriggers(on *yaml.Node) error {
+	var triggers []string
+	switch on.Kind {
+	case yaml.ScalarNode:
+		triggers = append(triggers, on.Value)
+	case yaml.SequenceNode:
+		if err := on.Decode(&triggers); err != nil {
+			return fmt.Errorf("invalid on: %w", err)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(on.Content); i += 2 {
+			trigger, config := on.Content[i].Value, on.Content[i+1]
+			triggers = append(triggers, trigger)
+			inputs := mappingValue(config, "inputs")
+			if inputs == nil || inputs.Kind != yaml.MappingNode {
+				continue
+			}
+			for k := 0; k+1 < len(inputs.Content); k += 2 {
+				key := inputs.Content[k].Value
+				var input ghInput
+				if err := inputs.Content[k+1].Decode(&input); err != nil {
+					return fmt.Errorf("invalid input %s: %w", key, err)
+				}
+				if _, exists := im.inputs[key]; exists {
+					continue
+				}
+				im.inputs[key] = im.variable(key, ghInputType(input.Type), input.Default, input.Description)
+			}
+		}
+	}
+
+	var unsupported []string
+	for _, trigger := range triggers {
+		if trigger != "workflow_dispatch" && trigger != "workflow_call" {
+			unsupported = append(unsupported, trigger)
+		}
+	}
+	if len(unsupported) > 0 {
+		im.warn("", "triggers (%s) are not imported; start the workflow with goflow run", strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// importEnv declares workflow-level env as variables with their values as
+// defaults
+func (im *githubImporter) importEnv(env map[string]string) {
+	for _, key := range sortedKeys(env) {
+		value := env[key]
+		if ghExpressionPattern.MatchString(value) {
+			im.env[key] = im.variable(key, "string", nil, "GitHub Actions env "+key)
+			im.warn("", "env %s is set from an expression (%s); imported without a default", key, value)
+			continue
+		}
+		im.env[key] = im.variable(key, "string", value, "GitHub Actions env "+key)
+	}
+}
+
+// importStep adds the node for one step
+func (im *githubImporter) importStep(nodeID string, step ghStep, job ghJob, defaults ghDefaults) {
+	switch {
+	case step.Run != "":
+		params := map[string]string{"command": im.expressions(nodeID, step.Run)}
+		if dir := firstNonEmpty(step.WorkingDirectory, job.Defaults.Run.WorkingDirectory, defaults.Run.WorkingDirectory); dir != "" {
+			params["working_directory"] = im.expressions(nodeID, dir)
+		}
+		if shell := firstNonEmpty(step.Shell, job.Defaults.Run.Shell, defaults.Run.Shell); shell != "" {
+			params["shell"] = shell
+		}
+		if env := im.stepEnv(nodeID, job.Env, step.Env); env != "" {
+			params["env"] = env
+		}
+		im.toolNode(nodeID, ImportShellServer, ImportShellTool, params)
+	case step.Uses != "":
+		im.placeholder(nodeID, "GitHub Action "+step.Uses)
+		if len(step.With) > 0 {
+			var with []string
+			for _, key := range sortedKeys(step.With) {
+				with = append(with, fmt.Sprintf("%s: %s", key, step.With[key]))
+			}
+			im.note(nodeID, im.wf.Note(nodeID)+"\nInputs: "+strings.Join(with, ", "))
+		}
+	default:
+		im.placeholder(nodeID, "a step without run or uses")
+	}
+
+	if step.Name != "" && step.Run != "" {
+		im.note(nodeID, step.Name)
+	}
+	if step.If != "" {
+		im.warn(nodeID, "step condition %q is not imported; the step always runs", step.If)
+	}
+	if step.ContinueOnError != "" && step.ContinueOnError != "false" {
+		im.warn(nodeID, "continue-on-error is not imported; add an error edge to keep going on failure")
+	}
+	if step.TimeoutMinutes != "" {
+		im.warn(nodeID, "timeout-minutes is not imported")
+	}
+}
+
+// stepEnv formats the job and step env as KEY=value lines for the shell tool
+func (im *githubImporter) stepEnv(nodeID string, jobEnv, stepEnv map[string]string) string {
+	merged := make(map[string]string, len(jobEnv)+len(stepEnv))
+	for k, v := range jobEnv {
+		merged[k] = v
+	}
+	for k, v := range stepEnv {
+		merged[k] = v
+	}
+	var lines []string
+	for _, key := range sortedKeys(merged) {
+		lines = append(lines, key+"="+im.expressions(nodeID, merged[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// expressions rewrites ${{ }} expressions in text as GoFlow templates:
+// inputs, env, and secrets become variables. Other expressions are kept as
+// escaped text and flagged. Shell ${VAR} references are escaped.
+func (im *githubImporter) expressions(nodeID, text string) string {
+	var out strings.Builder
+	rest := text
+	for {
+		loc := ghExpressionPattern.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			out.WriteString(escapeTemplates(rest))
+			return out.String()
+		}
+		out.WriteString(escapeTemplates(rest[:loc[0]]))
+		expr := rest[loc[2]:loc[3]]
+		if variable, ok := im.resolve(nodeID, expr); ok {
+			out.WriteString("${" + variable + "}")
+		} else {
+			im.warn(nodeID, "expression ${{ %s }} has no GoFlow equivalent and was kept as text", expr)
+			out.WriteString(`\` + rest[loc[0]:loc[1]])
+		}
+		rest = rest[loc[1]:]
+	}
+}
+
+// resolve maps an inputs, env, or secrets expression to a variable
+func (im *githubImporter) resolve(nodeID, expr string) (string, bool) {
+	for _, prefix := range []string{"inputs.", "github.event.inputs."} {
+		if key, ok := strings.CutPrefix(expr, prefix); ok {
+			variable, exists := im.inputs[key]
+			return variable, exists
+		}
+	}
+	if key, ok := strings.CutPrefix(expr, "env."); ok {
+		variable, exists := im.env[key]
+		return variable, exists
+	}
+	if key, ok := strings.CutPrefix(expr, "secrets."); ok && importIdentifier(key, "") != "" {
+		if variable, exists := im.secrets[key]; exists {
+			return variable, true
+		}
+		variable := im.variable(key, "string", nil, "GitHub secret "+key)
+		im.secrets[key] = variable
+		im.warn(nodeID, "secret %s is imported as variable %s; pass it with --var when running", key, variable)
+		return variable, true
+	}
+	return "", false
+}
+
+// orderGitHubJobs decodes the jobs and orders them so each job follows the
+// jobs it needs, otherwise keeping file order
+func orderGitHubJobs(node *yaml.Node) ([]string, map[string]ghJob, error) {
+	jobs := make(map[string]ghJob)
+	var fileOrder []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		var job ghJob
+		if err := node.Content[i+1].Decode(&job); err != nil {
+			return nil, nil, fmt.Errorf("invalid job %s: %w", key, err)
+		}
+		jobs[key] = job
+		fileOrder = append(fileOrder, key)
+	}
+	for _, key := range fileOrder {
+		for _, need := range jobs[key].Needs {
+			if _, exists := jobs[need]; !exists {
+				return nil, nil, fmt.Errorf("job %s needs unknown job %s", key, need)
+			}
+		}
+	}
+
+	placed := make(map[string]bool)
+	var order []string
+	for len(order) < len(fileOrder) {
+		progress := false
+		for _, key := range fileOrder {
+			if placed[key] {
+				continue
+			}
+			ready := true
+			for _, need := range jobs[key].Needs {
+				if !placed[need] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				placed[key] = true
+				order = append(order, key)
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			var remaining []string
+			for _, key := range fileOrder {
+				if !placed[key] {
+					remaining = append(remaining, key)
+				}
+			}
+			return nil, nil, fmt.Errorf("jobs have circular needs: %s", strings.Join(remaining, ", "))
+		}
+	}
+	return order, jobs, nil
+}
+
+// ghInputType maps a workflow_dispatch input type to a variable type
+func ghInputType(inputType string) string {
+	switch inputType {
+	case "boolean":
+		return "boolean"
+	case "number":
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
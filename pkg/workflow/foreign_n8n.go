@@ -0,0 +1,371 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// n8nWorkflow is an n8n workflow export
+type n8nWorkflow struct {
+	Name  string    `json:"name"`
+	Nodes []n8nNode `json:"nodes"`
+	// Connections maps a source node name to its outputs by connection type;
+	// each output lists the nodes it feeds
+	Connections map[string]map[string][][]n8nConnection `json:"connections"`
+}
+
+type n8nNode struct {
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`
+	Position   []float64              `json:"position"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Disabled   bool                   `json:"disabled"`
+	Notes      string                 `json:"notes"`
+	OnError    string                 `json:"onError"`
+}
+
+type n8nConnection struct {
+	Node string `json:"node"`
+}
+
+// n8n canvas coordinates are pixels; the builder's canvas is character cells
+const (
+	n8nCellWidth  = 10
+	n8nCellHeight = 20
+)
+
+// importN8n converts an n8n workflow export. Triggers become the start
+// node, HTTP Request and Execute Command nodes call the import servers, If
+// nodes become conditions, and Wait nodes become delays. Other nodes become
+// placeholders.
+func importN8n(data []byte) (*Workflow, []ImportWarning, error) {
+	var doc n8nWorkflow
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse n8n workflow: %w", err)
+	}
+	if len(doc.Nodes) == 0 {
+		return nil, nil, errors.New("n8n workflow has no nodes")
+	}
+
+	name := doc.Name
+	if name == "" {
+		name = "n8n"
+	}
+	im, err := newForeignImporter(name, fmt.Sprintf("Imported from n8n workflow %q", name))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := im.nodeID("start")
+	im.add(&StartNode{ID: start})
+	end := im.nodeID("end")
+
+	// Convert the nodes; triggers all map to the start node
+	ids := make(map[string]string, len(doc.Nodes))
+	var triggers []n8nNode
+	for _, node := range doc.Nodes {
+		kind := n8nKind(node.Type)
+		switch {
+		case kind == "stickyNote":
+			continue
+		case n8nIsTrigger(kind):
+			ids[node.Name] = start
+			triggers = append(triggers, node)
+			if kind != "manualTrigger" && kind != "start" {
+				im.warn(start, "trigger %q (%s) is not imported; start the workflow with goflow run", node.Name, node.Type)
+			}
+			continue
+		}
+		nodeID := im.nodeID(node.Name)
+		ids[node.Name] = nodeID
+		im.importN8nNode(nodeID, node)
+	}
+	im.add(&EndNode{ID: end})
+
+	// Connect the outputs
+	sources := make([]string, 0, len(doc.Connections))
+	for source := range doc.Connections {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	hasIncoming := make(map[string]bool)
+	hasOutgoing := make(map[string]bool)
+	for _, source := range sources {
+		from, ok := ids[source]
+		if !ok {
+			continue
+		}
+		node := n8nNodeNamed(doc.Nodes, source)
+		for connType, outputs := range doc.Connections[source] {
+			if connType != "main" {
+				im.warn(from, "%s connections are not imported", connType)
+				continue
+			}
+			for index, targets := range outputs {
+				for _, target := range targets {
+					to, ok := ids[target.Node]
+					if !ok {
+						continue
+					}
+					if to == start || im.reaches(to, from) {
+						im.warn(from, "loop back to %q is not imported; use a loop node to repeat steps", target.Node)
+						continue
+					}
+					im.connectN8nOutput(from, to, node, index)
+					hasOutgoing[from+"#"+fmt.Sprint(index)] = true
+					hasOutgoing[from] = true
+					hasIncoming[to] = true
+				}
+			}
+		}
+	}
+
+	// Nodes without inputs follow the start node; unconnected outputs end
+	// the workflow
+	for _, node := range im.wf.Nodes {
+		nodeID := node.GetID()
+		if nodeID == start || nodeID == end {
+			continue
+		}
+		if !hasIncoming[nodeID] {
+			im.connect(start, nodeID, "")
+			hasOutgoing[start] = true
+		}
+		if _, ok := node.(*ConditionNode); ok {
+			for index, branch := range []string{"true", "false"} {
+				if !hasOutgoing[nodeID+"#"+fmt.Sprint(index)] {
+					im.connect(nodeID, end, branch)
+				}
+			}
+			continue
+		}
+		if !hasOutgoing[nodeID] {
+			im.connect(nodeID, end, "")
+		}
+	}
+	if !hasOutgoing[start] {
+		im.connect(start, end, "")
+	}
+
+	im.layoutN8n(doc.Nodes, ids, triggers, end)
+	return im.wf, im.warnings, nil
+}
+
+// importN8nNode adds the GoFlow node for one n8n node
+func (im *foreignImporter) importN8nNode(nodeID string, node n8nNode) {
+	params := node.Parameters
+	kind := n8nKind(node.Type)
+
+	switch {
+	case node.Disabled:
+		im.add(&PassthroughNode{ID: nodeID})
+		im.warn(nodeID, "node %q is disabled in n8n; imported as a passthrough", node.Name)
+	case kind == "httpRequest":
+		method := n8nString(params, "method", n8nString(params, "requestMethod", "GET"))
+		toolParams := map[string]string{
+			"method": method,
+			"url":    im.n8nExpressions(nodeID, n8nString(params, "url", "")),
+		}
+		if headers := n8nPairs(params, "headerParameters"); headers != "" {
+			toolParams["headers"] = im.n8nExpressions(nodeID, headers)
+		}
+		if query := n8nPairs(params, "queryParameters"); query != "" {
+			toolParams["query"] = im.n8nExpressions(nodeID, query)
+		}
+		if body := n8nString(params, "jsonBody", n8nString(params, "body", "")); body != "" {
+			toolParams["body"] = im.n8nExpressions(nodeID, body)
+		} else if fields := n8nPairs(params, "bodyParameters"); fields != "" {
+			toolParams["body"] = im.n8nExpressions(nodeID, fields)
+		}
+		if n8nString(params, "authentication", "none") != "none" {
+			im.warn(nodeID, "HTTP authentication is not imported; configure credentials on the %s server", ImportHTTPServer)
+		}
+		im.toolNode(nodeID, ImportHTTPServer, ImportHTTPTool, toolParams)
+	case kind == "executeCommand":
+		im.toolNode(nodeID, ImportShellServer, ImportShellTool, map[string]string{
+			"command": im.n8nExpressions(nodeID, n8nString(params, "command", "")),
+		})
+	case kind == "if":
+		im.add(&ConditionNode{ID: nodeID, Condition: "true"})
+		conditions, _ := json.Marshal(params["conditions"])
+		im.note(nodeID, "Imported from n8n If node. Original conditions: "+string(conditions))
+		im.warn(nodeID, "If conditions are not converted; the condition is a placeholder that is always true")
+	case kind == "wait":
+		im.importN8nWait(nodeID, node)
+	case kind == "noOp" || kind == "merge":
+		im.add(&PassthroughNode{ID: nodeID})
+		if kind == "merge" {
+			im.warn(nodeID, "Merge combines the branches' data in n8n; imported as a passthrough")
+		}
+	default:
+		im.placeholder(nodeID, fmt.Sprintf("n8n node %q (%s)", node.Name, node.Type))
+	}
+
+	if node.Notes != "" {
+		text := node.Notes
+		if existing := im.wf.Note(nodeID); existing != "" {
+			text = existing + "\n" + text
+		}
+		im.note(nodeID, text)
+	}
+}
+
+// importN8nWait adds a delay for a Wait node that resumes after an interval
+// or at a time, and a placeholder for one resumed by a webhook or form
+func (im *foreignImporter) importN8nWait(nodeID string, node n8nNode) {
+	params := node.Parameters
+	switch resume := n8nString(params, "resume", "timeInterval"); resume {
+	case "timeInterval":
+		amount := 1.0
+		if v, ok := params["amount"].(float64); ok {
+			amount = v
+		}
+		unit := n8nString(params, "unit", "hours")
+		seconds := amount * map[string]float64{"seconds": 1, "minutes": 60, "hours": 3600, "days": 86400}[unit]
+		if seconds <= 0 {
+			im.placeholder(nodeID, fmt.Sprintf("wait of %v %s", amount, unit))
+			return
+		}
+		im.add(&DelayNode{ID: nodeID, Duration: time.Duration(seconds * float64(time.Second)).String()})
+	case "specificTime":
+		until := n8nString(params, "dateTime", "")
+		if until == "" || strings.HasPrefix(until, "=") {
+			im.placeholder(nodeID, "wait until a computed time")
+			return
+		}
+		im.add(&DelayNode{ID: nodeID, Until: until})
+	default:
+		im.placeholder(nodeID, fmt.Sprintf("wait resumed by %s", resume))
+	}
+}
+
+// connectN8nOutput connects one output of an n8n node: If outputs are the
+// true and false branches, and the extra output of a node that continues on
+// error is its error edge
+func (im *foreignImporter) connectN8nOutput(from, to string, node *n8nNode, index int) {
+	for _, existing := range im.wf.Edges {
+		if existing.FromNodeID == from && existing.ToNodeID == to {
+			return
+		}
+	}
+	if node != nil && !node.Disabled && n8nKind(node.Type) == "if" {
+		branch := "true"
+		if index > 0 {
+			branch = "false"
+		}
+		im.connect(from, to, branch)
+		return
+	}
+	edge := im.connect(from, to, "")
+	if node != nil && index > 0 && node.OnError == "continueErrorOutput" {
+		edge.OnError = true
+	}
+}
+
+// n8nExpressions flags n8n {{ }} expressions, which reference the previous
+// node's items and need rewriting as GoFlow templates
+func (im *foreignImporter) n8nExpressions(nodeID, value string) string {
+	if strings.HasPrefix(value, "=") {
+		value = value[1:]
+		if strings.Contains(value, "{{") {
+			im.warn(nodeID, "n8n expression %q needs rewriting as a GoFlow template", value)
+		}
+	}
+	return escapeTemplates(value)
+}
+
+// layoutN8n carries the n8n canvas positions over, scaled to the builder's
+// canvas. The start node takes the first trigger's position and the end
+// node sits right of the rightmost node.
+func (im *foreignImporter) layoutN8n(nodes []n8nNode, ids map[string]string, triggers []n8nNode, end string) {
+	minX, minY, maxX := math.Inf(1), math.Inf(1), math.Inf(-1)
+	for _, node := range nodes {
+		if len(node.Position) != 2 || ids[node.Name] == "" {
+			continue
+		}
+		minX, minY = math.Min(minX, node.Position[0]), math.Min(minY, node.Position[1])
+		maxX = math.Max(maxX, node.Position[0])
+	}
+	if math.IsInf(minX, 1) {
+		return
+	}
+
+	layout := &CanvasLayout{Positions: make(map[string]CanvasPosition)}
+	place := func(nodeID string, x, y float64) {
+		if _, exists := layout.Positions[nodeID]; exists {
+			return
+		}
+		layout.Positions[nodeID] = CanvasPosition{
+			X: int((x - minX) / n8nCellWidth),
+			Y: int((y - minY) / n8nCellHeight),
+		}
+	}
+	for _, trigger := range triggers {
+		if len(trigger.Position) == 2 {
+			place(ids[trigger.Name], trigger.Position[0], trigger.Position[1])
+		}
+	}
+	for _, node := range nodes {
+		if len(node.Position) == 2 && ids[node.Name] != "" {
+			place(ids[node.Name], node.Position[0], node.Position[1])
+		}
+	}
+	place(end, maxX+20*n8nCellWidth, minY)
+	im.wf.Metadata.Canvas = layout
+}
+
+// n8nKind strips the package from an n8n node type
+// (n8n-nodes-base.httpRequest is httpRequest)
+func n8nKind(nodeType string) string {
+	if i := strings.LastIndex(nodeType, "."); i >= 0 {
+		return nodeType[i+1:]
+	}
+	return nodeType
+}
+
+// n8nIsTrigger reports whether a node kind starts n8n workflows
+func n8nIsTrigger(kind string) bool {
+	return kind == "start" || kind == "webhook" || kind == "cron" || kind == "interval" ||
+		strings.HasSuffix(kind, "Trigger")
+}
+
+func n8nNodeNamed(nodes []n8nNode, name string) *n8nNode {
+	for i := range nodes {
+		if nodes[i].Name == name {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// n8nString returns a string parameter, or def when it is unset
+func n8nString(params map[string]interface{}, key, def string) string {
+	if v, ok := params[key].(string); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// n8nPairs encodes a name/value parameter list, such as headerParameters,
+// as a JSON object
+func n8nPairs(params map[string]interface{}, key string) string {
+	group, _ := params[key].(map[string]interface{})
+	list, _ := group["parameters"].([]interface{})
+	pairs := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		pair, _ := item.(map[string]interface{})
+		if name, ok := pair["name"].(string); ok && name != "" {
+			pairs[name] = pair["value"]
+		}
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	encoded, _ := json.Marshal(pairs)
+	return string(encoded)
+}
@@ -0,0 +1,269 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const githubActionsYAML = `
+name: Release Build
+on:
+  push:
+    branches: [main]
+  workflow_dispatch:
+    inputs:
+      target:
+        description: Build target
+        default: linux
+      dry-run:
+        type: boolean
+        default: false
+env:
+  GO_VERSION: "1.25"
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+      - name: Run tests
+        run: go test ./... -tags ${{ inputs.target }}
+        working-directory: src
+  build:
+    needs: test
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, darwin]
+    steps:
+      - id: compile
+        run: GOOS=${{ matrix.os }} go build -o "${OUT_DIR}/app" .
+        env:
+          TOKEN: ${{ secrets.DEPLOY_TOKEN }}
+          GO: ${{ env.GO_VERSION }}
+`
+
+func TestImportForeign_GitHubActions(t *testing.T) {
+	format, err := DetectForeignFormat([]byte(githubActionsYAML))
+	if err != nil || format != FormatGitHubActions {
+		t.Fatalf("DetectForeignFormat() = %q, %v", format, err)
+	}
+	wf, warnings, err := ImportForeign([]byte(githubActionsYAML), format)
+	if err != nil {
+		t.Fatalf("ImportForeign() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("imported workflow is invalid: %v", err)
+	}
+	if wf.Name != "release-build" {
+		t.Errorf("Name = %q", wf.Name)
+	}
+
+	// Jobs run in order, each step a node
+	var order []string
+	for from := "start"; from != ""; {
+		order = append(order, from)
+		next := ""
+		for _, edge := range wf.Edges {
+			if edge.FromNodeID == from {
+				next = edge.ToNodeID
+			}
+		}
+		from = next
+	}
+	want := []string{"start", "test_checkout", "test_run_tests", "build_compile", "end"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	tests := importedNode(t, wf, "test_run_tests")
+	tool := tests.(*MCPToolNode)
+	if tool.ServerID != ImportShellServer || tool.ToolName != ImportShellTool {
+		t.Errorf("run step calls %s/%s", tool.ServerID, tool.ToolName)
+	}
+	if got := tool.Parameters["command"]; got != "go test ./... -tags ${target}" {
+		t.Errorf("command = %q", got)
+	}
+	if tool.Parameters["working_directory"] != "src" {
+		t.Errorf("working_directory = %q", tool.Parameters["working_directory"])
+	}
+
+	compile := importedNode(t, wf, "build_compile")
+	params := compile.(*MCPToolNode).Parameters
+	if got := params["command"]; got != `GOOS=\${{ matrix.os }} go build -o "\${OUT_DIR}/app" .` {
+		t.Errorf("command = %q, want unsupported expressions and shell variables escaped", got)
+	}
+	if got := params["env"]; got != "GO=${go_version}\nTOKEN=${deploy_token}" {
+		t.Errorf("env = %q", got)
+	}
+
+	checkout := importedNode(t, wf, "test_checkout")
+	if checkout.Type() != "passthrough" || !strings.Contains(wf.Note("test_checkout"), "actions/checkout@v4") {
+		t.Errorf("uses step imported as %s with note %q", checkout.Type(), wf.Note("test_checkout"))
+	}
+
+	for _, name := range []string{"target", "dry_run", "go_version", "deploy_token"} {
+		if !wf.hasVariable(name) {
+			t.Errorf("variable %s not declared", name)
+		}
+	}
+
+	for _, fragment := range []string{"triggers (push)", "actions/checkout@v4", "strategy (matrix)", "matrix.os", "secret DEPLOY_TOKEN"} {
+		if !hasImportWarning(warnings, fragment) {
+			t.Errorf("no warning mentioning %q in %v", fragment, warnings)
+		}
+	}
+
+	// The converted workflow saves and loads like any other
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	if _, err := Parse(data); err != nil {
+		t.Fatalf("Parse() of imported workflow error = %v", err)
+	}
+}
+
+func TestImportForeign_GitHubActionsErrors(t *testing.T) {
+	tests := map[string]string{
+		"no jobs":       "name: x\non: push\n",
+		"unknown need":  "jobs:\n  a:\n    needs: b\n    steps:\n      - run: echo\n",
+		"circular need": "jobs:\n  a:\n    needs: b\n    steps: [{run: echo}]\n  b:\n    needs: a\n    steps: [{run: echo}]\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := ImportForeign([]byte(input), FormatGitHubActions); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+const n8nExportJSON = `{
+  "name": "Sync Orders",
+  "nodes": [
+    {"name": "When clicking Execute", "type": "n8n-nodes-base.manualTrigger", "position": [240, 300], "parameters": {}},
+    {"name": "Fetch Orders", "type": "n8n-nodes-base.httpRequest", "position": [460, 300], "onError": "continueErrorOutput",
+     "parameters": {"method": "POST", "url": "https://api.example.com/orders",
+       "sendHeaders": true, "headerParameters": {"parameters": [{"name": "Accept", "value": "application/json"}]},
+       "jsonBody": "={{ $json.filter }}"}},
+    {"name": "Has Orders?", "type": "n8n-nodes-base.if", "position": [680, 300],
+     "parameters": {"conditions": {"conditions": [{"leftValue": "={{ $json.count }}", "operator": {"operation": "gt"}, "rightValue": 0}]}}},
+    {"name": "Archive", "type": "n8n-nodes-base.executeCommand", "position": [900, 200], "parameters": {"command": "tar czf orders-${DATE}.tgz orders"}},
+    {"name": "Pause", "type": "n8n-nodes-base.wait", "position": [900, 400], "parameters": {"amount": 90, "unit": "seconds"}},
+    {"name": "Notify Slack", "type": "n8n-nodes-base.slack", "position": [680, 500], "parameters": {}, "notes": "Post to #orders"},
+    {"name": "Note", "type": "n8n-nodes-base.stickyNote", "position": [0, 0], "parameters": {"content": "hello"}}
+  ],
+  "connections": {
+    "When clicking Execute": {"main": [[{"node": "Fetch Orders", "type": "main", "index": 0}]]},
+    "Fetch Orders": {"main": [[{"node": "Has Orders?", "type": "main", "index": 0}], [{"node": "Notify Slack", "type": "main", "index": 0}]]},
+    "Has Orders?": {"main": [[{"node": "Archive", "type": "main", "index": 0}], [{"node": "Pause", "type": "main", "index": 0}]]},
+    "Pause": {"main": [[{"node": "Fetch Orders", "type": "main", "index": 0}]]}
+  }
+}`
+
+func TestImportForeign_N8n(t *testing.T) {
+	format, err := DetectForeignFormat([]byte(n8nExportJSON))
+	if err != nil || format != FormatN8n {
+		t.Fatalf("DetectForeignFormat() = %q, %v", format, err)
+	}
+	wf, warnings, err := ImportForeign([]byte(n8nExportJSON), format)
+	if err != nil {
+		t.Fatalf("ImportForeign() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("imported workflow is invalid: %v", err)
+	}
+	if wf.Name != "sync-orders" {
+		t.Errorf("Name = %q", wf.Name)
+	}
+
+	fetch := importedNode(t, wf, "fetch_orders")
+	tool := fetch.(*MCPToolNode)
+	if tool.ServerID != ImportHTTPServer || tool.Parameters["method"] != "POST" ||
+		tool.Parameters["url"] != "https://api.example.com/orders" ||
+		tool.Parameters["headers"] != `{"Accept":"application/json"}` {
+		t.Errorf("HTTP Request imported as %+v", tool)
+	}
+	archive := importedNode(t, wf, "archive")
+	if got := archive.(*MCPToolNode).Parameters["command"]; got != `tar czf orders-\${DATE}.tgz orders` {
+		t.Errorf("command = %q", got)
+	}
+	pause := importedNode(t, wf, "pause")
+	if delay, ok := pause.(*DelayNode); !ok || delay.Duration != "1m30s" {
+		t.Errorf("Wait imported as %+v", pause)
+	}
+	for _, node := range wf.Nodes {
+		if node.GetID() == "note" {
+			t.Error("sticky notes should not be imported")
+		}
+	}
+	if !strings.Contains(wf.Note("notify_slack"), "Post to #orders") {
+		t.Errorf("node notes not kept: %q", wf.Note("notify_slack"))
+	}
+
+	edges := make(map[string]*Edge)
+	for _, edge := range wf.Edges {
+		edges[edge.FromNodeID+">"+edge.ToNodeID] = edge
+	}
+	for key, check := range map[string]func(*Edge) bool{
+		"start>fetch_orders":        func(e *Edge) bool { return !e.OnError },
+		"fetch_orders>notify_slack": func(e *Edge) bool { return e.OnError },
+		"has_orders>archive":        func(e *Edge) bool { return e.Condition == "true" },
+		"has_orders>pause":          func(e *Edge) bool { return e.Condition == "false" },
+		"pause>end":                 func(e *Edge) bool { return true },
+		"archive>end":               func(e *Edge) bool { return true },
+	} {
+		if edge, ok := edges[key]; !ok || !check(edge) {
+			t.Errorf("edge %s = %+v", key, edge)
+		}
+	}
+	if _, ok := edges["pause>fetch_orders"]; ok {
+		t.Error("loop back edge was imported")
+	}
+
+	if pos, ok := wf.Metadata.Canvas.Position("archive"); !ok || pos != (CanvasPosition{X: 66, Y: 0}) {
+		t.Errorf("archive position = %v, %v", pos, ok)
+	}
+	if pos, _ := wf.Metadata.Canvas.Position("start"); pos != (CanvasPosition{X: 0, Y: 5}) {
+		t.Errorf("start position = %v", pos)
+	}
+
+	for _, fragment := range []string{"If conditions", "$json.filter", "loop back", "n8n-nodes-base.slack"} {
+		if !hasImportWarning(warnings, fragment) {
+			t.Errorf("no warning mentioning %q in %v", fragment, warnings)
+		}
+	}
+}
+
+func TestDetectForeignFormat_Unknown(t *testing.T) {
+	for _, input := range []string{"", "name: x\nnodes: []\n", `{"name": "x"}`} {
+		if _, err := DetectForeignFormat([]byte(input)); err == nil {
+			t.Errorf("DetectForeignFormat(%q) should fail", input)
+		}
+	}
+	if _, _, err := ImportForeign([]byte("{}"), "zapier"); err == nil {
+		t.Error("unsupported formats should fail")
+	}
+}
+
+func hasImportWarning(warnings []ImportWarning, fragment string) bool {
+	for _, w := range warnings {
+		if strings.Contains(w.String(), fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func importedNode(t *testing.T, wf *Workflow, nodeID string) Node {
+	t.Helper()
+	for _, node := range wf.Nodes {
+		if node.GetID() == nodeID {
+			return node
+		}
+	}
+	t.Fatalf("node %s not imported", nodeID)
+	return nil
+}
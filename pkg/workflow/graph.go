@@ -0,0 +1,491 @@
+package workflow
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// GraphFormat is an output format for RenderGraph
+type GraphFormat string
+
+// Graph output formats
+const (
+	GraphDOT     GraphFormat = "dot"
+	GraphMermaid GraphFormat = "mermaid"
+	GraphSVG     GraphFormat = "svg"
+)
+
+// SVG layout dimensions in pixels
+const (
+	svgCharWidth     = 7  // Approximate width of one label character
+	svgNodeHeight    = 44 // Height of a node box
+	svgNodePadding   = 16 // Horizontal padding inside a node box
+	svgMinNodeWidth  = 80 // Minimum width of a node box
+	svgLayerSpacing  = 70 // Vertical space between layers
+	svgColumnSpacing = 40 // Horizontal space between nodes in a layer
+	svgMargin        = 20 // Margin around the drawing
+)
+
+// graphEdge is an edge in the rendered graph. Structural edges link a loop
+// or try node to its body and a parallel node to its branches. Error edges
+// are followed only when their from node fails.
+type graphEdge struct {
+	from, to   string
+	label      string
+	structural bool
+	onError    bool
+}
+
+// errorEdgeColor is the color of error edges in DOT and SVG output
+const errorEdgeColor = "#c62828"
+
+// RenderGraph renders the workflow's node/edge graph in the given format
+func RenderGraph(wf *Workflow, format GraphFormat) (string, error) {
+	if wf == nil {
+		return "", fmt.Errorf("workflow cannot be nil")
+	}
+	switch format {
+	case GraphDOT:
+		return renderDOT(wf), nil
+	case GraphMermaid:
+		return renderMermaid(wf), nil
+	case GraphSVG:
+		return renderSVG(wf), nil
+	default:
+		return "", fmt.Errorf("unsupported graph format: %s (expected dot, mermaid, or svg)", format)
+	}
+}
+
+// graphEdges returns workflow edges followed by structural edges, skipping
+// any that reference missing nodes
+func graphEdges(wf *Workflow) []graphEdge {
+	exists := make(map[string]bool, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		exists[node.GetID()] = true
+	}
+
+	var edges []graphEdge
+	add := func(e graphEdge) {
+		if exists[e.from] && exists[e.to] {
+			edges = append(edges, e)
+		}
+	}
+
+	for _, edge := range wf.Edges {
+		if edge == nil {
+			continue
+		}
+		label := edge.Label
+		if label == "" {
+			label = edge.Condition
+		}
+		if label == "" && edge.OnError {
+			label = "on error"
+		}
+		add(graphEdge{from: edge.FromNodeID, to: edge.ToNodeID, label: label, onError: edge.OnError})
+	}
+	for _, node := range wf.Nodes {
+		switch n := node.(type) {
+		case *LoopNode:
+			if len(n.Body) > 0 {
+				add(graphEdge{from: n.ID, to: n.Body[0], label: "each " + n.ItemVariable, structural: true})
+			}
+		case *TryNode:
+			if len(n.Body) > 0 {
+				add(graphEdge{from: n.ID, to: n.Body[0], label: "try", structural: true})
+			}
+		case *ParallelNode:
+			for i, branch := range n.Branches {
+				if len(branch) > 0 {
+					add(graphEdge{from: n.ID, to: branch[0], label: fmt.Sprintf("branch %d", i+1), structural: true})
+				}
+			}
+		}
+	}
+	return edges
+}
+
+// graphDetailLength caps the detail line of a node label
+const graphDetailLength = 40
+
+// nodeLabel returns a short description of a node for diagrams: its ID and,
+// for most node types, one line of detail
+func nodeLabel(node Node) string {
+	var detail string
+	switch n := node.(type) {
+	case *MCPToolNode:
+		detail = n.ServerID + "." + n.ToolName
+	case *BatchToolNode:
+		detail = fmt.Sprintf("%s.%s for %s in %s", n.ServerID, n.ToolName, n.ItemVariable, n.Collection)
+	case *ConditionNode:
+		detail = n.Condition
+	case *SwitchNode:
+		labels := make([]string, 0, len(n.Cases))
+		for _, c := range n.Cases {
+			labels = append(labels, c.Label)
+		}
+		detail = strings.Join(labels, " | ")
+	case *LoopNode:
+		detail = fmt.Sprintf("for %s in %s", n.ItemVariable, n.Collection)
+	case *TransformNode:
+		detail = n.Expression
+	case *TryNode:
+		detail = "try " + strings.Join(n.Body, ", ")
+	case *CatchNode:
+		detail = "catch → " + n.ErrorVariable
+	case *DelayNode:
+		if n.Until != "" {
+			detail = "until " + n.Until
+		} else {
+			detail = "wait " + n.Duration
+		}
+	case *ApprovalNode:
+		detail = "approve: " + n.Message
+	}
+
+	detail = strings.Join(strings.Fields(detail), " ")
+	if detail == "" {
+		return node.GetID()
+	}
+	if runes := []rune(detail); len(runes) > graphDetailLength {
+		detail = string(runes[:graphDetailLength-1]) + "…"
+	}
+	return node.GetID() + "\n" + detail
+}
+
+// renderDOT renders the workflow as a Graphviz digraph
+func renderDOT(wf *Workflow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(wf.Name))
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=11];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n\n")
+
+	for _, node := range wf.Nodes {
+		var attrs string
+		switch node.Type() {
+		case "start":
+			attrs = "shape=circle, style=filled, fillcolor=\"#c8e6c9\""
+		case "end":
+			attrs = "shape=doublecircle, style=filled, fillcolor=\"#ffcdd2\""
+		case "condition", "switch":
+			attrs = "shape=diamond, style=filled, fillcolor=\"#fff9c4\""
+		case "loop":
+			attrs = "shape=hexagon, style=filled, fillcolor=\"#e1bee7\""
+		case "parallel":
+			attrs = "shape=parallelogram, style=filled, fillcolor=\"#b3e5fc\""
+		case "transform":
+			attrs = "shape=box, style=\"rounded,filled\", fillcolor=\"#f5f5f5\""
+		case "try":
+			attrs = "shape=component, style=filled, fillcolor=\"#ffe0b2\""
+		case "catch":
+			attrs = "shape=octagon, style=filled, fillcolor=\"#ffccbc\""
+		case "delay":
+			attrs = "shape=box, style=\"rounded,dashed,filled\", fillcolor=\"#e0f2f1\""
+		case "approval":
+			attrs = "shape=house, style=filled, fillcolor=\"#dcedc8\""
+		default:
+			attrs = "shape=box, style=filled, fillcolor=\"#bbdefb\""
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, %s];\n", dotQuote(node.GetID()), dotQuote(nodeLabel(node)), attrs)
+	}
+
+	edges := graphEdges(wf)
+	if len(edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, edge := range edges {
+		var attrs []string
+		if edge.label != "" {
+			attrs = append(attrs, "label="+dotQuote(edge.label))
+		}
+		if edge.structural {
+			attrs = append(attrs, "style=dashed")
+		}
+		if edge.onError {
+			attrs = append(attrs, "style=dashed", "color="+dotQuote(errorEdgeColor), "fontcolor="+dotQuote(errorEdgeColor))
+		}
+		fmt.Fprintf(&b, "  %s -> %s", dotQuote(edge.from), dotQuote(edge.to))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns s as a quoted DOT identifier
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// renderMermaid renders the workflow as a Mermaid flowchart. Node IDs are
+// replaced with generated ones because Mermaid reserves words such as "end".
+func renderMermaid(wf *Workflow) string {
+	ids := make(map[string]string, len(wf.Nodes))
+	for i, node := range wf.Nodes {
+		ids[node.GetID()] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, node := range wf.Nodes {
+		label := mermaidQuote(nodeLabel(node))
+		id := ids[node.GetID()]
+		switch node.Type() {
+		case "start", "end":
+			fmt.Fprintf(&b, "  %s([%s])\n", id, label)
+		case "condition", "switch":
+			fmt.Fprintf(&b, "  %s{%s}\n", id, label)
+		case "loop":
+			fmt.Fprintf(&b, "  %s{{%s}}\n", id, label)
+		case "parallel":
+			fmt.Fprintf(&b, "  %s[/%s/]\n", id, label)
+		case "transform":
+			fmt.Fprintf(&b, "  %s(%s)\n", id, label)
+		case "try":
+			fmt.Fprintf(&b, "  %s[[%s]]\n", id, label)
+		case "catch":
+			fmt.Fprintf(&b, "  %s>%s]\n", id, label)
+		case "delay":
+			fmt.Fprintf(&b, "  %s([%s])\n", id, label)
+		case "approval":
+			fmt.Fprintf(&b, "  %s[/%s\\]\n", id, label)
+		default:
+			fmt.Fprintf(&b, "  %s[%s]\n", id, label)
+		}
+	}
+
+	for _, edge := range graphEdges(wf) {
+		arrow := "-->"
+		if edge.structural || edge.onError {
+			arrow = "-.->"
+		}
+		if edge.label != "" {
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", ids[edge.from], arrow, mermaidQuote(edge.label), ids[edge.to])
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", ids[edge.from], arrow, ids[edge.to])
+		}
+	}
+	return b.String()
+}
+
+// mermaidQuote returns s as a quoted Mermaid label
+func mermaidQuote(s string) string {
+	s = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return `"` + s + `"`
+}
+
+// svgBox is a positioned node in the SVG layout
+type svgBox struct {
+	node          Node
+	lines         []string
+	x, y, w, h    int
+	centerX, midY int
+}
+
+// renderSVG renders the workflow as a standalone SVG image using a layered
+// top-down layout: each node sits one layer below its deepest predecessor
+func renderSVG(wf *Workflow) string {
+	edges := graphEdges(wf)
+	layers := graphLayers(wf, edges)
+
+	nodes := make(map[string]Node, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		nodes[node.GetID()] = node
+	}
+
+	boxes := make(map[string]*svgBox, len(wf.Nodes))
+	width := 0
+	for i, layer := range layers {
+		rowWidth := 0
+		for _, id := range layer {
+			node := nodes[id]
+			lines := strings.Split(nodeLabel(node), "\n")
+			w := svgMinNodeWidth
+			for _, line := range lines {
+				w = max(w, utf8.RuneCountInString(line)*svgCharWidth+2*svgNodePadding)
+			}
+			boxes[id] = &svgBox{node: node, lines: lines, y: svgMargin + i*(svgNodeHeight+svgLayerSpacing), w: w, h: svgNodeHeight}
+			rowWidth += w
+		}
+		rowWidth += (len(layer) - 1) * svgColumnSpacing
+		width = max(width, rowWidth)
+	}
+
+	// Center each layer horizontally
+	for _, layer := range layers {
+		rowWidth := (len(layer) - 1) * svgColumnSpacing
+		for _, id := range layer {
+			rowWidth += boxes[id].w
+		}
+		x := svgMargin + (width-rowWidth)/2
+		for _, id := range layer {
+			box := boxes[id]
+			box.x = x
+			box.centerX = x + box.w/2
+			box.midY = box.y + box.h/2
+			x += box.w + svgColumnSpacing
+		}
+	}
+
+	totalWidth := width + 2*svgMargin
+	totalHeight := 2*svgMargin + len(layers)*(svgNodeHeight+svgLayerSpacing) - svgLayerSpacing
+	totalHeight = max(totalHeight, 2*svgMargin)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n",
+		totalWidth, totalHeight, totalWidth, totalHeight)
+	fmt.Fprintf(&b, "  <title>%s</title>\n", html.EscapeString(wf.Name))
+	b.WriteString(`  <defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="#555"/></marker></defs>` + "\n")
+
+	for _, edge := range edges {
+		from, to := boxes[edge.from], boxes[edge.to]
+		x1, y1 := from.centerX, from.y+from.h
+		x2, y2 := to.centerX, to.y
+		if to.y <= from.y {
+			// Back edge: leave from the side and enter from the side
+			x1, y1 = from.x+from.w, from.midY
+			x2, y2 = to.x+to.w, to.midY
+		}
+		dash, stroke := "", "#555"
+		if edge.structural || edge.onError {
+			dash = ` stroke-dasharray="5,4"`
+		}
+		if edge.onError {
+			stroke = errorEdgeColor
+		}
+		fmt.Fprintf(&b, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1.5"%s marker-end="url(#arrow)"/>`+"\n", x1, y1, x2, y2, stroke, dash)
+		if edge.label != "" {
+			fmt.Fprintf(&b, `  <text x="%d" y="%d" text-anchor="middle" fill="#333" font-size="10">%s</text>`+"\n",
+				(x1+x2)/2+4, (y1+y2)/2, html.EscapeString(edge.label))
+		}
+	}
+
+	for _, layer := range layers {
+		for _, id := range layer {
+			box := boxes[id]
+			fill, rx := svgNodeStyle(box.node.Type())
+			fmt.Fprintf(&b, `  <g id="%s">`+"\n", html.EscapeString(id))
+			fmt.Fprintf(&b, `    <rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s" stroke="#444"/>`+"\n",
+				box.x, box.y, box.w, box.h, rx, fill)
+			top := box.midY - (len(box.lines)-1)*8 + 4
+			for i, line := range box.lines {
+				weight := ""
+				if i == 0 {
+					weight = ` font-weight="bold"`
+				}
+				fmt.Fprintf(&b, `    <text x="%d" y="%d" text-anchor="middle"%s>%s</text>`+"\n",
+					box.centerX, top+i*16, weight, html.EscapeString(line))
+			}
+			b.WriteString("  </g>\n")
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// svgNodeStyle returns the fill color and corner radius for a node type
+func svgNodeStyle(nodeType string) (string, int) {
+	switch nodeType {
+	case "start":
+		return "#c8e6c9", 22
+	case "end":
+		return "#ffcdd2", 22
+	case "condition", "switch":
+		return "#fff9c4", 4
+	case "loop":
+		return "#e1bee7", 4
+	case "parallel":
+		return "#b3e5fc", 4
+	case "transform":
+		return "#f5f5f5", 10
+	case "try":
+		return "#ffe0b2", 4
+	case "catch":
+		return "#ffccbc", 4
+	case "delay":
+		return "#e0f2f1", 10
+	case "approval":
+		return "#dcedc8", 4
+	default:
+		return "#bbdefb", 4
+	}
+}
+
+// graphLayers assigns each node to a layer one below its deepest
+// predecessor, ignoring back edges. Nodes within a layer keep their order
+// in the workflow.
+func graphLayers(wf *Workflow, edges []graphEdge) [][]string {
+	if len(wf.Nodes) == 0 {
+		return nil
+	}
+	ids := make(map[string]bool, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		ids[node.GetID()] = true
+	}
+
+	adjacency := make(map[string][]string)
+	inDegree := make(map[string]int, len(wf.Nodes))
+	for _, edge := range edges {
+		adjacency[edge.from] = append(adjacency[edge.from], edge.to)
+		inDegree[edge.to]++
+	}
+
+	// Kahn's algorithm; nodes left over belong to cycles and are placed
+	// after their first visited predecessor
+	layer := make(map[string]int, len(wf.Nodes))
+	visited := make(map[string]bool, len(wf.Nodes))
+	var queue []string
+	for _, node := range wf.Nodes {
+		if inDegree[node.GetID()] == 0 {
+			queue = append(queue, node.GetID())
+		}
+	}
+	for len(visited) < len(ids) {
+		if len(queue) == 0 {
+			// Break a cycle at the first unvisited node in workflow order
+			for _, node := range wf.Nodes {
+				if !visited[node.GetID()] {
+					queue = append(queue, node.GetID())
+					inDegree[node.GetID()] = 0
+					break
+				}
+			}
+		}
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		for _, next := range adjacency[current] {
+			if visited[next] {
+				continue
+			}
+			layer[next] = max(layer[next], layer[current]+1)
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	maxLayer := 0
+	for _, l := range layer {
+		maxLayer = max(maxLayer, l)
+	}
+	layers := make([][]string, maxLayer+1)
+	for _, node := range wf.Nodes {
+		id := node.GetID()
+		layers[layer[id]] = append(layers[layer[id]], id)
+	}
+	return layers
+}
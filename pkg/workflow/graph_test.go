@@ -0,0 +1,103 @@
+package workflow
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// newGraphWorkflow builds a branching workflow with a loop
+func newGraphWorkflow(t *testing.T) *Workflow {
+	t.Helper()
+	wf, err := NewWorkflow("graph-test", "Graph test workflow")
+	if err != nil {
+		t.Fatalf("NewWorkflow() error = %v", err)
+	}
+	_ = wf.AddNode(&StartNode{ID: "start"})
+	_ = wf.AddNode(&ConditionNode{ID: "check", Condition: `size > 10 && name != "a"`})
+	_ = wf.AddNode(&LoopNode{ID: "each", Collection: "items", ItemVariable: "item", Body: []string{"work"}})
+	_ = wf.AddNode(&MCPToolNode{ID: "work", ServerID: "fs", ToolName: "read_file"})
+	_ = wf.AddNode(&EndNode{ID: "end"})
+	_ = wf.AddEdge(&Edge{FromNodeID: "start", ToNodeID: "check"})
+	_ = wf.AddEdge(&Edge{FromNodeID: "check", ToNodeID: "each", Condition: "true"})
+	_ = wf.AddEdge(&Edge{FromNodeID: "check", ToNodeID: "end", Condition: "false", Label: "<= 10"})
+	_ = wf.AddEdge(&Edge{FromNodeID: "each", ToNodeID: "end"})
+	return wf
+}
+
+func TestRenderGraph_DOT(t *testing.T) {
+	out, err := RenderGraph(newGraphWorkflow(t), GraphDOT)
+	if err != nil {
+		t.Fatalf("RenderGraph() error = %v", err)
+	}
+	for _, want := range []string{
+		`digraph "graph-test" {`,
+		`"check" [label="check\nsize > 10 && name != \"a\"", shape=diamond`,
+		`"work" [label="work\nfs.read_file", shape=box`,
+		`"check" -> "each" [label="true"];`,
+		`"check" -> "end" [label="<= 10"];`,
+		`"each" -> "work" [label="each item", style=dashed];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderGraph_Mermaid(t *testing.T) {
+	out, err := RenderGraph(newGraphWorkflow(t), GraphMermaid)
+	if err != nil {
+		t.Fatalf("RenderGraph() error = %v", err)
+	}
+	for _, want := range []string{
+		"flowchart TD\n",
+		`n1{"check<br/>size #gt; 10 && name != #quot;a#quot;"}`,
+		`n2{{"each<br/>for item in items"}}`,
+		`n4(["end"])`,
+		`n1 -->|"#lt;= 10"| n4`,
+		`n2 -.->|"each item"| n3`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderGraph_SVG(t *testing.T) {
+	out, err := RenderGraph(newGraphWorkflow(t), GraphSVG)
+	if err != nil {
+		t.Fatalf("RenderGraph() error = %v", err)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"svg"`
+		Groups  []struct {
+			ID   string `xml:"id,attr"`
+			Rect struct {
+				Y int `xml:"y,attr"`
+			} `xml:"rect"`
+		} `xml:"g"`
+		Lines []struct{} `xml:"line"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("SVG is not well-formed XML: %v\n%s", err, out)
+	}
+	if len(doc.Groups) != 5 || len(doc.Lines) != 5 {
+		t.Fatalf("SVG has %d nodes and %d edges, want 5 and 5", len(doc.Groups), len(doc.Lines))
+	}
+
+	y := make(map[string]int)
+	for _, g := range doc.Groups {
+		y[g.ID] = g.Rect.Y
+	}
+	// Each node sits below its deepest predecessor
+	if !(y["start"] < y["check"] && y["check"] < y["each"] && y["each"] < y["work"] && y["each"] < y["end"]) {
+		t.Errorf("unexpected layering: %v", y)
+	}
+}
+
+func TestRenderGraph_UnknownFormat(t *testing.T) {
+	if _, err := RenderGraph(newGraphWorkflow(t), "png"); err == nil {
+		t.Error("RenderGraph() should reject unknown formats")
+	}
+}
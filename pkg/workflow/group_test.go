@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func newGroupTestWorkflow(t *testing.T) *Workflow {
+	t.Helper()
+	wf, err := NewWorkflow("groups", "group test")
+	if err != nil {
+		t.Fatalf("NewWorkflow() error = %v", err)
+	}
+	for _, node := range []Node{
+		&StartNode{ID: "start"},
+		&TransformNode{ID: "fetch", InputVariable: "in", Expression: "$.a", OutputVariable: "a"},
+		&TransformNode{ID: "parse", InputVariable: "a", Expression: "$.b", OutputVariable: "b"},
+		&EndNode{ID: "end"},
+	} {
+		if err := wf.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	return wf
+}
+
+func TestWorkflow_AddGroup(t *testing.T) {
+	wf := newGroupTestWorkflow(t)
+
+	group, err := wf.AddGroup("Load data", []string{"fetch", "parse", "fetch"})
+	if err != nil {
+		t.Fatalf("AddGroup() error = %v", err)
+	}
+	if strings.Join(group.Nodes, ",") != "fetch,parse" {
+		t.Errorf("group nodes = %v, want fetch,parse", group.Nodes)
+	}
+	if wf.GroupOf("parse") != group || wf.GroupOf("start") != nil {
+		t.Error("GroupOf() does not find the group")
+	}
+
+	tests := []struct {
+		name    string
+		group   string
+		nodes   []string
+		wantErr string
+	}{
+		{name: "empty name", group: " ", nodes: []string{"end"}, wantErr: "empty"},
+		{name: "duplicate name", group: "Load data", nodes: []string{"end"}, wantErr: "already exists"},
+		{name: "no nodes", group: "Finish", wantErr: "at least one node"},
+		{name: "unknown node", group: "Finish", nodes: []string{"missing"}, wantErr: "node not found"},
+		{name: "node in another group", group: "Finish", nodes: []string{"parse", "end"}, wantErr: "already in group Load data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := wf.AddGroup(tt.group, tt.nodes); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AddGroup() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflow_GroupsFollowNodes(t *testing.T) {
+	wf := newGroupTestWorkflow(t)
+	if _, err := wf.AddGroup("Load data", []string{"fetch", "parse"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wf.AddGroup("Finish", []string{"end"}); err != nil {
+		t.Fatal(err)
+	}
+	wf.Group("Finish").Collapsed = true
+
+	// Groups are saved with the workflow
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(parsed.Metadata.Groups) != 2 || !parsed.Group("Finish").Collapsed {
+		t.Errorf("parsed groups = %+v", parsed.Metadata.Groups)
+	}
+
+	// Removing a node takes it out of its group, and drops emptied groups
+	if err := wf.RemoveNode("fetch"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wf.RemoveNode("end"); err != nil {
+		t.Fatal(err)
+	}
+	if len(wf.Metadata.Groups) != 1 || strings.Join(wf.Group("Load data").Nodes, ",") != "parse" {
+		t.Errorf("groups after removing nodes = %+v", wf.Metadata.Groups)
+	}
+
+	if err := wf.RemoveGroup("Load data"); err != nil {
+		t.Fatalf("RemoveGroup() error = %v", err)
+	}
+	if err := wf.RemoveGroup("Load data"); err == nil {
+		t.Error("RemoveGroup() removed a missing group")
+	}
+}
@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const itemErrorsWorkflowYAML = `
+version: "1.0.0"
+name: "item-errors-test"
+variables:
+  - name: "orders"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "names"
+    type: "transform"
+    input: "orders"
+    expression: "$.items[?(@.price > 15)].name"
+    output: "names"
+    item_errors_output: "skipped"
+  - id: "end"
+    type: "end"
+    return: "${skipped}"
+edges:
+  - from: "start"
+    to: "names"
+  - from: "names"
+    to: "end"
+`
+
+func TestTransformNode_ItemErrorsOutputParse(t *testing.T) {
+	wf, err := Parse([]byte(itemErrorsWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	node, ok := wf.Nodes[1].(*TransformNode)
+	if !ok || node.ItemErrorsOutput != "skipped" {
+		t.Fatalf("node = %#v, want item errors in skipped", wf.Nodes[1])
+	}
+
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	if !strings.Contains(string(data), "item_errors_output: skipped") {
+		t.Errorf("ToYAML() = %s, want item_errors_output", data)
+	}
+}
+
+func TestTransformNode_ItemErrorsOutputValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		node      TransformNode
+		errSubstr string
+	}{
+		{
+			name:      "invalid variable name",
+			node:      TransformNode{Expression: "$.items[*].name", ItemErrorsOutput: "bad-name"},
+			errSubstr: "invalid item errors output variable",
+		},
+		{
+			name:      "same variable as output",
+			node:      TransformNode{Expression: "$.items[*].name", ItemErrorsOutput: "result"},
+			errSubstr: "conflicts with output variable",
+		},
+		{
+			name:      "query without iteration selector",
+			node:      TransformNode{Expression: "$.items[0].name", ItemErrorsOutput: "skipped"},
+			errSubstr: "requires a JSONPath expression",
+		},
+		{
+			name:      "expression language",
+			node:      TransformNode{Expression: "$.items[*].name", Language: "jq", ItemErrorsOutput: "skipped"},
+			errSubstr: "requires a JSONPath expression",
+		},
+		{
+			name: "root array with explicit language",
+			node: TransformNode{Expression: "$[*].id", Language: "jsonpath", ItemErrorsOutput: "skipped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := tt.node
+			node.ID = "transform"
+			node.InputVariable = "data"
+			node.OutputVariable = "result"
+
+			err := node.Validate()
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
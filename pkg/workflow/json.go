@@ -0,0 +1,41 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ToJSON serializes a workflow to JSON, for REST API payloads. The document
+// has the same fields as the workflow's YAML.
+func ToJSON(workflow *Workflow) ([]byte, error) {
+	yw, err := toDocument(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(&yw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	return jsonBytes, nil
+}
+
+// ParseJSON parses a workflow from JSON bytes
+func ParseJSON(jsonBytes []byte) (*Workflow, error) {
+	if len(jsonBytes) == 0 {
+		return nil, errors.New("empty JSON input")
+	}
+
+	var yw yamlWorkflow
+	if err := json.Unmarshal(jsonBytes, &yw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	// JSON is YAML, so documents written with an older schema can go
+	// through Parse and its migrations
+	if yw.SchemaVersion != CurrentSchemaVersion {
+		return Parse(jsonBytes)
+	}
+	return fromDocument(yw)
+}
@@ -0,0 +1,29 @@
+package workflow
+
+// CanvasLayout records how the builder's canvas looked when the workflow
+// was saved, so it reopens the same way. Like groups and notes, the layout
+// does not change how the workflow runs.
+type CanvasLayout struct {
+	// Positions are node positions, keyed by node ID
+	Positions map[string]CanvasPosition `json:"positions,omitempty" yaml:"positions,omitempty"`
+	// Zoom is the zoom level (0.5 to 2.0); zero means the default
+	Zoom float64 `json:"zoom,omitempty" yaml:"zoom,omitempty"`
+	// ViewportX and ViewportY are the viewport offset in canvas coordinates
+	ViewportX int `json:"viewport_x,omitempty" yaml:"viewport_x,omitempty"`
+	ViewportY int `json:"viewport_y,omitempty" yaml:"viewport_y,omitempty"`
+}
+
+// CanvasPosition is the top-left corner of a node on the canvas
+type CanvasPosition struct {
+	X int `json:"x" yaml:"x"`
+	Y int `json:"y" yaml:"y"`
+}
+
+// Position returns a node's saved canvas position
+func (l *CanvasLayout) Position(nodeID string) (CanvasPosition, bool) {
+	if l == nil {
+		return CanvasPosition{}, false
+	}
+	pos, ok := l.Positions[nodeID]
+	return pos, ok
+}
@@ -0,0 +1,43 @@
+package workflow
+
+import "testing"
+
+func TestCanvasLayout_SavedWithWorkflow(t *testing.T) {
+	wf, err := Parse([]byte(dataFlowWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	wf.Metadata.Canvas = &CanvasLayout{
+		Positions: map[string]CanvasPosition{"login": {X: 5, Y: 2}, "fetch": {X: 30, Y: 2}},
+		Zoom:      1.5,
+		ViewportY: 4,
+	}
+
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pos, ok := parsed.Metadata.Canvas.Position("fetch"); !ok || pos != (CanvasPosition{X: 30, Y: 2}) {
+		t.Errorf("Position(fetch) = %v, %v", pos, ok)
+	}
+	if parsed.Metadata.Canvas.Zoom != 1.5 || parsed.Metadata.Canvas.ViewportY != 4 {
+		t.Errorf("canvas = %+v", parsed.Metadata.Canvas)
+	}
+
+	// Removing a node forgets its position
+	if err := parsed.RemoveNode("fetch"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parsed.Metadata.Canvas.Position("fetch"); ok {
+		t.Error("removed node kept its position")
+	}
+
+	var none *CanvasLayout
+	if _, ok := none.Position("login"); ok {
+		t.Error("a workflow without a layout has no positions")
+	}
+}
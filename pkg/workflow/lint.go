@@ -0,0 +1,499 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/transform"
+)
+
+// LintSeverity is the severity of a lint finding
+type LintSeverity string
+
+// Lint severities. LintOff disables a rule when used in LintOptions.Severity.
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+	LintOff     LintSeverity = "off"
+)
+
+// Lint rule names
+const (
+	RuleStructure       = "structure"
+	RuleUnusedVariable  = "unused-variable"
+	RuleUnreachableNode = "unreachable-node"
+	RuleMissingOutput   = "missing-output"
+	RuleUnknownServer   = "unknown-server"
+	RuleUnknownTool     = "unknown-tool"
+	RuleChangedTool     = "changed-tool"
+	RuleSandbox         = "sandbox"
+)
+
+// LintRule describes a lint rule and its default severity
+type LintRule struct {
+	Name        string
+	Severity    LintSeverity
+	Description string
+}
+
+// LintRules lists every lint rule in the order they run
+var LintRules = []LintRule{
+	{RuleStructure, LintError, "Workflow fails structural validation"},
+	{RuleUnusedVariable, LintWarning, "Declared variable is never referenced"},
+	{RuleUnreachableNode, LintError, "Node cannot be reached from the start node"},
+	{RuleMissingOutput, LintWarning, "Tool result is discarded, or a referenced variable is never set"},
+	{RuleUnknownServer, LintError, "Server is not declared in the workflow or not registered"},
+	{RuleUnknownTool, LintError, "Tool is not offered by its server"},
+	{RuleChangedTool, LintWarning, "Tool's schema changed since it was last discovered"},
+	{RuleSandbox, LintError, "Node would abort the run under the sandbox profile"},
+}
+
+// LintFinding is a single problem reported by Lint
+type LintFinding struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	NodeID   string       `json:"node_id,omitempty"`
+	Message  string       `json:"message"`
+}
+
+// LintOptions configures Lint
+type LintOptions struct {
+	// Severity overrides the default severity per rule; LintOff disables it
+	Severity map[string]LintSeverity
+	// RegisteredServers is the set of server IDs in the server registry.
+	// Nil skips the registry check of the unknown-server rule.
+	RegisteredServers map[string]bool
+	// ServerTools lists the tools offered by each server. Servers that are
+	// not in the map are not checked by the unknown-tool rule.
+	ServerTools map[string][]string
+	// ToolChanges describes, per server and tool, how a tool changed since
+	// the server was last discovered. Tools that are not in the map are not
+	// reported by the changed-tool rule.
+	ToolChanges map[string]map[string]string
+	// Sandbox is the profile the workflow will run under. Nil skips the
+	// sandbox rule.
+	Sandbox *SandboxProfile
+}
+
+// Validate checks that options only name known rules and severities
+func (o LintOptions) Validate() error {
+	for name, severity := range o.Severity {
+		if lookupLintRule(name) == nil {
+			return fmt.Errorf("unknown lint rule: %s", name)
+		}
+		switch severity {
+		case LintError, LintWarning, LintOff:
+		default:
+			return fmt.Errorf("invalid severity for rule %s: %q (expected error, warning, or off)", name, severity)
+		}
+	}
+	return nil
+}
+
+// severity returns the effective severity of a rule
+func (o LintOptions) severity(rule string) LintSeverity {
+	if severity, ok := o.Severity[rule]; ok {
+		return severity
+	}
+	if r := lookupLintRule(rule); r != nil {
+		return r.Severity
+	}
+	return LintOff
+}
+
+func lookupLintRule(name string) *LintRule {
+	for i := range LintRules {
+		if LintRules[i].Name == name {
+			return &LintRules[i]
+		}
+	}
+	return nil
+}
+
+// Lint runs structural validation and the lint rule set over a workflow.
+// Findings are ordered by rule, then node ID.
+func Lint(wf *Workflow, opts LintOptions) ([]LintFinding, error) {
+	if wf == nil {
+		return nil, fmt.Errorf("workflow cannot be nil")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	l := &linter{wf: wf, opts: opts}
+	l.checkStructure()
+	l.checkUnusedVariables()
+	l.checkUnreachableNodes()
+	l.checkMissingOutputs()
+	l.checkServersAndTools()
+	l.checkSandbox()
+
+	order := make(map[string]int, len(LintRules))
+	for i, rule := range LintRules {
+		order[rule.Name] = i
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		a, b := l.findings[i], l.findings[j]
+		if a.Rule != b.Rule {
+			return order[a.Rule] < order[b.Rule]
+		}
+		return a.NodeID < b.NodeID
+	})
+	return l.findings, nil
+}
+
+// linter accumulates findings for one workflow
+type linter struct {
+	wf       *Workflow
+	opts     LintOptions
+	findings []LintFinding
+}
+
+func (l *linter) enabled(rule string) bool {
+	return l.severity(rule, "") != LintOff
+}
+
+// severity returns the severity of a finding: LintOptions override the
+// workflow's metadata.lint, which overrides the default. A rule suppressed
+// on the node is always off.
+func (l *linter) severity(rule, nodeID string) LintSeverity {
+	if severity, ok := l.opts.Severity[rule]; ok {
+		if l.wf.isSuppressed(rule, nodeID) {
+			return LintOff
+		}
+		return severity
+	}
+	return l.wf.RuleSeverity(rule, nodeID, l.opts.severity(rule))
+}
+
+func (l *linter) report(rule, nodeID, format string, args ...interface{}) {
+	severity := l.severity(rule, nodeID)
+	if severity == LintOff {
+		return
+	}
+	l.findings = append(l.findings, LintFinding{
+		Rule:     rule,
+		Severity: severity,
+		NodeID:   nodeID,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkStructure reports each structural validation error separately
+func (l *linter) checkStructure() {
+	if !l.enabled(RuleStructure) {
+		return
+	}
+	err := l.wf.Validate()
+	if err == nil {
+		return
+	}
+	for _, msg := range strings.Split(err.Error(), "; ") {
+		// Reported per node by the unreachable-node rule
+		if strings.HasPrefix(msg, "orphaned node") && l.enabled(RuleUnreachableNode) {
+			continue
+		}
+		l.report(RuleStructure, "", "%s", msg)
+	}
+}
+
+// checkUnusedVariables reports declared variables that nothing references
+func (l *linter) checkUnusedVariables() {
+	if !l.enabled(RuleUnusedVariable) {
+		return
+	}
+	used := l.referencedNames()
+	for _, variable := range l.wf.Variables {
+		if variable == nil || variable.Name == "" || used[variable.Name] {
+			continue
+		}
+		l.report(RuleUnusedVariable, "", "variable %s is declared but never used", variable.Name)
+	}
+}
+
+// checkUnreachableNodes reports every node not reachable from the start node
+func (l *linter) checkUnreachableNodes() {
+	if !l.enabled(RuleUnreachableNode) {
+		return
+	}
+
+	var startID string
+	for _, node := range l.wf.Nodes {
+		if node.Type() == "start" {
+			startID = node.GetID()
+			break
+		}
+	}
+	if startID == "" {
+		return // Reported by the structure rule
+	}
+
+	adjacency := make(map[string][]string)
+	for _, edge := range l.wf.Edges {
+		if edge != nil {
+			adjacency[edge.FromNodeID] = append(adjacency[edge.FromNodeID], edge.ToNodeID)
+		}
+	}
+	for _, node := range l.wf.Nodes {
+		switch n := node.(type) {
+		case *ParallelNode:
+			for _, branch := range n.Branches {
+				adjacency[n.ID] = append(adjacency[n.ID], branch...)
+			}
+		case *LoopNode:
+			adjacency[n.ID] = append(adjacency[n.ID], n.Body...)
+		case *TryNode:
+			adjacency[n.ID] = append(adjacency[n.ID], n.Body...)
+		}
+	}
+
+	reachable := map[string]bool{startID: true}
+	queue := []string{startID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if !reachable[next] {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	for _, node := range l.wf.Nodes {
+		if !reachable[node.GetID()] {
+			l.report(RuleUnreachableNode, node.GetID(), "node %s is not reachable from the start node", node.GetID())
+		}
+	}
+}
+
+// checkMissingOutputs reports tool results that are never stored and
+// references that validation does not cover (return values, loop
+// collections) to variables nothing sets
+func (l *linter) checkMissingOutputs() {
+	if !l.enabled(RuleMissingOutput) {
+		return
+	}
+
+	defined := func(name string) bool {
+		return l.wf.hasVariable(name) || l.wf.hasNodeOutput(name) || l.wf.isLoopItemVariable(name)
+	}
+
+	for _, node := range l.wf.Nodes {
+		switch n := node.(type) {
+		case *MCPToolNode:
+			if n.OutputVariable == "" && len(n.ContentOutputs) == 0 {
+				l.report(RuleMissingOutput, n.ID, "result of tool %s is discarded (no output variable)", n.ToolName)
+			}
+		case *EndNode:
+			for _, name := range extractTemplateVariables(n.ReturnValue) {
+				if !defined(name) {
+					l.report(RuleMissingOutput, n.ID, "return value references %s, which no variable or node output sets", name)
+				}
+			}
+		case *LoopNode:
+			for _, name := range templateOrNameReferences(n.Collection) {
+				if !defined(name) {
+					l.report(RuleMissingOutput, n.ID, "loop collection references %s, which no variable or node output sets", name)
+				}
+			}
+		case *BatchToolNode:
+			for _, name := range templateOrNameReferences(n.Collection) {
+				if !defined(name) {
+					l.report(RuleMissingOutput, n.ID, "batch collection references %s, which no variable or node output sets", name)
+				}
+			}
+		}
+	}
+}
+
+// checkServersAndTools reports tool nodes whose server or tool does not
+// exist, or whose tool changed since it was last discovered
+func (l *linter) checkServersAndTools() {
+	checkServers := l.enabled(RuleUnknownServer)
+	checkTools := l.enabled(RuleUnknownTool)
+	checkChanges := l.enabled(RuleChangedTool)
+	if !checkServers && !checkTools && !checkChanges {
+		return
+	}
+
+	declared := make(map[string]bool, len(l.wf.ServerConfigs))
+	aliases := make(map[string]bool)
+	for _, server := range l.wf.ServerConfigs {
+		if server != nil {
+			declared[server.ID] = true
+			aliases[server.ID] = server.IsAlias()
+		}
+	}
+
+	for _, node := range l.wf.Nodes {
+		var nodeID, serverID, toolName string
+		switch n := node.(type) {
+		case *MCPToolNode:
+			nodeID, serverID, toolName = n.ID, n.ServerID, n.ToolName
+		case *BatchToolNode:
+			nodeID, serverID, toolName = n.ID, n.ServerID, n.ToolName
+		}
+		if serverID == "" {
+			continue
+		}
+
+		if checkServers {
+			// Undeclared servers are already a structural error; aliases
+			// are resolved by tag at run time
+			if declared[serverID] && !aliases[serverID] && l.opts.RegisteredServers != nil && !l.opts.RegisteredServers[serverID] {
+				l.report(RuleUnknownServer, nodeID, "server %s is not registered (add it with 'goflow server add')", serverID)
+			}
+			if !declared[serverID] && !l.enabled(RuleStructure) {
+				l.report(RuleUnknownServer, nodeID, "server %s is not declared in the workflow", serverID)
+			}
+		}
+
+		if toolName == "" {
+			continue
+		}
+		change, changed := l.opts.ToolChanges[serverID][toolName]
+		tools, known := l.opts.ServerTools[serverID]
+		if checkTools && known && !slices.Contains(tools, toolName) {
+			if changed {
+				l.report(RuleUnknownTool, nodeID, "server %s has no tool %s (%s since it was last discovered)", serverID, toolName, change)
+			} else {
+				l.report(RuleUnknownTool, nodeID, "server %s has no tool %s", serverID, toolName)
+			}
+			continue
+		}
+		if checkChanges && changed {
+			l.report(RuleChangedTool, nodeID, "tool %s of server %s %s since it was last discovered", toolName, serverID, change)
+		}
+	}
+}
+
+// checkSandbox reports nodes the sandbox profile would not let run
+func (l *linter) checkSandbox() {
+	if l.opts.Sandbox == nil || !l.enabled(RuleSandbox) {
+		return
+	}
+	for _, node := range l.wf.Nodes {
+		if reason := l.opts.Sandbox.CheckNode(l.wf, node); reason != "" {
+			l.report(RuleSandbox, node.GetID(), "%s", reason)
+		}
+	}
+}
+
+// RuleSeverity returns the severity of a lint or validation warning rule for
+// a finding on nodeID ("" for the whole workflow): off when the node
+// suppresses the rule, otherwise the severity set in metadata.lint, or
+// fallback.
+func (w *Workflow) RuleSeverity(rule, nodeID string, fallback LintSeverity) LintSeverity {
+	if w.isSuppressed(rule, nodeID) {
+		return LintOff
+	}
+	if severity, ok := w.Metadata.Lint[rule]; ok {
+		return severity
+	}
+	return fallback
+}
+
+// isSuppressed reports whether nodeID silences rule with an inline
+// suppress annotation
+func (w *Workflow) isSuppressed(rule, nodeID string) bool {
+	return nodeID != "" && slices.Contains(w.Metadata.Suppressions[nodeID], rule)
+}
+
+// lintSeverityErrors checks the severities in metadata.lint
+func (w *Workflow) lintSeverityErrors() []string {
+	var errs []string
+	rules := make([]string, 0, len(w.Metadata.Lint))
+	for rule := range w.Metadata.Lint {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		switch severity := w.Metadata.Lint[rule]; severity {
+		case LintError, LintWarning, LintOff:
+		default:
+			errs = append(errs, fmt.Sprintf("invalid lint severity for rule %s: %q (expected error, warning, or off)", rule, severity))
+		}
+	}
+	return errs
+}
+
+// referencedNames returns every variable name referenced anywhere in the workflow
+func (l *linter) referencedNames() map[string]bool {
+	used := make(map[string]bool)
+	add := func(names []string) {
+		for _, name := range names {
+			used[name] = true
+		}
+	}
+
+	for _, node := range l.wf.Nodes {
+		switch n := node.(type) {
+		case *MCPToolNode:
+			for _, value := range n.Parameters {
+				add(extractTemplateVariables(value))
+			}
+		case *TransformNode:
+			add(templateOrNameReferences(n.InputVariable))
+			add(transformExpressionReferences(n))
+		case *ConditionNode:
+			add(extractVariableReferences(n.Condition))
+		case *SwitchNode:
+			for _, c := range n.Cases {
+				add(extractVariableReferences(c.Condition))
+			}
+		case *LoopNode:
+			add(templateOrNameReferences(n.Collection))
+			add(extractVariableReferences(n.BreakCondition))
+		case *EndNode:
+			add(extractTemplateVariables(n.ReturnValue))
+		case *DelayNode:
+			add(extractTemplateVariables(n.Duration))
+			add(extractTemplateVariables(n.Until))
+		case *ApprovalNode:
+			add(extractTemplateVariables(n.Message))
+			add(extractTemplateVariables(n.Timeout))
+		case *BatchToolNode:
+			add(templateOrNameReferences(n.Collection))
+			for _, value := range n.Parameters {
+				add(extractTemplateVariables(value))
+			}
+		}
+	}
+	for _, edge := range l.wf.Edges {
+		if edge != nil {
+			add(extractVariableReferences(edge.Condition))
+		}
+	}
+	return used
+}
+
+// templateOrNameReferences handles fields that hold either a template or a
+// plain variable name
+func templateOrNameReferences(s string) []string {
+	if s == "" {
+		return nil
+	}
+	if containsTemplate(s) {
+		return extractTemplateVariables(s)
+	}
+	return []string{s}
+}
+
+// transformExpressionReferences returns the variables a transform's
+// expression reads: the $name references of a jq program, or the template
+// variables of any other expression except its runtime input
+func transformExpressionReferences(n *TransformNode) []string {
+	if n.IsJQ() {
+		return transform.JQVariableReferences(n.Expression)
+	}
+	var names []string
+	for _, name := range extractTemplateVariables(n.Expression) {
+		if name != "input" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
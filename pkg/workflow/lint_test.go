@@ -0,0 +1,272 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+// newLintWorkflow builds a valid workflow that calls one tool and returns its result
+func newLintWorkflow(t *testing.T) *Workflow {
+	t.Helper()
+	wf, err := NewWorkflow("lint-test", "Lint test workflow")
+	if err != nil {
+		t.Fatalf("NewWorkflow() error = %v", err)
+	}
+	wf.ServerConfigs = append(wf.ServerConfigs, &ServerConfig{ID: "fs", Command: "fs-server", Transport: "stdio"})
+	_ = wf.AddVariable(&Variable{Name: "path", Type: "string"})
+	_ = wf.AddNode(&StartNode{ID: "start"})
+	_ = wf.AddNode(&MCPToolNode{
+		ID:             "read",
+		ServerID:       "fs",
+		ToolName:       "read_file",
+		Parameters:     map[string]string{"path": "${path}"},
+		OutputVariable: "content",
+	})
+	_ = wf.AddNode(&EndNode{ID: "end", ReturnValue: "${content}"})
+	_ = wf.AddEdge(&Edge{FromNodeID: "start", ToNodeID: "read"})
+	_ = wf.AddEdge(&Edge{FromNodeID: "read", ToNodeID: "end"})
+	return wf
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(wf *Workflow)
+		opts      LintOptions
+		wantRules []string
+		wantNodes []string
+	}{
+		{
+			name:   "clean workflow",
+			modify: func(wf *Workflow) {},
+		},
+		{
+			name: "unused variable",
+			modify: func(wf *Workflow) {
+				_ = wf.AddVariable(&Variable{Name: "unused", Type: "string"})
+			},
+			wantRules: []string{RuleUnusedVariable},
+			wantNodes: []string{""},
+		},
+		{
+			name: "unreachable node replaces orphan structure error",
+			modify: func(wf *Workflow) {
+				_ = wf.AddNode(&EndNode{ID: "island"})
+			},
+			wantRules: []string{RuleUnreachableNode},
+			wantNodes: []string{"island"},
+		},
+		{
+			name: "discarded tool result",
+			modify: func(wf *Workflow) {
+				wf.Nodes[1].(*MCPToolNode).OutputVariable = ""
+				wf.Nodes[2].(*EndNode).ReturnValue = ""
+			},
+			wantRules: []string{RuleMissingOutput},
+			wantNodes: []string{"read"},
+		},
+		{
+			name: "return value references undefined variable",
+			modify: func(wf *Workflow) {
+				wf.Nodes[2].(*EndNode).ReturnValue = "${missing}"
+			},
+			wantRules: []string{RuleMissingOutput},
+			wantNodes: []string{"end"},
+		},
+		{
+			name:      "server not registered",
+			modify:    func(wf *Workflow) {},
+			opts:      LintOptions{RegisteredServers: map[string]bool{"other": true}},
+			wantRules: []string{RuleUnknownServer},
+			wantNodes: []string{"read"},
+		},
+		{
+			name: "server alias resolved at run time",
+			modify: func(wf *Workflow) {
+				wf.ServerConfigs[0] = &ServerConfig{ID: "fs", Tags: []string{"fs"}}
+			},
+			opts: LintOptions{RegisteredServers: map[string]bool{"other": true}},
+		},
+		{
+			name:   "tool offered by server",
+			modify: func(wf *Workflow) {},
+			opts:   LintOptions{ServerTools: map[string][]string{"fs": {"list_dir", "read_file"}}},
+		},
+		{
+			name:      "tool not offered by server",
+			modify:    func(wf *Workflow) {},
+			opts:      LintOptions{ServerTools: map[string][]string{"fs": {"list_dir"}}},
+			wantRules: []string{RuleUnknownTool},
+			wantNodes: []string{"read"},
+		},
+		{
+			name:   "tool changed since last discovered",
+			modify: func(wf *Workflow) {},
+			opts: LintOptions{
+				ServerTools: map[string][]string{"fs": {"read_file"}},
+				ToolChanges: map[string]map[string]string{"fs": {"read_file": "changed (param path removed)"}},
+			},
+			wantRules: []string{RuleChangedTool},
+			wantNodes: []string{"read"},
+		},
+		{
+			name:   "tool removed since last discovered",
+			modify: func(wf *Workflow) {},
+			opts: LintOptions{
+				ServerTools: map[string][]string{"fs": {}},
+				ToolChanges: map[string]map[string]string{"fs": {"read_file": "removed"}},
+			},
+			wantRules: []string{RuleUnknownTool},
+			wantNodes: []string{"read"},
+		},
+		{
+			name: "read allowed by read-only sandbox",
+			modify: func(wf *Workflow) {
+				wf.ServerConfigs[0].ToolCategories = map[string][]ToolCategory{"read_file": {ToolCategoryRead}}
+			},
+			opts: LintOptions{Sandbox: &BuiltinSandboxProfiles[0]},
+		},
+		{
+			name: "write denied by read-only sandbox",
+			modify: func(wf *Workflow) {
+				wf.ServerConfigs[0].ToolCategories = map[string][]ToolCategory{"read_file": {ToolCategoryRead}, "*": {ToolCategoryWrite}}
+				wf.Nodes[1].(*MCPToolNode).ToolName = "write_file"
+			},
+			opts:      LintOptions{Sandbox: &BuiltinSandboxProfiles[0]},
+			wantRules: []string{RuleSandbox},
+			wantNodes: []string{"read"},
+		},
+		{
+			name:      "unclassified tool denied by read-only sandbox",
+			modify:    func(wf *Workflow) {},
+			opts:      LintOptions{Sandbox: &BuiltinSandboxProfiles[0]},
+			wantRules: []string{RuleSandbox},
+			wantNodes: []string{"read"},
+		},
+		{
+			name: "disabled rule",
+			modify: func(wf *Workflow) {
+				_ = wf.AddVariable(&Variable{Name: "unused", Type: "string"})
+			},
+			opts: LintOptions{Severity: map[string]LintSeverity{RuleUnusedVariable: LintOff}},
+		},
+		{
+			name: "findings ordered by rule",
+			modify: func(wf *Workflow) {
+				_ = wf.AddVariable(&Variable{Name: "unused", Type: "string"})
+				_ = wf.AddNode(&EndNode{ID: "island"})
+			},
+			opts:      LintOptions{ServerTools: map[string][]string{"fs": {}}},
+			wantRules: []string{RuleUnusedVariable, RuleUnreachableNode, RuleUnknownTool},
+			wantNodes: []string{"", "island", "read"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := newLintWorkflow(t)
+			tt.modify(wf)
+
+			findings, err := Lint(wf, tt.opts)
+			if err != nil {
+				t.Fatalf("Lint() error = %v", err)
+			}
+			if len(findings) != len(tt.wantRules) {
+				t.Fatalf("Lint() returned %d findings, want %d: %+v", len(findings), len(tt.wantRules), findings)
+			}
+			for i, finding := range findings {
+				if finding.Rule != tt.wantRules[i] || finding.NodeID != tt.wantNodes[i] {
+					t.Errorf("finding %d = %s on %q, want %s on %q", i, finding.Rule, finding.NodeID, tt.wantRules[i], tt.wantNodes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLint_SeverityOverride(t *testing.T) {
+	wf := newLintWorkflow(t)
+	_ = wf.AddVariable(&Variable{Name: "unused", Type: "string"})
+
+	findings, err := Lint(wf, LintOptions{Severity: map[string]LintSeverity{RuleUnusedVariable: LintError}})
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Severity != LintError {
+		t.Errorf("Lint() = %+v, want one error finding", findings)
+	}
+}
+
+func TestLint_WorkflowSeverities(t *testing.T) {
+	wf := newLintWorkflow(t)
+	_ = wf.AddVariable(&Variable{Name: "unused", Type: "string"})
+	_ = wf.AddNode(&EndNode{ID: "island"})
+
+	// metadata.lint raises the unused variable; island silences its own finding
+	wf.Metadata.Lint = map[string]LintSeverity{RuleUnusedVariable: LintError}
+	wf.Metadata.Suppressions = map[string][]string{"island": {RuleUnreachableNode}}
+
+	findings, err := Lint(wf, LintOptions{})
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Rule != RuleUnusedVariable || findings[0].Severity != LintError {
+		t.Errorf("Lint() = %+v, want one unused-variable error", findings)
+	}
+
+	// Options override the workflow, but not a node's suppression
+	findings, err = Lint(wf, LintOptions{Severity: map[string]LintSeverity{
+		RuleUnusedVariable:  LintWarning,
+		RuleUnreachableNode: LintError,
+	}})
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Severity != LintWarning {
+		t.Errorf("Lint() = %+v, want one unused-variable warning", findings)
+	}
+
+	// Inline suppressions and severities are saved with the workflow
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	if !strings.Contains(string(data), "suppress:\n") {
+		t.Errorf("suppression is not written inline on its node:\n%s", data)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := parsed.RuleSeverity(RuleUnreachableNode, "island", LintWarning); got != LintOff {
+		t.Errorf("parsed RuleSeverity(island) = %s, want off", got)
+	}
+	if got := parsed.RuleSeverity(RuleUnusedVariable, "", LintWarning); got != LintError {
+		t.Errorf("parsed RuleSeverity() = %s, want error", got)
+	}
+
+	wf.Metadata.Lint[RuleUnusedVariable] = "fatal"
+	if err := wf.Validate(); err == nil || !strings.Contains(err.Error(), "invalid lint severity for rule unused-variable") {
+		t.Errorf("Validate() error = %v, want an invalid severity", err)
+	}
+}
+
+func TestLintOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    LintOptions
+		wantErr bool
+	}{
+		{"empty", LintOptions{}, false},
+		{"known rule", LintOptions{Severity: map[string]LintSeverity{RuleUnknownTool: LintWarning}}, false},
+		{"unknown rule", LintOptions{Severity: map[string]LintSeverity{"no-such-rule": LintOff}}, true},
+		{"invalid severity", LintOptions{Severity: map[string]LintSeverity{RuleUnknownTool: "fatal"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeResult is the outcome of a three-way merge
+type MergeResult struct {
+	Workflow *Workflow
+	// Conflicts lists the elements both sides changed differently, such as
+	// "node fetch" or "edge start -> fetch". Ours was kept for each.
+	Conflicts []string
+}
+
+// Merge combines the changes two versions of a workflow made to their
+// common base. Nodes, edges, variables, servers and the header fields are
+// merged element by element, as Diff compares them: an element changed on
+// one side only takes that side's version, and one changed differently on
+// both sides is a conflict, resolved in favour of ours. The merged workflow
+// keeps the identity and metadata of ours.
+func Merge(base, ours, theirs *Workflow) (*MergeResult, error) {
+	if base == nil || ours == nil || theirs == nil {
+		return nil, fmt.Errorf("workflow cannot be nil")
+	}
+
+	merged := *ours
+	result := &MergeResult{Workflow: &merged}
+	conflicts := &result.Conflicts
+
+	merged.Version = mergeValue("version", base.Version, ours.Version, theirs.Version, conflicts)
+	merged.Description = mergeValue("description", base.Description, ours.Description, theirs.Description, conflicts)
+	merged.Metadata.Author = mergeValue("author", base.Metadata.Author, ours.Metadata.Author, theirs.Metadata.Author, conflicts)
+	merged.Metadata.ReadOnly = mergeValue("read_only", base.Metadata.ReadOnly, ours.Metadata.ReadOnly, theirs.Metadata.ReadOnly, conflicts)
+	merged.Metadata.ExpressionTimeout = mergeValue("expression_timeout", base.Metadata.ExpressionTimeout, ours.Metadata.ExpressionTimeout, theirs.Metadata.ExpressionTimeout, conflicts)
+	merged.Metadata.Tags = mergeValue("tags", base.Metadata.Tags, ours.Metadata.Tags, theirs.Metadata.Tags, conflicts)
+	merged.Metadata.Groups = mergeValue("groups", base.Metadata.Groups, ours.Metadata.Groups, theirs.Metadata.Groups, conflicts)
+	merged.Metadata.Notes = mergeValue("notes", base.Metadata.Notes, ours.Metadata.Notes, theirs.Metadata.Notes, conflicts)
+	merged.Metadata.Contracts = mergeValue("contracts", base.Metadata.Contracts, ours.Metadata.Contracts, theirs.Metadata.Contracts, conflicts)
+	merged.Metadata.Lint = mergeValue("lint", base.Metadata.Lint, ours.Metadata.Lint, theirs.Metadata.Lint, conflicts)
+	merged.Metadata.Suppressions = mergeValue("suppressions", base.Metadata.Suppressions, ours.Metadata.Suppressions, theirs.Metadata.Suppressions, conflicts)
+
+	// The canvas layout is cosmetic: when both sides changed it, ours wins
+	// rather than conflicting
+	var layoutConflicts []string
+	merged.Metadata.Canvas = mergeValue("canvas", base.Metadata.Canvas, ours.Metadata.Canvas, theirs.Metadata.Canvas, &layoutConflicts)
+
+	var err error
+	merged.Nodes, err = mergeElements("node", base.Nodes, ours.Nodes, theirs.Nodes,
+		func(n Node) string {
+			if n == nil {
+				return ""
+			}
+			return n.GetID()
+		},
+		func(n Node) interface{} { return n },
+		conflicts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Edges are keyed by endpoints like in Diff, since their IDs are
+	// generated when a workflow is parsed
+	merged.Edges, err = mergeElements("edge", base.Edges, ours.Edges, theirs.Edges,
+		func(e *Edge) string {
+			if e == nil {
+				return ""
+			}
+			return e.FromNodeID + " -> " + e.ToNodeID
+		},
+		func(e *Edge) interface{} {
+			return map[string]interface{}{"condition": e.Condition, "label": e.Label}
+		},
+		conflicts)
+	if err != nil {
+		return nil, err
+	}
+
+	merged.Variables, err = mergeElements("variable", base.Variables, ours.Variables, theirs.Variables,
+		func(v *Variable) string {
+			if v == nil {
+				return ""
+			}
+			return v.Name
+		},
+		func(v *Variable) interface{} { return v },
+		conflicts)
+	if err != nil {
+		return nil, err
+	}
+
+	merged.ServerConfigs, err = mergeElements("server", base.ServerConfigs, ours.ServerConfigs, theirs.ServerConfigs,
+		func(s *ServerConfig) string {
+			if s == nil {
+				return ""
+			}
+			return s.ID
+		},
+		func(s *ServerConfig) interface{} { return s },
+		conflicts)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mergeValue merges a single field
+func mergeValue[T any](name string, base, ours, theirs T, conflicts *[]string) T {
+	switch {
+	case reflect.DeepEqual(base, theirs), reflect.DeepEqual(ours, theirs):
+		return ours
+	case reflect.DeepEqual(base, ours):
+		return theirs
+	}
+	*conflicts = append(*conflicts, name)
+	return ours
+}
+
+// mergeElements merges keyed workflow elements, comparing the values
+// returned by compare. Elements keep the order of ours, followed by those
+// only theirs has in their order.
+func mergeElements[T any](kind string, base, ours, theirs []T, key func(T) string, compare func(T) interface{}, conflicts *[]string) ([]T, error) {
+	index := func(elements []T) map[string]T {
+		indexed := make(map[string]T, len(elements))
+		for _, element := range elements {
+			if k := key(element); k != "" {
+				indexed[k] = element
+			}
+		}
+		return indexed
+	}
+	baseByKey, oursByKey, theirsByKey := index(base), index(ours), index(theirs)
+
+	// same reports whether an element is the same on two sides, counting
+	// absence as a value
+	same := func(a, b map[string]T, k string) (bool, error) {
+		x, inA := a[k]
+		y, inB := b[k]
+		if inA != inB {
+			return false, nil
+		}
+		if !inA {
+			return true, nil
+		}
+		return equalElements(compare(x), compare(y))
+	}
+
+	keys := make([]string, 0, len(ours)+len(theirs))
+	seen := make(map[string]bool, len(ours)+len(theirs))
+	for _, elements := range [][]T{ours, theirs} {
+		for _, element := range elements {
+			if k := key(element); k != "" && !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	merged := make([]T, 0, len(keys))
+	for _, k := range keys {
+		theirsUnchanged, err := same(baseByKey, theirsByKey, k)
+		if err != nil {
+			return nil, err
+		}
+		oursUnchanged, err := same(baseByKey, oursByKey, k)
+		if err != nil {
+			return nil, err
+		}
+		agree, err := same(oursByKey, theirsByKey, k)
+		if err != nil {
+			return nil, err
+		}
+
+		from := oursByKey
+		switch {
+		case theirsUnchanged || agree:
+		case oursUnchanged:
+			from = theirsByKey
+		default:
+			*conflicts = append(*conflicts, kind+" "+k)
+		}
+		if element, exists := from[k]; exists {
+			merged = append(merged, element)
+		}
+	}
+	return merged, nil
+}
+
+// equalElements compares two elements via their JSON form, like Diff
+func equalElements(a, b interface{}) (bool, error) {
+	aFields, err := flattenJSON(a)
+	if err != nil {
+		return false, err
+	}
+	bFields, err := flattenJSON(b)
+	if err != nil {
+		return false, err
+	}
+	return len(compareFields(aFields, bFields)) == 0, nil
+}
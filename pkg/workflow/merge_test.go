@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := newLintWorkflow(t)
+	ours := newLintWorkflow(t)
+	theirs := newLintWorkflow(t)
+
+	// Ours adds a node and changes the description
+	_ = ours.AddNode(&TransformNode{ID: "shape", InputVariable: "content", Expression: "$.x", OutputVariable: "out"})
+	_ = ours.AddEdge(&Edge{FromNodeID: "read", ToNodeID: "shape"})
+	ours.Description = "Ours"
+
+	// Theirs changes a tool parameter, removes the end node's edge and
+	// bumps the version
+	theirs.Nodes[1].(*MCPToolNode).Parameters["path"] = "${other}"
+	theirs.Edges = theirs.Edges[:1]
+	theirs.Version = "2.0.0"
+
+	result, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("Conflicts = %v, want none", result.Conflicts)
+	}
+	merged := result.Workflow
+	if merged.ID != ours.ID || merged.Description != "Ours" || merged.Version != "2.0.0" {
+		t.Errorf("header = %s %q %s", merged.ID, merged.Description, merged.Version)
+	}
+
+	var ids []string
+	for _, node := range merged.Nodes {
+		ids = append(ids, node.GetID())
+	}
+	if want := []string{"start", "read", "end", "shape"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("nodes = %v, want %v", ids, want)
+	}
+	if got := merged.Nodes[1].(*MCPToolNode).Parameters["path"]; got != "${other}" {
+		t.Errorf("read path = %s, want their change", got)
+	}
+	var edges []string
+	for _, edge := range merged.Edges {
+		edges = append(edges, edge.FromNodeID+" -> "+edge.ToNodeID)
+	}
+	if want := []string{"start -> read", "read -> shape"}; !reflect.DeepEqual(edges, want) {
+		t.Errorf("edges = %v, want %v", edges, want)
+	}
+
+	// Inputs are not modified
+	if len(ours.Edges) != 3 || ours.Version != base.Version {
+		t.Error("Merge() modified ours")
+	}
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	base := newLintWorkflow(t)
+	ours := newLintWorkflow(t)
+	theirs := newLintWorkflow(t)
+
+	ours.Nodes[1].(*MCPToolNode).Parameters["path"] = "${mine}"
+	theirs.Nodes[1].(*MCPToolNode).Parameters["path"] = "${yours}"
+	ours.Description = "Ours"
+	theirs.Description = "Theirs"
+	// The same change on both sides is not a conflict
+	ours.Version, theirs.Version = "1.1.0", "1.1.0"
+	// Removed by ours, changed by theirs
+	ours.Variables = nil
+	theirs.Variables[0].Type = "object"
+
+	result, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	want := []string{"description", "node read", "variable path"}
+	if !reflect.DeepEqual(result.Conflicts, want) {
+		t.Errorf("Conflicts = %v, want %v", result.Conflicts, want)
+	}
+	merged := result.Workflow
+	if merged.Description != "Ours" || merged.Version != "1.1.0" || len(merged.Variables) != 0 {
+		t.Errorf("merged = %q %s %d variables, want ours", merged.Description, merged.Version, len(merged.Variables))
+	}
+	if got := merged.Nodes[1].(*MCPToolNode).Parameters["path"]; got != "${mine}" {
+		t.Errorf("read path = %s, want ours", got)
+	}
+
+	if _, err := Merge(nil, ours, theirs); err == nil {
+		t.Error("expected error for nil base")
+	}
+}
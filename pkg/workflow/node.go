@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/dshills/goflow/pkg/transform"
 )
 
 // RetryPolicy defines the retry behavior for a node
@@ -168,6 +171,25 @@ func (n *EndNode) GetRetryPolicy() *RetryPolicy {
 	return nil
 }
 
+// MCP tool result content types that can be routed with MCPToolNode.ContentOutputs
+const (
+	ContentTypeText         = "text"
+	ContentTypeImage        = "image"
+	ContentTypeAudio        = "audio"
+	ContentTypeResource     = "resource"
+	ContentTypeResourceLink = "resource_link"
+)
+
+// IsValidContentType reports whether contentType is a routable MCP content type
+func IsValidContentType(contentType string) bool {
+	switch contentType {
+	case ContentTypeText, ContentTypeImage, ContentTypeAudio, ContentTypeResource, ContentTypeResourceLink:
+		return true
+	default:
+		return false
+	}
+}
+
 // MCPToolNode represents a node that executes an MCP tool
 type MCPToolNode struct {
 	ID             string            `json:"id" yaml:"id"`
@@ -175,7 +197,20 @@ type MCPToolNode struct {
 	ToolName       string            `json:"tool_name" yaml:"tool_name"`
 	Parameters     map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	OutputVariable string            `json:"output_variable" yaml:"output_variable"`
+	// ContentOutputs routes result content items by type to additional
+	// variables (e.g. text -> summary, resource -> file_ref). The full
+	// result is always stored in OutputVariable.
+	ContentOutputs map[string]string `json:"content_outputs,omitempty" yaml:"content_outputs,omitempty"`
 	Retry          *RetryPolicy      `json:"retry,omitempty" yaml:"retry,omitempty"`
+	// CacheTTL caches the tool's results for this long (e.g. "10m"), keyed
+	// by server, tool and arguments, so repeated calls with the same
+	// arguments skip the MCP round trip. Only for read-only tools; empty
+	// disables caching.
+	CacheTTL string `json:"cache_ttl,omitempty" yaml:"cache_ttl,omitempty"`
+	// StreamOutput names a variable that receives the tool's streamed text
+	// as it arrives, for servers that send partial content with progress
+	// notifications. It holds the text streamed so far while the tool runs.
+	StreamOutput string `json:"stream_output,omitempty" yaml:"stream_output,omitempty"`
 }
 
 // GetID returns the node ID
@@ -202,14 +237,59 @@ func (n *MCPToolNode) Validate() error {
 	if n.OutputVariable == "" {
 		return errors.New("mcp_tool node: empty output variable")
 	}
+	routed := make(map[string]string, len(n.ContentOutputs))
+	for contentType, variable := range n.ContentOutputs {
+		if !IsValidContentType(contentType) {
+			return fmt.Errorf("mcp_tool node: unknown content type %q in content outputs", contentType)
+		}
+		if variable == "" {
+			return fmt.Errorf("mcp_tool node: empty variable for %s content", contentType)
+		}
+		if variable == n.OutputVariable {
+			return fmt.Errorf("mcp_tool node: %s content variable %q conflicts with output variable", contentType, variable)
+		}
+		if other, exists := routed[variable]; exists {
+			return fmt.Errorf("mcp_tool node: %s and %s content both route to variable %q", other, contentType, variable)
+		}
+		routed[variable] = contentType
+	}
 	if n.Retry != nil {
 		if err := n.Retry.Validate(); err != nil {
 			return fmt.Errorf("mcp_tool node: %w", err)
 		}
 	}
+	if n.CacheTTL != "" {
+		if _, err := ParseDelayDuration(n.CacheTTL); err != nil {
+			return fmt.Errorf("mcp_tool node: cache_ttl: %w", err)
+		}
+	}
+	if n.StreamOutput != "" {
+		if !validVariableNameRegex.MatchString(n.StreamOutput) {
+			return fmt.Errorf("mcp_tool node: invalid stream output variable %q", n.StreamOutput)
+		}
+		if n.StreamOutput == n.OutputVariable {
+			return fmt.Errorf("mcp_tool node: stream output variable %q conflicts with output variable", n.StreamOutput)
+		}
+		if _, routed := routed[n.StreamOutput]; routed {
+			return fmt.Errorf("mcp_tool node: stream output variable %q conflicts with content outputs", n.StreamOutput)
+		}
+	}
 	return nil
 }
 
+// CacheDuration returns how long the node's results are cached, or 0 when
+// caching is off
+func (n *MCPToolNode) CacheDuration() time.Duration {
+	if n.CacheTTL == "" {
+		return 0
+	}
+	ttl, err := ParseDelayDuration(n.CacheTTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
 // MarshalJSON implements custom JSON marshaling
 func (n *MCPToolNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
@@ -219,7 +299,10 @@ func (n *MCPToolNode) MarshalJSON() ([]byte, error) {
 		ToolName       string            `json:"tool_name"`
 		Parameters     map[string]string `json:"parameters,omitempty"`
 		OutputVariable string            `json:"output_variable"`
+		ContentOutputs map[string]string `json:"content_outputs,omitempty"`
 		Retry          *RetryPolicy      `json:"retry,omitempty"`
+		CacheTTL       string            `json:"cache_ttl,omitempty"`
+		StreamOutput   string            `json:"stream_output,omitempty"`
 	}{
 		ID:             n.ID,
 		Type:           "mcp_tool",
@@ -227,7 +310,10 @@ func (n *MCPToolNode) MarshalJSON() ([]byte, error) {
 		ToolName:       n.ToolName,
 		Parameters:     n.Parameters,
 		OutputVariable: n.OutputVariable,
+		ContentOutputs: n.ContentOutputs,
 		Retry:          n.Retry,
+		CacheTTL:       n.CacheTTL,
+		StreamOutput:   n.StreamOutput,
 	})
 }
 
@@ -244,9 +330,22 @@ func (n *MCPToolNode) GetConfiguration() map[string]interface{} {
 		}
 		config["parameters"] = params
 	}
+	if len(n.ContentOutputs) > 0 {
+		outputs := make(map[string]interface{})
+		for k, v := range n.ContentOutputs {
+			outputs[k] = v
+		}
+		config["content_outputs"] = outputs
+	}
 	if n.Retry != nil {
 		config["retry"] = n.Retry
 	}
+	if n.CacheTTL != "" {
+		config["cache_ttl"] = n.CacheTTL
+	}
+	if n.StreamOutput != "" {
+		config["stream_output"] = n.StreamOutput
+	}
 	return config
 }
 
@@ -262,6 +361,20 @@ type TransformNode struct {
 	Expression     string       `json:"expression" yaml:"expression"`
 	OutputVariable string       `json:"output_variable" yaml:"output_variable"`
 	Retry          *RetryPolicy `json:"retry,omitempty" yaml:"retry,omitempty"`
+	// ExpressionTimeout bounds the evaluation of Expression (e.g. "2s"),
+	// overriding the workflow's
+	ExpressionTimeout string `json:"expression_timeout,omitempty" yaml:"expression_timeout,omitempty"`
+	// OutputSchema is a JSON Schema the result must match before it is
+	// stored in OutputVariable
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty" yaml:"output_schema,omitempty"`
+	// Language selects the language of Expression: jsonpath, expr,
+	// template or jq. Empty, or auto, detects it from the expression.
+	Language string `json:"language,omitempty" yaml:"language,omitempty"`
+	// ItemErrorsOutput names a variable that receives an error record for
+	// each array element a [*] or [?()] JSONPath query had to skip. The
+	// output then holds the values of the remaining elements instead of
+	// failing or silently dropping them.
+	ItemErrorsOutput string `json:"item_errors_output,omitempty" yaml:"item_errors_output,omitempty"`
 }
 
 // GetID returns the node ID
@@ -293,25 +406,77 @@ func (n *TransformNode) Validate() error {
 			return fmt.Errorf("transform node: %w", err)
 		}
 	}
-	return nil
+	if n.OutputSchema != nil {
+		if _, err := compileOutputSchema(n.OutputSchema); err != nil {
+			return fmt.Errorf("transform node: invalid output_schema: %w", err)
+		}
+	}
+	if _, err := transform.ParseTransformType(n.Language); err != nil {
+		return fmt.Errorf("transform node: %w", err)
+	}
+	if n.ItemErrorsOutput != "" {
+		if !validVariableNameRegex.MatchString(n.ItemErrorsOutput) {
+			return fmt.Errorf("transform node: invalid item errors output variable %q", n.ItemErrorsOutput)
+		}
+		if n.ItemErrorsOutput == n.OutputVariable {
+			return fmt.Errorf("transform node: item errors output variable %q conflicts with output variable", n.ItemErrorsOutput)
+		}
+		if !n.isIteratingJSONPath() {
+			return errors.New("transform node: item_errors_output requires a JSONPath expression with a [*] or [?()] selector")
+		}
+	}
+	return validateExpressionTimeout("transform", n.ExpressionTimeout)
+}
+
+// isIteratingJSONPath reports whether Expression is a JSONPath query that
+// iterates over an array with a [*] or [?()] selector
+func (n *TransformNode) isIteratingJSONPath() bool {
+	expression := strings.TrimSpace(n.Expression)
+	switch n.Language {
+	case "", "auto":
+		if !strings.HasPrefix(expression, "$") {
+			return false
+		}
+	case transform.TransformTypeJSONPath.String():
+	default:
+		return false
+	}
+	return strings.Contains(expression, "[*]") || strings.Contains(expression, "[?(")
+}
+
+// IsJQ reports whether Expression is a jq program, selected with Language
+// or written as jq(program)
+func (n *TransformNode) IsJQ() bool {
+	if n.Language == "" || n.Language == "auto" {
+		return transform.IsJQExpression(n.Expression)
+	}
+	return n.Language == transform.TransformTypeJQ.String()
 }
 
 // MarshalJSON implements custom JSON marshaling
 func (n *TransformNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		ID             string       `json:"id"`
-		Type           string       `json:"type"`
-		InputVariable  string       `json:"input_variable"`
-		Expression     string       `json:"expression"`
-		OutputVariable string       `json:"output_variable"`
-		Retry          *RetryPolicy `json:"retry,omitempty"`
+		ID                string                 `json:"id"`
+		Type              string                 `json:"type"`
+		InputVariable     string                 `json:"input_variable"`
+		Expression        string                 `json:"expression"`
+		OutputVariable    string                 `json:"output_variable"`
+		Retry             *RetryPolicy           `json:"retry,omitempty"`
+		ExpressionTimeout string                 `json:"expression_timeout,omitempty"`
+		OutputSchema      map[string]interface{} `json:"output_schema,omitempty"`
+		Language          string                 `json:"language,omitempty"`
+		ItemErrorsOutput  string                 `json:"item_errors_output,omitempty"`
 	}{
-		ID:             n.ID,
-		Type:           "transform",
-		InputVariable:  n.InputVariable,
-		Expression:     n.Expression,
-		OutputVariable: n.OutputVariable,
-		Retry:          n.Retry,
+		ID:                n.ID,
+		Type:              "transform",
+		InputVariable:     n.InputVariable,
+		Expression:        n.Expression,
+		OutputVariable:    n.OutputVariable,
+		Retry:             n.Retry,
+		ExpressionTimeout: n.ExpressionTimeout,
+		OutputSchema:      n.OutputSchema,
+		Language:          n.Language,
+		ItemErrorsOutput:  n.ItemErrorsOutput,
 	})
 }
 
@@ -332,6 +497,18 @@ func (n *TransformNode) GetConfiguration() map[string]interface{} {
 	if n.Retry != nil {
 		config["retry"] = n.Retry
 	}
+	if n.ExpressionTimeout != "" {
+		config["expression_timeout"] = n.ExpressionTimeout
+	}
+	if n.OutputSchema != nil {
+		config["output_schema"] = n.OutputSchema
+	}
+	if n.Language != "" {
+		config["language"] = n.Language
+	}
+	if n.ItemErrorsOutput != "" {
+		config["item_errors_output"] = n.ItemErrorsOutput
+	}
 	return config
 }
 
@@ -389,6 +566,9 @@ func (n *PassthroughNode) GetRetryPolicy() *RetryPolicy {
 type ConditionNode struct {
 	ID        string `json:"id" yaml:"id"`
 	Condition string `json:"condition" yaml:"condition"`
+	// ExpressionTimeout bounds the evaluation of Condition, overriding the
+	// workflow's
+	ExpressionTimeout string `json:"expression_timeout,omitempty" yaml:"expression_timeout,omitempty"`
 }
 
 // GetID returns the node ID
@@ -409,19 +589,21 @@ func (n *ConditionNode) Validate() error {
 	if n.Condition == "" {
 		return errors.New("condition node: empty condition")
 	}
-	return nil
+	return validateExpressionTimeout("condition", n.ExpressionTimeout)
 }
 
 // MarshalJSON implements custom JSON marshaling
 func (n *ConditionNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		ID        string `json:"id"`
-		Type      string `json:"type"`
-		Condition string `json:"condition"`
+		ID                string `json:"id"`
+		Type              string `json:"type"`
+		Condition         string `json:"condition"`
+		ExpressionTimeout string `json:"expression_timeout,omitempty"`
 	}{
-		ID:        n.ID,
-		Type:      "condition",
-		Condition: n.Condition,
+		ID:                n.ID,
+		Type:              "condition",
+		Condition:         n.Condition,
+		ExpressionTimeout: n.ExpressionTimeout,
 	})
 }
 
@@ -429,6 +611,9 @@ func (n *ConditionNode) MarshalJSON() ([]byte, error) {
 func (n *ConditionNode) GetConfiguration() map[string]interface{} {
 	config := make(map[string]interface{})
 	config["condition"] = n.Condition
+	if n.ExpressionTimeout != "" {
+		config["expression_timeout"] = n.ExpressionTimeout
+	}
 	return config
 }
 
@@ -437,11 +622,129 @@ func (n *ConditionNode) GetRetryPolicy() *RetryPolicy {
 	return nil
 }
 
+// SwitchDefaultCase is the edge condition of a switch node's default branch,
+// taken when no case matches.
+const SwitchDefaultCase = "default"
+
+// SwitchCase is one labeled branch of a switch node. The edge leaving the
+// switch for this case has the label as its condition.
+type SwitchCase struct {
+	Label     string `json:"label" yaml:"label"`
+	Condition string `json:"condition" yaml:"condition"`
+}
+
+// SwitchNode represents a multi-way branch: cases are evaluated in order and
+// execution follows the edge of the first case whose condition is true, or
+// the default edge when none is.
+type SwitchNode struct {
+	ID    string       `json:"id" yaml:"id"`
+	Cases []SwitchCase `json:"cases" yaml:"cases"`
+	// ExpressionTimeout bounds the evaluation of each case's condition,
+	// overriding the workflow's
+	ExpressionTimeout string `json:"expression_timeout,omitempty" yaml:"expression_timeout,omitempty"`
+}
+
+// GetID returns the node ID
+func (n *SwitchNode) GetID() string {
+	return n.ID
+}
+
+// Type returns the node type
+func (n *SwitchNode) Type() string {
+	return "switch"
+}
+
+// Validate checks if the switch node is valid
+func (n *SwitchNode) Validate() error {
+	if n.ID == "" {
+		return errors.New("switch node: empty node ID")
+	}
+	if len(n.Cases) == 0 {
+		return errors.New("switch node: no cases")
+	}
+	seen := make(map[string]bool, len(n.Cases))
+	for i, c := range n.Cases {
+		if c.Label == "" {
+			return fmt.Errorf("switch node: case %d has an empty label", i+1)
+		}
+		if c.Label == SwitchDefaultCase {
+			return fmt.Errorf("switch node: case label %q is reserved for the default branch", SwitchDefaultCase)
+		}
+		if seen[c.Label] {
+			return fmt.Errorf("switch node: duplicate case label %q", c.Label)
+		}
+		seen[c.Label] = true
+		if c.Condition == "" {
+			return fmt.Errorf("switch node: case %q has an empty condition", c.Label)
+		}
+	}
+	return validateExpressionTimeout("switch", n.ExpressionTimeout)
+}
+
+// HasCase reports whether label is one of the node's case labels.
+func (n *SwitchNode) HasCase(label string) bool {
+	for _, c := range n.Cases {
+		if c.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExhaustive reports whether some case always matches, so the default
+// branch can never be taken. Only a case whose condition is the literal
+// "true" is known to always match.
+func (n *SwitchNode) IsExhaustive() bool {
+	for _, c := range n.Cases {
+		if strings.TrimSpace(c.Condition) == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements custom JSON marshaling
+func (n *SwitchNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID                string       `json:"id"`
+		Type              string       `json:"type"`
+		Cases             []SwitchCase `json:"cases"`
+		ExpressionTimeout string       `json:"expression_timeout,omitempty"`
+	}{
+		ID:                n.ID,
+		Type:              "switch",
+		Cases:             n.Cases,
+		ExpressionTimeout: n.ExpressionTimeout,
+	})
+}
+
+// GetConfiguration returns the node configuration
+func (n *SwitchNode) GetConfiguration() map[string]interface{} {
+	config := make(map[string]interface{})
+	config["cases"] = n.Cases
+	if n.ExpressionTimeout != "" {
+		config["expression_timeout"] = n.ExpressionTimeout
+	}
+	return config
+}
+
+// GetRetryPolicy returns nil (switch nodes don't need retry)
+func (n *SwitchNode) GetRetryPolicy() *RetryPolicy {
+	return nil
+}
+
 // ParallelNode represents a node that executes multiple branches concurrently
 type ParallelNode struct {
 	ID            string     `json:"id" yaml:"id"`
 	Branches      [][]string `json:"branches" yaml:"branches"`
 	MergeStrategy string     `json:"merge_strategy" yaml:"merge_strategy"`
+	// Scope is shared (the default) or isolated: isolated branches keep the
+	// variables they write to themselves except those in Promote
+	Scope   string   `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Promote []string `json:"promote,omitempty" yaml:"promote,omitempty"`
+	// OnConflict decides a variable branches write different values to:
+	// last_branch (the default), error, or collect
+	OnConflict string `json:"on_conflict,omitempty" yaml:"on_conflict,omitempty"`
 }
 
 // GetID returns the node ID
@@ -468,6 +771,14 @@ func (n *ParallelNode) Validate() error {
 	if n.MergeStrategy != "" && n.MergeStrategy != "wait_all" && n.MergeStrategy != "wait_any" && n.MergeStrategy != "wait_first" {
 		return fmt.Errorf("parallel node: invalid merge strategy: %s", n.MergeStrategy)
 	}
+	if err := validateScope("parallel", n.Scope, n.Promote); err != nil {
+		return err
+	}
+	switch n.OnConflict {
+	case "", ConflictLastBranch, ConflictError, ConflictCollect:
+	default:
+		return fmt.Errorf("parallel node: invalid on_conflict policy: %s (must be %s, %s, or %s)", n.OnConflict, ConflictLastBranch, ConflictError, ConflictCollect)
+	}
 	return nil
 }
 
@@ -478,11 +789,17 @@ func (n *ParallelNode) MarshalJSON() ([]byte, error) {
 		Type          string     `json:"type"`
 		Branches      [][]string `json:"branches"`
 		MergeStrategy string     `json:"merge_strategy"`
+		Scope         string     `json:"scope,omitempty"`
+		Promote       []string   `json:"promote,omitempty"`
+		OnConflict    string     `json:"on_conflict,omitempty"`
 	}{
 		ID:            n.ID,
 		Type:          "parallel",
 		Branches:      n.Branches,
 		MergeStrategy: n.MergeStrategy,
+		Scope:         n.Scope,
+		Promote:       n.Promote,
+		OnConflict:    n.OnConflict,
 	})
 }
 
@@ -491,6 +808,15 @@ func (n *ParallelNode) GetConfiguration() map[string]interface{} {
 	config := make(map[string]interface{})
 	config["branches"] = n.Branches
 	config["merge_strategy"] = n.MergeStrategy
+	if n.Scope != "" {
+		config["scope"] = n.Scope
+	}
+	if len(n.Promote) > 0 {
+		config["promote"] = n.Promote
+	}
+	if n.OnConflict != "" {
+		config["on_conflict"] = n.OnConflict
+	}
 	return config
 }
 
@@ -506,6 +832,14 @@ type LoopNode struct {
 	ItemVariable   string   `json:"item_variable" yaml:"item_variable"`
 	Body           []string `json:"body" yaml:"body"`
 	BreakCondition string   `json:"break_condition,omitempty" yaml:"break_condition,omitempty"`
+	// Scope is shared (the default) or isolated: each isolated iteration
+	// starts from the variables as they were before the loop, and only the
+	// variables in Promote outlive the iteration
+	Scope   string   `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Promote []string `json:"promote,omitempty" yaml:"promote,omitempty"`
+	// ExpressionTimeout bounds each evaluation of BreakCondition,
+	// overriding the workflow's
+	ExpressionTimeout string `json:"expression_timeout,omitempty" yaml:"expression_timeout,omitempty"`
 }
 
 // GetID returns the node ID
@@ -532,25 +866,34 @@ func (n *LoopNode) Validate() error {
 	if len(n.Body) == 0 {
 		return errors.New("loop node: empty body")
 	}
-	return nil
+	if err := validateExpressionTimeout("loop", n.ExpressionTimeout); err != nil {
+		return err
+	}
+	return validateScope("loop", n.Scope, n.Promote)
 }
 
 // MarshalJSON implements custom JSON marshaling
 func (n *LoopNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		ID             string   `json:"id"`
-		Type           string   `json:"type"`
-		Collection     string   `json:"collection"`
-		ItemVariable   string   `json:"item_variable"`
-		Body           []string `json:"body"`
-		BreakCondition string   `json:"break_condition,omitempty"`
+		ID                string   `json:"id"`
+		Type              string   `json:"type"`
+		Collection        string   `json:"collection"`
+		ItemVariable      string   `json:"item_variable"`
+		Body              []string `json:"body"`
+		BreakCondition    string   `json:"break_condition,omitempty"`
+		Scope             string   `json:"scope,omitempty"`
+		Promote           []string `json:"promote,omitempty"`
+		ExpressionTimeout string   `json:"expression_timeout,omitempty"`
 	}{
-		ID:             n.ID,
-		Type:           "loop",
-		Collection:     n.Collection,
-		ItemVariable:   n.ItemVariable,
-		Body:           n.Body,
-		BreakCondition: n.BreakCondition,
+		ID:                n.ID,
+		Type:              "loop",
+		Collection:        n.Collection,
+		ItemVariable:      n.ItemVariable,
+		Body:              n.Body,
+		BreakCondition:    n.BreakCondition,
+		Scope:             n.Scope,
+		Promote:           n.Promote,
+		ExpressionTimeout: n.ExpressionTimeout,
 	})
 }
 
@@ -563,6 +906,15 @@ func (n *LoopNode) GetConfiguration() map[string]interface{} {
 	if n.BreakCondition != "" {
 		config["break_condition"] = n.BreakCondition
 	}
+	if n.Scope != "" {
+		config["scope"] = n.Scope
+	}
+	if len(n.Promote) > 0 {
+		config["promote"] = n.Promote
+	}
+	if n.ExpressionTimeout != "" {
+		config["expression_timeout"] = n.ExpressionTimeout
+	}
 	return config
 }
 
@@ -571,6 +923,425 @@ func (n *LoopNode) GetRetryPolicy() *RetryPolicy {
 	return nil
 }
 
+// TryNode wraps a subgraph in an error scope: its body nodes run in order,
+// and if one fails the try node fails with that error, so its error edges
+// (usually to a CatchNode) handle the failure.
+type TryNode struct {
+	ID   string   `json:"id" yaml:"id"`
+	Body []string `json:"body" yaml:"body"`
+}
+
+// GetID returns the node ID
+func (n *TryNode) GetID() string {
+	return n.ID
+}
+
+// Type returns the node type
+func (n *TryNode) Type() string {
+	return "try"
+}
+
+// Validate checks if the try node is valid
+func (n *TryNode) Validate() error {
+	if n.ID == "" {
+		return errors.New("try node: empty node ID")
+	}
+	if len(n.Body) == 0 {
+		return errors.New("try node: empty body")
+	}
+	return nil
+}
+
+// MarshalJSON implements custom JSON marshaling
+func (n *TryNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID   string   `json:"id"`
+		Type string   `json:"type"`
+		Body []string `json:"body"`
+	}{
+		ID:   n.ID,
+		Type: "try",
+		Body: n.Body,
+	})
+}
+
+// GetConfiguration returns the node configuration
+func (n *TryNode) GetConfiguration() map[string]interface{} {
+	config := make(map[string]interface{})
+	config["body"] = n.Body
+	return config
+}
+
+// GetRetryPolicy returns nil (try nodes don't need retry)
+func (n *TryNode) GetRetryPolicy() *RetryPolicy {
+	return nil
+}
+
+// CatchNode handles a failure reached through an error edge: it captures
+// the error object of the node that failed (node_id, node_type, type and
+// message) into ErrorVariable for the recovery path.
+type CatchNode struct {
+	ID            string `json:"id" yaml:"id"`
+	ErrorVariable string `json:"error_variable" yaml:"error_variable"`
+}
+
+// GetID returns the node ID
+func (n *CatchNode) GetID() string {
+	return n.ID
+}
+
+// Type returns the node type
+func (n *CatchNode) Type() string {
+	return "catch"
+}
+
+// Validate checks if the catch node is valid
+func (n *CatchNode) Validate() error {
+	if n.ID == "" {
+		return errors.New("catch node: empty node ID")
+	}
+	if n.ErrorVariable == "" {
+		return errors.New("catch node: empty error variable")
+	}
+	return nil
+}
+
+// MarshalJSON implements custom JSON marshaling
+func (n *CatchNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID            string `json:"id"`
+		Type          string `json:"type"`
+		ErrorVariable string `json:"error_variable"`
+	}{
+		ID:            n.ID,
+		Type:          "catch",
+		ErrorVariable: n.ErrorVariable,
+	})
+}
+
+// GetConfiguration returns the node configuration
+func (n *CatchNode) GetConfiguration() map[string]interface{} {
+	config := make(map[string]interface{})
+	config["error_variable"] = n.ErrorVariable
+	return config
+}
+
+// GetRetryPolicy returns nil (catch nodes don't need retry)
+func (n *CatchNode) GetRetryPolicy() *RetryPolicy {
+	return nil
+}
+
+// DelayNode pauses the execution path for Duration (e.g. "30s", "5m") or
+// until the Until timestamp (RFC 3339). Either may be a ${var} template,
+// resolved when the node runs.
+type DelayNode struct {
+	ID       string `json:"id" yaml:"id"`
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Until    string `json:"until,omitempty" yaml:"until,omitempty"`
+}
+
+// GetID returns the node ID
+func (n *DelayNode) GetID() string {
+	return n.ID
+}
+
+// Type returns the node type
+func (n *DelayNode) Type() string {
+	return "delay"
+}
+
+// Validate checks if the delay node is valid
+func (n *DelayNode) Validate() error {
+	if n.ID == "" {
+		return errors.New("delay node: empty node ID")
+	}
+	if (n.Duration == "") == (n.Until == "") {
+		return errors.New("delay node: exactly one of duration and until is required")
+	}
+	if n.Duration != "" && !containsTemplate(n.Duration) {
+		if _, err := ParseDelayDuration(n.Duration); err != nil {
+			return fmt.Errorf("delay node: %w", err)
+		}
+	}
+	if n.Until != "" && !containsTemplate(n.Until) {
+		if _, err := time.Parse(time.RFC3339, n.Until); err != nil {
+			return fmt.Errorf("delay node: invalid until timestamp %q (expected RFC 3339)", n.Until)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements custom JSON marshaling
+func (n *DelayNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID       string `json:"id"`
+		Type     string `json:"type"`
+		Duration string `json:"duration,omitempty"`
+		Until    string `json:"until,omitempty"`
+	}{
+		ID:       n.ID,
+		Type:     "delay",
+		Duration: n.Duration,
+		Until:    n.Until,
+	})
+}
+
+// GetConfiguration returns the node configuration
+func (n *DelayNode) GetConfiguration() map[string]interface{} {
+	config := make(map[string]interface{})
+	config["duration"] = n.Duration
+	config["until"] = n.Until
+	return config
+}
+
+// GetRetryPolicy returns nil (delay nodes don't need retry)
+func (n *DelayNode) GetRetryPolicy() *RetryPolicy {
+	return nil
+}
+
+// ParseDelayDuration parses a delay or timeout such as "30s" or "1h30m".
+// Negative and zero durations are rejected.
+func ParseDelayDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %q must be positive", s)
+	}
+	return d, nil
+}
+
+// validateExpressionTimeout checks a node's expression_timeout, if set
+func validateExpressionTimeout(nodeType, timeout string) error {
+	if timeout == "" {
+		return nil
+	}
+	if _, err := ParseDelayDuration(timeout); err != nil {
+		return fmt.Errorf("%s node: expression_timeout: %w", nodeType, err)
+	}
+	return nil
+}
+
+// Approval actions, for the decision an ApprovalNode takes on timeout
+const (
+	ApprovalApprove = "approve"
+	ApprovalReject  = "reject"
+)
+
+// ApprovalNode pauses its execution path until a person approves or rejects
+// it, from the TUI execution monitor or the approval API. A rejection fails
+// the node, so its error edges can handle it. Without a Timeout the node
+// waits until a decision is made; after Timeout it takes DefaultAction
+// (reject unless set to approve). The decision is stored in OutputVariable
+// if set.
+type ApprovalNode struct {
+	ID             string `json:"id" yaml:"id"`
+	Message        string `json:"message,omitempty" yaml:"message,omitempty"`
+	Timeout        string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	DefaultAction  string `json:"default_action,omitempty" yaml:"default_action,omitempty"`
+	OutputVariable string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// GetID returns the node ID
+func (n *ApprovalNode) GetID() string {
+	return n.ID
+}
+
+// Type returns the node type
+func (n *ApprovalNode) Type() string {
+	return "approval"
+}
+
+// Validate checks if the approval node is valid
+func (n *ApprovalNode) Validate() error {
+	if n.ID == "" {
+		return errors.New("approval node: empty node ID")
+	}
+	if n.Timeout != "" && !containsTemplate(n.Timeout) {
+		if _, err := ParseDelayDuration(n.Timeout); err != nil {
+			return fmt.Errorf("approval node: timeout: %w", err)
+		}
+	}
+	switch n.DefaultAction {
+	case "", ApprovalApprove, ApprovalReject:
+	default:
+		return fmt.Errorf("approval node: invalid default action %q (must be approve or reject)", n.DefaultAction)
+	}
+	if n.DefaultAction != "" && n.Timeout == "" {
+		return errors.New("approval node: default action requires a timeout")
+	}
+	return nil
+}
+
+// TimeoutAction returns the decision taken when the timeout expires
+func (n *ApprovalNode) TimeoutAction() string {
+	if n.DefaultAction == ApprovalApprove {
+		return ApprovalApprove
+	}
+	return ApprovalReject
+}
+
+// MarshalJSON implements custom JSON marshaling
+func (n *ApprovalNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID             string `json:"id"`
+		Type           string `json:"type"`
+		Message        string `json:"message,omitempty"`
+		Timeout        string `json:"timeout,omitempty"`
+		DefaultAction  string `json:"default_action,omitempty"`
+		OutputVariable string `json:"output,omitempty"`
+	}{
+		ID:             n.ID,
+		Type:           "approval",
+		Message:        n.Message,
+		Timeout:        n.Timeout,
+		DefaultAction:  n.DefaultAction,
+		OutputVariable: n.OutputVariable,
+	})
+}
+
+// GetConfiguration returns the node configuration
+func (n *ApprovalNode) GetConfiguration() map[string]interface{} {
+	config := make(map[string]interface{})
+	config["message"] = n.Message
+	config["timeout"] = n.Timeout
+	config["default_action"] = n.DefaultAction
+	config["output"] = n.OutputVariable
+	return config
+}
+
+// GetRetryPolicy returns nil (approval nodes don't need retry)
+func (n *ApprovalNode) GetRetryPolicy() *RetryPolicy {
+	return nil
+}
+
+// DefaultBatchConcurrency is how many calls a BatchToolNode makes at once
+// when MaxConcurrency is not set
+const DefaultBatchConcurrency = 5
+
+// BatchToolNode calls one MCP tool for every element of a collection, with
+// at most MaxConcurrency calls in flight. Parameters are templates evaluated
+// per element, with the element bound to ItemVariable. The results are
+// stored in OutputVariable in collection order. A failed call fails the
+// node, unless ErrorVariable is set: then the node completes, and
+// ErrorVariable holds each call's error message (nil for successful calls)
+// in collection order, with nil results for the failed calls.
+type BatchToolNode struct {
+	ID             string            `json:"id" yaml:"id"`
+	ServerID       string            `json:"server_id" yaml:"server_id"`
+	ToolName       string            `json:"tool_name" yaml:"tool_name"`
+	Collection     string            `json:"collection" yaml:"collection"`
+	ItemVariable   string            `json:"item_variable" yaml:"item_variable"`
+	Parameters     map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	MaxConcurrency int               `json:"max_concurrency,omitempty" yaml:"max_concurrency,omitempty"`
+	OutputVariable string            `json:"output_variable" yaml:"output_variable"`
+	ErrorVariable  string            `json:"error_variable,omitempty" yaml:"error_variable,omitempty"`
+}
+
+// GetID returns the node ID
+func (n *BatchToolNode) GetID() string {
+	return n.ID
+}
+
+// Type returns the node type
+func (n *BatchToolNode) Type() string {
+	return "batch_tool"
+}
+
+// Validate checks if the batch tool node is valid
+func (n *BatchToolNode) Validate() error {
+	if n.ID == "" {
+		return errors.New("batch_tool node: empty node ID")
+	}
+	if n.ServerID == "" {
+		return errors.New("batch_tool node: empty server ID")
+	}
+	if n.ToolName == "" {
+		return errors.New("batch_tool node: empty tool name")
+	}
+	if n.Collection == "" {
+		return errors.New("batch_tool node: empty collection")
+	}
+	if !validVariableNameRegex.MatchString(n.ItemVariable) {
+		return fmt.Errorf("batch_tool node: invalid item variable %q", n.ItemVariable)
+	}
+	if n.OutputVariable == "" {
+		return errors.New("batch_tool node: empty output variable")
+	}
+	if n.ErrorVariable != "" && n.ErrorVariable == n.OutputVariable {
+		return fmt.Errorf("batch_tool node: error variable %q conflicts with output variable", n.ErrorVariable)
+	}
+	if n.MaxConcurrency < 0 {
+		return errors.New("batch_tool node: max_concurrency cannot be negative")
+	}
+	return nil
+}
+
+// Concurrency returns how many calls the node makes at once
+func (n *BatchToolNode) Concurrency() int {
+	if n.MaxConcurrency > 0 {
+		return n.MaxConcurrency
+	}
+	return DefaultBatchConcurrency
+}
+
+// MarshalJSON implements custom JSON marshaling
+func (n *BatchToolNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID             string            `json:"id"`
+		Type           string            `json:"type"`
+		ServerID       string            `json:"server_id"`
+		ToolName       string            `json:"tool_name"`
+		Collection     string            `json:"collection"`
+		ItemVariable   string            `json:"item_variable"`
+		Parameters     map[string]string `json:"parameters,omitempty"`
+		MaxConcurrency int               `json:"max_concurrency,omitempty"`
+		OutputVariable string            `json:"output_variable"`
+		ErrorVariable  string            `json:"error_variable,omitempty"`
+	}{
+		ID:             n.ID,
+		Type:           "batch_tool",
+		ServerID:       n.ServerID,
+		ToolName:       n.ToolName,
+		Collection:     n.Collection,
+		ItemVariable:   n.ItemVariable,
+		Parameters:     n.Parameters,
+		MaxConcurrency: n.MaxConcurrency,
+		OutputVariable: n.OutputVariable,
+		ErrorVariable:  n.ErrorVariable,
+	})
+}
+
+// GetConfiguration returns the node configuration
+func (n *BatchToolNode) GetConfiguration() map[string]interface{} {
+	config := make(map[string]interface{})
+	config["server"] = n.ServerID
+	config["tool"] = n.ToolName
+	config["collection"] = n.Collection
+	config["item_variable"] = n.ItemVariable
+	config["output_variable"] = n.OutputVariable
+	if len(n.Parameters) > 0 {
+		params := make(map[string]interface{})
+		for k, v := range n.Parameters {
+			params[k] = v
+		}
+		config["parameters"] = params
+	}
+	if n.MaxConcurrency > 0 {
+		config["max_concurrency"] = n.MaxConcurrency
+	}
+	if n.ErrorVariable != "" {
+		config["error_variable"] = n.ErrorVariable
+	}
+	return config
+}
+
+// GetRetryPolicy returns nil (each call's failure is reported per element)
+func (n *BatchToolNode) GetRetryPolicy() *RetryPolicy {
+	return nil
+}
+
 // UnmarshalNode unmarshals a JSON node into the appropriate concrete type
 func UnmarshalNode(data []byte) (Node, error) {
 	// First unmarshal to get the type
@@ -613,6 +1384,12 @@ func UnmarshalNode(data []byte) (Node, error) {
 			return nil, err
 		}
 		return &node, nil
+	case "switch":
+		var node SwitchNode
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
 	case "passthrough":
 		var node PassthroughNode
 		if err := json.Unmarshal(data, &node); err != nil {
@@ -631,6 +1408,36 @@ func UnmarshalNode(data []byte) (Node, error) {
 			return nil, err
 		}
 		return &node, nil
+	case "try":
+		var node TryNode
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+	case "catch":
+		var node CatchNode
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+	case "delay":
+		var node DelayNode
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+	case "approval":
+		var node ApprovalNode
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+	case "batch_tool":
+		var node BatchToolNode
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
 	default:
 		return nil, fmt.Errorf("unknown node type: %s", temp.Type)
 	}
@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxNoteLength bounds node notes, which are drawn on the canvas
+const maxNoteLength = 1000
+
+// SetNote attaches a note to a node, documenting its intent for the people
+// editing the workflow. Notes do not change how the workflow runs. An empty
+// note removes the node's note.
+func (w *Workflow) SetNote(nodeID, text string) error {
+	found := false
+	for _, node := range w.Nodes {
+		if node.GetID() == nodeID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		if _, exists := w.Metadata.Notes[nodeID]; exists {
+			delete(w.Metadata.Notes, nodeID)
+			w.Metadata.LastModified = time.Now()
+		}
+		return nil
+	}
+	if len(text) > maxNoteLength {
+		return fmt.Errorf("note exceeds maximum length of %d characters", maxNoteLength)
+	}
+	for _, r := range text {
+		if !unicode.IsPrint(r) && r != '\n' {
+			return errors.New("note contains non-printable characters")
+		}
+	}
+
+	if w.Metadata.Notes == nil {
+		w.Metadata.Notes = make(map[string]string)
+	}
+	w.Metadata.Notes[nodeID] = text
+	w.Metadata.LastModified = time.Now()
+	return nil
+}
+
+// Note returns a node's note, or "" if it has none
+func (w *Workflow) Note(nodeID string) string {
+	return w.Metadata.Notes[nodeID]
+}
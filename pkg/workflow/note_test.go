@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorkflow_SetNote(t *testing.T) {
+	wf := newGroupTestWorkflow(t)
+
+	if err := wf.SetNote("fetch", "  Reads the API page by page\nRetries on 429  "); err != nil {
+		t.Fatalf("SetNote() error = %v", err)
+	}
+	if got := wf.Note("fetch"); got != "Reads the API page by page\nRetries on 429" {
+		t.Errorf("Note() = %q", got)
+	}
+
+	tests := []struct {
+		name   string
+		nodeID string
+		text   string
+		want   string
+	}{
+		{"unknown node", "missing", "note", "node not found"},
+		{"too long", "parse", strings.Repeat("x", maxNoteLength+1), "maximum length"},
+		{"control characters", "parse", "bell\a", "non-printable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wf.SetNote(tt.nodeID, tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SetNote() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// Notes are saved with the workflow
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parsed.Note("fetch") != wf.Note("fetch") {
+		t.Errorf("parsed note = %q", parsed.Note("fetch"))
+	}
+
+	// An empty note removes it, as does removing the node
+	if err := wf.SetNote("parse", "Flattens the pages"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wf.SetNote("parse", " "); err != nil {
+		t.Fatalf("SetNote() error = %v", err)
+	}
+	if err := wf.RemoveNode("fetch"); err != nil {
+		t.Fatal(err)
+	}
+	if len(wf.Metadata.Notes) != 0 {
+		t.Errorf("notes = %v, want none", wf.Metadata.Notes)
+	}
+}
@@ -0,0 +1,96 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// maxReportedMismatches caps the mismatches a SchemaMismatchError lists
+const maxReportedMismatches = 20
+
+// SchemaMismatch is one way a value fails to match a JSON Schema
+type SchemaMismatch struct {
+	// Field is the path of the mismatching value, such as items.0.id, or
+	// (root) for the value itself
+	Field string `json:"field"`
+	// Description says what the schema expects there
+	Description string `json:"description"`
+	// Value is the mismatching value
+	Value interface{} `json:"value,omitempty"`
+}
+
+// SchemaMismatchError reports a transform result that does not match the
+// node's output schema
+type SchemaMismatchError struct {
+	NodeID     string
+	Mismatches []SchemaMismatch
+}
+
+// Error implements the error interface, listing the mismatches
+func (e *SchemaMismatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "transform node %s: output does not match output_schema (%d mismatches)", e.NodeID, len(e.Mismatches))
+	for i, mismatch := range e.Mismatches {
+		if i == maxReportedMismatches {
+			fmt.Fprintf(&b, "; and %d more", len(e.Mismatches)-i)
+			break
+		}
+		fmt.Fprintf(&b, "; %s: %s", mismatch.Field, mismatch.Description)
+	}
+	return b.String()
+}
+
+// compiledSchemas caches output schemas by their JSON encoding, as a
+// transform in a loop is checked once per iteration
+var compiledSchemas sync.Map
+
+// compileOutputSchema compiles a JSON Schema given as decoded YAML or JSON
+func compileOutputSchema(schema map[string]interface{}) (*gojsonschema.Schema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	if compiled, ok := compiledSchemas.Load(string(data)); ok {
+		return compiled.(*gojsonschema.Schema), nil
+	}
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, err
+	}
+	compiledSchemas.Store(string(data), compiled)
+	return compiled, nil
+}
+
+// ValidateOutput checks a result of the transform against its output
+// schema. A mismatch is reported as a *SchemaMismatchError listing every
+// field that does not match; nodes without a schema accept any result.
+func (n *TransformNode) ValidateOutput(result interface{}) error {
+	if n.OutputSchema == nil {
+		return nil
+	}
+	schema, err := compileOutputSchema(n.OutputSchema)
+	if err != nil {
+		return fmt.Errorf("transform node %s: invalid output_schema: %w", n.ID, err)
+	}
+	validation, err := schema.Validate(gojsonschema.NewGoLoader(result))
+	if err != nil {
+		return fmt.Errorf("transform node %s: checking output: %w", n.ID, err)
+	}
+	if validation.Valid() {
+		return nil
+	}
+
+	mismatchErr := &SchemaMismatchError{NodeID: n.ID}
+	for _, desc := range validation.Errors() {
+		mismatchErr.Mismatches = append(mismatchErr.Mismatches, SchemaMismatch{
+			Field:       desc.Field(),
+			Description: desc.Description(),
+			Value:       desc.Value(),
+		})
+	}
+	return mismatchErr
+}
@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// userSchema expects an object with a string email and a positive age
+var userSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"email", "age"},
+	"properties": map[string]interface{}{
+		"email": map[string]interface{}{"type": "string"},
+		"age":   map[string]interface{}{"type": "integer", "minimum": 1},
+	},
+}
+
+func TestTransformNode_ValidateOutput(t *testing.T) {
+	node := &TransformNode{ID: "shape", InputVariable: "raw", Expression: "$.user", OutputVariable: "user", OutputSchema: userSchema}
+
+	if err := node.ValidateOutput(map[string]interface{}{"email": "a@example.com", "age": 30}); err != nil {
+		t.Errorf("ValidateOutput() of matching result = %v", err)
+	}
+
+	err := node.ValidateOutput(map[string]interface{}{"age": "thirty"})
+	var mismatchErr *SchemaMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("ValidateOutput() = %v, want *SchemaMismatchError", err)
+	}
+	if mismatchErr.NodeID != "shape" || len(mismatchErr.Mismatches) != 2 {
+		t.Fatalf("mismatches = %+v, want 2 for node shape", mismatchErr.Mismatches)
+	}
+	for _, want := range []string{"transform node shape", "(root): email is required", "age: Invalid type"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err.Error(), want)
+		}
+	}
+
+	node.OutputSchema = nil
+	if err := node.ValidateOutput("anything"); err != nil {
+		t.Errorf("ValidateOutput() without schema = %v", err)
+	}
+}
+
+func TestTransformNode_ValidateOutputSchema(t *testing.T) {
+	node := &TransformNode{ID: "shape", InputVariable: "raw", Expression: "$.user", OutputVariable: "user", OutputSchema: userSchema}
+	if err := node.Validate(); err != nil {
+		t.Errorf("Validate() with valid schema = %v", err)
+	}
+
+	node.OutputSchema = map[string]interface{}{"type": 42}
+	err := node.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid output_schema") {
+		t.Errorf("Validate() with invalid schema = %v, want invalid output_schema", err)
+	}
+}
+
+func TestSchemaMismatchError_CapsReport(t *testing.T) {
+	err := &SchemaMismatchError{NodeID: "shape"}
+	for i := 0; i < maxReportedMismatches+5; i++ {
+		err.Mismatches = append(err.Mismatches, SchemaMismatch{Field: "items", Description: "invalid"})
+	}
+	if got := strings.Count(err.Error(), "items: invalid"); got != maxReportedMismatches {
+		t.Errorf("report lists %d mismatches, want %d", got, maxReportedMismatches)
+	}
+	if !strings.HasSuffix(err.Error(), "and 5 more") {
+		t.Errorf("report %q does not end with the remaining count", err.Error())
+	}
+}
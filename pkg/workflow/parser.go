@@ -8,77 +8,127 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// yamlWorkflow represents the YAML structure before conversion to domain objects
+// yamlWorkflow represents the YAML structure before conversion to domain
+// objects. JSON and Protobuf documents share it, with the same field names.
 type yamlWorkflow struct {
-	Version     string             `yaml:"version"`
-	Name        string             `yaml:"name"`
-	Description string             `yaml:"description,omitempty"`
-	Metadata    *WorkflowMetadata  `yaml:"metadata,omitempty"`
-	Variables   []yamlVariable     `yaml:"variables,omitempty"`
-	Servers     []yamlServerConfig `yaml:"servers,omitempty"`
-	Nodes       []yamlNode         `yaml:"nodes,omitempty"`
-	Edges       []yamlEdge         `yaml:"edges,omitempty"`
+	SchemaVersion int                `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+	Version       string             `json:"version" yaml:"version"`
+	Name          string             `json:"name" yaml:"name"`
+	Description   string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Metadata      *WorkflowMetadata  `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Variables     []yamlVariable     `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Servers       []yamlServerConfig `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Nodes         []yamlNode         `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Edges         []yamlEdge         `json:"edges,omitempty" yaml:"edges,omitempty"`
 }
 
 // yamlVariable represents a variable in YAML before type conversion
 type yamlVariable struct {
-	Name         string      `yaml:"name"`
-	Type         string      `yaml:"type"`
-	DefaultValue interface{} `yaml:"default,omitempty"`
-	Description  string      `yaml:"description,omitempty"`
+	Name         string      `json:"name" yaml:"name"`
+	Type         string      `json:"type" yaml:"type"`
+	Required     bool        `json:"required,omitempty" yaml:"required,omitempty"`
+	DefaultValue interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	Description  string      `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
 // yamlServerConfig represents a server config in YAML
 type yamlServerConfig struct {
-	ID            string            `yaml:"id"`
-	Name          string            `yaml:"name,omitempty"`
-	Command       string            `yaml:"command,omitempty"`
-	Args          []string          `yaml:"args,omitempty"`
-	Transport     string            `yaml:"transport,omitempty"`
-	Env           map[string]string `yaml:"env,omitempty"`
-	CredentialRef string            `yaml:"credential_ref,omitempty"`
-	URL           string            `yaml:"url,omitempty"`
-	Headers       map[string]string `yaml:"headers,omitempty"`
+	ID            string            `json:"id" yaml:"id"`
+	Name          string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Command       string            `json:"command,omitempty" yaml:"command,omitempty"`
+	Args          []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Transport     string            `json:"transport,omitempty" yaml:"transport,omitempty"`
+	Env           map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	CredentialRef string            `json:"credential_ref,omitempty" yaml:"credential_ref,omitempty"`
+	URL           string            `json:"url,omitempty" yaml:"url,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Auth          *ServerAuth       `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Limits        *ServerLimits     `json:"limits,omitempty" yaml:"limits,omitempty"`
+	Tags          []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
 }
 
 // yamlNode represents a node in YAML with type-specific fields
 type yamlNode struct {
-	ID   string `yaml:"id"`
-	Type string `yaml:"type"`
+	ID   string `json:"id" yaml:"id"`
+	Type string `json:"type" yaml:"type"`
 
 	// EndNode fields
-	Return string `yaml:"return,omitempty"`
+	Return string `json:"return,omitempty" yaml:"return,omitempty"`
 
 	// MCPToolNode fields
-	Server     string            `yaml:"server,omitempty"`
-	Tool       string            `yaml:"tool,omitempty"`
-	Parameters map[string]string `yaml:"parameters,omitempty"`
-	Output     string            `yaml:"output,omitempty"`
+	Server     string            `json:"server,omitempty" yaml:"server,omitempty"`
+	Tool       string            `json:"tool,omitempty" yaml:"tool,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Output     string            `json:"output,omitempty" yaml:"output,omitempty"`
+
+	// MCPToolNode content routing (content type -> variable)
+	ContentOutputs map[string]string `json:"content_outputs,omitempty" yaml:"content_outputs,omitempty"`
+
+	// MCPToolNode result cache lifetime
+	CacheTTL string `json:"cache_ttl,omitempty" yaml:"cache_ttl,omitempty"`
+
+	// MCPToolNode variable for streamed partial output
+	StreamOutput string `json:"stream_output,omitempty" yaml:"stream_output,omitempty"`
 
 	// TransformNode fields
-	Input      string `yaml:"input,omitempty"`
-	Expression string `yaml:"expression,omitempty"`
+	Input            string                 `json:"input,omitempty" yaml:"input,omitempty"`
+	Expression       string                 `json:"expression,omitempty" yaml:"expression,omitempty"`
+	OutputSchema     map[string]interface{} `json:"output_schema,omitempty" yaml:"output_schema,omitempty"`
+	Language         string                 `json:"language,omitempty" yaml:"language,omitempty"`
+	ItemErrorsOutput string                 `json:"item_errors_output,omitempty" yaml:"item_errors_output,omitempty"`
 
 	// ConditionNode fields
-	Condition string `yaml:"condition,omitempty"`
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+
+	// Transform, condition, switch, and loop node expression timeout
+	ExpressionTimeout string `json:"expression_timeout,omitempty" yaml:"expression_timeout,omitempty"`
+
+	// SwitchNode fields
+	Cases []SwitchCase `json:"cases,omitempty" yaml:"cases,omitempty"`
 
 	// ParallelNode fields
-	Branches [][]string `yaml:"branches,omitempty"`
-	Merge    string     `yaml:"merge_strategy,omitempty"`
+	Branches [][]string `json:"branches,omitempty" yaml:"branches,omitempty"`
+	Merge    string     `json:"merge_strategy,omitempty" yaml:"merge_strategy,omitempty"`
+
+	// Variable scope of loop iterations and parallel branches; on_conflict
+	// is the parallel node's policy for conflicting branch writes
+	Scope      string   `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Promote    []string `json:"promote,omitempty" yaml:"promote,omitempty"`
+	OnConflict string   `json:"on_conflict,omitempty" yaml:"on_conflict,omitempty"`
 
 	// LoopNode fields
-	Collection     string   `yaml:"collection,omitempty"`
-	Item           string   `yaml:"item,omitempty"`
-	Body           []string `yaml:"body,omitempty"`
-	BreakCondition string   `yaml:"break_condition,omitempty"`
+	Collection     string   `json:"collection,omitempty" yaml:"collection,omitempty"`
+	Item           string   `json:"item,omitempty" yaml:"item,omitempty"`
+	Body           []string `json:"body,omitempty" yaml:"body,omitempty"`
+	BreakCondition string   `json:"break_condition,omitempty" yaml:"break_condition,omitempty"`
+
+	// CatchNode fields (TryNode uses Body)
+	ErrorVariable string `json:"error_variable,omitempty" yaml:"error_variable,omitempty"`
+
+	// DelayNode fields
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Until    string `json:"until,omitempty" yaml:"until,omitempty"`
+
+	// ApprovalNode fields (output uses Output)
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+	Timeout       string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	DefaultAction string `json:"default_action,omitempty" yaml:"default_action,omitempty"`
+
+	// BatchToolNode fields (server, tool, parameters, output, collection,
+	// item, and error_variable are shared)
+	MaxConcurrency int `json:"max_concurrency,omitempty" yaml:"max_concurrency,omitempty"`
+
+	// Lint and validation warning rules silenced on this node
+	Suppress []string `json:"suppress,omitempty" yaml:"suppress,omitempty"`
 }
 
 // yamlEdge represents an edge in YAML
 type yamlEdge struct {
-	From      string `yaml:"from"`
-	To        string `yaml:"to"`
-	Condition string `yaml:"condition,omitempty"`
-	Label     string `yaml:"label,omitempty"`
+	From      string `json:"from" yaml:"from"`
+	To        string `json:"to" yaml:"to"`
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+	Label     string `json:"label,omitempty" yaml:"label,omitempty"`
+	OnError   bool   `json:"on_error,omitempty" yaml:"on_error,omitempty"`
 }
 
 // Parse parses a workflow from YAML bytes
@@ -92,6 +142,24 @@ func Parse(yamlBytes []byte) (*Workflow, error) {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
+	// Upgrade documents written with an older schema before reading them
+	if yw.SchemaVersion != CurrentSchemaVersion {
+		migrated, _, err := Migrate(yamlBytes)
+		if err != nil {
+			return nil, err
+		}
+		yw = yamlWorkflow{}
+		if err := yaml.Unmarshal(migrated, &yw); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	}
+
+	return fromDocument(yw)
+}
+
+// fromDocument builds a workflow from a decoded workflow document, whichever
+// encoding it was read from
+func fromDocument(yw yamlWorkflow) (*Workflow, error) {
 	// Validate required fields
 	if yw.Version == "" {
 		return nil, errors.New("missing required field: version")
@@ -122,6 +190,7 @@ func Parse(yamlBytes []byte) (*Workflow, error) {
 		variable := &Variable{
 			Name:         yv.Name,
 			Type:         yv.Type,
+			Required:     yv.Required,
 			DefaultValue: yv.DefaultValue,
 			Description:  yv.Description,
 		}
@@ -142,6 +211,9 @@ func Parse(yamlBytes []byte) (*Workflow, error) {
 			CredentialRef: ys.CredentialRef,
 			URL:           ys.URL,
 			Headers:       ys.Headers,
+			Auth:          ys.Auth,
+			Limits:        ys.Limits,
+			Tags:          ys.Tags,
 		}
 		// Validate server config
 		if err := serverConfig.Validate(); err != nil {
@@ -159,6 +231,12 @@ func Parse(yamlBytes []byte) (*Workflow, error) {
 		if err := wf.AddNode(node); err != nil {
 			return nil, fmt.Errorf("failed to add node: %w", err)
 		}
+		if len(yn.Suppress) > 0 {
+			if wf.Metadata.Suppressions == nil {
+				wf.Metadata.Suppressions = make(map[string][]string)
+			}
+			wf.Metadata.Suppressions[yn.ID] = yn.Suppress
+		}
 	}
 
 	// Parse edges
@@ -169,6 +247,7 @@ func Parse(yamlBytes []byte) (*Workflow, error) {
 			ToNodeID:   ye.To,
 			Condition:  ye.Condition,
 			Label:      ye.Label,
+			OnError:    ye.OnError,
 		}
 		if err := wf.AddEdge(edge); err != nil {
 			return nil, fmt.Errorf("failed to add edge: %w", err)
@@ -227,6 +306,9 @@ func parseNode(yn yamlNode) (Node, error) {
 			ToolName:       yn.Tool,
 			Parameters:     yn.Parameters,
 			OutputVariable: yn.Output,
+			ContentOutputs: yn.ContentOutputs,
+			CacheTTL:       yn.CacheTTL,
+			StreamOutput:   yn.StreamOutput,
 		}, nil
 
 	case "transform":
@@ -240,10 +322,14 @@ func parseNode(yn yamlNode) (Node, error) {
 			return nil, fmt.Errorf("transform node '%s': output field is required", yn.ID)
 		}
 		return &TransformNode{
-			ID:             yn.ID,
-			InputVariable:  yn.Input,
-			Expression:     yn.Expression,
-			OutputVariable: yn.Output,
+			ID:                yn.ID,
+			InputVariable:     yn.Input,
+			Expression:        yn.Expression,
+			OutputVariable:    yn.Output,
+			ExpressionTimeout: yn.ExpressionTimeout,
+			OutputSchema:      yn.OutputSchema,
+			Language:          yn.Language,
+			ItemErrorsOutput:  yn.ItemErrorsOutput,
 		}, nil
 
 	case "condition":
@@ -251,8 +337,19 @@ func parseNode(yn yamlNode) (Node, error) {
 			return nil, fmt.Errorf("condition node '%s': condition field is required", yn.ID)
 		}
 		return &ConditionNode{
-			ID:        yn.ID,
-			Condition: yn.Condition,
+			ID:                yn.ID,
+			Condition:         yn.Condition,
+			ExpressionTimeout: yn.ExpressionTimeout,
+		}, nil
+
+	case "switch":
+		if len(yn.Cases) == 0 {
+			return nil, fmt.Errorf("switch node '%s': cases field is required", yn.ID)
+		}
+		return &SwitchNode{
+			ID:                yn.ID,
+			Cases:             yn.Cases,
+			ExpressionTimeout: yn.ExpressionTimeout,
 		}, nil
 
 	case "passthrough":
@@ -272,6 +369,9 @@ func parseNode(yn yamlNode) (Node, error) {
 			ID:            yn.ID,
 			Branches:      yn.Branches,
 			MergeStrategy: mergeStrategy,
+			Scope:         yn.Scope,
+			Promote:       yn.Promote,
+			OnConflict:    yn.OnConflict,
 		}, nil
 
 	case "loop":
@@ -285,11 +385,79 @@ func parseNode(yn yamlNode) (Node, error) {
 			return nil, fmt.Errorf("loop node '%s': body field is required", yn.ID)
 		}
 		return &LoopNode{
+			ID:                yn.ID,
+			Collection:        yn.Collection,
+			ItemVariable:      yn.Item,
+			Body:              yn.Body,
+			BreakCondition:    yn.BreakCondition,
+			Scope:             yn.Scope,
+			Promote:           yn.Promote,
+			ExpressionTimeout: yn.ExpressionTimeout,
+		}, nil
+
+	case "try":
+		if len(yn.Body) == 0 {
+			return nil, fmt.Errorf("try node '%s': body field is required", yn.ID)
+		}
+		return &TryNode{
+			ID:   yn.ID,
+			Body: yn.Body,
+		}, nil
+
+	case "catch":
+		if yn.ErrorVariable == "" {
+			return nil, fmt.Errorf("catch node '%s': error_variable field is required", yn.ID)
+		}
+		return &CatchNode{
+			ID:            yn.ID,
+			ErrorVariable: yn.ErrorVariable,
+		}, nil
+
+	case "delay":
+		if yn.Duration == "" && yn.Until == "" {
+			return nil, fmt.Errorf("delay node '%s': duration or until field is required", yn.ID)
+		}
+		return &DelayNode{
+			ID:       yn.ID,
+			Duration: yn.Duration,
+			Until:    yn.Until,
+		}, nil
+
+	case "approval":
+		return &ApprovalNode{
 			ID:             yn.ID,
+			Message:        yn.Message,
+			Timeout:        yn.Timeout,
+			DefaultAction:  yn.DefaultAction,
+			OutputVariable: yn.Output,
+		}, nil
+
+	case "batch_tool":
+		if yn.Server == "" {
+			return nil, fmt.Errorf("batch_tool node '%s': server field is required", yn.ID)
+		}
+		if yn.Tool == "" {
+			return nil, fmt.Errorf("batch_tool node '%s': tool field is required", yn.ID)
+		}
+		if yn.Collection == "" {
+			return nil, fmt.Errorf("batch_tool node '%s': collection field is required", yn.ID)
+		}
+		if yn.Item == "" {
+			return nil, fmt.Errorf("batch_tool node '%s': item field is required", yn.ID)
+		}
+		if yn.Output == "" {
+			return nil, fmt.Errorf("batch_tool node '%s': output field is required", yn.ID)
+		}
+		return &BatchToolNode{
+			ID:             yn.ID,
+			ServerID:       yn.Server,
+			ToolName:       yn.Tool,
 			Collection:     yn.Collection,
 			ItemVariable:   yn.Item,
-			Body:           yn.Body,
-			BreakCondition: yn.BreakCondition,
+			Parameters:     yn.Parameters,
+			MaxConcurrency: yn.MaxConcurrency,
+			OutputVariable: yn.Output,
+			ErrorVariable:  yn.ErrorVariable,
 		}, nil
 
 	default:
@@ -299,20 +467,41 @@ func parseNode(yn yamlNode) (Node, error) {
 
 // ToYAML serializes a workflow to YAML bytes
 func ToYAML(workflow *Workflow) ([]byte, error) {
+	yw, err := toDocument(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marshal to YAML
+	yamlBytes, err := yaml.Marshal(&yw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+	}
+
+	return yamlBytes, nil
+}
+
+// toDocument converts a workflow to the workflow document every encoding
+// writes. Suppressions are moved from the metadata onto their nodes.
+func toDocument(workflow *Workflow) (yamlWorkflow, error) {
 	if workflow == nil {
-		return nil, errors.New("workflow cannot be nil")
+		return yamlWorkflow{}, errors.New("workflow cannot be nil")
 	}
 
+	metadata := workflow.Metadata
+	metadata.Suppressions = nil
+
 	// Convert workflow to YAML structure
 	yw := yamlWorkflow{
-		Version:     workflow.Version,
-		Name:        workflow.Name,
-		Description: workflow.Description,
-		Metadata:    &workflow.Metadata,
-		Variables:   make([]yamlVariable, 0, len(workflow.Variables)),
-		Servers:     make([]yamlServerConfig, 0, len(workflow.ServerConfigs)),
-		Nodes:       make([]yamlNode, 0, len(workflow.Nodes)),
-		Edges:       make([]yamlEdge, 0, len(workflow.Edges)),
+		SchemaVersion: CurrentSchemaVersion,
+		Version:       workflow.Version,
+		Name:          workflow.Name,
+		Description:   workflow.Description,
+		Metadata:      &metadata,
+		Variables:     make([]yamlVariable, 0, len(workflow.Variables)),
+		Servers:       make([]yamlServerConfig, 0, len(workflow.ServerConfigs)),
+		Nodes:         make([]yamlNode, 0, len(workflow.Nodes)),
+		Edges:         make([]yamlEdge, 0, len(workflow.Edges)),
 	}
 
 	// Convert variables
@@ -320,6 +509,7 @@ func ToYAML(workflow *Workflow) ([]byte, error) {
 		yw.Variables = append(yw.Variables, yamlVariable{
 			Name:         v.Name,
 			Type:         v.Type,
+			Required:     v.Required,
 			DefaultValue: v.DefaultValue,
 			Description:  v.Description,
 		})
@@ -337,6 +527,9 @@ func ToYAML(workflow *Workflow) ([]byte, error) {
 			CredentialRef: s.CredentialRef,
 			URL:           s.URL,
 			Headers:       s.Headers,
+			Auth:          s.Auth,
+			Limits:        s.Limits,
+			Tags:          s.Tags,
 		})
 	}
 
@@ -344,8 +537,9 @@ func ToYAML(workflow *Workflow) ([]byte, error) {
 	for _, node := range workflow.Nodes {
 		yn, err := nodeToYAML(node)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert node to YAML: %w", err)
+			return yamlWorkflow{}, fmt.Errorf("failed to convert node to YAML: %w", err)
 		}
+		yn.Suppress = workflow.Metadata.Suppressions[node.GetID()]
 		yw.Nodes = append(yw.Nodes, yn)
 	}
 
@@ -356,18 +550,81 @@ func ToYAML(workflow *Workflow) ([]byte, error) {
 			To:        edge.ToNodeID,
 			Condition: edge.Condition,
 			Label:     edge.Label,
+			OnError:   edge.OnError,
 		})
 	}
 
-	// Marshal to YAML
-	yamlBytes, err := yaml.Marshal(&yw)
+	return yw, nil
+}
+
+// NodesToYAML serializes nodes and edges as a workflow fragment: just the
+// nodes and edges sections, ready to merge into a workflow file
+func NodesToYAML(nodes []Node, edges []*Edge) ([]byte, error) {
+	fragment := struct {
+		Nodes []yamlNode `yaml:"nodes"`
+		Edges []yamlEdge `yaml:"edges,omitempty"`
+	}{
+		Nodes: make([]yamlNode, 0, len(nodes)),
+	}
+
+	for _, node := range nodes {
+		yn, err := nodeToYAML(node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert node to YAML: %w", err)
+		}
+		fragment.Nodes = append(fragment.Nodes, yn)
+	}
+	for _, edge := range edges {
+		fragment.Edges = append(fragment.Edges, yamlEdge{
+			From:      edge.FromNodeID,
+			To:        edge.ToNodeID,
+			Condition: edge.Condition,
+			Label:     edge.Label,
+			OnError:   edge.OnError,
+		})
+	}
+
+	yamlBytes, err := yaml.Marshal(&fragment)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
 	}
-
 	return yamlBytes, nil
 }
 
+// NodesFromYAML parses a fragment written by NodesToYAML back into nodes
+// and edges. Edges get fresh IDs.
+func NodesFromYAML(yamlBytes []byte) ([]Node, []*Edge, error) {
+	var fragment struct {
+		Nodes []yamlNode `yaml:"nodes"`
+		Edges []yamlEdge `yaml:"edges"`
+	}
+	if err := yaml.Unmarshal(yamlBytes, &fragment); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	nodes := make([]Node, 0, len(fragment.Nodes))
+	for _, yn := range fragment.Nodes {
+		node, err := parseNode(yn)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse node '%s': %w", yn.ID, err)
+		}
+		nodes = append(nodes, node)
+	}
+
+	edges := make([]*Edge, 0, len(fragment.Edges))
+	for _, ye := range fragment.Edges {
+		edges = append(edges, &Edge{
+			ID:         NewEdgeID().String(),
+			FromNodeID: ye.From,
+			ToNodeID:   ye.To,
+			Condition:  ye.Condition,
+			Label:      ye.Label,
+			OnError:    ye.OnError,
+		})
+	}
+	return nodes, edges, nil
+}
+
 // nodeToYAML converts a Node interface to yamlNode
 func nodeToYAML(node Node) (yamlNode, error) {
 	yn := yamlNode{
@@ -376,7 +633,7 @@ func nodeToYAML(node Node) (yamlNode, error) {
 	}
 
 	switch n := node.(type) {
-	case *StartNode:
+	case *StartNode, *PassthroughNode:
 		// No additional fields
 
 	case *EndNode:
@@ -387,24 +644,88 @@ func nodeToYAML(node Node) (yamlNode, error) {
 		yn.Tool = n.ToolName
 		yn.Parameters = n.Parameters
 		yn.Output = n.OutputVariable
+		yn.ContentOutputs = n.ContentOutputs
+		yn.CacheTTL = n.CacheTTL
+		yn.StreamOutput = n.StreamOutput
 
 	case *TransformNode:
 		yn.Input = n.InputVariable
 		yn.Expression = n.Expression
 		yn.Output = n.OutputVariable
+		yn.ExpressionTimeout = n.ExpressionTimeout
+		yn.OutputSchema = n.OutputSchema
+		yn.Language = n.Language
+		yn.ItemErrorsOutput = n.ItemErrorsOutput
 
 	case *ConditionNode:
 		yn.Condition = n.Condition
+		yn.ExpressionTimeout = n.ExpressionTimeout
+
+	case *SwitchNode:
+		yn.Cases = n.Cases
+		yn.ExpressionTimeout = n.ExpressionTimeout
 
 	case *ParallelNode:
 		yn.Branches = n.Branches
 		yn.Merge = n.MergeStrategy
+		yn.Scope = n.Scope
+		yn.Promote = n.Promote
+		yn.OnConflict = n.OnConflict
 
 	case *LoopNode:
 		yn.Collection = n.Collection
 		yn.Item = n.ItemVariable
 		yn.Body = n.Body
 		yn.BreakCondition = n.BreakCondition
+		yn.Scope = n.Scope
+		yn.Promote = n.Promote
+		yn.ExpressionTimeout = n.ExpressionTimeout
+
+	case *TryNode:
+		yn.Body = n.Body
+
+	case *CatchNode:
+		yn.ErrorVariable = n.ErrorVariable
+
+	case *DelayNode:
+		yn.Duration = n.Duration
+		yn.Until = n.Until
+
+	case *ApprovalNode:
+		yn.Message = n.Message
+		yn.Timeout = n.Timeout
+		yn.DefaultAction = n.DefaultAction
+		yn.Output = n.OutputVariable
+
+	case *BatchToolNode:
+		yn.Server = n.ServerID
+		yn.Tool = n.ToolName
+		yn.Collection = n.Collection
+		yn.Item = n.ItemVariable
+		yn.Parameters = n.Parameters
+		yn.MaxConcurrency = n.MaxConcurrency
+		yn.Output = n.OutputVariable
+		yn.ErrorVariable = n.ErrorVariable
+
+	// Nodes instantiated from templates keep their raw config
+	case *GenericMCPToolNode:
+		yn.Server, _ = n.Config["server"].(string)
+		yn.Tool, _ = n.Config["tool"].(string)
+		yn.Output = configString(n.Config, "output", "output_variable")
+		if params, ok := n.Config["parameters"].(map[string]interface{}); ok {
+			yn.Parameters = make(map[string]string, len(params))
+			for name, value := range params {
+				yn.Parameters[name] = formatValue(value)
+			}
+		}
+
+	case *GenericTransformNode:
+		yn.Input = configString(n.Config, "input", "input_variable")
+		yn.Expression, _ = n.Config["expression"].(string)
+		yn.Output = configString(n.Config, "output", "output_variable")
+
+	case *GenericConditionNode:
+		yn.Condition = n.BaseCondition.Condition
 
 	default:
 		return yn, fmt.Errorf("unknown node type: %T", node)
@@ -412,3 +733,13 @@ func nodeToYAML(node Node) (yamlNode, error) {
 
 	return yn, nil
 }
+
+// configString returns the first of keys that holds a string in config
+func configString(config map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := config[key].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -213,6 +214,152 @@ edges:
 	}
 }
 
+func TestNodesToYAML(t *testing.T) {
+	nodes := []Node{
+		&TransformNode{ID: "shape", InputVariable: "raw", Expression: "$.items", OutputVariable: "items"},
+		&ConditionNode{ID: "check", Condition: "len(items) > 0"},
+	}
+	edges := []*Edge{{ID: "e1", FromNodeID: "shape", ToNodeID: "check", Label: "next"}}
+
+	yamlBytes, err := NodesToYAML(nodes, edges)
+	if err != nil {
+		t.Fatalf("NodesToYAML failed: %v", err)
+	}
+
+	// The fragment has no workflow header, so wrap it to parse it back
+	wf, err := Parse(append([]byte("version: \"1.0\"\nname: \"fragment\"\n"), yamlBytes...))
+	if err != nil {
+		t.Fatalf("Parse of fragment failed: %v\n%s", err, yamlBytes)
+	}
+	if len(wf.Nodes) != 2 || wf.Nodes[0].GetID() != "shape" || wf.Nodes[1].Type() != "condition" {
+		t.Errorf("Nodes = %+v", wf.Nodes)
+	}
+	if len(wf.Edges) != 1 || wf.Edges[0].FromNodeID != "shape" || wf.Edges[0].Label != "next" {
+		t.Errorf("Edges = %+v", wf.Edges)
+	}
+	if strings.Contains(string(yamlBytes), "version") {
+		t.Errorf("fragment should not have a workflow header:\n%s", yamlBytes)
+	}
+}
+
+func TestNodesFromYAML(t *testing.T) {
+	nodes := []Node{
+		&StartNode{ID: "start"},
+		&LoopNode{ID: "each", Collection: "items", ItemVariable: "item", Body: []string{"shape"}},
+	}
+	edges := []*Edge{{ID: "e1", FromNodeID: "start", ToNodeID: "each", Condition: "ready"}}
+
+	yamlBytes, err := NodesToYAML(nodes, edges)
+	if err != nil {
+		t.Fatalf("NodesToYAML failed: %v", err)
+	}
+	gotNodes, gotEdges, err := NodesFromYAML(yamlBytes)
+	if err != nil {
+		t.Fatalf("NodesFromYAML failed: %v\n%s", err, yamlBytes)
+	}
+
+	if len(gotNodes) != 2 || gotNodes[0].Type() != "start" {
+		t.Fatalf("Nodes = %+v", gotNodes)
+	}
+	loop, ok := gotNodes[1].(*LoopNode)
+	if !ok || loop.Collection != "items" || len(loop.Body) != 1 || loop.Body[0] != "shape" {
+		t.Errorf("loop node = %+v", gotNodes[1])
+	}
+	if len(gotEdges) != 1 || gotEdges[0].Condition != "ready" || gotEdges[0].ID == "" {
+		t.Errorf("Edges = %+v", gotEdges)
+	}
+
+	if _, _, err := NodesFromYAML([]byte("nodes:\n  - id: x\n    type: bogus\n")); err == nil {
+		t.Error("expected an error for an unknown node type")
+	}
+}
+
+func TestParse_ContentOutputs(t *testing.T) {
+	yaml := `version: "1.0"
+name: "test"
+servers:
+  - id: "test-server"
+    command: "echo"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "tool1"
+    type: "mcp_tool"
+    server: "test-server"
+    tool: "fetch"
+    output: "result"
+    content_outputs:
+      text: "summary"
+      resource: "file_ref"
+  - id: "end"
+    type: "end"
+    return: "${summary}"
+edges:
+  - from: "start"
+    to: "tool1"
+  - from: "tool1"
+    to: "end"
+`
+	wf, err := Parse([]byte(yaml))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	node, ok := wf.Nodes[1].(*MCPToolNode)
+	if !ok {
+		t.Fatalf("Expected MCPToolNode, got %T", wf.Nodes[1])
+	}
+	if node.ContentOutputs["text"] != "summary" || node.ContentOutputs["resource"] != "file_ref" {
+		t.Errorf("Unexpected content outputs: %v", node.ContentOutputs)
+	}
+
+	yamlBytes, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML failed: %v", err)
+	}
+	wf2, err := Parse(yamlBytes)
+	if err != nil {
+		t.Fatalf("Re-parse failed: %v", err)
+	}
+	node2 := wf2.Nodes[1].(*MCPToolNode)
+	if len(node2.ContentOutputs) != 2 || node2.ContentOutputs["text"] != "summary" {
+		t.Errorf("Content outputs lost in round trip: %v", node2.ContentOutputs)
+	}
+}
+
+func TestMCPToolNode_ValidateContentOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs map[string]string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"text and resource", map[string]string{"text": "summary", "resource": "file_ref"}, false},
+		{"unknown type", map[string]string{"video": "clip"}, true},
+		{"empty variable", map[string]string{"text": ""}, true},
+		{"conflicts with output", map[string]string{"text": "result"}, true},
+		{"duplicate variable", map[string]string{"image": "media", "audio": "media"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &MCPToolNode{
+				ID:             "tool1",
+				ServerID:       "server",
+				ToolName:       "tool",
+				OutputVariable: "result",
+				ContentOutputs: tt.outputs,
+			}
+			if err := node.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestTopologicalSort_Simple(t *testing.T) {
 	wf, err := NewWorkflow("test", "test workflow")
 	if err != nil {
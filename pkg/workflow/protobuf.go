@@ -0,0 +1,484 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dshills/goflow/pkg/workflow/workflowpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+)
+
+// ToProto serializes a workflow to Protobuf, for compact wire transfer and
+// use from other languages. The message is workflowpb.Workflow, defined in
+// workflowpb/workflow.proto.
+func ToProto(workflow *Workflow) ([]byte, error) {
+	yw, err := toDocument(workflow)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := documentToProto(yw)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal to Protobuf: %w", err)
+	}
+	return data, nil
+}
+
+// ParseProto parses a workflow from Protobuf bytes
+func ParseProto(data []byte) (*Workflow, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty Protobuf input")
+	}
+
+	var msg workflowpb.Workflow
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("failed to parse Protobuf: %w", err)
+	}
+	yw := protoToDocument(&msg)
+
+	// Documents written with an older schema go through Parse and its
+	// migrations
+	if yw.SchemaVersion != CurrentSchemaVersion {
+		yamlBytes, err := yaml.Marshal(&yw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+		}
+		return Parse(yamlBytes)
+	}
+	return fromDocument(yw)
+}
+
+// documentToProto converts a workflow document to its Protobuf message
+func documentToProto(yw yamlWorkflow) (*workflowpb.Workflow, error) {
+	msg := &workflowpb.Workflow{
+		SchemaVersion: int32(yw.SchemaVersion),
+		Version:       yw.Version,
+		Name:          yw.Name,
+		Description:   yw.Description,
+	}
+
+	if yw.Metadata != nil {
+		metadata, err := metadataToProto(yw.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		msg.Metadata = metadata
+	}
+
+	for _, yv := range yw.Variables {
+		value, err := structpb.NewValue(yv.DefaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: invalid default: %w", yv.Name, err)
+		}
+		msg.Variables = append(msg.Variables, &workflowpb.Variable{
+			Name:        yv.Name,
+			Type:        yv.Type,
+			Default:     value,
+			Description: yv.Description,
+			Required:    yv.Required,
+		})
+	}
+
+	for _, ys := range yw.Servers {
+		server := &workflowpb.ServerConfig{
+			Id:            ys.ID,
+			Name:          ys.Name,
+			Command:       ys.Command,
+			Args:          ys.Args,
+			Transport:     ys.Transport,
+			Env:           ys.Env,
+			CredentialRef: ys.CredentialRef,
+			Url:           ys.URL,
+			Headers:       ys.Headers,
+			Tags:          ys.Tags,
+
+			Auth: authToProto(ys.Auth),
+		}
+		if ys.Limits != nil {
+			server.Limits = &workflowpb.ServerLimits{
+				MaxConcurrent:     int32(ys.Limits.MaxConcurrent),
+				RequestsPerSecond: ys.Limits.RequestsPerSecond,
+				Burst:             int32(ys.Limits.Burst),
+				QueueTimeout:      ys.Limits.QueueTimeout,
+			}
+		}
+		msg.Servers = append(msg.Servers, server)
+	}
+
+	for _, yn := range yw.Nodes {
+		node := &workflowpb.Node{
+			Id:                yn.ID,
+			Type:              yn.Type,
+			Return:            yn.Return,
+			Server:            yn.Server,
+			Tool:              yn.Tool,
+			Parameters:        yn.Parameters,
+			Output:            yn.Output,
+			ContentOutputs:    yn.ContentOutputs,
+			Input:             yn.Input,
+			Expression:        yn.Expression,
+			Condition:         yn.Condition,
+			MergeStrategy:     yn.Merge,
+			Collection:        yn.Collection,
+			Item:              yn.Item,
+			Body:              yn.Body,
+			BreakCondition:    yn.BreakCondition,
+			ErrorVariable:     yn.ErrorVariable,
+			Duration:          yn.Duration,
+			Until:             yn.Until,
+			Message:           yn.Message,
+			Timeout:           yn.Timeout,
+			DefaultAction:     yn.DefaultAction,
+			Suppress:          yn.Suppress,
+			Scope:             yn.Scope,
+			Promote:           yn.Promote,
+			OnConflict:        yn.OnConflict,
+			CacheTtl:          yn.CacheTTL,
+			MaxConcurrency:    int32(yn.MaxConcurrency),
+			StreamOutput:      yn.StreamOutput,
+			ExpressionTimeout: yn.ExpressionTimeout,
+			Language:          yn.Language,
+			ItemErrorsOutput:  yn.ItemErrorsOutput,
+		}
+		for _, c := range yn.Cases {
+			node.Cases = append(node.Cases, &workflowpb.SwitchCase{Label: c.Label, Condition: c.Condition})
+		}
+		for _, branch := range yn.Branches {
+			node.Branches = append(node.Branches, &workflowpb.Branch{Nodes: branch})
+		}
+		if yn.OutputSchema != nil {
+			schema, err := structpb.NewStruct(yn.OutputSchema)
+			if err != nil {
+				return nil, fmt.Errorf("node %s output_schema: %w", yn.ID, err)
+			}
+			node.OutputSchema = schema
+		}
+		msg.Nodes = append(msg.Nodes, node)
+	}
+
+	for _, ye := range yw.Edges {
+		msg.Edges = append(msg.Edges, &workflowpb.Edge{
+			From:      ye.From,
+			To:        ye.To,
+			Condition: ye.Condition,
+			Label:     ye.Label,
+			OnError:   ye.OnError,
+		})
+	}
+
+	return msg, nil
+}
+
+// authToProto encodes server auth as key=value entries in field order,
+// leaving out empty settings
+func authToProto(auth *ServerAuth) []string {
+	if auth == nil {
+		return nil
+	}
+	var entries []string
+	for _, setting := range [][2]string{
+		{"type", auth.Type},
+		{"token_ref", auth.TokenRef},
+		{"header_name", auth.HeaderName},
+		{"key_ref", auth.KeyRef},
+		{"token_url", auth.TokenURL},
+		{"client_id", auth.ClientID},
+		{"client_secret_ref", auth.ClientSecretRef},
+		{"scopes", strings.Join(auth.Scopes, ",")},
+	} {
+		if setting[1] != "" {
+			entries = append(entries, setting[0]+"="+setting[1])
+		}
+	}
+	return entries
+}
+
+// authFromProto decodes the entries authToProto encodes
+func authFromProto(entries []string) *ServerAuth {
+	if len(entries) == 0 {
+		return nil
+	}
+	auth := &ServerAuth{}
+	for _, entry := range entries {
+		key, value, _ := strings.Cut(entry, "=")
+		switch key {
+		case "type":
+			auth.Type = value
+		case "token_ref":
+			auth.TokenRef = value
+		case "header_name":
+			auth.HeaderName = value
+		case "key_ref":
+			auth.KeyRef = value
+		case "token_url":
+			auth.TokenURL = value
+		case "client_id":
+			auth.ClientID = value
+		case "client_secret_ref":
+			auth.ClientSecretRef = value
+		case "scopes":
+			auth.Scopes = strings.Split(value, ",")
+		}
+	}
+	return auth
+}
+
+// metadataToProto converts workflow metadata to its Protobuf message
+func metadataToProto(m *WorkflowMetadata) (*workflowpb.Metadata, error) {
+	msg := &workflowpb.Metadata{
+		Author:            m.Author,
+		Tags:              m.Tags,
+		Icon:              m.Icon,
+		Notes:             m.Notes,
+		ReadOnly:          m.ReadOnly,
+		ExpressionTimeout: m.ExpressionTimeout,
+	}
+	if !m.Created.IsZero() {
+		msg.Created = timestamppb.New(m.Created)
+	}
+	if !m.LastModified.IsZero() {
+		msg.LastModified = timestamppb.New(m.LastModified)
+	}
+
+	if m.Template != nil {
+		msg.Template = &workflowpb.TemplateSource{
+			Name:    m.Template.Name,
+			Version: m.Template.Version,
+			Nodes:   m.Template.Nodes,
+		}
+		if m.Template.Parameters != nil {
+			parameters, err := structpb.NewStruct(m.Template.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("template parameters: %w", err)
+			}
+			msg.Template.Parameters = parameters
+		}
+	}
+
+	for _, group := range m.Groups {
+		msg.Groups = append(msg.Groups, &workflowpb.NodeGroup{
+			Name:      group.Name,
+			Nodes:     group.Nodes,
+			Collapsed: group.Collapsed,
+		})
+	}
+
+	if len(m.Contracts) > 0 {
+		msg.Contracts = make(map[string]*workflowpb.NodeContract, len(m.Contracts))
+		for nodeID, contract := range m.Contracts {
+			if contract != nil {
+				msg.Contracts[nodeID] = &workflowpb.NodeContract{Reads: contract.Reads, Writes: contract.Writes}
+			}
+		}
+	}
+
+	if len(m.Lint) > 0 {
+		msg.Lint = make(map[string]string, len(m.Lint))
+		for rule, severity := range m.Lint {
+			msg.Lint[rule] = string(severity)
+		}
+	}
+
+	if m.Canvas != nil {
+		msg.Canvas = &workflowpb.CanvasLayout{
+			Zoom:      m.Canvas.Zoom,
+			ViewportX: int32(m.Canvas.ViewportX),
+			ViewportY: int32(m.Canvas.ViewportY),
+		}
+		if len(m.Canvas.Positions) > 0 {
+			msg.Canvas.Positions = make(map[string]*workflowpb.CanvasPosition, len(m.Canvas.Positions))
+			for nodeID, pos := range m.Canvas.Positions {
+				msg.Canvas.Positions[nodeID] = &workflowpb.CanvasPosition{X: int32(pos.X), Y: int32(pos.Y)}
+			}
+		}
+	}
+
+	return msg, nil
+}
+
+// protoToDocument converts a Protobuf message to a workflow document
+func protoToDocument(msg *workflowpb.Workflow) yamlWorkflow {
+	yw := yamlWorkflow{
+		SchemaVersion: int(msg.GetSchemaVersion()),
+		Version:       msg.GetVersion(),
+		Name:          msg.GetName(),
+		Description:   msg.GetDescription(),
+	}
+
+	if msg.Metadata != nil {
+		yw.Metadata = protoToMetadata(msg.Metadata)
+	}
+
+	for _, v := range msg.GetVariables() {
+		var defaultValue interface{}
+		if v.Default != nil {
+			defaultValue = v.Default.AsInterface()
+		}
+		yw.Variables = append(yw.Variables, yamlVariable{
+			Name:         v.GetName(),
+			Type:         v.GetType(),
+			Required:     v.GetRequired(),
+			DefaultValue: defaultValue,
+			Description:  v.GetDescription(),
+		})
+	}
+
+	for _, s := range msg.GetServers() {
+		ys := yamlServerConfig{
+			ID:            s.GetId(),
+			Name:          s.GetName(),
+			Command:       s.GetCommand(),
+			Args:          s.GetArgs(),
+			Transport:     s.GetTransport(),
+			Env:           s.GetEnv(),
+			CredentialRef: s.GetCredentialRef(),
+			URL:           s.GetUrl(),
+			Headers:       s.GetHeaders(),
+			Tags:          s.GetTags(),
+		}
+		ys.Auth = authFromProto(s.GetAuth())
+		if limits := s.GetLimits(); limits != nil {
+			ys.Limits = &ServerLimits{
+				MaxConcurrent:     int(limits.GetMaxConcurrent()),
+				RequestsPerSecond: limits.GetRequestsPerSecond(),
+				Burst:             int(limits.GetBurst()),
+				QueueTimeout:      limits.GetQueueTimeout(),
+			}
+		}
+		yw.Servers = append(yw.Servers, ys)
+	}
+
+	for _, n := range msg.GetNodes() {
+		yn := yamlNode{
+			ID:                n.GetId(),
+			Type:              n.GetType(),
+			Return:            n.GetReturn(),
+			Server:            n.GetServer(),
+			Tool:              n.GetTool(),
+			Parameters:        n.GetParameters(),
+			Output:            n.GetOutput(),
+			ContentOutputs:    n.GetContentOutputs(),
+			Input:             n.GetInput(),
+			Expression:        n.GetExpression(),
+			Condition:         n.GetCondition(),
+			Merge:             n.GetMergeStrategy(),
+			Collection:        n.GetCollection(),
+			Item:              n.GetItem(),
+			Body:              n.GetBody(),
+			BreakCondition:    n.GetBreakCondition(),
+			ErrorVariable:     n.GetErrorVariable(),
+			Duration:          n.GetDuration(),
+			Until:             n.GetUntil(),
+			Message:           n.GetMessage(),
+			Timeout:           n.GetTimeout(),
+			DefaultAction:     n.GetDefaultAction(),
+			Suppress:          n.GetSuppress(),
+			Scope:             n.GetScope(),
+			Promote:           n.GetPromote(),
+			OnConflict:        n.GetOnConflict(),
+			CacheTTL:          n.GetCacheTtl(),
+			MaxConcurrency:    int(n.GetMaxConcurrency()),
+			StreamOutput:      n.GetStreamOutput(),
+			ExpressionTimeout: n.GetExpressionTimeout(),
+			Language:          n.GetLanguage(),
+			ItemErrorsOutput:  n.GetItemErrorsOutput(),
+		}
+		for _, c := range n.GetCases() {
+			yn.Cases = append(yn.Cases, SwitchCase{Label: c.GetLabel(), Condition: c.GetCondition()})
+		}
+		for _, branch := range n.GetBranches() {
+			yn.Branches = append(yn.Branches, branch.GetNodes())
+		}
+		if schema := n.GetOutputSchema(); schema != nil {
+			yn.OutputSchema = schema.AsMap()
+		}
+		yw.Nodes = append(yw.Nodes, yn)
+	}
+
+	for _, e := range msg.GetEdges() {
+		yw.Edges = append(yw.Edges, yamlEdge{
+			From:      e.GetFrom(),
+			To:        e.GetTo(),
+			Condition: e.GetCondition(),
+			Label:     e.GetLabel(),
+			OnError:   e.GetOnError(),
+		})
+	}
+
+	return yw
+}
+
+// protoToMetadata converts a Protobuf message to workflow metadata
+func protoToMetadata(msg *workflowpb.Metadata) *WorkflowMetadata {
+	m := &WorkflowMetadata{
+		Author:            msg.GetAuthor(),
+		Tags:              msg.GetTags(),
+		Icon:              msg.GetIcon(),
+		Notes:             msg.GetNotes(),
+		ReadOnly:          msg.GetReadOnly(),
+		ExpressionTimeout: msg.GetExpressionTimeout(),
+	}
+	if msg.Created != nil {
+		m.Created = msg.Created.AsTime()
+	}
+	if msg.LastModified != nil {
+		m.LastModified = msg.LastModified.AsTime()
+	}
+
+	if t := msg.GetTemplate(); t != nil {
+		m.Template = &TemplateSource{
+			Name:    t.GetName(),
+			Version: t.GetVersion(),
+			Nodes:   t.GetNodes(),
+		}
+		if t.Parameters != nil {
+			m.Template.Parameters = t.Parameters.AsMap()
+		}
+	}
+
+	for _, group := range msg.GetGroups() {
+		m.Groups = append(m.Groups, &NodeGroup{
+			Name:      group.GetName(),
+			Nodes:     group.GetNodes(),
+			Collapsed: group.GetCollapsed(),
+		})
+	}
+
+	if len(msg.GetContracts()) > 0 {
+		m.Contracts = make(map[string]*NodeContract, len(msg.GetContracts()))
+		for nodeID, contract := range msg.GetContracts() {
+			m.Contracts[nodeID] = &NodeContract{Reads: contract.GetReads(), Writes: contract.GetWrites()}
+		}
+	}
+
+	if len(msg.GetLint()) > 0 {
+		m.Lint = make(map[string]LintSeverity, len(msg.GetLint()))
+		for rule, severity := range msg.GetLint() {
+			m.Lint[rule] = LintSeverity(severity)
+		}
+	}
+
+	if canvas := msg.GetCanvas(); canvas != nil {
+		m.Canvas = &CanvasLayout{
+			Zoom:      canvas.GetZoom(),
+			ViewportX: int(canvas.GetViewportX()),
+			ViewportY: int(canvas.GetViewportY()),
+		}
+		if len(canvas.GetPositions()) > 0 {
+			m.Canvas.Positions = make(map[string]CanvasPosition, len(canvas.GetPositions()))
+			for nodeID, pos := range canvas.GetPositions() {
+				m.Canvas.Positions[nodeID] = CanvasPosition{X: int(pos.GetX()), Y: int(pos.GetY())}
+			}
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,236 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToolCategory is a kind of effect an MCP tool call may have
+type ToolCategory string
+
+// Tool categories servers declare their tools in
+const (
+	// ToolCategoryRead tools only read, without the effects of the others
+	ToolCategoryRead ToolCategory = "read"
+	// ToolCategoryWrite tools change files, records or other state
+	ToolCategoryWrite ToolCategory = "write"
+	// ToolCategoryNetwork tools reach other hosts
+	ToolCategoryNetwork ToolCategory = "network"
+	// ToolCategoryExec tools run commands or code
+	ToolCategoryExec ToolCategory = "exec"
+)
+
+// DefaultToolCategoriesKey declares the categories of the tools of a server
+// that tool_categories does not list by name
+const DefaultToolCategoriesKey = "*"
+
+// deniableToolCategories are the categories sandbox profiles can deny
+var deniableToolCategories = []ToolCategory{ToolCategoryWrite, ToolCategoryNetwork, ToolCategoryExec}
+
+// ToolCategories returns the categories of effect of a call of tool on
+// server, as the server's ToolCategories declare them for the tool or for
+// every tool ("*"), with network added for servers reached over HTTP
+// or SSE. read is not returned, since it means no effect. declared is
+// false when the server declares nothing for the tool: such a tool may do
+// anything, and restricting sandbox profiles do not let it run.
+func ToolCategories(server *ServerConfig, tool string) (categories []ToolCategory, declared bool) {
+	if server == nil {
+		return nil, false
+	}
+	found := make(map[ToolCategory]bool)
+	listed, declared := server.ToolCategories[tool]
+	if !declared {
+		listed, declared = server.ToolCategories[DefaultToolCategoriesKey]
+	}
+	for _, category := range listed {
+		if category != ToolCategoryRead {
+			found[category] = true
+		}
+	}
+	if transport := server.GetTransport(); transport == "http" || transport == "sse" {
+		found[ToolCategoryNetwork] = true
+	}
+
+	categories = make([]ToolCategory, 0, len(found))
+	for category := range found {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+	return categories, declared
+}
+
+// validateToolCategories checks that a server's tool categories only list
+// known categories, and at least one for each tool
+func validateToolCategories(declared map[string][]ToolCategory) error {
+	for tool, categories := range declared {
+		if len(categories) == 0 {
+			return fmt.Errorf("tool_categories of %s is empty (use [read] for a tool that only reads)", tool)
+		}
+		for _, category := range categories {
+			if category != ToolCategoryRead && !slices.Contains(deniableToolCategories, category) {
+				return fmt.Errorf("tool_categories of %s: unknown category %q (expected read, write, network, or exec)", tool, category)
+			}
+		}
+	}
+	return nil
+}
+
+// SandboxProfile restricts which node types and tool categories a run may
+// execute, such as read-only or no-network
+type SandboxProfile struct {
+	Name               string         `yaml:"-"`
+	Description        string         `yaml:"description,omitempty"`
+	DenyNodeTypes      []string       `yaml:"deny_node_types,omitempty"`
+	DenyToolCategories []ToolCategory `yaml:"deny_tool_categories,omitempty"`
+}
+
+// BuiltinSandboxProfiles are the profiles available without configuration
+var BuiltinSandboxProfiles = []SandboxProfile{
+	{
+		Name:               "read-only",
+		Description:        "Tools may read, but not change state or run commands",
+		DenyToolCategories: []ToolCategory{ToolCategoryWrite, ToolCategoryExec},
+	},
+	{
+		Name:               "no-network",
+		Description:        "Tools may not reach other hosts",
+		DenyToolCategories: []ToolCategory{ToolCategoryNetwork},
+	},
+	{
+		Name:          "no-tools",
+		Description:   "No MCP tools run, only transforms and control flow",
+		DenyNodeTypes: []string{"mcp_tool", "batch_tool"},
+	},
+}
+
+// sandboxNodeTypes are the node types a profile can deny
+var sandboxNodeTypes = []string{"start", "end", "mcp_tool", "transform", "passthrough", "condition", "switch",
+	"parallel", "loop", "try", "catch", "delay", "approval", "batch_tool"}
+
+// Validate checks that the profile only denies known node types and tool
+// categories
+func (p *SandboxProfile) Validate() error {
+	for _, nodeType := range p.DenyNodeTypes {
+		if !slices.Contains(sandboxNodeTypes, nodeType) {
+			return fmt.Errorf("sandbox profile %s: unknown node type %q", p.Name, nodeType)
+		}
+	}
+	for _, category := range p.DenyToolCategories {
+		if !slices.Contains(deniableToolCategories, category) {
+			return fmt.Errorf("sandbox profile %s: unknown tool category %q (expected write, network, or exec)", p.Name, category)
+		}
+	}
+	return nil
+}
+
+// CheckNode returns why the profile does not let node of wf run, or "" if
+// it may. A profile that denies any tool category denies tools whose
+// categories are not declared, since they may do anything.
+func (p *SandboxProfile) CheckNode(wf *Workflow, node Node) string {
+	if p == nil || node == nil {
+		return ""
+	}
+	if slices.Contains(p.DenyNodeTypes, node.Type()) {
+		return fmt.Sprintf("%s nodes are not allowed by the %s sandbox profile", node.Type(), p.Name)
+	}
+
+	var serverID, toolName string
+	switch n := node.(type) {
+	case *MCPToolNode:
+		serverID, toolName = n.ServerID, n.ToolName
+	case *BatchToolNode:
+		serverID, toolName = n.ServerID, n.ToolName
+	default:
+		return ""
+	}
+	var server *ServerConfig
+	if wf != nil {
+		for _, config := range wf.ServerConfigs {
+			if config != nil && config.ID == serverID {
+				server = config
+			}
+		}
+	}
+	categories, declared := ToolCategories(server, toolName)
+	if !declared && len(p.DenyToolCategories) > 0 {
+		return fmt.Sprintf("tool %s of server %s has no declared categories, so the %s sandbox profile does not let it run (declare them in the server's tool_categories in servers.yaml)", toolName, serverID, p.Name)
+	}
+	for _, category := range categories {
+		if slices.Contains(p.DenyToolCategories, category) {
+			return fmt.Sprintf("tool %s of server %s is in the %s category, which the %s sandbox profile denies", toolName, serverID, category, p.Name)
+		}
+	}
+	return ""
+}
+
+// SandboxConfig is the sandbox profiles file format:
+//
+//	profiles:
+//	  reporting:
+//	    description: Read-only reports without approvals
+//	    deny_node_types: [approval]
+//	    deny_tool_categories: [write, exec]
+type SandboxConfig struct {
+	Profiles map[string]*SandboxProfile `yaml:"profiles"`
+}
+
+// LoadSandboxProfiles returns the built-in profiles and those defined in
+// the file at path, sorted by name. A profile in the file replaces a
+// built-in one of the same name. A missing file defines none.
+func LoadSandboxProfiles(path string) ([]*SandboxProfile, error) {
+	byName := make(map[string]*SandboxProfile, len(BuiltinSandboxProfiles))
+	for i := range BuiltinSandboxProfiles {
+		profile := BuiltinSandboxProfiles[i]
+		byName[profile.Name] = &profile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read sandbox profiles: %w", err)
+	}
+	if err == nil {
+		var config SandboxConfig
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse sandbox profiles %s: %w", path, err)
+		}
+		for name, profile := range config.Profiles {
+			if profile == nil {
+				profile = &SandboxProfile{}
+			}
+			profile.Name = name
+			if err := profile.Validate(); err != nil {
+				return nil, err
+			}
+			byName[name] = profile
+		}
+	}
+
+	profiles := make([]*SandboxProfile, 0, len(byName))
+	for _, profile := range byName {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// LoadSandboxProfile returns the built-in or configured profile called name
+func LoadSandboxProfile(path, name string) (*SandboxProfile, error) {
+	profiles, err := LoadSandboxProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(profiles))
+	for i, profile := range profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+		names[i] = profile.Name
+	}
+	return nil, fmt.Errorf("unknown sandbox profile %q (available: %s)", name, strings.Join(names, ", "))
+}
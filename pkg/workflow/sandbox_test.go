@@ -0,0 +1,105 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestToolCategories(t *testing.T) {
+	fs := &ServerConfig{ID: "fs", Command: "fs-server", ToolCategories: map[string][]ToolCategory{
+		"read_file": {ToolCategoryRead},
+		"*":         {ToolCategoryWrite},
+	}}
+	tests := []struct {
+		server       *ServerConfig
+		tool         string
+		want         []ToolCategory
+		wantDeclared bool
+	}{
+		{fs, "read_file", []ToolCategory{}, true},
+		{fs, "write_file", []ToolCategory{ToolCategoryWrite}, true},
+		{&ServerConfig{ID: "sh", Command: "shell-server", Tags: []string{"exec"}}, "run_command", []ToolCategory{}, false},
+		{&ServerConfig{ID: "api", Transport: "http", URL: "https://example.com/mcp"}, "get_status", []ToolCategory{ToolCategoryNetwork}, false},
+		{&ServerConfig{ID: "api", Transport: "sse", URL: "https://example.com/mcp", ToolCategories: map[string][]ToolCategory{
+			"deploy": {ToolCategoryExec, ToolCategoryWrite},
+		}}, "deploy", []ToolCategory{ToolCategoryExec, ToolCategoryNetwork, ToolCategoryWrite}, true},
+		{nil, "send_email", nil, false},
+	}
+	for _, tt := range tests {
+		got, declared := ToolCategories(tt.server, tt.tool)
+		if !slices.Equal(got, tt.want) || declared != tt.wantDeclared {
+			t.Errorf("ToolCategories(%s) = %v, %v, want %v, %v", tt.tool, got, declared, tt.want, tt.wantDeclared)
+		}
+	}
+}
+
+func TestSandboxProfile_CheckNode(t *testing.T) {
+	wf := &Workflow{ServerConfigs: []*ServerConfig{
+		{ID: "fs", Command: "fs-server", ToolCategories: map[string][]ToolCategory{"read_file": {ToolCategoryRead}}},
+	}}
+	readOnly := &BuiltinSandboxProfiles[0]
+	approvals := &SandboxProfile{Name: "approvals", DenyNodeTypes: []string{"approval"}}
+
+	if reason := readOnly.CheckNode(wf, &MCPToolNode{ID: "read", ServerID: "fs", ToolName: "read_file"}); reason != "" {
+		t.Errorf("declared read tool denied: %s", reason)
+	}
+	if reason := readOnly.CheckNode(wf, &MCPToolNode{ID: "list", ServerID: "fs", ToolName: "list_dir"}); !strings.Contains(reason, "tool_categories") {
+		t.Errorf("unclassified tool reason = %q, want it denied", reason)
+	}
+	if reason := readOnly.CheckNode(wf, &MCPToolNode{ID: "other", ServerID: "missing", ToolName: "read_file"}); reason == "" {
+		t.Error("tool of an unknown server allowed")
+	}
+	if reason := approvals.CheckNode(wf, &MCPToolNode{ID: "list", ServerID: "fs", ToolName: "list_dir"}); reason != "" {
+		t.Errorf("profile denying no categories denied an unclassified tool: %s", reason)
+	}
+}
+
+func TestServerConfig_ValidateToolCategories(t *testing.T) {
+	for _, declared := range []map[string][]ToolCategory{
+		{"*": {"teleport"}},
+		{"read_file": {}},
+	} {
+		server := &ServerConfig{ID: "fs", Command: "fs-server", ToolCategories: declared}
+		if err := server.Validate(); err == nil || !strings.Contains(err.Error(), "tool_categories") {
+			t.Errorf("Validate(%v) error = %v", declared, err)
+		}
+	}
+}
+
+func TestSandboxProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sandbox.yaml")
+	profiles, err := LoadSandboxProfiles(path)
+	if err != nil || len(profiles) != len(BuiltinSandboxProfiles) {
+		t.Fatalf("LoadSandboxProfiles without a file = %v, %v", profiles, err)
+	}
+
+	config := `profiles:
+  reporting:
+    description: Reports without approvals
+    deny_node_types: [approval]
+    deny_tool_categories: [write]
+`
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	reporting, err := LoadSandboxProfile(path, "reporting")
+	if err != nil || reporting.Name != "reporting" || !slices.Equal(reporting.DenyNodeTypes, []string{"approval"}) {
+		t.Fatalf("LoadSandboxProfile = %+v, %v", reporting, err)
+	}
+	if _, err := LoadSandboxProfile(path, "read-only"); err != nil {
+		t.Errorf("built-in profile: %v", err)
+	}
+	if _, err := LoadSandboxProfile(path, "missing"); err == nil || !strings.Contains(err.Error(), "no-network, no-tools, read-only, reporting") {
+		t.Errorf("unknown profile error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("profiles:\n  bad:\n    deny_tool_categories: [teleport]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSandboxProfiles(path); err == nil || !strings.Contains(err.Error(), "teleport") {
+		t.Errorf("invalid profile error = %v", err)
+	}
+}
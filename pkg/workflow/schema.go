@@ -0,0 +1,221 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentSchemaVersion is the workflow file format written by ToYAML.
+// Files without a schema_version predate versioning and are version 0.
+const CurrentSchemaVersion = 1
+
+// schemaMigration upgrades a workflow document by one schema version
+type schemaMigration struct {
+	description string
+	apply       func(doc *yaml.Node) error
+}
+
+// schemaMigrations[i] upgrades schema version i to i+1. Append a migration
+// and bump CurrentSchemaVersion whenever the file format changes; never
+// edit one that has shipped.
+var schemaMigrations = []schemaMigration{
+	{description: "rename end node return_value to return", apply: migrateEndReturnValue},
+}
+
+// SchemaVersion reports the schema version of a workflow document
+func SchemaVersion(yamlBytes []byte) (int, error) {
+	_, doc, err := decodeSchemaDocument(yamlBytes)
+	if err != nil {
+		return 0, err
+	}
+	return schemaVersionOf(doc)
+}
+
+// Migrate upgrades a workflow document to CurrentSchemaVersion, returning
+// the upgraded document and the version it started at. Documents that are
+// already current are returned unchanged; documents written by a newer
+// goflow are an error.
+func Migrate(yamlBytes []byte) ([]byte, int, error) {
+	root, doc, err := decodeSchemaDocument(yamlBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+	from, err := schemaVersionOf(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from == CurrentSchemaVersion {
+		return yamlBytes, from, nil
+	}
+
+	for version := from; version < CurrentSchemaVersion; version++ {
+		migration := schemaMigrations[version]
+		if err := migration.apply(doc); err != nil {
+			return nil, from, fmt.Errorf("schema migration %d to %d (%s): %w", version, version+1, migration.description, err)
+		}
+	}
+	setMappingValue(doc, "schema_version", strconv.Itoa(CurrentSchemaVersion), "!!int")
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, from, fmt.Errorf("failed to encode migrated workflow: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, from, fmt.Errorf("failed to encode migrated workflow: %w", err)
+	}
+	return buf.Bytes(), from, nil
+}
+
+// MigrateFile upgrades a workflow file in place when it uses an older
+// schema version, first copying the original to path + ".bak". It reports
+// whether the file was rewritten. Loading a file never calls it, since
+// loaders migrate in memory; it is for an explicit upgrade or a save. A
+// workflow whose metadata sets read_only, or a file that can't be written,
+// is an error and is left untouched.
+func MigrateFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file: %w", err)
+	}
+	migrated, from, err := Migrate(data)
+	if err != nil {
+		return false, err
+	}
+	if from == CurrentSchemaVersion {
+		return false, nil
+	}
+	if _, doc, err := decodeSchemaDocument(data); err == nil {
+		if metadata := mappingValue(doc, "metadata"); metadata != nil && metadata.Kind == yaml.MappingNode {
+			if readOnly := mappingValue(metadata, "read_only"); readOnly != nil && readOnly.Value == "true" {
+				return false, errors.New("the workflow's metadata sets read_only")
+			}
+		}
+	}
+
+	// Open before backing up, so an unwritable file leaves nothing behind.
+	// Writing through the open file keeps its inode, mode and owner.
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return false, fmt.Errorf("workflow file is not writable: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := WriteBackup(path, data); err != nil {
+		return false, err
+	}
+	if err := file.Truncate(0); err != nil {
+		return false, fmt.Errorf("failed to write migrated workflow file: %w", err)
+	}
+	if _, err := file.Write(migrated); err != nil {
+		return false, fmt.Errorf("failed to write migrated workflow file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return false, fmt.Errorf("failed to write migrated workflow file: %w", err)
+	}
+	return true, nil
+}
+
+// WriteBackup saves the original contents of a workflow file about to be
+// upgraded to path + ".bak", with the file's permissions
+func WriteBackup(path string, original []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path+".bak", original, mode); err != nil {
+		return fmt.Errorf("failed to back up workflow file: %w", err)
+	}
+	return nil
+}
+
+// decodeSchemaDocument decodes a workflow document, returning it along
+// with its top-level mapping
+func decodeSchemaDocument(yamlBytes []byte) (*yaml.Node, *yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(yamlBytes, &root); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if root.Kind == 0 {
+		return nil, nil, errors.New("empty YAML input")
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, errors.New("workflow document must be a YAML mapping")
+	}
+	return &root, root.Content[0], nil
+}
+
+// schemaVersionOf returns a document's schema_version, 0 when it has none
+func schemaVersionOf(doc *yaml.Node) (int, error) {
+	value := mappingValue(doc, "schema_version")
+	if value == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(value.Value)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid schema_version: %q", value.Value)
+	}
+	if version > CurrentSchemaVersion {
+		return 0, fmt.Errorf("workflow schema version %d is newer than supported version %d; upgrade goflow to load it", version, CurrentSchemaVersion)
+	}
+	return version, nil
+}
+
+// mappingValue returns the value stored under key in a mapping node
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets a scalar in a mapping node, adding new keys first.
+// A comment heading the document stays at the top.
+func setMappingValue(mapping *yaml.Node, key, value, tag string) {
+	if existing := mappingValue(mapping, key); existing != nil {
+		existing.Kind, existing.Tag, existing.Value = yaml.ScalarNode, tag, value
+		return
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	if len(mapping.Content) > 0 {
+		keyNode.HeadComment, mapping.Content[0].HeadComment = mapping.Content[0].HeadComment, ""
+	}
+	mapping.Content = append([]*yaml.Node{keyNode, {Kind: yaml.ScalarNode, Tag: tag, Value: value}}, mapping.Content...)
+}
+
+// migrateEndReturnValue renames the return_value key that early end nodes
+// used to the return key Parse reads (schema 0 to 1)
+func migrateEndReturnValue(doc *yaml.Node) error {
+	nodes := mappingValue(doc, "nodes")
+	if nodes == nil {
+		return nil
+	}
+	if nodes.Kind != yaml.SequenceNode {
+		return errors.New("nodes must be a list")
+	}
+	for _, node := range nodes.Content {
+		if node.Kind != yaml.MappingNode {
+			continue
+		}
+		if nodeType := mappingValue(node, "type"); nodeType == nil || nodeType.Value != "end" {
+			continue
+		}
+		if mappingValue(node, "return") != nil {
+			continue
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "return_value" {
+				node.Content[i].Value = "return"
+			}
+		}
+	}
+	return nil
+}
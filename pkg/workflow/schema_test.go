@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// legacyWorkflowYAML predates schema versioning: it has no schema_version
+// and its end node uses return_value
+const legacyWorkflowYAML = `# Saved before schema versioning
+version: "1.0.0"
+name: "legacy"
+variables:
+  - name: "greeting"
+    type: "string"
+    default: "hi"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+    return_value: "${greeting}"
+edges:
+  - from: "start"
+    to: "end"
+`
+
+func TestSchemaMigrations_Registered(t *testing.T) {
+	if len(schemaMigrations) != CurrentSchemaVersion {
+		t.Fatalf("%d schema migrations for schema version %d; add one per version", len(schemaMigrations), CurrentSchemaVersion)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	migrated, from, err := Migrate([]byte(legacyWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if from != 0 {
+		t.Errorf("from = %d, want 0", from)
+	}
+	text := string(migrated)
+	if !strings.HasPrefix(text, "# Saved before schema versioning\nschema_version: 1\n") {
+		t.Errorf("migrated document does not start with the comment and schema version:\n%s", text)
+	}
+	if strings.Contains(text, "return_value") || !strings.Contains(text, `return: "${greeting}"`) {
+		t.Errorf("return_value was not renamed:\n%s", text)
+	}
+
+	// Current documents come back unchanged
+	again, from, err := Migrate(migrated)
+	if err != nil || from != CurrentSchemaVersion || string(again) != text {
+		t.Errorf("Migrate() of a current document = %q, %d, %v", again, from, err)
+	}
+
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"newer schema", "schema_version: 99\nname: x\n", "newer than supported version"},
+		{"invalid schema", "schema_version: soon\nname: x\n", "invalid schema_version"},
+		{"not a mapping", "- a\n- b\n", "must be a YAML mapping"},
+		{"empty", "# nothing\n", "empty YAML input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Migrate([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Migrate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_MigratesLegacyWorkflow(t *testing.T) {
+	wf, err := Parse([]byte(legacyWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if end, ok := wf.Nodes[1].(*EndNode); !ok || end.ReturnValue != "${greeting}" {
+		t.Errorf("end node = %#v, want the migrated return value", wf.Nodes[1])
+	}
+
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	if version, err := SchemaVersion(data); err != nil || version != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion() of saved workflow = %d, %v, want %d", version, err, CurrentSchemaVersion)
+	}
+
+	if _, err := Parse([]byte("schema_version: 99\n" + legacyWorkflowYAML)); err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Errorf("Parse() error = %v, want a newer schema error", err)
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.yaml")
+	if err := os.WriteFile(path, []byte(legacyWorkflowYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := MigrateFile(path)
+	if err != nil || !migrated {
+		t.Fatalf("MigrateFile() = %v, %v, want a migration", migrated, err)
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != legacyWorkflowYAML {
+		t.Errorf("backup = %q, %v, want the original file", backup, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := SchemaVersion(data); err != nil || version != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion() of migrated file = %d, %v", version, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("migrated file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Current files are left alone
+	if migrated, err := MigrateFile(path); err != nil || migrated {
+		t.Errorf("second MigrateFile() = %v, %v, want no migration", migrated, err)
+	}
+}
+
+func TestMigrateFile_ReadOnly(t *testing.T) {
+	dir := t.TempDir()
+
+	// Metadata marks a shared workflow read-only
+	shared := filepath.Join(dir, "shared.yaml")
+	original := "metadata:\n  read_only: true\n" + legacyWorkflowYAML
+	if err := os.WriteFile(shared, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if migrated, err := MigrateFile(shared); err == nil || migrated || !strings.Contains(err.Error(), "read_only") {
+		t.Errorf("MigrateFile() = %v, %v, want the read_only error", migrated, err)
+	}
+	assertUntouched(t, shared, original)
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write files without write permission")
+	}
+	locked := filepath.Join(dir, "locked.yaml")
+	if err := os.WriteFile(locked, []byte(legacyWorkflowYAML), 0400); err != nil {
+		t.Fatal(err)
+	}
+	if migrated, err := MigrateFile(locked); err == nil || migrated {
+		t.Errorf("MigrateFile() of unwritable file = %v, %v, want an error", migrated, err)
+	}
+	assertUntouched(t, locked, legacyWorkflowYAML)
+}
+
+// assertUntouched fails unless path still holds want and has no backup
+func assertUntouched(t *testing.T, path, want string) {
+	t.Helper()
+	if data, err := os.ReadFile(path); err != nil || string(data) != want {
+		t.Errorf("%s = %q, %v, want it unchanged", path, data, err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("%s.bak exists, want no backup", path)
+	}
+}
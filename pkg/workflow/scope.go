@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Variable scopes of loop iterations and parallel branches
+const (
+	// ScopeShared makes the variables a loop iteration or parallel branch
+	// writes visible after it (the default)
+	ScopeShared = "shared"
+	// ScopeIsolated keeps the variables a loop iteration or parallel branch
+	// writes local to it; only promoted variables reach the parent scope
+	ScopeIsolated = "isolated"
+)
+
+// Policies for parallel branches writing different values to the same
+// variable
+const (
+	// ConflictLastBranch keeps the value of the last branch in order (the
+	// default)
+	ConflictLastBranch = "last_branch"
+	// ConflictError fails the parallel node
+	ConflictError = "error"
+	// ConflictCollect sets the variable to the list of the branches' values,
+	// in branch order
+	ConflictCollect = "collect"
+)
+
+// validateScope checks a loop or parallel node's scope and promoted
+// variables
+func validateScope(kind, scope string, promote []string) error {
+	switch scope {
+	case "", ScopeShared:
+		if len(promote) > 0 {
+			return fmt.Errorf("%s node: promote requires scope %s", kind, ScopeIsolated)
+		}
+	case ScopeIsolated:
+	default:
+		return fmt.Errorf("%s node: invalid scope: %s (must be %s or %s)", kind, scope, ScopeShared, ScopeIsolated)
+	}
+	for _, name := range promote {
+		if !validVariableNameRegex.MatchString(name) {
+			return fmt.Errorf("%s node: invalid promoted variable name: %s", kind, name)
+		}
+	}
+	return nil
+}
+
+// isolatedScopes maps each loop and parallel node with an isolated scope to
+// the variables it promotes
+func (w *Workflow) isolatedScopes() map[string]map[string]bool {
+	scopes := make(map[string]map[string]bool)
+	add := func(nodeID string, promote []string) {
+		promoted := make(map[string]bool, len(promote))
+		for _, name := range promote {
+			promoted[name] = true
+		}
+		scopes[nodeID] = promoted
+	}
+	for _, node := range w.Nodes {
+		switch n := node.(type) {
+		case *LoopNode:
+			if n.Scope == ScopeIsolated {
+				add(n.ID, n.Promote)
+			}
+		case *ParallelNode:
+			if n.Scope == ScopeIsolated {
+				add(n.ID, n.Promote)
+			}
+		}
+	}
+	return scopes
+}
+
+// BranchWriteConflict is a variable that more than one branch of a parallel
+// node writes
+type BranchWriteConflict struct {
+	Variable string
+	// Branches are the indexes of the branches writing the variable
+	Branches []int
+}
+
+// BranchWriteConflicts returns the variables that more than one branch of a
+// parallel node writes, by name. Writes kept local by an isolated scope,
+// the node's own or a nested one, are left out.
+func (w *Workflow) BranchWriteConflicts(node *ParallelNode) []BranchWriteConflict {
+	writes := make(map[string][]string)
+	for _, n := range w.dataFlowNodes() {
+		writes[n.id] = n.writes
+	}
+	scopes := w.isolatedScopes()
+	containers := make(map[string]Node)
+	for _, n := range w.Nodes {
+		if n != nil {
+			containers[n.GetID()] = n
+		}
+	}
+
+	// collect adds the variables nodeID writes that reach the parallel
+	// node's scope: only, when set, limits them to promoted variables
+	var collect func(nodeID string, seen, only, written map[string]bool)
+	collect = func(nodeID string, seen, only, written map[string]bool) {
+		if seen[nodeID] {
+			return
+		}
+		seen[nodeID] = true
+		for _, name := range writes[nodeID] {
+			if only == nil || only[name] {
+				written[name] = true
+			}
+		}
+		if promoted, isolated := scopes[nodeID]; isolated {
+			narrowed := make(map[string]bool, len(promoted))
+			for name := range promoted {
+				if only == nil || only[name] {
+					narrowed[name] = true
+				}
+			}
+			only = narrowed
+		}
+		for _, child := range containedNodes(containers[nodeID]) {
+			collect(child, seen, only, written)
+		}
+	}
+
+	only := scopes[node.ID]
+	branchesByVariable := make(map[string][]int)
+	for i, branch := range node.Branches {
+		written := make(map[string]bool)
+		seen := map[string]bool{node.ID: true}
+		for _, nodeID := range branch {
+			collect(nodeID, seen, only, written)
+		}
+		for name := range written {
+			branchesByVariable[name] = append(branchesByVariable[name], i)
+		}
+	}
+
+	var conflicts []BranchWriteConflict
+	for name, branches := range branchesByVariable {
+		if len(branches) > 1 {
+			conflicts = append(conflicts, BranchWriteConflict{Variable: name, Branches: branches})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Variable < conflicts[j].Variable
+	})
+	return conflicts
+}
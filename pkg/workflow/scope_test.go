@@ -0,0 +1,137 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const isolatedLoopYAML = `
+version: "1.0.0"
+name: "isolated-loop"
+variables:
+  - name: "items"
+    type: "array"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "each"
+    type: "loop"
+    collection: "items"
+    item: "item"
+    scope: "isolated"
+    promote: [PROMOTE]
+    body: ["double", "label"]
+  - id: "double"
+    type: "transform"
+    input: "item"
+    expression: "$.value"
+    output: "doubled"
+  - id: "label"
+    type: "transform"
+    input: "doubled"
+    expression: "$.name"
+    output: "labeled"
+  - id: "end"
+    type: "end"
+    return: "${labeled}"
+edges:
+  - from: "start"
+    to: "each"
+  - from: "each"
+    to: "end"
+`
+
+func TestScope_IsolatedDataFlow(t *testing.T) {
+	wf, err := Parse([]byte(strings.Replace(isolatedLoopYAML, "PROMOTE", `"labeled"`, 1)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	loop := wf.Nodes[1].(*LoopNode)
+	if loop.Scope != ScopeIsolated || len(loop.Promote) != 1 || loop.Promote[0] != "labeled" {
+		t.Errorf("loop scope = %q, promote = %v", loop.Scope, loop.Promote)
+	}
+
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	if !strings.Contains(string(data), "scope: isolated") || !strings.Contains(string(data), "- labeled") {
+		t.Errorf("scope not saved:\n%s", data)
+	}
+
+	// Reads inside the body still see the body's own writes, but only
+	// promoted variables are visible after the loop
+	wf, err = Parse([]byte(strings.Replace(isolatedLoopYAML, "PROMOTE", `"doubled"`, 1)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	err = wf.Validate()
+	if err == nil || !strings.Contains(err.Error(), "node end reads labeled") {
+		t.Errorf("Validate() error = %v, want a read of an unpromoted variable", err)
+	}
+}
+
+func TestScope_Validate(t *testing.T) {
+	tests := map[string]struct {
+		node Node
+		want string
+	}{
+		"unknown scope": {
+			node: &LoopNode{ID: "l", Collection: "items", ItemVariable: "item", Body: []string{"b"}, Scope: "global"},
+			want: "invalid scope: global",
+		},
+		"promote without isolation": {
+			node: &LoopNode{ID: "l", Collection: "items", ItemVariable: "item", Body: []string{"b"}, Promote: []string{"x"}},
+			want: "promote requires scope isolated",
+		},
+		"invalid promoted name": {
+			node: &ParallelNode{ID: "p", Branches: [][]string{{"a"}, {"b"}}, Scope: ScopeIsolated, Promote: []string{"1x"}},
+			want: "invalid promoted variable name: 1x",
+		},
+		"unknown conflict policy": {
+			node: &ParallelNode{ID: "p", Branches: [][]string{{"a"}, {"b"}}, OnConflict: "first"},
+			want: "invalid on_conflict policy: first",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.node.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScope_BranchWriteConflicts(t *testing.T) {
+	wf, err := NewWorkflow("conflicts", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel := &ParallelNode{ID: "fan_out", Branches: [][]string{{"left"}, {"each"}, {"right"}}}
+	for _, node := range []Node{
+		parallel,
+		&TransformNode{ID: "left", InputVariable: "a", Expression: "a + 1", OutputVariable: "total"},
+		&LoopNode{ID: "each", Collection: "items", ItemVariable: "item", Body: []string{"inner"}},
+		&TransformNode{ID: "inner", InputVariable: "item", Expression: "item", OutputVariable: "total"},
+		&TransformNode{ID: "right", InputVariable: "a", Expression: "a + 2", OutputVariable: "other"},
+	} {
+		if err := wf.AddNode(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Writes in nested loop bodies count for their branch
+	conflicts := wf.BranchWriteConflicts(parallel)
+	if len(conflicts) != 1 || conflicts[0].Variable != "total" || len(conflicts[0].Branches) != 2 ||
+		conflicts[0].Branches[0] != 0 || conflicts[0].Branches[1] != 1 {
+		t.Errorf("BranchWriteConflicts() = %+v, want total in branches 0 and 1", conflicts)
+	}
+
+	// unless the loop keeps them local
+	loop := wf.Nodes[2].(*LoopNode)
+	loop.Scope = ScopeIsolated
+	if conflicts := wf.BranchWriteConflicts(parallel); len(conflicts) != 0 {
+		t.Errorf("BranchWriteConflicts() = %+v, want none for an isolated loop", conflicts)
+	}
+}
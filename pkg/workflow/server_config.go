@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 )
 
 // ServerConfig represents configuration for connecting to an MCP server
@@ -20,8 +22,132 @@ type ServerConfig struct {
 	// Transport-specific configuration
 	URL     string            `json:"url,omitempty" yaml:"url,omitempty"`         // For SSE and HTTP transports
 	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"` // For SSE and HTTP transports
+
+	// Auth authenticates requests to SSE and HTTP servers
+	Auth *ServerAuth `json:"auth,omitempty" yaml:"auth,omitempty"`
+
+	// Limits bounds concurrent and per-second requests to the server
+	Limits *ServerLimits `json:"limits,omitempty" yaml:"limits,omitempty"`
+
+	// Tags make a server with no command or URL a logical alias: the
+	// registered server carrying all of them (e.g. [db, prod]) is used.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// ToolCategories are the effects of the server's tools, by tool name or
+	// "*" for the rest: read, write, network or exec. They come from the
+	// operator's servers.yaml, never the workflow file, so a workflow cannot
+	// vouch for its own tools. Sandbox profiles that deny a category do not
+	// let undeclared tools run.
+	ToolCategories map[string][]ToolCategory `json:"-" yaml:"-"`
+}
+
+// IsAlias reports whether the server is a logical alias, to be resolved to
+// a registered server by its tags before the workflow runs
+func (s *ServerConfig) IsAlias() bool {
+	return len(s.Tags) > 0 && s.Command == "" && s.URL == ""
+}
+
+// ServerLimits caps the load placed on a server during parallel execution.
+// Requests over the limits are queued; zero values mean unlimited.
+type ServerLimits struct {
+	// MaxConcurrent is the maximum number of in-flight requests
+	MaxConcurrent int `json:"max_concurrent,omitempty" yaml:"max_concurrent,omitempty"`
+	// RequestsPerSecond is the sustained request rate
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty" yaml:"requests_per_second,omitempty"`
+	// Burst is how many requests may start at once above the rate (default: 1)
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+	// QueueTimeout is how long a request waits for a slot before failing
+	// (e.g. "10s", default: 30s)
+	QueueTimeout string `json:"queue_timeout,omitempty" yaml:"queue_timeout,omitempty"`
+}
+
+// Validate checks if the limits are valid
+func (l *ServerLimits) Validate() error {
+	if l.MaxConcurrent < 0 {
+		return errors.New("server limits: max_concurrent cannot be negative")
+	}
+	if l.RequestsPerSecond < 0 {
+		return errors.New("server limits: requests_per_second cannot be negative")
+	}
+	if l.Burst < 0 {
+		return errors.New("server limits: burst cannot be negative")
+	}
+	if l.QueueTimeout != "" {
+		if _, err := ParseDelayDuration(l.QueueTimeout); err != nil {
+			return fmt.Errorf("server limits: queue_timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+// QueueDuration returns how long a request waits for a slot, or 0 when
+// QueueTimeout is unset and the client default applies
+func (l *ServerLimits) QueueDuration() time.Duration {
+	if l.QueueTimeout == "" {
+		return 0
+	}
+	timeout, err := ParseDelayDuration(l.QueueTimeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// ServerAuth authenticates requests to an SSE or HTTP server. Fields ending
+// in _ref name entries in the credential store (see `goflow credential add`),
+// resolved when the server connects, so secrets never appear in the workflow.
+type ServerAuth struct {
+	// Type is "bearer", "api_key" or "oauth2_client_credentials"
+	Type string `json:"type" yaml:"type"`
+	// TokenRef references the bearer token
+	TokenRef string `json:"token_ref,omitempty" yaml:"token_ref,omitempty"`
+	// HeaderName is the header carrying the API key (default: X-API-Key)
+	HeaderName string `json:"header_name,omitempty" yaml:"header_name,omitempty"`
+	// KeyRef references the API key
+	KeyRef string `json:"key_ref,omitempty" yaml:"key_ref,omitempty"`
+	// TokenURL is the OAuth2 token endpoint
+	TokenURL string `json:"token_url,omitempty" yaml:"token_url,omitempty"`
+	// ClientID is the OAuth2 client identifier
+	ClientID string `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	// ClientSecretRef references the OAuth2 client secret
+	ClientSecretRef string `json:"client_secret_ref,omitempty" yaml:"client_secret_ref,omitempty"`
+	// Scopes are requested with the OAuth2 token
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
 }
 
+// Validate checks that the auth settings are complete for their type
+func (a *ServerAuth) Validate() error {
+	switch a.Type {
+	case "bearer":
+		if a.TokenRef == "" {
+			return errors.New("server auth: token_ref is required for bearer auth")
+		}
+	case "api_key":
+		if a.KeyRef == "" {
+			return errors.New("server auth: key_ref is required for api_key auth")
+		}
+		if strings.ContainsAny(a.HeaderName, " :\r\n") {
+			return fmt.Errorf("server auth: invalid header_name %q", a.HeaderName)
+		}
+	case "oauth2_client_credentials":
+		if !strings.HasPrefix(a.TokenURL, "http://") && !strings.HasPrefix(a.TokenURL, "https://") {
+			return errors.New("server auth: token_url must start with http:// or https:// for oauth2_client_credentials auth")
+		}
+		if a.ClientID == "" {
+			return errors.New("server auth: client_id is required for oauth2_client_credentials auth")
+		}
+		if a.ClientSecretRef == "" {
+			return errors.New("server auth: client_secret_ref is required for oauth2_client_credentials auth")
+		}
+	default:
+		return fmt.Errorf("server auth: invalid type %q (must be one of: bearer, api_key, oauth2_client_credentials)", a.Type)
+	}
+	return nil
+}
+
+// serverTagRegex matches a server tag, as in the server registry
+var serverTagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
 // validTransportTypes are the allowed transport types
 var validTransportTypes = map[string]bool{
 	"stdio": true,
@@ -51,6 +177,32 @@ func (s *ServerConfig) Validate() error {
 		return fmt.Errorf("server config: invalid transport type: %s (must be one of: stdio, sse, http)", transport)
 	}
 
+	if s.Limits != nil {
+		if err := s.Limits.Validate(); err != nil {
+			return fmt.Errorf("server config: %w", err)
+		}
+	}
+	if s.Auth != nil {
+		if transport == "stdio" {
+			return errors.New("server config: auth is only supported for sse and http transports")
+		}
+		if err := s.Auth.Validate(); err != nil {
+			return fmt.Errorf("server config: %w", err)
+		}
+	}
+
+	for _, tag := range s.Tags {
+		if !serverTagRegex.MatchString(tag) {
+			return fmt.Errorf("server config: invalid tag %q (use lowercase letters, digits, dashes and underscores)", tag)
+		}
+	}
+	if err := validateToolCategories(s.ToolCategories); err != nil {
+		return fmt.Errorf("server config: %w", err)
+	}
+	if s.IsAlias() {
+		return nil
+	}
+
 	// Validate transport-specific configuration
 	switch transport {
 	case "stdio":
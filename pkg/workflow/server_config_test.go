@@ -256,6 +256,122 @@ func TestServerConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+
+		// Server aliases
+		{
+			name: "alias selected by tags",
+			config: ServerConfig{
+				ID:   "db",
+				Tags: []string{"db", "prod"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "alias with invalid tag",
+			config: ServerConfig{
+				ID:   "db",
+				Tags: []string{"Prod DB"},
+			},
+			wantErr: true,
+			errMsg:  "invalid tag",
+		},
+		{
+			name: "tagged server with a command is not an alias",
+			config: ServerConfig{
+				ID:        "db",
+				Tags:      []string{"db"},
+				Transport: "sse",
+				Command:   "python",
+			},
+			wantErr: true,
+			errMsg:  "URL is required",
+		},
+		{
+			name: "http server with bearer auth",
+			config: ServerConfig{
+				ID:        "api",
+				Transport: "http",
+				URL:       "https://api.example.com/mcp",
+				Auth:      &ServerAuth{Type: "bearer", TokenRef: "api:token"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bearer auth without token_ref",
+			config: ServerConfig{
+				ID:        "api",
+				Transport: "http",
+				URL:       "https://api.example.com/mcp",
+				Auth:      &ServerAuth{Type: "bearer"},
+			},
+			wantErr: true,
+			errMsg:  "token_ref is required",
+		},
+		{
+			name: "oauth2 auth without client secret",
+			config: ServerConfig{
+				ID:        "api",
+				Transport: "sse",
+				URL:       "https://api.example.com/sse",
+				Auth: &ServerAuth{
+					Type:     "oauth2_client_credentials",
+					TokenURL: "https://auth.example.com/token",
+					ClientID: "goflow",
+				},
+			},
+			wantErr: true,
+			errMsg:  "client_secret_ref is required",
+		},
+		{
+			name: "unknown auth type",
+			config: ServerConfig{
+				ID:        "api",
+				Transport: "http",
+				URL:       "https://api.example.com/mcp",
+				Auth:      &ServerAuth{Type: "basic"},
+			},
+			wantErr: true,
+			errMsg:  "invalid type",
+		},
+		{
+			name: "queue timeout duration",
+			config: ServerConfig{
+				ID:      "limited",
+				Command: "python",
+				Limits:  &ServerLimits{MaxConcurrent: 2, QueueTimeout: "10s"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "queue timeout not a duration",
+			config: ServerConfig{
+				ID:      "limited",
+				Command: "python",
+				Limits:  &ServerLimits{QueueTimeout: "soon"},
+			},
+			wantErr: true,
+			errMsg:  "queue_timeout: invalid duration",
+		},
+		{
+			name: "negative queue timeout",
+			config: ServerConfig{
+				ID:      "limited",
+				Command: "python",
+				Limits:  &ServerLimits{QueueTimeout: "-1s"},
+			},
+			wantErr: true,
+			errMsg:  "queue_timeout: duration \"-1s\" must be positive",
+		},
+		{
+			name: "auth on a stdio server",
+			config: ServerConfig{
+				ID:      "local",
+				Command: "python",
+				Auth:    &ServerAuth{Type: "bearer", TokenRef: "local:token"},
+			},
+			wantErr: true,
+			errMsg:  "only supported for sse and http",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,224 @@
+package workflow
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// signatureTrailer starts the comment line carrying a workflow file's
+// signature. The signature covers every byte before the line, so it travels
+// with the file through export, import and copying, and any edit breaks it:
+//
+//	# goflow-signature: ed25519 <key-id> <base64 signature>
+const signatureTrailer = "# goflow-signature: "
+
+// Signature verification errors
+var (
+	// ErrUnsigned means the workflow file has no signature
+	ErrUnsigned = errors.New("workflow is not signed")
+	// ErrUntrustedKey means the workflow is signed by a key that is not trusted
+	ErrUntrustedKey = errors.New("workflow is signed by an untrusted key")
+	// ErrBadSignature means the workflow changed since it was signed, or the
+	// signature is malformed
+	ErrBadSignature = errors.New("workflow signature does not match its content")
+)
+
+// KeyID identifies a public key by the start of its SHA-256
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SignWorkflowFile returns the workflow file data signed with key,
+// replacing any signature it had
+func SignWorkflowFile(data []byte, key ed25519.PrivateKey) []byte {
+	content, _, _ := splitSignature(data)
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content[:len(content):len(content)], '\n')
+	}
+	publicKey, _ := key.Public().(ed25519.PublicKey) // Always an ed25519.PublicKey
+	signature := ed25519.Sign(key, content)
+	trailer := fmt.Sprintf("%sed25519 %s %s\n", signatureTrailer, KeyID(publicKey), base64.StdEncoding.EncodeToString(signature))
+	return append(content[:len(content):len(content)], trailer...)
+}
+
+// VerifyWorkflowFile checks the signature of workflow file data against the
+// trusted keys, returning the ID of the key that signed it. The error wraps
+// ErrUnsigned, ErrUntrustedKey or ErrBadSignature.
+func VerifyWorkflowFile(data []byte, trusted map[string]ed25519.PublicKey) (string, error) {
+	content, trailer, found := splitSignature(data)
+	if !found {
+		return "", ErrUnsigned
+	}
+	fields := strings.Fields(trailer)
+	if len(fields) != 3 || fields[0] != "ed25519" {
+		return "", fmt.Errorf("%w: unsupported signature %q", ErrBadSignature, trailer)
+	}
+	keyID := fields[1]
+	signature, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return keyID, fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	key, ok := trusted[keyID]
+	if !ok {
+		return keyID, fmt.Errorf("%w %s", ErrUntrustedKey, keyID)
+	}
+	if !ed25519.Verify(key, content, signature) {
+		return keyID, fmt.Errorf("%w (key %s)", ErrBadSignature, keyID)
+	}
+	return keyID, nil
+}
+
+// splitSignature separates the signed content from the signature trailer,
+// the file's last non-empty line, reporting whether there is one
+func splitSignature(data []byte) (content []byte, trailer string, found bool) {
+	trimmed := bytes.TrimRight(data, " \t\r\n")
+	start := bytes.LastIndexByte(trimmed, '\n') + 1
+	line := string(trimmed[start:])
+	if !strings.HasPrefix(line, signatureTrailer) {
+		return data, "", false
+	}
+	return data[:start], strings.TrimPrefix(line, signatureTrailer), true
+}
+
+// GenerateSigningKey creates an ed25519 key pair in dir as <name>.key,
+// readable only by its owner, and <name>.pub, returning the public key
+func GenerateSigningKey(dir, name string) (ed25519.PublicKey, error) {
+	keyPath := filepath.Join(dir, name+".key")
+	if _, err := os.Stat(keyPath); err == nil {
+		return nil, fmt.Errorf("signing key %s already exists", keyPath)
+	}
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keys directory: %w", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write signing key: %w", err)
+	}
+	if err := WritePublicKey(filepath.Join(dir, name+".pub"), publicKey); err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
+
+// WritePublicKey writes a public key file
+func WritePublicKey(path string, key ed25519.PublicKey) error {
+	publicDER, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode public key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create keys directory: %w", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	return nil
+}
+
+// LoadSigningKey reads a private key file written by GenerateSigningKey
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an ed25519 key", path)
+	}
+	return privateKey, nil
+}
+
+// LoadPublicKey reads a public key file
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %w", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an ed25519 key", path)
+	}
+	return publicKey, nil
+}
+
+// readPEM reads the PEM block of the given type from path
+func readPEM(path, blockType string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s is not a PEM %s file", path, strings.ToLower(blockType))
+	}
+	return block, nil
+}
+
+// TrustedKey is a public key trusted to sign workflows
+type TrustedKey struct {
+	Name string // File name without the .pub extension
+	ID   string
+	Key  ed25519.PublicKey
+}
+
+// LoadTrustedKeys reads the .pub files in dir, sorted by name. A missing
+// directory trusts no keys.
+func LoadTrustedKeys(dir string) ([]TrustedKey, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trusted keys: %w", err)
+	}
+	var keys []TrustedKey
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pub" {
+			continue
+		}
+		key, err := LoadPublicKey(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, TrustedKey{Name: strings.TrimSuffix(entry.Name(), ".pub"), ID: KeyID(key), Key: key})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys, nil
+}
+
+// TrustedKeyMap indexes trusted keys by ID, for VerifyWorkflowFile
+func TrustedKeyMap(keys []TrustedKey) map[string]ed25519.PublicKey {
+	byID := make(map[string]ed25519.PublicKey, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key.Key
+	}
+	return byID
+}
@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignWorkflowFile(t *testing.T) {
+	dir := t.TempDir()
+	publicKey, err := GenerateSigningKey(dir, "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := LoadSigningKey(filepath.Join(dir, "release.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateSigningKey(dir, "release"); err == nil {
+		t.Error("expected an existing key not to be overwritten")
+	}
+	trusted, err := LoadTrustedKeys(dir)
+	if err != nil || len(trusted) != 1 || trusted[0].Name != "release" || trusted[0].ID != KeyID(publicKey) {
+		t.Fatalf("LoadTrustedKeys = %+v, %v", trusted, err)
+	}
+	keys := TrustedKeyMap(trusted)
+
+	data := []byte("name: deploy\nnodes: []\n")
+	signed := SignWorkflowFile(data, privateKey)
+	if !strings.HasPrefix(string(signed), string(data)) {
+		t.Fatalf("signing changed the content:\n%s", signed)
+	}
+	keyID, err := VerifyWorkflowFile(signed, keys)
+	if err != nil || keyID != KeyID(publicKey) {
+		t.Fatalf("VerifyWorkflowFile = %q, %v", keyID, err)
+	}
+
+	// Signing again replaces the signature rather than signing it
+	if resigned := SignWorkflowFile(signed, privateKey); string(resigned) != string(signed) {
+		t.Errorf("re-signing gave:\n%s", resigned)
+	}
+
+	tampered := []byte(strings.Replace(string(signed), "deploy", "deplo1", 1))
+	if _, err := VerifyWorkflowFile(tampered, keys); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("tampered file error = %v", err)
+	}
+	if _, err := VerifyWorkflowFile(data, keys); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("unsigned file error = %v", err)
+	}
+	_, other, _ := ed25519.GenerateKey(nil)
+	if _, err := VerifyWorkflowFile(SignWorkflowFile(data, other), keys); !errors.Is(err, ErrUntrustedKey) {
+		t.Errorf("untrusted key error = %v", err)
+	}
+
+	// A file without a trailing newline is signed as if it had one
+	signed = SignWorkflowFile([]byte("name: deploy"), privateKey)
+	if _, err := VerifyWorkflowFile(signed, keys); err != nil || !strings.HasPrefix(string(signed), "name: deploy\n") {
+		t.Errorf("VerifyWorkflowFile = %v\n%s", err, signed)
+	}
+
+	if keys, err := LoadTrustedKeys(filepath.Join(dir, "missing")); err != nil || len(keys) != 0 {
+		t.Errorf("missing directory = %v, %v", keys, err)
+	}
+}
@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const switchWorkflowYAML = `
+version: "1.0.0"
+name: "switch-test"
+variables:
+  - name: "total"
+    type: "number"
+    default: 0
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "size"
+    type: "switch"
+    cases:
+      - label: "large"
+        condition: "total > 100"
+      - label: "medium"
+        condition: "total > 10"
+  - id: "large_path"
+    type: "passthrough"
+  - id: "medium_path"
+    type: "passthrough"
+  - id: "small_path"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "size"
+  - from: "size"
+    to: "large_path"
+    condition: "large"
+  - from: "size"
+    to: "medium_path"
+    condition: "medium"
+  - from: "size"
+    to: "small_path"
+    condition: "default"
+  - from: "large_path"
+    to: "end"
+  - from: "medium_path"
+    to: "end"
+  - from: "small_path"
+    to: "end"
+`
+
+func TestSwitchNode_ParseAndRoundTrip(t *testing.T) {
+	wf, err := Parse([]byte(switchWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := ValidateWorkflow(wf); err != nil {
+		t.Fatalf("ValidateWorkflow() error = %v", err)
+	}
+
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() of serialized workflow error = %v", err)
+	}
+
+	for _, w := range []*Workflow{wf, again} {
+		sw, ok := w.Nodes[1].(*SwitchNode)
+		if !ok {
+			t.Fatalf("node is %T, want *SwitchNode", w.Nodes[1])
+		}
+		if len(sw.Cases) != 2 || sw.Cases[0] != (SwitchCase{Label: "large", Condition: "total > 100"}) {
+			t.Errorf("cases = %+v", sw.Cases)
+		}
+	}
+}
+
+func TestSwitchNode_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		cases []SwitchCase
+		want  string
+	}{
+		{name: "no cases", want: "no cases"},
+		{name: "empty label", cases: []SwitchCase{{Condition: "true"}}, want: "empty label"},
+		{name: "reserved label", cases: []SwitchCase{{Label: "default", Condition: "true"}}, want: "reserved"},
+		{name: "duplicate label", cases: []SwitchCase{{Label: "a", Condition: "x"}, {Label: "a", Condition: "y"}}, want: "duplicate"},
+		{name: "empty condition", cases: []SwitchCase{{Label: "a"}}, want: "empty condition"},
+		{name: "valid", cases: []SwitchCase{{Label: "a", Condition: "x > 1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&SwitchNode{ID: "sw", Cases: tt.cases}).Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwitchNode_EdgeValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(wf *Workflow)
+		want string
+	}{
+		{
+			name: "missing default",
+			edit: func(wf *Workflow) { _ = wf.RemoveNode("small_path") },
+			want: `must have a "default" edge`,
+		},
+		{
+			name: "exhaustive without default",
+			edit: func(wf *Workflow) {
+				_ = wf.RemoveNode("small_path")
+				wf.Nodes[1].(*SwitchNode).Cases[1].Condition = "true"
+			},
+		},
+		{
+			name: "case without edge",
+			edit: func(wf *Workflow) { _ = wf.RemoveNode("medium_path") },
+			want: `no edge for case "medium"`,
+		},
+		{
+			name: "unknown label",
+			edit: func(wf *Workflow) {
+				for _, edge := range wf.Edges {
+					if edge.ToNodeID == "small_path" {
+						edge.Condition = "tiny"
+					}
+				}
+			},
+			want: "must be labeled with a case",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := Parse([]byte(switchWorkflowYAML))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			tt.edit(wf)
+
+			err = wf.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
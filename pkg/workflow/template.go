@@ -63,6 +63,7 @@ type EdgeSpec struct {
 	From      string `json:"from" yaml:"from"`
 	To        string `json:"to" yaml:"to"`
 	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+	OnError   bool   `json:"on_error,omitempty" yaml:"on_error,omitempty"`
 }
 
 // WorkflowSpec defines the parameterized workflow structure
@@ -78,6 +79,18 @@ type WorkflowTemplate struct {
 	Version      string              `json:"version" yaml:"version"`
 	Parameters   []TemplateParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	WorkflowSpec WorkflowSpec        `json:"workflow_spec" yaml:"workflow_spec"`
+
+	// Extends names a base template whose parameters, nodes and edges this
+	// template inherits and may override (see ComposeTemplate)
+	Extends string `json:"extends,omitempty" yaml:"extends,omitempty"`
+	// Includes composes fragments from other templates into this one
+	Includes []TemplateInclude `json:"includes,omitempty" yaml:"includes,omitempty"`
+	// Fragment marks a template that is only meant to be included, such as
+	// a shared error notification step; it need not be a complete workflow
+	Fragment bool `json:"fragment,omitempty" yaml:"fragment,omitempty"`
+	// Migrations describe how to upgrade workflows instantiated from earlier
+	// versions of this template (see UpgradeWorkflow)
+	Migrations []TemplateMigration `json:"migrations,omitempty" yaml:"migrations,omitempty"`
 }
 
 // InstantiateTemplate creates a concrete workflow from a template and parameter values
@@ -86,6 +99,9 @@ func InstantiateTemplate(ctx context.Context, template *WorkflowTemplate, params
 	if err := validateTemplate(template); err != nil {
 		return nil, err
 	}
+	if template.Fragment {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateIsFragment, template.Name)
+	}
 
 	// Step 2: Check that all referenced parameters in the workflow spec are defined
 	// This must happen before merging params to catch undefined parameters early
@@ -115,6 +131,22 @@ func InstantiateTemplate(ctx context.Context, template *WorkflowTemplate, params
 		return nil, err
 	}
 
+	// Step 7: Record the template so the workflow can be upgraded later
+	nodeIDs := make([]string, 0, len(workflow.Nodes))
+	for _, node := range workflow.Nodes {
+		nodeIDs = append(nodeIDs, node.GetID())
+	}
+	provided := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		provided[name] = value
+	}
+	workflow.Metadata.Template = &TemplateSource{
+		Name:       template.Name,
+		Version:    template.Version,
+		Parameters: provided,
+		Nodes:      nodeIDs,
+	}
+
 	return workflow, nil
 }
 
@@ -131,6 +163,13 @@ func validateTemplate(template *WorkflowTemplate) error {
 	if template.Version == "" {
 		return fmt.Errorf("%w: template version is required", ErrInvalidTemplate)
 	}
+	if _, err := ParseTemplateVersion(template.Version); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	if template.Extends != "" || len(template.Includes) > 0 {
+		return fmt.Errorf("%w: %s", ErrTemplateNotComposed, template.Name)
+	}
 
 	// Check for duplicate parameter names
 	paramNames := make(map[string]bool)
@@ -505,6 +544,7 @@ func instantiateWorkflow(template *WorkflowTemplate, params map[string]interface
 			FromNodeID: edgeSpec.From,
 			ToNodeID:   edgeSpec.To,
 			Condition:  edgeSpec.Condition,
+			OnError:    edgeSpec.OnError,
 		}
 
 		workflow.Edges = append(workflow.Edges, edge)
@@ -661,6 +701,18 @@ func createNodeFromSpec(spec NodeSpec, config map[string]interface{}) (Node, err
 			Config:        config,
 		}, nil
 
+	case "switch":
+		node := &SwitchNode{ID: spec.ID}
+		if cases, ok := config["cases"].([]interface{}); ok {
+			for _, item := range cases {
+				c, _ := item.(map[string]interface{})
+				label, _ := c["label"].(string)
+				condition, _ := c["condition"].(string)
+				node.Cases = append(node.Cases, SwitchCase{Label: label, Condition: condition})
+			}
+		}
+		return node, nil
+
 	case "passthrough":
 		return &PassthroughNode{ID: spec.ID}, nil
 
@@ -672,6 +724,9 @@ func createNodeFromSpec(spec NodeSpec, config map[string]interface{}) (Node, err
 		if branches, ok := config["branches"].([][]string); ok {
 			node.Branches = branches
 		}
+		node.Scope, _ = config["scope"].(string)
+		node.Promote, _ = config["promote"].([]string)
+		node.OnConflict, _ = config["on_conflict"].(string)
 		return node, nil
 
 	case "loop":
@@ -688,6 +743,70 @@ func createNodeFromSpec(spec NodeSpec, config map[string]interface{}) (Node, err
 		if breakCond, ok := config["break_condition"].(string); ok {
 			node.BreakCondition = breakCond
 		}
+		node.Scope, _ = config["scope"].(string)
+		node.Promote, _ = config["promote"].([]string)
+		return node, nil
+
+	case "try":
+		node := &TryNode{ID: spec.ID}
+		if body, ok := config["body"].([]string); ok {
+			node.Body = body
+		}
+		return node, nil
+
+	case "catch":
+		node := &CatchNode{ID: spec.ID}
+		if errorVar, ok := config["error_variable"].(string); ok {
+			node.ErrorVariable = errorVar
+		}
+		return node, nil
+
+	case "delay":
+		node := &DelayNode{ID: spec.ID}
+		if duration, ok := config["duration"].(string); ok {
+			node.Duration = duration
+		}
+		if until, ok := config["until"].(string); ok {
+			node.Until = until
+		}
+		return node, nil
+
+	case "approval":
+		node := &ApprovalNode{ID: spec.ID}
+		if message, ok := config["message"].(string); ok {
+			node.Message = message
+		}
+		if timeout, ok := config["timeout"].(string); ok {
+			node.Timeout = timeout
+		}
+		if action, ok := config["default_action"].(string); ok {
+			node.DefaultAction = action
+		}
+		if output, ok := config["output"].(string); ok {
+			node.OutputVariable = output
+		}
+		return node, nil
+
+	case "batch_tool":
+		node := &BatchToolNode{ID: spec.ID}
+		node.ServerID, _ = config["server"].(string)
+		node.ToolName, _ = config["tool"].(string)
+		node.Collection, _ = config["collection"].(string)
+		node.ItemVariable = configString(config, "item", "item_variable")
+		node.OutputVariable = configString(config, "output", "output_variable")
+		node.ErrorVariable, _ = config["error_variable"].(string)
+		if params, ok := config["parameters"].(map[string]interface{}); ok {
+			node.Parameters = make(map[string]string, len(params))
+			for name, value := range params {
+				node.Parameters[name] = formatValue(value)
+			}
+		}
+		switch limit := config["max_concurrency"].(type) {
+		case int:
+			node.MaxConcurrency = limit
+		case float64:
+			node.MaxConcurrency = int(limit)
+		}
 		return node, nil
 
 	default:
@@ -828,3 +947,128 @@ func (n *GenericMCPToolNode) MarshalJSON() ([]byte, error) {
 func (n *GenericMCPToolNode) GetConfiguration() map[string]interface{} {
 	return n.Config
 }
+
+// ValidateTemplate checks a template against the same rules the engine
+// applies to workflows, without needing parameter values. Parameters without
+// defaults get a placeholder value of their type, the workflow is
+// instantiated from workflow_spec, and the result is checked for unknown node
+// types, duplicate IDs, cycles, orphaned nodes, bad edges and invalid
+// expressions. Every problem found is returned, joined with errors.Join.
+// Fragments are checked node by node but not as a complete workflow.
+func ValidateTemplate(template *WorkflowTemplate) error {
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+	if err := validateParameterReferences(template, nil); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	// Defaults must satisfy their own parameter definitions
+	params := make(map[string]interface{}, len(template.Parameters))
+	for _, param := range template.Parameters {
+		if param.Default != nil {
+			params[param.Name] = param.Default
+		}
+	}
+	if err := validateParameterTypes(template.Parameters, params); err != nil {
+		errs = append(errs, fmt.Errorf("default value: %w", err))
+	}
+	if err := validateParameterConstraints(template.Parameters, params); err != nil {
+		errs = append(errs, fmt.Errorf("default value: %w", err))
+	}
+	for _, param := range template.Parameters {
+		if _, ok := params[param.Name]; !ok || !isCorrectType(params[param.Name], param.Type) {
+			params[param.Name] = placeholderValue(param.Type)
+		}
+	}
+
+	// Report every unknown node type rather than stopping at the first
+	known := true
+	for _, spec := range template.WorkflowSpec.Nodes {
+		if _, err := createNodeFromSpec(NodeSpec{ID: spec.ID, Type: spec.Type}, map[string]interface{}{}); err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", spec.ID, err))
+			known = false
+		}
+	}
+	if !known {
+		return errors.Join(errs...)
+	}
+
+	wf, err := instantiateWorkflow(template, params)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	if !template.Fragment {
+		if err := wf.Validate(); err != nil {
+			for _, msg := range strings.Split(err.Error(), "; ") {
+				errs = append(errs, errors.New(msg))
+			}
+		}
+	}
+
+	// Template nodes keep their raw config, which Validate does not inspect
+	for _, node := range wf.Nodes {
+		if err := node.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", node.GetID(), err))
+			continue
+		}
+		if err := validateTemplateNodeExpressions(node); err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", node.GetID(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// placeholderValue returns a value of the given parameter type used to
+// instantiate a template for validation
+func placeholderValue(paramType ParameterType) interface{} {
+	switch paramType {
+	case ParameterTypeNumber:
+		return 1
+	case ParameterTypeBoolean:
+		return true
+	case ParameterTypeArray:
+		return []interface{}{}
+	default:
+		return "placeholder"
+	}
+}
+
+// validateTemplateNodeExpressions checks the syntax of condition and
+// transform expressions held in a template node's config
+func validateTemplateNodeExpressions(node Node) error {
+	switch n := node.(type) {
+	case *GenericConditionNode:
+		condition := strings.TrimSpace(n.BaseCondition.Condition)
+		if condition == "" {
+			return errors.New("condition expression cannot be empty")
+		}
+		// Conditions may be wrapped in a single ${...} template
+		if strings.HasPrefix(condition, "${") && strings.HasSuffix(condition, "}") && strings.Count(condition, "${") == 1 {
+			condition = condition[2 : len(condition)-1]
+		}
+		if err := validateExpressionSyntax(condition); err != nil {
+			return fmt.Errorf("invalid condition expression: %w", err)
+		}
+	case *GenericTransformNode:
+		expr, _ := n.Config["expression"].(string)
+		if expr == "" {
+			return errors.New("transform expression cannot be empty")
+		}
+		if expr[0] == '$' && !strings.HasPrefix(expr, "${") {
+			if err := validateJSONPathSyntax(expr); err != nil {
+				return fmt.Errorf("invalid JSONPath expression: %w", err)
+			}
+		}
+		if containsTemplate(expr) {
+			if err := validateTemplateSyntax(expr); err != nil {
+				return fmt.Errorf("invalid template syntax: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template composition errors
+var (
+	ErrTemplateCycle        = errors.New("template composition cycle")
+	ErrTemplateNotComposed  = errors.New("template uses extends or includes and must be composed first")
+	ErrTemplateIsFragment   = errors.New("fragment templates can only be included")
+	ErrInvalidTemplateUsage = errors.New("invalid template include")
+)
+
+// TemplateInclude composes the nodes and edges of another template into this
+// one. Included node IDs and unbound parameter names are prefixed with
+// "<as>." so that several fragments, or the same fragment twice, never
+// collide with each other or with the including template.
+type TemplateInclude struct {
+	// Template is the name of the included template
+	Template string `json:"template" yaml:"template"`
+	// As is the namespace for the included nodes and parameters
+	As string `json:"as" yaml:"as"`
+	// Parameters binds parameters of the included template to values, which
+	// may themselves be placeholders for parameters of this template
+	Parameters map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// TemplateResolver returns the template with the given name
+type TemplateResolver func(name string) (*WorkflowTemplate, error)
+
+// DirectoryTemplateResolver resolves template names to <dir>/<name>.yaml,
+// falling back to <dir>/<name>.yml
+func DirectoryTemplateResolver(dir string) TemplateResolver {
+	return func(name string) (*WorkflowTemplate, error) {
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return nil, fmt.Errorf("invalid template name: %q", name)
+		}
+
+		path := filepath.Join(dir, name+".yaml")
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			path = filepath.Join(dir, name+".yml")
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("template %s not found in %s: %w", name, dir, err)
+		}
+
+		var template WorkflowTemplate
+		if err := yaml.Unmarshal(data, &template); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		return &template, nil
+	}
+}
+
+// ComposeTemplate resolves a template's extends and includes into a single
+// self-contained template.
+//
+// A template that extends a base inherits the base's parameters, nodes and
+// edges. Its own parameters replace base parameters of the same name, its
+// own nodes replace base nodes with the same ID, and its edges are added to
+// the base's. Includes then add the nodes and edges of each fragment under
+// the include's namespace. Bases and fragments may themselves extend or
+// include other templates; a template that reaches itself is an error.
+func ComposeTemplate(template *WorkflowTemplate, resolve TemplateResolver) (*WorkflowTemplate, error) {
+	if template == nil {
+		return nil, fmt.Errorf("%w: template is nil", ErrInvalidTemplate)
+	}
+	if template.Extends == "" && len(template.Includes) == 0 {
+		return template, nil
+	}
+	if resolve == nil {
+		return nil, fmt.Errorf("%w: no template resolver", ErrTemplateNotComposed)
+	}
+	return composeTemplate(template, resolve, []string{template.Name})
+}
+
+// composeTemplate composes a template; chain holds the names of the
+// templates currently being composed, outermost first
+func composeTemplate(template *WorkflowTemplate, resolve TemplateResolver, chain []string) (*WorkflowTemplate, error) {
+	composed := &WorkflowTemplate{
+		Name:        template.Name,
+		Description: template.Description,
+		Version:     template.Version,
+		Fragment:    template.Fragment,
+		Migrations:  template.Migrations,
+	}
+
+	if template.Extends != "" {
+		base, err := resolveComposed(template.Extends, resolve, chain)
+		if err != nil {
+			return nil, err
+		}
+		composed.Parameters = append(composed.Parameters, base.Parameters...)
+		composed.WorkflowSpec.Nodes = append(composed.WorkflowSpec.Nodes, base.WorkflowSpec.Nodes...)
+		composed.WorkflowSpec.Edges = append(composed.WorkflowSpec.Edges, base.WorkflowSpec.Edges...)
+		if composed.Description == "" {
+			composed.Description = base.Description
+		}
+	}
+
+	composed.Parameters = mergeParameters(composed.Parameters, template.Parameters)
+	composed.WorkflowSpec.Nodes = mergeNodeSpecs(composed.WorkflowSpec.Nodes, template.WorkflowSpec.Nodes)
+	composed.WorkflowSpec.Edges = mergeEdgeSpecs(composed.WorkflowSpec.Edges, template.WorkflowSpec.Edges)
+
+	namespaces := make(map[string]bool, len(template.Includes))
+	for _, include := range template.Includes {
+		if include.Template == "" {
+			return nil, fmt.Errorf("%w: include in %s has no template name", ErrInvalidTemplateUsage, template.Name)
+		}
+		if include.As == "" || strings.ContainsAny(include.As, ".{} ") {
+			return nil, fmt.Errorf("%w: include of %s in %s needs an 'as' namespace without dots, braces or spaces", ErrInvalidTemplateUsage, include.Template, template.Name)
+		}
+		if namespaces[include.As] {
+			return nil, fmt.Errorf("%w: namespace %s is used twice in %s", ErrInvalidTemplateUsage, include.As, template.Name)
+		}
+		namespaces[include.As] = true
+
+		fragment, err := resolveComposed(include.Template, resolve, chain)
+		if err != nil {
+			return nil, err
+		}
+		if err := includeFragment(composed, fragment, include); err != nil {
+			return nil, err
+		}
+	}
+
+	return composed, nil
+}
+
+// resolveComposed resolves a template by name and composes it, detecting
+// cycles through chain
+func resolveComposed(name string, resolve TemplateResolver, chain []string) (*WorkflowTemplate, error) {
+	for _, seen := range chain {
+		if seen == name {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrTemplateCycle, strings.Join(chain, " -> "), name)
+		}
+	}
+
+	template, err := resolve(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve template %s: %w", name, err)
+	}
+	if template == nil {
+		return nil, fmt.Errorf("failed to resolve template %s: not found", name)
+	}
+
+	next := append(append([]string(nil), chain...), name)
+	return composeTemplate(template, resolve, next)
+}
+
+// includeFragment adds a composed fragment's nodes, edges and unbound
+// parameters to composed under the include's namespace
+func includeFragment(composed, fragment *WorkflowTemplate, include TemplateInclude) error {
+	// Fragment placeholders must refer to the fragment's own parameters;
+	// otherwise they would silently bind to the includer's parameters
+	if err := validateParameterReferences(fragment, nil); err != nil {
+		return fmt.Errorf("included template %s: %w", include.Template, err)
+	}
+
+	declared := make(map[string]bool, len(fragment.Parameters))
+	for _, param := range fragment.Parameters {
+		declared[param.Name] = true
+	}
+	for name := range include.Parameters {
+		if !declared[name] {
+			return fmt.Errorf("%w: %s has no parameter %s", ErrInvalidTemplateUsage, include.Template, name)
+		}
+	}
+
+	existing := make(map[string]bool, len(composed.Parameters))
+	for _, param := range composed.Parameters {
+		existing[param.Name] = true
+	}
+
+	// Bound parameters take their binding; unbound ones become namespaced
+	// parameters of the composed template
+	substitutions := make(map[string]interface{}, len(fragment.Parameters))
+	for _, param := range fragment.Parameters {
+		if value, ok := include.Parameters[param.Name]; ok {
+			substitutions[param.Name] = value
+			continue
+		}
+		param.Name = include.As + "." + param.Name
+		if existing[param.Name] {
+			return fmt.Errorf("%w: parameter %s is already defined", ErrInvalidTemplateUsage, param.Name)
+		}
+		substitutions[strings.TrimPrefix(param.Name, include.As+".")] = "{{" + param.Name + "}}"
+		composed.Parameters = append(composed.Parameters, param)
+	}
+
+	prefix := include.As + "."
+	for _, spec := range fragment.WorkflowSpec.Nodes {
+		config, err := substituteParameters(spec.Config, substitutions)
+		if err != nil {
+			return fmt.Errorf("included template %s node %s: %w", include.Template, spec.ID, err)
+		}
+
+		node := NodeSpec{ID: prefix + spec.ID, Type: spec.Type, Config: config}
+		if spec.Condition != "" {
+			condition, err := substituteString(spec.Condition, substitutions)
+			if err != nil {
+				return fmt.Errorf("included template %s node %s: %w", include.Template, spec.ID, err)
+			}
+			// A condition bound to a literal is decided now
+			switch c := condition.(type) {
+			case bool:
+				if !c {
+					continue
+				}
+			case string:
+				node.Condition = c
+			default:
+				return fmt.Errorf("%w: condition of %s node %s must be bound to a boolean", ErrInvalidTemplateUsage, include.Template, spec.ID)
+			}
+		}
+		composed.WorkflowSpec.Nodes = append(composed.WorkflowSpec.Nodes, node)
+	}
+
+	for _, edge := range fragment.WorkflowSpec.Edges {
+		edge.From = prefix + edge.From
+		edge.To = prefix + edge.To
+		composed.WorkflowSpec.Edges = append(composed.WorkflowSpec.Edges, edge)
+	}
+	return nil
+}
+
+// mergeParameters returns base parameters with overrides applied: same-name
+// parameters are replaced in place and new ones appended
+func mergeParameters(base, overrides []TemplateParameter) []TemplateParameter {
+	merged := append([]TemplateParameter(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, param := range merged {
+		index[param.Name] = i
+	}
+	for _, param := range overrides {
+		if i, ok := index[param.Name]; ok {
+			merged[i] = param
+			continue
+		}
+		index[param.Name] = len(merged)
+		merged = append(merged, param)
+	}
+	return merged
+}
+
+// mergeNodeSpecs returns base nodes with overrides applied by node ID
+func mergeNodeSpecs(base, overrides []NodeSpec) []NodeSpec {
+	merged := append([]NodeSpec(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, node := range merged {
+		index[node.ID] = i
+	}
+	for _, node := range overrides {
+		if i, ok := index[node.ID]; ok {
+			merged[i] = node
+			continue
+		}
+		index[node.ID] = len(merged)
+		merged = append(merged, node)
+	}
+	return merged
+}
+
+// mergeEdgeSpecs returns base edges with overrides applied by endpoints
+func mergeEdgeSpecs(base, overrides []EdgeSpec) []EdgeSpec {
+	merged := append([]EdgeSpec(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, edge := range merged {
+		index[edge.From+" -> "+edge.To] = i
+	}
+	for _, edge := range overrides {
+		key := edge.From + " -> " + edge.To
+		if i, ok := index[key]; ok {
+			merged[i] = edge
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, edge)
+	}
+	return merged
+}
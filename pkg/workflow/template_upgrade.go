@@ -0,0 +1,386 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Template upgrade errors
+var (
+	ErrNotFromTemplate   = errors.New("workflow was not instantiated from a template")
+	ErrTemplateMismatch  = errors.New("workflow was instantiated from a different template")
+	ErrNoTemplateUpgrade = errors.New("template is not newer than the workflow")
+)
+
+// TemplateSource records the template a workflow was instantiated from. It
+// is stored in the workflow's metadata so the workflow can be upgraded when
+// a newer version of the template is released.
+type TemplateSource struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	// Parameters are the values provided at instantiation; defaults are not
+	// recorded so that an upgrade picks up the new version's defaults
+	Parameters map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	// Nodes are the IDs of the nodes created from the template. Any other
+	// node was added to the workflow afterwards and survives upgrades.
+	Nodes []string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+}
+
+// TemplateVersion is a parsed semantic version. Minor and patch default to
+// zero when omitted, so "1.2" is the same version as "1.2.0".
+type TemplateVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+var templateVersionRegex = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseTemplateVersion parses a version such as "1.4.0", "v2.0" or
+// "2.0.0-beta.1". Build metadata after "+" is ignored.
+func ParseTemplateVersion(s string) (TemplateVersion, error) {
+	match := templateVersionRegex.FindStringSubmatch(s)
+	if match == nil {
+		return TemplateVersion{}, fmt.Errorf("version %q is not a semantic version (e.g., 1.0.0)", s)
+	}
+
+	var v TemplateVersion
+	parts := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return TemplateVersion{}, fmt.Errorf("version %q: %w", s, err)
+		}
+		*part = n
+	}
+	v.Prerelease = match[4]
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 as v is older than, the same as or newer than
+// other. A pre-release is older than the release it precedes.
+func (v TemplateVersion) Compare(other TemplateVersion) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	return strings.Compare(v.Prerelease, other.Prerelease)
+}
+
+// String formats the version as major.minor.patch[-prerelease]
+func (v TemplateVersion) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// TemplateMigration describes what changed between two versions of a
+// template, so workflows instantiated from the older version can be
+// upgraded. Renames map old names to new ones.
+type TemplateMigration struct {
+	From             string            `json:"from" yaml:"from"`
+	To               string            `json:"to" yaml:"to"`
+	Description      string            `json:"description,omitempty" yaml:"description,omitempty"`
+	RenameParameters map[string]string `json:"rename_parameters,omitempty" yaml:"rename_parameters,omitempty"`
+	RemoveParameters []string          `json:"remove_parameters,omitempty" yaml:"remove_parameters,omitempty"`
+	RenameNodes      map[string]string `json:"rename_nodes,omitempty" yaml:"rename_nodes,omitempty"`
+}
+
+// TemplateUpgrade is the result of upgrading a workflow to a newer template
+// version. Workflow is the upgraded workflow; nothing is written until the
+// caller saves it, so Diff can be shown for confirmation first.
+type TemplateUpgrade struct {
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	Migrations []TemplateMigration `json:"migrations,omitempty"`
+	// RenamedParameters and RenamedNodes map the workflow's names to the
+	// new version's names, combining chained renames
+	RenamedParameters map[string]string `json:"renamed_parameters,omitempty"`
+	RenamedNodes      map[string]string `json:"renamed_nodes,omitempty"`
+	// RemovedParameters were provided to the old version but are not
+	// parameters of the new one
+	RemovedParameters []string      `json:"removed_parameters,omitempty"`
+	Workflow          *Workflow     `json:"-"`
+	Diff              *WorkflowDiff `json:"diff"`
+}
+
+// UpgradeWorkflow upgrades a workflow instantiated from an earlier version
+// of template. The migrations between the two versions are applied to the
+// recorded parameters, the new version is instantiated with them, and nodes
+// the user added since instantiation are carried over along with their
+// edges. Variables, servers and metadata are kept from the workflow.
+//
+// The template must already be composed (see ComposeTemplate).
+func UpgradeWorkflow(ctx context.Context, wf *Workflow, template *WorkflowTemplate) (*TemplateUpgrade, error) {
+	if wf == nil || template == nil {
+		return nil, errors.New("workflow and template cannot be nil")
+	}
+	source := wf.Metadata.Template
+	if source == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFromTemplate, wf.Name)
+	}
+	if source.Name != template.Name {
+		return nil, fmt.Errorf("%w: %s, not %s", ErrTemplateMismatch, source.Name, template.Name)
+	}
+
+	from, err := ParseTemplateVersion(source.Version)
+	if err != nil {
+		return nil, fmt.Errorf("workflow template version: %w", err)
+	}
+	to, err := ParseTemplateVersion(template.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	if to.Compare(from) <= 0 {
+		return nil, fmt.Errorf("%w: workflow uses %s %s, template is %s", ErrNoTemplateUpgrade, source.Name, source.Version, template.Version)
+	}
+
+	migrations, err := selectMigrations(template.Migrations, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade := &TemplateUpgrade{
+		From:              source.Version,
+		To:                template.Version,
+		Migrations:        migrations,
+		RenamedParameters: make(map[string]string),
+		RenamedNodes:      make(map[string]string),
+	}
+
+	params := make(map[string]interface{}, len(source.Parameters))
+	for name, value := range source.Parameters {
+		params[name] = value
+	}
+	for _, migration := range migrations {
+		for _, name := range migration.RemoveParameters {
+			if _, ok := params[name]; ok {
+				delete(params, name)
+				upgrade.RemovedParameters = append(upgrade.RemovedParameters, originalName(upgrade.RenamedParameters, name))
+			}
+		}
+		applyRenames(migration.RenameParameters, upgrade.RenamedParameters, func(old, renamed string) {
+			if value, ok := params[old]; ok {
+				delete(params, old)
+				params[renamed] = value
+			}
+		})
+		applyRenames(migration.RenameNodes, upgrade.RenamedNodes, nil)
+	}
+	for _, renamed := range []map[string]string{upgrade.RenamedParameters, upgrade.RenamedNodes} {
+		for original, current := range renamed {
+			if original == current {
+				delete(renamed, original)
+			}
+		}
+	}
+
+	// Drop values for parameters the new version no longer defines
+	defined := make(map[string]bool, len(template.Parameters))
+	for _, param := range template.Parameters {
+		defined[param.Name] = true
+	}
+	for name := range params {
+		if !defined[name] {
+			delete(params, name)
+			upgrade.RemovedParameters = append(upgrade.RemovedParameters, originalName(upgrade.RenamedParameters, name))
+		}
+	}
+	sort.Strings(upgrade.RemovedParameters)
+
+	upgraded, err := InstantiateTemplate(ctx, template, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate %s %s: %w", template.Name, template.Version, err)
+	}
+	if err := carryOverWorkflow(wf, upgraded, source, upgrade.RenamedNodes); err != nil {
+		return nil, err
+	}
+	upgrade.Workflow = upgraded
+
+	// Compare the serialized forms, so template nodes instantiated with raw
+	// config compare equal to the same nodes loaded from YAML
+	upgrade.Diff, err = Diff(normalizeForDiff(wf), normalizeForDiff(upgraded))
+	if err != nil {
+		return nil, err
+	}
+	return upgrade, nil
+}
+
+// selectMigrations returns the migrations that fall between from and to,
+// ordered by their starting version
+func selectMigrations(all []TemplateMigration, from, to TemplateVersion) ([]TemplateMigration, error) {
+	type versioned struct {
+		from      TemplateVersion
+		migration TemplateMigration
+	}
+	var selected []versioned
+	for _, migration := range all {
+		mFrom, err := ParseTemplateVersion(migration.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: migration from: %v", ErrInvalidTemplate, err)
+		}
+		mTo, err := ParseTemplateVersion(migration.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: migration to: %v", ErrInvalidTemplate, err)
+		}
+		if mTo.Compare(mFrom) <= 0 {
+			return nil, fmt.Errorf("%w: migration %s -> %s does not go forward", ErrInvalidTemplate, migration.From, migration.To)
+		}
+		if mFrom.Compare(from) >= 0 && mTo.Compare(to) <= 0 {
+			selected = append(selected, versioned{from: mFrom, migration: migration})
+		}
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].from.Compare(selected[j].from) < 0
+	})
+	migrations := make([]TemplateMigration, len(selected))
+	for i, s := range selected {
+		migrations[i] = s.migration
+	}
+	return migrations, nil
+}
+
+// applyRenames applies one migration's renames, calling apply for each and
+// folding them into combined so that a -> b followed by b -> c is a -> c.
+// All old names are read before any is written, so swaps work.
+func applyRenames(renames map[string]string, combined map[string]string, apply func(old, renamed string)) {
+	olds := make([]string, 0, len(renames))
+	for old := range renames {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+
+	if apply != nil {
+		values := make(map[string]string, len(olds))
+		for _, old := range olds {
+			values[old] = renames[old]
+		}
+		// Rename into temporary keys first so swaps do not clobber
+		for _, old := range olds {
+			apply(old, "\x00"+values[old])
+		}
+		for _, old := range olds {
+			apply("\x00"+values[old], values[old])
+		}
+	}
+
+	updated := make(map[string]bool, len(olds))
+	for original, current := range combined {
+		if renamed, ok := renames[current]; ok {
+			combined[original] = renamed
+			updated[current] = true
+		}
+	}
+	for _, old := range olds {
+		if !updated[old] {
+			if _, seen := combined[old]; !seen {
+				combined[old] = renames[old]
+			}
+		}
+	}
+}
+
+// originalName returns the name a parameter had before any renames
+func originalName(renamed map[string]string, name string) string {
+	for original, current := range renamed {
+		if current == name {
+			return original
+		}
+	}
+	return name
+}
+
+// carryOverWorkflow copies everything the template does not define from the
+// old workflow into the upgraded one
+func carryOverWorkflow(old, upgraded *Workflow, source *TemplateSource, renamedNodes map[string]string) error {
+	upgraded.ID = old.ID
+	upgraded.Name = old.Name
+	upgraded.Description = old.Description
+	upgraded.Variables = old.Variables
+	upgraded.ServerConfigs = old.ServerConfigs
+
+	templateSource := upgraded.Metadata.Template
+	upgraded.Metadata = old.Metadata
+	upgraded.Metadata.Template = templateSource
+	upgraded.Metadata.LastModified = time.Now()
+
+	fromTemplate := make(map[string]bool, len(source.Nodes))
+	for _, id := range source.Nodes {
+		fromTemplate[id] = true
+	}
+	present := make(map[string]bool, len(upgraded.Nodes))
+	for _, node := range upgraded.Nodes {
+		present[node.GetID()] = true
+	}
+
+	for _, node := range old.Nodes {
+		if fromTemplate[node.GetID()] {
+			continue
+		}
+		if present[node.GetID()] {
+			return fmt.Errorf("node %s was added to the workflow but %s %s also defines it", node.GetID(), source.Name, upgraded.Metadata.Template.Version)
+		}
+		upgraded.Nodes = append(upgraded.Nodes, node)
+		present[node.GetID()] = true
+	}
+
+	// Edges between template nodes come from the new version; edges that
+	// touch a user node are kept, following renamed template nodes. An edge
+	// to a template node that no longer exists is dropped.
+	for _, edge := range old.Edges {
+		if edge == nil || (fromTemplate[edge.FromNodeID] && fromTemplate[edge.ToNodeID]) {
+			continue
+		}
+		kept := *edge
+		if renamed, ok := renamedNodes[kept.FromNodeID]; ok && fromTemplate[kept.FromNodeID] {
+			kept.FromNodeID = renamed
+		}
+		if renamed, ok := renamedNodes[kept.ToNodeID]; ok && fromTemplate[kept.ToNodeID] {
+			kept.ToNodeID = renamed
+		}
+		if !present[kept.FromNodeID] || !present[kept.ToNodeID] {
+			continue
+		}
+		upgraded.Edges = append(upgraded.Edges, &kept)
+	}
+	return nil
+}
+
+// normalizeForDiff round-trips a workflow through YAML, falling back to the
+// workflow itself if it cannot be serialized
+func normalizeForDiff(wf *Workflow) *Workflow {
+	data, err := ToYAML(wf)
+	if err != nil {
+		return wf
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		return wf
+	}
+	return parsed
+}
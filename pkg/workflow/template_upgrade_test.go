@@ -0,0 +1,190 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseTemplateVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0", 0},
+		{"v2", "2.0.0", 0},
+		{"1.10.0", "1.9.3", 1},
+		{"2.0.0-beta.1", "2.0.0", -1},
+		{"2.0.0-beta.2", "2.0.0-beta.1", 1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		a, err := ParseTemplateVersion(tt.a)
+		if err != nil {
+			t.Fatalf("ParseTemplateVersion(%q) error = %v", tt.a, err)
+		}
+		b, err := ParseTemplateVersion(tt.b)
+		if err != nil {
+			t.Fatalf("ParseTemplateVersion(%q) error = %v", tt.b, err)
+		}
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "latest", "1.0.0.0", "1.x"} {
+		if _, err := ParseTemplateVersion(bad); err == nil {
+			t.Errorf("ParseTemplateVersion(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+// newUpgradeTemplate returns version 1.0.0 of a template that reads a file
+func newUpgradeTemplate() *WorkflowTemplate {
+	return &WorkflowTemplate{
+		Name:    "reader",
+		Version: "1.0.0",
+		Parameters: []TemplateParameter{
+			{Name: "file", Type: ParameterTypeString, Required: true},
+			{Name: "encoding", Type: ParameterTypeString, Default: "utf-8"},
+		},
+		WorkflowSpec: WorkflowSpec{
+			Nodes: []NodeSpec{
+				{ID: "start", Type: "start"},
+				{ID: "read", Type: "mcp_tool", Config: map[string]interface{}{
+					"server": "fs", "tool": "read_file", "output": "content",
+					"parameters": map[string]interface{}{"path": "{{file}}", "encoding": "{{encoding}}"},
+				}},
+				{ID: "end", Type: "end"},
+			},
+			Edges: []EdgeSpec{{From: "start", To: "read"}, {From: "read", To: "end"}},
+		},
+	}
+}
+
+func TestUpgradeWorkflow(t *testing.T) {
+	ctx := context.Background()
+	v1 := newUpgradeTemplate()
+	instantiated, err := InstantiateTemplate(ctx, v1, map[string]interface{}{"file": "/data.txt", "encoding": "latin1"})
+	if err != nil {
+		t.Fatalf("InstantiateTemplate() error = %v", err)
+	}
+
+	// Add a user node hanging off a template node, then save and reload
+	_ = instantiated.AddNode(&TransformNode{ID: "count", InputVariable: "content", Expression: "$.length", OutputVariable: "n"})
+	_ = instantiated.AddEdge(&Edge{FromNodeID: "read", ToNodeID: "count"})
+	data, err := ToYAML(instantiated)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	wf, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if wf.Metadata.Template == nil || wf.Metadata.Template.Version != "1.0.0" {
+		t.Fatalf("Template source not preserved: %+v", wf.Metadata.Template)
+	}
+
+	// Version 2 renames the file parameter and the read node, drops the
+	// encoding parameter and adds a log step
+	v2 := newUpgradeTemplate()
+	v2.Version = "2.0.0"
+	v2.Parameters = []TemplateParameter{{Name: "path", Type: ParameterTypeString, Required: true}}
+	v2.WorkflowSpec.Nodes[1] = NodeSpec{ID: "load", Type: "mcp_tool", Config: map[string]interface{}{
+		"server": "fs", "tool": "read_file", "output": "content",
+		"parameters": map[string]interface{}{"path": "{{path}}"},
+	}}
+	v2.WorkflowSpec.Nodes = append(v2.WorkflowSpec.Nodes, NodeSpec{ID: "log", Type: "mcp_tool", Config: map[string]interface{}{
+		"server": "log", "tool": "write",
+	}})
+	v2.WorkflowSpec.Edges = []EdgeSpec{{From: "start", To: "load"}, {From: "load", To: "log"}, {From: "log", To: "end"}}
+	v2.Migrations = []TemplateMigration{
+		{From: "1.0.0", To: "1.5.0", RenameParameters: map[string]string{"file": "source"}},
+		{From: "1.5.0", To: "2.0.0", RenameParameters: map[string]string{"source": "path"}, RemoveParameters: []string{"encoding"}, RenameNodes: map[string]string{"read": "load"}},
+		{From: "2.0.0", To: "3.0.0", RenameParameters: map[string]string{"path": "ignored"}},
+	}
+
+	upgrade, err := UpgradeWorkflow(ctx, wf, v2)
+	if err != nil {
+		t.Fatalf("UpgradeWorkflow() error = %v", err)
+	}
+
+	if len(upgrade.Migrations) != 2 {
+		t.Errorf("Migrations = %d, want 2", len(upgrade.Migrations))
+	}
+	if upgrade.RenamedParameters["file"] != "path" || len(upgrade.RenamedParameters) != 1 {
+		t.Errorf("RenamedParameters = %v, want file -> path", upgrade.RenamedParameters)
+	}
+	if upgrade.RenamedNodes["read"] != "load" {
+		t.Errorf("RenamedNodes = %v, want read -> load", upgrade.RenamedNodes)
+	}
+	if len(upgrade.RemovedParameters) != 1 || upgrade.RemovedParameters[0] != "encoding" {
+		t.Errorf("RemovedParameters = %v, want [encoding]", upgrade.RemovedParameters)
+	}
+
+	upgraded := upgrade.Workflow
+	if upgraded.ID != wf.ID || upgraded.Metadata.Template.Version != "2.0.0" {
+		t.Errorf("Upgraded workflow ID %s version %s", upgraded.ID, upgraded.Metadata.Template.Version)
+	}
+	if upgraded.Metadata.Template.Parameters["path"] != "/data.txt" {
+		t.Errorf("Upgraded parameters = %v", upgraded.Metadata.Template.Parameters)
+	}
+
+	edges := make(map[string]bool)
+	for _, edge := range upgraded.Edges {
+		edges[edge.FromNodeID+" -> "+edge.ToNodeID] = true
+	}
+	if !edges["load -> count"] || !edges["load -> log"] || edges["read -> count"] {
+		t.Errorf("Upgraded edges = %v, want user edge moved to load", edges)
+	}
+
+	changes := make(map[string]ChangeType)
+	for _, entry := range upgrade.Diff.Nodes {
+		changes[entry.ID] = entry.Change
+	}
+	want := map[string]ChangeType{"read": ChangeRemoved, "load": ChangeAdded, "log": ChangeAdded}
+	if len(changes) != len(want) {
+		t.Errorf("Diff nodes = %v, want %v", changes, want)
+	}
+	for id, change := range want {
+		if changes[id] != change {
+			t.Errorf("Diff node %s = %s, want %s", id, changes[id], change)
+		}
+	}
+
+	// The upgraded workflow can be saved and upgraded no further
+	if _, err := ToYAML(upgraded); err != nil {
+		t.Errorf("ToYAML(upgraded) error = %v", err)
+	}
+	if _, err := UpgradeWorkflow(ctx, upgraded, v2); !errors.Is(err, ErrNoTemplateUpgrade) {
+		t.Errorf("Upgrade to same version error = %v, want ErrNoTemplateUpgrade", err)
+	}
+}
+
+func TestUpgradeWorkflowErrors(t *testing.T) {
+	ctx := context.Background()
+
+	plain := newLintWorkflow(t)
+	if _, err := UpgradeWorkflow(ctx, plain, newUpgradeTemplate()); !errors.Is(err, ErrNotFromTemplate) {
+		t.Errorf("error = %v, want ErrNotFromTemplate", err)
+	}
+
+	wf, err := InstantiateTemplate(ctx, newUpgradeTemplate(), map[string]interface{}{"file": "a"})
+	if err != nil {
+		t.Fatalf("InstantiateTemplate() error = %v", err)
+	}
+	other := newUpgradeTemplate()
+	other.Name = "writer"
+	other.Version = "2.0.0"
+	if _, err := UpgradeWorkflow(ctx, wf, other); !errors.Is(err, ErrTemplateMismatch) {
+		t.Errorf("error = %v, want ErrTemplateMismatch", err)
+	}
+
+	// A new required parameter without a value cannot be upgraded to
+	v2 := newUpgradeTemplate()
+	v2.Version = "1.1.0"
+	v2.Parameters = append(v2.Parameters, TemplateParameter{Name: "mode", Type: ParameterTypeString, Required: true})
+	if _, err := UpgradeWorkflow(ctx, wf, v2); !errors.Is(err, ErrMissingRequiredParameter) {
+		t.Errorf("error = %v, want ErrMissingRequiredParameter", err)
+	}
+}
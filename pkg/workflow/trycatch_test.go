@@ -0,0 +1,194 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const tryCatchWorkflowYAML = `
+version: "1.0.0"
+name: "try-catch-test"
+variables:
+  - name: "data"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "guard"
+    type: "try"
+    body: ["parse"]
+  - id: "parse"
+    type: "transform"
+    input: "data"
+    expression: "$.user.name"
+    output: "name"
+  - id: "handle"
+    type: "catch"
+    error_variable: "err"
+  - id: "report"
+    type: "condition"
+    condition: "err.node_id == \"parse\""
+  - id: "ok"
+    type: "end"
+  - id: "recovered"
+    type: "end"
+edges:
+  - from: "start"
+    to: "guard"
+  - from: "guard"
+    to: "ok"
+  - from: "guard"
+    to: "handle"
+    on_error: true
+  - from: "handle"
+    to: "report"
+  - from: "report"
+    to: "recovered"
+    condition: "true"
+  - from: "report"
+    to: "ok"
+    condition: "false"
+`
+
+func TestTryCatch_ParseAndRoundTrip(t *testing.T) {
+	wf, err := Parse([]byte(tryCatchWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := ValidateWorkflow(wf); err != nil {
+		t.Fatalf("ValidateWorkflow() error = %v", err)
+	}
+
+	data, err := ToYAML(wf)
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() of serialized workflow error = %v", err)
+	}
+
+	for _, w := range []*Workflow{wf, again} {
+		try, ok := w.Nodes[1].(*TryNode)
+		if !ok || len(try.Body) != 1 || try.Body[0] != "parse" {
+			t.Errorf("try node = %#v", w.Nodes[1])
+		}
+		catch, ok := w.Nodes[3].(*CatchNode)
+		if !ok || catch.ErrorVariable != "err" {
+			t.Errorf("catch node = %#v", w.Nodes[3])
+		}
+		errorEdges := 0
+		for _, edge := range w.Edges {
+			if edge.OnError {
+				errorEdges++
+				if edge.FromNodeID != "guard" || edge.ToNodeID != "handle" {
+					t.Errorf("error edge = %+v", edge)
+				}
+			}
+		}
+		if errorEdges != 1 {
+			t.Errorf("found %d error edges, want 1", errorEdges)
+		}
+	}
+}
+
+func TestTryCatch_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(wf *Workflow)
+		want string
+	}{
+		{
+			name: "try without error edge",
+			edit: func(wf *Workflow) { _ = wf.RemoveNode("handle") },
+			want: "try node guard must have an error edge",
+		},
+		{
+			name: "try body references unknown node",
+			edit: func(wf *Workflow) { wf.Nodes[1].(*TryNode).Body = append(wf.Nodes[1].(*TryNode).Body, "missing") },
+			want: "body references unknown node missing",
+		},
+		{
+			name: "catch reached by a normal edge",
+			edit: func(wf *Workflow) {
+				for _, edge := range wf.Edges {
+					if edge.OnError {
+						edge.OnError = false
+					}
+				}
+			},
+			want: "catch node handle must be reached only through error edges",
+		},
+		{
+			name: "error edge with a condition",
+			edit: func(wf *Workflow) {
+				for _, edge := range wf.Edges {
+					if edge.OnError {
+						edge.Condition = "true"
+					}
+				}
+			},
+			want: "error edge from guard cannot have a condition",
+		},
+		{
+			name: "error edge from start",
+			edit: func(wf *Workflow) {
+				_ = wf.AddEdge(&Edge{FromNodeID: "start", ToNodeID: "handle", OnError: true})
+			},
+			want: "start node start cannot have an error edge",
+		},
+		{
+			name: "condition node with an error edge",
+			edit: func(wf *Workflow) {
+				_ = wf.AddNode(&PassthroughNode{ID: "cleanup"})
+				_ = wf.AddEdge(&Edge{FromNodeID: "report", ToNodeID: "cleanup", OnError: true})
+				_ = wf.AddEdge(&Edge{FromNodeID: "cleanup", ToNodeID: "recovered"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := Parse([]byte(tryCatchWorkflowYAML))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			tt.edit(wf)
+
+			err = wf.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderGraph_ErrorEdges(t *testing.T) {
+	wf, err := Parse([]byte(tryCatchWorkflowYAML))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	dot, err := RenderGraph(wf, GraphDOT)
+	if err != nil {
+		t.Fatalf("RenderGraph() error = %v", err)
+	}
+	for _, want := range []string{
+		`"guard" -> "handle" [label="on error", style=dashed, color="#c62828"`,
+		`"guard" -> "parse" [label="try", style=dashed]`,
+		"shape=octagon",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot)
+		}
+	}
+}
@@ -536,6 +536,7 @@ func ValidateWorkflow(wf *Workflow) error {
 
 	// Validate nodes
 	nodeIDs := make(map[string]bool)
+	switchNodes := make(map[string]bool)
 	for i, node := range wf.Nodes {
 		if node == nil {
 			return fmt.Errorf("node at index %d is nil", i)
@@ -550,6 +551,9 @@ func ValidateWorkflow(wf *Workflow) error {
 			return fmt.Errorf("duplicate node ID: %s", nodeID)
 		}
 		nodeIDs[nodeID] = true
+		if node.Type() == "switch" {
+			switchNodes[nodeID] = true
+		}
 
 		// Validate node-specific fields
 		if err := node.Validate(); err != nil {
@@ -571,7 +575,8 @@ func ValidateWorkflow(wf *Workflow) error {
 			return fmt.Errorf("edge %d references non-existent to_node: %s", i, edge.ToNodeID)
 		}
 
-		if edge.Condition != "" {
+		// Edges leaving a switch node carry case labels, not expressions
+		if edge.Condition != "" && !switchNodes[edge.FromNodeID] {
 			if err := ValidateExpression(edge.Condition); err != nil {
 				return fmt.Errorf("invalid condition in edge %d: %w", i, err)
 			}
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/dshills/goflow/pkg/transform"
 )
 
 // WorkflowMetadata contains descriptive information about a workflow
@@ -15,6 +18,40 @@ type WorkflowMetadata struct {
 	LastModified time.Time `json:"last_modified,omitempty" yaml:"last_modified,omitempty"`
 	Tags         []string  `json:"tags,omitempty" yaml:"tags,omitempty"`
 	Icon         string    `json:"icon,omitempty" yaml:"icon,omitempty"`
+
+	// ReadOnly marks a shared workflow that should be viewed but not
+	// changed; the builder refuses edits and saves
+	ReadOnly bool `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+
+	// ExpressionTimeout bounds each expression and JSONPath evaluation of
+	// the workflow's nodes (e.g. "2s"); nodes can override it
+	ExpressionTimeout string `json:"expression_timeout,omitempty" yaml:"expression_timeout,omitempty"`
+
+	// Template records the template this workflow was instantiated from
+	Template *TemplateSource `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Groups organize the nodes into named stages on the builder's canvas
+	Groups []*NodeGroup `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	// Notes document nodes, keyed by node ID; the builder shows them beside
+	// the nodes
+	Notes map[string]string `json:"notes,omitempty" yaml:"notes,omitempty"`
+
+	// Contracts declare the variables nodes read and write, keyed by node
+	// ID, for data-flow validation
+	Contracts map[string]*NodeContract `json:"contracts,omitempty" yaml:"contracts,omitempty"`
+
+	// Lint sets the severity of lint and validation warning rules for this
+	// workflow: error, warning, or off
+	Lint map[string]LintSeverity `json:"lint,omitempty" yaml:"lint,omitempty"`
+
+	// Canvas is the builder's canvas layout: node positions, zoom, and
+	// viewport
+	Canvas *CanvasLayout `json:"canvas,omitempty" yaml:"canvas,omitempty"`
+
+	// Suppressions lists the rules silenced on each node, keyed by node ID.
+	// In YAML they are written inline on each node as suppress.
+	Suppressions map[string][]string `json:"suppressions,omitempty" yaml:"-"`
 }
 
 // Workflow represents a directed acyclic graph (DAG) of nodes and edges defining an automation workflow
@@ -94,6 +131,13 @@ func (w *Workflow) RemoveNode(nodeID string) error {
 		}
 	}
 	w.Edges = newEdges
+	w.removeFromGroups(nodeID)
+	delete(w.Metadata.Notes, nodeID)
+	delete(w.Metadata.Contracts, nodeID)
+	delete(w.Metadata.Suppressions, nodeID)
+	if w.Metadata.Canvas != nil {
+		delete(w.Metadata.Canvas.Positions, nodeID)
+	}
 
 	w.Metadata.LastModified = time.Now()
 	return nil
@@ -213,6 +257,34 @@ func (w *Workflow) UpdateVariable(name string, updated *Variable) error {
 	return fmt.Errorf("variable not found: %s", name)
 }
 
+// ExpressionTimeout returns how long each expression evaluation of node may
+// take: the node's expression_timeout, else the workflow's, else zero for
+// the evaluator's default.
+func (w *Workflow) ExpressionTimeout(node Node) time.Duration {
+	var timeout string
+	switch n := node.(type) {
+	case *TransformNode:
+		timeout = n.ExpressionTimeout
+	case *ConditionNode:
+		timeout = n.ExpressionTimeout
+	case *SwitchNode:
+		timeout = n.ExpressionTimeout
+	case *LoopNode:
+		timeout = n.ExpressionTimeout
+	}
+	if timeout == "" && w != nil {
+		timeout = w.Metadata.ExpressionTimeout
+	}
+	if timeout == "" {
+		return 0
+	}
+	d, err := ParseDelayDuration(timeout)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
 // Validate checks all workflow invariants
 func (w *Workflow) Validate() error {
 	var validationErrors []string
@@ -304,7 +376,7 @@ func (w *Workflow) Validate() error {
 			outgoingEdges := 0
 			conditionedEdges := 0
 			for _, edge := range w.Edges {
-				if edge.FromNodeID == nodeID {
+				if edge.FromNodeID == nodeID && !edge.OnError {
 					outgoingEdges++
 					if edge.Condition != "" {
 						conditionedEdges++
@@ -320,6 +392,19 @@ func (w *Workflow) Validate() error {
 		}
 	}
 
+	// Validate switch nodes have one labeled edge per case, and a default
+	// edge unless a case always matches
+	for _, node := range w.Nodes {
+		if n, ok := node.(*SwitchNode); ok {
+			validationErrors = append(validationErrors, w.SwitchEdgeErrors(n)...)
+		}
+	}
+
+	// Validate error edges and try/catch scopes
+	for _, node := range w.Nodes {
+		validationErrors = append(validationErrors, w.ErrorHandlingErrors(node)...)
+	}
+
 	// Validate expressions in nodes
 	for _, node := range w.Nodes {
 		switch n := node.(type) {
@@ -327,6 +412,12 @@ func (w *Workflow) Validate() error {
 			if err := w.validateConditionExpression(n); err != nil {
 				validationErrors = append(validationErrors, fmt.Sprintf("node %s: %v", n.GetID(), err))
 			}
+		case *SwitchNode:
+			for _, c := range n.Cases {
+				if err := w.validateCondition(c.Condition); err != nil {
+					validationErrors = append(validationErrors, fmt.Sprintf("node %s: case %q: %v", n.GetID(), c.Label, err))
+				}
+			}
 		case *TransformNode:
 			if err := w.validateTransformConfig(n); err != nil {
 				validationErrors = append(validationErrors, fmt.Sprintf("node %s: %v", n.GetID(), err))
@@ -335,9 +426,35 @@ func (w *Workflow) Validate() error {
 			if err := w.validateMCPToolNode(n); err != nil {
 				validationErrors = append(validationErrors, fmt.Sprintf("node %s: %v", n.GetID(), err))
 			}
+		case *BatchToolNode:
+			if err := w.validateToolCall(n.ServerID, n.Parameters); err != nil {
+				validationErrors = append(validationErrors, fmt.Sprintf("node %s: %v", n.GetID(), err))
+			}
+		case *DelayNode:
+			if err := w.validateTemplatedNode(n, n.Duration, n.Until); err != nil {
+				validationErrors = append(validationErrors, fmt.Sprintf("node %s: %v", n.GetID(), err))
+			}
+		case *ApprovalNode:
+			if err := w.validateTemplatedNode(n, n.Message, n.Timeout); err != nil {
+				validationErrors = append(validationErrors, fmt.Sprintf("node %s: %v", n.GetID(), err))
+			}
 		}
 	}
 
+	// Lint severities must be known
+	validationErrors = append(validationErrors, w.lintSeverityErrors()...)
+
+	// The expression timeout must be a positive duration
+	if w.Metadata.ExpressionTimeout != "" {
+		if _, err := ParseDelayDuration(w.Metadata.ExpressionTimeout); err != nil {
+			validationErrors = append(validationErrors, fmt.Sprintf("expression_timeout: %v", err))
+		}
+	}
+
+	// Variables must be written upstream of the nodes that read them
+	validationErrors = append(validationErrors, w.contractErrors(nodeIDs)...)
+	validationErrors = append(validationErrors, w.dataFlowErrors()...)
+
 	// Invariant 3: No circular dependencies (DAG property)
 	if err := w.checkForCycles(); err != nil {
 		validationErrors = append(validationErrors, err.Error())
@@ -450,6 +567,9 @@ func (w *Workflow) checkForOrphanedNodes() error {
 		case *LoopNode:
 			// Loop nodes connect to all body nodes
 			adjacency[n.ID] = append(adjacency[n.ID], n.Body...)
+		case *TryNode:
+			// Try nodes connect to all body nodes
+			adjacency[n.ID] = append(adjacency[n.ID], n.Body...)
 		}
 	}
 
@@ -483,19 +603,25 @@ func (w *Workflow) checkForOrphanedNodes() error {
 
 // validateConditionExpression validates the condition expression in a ConditionNode
 func (w *Workflow) validateConditionExpression(node *ConditionNode) error {
-	if node.Condition == "" {
+	return w.validateCondition(node.Condition)
+}
+
+// validateCondition validates a boolean expression, such as a condition
+// node's condition or a switch case
+func (w *Workflow) validateCondition(condition string) error {
+	if condition == "" {
 		return errors.New("condition expression cannot be empty")
 	}
 
 	// Try to compile the expression to validate syntax (this also checks for unsafe operations)
 	// Note: We use a minimal context for validation - actual values will be provided at runtime
 	// This validates syntax without requiring actual data
-	if err := validateExpressionSyntax(node.Condition); err != nil {
+	if err := validateExpressionSyntax(condition); err != nil {
 		return fmt.Errorf("invalid condition expression: %w", err)
 	}
 
 	// Extract variable references from the expression
-	varRefs := extractVariableReferences(node.Condition)
+	varRefs := extractVariableReferences(condition)
 
 	// Check that all referenced variables are defined in the workflow
 	for _, varName := range varRefs {
@@ -507,6 +633,98 @@ func (w *Workflow) validateConditionExpression(node *ConditionNode) error {
 	return nil
 }
 
+// SwitchEdgeErrors checks that every edge leaving a switch node is labeled
+// with one of its cases or the default branch, that every case has exactly
+// one edge, and that there is a default edge unless a case always matches.
+func (w *Workflow) SwitchEdgeErrors(node *SwitchNode) []string {
+	var errs []string
+	nodeID := node.GetID()
+	edgesByLabel := make(map[string]int)
+	for _, edge := range w.Edges {
+		if edge.FromNodeID != nodeID || edge.OnError {
+			continue
+		}
+		if edge.Condition != SwitchDefaultCase && !node.HasCase(edge.Condition) {
+			errs = append(errs, fmt.Sprintf("edge from switch node %s to %s must be labeled with a case or %q", nodeID, edge.ToNodeID, SwitchDefaultCase))
+			continue
+		}
+		edgesByLabel[edge.Condition]++
+	}
+
+	for _, c := range node.Cases {
+		switch edgesByLabel[c.Label] {
+		case 0:
+			errs = append(errs, fmt.Sprintf("switch node %s has no edge for case %q", nodeID, c.Label))
+		case 1:
+		default:
+			errs = append(errs, fmt.Sprintf("switch node %s has more than one edge for case %q", nodeID, c.Label))
+		}
+	}
+	switch defaults := edgesByLabel[SwitchDefaultCase]; {
+	case defaults > 1:
+		errs = append(errs, fmt.Sprintf("switch node %s has more than one %q edge", nodeID, SwitchDefaultCase))
+	case defaults == 0 && !node.IsExhaustive():
+		errs = append(errs, fmt.Sprintf("switch node %s must have a %q edge or a case whose condition is true", nodeID, SwitchDefaultCase))
+	}
+	return errs
+}
+
+// ErrorHandlingErrors checks a node's part in error handling: start and end
+// nodes cannot have error edges, a try node's body must reference existing
+// nodes and the try node needs an error edge to handle its failures, and a
+// catch node must be reached only through error edges.
+func (w *Workflow) ErrorHandlingErrors(node Node) []string {
+	var errs []string
+	nodeID := node.GetID()
+
+	errorEdges := 0
+	for _, edge := range w.Edges {
+		if edge.FromNodeID == nodeID && edge.OnError {
+			errorEdges++
+		}
+	}
+	switch node.Type() {
+	case "start", "end":
+		if errorEdges > 0 {
+			errs = append(errs, fmt.Sprintf("%s node %s cannot have an error edge", node.Type(), nodeID))
+		}
+	}
+
+	switch n := node.(type) {
+	case *TryNode:
+		nodeTypes := make(map[string]string, len(w.Nodes))
+		for _, other := range w.Nodes {
+			nodeTypes[other.GetID()] = other.Type()
+		}
+		for _, bodyID := range n.Body {
+			switch nodeTypes[bodyID] {
+			case "":
+				errs = append(errs, fmt.Sprintf("try node %s body references unknown node %s", nodeID, bodyID))
+			case "start", "end":
+				errs = append(errs, fmt.Sprintf("try node %s body cannot contain %s node %s", nodeID, nodeTypes[bodyID], bodyID))
+			}
+		}
+		if errorEdges == 0 {
+			errs = append(errs, fmt.Sprintf("try node %s must have an error edge", nodeID))
+		}
+	case *CatchNode:
+		incoming := 0
+		for _, edge := range w.Edges {
+			if edge.ToNodeID != nodeID {
+				continue
+			}
+			incoming++
+			if !edge.OnError {
+				errs = append(errs, fmt.Sprintf("catch node %s must be reached only through error edges (edge from %s)", nodeID, edge.FromNodeID))
+			}
+		}
+		if incoming == 0 && len(w.Edges) > 0 {
+			errs = append(errs, fmt.Sprintf("catch node %s has no incoming error edge", nodeID))
+		}
+	}
+	return errs
+}
+
 // validateTransformConfig validates the transformation configuration in a TransformNode
 func (w *Workflow) validateTransformConfig(node *TransformNode) error {
 	if node.Expression == "" {
@@ -541,6 +759,19 @@ func (w *Workflow) validateTransformConfig(node *TransformNode) error {
 	// Validate the expression syntax based on its type
 	expr := node.Expression
 
+	// A jq program reads workflow variables as $name
+	if node.IsJQ() {
+		if err := transform.CheckJQ(expr); err != nil {
+			return fmt.Errorf("invalid jq program: %w", err)
+		}
+		for _, varName := range transform.JQVariableReferences(expr) {
+			if !w.hasVariable(varName) && !w.hasNodeOutput(varName) && !w.isLoopItemVariable(varName) {
+				return fmt.Errorf("undefined variable in jq program: $%s", varName)
+			}
+		}
+		return nil
+	}
+
 	// Check if it's a JSONPath expression (starts with $)
 	if len(expr) > 0 && expr[0] == '$' {
 		if err := validateJSONPathSyntax(expr); err != nil {
@@ -571,23 +802,29 @@ func (w *Workflow) validateTransformConfig(node *TransformNode) error {
 
 // validateMCPToolNode validates MCP tool node configuration
 func (w *Workflow) validateMCPToolNode(node *MCPToolNode) error {
+	return w.validateToolCall(node.ServerID, node.Parameters)
+}
+
+// validateToolCall validates the server reference and parameter templates
+// of a node calling an MCP tool
+func (w *Workflow) validateToolCall(serverID string, parameters map[string]string) error {
 	// Validate server reference
-	if node.ServerID != "" {
+	if serverID != "" {
 		serverExists := false
 		for _, server := range w.ServerConfigs {
-			if server.ID == node.ServerID {
+			if server.ID == serverID {
 				serverExists = true
 				break
 			}
 		}
 		if !serverExists {
-			return fmt.Errorf("undefined server: %s", node.ServerID)
+			return fmt.Errorf("undefined server: %s", serverID)
 		}
 	}
 
 	// Validate variables in parameters
-	if node.Parameters != nil {
-		for key, value := range node.Parameters {
+	if parameters != nil {
+		for key, value := range parameters {
 			// Check if it's a template string
 			if containsTemplate(value) {
 				if err := validateTemplateSyntax(value); err != nil {
@@ -607,6 +844,29 @@ func (w *Workflow) validateMCPToolNode(node *MCPToolNode) error {
 	return nil
 }
 
+// validateTemplatedNode validates a node whose fields may hold ${var}
+// templates: the node's own configuration, then the template syntax and
+// variables of each field
+func (w *Workflow) validateTemplatedNode(node Node, fields ...string) error {
+	if err := node.Validate(); err != nil {
+		return err
+	}
+	for _, value := range fields {
+		if !containsTemplate(value) {
+			continue
+		}
+		if err := validateTemplateSyntax(value); err != nil {
+			return fmt.Errorf("invalid template syntax in %q: %w", value, err)
+		}
+		for _, varName := range extractTemplateVariables(value) {
+			if !w.hasVariable(varName) && !w.hasNodeOutput(varName) && !w.isLoopItemVariable(varName) {
+				return fmt.Errorf("undefined variable: %s", varName)
+			}
+		}
+	}
+	return nil
+}
+
 // hasVariable checks if a variable with the given name exists in the workflow
 func (w *Workflow) hasVariable(name string) bool {
 	for _, v := range w.Variables {
@@ -617,11 +877,17 @@ func (w *Workflow) hasVariable(name string) bool {
 	return false
 }
 
-// isLoopItemVariable checks if a variable name is a loop item variable
+// isLoopItemVariable checks if a variable name is the item variable of a
+// loop or batch tool node
 func (w *Workflow) isLoopItemVariable(name string) bool {
 	for _, node := range w.Nodes {
-		if loopNode, ok := node.(*LoopNode); ok {
-			if loopNode.ItemVariable == name {
+		switch n := node.(type) {
+		case *LoopNode:
+			if n.ItemVariable == name {
+				return true
+			}
+		case *BatchToolNode:
+			if n.ItemVariable == name {
 				return true
 			}
 		}
@@ -637,10 +903,35 @@ func (w *Workflow) hasNodeOutput(name string) bool {
 			if n.OutputVariable == name {
 				return true
 			}
+			for _, variable := range n.ContentOutputs {
+				if variable == name {
+					return true
+				}
+			}
+			if n.StreamOutput == name {
+				return true
+			}
 		case *TransformNode:
+			if n.OutputVariable == name || n.ItemErrorsOutput == name {
+				return true
+			}
+		case *CatchNode:
+			if n.ErrorVariable == name {
+				return true
+			}
+		case *ApprovalNode:
 			if n.OutputVariable == name {
 				return true
 			}
+		case *BatchToolNode:
+			if n.OutputVariable == name || (n.ErrorVariable != "" && n.ErrorVariable == name) {
+				return true
+			}
+		}
+	}
+	for _, contract := range w.Metadata.Contracts {
+		if contract != nil && slices.Contains(contract.Writes, name) {
+			return true
 		}
 	}
 	return false
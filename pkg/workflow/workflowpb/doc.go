@@ -0,0 +1,6 @@
+// Package workflowpb holds the Protobuf messages generated from
+// workflow.proto. Use workflow.ToProto and workflow.ParseProto rather than
+// filling the messages by hand.
+package workflowpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative workflow.proto
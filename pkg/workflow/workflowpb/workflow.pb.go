@@ -0,0 +1,1596 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: workflow.proto
+
+package workflowpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Workflow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SchemaVersion int32                  `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Metadata      *Metadata              `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Variables     []*Variable            `protobuf:"bytes,6,rep,name=variables,proto3" json:"variables,omitempty"`
+	Servers       []*ServerConfig        `protobuf:"bytes,7,rep,name=servers,proto3" json:"servers,omitempty"`
+	Nodes         []*Node                `protobuf:"bytes,8,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges         []*Edge                `protobuf:"bytes,9,rep,name=edges,proto3" json:"edges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Workflow) Reset() {
+	*x = Workflow{}
+	mi := &file_workflow_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow) ProtoMessage() {}
+
+func (x *Workflow) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow.ProtoReflect.Descriptor instead.
+func (*Workflow) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Workflow) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Workflow) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Workflow) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workflow) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Workflow) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Workflow) GetVariables() []*Variable {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *Workflow) GetServers() []*ServerConfig {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *Workflow) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *Workflow) GetEdges() []*Edge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+type Metadata struct {
+	state             protoimpl.MessageState   `protogen:"open.v1"`
+	Author            string                   `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Created           *timestamppb.Timestamp   `protobuf:"bytes,2,opt,name=created,proto3" json:"created,omitempty"`
+	LastModified      *timestamppb.Timestamp   `protobuf:"bytes,3,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Tags              []string                 `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Icon              string                   `protobuf:"bytes,5,opt,name=icon,proto3" json:"icon,omitempty"`
+	Template          *TemplateSource          `protobuf:"bytes,6,opt,name=template,proto3" json:"template,omitempty"`
+	Groups            []*NodeGroup             `protobuf:"bytes,7,rep,name=groups,proto3" json:"groups,omitempty"`
+	Notes             map[string]string        `protobuf:"bytes,8,rep,name=notes,proto3" json:"notes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Contracts         map[string]*NodeContract `protobuf:"bytes,9,rep,name=contracts,proto3" json:"contracts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Lint              map[string]string        `protobuf:"bytes,10,rep,name=lint,proto3" json:"lint,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Canvas            *CanvasLayout            `protobuf:"bytes,11,opt,name=canvas,proto3" json:"canvas,omitempty"`
+	ReadOnly          bool                     `protobuf:"varint,12,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	ExpressionTimeout string                   `protobuf:"bytes,13,opt,name=expression_timeout,json=expressionTimeout,proto3" json:"expression_timeout,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_workflow_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Metadata) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Metadata) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *Metadata) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+func (x *Metadata) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Metadata) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Metadata) GetTemplate() *TemplateSource {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *Metadata) GetGroups() []*NodeGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *Metadata) GetNotes() map[string]string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *Metadata) GetContracts() map[string]*NodeContract {
+	if x != nil {
+		return x.Contracts
+	}
+	return nil
+}
+
+func (x *Metadata) GetLint() map[string]string {
+	if x != nil {
+		return x.Lint
+	}
+	return nil
+}
+
+func (x *Metadata) GetCanvas() *CanvasLayout {
+	if x != nil {
+		return x.Canvas
+	}
+	return nil
+}
+
+func (x *Metadata) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *Metadata) GetExpressionTimeout() string {
+	if x != nil {
+		return x.ExpressionTimeout
+	}
+	return ""
+}
+
+type CanvasLayout struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Positions     map[string]*CanvasPosition `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Zoom          float64                    `protobuf:"fixed64,2,opt,name=zoom,proto3" json:"zoom,omitempty"`
+	ViewportX     int32                      `protobuf:"varint,3,opt,name=viewport_x,json=viewportX,proto3" json:"viewport_x,omitempty"`
+	ViewportY     int32                      `protobuf:"varint,4,opt,name=viewport_y,json=viewportY,proto3" json:"viewport_y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CanvasLayout) Reset() {
+	*x = CanvasLayout{}
+	mi := &file_workflow_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CanvasLayout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CanvasLayout) ProtoMessage() {}
+
+func (x *CanvasLayout) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CanvasLayout.ProtoReflect.Descriptor instead.
+func (*CanvasLayout) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CanvasLayout) GetPositions() map[string]*CanvasPosition {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+func (x *CanvasLayout) GetZoom() float64 {
+	if x != nil {
+		return x.Zoom
+	}
+	return 0
+}
+
+func (x *CanvasLayout) GetViewportX() int32 {
+	if x != nil {
+		return x.ViewportX
+	}
+	return 0
+}
+
+func (x *CanvasLayout) GetViewportY() int32 {
+	if x != nil {
+		return x.ViewportY
+	}
+	return 0
+}
+
+type CanvasPosition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CanvasPosition) Reset() {
+	*x = CanvasPosition{}
+	mi := &file_workflow_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CanvasPosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CanvasPosition) ProtoMessage() {}
+
+func (x *CanvasPosition) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CanvasPosition.ProtoReflect.Descriptor instead.
+func (*CanvasPosition) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CanvasPosition) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *CanvasPosition) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type TemplateSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Parameters    *structpb.Struct       `protobuf:"bytes,3,opt,name=parameters,proto3" json:"parameters,omitempty"`
+	Nodes         []string               `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TemplateSource) Reset() {
+	*x = TemplateSource{}
+	mi := &file_workflow_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TemplateSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateSource) ProtoMessage() {}
+
+func (x *TemplateSource) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateSource.ProtoReflect.Descriptor instead.
+func (*TemplateSource) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TemplateSource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateSource) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *TemplateSource) GetParameters() *structpb.Struct {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *TemplateSource) GetNodes() []string {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type NodeGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Nodes         []string               `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Collapsed     bool                   `protobuf:"varint,3,opt,name=collapsed,proto3" json:"collapsed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeGroup) Reset() {
+	*x = NodeGroup{}
+	mi := &file_workflow_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGroup) ProtoMessage() {}
+
+func (x *NodeGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGroup.ProtoReflect.Descriptor instead.
+func (*NodeGroup) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NodeGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NodeGroup) GetNodes() []string {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *NodeGroup) GetCollapsed() bool {
+	if x != nil {
+		return x.Collapsed
+	}
+	return false
+}
+
+type NodeContract struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reads         []string               `protobuf:"bytes,1,rep,name=reads,proto3" json:"reads,omitempty"`
+	Writes        []string               `protobuf:"bytes,2,rep,name=writes,proto3" json:"writes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeContract) Reset() {
+	*x = NodeContract{}
+	mi := &file_workflow_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeContract) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeContract) ProtoMessage() {}
+
+func (x *NodeContract) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeContract.ProtoReflect.Descriptor instead.
+func (*NodeContract) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NodeContract) GetReads() []string {
+	if x != nil {
+		return x.Reads
+	}
+	return nil
+}
+
+func (x *NodeContract) GetWrites() []string {
+	if x != nil {
+		return x.Writes
+	}
+	return nil
+}
+
+type Variable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Default       *structpb.Value        `protobuf:"bytes,3,opt,name=default,proto3" json:"default,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Required      bool                   `protobuf:"varint,5,opt,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variable) Reset() {
+	*x = Variable{}
+	mi := &file_workflow_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variable) ProtoMessage() {}
+
+func (x *Variable) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variable.ProtoReflect.Descriptor instead.
+func (*Variable) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Variable) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Variable) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Variable) GetDefault() *structpb.Value {
+	if x != nil {
+		return x.Default
+	}
+	return nil
+}
+
+func (x *Variable) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Variable) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+type ServerConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Args          []string               `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	Transport     string                 `protobuf:"bytes,5,opt,name=transport,proto3" json:"transport,omitempty"`
+	Env           map[string]string      `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CredentialRef string                 `protobuf:"bytes,7,opt,name=credential_ref,json=credentialRef,proto3" json:"credential_ref,omitempty"`
+	Url           string                 `protobuf:"bytes,8,opt,name=url,proto3" json:"url,omitempty"`
+	Headers       map[string]string      `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Limits        *ServerLimits          `protobuf:"bytes,10,opt,name=limits,proto3" json:"limits,omitempty"`
+	Tags          []string               `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Auth settings as key=value entries: type=bearer, token_ref=..., scopes=a,b
+	Auth          []string `protobuf:"bytes,13,rep,name=auth,proto3" json:"auth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerConfig) Reset() {
+	*x = ServerConfig{}
+	mi := &file_workflow_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerConfig) ProtoMessage() {}
+
+func (x *ServerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerConfig.ProtoReflect.Descriptor instead.
+func (*ServerConfig) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ServerConfig) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetCredentialRef() string {
+	if x != nil {
+		return x.CredentialRef
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetLimits() *ServerLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetAuth() []string {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+type ServerLimits struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MaxConcurrent     int32                  `protobuf:"varint,1,opt,name=max_concurrent,json=maxConcurrent,proto3" json:"max_concurrent,omitempty"`
+	RequestsPerSecond float64                `protobuf:"fixed64,2,opt,name=requests_per_second,json=requestsPerSecond,proto3" json:"requests_per_second,omitempty"`
+	Burst             int32                  `protobuf:"varint,3,opt,name=burst,proto3" json:"burst,omitempty"`
+	QueueTimeout      string                 `protobuf:"bytes,5,opt,name=queue_timeout,json=queueTimeout,proto3" json:"queue_timeout,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ServerLimits) Reset() {
+	*x = ServerLimits{}
+	mi := &file_workflow_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerLimits) ProtoMessage() {}
+
+func (x *ServerLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerLimits.ProtoReflect.Descriptor instead.
+func (*ServerLimits) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ServerLimits) GetMaxConcurrent() int32 {
+	if x != nil {
+		return x.MaxConcurrent
+	}
+	return 0
+}
+
+func (x *ServerLimits) GetRequestsPerSecond() float64 {
+	if x != nil {
+		return x.RequestsPerSecond
+	}
+	return 0
+}
+
+func (x *ServerLimits) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *ServerLimits) GetQueueTimeout() string {
+	if x != nil {
+		return x.QueueTimeout
+	}
+	return ""
+}
+
+type Node struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type              string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Return            string                 `protobuf:"bytes,3,opt,name=return,proto3" json:"return,omitempty"`
+	Server            string                 `protobuf:"bytes,4,opt,name=server,proto3" json:"server,omitempty"`
+	Tool              string                 `protobuf:"bytes,5,opt,name=tool,proto3" json:"tool,omitempty"`
+	Parameters        map[string]string      `protobuf:"bytes,6,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Output            string                 `protobuf:"bytes,7,opt,name=output,proto3" json:"output,omitempty"`
+	ContentOutputs    map[string]string      `protobuf:"bytes,8,rep,name=content_outputs,json=contentOutputs,proto3" json:"content_outputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Input             string                 `protobuf:"bytes,9,opt,name=input,proto3" json:"input,omitempty"`
+	Expression        string                 `protobuf:"bytes,10,opt,name=expression,proto3" json:"expression,omitempty"`
+	Condition         string                 `protobuf:"bytes,11,opt,name=condition,proto3" json:"condition,omitempty"`
+	Cases             []*SwitchCase          `protobuf:"bytes,12,rep,name=cases,proto3" json:"cases,omitempty"`
+	Branches          []*Branch              `protobuf:"bytes,13,rep,name=branches,proto3" json:"branches,omitempty"`
+	MergeStrategy     string                 `protobuf:"bytes,14,opt,name=merge_strategy,json=mergeStrategy,proto3" json:"merge_strategy,omitempty"`
+	Collection        string                 `protobuf:"bytes,15,opt,name=collection,proto3" json:"collection,omitempty"`
+	Item              string                 `protobuf:"bytes,16,opt,name=item,proto3" json:"item,omitempty"`
+	Body              []string               `protobuf:"bytes,17,rep,name=body,proto3" json:"body,omitempty"`
+	BreakCondition    string                 `protobuf:"bytes,18,opt,name=break_condition,json=breakCondition,proto3" json:"break_condition,omitempty"`
+	ErrorVariable     string                 `protobuf:"bytes,19,opt,name=error_variable,json=errorVariable,proto3" json:"error_variable,omitempty"`
+	Duration          string                 `protobuf:"bytes,20,opt,name=duration,proto3" json:"duration,omitempty"`
+	Until             string                 `protobuf:"bytes,21,opt,name=until,proto3" json:"until,omitempty"`
+	Message           string                 `protobuf:"bytes,22,opt,name=message,proto3" json:"message,omitempty"`
+	Timeout           string                 `protobuf:"bytes,23,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	DefaultAction     string                 `protobuf:"bytes,24,opt,name=default_action,json=defaultAction,proto3" json:"default_action,omitempty"`
+	Suppress          []string               `protobuf:"bytes,25,rep,name=suppress,proto3" json:"suppress,omitempty"`
+	Scope             string                 `protobuf:"bytes,26,opt,name=scope,proto3" json:"scope,omitempty"`
+	Promote           []string               `protobuf:"bytes,27,rep,name=promote,proto3" json:"promote,omitempty"`
+	OnConflict        string                 `protobuf:"bytes,28,opt,name=on_conflict,json=onConflict,proto3" json:"on_conflict,omitempty"`
+	CacheTtl          string                 `protobuf:"bytes,29,opt,name=cache_ttl,json=cacheTtl,proto3" json:"cache_ttl,omitempty"`
+	MaxConcurrency    int32                  `protobuf:"varint,30,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	StreamOutput      string                 `protobuf:"bytes,31,opt,name=stream_output,json=streamOutput,proto3" json:"stream_output,omitempty"`
+	ExpressionTimeout string                 `protobuf:"bytes,32,opt,name=expression_timeout,json=expressionTimeout,proto3" json:"expression_timeout,omitempty"`
+	OutputSchema      *structpb.Struct       `protobuf:"bytes,33,opt,name=output_schema,json=outputSchema,proto3" json:"output_schema,omitempty"`
+	Language          string                 `protobuf:"bytes,34,opt,name=language,proto3" json:"language,omitempty"`
+	ItemErrorsOutput  string                 `protobuf:"bytes,35,opt,name=item_errors_output,json=itemErrorsOutput,proto3" json:"item_errors_output,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_workflow_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Node) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Node) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Node) GetReturn() string {
+	if x != nil {
+		return x.Return
+	}
+	return ""
+}
+
+func (x *Node) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *Node) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *Node) GetParameters() map[string]string {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *Node) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *Node) GetContentOutputs() map[string]string {
+	if x != nil {
+		return x.ContentOutputs
+	}
+	return nil
+}
+
+func (x *Node) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *Node) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *Node) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *Node) GetCases() []*SwitchCase {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+func (x *Node) GetBranches() []*Branch {
+	if x != nil {
+		return x.Branches
+	}
+	return nil
+}
+
+func (x *Node) GetMergeStrategy() string {
+	if x != nil {
+		return x.MergeStrategy
+	}
+	return ""
+}
+
+func (x *Node) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *Node) GetItem() string {
+	if x != nil {
+		return x.Item
+	}
+	return ""
+}
+
+func (x *Node) GetBody() []string {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *Node) GetBreakCondition() string {
+	if x != nil {
+		return x.BreakCondition
+	}
+	return ""
+}
+
+func (x *Node) GetErrorVariable() string {
+	if x != nil {
+		return x.ErrorVariable
+	}
+	return ""
+}
+
+func (x *Node) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *Node) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *Node) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Node) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *Node) GetDefaultAction() string {
+	if x != nil {
+		return x.DefaultAction
+	}
+	return ""
+}
+
+func (x *Node) GetSuppress() []string {
+	if x != nil {
+		return x.Suppress
+	}
+	return nil
+}
+
+func (x *Node) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Node) GetPromote() []string {
+	if x != nil {
+		return x.Promote
+	}
+	return nil
+}
+
+func (x *Node) GetOnConflict() string {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ""
+}
+
+func (x *Node) GetCacheTtl() string {
+	if x != nil {
+		return x.CacheTtl
+	}
+	return ""
+}
+
+func (x *Node) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *Node) GetStreamOutput() string {
+	if x != nil {
+		return x.StreamOutput
+	}
+	return ""
+}
+
+func (x *Node) GetExpressionTimeout() string {
+	if x != nil {
+		return x.ExpressionTimeout
+	}
+	return ""
+}
+
+func (x *Node) GetOutputSchema() *structpb.Struct {
+	if x != nil {
+		return x.OutputSchema
+	}
+	return nil
+}
+
+func (x *Node) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Node) GetItemErrorsOutput() string {
+	if x != nil {
+		return x.ItemErrorsOutput
+	}
+	return ""
+}
+
+type SwitchCase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Condition     string                 `protobuf:"bytes,2,opt,name=condition,proto3" json:"condition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SwitchCase) Reset() {
+	*x = SwitchCase{}
+	mi := &file_workflow_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwitchCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwitchCase) ProtoMessage() {}
+
+func (x *SwitchCase) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwitchCase.ProtoReflect.Descriptor instead.
+func (*SwitchCase) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SwitchCase) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *SwitchCase) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+type Branch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []string               `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Branch) Reset() {
+	*x = Branch{}
+	mi := &file_workflow_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Branch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Branch) ProtoMessage() {}
+
+func (x *Branch) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Branch.ProtoReflect.Descriptor instead.
+func (*Branch) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Branch) GetNodes() []string {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type Edge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Condition     string                 `protobuf:"bytes,3,opt,name=condition,proto3" json:"condition,omitempty"`
+	Label         string                 `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	OnError       bool                   `protobuf:"varint,5,opt,name=on_error,json=onError,proto3" json:"on_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Edge) Reset() {
+	*x = Edge{}
+	mi := &file_workflow_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Edge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Edge) ProtoMessage() {}
+
+func (x *Edge) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Edge.ProtoReflect.Descriptor instead.
+func (*Edge) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Edge) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Edge) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Edge) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *Edge) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Edge) GetOnError() bool {
+	if x != nil {
+		return x.OnError
+	}
+	return false
+}
+
+var File_workflow_proto protoreflect.FileDescriptor
+
+const file_workflow_proto_rawDesc = "" +
+	"\n" +
+	"\x0eworkflow.proto\x12\x12goflow.workflow.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x93\x03\n" +
+	"\bWorkflow\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\x05R\rschemaVersion\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x128\n" +
+	"\bmetadata\x18\x05 \x01(\v2\x1c.goflow.workflow.v1.MetadataR\bmetadata\x12:\n" +
+	"\tvariables\x18\x06 \x03(\v2\x1c.goflow.workflow.v1.VariableR\tvariables\x12:\n" +
+	"\aservers\x18\a \x03(\v2 .goflow.workflow.v1.ServerConfigR\aservers\x12.\n" +
+	"\x05nodes\x18\b \x03(\v2\x18.goflow.workflow.v1.NodeR\x05nodes\x12.\n" +
+	"\x05edges\x18\t \x03(\v2\x18.goflow.workflow.v1.EdgeR\x05edges\"\xd7\x06\n" +
+	"\bMetadata\x12\x16\n" +
+	"\x06author\x18\x01 \x01(\tR\x06author\x124\n" +
+	"\acreated\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x12?\n" +
+	"\rlast_modified\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x12\n" +
+	"\x04icon\x18\x05 \x01(\tR\x04icon\x12>\n" +
+	"\btemplate\x18\x06 \x01(\v2\".goflow.workflow.v1.TemplateSourceR\btemplate\x125\n" +
+	"\x06groups\x18\a \x03(\v2\x1d.goflow.workflow.v1.NodeGroupR\x06groups\x12=\n" +
+	"\x05notes\x18\b \x03(\v2'.goflow.workflow.v1.Metadata.NotesEntryR\x05notes\x12I\n" +
+	"\tcontracts\x18\t \x03(\v2+.goflow.workflow.v1.Metadata.ContractsEntryR\tcontracts\x12:\n" +
+	"\x04lint\x18\n" +
+	" \x03(\v2&.goflow.workflow.v1.Metadata.LintEntryR\x04lint\x128\n" +
+	"\x06canvas\x18\v \x01(\v2 .goflow.workflow.v1.CanvasLayoutR\x06canvas\x12\x1b\n" +
+	"\tread_only\x18\f \x01(\bR\breadOnly\x12-\n" +
+	"\x12expression_timeout\x18\r \x01(\tR\x11expressionTimeout\x1a8\n" +
+	"\n" +
+	"NotesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a^\n" +
+	"\x0eContractsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x126\n" +
+	"\x05value\x18\x02 \x01(\v2 .goflow.workflow.v1.NodeContractR\x05value:\x028\x01\x1a7\n" +
+	"\tLintEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x91\x02\n" +
+	"\fCanvasLayout\x12M\n" +
+	"\tpositions\x18\x01 \x03(\v2/.goflow.workflow.v1.CanvasLayout.PositionsEntryR\tpositions\x12\x12\n" +
+	"\x04zoom\x18\x02 \x01(\x01R\x04zoom\x12\x1d\n" +
+	"\n" +
+	"viewport_x\x18\x03 \x01(\x05R\tviewportX\x12\x1d\n" +
+	"\n" +
+	"viewport_y\x18\x04 \x01(\x05R\tviewportY\x1a`\n" +
+	"\x0ePositionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x128\n" +
+	"\x05value\x18\x02 \x01(\v2\".goflow.workflow.v1.CanvasPositionR\x05value:\x028\x01\",\n" +
+	"\x0eCanvasPosition\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\"\x8d\x01\n" +
+	"\x0eTemplateSource\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x127\n" +
+	"\n" +
+	"parameters\x18\x03 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"parameters\x12\x14\n" +
+	"\x05nodes\x18\x04 \x03(\tR\x05nodes\"S\n" +
+	"\tNodeGroup\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05nodes\x18\x02 \x03(\tR\x05nodes\x12\x1c\n" +
+	"\tcollapsed\x18\x03 \x01(\bR\tcollapsed\"<\n" +
+	"\fNodeContract\x12\x14\n" +
+	"\x05reads\x18\x01 \x03(\tR\x05reads\x12\x16\n" +
+	"\x06writes\x18\x02 \x03(\tR\x06writes\"\xa2\x01\n" +
+	"\bVariable\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x120\n" +
+	"\adefault\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\adefault\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1a\n" +
+	"\brequired\x18\x05 \x01(\bR\brequired\"\xaa\x04\n" +
+	"\fServerConfig\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x04 \x03(\tR\x04args\x12\x1c\n" +
+	"\ttransport\x18\x05 \x01(\tR\ttransport\x12;\n" +
+	"\x03env\x18\x06 \x03(\v2).goflow.workflow.v1.ServerConfig.EnvEntryR\x03env\x12%\n" +
+	"\x0ecredential_ref\x18\a \x01(\tR\rcredentialRef\x12\x10\n" +
+	"\x03url\x18\b \x01(\tR\x03url\x12G\n" +
+	"\aheaders\x18\t \x03(\v2-.goflow.workflow.v1.ServerConfig.HeadersEntryR\aheaders\x128\n" +
+	"\x06limits\x18\n" +
+	" \x01(\v2 .goflow.workflow.v1.ServerLimitsR\x06limits\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12\x12\n" +
+	"\x04auth\x18\r \x03(\tR\x04auth\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b\f\x10\rR\x0ftool_categories\"\xa6\x01\n" +
+	"\fServerLimits\x12%\n" +
+	"\x0emax_concurrent\x18\x01 \x01(\x05R\rmaxConcurrent\x12.\n" +
+	"\x13requests_per_second\x18\x02 \x01(\x01R\x11requestsPerSecond\x12\x14\n" +
+	"\x05burst\x18\x03 \x01(\x05R\x05burst\x12#\n" +
+	"\rqueue_timeout\x18\x05 \x01(\tR\fqueueTimeoutJ\x04\b\x04\x10\x05\"\xc6\n\n" +
+	"\x04Node\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06return\x18\x03 \x01(\tR\x06return\x12\x16\n" +
+	"\x06server\x18\x04 \x01(\tR\x06server\x12\x12\n" +
+	"\x04tool\x18\x05 \x01(\tR\x04tool\x12H\n" +
+	"\n" +
+	"parameters\x18\x06 \x03(\v2(.goflow.workflow.v1.Node.ParametersEntryR\n" +
+	"parameters\x12\x16\n" +
+	"\x06output\x18\a \x01(\tR\x06output\x12U\n" +
+	"\x0fcontent_outputs\x18\b \x03(\v2,.goflow.workflow.v1.Node.ContentOutputsEntryR\x0econtentOutputs\x12\x14\n" +
+	"\x05input\x18\t \x01(\tR\x05input\x12\x1e\n" +
+	"\n" +
+	"expression\x18\n" +
+	" \x01(\tR\n" +
+	"expression\x12\x1c\n" +
+	"\tcondition\x18\v \x01(\tR\tcondition\x124\n" +
+	"\x05cases\x18\f \x03(\v2\x1e.goflow.workflow.v1.SwitchCaseR\x05cases\x126\n" +
+	"\bbranches\x18\r \x03(\v2\x1a.goflow.workflow.v1.BranchR\bbranches\x12%\n" +
+	"\x0emerge_strategy\x18\x0e \x01(\tR\rmergeStrategy\x12\x1e\n" +
+	"\n" +
+	"collection\x18\x0f \x01(\tR\n" +
+	"collection\x12\x12\n" +
+	"\x04item\x18\x10 \x01(\tR\x04item\x12\x12\n" +
+	"\x04body\x18\x11 \x03(\tR\x04body\x12'\n" +
+	"\x0fbreak_condition\x18\x12 \x01(\tR\x0ebreakCondition\x12%\n" +
+	"\x0eerror_variable\x18\x13 \x01(\tR\rerrorVariable\x12\x1a\n" +
+	"\bduration\x18\x14 \x01(\tR\bduration\x12\x14\n" +
+	"\x05until\x18\x15 \x01(\tR\x05until\x12\x18\n" +
+	"\amessage\x18\x16 \x01(\tR\amessage\x12\x18\n" +
+	"\atimeout\x18\x17 \x01(\tR\atimeout\x12%\n" +
+	"\x0edefault_action\x18\x18 \x01(\tR\rdefaultAction\x12\x1a\n" +
+	"\bsuppress\x18\x19 \x03(\tR\bsuppress\x12\x14\n" +
+	"\x05scope\x18\x1a \x01(\tR\x05scope\x12\x18\n" +
+	"\apromote\x18\x1b \x03(\tR\apromote\x12\x1f\n" +
+	"\von_conflict\x18\x1c \x01(\tR\n" +
+	"onConflict\x12\x1b\n" +
+	"\tcache_ttl\x18\x1d \x01(\tR\bcacheTtl\x12'\n" +
+	"\x0fmax_concurrency\x18\x1e \x01(\x05R\x0emaxConcurrency\x12#\n" +
+	"\rstream_output\x18\x1f \x01(\tR\fstreamOutput\x12-\n" +
+	"\x12expression_timeout\x18  \x01(\tR\x11expressionTimeout\x12<\n" +
+	"\routput_schema\x18! \x01(\v2\x17.google.protobuf.StructR\foutputSchema\x12\x1a\n" +
+	"\blanguage\x18\" \x01(\tR\blanguage\x12,\n" +
+	"\x12item_errors_output\x18# \x01(\tR\x10itemErrorsOutput\x1a=\n" +
+	"\x0fParametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aA\n" +
+	"\x13ContentOutputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"@\n" +
+	"\n" +
+	"SwitchCase\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x1c\n" +
+	"\tcondition\x18\x02 \x01(\tR\tcondition\"\x1e\n" +
+	"\x06Branch\x12\x14\n" +
+	"\x05nodes\x18\x01 \x03(\tR\x05nodes\"y\n" +
+	"\x04Edge\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x1c\n" +
+	"\tcondition\x18\x03 \x01(\tR\tcondition\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\x12\x19\n" +
+	"\bon_error\x18\x05 \x01(\bR\aonErrorB3Z1github.com/dshills/goflow/pkg/workflow/workflowpbb\x06proto3"
+
+var (
+	file_workflow_proto_rawDescOnce sync.Once
+	file_workflow_proto_rawDescData []byte
+)
+
+func file_workflow_proto_rawDescGZIP() []byte {
+	file_workflow_proto_rawDescOnce.Do(func() {
+		file_workflow_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_workflow_proto_rawDesc), len(file_workflow_proto_rawDesc)))
+	})
+	return file_workflow_proto_rawDescData
+}
+
+var file_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_workflow_proto_goTypes = []any{
+	(*Workflow)(nil),              // 0: goflow.workflow.v1.Workflow
+	(*Metadata)(nil),              // 1: goflow.workflow.v1.Metadata
+	(*CanvasLayout)(nil),          // 2: goflow.workflow.v1.CanvasLayout
+	(*CanvasPosition)(nil),        // 3: goflow.workflow.v1.CanvasPosition
+	(*TemplateSource)(nil),        // 4: goflow.workflow.v1.TemplateSource
+	(*NodeGroup)(nil),             // 5: goflow.workflow.v1.NodeGroup
+	(*NodeContract)(nil),          // 6: goflow.workflow.v1.NodeContract
+	(*Variable)(nil),              // 7: goflow.workflow.v1.Variable
+	(*ServerConfig)(nil),          // 8: goflow.workflow.v1.ServerConfig
+	(*ServerLimits)(nil),          // 9: goflow.workflow.v1.ServerLimits
+	(*Node)(nil),                  // 10: goflow.workflow.v1.Node
+	(*SwitchCase)(nil),            // 11: goflow.workflow.v1.SwitchCase
+	(*Branch)(nil),                // 12: goflow.workflow.v1.Branch
+	(*Edge)(nil),                  // 13: goflow.workflow.v1.Edge
+	nil,                           // 14: goflow.workflow.v1.Metadata.NotesEntry
+	nil,                           // 15: goflow.workflow.v1.Metadata.ContractsEntry
+	nil,                           // 16: goflow.workflow.v1.Metadata.LintEntry
+	nil,                           // 17: goflow.workflow.v1.CanvasLayout.PositionsEntry
+	nil,                           // 18: goflow.workflow.v1.ServerConfig.EnvEntry
+	nil,                           // 19: goflow.workflow.v1.ServerConfig.HeadersEntry
+	nil,                           // 20: goflow.workflow.v1.Node.ParametersEntry
+	nil,                           // 21: goflow.workflow.v1.Node.ContentOutputsEntry
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 23: google.protobuf.Struct
+	(*structpb.Value)(nil),        // 24: google.protobuf.Value
+}
+var file_workflow_proto_depIdxs = []int32{
+	1,  // 0: goflow.workflow.v1.Workflow.metadata:type_name -> goflow.workflow.v1.Metadata
+	7,  // 1: goflow.workflow.v1.Workflow.variables:type_name -> goflow.workflow.v1.Variable
+	8,  // 2: goflow.workflow.v1.Workflow.servers:type_name -> goflow.workflow.v1.ServerConfig
+	10, // 3: goflow.workflow.v1.Workflow.nodes:type_name -> goflow.workflow.v1.Node
+	13, // 4: goflow.workflow.v1.Workflow.edges:type_name -> goflow.workflow.v1.Edge
+	22, // 5: goflow.workflow.v1.Metadata.created:type_name -> google.protobuf.Timestamp
+	22, // 6: goflow.workflow.v1.Metadata.last_modified:type_name -> google.protobuf.Timestamp
+	4,  // 7: goflow.workflow.v1.Metadata.template:type_name -> goflow.workflow.v1.TemplateSource
+	5,  // 8: goflow.workflow.v1.Metadata.groups:type_name -> goflow.workflow.v1.NodeGroup
+	14, // 9: goflow.workflow.v1.Metadata.notes:type_name -> goflow.workflow.v1.Metadata.NotesEntry
+	15, // 10: goflow.workflow.v1.Metadata.contracts:type_name -> goflow.workflow.v1.Metadata.ContractsEntry
+	16, // 11: goflow.workflow.v1.Metadata.lint:type_name -> goflow.workflow.v1.Metadata.LintEntry
+	2,  // 12: goflow.workflow.v1.Metadata.canvas:type_name -> goflow.workflow.v1.CanvasLayout
+	17, // 13: goflow.workflow.v1.CanvasLayout.positions:type_name -> goflow.workflow.v1.CanvasLayout.PositionsEntry
+	23, // 14: goflow.workflow.v1.TemplateSource.parameters:type_name -> google.protobuf.Struct
+	24, // 15: goflow.workflow.v1.Variable.default:type_name -> google.protobuf.Value
+	18, // 16: goflow.workflow.v1.ServerConfig.env:type_name -> goflow.workflow.v1.ServerConfig.EnvEntry
+	19, // 17: goflow.workflow.v1.ServerConfig.headers:type_name -> goflow.workflow.v1.ServerConfig.HeadersEntry
+	9,  // 18: goflow.workflow.v1.ServerConfig.limits:type_name -> goflow.workflow.v1.ServerLimits
+	20, // 19: goflow.workflow.v1.Node.parameters:type_name -> goflow.workflow.v1.Node.ParametersEntry
+	21, // 20: goflow.workflow.v1.Node.content_outputs:type_name -> goflow.workflow.v1.Node.ContentOutputsEntry
+	11, // 21: goflow.workflow.v1.Node.cases:type_name -> goflow.workflow.v1.SwitchCase
+	12, // 22: goflow.workflow.v1.Node.branches:type_name -> goflow.workflow.v1.Branch
+	23, // 23: goflow.workflow.v1.Node.output_schema:type_name -> google.protobuf.Struct
+	6,  // 24: goflow.workflow.v1.Metadata.ContractsEntry.value:type_name -> goflow.workflow.v1.NodeContract
+	3,  // 25: goflow.workflow.v1.CanvasLayout.PositionsEntry.value:type_name -> goflow.workflow.v1.CanvasPosition
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_workflow_proto_init() }
+func file_workflow_proto_init() {
+	if File_workflow_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_workflow_proto_rawDesc), len(file_workflow_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_workflow_proto_goTypes,
+		DependencyIndexes: file_workflow_proto_depIdxs,
+		MessageInfos:      file_workflow_proto_msgTypes,
+	}.Build()
+	File_workflow_proto = out.File
+	file_workflow_proto_goTypes = nil
+	file_workflow_proto_depIdxs = nil
+}
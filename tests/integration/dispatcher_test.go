@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dshills/goflow/pkg/domain/execution"
+	runtimeexec "github.com/dshills/goflow/pkg/execution"
+	"github.com/dshills/goflow/pkg/workflow"
+)
+
+// dispatchedWorkflow returns a workflow that only starts and ends
+func dispatchedWorkflow(t *testing.T, name string) *workflow.Workflow {
+	t.Helper()
+	wf, err := workflow.Parse([]byte(fmt.Sprintf(`
+version: "1.0"
+name: %q
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "end"
+`, name)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return wf
+}
+
+// TestDispatcher_InteractiveRunOvertakesTriggeredRuns floods a single worker
+// with triggered runs and checks that a run started from the TUI or goflow
+// run executes as soon as the worker frees up, before the triggered runs
+// queued ahead of it.
+func TestDispatcher_InteractiveRunOvertakesTriggeredRuns(t *testing.T) {
+	// Triggered runs hold their worker until released
+	held := make(chan *runtimeexec.Engine, 10)
+	d := runtimeexec.NewDispatcher(runtimeexec.DispatcherOptions{
+		Workers: 1,
+		NewEngine: func() *runtimeexec.Engine {
+			engine := runtimeexec.NewEngine()
+			engine.Pause()
+			held <- engine
+			return engine
+		},
+	})
+	defer d.Close()
+
+	ctx := context.Background()
+	var triggered []*runtimeexec.Run
+	for i := 0; i < 4; i++ {
+		run, err := d.Submit(ctx, dispatchedWorkflow(t, fmt.Sprintf("triggered-%d", i)), nil, runtimeexec.PriorityBackground)
+		if err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+		triggered = append(triggered, run)
+	}
+	if stats := d.Stats(); stats.Running != 1 || stats.Queued != 3 {
+		t.Fatalf("Stats() = %+v, want 1 running and 3 queued", stats)
+	}
+
+	engine := runtimeexec.NewEngine()
+	defer func() { _ = engine.Close() }()
+	type result struct {
+		exec *execution.Execution
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		exec, err := d.Execute(ctx, engine, dispatchedWorkflow(t, "interactive"), nil, runtimeexec.PriorityInteractive)
+		done <- result{exec, err}
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for d.Stats().Queued != 4 {
+		if time.Now().After(deadline) {
+			t.Fatal("interactive run was not queued")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Free the worker: the interactive run goes next
+	(<-held).Resume()
+	select {
+	case r := <-done:
+		if r.err != nil || r.exec.Status != execution.StatusCompleted {
+			t.Fatalf("interactive run = %v, %v", r.exec, r.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactive run did not run when the worker freed up")
+	}
+	for _, run := range triggered[1:] {
+		if state := run.State(); state == runtimeexec.RunFinished {
+			t.Errorf("%s finished before the interactive run queued after it", run.Workflow().Name)
+		}
+	}
+
+	// The triggered runs follow in order
+	for range triggered[1:] {
+		(<-held).Resume()
+	}
+	for _, run := range triggered {
+		exec, err := run.Wait(ctx)
+		if err != nil || exec.Status != execution.StatusCompleted {
+			t.Errorf("%s = %v, %v", run.Workflow().Name, exec, err)
+		}
+	}
+}
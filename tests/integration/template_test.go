@@ -3,6 +3,7 @@ package integration
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/dshills/goflow/pkg/workflow"
@@ -830,6 +831,267 @@ func TestTemplateWithParameterSchema(t *testing.T) {
 	}
 }
 
+// TestValidateTemplateDeep tests that template validation applies the
+// workflow engine's rules to the instantiated workflow_spec
+func TestValidateTemplateDeep(t *testing.T) {
+	newTemplate := func(nodes []workflow.NodeSpec, edges []workflow.EdgeSpec) *workflow.WorkflowTemplate {
+		return &workflow.WorkflowTemplate{
+			Name:    "deep-validation",
+			Version: "1.0.0",
+			Parameters: []workflow.TemplateParameter{
+				{Name: "path", Type: workflow.ParameterTypeString, Required: true},
+				{Name: "limit", Type: workflow.ParameterTypeNumber, Default: 10},
+			},
+			WorkflowSpec: workflow.WorkflowSpec{Nodes: nodes, Edges: edges},
+		}
+	}
+	start := workflow.NodeSpec{ID: "start", Type: "start"}
+	end := workflow.NodeSpec{ID: "end", Type: "end"}
+	read := workflow.NodeSpec{ID: "read", Type: "mcp_tool", Config: map[string]interface{}{
+		"server": "fs", "tool": "read_file", "parameters": map[string]interface{}{"path": "{{path}}"},
+	}}
+
+	tests := []struct {
+		name        string
+		template    *workflow.WorkflowTemplate
+		errContains []string
+	}{
+		{
+			name: "valid template",
+			template: newTemplate(
+				[]workflow.NodeSpec{start, read, end},
+				[]workflow.EdgeSpec{{From: "start", To: "read"}, {From: "read", To: "end"}},
+			),
+		},
+		{
+			name: "unknown node types",
+			template: newTemplate(
+				[]workflow.NodeSpec{start, {ID: "a", Type: "webhook"}, {ID: "b", Type: "sleep"}, end},
+				[]workflow.EdgeSpec{{From: "start", To: "end"}},
+			),
+			errContains: []string{"node a: unknown node type: webhook", "node b: unknown node type: sleep"},
+		},
+		{
+			name: "duplicate IDs and cycle",
+			template: newTemplate(
+				[]workflow.NodeSpec{start, read, read, end},
+				[]workflow.EdgeSpec{{From: "start", To: "read"}, {From: "read", To: "end"}, {From: "end", To: "read"}},
+			),
+			errContains: []string{"duplicate node ID found: read", "circular dependency"},
+		},
+		{
+			name: "invalid condition expression",
+			template: newTemplate(
+				[]workflow.NodeSpec{start, {ID: "check", Type: "condition", Config: map[string]interface{}{
+					"condition": "${size > {{limit}} &&}",
+				}}, end, {ID: "other", Type: "end"}},
+				[]workflow.EdgeSpec{
+					{From: "start", To: "check"},
+					{From: "check", To: "end", Condition: "true"},
+					{From: "check", To: "other", Condition: "false"},
+				},
+			),
+			errContains: []string{"node check: invalid condition expression"},
+		},
+		{
+			name: "default violates constraints",
+			template: func() *workflow.WorkflowTemplate {
+				tmpl := newTemplate(
+					[]workflow.NodeSpec{start, end},
+					[]workflow.EdgeSpec{{From: "start", To: "end"}},
+				)
+				tmpl.Parameters[1].Validation = &workflow.ParameterValidation{Max: 5}
+				return tmpl
+			}(),
+			errContains: []string{"default value", "limit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := workflow.ValidateTemplate(tt.template)
+			if len(tt.errContains) == 0 {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected validation error, got nil")
+			}
+			for _, want := range tt.errContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got: %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+// TestTemplateComposition tests extends and includes, including parameter
+// namespacing and cycle detection
+func TestTemplateComposition(t *testing.T) {
+	notify := &workflow.WorkflowTemplate{
+		Name:     "error-notification",
+		Version:  "1.0.0",
+		Fragment: true,
+		Parameters: []workflow.TemplateParameter{
+			{Name: "channel", Type: workflow.ParameterTypeString, Required: true},
+			{Name: "message", Type: workflow.ParameterTypeString, Default: "workflow failed"},
+			{Name: "page", Type: workflow.ParameterTypeBoolean, Default: false},
+		},
+		WorkflowSpec: workflow.WorkflowSpec{
+			Nodes: []workflow.NodeSpec{
+				{ID: "send", Type: "mcp_tool", Config: map[string]interface{}{
+					"server": "slack", "tool": "post",
+					"parameters": map[string]interface{}{"channel": "{{channel}}", "text": "Error: {{message}}"},
+				}},
+				{ID: "page", Type: "mcp_tool", Condition: "{{page}}", Config: map[string]interface{}{
+					"server": "pager", "tool": "page",
+				}},
+			},
+			Edges: []workflow.EdgeSpec{{From: "send", To: "page"}},
+		},
+	}
+	base := &workflow.WorkflowTemplate{
+		Name:        "base-pipeline",
+		Description: "Read a file",
+		Version:     "1.0.0",
+		Parameters: []workflow.TemplateParameter{
+			{Name: "path", Type: workflow.ParameterTypeString, Required: true},
+		},
+		WorkflowSpec: workflow.WorkflowSpec{
+			Nodes: []workflow.NodeSpec{
+				{ID: "start", Type: "start"},
+				{ID: "read", Type: "mcp_tool", Config: map[string]interface{}{
+					"server": "fs", "tool": "read_file", "parameters": map[string]interface{}{"path": "{{path}}"},
+				}},
+				{ID: "end", Type: "end"},
+			},
+			Edges: []workflow.EdgeSpec{{From: "start", To: "read"}, {From: "read", To: "end"}},
+		},
+	}
+	child := &workflow.WorkflowTemplate{
+		Name:    "pipeline-with-alerts",
+		Version: "1.1.0",
+		Extends: "base-pipeline",
+		Parameters: []workflow.TemplateParameter{
+			{Name: "path", Type: workflow.ParameterTypeString, Default: "/tmp/input"},
+			{Name: "team", Type: workflow.ParameterTypeString, Required: true},
+		},
+		Includes: []workflow.TemplateInclude{{
+			Template:   "error-notification",
+			As:         "notify",
+			Parameters: map[string]interface{}{"channel": "#{{team}}", "page": false},
+		}},
+		WorkflowSpec: workflow.WorkflowSpec{
+			Nodes: []workflow.NodeSpec{{ID: "end", Type: "end", Config: map[string]interface{}{"return_value": "done"}}},
+			Edges: []workflow.EdgeSpec{{From: "read", To: "notify.send", Condition: "error != null"}},
+		},
+	}
+
+	templates := map[string]*workflow.WorkflowTemplate{
+		notify.Name: notify,
+		base.Name:   base,
+		child.Name:  child,
+	}
+	resolve := func(name string) (*workflow.WorkflowTemplate, error) {
+		if tmpl, ok := templates[name]; ok {
+			return tmpl, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	if _, err := workflow.InstantiateTemplate(context.Background(), child, map[string]interface{}{"team": "ops"}); !errors.Is(err, workflow.ErrTemplateNotComposed) {
+		t.Errorf("Expected ErrTemplateNotComposed for uncomposed template, got %v", err)
+	}
+
+	composed, err := workflow.ComposeTemplate(child, resolve)
+	if err != nil {
+		t.Fatalf("ComposeTemplate failed: %v", err)
+	}
+	if composed.Description != "Read a file" {
+		t.Errorf("Expected description inherited from base, got %q", composed.Description)
+	}
+
+	var paramNames []string
+	for _, param := range composed.Parameters {
+		paramNames = append(paramNames, param.Name)
+	}
+	if got := strings.Join(paramNames, ","); got != "path,team,notify.message" {
+		t.Errorf("Expected parameters path,team,notify.message, got %s", got)
+	}
+	if composed.Parameters[0].Required {
+		t.Error("Expected child definition of path to override the base")
+	}
+
+	var nodeIDs []string
+	for _, node := range composed.WorkflowSpec.Nodes {
+		nodeIDs = append(nodeIDs, node.ID)
+	}
+	if got := strings.Join(nodeIDs, ","); got != "start,read,end,notify.send" {
+		t.Errorf("Expected nodes start,read,end,notify.send (page excluded), got %s", got)
+	}
+
+	if err := workflow.ValidateTemplate(composed); err != nil {
+		t.Errorf("Expected composed template to validate, got %v", err)
+	}
+
+	wf, err := workflow.InstantiateTemplate(context.Background(), composed, map[string]interface{}{"team": "ops"})
+	if err != nil {
+		t.Fatalf("InstantiateTemplate failed: %v", err)
+	}
+	if end, ok := findNodeByID(wf.Nodes, "end").(*workflow.EndNode); !ok || end.ReturnValue != "done" {
+		t.Errorf("Expected overridden end node, got %#v", findNodeByID(wf.Nodes, "end"))
+	}
+	send, ok := findNodeByID(wf.Nodes, "notify.send").(*workflow.GenericMCPToolNode)
+	if !ok {
+		t.Fatalf("Expected notify.send node, got %#v", findNodeByID(wf.Nodes, "notify.send"))
+	}
+	params := send.Config["parameters"].(map[string]interface{})
+	if params["channel"] != "#ops" || params["text"] != "Error: workflow failed" {
+		t.Errorf("Unexpected notify.send parameters: %v", params)
+	}
+	if len(wf.Edges) != 3 {
+		t.Errorf("Expected 3 edges, got %d", len(wf.Edges))
+	}
+
+	if _, err := workflow.InstantiateTemplate(context.Background(), notify, map[string]interface{}{"channel": "x"}); !errors.Is(err, workflow.ErrTemplateIsFragment) {
+		t.Errorf("Expected ErrTemplateIsFragment, got %v", err)
+	}
+
+	t.Run("cycle", func(t *testing.T) {
+		templates["a"] = &workflow.WorkflowTemplate{Name: "a", Version: "1.0.0", Extends: "b"}
+		templates["b"] = &workflow.WorkflowTemplate{Name: "b", Version: "1.0.0", Includes: []workflow.TemplateInclude{{Template: "a", As: "x"}}}
+		_, err := workflow.ComposeTemplate(templates["a"], resolve)
+		if !errors.Is(err, workflow.ErrTemplateCycle) {
+			t.Fatalf("Expected ErrTemplateCycle, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "a -> b -> a") {
+			t.Errorf("Expected cycle path in error, got %v", err)
+		}
+	})
+
+	t.Run("invalid includes", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			include     workflow.TemplateInclude
+			errContains string
+		}{
+			{"missing namespace", workflow.TemplateInclude{Template: "error-notification"}, "'as' namespace"},
+			{"unknown binding", workflow.TemplateInclude{Template: "error-notification", As: "n", Parameters: map[string]interface{}{"color": "red"}}, "has no parameter color"},
+			{"missing template", workflow.TemplateInclude{Template: "nope", As: "n"}, "failed to resolve template nope"},
+		}
+		for _, tt := range tests {
+			tmpl := &workflow.WorkflowTemplate{Name: "bad", Version: "1.0.0", Includes: []workflow.TemplateInclude{tt.include}}
+			_, err := workflow.ComposeTemplate(tmpl, resolve)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.errContains, err)
+			}
+		}
+	})
+}
+
 // Helper function to find a node by ID
 func findNodeByID(nodes []workflow.Node, id string) workflow.Node {
 	for _, node := range nodes {
@@ -123,12 +123,14 @@ nodes:
     tool: "read_file"
     parameters:
       path: "/tmp/data.json"
+    output: "data"
   - id: "query_db"
     type: "mcp_tool"
     server: "sqlite"
     tool: "query"
     parameters:
       sql: "SELECT * FROM users"
+    output: "users"
   - id: "create_issue"
     type: "mcp_tool"
     server: "github"
@@ -136,6 +138,7 @@ nodes:
     parameters:
       repo: "test/repo"
       title: "Test issue"
+    output: "issue"
   - id: "end"
     type: "end"
 
@@ -264,6 +267,7 @@ nodes:
     type: "mcp_tool"
     server: "github-api"
     tool: "list_repositories"
+    output: "repos"
   - id: "end"
     type: "end"
 
@@ -478,6 +482,7 @@ nodes:
     type: "mcp_tool"
     server: "minimal-server"
     tool: "test"
+    output: "result1"
   - id: "end"
     type: "end"
 
@@ -587,10 +592,12 @@ nodes:
     type: "mcp_tool"
     server: "test"
     tool: "test"
+    output: "result2"
   - id: "node2"
     type: "mcp_tool"
     server: "test"
     tool: "test"
+    output: "result3"
   - id: "end"
     type: "end"
 edges:
@@ -734,6 +741,7 @@ nodes:
     type: "mcp_tool"
     server: "workflow-server"
     tool: "test"
+    output: "result4"
   - id: "end"
     type: "end"
 
@@ -1,12 +1,15 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/dshills/goflow/pkg/domain/execution"
 	"github.com/dshills/goflow/pkg/domain/types"
+	execpkg "github.com/dshills/goflow/pkg/execution"
 	"github.com/dshills/goflow/pkg/tui"
 	"github.com/dshills/goflow/pkg/workflow"
 	"github.com/dshills/goterm"
@@ -1143,3 +1146,591 @@ func valuesEqual(a, b interface{}) bool {
 	// This is a simplified comparison - would need more sophisticated logic for deep equality
 	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
 }
+
+// TestExecutionMonitorScratchpad tests evaluating expressions in the scratchpad
+func TestExecutionMonitorScratchpad(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+
+	screen := goterm.NewScreen(120, 40)
+	monitor := tui.NewExecutionMonitor(exec, wf, screen)
+
+	typeLine := func(line string) {
+		t.Helper()
+		for _, r := range line {
+			if err := monitor.HandleKey(r); err != nil {
+				t.Fatalf("HandleKey(%c) failed: %v", r, err)
+			}
+		}
+		if err := monitor.HandleKey('\r'); err != nil {
+			t.Fatalf("HandleKey(Enter) failed: %v", err)
+		}
+	}
+
+	if err := monitor.HandleKey('s'); err != nil {
+		t.Fatalf("HandleKey('s') failed: %v", err)
+	}
+	if monitor.GetActivePanel() != "scratch" {
+		t.Fatalf("Active panel = %q, want scratch", monitor.GetActivePanel())
+	}
+
+	// Keys that are shortcuts elsewhere ('q', 'e', 's') are typed as input
+	typeLine("sq = count * count")
+	typeLine("sq - count")
+
+	history := monitor.GetScratchpad().History()
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d", len(history))
+	}
+	if history[1].Err != nil || history[1].Result != 1722 {
+		t.Errorf("sq - count = %v (err=%v), want 1722", history[1].Result, history[1].Err)
+	}
+
+	// Scratch variables never reach the execution context
+	if _, exists := exec.Context.GetVariableSnapshot()["sq"]; exists {
+		t.Error("Scratch variable leaked into execution context")
+	}
+
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	for _, want := range []string{"Scratchpad", "sq = 1764", "= 1722"} {
+		if !screenContainsText(screen, want) {
+			t.Errorf("Expected %q in scratchpad render", want)
+		}
+	}
+
+	// Esc returns to the workflow panel; a shared scratchpad keeps its state
+	if err := monitor.HandleKey(27); err != nil {
+		t.Fatalf("HandleKey(Esc) failed: %v", err)
+	}
+	if monitor.GetActivePanel() != "workflow" {
+		t.Errorf("Active panel after Esc = %q, want workflow", monitor.GetActivePanel())
+	}
+
+	next := tui.NewExecutionMonitor(createTestExecution(wf), wf, goterm.NewScreen(120, 40))
+	next.SetScratchpad(monitor.GetScratchpad())
+	if next.GetScratchpad().Variables()["sq"] != 1764 {
+		t.Error("Shared scratchpad lost its variables")
+	}
+}
+
+// fakeNodeRetrier records retry requests and succeeds or fails on demand
+type fakeNodeRetrier struct {
+	calls []map[string]interface{}
+	fail  bool
+}
+
+func (f *fakeNodeRetrier) RetryNode(ctx context.Context, wf *workflow.Workflow, exec *execution.Execution, nodeID types.NodeID, params map[string]interface{}) (*execution.NodeExecution, error) {
+	f.calls = append(f.calls, params)
+
+	previous := exec.LatestNodeExecution(nodeID)
+	retry := execution.NewNodeExecution(exec.ID, nodeID, "mcp_tool")
+	retry.RetryOf = previous.ID
+	retry.RetryCount = previous.RetryCount + 1
+	retry.Start()
+	retry.Inputs = params
+	if f.fail {
+		retry.Fail(&execution.NodeError{Type: execution.ErrorTypeExecution, Message: "still broken"})
+	} else {
+		retry.Complete(map[string]interface{}{"file_content": "ok"})
+	}
+	_ = exec.AddNodeExecution(retry)
+	return retry, nil
+}
+
+func TestExecutionMonitorRetryFailedNode(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+
+	failed := execution.NewNodeExecution(exec.ID, "tool-1", "mcp_tool")
+	failed.Start()
+	failed.Inputs = map[string]interface{}{"path": "/tmp/missing.txt", "limit": float64(10)}
+	failed.Fail(&execution.NodeError{Type: execution.ErrorTypeExecution, Message: "file not found"})
+	exec.AddNodeExecution(failed)
+	exec.Fail(&execution.ExecutionError{Type: execution.ErrorTypeExecution, Message: "node tool-1 failed", NodeID: "tool-1"})
+
+	screen := goterm.NewScreen(120, 40)
+	monitor := tui.NewExecutionMonitor(exec, wf, screen)
+
+	press := func(keys ...rune) {
+		t.Helper()
+		for _, key := range keys {
+			if err := monitor.HandleKey(key); err != nil {
+				t.Fatalf("HandleKey(%q) failed: %v", key, err)
+			}
+		}
+	}
+
+	// Without a retrier the form is unavailable
+	press('r')
+	if monitor.GetActivePanel() == "retry" || monitor.GetLastAction() != "retry_unavailable" {
+		t.Fatalf("Retry form opened without a retrier (panel %q)", monitor.GetActivePanel())
+	}
+
+	retrier := &fakeNodeRetrier{}
+	monitor.SetNodeRetrier(retrier)
+	press('r')
+	if monitor.GetActivePanel() != "retry" {
+		t.Fatalf("Active panel = %q, want retry", monitor.GetActivePanel())
+	}
+	if got := monitor.GetRetryForm().NodeID(); got != "tool-1" {
+		t.Errorf("Retry form node = %q, want tool-1", got)
+	}
+
+	// Fields are sorted: limit (json), path. Edit path: clear it and type a
+	// new value; 'r' and 'j' are text while editing.
+	press('j', '\r')
+	for range "/tmp/missing.txt" {
+		press(127)
+	}
+	for _, r := range "/tmp/real.txt" {
+		press(r)
+	}
+	press('\r')
+
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	for _, want := range []string{"Retry tool-1 (read_file)", "file not found", "path = /tmp/real.txt", "limit (json) = 10"} {
+		if !screenContainsText(screen, want) {
+			t.Errorf("Expected %q in retry form render", want)
+		}
+	}
+
+	press('r')
+	if len(retrier.calls) != 1 {
+		t.Fatalf("Expected 1 retry call, got %d", len(retrier.calls))
+	}
+	args := retrier.calls[0]
+	if args["path"] != "/tmp/real.txt" || args["limit"] != float64(10) {
+		t.Errorf("Retry arguments = %v, want edited path and numeric limit", args)
+	}
+
+	result := monitor.GetRetryForm().Result()
+	if result == nil || result.RetryOf != failed.ID {
+		t.Fatalf("Retry result = %+v, want attempt referencing the failed execution", result)
+	}
+	if failed.Status != execution.NodeStatusFailed || len(exec.NodeExecutions) != 2 {
+		t.Error("Retry should be recorded as a distinct attempt")
+	}
+
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !screenContainsText(screen, "Retry #1 completed") {
+		t.Error("Expected retry outcome in retry form render")
+	}
+
+	// The log shows the manual retry as its own attempt
+	press(27)
+	foundRetryLog := false
+	for _, entry := range monitor.GetLogViewer().GetLogEntries() {
+		if entry.NodeID == "tool-1" && strings.Contains(entry.Message, "manual retry #1") {
+			foundRetryLog = true
+		}
+	}
+	if !foundRetryLog {
+		t.Error("Expected manual retry entry in execution log")
+	}
+
+	// Once the node succeeded there is nothing left to retry
+	press('r')
+	if monitor.GetActivePanel() == "retry" {
+		t.Error("Retry form opened for a node that no longer failed")
+	}
+}
+
+func TestExecutionMonitorRetryInvalidJSON(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+
+	failed := execution.NewNodeExecution(exec.ID, "tool-2", "mcp_tool")
+	failed.Start()
+	failed.Inputs = map[string]interface{}{"options": map[string]interface{}{"force": true}}
+	failed.Fail(&execution.NodeError{Type: execution.ErrorTypeExecution, Message: "permission denied"})
+	exec.AddNodeExecution(failed)
+	exec.Fail(&execution.ExecutionError{Type: execution.ErrorTypeExecution, Message: "node tool-2 failed", NodeID: "tool-2"})
+
+	monitor := tui.NewExecutionMonitor(exec, wf, goterm.NewScreen(120, 40))
+	retrier := &fakeNodeRetrier{}
+	monitor.SetNodeRetrier(retrier)
+
+	monitor.HandleKey('r')
+	form := monitor.GetRetryForm()
+	if err := form.SetFieldValue("options", "{not json"); err != nil {
+		t.Fatalf("SetFieldValue() failed: %v", err)
+	}
+	monitor.HandleKey('r')
+
+	if len(retrier.calls) != 0 {
+		t.Error("Retry should not run with invalid JSON arguments")
+	}
+	if !strings.Contains(form.Message(), "invalid JSON") {
+		t.Errorf("Form message = %q, want invalid JSON error", form.Message())
+	}
+}
+
+// fakePauser stands in for the engine's pause gate
+type fakePauser struct {
+	paused bool
+}
+
+func (f *fakePauser) Pause()         { f.paused = true }
+func (f *fakePauser) Resume()        { f.paused = false }
+func (f *fakePauser) IsPaused() bool { return f.paused }
+
+func TestExecutionMonitorEditVariablesWhilePaused(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+
+	screen := goterm.NewScreen(120, 40)
+	monitor := tui.NewExecutionMonitor(exec, wf, screen)
+	pauser := &fakePauser{}
+	monitor.SetPauser(pauser)
+	monitor.SetActivePanel("variables")
+	inspector := monitor.GetVariableInspector()
+
+	press := func(keys ...rune) {
+		t.Helper()
+		for _, key := range keys {
+			if err := monitor.HandleKey(key); err != nil {
+				t.Fatalf("HandleKey(%q) failed: %v", key, err)
+			}
+		}
+	}
+
+	// Variables are sorted: count, input_file
+	press('\r')
+	if monitor.GetLastAction() != "edit_unavailable" || inspector.IsEditing() {
+		t.Fatalf("Enter while running: action = %q, want edits refused", monitor.GetLastAction())
+	}
+
+	press(' ')
+	if !pauser.paused || monitor.GetLastAction() != "pause" {
+		t.Fatalf("Space did not pause (action %q)", monitor.GetLastAction())
+	}
+	press('\r')
+	if !inspector.IsEditing() {
+		t.Fatal("Enter while paused did not open the editor")
+	}
+	press(127, '7', '\r')
+	if value, _ := exec.Context.GetVariable("count"); value != float64(47) {
+		t.Errorf("count = %v (%T), want 47 parsed as JSON", value, value)
+	}
+
+	// A string keeps its type, whatever is typed
+	press('j', '\r')
+	for range "test.txt" {
+		press(127)
+	}
+	press('4', '2', '\r')
+	if value, _ := exec.Context.GetVariable("input_file"); value != "42" {
+		t.Errorf("input_file = %v (%T), want the string \"42\"", value, value)
+	}
+
+	// Invalid JSON keeps the editor open with the error
+	press('k', '\r', 'x', '\r')
+	if !inspector.IsEditing() || !strings.Contains(inspector.Message(), "invalid JSON") {
+		t.Errorf("message = %q, want the JSON error", inspector.Message())
+	}
+	press(27)
+
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !screenContainsText(screen, "running (paused)") {
+		t.Error("Expected the header to show the execution paused")
+	}
+
+	press(' ')
+	if pauser.paused {
+		t.Error("Space did not resume")
+	}
+}
+
+func TestExecutionMonitorWatchAndPin(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+	_ = exec.Context.SetVariable("user", map[string]interface{}{"name": "ada"})
+
+	screen := goterm.NewScreen(120, 40)
+	monitor := tui.NewExecutionMonitor(exec, wf, screen)
+	monitor.SetActivePanel("variables")
+	inspector := monitor.GetVariableInspector()
+
+	press := func(keys ...rune) {
+		t.Helper()
+		for _, key := range keys {
+			if err := monitor.HandleKey(key); err != nil {
+				t.Fatalf("HandleKey(%q) failed: %v", key, err)
+			}
+		}
+	}
+	typeWatch := func(expression string) {
+		t.Helper()
+		press('w')
+		press([]rune(expression)...)
+		press('\r')
+	}
+
+	typeWatch("count * 2")
+	typeWatch("$.user.name")
+	watches := inspector.Watches()
+	if len(watches) != 2 || watches[0].Value != 84 || watches[1].Value != "ada" {
+		t.Fatalf("watches = %+v", watches)
+	}
+
+	// Watches re-evaluate as the execution goes on
+	_ = exec.Context.SetVariable("count", 5)
+	monitor.OnExecutionEvent(exec)
+	if got := inspector.Watches()[0].Value; got != 10 {
+		t.Errorf("count * 2 = %v after count changed, want 10", got)
+	}
+
+	// Rows: the two watches, then count, input_file, user. Pin user.
+	press('j', 'j', 'j', 'j', 'p')
+	if pinned := inspector.PinnedVariables(); len(pinned) != 1 || pinned[0] != "user" {
+		t.Fatalf("pinned = %v, want [user]", pinned)
+	}
+	if name, _ := inspector.SelectedVariable(); name != "user" {
+		t.Errorf("selection = %q, want it to follow the pinned variable", name)
+	}
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !screenContainsText(screen, "*user = {1 fields}") || !screenContainsText(screen, "◆ $.user.name = \"ada\"") {
+		t.Error("Expected the pinned variable and the watches in the inspector")
+	}
+
+	// Remove the first watch
+	press('k', 'k', 'k', 'd')
+	if watches := inspector.Watches(); len(watches) != 1 || watches[0].Expression != "$.user.name" {
+		t.Errorf("watches after d = %+v", watches)
+	}
+}
+
+func TestExecutionMonitorPerformanceReport(t *testing.T) {
+	t.Setenv("GOFLOW_REPORTS_DIR", t.TempDir())
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+	for i, nodeID := range []types.NodeID{"start", "tool-1"} {
+		nodeExec := execution.NewNodeExecution(exec.ID, nodeID, "mcp_tool")
+		nodeExec.Start()
+		nodeExec.CompletedAt = nodeExec.StartedAt.Add(time.Duration(i+1) * 100 * time.Millisecond)
+		nodeExec.Status = execution.NodeStatusCompleted
+		exec.AddNodeExecution(nodeExec)
+	}
+
+	screen := goterm.NewScreen(120, 40)
+	monitor := tui.NewExecutionMonitor(exec, wf, screen)
+	if err := monitor.HandleKey('P'); err != nil {
+		t.Fatalf("HandleKey('P') failed: %v", err)
+	}
+	if monitor.GetActivePanel() != "profile" {
+		t.Fatalf("Active panel = %q, want profile", monitor.GetActivePanel())
+	}
+
+	report := monitor.GetProfile().Report()
+	if report == nil || report.MCPCalls != 2 || report.Nodes[0].NodeID != "tool-1" {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// Nodes completing while the report is open update it
+	nodeExec := execution.NewNodeExecution(exec.ID, "tool-2", "transform")
+	nodeExec.Start()
+	nodeExec.Complete(nil)
+	exec.AddNodeExecution(nodeExec)
+	monitor.OnExecutionEvent(exec)
+	if got := monitor.GetProfile().Report().Transforms; got != 1 {
+		t.Errorf("Transforms = %d after a transform completed, want 1", got)
+	}
+
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	for _, want := range []string{"Performance", "MCP 2 calls 300ms", "tool-1"} {
+		if !screenContainsText(screen, want) {
+			t.Errorf("Expected %q in the performance report", want)
+		}
+	}
+
+	if err := monitor.HandleKey('x'); err != nil {
+		t.Fatalf("HandleKey('x') failed: %v", err)
+	}
+	if monitor.GetLastAction() != "export" || !strings.Contains(monitor.GetProfile().Message(), exec.ID.String()+".csv") {
+		t.Errorf("export: action %q, message %q", monitor.GetLastAction(), monitor.GetProfile().Message())
+	}
+
+	if err := monitor.HandleKey(27); err != nil {
+		t.Fatalf("HandleKey(Esc) failed: %v", err)
+	}
+	if monitor.GetActivePanel() != "workflow" {
+		t.Errorf("Active panel after Esc = %q, want workflow", monitor.GetActivePanel())
+	}
+}
+
+// fakeCanceller records the reasons executions are stopped with
+type fakeCanceller struct {
+	reasons []string
+}
+
+func (f *fakeCanceller) Cancel(reason string) error {
+	f.reasons = append(f.reasons, reason)
+	return nil
+}
+
+func TestExecutionMonitorStopExecution(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+
+	screen := goterm.NewScreen(120, 40)
+	monitor := tui.NewExecutionMonitor(exec, wf, screen)
+	canceller := &fakeCanceller{}
+	monitor.SetCanceller(canceller)
+
+	press := func(key rune) {
+		t.Helper()
+		if err := monitor.HandleKey(key); err != nil {
+			t.Fatalf("HandleKey(%q) failed: %v", key, err)
+		}
+	}
+
+	// The first X asks for confirmation; another key disarms it
+	press('X')
+	if monitor.GetLastAction() != "confirm_stop" || len(canceller.reasons) != 0 {
+		t.Fatalf("first X: action %q, cancelled %v", monitor.GetLastAction(), canceller.reasons)
+	}
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !screenContainsText(screen, "Press X again") {
+		t.Error("Expected the stop confirmation in the header")
+	}
+	press('j')
+	press('X')
+	if monitor.GetLastAction() != "confirm_stop" || len(canceller.reasons) != 0 {
+		t.Fatalf("X after another key: action %q, cancelled %v", monitor.GetLastAction(), canceller.reasons)
+	}
+
+	press('X')
+	if monitor.GetLastAction() != "stop" || len(canceller.reasons) != 1 {
+		t.Fatalf("second X: action %q, cancelled %v", monitor.GetLastAction(), canceller.reasons)
+	}
+	if canceller.reasons[0] == "" {
+		t.Error("Expected a cancellation reason")
+	}
+
+	// Finished executions cannot be stopped
+	exec.Complete(nil)
+	press('X')
+	if monitor.GetLastAction() != "stop_unavailable" {
+		t.Errorf("X after completion: action %q, want stop_unavailable", monitor.GetLastAction())
+	}
+}
+
+func TestExecutionMonitorLogsPauseAndResume(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+
+	monitor := tui.NewExecutionMonitor(exec, wf, goterm.NewScreen(120, 40))
+	logs := monitor.GetLogViewer()
+	logs.AddEvent(execpkg.ExecutionEvent{
+		Type:        execpkg.EventExecutionPaused,
+		ExecutionID: exec.ID,
+		Metadata:    map[string]interface{}{"current_node": "node1"},
+	})
+	logs.AddEvent(execpkg.ExecutionEvent{
+		Type:        execpkg.EventExecutionResumed,
+		ExecutionID: exec.ID,
+	})
+
+	entries := logs.GetLogEntries()
+	if len(entries) < 2 {
+		t.Fatalf("got %d log entries, want the pause and the resume", len(entries))
+	}
+	last := entries[len(entries)-2:]
+	if last[0].Message != "Execution paused (finishing 'node1')" {
+		t.Errorf("pause entry = %q", last[0].Message)
+	}
+	if last[1].Message != "Execution resumed" {
+		t.Errorf("resume entry = %q", last[1].Message)
+	}
+}
+
+// fakeApprover records approval decisions
+type fakeApprover struct {
+	pending   []execpkg.PendingApproval
+	decisions []string
+}
+
+func (f *fakeApprover) PendingApprovals() []execpkg.PendingApproval {
+	return f.pending
+}
+
+func (f *fakeApprover) Approve(nodeID, by string) error {
+	return f.decide("approve " + nodeID)
+}
+
+func (f *fakeApprover) Reject(nodeID, by string) error {
+	return f.decide("reject " + nodeID)
+}
+
+func (f *fakeApprover) decide(decision string) error {
+	f.decisions = append(f.decisions, decision)
+	f.pending = f.pending[1:]
+	return nil
+}
+
+func TestExecutionMonitorApprovals(t *testing.T) {
+	wf := createTestWorkflowForExecution()
+	exec := createTestExecution(wf)
+	exec.Start()
+
+	screen := goterm.NewScreen(160, 40)
+	monitor := tui.NewExecutionMonitor(exec, wf, screen)
+	approver := &fakeApprover{pending: []execpkg.PendingApproval{
+		{NodeID: "deploy", Message: "Ship it?"},
+		{NodeID: "notify"},
+	}}
+	monitor.SetApprover(approver)
+
+	if _, err := monitor.Render(); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !screenContainsText(screen, "Approval needed: deploy - Ship it?") {
+		t.Error("Expected the pending approval in the header")
+	}
+
+	for _, key := range []rune{'a', 'n', 'a'} {
+		if err := monitor.HandleKey(key); err != nil {
+			t.Fatalf("HandleKey(%q) failed: %v", key, err)
+		}
+	}
+	want := []string{"approve deploy", "reject notify"}
+	if len(approver.decisions) != len(want) || approver.decisions[0] != want[0] || approver.decisions[1] != want[1] {
+		t.Errorf("decisions = %v, want %v", approver.decisions, want)
+	}
+	if monitor.GetLastAction() != "approval_unavailable" {
+		t.Errorf("a without a pending approval: action %q, want approval_unavailable", monitor.GetLastAction())
+	}
+
+	logs := monitor.GetLogViewer()
+	logs.AddEvent(execpkg.ExecutionEvent{
+		Type:     execpkg.EventApprovalDecided,
+		NodeID:   "deploy",
+		Metadata: map[string]interface{}{"approved": false, "by": "ops", "timed_out": false},
+	})
+	entries := logs.GetLogEntries()
+	if last := entries[len(entries)-1]; last.Message != "Node 'deploy' rejected by ops" {
+		t.Errorf("decision entry = %q", last.Message)
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/cli"
+)
+
+// TestDiffCommand tests text and JSON diffs between workflow files
+func TestDiffCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+
+	before := `
+version: "1.0"
+name: "diff-workflow"
+variables:
+  - name: "limit"
+    type: "number"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "check"
+    type: "condition"
+    condition: "limit > 5"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "check"
+  - from: "check"
+    to: "end"
+`
+	after := strings.Replace(before, "limit > 5", "limit > 10", 1)
+	after = strings.Replace(after, "  - id: \"end\"\n", "  - id: \"done\"\n", 1)
+	after = strings.Replace(after, "    to: \"end\"\n", "    to: \"done\"\n", 1)
+
+	beforePath := filepath.Join(tmpDir, "before.yaml")
+	afterPath := filepath.Join(tmpDir, "after.yaml")
+	if err := os.WriteFile(beforePath, []byte(before), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+	if err := os.WriteFile(afterPath, []byte(after), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := cli.NewDiffCommand()
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	output, err := run(beforePath, afterPath)
+	if err != nil {
+		t.Fatalf("Expected successful execution, got error: %v", err)
+	}
+	for _, want := range []string{
+		"~ check (condition)",
+		`condition: "limit > 5" → "limit > 10"`,
+		"+ done (end)",
+		"- end (end)",
+		"+ check -> done",
+		"- check -> end",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	output, err = run(beforePath, afterPath, "--format", "json", "--exit-code")
+	if err == nil {
+		t.Error("Expected --exit-code to fail when workflows differ")
+	}
+	var diff struct {
+		Nodes []struct {
+			ID     string `json:"id"`
+			Change string `json:"change"`
+		} `json:"nodes"`
+		Edges []struct {
+			ID string `json:"id"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal([]byte(output), &diff); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, output)
+	}
+	if len(diff.Nodes) != 3 || len(diff.Edges) != 2 {
+		t.Errorf("Unexpected JSON diff: %+v", diff)
+	}
+
+	output, err = run(beforePath, beforePath, "--exit-code")
+	if err != nil {
+		t.Errorf("Expected identical workflows to pass --exit-code, got: %v", err)
+	}
+	if !strings.Contains(output, "No differences") {
+		t.Errorf("Unexpected output for identical workflows: %s", output)
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/cli"
+)
+
+// TestEnvironmentCommand_SetUseAndRemove tests managing environments
+func TestEnvironmentCommand_SetUseAndRemove(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+	cli.GlobalConfig.ConfigDir = ""
+
+	servers := "servers:\n  db-staging:\n    id: db-staging\n    command: postgres-mcp\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "servers.yaml"), []byte(servers), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, error) {
+		cmd := cli.NewEnvironmentCommand()
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stdout)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	if _, err := run("set", "staging", "db=db-missing"); err == nil {
+		t.Error("Expected mapping to an unregistered server to fail")
+	}
+	if output, err := run("set", "staging", "db=db-staging"); err != nil || !strings.Contains(output, "created") {
+		t.Fatalf("Expected environment to be created, got %q, %v", output, err)
+	}
+	if _, err := run("use", "prod"); err == nil {
+		t.Error("Expected using an unknown environment to fail")
+	}
+	if _, err := run("use", "staging"); err != nil {
+		t.Fatalf("Expected use to succeed: %v", err)
+	}
+
+	output, err := run("list")
+	if err != nil {
+		t.Fatalf("Expected list to succeed: %v", err)
+	}
+	if !strings.Contains(output, "staging") || !strings.Contains(output, "*") {
+		t.Errorf("Expected staging listed as the default, got:\n%s", output)
+	}
+
+	output, err = run("show", "staging")
+	if err != nil || !strings.Contains(output, "db-staging") {
+		t.Errorf("Expected show to list the mapping, got %q, %v", output, err)
+	}
+
+	if _, err := run("remove", "staging"); err != nil {
+		t.Fatalf("Expected remove to succeed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "environments.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "staging") {
+		t.Errorf("Expected staging and the default to be removed, got:\n%s", data)
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/cli"
+)
+
+// TestGraphCommand tests rendering to stdout and to files
+func TestGraphCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, "workflows")
+	if err := os.MkdirAll(workflowsDir, 0755); err != nil {
+		t.Fatalf("Failed to create workflows directory: %v", err)
+	}
+	workflowYAML := `
+version: "1.0"
+name: "graph-workflow"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "end"
+`
+	if err := os.WriteFile(filepath.Join(workflowsDir, "graph-workflow.yaml"), []byte(workflowYAML), 0644); err != nil {
+		t.Fatalf("Failed to write test workflow: %v", err)
+	}
+	t.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+
+	tests := []struct {
+		name   string
+		args   []string
+		output string // File to read instead of stdout
+		want   []string
+	}{
+		{
+			name: "dot to stdout by default",
+			args: []string{"graph-workflow"},
+			want: []string{`digraph "graph-workflow" {`, `"start" -> "end";`},
+		},
+		{
+			name: "mermaid flag",
+			args: []string{"graph-workflow", "--format", "mermaid"},
+			want: []string{"flowchart TD", "n0 --> n1"},
+		},
+		{
+			name:   "svg inferred from extension",
+			args:   []string{"graph-workflow", "-o", filepath.Join(tmpDir, "docs", "graph.svg")},
+			output: filepath.Join(tmpDir, "docs", "graph.svg"),
+			want:   []string{"<svg ", `<g id="start">`},
+		},
+		{
+			name:   "mermaid fenced in markdown",
+			args:   []string{"graph-workflow", "-o", filepath.Join(tmpDir, "graph.md")},
+			output: filepath.Join(tmpDir, "graph.md"),
+			want:   []string{"```mermaid\nflowchart TD\n", "n0 --> n1\n```\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := cli.NewGraphCommand()
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Expected successful execution, got error: %v", err)
+			}
+
+			output := stdout.String()
+			if tt.output != "" {
+				data, err := os.ReadFile(tt.output)
+				if err != nil {
+					t.Fatalf("Failed to read output file: %v", err)
+				}
+				output = string(data)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/cli"
+)
+
+const lintWorkflowYAML = `
+version: "1.0"
+name: "lint-workflow"
+variables:
+  - name: "unused"
+    type: "string"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+  - id: "island"
+    type: "end"
+edges:
+  - from: "start"
+    to: "end"
+`
+
+// setupLintWorkflow writes a workflow with one warning and one error
+func setupLintWorkflow(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, "workflows")
+	if err := os.MkdirAll(workflowsDir, 0755); err != nil {
+		t.Fatalf("Failed to create workflows directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workflowsDir, "lint-workflow.yaml"), []byte(lintWorkflowYAML), 0644); err != nil {
+		t.Fatalf("Failed to write test workflow: %v", err)
+	}
+	t.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+	return tmpDir
+}
+
+func runLint(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := cli.NewLintCommand()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+// TestLintCommand_Text tests text output and the default failure threshold
+func TestLintCommand_Text(t *testing.T) {
+	setupLintWorkflow(t)
+
+	output, err := runLint(t, "lint-workflow")
+	if err == nil {
+		t.Fatal("Expected lint to fail on the unreachable node")
+	}
+	for _, want := range []string{
+		"lint-workflow: warning: variable unused is declared but never used [unused-variable]",
+		"lint-workflow:island: error: node island is not reachable from the start node [unreachable-node]",
+		"2 problem(s) in 1 workflow(s)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Disabling the error leaves only a warning, which passes by default.
+	// The orphan is also a structural error, so both rules are disabled.
+	if _, err := runLint(t, "--disable", "unreachable-node", "--disable", "structure"); err != nil {
+		t.Errorf("Expected warnings not to fail lint, got: %v", err)
+	}
+	if _, err := runLint(t, "--disable", "unreachable-node", "--disable", "structure", "--fail-on", "warning"); err == nil {
+		t.Error("Expected --fail-on warning to fail on a warning")
+	}
+}
+
+// TestLintCommand_Config tests rule severities from a config file
+func TestLintCommand_Config(t *testing.T) {
+	tmpDir := setupLintWorkflow(t)
+
+	configPath := filepath.Join(tmpDir, "lint.yaml")
+	config := "rules:\n  unreachable-node: warning\n  unused-variable: error\n"
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	output, err := runLint(t, "--config", configPath)
+	if err == nil {
+		t.Fatal("Expected lint to fail on the promoted unused-variable rule")
+	}
+	if !strings.Contains(output, "error: variable unused") || !strings.Contains(output, "warning: node island") {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+
+	if err := os.WriteFile(configPath, []byte("rules:\n  no-such-rule: off\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := runLint(t, "--config", configPath); err == nil || !strings.Contains(err.Error(), "unknown lint rule") {
+		t.Errorf("Expected unknown rule error, got: %v", err)
+	}
+}
+
+// TestLintCommand_SARIF tests the SARIF output format
+func TestLintCommand_SARIF(t *testing.T) {
+	setupLintWorkflow(t)
+
+	output, _ := runLint(t, "lint-workflow", "--format", "sarif", "--fail-on", "never")
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						Name string `json:"name"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Invalid SARIF output: %v\n%s", err, output)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log: %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "goflow" || len(run.Tool.Driver.Rules) == 0 {
+		t.Errorf("Unexpected driver: %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+
+	result := run.Results[1]
+	if result.RuleID != "unreachable-node" || result.Level != "error" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	location := result.Locations[0]
+	if !strings.HasSuffix(location.PhysicalLocation.ArtifactLocation.URI, "workflows/lint-workflow.yaml") {
+		t.Errorf("Unexpected artifact URI: %s", location.PhysicalLocation.ArtifactLocation.URI)
+	}
+	if len(location.LogicalLocations) != 1 || location.LogicalLocations[0].Name != "island" {
+		t.Errorf("Unexpected logical locations: %+v", location.LogicalLocations)
+	}
+}
+
+// TestLintCommand_SandboxUsesRegisteredToolCategories checks that a
+// sandbox profile judges tools by the categories in servers.yaml, not the
+// ones a workflow declares for itself
+func TestLintCommand_SandboxUsesRegisteredToolCategories(t *testing.T) {
+	tmpDir := setupLintWorkflow(t)
+	servers := `servers:
+  fs:
+    id: fs
+    command: fs-server
+    tool_categories:
+      read_file: [read]
+  shell:
+    id: shell
+    command: shell-mcp
+    tool_categories:
+      "*": [exec]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "servers.yaml"), []byte(servers), 0600); err != nil {
+		t.Fatal(err)
+	}
+	workflowYAML := `
+version: "1.0"
+name: "sandboxed"
+servers:
+  - id: "fs"
+    command: "fs-server"
+  - id: "shell"
+    command: "shell-mcp"
+    tool_categories:
+      "*": [read]
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "read"
+    type: "mcp_tool"
+    server: "fs"
+    tool: "read_file"
+    output: "content"
+  - id: "run"
+    type: "mcp_tool"
+    server: "shell"
+    tool: "run_command"
+    output: "result"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "read"
+  - from: "read"
+    to: "run"
+  - from: "run"
+    to: "end"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "workflows", "sandboxed.yaml"), []byte(workflowYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := runLint(t, "sandboxed", "--sandbox", "read-only")
+	if err == nil {
+		t.Fatal("Expected the read-only profile to reject the shell tool")
+	}
+	if !strings.Contains(output, "tool run_command of server shell is in the exec category") {
+		t.Errorf("Expected the registered exec category to apply, got:\n%s", output)
+	}
+	if strings.Contains(output, "read_file") {
+		t.Errorf("Expected read_file to pass as registered read-only, got:\n%s", output)
+	}
+}
@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/goflow/pkg/cli"
+)
+
+// runWorkflowCommand saves a workflow to a temporary repository and runs a
+// goflow subcommand on it by name, returning its output
+func runWorkflowCommand(t *testing.T, name, workflowYAML string, args ...string) (string, error) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, "workflows")
+	if err := os.MkdirAll(workflowsDir, 0755); err != nil {
+		t.Fatalf("Failed to create workflows directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workflowsDir, name+".yaml"), []byte(workflowYAML), 0644); err != nil {
+		t.Fatalf("Failed to write test workflow: %v", err)
+	}
+	t.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+
+	cmd := cli.NewRootCommand()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(append(args[:1:1], append([]string{name}, args[1:]...)...))
+	err := cmd.Execute()
+	return stdout.String() + stderr.String(), err
+}
+
+// TestRunCommand_DelayAndApprovalNodes runs a workflow that waits and then
+// takes an approval's default action when nobody decides in time
+func TestRunCommand_DelayAndApprovalNodes(t *testing.T) {
+	workflowYAML := `
+version: "1.0"
+name: "release"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "cooldown"
+    type: "delay"
+    duration: "10ms"
+  - id: "review"
+    type: "approval"
+    message: "Release now?"
+    timeout: "50ms"
+    default_action: "approve"
+    output: "decision"
+  - id: "end"
+    type: "end"
+    return: "${decision.approved}"
+edges:
+  - from: "start"
+    to: "cooldown"
+  - from: "cooldown"
+    to: "review"
+  - from: "review"
+    to: "end"
+`
+	for _, command := range []string{"validate", "lint", "graph"} {
+		if out, err := runWorkflowCommand(t, "release", workflowYAML, command); err != nil {
+			t.Errorf("goflow %s error = %v\n%s", command, err, out)
+		}
+	}
+
+	out, err := runWorkflowCommand(t, "release", workflowYAML, "run")
+	if err != nil {
+		t.Fatalf("goflow run error = %v\n%s", err, out)
+	}
+	for _, want := range []string{"cooldown completed", "review completed", "true"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got: %s", want, out)
+		}
+	}
+}
+
+// TestRunCommand_SwitchNode runs a workflow down the branch of the first
+// matching case
+func TestRunCommand_SwitchNode(t *testing.T) {
+	workflowYAML := `
+version: "1.0"
+name: "sizing"
+variables:
+  - name: "total"
+    type: "number"
+    default: 0
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "size"
+    type: "switch"
+    cases:
+      - label: "large"
+        condition: "total > 100"
+      - label: "medium"
+        condition: "total > 10"
+  - id: "large_path"
+    type: "passthrough"
+  - id: "medium_path"
+    type: "passthrough"
+  - id: "small_path"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "size"
+  - from: "size"
+    to: "large_path"
+    condition: "large"
+  - from: "size"
+    to: "medium_path"
+    condition: "medium"
+  - from: "size"
+    to: "small_path"
+    condition: "default"
+  - from: "large_path"
+    to: "end"
+  - from: "medium_path"
+    to: "end"
+  - from: "small_path"
+    to: "end"
+`
+	for _, command := range []string{"validate", "lint"} {
+		if out, err := runWorkflowCommand(t, "sizing", workflowYAML, command); err != nil {
+			t.Errorf("goflow %s error = %v\n%s", command, err, out)
+		}
+	}
+
+	out, err := runWorkflowCommand(t, "sizing", workflowYAML, "run", "--param", "total=50")
+	if err != nil {
+		t.Fatalf("goflow run error = %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "medium_path completed") {
+		t.Errorf("Expected the medium branch to run, got: %s", out)
+	}
+	for _, skipped := range []string{"large_path completed", "small_path completed"} {
+		if strings.Contains(out, skipped) {
+			t.Errorf("Expected %q not to run, got: %s", skipped, out)
+		}
+	}
+}
+
+// TestRunCommand_TryCatchAndErrorEdges runs workflows that recover from a
+// failing node through a catch node and through an error edge
+func TestRunCommand_TryCatchAndErrorEdges(t *testing.T) {
+	tryCatchYAML := `
+version: "1.0"
+name: "guarded"
+variables:
+  - name: "data"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "guard"
+    type: "try"
+    body: ["parse"]
+  - id: "parse"
+    type: "transform"
+    input: "data"
+    expression: "$.user.name"
+    output: "name"
+  - id: "handle"
+    type: "catch"
+    error_variable: "err"
+  - id: "ok"
+    type: "end"
+  - id: "recovered"
+    type: "end"
+    return: "${err.node_id}"
+edges:
+  - from: "start"
+    to: "guard"
+  - from: "guard"
+    to: "ok"
+  - from: "guard"
+    to: "handle"
+    on_error: true
+  - from: "handle"
+    to: "recovered"
+`
+	errorEdgeYAML := `
+version: "1.0"
+name: "fallback"
+variables:
+  - name: "data"
+    type: "object"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "parse"
+    type: "transform"
+    input: "data"
+    expression: "$.user.name"
+    output: "name"
+  - id: "fallback"
+    type: "passthrough"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "parse"
+  - from: "parse"
+    to: "end"
+  - from: "parse"
+    to: "fallback"
+    on_error: true
+  - from: "fallback"
+    to: "end"
+`
+	tests := []struct {
+		name, workflowYAML string
+		want               []string
+	}{
+		{"guarded", tryCatchYAML, []string{"handle completed", "recovered completed"}},
+		{"fallback", errorEdgeYAML, []string{"fallback completed", "end completed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, command := range []string{"validate", "lint"} {
+				if out, err := runWorkflowCommand(t, tt.name, tt.workflowYAML, command); err != nil {
+					t.Errorf("goflow %s error = %v\n%s", command, err, out)
+				}
+			}
+
+			out, err := runWorkflowCommand(t, tt.name, tt.workflowYAML, "run")
+			if err != nil {
+				t.Fatalf("goflow run error = %v\n%s", err, out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("Expected %q in output, got: %s", want, out)
+				}
+			}
+		})
+	}
+}
+
+// TestRunCommand_BatchToolNode runs a batch of tool calls against the test
+// MCP server
+func TestRunCommand_BatchToolNode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and starts the test MCP server")
+	}
+	serverPath, err := filepath.Abs("../../../cmd/testserver/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workflowYAML := `
+version: "1.0"
+name: "greetings"
+servers:
+  - id: "test-server"
+    command: "go"
+    args: ["run", "` + serverPath + `"]
+    transport: "stdio"
+variables:
+  - name: "names"
+    type: "array"
+    default: ["ada", "grace", "linus"]
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "greet"
+    type: "batch_tool"
+    server: "test-server"
+    tool: "echo"
+    collection: "names"
+    item: "name"
+    parameters:
+      message: "hello ${name}"
+    max_concurrency: 2
+    output: "greetings"
+  - id: "end"
+    type: "end"
+    return: "${greetings}"
+edges:
+  - from: "start"
+    to: "greet"
+  - from: "greet"
+    to: "end"
+`
+	// lint would also want the server registered
+	if out, err := runWorkflowCommand(t, "greetings", workflowYAML, "validate"); err != nil {
+		t.Errorf("goflow validate error = %v\n%s", err, out)
+	}
+
+	out, err := runWorkflowCommand(t, "greetings", workflowYAML, "run", "--output-json")
+	if err != nil {
+		t.Fatalf("goflow run error = %v\n%s", err, out)
+	}
+	for _, want := range []string{"hello ada", "hello grace", "hello linus"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got: %s", want, out)
+		}
+	}
+}
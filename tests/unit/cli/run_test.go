@@ -465,6 +465,7 @@ edges:
 
 // TestRunCommand_StdinWorkflow tests running workflow from stdin
 func TestRunCommand_StdinWorkflow(t *testing.T) {
+	t.Setenv("GOFLOW_CONFIG_DIR", t.TempDir()) // Runs are audited
 	workflowYAML := `
 version: "1.0"
 name: "stdin-workflow"
@@ -594,3 +595,144 @@ edges:
 		t.Error("Expected error for invalid input format, got nil")
 	}
 }
+
+// TestRunCommand_Params tests typed --param and --param-file inputs
+func TestRunCommand_Params(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, "workflows")
+	if err := os.MkdirAll(workflowsDir, 0755); err != nil {
+		t.Fatalf("Failed to create workflows directory: %v", err)
+	}
+
+	workflowYAML := `
+version: "1.0"
+name: "params-workflow"
+variables:
+  - name: "count"
+    type: "number"
+  - name: "dry_run"
+    type: "boolean"
+  - name: "target"
+    type: "string"
+    required: true
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "end"
+`
+	if err := os.WriteFile(filepath.Join(workflowsDir, "params-workflow.yaml"), []byte(workflowYAML), 0644); err != nil {
+		t.Fatalf("Failed to write test workflow: %v", err)
+	}
+
+	paramFile := filepath.Join(tmpDir, "params.yaml")
+	if err := os.WriteFile(paramFile, []byte("target: staging\ncount: 1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write parameter file: %v", err)
+	}
+
+	os.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+	defer os.Unsetenv("GOFLOW_CONFIG_DIR")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "typed params and param file",
+			args: []string{"--param-file", paramFile, "--param", "count=3", "--param", "dry_run=true"},
+		},
+		{
+			name:    "required variable still enforced",
+			args:    []string{"--param", "count=3"},
+			wantErr: "required variable missing: target",
+		},
+		{
+			name:    "value must match declared type",
+			args:    []string{"--param-file", paramFile, "--param", "count=three"},
+			wantErr: "invalid value for parameter count",
+		},
+		{
+			name:    "unknown parameter rejected",
+			args:    []string{"--param-file", paramFile, "--param", "cuont=3"},
+			wantErr: "unknown parameter: cuont",
+		},
+		{
+			name:    "missing param file",
+			args:    []string{"--param-file", filepath.Join(tmpDir, "missing.yaml")},
+			wantErr: "failed to read parameter file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := cli.NewRunCommand()
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SetArgs(append([]string{"params-workflow"}, tt.args...))
+
+			err := cmd.Execute()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected successful execution, got error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestRunCommand_StreamsNodeProgress tests that headless runs print node progress
+func TestRunCommand_StreamsNodeProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, "workflows")
+	if err := os.MkdirAll(workflowsDir, 0755); err != nil {
+		t.Fatalf("Failed to create workflows directory: %v", err)
+	}
+
+	workflowYAML := `
+version: "1.0"
+name: "progress-workflow"
+nodes:
+  - id: "start"
+    type: "start"
+  - id: "end"
+    type: "end"
+edges:
+  - from: "start"
+    to: "end"
+`
+	if err := os.WriteFile(filepath.Join(workflowsDir, "progress-workflow.yaml"), []byte(workflowYAML), 0644); err != nil {
+		t.Fatalf("Failed to write test workflow: %v", err)
+	}
+
+	os.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+	defer os.Unsetenv("GOFLOW_CONFIG_DIR")
+
+	cmd := cli.NewRunCommand()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"progress-workflow"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Expected successful execution, got error: %v", err)
+	}
+
+	output := stdout.String()
+	for _, want := range []string{"Execution started", "start started", "start completed", "end completed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "\033[") {
+		t.Errorf("Headless output should not contain ANSI escapes: %q", output)
+	}
+}
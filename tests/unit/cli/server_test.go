@@ -518,6 +518,69 @@ func TestServerShowCommand_Basic(t *testing.T) {
 	}
 }
 
+// TestServerImportCommand_ClaudeDesktopConfig tests importing an mcpServers config
+func TestServerImportCommand_ClaudeDesktopConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Reset global config to ensure clean state
+	cli.GlobalConfig.ConfigDir = ""
+
+	os.Setenv("GOFLOW_CONFIG_DIR", tmpDir)
+	defer os.Unsetenv("GOFLOW_CONFIG_DIR")
+
+	configPath := filepath.Join(tmpDir, "claude_desktop_config.json")
+	config := `{"mcpServers": {
+		"filesystem": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"]},
+		"existing": {"command": "node", "args": ["server.js"]}
+	}}`
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	addCmd := cli.NewServerCommand()
+	addCmd.SetArgs([]string{"add", "existing", "echo", "test"})
+	_ = addCmd.Execute()
+
+	// A dry run saves nothing
+	dryCmd := cli.NewServerCommand()
+	var dryOut bytes.Buffer
+	dryCmd.SetOut(&dryOut)
+	dryCmd.SetArgs([]string{"import", configPath, "--dry-run"})
+	if err := dryCmd.Execute(); err != nil {
+		t.Fatalf("Expected successful dry run, got error: %v", err)
+	}
+	if !strings.Contains(dryOut.String(), "Would import 1 server(s)") {
+		t.Errorf("Expected dry run summary, got: %s", dryOut.String())
+	}
+
+	importCmd := cli.NewServerCommand()
+	var stdout, stderr bytes.Buffer
+	importCmd.SetOut(&stdout)
+	importCmd.SetErr(&stderr)
+	importCmd.SetArgs([]string{"import", configPath})
+	if err := importCmd.Execute(); err != nil {
+		t.Fatalf("Expected successful import, got error: %v", err)
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "Imported server 'filesystem'") {
+		t.Errorf("Expected imported server, got: %s", output)
+	}
+	if !strings.Contains(output, "'existing' already exists") {
+		t.Errorf("Expected existing server to be left unchanged, got: %s", output)
+	}
+
+	showCmd := cli.NewServerCommand()
+	var showOut bytes.Buffer
+	showCmd.SetOut(&showOut)
+	showCmd.SetArgs([]string{"show", "filesystem"})
+	if err := showCmd.Execute(); err != nil {
+		t.Fatalf("Expected imported server to be registered, got error: %v", err)
+	}
+	if !strings.Contains(showOut.String(), "npx") {
+		t.Errorf("Expected imported command, got: %s", showOut.String())
+	}
+}
+
 // TestServerCommand_NoSubcommand tests server command without subcommand
 func TestServerCommand_NoSubcommand(t *testing.T) {
 	// This should fail because cli.NewServerCommand doesn't exist yet
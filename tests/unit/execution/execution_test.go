@@ -126,6 +126,33 @@ func TestExecutionStates(t *testing.T) {
 			expectedStatus: execution.StatusCancelled,
 			wantErr:        false,
 		},
+		{
+			name:          "running to cancelled via CancelWithReason",
+			initialStatus: execution.StatusRunning,
+			operation: func(e *execution.Execution) error {
+				if err := e.CancelWithReason("stopped by operator", "node1"); err != nil {
+					return err
+				}
+				if got := e.CancelReason(); got != "stopped by operator" {
+					t.Errorf("CancelReason() = %q, want %q", got, "stopped by operator")
+				}
+				if e.Error == nil || e.Error.Type != execution.ErrorTypeCancelled || e.Error.NodeID != "node1" {
+					t.Errorf("Error = %+v, want a cancelled error at node1", e.Error)
+				}
+				return nil
+			},
+			expectedStatus: execution.StatusCancelled,
+			wantErr:        false,
+		},
+		{
+			name:          "invalid transition: completed to cancelled with reason",
+			initialStatus: execution.StatusCompleted,
+			operation: func(e *execution.Execution) error {
+				return e.CancelWithReason("too late", "")
+			},
+			expectedStatus: execution.StatusCompleted,
+			wantErr:        true,
+		},
 		{
 			name:          "invalid transition: pending to completed",
 			initialStatus: execution.StatusPending,
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dshills/goflow/pkg/events"
 	"github.com/dshills/goflow/pkg/mcpserver"
 )
 
@@ -809,6 +810,41 @@ func TestMCPServer_HealthCheck(t *testing.T) {
 }
 
 // TestMCPServer_Reconnect tests reconnection with backoff
+func TestMCPServer_HealthChangeEvents(t *testing.T) {
+	server, _ := mcpserver.NewMCPServer("health-events-server", "npx", []string{"test"}, mcpserver.TransportStdio)
+	server.Connection.State = mcpserver.StateConnected
+	mockClient := &MockMCPClient{}
+	server.SetClient(mockClient)
+
+	received := make(chan events.ServerHealthChange, 10)
+	unsubscribe := events.Default().Subscribe(events.SubscriberFunc(func(event events.Event) {
+		if change, ok := event.Payload.(events.ServerHealthChange); ok && event.Source == server.ID {
+			received <- change
+		}
+	}), events.TopicServer)
+
+	_ = server.HealthCheck()
+	_ = server.HealthCheck() // Unchanged status, no event
+	mockClient.pingError = fmt.Errorf("simulated ping error")
+	_ = server.HealthCheck()
+	unsubscribe()
+	close(received)
+
+	var changes []events.ServerHealthChange
+	for change := range received {
+		changes = append(changes, change)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("got %d health change events, want 2: %+v", len(changes), changes)
+	}
+	if changes[0].Previous != "unknown" || changes[0].Current != "healthy" {
+		t.Errorf("first change = %+v, want unknown -> healthy", changes[0])
+	}
+	if changes[1].Current != "unhealthy" || changes[1].Error == "" {
+		t.Errorf("second change = %+v, want unhealthy with an error", changes[1])
+	}
+}
+
 func TestMCPServer_Reconnect(t *testing.T) {
 	tests := []struct {
 		name               string
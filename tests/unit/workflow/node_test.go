@@ -127,6 +127,29 @@ func TestMCPToolNode(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "MCP tool node with cache TTL",
+			node: &workflow.MCPToolNode{
+				ID:             "tool-1",
+				ServerID:       "fs-server",
+				ToolName:       "read_file",
+				OutputVariable: "file_content",
+				CacheTTL:       "10m",
+			},
+			wantErr: false,
+		},
+		{
+			name: "MCP tool node with invalid cache TTL",
+			node: &workflow.MCPToolNode{
+				ID:             "tool-1",
+				ServerID:       "fs-server",
+				ToolName:       "read_file",
+				OutputVariable: "file_content",
+				CacheTTL:       "-1m",
+			},
+			wantErr: true,
+			errMsg:  "cache_ttl",
+		},
 		{
 			name: "MCP tool node with empty ID",
 			node: &workflow.MCPToolNode{